@@ -0,0 +1,335 @@
+// Package activehours decides whether the town may spawn new polecats now.
+//
+// Towns can restrict heavy work to scheduled windows (active_hours in
+// settings/config.json): only at night when API capacity is cheap, or only
+// during working hours when someone is around to supervise. Outside every
+// window gt sling refuses to spawn polecats and the daemon stops feeding
+// stranded convoys. Agents that are already running are left alone.
+//
+// A manual override in <town>/.runtime/active-hours.json opens or closes
+// spawning regardless of the schedule, until a deadline or until cleared.
+package activehours
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Override modes.
+const (
+	ModeOpen   = "open"   // Spawning allowed regardless of schedule
+	ModeClosed = "closed" // Spawning refused regardless of schedule
+)
+
+// overrideFile lives under <town>/.runtime/.
+const overrideFile = "active-hours.json"
+
+// ErrOutsideActiveHours is returned by operations refused outside the
+// town's active hours.
+var ErrOutsideActiveHours = errors.New("outside the town's active hours")
+
+var dayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// window is one parsed ActiveWindow. Times are minutes after midnight.
+type window struct {
+	days       [7]bool
+	start, end int
+}
+
+// open reports whether the window covers minute m of a day that is wd.
+// A window whose end is at or before its start runs into the next day.
+func (w window) open(wd time.Weekday, m int) bool {
+	if w.start < w.end {
+		return w.days[wd] && m >= w.start && m < w.end
+	}
+	yesterday := (wd + 6) % 7
+	return (w.days[wd] && m >= w.start) || (w.days[yesterday] && m < w.end)
+}
+
+// Schedule is a parsed active hours configuration.
+type Schedule struct {
+	loc     *time.Location
+	windows []window
+}
+
+// Parse validates cfg and returns its schedule. A nil config or one
+// without windows returns a nil schedule, which is always open.
+func Parse(cfg *config.ActiveHoursConfig) (*Schedule, error) {
+	if cfg == nil || len(cfg.Windows) == 0 {
+		return nil, nil
+	}
+	s := &Schedule{loc: time.Local}
+	if cfg.Timezone != "" {
+		loc, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("active_hours.timezone: %w", err)
+		}
+		s.loc = loc
+	}
+	for i, cw := range cfg.Windows {
+		w, err := parseWindow(cw)
+		if err != nil {
+			return nil, fmt.Errorf("active_hours.windows[%d]: %w", i, err)
+		}
+		s.windows = append(s.windows, w)
+	}
+	return s, nil
+}
+
+func parseWindow(cw config.ActiveWindow) (window, error) {
+	var w window
+	var err error
+	if w.start, err = parseClock(cw.Start); err != nil {
+		return w, fmt.Errorf("start: %w", err)
+	}
+	if w.end, err = parseClock(cw.End); err != nil {
+		return w, fmt.Errorf("end: %w", err)
+	}
+	if len(cw.Days) == 0 {
+		w.days = [7]bool{true, true, true, true, true, true, true}
+	}
+	for _, d := range cw.Days {
+		switch d = strings.ToLower(d); d {
+		case "weekdays":
+			for wd := time.Monday; wd <= time.Friday; wd++ {
+				w.days[wd] = true
+			}
+		case "weekends":
+			w.days[time.Saturday], w.days[time.Sunday] = true, true
+		default:
+			wd, ok := dayNames[d]
+			if !ok {
+				return w, fmt.Errorf("unknown day %q (use mon..sun, weekdays, or weekends)", d)
+			}
+			w.days[wd] = true
+		}
+	}
+	return w, nil
+}
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Open reports whether the schedule allows spawning at t.
+func (s *Schedule) Open(t time.Time) bool {
+	if s == nil {
+		return true
+	}
+	lt := t.In(s.loc)
+	m := lt.Hour()*60 + lt.Minute()
+	for _, w := range s.windows {
+		if w.open(lt.Weekday(), m) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextChange returns when the schedule next opens or closes after t, or
+// the zero time if it never changes (always open or never open).
+func (s *Schedule) NextChange(t time.Time) time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+	now := s.Open(t)
+	// Windows are minute-aligned; a week plus a day covers every
+	// pattern, including DST shifts.
+	next := t.Truncate(time.Minute)
+	for i := 0; i < 8*24*60; i++ {
+		next = next.Add(time.Minute)
+		if s.Open(next) != now {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// Location returns the time zone the schedule is evaluated in.
+func (s *Schedule) Location() *time.Location {
+	if s == nil {
+		return time.Local
+	}
+	return s.loc
+}
+
+// Override is a manual open or close that wins over the schedule.
+type Override struct {
+	Mode   string    `json:"mode"`            // ModeOpen or ModeClosed
+	Until  time.Time `json:"until,omitempty"` // Zero means until cleared
+	Reason string    `json:"reason,omitempty"`
+	SetBy  string    `json:"set_by,omitempty"`
+	SetAt  time.Time `json:"set_at"`
+}
+
+// Expired reports whether the override's deadline has passed.
+func (o *Override) Expired(now time.Time) bool {
+	return !o.Until.IsZero() && !now.Before(o.Until)
+}
+
+// OverridePath returns the path of the override file.
+func OverridePath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, overrideFile)
+}
+
+// LoadOverride reads the manual override. It returns nil when there is
+// none or it has expired.
+func LoadOverride(townRoot string, now time.Time) (*Override, error) {
+	data, err := os.ReadFile(OverridePath(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading active hours override: %w", err)
+	}
+	var o Override
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, fmt.Errorf("parsing active hours override: %w", err)
+	}
+	if o.Expired(now) {
+		return nil, nil
+	}
+	return &o, nil
+}
+
+// SetOverride records a manual override.
+func SetOverride(townRoot string, o *Override) error {
+	if o.Mode != ModeOpen && o.Mode != ModeClosed {
+		return fmt.Errorf("invalid override mode %q", o.Mode)
+	}
+	if err := os.MkdirAll(filepath.Dir(OverridePath(townRoot)), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	return util.AtomicWriteJSON(OverridePath(townRoot), o)
+}
+
+// ClearOverride removes any manual override. Returns false if there was none.
+func ClearOverride(townRoot string) (bool, error) {
+	err := os.Remove(OverridePath(townRoot))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Status is the town's effective spawn window at a point in time.
+type Status struct {
+	// Configured is true when settings define at least one window.
+	Configured bool `json:"configured"`
+	// Open is whether spawning is allowed, after any override.
+	Open bool `json:"open"`
+	// ScheduledOpen is what the schedule alone says.
+	ScheduledOpen bool `json:"scheduled_open"`
+	// NextChange is when the schedule next opens or closes.
+	NextChange time.Time `json:"next_change,omitempty"`
+	// Timezone is the zone the schedule is evaluated in.
+	Timezone string `json:"timezone"`
+	// Override is the active manual override, if any.
+	Override *Override `json:"override,omitempty"`
+
+	loc *time.Location
+}
+
+// Current returns the town's spawn window status at now. Invalid active
+// hours settings are reported as an error; callers that gate spawns treat
+// that as open so a typo can't silently stop the town.
+func Current(townRoot string, now time.Time) (*Status, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	sched, err := Parse(settings.ActiveHours)
+	if err != nil {
+		return nil, err
+	}
+	override, err := LoadOverride(townRoot, now)
+	if err != nil {
+		return nil, err
+	}
+	return evaluate(sched, override, now), nil
+}
+
+func evaluate(sched *Schedule, override *Override, now time.Time) *Status {
+	st := &Status{
+		Configured:    sched != nil,
+		ScheduledOpen: sched.Open(now),
+		NextChange:    sched.NextChange(now),
+		Timezone:      sched.Location().String(),
+		Override:      override,
+		loc:           sched.Location(),
+	}
+	st.Open = st.ScheduledOpen
+	if override != nil {
+		st.Open = override.Mode == ModeOpen
+	}
+	return st
+}
+
+// Summary describes the status in one line, e.g. "closed until 22:00 PST".
+func (s *Status) Summary(now time.Time) string {
+	state := openWord(s.Open)
+	if s.Override != nil {
+		until := "until cleared"
+		if !s.Override.Until.IsZero() {
+			until = "until " + formatWhen(s.Override.Until, now, s.loc)
+		}
+		summary := fmt.Sprintf("%s by override %s", state, until)
+		if s.Configured {
+			summary += fmt.Sprintf(" (schedule: %s)", openWord(s.ScheduledOpen))
+		}
+		return summary
+	}
+	if !s.Configured {
+		return "always open (no active hours configured)"
+	}
+	if s.NextChange.IsZero() {
+		return state
+	}
+	return fmt.Sprintf("%s until %s", state, formatWhen(s.NextChange, now, s.loc))
+}
+
+func openWord(open bool) string {
+	if open {
+		return "open"
+	}
+	return "closed"
+}
+
+// formatWhen prints t in loc, with the weekday when it isn't within a day.
+func formatWhen(t, now time.Time, loc *time.Location) string {
+	if loc == nil {
+		loc = time.Local
+	}
+	if t.Sub(now) < 24*time.Hour {
+		return t.In(loc).Format("15:04 MST")
+	}
+	return t.In(loc).Format("Mon 15:04 MST")
+}
+
+// Check returns an error wrapping ErrOutsideActiveHours when spawning is
+// not allowed now. Unreadable or invalid settings don't block spawning.
+func Check(townRoot string) error {
+	now := time.Now()
+	st, err := Current(townRoot, now)
+	if err != nil || st.Open {
+		return nil
+	}
+	return fmt.Errorf("%w: spawning is %s", ErrOutsideActiveHours, st.Summary(now))
+}
@@ -0,0 +1,204 @@
+package activehours
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func mustParse(t *testing.T, cfg *config.ActiveHoursConfig) *Schedule {
+	t.Helper()
+	s, err := Parse(cfg)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return s
+}
+
+// at returns a UTC time in the week of Monday 2026-10-12.
+func at(day time.Weekday, hh, mm int) time.Time {
+	return time.Date(2026, 10, 11+int(day), hh, mm, 0, 0, time.UTC)
+}
+
+func TestSchedule_Open(t *testing.T) {
+	s := mustParse(t, &config.ActiveHoursConfig{
+		Timezone: "UTC",
+		Windows: []config.ActiveWindow{
+			{Days: []string{"weekdays"}, Start: "22:00", End: "06:00"}, // overnight
+			{Days: []string{"sat"}, Start: "10:00", End: "12:00"},
+		},
+	})
+	tests := []struct {
+		when time.Time
+		want bool
+	}{
+		{at(time.Monday, 21, 59), false},
+		{at(time.Monday, 22, 0), true},
+		{at(time.Tuesday, 5, 59), true}, // Monday's window runs past midnight
+		{at(time.Tuesday, 6, 0), false},
+		{at(time.Saturday, 3, 0), true}, // Friday night's window
+		{at(time.Saturday, 23, 0), false},
+		{at(time.Sunday, 2, 0), false},
+		{at(time.Monday, 2, 0), false}, // Sunday has no window
+		{at(time.Saturday, 11, 0), true},
+	}
+	for _, tt := range tests {
+		if got := s.Open(tt.when); got != tt.want {
+			t.Errorf("Open(%s) = %v, want %v", tt.when.Format("Mon 15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestSchedule_Timezone(t *testing.T) {
+	s := mustParse(t, &config.ActiveHoursConfig{
+		Timezone: "America/New_York",
+		Windows:  []config.ActiveWindow{{Start: "09:00", End: "17:00"}},
+	})
+	// 14:00 UTC is 10:00 in New York (EDT).
+	if !s.Open(at(time.Wednesday, 14, 0)) {
+		t.Error("10:00 New York should be open")
+	}
+	if s.Open(at(time.Wednesday, 12, 0)) {
+		t.Error("08:00 New York should be closed")
+	}
+}
+
+func TestSchedule_NextChange(t *testing.T) {
+	s := mustParse(t, &config.ActiveHoursConfig{
+		Timezone: "UTC",
+		Windows:  []config.ActiveWindow{{Start: "22:00", End: "06:00"}},
+	})
+	if got, want := s.NextChange(at(time.Monday, 12, 30)), at(time.Monday, 22, 0); !got.Equal(want) {
+		t.Errorf("NextChange = %v, want %v", got, want)
+	}
+	if got, want := s.NextChange(at(time.Monday, 23, 0)), at(time.Tuesday, 6, 0); !got.Equal(want) {
+		t.Errorf("NextChange = %v, want %v", got, want)
+	}
+
+	var always *Schedule
+	if !always.Open(time.Now()) || !always.NextChange(time.Now()).IsZero() {
+		t.Error("nil schedule should always be open and never change")
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, tt := range []struct {
+		cfg  config.ActiveHoursConfig
+		want string
+	}{
+		{config.ActiveHoursConfig{Timezone: "Mars/Olympus", Windows: []config.ActiveWindow{{Start: "09:00", End: "17:00"}}}, "active_hours.timezone"},
+		{config.ActiveHoursConfig{Windows: []config.ActiveWindow{{Start: "9am", End: "17:00"}}}, `active_hours.windows[0]: start: invalid time "9am"`},
+		{config.ActiveHoursConfig{Windows: []config.ActiveWindow{{Days: []string{"funday"}, Start: "09:00", End: "17:00"}}}, `unknown day "funday"`},
+	} {
+		if _, err := Parse(&tt.cfg); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%+v) = %v, want %q", tt.cfg, err, tt.want)
+		}
+	}
+	if s, err := Parse(&config.ActiveHoursConfig{Timezone: "UTC"}); s != nil || err != nil {
+		t.Errorf("no windows should mean always open, got %v %v", s, err)
+	}
+}
+
+// writeSettings writes town settings with the given active hours.
+func writeSettings(t *testing.T, townRoot string, hours *config.ActiveHoursConfig) {
+	t.Helper()
+	settings := config.NewTownSettings()
+	settings.ActiveHours = hours
+	data, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := config.TownSettingsPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// closedNow is a schedule whose only window is the hour opposite now.
+func closedNow() *config.ActiveHoursConfig {
+	h := (time.Now().UTC().Hour() + 12) % 24
+	return &config.ActiveHoursConfig{
+		Timezone: "UTC",
+		Windows:  []config.ActiveWindow{{Start: time.Date(0, 1, 1, h, 0, 0, 0, time.UTC).Format("15:04"), End: time.Date(0, 1, 1, (h+1)%24, 0, 0, 0, time.UTC).Format("15:04")}},
+	}
+}
+
+func TestCheck_Schedule(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := Check(townRoot); err != nil {
+		t.Errorf("unconfigured town should be open: %v", err)
+	}
+
+	writeSettings(t, townRoot, closedNow())
+	err := Check(townRoot)
+	if !errors.Is(err, ErrOutsideActiveHours) || !strings.Contains(err.Error(), "spawning is closed until") {
+		t.Errorf("Check = %v", err)
+	}
+
+	// Invalid settings don't block spawning.
+	writeSettings(t, townRoot, &config.ActiveHoursConfig{Windows: []config.ActiveWindow{{Start: "soon"}}})
+	if err := Check(townRoot); err != nil {
+		t.Errorf("invalid settings should not block: %v", err)
+	}
+}
+
+func TestCheck_Override(t *testing.T) {
+	townRoot := t.TempDir()
+	writeSettings(t, townRoot, closedNow())
+	now := time.Now()
+
+	if err := SetOverride(townRoot, &Override{Mode: ModeOpen, Until: now.Add(time.Hour), SetAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(townRoot); err != nil {
+		t.Errorf("open override should allow spawning: %v", err)
+	}
+	st, err := Current(townRoot, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := st.Summary(now); !strings.HasPrefix(got, "open by override until ") || !strings.HasSuffix(got, "(schedule: closed)") {
+		t.Errorf("Summary = %q", got)
+	}
+
+	// Expired overrides are ignored.
+	if err := SetOverride(townRoot, &Override{Mode: ModeOpen, Until: now.Add(-time.Minute), SetAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(townRoot); !errors.Is(err, ErrOutsideActiveHours) {
+		t.Errorf("expired override should fall back to the schedule: %v", err)
+	}
+
+	// A closed override wins over an unconfigured (always open) town.
+	writeSettings(t, townRoot, nil)
+	if err := SetOverride(townRoot, &Override{Mode: ModeClosed, SetAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(townRoot); err == nil || !strings.Contains(err.Error(), "closed by override until cleared") {
+		t.Errorf("Check = %v", err)
+	}
+	if cleared, err := ClearOverride(townRoot); !cleared || err != nil {
+		t.Errorf("ClearOverride = %v, %v", cleared, err)
+	}
+	if err := Check(townRoot); err != nil {
+		t.Errorf("after clearing: %v", err)
+	}
+	if cleared, _ := ClearOverride(townRoot); cleared {
+		t.Error("second clear should report nothing to clear")
+	}
+}
+
+func TestSetOverride_InvalidMode(t *testing.T) {
+	if err := SetOverride(t.TempDir(), &Override{Mode: "maybe"}); err == nil {
+		t.Error("expected error for invalid mode")
+	}
+}
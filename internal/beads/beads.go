@@ -137,12 +137,29 @@ type ListOptions struct {
 // CreateOptions specifies options for creating an issue.
 type CreateOptions struct {
 	Title       string
-	Type        string // "task", "bug", "feature", "epic"
+	Type        string // "task", "bug", "feature", "epic"; deprecated, sent as a gt:<type> label
+	IssueType   string // bd's own issue type, sent as --type; empty leaves bd's default
 	Priority    int    // 0-4
 	Description string
 	Parent      string
-	Actor       string // Who is creating this issue (populates created_by)
-	Ephemeral   bool   // Create as ephemeral (wisp) - not exported to JSONL
+	Assignee    string
+	Actor       string   // Who is creating this issue (populates created_by)
+	Ephemeral   bool     // Create as ephemeral (wisp) - not exported to JSONL
+	Labels      []string // Labels besides the gt:<type> one
+}
+
+// labelsFlag returns the --labels flag for the issue's labels, or "" if
+// it has none.
+func (opts CreateOptions) labelsFlag() string {
+	labels := opts.Labels
+	// Type is deprecated: convert to gt:<type> label
+	if opts.Type != "" {
+		labels = append([]string{"gt:" + opts.Type}, labels...)
+	}
+	if len(labels) == 0 {
+		return ""
+	}
+	return "--labels=" + strings.Join(labels, ",")
 }
 
 // UpdateOptions specifies options for updating an issue.
@@ -171,6 +188,8 @@ type Beads struct {
 	beadsDir string // Optional BEADS_DIR override for cross-database access
 	isolated bool   // If true, suppress inherited beads env vars (for test isolation)
 
+	subsystem string // Names this wrapper's writes in the agent history (see WithSubsystem)
+
 	// Lazy-cached town root for routing resolution.
 	// Populated on first call to getTownRoot() to avoid filesystem walk on every operation.
 	townRoot     string
@@ -272,6 +291,9 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	if isBDWrite(args) {
+		defer invalidateAgentCache()
+	}
 	err := cmd.Run()
 	if err != nil {
 		return nil, b.wrapError(err, stderr.String(), args)
@@ -316,6 +338,9 @@ func (b *Beads) runWithRouting(args ...string) ([]byte, error) { //nolint:unpara
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	if isBDWrite(args) {
+		defer invalidateAgentCache()
+	}
 	err := cmd.Run()
 	if err != nil {
 		return nil, b.wrapError(err, stderr.String(), args)
@@ -614,9 +639,11 @@ func (b *Beads) Create(opts CreateOptions) (*Issue, error) {
 	if opts.Title != "" {
 		args = append(args, "--title="+opts.Title)
 	}
-	// Type is deprecated: convert to gt:<type> label
-	if opts.Type != "" {
-		args = append(args, "--labels=gt:"+opts.Type)
+	if labels := opts.labelsFlag(); labels != "" {
+		args = append(args, labels)
+	}
+	if opts.IssueType != "" {
+		args = append(args, "--type="+opts.IssueType)
 	}
 	if opts.Priority >= 0 {
 		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
@@ -627,6 +654,9 @@ func (b *Beads) Create(opts CreateOptions) (*Issue, error) {
 	if opts.Parent != "" {
 		args = append(args, "--parent="+opts.Parent)
 	}
+	if opts.Assignee != "" {
+		args = append(args, "--assignee="+opts.Assignee)
+	}
 	if opts.Ephemeral {
 		args = append(args, "--ephemeral")
 	}
@@ -670,9 +700,11 @@ func (b *Beads) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
 	if opts.Title != "" {
 		args = append(args, "--title="+opts.Title)
 	}
-	// Type is deprecated: convert to gt:<type> label
-	if opts.Type != "" {
-		args = append(args, "--labels=gt:"+opts.Type)
+	if labels := opts.labelsFlag(); labels != "" {
+		args = append(args, labels)
+	}
+	if opts.IssueType != "" {
+		args = append(args, "--type="+opts.IssueType)
 	}
 	if opts.Priority >= 0 {
 		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
@@ -683,6 +715,9 @@ func (b *Beads) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
 	if opts.Parent != "" {
 		args = append(args, "--parent="+opts.Parent)
 	}
+	if opts.Assignee != "" {
+		args = append(args, "--assignee="+opts.Assignee)
+	}
 	// Default Actor from BD_ACTOR env var if not specified
 	// Uses getActor() to respect isolated mode (tests)
 	actor := opts.Actor
@@ -708,8 +743,14 @@ func (b *Beads) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
 
 // Update updates an existing issue.
 func (b *Beads) Update(id string, opts UpdateOptions) error {
-	args := []string{"update", id}
+	args := append([]string{"update", id}, opts.Flags()...)
+	_, err := b.run(args...)
+	return err
+}
 
+// Flags returns the bd update flags for the options.
+func (opts UpdateOptions) Flags() []string {
+	var args []string
 	if opts.Title != nil {
 		args = append(args, "--title="+*opts.Title)
 	}
@@ -738,9 +779,7 @@ func (b *Beads) Update(id string, opts UpdateOptions) error {
 			args = append(args, "--remove-label="+label)
 		}
 	}
-
-	_, err := b.run(args...)
-	return err
+	return args
 }
 
 // Close closes one or more issues.
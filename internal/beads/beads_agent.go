@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gofrs/flock"
 
@@ -16,7 +18,7 @@ import (
 
 // lockAgentBead acquires an exclusive file lock for a specific agent bead ID.
 // This prevents concurrent read-modify-write races in methods like
-// CreateOrReopenAgentBead, ResetAgentBeadForReuse, and UpdateAgentDescriptionFields.
+// CreateOrReopenAgentBead, ResetAgentBeadForReuse, and UpdateAgentFields.
 // Caller must defer fl.Unlock().
 func (b *Beads) lockAgentBead(id string) (*flock.Flock, error) {
 	lockDir := filepath.Join(b.getResolvedBeadsDir(), ".locks")
@@ -32,16 +34,41 @@ func (b *Beads) lockAgentBead(id string) (*flock.Flock, error) {
 }
 
 // AgentFields holds structured fields for agent beads.
-// These are stored as "key: value" lines in the description.
+// These are stored as a versioned JSON block in the description (see
+// FormatAgentDescription); older beads hold them as "key: value" lines.
 type AgentFields struct {
-	RoleType          string // polecat, witness, refinery, deacon, mayor
-	Rig               string // Rig name (empty for global agents like mayor/deacon)
-	AgentState        string // spawning, working, done, stuck
-	HookBead          string // Currently pinned work bead ID
-	CleanupStatus     string // ZFC: polecat self-reports git state (clean, has_uncommitted, has_stash, has_unpushed)
-	ActiveMR          string // Currently active merge request bead ID (for traceability)
-	NotificationLevel string // DND mode: verbose, normal, muted (default: normal)
-	Mode              string // Execution mode: "" (normal) or "ralph" (Ralph Wiggum loop)
+	RoleType          string `json:"role_type"`                     // polecat, witness, refinery, deacon, mayor
+	Rig               string `json:"rig"`                           // Rig name (empty for global agents like mayor/deacon)
+	AgentState        string `json:"agent_state"`                   // spawning, working, done, stuck
+	HookBead          string `json:"hook_bead"`                     // Currently pinned work bead ID
+	CleanupStatus     string `json:"cleanup_status"`                // ZFC: polecat self-reports git state (clean, has_uncommitted, has_stash, has_unpushed)
+	ActiveMR          string `json:"active_mr"`                     // Currently active merge request bead ID (for traceability)
+	NotificationLevel string `json:"notification_level"`            // DND mode: verbose, normal, muted (default: normal)
+	Mode              string `json:"mode,omitempty"`                // Execution mode: "" (normal) or "ralph" (Ralph Wiggum loop)
+	CircuitState      string `json:"circuit_state,omitempty"`       // Circuit breaker state for polecats: closed, open, half_open ("" = closed)
+	FailureCount      int    `json:"failure_count,omitempty"`       // Failures counted against the circuit since it last closed
+	CircuitOpenedAt   string `json:"circuit_opened_at,omitempty"`   // RFC3339 time the circuit last opened; its cooldown runs from here
+	TripCount         int    `json:"trip_count,omitempty"`          // Times the circuit opened since it last closed; lengthens the cooldown
+	TransientFailures int    `json:"transient_failures,omitempty"`  // Of FailureCount, failures classified transient (rate limits, timeouts)
+	LastFailureReason string `json:"last_failure_reason,omitempty"` // Classification of the most recent failure: transient, deterministic, unknown
+	LastFailureAt     string `json:"last_failure_at,omitempty"`     // RFC3339 time of the most recent failure
+	CircuitNote       string `json:"circuit_note,omitempty"`        // Last manual circuit override: who reset or tripped it, when and why
+	NukeVeto          string `json:"nuke_veto,omitempty"`           // Why a remediation hook vetoed auto-nuking the tripped polecat ("" = no veto)
+	Quarantine        string `json:"quarantine,omitempty"`          // Why the tripped polecat is held for a human instead of nuked ("" = not quarantined)
+	QuarantinedAt     string `json:"quarantined_at,omitempty"`      // RFC3339 time the polecat was quarantined
+	SuccessStreak     int    `json:"success_streak,omitempty"`      // Probe assignments landed in a row while half_open
+	ProbationUntil    string `json:"probation_until,omitempty"`     // RFC3339 end of probation after the circuit closed; a failure before it reopens the circuit
+	ResourceUsage     string `json:"resource_usage,omitempty"`      // Latest sample of the session's processes, e.g. "cpu 85.0%, rss 1.2GB, fds 310, procs 6"
+	ResourceCPUTicks  int64  `json:"resource_cpu_ticks,omitempty"`  // Session CPU time at the latest sample, in clock ticks; the next sample's CPU % is measured from it
+	ResourceSampledAt string `json:"resource_sampled_at,omitempty"` // RFC3339 time of the latest resource sample
+	ResourceStrikes   int    `json:"resource_strikes,omitempty"`    // Consecutive resource samples over the rig's budget
+	OutputFingerprint string `json:"output_fingerprint,omitempty"`  // Hash of the session's last pane lines, for stall detection
+	OutputChangedAt   string `json:"output_changed_at,omitempty"`   // RFC3339 time the output fingerprint last changed
+	StallState        string `json:"stall_state,omitempty"`         // Stall handling since the output froze: nudged, escalated, tripped ("" = not stalled)
+	StallStateAt      string `json:"stall_state_at,omitempty"`      // RFC3339 time StallState was set
+	BootMs            int64  `json:"boot_ms,omitempty"`             // Wall-clock ms from session creation to ready prompt on last spawn (0 = unknown)
+	BootDiagnosis     string `json:"boot_diagnosis,omitempty"`      // Comma-separated causes when the last boot exceeded its budget ("" = within budget)
+	Revision          int64  `json:"revision,omitempty"`            // Writes to the fields so far; UpdateAgentFields' compare-and-swap checks it
 	// Note: RoleBead field removed - role definitions are now config-based.
 	// See internal/config/roles/*.toml and config-based-roles.md.
 }
@@ -53,60 +80,92 @@ const (
 	NotifyMuted   = "muted"   // Silent/DND mode - batch for later
 )
 
-// FormatAgentDescription creates a description string from agent fields.
-func FormatAgentDescription(title string, fields *AgentFields) string {
-	if fields == nil {
-		return title
-	}
-
-	var lines []string
-	lines = append(lines, title)
-	lines = append(lines, "")
-	lines = append(lines, fmt.Sprintf("role_type: %s", fields.RoleType))
+// Circuit breaker states recorded on polecat agent beads.
+const (
+	CircuitClosed   = "closed"    // Normal operation, work may be assigned
+	CircuitOpen     = "open"      // Tripped after repeated failures, no new work
+	CircuitHalfOpen = "half_open" // Probation: a single probe assignment is allowed
+)
 
-	if fields.Rig != "" {
-		lines = append(lines, fmt.Sprintf("rig: %s", fields.Rig))
-	} else {
-		lines = append(lines, "rig: null")
-	}
+// Stall handling states recorded on polecat agent beads while their output
+// is frozen.
+const (
+	StallNudged    = "nudged"    // Output froze; the polecat was nudged once
+	StallEscalated = "escalated" // Still frozen after the nudge; the Mayor was told
+	StallTripped   = "tripped"   // Still frozen after the nudge; its circuit was opened
+)
 
-	lines = append(lines, fmt.Sprintf("agent_state: %s", fields.AgentState))
+// Failure classifications recorded against a polecat's circuit.
+const (
+	FailureTransient     = "transient"     // Rate limits, network timeouts: likely to pass on retry
+	FailureDeterministic = "deterministic" // The work itself fails the same way each time
+	FailureUnknown       = "unknown"       // No evidence either way (e.g., session died silently)
+)
 
-	if fields.HookBead != "" {
-		lines = append(lines, fmt.Sprintf("hook_bead: %s", fields.HookBead))
-	} else {
-		lines = append(lines, "hook_bead: null")
+// IsValidCircuitState reports whether s is a recognized circuit_state value.
+// The empty string is valid and means the circuit has never tripped (closed).
+func IsValidCircuitState(s string) bool {
+	switch s {
+	case "", CircuitClosed, CircuitOpen, CircuitHalfOpen:
+		return true
 	}
+	return false
+}
 
-	// Note: role_bead field no longer written - role definitions are config-based
-
-	if fields.CleanupStatus != "" {
-		lines = append(lines, fmt.Sprintf("cleanup_status: %s", fields.CleanupStatus))
-	} else {
-		lines = append(lines, "cleanup_status: null")
+// FormatAgentDescription creates a description string from agent fields:
+// the title, then the fields in a versioned JSON block (see
+// AgentFieldsSchemaVersion).
+func FormatAgentDescription(title string, fields *AgentFields) string {
+	if fields == nil {
+		return title
 	}
+	return title + "\n\n" + formatAgentFieldsBlock(fields)
+}
 
-	if fields.ActiveMR != "" {
-		lines = append(lines, fmt.Sprintf("active_mr: %s", fields.ActiveMR))
-	} else {
-		lines = append(lines, "active_mr: null")
+// ReplaceAgentFields returns an agent bead's description with its fields
+// replaced by fields, keeping the rest of its text: the fields block is
+// rewritten in place, or a legacy description's field lines are dropped
+// and the block added at the end. Lines that aren't fields (a dog's
+// location, which mail routing reads) survive the rewrite.
+func ReplaceAgentFields(description string, fields *AgentFields) string {
+	block := formatAgentFieldsBlock(fields)
+	lines := strings.Split(description, "\n")
+	if start, end, ok := agentFieldsBlockLines(lines); ok {
+		return strings.Join(append(append(lines[:start:start], block), lines[end+1:]...), "\n")
 	}
-
-	if fields.NotificationLevel != "" {
-		lines = append(lines, fmt.Sprintf("notification_level: %s", fields.NotificationLevel))
-	} else {
-		lines = append(lines, "notification_level: null")
+	var kept []string
+	for _, line := range lines {
+		key, _, found := strings.Cut(strings.TrimSpace(line), ":")
+		if _, isField := agentFieldIndexes[strings.TrimSpace(key)]; found && isField {
+			continue
+		}
+		kept = append(kept, line)
 	}
+	return strings.TrimRight(strings.Join(kept, "\n"), "\n") + "\n\n" + block
+}
 
-	if fields.Mode != "" {
-		lines = append(lines, fmt.Sprintf("mode: %s", fields.Mode))
-	}
+// ParseAgentFields extracts agent fields from an issue's description. It
+// reads the JSON fields block FormatAgentDescription writes and, for beads
+// not yet migrated (gt migrate-agent-fields), the legacy "key: value" lines.
+//
+// Each description is parsed once per process (see cachedAgentFields); the
+// caller gets its own copy of the fields.
+func ParseAgentFields(description string) *AgentFields {
+	fields := *cachedAgentFields(description, parseAgentFields)
+	return &fields
+}
 
-	return strings.Join(lines, "\n")
+// parseAgentFields is ParseAgentFields without the cache.
+func parseAgentFields(description string) *AgentFields {
+	if fields, _, ok := parseAgentFieldsBlock(description); ok {
+		return fields
+	}
+	return parseLegacyAgentFields(description)
 }
 
-// ParseAgentFields extracts agent fields from an issue's description.
-func ParseAgentFields(description string) *AgentFields {
+// parseLegacyAgentFields extracts agent fields from "key: value" lines, as
+// descriptions held them before the JSON fields block.
+func parseLegacyAgentFields(description string) *AgentFields {
 	fields := &AgentFields{}
 
 	for _, line := range strings.Split(description, "\n") {
@@ -145,6 +204,52 @@ func ParseAgentFields(description string) *AgentFields {
 			fields.NotificationLevel = value
 		case "mode":
 			fields.Mode = value
+		case "circuit_state":
+			fields.CircuitState = value
+		case "failure_count":
+			fields.FailureCount, _ = strconv.Atoi(value)
+		case "circuit_opened_at":
+			fields.CircuitOpenedAt = value
+		case "trip_count":
+			fields.TripCount, _ = strconv.Atoi(value)
+		case "transient_failures":
+			fields.TransientFailures, _ = strconv.Atoi(value)
+		case "last_failure_reason":
+			fields.LastFailureReason = value
+		case "last_failure_at":
+			fields.LastFailureAt = value
+		case "circuit_note":
+			fields.CircuitNote = value
+		case "nuke_veto":
+			fields.NukeVeto = value
+		case "quarantine":
+			fields.Quarantine = value
+		case "quarantined_at":
+			fields.QuarantinedAt = value
+		case "success_streak":
+			fields.SuccessStreak, _ = strconv.Atoi(value)
+		case "probation_until":
+			fields.ProbationUntil = value
+		case "resource_usage":
+			fields.ResourceUsage = value
+		case "resource_cpu_ticks":
+			fields.ResourceCPUTicks, _ = strconv.ParseInt(value, 10, 64)
+		case "resource_sampled_at":
+			fields.ResourceSampledAt = value
+		case "resource_strikes":
+			fields.ResourceStrikes, _ = strconv.Atoi(value)
+		case "output_fingerprint":
+			fields.OutputFingerprint = value
+		case "output_changed_at":
+			fields.OutputChangedAt = value
+		case "stall_state":
+			fields.StallState = value
+		case "stall_state_at":
+			fields.StallStateAt = value
+		case "boot_ms":
+			fields.BootMs, _ = strconv.ParseInt(value, 10, 64)
+		case "boot_diagnosis":
+			fields.BootDiagnosis = value
 		}
 	}
 
@@ -203,6 +308,11 @@ func (b *Beads) CreateAgentBead(id, title string, fields *AgentFields) (*Issue,
 	if err := json.Unmarshal(out, &issue); err != nil {
 		return nil, fmt.Errorf("parsing bd create output: %w", err)
 	}
+	var revision int64
+	if fields != nil {
+		revision = fields.Revision
+	}
+	b.recordAgentHistory(id, AgentOpCreate, revision, agentFieldChanges(nil, fields))
 
 	// Note: role slot no longer set - role definitions are config-based
 
@@ -215,7 +325,7 @@ func (b *Beads) CreateAgentBead(id, title string, fields *AgentFields) (*Issue,
 		if targetDir != b.getResolvedBeadsDir() {
 			target = NewWithBeadsDir(filepath.Dir(targetDir), targetDir)
 		}
-		if err := target.SetHookBead(id, fields.HookBead); err != nil {
+		if err := target.setHookSlot(id, fields.HookBead); err != nil {
 			// Non-fatal: warn but continue - description text has the backup
 			style.PrintWarning("could not set hook slot: %v", err)
 		}
@@ -265,7 +375,7 @@ func (b *Beads) CreateOrReopenAgentBead(id, title string, fields *AgentFields) (
 	targetDir := ResolveRoutingTarget(b.getTownRoot(), id, b.getResolvedBeadsDir())
 	target := b
 	if targetDir != b.getResolvedBeadsDir() {
-		target = NewWithBeadsDir(filepath.Dir(targetDir), targetDir)
+		target = NewWithBeadsDir(filepath.Dir(targetDir), targetDir).WithSubsystem(b.getSubsystem())
 	}
 
 	existing, showErr := target.Show(id)
@@ -288,7 +398,15 @@ func (b *Beads) CreateOrReopenAgentBead(id, title string, fields *AgentFields) (
 	}
 
 	// Update the bead with new fields and ensure type=agent (gt-dr02sy:
-	// old beads may have type=task, which breaks bd slot set).
+	// old beads may have type=task, which breaks bd slot set). The fields
+	// replace the old ones but carry on their revision, so a compare-and-swap
+	// against the previous lifecycle's fields fails.
+	previous := ParseAgentFields(existing.Description)
+	if fields != nil {
+		respawned := *fields
+		respawned.Revision = previous.Revision + 1
+		fields = &respawned
+	}
 	description := FormatAgentDescription(title, fields)
 	updateOpts := UpdateOptions{
 		Title:       &title,
@@ -302,16 +420,22 @@ func (b *Beads) CreateOrReopenAgentBead(id, title string, fields *AgentFields) (
 	if _, err := target.run("update", id, "--type=agent"); err != nil {
 		return nil, fmt.Errorf("fixing agent bead type: %w", err)
 	}
+	reopened := fields
+	if reopened == nil {
+		reopened = &AgentFields{}
+	}
+	previous.HookBead = existing.HookBead
+	target.recordAgentHistory(id, AgentOpReopen, reopened.Revision, agentFieldChanges(previous, reopened))
 
 	// Note: role slot no longer set - role definitions are config-based
 
 	// Clear any existing hook slot (handles stale state from previous lifecycle)
 	// Use target Beads instance with proper BEADS_DIR routing (gt-wrnwq).
-	_ = target.ClearHookBead(id)
+	_ = target.clearHookSlot(id)
 
 	// Set the hook slot if specified
 	if fields != nil && fields.HookBead != "" {
-		if err := target.SetHookBead(id, fields.HookBead); err != nil {
+		if err := target.setHookSlot(id, fields.HookBead); err != nil {
 			// Non-fatal: warn but continue - description text has the backup
 			style.PrintWarning("could not set hook slot: %v", err)
 		}
@@ -344,7 +468,7 @@ func (b *Beads) ResetAgentBeadForReuse(id, reason string) error {
 	targetDir := ResolveRoutingTarget(b.getTownRoot(), id, b.getResolvedBeadsDir())
 	target := b
 	if targetDir != b.getResolvedBeadsDir() {
-		target = NewWithBeadsDir(filepath.Dir(targetDir), targetDir)
+		target = NewWithBeadsDir(filepath.Dir(targetDir), targetDir).WithSubsystem(b.getSubsystem())
 	}
 
 	// Get current issue to preserve immutable fields (title, role_type, rig)
@@ -355,19 +479,25 @@ func (b *Beads) ResetAgentBeadForReuse(id, reason string) error {
 
 	// Parse existing fields and clear mutable ones
 	fields := ParseAgentFields(issue.Description)
+	if issue.HookBead != "" {
+		fields.HookBead = issue.HookBead
+	}
+	before := *fields
 	fields.HookBead = ""      // Clear hook_bead
 	fields.ActiveMR = ""      // Clear active_mr
 	fields.CleanupStatus = "" // Clear cleanup_status
 	fields.AgentState = "nuked"
+	fields.Revision++
 
 	// Update description with cleared fields
-	description := FormatAgentDescription(issue.Title, fields)
+	description := ReplaceAgentFields(issue.Description, fields)
 	if err := target.Update(id, UpdateOptions{Description: &description}); err != nil {
 		return fmt.Errorf("resetting agent bead fields: %w", err)
 	}
+	target.recordAgentHistory(id, AgentOpReset, fields.Revision, agentFieldChanges(&before, fields))
 
 	// Also clear the hook slot in the database
-	_ = target.ClearHookBead(id)
+	_ = target.clearHookSlot(id)
 
 	return nil
 }
@@ -383,6 +513,14 @@ func (b *Beads) ResetAgentBeadForReuse(id, reason string) error {
 // Previously, this function embedded these fields in the description text,
 // which caused inconsistencies with bd slot commands (see GH #gt-9v52).
 func (b *Beads) UpdateAgentState(id string, state string, hookBead *string) error {
+	// Lock the agent bead, and bump its revision once the writes are done,
+	// so an UpdateAgentFields compare-and-swap can't apply over them.
+	fl, lockErr := b.lockAgentBead(id)
+	if lockErr != nil {
+		return fmt.Errorf("locking agent bead %s: %w", id, lockErr)
+	}
+	defer func() { _ = fl.Unlock() }()
+
 	// Update agent state using bd agent state command
 	// Use runWithRouting so bd can resolve cross-prefix agent beads (e.g., wa-*
 	// agent beads from hq context) via routes.jsonl instead of BEADS_DIR.
@@ -390,35 +528,26 @@ func (b *Beads) UpdateAgentState(id string, state string, hookBead *string) erro
 	if err != nil {
 		return fmt.Errorf("updating agent state: %w", err)
 	}
+	writes := []agentSlotWrite{{AgentOpState, AgentFieldChange{Field: "agent_state", New: state}}}
 
 	// Update hook_bead if provided
 	// Use runWithRouting for slot ops so bd can resolve cross-prefix beads
 	// (e.g., hq-* hook beads on gt-* agent beads) via routes.jsonl.
 	if hookBead != nil {
 		if *hookBead != "" {
-			// Set the hook using bd slot set
-			_, err = b.runWithRouting("slot", "set", id, "hook", *hookBead)
-			if err != nil {
-				// If slot is already occupied, clear it first then retry
-				// This handles re-slinging scenarios where we're updating the hook
-				errStr := err.Error()
-				if strings.Contains(errStr, "already occupied") {
-					_, _ = b.runWithRouting("slot", "clear", id, "hook")
-					_, err = b.runWithRouting("slot", "set", id, "hook", *hookBead)
-				}
-				if err != nil {
-					return fmt.Errorf("setting hook: %w", err)
-				}
-			}
+			err = b.setHookSlot(id, *hookBead)
+			writes = append(writes, agentSlotWrite{AgentOpHook, AgentFieldChange{Field: "hook_bead", New: *hookBead}})
 		} else {
-			// Clear the hook
-			_, err = b.runWithRouting("slot", "clear", id, "hook")
-			if err != nil {
-				return fmt.Errorf("clearing hook: %w", err)
-			}
+			err = b.clearHookSlot(id)
+			writes = append(writes, agentSlotWrite{AgentOpUnhook, AgentFieldChange{Field: "hook_bead", New: ""}})
+		}
+		if err != nil {
+			b.recordAgentSlotWrites(id, writes[:1]...)
+			return err
 		}
 	}
 
+	b.recordAgentSlotWrites(id, writes...)
 	return nil
 }
 
@@ -426,7 +555,27 @@ func (b *Beads) UpdateAgentState(id string, state string, hookBead *string) erro
 // This is a convenience wrapper that only sets the hook without changing agent_state.
 // Per gt-zecmc: agent_state ("running", "dead", "idle") is observable from tmux
 // and should not be recorded in beads ("discover, don't track" principle).
+//
+// It takes the agent bead lock and, once the hook is set, bumps the fields'
+// Revision, so an UpdateAgentFields compare-and-swap can't apply over the
+// hook change.
 func (b *Beads) SetHookBead(agentBeadID, hookBeadID string) error {
+	fl, lockErr := b.lockAgentBead(agentBeadID)
+	if lockErr != nil {
+		return fmt.Errorf("locking agent bead %s: %w", agentBeadID, lockErr)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	if err := b.setHookSlot(agentBeadID, hookBeadID); err != nil {
+		return err
+	}
+	b.recordAgentSlotWrites(agentBeadID, agentSlotWrite{AgentOpHook, AgentFieldChange{Field: "hook_bead", New: hookBeadID}})
+	return nil
+}
+
+// setHookSlot is SetHookBead without the history entry, for writes that
+// record the hook change themselves.
+func (b *Beads) setHookSlot(agentBeadID, hookBeadID string) error {
 	// Set the hook using bd slot set
 	// Use runWithRouting so bd can resolve cross-prefix beads (e.g., hq-* hook
 	// beads on gt-* agent beads) via routes.jsonl instead of BEADS_DIR.
@@ -446,8 +595,24 @@ func (b *Beads) SetHookBead(agentBeadID, hookBeadID string) error {
 }
 
 // ClearHookBead clears the hook_bead slot on an agent bead.
-// Used when work is complete or unslung.
+// Used when work is complete or unslung. Like SetHookBead, it bumps the
+// fields' Revision under the agent bead lock.
 func (b *Beads) ClearHookBead(agentBeadID string) error {
+	fl, lockErr := b.lockAgentBead(agentBeadID)
+	if lockErr != nil {
+		return fmt.Errorf("locking agent bead %s: %w", agentBeadID, lockErr)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	if err := b.clearHookSlot(agentBeadID); err != nil {
+		return err
+	}
+	b.recordAgentSlotWrites(agentBeadID, agentSlotWrite{AgentOpUnhook, AgentFieldChange{Field: "hook_bead", New: ""}})
+	return nil
+}
+
+// clearHookSlot is ClearHookBead without the history entry.
+func (b *Beads) clearHookSlot(agentBeadID string) error {
 	// Use runWithRouting so bd can resolve cross-prefix beads via routes.jsonl.
 	_, err := b.runWithRouting("slot", "clear", agentBeadID, "hook")
 	if err != nil {
@@ -465,6 +630,13 @@ type AgentFieldUpdates struct {
 	ActiveMR          *string
 	NotificationLevel *string
 	Mode              *string
+	BootMs            *int64
+	BootDiagnosis     *string
+	CircuitState      *string
+	FailureCount      *int
+	CircuitOpenedAt   *string
+	TripCount         *int
+	CircuitNote       *string
 }
 
 // UpdateAgentDescriptionFields atomically updates one or more agent description
@@ -480,37 +652,261 @@ func (b *Beads) UpdateAgentDescriptionFields(id string, updates AgentFieldUpdate
 		}
 	}
 
-	// Lock the agent bead to prevent concurrent read-modify-write races.
-	// Without this, concurrent callers updating different fields could overwrite
-	// each other's changes. See gt-joazs.
-	fl, lockErr := b.lockAgentBead(id)
-	if lockErr != nil {
-		return fmt.Errorf("locking agent bead %s: %w", id, lockErr)
+	if updates.CircuitState != nil && !IsValidCircuitState(*updates.CircuitState) {
+		return fmt.Errorf("invalid circuit state %q: must be closed, open, or half_open", *updates.CircuitState)
 	}
-	defer func() { _ = fl.Unlock() }()
 
-	issue, err := b.Show(id)
-	if err != nil {
-		return err
+	// The read-modify-write happens under the agent bead lock, so concurrent
+	// callers updating different fields don't overwrite each other's
+	// changes. See gt-joazs.
+	_, err := b.modifyAgentFields(id, AgentOpUpdate, func(fields *AgentFields) {
+		if updates.CleanupStatus != nil {
+			fields.CleanupStatus = *updates.CleanupStatus
+		}
+		if updates.ActiveMR != nil {
+			fields.ActiveMR = *updates.ActiveMR
+		}
+		if updates.NotificationLevel != nil {
+			fields.NotificationLevel = *updates.NotificationLevel
+		}
+		if updates.Mode != nil {
+			fields.Mode = *updates.Mode
+		}
+		if updates.BootMs != nil {
+			fields.BootMs = *updates.BootMs
+		}
+		if updates.BootDiagnosis != nil {
+			fields.BootDiagnosis = *updates.BootDiagnosis
+		}
+		if updates.CircuitState != nil {
+			fields.CircuitState = *updates.CircuitState
+		}
+		if updates.FailureCount != nil {
+			fields.FailureCount = *updates.FailureCount
+		}
+		if updates.CircuitOpenedAt != nil {
+			fields.CircuitOpenedAt = *updates.CircuitOpenedAt
+		}
+		if updates.TripCount != nil {
+			fields.TripCount = *updates.TripCount
+		}
+		if updates.CircuitNote != nil {
+			fields.CircuitNote = *updates.CircuitNote
+		}
+	})
+	return err
+}
+
+// UpdateAgentCircuitState sets a polecat's circuit breaker state
+// (closed, open or half_open). Opening the circuit stamps circuit_opened_at,
+// starting its cooldown; closing it clears the stamp.
+func (b *Beads) UpdateAgentCircuitState(id string, state string) error {
+	if !IsValidCircuitState(state) {
+		return fmt.Errorf("invalid circuit state %q: must be closed, open, or half_open", state)
 	}
+	_, err := b.modifyAgentFields(id, AgentOpCircuitState, func(fields *AgentFields) {
+		ApplyCircuitState(fields, state, time.Now())
+	})
+	return err
+}
 
-	fields := ParseAgentFields(issue.Description)
+// ApplyCircuitState is UpdateAgentCircuitState's change to fields, at time
+// at.
+func ApplyCircuitState(fields *AgentFields, state string, at time.Time) {
+	fields.CircuitState = state
+	switch state {
+	case CircuitOpen:
+		fields.CircuitOpenedAt = at.UTC().Format(time.RFC3339)
+	case CircuitClosed, "":
+		fields.CircuitOpenedAt = ""
+	}
+}
+
+// IncrementAgentFailureCount adds one failure, classified as reason
+// (FailureTransient, FailureDeterministic or FailureUnknown), to a polecat's
+// circuit and returns the fields as updated. The read and write happen under
+// the agent bead lock, so concurrent failures are all counted.
+func (b *Beads) IncrementAgentFailureCount(id, reason string) (*AgentFields, error) {
+	return b.modifyAgentFields(id, AgentOpFailure, func(fields *AgentFields) {
+		ApplyAgentFailure(fields, reason, time.Now())
+	})
+}
 
-	if updates.CleanupStatus != nil {
-		fields.CleanupStatus = *updates.CleanupStatus
+// ApplyAgentFailure is IncrementAgentFailureCount's change to fields, for a
+// failure at time at.
+func ApplyAgentFailure(fields *AgentFields, reason string, at time.Time) {
+	fields.FailureCount++
+	if reason == FailureTransient {
+		fields.TransientFailures++
 	}
-	if updates.ActiveMR != nil {
-		fields.ActiveMR = *updates.ActiveMR
+	fields.LastFailureReason = reason
+	fields.LastFailureAt = at.UTC().Format(time.RFC3339)
+}
+
+// OpenAgentCircuit trips a polecat's circuit: the state becomes open,
+// circuit_opened_at is stamped and trip_count goes up by one unless the
+// circuit was already open. Any success streak or probation ends. Returns
+// the fields as updated.
+func (b *Beads) OpenAgentCircuit(id string) (*AgentFields, error) {
+	return b.modifyAgentFields(id, AgentOpOpenCircuit, func(fields *AgentFields) {
+		ApplyOpenCircuit(fields, time.Now())
+	})
+}
+
+// ApplyOpenCircuit is OpenAgentCircuit's change to fields, tripping the
+// circuit at time at.
+func ApplyOpenCircuit(fields *AgentFields, at time.Time) {
+	if fields.CircuitState != CircuitOpen {
+		fields.TripCount++
 	}
-	if updates.NotificationLevel != nil {
-		fields.NotificationLevel = *updates.NotificationLevel
+	fields.CircuitState = CircuitOpen
+	fields.CircuitOpenedAt = at.UTC().Format(time.RFC3339)
+	fields.SuccessStreak = 0
+	fields.ProbationUntil = ""
+}
+
+// RecordAgentSuccess counts landed work against a polecat's circuit and
+// returns the fields as updated. A half-open circuit adds one to its success
+// streak and closes once the streak reaches streak; any other circuit with
+// failures closes at once. A circuit that was open or half_open goes on
+// probation until probationUntil (zero for none).
+func (b *Beads) RecordAgentSuccess(id string, streak int, probationUntil time.Time) (*AgentFields, error) {
+	return b.modifyAgentFields(id, AgentOpSuccess, func(fields *AgentFields) {
+		ApplyAgentSuccess(fields, streak, probationUntil)
+	})
+}
+
+// ApplyAgentSuccess is RecordAgentSuccess's change to fields, for stores
+// that keep agent fields elsewhere.
+func ApplyAgentSuccess(fields *AgentFields, streak int, probationUntil time.Time) {
+	tripped := fields.CircuitState == CircuitOpen || fields.CircuitState == CircuitHalfOpen
+	if fields.CircuitState == CircuitHalfOpen {
+		fields.SuccessStreak++
+		if fields.SuccessStreak < streak {
+			return
+		}
+	} else if fields.FailureCount == 0 && !tripped {
+		return
+	}
+	fields.FailureCount = 0
+	fields.TransientFailures = 0
+	fields.LastFailureReason = ""
+	fields.LastFailureAt = ""
+	fields.CircuitState = CircuitClosed
+	fields.CircuitOpenedAt = ""
+	fields.TripCount = 0
+	fields.NukeVeto = ""
+	fields.SuccessStreak = 0
+	if tripped && !probationUntil.IsZero() {
+		fields.ProbationUntil = probationUntil.UTC().Format(time.RFC3339)
 	}
-	if updates.Mode != nil {
-		fields.Mode = *updates.Mode
+}
+
+// OnProbation reports whether a polecat whose circuit closed again is still
+// on probation at t.
+func (f *AgentFields) OnProbation(t time.Time) bool {
+	if f.ProbationUntil == "" {
+		return false
 	}
+	until, err := time.Parse(time.RFC3339, f.ProbationUntil)
+	return err == nil && t.Before(until)
+}
+
+// modifyAgentFields applies modify to an agent bead's fields under the agent
+// bead lock and returns the fields as written, recording the write in the
+// agent history as operation op.
+func (b *Beads) modifyAgentFields(id, op string, modify func(*AgentFields)) (*AgentFields, error) {
+	return b.updateAgentFields(id, AnyAgentRevision, op, func(fields *AgentFields) error {
+		modify(fields)
+		return nil
+	})
+}
+
+// ResetAgentFailureCount clears a polecat's failure and trip counts, closes
+// its circuit, lifts any nuke veto and ends any probation.
+func (b *Beads) ResetAgentFailureCount(id string) error {
+	_, err := b.modifyAgentFields(id, AgentOpCircuitReset, ApplyCircuitReset)
+	return err
+}
+
+// ApplyCircuitReset is ResetAgentFailureCount's change to fields.
+func ApplyCircuitReset(fields *AgentFields) {
+	fields.FailureCount = 0
+	fields.TransientFailures = 0
+	fields.LastFailureReason = ""
+	fields.LastFailureAt = ""
+	fields.CircuitState = CircuitClosed
+	fields.CircuitOpenedAt = ""
+	fields.TripCount = 0
+	fields.NukeVeto = ""
+	fields.SuccessStreak = 0
+	fields.ProbationUntil = ""
+}
+
+// RecordAgentResources records a resource sample of a polecat's session:
+// usage is its summary, cpuTicks the session's CPU time at sampledAt. A
+// sample over the rig's budget adds a strike; one within it clears them.
+// Returns the fields as updated, under the agent bead lock.
+func (b *Beads) RecordAgentResources(id, usage string, cpuTicks int64, sampledAt time.Time, overBudget bool) (*AgentFields, error) {
+	return b.modifyAgentFields(id, AgentOpResources, func(fields *AgentFields) {
+		fields.ResourceUsage = usage
+		fields.ResourceCPUTicks = cpuTicks
+		fields.ResourceSampledAt = sampledAt.UTC().Format(time.RFC3339)
+		if overBudget {
+			fields.ResourceStrikes++
+		} else {
+			fields.ResourceStrikes = 0
+		}
+	})
+}
+
+// RecordAgentOutput records the fingerprint of a polecat's latest pane
+// output. A new fingerprint restarts output_changed_at and clears any stall
+// state; an unchanged one leaves both as they were. Returns the fields as
+// updated, under the agent bead lock.
+func (b *Beads) RecordAgentOutput(id, fingerprint string, at time.Time) (*AgentFields, error) {
+	return b.modifyAgentFields(id, AgentOpOutput, func(fields *AgentFields) {
+		if fields.OutputFingerprint == fingerprint && fields.OutputChangedAt != "" {
+			return
+		}
+		fields.OutputFingerprint = fingerprint
+		fields.OutputChangedAt = at.UTC().Format(time.RFC3339)
+		fields.StallState = ""
+		fields.StallStateAt = ""
+	})
+}
+
+// SetAgentStallState records how a polecat with frozen output has been
+// handled (StallNudged, StallEscalated or StallTripped).
+func (b *Beads) SetAgentStallState(id, state string, at time.Time) error {
+	_, err := b.modifyAgentFields(id, AgentOpStall, func(fields *AgentFields) {
+		fields.StallState = state
+		fields.StallStateAt = at.UTC().Format(time.RFC3339)
+	})
+	return err
+}
 
-	description := FormatAgentDescription(issue.Title, fields)
-	return b.Update(id, UpdateOptions{Description: &description})
+// SetAgentNukeVeto records why a polecat must not be auto-nuked (a
+// remediation hook vetoed it); "" lifts the veto. ResetAgentFailureCount
+// lifts it too.
+func (b *Beads) SetAgentNukeVeto(id, reason string) error {
+	_, err := b.modifyAgentFields(id, AgentOpNukeVeto, func(fields *AgentFields) {
+		fields.NukeVeto = strings.Join(strings.Fields(reason), " ")
+	})
+	return err
+}
+
+// SetAgentQuarantine quarantines a tripped polecat that couldn't be nuked
+// without losing work, recording why and when; "" releases it.
+func (b *Beads) SetAgentQuarantine(id, reason string, at time.Time) error {
+	_, err := b.modifyAgentFields(id, AgentOpQuarantine, func(fields *AgentFields) {
+		fields.Quarantine = strings.Join(strings.Fields(reason), " ")
+		fields.QuarantinedAt = ""
+		if fields.Quarantine != "" {
+			fields.QuarantinedAt = at.UTC().Format(time.RFC3339)
+		}
+	})
+	return err
 }
 
 // UpdateAgentCleanupStatus updates the cleanup_status field in an agent bead.
@@ -527,6 +923,13 @@ func (b *Beads) UpdateAgentActiveMR(id string, activeMR string) error {
 	return b.UpdateAgentDescriptionFields(id, AgentFieldUpdates{ActiveMR: &activeMR})
 }
 
+// UpdateAgentBootTime records the last spawn's boot time and, when it exceeded
+// its budget, the diagnosed causes. An empty diagnosis clears a previous one.
+func (b *Beads) UpdateAgentBootTime(id string, boot time.Duration, diagnosis string) error {
+	ms := boot.Milliseconds()
+	return b.UpdateAgentDescriptionFields(id, AgentFieldUpdates{BootMs: &ms, BootDiagnosis: &diagnosis})
+}
+
 // UpdateAgentNotificationLevel updates the notification_level field in an agent bead.
 // Valid levels: verbose, normal, muted (DND mode).
 // Pass empty string to reset to default (normal).
@@ -570,8 +973,22 @@ func (b *Beads) GetAgentBead(id string) (*Issue, *AgentFields, error) {
 }
 
 // ListAgentBeads returns all agent beads in a single query.
-// Returns a map of agent bead ID to Issue.
+// Returns a map of agent bead ID to Issue. With an agent index (see
+// AgentIndexFile) the query runs against it instead of bd, and an index
+// out of date is rebuilt from bd's answer. The result is cached in-process
+// until the database changes (see agentCacheStamp).
 func (b *Beads) ListAgentBeads() (map[string]*Issue, error) {
+	beadsDir := b.getResolvedBeadsDir()
+	return cachedAgentList(beadsDir, agentCacheStamp(b.getTownRoot(), beadsDir), b.listAgentBeads)
+}
+
+// listAgentBeads is ListAgentBeads without the cache.
+func (b *Beads) listAgentBeads() (map[string]*Issue, error) {
+	if agents, ok := b.indexedAgents(""); ok {
+		return agents, nil
+	}
+
+	stamp := agentCacheStamp(b.getTownRoot(), b.getResolvedBeadsDir())
 	out, err := b.run("list", "--label=gt:agent", "--json")
 	if err != nil {
 		return nil, err
@@ -586,6 +1003,46 @@ func (b *Beads) ListAgentBeads() (map[string]*Issue, error) {
 	for _, issue := range issues {
 		result[issue.ID] = issue
 	}
+	b.reindexAgents(result, stamp)
 
 	return result, nil
 }
+
+// ListAgentBeadsWithField returns the agent beads whose description has
+// the field key set to value, or set at all when value is "". The filter
+// runs in bd, so a caller interested in a few agents (open circuits, say)
+// doesn't fetch and parse every agent in the database. It matches on the
+// description text, once per AgentFieldPatterns form while beads are being
+// migrated: callers should still check the parsed fields. With an agent
+// index (see AgentIndexFile), fields it has a column for are looked up
+// there instead.
+func (b *Beads) ListAgentBeadsWithField(key, value string) (map[string]*Issue, error) {
+	if where, ok := agentIndexWhere(key, value); ok {
+		if agents, ok := b.indexedAgents(where); ok {
+			return agents, nil
+		}
+	}
+
+	result := make(map[string]*Issue)
+	for _, pattern := range AgentFieldPatterns(key, value) {
+		out, err := b.run("list", "--label=gt:agent", "--json", "--limit=0", "--desc-contains="+pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		var issues []*Issue
+		if err := json.Unmarshal(out, &issues); err != nil {
+			return nil, fmt.Errorf("parsing bd list output: %w", err)
+		}
+		for _, issue := range issues {
+			result[issue.ID] = issue
+		}
+	}
+	return result, nil
+}
+
+// AgentFieldLine is how the legacy description format writes the field key
+// set to value; with value "" it is the line's prefix.
+func AgentFieldLine(key, value string) string {
+	return key + ": " + value
+}
@@ -0,0 +1,151 @@
+// Package beads provides the in-process cache of agent bead reads.
+package beads
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The witness, deacon, daemon and status commands list and parse every
+// agent bead on each sweep, mostly finding nothing changed. Two caches
+// keep that from costing a bd call and a parse per agent every time:
+//
+//   - ParseAgentFields keeps the fields parsed from each description it
+//     has seen, so an unchanged bead is parsed once per process.
+//   - ListAgentBeads keeps its last result per database while its files
+//     and Dolt manifests are unchanged (see agentCacheStamp), no write has
+//     gone through this package since, and it is under
+//     agentListCacheMaxAge old, in case a write changed no file time
+//     checked.
+
+// agentFieldsCacheSize bounds the parsed-fields cache. When full it is
+// emptied and refilled, which keeps only the descriptions still in use.
+const agentFieldsCacheSize = 4096
+
+// agentListCacheMaxAge is how long a cached agent listing is used without
+// re-reading it, however unchanged the database looks.
+const agentListCacheMaxAge = 10 * time.Second
+
+var (
+	agentFieldsMu    sync.Mutex
+	agentFieldsCache = make(map[string]*AgentFields)
+
+	agentListMu    sync.Mutex
+	agentListCache = make(map[string]agentListEntry) // resolved .beads dir -> listing
+
+	// agentWriteGeneration counts bd writes made by this process; a
+	// listing cached before the latest is stale.
+	agentWriteGeneration atomic.Uint64
+)
+
+// agentListEntry is a cached ListAgentBeads result.
+type agentListEntry struct {
+	agents     map[string]*Issue
+	stamp      time.Time // agentCacheStamp of the database when read
+	generation uint64
+	readAt     time.Time
+}
+
+// cachedAgentFields returns the fields parsed from description, parsing
+// it with parse only the first time. The returned struct is shared: the
+// caller must copy it before changing it.
+func cachedAgentFields(description string, parse func(string) *AgentFields) *AgentFields {
+	agentFieldsMu.Lock()
+	fields, ok := agentFieldsCache[description]
+	agentFieldsMu.Unlock()
+	if ok {
+		return fields
+	}
+
+	fields = parse(description)
+	agentFieldsMu.Lock()
+	if len(agentFieldsCache) >= agentFieldsCacheSize {
+		agentFieldsCache = make(map[string]*AgentFields)
+	}
+	agentFieldsCache[description] = fields
+	agentFieldsMu.Unlock()
+	return fields
+}
+
+// agentCacheStamp returns the latest modification time among a beads
+// database's files (see agentWatchStamp) and the manifests of the Dolt
+// databases it or the town's Dolt server keeps, which every Dolt write
+// rewrites.
+func agentCacheStamp(townRoot, beadsDir string) time.Time {
+	latest := agentWatchStamp(beadsDir)
+	patterns := []string{filepath.Join(beadsDir, "dolt", "*", ".dolt", "noms", "manifest")}
+	if townRoot != "" {
+		patterns = append(patterns, filepath.Join(townRoot, ".dolt-data", "*", ".dolt", "noms", "manifest"))
+	}
+	for _, pattern := range patterns {
+		manifests, _ := filepath.Glob(pattern)
+		for _, manifest := range manifests {
+			if info, err := os.Stat(manifest); err == nil && info.ModTime().After(latest) {
+				latest = info.ModTime()
+			}
+		}
+	}
+	return latest
+}
+
+// cachedAgentList returns the agent listing of the database in beadsDir,
+// reading it with load unless the cached one was read at the same stamp
+// and is still current. The map and issues returned are the caller's own.
+func cachedAgentList(beadsDir string, stamp time.Time, load func() (map[string]*Issue, error)) (map[string]*Issue, error) {
+	generation := agentWriteGeneration.Load()
+
+	agentListMu.Lock()
+	entry, ok := agentListCache[beadsDir]
+	agentListMu.Unlock()
+	if ok && entry.generation == generation && entry.stamp.Equal(stamp) && time.Since(entry.readAt) < agentListCacheMaxAge {
+		return copyAgentList(entry.agents), nil
+	}
+
+	agents, err := load()
+	if err != nil {
+		return nil, err
+	}
+	agentListMu.Lock()
+	agentListCache[beadsDir] = agentListEntry{agents: agents, stamp: stamp, generation: generation, readAt: time.Now()}
+	agentListMu.Unlock()
+	return copyAgentList(agents), nil
+}
+
+// copyAgentList returns a copy of a listing that can be changed without
+// changing the cache.
+func copyAgentList(agents map[string]*Issue) map[string]*Issue {
+	copied := make(map[string]*Issue, len(agents))
+	for id, issue := range agents {
+		c := *issue
+		copied[id] = &c
+	}
+	return copied
+}
+
+// invalidateAgentCache marks every cached agent listing stale, after a
+// write that may have changed agent beads. Which database a routed write
+// lands in isn't known here, so it covers them all.
+func invalidateAgentCache() {
+	agentWriteGeneration.Add(1)
+}
+
+// bdReadCommands are the bd commands that don't write, so needn't
+// invalidate cached agent listings. Anything else is assumed to write.
+var bdReadCommands = map[string]bool{
+	"show":    true,
+	"list":    true,
+	"ready":   true,
+	"blocked": true,
+	"stats":   true,
+	"search":  true,
+	"count":   true,
+	"version": true,
+}
+
+// isBDWrite reports whether bd args may write to a database.
+func isBDWrite(args []string) bool {
+	return len(args) == 0 || !bdReadCommands[args[0]]
+}
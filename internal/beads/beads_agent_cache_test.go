@@ -0,0 +1,74 @@
+package beads
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseAgentFields_CachedCopies(t *testing.T) {
+	description := FormatAgentDescription("Polecat nux", &AgentFields{RoleType: "polecat", Rig: "gastown", AgentState: "working"})
+	parses := 0
+	parse := func(d string) *AgentFields {
+		parses++
+		return parseAgentFields(d)
+	}
+	first := cachedAgentFields(description, parse)
+	if again := cachedAgentFields(description, parse); again != first || parses != 1 {
+		t.Errorf("a description seen before should not be parsed again (parses = %d)", parses)
+	}
+
+	fields := ParseAgentFields(description)
+	fields.AgentState = "stuck"
+	if got := ParseAgentFields(description).AgentState; got != "working" {
+		t.Errorf("changing parsed fields changed the cache: AgentState = %q", got)
+	}
+}
+
+func TestCachedAgentList(t *testing.T) {
+	beadsDir := t.TempDir()
+	dbFile := filepath.Join(beadsDir, "beads.db")
+	if err := os.WriteFile(dbFile, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	loads := 0
+	load := func() (map[string]*Issue, error) {
+		loads++
+		return map[string]*Issue{"gt-gastown-polecat-nux": {ID: "gt-gastown-polecat-nux", Status: "open"}}, nil
+	}
+	list := func() map[string]*Issue {
+		t.Helper()
+		agents, err := cachedAgentList(beadsDir, agentCacheStamp("", beadsDir), load)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return agents
+	}
+
+	agents := list()
+	agents["gt-gastown-polecat-nux"].Status = "closed"
+	delete(agents, "gt-gastown-polecat-nux")
+	if again := list(); loads != 1 || again["gt-gastown-polecat-nux"] == nil || again["gt-gastown-polecat-nux"].Status != "open" {
+		t.Errorf("second read should come unchanged from the cache (loads = %d, agents = %v)", loads, again)
+	}
+
+	invalidateAgentCache()
+	list()
+	if loads != 2 {
+		t.Errorf("a write should invalidate the cache (loads = %d)", loads)
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(dbFile, later, later); err != nil {
+		t.Fatal(err)
+	}
+	list()
+	if loads != 3 {
+		t.Errorf("a database file change should invalidate the cache (loads = %d)", loads)
+	}
+
+	if !isBDWrite([]string{"update", "gt-1"}) || isBDWrite([]string{"list", "--json"}) {
+		t.Error("isBDWrite misclassifies update or list")
+	}
+}
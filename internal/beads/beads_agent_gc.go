@@ -0,0 +1,202 @@
+// Package beads provides garbage collection of dead polecat agent beads.
+package beads
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// AgentArchiveDir is where GCAgentBeads archives the agent beads it
+// removes, in the resolved .beads directory: one gzipped JSONL file per
+// run, a line per bead.
+const AgentArchiveDir = "agent-archive"
+
+// DefaultAgentRetention is how long a nuked polecat's agent bead is kept
+// after its last update when no retention is configured.
+const DefaultAgentRetention = 30 * 24 * time.Hour
+
+// ArchivedAgent is an agent bead as GCAgentBeads archived it.
+type ArchivedAgent struct {
+	ArchivedAt string              `json:"archived_at"`
+	Issue      *Issue              `json:"issue"`
+	History    []AgentHistoryEntry `json:"history,omitempty"` // Its entries from the agent history
+}
+
+// IsStaleAgent reports whether agent bead issue is garbage: a polecat's
+// bead, nuked or closed, with nothing hooked and not updated within
+// retention of now. Other roles' beads are long-lived and never are; so is
+// a bead whose last update can't be read.
+func IsStaleAgent(issue *Issue, retention time.Duration, now time.Time) bool {
+	fields := ParseAgentFields(issue.Description)
+	if fields.RoleType != "polecat" || fields.HookBead != "" || issue.HookBead != "" {
+		return false
+	}
+	if fields.AgentState != "nuked" && issue.Status != "closed" && issue.Status != "tombstone" {
+		return false
+	}
+	updated, err := time.Parse(time.RFC3339, issue.UpdatedAt)
+	if err != nil {
+		return false
+	}
+	return now.Sub(updated) > retention
+}
+
+// StaleAgentBeads returns the agent beads of this wrapper's database,
+// closed ones included, that IsStaleAgent holds for, sorted by ID.
+func (b *Beads) StaleAgentBeads(retention time.Duration, now time.Time) ([]*Issue, error) {
+	out, err := b.run("list", "--label=gt:agent", "--status=all", "--limit=0", "--json")
+	if err != nil {
+		return nil, err
+	}
+	var issues []*Issue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return nil, fmt.Errorf("parsing bd list output: %w", err)
+	}
+
+	var stale []*Issue
+	for _, issue := range issues {
+		if IsStaleAgent(issue, retention, now) {
+			stale = append(stale, issue)
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i].ID < stale[j].ID })
+	return stale, nil
+}
+
+// GCAgentBeads archives the stale agent beads of this wrapper's database
+// (see StaleAgentBeads) to AgentArchiveDir, then deletes them, and returns
+// them with the archive's path. With dryRun it only finds them. Each bead
+// is locked and read again before it's archived, and skipped if it isn't
+// stale any more (a polecat was respawned into it since it was listed);
+// the locks are held until the deletes are done. Beads are deleted only once the
+// archive is written; the ones that can't be are reported in the error and
+// left for the next run.
+func (b *Beads) GCAgentBeads(retention time.Duration, dryRun bool) ([]*Issue, string, error) {
+	now := time.Now().UTC()
+	listed, err := b.StaleAgentBeads(retention, now)
+	if err != nil || dryRun || len(listed) == 0 {
+		return listed, "", err
+	}
+
+	var locks []*flock.Flock
+	defer func() {
+		for _, fl := range locks {
+			_ = fl.Unlock()
+		}
+	}()
+	var stale []*Issue
+	for _, issue := range listed {
+		fl, err := b.lockAgentBead(issue.ID)
+		if err != nil {
+			return nil, "", fmt.Errorf("locking agent bead %s: %w", issue.ID, err)
+		}
+		locks = append(locks, fl)
+		current, err := b.Show(issue.ID)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if IsStaleAgent(current, retention, now) {
+			stale = append(stale, current)
+		}
+	}
+	if len(stale) == 0 {
+		return nil, "", nil
+	}
+
+	history, err := b.AgentHistory("")
+	if err != nil {
+		return nil, "", err
+	}
+	byID := make(map[string][]AgentHistoryEntry)
+	for _, entry := range history {
+		byID[entry.ID] = append(byID[entry.ID], entry)
+	}
+	records := make([]ArchivedAgent, len(stale))
+	for i, issue := range stale {
+		records[i] = ArchivedAgent{ArchivedAt: now.Format(time.RFC3339), Issue: issue, History: byID[issue.ID]}
+	}
+
+	path := AgentArchivePath(b.getResolvedBeadsDir(), now)
+	if err := writeAgentArchive(path, records); err != nil {
+		return nil, "", err
+	}
+
+	var errs []error
+	deleted := stale[:0]
+	for _, issue := range stale {
+		if _, err := b.run("delete", issue.ID, "--hard", "--force"); err != nil {
+			errs = append(errs, fmt.Errorf("deleting %s: %w", issue.ID, err))
+			continue
+		}
+		deleted = append(deleted, issue)
+	}
+	return deleted, path, errors.Join(errs...)
+}
+
+// AgentArchivePath returns the archive a GC run at now writes in beadsDir.
+func AgentArchivePath(beadsDir string, now time.Time) string {
+	return filepath.Join(beadsDir, AgentArchiveDir, "agents-"+now.UTC().Format("20060102T150405Z")+".jsonl.gz")
+}
+
+// writeAgentArchive writes records to path as gzipped JSONL.
+func writeAgentArchive(path string, records []ArchivedAgent) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating agent archive dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("creating agent archive: %w", err)
+	}
+	gz := gzip.NewWriter(f)
+	enc := json.NewEncoder(gz)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("writing agent archive: %w", err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing agent archive: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing agent archive: %w", err)
+	}
+	return nil
+}
+
+// ReadAgentArchive returns the agent beads archived at path.
+func ReadAgentArchive(path string) ([]ArchivedAgent, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is an archive the caller names
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading agent archive: %w", err)
+	}
+	defer gz.Close()
+
+	var records []ArchivedAgent
+	dec := json.NewDecoder(gz)
+	for dec.More() {
+		var record ArchivedAgent
+		if err := dec.Decode(&record); err != nil {
+			return nil, fmt.Errorf("reading agent archive: %w", err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,68 @@
+package beads
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIsStaleAgent(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-40 * 24 * time.Hour).Format(time.RFC3339)
+	recent := now.Add(-2 * time.Hour).Format(time.RFC3339)
+	agent := func(role, state, status, updated string) *Issue {
+		fields := &AgentFields{RoleType: role, Rig: "gastown", AgentState: state}
+		return &Issue{Status: status, UpdatedAt: updated, Description: FormatAgentDescription("agent", fields)}
+	}
+
+	tests := []struct {
+		name  string
+		issue *Issue
+		want  bool
+	}{
+		{"nuked polecat past retention", agent("polecat", "nuked", "open", old), true},
+		{"closed polecat past retention", agent("polecat", "done", "closed", old), true},
+		{"recently nuked polecat", agent("polecat", "nuked", "open", recent), false},
+		{"working polecat", agent("polecat", "working", "open", old), false},
+		{"witness", agent("witness", "nuked", "closed", old), false},
+		{"unreadable update time", agent("polecat", "nuked", "open", "yesterday"), false},
+	}
+	for _, tt := range tests {
+		if got := IsStaleAgent(tt.issue, DefaultAgentRetention, now); got != tt.want {
+			t.Errorf("%s: IsStaleAgent = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	hooked := agent("polecat", "nuked", "open", old)
+	hooked.HookBead = "gt-abc"
+	if IsStaleAgent(hooked, DefaultAgentRetention, now) {
+		t.Error("a polecat with hooked work is never stale")
+	}
+}
+
+func TestAgentArchive_RoundTrip(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	path := AgentArchivePath(t.TempDir(), now)
+	if filepath.Base(path) != "agents-20260301T120000Z.jsonl.gz" {
+		t.Errorf("AgentArchivePath = %s", path)
+	}
+	records := []ArchivedAgent{
+		{ArchivedAt: now.Format(time.RFC3339), Issue: &Issue{ID: "gt-gastown-polecat-nux"},
+			History: []AgentHistoryEntry{{ID: "gt-gastown-polecat-nux", Operation: AgentOpReset}}},
+		{ArchivedAt: now.Format(time.RFC3339), Issue: &Issue{ID: "gt-gastown-polecat-toast"}},
+	}
+	if err := writeAgentArchive(path, records); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeAgentArchive(path, records); err == nil {
+		t.Error("an existing archive should not be overwritten")
+	}
+
+	read, err := ReadAgentArchive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != 2 || read[0].Issue.ID != "gt-gastown-polecat-nux" || len(read[0].History) != 1 || read[1].History != nil {
+		t.Errorf("ReadAgentArchive = %+v", read)
+	}
+}
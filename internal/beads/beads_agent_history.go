@@ -0,0 +1,165 @@
+// Package beads provides the mutation history of agent beads.
+package beads
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// AgentHistoryFile is the append-only log of agent bead mutations, kept in
+// the resolved .beads directory next to audit.log.
+const AgentHistoryFile = "agent-history.jsonl"
+
+// Operations recorded in the agent history.
+const (
+	AgentOpCreate       = "create"
+	AgentOpReopen       = "reopen"
+	AgentOpReset        = "reset" // ResetAgentBeadForReuse (nuke)
+	AgentOpUpdate       = "update"
+	AgentOpState        = "state"
+	AgentOpHook         = "hook"
+	AgentOpUnhook       = "unhook"
+	AgentOpFailure      = "failure"
+	AgentOpOpenCircuit  = "open_circuit"
+	AgentOpCircuitState = "circuit_state"
+	AgentOpCircuitReset = "circuit_reset"
+	AgentOpSuccess      = "success"
+	AgentOpResources    = "resources"
+	AgentOpOutput       = "output"
+	AgentOpStall        = "stall"
+	AgentOpNukeVeto     = "nuke_veto"
+	AgentOpQuarantine   = "quarantine"
+	AgentOpMigrate      = "migrate"
+)
+
+// AgentHistoryEntry is one mutation of an agent bead.
+type AgentHistoryEntry struct {
+	Timestamp string             `json:"ts"`
+	ID        string             `json:"id"`
+	Operation string             `json:"op"`
+	Actor     string             `json:"actor,omitempty"`     // BD_ACTOR of the writing process
+	Subsystem string             `json:"subsystem,omitempty"` // What made the write: witness, daemon, or the gt command
+	Revision  int64              `json:"revision,omitempty"`  // The fields' revision after the write, when known
+	Changes   []AgentFieldChange `json:"changes,omitempty"`
+}
+
+// AgentFieldChange is one field an agent bead mutation changed.
+type AgentFieldChange struct {
+	Field string  `json:"field"`
+	Old   *string `json:"old,omitempty"` // nil when the write didn't read the field first (slot and agent_state writes)
+	New   string  `json:"new"`
+}
+
+// WithSubsystem names the subsystem whose writes this wrapper makes (e.g.
+// "witness"), for the agent history, and returns b. Unset, the history
+// names the gt command the process is running.
+func (b *Beads) WithSubsystem(name string) *Beads {
+	b.subsystem = name
+	return b
+}
+
+// getSubsystem returns the subsystem recorded for this wrapper's writes.
+func (b *Beads) getSubsystem() string {
+	if b.subsystem != "" {
+		return b.subsystem
+	}
+	var words []string
+	for _, arg := range os.Args[1:] {
+		if strings.HasPrefix(arg, "-") || len(words) == 2 {
+			break
+		}
+		words = append(words, arg)
+	}
+	return strings.Join(words, " ")
+}
+
+// agentFieldChanges returns the fields that differ between old and updated
+// as history changes; nil stands for all fields unset.
+func agentFieldChanges(old, updated *AgentFields) []AgentFieldChange {
+	var changes []AgentFieldChange
+	for _, name := range ChangedAgentFields(old, updated) {
+		was := agentFieldString(old, name)
+		changes = append(changes, AgentFieldChange{Field: name, Old: &was, New: agentFieldString(updated, name)})
+	}
+	return changes
+}
+
+// recordAgentHistory appends an entry for a mutation of agent bead id to
+// the history. A field update that changed nothing (a repeated output
+// fingerprint, say) isn't recorded; creating, reopening, resetting and
+// migrating the bead always are. A failure to write the entry is reported
+// but doesn't fail the mutation, which has already happened.
+func (b *Beads) recordAgentHistory(id, op string, revision int64, changes []AgentFieldChange) {
+	lifecycle := op == AgentOpCreate || op == AgentOpReopen || op == AgentOpReset || op == AgentOpMigrate
+	if len(changes) == 0 && !lifecycle {
+		return
+	}
+	entry := AgentHistoryEntry{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		ID:        id,
+		Operation: op,
+		Actor:     b.getActor(),
+		Subsystem: b.getSubsystem(),
+		Revision:  revision,
+		Changes:   changes,
+	}
+	if err := b.LogAgentHistory(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write agent history: %v\n", err)
+	}
+}
+
+// LogAgentHistory appends an entry to the agent history of the resolved
+// .beads directory.
+func (b *Beads) LogAgentHistory(entry AgentHistoryEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshaling agent history entry: %w", err)
+	}
+
+	historyPath := filepath.Join(b.getResolvedBeadsDir(), AgentHistoryFile)
+	f, err := os.OpenFile(historyPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("opening agent history: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing agent history entry: %w", err)
+	}
+	return nil
+}
+
+// AgentHistory returns the recorded mutations of agent bead id, oldest
+// first; all agents' if id is empty. Lines that don't parse are skipped.
+func (b *Beads) AgentHistory(id string) ([]AgentHistoryEntry, error) {
+	f, err := os.Open(filepath.Join(b.getResolvedBeadsDir(), AgentHistoryFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening agent history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []AgentHistoryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var entry AgentHistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if id == "" || entry.ID == id {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading agent history: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,62 @@
+package beads
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAgentHistory_RecordsFieldDeltas(t *testing.T) {
+	beadsDir := t.TempDir()
+	b := NewWithBeadsDir(beadsDir, beadsDir).WithSubsystem("witness")
+
+	before := &AgentFields{RoleType: "polecat", Rig: "gastown", FailureCount: 6, Revision: 11}
+	after := *before
+	ApplyAgentFailure(&after, FailureDeterministic, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	after.Revision++
+	b.recordAgentHistory("gt-gastown-polecat-nux", AgentOpFailure, after.Revision, agentFieldChanges(before, &after))
+	b.recordAgentHistory("gt-gastown-polecat-nux", AgentOpOutput, 13, agentFieldChanges(&after, &after)) // nothing changed
+	b.recordAgentHistory("gt-gastown-polecat-ace", AgentOpCreate, 0, nil)
+	b.recordAgentHistory("gt-gastown-polecat-nux", AgentOpUnhook, 0, []AgentFieldChange{{Field: "hook_bead", New: ""}})
+
+	all, err := b.AgentHistory("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("recorded %d entries, want 3 (the no-op write skipped): %+v", len(all), all)
+	}
+
+	nux, err := b.AgentHistory("gt-gastown-polecat-nux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nux) != 2 {
+		t.Fatalf("nux has %d entries, want 2", len(nux))
+	}
+	failure := nux[0]
+	if failure.Operation != AgentOpFailure || failure.Subsystem != "witness" || failure.Revision != 12 {
+		t.Errorf("failure entry = %+v", failure)
+	}
+	var fields []string
+	for _, change := range failure.Changes {
+		fields = append(fields, change.Field)
+		if change.Field == "failure_count" && (change.Old == nil || *change.Old != "6" || change.New != "7") {
+			t.Errorf("failure_count change = %+v, want 6 → 7", change)
+		}
+	}
+	if want := []string{"failure_count", "last_failure_at", "last_failure_reason"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("changed fields = %v, want %v", fields, want)
+	}
+	if unhook := nux[1]; unhook.Operation != AgentOpUnhook || unhook.Changes[0].Old != nil {
+		t.Errorf("unhook entry = %+v, want an unknown old value", unhook)
+	}
+}
+
+func TestAgentHistory_MissingLog(t *testing.T) {
+	beadsDir := t.TempDir()
+	entries, err := NewWithBeadsDir(beadsDir, beadsDir).AgentHistory("gt-x")
+	if err != nil || entries != nil {
+		t.Errorf("AgentHistory with no log = %v, %v; want nothing", entries, err)
+	}
+}
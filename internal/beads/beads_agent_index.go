@@ -0,0 +1,296 @@
+// Package beads provides the optional SQLite index of agent beads.
+package beads
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// AgentIndexFile is the optional SQLite index of a beads database's agent
+// beads, kept in its .beads directory. 'gt beads migrate' builds it; while
+// it exists, ListAgentBeads, ListAgentBeadsWithField and AgentQuery.Run
+// read it instead of listing and text-matching every agent bead through
+// bd. bd stays the source of truth: the index records the database's
+// stamp (see agentCacheStamp) when it was built, and once any write (by
+// gt, bd or anything else) changes the stamp, reads go to bd until
+// ListAgentBeads next lists the agents and rebuilds it.
+const AgentIndexFile = "gt-agents.db"
+
+// agentIndexSchemaVersion is the version of the index's tables. An index
+// built with another version is ignored until rebuilt.
+const agentIndexSchemaVersion = 1
+
+// agentIndexColumns maps the agent fields the index can filter on (by
+// their JSON name) to their columns.
+var agentIndexColumns = map[string]string{
+	"role_type":     "role_type",
+	"rig":           "rig",
+	"agent_state":   "agent_state",
+	"hook_bead":     "hook_bead",
+	"circuit_state": "circuit_state",
+	"failure_count": "failure_count",
+}
+
+const agentIndexSchema = `
+CREATE TABLE IF NOT EXISTS meta (key TEXT PRIMARY KEY, value TEXT NOT NULL);
+CREATE TABLE IF NOT EXISTS agents (
+	id            TEXT PRIMARY KEY,
+	status        TEXT NOT NULL,
+	role_type     TEXT NOT NULL,
+	rig           TEXT NOT NULL,
+	agent_state   TEXT NOT NULL,
+	hook_bead     TEXT NOT NULL,
+	circuit_state TEXT NOT NULL,
+	failure_count INTEGER NOT NULL,
+	issue         TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS agents_role_type ON agents(role_type);
+CREATE INDEX IF NOT EXISTS agents_rig ON agents(rig);
+CREATE INDEX IF NOT EXISTS agents_circuit_state ON agents(circuit_state);
+CREATE INDEX IF NOT EXISTS agents_hook_bead ON agents(hook_bead);
+`
+
+var (
+	sqlite3Once sync.Once
+	sqlite3Path string
+)
+
+// sqlite3Binary returns the sqlite3 CLI the index is read and written
+// with, or "" if it isn't installed.
+func sqlite3Binary() string {
+	sqlite3Once.Do(func() {
+		sqlite3Path, _ = exec.LookPath("sqlite3")
+	})
+	return sqlite3Path
+}
+
+// agentIndex is an agent bead index file.
+type agentIndex struct {
+	path string
+}
+
+// AgentIndexPath returns the path of the agent index for a .beads directory.
+func AgentIndexPath(beadsDir string) string {
+	return filepath.Join(beadsDir, AgentIndexFile)
+}
+
+// openAgentIndex returns the agent index of a .beads directory, or nil if
+// there is none or sqlite3 isn't installed.
+func openAgentIndex(beadsDir string) *agentIndex {
+	path := AgentIndexPath(beadsDir)
+	if _, err := os.Stat(path); err != nil || sqlite3Binary() == "" {
+		return nil
+	}
+	return &agentIndex{path: path}
+}
+
+// exec runs a SQL script against the index in one transaction.
+func (x *agentIndex) exec(script string) error {
+	cmd := exec.Command(sqlite3Binary(), "-bail", "-cmd", ".timeout 5000", x.path) //nolint:gosec // G204: sqlite3 with our own index path
+	cmd.Stdin = strings.NewReader("BEGIN IMMEDIATE;\n" + script + "\nCOMMIT;\n")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("writing agent index %s: %v: %s", x.path, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// query runs a SELECT against the index and returns its rows as JSON.
+func (x *agentIndex) query(sql string) ([]byte, error) {
+	cmd := exec.Command(sqlite3Binary(), "-json", "-cmd", ".timeout 5000", x.path, sql) //nolint:gosec // G204: sqlite3 with our own index path
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("reading agent index %s: %w", x.path, err)
+	}
+	return out, nil
+}
+
+// rebuild replaces the index's agents with issues, read from bd when the
+// database had stamp (see agentCacheStamp).
+func (x *agentIndex) rebuild(issues []*Issue, stamp time.Time) error {
+	var script strings.Builder
+	script.WriteString(agentIndexSchema)
+	script.WriteString("DELETE FROM agents;\n")
+	for _, issue := range issues {
+		script.WriteString(agentIndexRow(issue))
+	}
+	fmt.Fprintf(&script, "INSERT OR REPLACE INTO meta VALUES ('schema', '%d'), ('db_stamp', '%d'), ('built_at', %s);\n",
+		agentIndexSchemaVersion, stamp.UnixNano(), sqlQuote(time.Now().UTC().Format(time.RFC3339)))
+	return x.exec(script.String())
+}
+
+// errAgentIndexStale is returned by list for an index built with another
+// schema version or before the database's last write.
+var errAgentIndexStale = errors.New("agent index is out of date")
+
+// list returns the agent beads that aren't closed and match where (SQL
+// over the agents table; "" for all), if the index was built with this
+// schema version when the database had stamp. The check and the read are
+// one sqlite3 run.
+func (x *agentIndex) list(stamp time.Time, where string) (map[string]*Issue, error) {
+	sql := "SELECT key, value FROM meta; SELECT issue FROM agents WHERE status != 'closed'"
+	if where != "" {
+		sql += " AND " + where
+	}
+	out, err := x.query(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	// sqlite3 prints one JSON array per statement, and none for no rows.
+	dec := json.NewDecoder(bytes.NewReader(out))
+	var meta []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+	if err := dec.Decode(&meta); err != nil {
+		return nil, errAgentIndexStale
+	}
+	built := make(map[string]string, len(meta))
+	for _, m := range meta {
+		built[m.Key] = m.Value
+	}
+	if built["schema"] != strconv.Itoa(agentIndexSchemaVersion) || built["db_stamp"] != strconv.FormatInt(stamp.UnixNano(), 10) {
+		return nil, errAgentIndexStale
+	}
+
+	var rows []struct {
+		Issue string `json:"issue"`
+	}
+	if err := dec.Decode(&rows); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("parsing agent index rows: %w", err)
+	}
+	result := make(map[string]*Issue, len(rows))
+	for _, row := range rows {
+		var issue Issue
+		if err := json.Unmarshal([]byte(row.Issue), &issue); err != nil {
+			return nil, fmt.Errorf("parsing agent index row: %w", err)
+		}
+		result[issue.ID] = &issue
+	}
+	return result, nil
+}
+
+// agentIndexRow returns the INSERT that indexes issue.
+func agentIndexRow(issue *Issue) string {
+	fields := ParseAgentFields(issue.Description)
+	hook := issue.HookBead // the hook slot is authoritative
+	if hook == "" {
+		hook = fields.HookBead
+	}
+	data, _ := json.Marshal(issue)
+	return fmt.Sprintf("INSERT OR REPLACE INTO agents VALUES (%s, %s, %s, %s, %s, %s, %s, %d, %s);\n",
+		sqlQuote(issue.ID), sqlQuote(issue.Status), sqlQuote(fields.RoleType), sqlQuote(fields.Rig),
+		sqlQuote(fields.AgentState), sqlQuote(hook), sqlQuote(fields.CircuitState), fields.FailureCount,
+		sqlQuote(string(data)))
+}
+
+// agentIndexWhere returns the filter that ListAgentBeadsWithField(key,
+// value) applies, or ok false if the index has no column for key.
+func agentIndexWhere(key, value string) (where string, ok bool) {
+	column, ok := agentIndexColumns[key]
+	if !ok {
+		return "", false
+	}
+	numeric := agentFieldIsNumber(key)
+	switch {
+	case value == "" && numeric:
+		return column + " != 0", true
+	case value == "":
+		return column + " != ''", true
+	case value == "null":
+		return column + " = ''", true
+	case numeric:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "", false
+		}
+		return fmt.Sprintf("%s = %d", column, n), true
+	}
+	return column + " = " + sqlQuote(value), true
+}
+
+// sqlQuote quotes s as an SQL string literal.
+func sqlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// indexedAgents answers a listing from the agent index of the database
+// this wrapper operates on: the agent beads that aren't closed and match
+// where (see agentIndex.list). It returns ok false, for the caller to ask
+// bd, if there is no index or it is out of date.
+func (b *Beads) indexedAgents(where string) (agents map[string]*Issue, ok bool) {
+	beadsDir := b.getResolvedBeadsDir()
+	index := openAgentIndex(beadsDir)
+	if index == nil {
+		return nil, false
+	}
+	agents, err := index.list(agentCacheStamp(b.getTownRoot(), beadsDir), where)
+	return agents, err == nil
+}
+
+// reindexAgents rebuilds the agent index of the database this wrapper
+// operates on, if it has one, from a full listing of its open agents read
+// from bd when the database had stamp. A database written since is seen
+// as stale at the next read, and rebuilt again.
+func (b *Beads) reindexAgents(agents map[string]*Issue, stamp time.Time) {
+	index := openAgentIndex(b.getResolvedBeadsDir())
+	if index == nil {
+		return
+	}
+	issues := make([]*Issue, 0, len(agents))
+	for _, issue := range agents {
+		issues = append(issues, issue)
+	}
+	if err := index.rebuild(issues, stamp); err != nil {
+		if rmErr := os.Remove(index.path); rmErr == nil {
+			style.PrintWarning("agent index %s could not be rebuilt and was removed (%v); rebuild it with 'gt beads migrate'", index.path, err)
+		}
+	}
+}
+
+// BuildAgentIndex builds (or rebuilds) the SQLite agent index of the
+// database this wrapper operates on from every agent bead in it, and
+// returns how many it indexed. It needs the sqlite3 CLI.
+func (b *Beads) BuildAgentIndex() (int, error) {
+	if sqlite3Binary() == "" {
+		return 0, fmt.Errorf("the agent index needs sqlite3, which isn't installed")
+	}
+	beadsDir := b.getResolvedBeadsDir()
+	stamp := agentCacheStamp(b.getTownRoot(), beadsDir)
+	out, err := b.run("list", "--label=gt:agent", "--status=all", "--limit=0", "--json")
+	if err != nil {
+		return 0, err
+	}
+	var issues []*Issue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return 0, fmt.Errorf("parsing bd list output: %w", err)
+	}
+
+	index := &agentIndex{path: AgentIndexPath(beadsDir)}
+	if err := index.rebuild(issues, stamp); err != nil {
+		return 0, err
+	}
+	return len(issues), nil
+}
+
+// RemoveAgentIndex removes the agent index of the database this wrapper
+// operates on; reads go back to bd. Removing a missing index is not an
+// error.
+func (b *Beads) RemoveAgentIndex() error {
+	if err := os.Remove(AgentIndexPath(b.getResolvedBeadsDir())); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package beads
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAgentIndex(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+	beadsDir := t.TempDir()
+	agent := func(id, status string, fields *AgentFields) *Issue {
+		return &Issue{ID: id, Title: id, Status: status, Type: "agent", Labels: []string{"gt:agent"},
+			Description: FormatAgentDescription(id, fields)}
+	}
+	index := &agentIndex{path: AgentIndexPath(beadsDir)}
+	err := index.rebuild([]*Issue{
+		agent("gt-gastown-polecat-nux", "open", &AgentFields{RoleType: "polecat", Rig: "gastown", CircuitState: CircuitOpen, FailureCount: 3}),
+		agent("gt-gastown-polecat-ace", "open", &AgentFields{RoleType: "polecat", Rig: "gastown", HookBead: "gt-abc", CircuitNote: "reset by o'brien"}),
+		agent("gt-gastown-polecat-old", "closed", &AgentFields{RoleType: "polecat", Rig: "gastown", CircuitState: CircuitOpen}),
+		agent("hq-mayor", "open", &AgentFields{RoleType: "mayor"}),
+		{ID: "gt-gastown-witness", Status: "open", Description: "Witness\n\nrole_type: witness\nrig: gastown"}, // not migrated
+	}, agentCacheStamp("", beadsDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := NewWithBeadsDir(beadsDir, beadsDir)
+	if _, ok := b.indexedAgents(""); !ok {
+		t.Fatal("index unused after rebuild")
+	}
+	tests := []struct {
+		key, value string
+		want       []string
+	}{
+		{"circuit_state", CircuitOpen, []string{"gt-gastown-polecat-nux"}},
+		{"failure_count", "", []string{"gt-gastown-polecat-nux"}},
+		{"failure_count", "3", []string{"gt-gastown-polecat-nux"}},
+		{"hook_bead", "gt-abc", []string{"gt-gastown-polecat-ace"}},
+		{"rig", "null", []string{"hq-mayor"}},
+		{"role_type", "witness", []string{"gt-gastown-witness"}},
+	}
+	for _, tt := range tests {
+		got, err := b.ListAgentBeadsWithField(tt.key, tt.value)
+		if err != nil {
+			t.Fatalf("%s=%s: %v", tt.key, tt.value, err)
+		}
+		if len(got) != len(tt.want) || got[tt.want[0]] == nil {
+			t.Errorf("%s=%s: got %d agents %v, want %v", tt.key, tt.value, len(got), issueIDs(got), tt.want)
+		}
+	}
+
+	all, err := b.ListAgentBeads()
+	if err != nil || len(all) != 4 || all["gt-gastown-polecat-old"] != nil {
+		t.Fatalf("ListAgentBeads = %v, %v; want the four open agents", issueIDs(all), err)
+	}
+	if note := ParseAgentFields(all["gt-gastown-polecat-ace"].Description).CircuitNote; note != "reset by o'brien" {
+		t.Errorf("description didn't survive the index: circuit_note %q", note)
+	}
+
+	// A write to the database, by anyone, makes the index stale until the
+	// next rebuild.
+	later := time.Now().Add(time.Second)
+	if err := os.WriteFile(filepath.Join(beadsDir, "issues.jsonl"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(filepath.Join(beadsDir, "issues.jsonl"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.indexedAgents(""); ok {
+		t.Error("index used after the database was written")
+	}
+	b.reindexAgents(map[string]*Issue{"hq-mayor": agent("hq-mayor", "open", &AgentFields{RoleType: "mayor"})}, agentCacheStamp("", beadsDir))
+	if all, ok := b.indexedAgents(""); !ok || len(all) != 1 || all["hq-mayor"] == nil {
+		t.Errorf("after reindexing: agents %v (used %v), want hq-mayor", issueIDs(all), ok)
+	}
+
+	if err := b.RemoveAgentIndex(); err != nil || openAgentIndex(beadsDir) != nil {
+		t.Errorf("RemoveAgentIndex: %v; index still at %s", err, filepath.Join(beadsDir, AgentIndexFile))
+	}
+}
+
+func issueIDs(m map[string]*Issue) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
@@ -0,0 +1,368 @@
+// Package beads provides queries over agent bead fields.
+package beads
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AgentFilter is one condition of an AgentQuery on an agent's fields.
+type AgentFilter struct {
+	Key   string // The field's JSON name, e.g. "circuit_state"
+	Op    string // One of AgentQueryOps
+	Value string // Comma-separated alternatives for = and != ; "" (or null) is an unset field
+}
+
+// AgentQueryOps are the comparisons an AgentFilter can make. = and != take
+// a list of alternatives; the others compare numeric fields as numbers and
+// other fields as strings (which orders RFC3339 times correctly).
+var AgentQueryOps = []string{"!=", ">=", "<=", "=", ">", "<"}
+
+// agentQueryAliases are the short names a filter expression may use for
+// the common fields.
+var agentQueryAliases = map[string]string{
+	"role":     "role_type",
+	"state":    "agent_state",
+	"hook":     "hook_bead",
+	"circuit":  "circuit_state",
+	"failures": "failure_count",
+}
+
+// agentFieldIndexes maps the JSON name of each agent field to its index in
+// AgentFields.
+var agentFieldIndexes = func() map[string]int {
+	t := reflect.TypeOf(AgentFields{})
+	indexes := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		indexes[name] = i
+	}
+	return indexes
+}()
+
+// AgentQuery selects the agent beads of a database by their fields. Build
+// one with Beads.Query, add filters, then Run it; an agent must pass every
+// filter. For example:
+//
+//	agents, err := bd.Query().Role("polecat").Rig("gastown").CircuitState(CircuitOpen).Run()
+type AgentQuery struct {
+	b       *Beads
+	filters []AgentFilter
+	err     error
+}
+
+// Query starts a query over the agent beads of the database this wrapper
+// operates on.
+func (b *Beads) Query() *AgentQuery {
+	return &AgentQuery{b: b}
+}
+
+// Role keeps agents with this role_type.
+func (q *AgentQuery) Role(role string) *AgentQuery {
+	return q.Where("role_type", "=", role)
+}
+
+// Rig keeps agents of this rig.
+func (q *AgentQuery) Rig(rig string) *AgentQuery {
+	return q.Where("rig", "=", rig)
+}
+
+// AgentState keeps agents in this agent_state.
+func (q *AgentQuery) AgentState(state string) *AgentQuery {
+	return q.Where("agent_state", "=", state)
+}
+
+// CircuitState keeps agents whose circuit is in this state. CircuitClosed
+// also matches a circuit that never tripped.
+func (q *AgentQuery) CircuitState(state string) *AgentQuery {
+	if state == CircuitClosed {
+		return q.Where("circuit_state", "=", ","+CircuitClosed)
+	}
+	return q.Where("circuit_state", "=", state)
+}
+
+// HookBead keeps agents with this bead on their hook.
+func (q *AgentQuery) HookBead(id string) *AgentQuery {
+	return q.Where("hook_bead", "=", id)
+}
+
+// Where keeps agents whose field key (its JSON name) compares to value
+// with op. An unknown field or op fails Run.
+func (q *AgentQuery) Where(key, op, value string) *AgentQuery {
+	return q.Filter(AgentFilter{Key: key, Op: op, Value: value})
+}
+
+// Filter adds filters, as ParseAgentQuery returns them.
+func (q *AgentQuery) Filter(filters ...AgentFilter) *AgentQuery {
+	for _, filter := range filters {
+		if err := filter.validate(); err != nil && q.err == nil {
+			q.err = err
+		}
+		q.filters = append(q.filters, filter)
+	}
+	return q
+}
+
+// Run returns the agent beads that aren't closed and pass every filter,
+// by ID. With an agent index whose columns cover the filters, it answers
+// from the index; otherwise it lists the agents matching one equality
+// filter through bd (see ListAgentBeadsWithField), or every agent if there
+// is none, and applies the filters to their parsed fields.
+func (q *AgentQuery) Run() (map[string]*Issue, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if where, ok := agentQueryWhere(q.filters); ok {
+		if agents, ok := q.b.indexedAgents(where); ok {
+			return agents, nil
+		}
+	}
+
+	var candidates map[string]*Issue
+	var err error
+	if key, value, ok := q.narrowest(); ok {
+		candidates, err = q.b.ListAgentBeadsWithField(key, value)
+	} else {
+		candidates, err = q.b.ListAgentBeads()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*Issue, len(candidates))
+	for id, issue := range candidates {
+		if issue.Status != "closed" && q.Matches(issue) {
+			result[id] = issue
+		}
+	}
+	return result, nil
+}
+
+// Matches reports whether an agent bead passes every filter. The hook
+// slot, when set, stands in for the hook_bead field.
+func (q *AgentQuery) Matches(issue *Issue) bool {
+	return q.matchesFields(AgentIssueFields(issue))
+}
+
+func (q *AgentQuery) matchesFields(fields *AgentFields) bool {
+	for _, filter := range q.filters {
+		if !filter.matches(fields) {
+			return false
+		}
+	}
+	return true
+}
+
+// AgentIssueFields returns an agent bead's parsed fields, with the hook
+// slot, when set, standing in for hook_bead.
+func AgentIssueFields(issue *Issue) *AgentFields {
+	fields := ParseAgentFields(issue.Description)
+	if issue.HookBead != "" {
+		fields.HookBead = issue.HookBead
+	}
+	return fields
+}
+
+// agentSlotFields are the agent fields a bead slot stands in for (see
+// AgentIssueFields). Their description text can be stale or missing, so
+// bd can't list agents by them.
+var agentSlotFields = map[string]bool{
+	"hook_bead": true,
+}
+
+// narrowest returns a filter bd can list agents by: an equality on a
+// single set value of a field kept in the description.
+func (q *AgentQuery) narrowest() (key, value string, ok bool) {
+	for _, filter := range q.filters {
+		if agentSlotFields[filter.Key] {
+			continue
+		}
+		if filter.Op == "=" && filter.Value != "" && filter.Value != "null" && !strings.Contains(filter.Value, ",") {
+			return filter.Key, filter.Value, true
+		}
+	}
+	return "", "", false
+}
+
+func (f AgentFilter) validate() error {
+	if _, ok := agentFieldIndexes[f.Key]; !ok {
+		return fmt.Errorf("unknown agent field %q", f.Key)
+	}
+	for _, op := range AgentQueryOps {
+		if f.Op == op {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown comparison %q on %s: use one of %s", f.Op, f.Key, strings.Join(AgentQueryOps, " "))
+}
+
+// matches reports whether fields pass the filter.
+func (f AgentFilter) matches(fields *AgentFields) bool {
+	field := reflect.ValueOf(fields).Elem().Field(agentFieldIndexes[f.Key])
+	got := agentFieldValue(field)
+	numeric := field.Kind() != reflect.String
+
+	switch f.Op {
+	case "=", "!=":
+		found := false
+		for _, alt := range f.alternatives() {
+			found = found || got == alt
+		}
+		return found == (f.Op == "=")
+	}
+
+	cmp := strings.Compare(got, f.Value)
+	if numeric {
+		want, err := strconv.ParseInt(f.Value, 10, 64)
+		if err != nil {
+			return false
+		}
+		cmp = compareInt(field.Int(), want)
+	}
+	switch f.Op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	}
+	return cmp >= 0
+}
+
+// agentFieldValue returns an agent field as filters compare it: "" when
+// unset.
+func agentFieldValue(field reflect.Value) string {
+	if field.Kind() == reflect.String {
+		return field.String()
+	}
+	if n := field.Int(); n != 0 {
+		return strconv.FormatInt(n, 10)
+	}
+	return ""
+}
+
+// alternatives returns the values an = or != filter compares with; an
+// unset field is "".
+func (f AgentFilter) alternatives() []string {
+	alts := strings.Split(f.Value, ",")
+	for i, alt := range alts {
+		if alt == "null" {
+			alts[i] = ""
+		}
+	}
+	return alts
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// agentQueryWhere returns filters as SQL over the agent index, or ok false
+// if the index has no column for one of them.
+func agentQueryWhere(filters []AgentFilter) (where string, ok bool) {
+	var terms []string
+	for _, filter := range filters {
+		column, ok := agentIndexColumns[filter.Key]
+		if !ok {
+			return "", false
+		}
+		numeric := agentFieldIsNumber(filter.Key)
+		literal := func(value string) (string, bool) {
+			if !numeric {
+				return sqlQuote(value), true
+			}
+			if value == "" {
+				return "0", true
+			}
+			n, err := strconv.ParseInt(value, 10, 64)
+			return strconv.FormatInt(n, 10), err == nil
+		}
+
+		switch filter.Op {
+		case "=", "!=":
+			var values []string
+			for _, alt := range filter.alternatives() {
+				value, ok := literal(alt)
+				if !ok {
+					return "", false
+				}
+				values = append(values, value)
+			}
+			in := " IN ("
+			if filter.Op == "!=" {
+				in = " NOT IN ("
+			}
+			terms = append(terms, column+in+strings.Join(values, ", ")+")")
+		default:
+			value, ok := literal(filter.Value)
+			if !ok {
+				return "", false
+			}
+			terms = append(terms, column+" "+filter.Op+" "+value)
+		}
+	}
+	return strings.Join(terms, " AND "), true
+}
+
+// ParseAgentQuery parses a filter expression into filters for
+// AgentQuery.Filter. An expression is a list of terms, optionally joined
+// by "and", each a field, a comparison and a value with no spaces:
+//
+//	role=polecat rig=gastown circuit=open,half_open
+//	role_type=polecat and failure_count>=2 and hook=
+//
+// Fields are JSON names (role_type, circuit_state, ...) or the aliases
+// role, state, hook, circuit and failures. An empty value (or null) is an
+// unset field, so "hook=" is an agent with nothing hooked and "hook!=" one
+// with something hooked.
+func ParseAgentQuery(expr string) ([]AgentFilter, error) {
+	var filters []AgentFilter
+	for _, term := range strings.Fields(expr) {
+		if strings.EqualFold(term, "and") {
+			continue
+		}
+		at, op := -1, ""
+		for _, candidate := range AgentQueryOps {
+			if i := strings.Index(term, candidate); i > 0 && (at < 0 || i < at || (i == at && len(candidate) > len(op))) {
+				at, op = i, candidate
+			}
+		}
+		if at < 0 {
+			return nil, fmt.Errorf("%q: want field, comparison and value, e.g. role=polecat", term)
+		}
+		key := term[:at]
+		if alias, ok := agentQueryAliases[key]; ok {
+			key = alias
+		}
+		filter := AgentFilter{Key: key, Op: op, Value: term[at+len(op):]}
+		if err := filter.validate(); err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	if len(filters) == 0 {
+		return nil, fmt.Errorf("empty query")
+	}
+	return filters, nil
+}
+
+// AgentQueryFields returns the field names a filter expression can use,
+// sorted.
+func AgentQueryFields() []string {
+	names := make([]string, 0, len(agentFieldIndexes))
+	for name := range agentFieldIndexes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,127 @@
+package beads
+
+import (
+	"os/exec"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestParseAgentQuery(t *testing.T) {
+	filters, err := ParseAgentQuery("role=polecat and failures>=2 circuit=open,half_open hook!=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []AgentFilter{
+		{Key: "role_type", Op: "=", Value: "polecat"},
+		{Key: "failure_count", Op: ">=", Value: "2"},
+		{Key: "circuit_state", Op: "=", Value: "open,half_open"},
+		{Key: "hook_bead", Op: "!=", Value: ""},
+	}
+	if !reflect.DeepEqual(filters, want) {
+		t.Errorf("filters = %+v, want %+v", filters, want)
+	}
+
+	for _, bad := range []string{"", "and", "role", "colour=red", "=polecat"} {
+		if _, err := ParseAgentQuery(bad); err == nil {
+			t.Errorf("ParseAgentQuery(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestAgentQuery_Matches(t *testing.T) {
+	agent := func(fields *AgentFields) *Issue {
+		return &Issue{ID: "gt-x", Description: FormatAgentDescription("x", fields)}
+	}
+	tripped := agent(&AgentFields{RoleType: "polecat", Rig: "gastown", CircuitState: CircuitOpen, FailureCount: 3,
+		LastFailureAt: "2026-03-01T12:00:00Z"})
+	fresh := agent(&AgentFields{RoleType: "polecat", Rig: "gastown"})
+	hooked := agent(&AgentFields{RoleType: "witness", Rig: "gastown"})
+	hooked.HookBead = "gt-abc" // only in the slot
+
+	tests := []struct {
+		expr  string
+		issue *Issue
+		want  bool
+	}{
+		{"role=polecat rig=gastown circuit=open", tripped, true},
+		{"role=polecat rig=gastown circuit=open", fresh, false},
+		{"failures>=3", tripped, true},
+		{"failures>3", tripped, false},
+		{"failures=", fresh, true},
+		{"failures<1", fresh, true},
+		{"circuit=,closed", fresh, true},
+		{"circuit!=open,half_open", tripped, false},
+		{"rig=null", fresh, false},
+		{"last_failure_at>=2026-03-01T00:00:00Z", tripped, true},
+		{"last_failure_at>=2026-03-01T00:00:00Z", fresh, false},
+		{"hook=gt-abc", hooked, true},
+		{"hook!=", fresh, false},
+	}
+	for _, tt := range tests {
+		filters, err := ParseAgentQuery(tt.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.expr, err)
+		}
+		if got := (&AgentQuery{}).Filter(filters...).Matches(tt.issue); got != tt.want {
+			t.Errorf("%s on %s: matched %v, want %v", tt.expr, tt.issue.Description, got, tt.want)
+		}
+	}
+
+	if closed := (&AgentQuery{}).CircuitState(CircuitClosed); !closed.Matches(fresh) || closed.Matches(tripped) {
+		t.Error("CircuitState(closed) should match a circuit that never tripped, and only that")
+	}
+	if _, err := (&Beads{}).Query().Where("colour", "=", "red").Run(); err == nil {
+		t.Error("Run with an unknown field succeeded")
+	}
+	// The hook slot isn't in the description bd would search.
+	if key, _, ok := (&AgentQuery{}).HookBead("gt-abc").narrowest(); ok {
+		t.Errorf("narrowest = %s, want none for a hook filter", key)
+	}
+	if key, _, ok := (&AgentQuery{}).HookBead("gt-abc").Role("polecat").narrowest(); !ok || key != "role_type" {
+		t.Errorf("narrowest = %s, %v; want role_type", key, ok)
+	}
+}
+
+func TestAgentQuery_RunsOnIndex(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+	beadsDir := t.TempDir()
+	agent := func(id string, fields *AgentFields) *Issue {
+		return &Issue{ID: id, Title: id, Status: "open", Type: "agent", Description: FormatAgentDescription(id, fields)}
+	}
+	index := &agentIndex{path: AgentIndexPath(beadsDir)}
+	err := index.rebuild([]*Issue{
+		agent("gt-gastown-polecat-nux", &AgentFields{RoleType: "polecat", Rig: "gastown", CircuitState: CircuitOpen, FailureCount: 3}),
+		agent("gt-gastown-polecat-ace", &AgentFields{RoleType: "polecat", Rig: "gastown", FailureCount: 1}),
+		agent("gt-gastown-polecat-fox", &AgentFields{RoleType: "polecat", Rig: "gastown", CircuitState: CircuitClosed}),
+		agent("bd-beads-polecat-obs", &AgentFields{RoleType: "polecat", Rig: "beads", CircuitState: CircuitOpen}),
+		agent("gt-gastown-witness", &AgentFields{RoleType: "witness", Rig: "gastown"}),
+	}, agentCacheStamp("", beadsDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewWithBeadsDir(beadsDir, beadsDir)
+
+	tests := []struct {
+		query *AgentQuery
+		want  []string
+	}{
+		{b.Query().Role("polecat").Rig("gastown").CircuitState(CircuitOpen), []string{"gt-gastown-polecat-nux"}},
+		{b.Query().Role("polecat").CircuitState(CircuitClosed), []string{"gt-gastown-polecat-ace", "gt-gastown-polecat-fox"}},
+		{b.Query().Rig("gastown").Where("failure_count", ">", "0"), []string{"gt-gastown-polecat-ace", "gt-gastown-polecat-nux"}},
+		{b.Query().Where("role_type", "!=", "polecat"), []string{"gt-gastown-witness"}},
+	}
+	for i, tt := range tests {
+		agents, err := tt.query.Run()
+		if err != nil {
+			t.Fatalf("query %d: %v", i, err)
+		}
+		got := issueIDs(agents)
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("query %d = %v, want %v", i, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Package beads provides the versioned fields block of agent beads.
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// AgentFieldsSchemaVersion is the version of the agent fields block that
+// FormatAgentDescription writes. Bump it when a field changes meaning, and
+// teach parseAgentFieldsBlock to read the older versions.
+//
+// Version 0 is the legacy format: one "key: value" line per field, which
+// ParseAgentFields still reads until every bead has been migrated.
+const AgentFieldsSchemaVersion = 1
+
+// agentFieldsFence opens the agent fields block in an agent bead's
+// description; a line of three backticks closes it.
+const agentFieldsFence = "```gt:agent-fields"
+
+// agentFieldsBlock is the JSON held in the agent fields block.
+type agentFieldsBlock struct {
+	Schema int `json:"schema"`
+	*AgentFields
+}
+
+// formatAgentFieldsBlock returns the agent fields block for fields.
+func formatAgentFieldsBlock(fields *AgentFields) string {
+	// AgentFields holds only strings and integers: marshaling can't fail.
+	data, _ := json.MarshalIndent(agentFieldsBlock{Schema: AgentFieldsSchemaVersion, AgentFields: fields}, "", "  ")
+	return agentFieldsFence + "\n" + string(data) + "\n```"
+}
+
+// parseAgentFieldsBlock extracts agent fields and their schema version from
+// the agent fields block in description. ok is false if there is no block
+// or it isn't valid JSON. A block from a newer schema is read for the fields
+// this version knows.
+func parseAgentFieldsBlock(description string) (fields *AgentFields, schema int, ok bool) {
+	lines := strings.Split(description, "\n")
+	start, end, ok := agentFieldsBlockLines(lines)
+	if !ok {
+		return nil, 0, false
+	}
+
+	block := agentFieldsBlock{AgentFields: &AgentFields{}}
+	if err := json.Unmarshal([]byte(strings.Join(lines[start+1:end], "\n")), &block); err != nil {
+		return nil, 0, false
+	}
+	return block.AgentFields, block.Schema, true
+}
+
+// agentFieldsBlockLines returns the indexes of the lines opening and
+// closing the agent fields block in a description's lines.
+func agentFieldsBlockLines(lines []string) (start, end int, ok bool) {
+	start = -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == agentFieldsFence {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return 0, 0, false
+	}
+	for end = start + 1; end < len(lines); end++ {
+		if strings.TrimSpace(lines[end]) == "```" {
+			return start, end, true
+		}
+	}
+	return 0, 0, false
+}
+
+// AgentFieldsSchema returns the schema version of the agent fields in
+// description: 0 for legacy "key: value" lines (or no fields at all).
+func AgentFieldsSchema(description string) int {
+	if _, schema, ok := parseAgentFieldsBlock(description); ok {
+		return schema
+	}
+	return 0
+}
+
+// AgentFieldPatterns returns the description substrings that bd's
+// --desc-contains can match to find agent beads whose field key is value,
+// or is set at all when value is "": its form in the agent fields block,
+// then the legacy "key: value" line. Value "null" matches a field the
+// legacy format wrote as null (role_type, rig, hook_bead and the other
+// always-written fields) being empty.
+func AgentFieldPatterns(key, value string) []string {
+	structured := strconv.Quote(key) + ": "
+	switch {
+	case value == "":
+	case value == "null":
+		structured += `""`
+	case agentFieldIsNumber(key):
+		structured += value
+	default:
+		structured += strconv.Quote(value)
+	}
+	return []string{structured, AgentFieldLine(key, value)}
+}
+
+// agentFieldIsNumber reports whether the agent field named key (its JSON
+// name) is written as a number.
+func agentFieldIsNumber(key string) bool {
+	t := reflect.TypeOf(AgentFields{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name == key {
+			return field.Type.Kind() != reflect.String
+		}
+	}
+	return false
+}
+
+// AgentFieldsMigration reports what MigrateAgentBeads did, or in a dry run
+// would do, in one beads database.
+type AgentFieldsMigration struct {
+	Migrated []string         // Agent beads rewritten in the current schema
+	Current  int              // Agent beads already in the current schema
+	Skipped  []string         // Agent beads without agent fields to migrate (dogs, unparseable descriptions)
+	Failed   map[string]error // Agent beads that couldn't be rewritten
+}
+
+// MigrateAgentBeads rewrites every agent bead whose fields are still held
+// in an older schema (usually legacy "key: value" lines) as a current
+// agent fields block. Dog beads keep their own description format and are
+// skipped, as are descriptions ParseAgentFields recovers nothing from ('gt
+// doctor' reports those). Each rewrite happens under the agent bead lock, so
+// it's safe while agents are running.
+func (b *Beads) MigrateAgentBeads(dryRun bool) (*AgentFieldsMigration, error) {
+	out, err := b.run("list", "--label=gt:agent", "--status=all", "--limit=0", "--json")
+	if err != nil {
+		return nil, err
+	}
+	var issues []*Issue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return nil, fmt.Errorf("parsing bd list output: %w", err)
+	}
+
+	result := &AgentFieldsMigration{Failed: make(map[string]error)}
+	for _, issue := range issues {
+		if AgentFieldsSchema(issue.Description) >= AgentFieldsSchemaVersion {
+			result.Current++
+			continue
+		}
+		fields := ParseAgentFields(issue.Description)
+		if fields.RoleType == "" || fields.RoleType == "dog" {
+			result.Skipped = append(result.Skipped, issue.ID)
+			continue
+		}
+		if !dryRun {
+			if err := b.migrateAgentBead(issue.ID); err != nil {
+				result.Failed[issue.ID] = err
+				continue
+			}
+		}
+		result.Migrated = append(result.Migrated, issue.ID)
+	}
+	return result, nil
+}
+
+// migrateAgentBead rewrites one agent bead's fields in the current schema,
+// rereading it under the agent bead lock.
+func (b *Beads) migrateAgentBead(id string) error {
+	fl, lockErr := b.lockAgentBead(id)
+	if lockErr != nil {
+		return fmt.Errorf("locking agent bead %s: %w", id, lockErr)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+	if AgentFieldsSchema(issue.Description) >= AgentFieldsSchemaVersion {
+		return nil
+	}
+	fields := ParseAgentFields(issue.Description)
+	description := FormatAgentDescription(issue.Title, fields)
+	if err := b.Update(id, UpdateOptions{Description: &description}); err != nil {
+		return err
+	}
+	b.recordAgentHistory(id, AgentOpMigrate, fields.Revision, nil)
+	return nil
+}
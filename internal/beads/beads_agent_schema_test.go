@@ -0,0 +1,109 @@
+package beads
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAgentFields_ReadsLegacyAndBlock(t *testing.T) {
+	legacy := "Polecat nux\n\nrole_type: polecat\nrig: gastown\nagent_state: working\nhook_bead: gt-abc\n" +
+		"cleanup_status: null\nactive_mr: null\nnotification_level: null\ncircuit_state: open\nfailure_count: 3"
+	if got := AgentFieldsSchema(legacy); got != 0 {
+		t.Fatalf("legacy schema = %d, want 0", got)
+	}
+	fields := ParseAgentFields(legacy)
+
+	migrated := FormatAgentDescription("Polecat nux", fields)
+	if got := AgentFieldsSchema(migrated); got != AgentFieldsSchemaVersion {
+		t.Fatalf("migrated schema = %d, want %d", got, AgentFieldsSchemaVersion)
+	}
+	if !strings.HasPrefix(migrated, "Polecat nux\n\n"+agentFieldsFence+"\n") || strings.Contains(migrated, "role_type: ") {
+		t.Errorf("migrated description isn't a fields block:\n%s", migrated)
+	}
+	if got := ParseAgentFields(migrated); *got != *fields {
+		t.Errorf("round trip = %+v, want %+v", got, fields)
+	}
+	if fields.RoleType != "polecat" || fields.HookBead != "gt-abc" || fields.CleanupStatus != "" || fields.FailureCount != 3 {
+		t.Errorf("legacy fields = %+v", fields)
+	}
+
+	// Values that would break a line format survive the block.
+	fields.CircuitNote = "reset by mayor: flaky\nretry"
+	if got := ParseAgentFields(FormatAgentDescription("Polecat nux", fields)); got.CircuitNote != fields.CircuitNote {
+		t.Errorf("circuit_note = %q, want %q", got.CircuitNote, fields.CircuitNote)
+	}
+
+	// A newer schema is read for the fields this version knows.
+	newer := "Polecat nux\n\n" + agentFieldsFence + "\n{\"schema\": 9, \"role_type\": \"polecat\", \"shiny\": true}\n```"
+	if got := ParseAgentFields(newer); got.RoleType != "polecat" || AgentFieldsSchema(newer) != 9 {
+		t.Errorf("newer schema: fields %+v, schema %d", got, AgentFieldsSchema(newer))
+	}
+
+	// An unterminated block falls back to the legacy lines.
+	broken := legacy + "\n" + agentFieldsFence + "\n{\"schema\": 1"
+	if got := ParseAgentFields(broken); got.RoleType != "polecat" || AgentFieldsSchema(broken) != 0 {
+		t.Errorf("unterminated block: fields %+v, schema %d", got, AgentFieldsSchema(broken))
+	}
+}
+
+func TestReplaceAgentFields_KeepsOtherText(t *testing.T) {
+	dog := "Dog: alpha\n\nrole_type: dog\nrig: town\nlocation: deacon/dogs/alpha"
+	fields := ParseAgentFields(dog)
+	fields.HookBead = "hq-abc"
+	replaced := ReplaceAgentFields(dog, fields)
+	if !strings.HasPrefix(replaced, "Dog: alpha\n\nlocation: deacon/dogs/alpha\n\n"+agentFieldsFence+"\n") ||
+		strings.Contains(replaced, "role_type: ") {
+		t.Fatalf("replaced legacy description:\n%s", replaced)
+	}
+	if got := ParseAgentFields(replaced); *got != *fields {
+		t.Errorf("fields = %+v, want %+v", got, fields)
+	}
+
+	// A second write replaces the block in place.
+	fields.Revision++
+	again := ReplaceAgentFields(replaced+"\nnotes after", fields)
+	if strings.Count(again, agentFieldsFence) != 1 || !strings.HasSuffix(again, "```\nnotes after") ||
+		!strings.Contains(again, "location: deacon/dogs/alpha") {
+		t.Errorf("replaced block description:\n%s", again)
+	}
+	if got := ParseAgentFields(again); got.Revision != fields.Revision {
+		t.Errorf("revision = %d, want %d", got.Revision, fields.Revision)
+	}
+}
+
+func TestAgentFieldPatterns(t *testing.T) {
+	polecat := &AgentFields{RoleType: "polecat", Rig: "gastown", CircuitState: CircuitOpen, FailureCount: 3}
+	mayor := &AgentFields{RoleType: "mayor"}
+	other := &AgentFields{RoleType: "polecat", Rig: "gastown2"}
+	legacyMayor := "Mayor\n\nrole_type: mayor\nrig: null"
+
+	matches := func(description, key, value string) bool {
+		for _, pattern := range AgentFieldPatterns(key, value) {
+			if strings.Contains(description, pattern) {
+				return true
+			}
+		}
+		return false
+	}
+	tests := []struct {
+		description string
+		key, value  string
+		want        bool
+	}{
+		{FormatAgentDescription("nux", polecat), "circuit_state", CircuitOpen, true},
+		{FormatAgentDescription("nux", polecat), "failure_count", "3", true},
+		{FormatAgentDescription("nux", polecat), "failure_count", "", true},
+		{FormatAgentDescription("nux", polecat), "rig", "gastown", true},
+		{FormatAgentDescription("ace", other), "rig", "gastown", false},
+		{FormatAgentDescription("ace", other), "failure_count", "", false},
+		{FormatAgentDescription("mayor", mayor), "rig", "null", true},
+		{FormatAgentDescription("nux", polecat), "rig", "null", false},
+		{legacyMayor, "rig", "null", true},
+		{legacyMayor, "role_type", "mayor", true},
+	}
+	for _, tt := range tests {
+		if got := matches(tt.description, tt.key, tt.value); got != tt.want {
+			t.Errorf("%s=%q in %q: matched %v, want %v", tt.key, tt.value, tt.description, got, tt.want)
+		}
+	}
+}
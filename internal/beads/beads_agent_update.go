@@ -0,0 +1,121 @@
+// Package beads provides compare-and-swap updates of agent bead fields.
+package beads
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// AnyAgentRevision, passed to UpdateAgentFields as the expected revision,
+// applies the update whatever revision the fields are at.
+const AnyAgentRevision int64 = -1
+
+// ErrAgentFieldsConflict is returned by UpdateAgentFields when an agent
+// bead's fields were written since the revision the caller expected.
+var ErrAgentFieldsConflict = errors.New("agent fields changed since they were read")
+
+// UpdateAgentFields applies modify to an agent bead's fields in one
+// read-modify-write under the agent bead lock, and returns the fields as
+// written. Everything modify changes lands in the same write, so the
+// Witness, Deacon and daemon can each change several fields (a failure and
+// the trip it causes, say) without their writes interleaving.
+//
+// expectedRevision makes the update a compare-and-swap: unless the fields
+// are still at that Revision (the one the caller read and decided on),
+// nothing is written and the error wraps ErrAgentFieldsConflict, and the
+// caller should re-read and decide again. AnyAgentRevision skips the check.
+// If modify returns an error, nothing is written and that error is
+// returned.
+//
+// Each write bumps Revision and is recorded in the agent history. If modify
+// changes HookBead, the hook slot is set or cleared to match before the
+// lock is released.
+func (b *Beads) UpdateAgentFields(id string, expectedRevision int64, modify func(*AgentFields) error) (*AgentFields, error) {
+	return b.updateAgentFields(id, expectedRevision, AgentOpUpdate, modify)
+}
+
+// updateAgentFields is UpdateAgentFields, recording the write in the agent
+// history as operation op.
+func (b *Beads) updateAgentFields(id string, expectedRevision int64, op string, modify func(*AgentFields) error) (*AgentFields, error) {
+	fl, lockErr := b.lockAgentBead(id)
+	if lockErr != nil {
+		return nil, fmt.Errorf("locking agent bead %s: %w", id, lockErr)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	issue, err := b.Show(id)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := ParseAgentFields(issue.Description)
+	revision, hook := fields.Revision, fields.HookBead
+	if expectedRevision != AnyAgentRevision && revision != expectedRevision {
+		return nil, fmt.Errorf("%w: %s is at revision %d, expected %d", ErrAgentFieldsConflict, id, revision, expectedRevision)
+	}
+	before := *fields
+	if err := modify(fields); err != nil {
+		return nil, err
+	}
+	fields.Revision = revision + 1
+
+	description := ReplaceAgentFields(issue.Description, fields)
+	if err := b.Update(id, UpdateOptions{Description: &description}); err != nil {
+		return nil, err
+	}
+	b.recordAgentHistory(id, op, fields.Revision, agentFieldChanges(&before, fields))
+	if fields.HookBead != hook {
+		if fields.HookBead == "" {
+			err = b.clearHookSlot(id)
+		} else {
+			err = b.setHookSlot(id, fields.HookBead)
+		}
+		if err != nil {
+			return fields, fmt.Errorf("fields written, but the hook slot wasn't: %w", err)
+		}
+	}
+	return fields, nil
+}
+
+// bumpAgentRevision bumps an agent bead's Revision after a write that goes
+// around its description (the hook slot, bd agent state), so an
+// UpdateAgentFields compare-and-swap decided on the fields from before
+// that write fails instead of applying over it. The caller holds the agent
+// bead lock. It returns the new revision.
+func (b *Beads) bumpAgentRevision(id string) (int64, error) {
+	issue, err := b.Show(id)
+	if err != nil {
+		return 0, fmt.Errorf("bumping revision of %s: %w", id, err)
+	}
+	fields := ParseAgentFields(issue.Description)
+	fields.Revision++
+	description := ReplaceAgentFields(issue.Description, fields)
+	args := append([]string{"update", id}, UpdateOptions{Description: &description}.Flags()...)
+	if _, err := b.runWithRouting(args...); err != nil {
+		return 0, fmt.Errorf("bumping revision of %s: %w", id, err)
+	}
+	return fields.Revision, nil
+}
+
+// agentSlotWrite is a write around an agent bead's description, as its
+// history records it.
+type agentSlotWrite struct {
+	op     string
+	change AgentFieldChange
+}
+
+// recordAgentSlotWrites bumps an agent bead's Revision after writes around
+// its description and records them in the agent history at the new
+// revision. The writes are done whatever happens here, so a failed bump
+// is a warning, like a failed history write.
+func (b *Beads) recordAgentSlotWrites(id string, writes ...agentSlotWrite) {
+	revision, err := b.bumpAgentRevision(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	for _, w := range writes {
+		b.recordAgentHistory(id, w.op, revision, []AgentFieldChange{w.change})
+	}
+}
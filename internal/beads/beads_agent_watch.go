@@ -0,0 +1,295 @@
+// Package beads provides change notifications for agent beads.
+package beads
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultAgentWatchInterval is AgentQuery.Watch's interval when none is
+// given: how often it checks its database for writes when it can't be
+// notified of them.
+const DefaultAgentWatchInterval = 5 * time.Second
+
+// agentWatchResyncEvery is how many intervals Watch goes without
+// re-reading the agents when the database looks unchanged, in case a
+// write wasn't noticed (one deep in a backend's directory, say).
+const agentWatchResyncEvery = 12
+
+// agentWatchSettle is how long Watch waits after being notified of a write
+// before re-reading, so the burst of writes one bd command makes is read
+// once.
+const agentWatchSettle = 100 * time.Millisecond
+
+// AgentChange is a change to an agent bead seen by AgentQuery.Watch.
+type AgentChange struct {
+	ID     string
+	Old    *AgentFields // Fields before the change; nil for an agent that appeared
+	New    *AgentFields // Fields after the change; nil for an agent closed or deleted
+	Fields []string     // JSON names of the fields that changed, sorted
+}
+
+// Became reports whether the change set field key (its JSON name) to
+// value, from anything else.
+func (c AgentChange) Became(key, value string) bool {
+	return agentFieldString(c.New, key) == value && agentFieldString(c.Old, key) != value
+}
+
+// Cleared reports whether the change unset field key, e.g. hook_bead when
+// an agent's work was unslung.
+func (c AgentChange) Cleared(key string) bool {
+	return c.Old != nil && c.New != nil && agentFieldString(c.Old, key) != "" && agentFieldString(c.New, key) == ""
+}
+
+// agentFieldString returns a field as a filter would compare it: "" when
+// unset (or fields is nil).
+func agentFieldString(fields *AgentFields, key string) string {
+	i, ok := agentFieldIndexes[key]
+	if fields == nil || !ok {
+		return ""
+	}
+	return agentFieldValue(reflect.ValueOf(fields).Elem().Field(i))
+}
+
+// Watch reports changes to the agent beads the query matches until ctx is
+// done, then closes the channel. A change is reported if the agent matched
+// before or after it, so an agent leaving the query (its circuit closing,
+// say) is seen too; the first read only sets the baseline.
+//
+// Watch is notified (fsnotify) of writes to the database's files and the
+// directories directly under its .beads, and re-reads the agents (from the
+// agent index when there is one) once the writes settle. Where it can't be
+// notified, it checks every interval (DefaultAgentWatchInterval if zero)
+// whether those files were written instead. Either way it also re-reads
+// after a dozen quiet intervals. Changes in the Revision bookkeeping alone
+// aren't reported. A failed read is retried at the next interval. The
+// error is the query's, as Run would return it.
+func (q *AgentQuery) Watch(ctx context.Context, interval time.Duration) (<-chan AgentChange, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if interval <= 0 {
+		interval = DefaultAgentWatchInterval
+	}
+	changes := make(chan AgentChange, 16)
+	go func() {
+		defer close(changes)
+		dir := q.b.getResolvedBeadsDir()
+		var events <-chan fsnotify.Event
+		var errs <-chan error
+		watcher := watchBeadsDir(dir)
+		if watcher != nil {
+			defer func() { _ = watcher.Close() }()
+			events, errs = watcher.Events, watcher.Errors
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var known map[string]*AgentFields
+		var stamp time.Time
+		var settle <-chan time.Time
+		quiet := 0
+		// read re-reads the agents and sends what changed since the last
+		// read. It reports false once ctx is done.
+		read := func() bool {
+			latest := agentWatchStamp(dir)
+			current, err := q.snapshot()
+			if err != nil {
+				return true
+			}
+			for _, change := range q.diff(known, current) {
+				select {
+				case changes <- change:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			known, stamp, quiet = current, latest, 0
+			return true
+		}
+
+		if !read() {
+			return
+		}
+		for {
+			select {
+			case ev, ok := <-events:
+				if !ok {
+					events, errs = nil, nil // Fall back to checking
+					continue
+				}
+				if agentWatchIgnored(dir, ev.Name) {
+					continue
+				}
+				if ev.Has(fsnotify.Create) && filepath.Dir(ev.Name) == dir {
+					if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+						_ = watcher.Add(ev.Name)
+					}
+				}
+				if settle == nil {
+					settle = time.After(agentWatchSettle)
+				}
+			case _, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				// Events may have been dropped: read to catch up.
+				if settle == nil {
+					settle = time.After(agentWatchSettle)
+				}
+			case <-settle:
+				settle = nil
+				if !read() {
+					return
+				}
+			case <-ticker.C:
+				quiet++
+				if known == nil || quiet >= agentWatchResyncEvery || (events == nil && agentWatchStamp(dir).After(stamp)) {
+					if !read() {
+						return
+					}
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return changes, nil
+}
+
+// watchBeadsDir returns a watcher on a beads database's directory and the
+// directories directly under it, or nil if it can't be watched.
+func watchBeadsDir(beadsDir string) *fsnotify.Watcher {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil
+	}
+	if err := watcher.Add(beadsDir); err != nil {
+		_ = watcher.Close()
+		return nil
+	}
+	entries, _ := os.ReadDir(beadsDir)
+	for _, entry := range entries {
+		if entry.IsDir() && !agentWatchIgnored(beadsDir, filepath.Join(beadsDir, entry.Name())) {
+			_ = watcher.Add(filepath.Join(beadsDir, entry.Name()))
+		}
+	}
+	return watcher
+}
+
+// agentWatchIgnored reports whether a write to path, in a beads database's
+// directory or one directly under it, isn't a write to the database: the
+// agent index (see AgentIndexFile) and the agent bead locks.
+func agentWatchIgnored(beadsDir, path string) bool {
+	rel, err := filepath.Rel(beadsDir, path)
+	if err != nil {
+		return false
+	}
+	top := strings.SplitN(filepath.ToSlash(rel), "/", 2)[0]
+	return strings.HasPrefix(top, AgentIndexFile) || top == ".locks"
+}
+
+// snapshot reads the fields of every open agent bead in the database.
+func (q *AgentQuery) snapshot() (map[string]*AgentFields, error) {
+	agents, err := q.b.ListAgentBeads()
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]*AgentFields, len(agents))
+	for id, issue := range agents {
+		if issue.Status != "closed" {
+			current[id] = AgentIssueFields(issue)
+		}
+	}
+	return current, nil
+}
+
+// diff returns the changes from before to after among agents the query
+// matches on either side, sorted by ID. A nil before is the baseline and
+// has none.
+func (q *AgentQuery) diff(before, after map[string]*AgentFields) []AgentChange {
+	if before == nil {
+		return nil
+	}
+	var changes []AgentChange
+	report := func(id string, old, updated *AgentFields) {
+		if (old == nil || !q.matchesFields(old)) && (updated == nil || !q.matchesFields(updated)) {
+			return
+		}
+		if fields := ChangedAgentFields(old, updated); len(fields) > 0 {
+			changes = append(changes, AgentChange{ID: id, Old: old, New: updated, Fields: fields})
+		}
+	}
+	for id, updated := range after {
+		report(id, before[id], updated)
+	}
+	for id, old := range before {
+		if after[id] == nil {
+			report(id, old, nil)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+	return changes
+}
+
+// ChangedAgentFields returns the JSON names of the fields that differ
+// between old and updated, sorted; nil stands for all fields unset.
+// Revision is bookkeeping and never reported.
+func ChangedAgentFields(old, updated *AgentFields) []string {
+	var changed []string
+	for name := range agentFieldIndexes {
+		if name != "revision" && agentFieldString(old, name) != agentFieldString(updated, name) {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// agentWatchStamp returns the latest modification time among a beads
+// database's files and the directories directly under its .beads (where
+// Dolt and SQLite backends keep theirs), as a cheap sign it was written.
+// The agent index (see AgentIndexFile) isn't the database and is skipped.
+func agentWatchStamp(beadsDir string) time.Time {
+	var latest time.Time
+	note := func(info os.FileInfo) {
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	entries, err := os.ReadDir(beadsDir)
+	if err != nil {
+		return latest
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), AgentIndexFile) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		note(info)
+		if !entry.IsDir() || entry.Name() == ".locks" {
+			continue
+		}
+		children, err := os.ReadDir(filepath.Join(beadsDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		for _, child := range children {
+			if info, err := child.Info(); err == nil {
+				note(info)
+			}
+		}
+	}
+	return latest
+}
@@ -0,0 +1,49 @@
+package beads
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAgentQuery_DiffReportsTransitions(t *testing.T) {
+	q := (&Beads{}).Query().Role("polecat").Rig("gastown")
+	before := map[string]*AgentFields{
+		"gt-gastown-polecat-nux": {RoleType: "polecat", Rig: "gastown", HookBead: "gt-abc", Revision: 4},
+		"gt-gastown-polecat-ace": {RoleType: "polecat", Rig: "gastown", CircuitState: CircuitOpen},
+		"gt-gastown-polecat-fox": {RoleType: "polecat", Rig: "gastown", Revision: 1},
+		"gt-gastown-witness":     {RoleType: "witness", Rig: "gastown"},
+	}
+	after := map[string]*AgentFields{
+		"gt-gastown-polecat-nux": {RoleType: "polecat", Rig: "gastown", CircuitState: CircuitOpen, FailureCount: 3, Revision: 5},
+		"gt-gastown-polecat-fox": {RoleType: "polecat", Rig: "gastown", Revision: 2}, // bookkeeping only
+		"gt-gastown-polecat-new": {RoleType: "polecat", Rig: "gastown"},
+		"gt-gastown-witness":     {RoleType: "witness", Rig: "gastown", AgentState: "working"}, // outside the query
+	}
+
+	if changes := q.diff(nil, after); len(changes) != 0 {
+		t.Fatalf("baseline reported %d change(s)", len(changes))
+	}
+	changes := q.diff(before, after)
+	var ids []string
+	for _, c := range changes {
+		ids = append(ids, c.ID)
+	}
+	want := []string{"gt-gastown-polecat-ace", "gt-gastown-polecat-new", "gt-gastown-polecat-nux"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("changed agents = %v, want %v", ids, want)
+	}
+
+	gone, appeared, nux := changes[0], changes[1], changes[2]
+	if gone.New != nil || !gone.Became("circuit_state", "") {
+		t.Errorf("closed agent change = %+v, want New nil", gone)
+	}
+	if appeared.Old != nil || appeared.Became("circuit_state", CircuitOpen) {
+		t.Errorf("new agent change = %+v", appeared)
+	}
+	if !nux.Became("circuit_state", CircuitOpen) || !nux.Cleared("hook_bead") || nux.Cleared("rig") {
+		t.Errorf("nux change = %+v, want circuit opened and hook cleared", nux)
+	}
+	if fields := []string{"circuit_state", "failure_count", "hook_bead"}; !reflect.DeepEqual(nux.Fields, fields) {
+		t.Errorf("nux fields = %v, want %v", nux.Fields, fields)
+	}
+}
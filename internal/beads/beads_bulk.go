@@ -0,0 +1,416 @@
+// Package beads provides bulk create, update and close of beads.
+package beads
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Bulk operations.
+const (
+	BulkCreate = "create"
+	BulkUpdate = "update"
+	BulkClose  = "close"
+)
+
+// BulkOp is one operation of a bulk batch, as a line of 'gt beads bulk'
+// input reads it.
+type BulkOp struct {
+	Op string `json:"op"`           // create, update or close
+	ID string `json:"id,omitempty"` // The bead to update or close
+
+	Title        *string  `json:"title,omitempty"`
+	Type         string   `json:"type,omitempty"` // create only
+	Description  *string  `json:"description,omitempty"`
+	Parent       string   `json:"parent,omitempty"` // create only
+	Priority     *int     `json:"priority,omitempty"`
+	Status       *string  `json:"status,omitempty"`        // update only
+	Assignee     *string  `json:"assignee,omitempty"`      // update only; "" unassigns
+	Labels       []string `json:"labels,omitempty"`        // create: its labels; update: replaces them all
+	AddLabels    []string `json:"add_labels,omitempty"`    // update only
+	RemoveLabels []string `json:"remove_labels,omitempty"` // update only
+	Reason       string   `json:"reason,omitempty"`        // close only
+}
+
+// bulkFields maps each operation to the fields it accepts besides op.
+var bulkFields = map[string][]string{
+	BulkCreate: {"title", "type", "description", "parent", "priority", "labels"},
+	BulkUpdate: {"id", "title", "description", "priority", "status", "assignee", "labels", "add_labels", "remove_labels"},
+	BulkClose:  {"id", "reason"},
+}
+
+// setFields returns the JSON names of the fields op sets, besides op.
+func (op BulkOp) setFields() []string {
+	set := []struct {
+		name string
+		ok   bool
+	}{
+		{"id", op.ID != ""},
+		{"title", op.Title != nil},
+		{"type", op.Type != ""},
+		{"description", op.Description != nil},
+		{"parent", op.Parent != ""},
+		{"priority", op.Priority != nil},
+		{"status", op.Status != nil},
+		{"assignee", op.Assignee != nil},
+		{"labels", op.Labels != nil},
+		{"add_labels", len(op.AddLabels) > 0},
+		{"remove_labels", len(op.RemoveLabels) > 0},
+		{"reason", op.Reason != ""},
+	}
+	var names []string
+	for _, field := range set {
+		if field.ok {
+			names = append(names, field.name)
+		}
+	}
+	return names
+}
+
+// BulkUpdateCall is one bd update of a bulk plan: the same change to
+// every bead in IDs.
+type BulkUpdateCall struct {
+	IDs     []string
+	Options UpdateOptions
+}
+
+// BulkCloseCall is one bd close of a bulk plan.
+type BulkCloseCall struct {
+	IDs    []string
+	Reason string
+}
+
+// BulkStep is one bd call of a bulk plan: exactly one of its fields is set.
+type BulkStep struct {
+	Create *CreateOptions
+	Update *BulkUpdateCall
+	Close  *BulkCloseCall
+}
+
+// BulkPlan is a validated bulk batch, grouped into as few bd calls as
+// possible while keeping its order: a create per new bead (bd creates one
+// at a time), and within each run of consecutive updates or closes, an
+// update per distinct change and a close per distinct reason.
+type BulkPlan struct {
+	Steps []BulkStep
+}
+
+// BulkResult is what ApplyBulk did.
+type BulkResult struct {
+	Created    []string `json:"created,omitempty"` // New bead IDs, in the order of their create ops
+	Updated    []string `json:"updated,omitempty"`
+	Closed     []string `json:"closed,omitempty"`
+	Calls      int      `json:"calls"`                 // bd invocations made
+	RolledBack bool     `json:"rolled_back,omitempty"` // A call failed and the batch was undone
+}
+
+// ReadBulkOps reads a batch as JSONL: an operation per line. Blank lines
+// and lines starting with # are skipped; an unknown field is an error, so
+// a misspelled one doesn't silently do nothing.
+func ReadBulkOps(r io.Reader) ([]BulkOp, error) {
+	var ops []BulkOp
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.DisallowUnknownFields()
+		var op BulkOp
+		if err := dec.Decode(&op); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		ops = append(ops, op)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading bulk input: %w", err)
+	}
+	return ops, nil
+}
+
+// PlanBulk validates a batch and groups it into bd calls. Nothing is
+// written: a batch with any invalid op is rejected whole.
+func PlanBulk(ops []BulkOp) (*BulkPlan, error) {
+	plan := &BulkPlan{}
+	// The run of consecutive updates or closes being grouped: change key
+	// (or close reason) -> index in plan.Steps.
+	var runOp string
+	var run map[string]int
+	var errs []error
+	for i, op := range ops {
+		if err := validateBulkOp(op); err != nil {
+			errs = append(errs, fmt.Errorf("op %d: %w", i+1, err))
+			continue
+		}
+		if op.Op != runOp {
+			runOp, run = op.Op, make(map[string]int)
+		}
+		switch op.Op {
+		case BulkCreate:
+			create := CreateOptions{Title: *op.Title, Type: op.Type, Parent: op.Parent, Priority: -1, Labels: op.Labels}
+			if op.Description != nil {
+				create.Description = *op.Description
+			}
+			if op.Priority != nil {
+				create.Priority = *op.Priority
+			}
+			plan.Steps = append(plan.Steps, BulkStep{Create: &create})
+
+		case BulkUpdate:
+			opts := UpdateOptions{Title: op.Title, Status: op.Status, Priority: op.Priority, Description: op.Description,
+				Assignee: op.Assignee, SetLabels: op.Labels, AddLabels: op.AddLabels, RemoveLabels: op.RemoveLabels}
+			key := strings.Join(opts.Flags(), "\x00")
+			// Join an earlier identical change in the run unless the bead
+			// has a change in a later call, which must keep coming after it.
+			if j, ok := run[key]; ok && !bulkUpdatedAfter(plan.Steps, j, op.ID) {
+				if call := plan.Steps[j].Update; !containsString(call.IDs, op.ID) {
+					call.IDs = append(call.IDs, op.ID)
+				}
+				continue
+			}
+			run[key] = len(plan.Steps)
+			plan.Steps = append(plan.Steps, BulkStep{Update: &BulkUpdateCall{IDs: []string{op.ID}, Options: opts}})
+
+		case BulkClose:
+			if j, ok := run[op.Reason]; ok {
+				if call := plan.Steps[j].Close; !containsString(call.IDs, op.ID) {
+					call.IDs = append(call.IDs, op.ID)
+				}
+				continue
+			}
+			run[op.Reason] = len(plan.Steps)
+			plan.Steps = append(plan.Steps, BulkStep{Close: &BulkCloseCall{IDs: []string{op.ID}, Reason: op.Reason}})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return plan, nil
+}
+
+// validateBulkOp checks an op has what its operation needs and nothing it
+// doesn't take.
+func validateBulkOp(op BulkOp) error {
+	allowed, ok := bulkFields[op.Op]
+	if !ok {
+		return fmt.Errorf("unknown op %q (want create, update or close)", op.Op)
+	}
+	fields := op.setFields()
+	for _, name := range fields {
+		if !containsString(allowed, name) {
+			return fmt.Errorf("%s doesn't take %s", op.Op, name)
+		}
+	}
+	switch {
+	case op.Op == BulkCreate && (op.Title == nil || strings.TrimSpace(*op.Title) == ""):
+		return fmt.Errorf("create needs a title")
+	case op.Op == BulkCreate && IsFlagLikeTitle(*op.Title):
+		return fmt.Errorf("%w (got %q)", ErrFlagTitle, *op.Title)
+	case op.Op != BulkCreate && op.ID == "":
+		return fmt.Errorf("%s needs an id", op.Op)
+	case op.Op == BulkUpdate && len(fields) == 1:
+		return fmt.Errorf("update of %s changes nothing", op.ID)
+	case op.Op == BulkUpdate && op.Labels != nil && len(op.Labels) == 0:
+		return fmt.Errorf("labels can't be empty; use remove_labels")
+	case op.Op == BulkUpdate && op.Labels != nil && len(op.AddLabels)+len(op.RemoveLabels) > 0:
+		return fmt.Errorf("labels replaces them all; it can't be combined with add_labels or remove_labels")
+	case op.Priority != nil && (*op.Priority < 0 || *op.Priority > 4):
+		return fmt.Errorf("priority %d is out of range (0-4)", *op.Priority)
+	}
+	return nil
+}
+
+// bulkUpdatedAfter reports whether an update after steps[i] updates id.
+func bulkUpdatedAfter(steps []BulkStep, i int, id string) bool {
+	for _, step := range steps[i+1:] {
+		if step.Update != nil && containsString(step.Update.IDs, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ExistingIDs returns the beads the plan updates or closes, each once.
+func (p *BulkPlan) ExistingIDs() []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, step := range p.Steps {
+		var list []string
+		switch {
+		case step.Update != nil:
+			list = step.Update.IDs
+		case step.Close != nil:
+			list = step.Close.IDs
+		}
+		for _, id := range list {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// ApplyBulk runs a plan against this wrapper's database, in order. It
+// first reads every bead the plan updates or closes, so a typo'd ID fails
+// the batch before anything is written. Each step is one bd invocation,
+// which bd applies in a single transaction; bd has no transaction spanning
+// invocations, so if one fails ApplyBulk undoes the steps before it:
+// beads it created are deleted, and beads it updated or closed get back
+// the fields, labels and status they were read with. The result reports
+// what was applied and whether it was rolled back.
+func (b *Beads) ApplyBulk(plan *BulkPlan) (*BulkResult, error) {
+	result := &BulkResult{}
+	before := make(map[string]*Issue)
+	if ids := plan.ExistingIDs(); len(ids) > 0 {
+		found, err := b.ShowMultiple(ids)
+		result.Calls++
+		if err != nil {
+			return result, err
+		}
+		var missing []string
+		for _, id := range ids {
+			if found[id] == nil {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) > 0 {
+			return result, fmt.Errorf("%w: %s", ErrNotFound, strings.Join(missing, ", "))
+		}
+		before = found
+	}
+
+	var touched []string
+	for _, step := range plan.Steps {
+		var err error
+		switch {
+		case step.Create != nil:
+			var issue *Issue
+			issue, err = b.Create(*step.Create)
+			if err != nil {
+				err = fmt.Errorf("creating %q: %w", step.Create.Title, err)
+				break
+			}
+			result.Created = append(result.Created, issue.ID)
+
+		case step.Update != nil:
+			call := step.Update
+			touched = append(touched, call.IDs...)
+			args := append(append([]string{"update"}, call.IDs...), call.Options.Flags()...)
+			if _, err = b.run(args...); err != nil {
+				err = fmt.Errorf("updating %s: %w", strings.Join(call.IDs, ", "), err)
+				break
+			}
+			for _, id := range call.IDs {
+				if !containsString(result.Updated, id) {
+					result.Updated = append(result.Updated, id)
+				}
+			}
+
+		case step.Close != nil:
+			call := step.Close
+			touched = append(touched, call.IDs...)
+			if call.Reason != "" {
+				err = b.CloseWithReason(call.Reason, call.IDs...)
+			} else {
+				err = b.Close(call.IDs...)
+			}
+			if err != nil {
+				err = fmt.Errorf("closing %s: %w", strings.Join(call.IDs, ", "), err)
+				break
+			}
+			result.Closed = append(result.Closed, call.IDs...)
+		}
+		result.Calls++
+		if err != nil {
+			if rbErr := b.rollbackBulk(result.Created, touched, before); rbErr != nil {
+				return result, fmt.Errorf("%w; rolling back failed too, the batch is partly applied: %v", err, rbErr)
+			}
+			result.RolledBack = true
+			return result, fmt.Errorf("%w (rolled back)", err)
+		}
+	}
+	return result, nil
+}
+
+// rollbackBulk undoes a failed bulk batch: it deletes the beads it
+// created, and puts the beads it touched back as they were in before.
+func (b *Beads) rollbackBulk(created, touched []string, before map[string]*Issue) error {
+	var errs []error
+	for _, id := range created {
+		if _, err := b.run("delete", id, "--hard", "--force"); err != nil {
+			errs = append(errs, fmt.Errorf("deleting %s: %w", id, err))
+		}
+	}
+	restored := make(map[string]bool)
+	for _, id := range touched {
+		if restored[id] {
+			continue
+		}
+		restored[id] = true
+		if err := b.restoreBulkBead(before[id]); err != nil {
+			errs = append(errs, fmt.Errorf("restoring %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// restoreBulkBead puts a bead back as old.
+func (b *Beads) restoreBulkBead(old *Issue) error {
+	current, err := b.Show(old.ID)
+	if err != nil {
+		return err
+	}
+	if current.Status == "closed" && old.Status != "closed" {
+		if _, err := b.run("reopen", old.ID, "--reason=bulk batch rolled back"); err != nil {
+			return err
+		}
+	}
+	opts := UpdateOptions{Title: &old.Title, Description: &old.Description, Priority: &old.Priority, Assignee: &old.Assignee}
+	if old.Status != "closed" {
+		opts.Status = &old.Status
+	}
+	for _, label := range old.Labels {
+		if !containsString(current.Labels, label) {
+			opts.AddLabels = append(opts.AddLabels, label)
+		}
+	}
+	for _, label := range current.Labels {
+		if !containsString(old.Labels, label) {
+			opts.RemoveLabels = append(opts.RemoveLabels, label)
+		}
+	}
+	if err := b.Update(old.ID, opts); err != nil {
+		return err
+	}
+	if current.Status != "closed" && old.Status == "closed" {
+		return b.CloseWithReason("bulk batch rolled back", old.ID)
+	}
+	return nil
+}
+
+// Bulk validates and applies a batch; see PlanBulk and ApplyBulk.
+func (b *Beads) Bulk(ops []BulkOp) (*BulkResult, error) {
+	plan, err := PlanBulk(ops)
+	if err != nil {
+		return nil, err
+	}
+	return b.ApplyBulk(plan)
+}
@@ -0,0 +1,106 @@
+package beads
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadBulkOps(t *testing.T) {
+	input := `# requeue the stuck ones
+{"op":"update","id":"gt-a","status":"open","assignee":""}
+
+{"op":"close","id":"gt-b","reason":"duplicate"}
+`
+	ops, err := ReadBulkOps(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 2 || ops[0].ID != "gt-a" || ops[0].Assignee == nil || *ops[0].Assignee != "" || ops[1].Reason != "duplicate" {
+		t.Errorf("ReadBulkOps = %+v", ops)
+	}
+
+	_, err = ReadBulkOps(strings.NewReader("{\"op\":\"update\",\"id\":\"gt-a\"}\n{\"op\":\"update\",\"stauts\":\"open\"}\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("a misspelled field should fail with its line, got %v", err)
+	}
+}
+
+func TestPlanBulk_GroupsCalls(t *testing.T) {
+	open, empty := "open", ""
+	requeue := func(id string) BulkOp { return BulkOp{Op: BulkUpdate, ID: id, Status: &open, Assignee: &empty} }
+	retag := func(id string) BulkOp {
+		return BulkOp{Op: BulkUpdate, ID: id, AddLabels: []string{"area:auth"}, RemoveLabels: []string{"triage"}}
+	}
+	title := "Add login rate limit"
+	p1 := 1
+
+	plan, err := PlanBulk([]BulkOp{
+		requeue("gt-a"), requeue("gt-b"), retag("gt-a"), requeue("gt-c"), retag("gt-c"),
+		{Op: BulkCreate, Title: &title, Type: "task", Priority: &p1, Labels: []string{"area:auth"}},
+		{Op: BulkClose, ID: "gt-d", Reason: "duplicate"},
+		{Op: BulkClose, ID: "gt-e", Reason: "duplicate"},
+		requeue("gt-a"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls []string
+	for _, step := range plan.Steps {
+		switch {
+		case step.Create != nil:
+			calls = append(calls, "create "+step.Create.Title)
+		case step.Update != nil:
+			calls = append(calls, "update "+strings.Join(step.Update.IDs, " "))
+		case step.Close != nil:
+			calls = append(calls, "close "+strings.Join(step.Close.IDs, " "))
+		}
+	}
+	// Only consecutive ops are grouped, so gt-a's last requeue stays after
+	// the create and the closes.
+	want := []string{"update gt-a gt-b gt-c", "update gt-a gt-c", "create " + title, "close gt-d gt-e", "update gt-a"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+	if got := plan.Steps[1].Update.Options.Flags(); !reflect.DeepEqual(got, []string{"--add-label=area:auth", "--remove-label=triage"}) {
+		t.Errorf("retag flags = %v", got)
+	}
+	if create := plan.Steps[2].Create; create.labelsFlag() != "--labels=gt:task,area:auth" || create.Priority != 1 {
+		t.Errorf("create = %+v", create)
+	}
+	if got := plan.ExistingIDs(); !reflect.DeepEqual(got, []string{"gt-a", "gt-b", "gt-c", "gt-d", "gt-e"}) {
+		t.Errorf("ExistingIDs = %v", got)
+	}
+}
+
+func TestPlanBulk_RejectsInvalidBatch(t *testing.T) {
+	p9 := 9
+	flagTitle := "--help"
+	_, err := PlanBulk([]BulkOp{
+		{Op: BulkUpdate, ID: "gt-a", Priority: &p9},
+		{Op: "delete", ID: "gt-b"},
+		{Op: BulkClose, ID: "gt-c", Type: "bug"},
+		{Op: BulkUpdate, ID: "gt-d"},
+		{Op: BulkCreate, Title: &flagTitle},
+		{Op: BulkClose},
+	})
+	if err == nil {
+		t.Fatal("expected the batch to be rejected")
+	}
+	for _, want := range []string{
+		"op 1: priority 9 is out of range",
+		`op 2: unknown op "delete"`,
+		"op 3: close doesn't take type",
+		"op 4: update of gt-d changes nothing",
+		"op 6: close needs an id",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q is missing %q", err, want)
+		}
+	}
+	if !errors.Is(err, ErrFlagTitle) {
+		t.Errorf("a flag-like title should be rejected: %v", err)
+	}
+}
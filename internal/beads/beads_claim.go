@@ -0,0 +1,242 @@
+// Package beads provides claim/unclaim semantics for work beads.
+package beads
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/lock"
+)
+
+// DefaultClaimLease is how long a claim holds without renewal.
+const DefaultClaimLease = 2 * time.Hour
+
+// ErrNotClaimed is returned by Unclaim when the bead has no claim.
+var ErrNotClaimed = errors.New("bead is not claimed")
+
+// AlreadyClaimedError is returned when a bead is held by another agent
+// under a live lease. Use errors.As to inspect the holder.
+type AlreadyClaimedError struct {
+	ID         string
+	Holder     string
+	LeaseUntil time.Time // zero for assignments made without a lease
+}
+
+func (e *AlreadyClaimedError) Error() string {
+	if e.LeaseUntil.IsZero() {
+		return fmt.Sprintf("%s is already claimed by %s", e.ID, e.Holder)
+	}
+	return fmt.Sprintf("%s is already claimed by %s (lease until %s)", e.ID, e.Holder, e.LeaseUntil.Format(time.RFC3339))
+}
+
+// ClaimFields are the claim lines stored in a work bead's description.
+type ClaimFields struct {
+	ClaimedBy  string    // Agent address holding the claim
+	ClaimedAt  time.Time // When the claim was taken (or last renewed)
+	LeaseUntil time.Time // When the claim lapses unless renewed
+}
+
+// claimKeys are the description keys owned by ClaimFields.
+var claimKeys = map[string]bool{
+	"claimed_by":  true,
+	"claimed_at":  true,
+	"claim_lease": true,
+}
+
+// ParseClaimFields extracts claim fields from a description.
+// Returns nil if the description has no claim.
+func ParseClaimFields(description string) *ClaimFields {
+	var fields ClaimFields
+	for _, line := range strings.Split(description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "claimed_by":
+			fields.ClaimedBy = value
+		case "claimed_at":
+			fields.ClaimedAt, _ = time.Parse(time.RFC3339, value)
+		case "claim_lease":
+			fields.LeaseUntil, _ = time.Parse(time.RFC3339, value)
+		}
+	}
+	if fields.ClaimedBy == "" {
+		return nil
+	}
+	return &fields
+}
+
+// SetClaimFields replaces the claim lines in a description, preserving all
+// other content. A nil fields removes the claim.
+func SetClaimFields(description string, fields *ClaimFields) string {
+	var kept []string
+	for _, line := range strings.Split(description, "\n") {
+		if key, _, ok := strings.Cut(strings.TrimSpace(line), ":"); ok && claimKeys[strings.TrimSpace(key)] {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	rest := strings.TrimRight(strings.Join(kept, "\n"), "\n")
+	if fields == nil {
+		return rest
+	}
+
+	claim := []string{"claimed_by: " + fields.ClaimedBy}
+	if !fields.ClaimedAt.IsZero() {
+		claim = append(claim, "claimed_at: "+fields.ClaimedAt.UTC().Format(time.RFC3339))
+	}
+	if !fields.LeaseUntil.IsZero() {
+		claim = append(claim, "claim_lease: "+fields.LeaseUntil.UTC().Format(time.RFC3339))
+	}
+	if rest == "" {
+		return strings.Join(claim, "\n")
+	}
+	return rest + "\n" + strings.Join(claim, "\n")
+}
+
+// ClaimHolder returns who holds an issue and until when, or "" if it is free
+// at now. Expired leases are free, as are claims whose holder is no longer the
+// assignee (the bead was unhooked or reassigned outside Claim). An in-progress
+// or hooked assignment made without a claim counts as held with no lease.
+func ClaimHolder(issue *Issue, now time.Time) (string, time.Time) {
+	if issue == nil || issue.Status == "closed" {
+		return "", time.Time{}
+	}
+	if claim := ParseClaimFields(issue.Description); claim != nil && claim.ClaimedBy == issue.Assignee {
+		if !claim.LeaseUntil.IsZero() && now.After(claim.LeaseUntil) {
+			return "", time.Time{}
+		}
+		return claim.ClaimedBy, claim.LeaseUntil
+	}
+	if issue.Assignee != "" && (issue.Status == "in_progress" || issue.Status == StatusHooked) {
+		return issue.Assignee, time.Time{}
+	}
+	return "", time.Time{}
+}
+
+// Claim atomically takes (or renews) a claim on a work bead for holder,
+// recording the holder as assignee and the lease in the description.
+// Returns *AlreadyClaimedError if another agent holds a live claim.
+//
+// Claims on the same beads database are serialized by a file lock, and the
+// result is re-read to catch a concurrent writer that slipped past it (e.g. on
+// another machine sharing the database).
+func (b *Beads) Claim(id, holder string, lease time.Duration) (*ClaimFields, error) {
+	if target := b.routedFor(id); target != nil {
+		return target.Claim(id, holder, lease)
+	}
+	if holder == "" {
+		return nil, fmt.Errorf("claiming %s: holder is required", id)
+	}
+	if lease <= 0 {
+		lease = DefaultClaimLease
+	}
+
+	unlock, err := b.lockClaims(id)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	issue, err := b.Show(id)
+	if err != nil {
+		return nil, fmt.Errorf("claiming %s: %w", id, err)
+	}
+	if issue.Status == "closed" {
+		return nil, fmt.Errorf("claiming %s: bead is closed", id)
+	}
+	now := time.Now()
+	if current, until := ClaimHolder(issue, now); current != "" && current != holder {
+		return nil, &AlreadyClaimedError{ID: id, Holder: current, LeaseUntil: until}
+	}
+
+	fields := &ClaimFields{ClaimedBy: holder, ClaimedAt: now, LeaseUntil: now.Add(lease)}
+	description := SetClaimFields(issue.Description, fields)
+	if err := b.Update(id, UpdateOptions{Assignee: &holder, Description: &description}); err != nil {
+		return nil, fmt.Errorf("claiming %s: %w", id, err)
+	}
+
+	after, err := b.Show(id)
+	if err != nil {
+		return nil, fmt.Errorf("verifying claim on %s: %w", id, err)
+	}
+	switch current, until := ClaimHolder(after, now); current {
+	case holder:
+	case "":
+		return nil, fmt.Errorf("claim on %s did not stick", id)
+	default:
+		return nil, &AlreadyClaimedError{ID: id, Holder: current, LeaseUntil: until}
+	}
+	return fields, nil
+}
+
+// Unclaim releases holder's claim on a bead: the claim lines and assignee are
+// cleared and an in-progress or hooked bead goes back to open. With force,
+// any holder's claim is released. Returns *AlreadyClaimedError if another
+// agent holds the claim, or ErrNotClaimed if nobody does.
+func (b *Beads) Unclaim(id, holder string, force bool) error {
+	if target := b.routedFor(id); target != nil {
+		return target.Unclaim(id, holder, force)
+	}
+
+	unlock, err := b.lockClaims(id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	issue, err := b.Show(id)
+	if err != nil {
+		return fmt.Errorf("unclaiming %s: %w", id, err)
+	}
+	current, until := ClaimHolder(issue, time.Now())
+	if current == "" && ParseClaimFields(issue.Description) == nil {
+		return ErrNotClaimed
+	}
+	if current != "" && current != holder && !force {
+		return &AlreadyClaimedError{ID: id, Holder: current, LeaseUntil: until}
+	}
+
+	empty := ""
+	description := SetClaimFields(issue.Description, nil)
+	opts := UpdateOptions{Assignee: &empty, Description: &description}
+	if issue.Status == "in_progress" || issue.Status == StatusHooked {
+		open := "open"
+		opts.Status = &open
+	}
+	if err := b.Update(id, opts); err != nil {
+		return fmt.Errorf("unclaiming %s: %w", id, err)
+	}
+	return nil
+}
+
+// routedFor returns a wrapper for the database that owns id when it differs
+// from this one (see Show), or nil.
+func (b *Beads) routedFor(id string) *Beads {
+	targetDir := ResolveRoutingTarget(b.getTownRoot(), id, b.getResolvedBeadsDir())
+	if targetDir == b.getResolvedBeadsDir() {
+		return nil
+	}
+	return NewWithBeadsDir(filepath.Dir(targetDir), targetDir)
+}
+
+// lockClaims serializes claim read-modify-writes on this beads database.
+// The lock lives in the owning rig's .runtime/locks directory.
+func (b *Beads) lockClaims(id string) (func(), error) {
+	dir := filepath.Join(filepath.Dir(b.getResolvedBeadsDir()), constants.DirRuntime, "locks")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating claim lock dir: %w", err)
+	}
+	unlock, err := lock.FlockAcquire(filepath.Join(dir, "claim-"+id+".lock"))
+	if err != nil {
+		return nil, fmt.Errorf("locking claim on %s: %w", id, err)
+	}
+	return unlock, nil
+}
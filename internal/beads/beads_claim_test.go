@@ -0,0 +1,72 @@
+package beads
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetClaimFields_RoundTrip(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	desc := "Fix the widget.\n\nattached_molecule: gt-wisp-1"
+	fields := &ClaimFields{ClaimedBy: "gastown/polecats/Toast", ClaimedAt: at, LeaseUntil: at.Add(2 * time.Hour)}
+
+	withClaim := SetClaimFields(desc, fields)
+	if !strings.HasPrefix(withClaim, desc) {
+		t.Errorf("existing content not preserved:\n%s", withClaim)
+	}
+	got := ParseClaimFields(withClaim)
+	if got == nil || *got != *fields {
+		t.Fatalf("ParseClaimFields = %+v, want %+v", got, fields)
+	}
+
+	// Renewing replaces rather than duplicates the claim lines.
+	renewed := SetClaimFields(withClaim, &ClaimFields{ClaimedBy: "gastown/polecats/Toast", ClaimedAt: at, LeaseUntil: at.Add(4 * time.Hour)})
+	if n := strings.Count(renewed, "claim_lease:"); n != 1 {
+		t.Errorf("claim_lease appears %d times after renewal", n)
+	}
+
+	if cleared := SetClaimFields(withClaim, nil); cleared != desc || ParseClaimFields(cleared) != nil {
+		t.Errorf("clearing claim gave %q, want %q", cleared, desc)
+	}
+}
+
+func TestClaimHolder(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	claim := func(holder string, until time.Time) string {
+		return SetClaimFields("", &ClaimFields{ClaimedBy: holder, ClaimedAt: now.Add(-time.Hour), LeaseUntil: until})
+	}
+
+	tests := []struct {
+		name  string
+		issue *Issue
+		want  string
+	}{
+		{"unclaimed open", &Issue{Status: "open"}, ""},
+		{"live lease", &Issue{Status: "open", Assignee: "a", Description: claim("a", now.Add(time.Hour))}, "a"},
+		{"expired lease", &Issue{Status: "hooked", Assignee: "a", Description: claim("a", now.Add(-time.Minute))}, ""},
+		{"claim from before unhook", &Issue{Status: "open", Description: claim("a", now.Add(time.Hour))}, ""},
+		{"legacy hooked assignment", &Issue{Status: StatusHooked, Assignee: "b"}, "b"},
+		{"assigned but open", &Issue{Status: "open", Assignee: "b"}, ""},
+		{"closed", &Issue{Status: "closed", Assignee: "a", Description: claim("a", now.Add(time.Hour))}, ""},
+	}
+	for _, tt := range tests {
+		if got, _ := ClaimHolder(tt.issue, now); got != tt.want {
+			t.Errorf("%s: ClaimHolder = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAlreadyClaimedError(t *testing.T) {
+	var err error = &AlreadyClaimedError{ID: "gt-1", Holder: "gastown/polecats/Toast"}
+	wrapped := errors.Join(errors.New("hooking bead"), err)
+
+	var claimed *AlreadyClaimedError
+	if !errors.As(wrapped, &claimed) || claimed.Holder != "gastown/polecats/Toast" {
+		t.Fatalf("errors.As failed on %v", wrapped)
+	}
+	if !strings.Contains(err.Error(), "already claimed by gastown/polecats/Toast") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
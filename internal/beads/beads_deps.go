@@ -0,0 +1,200 @@
+// Package beads provides the depends_on graph between work beads.
+package beads
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DepTypeBlocks is the dependency type of a depends_on relation: the
+// dependent can't start, or merge, until the dependency is closed. It is
+// what 'bd dep add' records by default.
+const DepTypeBlocks = "blocks"
+
+// IsBlockingDepType reports whether a dependency type holds up the
+// dependent. It matches bd's canonical blocking types (AffectsReadyWork)
+// except parent-child, which gt uses for hierarchy (molecule → step, epic →
+// task), not ordering. Unknown or custom types, and a relation bd reported
+// without a type, are non-blocking, matching bd's default behavior.
+func IsBlockingDepType(depType string) bool {
+	switch depType {
+	case DepTypeBlocks, "conditional-blocks", "waits-for":
+		return true
+	default:
+		return false
+	}
+}
+
+// ErrDependencyCycle is returned by AddDependencyChecked when the new
+// relation would make a bead depend on itself.
+var ErrDependencyCycle = errors.New("dependency cycle")
+
+// BlockedError reports a bead that can't be assigned or merged because of
+// dependencies that aren't closed.
+type BlockedError struct {
+	ID       string
+	Blockers []IssueDep
+}
+
+func (e *BlockedError) Error() string {
+	ids := make([]string, len(e.Blockers))
+	for i, dep := range e.Blockers {
+		ids[i] = dep.ID + " (" + dep.Status + ")"
+	}
+	return fmt.Sprintf("%s is blocked by unfinished dependencies: %s", e.ID, strings.Join(ids, ", "))
+}
+
+// IsBlockingDependency reports whether a dependency of a bead holds it up:
+// a relation of a blocking type on a bead that isn't closed. Molecule bonds (wisp
+// dependencies) are workflow, not work, and never do.
+func IsBlockingDependency(dep IssueDep) bool {
+	if !IsBlockingDepType(dep.DependencyType) || strings.Contains(dep.ID, "-wisp-") {
+		return false
+	}
+	return dep.Status != "closed" && dep.Status != "tombstone"
+}
+
+// UnmetDependencies returns a shown bead's blocking dependencies.
+func UnmetDependencies(issue *Issue) []IssueDep {
+	var unmet []IssueDep
+	for _, dep := range issue.Dependencies {
+		if IsBlockingDependency(dep) {
+			unmet = append(unmet, dep)
+		}
+	}
+	return unmet
+}
+
+// CheckAssignable returns a *BlockedError if bead id has dependencies that
+// aren't closed, so it shouldn't be handed to an agent yet.
+func (b *Beads) CheckAssignable(id string) error {
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+	if unmet := UnmetDependencies(issue); len(unmet) > 0 {
+		return &BlockedError{ID: id, Blockers: unmet}
+	}
+	return nil
+}
+
+// AddDependencyChecked records that issue depends on dependsOn, refusing
+// with ErrDependencyCycle if dependsOn already depends on issue, directly
+// or through other beads.
+func (b *Beads) AddDependencyChecked(issue, dependsOn string) error {
+	if issue == dependsOn {
+		return fmt.Errorf("%w: %s can't depend on itself", ErrDependencyCycle, issue)
+	}
+	graph, err := b.DependencyGraph(dependsOn, 0)
+	if err != nil {
+		return err
+	}
+	if path := graph.PathTo(issue); path != nil {
+		return fmt.Errorf("%w: %s already depends on %s via %s", ErrDependencyCycle, dependsOn, issue, strings.Join(path, " → "))
+	}
+	return b.AddDependency(issue, dependsOn)
+}
+
+// DependencyNode is a bead in a depends_on graph.
+type DependencyNode struct {
+	ID        string
+	Title     string
+	Status    string
+	DependsOn []*DependencyNode // Its depends_on relations, in bd's order
+
+	// Blocked is set when a dependency, however deep, isn't closed.
+	Blocked bool
+	// Seen is set when the bead appears earlier in the graph; its
+	// dependencies are listed there.
+	Seen bool
+	// Truncated is set when the graph's depth limit cut off its
+	// dependencies.
+	Truncated bool
+}
+
+// Done reports whether the bead's work is finished.
+func (n *DependencyNode) Done() bool {
+	return n.Status == "closed" || n.Status == "tombstone"
+}
+
+// DependencyGraph returns the depends_on graph below bead id, following
+// relations up to maxDepth deep (0 for no limit). A bead reached twice is
+// expanded only the first time, which also stops at cycles. Blocked is
+// propagated up from every unfinished dependency.
+func (b *Beads) DependencyGraph(id string, maxDepth int) (*DependencyNode, error) {
+	return dependencyGraph(b.Show, id, maxDepth)
+}
+
+// dependencyGraph is DependencyGraph, reading beads with show.
+func dependencyGraph(show func(id string) (*Issue, error), id string, maxDepth int) (*DependencyNode, error) {
+	seen := make(map[string]bool)
+	var build func(id string, depth int) (*DependencyNode, error)
+	build = func(id string, depth int) (*DependencyNode, error) {
+		issue, err := show(id)
+		if err != nil {
+			return nil, err
+		}
+		node := &DependencyNode{ID: issue.ID, Title: issue.Title, Status: issue.Status}
+		seen[issue.ID] = true
+		for _, dep := range issue.Dependencies {
+			if !IsBlockingDepType(dep.DependencyType) || strings.Contains(dep.ID, "-wisp-") {
+				continue
+			}
+			var child *DependencyNode
+			switch {
+			case seen[dep.ID]:
+				child = &DependencyNode{ID: dep.ID, Title: dep.Title, Status: dep.Status, Seen: true}
+			case maxDepth > 0 && depth >= maxDepth:
+				child = &DependencyNode{ID: dep.ID, Title: dep.Title, Status: dep.Status, Truncated: true}
+			default:
+				if child, err = build(dep.ID, depth+1); err != nil {
+					// A dependency that can't be read is shown as bd listed it.
+					child = &DependencyNode{ID: dep.ID, Title: dep.Title, Status: dep.Status}
+				}
+			}
+			node.DependsOn = append(node.DependsOn, child)
+			if !child.Done() || child.Blocked {
+				node.Blocked = true
+			}
+		}
+		return node, nil
+	}
+	return build(id, 1)
+}
+
+// PathTo returns the IDs from n down to the bead id along depends_on
+// relations, or nil if n doesn't depend on it.
+func (n *DependencyNode) PathTo(id string) []string {
+	if n.ID == id {
+		return []string{n.ID}
+	}
+	for _, child := range n.DependsOn {
+		if path := child.PathTo(id); path != nil {
+			return append([]string{n.ID}, path...)
+		}
+	}
+	return nil
+}
+
+// Frontier returns the unfinished beads below n that aren't blocked
+// themselves: the work that unblocks the chain, each once, in graph order.
+func (n *DependencyNode) Frontier() []*DependencyNode {
+	var frontier []*DependencyNode
+	listed := make(map[string]bool)
+	var walk func(node *DependencyNode)
+	walk = func(node *DependencyNode) {
+		for _, child := range node.DependsOn {
+			if child.Done() || child.Seen {
+				continue
+			}
+			if !child.Blocked && !listed[child.ID] {
+				listed[child.ID] = true
+				frontier = append(frontier, child)
+			}
+			walk(child)
+		}
+	}
+	walk(n)
+	return frontier
+}
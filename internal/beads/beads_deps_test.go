@@ -0,0 +1,93 @@
+package beads
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnmetDependencies(t *testing.T) {
+	issue := &Issue{Dependencies: []IssueDep{
+		{ID: "gt-schema", Status: "closed", DependencyType: DepTypeBlocks},
+		{ID: "gt-auth", Status: "in_progress", DependencyType: DepTypeBlocks},
+		{ID: "gt-epic", Status: "open", DependencyType: "parent-child"},
+		{ID: "gt-wisp-x1", Status: "open", DependencyType: DepTypeBlocks},
+		{ID: "gt-gone", Status: "tombstone", DependencyType: DepTypeBlocks},
+		{ID: "gt-gate", Status: "open", DependencyType: "waits-for"},
+		{ID: "gt-docs", Status: "open", DependencyType: "related"},
+		{ID: "gt-untyped", Status: "open"},
+	}}
+	unmet := UnmetDependencies(issue)
+	if len(unmet) != 2 || unmet[0].ID != "gt-auth" || unmet[1].ID != "gt-gate" {
+		t.Fatalf("UnmetDependencies = %+v, want gt-auth and gt-gate", unmet)
+	}
+	err := error(&BlockedError{ID: "gt-api", Blockers: unmet})
+	var blocked *BlockedError
+	if !errors.As(err, &blocked) || !strings.Contains(err.Error(), "gt-auth (in_progress)") {
+		t.Errorf("BlockedError = %v", err)
+	}
+}
+
+func TestDependencyGraph(t *testing.T) {
+	dep := func(id, status string) IssueDep {
+		return IssueDep{ID: id, Status: status, DependencyType: DepTypeBlocks}
+	}
+	store := map[string]*Issue{
+		// api → schema → design (closed); api → auth → schema.
+		"gt-api":    {ID: "gt-api", Status: "open", Dependencies: []IssueDep{dep("gt-schema", "open"), dep("gt-auth", "open")}},
+		"gt-schema": {ID: "gt-schema", Status: "open", Dependencies: []IssueDep{dep("gt-design", "closed")}},
+		"gt-design": {ID: "gt-design", Status: "closed"},
+		"gt-auth":   {ID: "gt-auth", Status: "open", Dependencies: []IssueDep{dep("gt-schema", "open")}},
+	}
+	show := func(id string) (*Issue, error) {
+		if issue, ok := store[id]; ok {
+			return issue, nil
+		}
+		return nil, ErrNotFound
+	}
+
+	graph, err := dependencyGraph(show, "gt-api", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !graph.Blocked {
+		t.Error("gt-api should be blocked")
+	}
+	schema, auth := graph.DependsOn[0], graph.DependsOn[1]
+	if schema.Blocked || !auth.Blocked {
+		t.Errorf("schema blocked = %v, auth blocked = %v; want false, true", schema.Blocked, auth.Blocked)
+	}
+	if len(auth.DependsOn) != 1 || !auth.DependsOn[0].Seen {
+		t.Errorf("schema under auth should be marked seen: %+v", auth.DependsOn)
+	}
+
+	var frontier []string
+	for _, node := range graph.Frontier() {
+		frontier = append(frontier, node.ID)
+	}
+	if !reflect.DeepEqual(frontier, []string{"gt-schema"}) {
+		t.Errorf("Frontier = %v, want [gt-schema]", frontier)
+	}
+	if path := graph.PathTo("gt-design"); !reflect.DeepEqual(path, []string{"gt-api", "gt-schema", "gt-design"}) {
+		t.Errorf("PathTo = %v", path)
+	}
+
+	// A cycle stops where it comes back round.
+	store["gt-design"].Dependencies = []IssueDep{dep("gt-api", "open")}
+	graph, err = dependencyGraph(show, "gt-api", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cycle := graph.DependsOn[0].DependsOn[0].DependsOn[0]; cycle.ID != "gt-api" || !cycle.Seen {
+		t.Errorf("the cycle back to gt-api should stop: %+v", cycle)
+	}
+
+	shallow, err := dependencyGraph(show, "gt-api", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !shallow.DependsOn[0].Truncated || len(shallow.DependsOn[0].DependsOn) != 0 {
+		t.Errorf("depth 1 should stop below the direct dependencies: %+v", shallow.DependsOn[0])
+	}
+}
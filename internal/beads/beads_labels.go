@@ -0,0 +1,198 @@
+// Package beads provides user labels on beads and filters over them.
+package beads
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SystemLabelPrefix starts the labels Gas Town manages itself (gt:agent,
+// gt:hotfix, ...). Every other label is a user label: free-form tags such
+// as area:refinery, flaky or needs-human, which bd stores in the bead's
+// labels field alongside the system ones.
+const SystemLabelPrefix = "gt:"
+
+// IsSystemLabel reports whether label is one Gas Town manages.
+func IsSystemLabel(label string) bool {
+	return strings.HasPrefix(label, SystemLabelPrefix)
+}
+
+// UserLabels returns the user labels among labels, sorted, each once.
+func UserLabels(labels []string) []string {
+	var user []string
+	seen := make(map[string]bool)
+	for _, label := range labels {
+		if label == "" || IsSystemLabel(label) || seen[label] {
+			continue
+		}
+		seen[label] = true
+		user = append(user, label)
+	}
+	sort.Strings(user)
+	return user
+}
+
+// ValidateLabel checks label can be set as a user label: bd's labels
+// flag is comma-separated, and system labels are Gas Town's to set.
+func ValidateLabel(label string) error {
+	switch {
+	case label == "":
+		return fmt.Errorf("empty label")
+	case strings.ContainsAny(label, ", \t\n"):
+		return fmt.Errorf("label %q can't contain commas or spaces", label)
+	case strings.HasPrefix(label, "!"):
+		return fmt.Errorf("label %q can't start with !", label)
+	case IsSystemLabel(label):
+		return fmt.Errorf("label %q is reserved: %s labels are managed by Gas Town", label, SystemLabelPrefix)
+	}
+	return nil
+}
+
+// MatchLabel reports whether label matches pattern: equal to it or, for a
+// pattern ending in *, starting with the rest (area:* matches every area).
+func MatchLabel(pattern, label string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(label, prefix)
+	}
+	return pattern == label
+}
+
+// hasLabelMatching reports whether any of labels matches pattern.
+func hasLabelMatching(labels []string, pattern string) bool {
+	for _, label := range labels {
+		if MatchLabel(pattern, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// LabelFilter selects beads by their labels: a bead must have a label
+// matching every Require pattern and none matching an Exclude one.
+type LabelFilter struct {
+	Require []string
+	Exclude []string
+}
+
+// ParseLabelFilter parses --label flags: each a comma-separated list of
+// patterns (see MatchLabel), where !pattern excludes.
+func ParseLabelFilter(flags []string) (LabelFilter, error) {
+	var filter LabelFilter
+	for _, flag := range flags {
+		for _, pattern := range strings.Split(flag, ",") {
+			pattern = strings.TrimSpace(pattern)
+			exclude := strings.HasPrefix(pattern, "!")
+			pattern = strings.TrimPrefix(pattern, "!")
+			if pattern == "" || pattern == "*" {
+				return LabelFilter{}, fmt.Errorf("invalid label filter %q", flag)
+			}
+			if exclude {
+				filter.Exclude = append(filter.Exclude, pattern)
+			} else {
+				filter.Require = append(filter.Require, pattern)
+			}
+		}
+	}
+	return filter, nil
+}
+
+// Empty reports whether the filter selects every bead.
+func (f LabelFilter) Empty() bool {
+	return len(f.Require) == 0 && len(f.Exclude) == 0
+}
+
+// Matches reports whether a bead with labels passes the filter.
+func (f LabelFilter) Matches(labels []string) bool {
+	for _, pattern := range f.Require {
+		if !hasLabelMatching(labels, pattern) {
+			return false
+		}
+	}
+	for _, pattern := range f.Exclude {
+		if hasLabelMatching(labels, pattern) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterByLabels returns the issues that pass the filter, in order.
+func FilterByLabels(issues []*Issue, filter LabelFilter) []*Issue {
+	if filter.Empty() {
+		return issues
+	}
+	var kept []*Issue
+	for _, issue := range issues {
+		if filter.Matches(issue.Labels) {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
+
+// LabelRoute is where a bead's labels send it, given the label patterns
+// each rig is configured to take work for (see RigSettings.WorkLabels).
+type LabelRoute struct {
+	// Routed are the bead's user labels some rig claims; the others don't
+	// constrain where it goes.
+	Routed []string
+	// Rigs are the rigs that claim every routed label, sorted. Empty with
+	// Routed set means no single rig takes all of the bead's work.
+	Rigs []string
+}
+
+// RouteLabels works out which rigs a bead with labels belongs in, given
+// claims: each rig's work label patterns.
+func RouteLabels(labels []string, claims map[string][]string) LabelRoute {
+	var route LabelRoute
+	for _, label := range UserLabels(labels) {
+		for _, patterns := range claims {
+			if hasPatternMatching(patterns, label) {
+				route.Routed = append(route.Routed, label)
+				break
+			}
+		}
+	}
+	if len(route.Routed) == 0 {
+		return route
+	}
+	for rigName, patterns := range claims {
+		takesAll := true
+		for _, label := range route.Routed {
+			if !hasPatternMatching(patterns, label) {
+				takesAll = false
+				break
+			}
+		}
+		if takesAll {
+			route.Rigs = append(route.Rigs, rigName)
+		}
+	}
+	sort.Strings(route.Rigs)
+	return route
+}
+
+// Allows reports whether the route lets the bead go to rigName: it has no
+// routed labels, or the rig claims them all.
+func (r LabelRoute) Allows(rigName string) bool {
+	if len(r.Routed) == 0 {
+		return true
+	}
+	for _, name := range r.Rigs {
+		if name == rigName {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPatternMatching reports whether label matches any of patterns.
+func hasPatternMatching(patterns []string, label string) bool {
+	for _, pattern := range patterns {
+		if MatchLabel(pattern, label) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package beads
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUserLabels(t *testing.T) {
+	got := UserLabels([]string{"gt:task", "flaky", "area:cli", "flaky", "", "gt:hotfix"})
+	if want := []string{"area:cli", "flaky"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("UserLabels() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateLabel(t *testing.T) {
+	for _, label := range []string{"flaky", "area:refinery", "needs-human"} {
+		if err := ValidateLabel(label); err != nil {
+			t.Errorf("ValidateLabel(%q) = %v, want nil", label, err)
+		}
+	}
+	for _, label := range []string{"", "a,b", "needs human", "!flaky", "gt:hotfix"} {
+		if err := ValidateLabel(label); err == nil {
+			t.Errorf("ValidateLabel(%q) should fail", label)
+		}
+	}
+}
+
+func TestLabelFilter(t *testing.T) {
+	filter, err := ParseLabelFilter([]string{"area:*,!needs-human", "!flaky"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (LabelFilter{Require: []string{"area:*"}, Exclude: []string{"needs-human", "flaky"}}); !reflect.DeepEqual(filter, want) {
+		t.Fatalf("ParseLabelFilter() = %+v, want %+v", filter, want)
+	}
+
+	tests := []struct {
+		labels []string
+		want   bool
+	}{
+		{[]string{"area:cli"}, true},
+		{[]string{"area:cli", "gt:task"}, true},
+		{[]string{"area:cli", "flaky"}, false},
+		{[]string{"needs-human", "area:cli"}, false},
+		{[]string{"bugfix"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := filter.Matches(tt.labels); got != tt.want {
+			t.Errorf("Matches(%v) = %v, want %v", tt.labels, got, tt.want)
+		}
+	}
+
+	issues := []*Issue{{ID: "a", Labels: []string{"area:cli"}}, {ID: "b"}}
+	if kept := FilterByLabels(issues, filter); len(kept) != 1 || kept[0].ID != "a" {
+		t.Errorf("FilterByLabels() kept %v, want only a", kept)
+	}
+	if kept := FilterByLabels(issues, LabelFilter{}); len(kept) != 2 {
+		t.Errorf("an empty filter should keep every issue, kept %d", len(kept))
+	}
+
+	for _, bad := range []string{"", "!", "a,,b", "*"} {
+		if _, err := ParseLabelFilter([]string{bad}); err == nil {
+			t.Errorf("ParseLabelFilter(%q) should fail", bad)
+		}
+	}
+}
+
+func TestRouteLabels(t *testing.T) {
+	claims := map[string][]string{
+		"gastown":  {"area:refinery", "area:cli"},
+		"beads":    {"area:*"},
+		"frontend": nil,
+	}
+
+	tests := []struct {
+		name   string
+		labels []string
+		routed []string
+		rigs   []string
+	}{
+		{"unclaimed labels go anywhere", []string{"flaky", "gt:task"}, nil, nil},
+		{"claimed by two rigs", []string{"area:cli", "flaky"}, []string{"area:cli"}, []string{"beads", "gastown"}},
+		{"claimed by one rig", []string{"area:storage"}, []string{"area:storage"}, []string{"beads"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := RouteLabels(tt.labels, claims)
+			if !reflect.DeepEqual(route.Routed, tt.routed) || !reflect.DeepEqual(route.Rigs, tt.rigs) {
+				t.Errorf("RouteLabels() = %+v, want routed %v to %v", route, tt.routed, tt.rigs)
+			}
+		})
+	}
+
+	route := RouteLabels([]string{"area:refinery"}, claims)
+	if !route.Allows("gastown") || route.Allows("frontend") {
+		t.Errorf("route %+v should allow gastown but not frontend", route)
+	}
+	if !RouteLabels([]string{"flaky"}, claims).Allows("frontend") {
+		t.Error("a bead without routed labels should be allowed anywhere")
+	}
+}
@@ -0,0 +1,106 @@
+// Package beads provides bead lifecycle events, worked out from snapshots.
+package beads
+
+// Bead lifecycle events, as sent to bead webhooks.
+const (
+	// LifecycleCreated is a work bead appearing.
+	LifecycleCreated = "bead.created"
+
+	// LifecycleStatusChanged is a work bead moving between open statuses
+	// (open, in_progress, hooked, blocked...) or being reopened.
+	LifecycleStatusChanged = "bead.status_changed"
+
+	// LifecycleClosed is a work bead being closed.
+	LifecycleClosed = "bead.closed"
+
+	// LifecycleCircuitTripped is a polecat's circuit breaker opening after
+	// repeated failures (see AgentFields.CircuitState).
+	LifecycleCircuitTripped = "agent.circuit_tripped"
+)
+
+// LifecycleEvents lists the bead lifecycle events.
+var LifecycleEvents = []string{
+	LifecycleCreated,
+	LifecycleStatusChanged,
+	LifecycleClosed,
+	LifecycleCircuitTripped,
+}
+
+// LifecycleState is what lifecycle events are worked out from: a work
+// bead's status, or an agent bead's circuit.
+type LifecycleState struct {
+	Status  string `json:"status,omitempty"`
+	Circuit string `json:"circuit,omitempty"`
+	Trips   int    `json:"trips,omitempty"`
+}
+
+// LifecycleSnapshot is the lifecycle state of a database's beads, by ID.
+type LifecycleSnapshot map[string]LifecycleState
+
+// LifecycleEvent is one change between two snapshots of a database.
+type LifecycleEvent struct {
+	Event      string
+	Issue      *Issue       // The bead as it is now
+	PrevStatus string       // Status before, for status changes and closes
+	Agent      *AgentFields // The agent's fields, for circuit trips
+}
+
+// TakeLifecycleSnapshot returns the lifecycle state of issues. Wisps are
+// left out: they come and go with every molecule step.
+func TakeLifecycleSnapshot(issues []*Issue) LifecycleSnapshot {
+	snap := make(LifecycleSnapshot, len(issues))
+	for _, issue := range issues {
+		if issue.Ephemeral {
+			continue
+		}
+		if IsAgentBead(issue) {
+			fields := ParseAgentFields(issue.Description)
+			snap[issue.ID] = LifecycleState{Circuit: fields.CircuitState, Trips: fields.TripCount}
+			continue
+		}
+		snap[issue.ID] = LifecycleState{Status: issue.Status}
+	}
+	return snap
+}
+
+// DiffLifecycle returns the lifecycle events that took a database from
+// prev to issues, in the order of issues. A nil prev is a database not
+// seen before, whose beads are taken as they are without events.
+//
+// A bead both created and closed between two snapshots is reported as
+// created, with its closed status. A circuit that opened and closed again
+// between two snapshots is missed unless its trip count still shows it;
+// one that re-opened is caught by the count going up.
+func DiffLifecycle(prev LifecycleSnapshot, issues []*Issue) []LifecycleEvent {
+	if prev == nil {
+		return nil
+	}
+	cur := TakeLifecycleSnapshot(issues)
+	var events []LifecycleEvent
+	for _, issue := range issues {
+		now, tracked := cur[issue.ID]
+		if !tracked {
+			continue
+		}
+		before, seen := prev[issue.ID]
+
+		if IsAgentBead(issue) {
+			tripped := now.Circuit == CircuitOpen && (before.Circuit != CircuitOpen || now.Trips > before.Trips)
+			if tripped {
+				events = append(events, LifecycleEvent{Event: LifecycleCircuitTripped, Issue: issue, Agent: ParseAgentFields(issue.Description)})
+			}
+			continue
+		}
+
+		switch {
+		case !seen:
+			events = append(events, LifecycleEvent{Event: LifecycleCreated, Issue: issue})
+		case now.Status == before.Status:
+		case now.Status == "closed":
+			events = append(events, LifecycleEvent{Event: LifecycleClosed, Issue: issue, PrevStatus: before.Status})
+		default:
+			events = append(events, LifecycleEvent{Event: LifecycleStatusChanged, Issue: issue, PrevStatus: before.Status})
+		}
+	}
+	return events
+}
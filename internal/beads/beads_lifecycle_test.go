@@ -0,0 +1,57 @@
+package beads
+
+import "testing"
+
+func TestDiffLifecycle(t *testing.T) {
+	agent := func(state string, trips int) *Issue {
+		return &Issue{ID: "gt-gastown-polecat-nux", Labels: []string{"gt:agent"},
+			Description: FormatAgentDescription("Polecat nux", &AgentFields{RoleType: "polecat", Rig: "gastown", CircuitState: state, TripCount: trips})}
+	}
+	before := []*Issue{
+		{ID: "gt-a", Status: "open"},
+		{ID: "gt-b", Status: "open"},
+		{ID: "gt-c", Status: "in_progress"},
+		{ID: "gt-d", Status: "open"},
+		agent(CircuitClosed, 0),
+	}
+	prev := TakeLifecycleSnapshot(before)
+
+	if events := DiffLifecycle(nil, before); len(events) != 0 {
+		t.Errorf("a database seen for the first time should give no events, got %d", len(events))
+	}
+	if events := DiffLifecycle(prev, before); len(events) != 0 {
+		t.Errorf("an unchanged database should give no events, got %d", len(events))
+	}
+
+	after := []*Issue{
+		{ID: "gt-a", Status: "open"},
+		{ID: "gt-b", Status: "in_progress"},
+		{ID: "gt-c", Status: "closed"},
+		{ID: "gt-e", Status: "open"},
+		{ID: "gt-wisp-x", Status: "open", Ephemeral: true},
+		agent(CircuitOpen, 1),
+	}
+	events := DiffLifecycle(prev, after)
+	want := []struct{ event, id, prevStatus string }{
+		{LifecycleStatusChanged, "gt-b", "open"},
+		{LifecycleClosed, "gt-c", "in_progress"},
+		{LifecycleCreated, "gt-e", ""},
+		{LifecycleCircuitTripped, "gt-gastown-polecat-nux", ""},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		if events[i].Event != w.event || events[i].Issue.ID != w.id || events[i].PrevStatus != w.prevStatus {
+			t.Errorf("event %d = %s %s (was %q), want %s %s (was %q)", i, events[i].Event, events[i].Issue.ID, events[i].PrevStatus, w.event, w.id, w.prevStatus)
+		}
+	}
+	if events[3].Agent == nil || events[3].Agent.TripCount != 1 {
+		t.Errorf("a circuit trip should carry the agent's fields, got %+v", events[3].Agent)
+	}
+
+	// A circuit that re-opened between snapshots trips again.
+	if events := DiffLifecycle(TakeLifecycleSnapshot(after), []*Issue{agent(CircuitOpen, 2)}); len(events) != 1 || events[0].Event != LifecycleCircuitTripped {
+		t.Errorf("a re-opened circuit should trip again, got %+v", events)
+	}
+}
@@ -0,0 +1,371 @@
+// Package beads provides export and import of beads in a portable format.
+package beads
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PortableFormat and PortableVersion identify a portable beads snapshot.
+// The format is plain JSON so a snapshot can go in a bug report, a backup,
+// or another town, whatever its beads backend.
+const (
+	PortableFormat  = "gastown-beads"
+	PortableVersion = 1
+)
+
+// Collision policies for ImportPortable: what to do with a bead whose ID,
+// after prefix remapping, already exists.
+const (
+	ImportSkip      = "skip"      // Keep the existing bead; references go to it
+	ImportRename    = "rename"    // Import under a new ID bd assigns
+	ImportOverwrite = "overwrite" // Replace the existing bead's fields
+)
+
+// PortableSnapshot is a database's beads as 'gt beads export' writes them.
+type PortableSnapshot struct {
+	Format     string         `json:"format"`
+	Version    int            `json:"version"`
+	ExportedAt string         `json:"exported_at"`
+	Source     string         `json:"source,omitempty"` // The rig (or "town") exported
+	Prefix     string         `json:"prefix,omitempty"` // The most common ID prefix, e.g. "gt-"
+	Beads      []PortableBead `json:"beads"`
+}
+
+// PortableBead is one bead of a snapshot.
+type PortableBead struct {
+	ID          string        `json:"id"`
+	Title       string        `json:"title"`
+	Description string        `json:"description,omitempty"`
+	Status      string        `json:"status"`
+	Priority    int           `json:"priority"`
+	Type        string        `json:"type,omitempty"`
+	Assignee    string        `json:"assignee,omitempty"`
+	Labels      []string      `json:"labels,omitempty"`
+	Parent      string        `json:"parent,omitempty"`
+	DependsOn   []PortableDep `json:"depends_on,omitempty"`
+	CreatedAt   string        `json:"created_at,omitempty"`
+	CreatedBy   string        `json:"created_by,omitempty"`
+	UpdatedAt   string        `json:"updated_at,omitempty"`
+	ClosedAt    string        `json:"closed_at,omitempty"`
+}
+
+// PortableDep is a dependency of a snapshot bead, on a bead in the
+// snapshot or outside it.
+type PortableDep struct {
+	ID   string `json:"id"`
+	Type string `json:"type"` // bd dependency type: blocks, tracks, related...
+}
+
+// exportChunk is how many beads one bd show reads during an export.
+const exportChunk = 100
+
+// ExportPortable returns a snapshot of the beads in this wrapper's
+// database with status ("all" for every status), sorted by ID. Wisps are
+// left out: they are molecule state, not work.
+func (b *Beads) ExportPortable(source, status string) (*PortableSnapshot, error) {
+	listed, err := b.List(ListOptions{Status: status, Priority: -1})
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, issue := range listed {
+		if !issue.Ephemeral && !strings.Contains(issue.ID, "-wisp-") {
+			ids = append(ids, issue.ID)
+		}
+	}
+	sort.Strings(ids)
+
+	snap := &PortableSnapshot{
+		Format:     PortableFormat,
+		Version:    PortableVersion,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		Source:     source,
+		Beads:      []PortableBead{},
+	}
+	// Dependencies are only in bd show's output.
+	for start := 0; start < len(ids); start += exportChunk {
+		chunk := ids[start:min(start+exportChunk, len(ids))]
+		shown, err := b.ShowMultiple(chunk)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range chunk {
+			if issue := shown[id]; issue != nil {
+				snap.Beads = append(snap.Beads, ToPortable(issue))
+			}
+		}
+	}
+	snap.Prefix = commonPrefix(snap.Beads)
+	return snap, nil
+}
+
+// ToPortable converts a shown bead to its snapshot form.
+func ToPortable(issue *Issue) PortableBead {
+	bead := PortableBead{
+		ID:          issue.ID,
+		Title:       issue.Title,
+		Description: issue.Description,
+		Status:      issue.Status,
+		Priority:    issue.Priority,
+		Type:        issue.Type,
+		Assignee:    issue.Assignee,
+		Labels:      issue.Labels,
+		Parent:      issue.Parent,
+		CreatedAt:   issue.CreatedAt,
+		CreatedBy:   issue.CreatedBy,
+		UpdatedAt:   issue.UpdatedAt,
+		ClosedAt:    issue.ClosedAt,
+	}
+	for _, dep := range issue.Dependencies {
+		if dep.DependencyType == "parent-child" {
+			if bead.Parent == "" {
+				bead.Parent = dep.ID
+			}
+			continue
+		}
+		bead.DependsOn = append(bead.DependsOn, PortableDep{ID: dep.ID, Type: dep.DependencyType})
+	}
+	return bead
+}
+
+// commonPrefix returns the ID prefix most of beads have.
+func commonPrefix(beads []PortableBead) string {
+	counts := make(map[string]int)
+	best := ""
+	for _, bead := range beads {
+		prefix := ExtractPrefix(bead.ID)
+		counts[prefix]++
+		if counts[prefix] > counts[best] || (counts[prefix] == counts[best] && prefix < best) {
+			best = prefix
+		}
+	}
+	return best
+}
+
+// ReadPortable reads a snapshot, refusing other formats and newer versions.
+func ReadPortable(r io.Reader) (*PortableSnapshot, error) {
+	var snap PortableSnapshot
+	if err := json.NewDecoder(r).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("parsing beads snapshot: %w", err)
+	}
+	if snap.Format != PortableFormat {
+		return nil, fmt.Errorf("not a beads snapshot (format %q, want %q)", snap.Format, PortableFormat)
+	}
+	if snap.Version > PortableVersion {
+		return nil, fmt.Errorf("beads snapshot version %d is newer than this gt supports (%d)", snap.Version, PortableVersion)
+	}
+	return &snap, nil
+}
+
+// ImportOptions controls ImportPortable.
+type ImportOptions struct {
+	// PrefixMap renames ID prefixes, old to new, both with their trailing
+	// hyphen (e.g. "gt-" to "xy-"). It applies to every ID in the
+	// snapshot, including dependencies on beads outside it.
+	PrefixMap map[string]string
+
+	// OnCollision is ImportSkip (the default), ImportRename or
+	// ImportOverwrite.
+	OnCollision string
+
+	// DryRun plans the import without writing.
+	DryRun bool
+}
+
+// ImportAction is what ImportPortable does with one snapshot bead.
+type ImportAction struct {
+	OldID  string `json:"old_id"`
+	NewID  string `json:"new_id,omitempty"` // Empty for a rename that hasn't run yet
+	Action string `json:"action"`           // create, or the collision policy applied
+}
+
+// ImportResult is what ImportPortable did.
+type ImportResult struct {
+	Actions  []ImportAction `json:"actions"`
+	Warnings []string       `json:"warnings,omitempty"`
+}
+
+// RemapID applies a prefix map to a bead ID.
+func RemapID(id string, prefixMap map[string]string) string {
+	prefix := ExtractPrefix(id)
+	if to, ok := prefixMap[prefix]; ok && prefix != "" {
+		return to + id[len(prefix):]
+	}
+	return id
+}
+
+// PlanImport decides what ImportPortable does with each bead of snap,
+// given the IDs that already exist in the target database.
+func PlanImport(snap *PortableSnapshot, existing map[string]bool, opts ImportOptions) ([]ImportAction, error) {
+	policy := opts.OnCollision
+	if policy == "" {
+		policy = ImportSkip
+	}
+	if policy != ImportSkip && policy != ImportRename && policy != ImportOverwrite {
+		return nil, fmt.Errorf("unknown collision policy %q (want skip, rename or overwrite)", policy)
+	}
+
+	actions := make([]ImportAction, 0, len(snap.Beads))
+	seen := make(map[string]bool)
+	for _, bead := range snap.Beads {
+		if bead.ID == "" || strings.TrimSpace(bead.Title) == "" {
+			return nil, fmt.Errorf("snapshot bead %q has no ID or title", bead.ID)
+		}
+		if IsFlagLikeTitle(bead.Title) {
+			return nil, fmt.Errorf("snapshot bead %s: %w (got %q)", bead.ID, ErrFlagTitle, bead.Title)
+		}
+		newID := RemapID(bead.ID, opts.PrefixMap)
+		if seen[newID] {
+			return nil, fmt.Errorf("snapshot has %s twice (after prefix remapping)", newID)
+		}
+		seen[newID] = true
+
+		action := ImportAction{OldID: bead.ID, NewID: newID, Action: "create"}
+		if existing[newID] {
+			action.Action = policy
+			if policy == ImportRename {
+				action.NewID = ""
+			}
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// ImportPortable imports a snapshot into this wrapper's database. New
+// beads keep their (remapped) IDs; dependencies and parents between them
+// are rewritten to match. Beads are created, then closed or given their
+// status, then linked: a dependency that can't be added (its target isn't
+// in the database, say) is a warning, not a failure. An overwritten bead
+// gets the snapshot's fields but keeps its dependencies. With DryRun only
+// the plan is returned.
+func (b *Beads) ImportPortable(snap *PortableSnapshot, opts ImportOptions) (*ImportResult, error) {
+	existingList, err := b.List(ListOptions{Status: "all", Priority: -1})
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(existingList))
+	for _, issue := range existingList {
+		existing[issue.ID] = true
+	}
+	actions, err := PlanImport(snap, existing, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &ImportResult{Actions: actions}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	// idMap maps every snapshot ID to the ID its bead has after the import.
+	idMap := make(map[string]string, len(actions))
+	var errs []error
+	for i, bead := range snap.Beads {
+		action := &result.Actions[i]
+		switch action.Action {
+		case ImportSkip:
+			idMap[bead.ID] = action.NewID
+		case ImportOverwrite:
+			if err := b.Update(action.NewID, portableUpdate(bead)); err != nil {
+				errs = append(errs, fmt.Errorf("overwriting %s: %w", action.NewID, err))
+				continue
+			}
+			idMap[bead.ID] = action.NewID
+		default:
+			newID, err := b.createPortable(bead, action.NewID)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("importing %s: %w", bead.ID, err))
+				continue
+			}
+			action.NewID = newID
+			idMap[bead.ID] = newID
+			if err := b.setPortableStatus(newID, bead.Status); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s: setting status %s: %v", newID, bead.Status, err))
+			}
+		}
+	}
+
+	for i, bead := range snap.Beads {
+		action := result.Actions[i]
+		if action.Action == ImportSkip || action.Action == ImportOverwrite || idMap[bead.ID] == "" {
+			continue
+		}
+		link := func(target, depType string) {
+			to, ok := idMap[target]
+			if !ok {
+				to = RemapID(target, opts.PrefixMap)
+			}
+			// bd would record an untyped relation as blocks, which it wasn't.
+			if depType == "" {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s → %s: relation has no type, not linked", idMap[bead.ID], to))
+				return
+			}
+			if _, err := b.run("dep", "add", idMap[bead.ID], to, "--type="+depType); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s → %s (%s): %v", idMap[bead.ID], to, depType, err))
+			}
+		}
+		if bead.Parent != "" {
+			link(bead.Parent, "parent-child")
+		}
+		for _, dep := range bead.DependsOn {
+			link(dep.ID, dep.Type)
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// createPortable creates a snapshot bead with ID id, or one bd assigns if
+// id is empty, and returns its ID.
+func (b *Beads) createPortable(bead PortableBead, id string) (string, error) {
+	opts := CreateOptions{
+		Title:       bead.Title,
+		Priority:    bead.Priority,
+		Description: bead.Description,
+		Assignee:    bead.Assignee,
+		Actor:       bead.CreatedBy,
+		IssueType:   bead.Type,
+		Labels:      bead.Labels,
+	}
+	var issue *Issue
+	var err error
+	if id != "" {
+		issue, err = b.CreateWithID(id, opts)
+	} else {
+		issue, err = b.Create(opts)
+	}
+	if err != nil {
+		return "", err
+	}
+	return issue.ID, nil
+}
+
+// setPortableStatus gives a newly created bead its snapshot status.
+func (b *Beads) setPortableStatus(id, status string) error {
+	switch status {
+	case "", "open":
+		return nil
+	case "closed", "tombstone":
+		return b.CloseWithReason("Imported closed", id)
+	default:
+		return b.Update(id, UpdateOptions{Status: &status})
+	}
+}
+
+// portableUpdate returns the update that overwrites a bead with a snapshot
+// bead's fields.
+func portableUpdate(bead PortableBead) UpdateOptions {
+	return UpdateOptions{
+		Title:       &bead.Title,
+		Status:      &bead.Status,
+		Priority:    &bead.Priority,
+		Description: &bead.Description,
+		Assignee:    &bead.Assignee,
+		SetLabels:   bead.Labels,
+	}
+}
@@ -0,0 +1,157 @@
+package beads
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestToPortable(t *testing.T) {
+	issue := &Issue{ID: "gt-api", Title: "API", Status: "open", Priority: 1, Labels: []string{"gt:task"},
+		Dependencies: []IssueDep{
+			{ID: "gt-epic", DependencyType: "parent-child"},
+			{ID: "gt-schema", DependencyType: "blocks"},
+			{ID: "hq-cv1", DependencyType: "tracks"},
+		}}
+	bead := ToPortable(issue)
+	if bead.Parent != "gt-epic" {
+		t.Errorf("Parent = %q, want gt-epic", bead.Parent)
+	}
+	want := []PortableDep{{ID: "gt-schema", Type: "blocks"}, {ID: "hq-cv1", Type: "tracks"}}
+	if !reflect.DeepEqual(bead.DependsOn, want) {
+		t.Errorf("DependsOn = %+v, want %+v", bead.DependsOn, want)
+	}
+	if got := commonPrefix([]PortableBead{{ID: "hq-1"}, bead, {ID: "gt-2"}}); got != "gt-" {
+		t.Errorf("commonPrefix = %q, want gt-", got)
+	}
+}
+
+func TestReadPortable(t *testing.T) {
+	snap := &PortableSnapshot{Format: PortableFormat, Version: PortableVersion, Beads: []PortableBead{{ID: "gt-a", Title: "A"}}}
+	data, _ := json.Marshal(snap)
+	read, err := ReadPortable(bytes.NewReader(data))
+	if err != nil || !reflect.DeepEqual(read, snap) {
+		t.Errorf("ReadPortable = %+v, %v", read, err)
+	}
+
+	if _, err := ReadPortable(strings.NewReader(`[{"id":"gt-a"}]`)); err == nil {
+		t.Error("bd's own JSON should not be accepted as a snapshot")
+	}
+	if _, err := ReadPortable(strings.NewReader(`{"format":"gastown-beads","version":99}`)); err == nil {
+		t.Error("a newer snapshot version should be refused")
+	}
+}
+
+func TestPlanImport(t *testing.T) {
+	snap := &PortableSnapshot{Beads: []PortableBead{
+		{ID: "gt-a", Title: "A"},
+		{ID: "gt-b", Title: "B"},
+		{ID: "hq-c", Title: "C"},
+	}}
+	existing := map[string]bool{"xy-b": true, "hq-c": true}
+	prefixMap := map[string]string{"gt-": "xy-"}
+
+	tests := []struct {
+		policy string
+		want   []ImportAction
+	}{
+		{"", []ImportAction{
+			{OldID: "gt-a", NewID: "xy-a", Action: "create"},
+			{OldID: "gt-b", NewID: "xy-b", Action: ImportSkip},
+			{OldID: "hq-c", NewID: "hq-c", Action: ImportSkip},
+		}},
+		{ImportRename, []ImportAction{
+			{OldID: "gt-a", NewID: "xy-a", Action: "create"},
+			{OldID: "gt-b", Action: ImportRename},
+			{OldID: "hq-c", Action: ImportRename},
+		}},
+		{ImportOverwrite, []ImportAction{
+			{OldID: "gt-a", NewID: "xy-a", Action: "create"},
+			{OldID: "gt-b", NewID: "xy-b", Action: ImportOverwrite},
+			{OldID: "hq-c", NewID: "hq-c", Action: ImportOverwrite},
+		}},
+	}
+	for _, tt := range tests {
+		got, err := PlanImport(snap, existing, ImportOptions{PrefixMap: prefixMap, OnCollision: tt.policy})
+		if err != nil {
+			t.Fatalf("%q: %v", tt.policy, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: PlanImport = %+v, want %+v", tt.policy, got, tt.want)
+		}
+	}
+
+	if _, err := PlanImport(snap, existing, ImportOptions{OnCollision: "merge"}); err == nil {
+		t.Error("an unknown collision policy should be refused")
+	}
+	// Remapping gt- onto hq- makes hq-c appear twice.
+	if _, err := PlanImport(&PortableSnapshot{Beads: []PortableBead{{ID: "gt-c", Title: "C"}, {ID: "hq-c", Title: "C"}}},
+		nil, ImportOptions{PrefixMap: map[string]string{"gt-": "hq-"}}); err == nil {
+		t.Error("IDs colliding within the snapshot should be refused")
+	}
+}
+
+func TestImportPortable_KeepsIssueType(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell bd stub")
+	}
+	dir := t.TempDir()
+	binDir := filepath.Join(dir, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(dir, "bd.log")
+	// The stub has no beads, and logs each create's arguments on a line.
+	script := `#!/bin/sh
+case " $* " in
+  *" list "*) echo '[]' ;;
+  *" create "*) echo "$*" >> "` + logPath + `"; echo '{"id":"gt-new"}' ;;
+esac
+exit 0
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	// Export: an epic, and a legacy agent bead whose gt:agent label came
+	// with its issue type.
+	snap := &PortableSnapshot{Format: PortableFormat, Version: PortableVersion}
+	for _, issue := range []*Issue{
+		{ID: "gt-epic", Title: "Epic", Status: "open", Type: "epic"},
+		{ID: "gt-gastown-witness", Title: "Witness", Status: "open", Type: "agent", Labels: []string{"gt:agent"}},
+	} {
+		snap.Beads = append(snap.Beads, ToPortable(issue))
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadPortable(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := New(dir).ImportPortable(read, ImportOptions{}); err != nil {
+		t.Fatalf("ImportPortable: %v", err)
+	}
+	log, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	creates := strings.Split(strings.TrimSpace(string(log)), "\n")
+	if len(creates) != 2 {
+		t.Fatalf("creates = %q, want 2", creates)
+	}
+	if !strings.Contains(creates[0], "--type=epic") || strings.Contains(creates[0], "gt:epic") {
+		t.Errorf("epic created with %q, want --type=epic and no gt:epic label", creates[0])
+	}
+	if !strings.Contains(creates[1], "--type=agent") || !strings.Contains(creates[1], "--labels=gt:agent") {
+		t.Errorf("agent bead created with %q, want --type=agent and its gt:agent label", creates[1])
+	}
+}
@@ -0,0 +1,242 @@
+// Package beads provides postmortem bead management.
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// postmortemTimelineHeader separates the structured fields from the
+// timeline pre-filled from the event log.
+const postmortemTimelineHeader = "## Timeline"
+
+// PostmortemFields holds structured fields for postmortem beads.
+// These are stored as "key: value" lines in the description, followed by
+// the incident timeline.
+type PostmortemFields struct {
+	IncidentStart string // ISO 8601 start of the incident window
+	IncidentEnd   string // ISO 8601 end of the incident window
+	Summary       string // What happened, one line
+	StartedBy     string // Agent address that opened the postmortem
+	StartedAt     string // ISO 8601 timestamp
+	RootCause     string // Filled in on finish
+	FinishedBy    string // Agent that finished (empty if open)
+	FinishedAt    string // When finished (empty if open)
+}
+
+// FormatPostmortemDescription creates a description string from postmortem
+// fields and timeline lines.
+func FormatPostmortemDescription(title string, fields *PostmortemFields, timeline []string) string {
+	if fields == nil {
+		fields = &PostmortemFields{}
+	}
+	orNull := func(s string) string {
+		if s == "" {
+			return "null"
+		}
+		return s
+	}
+
+	lines := []string{
+		title,
+		"",
+		"incident_start: " + orNull(fields.IncidentStart),
+		"incident_end: " + orNull(fields.IncidentEnd),
+		"summary: " + orNull(fields.Summary),
+		"started_by: " + orNull(fields.StartedBy),
+		"started_at: " + orNull(fields.StartedAt),
+		"root_cause: " + orNull(fields.RootCause),
+		"finished_by: " + orNull(fields.FinishedBy),
+		"finished_at: " + orNull(fields.FinishedAt),
+		"",
+		postmortemTimelineHeader,
+		"",
+	}
+	if len(timeline) == 0 {
+		lines = append(lines, "(no events in the incident window)")
+	}
+	lines = append(lines, timeline...)
+	return strings.Join(lines, "\n")
+}
+
+// ParsePostmortemFields extracts postmortem fields and the timeline from an
+// issue's description.
+func ParsePostmortemFields(description string) (*PostmortemFields, []string) {
+	fields := &PostmortemFields{}
+	head, tail, _ := strings.Cut(description, postmortemTimelineHeader)
+
+	for _, line := range strings.Split(head, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "null" {
+			value = ""
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "incident_start":
+			fields.IncidentStart = value
+		case "incident_end":
+			fields.IncidentEnd = value
+		case "summary":
+			fields.Summary = value
+		case "started_by":
+			fields.StartedBy = value
+		case "started_at":
+			fields.StartedAt = value
+		case "root_cause":
+			fields.RootCause = value
+		case "finished_by":
+			fields.FinishedBy = value
+		case "finished_at":
+			fields.FinishedAt = value
+		}
+	}
+
+	var timeline []string
+	for _, line := range strings.Split(tail, "\n") {
+		if line = strings.TrimSpace(line); line != "" && line != "(no events in the incident window)" {
+			timeline = append(timeline, line)
+		}
+	}
+	return fields, timeline
+}
+
+// CreatePostmortemBead creates a postmortem bead with a pre-filled timeline.
+// The created_by field is populated from BD_ACTOR env var for provenance tracking.
+func (b *Beads) CreatePostmortemBead(title string, fields *PostmortemFields, timeline []string) (*Issue, error) {
+	// Guard against flag-like titles (gt-e0kx5: --help garbage beads)
+	if IsFlagLikeTitle(title) {
+		return nil, fmt.Errorf("refusing to create postmortem bead: %w (got %q)", ErrFlagTitle, title)
+	}
+
+	args := []string{"create", "--json",
+		"--title=" + title,
+		"--description=" + FormatPostmortemDescription(title, fields, timeline),
+		"--type=task",
+		"--labels=gt:postmortem",
+	}
+	if actor := b.getActor(); actor != "" {
+		args = append(args, "--actor="+actor)
+	}
+
+	out, err := b.run(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var issue Issue
+	if err := json.Unmarshal(out, &issue); err != nil {
+		return nil, fmt.Errorf("parsing bd create output: %w", err)
+	}
+	return &issue, nil
+}
+
+// GetPostmortemBead retrieves a postmortem bead and its parsed fields.
+func (b *Beads) GetPostmortemBead(id string) (*Issue, *PostmortemFields, []string, error) {
+	issue, err := b.Show(id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !HasLabel(issue, "gt:postmortem") {
+		return nil, nil, nil, fmt.Errorf("issue %s is not a postmortem bead (missing gt:postmortem label)", id)
+	}
+	fields, timeline := ParsePostmortemFields(issue.Description)
+	return issue, fields, timeline, nil
+}
+
+// AddPostmortemAction creates an action item as a child of the postmortem,
+// so its completion can be tracked from the postmortem.
+func (b *Beads) AddPostmortemAction(postmortemID, title, assignee string) (*Issue, error) {
+	if _, _, _, err := b.GetPostmortemBead(postmortemID); err != nil {
+		return nil, err
+	}
+	issue, err := b.Create(CreateOptions{
+		Title:       title,
+		Type:        "task",
+		Priority:    2,
+		Description: fmt.Sprintf("Action item from postmortem %s.", postmortemID),
+		Parent:      postmortemID,
+		Actor:       b.getActor(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	labels := []string{"postmortem-action"}
+	opts := UpdateOptions{AddLabels: labels}
+	if assignee != "" {
+		opts.Assignee = &assignee
+		issue.Assignee = assignee
+	}
+	if err := b.Update(issue.ID, opts); err != nil {
+		return issue, fmt.Errorf("labeling action item %s: %w", issue.ID, err)
+	}
+	issue.Labels = append(issue.Labels, labels...)
+	return issue, nil
+}
+
+// ListPostmortemActions returns every action item of a postmortem, open or closed.
+func (b *Beads) ListPostmortemActions(postmortemID string) ([]*Issue, error) {
+	return b.List(ListOptions{Parent: postmortemID, Status: "all", Priority: -1})
+}
+
+// ListPostmortems returns postmortem beads; closed ones only when all is set.
+func (b *Beads) ListPostmortems(all bool) ([]*Issue, error) {
+	status := "open"
+	if all {
+		status = "all"
+	}
+	return b.List(ListOptions{Label: "gt:postmortem", Status: status, Priority: -1})
+}
+
+// OpenPostmortemActions filters action items that are not yet closed.
+func OpenPostmortemActions(actions []*Issue) []*Issue {
+	var open []*Issue
+	for _, a := range actions {
+		if a.Status != "closed" {
+			open = append(open, a)
+		}
+	}
+	return open
+}
+
+// FinishPostmortem records the root cause and closes the postmortem. It
+// refuses while action items are open unless force is set.
+func (b *Beads) FinishPostmortem(id, finishedBy, rootCause string, force bool) error {
+	issue, fields, timeline, err := b.GetPostmortemBead(id)
+	if err != nil {
+		return err
+	}
+	if !force {
+		actions, err := b.ListPostmortemActions(id)
+		if err != nil {
+			return fmt.Errorf("listing action items: %w", err)
+		}
+		if open := OpenPostmortemActions(actions); len(open) > 0 {
+			ids := make([]string, len(open))
+			for i, a := range open {
+				ids[i] = a.ID
+			}
+			return fmt.Errorf("%d action item(s) still open: %s", len(open), strings.Join(ids, ", "))
+		}
+	}
+
+	fields.FinishedBy = finishedBy
+	fields.FinishedAt = time.Now().Format(time.RFC3339)
+	if rootCause != "" {
+		fields.RootCause = rootCause
+	}
+	description := FormatPostmortemDescription(issue.Title, fields, timeline)
+	if err := b.Update(id, UpdateOptions{Description: &description}); err != nil {
+		return err
+	}
+
+	reason := "postmortem finished"
+	if fields.RootCause != "" {
+		reason = "root cause: " + fields.RootCause
+	}
+	return b.CloseWithReason(reason, id)
+}
@@ -0,0 +1,57 @@
+package beads
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPostmortemDescriptionRoundTrip(t *testing.T) {
+	fields := &PostmortemFields{
+		IncidentStart: "2026-10-01T09:00:00Z",
+		IncidentEnd:   "2026-10-01T11:00:00Z",
+		Summary:       "Polecats died: too many open files",
+		StartedBy:     "mayor/",
+		StartedAt:     "2026-10-01T12:00:00Z",
+	}
+	timeline := []string{
+		"- 2026-10-01 09:01:00 session_death gastown/witness: agent=gastown/polecats/toast reason=zombie cleanup",
+		"- 2026-10-01 09:02:00 mass_death daemon: count=5",
+	}
+
+	desc := FormatPostmortemDescription("Mass death", fields, timeline)
+	for _, want := range []string{"incident_start: 2026-10-01T09:00:00Z", "root_cause: null", "## Timeline", timeline[1]} {
+		if !strings.Contains(desc, want) {
+			t.Errorf("description missing %q:\n%s", want, desc)
+		}
+	}
+
+	got, gotTimeline := ParsePostmortemFields(desc)
+	if *got != *fields {
+		t.Errorf("fields = %+v, want %+v", got, fields)
+	}
+	if len(gotTimeline) != 2 || gotTimeline[0] != timeline[0] {
+		t.Errorf("timeline = %q", gotTimeline)
+	}
+}
+
+func TestPostmortemDescription_EmptyTimeline(t *testing.T) {
+	desc := FormatPostmortemDescription("Quiet", nil, nil)
+	if !strings.Contains(desc, "(no events in the incident window)") {
+		t.Errorf("empty timeline should say so:\n%s", desc)
+	}
+	fields, timeline := ParsePostmortemFields(desc)
+	if fields.IncidentStart != "" || len(timeline) != 0 {
+		t.Errorf("got %+v %q", fields, timeline)
+	}
+}
+
+func TestOpenPostmortemActions(t *testing.T) {
+	open := OpenPostmortemActions([]*Issue{
+		{ID: "a", Status: "closed"},
+		{ID: "b", Status: "open"},
+		{ID: "c", Status: "in_progress"},
+	})
+	if len(open) != 2 || open[0].ID != "b" || open[1].ID != "c" {
+		t.Errorf("open = %v", open)
+	}
+}
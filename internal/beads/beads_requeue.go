@@ -0,0 +1,135 @@
+// Package beads provides requeue bookkeeping for work beads.
+package beads
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RequeueFields are the requeue lines stored in a work bead's description
+// when the Witness takes it from a tripped polecat.
+type RequeueFields struct {
+	Key           string    // Idempotency key: bead ID + trip timestamp (see RequeueKey)
+	CorrelationID string    // Traces the requeue through reassignment to completion
+	RequeuedAt    time.Time // When the requeue was recorded
+}
+
+// requeueKeys are the description keys owned by RequeueFields.
+var requeueKeys = map[string]bool{
+	"requeue_key":    true,
+	"correlation_id": true,
+	"requeued_at":    true,
+}
+
+// RequeueKey returns the idempotency key for requeuing a bead taken from a
+// polecat whose circuit opened at openedAt (its circuit_opened_at). Sweeps
+// of the same trip produce the same key, so the work is requeued once.
+func RequeueKey(beadID, openedAt string) string {
+	return beadID + "@" + openedAt
+}
+
+// CorrelationIDFor derives the correlation ID for a requeue key. It is
+// deterministic, so racing sweeps agree on it.
+func CorrelationIDFor(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "rq-" + hex.EncodeToString(sum[:])[:10]
+}
+
+// ParseRequeueFields extracts requeue fields from a description.
+// Returns nil if the bead was never requeued.
+func ParseRequeueFields(description string) *RequeueFields {
+	var fields RequeueFields
+	for _, line := range strings.Split(description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "requeue_key":
+			fields.Key = value
+		case "correlation_id":
+			fields.CorrelationID = value
+		case "requeued_at":
+			fields.RequeuedAt, _ = time.Parse(time.RFC3339, value)
+		}
+	}
+	if fields.Key == "" && fields.CorrelationID == "" {
+		return nil
+	}
+	return &fields
+}
+
+// SetRequeueFields replaces the requeue lines in a description, preserving
+// all other content. A nil fields removes them.
+func SetRequeueFields(description string, fields *RequeueFields) string {
+	var kept []string
+	for _, line := range strings.Split(description, "\n") {
+		if key, _, ok := strings.Cut(strings.TrimSpace(line), ":"); ok && requeueKeys[strings.TrimSpace(key)] {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	rest := strings.TrimRight(strings.Join(kept, "\n"), "\n")
+	if fields == nil {
+		return rest
+	}
+
+	var lines []string
+	if fields.Key != "" {
+		lines = append(lines, "requeue_key: "+fields.Key)
+	}
+	if fields.CorrelationID != "" {
+		lines = append(lines, "correlation_id: "+fields.CorrelationID)
+	}
+	if !fields.RequeuedAt.IsZero() {
+		lines = append(lines, "requeued_at: "+fields.RequeuedAt.UTC().Format(time.RFC3339))
+	}
+	if rest == "" {
+		return strings.Join(lines, "\n")
+	}
+	return rest + "\n" + strings.Join(lines, "\n")
+}
+
+// CorrelationID returns the correlation ID recorded on a work bead's
+// description, or "" if it was never requeued.
+func CorrelationID(description string) string {
+	if fields := ParseRequeueFields(description); fields != nil {
+		return fields.CorrelationID
+	}
+	return ""
+}
+
+// ClaimRequeue records a requeue of a work bead under key (see RequeueKey).
+// Returns the fields as written and true, or, when the bead already carries
+// key, the recorded fields and false: that requeue already happened and
+// must not be repeated.
+func (b *Beads) ClaimRequeue(id, key string) (*RequeueFields, bool, error) {
+	if target := b.routedFor(id); target != nil {
+		return target.ClaimRequeue(id, key)
+	}
+
+	unlock, err := b.lockBead(id)
+	if err != nil {
+		return nil, false, fmt.Errorf("locking %s: %w", id, err)
+	}
+	defer unlock()
+
+	issue, err := b.Show(id)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing := ParseRequeueFields(issue.Description); existing != nil && existing.Key == key {
+		return existing, false, nil
+	}
+
+	fields := &RequeueFields{Key: key, CorrelationID: CorrelationIDFor(key), RequeuedAt: time.Now()}
+	description := SetRequeueFields(issue.Description, fields)
+	if err := b.Update(id, UpdateOptions{Description: &description}); err != nil {
+		return nil, false, err
+	}
+	return fields, true, nil
+}
@@ -0,0 +1,41 @@
+package beads
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequeueFieldsRoundTrip(t *testing.T) {
+	key := RequeueKey("gt-abc", "2026-03-01T12:00:00Z")
+	if key != "gt-abc@2026-03-01T12:00:00Z" {
+		t.Errorf("RequeueKey() = %q", key)
+	}
+	id := CorrelationIDFor(key)
+	if !strings.HasPrefix(id, "rq-") || len(id) != 13 || id != CorrelationIDFor(key) {
+		t.Errorf("CorrelationIDFor() = %q, want a stable rq- ID", id)
+	}
+	if CorrelationIDFor(RequeueKey("gt-abc", "2026-03-01T13:00:00Z")) == id {
+		t.Error("a later trip got the same correlation ID")
+	}
+
+	at := time.Date(2026, 3, 1, 12, 1, 0, 0, time.UTC)
+	desc := SetRequeueFields("Fix the parser.\nwork_failures: 1", &RequeueFields{Key: key, CorrelationID: id, RequeuedAt: at})
+	got := ParseRequeueFields(desc)
+	if got == nil || got.Key != key || got.CorrelationID != id || !got.RequeuedAt.Equal(at) {
+		t.Fatalf("round trip = %+v", got)
+	}
+	if CorrelationID(desc) != id {
+		t.Errorf("CorrelationID() = %q, want %q", CorrelationID(desc), id)
+	}
+	if ParseWorkFailureFields(desc) == nil {
+		t.Errorf("work failure lines lost:\n%s", desc)
+	}
+
+	if cleared := SetRequeueFields(desc, nil); cleared != "Fix the parser.\nwork_failures: 1" {
+		t.Errorf("cleared description = %q", cleared)
+	}
+	if CorrelationID("no requeue here") != "" {
+		t.Error("CorrelationID() found an ID in a bead that was never requeued")
+	}
+}
@@ -0,0 +1,213 @@
+// Package beads provides work bead templates.
+package beads
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+// TemplatesDir is where a town keeps its work bead templates, in its .beads
+// directory beside formulas: one <name>.toml file per template.
+const TemplatesDir = "templates"
+
+// TemplateLabelPrefix starts the label a bead made from a template carries,
+// e.g. "template:bugfix", so the beads of a kind can be found again.
+const TemplateLabelPrefix = "template:"
+
+// ErrTemplateNotFound is returned when a town has no template of a name.
+var ErrTemplateNotFound = errors.New("template not found")
+
+var templateNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// BeadTemplate is a work bead template: the structure of a kind of work,
+// with {{var}} placeholders filled in when a bead is made from it.
+//
+//	about = "Fix a reported bug"
+//	title = "Fix: {{summary}}"
+//	type = "bug"
+//	priority = 1
+//	labels = ["bugfix"]
+//	description = """
+//	## Problem
+//	{{summary}} in {{repo}}.
+//	"""
+//	acceptance = ["A regression test covers the bug"]
+//
+//	[vars.repo]
+//	required = true
+//	[vars.summary]
+//	required = true
+type BeadTemplate struct {
+	Name        string                 `toml:"-" json:"name"`
+	About       string                 `toml:"about" json:"about,omitempty"` // One line on what the template is for
+	Title       string                 `toml:"title" json:"title,omitempty"`
+	Type        string                 `toml:"type" json:"type,omitempty"`
+	Priority    *int                   `toml:"priority" json:"priority,omitempty"`
+	Labels      []string               `toml:"labels" json:"labels,omitempty"`
+	Description string                 `toml:"description" json:"description,omitempty"`
+	Acceptance  []string               `toml:"acceptance" json:"acceptance,omitempty"` // Acceptance criteria, a bullet each
+	Vars        map[string]formula.Var `toml:"vars" json:"vars,omitempty"`
+}
+
+// TemplatePath returns the file of the town's template name.
+func TemplatePath(townRoot, name string) string {
+	return filepath.Join(townRoot, ".beads", TemplatesDir, name+".toml")
+}
+
+// LoadTemplate reads and checks the town's template name.
+func LoadTemplate(townRoot, name string) (*BeadTemplate, error) {
+	if !templateNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+	data, err := os.ReadFile(TemplatePath(townRoot, name)) //nolint:gosec // G304: name is checked above
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s (looked in %s)", ErrTemplateNotFound, name, filepath.Dir(TemplatePath(townRoot, name)))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseTemplate(name, data)
+}
+
+// ListTemplates returns the town's templates, sorted by name. A template
+// that can't be parsed is reported in the error; the others are returned.
+func ListTemplates(townRoot string) ([]*BeadTemplate, error) {
+	entries, err := os.ReadDir(filepath.Join(townRoot, ".beads", TemplatesDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var templates []*BeadTemplate
+	var errs []error
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".toml")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		t, err := LoadTemplate(townRoot, name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, errors.Join(errs...)
+}
+
+// ParseTemplate parses template name from TOML and checks that every
+// placeholder it uses is a declared variable.
+func ParseTemplate(name string, data []byte) (*BeadTemplate, error) {
+	t := &BeadTemplate{Name: name}
+	meta, err := toml.Decode(string(data), t)
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", name, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("template %s: unknown field %s", name, undecoded[0])
+	}
+	if t.Priority != nil && (*t.Priority < 0 || *t.Priority > 4) {
+		return nil, fmt.Errorf("template %s: priority %d is out of range (0-4)", name, *t.Priority)
+	}
+	var undeclared []string
+	for _, v := range formula.ExtractTemplateVariables(t.text()) {
+		if _, ok := t.Vars[v]; !ok {
+			undeclared = append(undeclared, v)
+		}
+	}
+	if len(undeclared) > 0 {
+		return nil, fmt.Errorf("template %s: undeclared variable(s) %s (add them under [vars])", name, strings.Join(undeclared, ", "))
+	}
+	return t, nil
+}
+
+// text returns all of the template's text that placeholders may appear in.
+func (t *BeadTemplate) text() string {
+	parts := append([]string{t.Title, t.Description}, t.Labels...)
+	return strings.Join(append(parts, t.Acceptance...), "\n")
+}
+
+// VarNames returns the template's variables, sorted.
+func (t *BeadTemplate) VarNames() []string {
+	names := make([]string, 0, len(t.Vars))
+	for name := range t.Vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render fills the template's placeholders from vars, falling back to
+// each variable's default, and returns the bead to create. The
+// acceptance criteria become an "## Acceptance criteria" section of the
+// description, and the bead is labeled with the template's name. A
+// variable that isn't declared, or a required one that isn't given, is an
+// error.
+func (t *BeadTemplate) Render(vars map[string]string) (CreateOptions, error) {
+	var unknown, missing []string
+	for name := range vars {
+		if _, ok := t.Vars[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	pairs := make([]string, 0, 2*len(t.Vars))
+	for _, name := range t.VarNames() {
+		value, ok := vars[name]
+		if !ok {
+			if t.Vars[name].Required {
+				missing = append(missing, name)
+				continue
+			}
+			value = t.Vars[name].Default
+		}
+		pairs = append(pairs, "{{"+name+"}}", value)
+	}
+	sort.Strings(unknown)
+	switch {
+	case len(unknown) > 0:
+		return CreateOptions{}, fmt.Errorf("template %s has no variable(s) %s (it takes: %s)", t.Name, strings.Join(unknown, ", "), strings.Join(t.VarNames(), ", "))
+	case len(missing) > 0:
+		return CreateOptions{}, fmt.Errorf("template %s needs variable(s) %s", t.Name, strings.Join(missing, ", "))
+	}
+	fill := strings.NewReplacer(pairs...).Replace
+
+	description := strings.TrimSpace(fill(t.Description))
+	if len(t.Acceptance) > 0 {
+		var section strings.Builder
+		section.WriteString("## Acceptance criteria\n")
+		for _, criterion := range t.Acceptance {
+			section.WriteString("- " + fill(criterion) + "\n")
+		}
+		if description != "" {
+			description += "\n\n"
+		}
+		description += strings.TrimSuffix(section.String(), "\n")
+	}
+
+	opts := CreateOptions{
+		Title:       strings.TrimSpace(fill(t.Title)),
+		Type:        t.Type,
+		Priority:    -1,
+		Description: description,
+		Labels:      []string{TemplateLabelPrefix + t.Name},
+	}
+	if t.Priority != nil {
+		opts.Priority = *t.Priority
+	}
+	for _, label := range t.Labels {
+		if label = strings.TrimSpace(fill(label)); label != "" {
+			opts.Labels = append(opts.Labels, label)
+		}
+	}
+	return opts, nil
+}
@@ -0,0 +1,121 @@
+package beads
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const bugfixTemplate = `about = "Fix a reported bug"
+title = "Fix: {{summary}}"
+type = "bug"
+priority = 1
+labels = ["bugfix", "repo:{{repo}}"]
+description = """
+## Problem
+{{summary}} in {{repo}} (branch {{branch}}).
+"""
+acceptance = ["A regression test covers it", "CI is green on {{branch}}"]
+
+[vars]
+branch = "main"
+
+[vars.repo]
+required = true
+
+[vars.summary]
+description = "What is broken"
+required = true
+`
+
+func TestBeadTemplateRender(t *testing.T) {
+	tmpl, err := ParseTemplate("bugfix", []byte(bugfixTemplate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tmpl.VarNames(); !reflect.DeepEqual(got, []string{"branch", "repo", "summary"}) {
+		t.Errorf("VarNames = %v", got)
+	}
+
+	opts, err := tmpl.Render(map[string]string{"repo": "gastown", "summary": "Login fails"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := CreateOptions{
+		Title:    "Fix: Login fails",
+		Type:     "bug",
+		Priority: 1,
+		Description: "## Problem\nLogin fails in gastown (branch main).\n\n" +
+			"## Acceptance criteria\n- A regression test covers it\n- CI is green on main",
+		Labels: []string{"template:bugfix", "bugfix", "repo:gastown"},
+	}
+	if !reflect.DeepEqual(opts, want) {
+		t.Errorf("Render = %+v\nwant %+v", opts, want)
+	}
+
+	if _, err := tmpl.Render(map[string]string{"repo": "gastown"}); err == nil || !strings.Contains(err.Error(), "summary") {
+		t.Errorf("a missing required variable should fail, got %v", err)
+	}
+	if _, err := tmpl.Render(map[string]string{"repo": "gastown", "summary": "x", "rpeo": "y"}); err == nil || !strings.Contains(err.Error(), "rpeo") {
+		t.Errorf("an unknown variable should fail, got %v", err)
+	}
+}
+
+func TestParseTemplateErrors(t *testing.T) {
+	tests := map[string]string{
+		"undeclared": `title = "Fix {{thing}}"`,
+		"unknown":    `titel = "Fix"`,
+		"priority":   `priority = 7`,
+	}
+	for name, data := range tests {
+		if _, err := ParseTemplate(name, []byte(data)); err == nil {
+			t.Errorf("%s: ParseTemplate should fail", name)
+		}
+	}
+	if tmpl, err := ParseTemplate("plain", []byte(`title = "Chore"`)); err != nil || tmpl.Priority != nil {
+		t.Errorf("ParseTemplate(plain) = %+v, %v", tmpl, err)
+	}
+}
+
+func TestLoadTemplates(t *testing.T) {
+	townRoot := t.TempDir()
+	dir := filepath.Join(townRoot, ".beads", TemplatesDir)
+	if templates, err := ListTemplates(townRoot); err != nil || len(templates) != 0 {
+		t.Errorf("ListTemplates with no dir = %v, %v", templates, err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string]string{
+		"bugfix.toml": bugfixTemplate,
+		"chore.toml":  `title = "Chore"`,
+		"broken.toml": `title = "{{x}}"`,
+		"README.md":   "not a template",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := LoadTemplate(townRoot, "missing"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("LoadTemplate(missing) error = %v, want ErrTemplateNotFound", err)
+	}
+	if _, err := LoadTemplate(townRoot, "../bugfix"); err == nil {
+		t.Error("a template name with a path should be refused")
+	}
+
+	templates, err := ListTemplates(townRoot)
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("ListTemplates should report the broken template, got %v", err)
+	}
+	var names []string
+	for _, tmpl := range templates {
+		names = append(names, tmpl.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"bugfix", "chore"}) {
+		t.Errorf("ListTemplates names = %v", names)
+	}
+}
@@ -597,6 +597,8 @@ func TestMRFieldsRoundTrip(t *testing.T) {
 		Rig:         "gastown",
 		MergeCommit: "abc123def789",
 		CloseReason: "merged",
+		ForgePR:     "github:acme/widgets#12",
+		HeadSHA:     "0f1e2d3c",
 	}
 
 	// Format to string
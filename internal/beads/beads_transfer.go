@@ -0,0 +1,185 @@
+// Package beads provides the transfer of work beads between rigs.
+package beads
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTransferInProgress is returned when transferring a bead that is on
+// an agent's hook or being worked, without TransferOptions.Force.
+var ErrTransferInProgress = errors.New("bead is being worked")
+
+// TransferOptions controls Transfer.
+type TransferOptions struct {
+	// SourceRig and TargetRig name the rigs ("hq" for the town) in the
+	// notes left on both beads.
+	SourceRig string
+	TargetRig string
+
+	// TargetPrefix is the target database's ID prefix, with its trailing
+	// hyphen (e.g. "bd-").
+	TargetPrefix string
+
+	// Force transfers a bead that is hooked or in progress. Whoever holds
+	// it is not told; the new bead is unassigned either way.
+	Force bool
+
+	// DryRun plans the transfer without writing.
+	DryRun bool
+}
+
+// TransferResult is what Transfer did.
+type TransferResult struct {
+	OldID    string   `json:"old_id"`
+	NewID    string   `json:"new_id,omitempty"` // Empty on a dry run whose ID bd will assign
+	Comments int      `json:"comments"`         // Comments copied to the new bead
+	Relinked []string `json:"relinked,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// transferComment is a comment as bd comments --json lists it.
+type transferComment struct {
+	Author    string `json:"author"`
+	Text      string `json:"text"`
+	CreatedAt string `json:"created_at"`
+}
+
+// TransferID returns the ID a bead gets in a database with prefix: the
+// same ID with its prefix rewritten (gt-abc12 to bd-abc12).
+func TransferID(id, prefix string) string {
+	return RemapID(id, map[string]string{ExtractPrefix(id): prefix})
+}
+
+// CheckTransferable reports why issue can't be transferred with opts, if
+// it can't: it is closed, isn't a work bead, is being worked (without
+// Force), or already has the target's prefix.
+func CheckTransferable(issue *Issue, opts TransferOptions) error {
+	switch {
+	case issue.Status == "closed" || issue.Status == "tombstone":
+		return fmt.Errorf("cannot transfer closed bead %s", issue.ID)
+	case IsAgentBead(issue) || issue.Ephemeral:
+		return fmt.Errorf("cannot transfer %s: only work beads can be transferred", issue.ID)
+	case (issue.Status == "hooked" || issue.Status == "in_progress") && !opts.Force:
+		return fmt.Errorf("%w: %s is %s by %s (unsling it first, or force)", ErrTransferInProgress, issue.ID, issue.Status, issue.Assignee)
+	case IsFlagLikeTitle(issue.Title):
+		return fmt.Errorf("cannot transfer %s: %w (got %q)", issue.ID, ErrFlagTitle, issue.Title)
+	case ExtractPrefix(issue.ID) == opts.TargetPrefix:
+		return fmt.Errorf("%s already has the target's prefix %s", issue.ID, opts.TargetPrefix)
+	}
+	return nil
+}
+
+// Transfer moves work bead id from this wrapper's database to target's,
+// so it can be worked in another rig. The new bead keeps the old ID with
+// the target's prefix (or gets one from bd if that is taken), the bead's
+// fields, labels, parent and dependencies, and its comments, plus one
+// recording where it came from. It arrives open and unassigned: its old
+// assignee works in the other rig. Beads that depended on the old bead
+// are pointed at the new one, and the old bead is closed with a reference
+// to it. A link that can't be made is a warning, not a failure.
+func (b *Beads) Transfer(target *Beads, id string, opts TransferOptions) (*TransferResult, error) {
+	issue, err := b.Show(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckTransferable(issue, opts); err != nil {
+		return nil, err
+	}
+
+	result := &TransferResult{OldID: id, NewID: TransferID(id, opts.TargetPrefix)}
+	if _, err := target.Show(result.NewID); err == nil {
+		result.NewID = "" // Taken: let bd assign one
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	bead := ToPortable(issue)
+	bead.Assignee = ""
+	newID, err := target.createPortable(bead, result.NewID)
+	if err != nil {
+		return nil, fmt.Errorf("creating %s in %s: %w", id, opts.TargetRig, err)
+	}
+	result.NewID = newID
+	warn := func(format string, args ...interface{}) {
+		result.Warnings = append(result.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	// History: the old bead's comments, then where it came from.
+	comments, err := b.transferComments(id)
+	if err != nil {
+		warn("comments of %s not copied: %v", id, err)
+	}
+	for _, c := range comments {
+		text := fmt.Sprintf("[%s, %s] %s", c.Author, c.CreatedAt, c.Text)
+		if _, err := target.run("comment", newID, text); err != nil {
+			warn("copying a comment to %s: %v", newID, err)
+			continue
+		}
+		result.Comments++
+	}
+	note := fmt.Sprintf("Transferred from %s (%s) by %s; created %s", id, opts.SourceRig, b.getActor(), issue.CreatedAt)
+	if issue.CreatedBy != "" {
+		note += " by " + issue.CreatedBy
+	}
+	if _, err := target.run("comment", newID, note); err != nil {
+		warn("recording the transfer on %s: %v", newID, err)
+	}
+
+	// Links: the new bead's own, then beads that depended on the old one.
+	link := func(from, to, depType string) error {
+		if depType == "" {
+			// bd would record it as blocks, which it wasn't.
+			return errors.New("relation has no type")
+		}
+		_, err := target.runWithRouting("dep", "add", from, to, "--type="+depType)
+		return err
+	}
+	if bead.Parent != "" {
+		if err := link(newID, bead.Parent, "parent-child"); err != nil {
+			warn("%s → parent %s: %v", newID, bead.Parent, err)
+		}
+	}
+	for _, dep := range bead.DependsOn {
+		if err := link(newID, dep.ID, dep.Type); err != nil {
+			warn("%s → %s (%s): %v", newID, dep.ID, dep.Type, err)
+		}
+	}
+	for _, dependent := range issue.Dependents {
+		if err := link(dependent.ID, newID, dependent.DependencyType); err != nil {
+			warn("%s still depends on %s, which is closing: %v", dependent.ID, id, err)
+			continue
+		}
+		if _, err := b.runWithRouting("dep", "remove", dependent.ID, id); err != nil {
+			warn("%s → %s not removed: %v", dependent.ID, id, err)
+		}
+		result.Relinked = append(result.Relinked, dependent.ID)
+	}
+
+	if err := b.ForceCloseWithReason(fmt.Sprintf("Transferred to %s (%s)", newID, opts.TargetRig), id); err != nil {
+		if cleanupErr := target.ForceCloseWithReason("Cleanup: source bead close failed during transfer", newID); cleanupErr != nil {
+			return result, fmt.Errorf("closing %s: %w (and closing %s failed too: %v; both are open)", id, err, newID, cleanupErr)
+		}
+		return result, fmt.Errorf("closing %s: %w (closed %s again)", id, err, newID)
+	}
+	return result, nil
+}
+
+// transferComments returns a bead's comments, oldest first.
+func (b *Beads) transferComments(id string) ([]transferComment, error) {
+	out, err := b.run("comments", id, "--json")
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(string(out)) == "" {
+		return nil, nil
+	}
+	var comments []transferComment
+	if err := json.Unmarshal(out, &comments); err != nil {
+		return nil, fmt.Errorf("parsing bd comments output: %w", err)
+	}
+	return comments, nil
+}
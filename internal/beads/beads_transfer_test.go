@@ -0,0 +1,46 @@
+package beads
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTransferID(t *testing.T) {
+	tests := []struct{ id, prefix, want string }{
+		{"gt-abc12", "bd-", "bd-abc12"},
+		{"gt-abc12.3", "bd-", "bd-abc12.3"},
+		{"hq-cv-xyz", "gt-", "gt-cv-xyz"},
+	}
+	for _, tt := range tests {
+		if got := TransferID(tt.id, tt.prefix); got != tt.want {
+			t.Errorf("TransferID(%q, %q) = %q, want %q", tt.id, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestCheckTransferable(t *testing.T) {
+	opts := TransferOptions{TargetPrefix: "bd-"}
+	if err := CheckTransferable(&Issue{ID: "gt-abc", Title: "Fix login", Status: "open"}, opts); err != nil {
+		t.Errorf("an open work bead should be transferable: %v", err)
+	}
+
+	hooked := &Issue{ID: "gt-abc", Title: "Fix login", Status: "hooked", Assignee: "gastown/polecats/nux"}
+	if err := CheckTransferable(hooked, opts); !errors.Is(err, ErrTransferInProgress) {
+		t.Errorf("a hooked bead should be refused with ErrTransferInProgress, got %v", err)
+	}
+	if err := CheckTransferable(hooked, TransferOptions{TargetPrefix: "bd-", Force: true}); err != nil {
+		t.Errorf("force should allow a hooked bead: %v", err)
+	}
+
+	refused := []*Issue{
+		{ID: "gt-abc", Title: "Fix login", Status: "closed"},
+		{ID: "gt-gastown-polecat-nux", Title: "Polecat nux", Status: "open", Labels: []string{"gt:agent"}},
+		{ID: "gt-wisp-abc", Title: "Step", Status: "open", Ephemeral: true},
+		{ID: "bd-abc", Title: "Already there", Status: "open"},
+	}
+	for _, issue := range refused {
+		if err := CheckTransferable(issue, opts); err == nil {
+			t.Errorf("%s (%s) should not be transferable", issue.ID, issue.Status)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package beads provides failure tracking for work beads.
+package beads
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WorkFailureFields are the failure lines stored in a work bead's
+// description. They count failures of the work itself, across every polecat
+// that took it, where an agent bead's failure_count follows one polecat.
+type WorkFailureFields struct {
+	FailureCount      int       // Failures recorded while the bead was hooked
+	TransientFailures int       // How many of those were transient
+	FailedBy          []string  // Polecats that failed it (rig/name), oldest first, no repeats
+	LastFailureReason string    // Classification of the latest failure
+	LastFailureAt     time.Time // When the latest failure was recorded
+}
+
+// Score is the failure count with transient failures left out: a rate limit
+// says nothing about whether the work itself is broken.
+func (f *WorkFailureFields) Score() int {
+	transient := f.TransientFailures
+	if transient > f.FailureCount {
+		transient = f.FailureCount
+	}
+	return f.FailureCount - transient
+}
+
+// workFailureKeys are the description keys owned by WorkFailureFields.
+var workFailureKeys = map[string]bool{
+	"work_failures":           true,
+	"work_transient_failures": true,
+	"work_failed_by":          true,
+	"work_last_failure":       true,
+	"work_last_failure_at":    true,
+}
+
+// ParseWorkFailureFields extracts work failure fields from a description.
+// Returns nil if no failure has been recorded.
+func ParseWorkFailureFields(description string) *WorkFailureFields {
+	var fields WorkFailureFields
+	for _, line := range strings.Split(description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "work_failures":
+			fields.FailureCount, _ = strconv.Atoi(value)
+		case "work_transient_failures":
+			fields.TransientFailures, _ = strconv.Atoi(value)
+		case "work_failed_by":
+			for _, polecat := range strings.Split(value, ",") {
+				if polecat = strings.TrimSpace(polecat); polecat != "" {
+					fields.FailedBy = append(fields.FailedBy, polecat)
+				}
+			}
+		case "work_last_failure":
+			fields.LastFailureReason = value
+		case "work_last_failure_at":
+			fields.LastFailureAt, _ = time.Parse(time.RFC3339, value)
+		}
+	}
+	if fields.FailureCount == 0 {
+		return nil
+	}
+	return &fields
+}
+
+// SetWorkFailureFields replaces the work failure lines in a description,
+// preserving all other content. A nil fields removes them.
+func SetWorkFailureFields(description string, fields *WorkFailureFields) string {
+	var kept []string
+	for _, line := range strings.Split(description, "\n") {
+		if key, _, ok := strings.Cut(strings.TrimSpace(line), ":"); ok && workFailureKeys[strings.TrimSpace(key)] {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	rest := strings.TrimRight(strings.Join(kept, "\n"), "\n")
+	if fields == nil || fields.FailureCount == 0 {
+		return rest
+	}
+
+	lines := []string{fmt.Sprintf("work_failures: %d", fields.FailureCount)}
+	if fields.TransientFailures > 0 {
+		lines = append(lines, fmt.Sprintf("work_transient_failures: %d", fields.TransientFailures))
+	}
+	if len(fields.FailedBy) > 0 {
+		lines = append(lines, "work_failed_by: "+strings.Join(fields.FailedBy, ", "))
+	}
+	if fields.LastFailureReason != "" {
+		lines = append(lines, "work_last_failure: "+fields.LastFailureReason)
+	}
+	if !fields.LastFailureAt.IsZero() {
+		lines = append(lines, "work_last_failure_at: "+fields.LastFailureAt.UTC().Format(time.RFC3339))
+	}
+	if rest == "" {
+		return strings.Join(lines, "\n")
+	}
+	return rest + "\n" + strings.Join(lines, "\n")
+}
+
+// RecordWorkFailure counts a failure, classified as reason (FailureTransient
+// etc.), against a work bead, noting polecat among the polecats that failed
+// it. Returns the fields as written.
+func (b *Beads) RecordWorkFailure(id, polecat, reason string) (*WorkFailureFields, error) {
+	if target := b.routedFor(id); target != nil {
+		return target.RecordWorkFailure(id, polecat, reason)
+	}
+
+	unlock, err := b.lockBead(id)
+	if err != nil {
+		return nil, fmt.Errorf("locking %s: %w", id, err)
+	}
+	defer unlock()
+
+	issue, err := b.Show(id)
+	if err != nil {
+		return nil, err
+	}
+	fields := ParseWorkFailureFields(issue.Description)
+	if fields == nil {
+		fields = &WorkFailureFields{}
+	}
+	fields.FailureCount++
+	if reason == FailureTransient {
+		fields.TransientFailures++
+	}
+	if polecat != "" && !slices.Contains(fields.FailedBy, polecat) {
+		fields.FailedBy = append(fields.FailedBy, polecat)
+	}
+	fields.LastFailureReason = reason
+	fields.LastFailureAt = time.Now()
+
+	description := SetWorkFailureFields(issue.Description, fields)
+	if err := b.Update(id, UpdateOptions{Description: &description}); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// WorkFailures returns the failures recorded against a work bead, or nil if
+// there are none.
+func (b *Beads) WorkFailures(id string) (*WorkFailureFields, error) {
+	issue, err := b.Show(id)
+	if err != nil {
+		return nil, err
+	}
+	return ParseWorkFailureFields(issue.Description), nil
+}
@@ -0,0 +1,46 @@
+package beads
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkFailureFieldsRoundTrip(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	fields := &WorkFailureFields{
+		FailureCount:      3,
+		TransientFailures: 1,
+		FailedBy:          []string{"gastown/nux", "gastown/toast"},
+		LastFailureReason: FailureDeterministic,
+		LastFailureAt:     at,
+	}
+	desc := SetWorkFailureFields("Fix the parser.\n\nattached_molecule: gt-wisp-1", fields)
+
+	got := ParseWorkFailureFields(desc)
+	if got == nil || got.FailureCount != 3 || got.TransientFailures != 1 ||
+		len(got.FailedBy) != 2 || got.FailedBy[1] != "gastown/toast" ||
+		got.LastFailureReason != FailureDeterministic || !got.LastFailureAt.Equal(at) {
+		t.Fatalf("round trip = %+v, want %+v", got, fields)
+	}
+	if got.Score() != 2 {
+		t.Errorf("Score() = %d, want 2 (transient failure left out)", got.Score())
+	}
+
+	// Replacing keeps other content and doesn't duplicate lines.
+	fields.FailureCount = 4
+	desc = SetWorkFailureFields(desc, fields)
+	if got := ParseWorkFailureFields(desc); got.FailureCount != 4 {
+		t.Errorf("FailureCount = %d after update, want 4", got.FailureCount)
+	}
+	if ParseAttachmentFields(&Issue{Description: desc}).AttachedMolecule != "gt-wisp-1" {
+		t.Errorf("attachment lost:\n%s", desc)
+	}
+
+	cleared := SetWorkFailureFields(desc, nil)
+	if ParseWorkFailureFields(cleared) != nil {
+		t.Errorf("failures not cleared:\n%s", cleared)
+	}
+	if want := "Fix the parser.\n\nattached_molecule: gt-wisp-1"; cleared != want {
+		t.Errorf("cleared description = %q, want %q", cleared, want)
+	}
+}
@@ -227,6 +227,10 @@ type MRFields struct {
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
 	ConvoyID        string // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
+
+	// Forge tracking (for MRs ingested from forge webhooks)
+	ForgePR string // Upstream pull request (e.g., "github:owner/repo#12")
+	HeadSHA string // Head commit the MR was last seen at
 }
 
 // ParseMRFields extracts structured merge-request fields from an issue's description.
@@ -301,6 +305,12 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "convoy_created_at", "convoy-created-at", "convoycreatedat":
 			fields.ConvoyCreatedAt = value
 			hasFields = true
+		case "forge_pr", "forge-pr", "forgepr":
+			fields.ForgePR = value
+			hasFields = true
+		case "head_sha", "head-sha", "headsha":
+			fields.HeadSHA = value
+			hasFields = true
 		}
 	}
 
@@ -365,6 +375,12 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.ConvoyCreatedAt != "" {
 		lines = append(lines, "convoy_created_at: "+fields.ConvoyCreatedAt)
 	}
+	if fields.ForgePR != "" {
+		lines = append(lines, "forge_pr: "+fields.ForgePR)
+	}
+	if fields.HeadSHA != "" {
+		lines = append(lines, "head_sha: "+fields.HeadSHA)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -411,6 +427,12 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"convoy_created_at":  true,
 		"convoy-created-at":  true,
 		"convoycreatedat":    true,
+		"forge_pr":           true,
+		"forge-pr":           true,
+		"forgepr":            true,
+		"head_sha":           true,
+		"head-sha":           true,
+		"headsha":            true,
 	}
 
 	// Collect non-MR lines from existing description
@@ -93,7 +93,7 @@ func TestAgentFieldsModeRoundTrip(t *testing.T) {
 	}
 
 	formatted := FormatAgentDescription("Polecat Test", original)
-	if !strings.Contains(formatted, "mode: ralph") {
+	if !strings.Contains(formatted, `"mode": "ralph"`) {
 		t.Errorf("FormatAgentDescription missing mode field, got:\n%s", formatted)
 	}
 
@@ -115,11 +115,142 @@ func TestAgentFieldsModeOmittedWhenEmpty(t *testing.T) {
 	}
 
 	formatted := FormatAgentDescription("Polecat Test", fields)
-	if strings.Contains(formatted, "mode:") {
+	if strings.Contains(formatted, `"mode":`) {
 		t.Errorf("FormatAgentDescription should not include mode when empty, got:\n%s", formatted)
 	}
 }
 
+// --- AgentFields CircuitState round-trip ---
+
+func TestAgentFieldsCircuitStateRoundTrip(t *testing.T) {
+	fields := &AgentFields{
+		RoleType:     "polecat",
+		Rig:          "gastown",
+		AgentState:   "working",
+		CircuitState: CircuitHalfOpen,
+	}
+
+	formatted := FormatAgentDescription("Polecat Test", fields)
+	if !strings.Contains(formatted, `"circuit_state": "half_open"`) {
+		t.Errorf("FormatAgentDescription missing circuit_state, got:\n%s", formatted)
+	}
+	if parsed := ParseAgentFields(formatted); parsed.CircuitState != CircuitHalfOpen {
+		t.Errorf("CircuitState: got %q, want %q", parsed.CircuitState, CircuitHalfOpen)
+	}
+
+	fields.CircuitState = ""
+	if formatted := FormatAgentDescription("Polecat Test", fields); strings.Contains(formatted, `"circuit_state":`) {
+		t.Errorf("FormatAgentDescription should omit empty circuit_state, got:\n%s", formatted)
+	}
+}
+
+func TestAgentFieldsFailureCountRoundTrip(t *testing.T) {
+	fields := &AgentFields{
+		RoleType:     "polecat",
+		Rig:          "gastown",
+		CircuitState: CircuitOpen,
+		FailureCount: 3,
+	}
+
+	formatted := FormatAgentDescription("Polecat Test", fields)
+	if !strings.Contains(formatted, `"failure_count": 3`) {
+		t.Errorf("FormatAgentDescription missing failure_count, got:\n%s", formatted)
+	}
+	if parsed := ParseAgentFields(formatted); parsed.FailureCount != 3 {
+		t.Errorf("FailureCount: got %d, want 3", parsed.FailureCount)
+	}
+
+	fields.CircuitOpenedAt = "2026-03-01T12:00:00Z"
+	formatted = FormatAgentDescription("Polecat Test", fields)
+	if parsed := ParseAgentFields(formatted); parsed.CircuitOpenedAt != fields.CircuitOpenedAt {
+		t.Errorf("CircuitOpenedAt: got %q, want %q", parsed.CircuitOpenedAt, fields.CircuitOpenedAt)
+	}
+
+	fields.FailureCount = 0
+	if formatted := FormatAgentDescription("Polecat Test", fields); strings.Contains(formatted, `"failure_count":`) {
+		t.Errorf("FormatAgentDescription should omit zero failure_count, got:\n%s", formatted)
+	}
+}
+
+// --- AgentFields boot time round-trip ---
+
+func TestAgentFieldsBootTimeRoundTrip(t *testing.T) {
+	fields := &AgentFields{
+		RoleType:      "polecat",
+		Rig:           "gastown",
+		AgentState:    "working",
+		BootMs:        72500,
+		BootDiagnosis: "slow_shell_init,priming_size",
+	}
+
+	formatted := FormatAgentDescription("Polecat Test", fields)
+	parsed := ParseAgentFields(formatted)
+	if parsed.BootMs != 72500 || parsed.BootDiagnosis != "slow_shell_init,priming_size" {
+		t.Errorf("boot fields: got %d/%q, formatted:\n%s", parsed.BootMs, parsed.BootDiagnosis, formatted)
+	}
+
+	fields.BootMs, fields.BootDiagnosis = 0, ""
+	if formatted := FormatAgentDescription("Polecat Test", fields); strings.Contains(formatted, "boot_") {
+		t.Errorf("FormatAgentDescription should omit unset boot fields, got:\n%s", formatted)
+	}
+}
+
+// --- AgentFields resource sample round-trip ---
+
+func TestAgentFieldsResourcesRoundTrip(t *testing.T) {
+	fields := &AgentFields{
+		RoleType:          "polecat",
+		Rig:               "gastown",
+		ResourceUsage:     "cpu 85.0%, rss 1.2GB, fds 310, procs 6",
+		ResourceCPUTicks:  123456,
+		ResourceSampledAt: "2026-03-01T12:00:00Z",
+		ResourceStrikes:   2,
+	}
+
+	formatted := FormatAgentDescription("Polecat Test", fields)
+	parsed := ParseAgentFields(formatted)
+	if parsed.ResourceUsage != fields.ResourceUsage || parsed.ResourceCPUTicks != 123456 ||
+		parsed.ResourceSampledAt != fields.ResourceSampledAt || parsed.ResourceStrikes != 2 {
+		t.Errorf("resource fields: got %+v, formatted:\n%s", parsed, formatted)
+	}
+
+	fields.ResourceUsage, fields.ResourceCPUTicks, fields.ResourceSampledAt, fields.ResourceStrikes = "", 0, "", 0
+	if formatted := FormatAgentDescription("Polecat Test", fields); strings.Contains(formatted, "resource_") {
+		t.Errorf("FormatAgentDescription should omit unset resource fields, got:\n%s", formatted)
+	}
+}
+
+func TestAgentFieldsStallRoundTrip(t *testing.T) {
+	fields := &AgentFields{
+		RoleType:          "polecat",
+		Rig:               "gastown",
+		OutputFingerprint: "3f2a9c01d4e5",
+		OutputChangedAt:   "2026-03-01T12:00:00Z",
+		StallState:        StallNudged,
+		StallStateAt:      "2026-03-01T12:20:00Z",
+	}
+
+	formatted := FormatAgentDescription("Polecat Test", fields)
+	parsed := ParseAgentFields(formatted)
+	if parsed.OutputFingerprint != fields.OutputFingerprint || parsed.OutputChangedAt != fields.OutputChangedAt ||
+		parsed.StallState != StallNudged || parsed.StallStateAt != fields.StallStateAt {
+		t.Errorf("stall fields: got %+v, formatted:\n%s", parsed, formatted)
+	}
+}
+
+func TestIsValidCircuitState(t *testing.T) {
+	for _, s := range []string{"", CircuitClosed, CircuitOpen, CircuitHalfOpen} {
+		if !IsValidCircuitState(s) {
+			t.Errorf("IsValidCircuitState(%q) = false, want true", s)
+		}
+	}
+	for _, s := range []string{"half-open", "OPEN", "tripped"} {
+		if IsValidCircuitState(s) {
+			t.Errorf("IsValidCircuitState(%q) = true, want false", s)
+		}
+	}
+}
+
 // --- Convoy fields in AttachmentFields (gt-7b6wf fix) ---
 
 func TestParseAttachmentFieldsConvoy(t *testing.T) {
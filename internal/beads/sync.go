@@ -0,0 +1,388 @@
+package beads
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// SyncActor is the actor 'gt beads sync' applies a peer's changes as. The
+// events it leaves are the sync's own writes, not changes to send back.
+const SyncActor = "gt-sync"
+
+// SyncDep is one dependency of a synced bead.
+type SyncDep struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// SyncRecord is the replicated content of one bead, as exchanged by
+// 'gt beads sync'. Runtime-only state (agent slots, dependency counts) is
+// not part of it.
+type SyncRecord struct {
+	DB          string    `json:"db"` // "hq" or a rig name
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Status      string    `json:"status"`
+	Priority    int       `json:"priority"`
+	Type        string    `json:"type,omitempty"`
+	Assignee    string    `json:"assignee,omitempty"`
+	Labels      []string  `json:"labels,omitempty"`
+	Deps        []SyncDep `json:"deps,omitempty"` // Including the parent, as parent-child
+	UpdatedAt   string    `json:"updated_at,omitempty"`
+}
+
+// SyncRecordFromIssue builds a sync record for an issue in db, as bd show
+// returns it (bd list leaves out dependency IDs).
+func SyncRecordFromIssue(db string, issue *Issue) SyncRecord {
+	labels := append([]string(nil), issue.Labels...)
+	sort.Strings(labels)
+	return SyncRecord{
+		DB:          db,
+		ID:          issue.ID,
+		Title:       issue.Title,
+		Description: issue.Description,
+		Status:      issue.Status,
+		Priority:    issue.Priority,
+		Type:        issue.Type,
+		Assignee:    issue.Assignee,
+		Labels:      labels,
+		Deps:        syncDeps(issue),
+		UpdatedAt:   issue.UpdatedAt,
+	}
+}
+
+// syncDeps returns an issue's dependencies, sorted.
+func syncDeps(issue *Issue) []SyncDep {
+	var deps []SyncDep
+	for _, dep := range issue.Dependencies {
+		deps = append(deps, SyncDep{ID: dep.ID, Type: dep.DependencyType})
+	}
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].ID != deps[j].ID {
+			return deps[i].ID < deps[j].ID
+		}
+		return deps[i].Type < deps[j].Type
+	})
+	return deps
+}
+
+// Fingerprint identifies a record's content. UpdatedAt is left out so a
+// bead re-applied with identical content doesn't look changed.
+func (r SyncRecord) Fingerprint() string {
+	r.UpdatedAt = ""
+	data, _ := json.Marshal(r)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+// Syncable reports whether an issue is replicated between towns. Wisps are
+// ephemeral and agent beads describe sessions on one machine, so neither
+// travels.
+func Syncable(issue *Issue) bool {
+	return !issue.Ephemeral && !HasLabel(issue, "gt:agent")
+}
+
+// SyncEvent is an entry in a database's event history, bd's audit trail
+// of every write to a bead.
+type SyncEvent struct {
+	DB        string `json:"db"` // Set by the sync; bd doesn't know it
+	ID        int64  `json:"id"`
+	IssueID   string `json:"issue_id"`
+	EventType string `json:"event_type"`
+	Actor     string `json:"actor"`
+	CreatedAt string `json:"created_at"`
+}
+
+// SyncEvents returns the events in the event history of the database this
+// wrapper operates on after event ID after, oldest first. bd appends its
+// new events to .beads/events.jsonl on 'bd export --events', which this
+// runs first.
+func (b *Beads) SyncEvents(after int64) ([]SyncEvent, error) {
+	if _, err := b.run("export", "--events"); err != nil {
+		return nil, fmt.Errorf("exporting events: %w", err)
+	}
+	f, err := os.Open(filepath.Join(b.getResolvedBeadsDir(), "events.jsonl")) //nolint:gosec // G304: path is the resolved beads dir
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []SyncEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var ev SyncEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			continue // A torn line from an export in progress
+		}
+		if ev.ID > after {
+			events = append(events, ev)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading events: %w", err)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events, nil
+}
+
+// SyncChanges is one town's side of a sync: the beads its event history
+// shows were changed since the last sync with the peer, as they are now,
+// the events that changed them, and where each database's history ends.
+type SyncChanges struct {
+	Records []SyncRecord     `json:"records"`
+	Events  []SyncEvent      `json:"events,omitempty"`
+	Marks   map[string]int64 `json:"marks"` // db -> ID of the last event in its history
+}
+
+// Sync actions.
+const (
+	SyncPull     = "pull"     // Remote changed; apply it locally
+	SyncPush     = "push"     // Local changed; apply it remotely
+	SyncConflict = "conflict" // Both changed since the last sync
+)
+
+// SyncAction is one planned change.
+type SyncAction struct {
+	Action       string      `json:"action"`
+	ID           string      `json:"id"`
+	Local        *SyncRecord `json:"local,omitempty"`
+	Remote       *SyncRecord `json:"remote,omitempty"`
+	LocalEvents  []SyncEvent `json:"local_events,omitempty"`  // For a conflict: the local changes
+	RemoteEvents []SyncEvent `json:"remote_events,omitempty"` // For a conflict: the remote changes
+}
+
+// SyncBase is where each town's event history stood at the end of the last
+// sync with a peer, and which changes that sync didn't carry over. It
+// tells "changed here" apart from "changed there": a bead with events
+// after the mark was changed on that side.
+type SyncBase struct {
+	Peer     string    `json:"peer"`
+	SyncedAt time.Time `json:"synced_at,omitempty"`
+
+	// Local and Remote map each database to the ID of the last event of
+	// this town's and the peer's history the sync covered.
+	Local  map[string]int64 `json:"local"`
+	Remote map[string]int64 `json:"remote"`
+
+	// LocalPending and RemotePending list, by database, the beads whose
+	// change on this side or the peer's wasn't applied on the other
+	// (conflicts, failures), so the next sync takes them as changed
+	// whatever their events.
+	LocalPending  map[string][]string `json:"local_pending,omitempty"`
+	RemotePending map[string][]string `json:"remote_pending,omitempty"`
+}
+
+// Mirror returns the base as the peer keeps it.
+func (base *SyncBase) Mirror(peer string) *SyncBase {
+	return &SyncBase{
+		Peer:          peer,
+		SyncedAt:      base.SyncedAt,
+		Local:         base.Remote,
+		Remote:        base.Local,
+		LocalPending:  base.RemotePending,
+		RemotePending: base.LocalPending,
+	}
+}
+
+// SyncChangedIDs returns the beads events changed, by database, leaving
+// out the sync's own writes (see SyncActor).
+func SyncChangedIDs(events []SyncEvent) map[string]map[string]bool {
+	changed := make(map[string]map[string]bool)
+	for _, ev := range events {
+		if ev.Actor == SyncActor {
+			continue
+		}
+		if changed[ev.DB] == nil {
+			changed[ev.DB] = make(map[string]bool)
+		}
+		changed[ev.DB][ev.IssueID] = true
+	}
+	return changed
+}
+
+// PlanSync compares the beads each town changed since the last sync and
+// returns the changes needed to converge, ordered by bead ID. A bead
+// changed on only one side is copied to the other. A bead changed on both
+// sides is a conflict, unless both now hold the same content. A database
+// neither town has synced before counts every bead as changed, so on the
+// first sync any bead that differs is a conflict. Deletions aren't
+// propagated: beads are closed, not deleted.
+func PlanSync(local, remote SyncChanges) []SyncAction {
+	localByID := make(map[string]*SyncRecord, len(local.Records))
+	for i := range local.Records {
+		localByID[local.Records[i].ID] = &local.Records[i]
+	}
+	remoteByID := make(map[string]*SyncRecord, len(remote.Records))
+	for i := range remote.Records {
+		remoteByID[remote.Records[i].ID] = &remote.Records[i]
+	}
+	eventsByID := func(events []SyncEvent) map[string][]SyncEvent {
+		byID := make(map[string][]SyncEvent)
+		for _, ev := range events {
+			if ev.Actor != SyncActor {
+				byID[ev.IssueID] = append(byID[ev.IssueID], ev)
+			}
+		}
+		return byID
+	}
+	localEvents, remoteEvents := eventsByID(local.Events), eventsByID(remote.Events)
+
+	ids := make([]string, 0, len(localByID)+len(remoteByID))
+	for id := range localByID {
+		ids = append(ids, id)
+	}
+	for id := range remoteByID {
+		if _, ok := localByID[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var actions []SyncAction
+	for _, id := range ids {
+		l, r := localByID[id], remoteByID[id]
+		switch {
+		case r == nil:
+			actions = append(actions, SyncAction{Action: SyncPush, ID: id, Local: l})
+		case l == nil:
+			actions = append(actions, SyncAction{Action: SyncPull, ID: id, Remote: r})
+		case l.Fingerprint() != r.Fingerprint():
+			actions = append(actions, SyncAction{Action: SyncConflict, ID: id, Local: l, Remote: r,
+				LocalEvents: localEvents[id], RemoteEvents: remoteEvents[id]})
+		}
+	}
+	return actions
+}
+
+// ApplySyncRecord makes the fields of the bead in b match rec, creating it
+// with the same ID if it doesn't exist. Dependencies are left to
+// ApplySyncDeps, once every bead in the sync exists.
+func (b *Beads) ApplySyncRecord(rec SyncRecord) error {
+	current, err := b.Show(rec.ID)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		if _, err := b.CreateWithID(rec.ID, CreateOptions{
+			Title:       rec.Title,
+			Priority:    rec.Priority,
+			Description: rec.Description,
+			Labels:      rec.Labels,
+		}); err != nil {
+			return fmt.Errorf("creating %s: %w", rec.ID, err)
+		}
+		current = &Issue{ID: rec.ID}
+	case err != nil:
+		return err
+	}
+
+	status := rec.Status
+	opts := UpdateOptions{
+		Title:       &rec.Title,
+		Priority:    &rec.Priority,
+		Description: &rec.Description,
+		Assignee:    &rec.Assignee,
+		SetLabels:   rec.Labels,
+	}
+	if status != "closed" {
+		opts.Status = &status
+	}
+	if err := b.Update(rec.ID, opts); err != nil {
+		return fmt.Errorf("updating %s: %w", rec.ID, err)
+	}
+	if rec.Type != "" && rec.Type != current.Type {
+		if _, err := b.run("update", rec.ID, "--type="+rec.Type); err != nil {
+			return fmt.Errorf("setting type of %s: %w", rec.ID, err)
+		}
+	}
+	if status == "closed" {
+		if err := b.CloseWithReason("synced from peer town", rec.ID); err != nil {
+			return fmt.Errorf("closing %s: %w", rec.ID, err)
+		}
+	}
+	return nil
+}
+
+// ApplySyncDeps makes the dependencies of the bead in b match rec's.
+func (b *Beads) ApplySyncDeps(rec SyncRecord) error {
+	issue, err := b.Show(rec.ID)
+	if err != nil {
+		return err
+	}
+	current := make(map[SyncDep]bool)
+	for _, dep := range syncDeps(issue) {
+		current[dep] = true
+	}
+	want := make(map[SyncDep]bool, len(rec.Deps))
+	for _, dep := range rec.Deps {
+		want[dep] = true
+		// bd would record an untyped relation as blocks, which it wasn't.
+		if !current[dep] && dep.Type != "" {
+			if _, err := b.runWithRouting("dep", "add", rec.ID, dep.ID, "--type="+dep.Type); err != nil {
+				return fmt.Errorf("adding %s → %s (%s): %w", rec.ID, dep.ID, dep.Type, err)
+			}
+		}
+	}
+	for dep := range current {
+		if !want[dep] {
+			if _, err := b.runWithRouting("dep", "remove", rec.ID, dep.ID); err != nil {
+				return fmt.Errorf("removing %s → %s: %w", rec.ID, dep.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// syncPeerName matches characters allowed in a sync base file name.
+var syncPeerName = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// SyncBasePath returns where the sync base for peer is kept.
+func SyncBasePath(townRoot, peer string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "beads-sync", syncPeerName.ReplaceAllString(peer, "_")+".json")
+}
+
+// LoadSyncBase loads the sync base for peer. A peer never synced with
+// returns an empty base.
+func LoadSyncBase(townRoot, peer string) (*SyncBase, error) {
+	base := &SyncBase{Peer: peer}
+	data, err := os.ReadFile(SyncBasePath(townRoot, peer)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, base); err != nil {
+			return nil, fmt.Errorf("parsing sync base for %s: %w", peer, err)
+		}
+	}
+	if base.Local == nil {
+		base.Local = map[string]int64{}
+	}
+	if base.Remote == nil {
+		base.Remote = map[string]int64{}
+	}
+	return base, nil
+}
+
+// SaveSyncBase writes the sync base for its peer.
+func SaveSyncBase(townRoot string, base *SyncBase) error {
+	path := SyncBasePath(townRoot, base.Peer)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, base)
+}
@@ -0,0 +1,121 @@
+package beads
+
+import (
+	"testing"
+)
+
+func TestPlanSync(t *testing.T) {
+	rec := func(id, status string) SyncRecord {
+		return SyncRecord{DB: "hq", ID: id, Title: "Bead " + id, Status: status}
+	}
+	local := SyncChanges{
+		Records: []SyncRecord{
+			rec("hq-local", "closed"),
+			rec("hq-both", "in_progress"),
+			rec("hq-converge", "closed"),
+		},
+		Events: []SyncEvent{
+			{DB: "hq", ID: 7, IssueID: "hq-both", EventType: "status_changed", Actor: "me"},
+			{DB: "hq", ID: 8, IssueID: "hq-both", EventType: "updated", Actor: SyncActor},
+		},
+	}
+	remote := SyncChanges{
+		Records: []SyncRecord{
+			rec("hq-remote", "hooked"),
+			rec("hq-both", "closed"),
+			rec("hq-converge", "closed"),
+		},
+	}
+
+	got := make(map[string]SyncAction)
+	for _, a := range PlanSync(local, remote) {
+		got[a.ID] = a
+	}
+	want := map[string]string{
+		"hq-local":  SyncPush,
+		"hq-remote": SyncPull,
+		"hq-both":   SyncConflict,
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d actions %v, want %d", len(got), got, len(want))
+	}
+	for id, action := range want {
+		if got[id].Action != action {
+			t.Errorf("%s: got %q, want %q", id, got[id].Action, action)
+		}
+	}
+	if events := got["hq-both"].LocalEvents; len(events) != 1 || events[0].ID != 7 {
+		t.Errorf("conflict should carry the local changes but not the sync's own writes, got %+v", events)
+	}
+}
+
+func TestSyncChangedIDs(t *testing.T) {
+	changed := SyncChangedIDs([]SyncEvent{
+		{DB: "hq", IssueID: "hq-1", Actor: "me"},
+		{DB: "gastown", IssueID: "gt-1", Actor: "me"},
+		{DB: "hq", IssueID: "hq-2", Actor: SyncActor},
+	})
+	if !changed["hq"]["hq-1"] || !changed["gastown"]["gt-1"] || changed["hq"]["hq-2"] {
+		t.Errorf("changed = %v, want hq-1 and gt-1 but not the sync's own write", changed)
+	}
+}
+
+func TestSyncRecordFromIssue(t *testing.T) {
+	rec := SyncRecordFromIssue("hq", &Issue{ID: "hq-1", Type: "bug", Labels: []string{"b", "a"},
+		Dependencies: []IssueDep{{ID: "hq-3"}, {ID: "hq-2", DependencyType: "parent-child"}}})
+	if rec.Type != "bug" || rec.Labels[0] != "a" {
+		t.Errorf("record = %+v", rec)
+	}
+	want := []SyncDep{{ID: "hq-2", Type: "parent-child"}, {ID: "hq-3"}}
+	if len(rec.Deps) != 2 || rec.Deps[0] != want[0] || rec.Deps[1] != want[1] {
+		t.Errorf("deps = %+v, want %+v", rec.Deps, want)
+	}
+}
+
+func TestSyncRecordFingerprint(t *testing.T) {
+	a := SyncRecord{ID: "gt-1", Title: "x", Status: "open", UpdatedAt: "2026-10-16T12:00:00Z"}
+	b := a
+	b.UpdatedAt = "2026-10-16T13:00:00Z"
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Error("fingerprint should ignore updated_at")
+	}
+	b.Status = "closed"
+	if a.Fingerprint() == b.Fingerprint() {
+		t.Error("fingerprint should change with status")
+	}
+}
+
+func TestSyncable(t *testing.T) {
+	if !Syncable(&Issue{ID: "gt-1"}) {
+		t.Error("plain issue should sync")
+	}
+	if Syncable(&Issue{ID: "gt-wisp-1", Ephemeral: true}) {
+		t.Error("wisps should not sync")
+	}
+	if Syncable(&Issue{ID: "gt-gastown-polecat-nux", Labels: []string{"gt:agent"}}) {
+		t.Error("agent beads should not sync")
+	}
+}
+
+func TestSyncBaseRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	base, err := LoadSyncBase(townRoot, "me@desk top")
+	if err != nil || len(base.Local) != 0 || len(base.Remote) != 0 {
+		t.Fatalf("missing base: %+v, %v", base, err)
+	}
+	base.Local["hq"] = 12
+	base.Remote["hq"] = 40
+	base.LocalPending = map[string][]string{"hq": {"hq-1"}}
+	if err := SaveSyncBase(townRoot, base); err != nil {
+		t.Fatalf("SaveSyncBase: %v", err)
+	}
+	got, err := LoadSyncBase(townRoot, "me@desk top")
+	if err != nil || got.Local["hq"] != 12 || got.LocalPending["hq"][0] != "hq-1" {
+		t.Errorf("reloaded base: %+v, %v", got, err)
+	}
+
+	mirror := got.Mirror("laptop")
+	if mirror.Peer != "laptop" || mirror.Local["hq"] != 40 || mirror.Remote["hq"] != 12 || mirror.RemotePending["hq"][0] != "hq-1" {
+		t.Errorf("mirror = %+v", mirror)
+	}
+}
@@ -24,6 +24,13 @@ const MarkerFileName = ".boot-running"
 // StatusFileName stores Boot's last execution status.
 const StatusFileName = ".boot-status.json"
 
+// OneShotLimits bound a Boot triage session. Triage is a single decision;
+// a Boot still around after these is hung and gets reaped by the daemon.
+var OneShotLimits = session.OneShotLimits{
+	MaxLifetime: 15 * time.Minute,
+	IdleTimeout: 5 * time.Minute,
+}
+
 // Status represents Boot's execution status.
 type Status struct {
 	Running     bool      `json:"running"`
@@ -191,6 +198,7 @@ func (b *Boot) spawnTmux(agentOverride string) error {
 		},
 		Instructions:  "Run `" + cli.Name() + " boot triage` now.",
 		AgentOverride: agentOverride,
+		OneShot:       &OneShotLimits,
 	})
 	return err
 }
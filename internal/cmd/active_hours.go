@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/activehours"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	activeHoursJSON   bool
+	activeHoursFor    time.Duration
+	activeHoursReason string
+)
+
+var activeHoursCmd = &cobra.Command{
+	Use:     "active-hours",
+	GroupID: GroupServices,
+	Short:   "Show or override when the town may spawn polecats",
+	Long: `Show or override the town's active hours.
+
+Active hours restrict when new polecats may be spawned. Outside every
+window, gt sling to a rig is refused and the daemon stops feeding stranded
+convoys; agents already running keep working. Configure windows in
+settings/config.json:
+
+  "active_hours": {
+    "timezone": "America/Los_Angeles",
+    "windows": [
+      {"start": "22:00", "end": "06:00"},
+      {"days": ["weekends"], "start": "10:00", "end": "18:00"}
+    ]
+  }
+
+Days are mon..sun, weekdays, or weekends (default every day). A window
+whose end is at or before its start runs into the next day. Without
+windows the town is always open.
+
+Overrides win over the schedule until they expire or are cleared. A single
+spawn can also bypass the schedule with gt sling --ignore-active-hours.
+
+Examples:
+  gt active-hours                          # Show status
+  gt active-hours open --for 2h            # Allow spawns now for two hours
+  gt active-hours close --reason "demo"    # Pause spawns until resumed
+  gt active-hours resume                   # Drop the override, follow the schedule`,
+	RunE: runActiveHoursStatus,
+}
+
+var activeHoursStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether spawning is allowed now",
+	RunE:  runActiveHoursStatus,
+}
+
+var activeHoursOpenCmd = &cobra.Command{
+	Use:   "open",
+	Short: "Allow spawning regardless of the schedule",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runActiveHoursOverride(activehours.ModeOpen)
+	},
+}
+
+var activeHoursCloseCmd = &cobra.Command{
+	Use:   "close",
+	Short: "Refuse spawning regardless of the schedule",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runActiveHoursOverride(activehours.ModeClosed)
+	},
+}
+
+var activeHoursResumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Clear the override and follow the schedule again",
+	RunE:  runActiveHoursResume,
+}
+
+func init() {
+	activeHoursCmd.Flags().BoolVar(&activeHoursJSON, "json", false, "Output as JSON")
+	activeHoursStatusCmd.Flags().BoolVar(&activeHoursJSON, "json", false, "Output as JSON")
+	for _, c := range []*cobra.Command{activeHoursOpenCmd, activeHoursCloseCmd} {
+		c.Flags().DurationVar(&activeHoursFor, "for", 0, "How long the override lasts (default: until resumed)")
+		c.Flags().StringVar(&activeHoursReason, "reason", "", "Why the schedule is being overridden")
+	}
+
+	activeHoursCmd.AddCommand(activeHoursStatusCmd)
+	activeHoursCmd.AddCommand(activeHoursOpenCmd)
+	activeHoursCmd.AddCommand(activeHoursCloseCmd)
+	activeHoursCmd.AddCommand(activeHoursResumeCmd)
+	rootCmd.AddCommand(activeHoursCmd)
+}
+
+func runActiveHoursStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	now := time.Now()
+	st, err := activehours.Current(townRoot, now)
+	if err != nil {
+		return err
+	}
+
+	if activeHoursJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(st)
+	}
+
+	if st.Open {
+		fmt.Printf("%s Spawning %s\n", style.Success.Render("●"), st.Summary(now))
+	} else {
+		fmt.Printf("%s Spawning %s\n", style.Warning.Render("⏸"), st.Summary(now))
+	}
+	if st.Configured {
+		fmt.Printf("  Time zone: %s\n", st.Timezone)
+	}
+	if o := st.Override; o != nil {
+		fmt.Printf("  Override set by %s at %s\n", o.SetBy, o.SetAt.Local().Format(time.RFC3339))
+		if o.Reason != "" {
+			fmt.Printf("  Reason: %s\n", o.Reason)
+		}
+		fmt.Printf("Follow the schedule again with: %s\n", style.Dim.Render("gt active-hours resume"))
+	}
+	return nil
+}
+
+func runActiveHoursOverride(mode string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if activeHoursFor < 0 {
+		return fmt.Errorf("--for must be positive")
+	}
+
+	now := time.Now()
+	o := &activehours.Override{
+		Mode:   mode,
+		Reason: activeHoursReason,
+		SetBy:  detectSender(),
+		SetAt:  now.UTC(),
+	}
+	if o.SetBy == "" {
+		o.SetBy = "human"
+	}
+	if activeHoursFor > 0 {
+		o.Until = now.Add(activeHoursFor).UTC()
+	}
+	if err := activehours.SetOverride(townRoot, o); err != nil {
+		return fmt.Errorf("setting override: %w", err)
+	}
+
+	st, err := activehours.Current(townRoot, now)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Spawning %s\n", style.Success.Render("✓"), st.Summary(now))
+	return nil
+}
+
+func runActiveHoursResume(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cleared, err := activehours.ClearOverride(townRoot)
+	if err != nil {
+		return fmt.Errorf("clearing override: %w", err)
+	}
+	if !cleared {
+		fmt.Printf("%s No override set\n", style.Dim.Render("○"))
+	}
+
+	now := time.Now()
+	st, err := activehours.Current(townRoot, now)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Spawning %s\n", style.Success.Render("✓"), st.Summary(now))
+	return nil
+}
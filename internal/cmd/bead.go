@@ -14,7 +14,7 @@ import (
 
 var beadCmd = &cobra.Command{
 	Use:     "bead",
-	Aliases: []string{"bd"},
+	Aliases: []string{"bd", "beads"},
 	GroupID: GroupWork,
 	Short:   "Bead management utilities",
 	Long: `Utilities for managing beads across repositories.
@@ -24,9 +24,25 @@ moving beads between repos and viewing beads by ID with automatic
 prefix-based routing.
 
 Subcommands:
-  move    Move a bead from one repository to another
-  show    Show details of a bead (routes by prefix)
-  read    Alias for show`,
+  move     Move a bead from one repository to another
+  show     Show details of a bead (routes by prefix)
+  read     Alias for show
+  claim    Claim a work bead (holder + lease)
+  unclaim  Release a claim
+  watch    Follow beads or convoys and get notified of changes
+  sync     Exchange bead changes with another town replica over SSH
+  migrate  Build the SQLite agent bead index
+  query    Find agent beads by their fields
+  history  Show the recorded mutations of an agent bead
+  graph    Show the chain of beads a bead depends on
+  depend   Record that a bead depends on other beads
+  gc       Archive the agent beads of long-dead polecats
+  bulk     Create, update and close many beads from JSONL on stdin
+  export   Write a database's beads as portable JSON
+  import   Import beads from portable JSON
+  new      Create a work bead from a town template
+  label    Show, add or remove a bead's labels
+  transfer Transfer a work bead to another rig, with its history`,
 }
 
 var beadMoveCmd = &cobra.Command{
@@ -43,7 +59,10 @@ Common prefixes: gt- (gastown), bd- (beads), hq- (headquarters)
 Examples:
   gt bead move gt-abc123 bd-     # Move gt-abc123 to beads repo as bd-*
   gt bead move hq-xyz bd-        # Move hq-xyz to beads repo
-  gt bead move bd-123 gt-        # Move bd-123 to gastown repo`,
+  gt bead move bd-123 gt-        # Move bd-123 to gastown repo
+
+To hand work to another rig with its comments and links, use
+'gt beads transfer' instead.`,
 	Args: cobra.ExactArgs(2),
 	RunE: runBeadMove,
 }
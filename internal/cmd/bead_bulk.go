@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	beadBulkDryRun bool
+	beadBulkJSON   bool
+)
+
+var beadBulkCmd = &cobra.Command{
+	Use:   "bulk",
+	Short: "Create, update and close many beads from JSONL on stdin",
+	Long: `Apply a batch of bead operations read from stdin, one JSON object per
+line, instead of driving beads one at a time.
+
+Operations:
+  {"op":"create","title":"...","type":"task","priority":2,"description":"...","parent":"gt-epic","labels":["area:auth"]}
+  {"op":"update","id":"gt-abc","status":"open","assignee":"","priority":1,"title":"...",
+   "description":"...","add_labels":["x"],"remove_labels":["y"],"labels":["replaces","all"]}
+  {"op":"close","id":"gt-abc","reason":"duplicate"}
+
+An update sets only the fields it names; "assignee":"" unassigns. Blank
+lines and lines starting with # are skipped.
+
+The whole batch is checked before anything is written: an invalid op, or
+an update or close of a bead that doesn't exist, rejects it. Operations are
+grouped by the database their bead lives in (creates go to the current
+directory's), and within each into as few bd calls as possible without
+reordering them: within a run of consecutive updates, one update for every
+bead getting the same change, and within a run of closes, one close per
+reason. Each call is a single transaction. If a call fails, the calls
+before it in that database are undone: beads the batch created are
+deleted, and beads it updated or closed are put back as they were.
+
+Examples:
+  # Requeue every bead a dead polecat held
+  bd list --assignee gastown/polecats/nux --json | \
+    jq -c '.[] | {op:"update", id, status:"open", assignee:""}' | gt beads bulk
+
+  gt beads bulk --dry-run < retag.jsonl`,
+	Args: cobra.NoArgs,
+	RunE: runBeadBulk,
+}
+
+func init() {
+	beadBulkCmd.Flags().BoolVar(&beadBulkDryRun, "dry-run", false, "Check the batch and show the bd calls it would make")
+	beadBulkCmd.Flags().BoolVar(&beadBulkJSON, "json", false, "Output as JSON")
+	beadCmd.AddCommand(beadBulkCmd)
+}
+
+// beadBulkBatch is the part of a bulk batch for one database.
+type beadBulkBatch struct {
+	Dir    string            `json:"database"`
+	Result *beads.BulkResult `json:"result,omitempty"`
+	Error  string            `json:"error,omitempty"`
+
+	ops []beads.BulkOp
+}
+
+func runBeadBulk(cmd *cobra.Command, args []string) error {
+	ops, err := beads.ReadBulkOps(cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	if len(ops) == 0 {
+		return fmt.Errorf("no operations on stdin")
+	}
+	// Check the whole batch first, so errors name ops by their place in it.
+	if _, err := beads.PlanBulk(ops); err != nil {
+		return fmt.Errorf("invalid batch:\n%w", err)
+	}
+
+	var batches []*beadBulkBatch
+	byDir := make(map[string]*beadBulkBatch)
+	for _, op := range ops {
+		dir := "."
+		if op.ID != "" {
+			if resolved := resolveBeadDir(op.ID); resolved != "" {
+				dir = resolved
+			}
+		}
+		batch := byDir[dir]
+		if batch == nil {
+			batch = &beadBulkBatch{Dir: dir}
+			byDir[dir] = batch
+			batches = append(batches, batch)
+		}
+		batch.ops = append(batch.ops, op)
+	}
+
+	if beadBulkDryRun {
+		for _, batch := range batches {
+			plan, _ := beads.PlanBulk(batch.ops)
+			printBulkPlan(batch.Dir, plan)
+		}
+		return nil
+	}
+
+	var errs []error
+	for _, batch := range batches {
+		result, err := beads.New(batch.Dir).Bulk(batch.ops)
+		batch.Result = result
+		if err != nil {
+			batch.Error = err.Error()
+			errs = append(errs, fmt.Errorf("%s: %w", batch.Dir, err))
+		}
+	}
+
+	if beadBulkJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(batches); err != nil {
+			return err
+		}
+	} else {
+		for _, batch := range batches {
+			printBulkResult(batch)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func printBulkPlan(dir string, plan *beads.BulkPlan) {
+	fmt.Printf("%s\n", style.Bold.Render(dir))
+	for _, step := range plan.Steps {
+		switch {
+		case step.Create != nil:
+			fmt.Printf("  create  %q\n", step.Create.Title)
+		case step.Update != nil:
+			fmt.Printf("  update  %s %s\n", strings.Join(step.Update.IDs, " "), style.Dim.Render(strings.Join(step.Update.Options.Flags(), " ")))
+		case step.Close != nil:
+			reason := ""
+			if step.Close.Reason != "" {
+				reason = style.Dim.Render("(" + step.Close.Reason + ")")
+			}
+			fmt.Printf("  close   %s %s\n", strings.Join(step.Close.IDs, " "), reason)
+		}
+	}
+	calls := len(plan.Steps)
+	fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("%d bd call(s), not run (dry run)", calls)))
+}
+
+func printBulkResult(batch *beadBulkBatch) {
+	result := batch.Result
+	if result == nil {
+		result = &beads.BulkResult{}
+	}
+	icon := style.Success.Render("✓")
+	if batch.Error != "" {
+		icon = style.Error.Render("✗")
+	}
+	fmt.Printf("%s %s: created %d, updated %d, closed %d (%d bd call(s))\n", icon, batch.Dir,
+		len(result.Created), len(result.Updated), len(result.Closed), result.Calls)
+	if result.RolledBack {
+		fmt.Printf("    %s\n", style.Dim.Render("rolled back: none of these changes remain"))
+	} else {
+		for _, id := range result.Created {
+			fmt.Printf("    created %s\n", id)
+		}
+	}
+	if batch.Error != "" {
+		fmt.Printf("    %s\n", style.Dim.Render("stopped: "+batch.Error))
+	}
+}
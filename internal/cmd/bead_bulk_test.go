@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestBeadBulk_GroupsIntoFewCalls(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("bd stub is a shell script")
+	}
+	dir := t.TempDir()
+	binDir := filepath.Join(dir, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(dir, "bd.log")
+	writeBDStub(t, binDir, `#!/bin/sh
+echo "$*" >> "${BD_LOG}"
+[ "$1" = "--allow-stale" ] && shift
+case "$1" in
+  show) echo '[{"id":"gt-a","status":"open"},{"id":"gt-b","status":"open"},{"id":"gt-c","status":"open"}]' ;;
+  create) echo '{"id":"gt-new"}' ;;
+esac
+exit 0
+`, "")
+	t.Setenv("BD_LOG", logPath)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	cwd, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	input := `{"op":"update","id":"gt-a","status":"open","assignee":""}
+{"op":"update","id":"gt-b","status":"open","assignee":""}
+{"op":"close","id":"gt-c","reason":"duplicate"}
+{"op":"create","title":"Follow-up","type":"task"}
+`
+	beadBulkCmd.SetIn(strings.NewReader(input))
+	t.Cleanup(func() { beadBulkCmd.SetIn(nil) })
+	if err := runBeadBulk(beadBulkCmd, nil); err != nil {
+		t.Fatalf("runBeadBulk: %v", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		calls = append(calls, strings.TrimPrefix(line, "--allow-stale "))
+	}
+	want := []string{
+		"show --json gt-a gt-b gt-c",
+		"update gt-a gt-b --status=open --assignee=",
+		"close gt-c --reason=duplicate",
+		"create --json --title=Follow-up --labels=gt:task",
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("bd calls = %q, want %q", calls, want)
+	}
+	for i := range want {
+		if !strings.HasPrefix(calls[i], want[i]) {
+			t.Errorf("call %d = %q, want %q", i, calls[i], want[i])
+		}
+	}
+}
+
+func TestBeadBulk_RollsBackOnFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("bd stub is a shell script")
+	}
+	dir := t.TempDir()
+	binDir := filepath.Join(dir, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(dir, "bd.log")
+	writeBDStub(t, binDir, `#!/bin/sh
+echo "$*" >> "${BD_LOG}"
+[ "$1" = "--allow-stale" ] && shift
+case "$1" in
+  show) echo '[{"id":"gt-a","title":"A","status":"open","priority":2,"labels":["triage"]}]' ;;
+  create) echo '{"id":"gt-new"}' ;;
+  close) echo "close failed" >&2; exit 1 ;;
+esac
+exit 0
+`, "")
+	t.Setenv("BD_LOG", logPath)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	cwd, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	input := `{"op":"create","title":"Follow-up","type":"task"}
+{"op":"update","id":"gt-a","add_labels":["area:auth"]}
+{"op":"close","id":"gt-a"}
+`
+	beadBulkCmd.SetIn(strings.NewReader(input))
+	t.Cleanup(func() { beadBulkCmd.SetIn(nil) })
+	err := runBeadBulk(beadBulkCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "rolled back") {
+		t.Fatalf("runBeadBulk = %v, want a rolled back failure", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := string(data)
+	// The created bead is deleted, and gt-a is put back as it was read.
+	for _, want := range []string{"delete gt-new --hard --force", "update gt-a --title=A"} {
+		if !strings.Contains(log, want) {
+			t.Errorf("bd calls missing %q:\n%s", want, log)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	beadClaimAs    string
+	beadClaimLease time.Duration
+	beadClaimForce bool
+)
+
+var beadClaimCmd = &cobra.Command{
+	Use:   "claim <bead-id>",
+	Short: "Claim a work bead before starting on it",
+	Long: `Atomically claim a work bead for an agent.
+
+The claim records the holder as assignee and a lease in the bead's
+description. Claiming a bead someone else holds under a live lease fails,
+so two agents working from the same (possibly stale) list can't both start
+it. Claiming a bead you already hold renews the lease. Expired leases are
+free to take. gt sling claims beads for their target automatically.
+
+The holder defaults to the current agent's address.
+
+Examples:
+  gt bead claim gt-abc123
+  gt bead claim gt-abc123 --lease 4h
+  gt bead claim gt-abc123 --as gastown/polecats/Toast`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadClaim,
+}
+
+var beadUnclaimCmd = &cobra.Command{
+	Use:   "unclaim <bead-id>",
+	Short: "Release a claim on a work bead",
+	Long: `Release a claim on a work bead.
+
+Clears the claim and assignee; an in-progress or hooked bead goes back to
+open. Only the holder can release a claim unless --force is given.
+
+Examples:
+  gt bead unclaim gt-abc123
+  gt bead unclaim gt-abc123 --force   # Release someone else's claim`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadUnclaim,
+}
+
+func init() {
+	beadClaimCmd.Flags().StringVar(&beadClaimAs, "as", "", "Claim holder (default: current agent)")
+	beadClaimCmd.Flags().DurationVar(&beadClaimLease, "lease", beads.DefaultClaimLease, "How long the claim holds without renewal")
+	beadUnclaimCmd.Flags().StringVar(&beadClaimAs, "as", "", "Claim holder (default: current agent)")
+	beadUnclaimCmd.Flags().BoolVarP(&beadClaimForce, "force", "f", false, "Release even if another agent holds the claim")
+	beadCmd.AddCommand(beadClaimCmd)
+	beadCmd.AddCommand(beadUnclaimCmd)
+}
+
+func runBeadClaim(cmd *cobra.Command, args []string) error {
+	beadID := args[0]
+	holder := beadClaimHolder()
+
+	claim, err := beads.New(".").Claim(beadID, holder, beadClaimLease)
+	var claimed *beads.AlreadyClaimedError
+	if errors.As(err, &claimed) {
+		return fmt.Errorf("%w\nwait for the lease to lapse, or pick other work", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Claimed %s for %s (lease until %s)\n", style.Success.Render("✓"),
+		beadID, holder, claim.LeaseUntil.Local().Format(time.Kitchen))
+	return nil
+}
+
+func runBeadUnclaim(cmd *cobra.Command, args []string) error {
+	beadID := args[0]
+	holder := beadClaimHolder()
+
+	err := beads.New(".").Unclaim(beadID, holder, beadClaimForce)
+	if errors.Is(err, beads.ErrNotClaimed) {
+		fmt.Printf("%s %s is not claimed\n", style.Dim.Render("○"), beadID)
+		return nil
+	}
+	var claimed *beads.AlreadyClaimedError
+	if errors.As(err, &claimed) {
+		return fmt.Errorf("%w\nuse --force to release it anyway", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Released claim on %s\n", style.Success.Render("✓"), beadID)
+	return nil
+}
+
+// beadClaimHolder returns the --as holder or the current agent's address.
+func beadClaimHolder() string {
+	if beadClaimAs != "" {
+		return beadClaimAs
+	}
+	return detectSender()
+}
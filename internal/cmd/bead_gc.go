@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadGCDryRun     bool
+	beadGCRetainDays int
+)
+
+var beadGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Archive the agent beads of long-dead polecats",
+	Long: `Archive and delete the agent beads of polecats that were nuked (or
+closed) and haven't been updated within the retention, in every beads
+database in the town (town + per-rig).
+
+Nuking a polecat keeps its agent bead for reuse, so beads of polecats that
+never come back pile up and slow every agent listing. Other roles' beads,
+and any bead with work hooked, are never collected.
+
+Each run writes the collected beads, with their agent history, to a
+gzipped JSONL file in .beads/` + beads.AgentArchiveDir + `/ before deleting them.
+
+The retention defaults to the daemon's agent_gc.retain_days, or 30 days.
+With the agent_gc patrol enabled, the daemon runs this periodically.
+
+Examples:
+  gt beads gc --dry-run          # List what would be collected
+  gt beads gc                    # Archive and delete
+  gt beads gc --retain-days 7`,
+	Args: cobra.NoArgs,
+	RunE: runBeadGC,
+}
+
+func init() {
+	beadGCCmd.Flags().BoolVar(&beadGCDryRun, "dry-run", false, "List the agent beads that would be collected without changing anything")
+	beadGCCmd.Flags().IntVar(&beadGCRetainDays, "retain-days", 0, "Keep dead polecats' agent beads this many days after their last update")
+	beadCmd.AddCommand(beadGCCmd)
+}
+
+func runBeadGC(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	targets, err := migrationTargets(townRoot)
+	if err != nil {
+		return err
+	}
+	retention := daemon.AgentGCRetention(daemon.LoadPatrolConfig(townRoot))
+	if beadGCRetainDays > 0 {
+		retention = time.Duration(beadGCRetainDays) * 24 * time.Hour
+	}
+
+	failed, total := 0, 0
+	for _, target := range targets {
+		b := beads.NewWithBeadsDir(filepath.Dir(target.beadsDir), target.beadsDir)
+		collected, archive, err := b.GCAgentBeads(retention, beadGCDryRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  warning: %s: %v\n", target.name, err)
+			failed++
+		}
+		if len(collected) == 0 {
+			continue
+		}
+		total += len(collected)
+		for _, issue := range collected {
+			fmt.Printf("  %s %s %s\n", target.name, issue.ID, style.Dim.Render("(last updated "+issue.UpdatedAt+")"))
+		}
+		if archive != "" {
+			fmt.Printf("  %s %s: archived %d agent bead(s) to %s\n", style.Success.Render("✓"), target.name, len(collected), archive)
+		}
+	}
+
+	switch {
+	case total == 0:
+		fmt.Printf("No agent beads older than %s to collect\n", formatRetention(retention))
+	case beadGCDryRun:
+		fmt.Printf("\nWould collect %d agent bead(s) (dry run)\n", total)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d database(s) failed", failed, len(targets))
+	}
+	return nil
+}
+
+// formatRetention renders a retention in days.
+func formatRetention(d time.Duration) string {
+	return fmt.Sprintf("%d days", int(d/(24*time.Hour)))
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	beadGraphDepth int
+	beadGraphJSON  bool
+
+	beadDependRemove bool
+)
+
+var beadGraphCmd = &cobra.Command{
+	Use:   "graph <bead-id>",
+	Short: "Show the chain of beads a bead depends on",
+	Long: `Show the depends_on graph below a work bead as a tree: every bead it
+depends on, what those depend on, and so on, with each bead's state.
+
+A bead is blocked while any bead below it isn't closed. Blocked beads
+can't be slung to an agent (without --force), and the refinery won't
+merge their work before their dependencies'. The beads listed under
+"Work next" are the unfinished ones nothing blocks: finishing them
+unblocks the chain.
+
+A bead reached by more than one path is expanded once and marked "(see
+above)" after that.
+
+Examples:
+  gt beads graph gt-abc
+  gt beads graph gt-abc --depth 2
+  gt beads graph gt-abc --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadGraph,
+}
+
+var beadDependCmd = &cobra.Command{
+	Use:   "depend <bead-id> <depends-on-id>...",
+	Short: "Record that a bead depends on other beads",
+	Long: `Record that a work bead depends on other beads: it is blocked until
+they are closed. A dependency that would make a bead depend on itself,
+directly or through others, is refused.
+
+Examples:
+  gt beads depend gt-api gt-schema gt-auth
+  gt beads depend gt-api gt-auth --remove`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runBeadDepend,
+}
+
+func init() {
+	beadGraphCmd.Flags().IntVar(&beadGraphDepth, "depth", 0, "Only follow dependencies this many levels deep (0 = all)")
+	beadGraphCmd.Flags().BoolVar(&beadGraphJSON, "json", false, "Output as JSON")
+	beadDependCmd.Flags().BoolVar(&beadDependRemove, "remove", false, "Remove the dependencies instead")
+	beadCmd.AddCommand(beadGraphCmd)
+	beadCmd.AddCommand(beadDependCmd)
+}
+
+// beadGraphJSONNode is a bead in 'gt beads graph --json'.
+type beadGraphJSONNode struct {
+	ID        string               `json:"id"`
+	Title     string               `json:"title"`
+	Status    string               `json:"status"`
+	Blocked   bool                 `json:"blocked"`
+	Seen      bool                 `json:"seen,omitempty"`
+	Truncated bool                 `json:"truncated,omitempty"`
+	DependsOn []*beadGraphJSONNode `json:"depends_on,omitempty"`
+}
+
+func toBeadGraphJSON(node *beads.DependencyNode) *beadGraphJSONNode {
+	out := &beadGraphJSONNode{ID: node.ID, Title: node.Title, Status: node.Status, Blocked: node.Blocked,
+		Seen: node.Seen, Truncated: node.Truncated}
+	for _, child := range node.DependsOn {
+		out.DependsOn = append(out.DependsOn, toBeadGraphJSON(child))
+	}
+	return out
+}
+
+func runBeadGraph(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	graph, err := beads.New(resolveBeadDir(id)).DependencyGraph(id, beadGraphDepth)
+	if err != nil {
+		return err
+	}
+
+	if beadGraphJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(toBeadGraphJSON(graph))
+	}
+
+	fmt.Printf("%s %s\n", beadGraphLabel(graph), style.Dim.Render(graph.Title))
+	printBeadGraph(graph, "")
+	if len(graph.DependsOn) == 0 {
+		fmt.Printf("   %s\n", style.Dim.Render("(no dependencies)"))
+		return nil
+	}
+
+	if next := graph.Frontier(); len(next) > 0 {
+		ids := make([]string, len(next))
+		for i, node := range next {
+			ids[i] = node.ID
+		}
+		fmt.Printf("\nWork next: %s\n", strings.Join(ids, ", "))
+	}
+	fmt.Printf("\n   %s done  %s in_progress  %s ready  %s blocked\n",
+		style.Bold.Render("✓"), style.Bold.Render("⧖"), style.Bold.Render("○"), style.Dim.Render("◌"))
+	return nil
+}
+
+// printBeadGraph prints the dependencies of node as a tree below it.
+func printBeadGraph(node *beads.DependencyNode, prefix string) {
+	for i, child := range node.DependsOn {
+		connector, indent := "├── ", "│   "
+		if i == len(node.DependsOn)-1 {
+			connector, indent = "└── ", "    "
+		}
+		note := style.Dim.Render(child.Title)
+		switch {
+		case child.Seen:
+			note = style.Dim.Render("(see above)")
+		case child.Truncated && len(child.DependsOn) == 0:
+			note += style.Dim.Render(" …")
+		}
+		fmt.Printf("%s%s%s %s\n", prefix, connector, beadGraphLabel(child), note)
+		printBeadGraph(child, prefix+indent)
+	}
+}
+
+// beadGraphLabel renders a bead's ID with its state icon.
+func beadGraphLabel(node *beads.DependencyNode) string {
+	var icon string
+	switch {
+	case node.Done():
+		icon = style.Bold.Render("✓")
+	case node.Blocked:
+		icon = style.Dim.Render("◌")
+	case node.Status == "in_progress" || node.Status == "hooked":
+		icon = style.Bold.Render("⧖")
+	default:
+		icon = style.Bold.Render("○")
+	}
+	return icon + " " + node.ID
+}
+
+func runBeadDepend(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	b := beads.New(resolveBeadDir(id))
+	for _, dependsOn := range args[1:] {
+		if beadDependRemove {
+			if err := b.RemoveDependency(id, dependsOn); err != nil {
+				return fmt.Errorf("removing %s → %s: %w", id, dependsOn, err)
+			}
+			fmt.Printf("%s %s no longer depends on %s\n", style.Bold.Render("✓"), id, dependsOn)
+			continue
+		}
+		if err := b.AddDependencyChecked(id, dependsOn); err != nil {
+			return fmt.Errorf("adding %s → %s: %w", id, dependsOn, err)
+		}
+		fmt.Printf("%s %s depends on %s\n", style.Bold.Render("✓"), id, dependsOn)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadHistoryField string
+	beadHistoryLimit int
+	beadHistoryJSON  bool
+)
+
+var beadHistoryCmd = &cobra.Command{
+	Use:   "history <agent-bead-id>",
+	Short: "Show the recorded mutations of an agent bead",
+	Long: `Show every recorded write to an agent bead's fields, oldest first:
+when it happened, who made it (BD_ACTOR), which subsystem (witness,
+polecat, or the gt command that ran), and each field's old and new value.
+
+Use it to reconstruct how an agent got where it is: why a polecat was
+nuked, or how its failure_count reached 7.
+
+The history is append-only and kept per beads database in
+.beads/agent-history.jsonl; every database in the town is searched.
+Writes that changed nothing aren't recorded. A "?" old value means the
+write set the field without reading it first (hook and agent_state).
+
+Examples:
+  gt beads history gt-gastown-polecat-nux
+  gt beads history gt-gastown-polecat-nux --field failure_count
+  gt beads history gt-gastown-polecat-nux -n 20 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadHistory,
+}
+
+func init() {
+	beadHistoryCmd.Flags().StringVar(&beadHistoryField, "field", "", "Only show writes that changed this field")
+	beadHistoryCmd.Flags().IntVarP(&beadHistoryLimit, "limit", "n", 0, "Only show the most recent N writes")
+	beadHistoryCmd.Flags().BoolVar(&beadHistoryJSON, "json", false, "Output as JSON")
+	beadCmd.AddCommand(beadHistoryCmd)
+}
+
+func runBeadHistory(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	targets, err := migrationTargets(townRoot)
+	if err != nil {
+		return err
+	}
+
+	entries := []beads.AgentHistoryEntry{} // Empty slice (not nil) for JSON
+	for _, target := range targets {
+		b := beads.NewWithBeadsDir(filepath.Dir(target.beadsDir), target.beadsDir)
+		found, err := b.AgentHistory(id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  warning: %s: %v\n", target.name, err)
+			continue
+		}
+		entries = append(entries, filterAgentHistory(found, beadHistoryField)...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Timestamp < entries[j].Timestamp })
+	if beadHistoryLimit > 0 && len(entries) > beadHistoryLimit {
+		entries = entries[len(entries)-beadHistoryLimit:]
+	}
+
+	if beadHistoryJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("No recorded history for %s.\n", id)
+		return nil
+	}
+	for _, entry := range entries {
+		who := dashIfEmpty(entry.Actor)
+		if entry.Subsystem != "" {
+			who += " (" + entry.Subsystem + ")"
+		}
+		fmt.Printf("%s  %s  %s", entry.Timestamp, style.Bold.Render(entry.Operation), who)
+		if entry.Revision > 0 {
+			fmt.Printf("  %s", style.Dim.Render(fmt.Sprintf("rev %d", entry.Revision)))
+		}
+		fmt.Println()
+		for _, change := range entry.Changes {
+			old := "?"
+			if change.Old != nil {
+				old = quoteIfEmpty(*change.Old)
+			}
+			fmt.Printf("    %s: %s → %s\n", change.Field, old, quoteIfEmpty(change.New))
+		}
+	}
+	return nil
+}
+
+// filterAgentHistory keeps the entries that changed field; all of them if
+// field is empty.
+func filterAgentHistory(entries []beads.AgentHistoryEntry, field string) []beads.AgentHistoryEntry {
+	if field == "" {
+		return entries
+	}
+	var kept []beads.AgentHistoryEntry
+	for _, entry := range entries {
+		for _, change := range entry.Changes {
+			if change.Field == field {
+				kept = append(kept, entry)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// quoteIfEmpty shows an unset field value as "" in history output.
+func quoteIfEmpty(s string) string {
+	if s == "" {
+		return `""`
+	}
+	return s
+}
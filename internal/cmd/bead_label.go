@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	beadLabelRemove []string
+	beadLabelJSON   bool
+)
+
+var beadLabelCmd = &cobra.Command{
+	Use:   "label <bead-id> [label...] [--remove label...]",
+	Short: "Show, add or remove a bead's labels",
+	Long: `Show a work bead's user labels, or add and remove them.
+
+Labels are free-form tags such as area:refinery, flaky or needs-human.
+They are stored with the bead, so every command reading it sees them:
+
+  gt ready --label area:refinery     Only ready work with the label
+  gt ready --label '!needs-human'    Only ready work without it
+  gt mq list --label 'area:*'        Label patterns end in *
+
+A rig's settings can claim labels for the rigs that should do the work
+(work_labels), and gt sling refuses to send a bead to a rig that doesn't
+claim its labels (without --force). The merge queue weights labels set
+in merge_queue.label_weights when scoring merge requests, which carry
+their source bead's labels.
+
+Labels starting with ` + beads.SystemLabelPrefix + ` are managed by Gas Town and aren't shown or set
+here.
+
+Examples:
+  gt beads label gt-abc
+  gt beads label gt-abc area:refinery flaky
+  gt beads label gt-abc needs-human --remove flaky`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runBeadLabel,
+}
+
+func init() {
+	beadLabelCmd.Flags().StringArrayVar(&beadLabelRemove, "remove", nil, "Label to remove, can be repeated")
+	beadLabelCmd.Flags().BoolVar(&beadLabelJSON, "json", false, "Output as JSON")
+	beadCmd.AddCommand(beadLabelCmd)
+}
+
+func runBeadLabel(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	add, remove := args[1:], beadLabelRemove
+	for _, label := range append(append([]string{}, add...), remove...) {
+		if err := beads.ValidateLabel(label); err != nil {
+			return err
+		}
+	}
+
+	b := beads.New(resolveBeadDir(id))
+	if len(add) > 0 || len(remove) > 0 {
+		if err := b.Update(id, beads.UpdateOptions{AddLabels: add, RemoveLabels: remove}); err != nil {
+			return fmt.Errorf("updating labels of %s: %w", id, err)
+		}
+	}
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+	labels := beads.UserLabels(issue.Labels)
+
+	if beadLabelJSON {
+		out := struct {
+			ID     string   `json:"id"`
+			Labels []string `json:"labels"`
+		}{ID: issue.ID, Labels: labels}
+		if out.Labels == nil {
+			out.Labels = []string{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+	if len(labels) == 0 {
+		fmt.Printf("%s %s\n", issue.ID, style.Dim.Render("(no labels)"))
+		return nil
+	}
+	fmt.Printf("%s %s\n", issue.ID, strings.Join(labels, ", "))
+	return nil
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var beadMigrateRemove bool
+
+var beadMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Build the SQLite agent bead index for every beads database",
+	Long: `Build (or rebuild) the SQLite index of agent beads in every beads
+database in the town (town + per-rig).
+
+Listing agent beads through bd and matching their descriptions is slow on
+large towns, and Witness sweeps and 'gt status' do it on every run. With an
+index (` + beads.AgentIndexFile + ` in the .beads directory), those lookups are
+answered from SQLite, indexed on role_type, rig, circuit_state and
+hook_bead. bd stays the source of truth: the index records when the
+database was last written as it was built, and after any write (through
+gt or bd directly) lookups go back to bd until gt next lists every agent
+and rebuilds the index from the answer.
+
+The index needs the sqlite3 CLI. Run 'gt migrate-agent-fields' first so
+every agent bead's fields can be indexed.
+
+Examples:
+  gt beads migrate            # Build or rebuild the indexes
+  gt beads migrate --remove   # Remove them; reads go back to bd`,
+	Args: cobra.NoArgs,
+	RunE: runBeadMigrate,
+}
+
+func init() {
+	beadMigrateCmd.Flags().BoolVar(&beadMigrateRemove, "remove", false, "Remove the indexes instead of building them")
+	beadCmd.AddCommand(beadMigrateCmd)
+}
+
+func runBeadMigrate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	targets, err := migrationTargets(townRoot)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, target := range targets {
+		b := beads.NewWithBeadsDir(filepath.Dir(target.beadsDir), target.beadsDir)
+		if beadMigrateRemove {
+			if err := b.RemoveAgentIndex(); err != nil {
+				fmt.Fprintf(os.Stderr, "  warning: %s: removing agent index: %v\n", target.name, err)
+				failed++
+				continue
+			}
+			fmt.Printf("  %s %s: agent index removed\n", style.Success.Render("✓"), target.name)
+			continue
+		}
+
+		n, err := b.BuildAgentIndex()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  warning: %s: building agent index: %v\n", target.name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("  %s %s: indexed %d agent bead(s)\n", style.Success.Render("✓"), target.name, n)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d database(s) failed", failed, len(targets))
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadNewTemplate string
+	beadNewVars     []string
+	beadNewTitle    string
+	beadNewPriority int
+	beadNewParent   string
+	beadNewRig      string
+	beadNewDryRun   bool
+	beadNewList     bool
+	beadNewJSON     bool
+)
+
+var beadNewCmd = &cobra.Command{
+	Use:   "new --template <name> [--var key=value...]",
+	Short: "Create a work bead from a town template",
+	Long: `Create a work bead from one of the town's templates, so work of a kind
+is always structured the same way and polecats can parse it reliably.
+
+Templates are TOML files in <town>/.beads/` + beads.TemplatesDir + `/<name>.toml:
+
+  about = "Fix a reported bug"
+  title = "Fix: {{summary}}"
+  type = "bug"
+  priority = 1
+  labels = ["bugfix"]
+  description = """
+  ## Problem
+  {{summary}} in {{repo}}.
+  """
+  acceptance = ["A regression test covers the bug", "CI is green"]
+
+  [vars]
+  branch = "main"            # optional, with a default
+  [vars.repo]
+  required = true
+  [vars.summary]
+  description = "What is broken"
+  required = true
+
+Every {{var}} must be declared under [vars]. The acceptance criteria
+become an "## Acceptance criteria" section of the description, and the
+bead is labeled ` + beads.TemplateLabelPrefix + `<name>. The bead is created in the current
+directory's database, or the rig's with --rig.
+
+Examples:
+  gt beads new --list
+  gt beads new --template bugfix --var repo=gastown --var summary="Login fails"
+  gt beads new -t bugfix --var repo=gastown --var summary=x --priority 0 --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runBeadNew,
+}
+
+func init() {
+	beadNewCmd.Flags().StringVarP(&beadNewTemplate, "template", "t", "", "Template to create the bead from")
+	beadNewCmd.Flags().StringArrayVar(&beadNewVars, "var", nil, "Template variable (key=value), can be repeated")
+	beadNewCmd.Flags().StringVar(&beadNewTitle, "title", "", "Use this title instead of the template's")
+	beadNewCmd.Flags().IntVarP(&beadNewPriority, "priority", "p", -1, "Use this priority (0-4) instead of the template's")
+	beadNewCmd.Flags().StringVar(&beadNewParent, "parent", "", "Parent bead (e.g. an epic)")
+	beadNewCmd.Flags().StringVar(&beadNewRig, "rig", "", "Create the bead in this rig's database")
+	beadNewCmd.Flags().BoolVar(&beadNewDryRun, "dry-run", false, "Show the bead without creating it")
+	beadNewCmd.Flags().BoolVar(&beadNewList, "list", false, "List the town's templates and their variables")
+	beadNewCmd.Flags().BoolVar(&beadNewJSON, "json", false, "Output as JSON")
+	beadCmd.AddCommand(beadNewCmd)
+}
+
+func runBeadNew(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	if beadNewList {
+		return listBeadTemplates(townRoot)
+	}
+	if beadNewTemplate == "" {
+		return fmt.Errorf("--template is required (see 'gt beads new --list')")
+	}
+
+	tmpl, err := beads.LoadTemplate(townRoot, beadNewTemplate)
+	if err != nil {
+		return err
+	}
+	vars, err := parseTemplateVars(beadNewVars)
+	if err != nil {
+		return err
+	}
+	opts, err := tmpl.Render(vars)
+	if err != nil {
+		return err
+	}
+	if beadNewTitle != "" {
+		opts.Title = beadNewTitle
+	}
+	if opts.Title == "" {
+		return fmt.Errorf("template %s has no title; pass --title", tmpl.Name)
+	}
+	if cmd.Flags().Changed("priority") {
+		if beadNewPriority < 0 || beadNewPriority > 4 {
+			return fmt.Errorf("priority %d is out of range (0-4)", beadNewPriority)
+		}
+		opts.Priority = beadNewPriority
+	}
+	opts.Parent = beadNewParent
+
+	if beadNewDryRun {
+		return printBeadNew(nil, opts)
+	}
+
+	dir := "."
+	if beadNewRig != "" {
+		if dir, _, err = portableDatabase(beadNewRig); err != nil {
+			return err
+		}
+	}
+	issue, err := beads.New(dir).Create(opts)
+	if err != nil {
+		return err
+	}
+	return printBeadNew(issue, opts)
+}
+
+// parseTemplateVars parses --var key=value flags.
+func parseTemplateVars(flags []string) (map[string]string, error) {
+	vars := make(map[string]string, len(flags))
+	for _, flag := range flags {
+		key, value, ok := strings.Cut(flag, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid --var %q (want key=value)", flag)
+		}
+		vars[strings.TrimSpace(key)] = value
+	}
+	return vars, nil
+}
+
+// printBeadNew reports the bead made from a template, or with --dry-run
+// (issue nil) the bead that would be.
+func printBeadNew(issue *beads.Issue, opts beads.CreateOptions) error {
+	if beadNewJSON {
+		out := struct {
+			ID          string   `json:"id,omitempty"`
+			Title       string   `json:"title"`
+			Priority    int      `json:"priority"`
+			Labels      []string `json:"labels"`
+			Description string   `json:"description"`
+			DryRun      bool     `json:"dry_run,omitempty"`
+		}{Title: opts.Title, Priority: opts.Priority, Labels: opts.Labels, Description: opts.Description, DryRun: issue == nil}
+		if issue != nil {
+			out.ID, out.Priority = issue.ID, issue.Priority
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if issue != nil {
+		fmt.Printf("%s Created %s: %s %s\n", style.Success.Render("✓"), issue.ID, issue.Title, style.Dim.Render("(template "+beadNewTemplate+")"))
+		return nil
+	}
+	priority := "default"
+	if opts.Priority >= 0 {
+		priority = fmt.Sprintf("P%d", opts.Priority)
+	}
+	fmt.Printf("%s %s\n", style.Bold.Render("Title:"), opts.Title)
+	fmt.Printf("%s %s\n", style.Bold.Render("Priority:"), priority)
+	fmt.Printf("%s %s\n", style.Bold.Render("Labels:"), strings.Join(opts.Labels, ", "))
+	if opts.Parent != "" {
+		fmt.Printf("%s %s\n", style.Bold.Render("Parent:"), opts.Parent)
+	}
+	if opts.Description != "" {
+		fmt.Printf("\n%s\n", opts.Description)
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("(dry run: nothing created)"))
+	return nil
+}
+
+func listBeadTemplates(townRoot string) error {
+	templates, err := beads.ListTemplates(townRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  warning: %v\n", err)
+	}
+	if beadNewJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(templates)
+	}
+	if len(templates) == 0 {
+		fmt.Printf("No templates in %s\n", filepath.Join(townRoot, ".beads", beads.TemplatesDir))
+		return nil
+	}
+	for _, tmpl := range templates {
+		fmt.Printf("%s %s\n", style.Bold.Render(tmpl.Name), style.Dim.Render(tmpl.About))
+		for _, name := range tmpl.VarNames() {
+			v := tmpl.Vars[name]
+			detail := v.Description
+			switch {
+			case v.Required:
+				detail = strings.TrimSpace("(required) " + detail)
+			case v.Default != "":
+				detail = strings.TrimSpace(fmt.Sprintf("(default %q) %s", v.Default, detail))
+			}
+			fmt.Printf("  --var %s=...  %s\n", name, style.Dim.Render(detail))
+		}
+	}
+	return nil
+}
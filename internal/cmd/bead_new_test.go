@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTemplateVars(t *testing.T) {
+	got, err := parseTemplateVars([]string{"repo=gastown", "summary=a=b", "empty="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"repo": "gastown", "summary": "a=b", "empty": ""}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTemplateVars = %v, want %v", got, want)
+	}
+
+	for _, flag := range []string{"repo", "=gastown"} {
+		if _, err := parseTemplateVars([]string{flag}); err == nil {
+			t.Errorf("parseTemplateVars(%q) should fail", flag)
+		}
+	}
+}
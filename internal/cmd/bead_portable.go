@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadExportRig    string
+	beadExportStatus string
+	beadExportOutput string
+
+	beadImportRig         string
+	beadImportPrefixes    []string
+	beadImportOnCollision string
+	beadImportDryRun      bool
+	beadImportJSON        bool
+)
+
+var beadExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write a database's beads as portable JSON",
+	Long: `Write the beads of the town's database, or a rig's, as a portable JSON
+snapshot: each bead's fields, labels, parent and dependencies. Wisps are
+left out.
+
+Use it to back a town up, clone or merge one into another with 'gt beads
+import', or attach a reproducible beads snapshot to a bug report.
+
+Examples:
+  gt beads export --rig gastown > beads.json
+  gt beads export --status open -o town-open.json`,
+	Args: cobra.NoArgs,
+	RunE: runBeadExport,
+}
+
+var beadImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import beads from portable JSON",
+	Long: `Import a snapshot written by 'gt beads export' (from file, or stdin)
+into the town's database, or a rig's.
+
+Beads keep their IDs, with prefixes remapped by --prefix: "gt=xy" renames
+gt-* IDs to xy-*, and a bare "xy" renames the snapshot's main prefix.
+Parents and dependencies are rewritten to match.
+
+A bead whose ID already exists is handled by --on-collision:
+  skip       Keep the existing bead; dependencies on it point there (default)
+  rename     Import it under a new ID bd assigns
+  overwrite  Replace the existing bead's fields with the snapshot's
+
+Examples:
+  gt beads import beads.json --rig gastown --dry-run
+  gt beads import beads.json --rig gastown --prefix xy --on-collision rename
+  gt beads export --rig old | gt beads import --rig new --prefix old=new`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBeadImport,
+}
+
+func init() {
+	beadExportCmd.Flags().StringVar(&beadExportRig, "rig", "", "Export this rig's beads (default: the town's)")
+	beadExportCmd.Flags().StringVar(&beadExportStatus, "status", "all", "Only export beads with this status")
+	beadExportCmd.Flags().StringVarP(&beadExportOutput, "output", "o", "", "Write to this file instead of stdout")
+	beadImportCmd.Flags().StringVar(&beadImportRig, "rig", "", "Import into this rig's database (default: the town's)")
+	beadImportCmd.Flags().StringArrayVar(&beadImportPrefixes, "prefix", nil, "Remap an ID prefix: old=new, or new for the snapshot's prefix (repeatable)")
+	beadImportCmd.Flags().StringVar(&beadImportOnCollision, "on-collision", beads.ImportSkip, "What to do with beads whose ID exists: skip, rename or overwrite")
+	beadImportCmd.Flags().BoolVar(&beadImportDryRun, "dry-run", false, "Show what would be imported without writing")
+	beadImportCmd.Flags().BoolVar(&beadImportJSON, "json", false, "Output as JSON")
+	beadCmd.AddCommand(beadExportCmd)
+	beadCmd.AddCommand(beadImportCmd)
+}
+
+// portableDatabase returns the directory of the database --rig names, and
+// its name for a snapshot's source.
+func portableDatabase(rigName string) (string, string, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", "", err
+	}
+	if rigName == "" {
+		return townRoot, "town", nil
+	}
+	dir := filepath.Join(townRoot, rigName)
+	if _, err := os.Stat(dir); err != nil {
+		return "", "", fmt.Errorf("rig %q not found", rigName)
+	}
+	return dir, rigName, nil
+}
+
+func runBeadExport(cmd *cobra.Command, args []string) error {
+	dir, source, err := portableDatabase(beadExportRig)
+	if err != nil {
+		return err
+	}
+	snap, err := beads.New(dir).ExportPortable(source, beadExportStatus)
+	if err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	if beadExportOutput != "" {
+		f, err := os.Create(beadExportOutput)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(snap); err != nil {
+		return err
+	}
+	if beadExportOutput != "" {
+		fmt.Printf("%s Exported %d bead(s) from %s to %s\n", style.Success.Render("✓"), len(snap.Beads), source, beadExportOutput)
+	}
+	return nil
+}
+
+// parsePrefixMap parses --prefix flags into a prefix map; a bare new
+// prefix renames the snapshot's.
+func parsePrefixMap(flags []string, snapshotPrefix string) (map[string]string, error) {
+	normalize := func(prefix string) string {
+		return strings.TrimSuffix(strings.TrimSpace(prefix), "-") + "-"
+	}
+	prefixMap := make(map[string]string)
+	for _, flag := range flags {
+		from, to, ok := strings.Cut(flag, "=")
+		if !ok {
+			if snapshotPrefix == "" {
+				return nil, fmt.Errorf("--prefix %s: the snapshot has no prefix to rename; use old=new", flag)
+			}
+			from, to = snapshotPrefix, flag
+		}
+		if strings.Trim(from, "- ") == "" || strings.Trim(to, "- ") == "" {
+			return nil, fmt.Errorf("invalid --prefix %q", flag)
+		}
+		prefixMap[normalize(from)] = normalize(to)
+	}
+	return prefixMap, nil
+}
+
+func runBeadImport(cmd *cobra.Command, args []string) error {
+	in := cmd.InOrStdin()
+	if len(args) == 1 && args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	snap, err := beads.ReadPortable(in)
+	if err != nil {
+		return err
+	}
+	prefixMap, err := parsePrefixMap(beadImportPrefixes, snap.Prefix)
+	if err != nil {
+		return err
+	}
+	dir, target, err := portableDatabase(beadImportRig)
+	if err != nil {
+		return err
+	}
+
+	result, err := beads.New(dir).ImportPortable(snap, beads.ImportOptions{
+		PrefixMap:   prefixMap,
+		OnCollision: beadImportOnCollision,
+		DryRun:      beadImportDryRun,
+	})
+	if result == nil {
+		return err
+	}
+
+	if beadImportJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if encErr := enc.Encode(result); encErr != nil {
+			return encErr
+		}
+		return err
+	}
+
+	counts := make(map[string]int)
+	for _, action := range result.Actions {
+		counts[action.Action]++
+		if action.Action == beads.ImportSkip {
+			continue
+		}
+		newID := action.NewID
+		if newID == "" {
+			newID = "(new ID)"
+		}
+		fmt.Printf("  %-9s %s → %s\n", action.Action, action.OldID, newID)
+	}
+	for _, warning := range result.Warnings {
+		fmt.Printf("  %s %s\n", style.Warning.Render("⚠"), warning)
+	}
+	verb := "Imported"
+	if beadImportDryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("%s %d bead(s) into %s: %d created, %d renamed, %d overwritten, %d skipped\n", verb,
+		len(result.Actions)-counts[beads.ImportSkip], target, counts["create"], counts[beads.ImportRename],
+		counts[beads.ImportOverwrite], counts[beads.ImportSkip])
+	return err
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePrefixMap(t *testing.T) {
+	got, err := parsePrefixMap([]string{"xy", "hq-=town-"}, "gt-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"gt-": "xy-", "hq-": "town-"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePrefixMap = %v, want %v", got, want)
+	}
+
+	for _, flags := range [][]string{{"=xy"}, {"gt="}} {
+		if _, err := parsePrefixMap(flags, "gt-"); err == nil {
+			t.Errorf("parsePrefixMap(%q) should fail", flags)
+		}
+	}
+	if _, err := parsePrefixMap([]string{"xy"}, ""); err == nil {
+		t.Error("a bare prefix needs the snapshot's prefix")
+	}
+}
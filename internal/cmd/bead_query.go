@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadQueryDB   string
+	beadQueryJSON bool
+)
+
+var beadQueryCmd = &cobra.Command{
+	Use:   "query <expression>...",
+	Short: "Find agent beads by their fields",
+	Long: `Find the agent beads whose fields match a filter expression, across
+every beads database in the town (town + per-rig).
+
+An expression is a list of terms, optionally joined by "and"; an agent
+must match them all. Each term is a field, a comparison and a value, with
+no spaces:
+
+  =  !=        value, or comma-separated alternatives
+  <  <=  >  >= numbers compare as numbers, other fields as text
+
+Fields are agent field names (role_type, rig, agent_state, hook_bead,
+circuit_state, failure_count, quarantine, ...) or the aliases role, state,
+hook, circuit and failures. An empty value is an unset field: "hook=" is an
+agent with nothing hooked, "hook!=" one with work hooked.
+
+Closed agent beads are never matched. With an agent index ('gt beads
+migrate'), queries on indexed fields are answered from it.
+
+Examples:
+  gt beads query role=polecat rig=gastown circuit=open
+  gt beads query role=polecat and failures>=2
+  gt beads query circuit=open,half_open --db gastown --json
+  gt beads query role=witness hook=`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runBeadQuery,
+}
+
+func init() {
+	beadQueryCmd.Flags().StringVar(&beadQueryDB, "db", "", "Only query this database (town, or a rig's name)")
+	beadQueryCmd.Flags().BoolVar(&beadQueryJSON, "json", false, "Output as JSON")
+	beadCmd.AddCommand(beadQueryCmd)
+}
+
+// beadQueryResult is one matched agent bead in 'gt beads query --json'.
+type beadQueryResult struct {
+	ID       string             `json:"id"`
+	Database string             `json:"database"`
+	Fields   *beads.AgentFields `json:"fields"`
+}
+
+func runBeadQuery(cmd *cobra.Command, args []string) error {
+	filters, err := beads.ParseAgentQuery(strings.Join(args, " "))
+	if err != nil {
+		return fmt.Errorf("%w\nfields: %s", err, strings.Join(beads.AgentQueryFields(), ", "))
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	targets, err := migrationTargets(townRoot)
+	if err != nil {
+		return err
+	}
+
+	results := []beadQueryResult{} // Empty slice (not nil) for JSON
+	queried := 0
+	for _, target := range targets {
+		if beadQueryDB != "" && target.name != beadQueryDB {
+			continue
+		}
+		queried++
+		b := beads.NewWithBeadsDir(filepath.Dir(target.beadsDir), target.beadsDir)
+		agents, err := b.Query().Filter(filters...).Run()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  warning: %s: %v\n", target.name, err)
+			continue
+		}
+		for id, issue := range agents {
+			results = append(results, beadQueryResult{ID: id, Database: target.name, Fields: beads.AgentIssueFields(issue)})
+		}
+	}
+	if queried == 0 {
+		return fmt.Errorf("no beads database named %q", beadQueryDB)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+
+	if beadQueryJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No matching agent beads.")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tROLE\tRIG\tSTATE\tCIRCUIT\tFAILURES\tHOOK")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", r.ID, dashIfEmpty(r.Fields.RoleType), dashIfEmpty(r.Fields.Rig),
+			dashIfEmpty(r.Fields.AgentState), dashIfEmpty(r.Fields.CircuitState), r.Fields.FailureCount, dashIfEmpty(r.Fields.HookBead))
+	}
+	return w.Flush()
+}
+
+// dashIfEmpty shows an unset field as "-" in a table.
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadTransferTo     string
+	beadTransferForce  bool
+	beadTransferDryRun bool
+	beadTransferJSON   bool
+)
+
+var beadTransferCmd = &cobra.Command{
+	Use:   "transfer <bead-id> --to <rig>",
+	Short: "Transfer a work bead to another rig",
+	Long: `Transfer a work bead to another rig, so work filed in the wrong rig can
+be done where it belongs.
+
+The bead is recreated in the target rig's database with its prefix
+rewritten (gt-abc12 becomes bd-abc12, unless that ID is taken), keeping
+its title, description, priority, type, labels, parent and dependencies.
+Its comments are copied, and a comment records where it came from. It
+arrives open and unassigned, ready to be slung in the target rig.
+
+Beads that depended on the old bead are pointed at the new one, and the
+old bead is closed with a reference to it. Both rigs' witnesses are
+mailed about the transfer.
+
+A bead that is hooked or in progress is refused unless --force: unsling
+it first, so its polecat isn't left working a closed bead.
+
+Unlike 'gt beads move', which only copies a bead's fields, transfer keeps
+its history and links.
+
+Examples:
+  gt beads transfer gt-abc12 --to beads
+  gt beads transfer gt-abc12 --to beads --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadTransfer,
+}
+
+func init() {
+	beadTransferCmd.Flags().StringVar(&beadTransferTo, "to", "", "Rig to transfer the bead to (required)")
+	beadTransferCmd.Flags().BoolVar(&beadTransferForce, "force", false, "Transfer a bead that is hooked or in progress")
+	beadTransferCmd.Flags().BoolVarP(&beadTransferDryRun, "dry-run", "n", false, "Show what would be done")
+	beadTransferCmd.Flags().BoolVar(&beadTransferJSON, "json", false, "Output as JSON")
+	_ = beadTransferCmd.MarkFlagRequired("to")
+	beadCmd.AddCommand(beadTransferCmd)
+}
+
+func runBeadTransfer(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	targetDir, targetRig, err := portableDatabase(beadTransferTo)
+	if err != nil {
+		return err
+	}
+	sourceRig := beads.GetRigNameForPrefix(townRoot, beads.ExtractPrefix(id))
+	if sourceRig == "" {
+		sourceRig = "hq"
+	}
+	if sourceRig == targetRig {
+		return fmt.Errorf("%s is already in rig %s", id, targetRig)
+	}
+
+	opts := beads.TransferOptions{
+		SourceRig:    sourceRig,
+		TargetRig:    targetRig,
+		TargetPrefix: beads.GetPrefixForRig(townRoot, targetRig) + "-",
+		Force:        beadTransferForce,
+		DryRun:       beadTransferDryRun,
+	}
+	result, err := beads.New(resolveBeadDir(id)).Transfer(beads.New(targetDir), id, opts)
+	if result == nil {
+		return err
+	}
+	if err == nil && !beadTransferDryRun {
+		notifyTransferWitnesses(townRoot, result, opts)
+	}
+
+	if beadTransferJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if encErr := enc.Encode(result); encErr != nil {
+			return encErr
+		}
+		return err
+	}
+
+	newID := result.NewID
+	if newID == "" {
+		newID = opts.TargetPrefix + "* (assigned by bd)"
+	}
+	if beadTransferDryRun {
+		fmt.Printf("Dry run - would:\n")
+		fmt.Printf("  1. Create %s in %s with %s's fields, links and comments\n", newID, targetRig, id)
+		fmt.Printf("  2. Point beads depending on %s at %s\n", id, newID)
+		fmt.Printf("  3. Close %s and mail the %s and %s witnesses\n", id, sourceRig, targetRig)
+		return nil
+	}
+	for _, w := range result.Warnings {
+		style.PrintWarning("%s", w)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Transferred %s → %s (%s → %s)\n", style.Bold.Render("✓"), id, newID, sourceRig, targetRig)
+	fmt.Printf("  %d comment(s) copied", result.Comments)
+	if len(result.Relinked) > 0 {
+		fmt.Printf(", dependents relinked: %s", strings.Join(result.Relinked, ", "))
+	}
+	fmt.Println()
+	return nil
+}
+
+// notifyTransferWitnesses mails the witnesses of both rigs of a transfer
+// (the town has none). Failures are warnings: the transfer is done.
+func notifyTransferWitnesses(townRoot string, result *beads.TransferResult, opts beads.TransferOptions) {
+	router := mail.NewRouterWithTownRoot(townRoot, townRoot)
+	defer router.WaitPendingNotifications()
+	subject := fmt.Sprintf("Bead transferred: %s → %s", result.OldID, result.NewID)
+	body := fmt.Sprintf("%s was transferred from rig %s to rig %s as %s.\n\n%s is closed; work %s in %s.",
+		result.OldID, opts.SourceRig, opts.TargetRig, result.NewID, result.OldID, result.NewID, opts.TargetRig)
+	for _, rigName := range []string{opts.SourceRig, opts.TargetRig} {
+		if rigName == "hq" || rigName == "town" {
+			continue
+		}
+		msg := mail.NewMessage(detectSender(), rigName+"/witness", subject, body)
+		if err := router.Send(msg); err != nil {
+			style.PrintWarning("could not notify %s/witness: %v", rigName, err)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Bead watch notification sinks.
+const (
+	watchNotifyBell    = "bell"
+	watchNotifyDesktop = "desktop"
+	watchNotifyMail    = "mail"
+)
+
+var (
+	beadWatchNotify   []string
+	beadWatchInterval time.Duration
+	beadWatchDigest   time.Duration
+	beadWatchMailTo   string
+)
+
+var beadWatchCmd = &cobra.Command{
+	Use:   "watch <bead-or-convoy-id>...",
+	Short: "Follow beads or convoys and get notified when they change",
+	Long: `Watch beads or convoys and report state changes as they happen, so you
+can follow specific work without polling 'gt bead show'.
+
+A change is a status transition, a new assignee, or a bead becoming
+blocked or unblocked. Watching a convoy also follows every issue it
+tracks, including issues added while you watch.
+
+Changes are always printed. --notify adds sinks:
+  bell     Ring the terminal bell with each batch of changes (default)
+  desktop  Desktop notification (notify-send on Linux, osascript on macOS)
+  mail     Digest mail of all changes, sent every --digest interval
+
+The watch ends when every watched bead is closed (a pending digest is
+sent first), or on Ctrl-C.
+
+Examples:
+  gt bead watch gt-abc123
+  gt bead watch hq-cv-xyz --notify desktop,mail --digest 30m
+  gt bead watch gt-abc gt-def --interval 10s --notify ""`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runBeadWatch,
+}
+
+func init() {
+	beadWatchCmd.Flags().StringSliceVar(&beadWatchNotify, "notify", []string{watchNotifyBell},
+		"Notification sinks: bell, desktop, mail (empty for print only)")
+	beadWatchCmd.Flags().DurationVar(&beadWatchInterval, "interval", 30*time.Second, "How often to check for changes")
+	beadWatchCmd.Flags().DurationVar(&beadWatchDigest, "digest", time.Hour, "How often to send the digest mail (with --notify mail)")
+	beadWatchCmd.Flags().StringVar(&beadWatchMailTo, "mail-to", "overseer", "Digest mail recipient (with --notify mail)")
+	beadCmd.AddCommand(beadWatchCmd)
+}
+
+// beadWatchState is the watched state of one bead.
+type beadWatchState struct {
+	ID       string
+	Title    string
+	Status   string
+	Assignee string
+	Blocked  bool
+	Convoy   string // Convoy tracking this bead, when it was reached through one
+}
+
+// beadWatchChange is one observed change.
+type beadWatchChange struct {
+	Time    time.Time
+	ID      string
+	Convoy  string
+	Message string
+}
+
+func (c beadWatchChange) String() string {
+	s := c.ID + ": " + c.Message
+	if c.Convoy != "" {
+		s += " (convoy " + c.Convoy + ")"
+	}
+	return s
+}
+
+func runBeadWatch(cmd *cobra.Command, args []string) error {
+	sinks, err := parseWatchNotify(beadWatchNotify)
+	if err != nil {
+		return err
+	}
+	if beadWatchInterval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+
+	prev := snapshotWatchedBeads(townBeads, args)
+	for _, id := range args {
+		if s, ok := prev[id]; ok {
+			fmt.Printf("%s Watching %s %s [%s]\n", style.Bold.Render("●"), id, style.Dim.Render(s.Title), s.Status)
+		} else {
+			style.PrintWarning("%s not found; watching in case it appears", id)
+		}
+	}
+	if allWatchedClosed(prev, args) {
+		fmt.Printf("%s All watched beads are already closed\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	var digest []beadWatchChange
+	lastDigest := time.Now()
+	flushDigest := func() {
+		if sinks[watchNotifyMail] && len(digest) > 0 {
+			sendMail(townRoot, beadWatchMailTo, fmt.Sprintf("Bead watch: %d change(s)", len(digest)), formatWatchDigest(digest))
+			digest = nil
+		}
+		lastDigest = time.Now()
+	}
+	defer flushDigest()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	ticker := time.NewTicker(beadWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sigCh:
+			return nil
+		case <-ticker.C:
+		}
+
+		cur := snapshotWatchedBeads(townBeads, args)
+		changes := diffWatchedBeads(prev, cur, time.Now())
+		prev = cur
+
+		if len(changes) > 0 {
+			for _, c := range changes {
+				fmt.Printf("%s %s\n", style.Dim.Render(c.Time.Format("15:04:05")), c.String())
+			}
+			if sinks[watchNotifyBell] {
+				fmt.Print("\a")
+			}
+			if sinks[watchNotifyDesktop] {
+				if err := desktopNotify("Gas Town: bead watch", formatWatchSummary(changes)); err != nil {
+					style.PrintWarning("desktop notification failed: %v", err)
+				}
+			}
+			digest = append(digest, changes...)
+		}
+		if time.Since(lastDigest) >= beadWatchDigest {
+			flushDigest()
+		}
+
+		if allWatchedClosed(cur, args) {
+			fmt.Printf("%s All watched beads are closed\n", style.Bold.Render("✓"))
+			return nil
+		}
+	}
+}
+
+// parseWatchNotify validates --notify and returns the chosen sinks.
+func parseWatchNotify(values []string) (map[string]bool, error) {
+	sinks := make(map[string]bool)
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		switch v {
+		case "":
+		case watchNotifyBell, watchNotifyDesktop, watchNotifyMail:
+			sinks[v] = true
+		default:
+			return nil, fmt.Errorf("unknown --notify sink %q (valid: bell, desktop, mail)", v)
+		}
+	}
+	return sinks, nil
+}
+
+// snapshotWatchedBeads reads the current state of the watched beads, and of
+// every issue tracked by a watched convoy. Beads that can't be read are
+// left out of the snapshot.
+func snapshotWatchedBeads(townBeads string, ids []string) map[string]beadWatchState {
+	snap := make(map[string]beadWatchState)
+	for id, d := range getIssueDetailsBatch(ids) {
+		snap[id] = beadWatchState{
+			ID:       id,
+			Title:    d.Title,
+			Status:   d.Status,
+			Assignee: d.Assignee,
+			Blocked:  d.IsBlocked(),
+		}
+		if d.IssueType != "convoy" {
+			continue
+		}
+		tracked, err := getTrackedIssues(townBeads, id)
+		if err != nil {
+			continue
+		}
+		for _, t := range tracked {
+			if _, watched := snap[t.ID]; watched {
+				continue
+			}
+			snap[t.ID] = beadWatchState{
+				ID:       t.ID,
+				Title:    t.Title,
+				Status:   t.Status,
+				Assignee: t.Assignee,
+				Blocked:  t.Blocked,
+				Convoy:   id,
+			}
+		}
+	}
+	return snap
+}
+
+// diffWatchedBeads reports what changed between two snapshots. Beads that
+// disappear are ignored, since a failed lookup looks the same.
+func diffWatchedBeads(prev, cur map[string]beadWatchState, now time.Time) []beadWatchChange {
+	ids := make([]string, 0, len(cur))
+	for id := range cur {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var changes []beadWatchChange
+	for _, id := range ids {
+		c := cur[id]
+		add := func(msg string) {
+			changes = append(changes, beadWatchChange{Time: now, ID: id, Convoy: c.Convoy, Message: msg})
+		}
+		p, seen := prev[id]
+		if !seen {
+			if c.Convoy != "" {
+				add(fmt.Sprintf("now tracked [%s] %s", c.Status, c.Title))
+			} else {
+				add(fmt.Sprintf("appeared [%s] %s", c.Status, c.Title))
+			}
+			continue
+		}
+		if p.Status != c.Status {
+			add(fmt.Sprintf("%s → %s", p.Status, c.Status))
+		}
+		if p.Assignee != c.Assignee {
+			if c.Assignee == "" {
+				add("unassigned from " + p.Assignee)
+			} else {
+				add("assigned to " + c.Assignee)
+			}
+		}
+		if p.Blocked != c.Blocked {
+			if c.Blocked {
+				add("blocked")
+			} else {
+				add("unblocked")
+			}
+		}
+	}
+	return changes
+}
+
+// allWatchedClosed reports whether every bead named on the command line is
+// known and closed.
+func allWatchedClosed(snap map[string]beadWatchState, ids []string) bool {
+	for _, id := range ids {
+		s, ok := snap[id]
+		if !ok || s.Status != "closed" {
+			return false
+		}
+	}
+	return true
+}
+
+// formatWatchSummary is a short notification body for a batch of changes.
+func formatWatchSummary(changes []beadWatchChange) string {
+	const maxLines = 3
+	var lines []string
+	for i, c := range changes {
+		if i == maxLines {
+			lines = append(lines, fmt.Sprintf("...and %d more", len(changes)-maxLines))
+			break
+		}
+		lines = append(lines, c.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatWatchDigest is the body of a digest mail.
+func formatWatchDigest(changes []beadWatchChange) string {
+	var sb strings.Builder
+	for _, c := range changes {
+		fmt.Fprintf(&sb, "%s  %s\n", c.Time.Format("2006-01-02 15:04"), c.String())
+	}
+	return sb.String()
+}
+
+// desktopNotify shows a desktop notification.
+func desktopNotify(title, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", body, title)
+		cmd = exec.Command("osascript", "-e", script)
+	case "linux":
+		cmd = exec.Command("notify-send", title, body)
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+	return cmd.Run()
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiffWatchedBeads(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	prev := map[string]beadWatchState{
+		"hq-cv-1": {ID: "hq-cv-1", Status: "open"},
+		"gt-a":    {ID: "gt-a", Status: "open", Convoy: "hq-cv-1"},
+		"gt-b":    {ID: "gt-b", Status: "in_progress", Assignee: "gastown/nux", Convoy: "hq-cv-1"},
+		"gt-gone": {ID: "gt-gone", Status: "open"},
+	}
+	cur := map[string]beadWatchState{
+		"hq-cv-1": {ID: "hq-cv-1", Status: "open"},
+		"gt-a":    {ID: "gt-a", Status: "in_progress", Assignee: "gastown/furiosa", Convoy: "hq-cv-1"},
+		"gt-b":    {ID: "gt-b", Status: "in_progress", Blocked: true, Convoy: "hq-cv-1"},
+		"gt-c":    {ID: "gt-c", Status: "open", Title: "New work", Convoy: "hq-cv-1"},
+	}
+
+	var got []string
+	for _, c := range diffWatchedBeads(prev, cur, now) {
+		got = append(got, c.String())
+	}
+	want := []string{
+		"gt-a: open → in_progress (convoy hq-cv-1)",
+		"gt-a: assigned to gastown/furiosa (convoy hq-cv-1)",
+		"gt-b: unassigned from gastown/nux (convoy hq-cv-1)",
+		"gt-b: blocked (convoy hq-cv-1)",
+		"gt-c: now tracked [open] New work (convoy hq-cv-1)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("changes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if changes := diffWatchedBeads(cur, cur, now); len(changes) != 0 {
+		t.Errorf("unchanged snapshot reported %v", changes)
+	}
+}
+
+func TestAllWatchedClosed(t *testing.T) {
+	snap := map[string]beadWatchState{
+		"gt-a": {Status: "closed"},
+		"gt-b": {Status: "open", Convoy: "hq-cv-1"},
+	}
+	if !allWatchedClosed(snap, []string{"gt-a"}) {
+		t.Error("gt-a is closed")
+	}
+	if allWatchedClosed(snap, []string{"gt-a", "gt-missing"}) {
+		t.Error("a missing bead is not closed")
+	}
+}
+
+func TestParseWatchNotify(t *testing.T) {
+	sinks, err := parseWatchNotify([]string{"bell", " mail", ""})
+	if err != nil || !sinks[watchNotifyBell] || !sinks[watchNotifyMail] || sinks[watchNotifyDesktop] {
+		t.Errorf("got %v, %v", sinks, err)
+	}
+	if _, err := parseWatchNotify([]string{"pager"}); err == nil {
+		t.Error("expected error for unknown sink")
+	}
+}
+
+func TestFormatWatchSummary(t *testing.T) {
+	var changes []beadWatchChange
+	for _, id := range []string{"gt-1", "gt-2", "gt-3", "gt-4", "gt-5"} {
+		changes = append(changes, beadWatchChange{ID: id, Message: "closed"})
+	}
+	got := formatWatchSummary(changes)
+	if !strings.HasSuffix(got, "...and 2 more") || strings.Count(got, "\n") != 3 {
+		t.Errorf("summary = %q", got)
+	}
+}
@@ -0,0 +1,491 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Beads sync flags
+var (
+	beadsSyncDryRun bool
+	beadsSyncPrefer string
+	beadsSyncServe  string
+	beadsSyncTown   string
+)
+
+var beadsSyncCmd = &cobra.Command{
+	Use:   "sync <[user@]host>:<town-path>",
+	Short: "Exchange bead changes with another town replica over SSH",
+	Long: `Sync beads with a replica of this town on another machine, so you can
+move between a laptop and a desktop without carrying town state by hand.
+
+Each town reads its bd event history (the audit trail of every write to a
+bead) since the last sync with the other, and sends the beads it shows
+changed, with those events:
+
+  changed here only   → copied to the remote town
+  changed there only  → copied to this town
+  changed on both     → conflict, left alone and reported
+  new on one side     → created on the other with the same ID
+
+A bead's fields, type, labels and dependencies travel. The first sync with
+a peer has no shared history, so any bead that differs between the towns
+is a conflict. Resolve conflicts with --prefer local or --prefer remote;
+until then they are taken up again on every sync. Wisps and agent beads
+are machine-local and never synced; closed beads stay closed (nothing is
+deleted).
+
+The remote needs gt and bd on its PATH. Sync history is kept in
+.runtime/beads-sync/ on both machines, so either side can start the next
+sync.
+
+Examples:
+  gt beads sync desktop:~/gt --dry-run
+  gt beads sync me@desktop:/home/me/gt
+  gt beads sync desktop:~/gt --prefer local`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if beadsSyncServe != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: runBeadsSync,
+}
+
+func init() {
+	beadsSyncCmd.Flags().BoolVarP(&beadsSyncDryRun, "dry-run", "n", false, "Show what would change without applying it")
+	beadsSyncCmd.Flags().StringVar(&beadsSyncPrefer, "prefer", "", "Resolve conflicts in favor of local or remote")
+	// Plumbing for the remote end of a sync.
+	beadsSyncCmd.Flags().StringVar(&beadsSyncServe, "serve", "", "")
+	beadsSyncCmd.Flags().StringVar(&beadsSyncTown, "town", "", "")
+	_ = beadsSyncCmd.Flags().MarkHidden("serve")
+	_ = beadsSyncCmd.Flags().MarkHidden("town")
+	beadCmd.AddCommand(beadsSyncCmd)
+}
+
+// beadsSyncExportRequest asks a town for its changes since marks, its
+// own event history marks as the peer last saw them.
+type beadsSyncExportRequest struct {
+	Peer    string              `json:"peer"`
+	Marks   map[string]int64    `json:"marks"`
+	Pending map[string][]string `json:"pending,omitempty"`
+}
+
+// beadsSyncExport is what a town sends when asked for its changes.
+type beadsSyncExport struct {
+	Peer    string            `json:"peer"`
+	Changes beads.SyncChanges `json:"changes"`
+}
+
+// beadsSyncApply is what the initiating town sends to the remote: records
+// to apply, and the sync base as the remote keeps it afterwards.
+type beadsSyncApply struct {
+	Peer    string             `json:"peer"`
+	Records []beads.SyncRecord `json:"records"`
+	Base    *beads.SyncBase    `json:"base"`
+}
+
+// beadsSyncApplied reports which records the remote applied.
+type beadsSyncApplied struct {
+	Applied []string          `json:"applied"`
+	Errors  map[string]string `json:"errors,omitempty"`
+}
+
+func runBeadsSync(cmd *cobra.Command, args []string) error {
+	if beadsSyncServe != "" {
+		return serveBeadsSync(beadsSyncServe, beadsSyncTown, os.Stdin, os.Stdout)
+	}
+	if beadsSyncPrefer != "" && beadsSyncPrefer != "local" && beadsSyncPrefer != "remote" {
+		return fmt.Errorf("invalid --prefer %q (use local or remote)", beadsSyncPrefer)
+	}
+	host, remoteTown, err := parseBeadsSyncRemote(args[0])
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	self, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("getting hostname: %w", err)
+	}
+
+	// The base is kept per peer, and the peer is only known once it
+	// answers: ask it who it is first, then for its changes.
+	var who beadsSyncExport
+	if err := runRemoteBeadsSync(host, remoteTown, "whoami", nil, &who); err != nil {
+		return err
+	}
+	base, err := beads.LoadSyncBase(townRoot, who.Peer)
+	if err != nil {
+		return err
+	}
+	local, err := collectBeadsSyncChanges(townRoot, base.Local, base.LocalPending)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Fetching bead changes from %s...\n", style.Dim.Render("○"), args[0])
+	var remote beadsSyncExport
+	req := beadsSyncExportRequest{Peer: self, Marks: base.Remote, Pending: base.RemotePending}
+	if err := runRemoteBeadsSync(host, remoteTown, "export", req, &remote); err != nil {
+		return err
+	}
+
+	actions := resolveBeadsSyncConflicts(beads.PlanSync(local, remote.Changes), beadsSyncPrefer)
+	printBeadsSyncPlan(actions, beadsSyncDryRun)
+	if beadsSyncDryRun {
+		return nil
+	}
+
+	// Pull: apply remote changes here, as the sync's own writes so they
+	// aren't sent back as local changes next time.
+	_ = os.Setenv("BD_ACTOR", beads.SyncActor)
+	var pulls, pushes []beads.SyncRecord
+	for _, a := range actions {
+		switch a.Action {
+		case beads.SyncPull:
+			pulls = append(pulls, *a.Remote)
+		case beads.SyncPush:
+			pushes = append(pushes, *a.Local)
+		}
+	}
+	failed := make(map[string]string)
+	for id, err := range applyBeadsSyncRecords(townRoot, pulls) {
+		fmt.Printf("  %s pull %s: %s\n", style.Error.Render("✗"), id, err)
+		failed[id] = err
+	}
+
+	// Push: apply local changes there, handing over the base as the
+	// remote keeps it, so either town can start the next sync.
+	next := nextBeadsSyncBase(base, local, remote.Changes, actions, failed)
+	applyReq := beadsSyncApply{Peer: self, Records: pushes, Base: next.Mirror(self)}
+	var result beadsSyncApplied
+	if err := runRemoteBeadsSync(host, remoteTown, "apply", applyReq, &result); err != nil {
+		return err
+	}
+	for id, msg := range result.Errors {
+		fmt.Printf("  %s push %s: %s\n", style.Error.Render("✗"), id, msg)
+		failed[id] = msg
+	}
+
+	next = nextBeadsSyncBase(base, local, remote.Changes, actions, failed)
+	if err := beads.SaveSyncBase(townRoot, next); err != nil {
+		return fmt.Errorf("saving sync history: %w", err)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d bead(s) failed to sync", len(failed))
+	}
+	fmt.Printf("%s Synced with %s\n", style.Bold.Render("✓"), who.Peer)
+	return nil
+}
+
+// parseBeadsSyncRemote splits "[user@]host:path".
+func parseBeadsSyncRemote(spec string) (host, path string, err error) {
+	host, path, ok := strings.Cut(spec, ":")
+	if !ok || host == "" || path == "" || strings.HasPrefix(host, "-") {
+		return "", "", fmt.Errorf("invalid remote %q: want [user@]host:town-path", spec)
+	}
+	return host, path, nil
+}
+
+// runRemoteBeadsSync runs the plumbing end of a sync on host over SSH,
+// sending req (if any) as JSON on stdin and decoding the reply into resp.
+func runRemoteBeadsSync(host, town, mode string, req, resp interface{}) error {
+	remoteCmd := fmt.Sprintf("gt beads sync --serve %s --town %s", mode, config.ShellQuote(town))
+	c := exec.Command("ssh", host, remoteCmd) //nolint:gosec // G204: host is from the command line
+	if req != nil {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		c.Stdin = bytes.NewReader(data)
+	}
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("remote %s on %s: %w: %s", mode, host, err, strings.TrimSpace(stderr.String()))
+	}
+	if err := json.Unmarshal(stdout.Bytes(), resp); err != nil {
+		return fmt.Errorf("parsing remote %s reply: %w", mode, err)
+	}
+	return nil
+}
+
+// serveBeadsSync is the remote end of a sync: say who this town is,
+// export its changes since the marks the peer sends, or apply records sent
+// by the peer and record the shared base.
+func serveBeadsSync(mode, town string, in io.Reader, out io.Writer) error {
+	townRoot, err := workspace.Find(town)
+	if err != nil || townRoot == "" {
+		return fmt.Errorf("not a Gas Town workspace: %s", town)
+	}
+	self, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(out)
+
+	switch mode {
+	case "whoami":
+		return enc.Encode(beadsSyncExport{Peer: self})
+
+	case "export":
+		var req beadsSyncExportRequest
+		if err := json.NewDecoder(in).Decode(&req); err != nil {
+			return fmt.Errorf("parsing sync request: %w", err)
+		}
+		changes, err := collectBeadsSyncChanges(townRoot, req.Marks, req.Pending)
+		if err != nil {
+			return err
+		}
+		return enc.Encode(beadsSyncExport{Peer: self, Changes: changes})
+
+	case "apply":
+		var req beadsSyncApply
+		if err := json.NewDecoder(in).Decode(&req); err != nil {
+			return fmt.Errorf("parsing sync request: %w", err)
+		}
+		_ = os.Setenv("BD_ACTOR", beads.SyncActor)
+		result := beadsSyncApplied{Applied: []string{}, Errors: applyBeadsSyncRecords(townRoot, req.Records)}
+		base := req.Base
+		if base == nil {
+			base = &beads.SyncBase{}
+		}
+		base.Peer = req.Peer
+		for _, rec := range req.Records {
+			if _, failed := result.Errors[rec.ID]; failed {
+				// The peer's change didn't land here: take it next time.
+				base.RemotePending = addBeadsSyncPending(base.RemotePending, rec.DB, rec.ID)
+				continue
+			}
+			result.Applied = append(result.Applied, rec.ID)
+		}
+		if err := beads.SaveSyncBase(townRoot, base); err != nil {
+			return err
+		}
+		return enc.Encode(result)
+
+	default:
+		return fmt.Errorf("unknown sync mode %q", mode)
+	}
+}
+
+// beadsSyncDBs returns the databases a sync covers: HQ and every rig.
+func beadsSyncDBs(townRoot string) []string {
+	dbs := []string{"hq"}
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err == nil {
+		for name := range rigsConfig.Rigs {
+			dbs = append(dbs, name)
+		}
+	}
+	sort.Strings(dbs[1:])
+	return dbs
+}
+
+// collectBeadsSyncChanges gathers this town's side of a sync: for each
+// database, the events after marks[db], and the syncable beads they or
+// pending[db] name. A database without a mark hasn't been synced with the
+// peer, so every bead in it counts as changed.
+func collectBeadsSyncChanges(townRoot string, marks map[string]int64, pending map[string][]string) (beads.SyncChanges, error) {
+	changes := beads.SyncChanges{Records: []beads.SyncRecord{}, Marks: make(map[string]int64)}
+	for _, db := range beadsSyncDBs(townRoot) {
+		b := beadsForSyncDB(townRoot, db)
+		mark, synced := marks[db]
+		events, err := b.SyncEvents(mark)
+		if err != nil {
+			return changes, fmt.Errorf("reading %s event history: %w", db, err)
+		}
+		changes.Marks[db] = mark
+		for i := range events {
+			events[i].DB = db
+			changes.Marks[db] = events[i].ID
+		}
+
+		ids := append([]string(nil), pending[db]...)
+		if synced {
+			for id := range beads.SyncChangedIDs(events)[db] {
+				ids = append(ids, id)
+			}
+		} else {
+			issues, err := b.List(beads.ListOptions{Status: "all", Priority: -1})
+			if err != nil {
+				return changes, fmt.Errorf("listing %s beads: %w", db, err)
+			}
+			for _, issue := range issues {
+				ids = append(ids, issue.ID)
+			}
+		}
+		sort.Strings(ids)
+		ids = slices.Compact(ids)
+
+		// bd list leaves out dependency IDs, so read the beads with show.
+		issues, err := b.ShowMultiple(ids)
+		if err != nil {
+			return changes, fmt.Errorf("reading %s beads: %w", db, err)
+		}
+		changed := make(map[string]bool, len(issues))
+		for _, id := range ids {
+			if issue := issues[id]; issue != nil && beads.Syncable(issue) {
+				changes.Records = append(changes.Records, beads.SyncRecordFromIssue(db, issue))
+				changed[id] = true
+			}
+		}
+		for _, ev := range events {
+			if changed[ev.IssueID] {
+				changes.Events = append(changes.Events, ev)
+			}
+		}
+	}
+	return changes, nil
+}
+
+// applyBeadsSyncRecords applies records in their databases, their fields
+// first and then, once every bead exists, their dependencies. It returns
+// the error of each record that failed, by bead ID.
+func applyBeadsSyncRecords(townRoot string, records []beads.SyncRecord) map[string]string {
+	failed := make(map[string]string)
+	for _, rec := range records {
+		if err := beadsForSyncDB(townRoot, rec.DB).ApplySyncRecord(rec); err != nil {
+			failed[rec.ID] = err.Error()
+		}
+	}
+	for _, rec := range records {
+		if _, ok := failed[rec.ID]; ok {
+			continue
+		}
+		if err := beadsForSyncDB(townRoot, rec.DB).ApplySyncDeps(rec); err != nil {
+			failed[rec.ID] = err.Error()
+		}
+	}
+	return failed
+}
+
+// beadsForSyncDB returns a bd wrapper for a sync record's database.
+func beadsForSyncDB(townRoot, db string) *beads.Beads {
+	if db == "hq" {
+		return beads.New(townRoot)
+	}
+	return beads.New(filepath.Join(townRoot, db))
+}
+
+// resolveBeadsSyncConflicts turns conflicts into pushes or pulls when
+// prefer is "local" or "remote".
+func resolveBeadsSyncConflicts(actions []beads.SyncAction, prefer string) []beads.SyncAction {
+	for i, a := range actions {
+		if a.Action != beads.SyncConflict {
+			continue
+		}
+		switch prefer {
+		case "local":
+			actions[i].Action = beads.SyncPush
+		case "remote":
+			actions[i].Action = beads.SyncPull
+		}
+	}
+	return actions
+}
+
+// nextBeadsSyncBase is the base after a sync: both towns' histories
+// covered up to where they were read, with the beads whose change didn't
+// land (unresolved conflicts, failed applies) pending on their side.
+func nextBeadsSyncBase(base *beads.SyncBase, local, remote beads.SyncChanges, actions []beads.SyncAction, failed map[string]string) *beads.SyncBase {
+	next := &beads.SyncBase{
+		Peer:     base.Peer,
+		SyncedAt: time.Now().UTC(),
+		Local:    local.Marks,
+		Remote:   remote.Marks,
+	}
+	for _, a := range actions {
+		_, didFail := failed[a.ID]
+		switch {
+		case a.Action == beads.SyncConflict:
+			next.LocalPending = addBeadsSyncPending(next.LocalPending, a.Local.DB, a.ID)
+			next.RemotePending = addBeadsSyncPending(next.RemotePending, a.Remote.DB, a.ID)
+		case a.Action == beads.SyncPush && didFail:
+			next.LocalPending = addBeadsSyncPending(next.LocalPending, a.Local.DB, a.ID)
+		case a.Action == beads.SyncPull && didFail:
+			next.RemotePending = addBeadsSyncPending(next.RemotePending, a.Remote.DB, a.ID)
+		}
+	}
+	return next
+}
+
+// addBeadsSyncPending adds a bead to a pending list.
+func addBeadsSyncPending(pending map[string][]string, db, id string) map[string][]string {
+	if pending == nil {
+		pending = make(map[string][]string)
+	}
+	if !slices.Contains(pending[db], id) {
+		pending[db] = append(pending[db], id)
+	}
+	return pending
+}
+
+// printBeadsSyncPlan shows what a sync does.
+func printBeadsSyncPlan(actions []beads.SyncAction, dryRun bool) {
+	if len(actions) == 0 {
+		fmt.Printf("%s Towns are in sync\n", style.Dim.Render("○"))
+		return
+	}
+	var pulls, pushes, conflicts int
+	for _, a := range actions {
+		var rec *beads.SyncRecord
+		var tag string
+		switch a.Action {
+		case beads.SyncPull:
+			pulls++
+			rec, tag = a.Remote, style.Success.Render("← pull    ")
+		case beads.SyncPush:
+			pushes++
+			rec, tag = a.Local, style.Success.Render("→ push    ")
+		case beads.SyncConflict:
+			conflicts++
+			rec, tag = a.Local, style.Warning.Render("! conflict")
+		}
+		fmt.Println(style.FitLine(fmt.Sprintf("  %s %s [%s] %s", tag, a.ID, rec.Status, rec.Title)))
+		if a.Action == beads.SyncConflict {
+			fmt.Printf("      %s\n", style.Dim.Render(fmt.Sprintf("local: %s (%s); remote: %s (%s)",
+				a.Local.Status, describeBeadsSyncEvents(a.LocalEvents), a.Remote.Status, describeBeadsSyncEvents(a.RemoteEvents))))
+		}
+	}
+	verb := ""
+	if dryRun {
+		verb = " (dry run)"
+	}
+	fmt.Printf("\n%d to pull, %d to push, %d conflict(s)%s\n", pulls, pushes, conflicts, verb)
+	if conflicts > 0 {
+		fmt.Printf("%s\n", style.Dim.Render("Conflicts are left alone; rerun with --prefer local or --prefer remote to resolve them."))
+	}
+}
+
+// describeBeadsSyncEvents summarizes the changes one side made to a bead.
+func describeBeadsSyncEvents(events []beads.SyncEvent) string {
+	if len(events) == 0 {
+		return "not synced before"
+	}
+	var parts []string
+	for _, ev := range events {
+		parts = append(parts, fmt.Sprintf("%s by %s", ev.EventType, ev.Actor))
+	}
+	last := events[len(events)-1].CreatedAt
+	return strings.Join(parts, ", ") + ", last " + last
+}
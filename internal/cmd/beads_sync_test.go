@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestParseBeadsSyncRemote(t *testing.T) {
+	host, path, err := parseBeadsSyncRemote("me@desktop:~/gt")
+	if err != nil || host != "me@desktop" || path != "~/gt" {
+		t.Errorf("got %q %q %v", host, path, err)
+	}
+	for _, bad := range []string{"desktop", "desktop:", ":~/gt", "-oProxyCommand=x:~/gt"} {
+		if _, _, err := parseBeadsSyncRemote(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
+
+func TestNextBeadsSyncBase(t *testing.T) {
+	pulled := beads.SyncRecord{DB: "hq", ID: "hq-pull", Status: "closed"}
+	pushed := beads.SyncRecord{DB: "gastown", ID: "gt-push", Status: "closed"}
+	conflictL := beads.SyncRecord{DB: "hq", ID: "hq-conf", Status: "closed"}
+	conflictR := beads.SyncRecord{DB: "hq", ID: "hq-conf", Status: "hooked"}
+	base := &beads.SyncBase{Peer: "desktop"}
+	local := beads.SyncChanges{Marks: map[string]int64{"hq": 10, "gastown": 3}}
+	remote := beads.SyncChanges{Marks: map[string]int64{"hq": 52}}
+	actions := []beads.SyncAction{
+		{Action: beads.SyncPull, ID: "hq-pull", Remote: &pulled},
+		{Action: beads.SyncPush, ID: "gt-push", Local: &pushed},
+		{Action: beads.SyncConflict, ID: "hq-conf", Local: &conflictL, Remote: &conflictR},
+	}
+
+	next := nextBeadsSyncBase(base, local, remote, actions, map[string]string{"gt-push": "bd failed"})
+	if next.Peer != "desktop" || next.Local["gastown"] != 3 || next.Remote["hq"] != 52 {
+		t.Errorf("marks should move to where the histories were read: %+v", next)
+	}
+	if got := next.LocalPending; len(got["gastown"]) != 1 || len(got["hq"]) != 1 {
+		t.Errorf("local pending = %v, want the failed push and the conflict", got)
+	}
+	if got := next.RemotePending; len(got["hq"]) != 1 || got["hq"][0] != "hq-conf" {
+		t.Errorf("remote pending = %v, want only the conflict", got)
+	}
+}
+
+func TestResolveBeadsSyncConflicts(t *testing.T) {
+	actions := []beads.SyncAction{{Action: beads.SyncConflict, ID: "a"}, {Action: beads.SyncPull, ID: "b"}}
+	got := resolveBeadsSyncConflicts(actions, "local")
+	if got[0].Action != beads.SyncPush || got[1].Action != beads.SyncPull {
+		t.Errorf("got %+v", got)
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	configEffectiveFor  string
+	configEffectiveJSON bool
+)
+
+// configEffectiveCmd shows the merged override hierarchy for one agent.
+var configEffectiveCmd = &cobra.Command{
+	Use:   "effective",
+	Short: "Show the effective configuration for an agent",
+	Long: `Show an agent's effective configuration and where each value came from.
+
+Overrides are resolved town → rig → role → agent. Each level can refine the
+one below it; at each level the rig's settings beat the town's:
+
+  system       compiled-in defaults
+  town         settings/config.json        overrides.defaults
+  rig          <rig>/settings/config.json  overrides.defaults
+  town role    settings/config.json        overrides.roles.<role>
+  rig role     <rig>/settings/config.json  overrides.roles.<role>
+  town agent   settings/config.json        overrides.agents.<address>
+  rig agent    <rig>/settings/config.json  overrides.agents.<address>
+
+Keys with built-in consumers:
+  nuke_policy    "auto" (default) or "manual": whether the witness may
+                 auto-nuke an orphaned polecat whose work has landed
+  runtime_args   extra flags appended to the agent runtime command
+
+Without --for, the current agent (GT_ROLE) is used.
+
+Examples:
+  gt config effective --for gastown/polecats/Toast
+  gt config effective --for gastown/witness --json
+  gt config effective --for mayor`,
+	Args: cobra.NoArgs,
+	RunE: runConfigEffective,
+}
+
+func init() {
+	configEffectiveCmd.Flags().StringVar(&configEffectiveFor, "for", "", "Agent address (e.g. gastown/polecats/Toast, mayor)")
+	configEffectiveCmd.Flags().BoolVar(&configEffectiveJSON, "json", false, "Output as JSON")
+	configCmd.AddCommand(configEffectiveCmd)
+}
+
+func runConfigEffective(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding town root: %w", err)
+	}
+
+	address := configEffectiveFor
+	if address == "" {
+		address = os.Getenv("GT_ROLE")
+	}
+	if address == "" {
+		return fmt.Errorf("no agent given: use --for <address> (e.g. gastown/polecats/Toast)")
+	}
+	target, err := config.ParseOverrideTarget(address)
+	if err != nil {
+		return err
+	}
+	eff, err := config.ResolveEffectiveConfig(townRoot, target)
+	if err != nil {
+		return err
+	}
+
+	if configEffectiveJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(eff)
+	}
+
+	fmt.Printf("%s %s (role: %s)\n\n", style.Bold.Render("Effective config for"), target.Address, target.Role)
+	fmt.Printf("%-20s %-25s %s\n", "Key", "Value", "Source")
+	fmt.Printf("%-20s %-25s %s\n", "---", "-----", "------")
+	for _, v := range eff.Values {
+		source := v.Source
+		if len(v.Shadowed) > 0 {
+			source += style.Dim.Render(" (overrides " + strings.Join(v.Shadowed, ", ") + ")")
+		}
+		fmt.Printf("%-20s %-25s %s\n", v.Key, formatEffectiveValue(v.Value), source)
+	}
+	return nil
+}
+
+// formatEffectiveValue renders an override value compactly.
+func formatEffectiveValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
@@ -2116,7 +2116,7 @@ func (d issueDetails) IsBlocked() bool {
 
 	// bd show can omit blocked_by_count; fall back to live dependency edges.
 	for _, dep := range d.Dependencies {
-		if dep.DependencyType == "blocks" && dep.Status != "closed" && dep.Status != "tombstone" {
+		if beads.IsBlockingDepType(dep.DependencyType) && dep.Status != "closed" && dep.Status != "tombstone" {
 			return true
 		}
 	}
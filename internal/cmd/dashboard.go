@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
 	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
 	"golang.org/x/term"
@@ -17,8 +20,10 @@ import (
 )
 
 var (
-	dashboardPort int
-	dashboardOpen bool
+	dashboardPort     int
+	dashboardOpen     bool
+	dashboardBind     string
+	dashboardInsecure bool
 )
 
 var dashboardCmd = &cobra.Command{
@@ -33,22 +38,36 @@ The dashboard shows real-time convoy status with:
 - Last activity indicator (green/yellow/red)
 - Auto-refresh every 30 seconds via htmx
 
+By default the dashboard listens on every interface, so a town can be
+operated remotely (over a tailnet or VPN). Serving beyond localhost needs
+web_auth in settings/config.json: bearer tokens (see 'gt dashboard
+token') and/or mTLS client certificates, each with scopes:
+  read   dashboard pages and read-only API
+  write  mail send, issue create/close/update
+  run    /api/run (arbitrary gt commands)
+Without web_auth the dashboard serves on localhost only, with a warning;
+a wider --bind is refused unless --insecure.
+
 Example:
   gt dashboard              # Start on default port 8080
   gt dashboard --port 3000  # Start on port 3000
-  gt dashboard --open       # Start and open browser`,
+  gt dashboard --open       # Start and open browser
+  gt dashboard --bind 0.0.0.0  # Serve remotely (requires web_auth)`,
 	RunE: runDashboard,
 }
 
 func init() {
 	dashboardCmd.Flags().IntVar(&dashboardPort, "port", 8080, "HTTP port to listen on")
 	dashboardCmd.Flags().BoolVar(&dashboardOpen, "open", false, "Open browser automatically")
+	dashboardCmd.Flags().StringVar(&dashboardBind, "bind", "", "Address to listen on (default every interface)")
+	dashboardCmd.Flags().BoolVar(&dashboardInsecure, "insecure", false, "Allow binding beyond localhost without web_auth")
 	rootCmd.AddCommand(dashboardCmd)
 }
 
 func runDashboard(cmd *cobra.Command, args []string) error {
 	// Check if we're in a workspace - if not, run in setup mode
 	var handler http.Handler
+	var authCfg *config.WebAuthConfig
 	var err error
 
 	townRoot, wsErr := workspace.FindFromCwdOrError()
@@ -69,6 +88,7 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 		var webCfg *config.WebTimeoutsConfig
 		if ts, loadErr := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); loadErr == nil {
 			webCfg = ts.WebTimeouts
+			authCfg = ts.WebAuth
 		} else {
 			fmt.Fprintf(cmd.ErrOrStderr(), "warning: loading town settings: %v (using defaults)\n", loadErr)
 		}
@@ -79,8 +99,30 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	bind, err := dashboardListenBind(dashboardBind, cmd.Flags().Changed("bind"), web.AuthEnabled(authCfg), dashboardInsecure)
+	if err != nil {
+		return err
+	}
+	if bind != dashboardBind {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: web_auth is not configured; serving on localhost only (see 'gt dashboard token create', or pass --insecure)\n")
+		dashboardBind = bind
+	}
+	tlsCfg, err := web.TLSConfig(authCfg)
+	if err != nil {
+		return err
+	}
+	handler = web.NewAuthHandler(authCfg, handler)
+
 	// Build the URL
-	url := fmt.Sprintf("http://localhost:%d", dashboardPort)
+	scheme := "http"
+	if tlsCfg != nil {
+		scheme = "https"
+	}
+	host := dashboardBind
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	url := fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, strconv.Itoa(dashboardPort)))
 
 	// Open browser if requested
 	if dashboardOpen {
@@ -119,16 +161,44 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  launching dashboard at %s  •  api: %s/api/  •  ctrl+c to stop\n", url, url)
 
 	server := &http.Server{
-		Addr:              fmt.Sprintf(":%d", dashboardPort),
+		Addr:              net.JoinHostPort(dashboardBind, strconv.Itoa(dashboardPort)),
 		Handler:           handler,
+		TLSConfig:         tlsCfg,
 		ReadHeaderTimeout: 10 * time.Second,
 		ReadTimeout:       30 * time.Second,
 		WriteTimeout:      60 * time.Second,
 		IdleTimeout:       120 * time.Second,
 	}
+	if tlsCfg != nil {
+		return server.ListenAndServeTLS(authCfg.TLSCertFile, authCfg.TLSKeyFile)
+	}
 	return server.ListenAndServe()
 }
 
+// dashboardListenBind returns the address the dashboard listens on. The
+// default (every interface) falls back to localhost when there's no auth;
+// a wider address asked for with --bind is refused instead, unless
+// insecure.
+func dashboardListenBind(bind string, explicit, authEnabled, insecure bool) (string, error) {
+	if isLoopbackBind(bind) || authEnabled || insecure {
+		return bind, nil
+	}
+	if !explicit {
+		return "localhost", nil
+	}
+	return "", fmt.Errorf("refusing to serve on %q without authentication: configure web_auth (see 'gt dashboard token create'), pass --bind localhost, or pass --insecure", bind)
+}
+
+// isLoopbackBind reports whether a --bind address only accepts local
+// connections. An empty address listens on every interface.
+func isLoopbackBind(addr string) bool {
+	if addr == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(addr, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
 // openBrowser opens the specified URL in the default browser.
 func openBrowser(url string) {
 	var cmd *exec.Cmd
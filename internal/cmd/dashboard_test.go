@@ -56,3 +56,44 @@ func TestDashboardCmd_RequiresWorkspace(t *testing.T) {
 		t.Error("dashboard command should have RunE set")
 	}
 }
+
+func TestIsLoopbackBind(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"localhost":     true,
+		"127.0.0.1":     true,
+		"::1":           true,
+		"[::1]":         true,
+		"":              false,
+		"0.0.0.0":       false,
+		"100.64.0.12":   false,
+		"gt.tailnet.ts": false,
+	} {
+		if got := isLoopbackBind(addr); got != want {
+			t.Errorf("isLoopbackBind(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestDashboardListenBind(t *testing.T) {
+	tests := []struct {
+		bind                     string
+		explicit, auth, insecure bool
+		want                     string
+		wantErr                  bool
+	}{
+		// Plain 'gt dashboard' in a town (or setup mode) without web_auth.
+		{bind: "", want: "localhost"},
+		{bind: "", auth: true, want: ""},
+		{bind: "localhost", explicit: true, want: "localhost"},
+		{bind: "0.0.0.0", explicit: true, wantErr: true},
+		{bind: "0.0.0.0", explicit: true, auth: true, want: "0.0.0.0"},
+		{bind: "0.0.0.0", explicit: true, insecure: true, want: "0.0.0.0"},
+	}
+	for _, tt := range tests {
+		got, err := dashboardListenBind(tt.bind, tt.explicit, tt.auth, tt.insecure)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("dashboardListenBind(%q, explicit=%v, auth=%v, insecure=%v) = %q, %v; want %q (error %v)",
+				tt.bind, tt.explicit, tt.auth, tt.insecure, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
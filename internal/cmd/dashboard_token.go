@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/web"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var dashboardTokenScopes []string
+
+var dashboardTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage dashboard access tokens",
+	Long: `Manage bearer tokens for the dashboard's web_auth.
+
+Tokens are stored in settings/config.json as SHA-256 hashes; the token
+itself is shown once, at creation. Send it as 'Authorization: Bearer
+<token>', or open the dashboard once with ?token=<token> to store it in a
+browser cookie.`,
+	RunE: requireSubcommand,
+}
+
+var dashboardTokenCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a dashboard token",
+	Long: `Create a named dashboard token with the given scopes.
+
+Scopes: read (dashboard and read-only API), write (mail send, issue
+changes), run (/api/run, arbitrary gt commands), * (everything).
+
+Examples:
+  gt dashboard token create phone --scope read
+  gt dashboard token create laptop --scope read,write,run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDashboardTokenCreate,
+}
+
+var dashboardTokenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List dashboard tokens",
+	Args:  cobra.NoArgs,
+	RunE:  runDashboardTokenList,
+}
+
+var dashboardTokenRevokeCmd = &cobra.Command{
+	Use:   "revoke <name>",
+	Short: "Revoke a dashboard token",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDashboardTokenRevoke,
+}
+
+func init() {
+	dashboardTokenCreateCmd.Flags().StringSliceVar(&dashboardTokenScopes, "scope", []string{web.ScopeRead},
+		"Scopes to grant (read, write, run, *)")
+	dashboardTokenCmd.AddCommand(dashboardTokenCreateCmd)
+	dashboardTokenCmd.AddCommand(dashboardTokenListCmd)
+	dashboardTokenCmd.AddCommand(dashboardTokenRevokeCmd)
+	dashboardCmd.AddCommand(dashboardTokenCmd)
+}
+
+// loadWebAuthSettings loads town settings for editing web_auth.
+func loadWebAuthSettings() (string, *config.TownSettings, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	path := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.WebAuth == nil {
+		settings.WebAuth = &config.WebAuthConfig{}
+	}
+	return path, settings, nil
+}
+
+func runDashboardTokenCreate(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := web.ValidateScopes(dashboardTokenScopes); err != nil {
+		return err
+	}
+	path, settings, err := loadWebAuthSettings()
+	if err != nil {
+		return err
+	}
+	for _, t := range settings.WebAuth.Tokens {
+		if t.Name == name {
+			return fmt.Errorf("token %q already exists; revoke it first", name)
+		}
+	}
+
+	token, err := web.GenerateToken()
+	if err != nil {
+		return fmt.Errorf("generating token: %w", err)
+	}
+	settings.WebAuth.Tokens = append(settings.WebAuth.Tokens, config.WebAuthToken{
+		Name:      name,
+		SHA256:    web.HashToken(token),
+		Scopes:    dashboardTokenScopes,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	})
+	if err := config.SaveTownSettings(path, settings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+
+	fmt.Printf("%s Created dashboard token %q (scopes: %s)\n", style.Bold.Render("✓"), name, strings.Join(dashboardTokenScopes, ","))
+	fmt.Printf("\n  %s\n\n", token)
+	fmt.Printf("  %s\n", style.Dim.Render("This is the only time the token is shown. Restart 'gt dashboard' to pick it up."))
+	return nil
+}
+
+func runDashboardTokenList(cmd *cobra.Command, args []string) error {
+	_, settings, err := loadWebAuthSettings()
+	if err != nil {
+		return err
+	}
+	if len(settings.WebAuth.Tokens) == 0 {
+		fmt.Printf("%s No dashboard tokens\n", style.Dim.Render("○"))
+		return nil
+	}
+	table := style.NewTable(
+		style.Column{Name: "NAME", Width: 20},
+		style.Column{Name: "SCOPES", Width: 18},
+		style.Column{Name: "CREATED", Width: 20},
+	)
+	for _, t := range settings.WebAuth.Tokens {
+		table.AddRow(t.Name, strings.Join(t.Scopes, ","), t.CreatedAt)
+	}
+	fmt.Print(table.Render())
+	return nil
+}
+
+func runDashboardTokenRevoke(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	path, settings, err := loadWebAuthSettings()
+	if err != nil {
+		return err
+	}
+	kept := settings.WebAuth.Tokens[:0]
+	for _, t := range settings.WebAuth.Tokens {
+		if t.Name != name {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) == len(settings.WebAuth.Tokens) {
+		return fmt.Errorf("no dashboard token named %q", name)
+	}
+	settings.WebAuth.Tokens = kept
+	if err := config.SaveTownSettings(path, settings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+	fmt.Printf("%s Revoked dashboard token %q\n", style.Bold.Render("✓"), name)
+	return nil
+}
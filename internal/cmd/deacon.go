@@ -15,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/runtime"
@@ -57,7 +58,12 @@ var deaconStartCmd = &cobra.Command{
 	Long: `Start the Deacon tmux session.
 
 Creates a new detached tmux session for the Deacon and launches Claude.
-The session runs in the workspace root directory.`,
+The session runs in the workspace root directory.
+
+With --headless, the Deacon's mechanical patrol loop runs as a plain
+process supervised by the daemon, with no tmux dependency. A short-lived
+interactive Claude session is started in tmux only when mail needs
+judgment. Starting without --headless switches back to the tmux Deacon.`,
 	RunE: runDeaconStart,
 }
 
@@ -66,7 +72,14 @@ var deaconStopCmd = &cobra.Command{
 	Short: "Stop the Deacon session",
 	Long: `Stop the Deacon tmux session.
 
-Attempts graceful shutdown first (Ctrl-C), then kills the tmux session.`,
+Stops gracefully: writes a SHUTDOWN_REQUESTED file and nudges the Deacon,
+which acknowledges on its next 'gt deacon heartbeat' after flushing its
+heartbeat and persisting its pending spawns for the next Deacon. The
+session is killed once the Deacon acknowledges, or after --timeout if it
+never does. Use --force to skip the handshake and kill right away.
+
+A headless Deacon's loop process is signaled to exit instead. Either way
+the daemon starts the Deacon again on its next heartbeat.`,
 	RunE: runDeaconStop,
 }
 
@@ -112,11 +125,22 @@ var deaconHeartbeatCmd = &cobra.Command{
 	Long: `Update the Deacon heartbeat file.
 
 The heartbeat signals to the daemon that the Deacon is alive and working.
-Call this at the start of each wake cycle to prevent daemon pokes.
+Call this at the start of each wake cycle to prevent daemon pokes. Run
+from the Deacon's session, the heartbeat also records the session and
+its pane PID, and counts cycles and restarts per owner.
+
+With --status, inspect the heartbeat instead: its age and whether it is
+within tolerance (the very-stale threshold, past which the daemon steps
+in), whether its owner process is still running, and the Deacon's last
+actions from its log. This tells a working Deacon apart from one that
+only has a tmux session. Exits non-zero when the heartbeat is missing or
+out of tolerance, or its owner is gone.
 
 Examples:
   gt deacon heartbeat                    # Touch heartbeat with timestamp
-  gt deacon heartbeat "checking mayor"   # Touch with action description`,
+  gt deacon heartbeat "checking mayor"   # Touch with action description
+  gt deacon heartbeat --status           # Is the Deacon actually alive?
+  gt deacon heartbeat --status -n 20     # ...with the last 20 actions`,
 	RunE: runDeaconHeartbeat,
 }
 
@@ -135,7 +159,24 @@ In steady-state, the Deacon should use AI-based observation instead:
   gt peek <session>     # Observe session output (AI analyzes)
   gt nudge <session>    # Trigger when AI determines ready
 
-This command is typically called by the daemon during cold startup.`,
+This command is typically called by the daemon during cold startup.
+
+A spawn whose runtime isn't ready is re-checked with exponential backoff
+(pending_spawn_backoff, doubling up to a minute). After
+pending_spawn_max_attempts failed checks, or once it is older than
+pending_spawn_max_age, it is escalated to the Mayor with a SPAWN_STUCK
+mail and its POLECAT_STARTED message is archived.
+
+Spawns are triggered oldest first. With max_polecats_per_rig or
+max_triggers_per_minute set, a ready spawn past either limit is held: it
+stays pending, is never pruned for age, and is triggered on a later pass
+once a polecat in its rig finishes or the rate window frees up. Held
+spawns show their queue position in 'gt deacon pending'.
+
+With --dry-run, it reports which sessions would be nudged, which spawns
+would be archived, backed off or escalated, and why, without sending any
+keys or touching the inbox. Use it when spurious "Begin." nudges are hitting
+working polecats.`,
 	RunE: runDeaconTriggerPending,
 }
 
@@ -225,11 +266,19 @@ var deaconPauseCmd = &cobra.Command{
 	Short: "Pause the Deacon to prevent patrol actions",
 	Long: `Pause the Deacon to prevent it from performing any patrol actions.
 
+Use it during upgrades, manual surgery on beads, or to take over the Deacon
+session by hand without the orchestrator fighting you.
+
 When paused, the Deacon:
 - Will not create patrol molecules
-- Will not run health checks
+- Will not trigger pending polecat spawns (gt deacon trigger-pending)
+- Will not run health checks or nudge other agents
 - Will not take any autonomous actions
 - Will display a PAUSED message on startup
+- Still records heartbeats (gt deacon heartbeat)
+
+The daemon respects the pause too: it does not restart, poke or triage the
+Deacon and does not trigger pending spawns until the Deacon is resumed.
 
 The pause state persists across session restarts. Use 'gt deacon resume'
 to allow the Deacon to work again.
@@ -245,7 +294,9 @@ var deaconResumeCmd = &cobra.Command{
 	Short: "Resume the Deacon to allow patrol actions",
 	Long: `Resume the Deacon so it can perform patrol actions again.
 
-This removes the pause file and allows the Deacon to work normally.`,
+This removes the pause file and allows the Deacon to work normally. The
+heartbeat is refreshed so a long pause doesn't look like a stuck Deacon
+to the daemon.`,
 	RunE: runDeaconResume,
 }
 
@@ -337,6 +388,7 @@ This helps the Deacon understand which recovered beads need attention.`,
 
 var (
 	triggerTimeout time.Duration
+	triggerDryRun  bool
 
 	// Status flags
 	deaconStatusJSON bool
@@ -357,6 +409,10 @@ var (
 	// Pause flags
 	pauseReason string
 
+	// Stop flags
+	deaconStopForce   bool
+	deaconStopTimeout time.Duration
+
 	// Zombie scan flags
 	zombieScanDryRun bool
 
@@ -384,6 +440,7 @@ func init() {
 	deaconCmd.AddCommand(deaconZombieScanCmd)
 	deaconCmd.AddCommand(deaconRedispatchCmd)
 	deaconCmd.AddCommand(deaconRedispatchStateCmd)
+	deaconCmd.AddCommand(deaconLogsCmd)
 
 	// Flags for status
 	deaconStatusCmd.Flags().BoolVar(&deaconStatusJSON, "json", false, "Output as JSON")
@@ -391,6 +448,8 @@ func init() {
 	// Flags for trigger-pending
 	deaconTriggerPendingCmd.Flags().DurationVar(&triggerTimeout, "timeout", 2*time.Second,
 		"Timeout for checking if Claude is ready")
+	deaconTriggerPendingCmd.Flags().BoolVarP(&triggerDryRun, "dry-run", "n", false,
+		"Show what would be nudged or escalated without doing it")
 
 	// Flags for health-check
 	deaconHealthCheckCmd.Flags().DurationVar(&healthCheckTimeout, "timeout", 30*time.Second,
@@ -412,6 +471,12 @@ func init() {
 	deaconStaleHooksCmd.Flags().BoolVar(&staleHooksDryRun, "dry-run", false,
 		"Preview what would be unhooked without making changes")
 
+	// Flags for stop
+	deaconStopCmd.Flags().BoolVarP(&deaconStopForce, "force", "f", false,
+		"Kill the session without waiting for the Deacon to acknowledge")
+	deaconStopCmd.Flags().DurationVar(&deaconStopTimeout, "timeout", time.Minute,
+		"How long to wait for the Deacon to acknowledge shutdown before killing it")
+
 	// Flags for pause
 	deaconPauseCmd.Flags().StringVar(&pauseReason, "reason", "",
 		"Reason for pausing the Deacon")
@@ -440,6 +505,15 @@ func runDeaconStart(cmd *cobra.Command, args []string) error {
 
 	sessionName := getDeaconSessionName()
 
+	if deaconStartHeadless {
+		return startHeadlessDeacon(t, sessionName)
+	}
+	if townRoot, err := workspace.FindFromCwdOrError(); err == nil {
+		if err := leaveHeadlessMode(townRoot); err != nil {
+			return err
+		}
+	}
+
 	// Check if session already exists
 	running, err := t.HasSession(sessionName)
 	if err != nil {
@@ -468,6 +542,11 @@ func startDeaconSession(t *tmux.Tmux, sessionName, agentOverride string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	// A shutdown request left by an interrupted stop is not for this Deacon.
+	if err := deacon.ClearShutdownRequest(townRoot); err != nil {
+		style.PrintWarning("could not clear stale shutdown request: %v", err)
+	}
+
 	// Deacon runs from its own directory (for correct role detection by gt prime)
 	deaconDir := filepath.Join(townRoot, "deacon")
 
@@ -476,8 +555,19 @@ func startDeaconSession(t *tmux.Tmux, sessionName, agentOverride string) error {
 		return fmt.Errorf("creating deacon directory: %w", err)
 	}
 
-	// Ensure runtime settings exist (autonomous role needs mail in SessionStart)
+	// Resolve the Deacon's agent runtime (role_agents.deacon, the town
+	// default, or --agent), so the settings, readiness wait and fallback
+	// below match the CLI that is actually launched.
 	runtimeConfig := config.ResolveRoleAgentConfig("deacon", townRoot, deaconDir)
+	if agentOverride != "" {
+		rc, _, err := config.ResolveAgentConfigWithOverride(townRoot, "", agentOverride)
+		if err != nil {
+			return fmt.Errorf("resolving agent %q: %w", agentOverride, err)
+		}
+		runtimeConfig = rc
+	}
+
+	// Ensure runtime settings exist (autonomous role needs mail in SessionStart)
 	if err := runtime.EnsureSettingsForRole(deaconDir, deaconDir, "deacon", runtimeConfig); err != nil {
 		return fmt.Errorf("ensuring runtime settings: %w", err)
 	}
@@ -514,20 +604,25 @@ func startDeaconSession(t *tmux.Tmux, sessionName, agentOverride string) error {
 	theme := tmux.DeaconTheme()
 	_ = t.ConfigureGasTownSession(sessionName, theme, "", "Deacon", "health-check")
 
-	// Wait for Claude to start
+	// Wait for the agent to start
 	if err := t.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 		return fmt.Errorf("waiting for deacon to start: %w", err)
 	}
 
 	// Accept bypass permissions warning dialog if it appears.
 	// This prevents hangs on systems where Claude prompts for permissions.
-	_ = t.AcceptBypassPermissionsWarning(sessionName)
+	if runtime.EmitsPermissionWarning(runtimeConfig) {
+		_ = t.AcceptBypassPermissionsWarning(sessionName)
+	}
+
+	// Wait for the prompt (ready_pattern or ready_prompt_prefix) before any
+	// fallback nudge. Non-fatal: the agent is running, and a slow prompt
+	// only delays the fallback.
+	_ = t.WaitForRuntimeReady(sessionName, runtimeConfig, constants.ClaudeStartTimeout)
 
 	time.Sleep(constants.ShutdownNotifyDelay)
 
-	deaconTownRoot, _ := workspace.FindFromCwdOrError()
-	runtimeCfg := config.ResolveRoleAgentConfig("deacon", deaconTownRoot, "")
-	_ = runtime.RunStartupFallback(t, sessionName, "deacon", runtimeCfg)
+	_ = runtime.RunStartupFallback(t, sessionName, "deacon", runtimeConfig)
 
 	return nil
 }
@@ -537,6 +632,24 @@ func runDeaconStop(cmd *cobra.Command, args []string) error {
 
 	sessionName := getDeaconSessionName()
 
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err == nil && deacon.IsHeadless(townRoot) {
+		p := daemon.HeadlessDeaconProcess(townRoot)
+		if p == nil {
+			return errors.New("headless Deacon is not running")
+		}
+		fmt.Printf("Stopping headless Deacon (PID %d)...\n", p.Pid)
+		timeout := 5 * time.Second
+		if deaconStopForce {
+			timeout = 0
+		}
+		if err := daemon.StopHeadlessDeacon(townRoot, timeout); err != nil {
+			return err
+		}
+		fmt.Printf("%s Headless Deacon stopped.\n", style.Bold.Render("✓"))
+		return nil
+	}
+
 	// Check if session exists
 	running, err := t.HasSession(sessionName)
 	if err != nil {
@@ -548,7 +661,16 @@ func runDeaconStop(cmd *cobra.Command, args []string) error {
 
 	fmt.Println("Stopping Deacon session...")
 
-	// Try graceful shutdown first (best-effort interrupt)
+	if !deaconStopForce && townRoot != "" {
+		gone := requestDeaconShutdown(t, townRoot, sessionName, deaconStopTimeout)
+		defer func() { _ = deacon.ClearShutdownRequest(townRoot) }()
+		if gone {
+			fmt.Printf("%s Deacon session stopped.\n", style.Bold.Render("✓"))
+			return nil
+		}
+	}
+
+	// Interrupt whatever is running (best-effort)
 	_ = t.SendKeysRaw(sessionName, "C-c")
 	time.Sleep(100 * time.Millisecond)
 
@@ -562,6 +684,44 @@ func runDeaconStop(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// deaconShutdownNudge asks the Deacon to acknowledge a shutdown request.
+const deaconShutdownNudge = "SHUTDOWN_REQUESTED: finish your current step, then run " +
+	"'gt deacon heartbeat \"shutting down\"' and stop. Do not start new work."
+
+// requestDeaconShutdown writes the shutdown request, nudges the Deacon and
+// waits up to timeout for it to acknowledge. It reports whether the
+// session has already exited by itself; otherwise the caller kills it.
+func requestDeaconShutdown(t *tmux.Tmux, townRoot, sessionName string, timeout time.Duration) bool {
+	if _, err := deacon.RequestShutdown(townRoot, "human"); err != nil {
+		style.PrintWarning("could not request graceful shutdown: %v", err)
+		return false
+	}
+	if err := t.NudgeSession(sessionName, deaconShutdownNudge); err != nil {
+		style.PrintWarning("could not nudge Deacon: %v", err)
+		return false
+	}
+	fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("Waiting up to %s for the Deacon to flush its state...", timeout)))
+
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(500 * time.Millisecond) {
+		if running, err := t.HasSession(sessionName); err == nil && !running {
+			return true
+		}
+		req, err := deacon.ReadShutdownRequest(townRoot)
+		if err != nil || !req.Ready() {
+			continue
+		}
+		fmt.Printf("  Deacon acknowledged at cycle %d", req.Cycle)
+		if len(req.Pending) > 0 {
+			fmt.Printf(", handing off %d pending spawn(s): %s", len(req.Pending), strings.Join(req.Pending, ", "))
+		}
+		fmt.Println()
+		return false
+	}
+
+	style.PrintWarning("Deacon did not acknowledge shutdown within %s, killing it", timeout)
+	return false
+}
+
 func runDeaconAttach(cmd *cobra.Command, args []string) error {
 	t := tmux.NewTmux()
 
@@ -573,6 +733,9 @@ func runDeaconAttach(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("checking session: %w", err)
 	}
 	if !running {
+		if townRoot, err := workspace.FindFromCwdOrError(); err == nil && deacon.IsHeadless(townRoot) {
+			return errors.New("Deacon is headless and has no interactive session right now; use 'gt deacon logs -f' to watch it")
+		}
 		// Auto-start if not running
 		fmt.Println("Deacon session not running, starting...")
 		if err := startDeaconSession(t, sessionName, deaconAgentOverride); err != nil {
@@ -590,6 +753,8 @@ type DeaconStatusOutput struct {
 	Running   bool             `json:"running"`
 	Paused    bool             `json:"paused"`
 	Session   string           `json:"session"`
+	Headless  bool             `json:"headless,omitempty"`
+	PID       int              `json:"pid,omitempty"`
 	Heartbeat *HeartbeatStatus `json:"heartbeat,omitempty"`
 }
 
@@ -602,6 +767,14 @@ type HeartbeatStatus struct {
 	Fresh      bool      `json:"fresh"`
 	Stale      bool      `json:"stale"`
 	VeryStale  bool      `json:"very_stale"`
+
+	// Owner of the heartbeat: PIDAlive is set when the PID is known.
+	PID         int        `json:"pid,omitempty"`
+	PIDAlive    *bool      `json:"pid_alive,omitempty"`
+	Session     string     `json:"session,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	OwnerCycles int64      `json:"owner_cycles,omitempty"`
+	Restarts    int64      `json:"restarts,omitempty"`
 }
 
 func runDeaconStatus(cmd *cobra.Command, args []string) error {
@@ -621,24 +794,27 @@ func runDeaconStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	running, err := t.HasSession(sessionName)
-	if err != nil {
-		return fmt.Errorf("checking session: %w", err)
+	headless := townRoot != "" && deacon.IsHeadless(townRoot)
+	headlessPID := 0
+	var running bool
+	if headless {
+		if p := daemon.HeadlessDeaconProcess(townRoot); p != nil {
+			running, headlessPID = true, p.Pid
+		}
+	} else {
+		var err error
+		running, err = t.HasSession(sessionName)
+		if err != nil {
+			return fmt.Errorf("checking session: %w", err)
+		}
 	}
 
 	// Read heartbeat
 	var hbStatus *HeartbeatStatus
 	if townRoot != "" {
 		if hb := deacon.ReadHeartbeat(townRoot); hb != nil {
-			hbStatus = &HeartbeatStatus{
-				Timestamp:  hb.Timestamp,
-				AgeSec:     hb.Age().Seconds(),
-				Cycle:      hb.Cycle,
-				LastAction: hb.LastAction,
-				Fresh:      hb.IsFresh(),
-				Stale:      hb.IsStale(),
-				VeryStale:  hb.IsVeryStale(),
-			}
+			cfg, _ := deacon.LoadConfigOrDefault(townRoot)
+			hbStatus = heartbeatStatusFor(hb, cfg)
 		}
 	}
 
@@ -648,6 +824,8 @@ func runDeaconStatus(cmd *cobra.Command, args []string) error {
 			Running:   running,
 			Paused:    paused,
 			Session:   sessionName,
+			Headless:  headless,
+			PID:       headlessPID,
 			Heartbeat: hbStatus,
 		}
 		enc := json.NewEncoder(os.Stdout)
@@ -668,7 +846,15 @@ func runDeaconStatus(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
-	if running {
+	if headless {
+		if running {
+			fmt.Printf("%s Deacon is %s (headless, PID %d)\n",
+				style.Bold.Render("●"), style.Bold.Render("running"), headlessPID)
+		} else {
+			fmt.Printf("%s Deacon is not running (headless; the daemon restarts it on its next heartbeat)\n",
+				style.Dim.Render("○"))
+		}
+	} else if running {
 		// Get session info for more details
 		info, err := t.GetSessionInfo(sessionName)
 		if err == nil {
@@ -756,18 +942,22 @@ func runDeaconHeartbeat(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
+	if deaconHeartbeatStatus {
+		if len(args) > 0 {
+			return fmt.Errorf("--status takes no action argument")
+		}
+		return runDeaconHeartbeatStatus(townRoot)
+	}
 
-	// Check if Deacon is paused - if so, refuse to update heartbeat
-	paused, state, err := deacon.IsPaused(townRoot)
+	// A paused Deacon still records heartbeats: pause suspends patrol
+	// actions, not liveness.
+	paused, _, err := deacon.IsPaused(townRoot)
 	if err != nil {
 		return fmt.Errorf("checking pause state: %w", err)
 	}
-	if paused {
-		fmt.Printf("%s Deacon is paused. Use 'gt deacon resume' to unpause.\n", style.Bold.Render("⏸️"))
-		if state.Reason != "" {
-			fmt.Printf("  Reason: %s\n", state.Reason)
-		}
-		return errors.New("Deacon is paused")
+	shutdown, _ := deacon.ReadShutdownRequest(townRoot)
+	if shutdown.Expired() {
+		shutdown = nil
 	}
 
 	action := ""
@@ -775,18 +965,52 @@ func runDeaconHeartbeat(cmd *cobra.Command, args []string) error {
 		action = strings.Join(args, " ")
 	}
 
+	if err := deacon.TouchFrom(townRoot, deaconHeartbeatOwner(), action, 0, 0); err != nil {
+		return fmt.Errorf("updating heartbeat: %w", err)
+	}
 	if action != "" {
-		if err := deacon.TouchWithAction(townRoot, action, 0, 0); err != nil {
-			return fmt.Errorf("updating heartbeat: %w", err)
-		}
 		fmt.Printf("%s Heartbeat updated: %s\n", style.Bold.Render("✓"), action)
 	} else {
-		if err := deacon.Touch(townRoot); err != nil {
-			return fmt.Errorf("updating heartbeat: %w", err)
-		}
 		fmt.Printf("%s Heartbeat updated\n", style.Bold.Render("✓"))
 	}
+	if paused {
+		fmt.Printf("  %s\n", style.Dim.Render("Deacon is paused: patrol actions stay suspended until 'gt deacon resume'"))
+	}
+
+	entry := deacon.LogEntry{Event: deacon.LogEventHeartbeat, Message: action}
+	if paused {
+		entry.Fields = map[string]string{"paused": "true"}
+	}
+	if hb := deacon.ReadHeartbeat(townRoot); hb != nil {
+		entry.Cycle = hb.Cycle
+	}
+	if err := deacon.AppendLog(townRoot, entry); err != nil {
+		style.PrintWarning("could not write deacon log: %v", err)
+	}
 
+	if shutdown != nil {
+		return acknowledgeDeaconShutdown(townRoot, entry.Cycle)
+	}
+	return nil
+}
+
+// acknowledgeDeaconShutdown answers gt deacon stop: it brings the pending
+// spawn state up to date from the inbox, so the next Deacon picks up where
+// this one left off, then marks the shutdown request ready.
+func acknowledgeDeaconShutdown(townRoot string, cycle int64) error {
+	pending, err := polecat.CheckInboxForSpawns(townRoot)
+	if err != nil {
+		style.PrintWarning("could not scan inbox for pending spawns: %v", err)
+	}
+	sessions := make([]string, 0, len(pending))
+	for _, ps := range pending {
+		sessions = append(sessions, ps.Session)
+	}
+	if _, err := deacon.AcknowledgeShutdown(townRoot, cycle, sessions); err != nil {
+		return fmt.Errorf("acknowledging shutdown: %w", err)
+	}
+	fmt.Printf("%s Shutdown requested: state flushed, %d pending spawn(s) handed off. Stop now; the session will be ended.\n",
+		style.Bold.Render("⏻"), len(sessions))
 	return nil
 }
 
@@ -795,6 +1019,9 @@ func runDeaconTriggerPending(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
+	if !triggerDryRun && deaconPausedSkip(townRoot, "triggering pending spawns") {
+		return nil
+	}
 
 	// Step 1: Check inbox for new POLECAT_STARTED messages
 	pending, err := polecat.CheckInboxForSpawns(townRoot)
@@ -809,8 +1036,16 @@ func runDeaconTriggerPending(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("%s Found %d pending spawn(s)\n", style.Bold.Render("●"), len(pending))
 
+	cfg, err := deacon.LoadConfigOrDefault(townRoot)
+	if err != nil {
+		style.PrintWarning("invalid deacon config, using defaults: %v", err)
+	}
+	if triggerDryRun {
+		return reportPendingPlan(townRoot, cfg.GetPendingSpawnMaxAge(), pendingRetryPolicy(cfg), pendingTriggerLimits(cfg))
+	}
+
 	// Step 2: Try to trigger each pending spawn
-	results, err := polecat.TriggerPendingSpawns(townRoot, triggerTimeout)
+	results, err := polecat.TriggerPendingSpawns(townRoot, triggerTimeout, pendingRetryPolicy(cfg), pendingTriggerLimits(cfg))
 	if err != nil {
 		return fmt.Errorf("triggering: %w", err)
 	}
@@ -823,6 +1058,33 @@ func runDeaconTriggerPending(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  %s Triggered %s/%s\n",
 				style.Bold.Render("✓"),
 				r.Spawn.Rig, r.Spawn.Polecat)
+			_ = deacon.AppendLog(townRoot, deacon.LogEntry{
+				Event:   deacon.LogEventSpawn,
+				Message: "triggered pending spawn",
+				Fields:  map[string]string{"rig": r.Spawn.Rig, "polecat": r.Spawn.Polecat, "session": r.Spawn.Session},
+			})
+		} else if r.Escalated {
+			triggered++ // no longer pending
+			fmt.Printf("  %s %s/%s not ready after %d checks; escalated to mayor\n",
+				style.Warning.Render("⚠"),
+				r.Spawn.Rig, r.Spawn.Polecat, r.Spawn.Attempts)
+			_ = deacon.AppendLog(townRoot, deacon.LogEntry{
+				Event:   deacon.LogEventEscalation,
+				Message: "pending spawn never became ready",
+				Fields:  map[string]string{"rig": r.Spawn.Rig, "polecat": r.Spawn.Polecat, "attempts": fmt.Sprint(r.Spawn.Attempts)},
+			})
+			if err := deacon.Notify(townRoot, deacon.SpawnFailureNotification(r.Spawn.Rig, r.Spawn.Polecat, r.Spawn.Attempts)); err != nil {
+				style.PrintWarning("notification failed: %v", err)
+			}
+		} else if r.Held {
+			fmt.Printf("  %s %s/%s ready but held: %s (queue position %d)\n",
+				style.Dim.Render("○"),
+				r.Spawn.Rig, r.Spawn.Polecat, r.HeldBy, r.QueuePosition)
+		} else if r.Skipped {
+			fmt.Printf("  %s %s/%s not ready (check %d); retry in %v\n",
+				style.Dim.Render("○"),
+				r.Spawn.Rig, r.Spawn.Polecat, r.Spawn.Attempts,
+				time.Until(r.Spawn.NextAttempt).Round(time.Second))
 		} else if r.Error != nil {
 			fmt.Printf("  %s %s/%s: %v\n",
 				style.Dim.Render("⚠"),
@@ -830,10 +1092,15 @@ func runDeaconTriggerPending(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Step 3: Prune stale pending spawns (older than 5 minutes)
-	pruned, _ := polecat.PruneStalePending(townRoot, 5*time.Minute)
+	// Step 3: Escalate stale pending spawns (older than pending_spawn_max_age)
+	pruned, _ := polecat.PruneStalePending(townRoot, cfg.GetPendingSpawnMaxAge())
 	if pruned > 0 {
-		fmt.Printf("  %s Pruned %d stale spawn(s)\n", style.Dim.Render("○"), pruned)
+		fmt.Printf("  %s Escalated %d stale spawn(s) to mayor\n", style.Warning.Render("⚠"), pruned)
+		_ = deacon.AppendLog(townRoot, deacon.LogEntry{
+			Event:   deacon.LogEventPendingCleared,
+			Message: "escalated stale pending spawns",
+			Fields:  map[string]string{"count": fmt.Sprint(pruned), "max_age": cfg.GetPendingSpawnMaxAge().String()},
+		})
 	}
 
 	// Summary
@@ -846,6 +1113,54 @@ func runDeaconTriggerPending(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// pendingRetryPolicy returns the readiness retry budget from the Deacon config.
+func pendingRetryPolicy(cfg *deacon.Config) polecat.RetryPolicy {
+	return polecat.RetryPolicy{
+		MaxAttempts: cfg.GetPendingSpawnMaxAttempts(),
+		Backoff:     cfg.GetPendingSpawnBackoff(),
+	}
+}
+
+// pendingTriggerLimits returns the trigger caps from the Deacon config.
+func pendingTriggerLimits(cfg *deacon.Config) polecat.TriggerLimits {
+	return polecat.TriggerLimits{
+		MaxPerRig:    cfg.GetMaxPolecatsPerRig(),
+		MaxPerMinute: cfg.GetMaxTriggersPerMinute(),
+	}
+}
+
+// reportPendingPlan prints what trigger-pending would do with each pending
+// spawn without nudging or archiving anything.
+func reportPendingPlan(townRoot string, maxAge time.Duration, policy polecat.RetryPolicy, limits polecat.TriggerLimits) error {
+	plans, err := polecat.PlanPendingSpawns(townRoot, triggerTimeout, maxAge, policy, limits)
+	if err != nil {
+		return err
+	}
+	counts := make(map[polecat.PendingAction]int)
+	for _, p := range plans {
+		counts[p.Action]++
+		icon, verb := style.Dim.Render("○"), "would "+string(p.Action)
+		switch p.Action {
+		case polecat.PendingNudge:
+			icon = style.Bold.Render("→")
+		case polecat.PendingEscalate:
+			icon = style.Warning.Render("⚠")
+		case polecat.PendingBackoff:
+			verb = "backing off"
+		case polecat.PendingHold:
+			verb = fmt.Sprintf("would hold (queue position %d)", p.QueuePosition)
+		case polecat.PendingError:
+			icon, verb = style.Warning.Render("⚠"), "can't check"
+		}
+		fmt.Printf("  %s %s %s/%s (%s): %s\n",
+			icon, verb, p.Spawn.Rig, p.Spawn.Polecat, p.Spawn.Session, p.Reason)
+	}
+	fmt.Printf("%s Dry run: %d to nudge, %d to hold, %d to escalate, %d to archive, %d waiting, %d backing off; nothing was sent\n",
+		style.Dim.Render("○"), counts[polecat.PendingNudge], counts[polecat.PendingHold], counts[polecat.PendingEscalate],
+		counts[polecat.PendingArchive], counts[polecat.PendingWait], counts[polecat.PendingBackoff])
+	return nil
+}
+
 // runDeaconHealthCheck implements the health-check command.
 // It sends a HEALTH_CHECK nudge to an agent, waits for response, and tracks state.
 func runDeaconHealthCheck(cmd *cobra.Command, args []string) error {
@@ -855,6 +1170,9 @@ func runDeaconHealthCheck(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
+	if deaconPausedSkip(townRoot, "health-checking "+agent) {
+		return nil
+	}
 
 	// Load health check state
 	state, err := deacon.LoadHealthCheckState(townRoot)
@@ -1039,11 +1357,31 @@ func runDeaconForceKill(cmd *cobra.Command, args []string) error {
 	fmt.Printf("%s Updating agent bead state to 'killed'...\n", style.Dim.Render("3."))
 	updateAgentBeadState(townRoot, agent, "killed", reason)
 
+	_ = deacon.AppendLog(townRoot, deacon.LogEntry{
+		Event:   deacon.LogEventKill,
+		Message: reason,
+		Fields:  map[string]string{"agent": agent, "session": sessionName},
+	})
+	if err := deacon.Notify(townRoot, deacon.Notification{
+		Event:   deacon.NotifyEventEscalation,
+		Key:     "kill:" + agent,
+		Title:   "Deacon force-killed " + agent,
+		Message: reason,
+		Fields:  map[string]string{"agent": agent, "session": sessionName},
+	}); err != nil {
+		style.PrintWarning("notification failed: %v", err)
+	}
+
 	// Step 4: Notify mayor (optional)
 	if !forceKillSkipNotify {
 		fmt.Printf("%s Notifying mayor...\n", style.Dim.Render("4."))
 		notifyBody := fmt.Sprintf("Agent %s was force-killed by Deacon.\nReason: %s", agent, reason)
 		sendMail(townRoot, "mayor/", "Agent killed: "+agent, notifyBody)
+		_ = deacon.AppendLog(townRoot, deacon.LogEntry{
+			Event:   deacon.LogEventEscalation,
+			Message: "notified Mayor of force-kill",
+			Fields:  map[string]string{"agent": agent},
+		})
 	}
 
 	// Record force-kill in state
@@ -1308,6 +1646,7 @@ func runDeaconPause(cmd *cobra.Command, args []string) error {
 	if err := deacon.Pause(townRoot, pauseReason, "human"); err != nil {
 		return fmt.Errorf("pausing Deacon: %w", err)
 	}
+	_ = deacon.AppendLog(townRoot, deacon.LogEntry{Event: deacon.LogEventPause, Message: pauseReason})
 
 	fmt.Printf("%s Deacon paused\n", style.Bold.Render("⏸️"))
 	if pauseReason != "" {
@@ -1321,6 +1660,22 @@ func runDeaconPause(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// deaconPausedSkip reports whether the Deacon is paused, printing that
+// the given action is skipped if so. Commands that trigger or poke other
+// agents call it first; an unreadable pause file doesn't block them.
+func deaconPausedSkip(townRoot, action string) bool {
+	paused, state, _ := deacon.IsPaused(townRoot)
+	if !paused {
+		return false
+	}
+	fmt.Printf("%s Deacon is paused, not %s\n", style.Bold.Render("⏸️"), action)
+	if state.Reason != "" {
+		fmt.Printf("  Reason: %s\n", state.Reason)
+	}
+	fmt.Printf("  Resume with: %s\n", style.Dim.Render("gt deacon resume"))
+	return true
+}
+
 // runDeaconResume resumes the Deacon to allow patrol actions.
 func runDeaconResume(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwdOrError()
@@ -1342,6 +1697,10 @@ func runDeaconResume(cmd *cobra.Command, args []string) error {
 	if err := deacon.Resume(townRoot); err != nil {
 		return fmt.Errorf("resuming Deacon: %w", err)
 	}
+	if err := deacon.TouchWithAction(townRoot, "resumed", 0, 0); err != nil {
+		style.PrintWarning("could not refresh heartbeat: %v", err)
+	}
+	_ = deacon.AppendLog(townRoot, deacon.LogEntry{Event: deacon.LogEventResume})
 
 	fmt.Printf("%s Deacon resumed\n", style.Bold.Render("▶️"))
 	fmt.Println("The Deacon can now perform patrol actions.")
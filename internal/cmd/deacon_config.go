@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var deaconConfigJSON bool
+
+var deaconConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and update the Deacon's cadence and thresholds",
+	Long: `View and update the Deacon's configuration in <town>/deacon/config.json.
+
+Keys:
+  heartbeat_stale        Heartbeat age at which it stops being fresh (default 5m)
+  heartbeat_very_stale   Heartbeat age at which the daemon checks on the Deacon (default 15m)
+  stuck_restart          Heartbeat age past which a stuck Deacon is restarted, not nudged (default 10m)
+  pending_spawn_max_age  How long a pending spawn waits before it is escalated (default 5m)
+  pending_spawn_max_attempts
+                         Failed readiness checks before a pending spawn is escalated (default 5)
+  pending_spawn_backoff  Delay after a spawn's first failed readiness check, doubling (default 10s)
+  max_polecats_per_rig   Polecats working at once per rig; ready spawns past it wait (default 0, no cap)
+  max_triggers_per_minute
+                         Pending spawns triggered per minute, town-wide (default 0, no limit)
+  capture_lines          Pane lines kept in a reaped one-shot session's summary (default 10)
+  notify_cooldown        Minimum time between repeats of a notification (default 30m)
+
+Durations are Go duration strings (30s, 5m, 1h). Unset keys use the defaults.
+Notification sinks live in the same file; manage them with 'gt deacon notify'.
+The daemon and trigger-pending read the file on each run; no restart needed.
+
+Examples:
+  gt deacon config                            # Show effective values
+  gt deacon config set heartbeat_very_stale 20m
+  gt deacon config unset heartbeat_very_stale # Back to the default`,
+	Args: cobra.NoArgs,
+	RunE: runDeaconConfigShow,
+}
+
+var deaconConfigSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a Deacon config value",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runDeaconConfigSet,
+}
+
+var deaconConfigUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Reset a Deacon config value to its default",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDeaconConfigUnset,
+}
+
+func init() {
+	deaconConfigCmd.Flags().BoolVar(&deaconConfigJSON, "json", false, "Output as JSON")
+	deaconConfigCmd.AddCommand(deaconConfigSetCmd)
+	deaconConfigCmd.AddCommand(deaconConfigUnsetCmd)
+	deaconCmd.AddCommand(deaconConfigCmd)
+}
+
+// deaconConfigValue is one effective setting in 'gt deacon config' output.
+type deaconConfigValue struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"` // "file" or "default"
+}
+
+func runDeaconConfigShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := deacon.LoadConfig(townRoot)
+	if err != nil {
+		return err
+	}
+
+	var values []deaconConfigValue
+	for _, key := range deacon.ConfigKeys() {
+		v, _ := cfg.Get(key)
+		source := "default"
+		if cfg.IsSet(key) {
+			source = "file"
+		}
+		values = append(values, deaconConfigValue{Key: key, Value: v, Source: source})
+	}
+
+	if deaconConfigJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(values)
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Deacon config: "+deacon.ConfigFile(townRoot)))
+	fmt.Printf("  %-22s %-10s %s\n", "Key", "Value", "Source")
+	for _, v := range values {
+		source := v.Source
+		if source == "default" {
+			source = style.Dim.Render(source)
+		}
+		fmt.Printf("  %-22s %-10s %s\n", v.Key, v.Value, source)
+	}
+	return nil
+}
+
+func runDeaconConfigSet(cmd *cobra.Command, args []string) error {
+	return updateDeaconConfig(args[0], args[1])
+}
+
+func runDeaconConfigUnset(cmd *cobra.Command, args []string) error {
+	return updateDeaconConfig(args[0], "")
+}
+
+// updateDeaconConfig sets key (or resets it when value is empty) and saves
+// the file. An invalid file is refused rather than overwritten.
+func updateDeaconConfig(key, value string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := deacon.LoadConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Set(key, value); err != nil {
+		return err
+	}
+	if err := deacon.SaveConfig(townRoot, cfg); err != nil {
+		return fmt.Errorf("saving deacon config: %w", err)
+	}
+
+	effective, _ := cfg.Get(key)
+	if value == "" {
+		fmt.Printf("%s %s reset to default (%s)\n", style.Bold.Render("✓"), key, effective)
+	} else {
+		fmt.Printf("%s %s = %s\n", style.Bold.Render("✓"), key, effective)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Deacon events flags
+var (
+	deaconEventsTypes  []string
+	deaconEventsSince  string
+	deaconEventsTail   int
+	deaconEventsFollow bool
+	deaconEventsJSON   bool
+)
+
+var deaconEventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Tail and filter the Deacon's event journal",
+	Long: `Show what the Deacon actually did, filtered by event type.
+
+Every significant Deacon action is journaled as a JSON line in
+<town>/deacon/logs/deacon.log (see 'gt deacon logs'):
+
+  heartbeat        Deacon heartbeat at the top of a patrol cycle
+  wake             Daemon nudged, restarted, or started the Deacon
+  spawn            Pending polecat spawn triggered
+  pending-cleared  Stale pending spawns pruned without being triggered
+  escalation       Problem handed to the Mayor (failed re-dispatches, force-kill)
+  kill             Unresponsive agent force-killed
+  pause, resume    Deacon paused or resumed
+
+--type may be repeated or comma-separated. --json prints the raw journal
+records, one per line, for scripts.
+
+Examples:
+  gt deacon events --type escalation,kill --since 24h
+  gt deacon events --type spawn -f
+  gt deacon events --json | jq 'select(.event == "spawn")'`,
+	Args: cobra.NoArgs,
+	RunE: runDeaconEvents,
+}
+
+func init() {
+	deaconEventsCmd.Flags().StringSliceVarP(&deaconEventsTypes, "type", "t", nil, "Only show these event types (repeatable)")
+	deaconEventsCmd.Flags().StringVar(&deaconEventsSince, "since", "", "Show events since a duration ago (e.g. 1h) or an RFC 3339 time")
+	deaconEventsCmd.Flags().IntVarP(&deaconEventsTail, "tail", "n", 50, "Number of events to show when --since is not set (0 for all)")
+	deaconEventsCmd.Flags().BoolVarP(&deaconEventsFollow, "follow", "f", false, "Follow new events (like tail -f)")
+	deaconEventsCmd.Flags().BoolVar(&deaconEventsJSON, "json", false, "Output raw JSON lines")
+	deaconCmd.AddCommand(deaconEventsCmd)
+}
+
+func runDeaconEvents(cmd *cobra.Command, args []string) error {
+	if err := validateDeaconEventTypes(deaconEventsTypes); err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	since, err := parseDeaconLogsSince(deaconEventsSince, time.Now())
+	if err != nil {
+		return err
+	}
+
+	entries, err := deacon.ReadLog(townRoot, since)
+	if err != nil {
+		return fmt.Errorf("reading deacon log: %w", err)
+	}
+	entries = deacon.FilterLogEntries(entries, deaconEventsTypes)
+	if since.IsZero() && deaconEventsTail > 0 && len(entries) > deaconEventsTail {
+		entries = entries[len(entries)-deaconEventsTail:]
+	}
+
+	emit := func(e deacon.LogEntry) {
+		if deaconEventsJSON {
+			data, _ := json.Marshal(e)
+			fmt.Println(string(data))
+			return
+		}
+		fmt.Println(formatDeaconLogEntry(e))
+	}
+
+	if len(entries) == 0 && !deaconEventsFollow && !deaconEventsJSON {
+		fmt.Printf("%s No matching Deacon events\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, e := range entries {
+		emit(e)
+	}
+
+	if deaconEventsFollow {
+		return followDeaconLog(townRoot, func(e deacon.LogEntry) {
+			if len(deacon.FilterLogEntries([]deacon.LogEntry{e}, deaconEventsTypes)) > 0 {
+				emit(e)
+			}
+		})
+	}
+	return nil
+}
+
+// validateDeaconEventTypes rejects --type values that aren't event kinds,
+// so a typo doesn't silently filter out everything.
+func validateDeaconEventTypes(types []string) error {
+	for _, t := range types {
+		known := false
+		for _, e := range deacon.LogEvents {
+			if t == e {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown event type %q (valid: %s)", t, strings.Join(deacon.LogEvents, ", "))
+		}
+	}
+	return nil
+}
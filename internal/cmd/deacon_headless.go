@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cli"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// headlessInteractiveLimits bound the Claude session the headless loop
+// starts for mail that needs judgment. It handles the mail and exits; the
+// daemon's session_reaper cleans up one that doesn't.
+var headlessInteractiveLimits = session.OneShotLimits{
+	MaxLifetime: time.Hour,
+	IdleTimeout: 15 * time.Minute,
+}
+
+// headlessPatrolSteps are the mechanical patrol steps the headless loop runs
+// each cycle, as gt subcommands so one failing step can't take the loop down.
+// The tmux-process cleanups only run when tmux is available.
+var headlessPatrolSteps = []struct {
+	args      []string
+	needsTmux bool
+}{
+	{args: []string{"deacon", "trigger-pending"}},
+	{args: []string{"deacon", "stale-hooks"}},
+	{args: []string{"deacon", "cleanup-orphans"}, needsTmux: true},
+	{args: []string{"deacon", "zombie-scan"}, needsTmux: true},
+}
+
+var (
+	deaconStartHeadless    bool
+	deaconHeadlessInterval time.Duration
+)
+
+var deaconRunHeadlessCmd = &cobra.Command{
+	Use:    "run-headless",
+	Hidden: true,
+	Short:  "Run the Deacon's mechanical patrol loop (started by the daemon)",
+	Long: `Run the Deacon's patrol loop as a plain process, without tmux.
+
+Each cycle writes the heartbeat, triggers pending spawns, unhooks stale
+hooks, and (when tmux is available) cleans up orphaned and zombie Claude
+processes. Mail that needs judgment brings up a short-lived interactive
+Deacon session in tmux; without tmux it is logged and left for a human.
+
+This is started and supervised by the daemon after 'gt deacon start
+--headless'; it is not normally run by hand.`,
+	Args: cobra.NoArgs,
+	RunE: runDeaconRunHeadless,
+}
+
+func init() {
+	deaconStartCmd.Flags().BoolVar(&deaconStartHeadless, "headless", false,
+		"Run the patrol loop as a daemon-supervised process instead of a tmux session")
+	deaconRunHeadlessCmd.Flags().DurationVar(&deaconHeadlessInterval, "interval", 2*time.Minute,
+		"Time between patrol cycles")
+	deaconCmd.AddCommand(deaconRunHeadlessCmd)
+}
+
+// startHeadlessDeacon selects headless mode and starts the loop right away
+// rather than waiting for the daemon's next heartbeat to notice.
+func startHeadlessDeacon(t *tmux.Tmux, sessionName string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if p := daemon.HeadlessDeaconProcess(townRoot); p != nil {
+		return fmt.Errorf("headless Deacon already running (PID %d)", p.Pid)
+	}
+	if t.IsAvailable() {
+		if running, _ := t.HasSession(sessionName); running {
+			return fmt.Errorf("Deacon tmux session is running; stop it first with: gt deacon stop")
+		}
+	}
+
+	if err := deacon.WriteHeadlessState(townRoot, &deacon.HeadlessState{}); err != nil {
+		return fmt.Errorf("selecting headless mode: %w", err)
+	}
+	gtPath, err := os.Executable()
+	if err != nil {
+		gtPath = cli.Name()
+	}
+	pid, err := daemon.StartHeadlessDeacon(townRoot, gtPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Headless Deacon started (PID %d)\n", style.Bold.Render("✓"), pid)
+	fmt.Printf("  %s\n", style.Dim.Render("Supervised by the daemon; 'gt deacon start' without --headless switches back to tmux"))
+	fmt.Printf("  %s\n", style.Dim.Render("Logs: gt deacon logs -f"))
+	return nil
+}
+
+// leaveHeadlessMode stops a headless loop and clears headless mode before
+// the Deacon is started in tmux.
+func leaveHeadlessMode(townRoot string) error {
+	if !deacon.IsHeadless(townRoot) {
+		return nil
+	}
+	fmt.Println("Switching Deacon from headless to tmux...")
+	if err := daemon.StopHeadlessDeacon(townRoot, 5*time.Second); err != nil {
+		return fmt.Errorf("stopping headless Deacon: %w", err)
+	}
+	return deacon.ClearHeadless(townRoot)
+}
+
+func runDeaconRunHeadless(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	gtPath, err := os.Executable()
+	if err != nil {
+		gtPath = cli.Name()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	t := tmux.NewTmux()
+	fmt.Printf("%s headless Deacon loop started (interval %v)\n", time.Now().Format(time.RFC3339), deaconHeadlessInterval)
+	for {
+		runHeadlessCycle(ctx, townRoot, gtPath, t)
+
+		select {
+		case <-ctx.Done():
+			fmt.Printf("%s headless Deacon loop stopping\n", time.Now().Format(time.RFC3339))
+			return nil
+		case <-time.After(deaconHeadlessInterval):
+		}
+	}
+}
+
+// runHeadlessCycle runs one patrol cycle of the headless loop.
+func runHeadlessCycle(ctx context.Context, townRoot, gtPath string, t *tmux.Tmux) {
+	if paused, _, _ := deacon.IsPaused(townRoot); paused {
+		// Keep the heartbeat fresh so the daemon doesn't restart a loop
+		// that is only idling.
+		_ = deacon.TouchWithAction(townRoot, "paused", 0, 0)
+		return
+	}
+
+	if err := deacon.TouchWithAction(townRoot, "headless patrol", 0, 0); err != nil {
+		fmt.Fprintf(os.Stderr, "heartbeat: %v\n", err)
+	}
+	entry := deacon.LogEntry{Event: deacon.LogEventHeartbeat, Message: "headless patrol"}
+	if hb := deacon.ReadHeartbeat(townRoot); hb != nil {
+		entry.Cycle = hb.Cycle
+	}
+	_ = deacon.AppendLog(townRoot, entry)
+
+	tmuxAvailable := t.IsAvailable()
+	for _, step := range headlessPatrolSteps {
+		if ctx.Err() != nil {
+			return
+		}
+		if step.needsTmux && !tmuxAvailable {
+			continue
+		}
+		c := exec.CommandContext(ctx, gtPath, step.args...) //nolint:gosec // G204: fixed gt subcommands
+		c.Dir = filepath.Join(townRoot, "deacon")
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		if err := c.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", step.args, err)
+		}
+	}
+
+	escalateHeadlessMail(townRoot, t, tmuxAvailable)
+}
+
+// escalateHeadlessMail brings up a short-lived interactive Deacon for mail
+// the loop can't handle mechanically.
+func escalateHeadlessMail(townRoot string, t *tmux.Tmux, tmuxAvailable bool) {
+	mailbox, err := mail.NewRouter(townRoot).GetMailbox("deacon/")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "deacon mailbox: %v\n", err)
+		return
+	}
+	msgs, err := mailbox.ListUnread()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "deacon mailbox: %v\n", err)
+		return
+	}
+	pending := deacon.NeedsInteractive(msgs)
+	if len(pending) == 0 {
+		return
+	}
+
+	sessionName := getDeaconSessionName()
+	if !tmuxAvailable {
+		_ = deacon.AppendLog(townRoot, deacon.LogEntry{
+			Event:   deacon.LogEventWake,
+			Message: fmt.Sprintf("%d message(s) need an interactive Deacon but tmux is unavailable", len(pending)),
+		})
+		return
+	}
+	if running, _ := t.HasSession(sessionName); running {
+		return // Already handling it
+	}
+
+	_, err = session.StartSession(t, session.SessionConfig{
+		SessionID: sessionName,
+		WorkDir:   filepath.Join(townRoot, "deacon"),
+		Role:      "deacon",
+		TownRoot:  townRoot,
+		Beacon: session.BeaconConfig{
+			Recipient: "deacon",
+			Sender:    "deacon/headless",
+			Topic:     "inbox",
+		},
+		Instructions: fmt.Sprintf("I am Deacon, brought up by the headless patrol loop to handle %d message(s) that need judgment. "+
+			"Run `"+cli.Name()+" mail inbox` and handle each non-POLECAT_STARTED message. The headless loop runs the patrol; do not start mol-deacon-patrol. "+
+			"Exit when the inbox is handled.", len(pending)),
+		OneShot:      &headlessInteractiveLimits,
+		AcceptBypass: true,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "starting interactive deacon: %v\n", err)
+		return
+	}
+	_ = deacon.AppendLog(townRoot, deacon.LogEntry{
+		Event:   deacon.LogEventWake,
+		Message: fmt.Sprintf("started interactive Deacon for %d message(s)", len(pending)),
+		Fields:  map[string]string{"by": "headless", "first": pending[0].Subject},
+	})
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Deacon heartbeat flags
+var (
+	deaconHeartbeatStatus bool
+	deaconHeartbeatJSON   bool
+	deaconHeartbeatLast   int
+)
+
+func init() {
+	deaconHeartbeatCmd.Flags().BoolVar(&deaconHeartbeatStatus, "status", false, "Inspect the heartbeat instead of updating it")
+	deaconHeartbeatCmd.Flags().BoolVar(&deaconHeartbeatJSON, "json", false, "Output --status as JSON")
+	deaconHeartbeatCmd.Flags().IntVarP(&deaconHeartbeatLast, "last", "n", 10, "Number of recent Deacon actions to show with --status")
+}
+
+// DeaconHeartbeatReport is the output of gt deacon heartbeat --status.
+type DeaconHeartbeatReport struct {
+	Heartbeat *HeartbeatStatus `json:"heartbeat,omitempty"`
+
+	// Tolerance is the very-stale threshold: past it the daemon steps in.
+	Tolerance       string `json:"tolerance"`
+	WithinTolerance bool   `json:"within_tolerance"`
+
+	// SessionRunning reports whether the Deacon's tmux session exists,
+	// which on its own says nothing about whether the agent in it works.
+	SessionRunning bool `json:"session_running"`
+	Paused         bool `json:"paused"`
+
+	// Alive is a heartbeat within tolerance whose owner, if known, is
+	// still running.
+	Alive   bool              `json:"alive"`
+	Verdict string            `json:"verdict"`
+	Recent  []deacon.LogEntry `json:"recent"`
+}
+
+// deaconHeartbeatOwner identifies the Deacon agent writing a heartbeat with
+// 'gt deacon heartbeat': the pane process of its tmux session. The gt
+// process itself exits at once, so its PID would say nothing about
+// liveness. Outside the Deacon's session the owner is left unknown.
+func deaconHeartbeatOwner() deacon.HeartbeatOwner {
+	if os.Getenv("GT_ROLE") != "deacon" || os.Getenv("TMUX") == "" {
+		return deacon.HeartbeatOwner{}
+	}
+	name := getDeaconSessionName()
+	owner := deacon.HeartbeatOwner{Session: name}
+	if pid, err := tmux.NewTmux().GetPanePID(name); err == nil {
+		owner.PID, _ = strconv.Atoi(pid)
+	}
+	return owner
+}
+
+// heartbeatStatusFor converts a heartbeat for display, checking whether its
+// owner process is still running.
+func heartbeatStatusFor(hb *deacon.Heartbeat, cfg *deacon.Config) *HeartbeatStatus {
+	st := &HeartbeatStatus{
+		Timestamp:   hb.Timestamp,
+		AgeSec:      hb.Age().Seconds(),
+		Cycle:       hb.Cycle,
+		LastAction:  hb.LastAction,
+		Fresh:       cfg.IsFresh(hb),
+		Stale:       cfg.IsStale(hb),
+		VeryStale:   cfg.IsVeryStale(hb),
+		PID:         hb.PID,
+		Session:     hb.Session,
+		OwnerCycles: hb.OwnerCycles,
+		Restarts:    hb.Restarts,
+	}
+	if !hb.StartedAt.IsZero() {
+		started := hb.StartedAt
+		st.StartedAt = &started
+	}
+	if hb.PID > 0 {
+		alive := isProcessRunning(hb.PID)
+		st.PIDAlive = &alive
+	}
+	return st
+}
+
+// deaconHeartbeatVerdict sums up whether the Deacon is alive, telling a
+// working Deacon apart from one that only has a tmux session.
+func deaconHeartbeatVerdict(r *DeaconHeartbeatReport) string {
+	hb := r.Heartbeat
+	switch {
+	case hb == nil && r.SessionRunning:
+		return "session exists but the Deacon has never written a heartbeat"
+	case hb == nil:
+		return "no heartbeat: the Deacon has never run here"
+	case hb.PIDAlive != nil && !*hb.PIDAlive:
+		return fmt.Sprintf("heartbeat owner PID %d is gone: the Deacon died", hb.PID)
+	case hb.VeryStale && r.SessionRunning:
+		return "session exists but the heartbeat is very stale: the Deacon is hung or idle"
+	case hb.VeryStale:
+		return "heartbeat is very stale and no session is running: the Deacon is down"
+	case hb.Stale:
+		return "alive but slow: no heartbeat for a while, likely a long operation"
+	default:
+		return "alive"
+	}
+}
+
+func runDeaconHeartbeatStatus(townRoot string) error {
+	cfg, err := deacon.LoadConfigOrDefault(townRoot)
+	if err != nil {
+		return err
+	}
+	report := &DeaconHeartbeatReport{
+		Tolerance: cfg.GetHeartbeatVeryStale().String(),
+		Recent:    []deacon.LogEntry{},
+	}
+	if hb := deacon.ReadHeartbeat(townRoot); hb != nil {
+		report.Heartbeat = heartbeatStatusFor(hb, cfg)
+		report.WithinTolerance = !report.Heartbeat.VeryStale
+		report.Alive = report.WithinTolerance && (report.Heartbeat.PIDAlive == nil || *report.Heartbeat.PIDAlive)
+	}
+	if !deacon.IsHeadless(townRoot) {
+		report.SessionRunning, _ = tmux.NewTmux().HasSession(getDeaconSessionName())
+	}
+	report.Paused, _, _ = deacon.IsPaused(townRoot)
+	report.Verdict = deaconHeartbeatVerdict(report)
+
+	if deaconHeartbeatLast > 0 {
+		entries, err := deacon.ReadLog(townRoot, time.Time{})
+		if err != nil {
+			return fmt.Errorf("reading deacon log: %w", err)
+		}
+		if len(entries) > deaconHeartbeatLast {
+			entries = entries[len(entries)-deaconHeartbeatLast:]
+		}
+		report.Recent = append(report.Recent, entries...)
+	}
+
+	if deaconHeartbeatJSON {
+		if err := outputJSON(report); err != nil {
+			return err
+		}
+	} else {
+		printDeaconHeartbeatReport(report)
+	}
+	if !report.Alive {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func printDeaconHeartbeatReport(r *DeaconHeartbeatReport) {
+	hb := r.Heartbeat
+	mark := style.SuccessPrefix
+	if !r.Alive {
+		mark = style.ErrorPrefix
+	} else if hb.Stale {
+		mark = style.WarningPrefix
+	}
+	fmt.Printf("%s Deacon: %s\n", mark, r.Verdict)
+	if r.Paused {
+		fmt.Printf("  %s\n", style.Dim.Render("Deacon is paused: heartbeats continue, patrol actions are suspended"))
+	}
+
+	if hb != nil {
+		age := time.Duration(hb.AgeSec * float64(time.Second)).Round(time.Second)
+		state := "fresh"
+		switch {
+		case hb.VeryStale:
+			state = style.Error.Render("very stale")
+		case hb.Stale:
+			state = style.Warning.Render("stale")
+		}
+		fmt.Printf("  Heartbeat: %s ago, %s (tolerance %s)\n", age, state, r.Tolerance)
+		fmt.Printf("  Cycle:     %d", hb.Cycle)
+		if hb.OwnerCycles > 0 {
+			fmt.Printf(" (%d from this owner, %d restart(s))", hb.OwnerCycles, hb.Restarts)
+		}
+		fmt.Println()
+		if hb.LastAction != "" {
+			fmt.Printf("  Action:    %s\n", hb.LastAction)
+		}
+		switch {
+		case hb.PID > 0 && *hb.PIDAlive:
+			fmt.Printf("  Owner:     PID %d (running)", hb.PID)
+		case hb.PID > 0:
+			fmt.Printf("  Owner:     PID %d (%s)", hb.PID, style.Error.Render("not running"))
+		default:
+			fmt.Printf("  Owner:     %s", style.Dim.Render("unknown"))
+		}
+		if hb.Session != "" {
+			fmt.Printf(" in %s", hb.Session)
+		}
+		if hb.StartedAt != nil {
+			fmt.Printf(", since %s", hb.StartedAt.Local().Format("2006-01-02 15:04:05"))
+		}
+		fmt.Println()
+	}
+	if r.SessionRunning {
+		fmt.Printf("  Session:   running\n")
+	} else {
+		fmt.Printf("  Session:   %s\n", style.Dim.Render("not running"))
+	}
+
+	if len(r.Recent) > 0 {
+		fmt.Printf("\nLast %d action(s):\n", len(r.Recent))
+		for _, e := range r.Recent {
+			fmt.Println(style.FitLine("  " + formatDeaconLogEntry(e)))
+		}
+	}
+}
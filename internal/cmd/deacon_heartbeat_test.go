@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeaconHeartbeatVerdict(t *testing.T) {
+	alive, dead := true, false
+	tests := []struct {
+		name   string
+		report DeaconHeartbeatReport
+		want   string
+	}{
+		{"no heartbeat", DeaconHeartbeatReport{}, "never run"},
+		{"session only", DeaconHeartbeatReport{SessionRunning: true}, "never written a heartbeat"},
+		{"fresh", DeaconHeartbeatReport{SessionRunning: true, Heartbeat: &HeartbeatStatus{Fresh: true, PID: 42, PIDAlive: &alive}}, "alive"},
+		{"stale", DeaconHeartbeatReport{Heartbeat: &HeartbeatStatus{Stale: true}}, "alive but slow"},
+		{"hung", DeaconHeartbeatReport{SessionRunning: true, Heartbeat: &HeartbeatStatus{VeryStale: true, PID: 42, PIDAlive: &alive}}, "hung or idle"},
+		{"down", DeaconHeartbeatReport{Heartbeat: &HeartbeatStatus{VeryStale: true}}, "the Deacon is down"},
+		{"owner gone", DeaconHeartbeatReport{SessionRunning: true, Heartbeat: &HeartbeatStatus{Fresh: true, PID: 42, PIDAlive: &dead}}, "PID 42 is gone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := deaconHeartbeatVerdict(&tt.report)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("verdict = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
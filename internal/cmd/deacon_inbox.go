@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	deaconInboxStatsJSON  bool
+	deaconInboxStatsReset bool
+)
+
+var deaconInboxStatsCmd = &cobra.Command{
+	Use:   "inbox-stats",
+	Short: "Show how much of the Deacon's inbox each scan re-reads",
+	Long: `Show the Deacon inbox cursor and what pending spawn scans have cost.
+
+Every trigger-pending pass, daemon heartbeat and 'gt deacon pending' lists
+the Deacon's inbox to find POLECAT_STARTED mail. The cursor remembers each
+open message already parsed, so a scan only reads mail that arrived since
+the last one; messages that are archived drop out of the cursor. When a
+session is announced more than once, only the newest message counts and
+the duplicates are archived with it.
+
+Counters:
+  parsed      Messages read for the first time
+  skipped     Messages already processed, not re-parsed
+  duplicates  Extra POLECAT_STARTED mail for a session already pending
+
+Use --reset to drop the cursor; the next scan parses the whole inbox once
+and rebuilds it.`,
+	Args: cobra.NoArgs,
+	RunE: runDeaconInboxStats,
+}
+
+func init() {
+	deaconInboxStatsCmd.Flags().BoolVar(&deaconInboxStatsJSON, "json", false, "Output as JSON")
+	deaconInboxStatsCmd.Flags().BoolVar(&deaconInboxStatsReset, "reset", false, "Drop the cursor so the next scan starts over")
+	deaconCmd.AddCommand(deaconInboxStatsCmd)
+}
+
+func runDeaconInboxStats(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if deaconInboxStatsReset {
+		if err := polecat.ResetInboxCursor(townRoot); err != nil {
+			return fmt.Errorf("resetting inbox cursor: %w", err)
+		}
+		fmt.Printf("%s Inbox cursor reset; the next scan parses the whole inbox\n", style.Bold.Render("✓"))
+		return nil
+	}
+
+	st := polecat.ReadInboxCursor(townRoot)
+	if deaconInboxStatsJSON {
+		return outputJSON(st)
+	}
+
+	if st.Stats.Scans == 0 {
+		fmt.Printf("%s No inbox scans recorded yet\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	fmt.Printf("%s Deacon inbox cursor\n", style.Bold.Render("●"))
+	if st.LastID != "" {
+		fmt.Printf("  Newest message: %s %s\n", st.LastID,
+			style.Dim.Render("("+st.LastTimestamp.Local().Format("2006-01-02 15:04:05")+")"))
+	}
+	fmt.Printf("  Tracked:        %d open message(s), %d POLECAT_STARTED\n", st.Tracked, st.Spawns)
+
+	s := st.Stats
+	fmt.Printf("\n%s %s\n", style.Bold.Render("Last scan"),
+		style.Dim.Render(fmt.Sprintf("(%s ago)", time.Since(s.LastScan).Round(time.Second))))
+	fmt.Printf("  %d message(s): %d parsed, %d skipped; %d duplicate(s); %d pending spawn(s)\n",
+		s.LastMessages, s.LastParsed, s.LastSkipped, s.LastDuplicates, s.LastPending)
+
+	fmt.Printf("\n%s %s\n", style.Bold.Render("All scans"), style.Dim.Render(fmt.Sprintf("(%d)", s.Scans)))
+	fmt.Printf("  Parsed:     %d\n", s.Parsed)
+	fmt.Printf("  Skipped:    %d", s.Skipped)
+	if total := s.Parsed + s.Skipped; total > 0 {
+		fmt.Printf(" %s", style.Dim.Render(fmt.Sprintf("(%.0f%% of messages not re-parsed)", 100*float64(s.Skipped)/float64(total))))
+	}
+	fmt.Println()
+	fmt.Printf("  Duplicates: %d\n", s.Duplicates)
+	return nil
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// deaconLogsPollInterval is how often --follow checks the log for new lines.
+const deaconLogsPollInterval = 500 * time.Millisecond
+
+// Deacon logs flags
+var (
+	deaconLogsFollow bool
+	deaconLogsSince  string
+	deaconLogsTail   int
+)
+
+var deaconLogsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Show the Deacon's structured log",
+	Long: `Show the Deacon's log of wake cycles, triggered spawns, and heartbeats.
+
+The Deacon runs inside tmux and its scrollback is lost when the session
+restarts. Its heartbeats, the spawns it triggers, pruned pending spawns,
+escalations to the Mayor, force-kills, pauses, and every time the daemon
+wakes it (nudge, restart, fresh start) are appended to
+<town>/deacon/logs/deacon.log as JSON lines. The log rotates at 5 MB,
+keeping three old files. Use 'gt deacon events' to filter by event type.
+
+--since accepts a duration (1h, 30m) or an RFC 3339 timestamp.
+
+Examples:
+  gt deacon logs                # Last 50 entries
+  gt deacon logs --since 2h     # Everything from the last two hours
+  gt deacon logs -f             # Follow new entries`,
+	Args: cobra.NoArgs,
+	RunE: runDeaconLogs,
+}
+
+func init() {
+	deaconLogsCmd.Flags().BoolVarP(&deaconLogsFollow, "follow", "f", false, "Follow new log entries (like tail -f)")
+	deaconLogsCmd.Flags().StringVar(&deaconLogsSince, "since", "", "Show entries since a duration ago (e.g. 1h) or an RFC 3339 time")
+	deaconLogsCmd.Flags().IntVarP(&deaconLogsTail, "tail", "n", 50, "Number of entries to show when --since is not set (0 for all)")
+}
+
+func runDeaconLogs(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	since, err := parseDeaconLogsSince(deaconLogsSince, time.Now())
+	if err != nil {
+		return err
+	}
+
+	entries, err := deacon.ReadLog(townRoot, since)
+	if err != nil {
+		return fmt.Errorf("reading deacon log: %w", err)
+	}
+	if since.IsZero() && deaconLogsTail > 0 && len(entries) > deaconLogsTail {
+		entries = entries[len(entries)-deaconLogsTail:]
+	}
+
+	if len(entries) == 0 && !deaconLogsFollow {
+		fmt.Printf("%s No Deacon log entries\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, e := range entries {
+		fmt.Println(formatDeaconLogEntry(e))
+	}
+
+	if deaconLogsFollow {
+		return followDeaconLog(townRoot, func(e deacon.LogEntry) {
+			fmt.Println(formatDeaconLogEntry(e))
+		})
+	}
+	return nil
+}
+
+// parseDeaconLogsSince turns --since into a cutoff time. Empty means no cutoff.
+func parseDeaconLogsSince(since string, now time.Time) (time.Time, error) {
+	if since == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(since); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: want a duration (1h) or RFC 3339 time", since)
+}
+
+// formatDeaconLogEntry renders one log entry on a single line.
+func formatDeaconLogEntry(e deacon.LogEntry) string {
+	var tag string
+	switch e.Event {
+	case deacon.LogEventHeartbeat:
+		tag = style.Dim.Render("[heartbeat]")
+	case deacon.LogEventWake:
+		tag = style.Warning.Render("[wake]")
+	case deacon.LogEventSpawn:
+		tag = style.Success.Render("[spawn]")
+	case deacon.LogEventEscalation, deacon.LogEventKill, deacon.LogEventFailover:
+		tag = style.Error.Render("[" + e.Event + "]")
+	default:
+		tag = fmt.Sprintf("[%s]", e.Event)
+	}
+
+	parts := []string{style.Dim.Render(e.Time.Local().Format("2006-01-02 15:04:05")), tag}
+	if e.Cycle > 0 {
+		parts = append(parts, fmt.Sprintf("cycle %d", e.Cycle))
+	}
+	if e.Message != "" {
+		parts = append(parts, e.Message)
+	}
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, style.Dim.Render(k+"="+e.Fields[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// followDeaconLog passes entries appended to the active log to emit until
+// interrupted, starting over from the top of the file when it rotates.
+func followDeaconLog(townRoot string, emit func(deacon.LogEntry)) error {
+	path := deacon.LogFile(townRoot)
+	var offset int64
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	ticker := time.NewTicker(deaconLogsPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sigCh:
+			return nil
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue // Not written yet, or mid-rotation
+		}
+		if info.Size() < offset {
+			offset = 0 // Rotated
+		}
+		if info.Size() == offset {
+			continue
+		}
+
+		f, err := os.Open(path) //nolint:gosec // G304: path is constructed from trusted townRoot
+		if err != nil {
+			continue
+		}
+		if _, err := f.Seek(offset, 0); err == nil {
+			entries, n, _ := deacon.ReadLogEntries(f, time.Time{})
+			offset += n
+			for _, e := range entries {
+				emit(e)
+			}
+		}
+		_ = f.Close()
+	}
+}
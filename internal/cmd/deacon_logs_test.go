@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/deacon"
+)
+
+func TestParseDeaconLogsSince(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{"", time.Time{}, false},
+		{"2h", now.Add(-2 * time.Hour), false},
+		{"2026-10-15T08:00:00Z", time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC), false},
+		{"yesterday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseDeaconLogsSince(tt.in, now)
+		if (err != nil) != tt.wantErr || !got.Equal(tt.want) {
+			t.Errorf("parseDeaconLogsSince(%q) = %v, %v; want %v (err %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFormatDeaconLogEntry(t *testing.T) {
+	line := formatDeaconLogEntry(deacon.LogEntry{
+		Time:    time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+		Event:   deacon.LogEventSpawn,
+		Message: "triggered pending spawn",
+		Fields:  map[string]string{"rig": "gastown", "polecat": "nux"},
+	})
+	for _, want := range []string{"[spawn]", "triggered pending spawn", "polecat=nux", "rig=gastown"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("line missing %q: %s", want, line)
+		}
+	}
+	if strings.Index(line, "polecat=") > strings.Index(line, "rig=") {
+		t.Errorf("fields should be sorted: %s", line)
+	}
+}
+
+func TestValidateDeaconEventTypes(t *testing.T) {
+	if err := validateDeaconEventTypes([]string{deacon.LogEventSpawn, deacon.LogEventPendingCleared}); err != nil {
+		t.Errorf("known types: %v", err)
+	}
+	if err := validateDeaconEventTypes([]string{"spwan"}); err == nil || !strings.Contains(err.Error(), "spwan") {
+		t.Errorf("typo: got %v, want unknown event type error", err)
+	}
+}
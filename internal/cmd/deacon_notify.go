@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	deaconNotifyJSON   bool
+	deaconNotifyEvents []string
+)
+
+var deaconNotifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Manage where the Deacon sends desktop and webhook notifications",
+	Long: `Manage notification sinks: where the Deacon and daemon send high-signal
+events so operators don't need to keep a terminal attached.
+
+Events:
+  escalation       A bead escalated to the Mayor after failed re-dispatches,
+                   or an agent force-killed
+  stale-heartbeat  The Deacon's or a Witness's heartbeat went very stale
+                   and the daemon is nudging or restarting it
+  spawn-failure    A pending polecat spawn never became ready
+  circuit          The model API became unreachable (town degraded) or
+                   reachable again
+
+Sinks:
+  desktop          notify-send on Linux, osascript on macOS; the daemon
+                   must run in the desktop session
+  webhook <url>    POSTs the event as JSON (event, title, message, town,
+                   time, fields)
+  slack <url>      Posts to a Slack incoming webhook
+
+Sinks are stored in deacon/config.json under "notify". The same event is
+sent at most once per notify_cooldown (default 30m; see 'gt deacon
+config'), so a condition that persists across patrols notifies once.
+
+Examples:
+  gt deacon notify add desktop
+  gt deacon notify add slack https://hooks.slack.com/services/T/B/X --events escalation,circuit
+  gt deacon notify add webhook https://ops.example.com/gastown
+  gt deacon notify test
+  gt deacon notify remove 2`,
+	RunE: requireSubcommand,
+}
+
+var deaconNotifyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List notification sinks",
+	Args:  cobra.NoArgs,
+	RunE:  runDeaconNotifyList,
+}
+
+var deaconNotifyAddCmd = &cobra.Command{
+	Use:   "add <desktop|webhook|slack> [url]",
+	Short: "Add a notification sink",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  runDeaconNotifyAdd,
+}
+
+var deaconNotifyRemoveCmd = &cobra.Command{
+	Use:   "remove <number>",
+	Short: "Remove a notification sink by its number in 'list'",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDeaconNotifyRemove,
+}
+
+var deaconNotifyTestCmd = &cobra.Command{
+	Use:   "test [message]",
+	Short: "Send a test notification to every sink",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runDeaconNotifyTest,
+}
+
+func init() {
+	deaconNotifyListCmd.Flags().BoolVar(&deaconNotifyJSON, "json", false, "Output as JSON")
+	deaconNotifyAddCmd.Flags().StringSliceVar(&deaconNotifyEvents, "events", nil, "Only send these events (default: all)")
+
+	deaconNotifyCmd.AddCommand(deaconNotifyListCmd)
+	deaconNotifyCmd.AddCommand(deaconNotifyAddCmd)
+	deaconNotifyCmd.AddCommand(deaconNotifyRemoveCmd)
+	deaconNotifyCmd.AddCommand(deaconNotifyTestCmd)
+	deaconCmd.AddCommand(deaconNotifyCmd)
+}
+
+func runDeaconNotifyList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := deacon.LoadConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	if deaconNotifyJSON {
+		sinks := cfg.Notify
+		if sinks == nil {
+			sinks = []deacon.NotifySinkConfig{}
+		}
+		return outputJSON(sinks)
+	}
+	if len(cfg.Notify) == 0 {
+		fmt.Printf("%s No notification sinks (add one with 'gt deacon notify add')\n", style.Dim.Render("○"))
+		return nil
+	}
+	for i, s := range cfg.Notify {
+		events := "all events"
+		if len(s.Events) > 0 {
+			events = strings.Join(s.Events, ", ")
+		}
+		target := s.Type
+		if s.URL != "" {
+			target += " " + deacon.RedactURL(s.URL)
+		}
+		fmt.Printf("%d. %s %s\n", i+1, target, style.Dim.Render("("+events+")"))
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("Cooldown between repeats: "+cfg.GetNotifyCooldown().String()))
+	return nil
+}
+
+func runDeaconNotifyAdd(cmd *cobra.Command, args []string) error {
+	sink := deacon.NotifySinkConfig{Type: args[0], Events: deaconNotifyEvents}
+	if len(args) > 1 {
+		sink.URL = args[1]
+	}
+	if sink.Type == deacon.NotifySinkDesktop && sink.URL != "" {
+		return fmt.Errorf("desktop sinks take no url")
+	}
+	n, err := updateDeaconNotify(func(cfg *deacon.Config) error {
+		cfg.Notify = append(cfg.Notify, sink)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Added notification sink %d: %s\n", style.Bold.Render("✓"), n, sink.Type)
+	fmt.Printf("  Check it with: %s\n", style.Dim.Render("gt deacon notify test"))
+	return nil
+}
+
+func runDeaconNotifyRemove(cmd *cobra.Command, args []string) error {
+	idx, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid sink number %q", args[0])
+	}
+	var removed deacon.NotifySinkConfig
+	_, err = updateDeaconNotify(func(cfg *deacon.Config) error {
+		if idx < 1 || idx > len(cfg.Notify) {
+			return fmt.Errorf("no notification sink %d (have %d)", idx, len(cfg.Notify))
+		}
+		removed = cfg.Notify[idx-1]
+		cfg.Notify = append(cfg.Notify[:idx-1], cfg.Notify[idx:]...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed notification sink %d: %s\n", style.Bold.Render("✓"), idx, removed.Type)
+	return nil
+}
+
+// updateDeaconNotify applies fn to the Deacon config and saves it,
+// returning the number of sinks afterwards.
+func updateDeaconNotify(fn func(*deacon.Config) error) (int, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return 0, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := deacon.LoadConfig(townRoot)
+	if err != nil {
+		return 0, err
+	}
+	if err := fn(cfg); err != nil {
+		return 0, err
+	}
+	if err := deacon.SaveConfig(townRoot, cfg); err != nil {
+		return 0, err
+	}
+	return len(cfg.Notify), nil
+}
+
+func runDeaconNotifyTest(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := deacon.LoadConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Notify) == 0 {
+		return fmt.Errorf("no notification sinks configured (add one with 'gt deacon notify add')")
+	}
+	msg := "Test notification from gt deacon notify test."
+	if len(args) > 0 {
+		msg = args[0]
+	}
+	if err := deacon.Notify(townRoot, deacon.Notification{
+		Event:   deacon.NotifyEventTest,
+		Title:   "Gas Town test notification",
+		Message: msg,
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("%s Sent a test notification to %d sink(s)\n", style.Bold.Render("✓"), len(cfg.Notify))
+	return nil
+}
@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	pendingtui "github.com/steveyegge/gastown/internal/tui/pending"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	deaconPendingJSON        bool
+	deaconPendingLines       int
+	deaconPendingInteractive bool
+	deaconPendingRefresh     time.Duration
+)
+
+var deaconPendingCmd = &cobra.Command{
+	Use:   "pending",
+	Short: "List pending polecat spawns with their captured output",
+	Long: `List polecats that have started but not yet been triggered, with the
+last lines of each session's pane so the Deacon can judge whether the
+runtime is ready.
+
+Pending spawns come from POLECAT_STARTED messages in the Deacon's inbox.
+Once a session looks ready, trigger it with 'gt nudge <session>'; spawns
+older than pending_spawn_max_age (see 'gt deacon config') are pruned by
+trigger-pending.
+
+Spawns held back by max_polecats_per_rig or max_triggers_per_minute are
+ready and waiting for room; they show their position in the queue and are
+triggered oldest first.
+
+Use --json for structured records (session, rig, polecat, issue, spawn
+age, captured output) for scripts.
+
+Use --interactive for a triage screen: the spawns are listed with the
+selected session's output below, refreshed every --refresh interval.
+Keys: n (or enter) nudges the session with "Begin." and clears it from
+pending, c clears it without touching the session, x kills the session
+(confirm with y), r refreshes, q quits.`,
+	Args: cobra.NoArgs,
+	RunE: runDeaconPending,
+}
+
+func init() {
+	deaconPendingCmd.Flags().BoolVar(&deaconPendingJSON, "json", false, "Output as JSON")
+	deaconPendingCmd.Flags().IntVarP(&deaconPendingLines, "lines", "n", 0,
+		"Pane lines to capture per session (default: capture_lines from deacon config)")
+	deaconPendingCmd.Flags().BoolVarP(&deaconPendingInteractive, "interactive", "i", false, "Triage pending spawns in an interactive screen")
+	deaconPendingCmd.Flags().DurationVar(&deaconPendingRefresh, "refresh", 2*time.Second, "Refresh interval for --interactive")
+	deaconCmd.AddCommand(deaconPendingCmd)
+}
+
+// PendingSpawnStatus is the JSON record for one pending spawn.
+type PendingSpawnStatus struct {
+	Session        string    `json:"session"`
+	Rig            string    `json:"rig"`
+	Polecat        string    `json:"polecat"`
+	Issue          string    `json:"issue,omitempty"`
+	SpawnedAt      time.Time `json:"spawned_at"`
+	AgeSec         float64   `json:"age_seconds"`
+	SessionRunning bool      `json:"session_running"`
+	Attempts       int       `json:"attempts,omitempty"`
+	NextAttempt    time.Time `json:"next_attempt,omitempty"`
+	HeldSince      time.Time `json:"held_since,omitempty"`
+	QueuePosition  int       `json:"queue_position,omitempty"`
+	Output         []string  `json:"output"`
+	CaptureError   string    `json:"capture_error,omitempty"`
+}
+
+func runDeaconPending(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	lines := deaconPendingLines
+	if lines <= 0 {
+		cfg, _ := deacon.LoadConfigOrDefault(townRoot)
+		lines = cfg.GetCaptureLines()
+	}
+	if deaconPendingInteractive {
+		if deaconPendingJSON {
+			return fmt.Errorf("--interactive and --json cannot be used together")
+		}
+		if deaconPendingLines <= 0 {
+			lines = pendingTUILines
+		}
+		src := &pendingTUISource{townRoot: townRoot, lines: lines, t: tmux.NewTmux()}
+		p := tea.NewProgram(pendingtui.New(src, deaconPendingRefresh), tea.WithAltScreen())
+		_, err := p.Run()
+		return err
+	}
+
+	pending, err := polecat.CheckInboxForSpawns(townRoot)
+	if err != nil {
+		return fmt.Errorf("checking inbox: %w", err)
+	}
+
+	statuses := collectPendingSpawns(pending, tmux.NewTmux(), lines, time.Now())
+	if deaconPendingJSON {
+		return outputJSON(statuses)
+	}
+
+	if len(statuses) == 0 {
+		fmt.Printf("%s No pending spawns\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	fmt.Printf("%s %d pending spawn(s)\n\n", style.Bold.Render("●"), len(statuses))
+	for _, s := range statuses {
+		header := fmt.Sprintf("%s/%s", s.Rig, s.Polecat)
+		if s.Issue != "" {
+			header += " " + style.Dim.Render(s.Issue)
+		}
+		fmt.Println(style.Rule(style.Bold.Render(header)))
+		age := time.Duration(s.AgeSec * float64(time.Second)).Round(time.Second)
+		fmt.Printf("  Session: %s  Age: %s\n", s.Session, age)
+		if s.QueuePosition > 0 {
+			fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("Held by trigger limits since %s; queue position %d",
+				s.HeldSince.Local().Format("15:04:05"), s.QueuePosition)))
+		} else if s.Attempts > 0 {
+			fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("%d failed readiness check(s); next %s",
+				s.Attempts, s.NextAttempt.Local().Format("15:04:05"))))
+		}
+		switch {
+		case !s.SessionRunning:
+			fmt.Printf("  %s\n", style.Warning.Render("session not running"))
+		case s.CaptureError != "":
+			fmt.Printf("  %s\n", style.Dim.Render("capture failed: "+s.CaptureError))
+		case len(s.Output) == 0:
+			fmt.Printf("  %s\n", style.Dim.Render("(no output)"))
+		default:
+			for _, line := range s.Output {
+				fmt.Println(style.FitLine("  │ " + line))
+			}
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// pendingSessionCapturer is the tmux surface runDeaconPending needs.
+type pendingSessionCapturer interface {
+	HasSession(name string) (bool, error)
+	CapturePane(session string, lines int) (string, error)
+}
+
+// collectPendingSpawns builds a status record for each pending spawn,
+// oldest first, capturing the last lines of each running session.
+func collectPendingSpawns(pending []*polecat.PendingSpawn, t pendingSessionCapturer, lines int, now time.Time) []PendingSpawnStatus {
+	statuses := make([]PendingSpawnStatus, 0, len(pending))
+	positions := polecat.QueuePositions(pending)
+	for _, ps := range pending {
+		s := PendingSpawnStatus{
+			Session:       ps.Session,
+			Rig:           ps.Rig,
+			Polecat:       ps.Polecat,
+			Issue:         ps.Issue,
+			SpawnedAt:     ps.SpawnedAt,
+			AgeSec:        now.Sub(ps.SpawnedAt).Seconds(),
+			Attempts:      ps.Attempts,
+			NextAttempt:   ps.NextAttempt,
+			HeldSince:     ps.HeldSince,
+			QueuePosition: positions[ps.MailID],
+			Output:        []string{},
+		}
+		if ps.Session != "" {
+			s.SessionRunning, _ = t.HasSession(ps.Session)
+		}
+		if s.SessionRunning {
+			out, err := t.CapturePane(ps.Session, lines)
+			if err != nil {
+				s.CaptureError = err.Error()
+			} else {
+				s.Output = trimCapturedLines(out)
+			}
+		}
+		statuses = append(statuses, s)
+	}
+	sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].SpawnedAt.Before(statuses[j].SpawnedAt) })
+	return statuses
+}
+
+// trimCapturedLines splits captured pane output into lines, dropping the
+// trailing blank lines tmux pads the pane with.
+func trimCapturedLines(out string) []string {
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return lines
+}
+
+// pendingTUILines is how much of each pane the interactive view captures
+// when --lines isn't given; the view shows as much as fits.
+const pendingTUILines = 50
+
+// pendingTUISource feeds the interactive pending view from the Deacon's
+// inbox and tmux.
+type pendingTUISource struct {
+	townRoot string
+	lines    int
+	t        *tmux.Tmux
+
+	mu     sync.Mutex
+	spawns map[string]*polecat.PendingSpawn // by session, from the last Load
+}
+
+func (s *pendingTUISource) Load() ([]pendingtui.Item, error) {
+	pending, err := polecat.CheckInboxForSpawns(s.townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("checking inbox: %w", err)
+	}
+	bySession := make(map[string]*polecat.PendingSpawn, len(pending))
+	for _, ps := range pending {
+		bySession[ps.Session] = ps
+	}
+	s.mu.Lock()
+	s.spawns = bySession
+	s.mu.Unlock()
+
+	statuses := collectPendingSpawns(pending, s.t, s.lines, time.Now())
+	items := make([]pendingtui.Item, 0, len(statuses))
+	for _, st := range statuses {
+		items = append(items, pendingtui.Item{
+			Session:       st.Session,
+			Rig:           st.Rig,
+			Polecat:       st.Polecat,
+			Issue:         st.Issue,
+			SpawnedAt:     st.SpawnedAt,
+			Running:       st.SessionRunning,
+			Attempts:      st.Attempts,
+			QueuePosition: st.QueuePosition,
+			Output:        st.Output,
+			CaptureError:  st.CaptureError,
+		})
+	}
+	return items, nil
+}
+
+// spawn returns the pending spawn behind it, as of the last Load.
+func (s *pendingTUISource) spawn(it pendingtui.Item) (*polecat.PendingSpawn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ps, ok := s.spawns[it.Session]
+	if !ok {
+		return nil, fmt.Errorf("no longer pending")
+	}
+	return ps, nil
+}
+
+func (s *pendingTUISource) Nudge(it pendingtui.Item) error {
+	ps, err := s.spawn(it)
+	if err != nil {
+		return err
+	}
+	if err := s.t.NudgeSession(ps.Session, "Begin."); err != nil {
+		return err
+	}
+	s.log(deacon.LogEventSpawn, "triggered pending spawn by hand", ps)
+	return polecat.FinishPendingSpawn(s.townRoot, ps)
+}
+
+func (s *pendingTUISource) Clear(it pendingtui.Item) error {
+	ps, err := s.spawn(it)
+	if err != nil {
+		return err
+	}
+	s.log(deacon.LogEventPendingCleared, "cleared pending spawn by hand", ps)
+	return polecat.FinishPendingSpawn(s.townRoot, ps)
+}
+
+func (s *pendingTUISource) Kill(it pendingtui.Item) error {
+	ps, err := s.spawn(it)
+	if err != nil {
+		return err
+	}
+	if running, _ := s.t.HasSession(ps.Session); running {
+		if err := s.t.KillSessionWithProcesses(ps.Session); err != nil {
+			return err
+		}
+	}
+	s.log(deacon.LogEventPendingCleared, "killed pending spawn session by hand", ps)
+	return polecat.FinishPendingSpawn(s.townRoot, ps)
+}
+
+func (s *pendingTUISource) log(event, message string, ps *polecat.PendingSpawn) {
+	_ = deacon.AppendLog(s.townRoot, deacon.LogEntry{
+		Event:   event,
+		Message: message,
+		Fields:  map[string]string{"rig": ps.Rig, "polecat": ps.Polecat, "session": ps.Session},
+	})
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/polecat"
+)
+
+type fakePendingTmux struct {
+	sessions map[string]string // session -> pane; "!" fails the capture
+}
+
+func (f fakePendingTmux) HasSession(name string) (bool, error) {
+	_, ok := f.sessions[name]
+	return ok, nil
+}
+
+func (f fakePendingTmux) CapturePane(session string, lines int) (string, error) {
+	if f.sessions[session] == "!" {
+		return "", errors.New("capture failed")
+	}
+	return f.sessions[session], nil
+}
+
+func TestCollectPendingSpawns(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	pending := []*polecat.PendingSpawn{
+		{Rig: "gastown", Polecat: "nux", Session: "gt-gastown-nux", Issue: "gt-123", SpawnedAt: now.Add(-time.Minute)},
+		{Rig: "gastown", Polecat: "toast", Session: "gt-gastown-toast", SpawnedAt: now.Add(-3 * time.Minute)},
+		{Rig: "beads", Polecat: "ace", Session: "bd-beads-ace", SpawnedAt: now.Add(-2 * time.Minute)},
+	}
+	tm := fakePendingTmux{sessions: map[string]string{
+		"gt-gastown-nux":   "Welcome to Claude\n> \n\n\n",
+		"gt-gastown-toast": "!",
+	}}
+
+	got := collectPendingSpawns(pending, tm, 10, now)
+	if len(got) != 3 || got[0].Polecat != "toast" || got[2].Polecat != "nux" {
+		t.Fatalf("expected oldest first, got %+v", got)
+	}
+	if got[0].CaptureError == "" {
+		t.Errorf("toast capture error not recorded: %+v", got[0])
+	}
+	if got[1].SessionRunning || len(got[1].Output) != 0 {
+		t.Errorf("ace has no session: %+v", got[1])
+	}
+	nux := got[2]
+	if !nux.SessionRunning || nux.AgeSec != 60 || strings.Join(nux.Output, "|") != "Welcome to Claude|>" {
+		t.Errorf("unexpected nux record: %+v", nux)
+	}
+
+	data, err := json.Marshal(got[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"session":"bd-beads-ace"`, `"age_seconds":120`, `"output":[]`, `"session_running":false`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("JSON missing %s: %s", want, data)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	deaconScheduleJSON    bool
+	deaconScheduleName    string
+	deaconScheduleTimeout time.Duration
+)
+
+var deaconScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Manage recurring tasks the Deacon runs on a cron schedule",
+	Long: `Manage scheduled tasks: shell commands the Deacon runs on a cron schedule.
+
+Schedules are standard five-field cron expressions in the daemon's local
+time (minute hour day-of-month month day-of-week), or one of @hourly,
+@daily, @weekly, @monthly and @yearly. Commands run through the shell
+in the town root, with GT_TOWN_ROOT and GT_SCHEDULED_TASK set.
+
+Due tasks are run by the daemon (checked every minute), and by
+'gt deacon watch' and 'gt deacon schedule run-due' when those are used
+instead. A task is claimed before it runs, so it runs once even when
+several of these poll. Missed runs are not caught up: a task that was due
+several times while nothing was polling runs once. Nothing runs while
+the Deacon is paused.
+
+Tasks are stored in deacon/schedule.json with each task's last run,
+status, duration and output.
+
+Examples:
+  gt deacon schedule add "0 */2 * * *" "gt witness sweep"
+  gt deacon schedule add @hourly "gt deacon stale-hooks" --name hooks
+  gt deacon schedule list
+  gt deacon schedule run witness-sweep
+  gt deacon schedule remove witness-sweep`,
+	RunE: requireSubcommand,
+}
+
+var deaconScheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List scheduled tasks with their next and last runs",
+	Args:  cobra.NoArgs,
+	RunE:  runDeaconScheduleList,
+}
+
+var deaconScheduleAddCmd = &cobra.Command{
+	Use:   "add <cron> <command>",
+	Short: "Schedule a command",
+	Long: `Schedule a command. The task is named after the command (gt witness
+sweep becomes witness-sweep) unless --name is given.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDeaconScheduleAdd,
+}
+
+var deaconScheduleRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a scheduled task",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDeaconScheduleRemove,
+}
+
+var deaconScheduleRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Run a scheduled task now",
+	Long: `Run a scheduled task now, in the foreground, and record the result as its
+last run. Its next scheduled run is unchanged. This works while the Deacon
+is paused.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDeaconScheduleRun,
+}
+
+var deaconScheduleRunDueCmd = &cobra.Command{
+	Use:   "run-due",
+	Short: "Run every scheduled task that is due",
+	Long: `Run every scheduled task that is due, one after another. The daemon does
+this every minute; use it from a Deacon patrol or cron when the daemon is
+not running.
+
+Exits non-zero if any task failed.`,
+	Args: cobra.NoArgs,
+	RunE: runDeaconScheduleRunDue,
+}
+
+func init() {
+	deaconScheduleListCmd.Flags().BoolVar(&deaconScheduleJSON, "json", false, "Output as JSON")
+	deaconScheduleAddCmd.Flags().StringVar(&deaconScheduleName, "name", "", "Task name (default: derived from the command)")
+	deaconScheduleAddCmd.Flags().DurationVar(&deaconScheduleTimeout, "timeout", deacon.DefaultScheduleTimeout, "How long one run may take")
+
+	deaconScheduleCmd.AddCommand(deaconScheduleListCmd)
+	deaconScheduleCmd.AddCommand(deaconScheduleAddCmd)
+	deaconScheduleCmd.AddCommand(deaconScheduleRemoveCmd)
+	deaconScheduleCmd.AddCommand(deaconScheduleRunCmd)
+	deaconScheduleCmd.AddCommand(deaconScheduleRunDueCmd)
+	deaconCmd.AddCommand(deaconScheduleCmd)
+}
+
+var scheduleNameUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// scheduleTaskName derives a task name from its command: "gt witness sweep"
+// becomes "witness-sweep".
+func scheduleTaskName(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) > 1 && fields[0] == "gt" {
+		fields = fields[1:]
+	}
+	name := strings.Trim(scheduleNameUnsafe.ReplaceAllString(strings.ToLower(strings.Join(fields, " ")), "-"), "-")
+	if len(name) > 40 {
+		name = strings.TrimRight(name[:40], "-")
+	}
+	return name
+}
+
+func runDeaconScheduleList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	s, err := deacon.LoadSchedule(townRoot)
+	if err != nil {
+		return err
+	}
+	tasks := s.Sorted()
+	if deaconScheduleJSON {
+		return outputJSON(tasks)
+	}
+	if len(tasks) == 0 {
+		fmt.Printf("%s No scheduled tasks (add one with 'gt deacon schedule add')\n", style.Dim.Render("○"))
+		return nil
+	}
+	if paused, _, _ := deacon.IsPaused(townRoot); paused {
+		fmt.Printf("%s Deacon is paused: scheduled tasks will not run\n\n", style.Warning.Render("⏸️"))
+	}
+	for _, t := range tasks {
+		fmt.Printf("%s %s  %s\n", style.Bold.Render("●"), t.Name, style.Dim.Render(t.Cron))
+		fmt.Printf("  Command: %s\n", t.Command)
+		if t.NextRun.IsZero() {
+			fmt.Printf("  Next:    %s\n", style.Warning.Render("never"))
+		} else {
+			fmt.Printf("  Next:    %s\n", t.NextRun.Local().Format("2006-01-02 15:04"))
+		}
+		if t.LastRun == nil {
+			fmt.Printf("  Last:    %s\n", style.Dim.Render("never run"))
+			continue
+		}
+		status := t.LastStatus
+		switch t.LastStatus {
+		case deacon.ScheduleStatusFailed:
+			status = style.Warning.Render(status)
+		case deacon.ScheduleStatusRunning:
+			status = style.Dim.Render(status)
+		}
+		fmt.Printf("  Last:    %s %s", t.LastRun.Local().Format("2006-01-02 15:04"), status)
+		if t.LastStatus != deacon.ScheduleStatusRunning {
+			fmt.Printf(" (%s)", t.LastDuration.Round(time.Millisecond))
+		}
+		fmt.Println()
+		if t.LastError != "" {
+			fmt.Printf("  %s\n", style.Warning.Render(t.LastError))
+		}
+	}
+	return nil
+}
+
+func runDeaconScheduleAdd(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if deaconScheduleTimeout <= 0 {
+		return fmt.Errorf("--timeout must be positive")
+	}
+	name := deaconScheduleName
+	if name == "" {
+		name = scheduleTaskName(args[1])
+	}
+	task, err := deacon.AddScheduledTask(townRoot, deacon.ScheduledTask{
+		Name:    name,
+		Cron:    args[0],
+		Command: args[1],
+		Timeout: deaconScheduleTimeout,
+	}, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Scheduled %s: %s\n", style.Bold.Render("✓"), task.Name, task.Command)
+	fmt.Printf("  Next run: %s\n", task.NextRun.Local().Format("2006-01-02 15:04"))
+	return nil
+}
+
+func runDeaconScheduleRemove(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := deacon.RemoveScheduledTask(townRoot, args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("%s Removed scheduled task %s\n", style.Bold.Render("✓"), args[0])
+	return nil
+}
+
+func runDeaconScheduleRun(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	run, err := deacon.RunScheduledTaskNow(ctx, townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	printScheduleRun(run)
+	if run.Err != nil {
+		return fmt.Errorf("%s failed: %w", run.Name, run.Err)
+	}
+	return nil
+}
+
+func runDeaconScheduleRunDue(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if deaconPausedSkip(townRoot, "running scheduled tasks") {
+		return nil
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	runs, err := deacon.RunDueTasks(ctx, townRoot, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		fmt.Printf("%s No scheduled tasks due\n", style.Dim.Render("○"))
+		return nil
+	}
+	var failed []string
+	for _, run := range runs {
+		printScheduleRun(run)
+		if run.Err != nil {
+			failed = append(failed, run.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return errors.New("scheduled task(s) failed: " + strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// printScheduleRun reports one run with its output.
+func printScheduleRun(run deacon.ScheduleRun) {
+	took := run.Duration.Round(time.Millisecond)
+	if run.Err != nil {
+		fmt.Printf("%s %s failed after %s: %v\n", style.Warning.Render("✗"), run.Name, took, run.Err)
+	} else {
+		fmt.Printf("%s %s (%s)\n", style.Bold.Render("✓"), run.Name, took)
+	}
+	if run.Output != "" {
+		for _, line := range strings.Split(run.Output, "\n") {
+			fmt.Println(style.FitLine("  │ " + line))
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import "testing"
+
+func TestScheduleTaskName(t *testing.T) {
+	tests := map[string]string{
+		"gt witness sweep":           "witness-sweep",
+		"gt deacon stale-hooks":      "deacon-stale-hooks",
+		"./scripts/backup.sh --full": "scripts-backup-sh-full",
+		"gt":                         "gt",
+		"echo 'a very long command that goes on and on'": "echo-a-very-long-command-that-goes-on-an",
+	}
+	for command, want := range tests {
+		if got := scheduleTaskName(command); got != want {
+			t.Errorf("scheduleTaskName(%q) = %q, want %q", command, got, want)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	deaconRestoreDryRun bool
+	deaconRestoreForce  bool
+)
+
+var deaconSnapshotCmd = &cobra.Command{
+	Use:   "snapshot [file]",
+	Short: "Save Deacon and daemon runtime state to an archive",
+	Long: `Save the Deacon's and daemon's runtime state to a single archive, for
+recovery after a host reboot or when moving the town to another machine.
+
+The archive (a gzipped tar) holds:
+  - State files: Deacon config, heartbeat, schedule, re-dispatch and
+    health-check state, notification cooldowns, pause and headless
+    markers, the inbox cursor, pending spawn retry state, inbox nag state
+    and the daemon's restart backoff
+  - Pending spawns: the POLECAT_STARTED mail the Deacon is waiting on
+  - Session inventory: the agent sessions running when it was taken
+
+The failover lease and daemon PID file are not saved: they name processes
+on this host. Restore with 'gt deacon restore'.
+
+The default file is deacon-snapshot-<time>.tar.gz in the current directory.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDeaconSnapshot,
+}
+
+var deaconRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Restore Deacon and daemon runtime state from a snapshot",
+	Long: `Restore the state files in a 'gt deacon snapshot' archive into this town.
+
+Files in the archive replace the town's copies; state the archive doesn't
+carry is left alone. The Deacon and daemon must be stopped first so they
+don't overwrite the restored state (--force skips the check).
+
+After restoring, the command lists pending spawns whose POLECAT_STARTED
+mail is no longer in the Deacon's inbox, and the sessions that were
+running when the snapshot was taken but aren't now. Start them again
+with 'gt up'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDeaconRestore,
+}
+
+func init() {
+	deaconRestoreCmd.Flags().BoolVarP(&deaconRestoreDryRun, "dry-run", "n", false, "Show what would be restored without writing")
+	deaconRestoreCmd.Flags().BoolVarP(&deaconRestoreForce, "force", "f", false, "Restore even if the Deacon or daemon is running")
+
+	deaconCmd.AddCommand(deaconSnapshotCmd)
+	deaconCmd.AddCommand(deaconRestoreCmd)
+}
+
+func runDeaconSnapshot(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	now := time.Now().UTC()
+	out := fmt.Sprintf("deacon-snapshot-%s.tar.gz", now.Format("20060102-150405"))
+	if len(args) > 0 {
+		out = args[0]
+	}
+
+	host, _ := os.Hostname()
+	m := &deacon.SnapshotManifest{CreatedAt: now, Host: host, TownRoot: townRoot}
+	spawns, err := polecat.CheckInboxForSpawns(townRoot)
+	if err != nil {
+		style.PrintWarning("pending spawns not recorded: %v", err)
+	}
+	for _, ps := range spawns {
+		m.PendingSpawns = append(m.PendingSpawns, deacon.SnapshotSpawn{
+			Rig: ps.Rig, Polecat: ps.Polecat, Session: ps.Session, Issue: ps.Issue,
+			MailID: ps.MailID, SpawnedAt: ps.SpawnedAt, Attempts: ps.Attempts,
+		})
+	}
+	m.Sessions, err = deaconSessionInventory()
+	if err != nil {
+		style.PrintWarning("session inventory not recorded: %v", err)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(out), ".deacon-snapshot-*")
+	if err != nil {
+		return fmt.Errorf("creating snapshot: %w", err)
+	}
+	defer os.Remove(f.Name()) //nolint:errcheck // no-op after the rename
+	if err := deacon.WriteSnapshot(townRoot, f, m); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+	if err := os.Rename(f.Name(), out); err != nil {
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+
+	fmt.Printf("%s Saved Deacon snapshot to %s\n", style.Bold.Render("✓"), out)
+	fmt.Printf("  State files:    %d\n", len(m.Files))
+	fmt.Printf("  Pending spawns: %d\n", len(m.PendingSpawns))
+	fmt.Printf("  Sessions:       %d\n", len(m.Sessions))
+	return nil
+}
+
+// deaconSessionInventory lists the running tmux sessions that belong to
+// Gas Town agents.
+func deaconSessionInventory() ([]deacon.SnapshotSession, error) {
+	names, err := tmux.NewTmux().ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	var inv []deacon.SnapshotSession
+	for _, name := range names {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue
+		}
+		inv = append(inv, deacon.SnapshotSession{Name: name, Role: string(id.Role), Address: id.Address()})
+	}
+	sort.Slice(inv, func(i, j int) bool { return inv[i].Name < inv[j].Name })
+	return inv, nil
+}
+
+func runDeaconRestore(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	m, files, err := deacon.ReadSnapshot(f)
+	_ = f.Close()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Snapshot taken %s on %s (%s)\n", style.Bold.Render("●"),
+		m.CreatedAt.Local().Format("2006-01-02 15:04:05"), m.Host, m.TownRoot)
+	if m.TownRoot != townRoot {
+		fmt.Printf("  %s\n", style.Dim.Render("Restoring into "+townRoot))
+	}
+
+	if !deaconRestoreDryRun && !deaconRestoreForce {
+		if running, _ := tmux.NewTmux().HasSession(getDeaconSessionName()); running {
+			return fmt.Errorf("the Deacon is running; stop it first with 'gt deacon stop' (or use --force)")
+		}
+		if running, pid, _ := daemon.IsRunning(townRoot); running {
+			return fmt.Errorf("the daemon is running (PID %d); stop it first with 'gt daemon stop' (or use --force)", pid)
+		}
+	}
+
+	if deaconRestoreDryRun {
+		fmt.Printf("\nWould restore:\n")
+		for _, rel := range deacon.SnapshotStateFiles {
+			if _, ok := files[rel]; ok {
+				fmt.Printf("  %s\n", rel)
+			}
+		}
+	} else {
+		restored, err := deacon.RestoreSnapshot(townRoot, files)
+		for _, rel := range restored {
+			fmt.Printf("  %s %s\n", style.SuccessPrefix, rel)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Restored %d state file(s)\n", style.Bold.Render("✓"), len(restored))
+	}
+
+	printDeaconRestoreSpawns(townRoot, m.PendingSpawns)
+	printDeaconRestoreSessions(m.Sessions)
+	return nil
+}
+
+// printDeaconRestoreSpawns reports which of the snapshot's pending spawns
+// still have their POLECAT_STARTED mail in the Deacon's inbox.
+func printDeaconRestoreSpawns(townRoot string, spawns []deacon.SnapshotSpawn) {
+	if len(spawns) == 0 {
+		return
+	}
+	current := make(map[string]bool)
+	if live, err := polecat.CheckInboxForSpawns(townRoot); err == nil {
+		for _, ps := range live {
+			current[ps.MailID] = true
+		}
+	}
+	fmt.Printf("\n%s\n", style.Bold.Render("Pending spawns"))
+	for _, s := range spawns {
+		name := s.Rig + "/" + s.Polecat
+		if current[s.MailID] {
+			fmt.Printf("  %s %s %s\n", style.SuccessPrefix, name, style.Dim.Render("(mail present; gt deacon trigger-pending picks it up)"))
+		} else {
+			hint := "mail " + s.MailID + " not in the inbox"
+			if s.Issue != "" {
+				hint += "; re-sling " + s.Issue + " if still needed"
+			}
+			fmt.Printf("  %s %s %s\n", style.WarningPrefix, name, style.Dim.Render("("+hint+")"))
+		}
+	}
+}
+
+// printDeaconRestoreSessions compares the snapshot's session inventory with
+// what is running now.
+func printDeaconRestoreSessions(sessions []deacon.SnapshotSession) {
+	if len(sessions) == 0 {
+		return
+	}
+	t := tmux.NewTmux()
+	missing := 0
+	fmt.Printf("\n%s\n", style.Bold.Render("Sessions at snapshot time"))
+	for _, s := range sessions {
+		if running, _ := t.HasSession(s.Name); running {
+			fmt.Printf("  %s %s %s\n", style.SuccessPrefix, s.Address, style.Dim.Render("(running)"))
+			continue
+		}
+		missing++
+		fmt.Printf("  %s %s %s\n", style.WarningPrefix, s.Address, style.Dim.Render("(not running)"))
+	}
+	if missing > 0 {
+		fmt.Printf("\n%d session(s) not running; start them with: %s\n", missing, style.Dim.Render("gt up"))
+	}
+}
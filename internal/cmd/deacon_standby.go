@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cli"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	deaconStandbyThreshold time.Duration
+	deaconStandbyInterval  time.Duration
+	deaconStandbyLeaseTTL  time.Duration
+	deaconStandbyHolder    string
+	deaconStandbyOnce      bool
+)
+
+var deaconStandbyCmd = &cobra.Command{
+	Use:   "standby",
+	Short: "Watch for a dead Deacon and start it if the daemon doesn't",
+	Long: `Stand by to bring the Deacon back if it dies while the daemon is wedged.
+
+The daemon normally restarts a dead Deacon. If both are down, nothing
+does, and the town stalls. A standby checks the Deacon heartbeat; when it
+is older than --threshold and the Deacon isn't running, the standby takes
+the failover lease and starts the Deacon itself, logs a failover entry
+(gt deacon logs) and mails the Mayor.
+
+The lease keeps several standbys from starting the Deacon twice: only its
+holder acts, and it lasts --lease-ttl so the new session can write its
+first heartbeat before anyone looks again. Keep --threshold above the
+daemon's own restart threshold so the daemon gets the first chance.
+
+In headless mode the standby restarts the headless loop instead of a tmux
+session. A paused Deacon is left alone.
+
+Run it as a long-lived process, or with --once from a Witness patrol or
+cron.
+
+Examples:
+  gt deacon standby
+  gt deacon standby --once --holder gastown/witness
+  gt deacon standby --threshold 30m --lease-ttl 15m`,
+	Args: cobra.NoArgs,
+	RunE: runDeaconStandby,
+}
+
+func init() {
+	deaconStandbyCmd.Flags().DurationVar(&deaconStandbyThreshold, "threshold", 20*time.Minute, "Heartbeat age at which a dead Deacon is taken over")
+	deaconStandbyCmd.Flags().DurationVar(&deaconStandbyInterval, "interval", time.Minute, "Time between checks")
+	deaconStandbyCmd.Flags().DurationVar(&deaconStandbyLeaseTTL, "lease-ttl", 10*time.Minute, "How long the failover lease is held after starting the Deacon")
+	deaconStandbyCmd.Flags().StringVar(&deaconStandbyHolder, "holder", "", "Lease holder name (default standby/<host>-<pid>)")
+	deaconStandbyCmd.Flags().BoolVar(&deaconStandbyOnce, "once", false, "Check once and exit")
+	deaconCmd.AddCommand(deaconStandbyCmd)
+}
+
+func runDeaconStandby(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	holder := deaconStandbyHolder
+	if holder == "" {
+		host, _ := os.Hostname()
+		holder = fmt.Sprintf("standby/%s-%d", host, os.Getpid())
+	}
+	t := tmux.NewTmux()
+
+	if deaconStandbyOnce {
+		return deaconStandbyCheck(townRoot, holder, t, true)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Printf("%s Deacon standby %s watching (threshold %v)\n", style.Bold.Render("●"), holder, deaconStandbyThreshold)
+	for {
+		if err := deaconStandbyCheck(townRoot, holder, t, false); err != nil {
+			style.PrintWarning("%v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(deaconStandbyInterval):
+		}
+	}
+}
+
+// deaconStandbyCheck runs one standby check, starting the Deacon when it
+// is dead and this standby wins the lease. verbose reports healthy checks.
+func deaconStandbyCheck(townRoot, holder string, t *tmux.Tmux, verbose bool) error {
+	headless := deacon.IsHeadless(townRoot)
+	var alive bool
+	if headless {
+		alive = daemon.HeadlessDeaconProcess(townRoot) != nil
+	} else if t.IsAvailable() {
+		alive, _ = t.HasSession(getDeaconSessionName())
+	}
+	paused, _, _ := deacon.IsPaused(townRoot)
+	age := deacon.ReadHeartbeat(townRoot).Age()
+
+	act, reason := deaconFailoverDecision(age, deaconStandbyThreshold, alive, paused)
+	if !act {
+		if verbose {
+			fmt.Printf("%s No failover: %s\n", style.Dim.Render("○"), reason)
+		}
+		return nil
+	}
+
+	lease, won, err := deacon.AcquireFailoverLease(townRoot, holder, deaconStandbyLeaseTTL, time.Now())
+	if err != nil {
+		return fmt.Errorf("acquiring failover lease: %w", err)
+	}
+	if !won {
+		fmt.Printf("%s Deacon is down (%s); %s holds the failover lease until %s\n",
+			style.Dim.Render("○"), reason, lease.Holder, lease.ExpiresAt.Local().Format("15:04:05"))
+		return nil
+	}
+
+	fmt.Printf("%s Deacon is down (%s); starting it\n", style.Warning.Render("⚠"), reason)
+	if headless {
+		gtPath, err := os.Executable()
+		if err != nil {
+			gtPath = cli.Name()
+		}
+		if _, err := daemon.StartHeadlessDeacon(townRoot, gtPath); err != nil {
+			return fmt.Errorf("starting headless Deacon: %w", err)
+		}
+	} else if err := startDeaconSession(t, getDeaconSessionName(), ""); err != nil {
+		return fmt.Errorf("starting Deacon: %w", err)
+	}
+
+	_ = deacon.AppendLog(townRoot, deacon.LogEntry{
+		Event:   deacon.LogEventFailover,
+		Message: "standby started the Deacon",
+		Fields:  map[string]string{"holder": holder, "reason": reason},
+	})
+	sendMail(townRoot, "mayor/", "DEACON_FAILOVER",
+		fmt.Sprintf("The Deacon was down (%s) and the daemon did not restart it.\n"+
+			"Standby %s started it. Check the daemon: gt daemon status", reason, holder))
+	fmt.Printf("%s Deacon started by standby %s\n", style.Bold.Render("✓"), holder)
+	return nil
+}
+
+// deaconFailoverDecision decides whether a standby should start the
+// Deacon: only when it isn't running, isn't paused, and its heartbeat is
+// older than threshold. A running but stale Deacon is Boot's and the
+// daemon's to handle.
+func deaconFailoverDecision(age, threshold time.Duration, alive, paused bool) (bool, string) {
+	switch {
+	case paused:
+		return false, "Deacon is paused"
+	case alive:
+		return false, "Deacon is running"
+	case age < threshold:
+		return false, fmt.Sprintf("heartbeat %v old, under the %v threshold", age.Round(time.Second), threshold)
+	default:
+		return true, fmt.Sprintf("not running, heartbeat %v old", age.Round(time.Minute))
+	}
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeaconFailoverDecision(t *testing.T) {
+	threshold := 20 * time.Minute
+	tests := []struct {
+		name   string
+		age    time.Duration
+		alive  bool
+		paused bool
+		want   bool
+	}{
+		{"dead and stale", time.Hour, false, false, true},
+		{"dead but recent", 5 * time.Minute, false, false, false},
+		{"alive and stale", time.Hour, true, false, false},
+		{"paused", time.Hour, false, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := deaconFailoverDecision(tt.age, threshold, tt.alive, tt.paused)
+			if got != tt.want || reason == "" {
+				t.Errorf("deaconFailoverDecision = %v, %q; want %v", got, reason, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cli"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	deaconWatchHeartbeat time.Duration
+	deaconWatchPending   time.Duration
+	deaconWatchInbox     time.Duration
+	deaconWatchCircuit   time.Duration
+	deaconWatchSchedule  time.Duration
+	deaconWatchJitter    float64
+	deaconWatchGrace     time.Duration
+	deaconWatchOnce      bool
+)
+
+var deaconWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Run the Deacon's checks in a deterministic foreground loop",
+	Long: `Run the Deacon's routine checks on a timer, in the foreground, without an
+AI session: a deterministic fallback Deacon.
+
+Each check runs on its own interval, with random jitter so several towns
+(or a restarted loop) don't fire in lockstep:
+  heartbeat  Keep the Deacon heartbeat fresh
+  pending    Trigger pending polecat spawns (gt deacon trigger-pending)
+  inbox      Scan Deacon mail; bring up a short-lived interactive Deacon for
+             mail that needs judgment (logged only without tmux)
+  circuit    Probe the model API, entering or leaving degraded mode
+             (gt degraded probe)
+  schedule   Run scheduled tasks that are due (gt deacon schedule run-due)
+
+While a Deacon session or the headless loop is running, watch stands by
+and only reports; it takes over when they go away. While the Deacon is
+paused, only the heartbeat runs.
+
+Ctrl-C or SIGTERM stops the loop gracefully: no new check starts, and a
+running check gets --grace to finish before it is cancelled.
+
+Examples:
+  gt deacon watch
+  gt deacon watch --pending 30s --circuit 2m --jitter 0.2
+  gt deacon watch --once`,
+	Args: cobra.NoArgs,
+	RunE: runDeaconWatch,
+}
+
+func init() {
+	deaconWatchCmd.Flags().DurationVar(&deaconWatchHeartbeat, "heartbeat", time.Minute, "Interval between heartbeats")
+	deaconWatchCmd.Flags().DurationVar(&deaconWatchPending, "pending", time.Minute, "Interval between pending spawn checks")
+	deaconWatchCmd.Flags().DurationVar(&deaconWatchInbox, "inbox", 2*time.Minute, "Interval between inbox scans")
+	deaconWatchCmd.Flags().DurationVar(&deaconWatchCircuit, "circuit", 5*time.Minute, "Interval between model API probes")
+	deaconWatchCmd.Flags().DurationVar(&deaconWatchSchedule, "schedule", time.Minute, "Interval between scheduled task checks")
+	deaconWatchCmd.Flags().Float64Var(&deaconWatchJitter, "jitter", 0.1, "Random jitter as a fraction of each interval (0 to 0.5)")
+	deaconWatchCmd.Flags().DurationVar(&deaconWatchGrace, "grace", 30*time.Second, "On shutdown, how long a running check may take to finish")
+	deaconWatchCmd.Flags().BoolVar(&deaconWatchOnce, "once", false, "Run every check once and exit")
+	deaconCmd.AddCommand(deaconWatchCmd)
+}
+
+// deaconWatchTask is one check in the watch loop.
+type deaconWatchTask struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+func runDeaconWatch(cmd *cobra.Command, args []string) error {
+	if deaconWatchJitter < 0 || deaconWatchJitter > 0.5 {
+		return fmt.Errorf("--jitter must be between 0 and 0.5")
+	}
+	for _, d := range []time.Duration{deaconWatchHeartbeat, deaconWatchPending, deaconWatchInbox, deaconWatchCircuit, deaconWatchSchedule} {
+		if d < time.Second {
+			return fmt.Errorf("check intervals must be at least 1s")
+		}
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	gtPath, err := os.Executable()
+	if err != nil {
+		gtPath = cli.Name()
+	}
+	t := tmux.NewTmux()
+	tasks := deaconWatchTasks(townRoot, gtPath, t)
+
+	// Shutdown is two-stage: the signal stops new checks at once; a check
+	// already running is cancelled only after the grace period.
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	taskCtx, cancelTasks := context.WithCancel(context.Background())
+	defer cancelTasks()
+	go func() {
+		<-sigCtx.Done()
+		select {
+		case <-time.After(deaconWatchGrace):
+			cancelTasks()
+		case <-taskCtx.Done():
+		}
+	}()
+
+	logWatch := func(msg string) {
+		fmt.Printf("%s %s\n", style.Dim.Render(time.Now().Format("15:04:05")), msg)
+	}
+	_ = deacon.AppendLog(townRoot, deacon.LogEntry{Event: deacon.LogEventWake, Message: "deacon watch started"})
+	defer func() {
+		_ = deacon.AppendLog(townRoot, deacon.LogEntry{Event: deacon.LogEventWake, Message: "deacon watch stopped"})
+	}()
+
+	if deaconWatchOnce {
+		for _, task := range tasks {
+			if sigCtx.Err() != nil {
+				break
+			}
+			runDeaconWatchTask(taskCtx, townRoot, t, task, logWatch)
+		}
+		return nil
+	}
+
+	logWatch(fmt.Sprintf("%s Deacon watch started (Ctrl-C to stop)", style.Bold.Render("●")))
+	next := make([]time.Time, len(tasks))
+	now := time.Now()
+	for i := range next {
+		next[i] = now // Run every check once at startup
+	}
+	for {
+		i := nextDeaconWatchTask(next)
+		select {
+		case <-sigCtx.Done():
+			logWatch("Deacon watch stopped")
+			return nil
+		case <-time.After(time.Until(next[i])):
+		}
+		runDeaconWatchTask(taskCtx, townRoot, t, tasks[i], logWatch)
+		next[i] = time.Now().Add(jitterInterval(tasks[i].interval, deaconWatchJitter, rand.Float64))
+	}
+}
+
+// deaconWatchTasks returns the checks the watch loop runs, in startup order.
+func deaconWatchTasks(townRoot, gtPath string, t *tmux.Tmux) []deaconWatchTask {
+	gt := func(args ...string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			c := exec.CommandContext(ctx, gtPath, args...) //nolint:gosec // G204: fixed gt subcommands
+			c.Dir = filepath.Join(townRoot, "deacon")
+			c.Stdout = os.Stdout
+			c.Stderr = os.Stderr
+			return c.Run()
+		}
+	}
+	return []deaconWatchTask{
+		{name: "heartbeat", interval: deaconWatchHeartbeat, run: func(context.Context) error {
+			return deacon.TouchWithAction(townRoot, "deacon watch", 0, 0)
+		}},
+		{name: "circuit", interval: deaconWatchCircuit, run: gt("degraded", "probe")},
+		{name: "pending", interval: deaconWatchPending, run: gt("deacon", "trigger-pending")},
+		{name: "inbox", interval: deaconWatchInbox, run: func(context.Context) error {
+			escalateHeadlessMail(townRoot, t, t.IsAvailable())
+			return nil
+		}},
+		{name: "schedule", interval: deaconWatchSchedule, run: gt("deacon", "schedule", "run-due")},
+	}
+}
+
+// runDeaconWatchTask runs one check unless another Deacon is handling the
+// town or the Deacon is paused (the heartbeat always runs while paused).
+func runDeaconWatchTask(ctx context.Context, townRoot string, t *tmux.Tmux, task deaconWatchTask, logWatch func(string)) {
+	if other := activeDeacon(townRoot, t); other != "" {
+		logWatch(fmt.Sprintf("%s: standing by (%s)", task.name, other))
+		return
+	}
+	if paused, _, _ := deacon.IsPaused(townRoot); paused && task.name != "heartbeat" {
+		return
+	}
+	start := time.Now()
+	if err := task.run(ctx); err != nil {
+		logWatch(fmt.Sprintf("%s %s: %v", style.Warning.Render("⚠"), task.name, err))
+		return
+	}
+	logWatch(fmt.Sprintf("%s (%s)", task.name, time.Since(start).Round(time.Millisecond)))
+}
+
+// activeDeacon describes a running Deacon other than this loop, or returns
+// "" if there is none.
+func activeDeacon(townRoot string, t *tmux.Tmux) string {
+	if p := daemon.HeadlessDeaconProcess(townRoot); p != nil {
+		return fmt.Sprintf("headless Deacon running, PID %d", p.Pid)
+	}
+	if t.IsAvailable() {
+		if running, _ := t.HasSession(getDeaconSessionName()); running {
+			return "Deacon session running"
+		}
+	}
+	return ""
+}
+
+// nextDeaconWatchTask returns the index of the check due soonest.
+func nextDeaconWatchTask(next []time.Time) int {
+	best := 0
+	for i := range next {
+		if next[i].Before(next[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// jitterInterval spreads d uniformly over d±frac·d, using rnd for a value
+// in [0, 1).
+func jitterInterval(d time.Duration, frac float64, rnd func() float64) time.Duration {
+	if frac <= 0 {
+		return d
+	}
+	return d + time.Duration((rnd()*2-1)*frac*float64(d))
+}
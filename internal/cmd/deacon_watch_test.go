@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitterInterval(t *testing.T) {
+	d := 10 * time.Minute
+	tests := []struct {
+		rnd  float64
+		frac float64
+		want time.Duration
+	}{
+		{rnd: 0.5, frac: 0.1, want: d},
+		{rnd: 0, frac: 0.1, want: 9 * time.Minute},
+		{rnd: 1, frac: 0.1, want: 11 * time.Minute},
+		{rnd: 0.9, frac: 0, want: d},
+	}
+	for _, tt := range tests {
+		got := jitterInterval(d, tt.frac, func() float64 { return tt.rnd })
+		if got != tt.want {
+			t.Errorf("jitterInterval(rnd=%v, frac=%v) = %v, want %v", tt.rnd, tt.frac, got, tt.want)
+		}
+	}
+}
+
+func TestNextDeaconWatchTask(t *testing.T) {
+	now := time.Now()
+	next := []time.Time{now.Add(time.Minute), now.Add(-time.Second), now.Add(30 * time.Second)}
+	if got := nextDeaconWatchTask(next); got != 1 {
+		t.Errorf("nextDeaconWatchTask = %d, want 1", got)
+	}
+	// Ties go to the earlier check, keeping startup order.
+	tied := []time.Time{now, now, now}
+	if got := nextDeaconWatchTask(tied); got != 0 {
+		t.Errorf("nextDeaconWatchTask(tied) = %d, want 0", got)
+	}
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/degraded"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	degradedJSON   bool
+	degradedReason string
+)
+
+var degradedCmd = &cobra.Command{
+	Use:     "degraded",
+	GroupID: GroupDiag,
+	Short:   "Show or control degraded mode (model API down)",
+	Long: `Show or control degraded mode.
+
+When the model API is unreachable, the town switches to degraded mode:
+  - polecat spawns are refused (gt sling to a rig fails fast)
+  - refinery gates marked requires_model are frozen; MRs wait in queue
+  - the daemon stops restarting agents for stale heartbeats or idle sessions
+
+The daemon enters degraded mode after repeated failed probes of the API
+(the model_probe patrol), or after repeated agent launch failures that a
+probe confirms. It leaves degraded mode on the first successful probe.
+degraded_enter and degraded_exit events mark the window in the feed.
+
+Degraded mode entered by hand is not ended by probes; use 'gt degraded exit'.
+
+Examples:
+  gt degraded                         # Show status
+  gt degraded probe                   # Probe the API now
+  gt degraded enter --reason "outage" # Enter by hand
+  gt degraded exit                    # Resume normal operation`,
+	RunE: runDegradedStatus,
+}
+
+var degradedStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the town is in degraded mode",
+	RunE:  runDegradedStatus,
+}
+
+var degradedProbeCmd = &cobra.Command{
+	Use:   "probe",
+	Short: "Probe the model API now and record the result",
+	Long: `Probe the model API now and record the result.
+
+A successful probe ends degraded mode unless it was entered by hand; a
+failed probe counts toward entering it, as with the daemon's probes.`,
+	RunE: runDegradedProbe,
+}
+
+var degradedEnterCmd = &cobra.Command{
+	Use:   "enter",
+	Short: "Put the town into degraded mode by hand",
+	RunE:  runDegradedEnter,
+}
+
+var degradedExitCmd = &cobra.Command{
+	Use:   "exit",
+	Short: "Leave degraded mode and resume spawns and gates",
+	RunE:  runDegradedExit,
+}
+
+func init() {
+	degradedCmd.Flags().BoolVar(&degradedJSON, "json", false, "Output as JSON")
+	degradedStatusCmd.Flags().BoolVar(&degradedJSON, "json", false, "Output as JSON")
+	degradedEnterCmd.Flags().StringVar(&degradedReason, "reason", "", "Why degraded mode is being entered")
+
+	degradedCmd.AddCommand(degradedStatusCmd)
+	degradedCmd.AddCommand(degradedProbeCmd)
+	degradedCmd.AddCommand(degradedEnterCmd)
+	degradedCmd.AddCommand(degradedExitCmd)
+	rootCmd.AddCommand(degradedCmd)
+}
+
+func runDegradedStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := degraded.Load(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if degradedJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(state)
+	}
+
+	if !state.Active {
+		fmt.Printf("%s Normal operation (model API reachable)\n", style.Success.Render("●"))
+		if state.ProbeFailures > 0 || state.LaunchFailures > 0 {
+			fmt.Printf("  Recent failures: %d probe, %d launch\n", state.ProbeFailures, state.LaunchFailures)
+		}
+		printDegradedLastProbe(state)
+		return nil
+	}
+
+	fmt.Printf("%s Degraded mode (model API unreachable)\n", style.Warning.Render("⚠"))
+	fmt.Printf("  Since: %s (%s)\n", state.Since.Local().Format(time.RFC3339), state.Duration(time.Now()).Round(time.Second))
+	fmt.Printf("  Entered by: %s\n", state.Source)
+	if state.Reason != "" {
+		fmt.Printf("  Reason: %s\n", state.Reason)
+	}
+	printDegradedLastProbe(state)
+	fmt.Println()
+	fmt.Println("Spawns are paused and model-dependent refinery gates are frozen.")
+	if state.Source == degraded.SourceManual {
+		fmt.Printf("Resume with: %s\n", style.Dim.Render("gt degraded exit"))
+	} else {
+		fmt.Println("Normal operation resumes automatically once the API answers a probe.")
+	}
+	return nil
+}
+
+func printDegradedLastProbe(state *degraded.State) {
+	if !state.LastProbe.IsZero() {
+		fmt.Printf("  Last probe: %s ago\n", time.Since(state.LastProbe).Round(time.Second))
+	}
+}
+
+func runDegradedProbe(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	url := degraded.ProbeURL()
+	probeErr := degraded.Probe(context.Background(), nil, url)
+	state, err := degraded.RecordProbe(townRoot, probeErr)
+	if err != nil {
+		return err
+	}
+	if probeErr != nil {
+		fmt.Printf("%s %s unreachable: %v\n", style.Error.Render("✗"), url, probeErr)
+		if state.Active {
+			fmt.Println("  Town is in degraded mode")
+		} else {
+			fmt.Printf("  %d of %d consecutive failures before degraded mode\n", state.ProbeFailures, degraded.ProbeFailureThreshold)
+		}
+		return nil
+	}
+	fmt.Printf("%s %s reachable\n", style.Success.Render("✓"), url)
+	if state.Active {
+		fmt.Printf("  Degraded mode was entered by hand; leave it with %s\n", style.Dim.Render("gt degraded exit"))
+	}
+	return nil
+}
+
+func runDegradedEnter(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	entered, err := degraded.Enter(townRoot, degraded.SourceManual, degradedReason)
+	if err != nil {
+		return fmt.Errorf("entering degraded mode: %w", err)
+	}
+	if !entered {
+		fmt.Printf("%s Town is already in degraded mode\n", style.Dim.Render("○"))
+		return nil
+	}
+	fmt.Printf("%s Degraded mode entered: spawns paused, model-dependent gates frozen\n", style.Warning.Render("⚠"))
+	fmt.Printf("Resume with: %s\n", style.Dim.Render("gt degraded exit"))
+	return nil
+}
+
+func runDegradedExit(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	exited, err := degraded.Exit(townRoot, degraded.SourceManual)
+	if err != nil {
+		return fmt.Errorf("leaving degraded mode: %w", err)
+	}
+	if !exited {
+		fmt.Printf("%s Town is not in degraded mode\n", style.Dim.Render("○"))
+		return nil
+	}
+	fmt.Printf("%s Degraded mode ended: spawns and gates resumed\n", style.Success.Render("✓"))
+	return nil
+}
@@ -0,0 +1,376 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/degraded"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/squad"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	diagnoseJSON bool
+	diagnoseAll  bool
+)
+
+// diagnoseMailBacklog is how many unread messages in the Deacon's inbox
+// count as a backlog.
+const diagnoseMailBacklog = 20
+
+var diagnoseCmd = &cobra.Command{
+	Use:     "diagnose",
+	GroupID: GroupDiag,
+	Short:   "Explain why the town seems idle",
+	Long: `Walk the chain of things a town needs to make progress and report the
+first broken link, with the command to run next.
+
+The chain, in order:
+  daemon     The daemon is running (it supervises everything else)
+  paused     The Deacon is not paused
+  deacon     The Deacon's heartbeat is fresh
+  circuit    The model API is reachable (not in degraded mode)
+  witnesses  Every active rig's witness session is alive (parked and
+             docked rigs are skipped)
+  queue      No rig has merge requests queued with its refinery down
+  pending    No polecat spawn is stuck waiting to be triggered
+  budget     No squad is paused or over budget
+  mail       The Deacon's inbox is not backed up
+
+Later links usually depend on earlier ones (a dead daemon explains a
+stale heartbeat), so diagnose stops at the first failure. Use --all to
+check every link anyway. Exits non-zero if a link is broken.
+
+For configuration and installation problems, use 'gt doctor'.
+
+Examples:
+  gt diagnose
+  gt diagnose --all
+  gt diagnose --json`,
+	Args: cobra.NoArgs,
+	RunE: runDiagnose,
+}
+
+func init() {
+	diagnoseCmd.Flags().BoolVar(&diagnoseJSON, "json", false, "Output as JSON")
+	diagnoseCmd.Flags().BoolVar(&diagnoseAll, "all", false, "Check every link instead of stopping at the first failure")
+	rootCmd.AddCommand(diagnoseCmd)
+}
+
+// DiagnosisLink is the result of checking one link of the chain.
+type DiagnosisLink struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Detail  string `json:"detail"`
+	Suggest string `json:"suggest,omitempty"`
+}
+
+// diagnoseEnv is what the checks read about the town, so they can be
+// exercised without tmux or beads.
+type diagnoseEnv struct {
+	townRoot   string
+	rigs       []string
+	now        time.Time
+	hasSession func(name string) (bool, error)
+	queueLen   func(rigName string) (int, error)
+	unread     func(address string) (int, error)
+	pending    func() ([]*polecat.PendingSpawn, error)
+	spendToday func(s *squad.Squad) float64
+}
+
+// diagnoseCheck checks one link.
+type diagnoseCheck struct {
+	name string
+	run  func(env *diagnoseEnv) DiagnosisLink
+}
+
+// diagnoseChecks returns the chain in causal order.
+func diagnoseChecks() []diagnoseCheck {
+	return []diagnoseCheck{
+		{"daemon", diagnoseDaemon},
+		{"paused", diagnosePaused},
+		{"deacon", diagnoseDeaconHeartbeat},
+		{"circuit", diagnoseCircuit},
+		{"witnesses", diagnoseWitnesses},
+		{"queue", diagnoseQueue},
+		{"pending", diagnosePending},
+		{"budget", diagnoseBudget},
+		{"mail", diagnoseMail},
+	}
+}
+
+// runDiagnoseChain runs the checks in order, stopping after the first
+// broken link unless all is set.
+func runDiagnoseChain(env *diagnoseEnv, checks []diagnoseCheck, all bool) []DiagnosisLink {
+	var links []DiagnosisLink
+	for _, c := range checks {
+		link := c.run(env)
+		link.Name = c.name
+		links = append(links, link)
+		if !link.OK && !all {
+			break
+		}
+	}
+	return links
+}
+
+func runDiagnose(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	env := newDiagnoseEnv(townRoot)
+	links := runDiagnoseChain(env, diagnoseChecks(), diagnoseAll)
+
+	broken := 0
+	for _, l := range links {
+		if !l.OK {
+			broken++
+		}
+	}
+	if diagnoseJSON {
+		if err := outputJSON(links); err != nil {
+			return err
+		}
+	} else {
+		printDiagnosis(links, len(diagnoseChecks()))
+	}
+	if broken > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// newDiagnoseEnv wires the checks to the real town.
+func newDiagnoseEnv(townRoot string) *diagnoseEnv {
+	t := tmux.NewTmux()
+	router := mail.NewRouter(townRoot)
+	// Parked and docked rigs are offline on purpose; their agents being
+	// down is not a fault.
+	var rigs []string
+	for _, r := range discoverRigs(townRoot) {
+		if !IsRigParked(townRoot, r) && !IsRigDocked(townRoot, r, session.PrefixFor(r)) {
+			rigs = append(rigs, r)
+		}
+	}
+	sort.Strings(rigs)
+	return &diagnoseEnv{
+		townRoot:   townRoot,
+		rigs:       rigs,
+		now:        time.Now(),
+		hasSession: t.HasSession,
+		queueLen: func(rigName string) (int, error) {
+			r := &rig.Rig{Name: rigName, Path: filepath.Join(townRoot, rigName)}
+			items, err := refinery.NewManager(r).Queue()
+			return len(items), err
+		},
+		unread: func(address string) (int, error) {
+			mb, err := router.GetMailbox(address)
+			if err != nil {
+				return 0, err
+			}
+			msgs, err := mb.ListUnread()
+			return len(msgs), err
+		},
+		pending: func() ([]*polecat.PendingSpawn, error) {
+			return polecat.CheckInboxForSpawns(townRoot)
+		},
+		spendToday: func(s *squad.Squad) float64 {
+			total, _ := squadSpendToday(s, time.Now())
+			return total
+		},
+	}
+}
+
+func printDiagnosis(links []DiagnosisLink, total int) {
+	for _, l := range links {
+		if l.OK {
+			fmt.Printf("%s %-10s %s\n", style.SuccessPrefix, l.Name, style.Dim.Render(l.Detail))
+			continue
+		}
+		fmt.Printf("%s %-10s %s\n", style.ErrorPrefix, l.Name, l.Detail)
+		if l.Suggest != "" {
+			fmt.Printf("  %s %s\n", style.Dim.Render("Next:"), style.Bold.Render(l.Suggest))
+		}
+	}
+	last := links[len(links)-1]
+	switch {
+	case !last.OK && len(links) < total:
+		fmt.Printf("\n%s\n", style.Dim.Render("Stopped at the first broken link; fix it and re-run, or use --all."))
+	case last.OK:
+		fmt.Printf("\n%s Every link looks healthy. If the town is still idle, check for ready work with 'bd ready'.\n", style.Bold.Render("●"))
+	}
+}
+
+func diagnoseDaemon(env *diagnoseEnv) DiagnosisLink {
+	running, pid, err := daemon.IsRunning(env.townRoot)
+	if err != nil {
+		return DiagnosisLink{Detail: fmt.Sprintf("could not check the daemon: %v", err), Suggest: "gt daemon status"}
+	}
+	if !running {
+		return DiagnosisLink{Detail: "daemon is not running: nothing restarts agents or triggers spawns", Suggest: "gt daemon start"}
+	}
+	return DiagnosisLink{OK: true, Detail: fmt.Sprintf("running (PID %d)", pid)}
+}
+
+func diagnosePaused(env *diagnoseEnv) DiagnosisLink {
+	paused, state, _ := deacon.IsPaused(env.townRoot)
+	if !paused {
+		return DiagnosisLink{OK: true, Detail: "not paused"}
+	}
+	detail := "Deacon is paused: no spawns are triggered and nothing is restarted"
+	if state != nil && state.Reason != "" {
+		detail += fmt.Sprintf(" (%s)", state.Reason)
+	}
+	return DiagnosisLink{Detail: detail, Suggest: "gt deacon resume"}
+}
+
+func diagnoseDeaconHeartbeat(env *diagnoseEnv) DiagnosisLink {
+	hb := deacon.ReadHeartbeat(env.townRoot)
+	if hb == nil {
+		return DiagnosisLink{Detail: "Deacon has never written a heartbeat", Suggest: "gt deacon start"}
+	}
+	age := env.now.Sub(hb.Timestamp).Round(time.Second)
+	cfg, _ := deacon.LoadConfigOrDefault(env.townRoot)
+	if cfg.IsVeryStale(hb) {
+		return DiagnosisLink{Detail: fmt.Sprintf("heartbeat is %s old: the Deacon is stuck or gone", age), Suggest: "gt deacon restart"}
+	}
+	return DiagnosisLink{OK: true, Detail: fmt.Sprintf("heartbeat %s ago", age)}
+}
+
+func diagnoseCircuit(env *diagnoseEnv) DiagnosisLink {
+	s, err := degraded.Load(env.townRoot)
+	if err != nil {
+		return DiagnosisLink{Detail: fmt.Sprintf("could not read degraded state: %v", err), Suggest: "gt degraded"}
+	}
+	if !s.Active {
+		return DiagnosisLink{OK: true, Detail: "model API reachable"}
+	}
+	detail := fmt.Sprintf("degraded mode for %s: spawns refused and model gates frozen", s.Duration(env.now).Round(time.Second))
+	if s.Reason != "" {
+		detail += fmt.Sprintf(" (%s)", s.Reason)
+	}
+	return DiagnosisLink{Detail: detail, Suggest: "gt degraded probe"}
+}
+
+func diagnoseWitnesses(env *diagnoseEnv) DiagnosisLink {
+	if len(env.rigs) == 0 {
+		return DiagnosisLink{Detail: "no rigs: there is nowhere for work to happen", Suggest: "gt rig add <name> <git-url>"}
+	}
+	var down []string
+	for _, r := range env.rigs {
+		if ok, _ := env.hasSession(session.WitnessSessionName(session.PrefixFor(r))); !ok {
+			down = append(down, r)
+		}
+	}
+	if len(down) > 0 {
+		return DiagnosisLink{
+			Detail:  fmt.Sprintf("witness not running for %s: polecats there are unsupervised", strings.Join(down, ", ")),
+			Suggest: "gt witness start " + down[0],
+		}
+	}
+	return DiagnosisLink{OK: true, Detail: fmt.Sprintf("%d witness(es) alive", len(env.rigs))}
+}
+
+func diagnoseQueue(env *diagnoseEnv) DiagnosisLink {
+	var stalled []string
+	total := 0
+	for _, r := range env.rigs {
+		n, err := env.queueLen(r)
+		if err != nil || n == 0 {
+			continue
+		}
+		total += n
+		if ok, _ := env.hasSession(session.RefinerySessionName(session.PrefixFor(r))); !ok {
+			stalled = append(stalled, fmt.Sprintf("%s (%d MR(s))", r, n))
+		}
+	}
+	if len(stalled) > 0 {
+		rigName, _, _ := strings.Cut(stalled[0], " ")
+		return DiagnosisLink{
+			Detail:  "merge queue frozen, refinery not running: " + strings.Join(stalled, ", "),
+			Suggest: "gt refinery start " + rigName,
+		}
+	}
+	return DiagnosisLink{OK: true, Detail: fmt.Sprintf("%d MR(s) queued, refineries up", total)}
+}
+
+func diagnosePending(env *diagnoseEnv) DiagnosisLink {
+	pending, err := env.pending()
+	if err != nil {
+		return DiagnosisLink{Detail: fmt.Sprintf("could not read pending spawns: %v", err), Suggest: "gt deacon pending"}
+	}
+	cfg, _ := deacon.LoadConfigOrDefault(env.townRoot)
+	maxAge := cfg.GetPendingSpawnMaxAge()
+	var stuck []string
+	for _, ps := range pending {
+		if env.now.Sub(ps.SpawnedAt) > maxAge/2 {
+			stuck = append(stuck, ps.Rig+"/"+ps.Polecat)
+		}
+	}
+	if len(stuck) > 0 {
+		return DiagnosisLink{
+			Detail:  fmt.Sprintf("%d polecat(s) started but never triggered: %s", len(stuck), strings.Join(stuck, ", ")),
+			Suggest: "gt deacon pending",
+		}
+	}
+	return DiagnosisLink{OK: true, Detail: fmt.Sprintf("%d pending spawn(s)", len(pending))}
+}
+
+func diagnoseBudget(env *diagnoseEnv) DiagnosisLink {
+	reg, err := squad.Load(env.townRoot)
+	if err != nil {
+		return DiagnosisLink{Detail: fmt.Sprintf("could not read squads: %v", err), Suggest: "gt squad list"}
+	}
+	var blocked []string
+	var first string
+	for _, s := range reg.Sorted() {
+		reason := ""
+		switch {
+		case s.Paused:
+			reason = "paused"
+		case s.Budget.DailyUSD > 0 && env.spendToday(s) >= s.Budget.DailyUSD:
+			reason = fmt.Sprintf("daily budget $%.2f spent", s.Budget.DailyUSD)
+		}
+		if reason != "" {
+			blocked = append(blocked, fmt.Sprintf("%s (%s)", s.Name, reason))
+			if first == "" {
+				first = s.Name
+			}
+		}
+	}
+	if len(blocked) > 0 {
+		return DiagnosisLink{
+			Detail:  "squads refusing spawns: " + strings.Join(blocked, ", "),
+			Suggest: "gt squad report " + first,
+		}
+	}
+	return DiagnosisLink{OK: true, Detail: fmt.Sprintf("%d squad(s), none blocked", len(reg.Squads))}
+}
+
+func diagnoseMail(env *diagnoseEnv) DiagnosisLink {
+	n, err := env.unread("deacon/")
+	if err != nil {
+		return DiagnosisLink{Detail: fmt.Sprintf("could not read the Deacon's inbox: %v", err), Suggest: "gt mail inbox --identity deacon/"}
+	}
+	if n >= diagnoseMailBacklog {
+		return DiagnosisLink{
+			Detail:  fmt.Sprintf("%d unread messages in the Deacon's inbox: it is not keeping up", n),
+			Suggest: "gt mail inbox --identity deacon/",
+		}
+	}
+	return DiagnosisLink{OK: true, Detail: fmt.Sprintf("%d unread in the Deacon's inbox", n)}
+}
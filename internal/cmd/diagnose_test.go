@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/squad"
+)
+
+// fakeDiagnoseEnv is a healthy town with one rig, in a temp dir.
+func fakeDiagnoseEnv(t *testing.T) (*diagnoseEnv, map[string]bool) {
+	t.Helper()
+	townRoot := t.TempDir()
+	sessions := map[string]bool{
+		session.WitnessSessionName(session.PrefixFor("gastown")):  true,
+		session.RefinerySessionName(session.PrefixFor("gastown")): true,
+	}
+	env := &diagnoseEnv{
+		townRoot:   townRoot,
+		rigs:       []string{"gastown"},
+		now:        time.Now(),
+		hasSession: func(name string) (bool, error) { return sessions[name], nil },
+		queueLen:   func(string) (int, error) { return 2, nil },
+		unread:     func(string) (int, error) { return 0, nil },
+		pending:    func() ([]*polecat.PendingSpawn, error) { return nil, nil },
+		spendToday: func(*squad.Squad) float64 { return 0 },
+	}
+	return env, sessions
+}
+
+// chainFrom runs the chain from the named check onwards, skipping the
+// daemon and Deacon checks that need a live town.
+func chainFrom(env *diagnoseEnv, first string, all bool) []DiagnosisLink {
+	checks := diagnoseChecks()
+	for i, c := range checks {
+		if c.name == first {
+			checks = checks[i:]
+			break
+		}
+	}
+	return runDiagnoseChain(env, checks, all)
+}
+
+func TestDiagnose_HealthyChain(t *testing.T) {
+	env, _ := fakeDiagnoseEnv(t)
+	links := chainFrom(env, "circuit", false)
+	if len(links) != 6 {
+		t.Fatalf("got %d links, want 6: %+v", len(links), links)
+	}
+	for _, l := range links {
+		if !l.OK {
+			t.Errorf("%s broken: %s", l.Name, l.Detail)
+		}
+	}
+}
+
+func TestDiagnose_StopsAtFirstBrokenLink(t *testing.T) {
+	env, sessions := fakeDiagnoseEnv(t)
+	delete(sessions, session.WitnessSessionName(session.PrefixFor("gastown")))
+	delete(sessions, session.RefinerySessionName(session.PrefixFor("gastown")))
+
+	links := chainFrom(env, "circuit", false)
+	last := links[len(links)-1]
+	if last.Name != "witnesses" || last.OK {
+		t.Fatalf("last link = %+v, want broken witnesses", last)
+	}
+	if last.Suggest != "gt witness start gastown" {
+		t.Errorf("Suggest = %q", last.Suggest)
+	}
+
+	links = chainFrom(env, "circuit", true)
+	var queue DiagnosisLink
+	for _, l := range links {
+		if l.Name == "queue" {
+			queue = l
+		}
+	}
+	if queue.OK || queue.Suggest != "gt refinery start gastown" {
+		t.Errorf("queue = %+v, want frozen queue with refinery suggestion", queue)
+	}
+}
+
+func TestDiagnose_PausedAndStaleDeacon(t *testing.T) {
+	env, _ := fakeDiagnoseEnv(t)
+	if l := diagnoseDeaconHeartbeat(env); l.OK || l.Suggest != "gt deacon start" {
+		t.Errorf("no heartbeat = %+v", l)
+	}
+	if err := deacon.WriteHeartbeat(env.townRoot, &deacon.Heartbeat{Timestamp: time.Now().Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if l := diagnoseDeaconHeartbeat(env); l.OK || l.Suggest != "gt deacon restart" {
+		t.Errorf("stale heartbeat = %+v", l)
+	}
+
+	if l := diagnosePaused(env); !l.OK {
+		t.Errorf("unpaused = %+v", l)
+	}
+	if err := deacon.Pause(env.townRoot, "maintenance", "human"); err != nil {
+		t.Fatal(err)
+	}
+	if l := diagnosePaused(env); l.OK || !strings.Contains(l.Detail, "maintenance") || l.Suggest != "gt deacon resume" {
+		t.Errorf("paused = %+v", l)
+	}
+}
+
+func TestDiagnose_PendingBudgetAndMail(t *testing.T) {
+	env, _ := fakeDiagnoseEnv(t)
+	err := squad.Update(env.townRoot, func(reg *squad.Registry) error {
+		reg.Squads["search"] = &squad.Squad{Name: "search", Budget: squad.Budget{DailyUSD: 10}}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := diagnoseBudget(env); !l.OK {
+		t.Errorf("under budget = %+v", l)
+	}
+	env.spendToday = func(*squad.Squad) float64 { return 12 }
+	if l := diagnoseBudget(env); l.OK || l.Suggest != "gt squad report search" {
+		t.Errorf("over budget = %+v", l)
+	}
+
+	env.pending = func() ([]*polecat.PendingSpawn, error) {
+		return []*polecat.PendingSpawn{{Rig: "gastown", Polecat: "nux", SpawnedAt: time.Now().Add(-time.Hour)}}, nil
+	}
+	if l := diagnosePending(env); l.OK || !strings.Contains(l.Detail, "gastown/nux") {
+		t.Errorf("stuck spawn = %+v", l)
+	}
+
+	env.unread = func(string) (int, error) { return diagnoseMailBacklog, nil }
+	if l := diagnoseMail(env); l.OK {
+		t.Errorf("backlog = %+v, want broken", l)
+	}
+}
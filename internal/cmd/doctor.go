@@ -12,6 +12,7 @@ import (
 
 var (
 	doctorFix             bool
+	doctorDiff            bool
 	doctorVerbose         bool
 	doctorRig             string
 	doctorRestartSessions bool
@@ -44,6 +45,14 @@ Infrastructure checks:
   - beads-binary             Check that beads (bd) is installed and meets minimum version
   - daemon                   Check if daemon is running (fixable)
   - boot-health              Check Boot watchdog health (vet mode)
+  - mail-health              Check agent inbox backlogs and undeliverable mail
+  - limits                   Check fd/process limits for the configured polecat count (fixable)
+  - clock                    Check local clock skew against NTP
+  - connectivity             Check Anthropic API and git remote reachability, auth, and latency
+  - memory                   Check memory headroom, pressure, and swap thrashing
+  - fd-leaks                 Attribute open descriptors to town processes and flag steady growth
+  - security-policy          Check for AppArmor/SELinux denials against tmux, claude, gt (fixable)
+  - boot-time                Check polecat boot times against the budget and for regressions
 
 Cleanup checks (fixable):
   - orphan-sessions          Detect orphaned tmux sessions
@@ -57,6 +66,8 @@ Clone divergence checks:
   - clone-divergence         Detect clones significantly behind origin/main
   - default-branch-all-rigs  Verify default_branch exists on remote for all rigs
   - worktree-gitdir-valid    Verify worktree .git files reference existing paths (fixable)
+  - worktree-integrity       Check rig worktrees for stale git locks and damaged admin files (fixable)
+  - git-health               Check rig repos for corruption, loose objects, stale worktrees, detached polecats (fixable)
 
 Crew workspace checks:
   - crew-state               Validate crew worker state.json files (fixable)
@@ -92,6 +103,9 @@ Dolt checks:
   - dolt-server-reachable    Check dolt sql-server is reachable
   - dolt-orphaned-databases  Detect orphaned dolt databases
 
+Agent bead checks:
+  - beads-integrity          Validate agent bead fields, circuit states, and IDs (fixable)
+
 Patrol checks:
   - patrol-molecules-exist   Verify patrol molecules exist
   - patrol-hooks-wired       Verify daemon triggers patrols
@@ -99,6 +113,9 @@ Patrol checks:
   - patrol-plugins-accessible Verify plugin directories
 
 Use --fix to attempt automatic fixes for issues that support it.
+Use --fix --diff to preview instead: fixes that write files (such as the
+limits fix script's limits.conf, sysctl.d, systemd, and launchd files) print
+the files they would change and a unified diff, and nothing is applied.
 Use --rig to check a specific rig instead of the entire workspace.
 Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).`,
 	RunE: runDoctor,
@@ -106,6 +123,7 @@ Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).`
 
 func init() {
 	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Attempt to automatically fix issues")
+	doctorCmd.Flags().BoolVar(&doctorDiff, "diff", false, "Preview the file changes fixes would make without applying them (use with --fix)")
 	doctorCmd.Flags().BoolVarP(&doctorVerbose, "verbose", "v", false, "Show detailed output")
 	doctorCmd.Flags().StringVar(&doctorRig, "rig", "", "Check specific rig only")
 	doctorCmd.Flags().BoolVar(&doctorRestartSessions, "restart-sessions", false, "Restart patrol sessions when fixing stale settings (use with --fix)")
@@ -116,6 +134,10 @@ func init() {
 }
 
 func runDoctor(cmd *cobra.Command, args []string) error {
+	if doctorDiff && !doctorFix {
+		return fmt.Errorf("--diff previews fixes; use it with --fix")
+	}
+
 	// Find town root
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -147,6 +169,13 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewPreCheckoutHookCheck())
 	d.Register(doctor.NewDaemonCheck())
 	d.Register(doctor.NewBootHealthCheck())
+	d.Register(doctor.NewMailCheck())
+	d.Register(doctor.NewLimitsCheck())
+	d.Register(doctor.NewClockCheck())
+	d.Register(doctor.NewConnectivityCheck())
+	d.Register(doctor.NewMemoryCheck())
+	d.Register(doctor.NewFDCheck())
+	d.Register(doctor.NewSecurityPolicyCheck())
 	d.Register(doctor.NewCustomTypesCheck())
 	d.Register(doctor.NewRoleLabelCheck())
 	d.Register(doctor.NewFormulaCheck())
@@ -180,6 +209,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewPatrolPluginsAccessibleCheck())
 	d.Register(doctor.NewAgentBeadsCheck())
 	d.Register(doctor.NewStaleAgentBeadsCheck())
+	d.Register(doctor.NewBeadsIntegrityCheck())
 	d.Register(doctor.NewRigBeadsCheck())
 	d.Register(doctor.NewRoleBeadsCheck())
 
@@ -201,6 +231,9 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	// Priming subsystem check
 	d.Register(doctor.NewPrimingCheck())
 
+	// Polecat boot time budget and regressions
+	d.Register(doctor.NewBootTimeCheck())
+
 	// Crew workspace checks
 	d.Register(doctor.NewCrewStateCheck())
 	d.Register(doctor.NewCrewWorktreeCheck())
@@ -226,6 +259,8 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 
 	// Worktree gitdir validity (runs across all rigs, or specific rig with --rig)
 	d.Register(doctor.NewWorktreeGitdirCheck())
+	d.Register(doctor.NewWorktreeCheck())
+	d.Register(doctor.NewGitCheck())
 
 	// Rig-specific checks (only when --rig is specified)
 	if doctorRig != "" {
@@ -245,7 +280,9 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	// Run checks with streaming output
 	fmt.Println() // Initial blank line
 	var report *doctor.Report
-	if doctorFix {
+	if doctorFix && doctorDiff {
+		report = d.PlanFixesStreaming(ctx, os.Stdout, slowThreshold)
+	} else if doctorFix {
 		report = d.FixStreaming(ctx, os.Stdout, slowThreshold)
 	} else {
 		report = d.RunStreaming(ctx, os.Stdout, slowThreshold)
@@ -704,18 +704,15 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			}
 		}
 
-		// Get source issue for priority inheritance
-		var priority int
+		// Get source issue for priority and label inheritance
+		priority := 2 // Default
+		var labels []string
+		if sourceIssue, err := bd.Show(issueID); err == nil {
+			priority = sourceIssue.Priority
+			labels = beads.UserLabels(sourceIssue.Labels)
+		}
 		if donePriority >= 0 {
 			priority = donePriority
-		} else {
-			// Try to inherit from source issue
-			sourceIssue, err := bd.Show(issueID)
-			if err != nil {
-				priority = 2 // Default
-			} else {
-				priority = sourceIssue.Priority
-			}
 		}
 
 		// Check if MR bead already exists for this branch (idempotency)
@@ -754,6 +751,7 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 				Priority:    priority,
 				Description: description,
 				Ephemeral:   true,
+				Labels:      labels,
 			})
 			if err != nil {
 				// Non-fatal: record the error and skip to notifyWitness.
@@ -872,6 +870,11 @@ afterDoltMerge:
 			bodyLines = append(bodyLines, fmt.Sprintf("MergeStrategy: %s", convoyInfo.MergeStrategy))
 		}
 	}
+	// Requeued work carries its correlation ID through to completion
+	correlationID := issueCorrelationID(issueID, cwd)
+	if correlationID != "" {
+		bodyLines = append(bodyLines, fmt.Sprintf("Correlation: %s", correlationID))
+	}
 	if len(doneErrors) > 0 {
 		bodyLines = append(bodyLines, fmt.Sprintf("Errors: %s", strings.Join(doneErrors, "; ")))
 	}
@@ -917,7 +920,11 @@ afterDoltMerge:
 	if err := LogDone(townRoot, sender, issueID); err != nil {
 		style.PrintWarning("could not log done event: %v", err)
 	}
-	if err := events.LogFeed(events.TypeDone, sender, events.DonePayload(issueID, branch)); err != nil {
+	donePayload := events.DonePayload(issueID, branch)
+	if correlationID != "" {
+		donePayload["correlation_id"] = correlationID
+	}
+	if err := events.LogFeed(events.TypeDone, sender, donePayload); err != nil {
 		style.PrintWarning("could not log feed event: %v", err)
 	}
 
@@ -1413,3 +1420,16 @@ func selfKillSession(townRoot string, roleInfo RoleInfo) error {
 
 	return nil
 }
+
+// issueCorrelationID returns the correlation ID the Witness recorded on an
+// issue when it requeued it from a tripped polecat, or "" if it has none.
+func issueCorrelationID(issueID, cwd string) string {
+	if issueID == "" {
+		return ""
+	}
+	issue, err := beads.New(beads.ResolveBeadsDir(cwd)).Show(issueID)
+	if err != nil {
+		return ""
+	}
+	return beads.CorrelationID(issue.Description)
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/graphexport"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Export command flags
+var (
+	exportFormat string
+	exportSince  string
+	exportOutput string
+)
+
+var exportCmd = &cobra.Command{
+	Use:     "export",
+	GroupID: GroupDiag,
+	Short:   "Export town data for offline analysis",
+	RunE:    requireSubcommand,
+}
+
+var exportGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export town activity as a graph dataset",
+	Long: `Export the event log, beads, and mail metadata as a graph.
+
+Nodes are agents and beads. Edges are:
+  - events from the town event log (sling, hook, done, nudge, ...), from
+    the acting agent to the bead or agent the event names
+  - created / assigned, from agents to beads
+  - depends_on / child_of, between beads
+  - mail, from sender to recipient (metadata only, never bodies)
+
+Each event and mail is its own timestamped edge, so the dataset can be
+sliced by time. Agent nodes carry activity counts and first/last seen;
+bead nodes carry status, priority, and cycle time when closed.
+
+Formats:
+  jsonl    One JSON object per line, tagged "record": "node" or "edge"
+  graphml  GraphML for Gephi, yEd, NetworkX (nx.read_graphml), igraph
+
+Examples:
+  gt export graph > town.jsonl
+  gt export graph --format graphml -o town.graphml
+  gt export graph --since 7d`,
+	RunE: runExportGraph,
+}
+
+func init() {
+	exportGraphCmd.Flags().StringVar(&exportFormat, "format", graphexport.FormatJSONL, "Output format: jsonl or graphml")
+	exportGraphCmd.Flags().StringVar(&exportSince, "since", "", "Only include activity since duration (e.g., 24h, 7d)")
+	exportGraphCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write to file instead of stdout")
+
+	exportCmd.AddCommand(exportGraphCmd)
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExportGraph(cmd *cobra.Command, args []string) error {
+	if exportFormat != graphexport.FormatJSONL && exportFormat != graphexport.FormatGraphML {
+		return fmt.Errorf("invalid --format %q (use jsonl or graphml)", exportFormat)
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var since time.Time
+	if exportSince != "" {
+		d, err := parseDuration(exportSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since duration: %w", err)
+		}
+		since = time.Now().Add(-d)
+	}
+
+	g := graphexport.New(since)
+
+	// Sources are best-effort: a town without mail or a rig whose beads
+	// can't be read still exports everything else.
+	evts, err := readTownEvents(townRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not read events: %v\n", err)
+	}
+	for _, e := range evts {
+		g.AddEvent(e)
+	}
+
+	issues, err := collectExportIssues(townRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not query beads: %v\n", err)
+	}
+	for _, issue := range issues {
+		g.AddIssue(issue)
+	}
+
+	msgs, err := mail.ListAllMessages(townRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not query mail: %v\n", err)
+	}
+	for _, msg := range msgs {
+		g.AddMessage(msg)
+	}
+
+	var out io.Writer = os.Stdout
+	if exportOutput != "" {
+		f, err := os.Create(exportOutput) //nolint:gosec // G304: user-chosen output path
+		if err != nil {
+			return fmt.Errorf("creating output file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	w := bufio.NewWriter(out)
+	if err := g.Write(w, exportFormat); err != nil {
+		return fmt.Errorf("writing graph: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("writing graph: %w", err)
+	}
+
+	if exportOutput != "" {
+		fmt.Fprintf(os.Stderr, "%s Exported %d nodes and %d edges to %s\n",
+			style.Success.Render("✓"), len(g.Nodes()), len(g.Edges()), exportOutput)
+	}
+	return nil
+}
+
+// readTownEvents reads every event from the town's raw event log.
+func readTownEvents(townRoot string) ([]events.Event, error) {
+	file, err := os.Open(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var evts []events.Event
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // Skip malformed lines
+		}
+		evts = append(evts, e)
+	}
+	return evts, scanner.Err()
+}
+
+// collectExportIssues lists every bead in the town and rig databases,
+// deduplicated by ID.
+func collectExportIssues(townRoot string) ([]*beads.Issue, error) {
+	locations := []string{townRoot}
+	rigsConfigPath := filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON)
+	if rigsConfig, err := config.LoadRigsConfig(rigsConfigPath); err == nil {
+		var rigNames []string
+		for name := range rigsConfig.Rigs {
+			rigNames = append(rigNames, name)
+		}
+		sort.Strings(rigNames)
+		for _, name := range rigNames {
+			rigPath := filepath.Join(townRoot, name)
+			if _, err := os.Stat(filepath.Join(rigPath, constants.DirBeads)); err == nil {
+				locations = append(locations, rigPath)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	var all []*beads.Issue
+	var firstErr error
+	for _, loc := range locations {
+		issues, err := beads.New(loc).List(beads.ListOptions{Status: "all", Priority: -1})
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", loc, err)
+			}
+			continue
+		}
+		for _, issue := range issues {
+			if !seen[issue.ID] {
+				seen[issue.ID] = true
+				all = append(all, issue)
+			}
+		}
+	}
+	return all, firstErr
+}
@@ -73,6 +73,7 @@ const HQGitignore = `# Gas Town HQ .gitignore
 # =============================================================================
 daemon/
 logs/
+forensics/
 
 # =============================================================================
 # Rig git worktrees (recreate with 'gt sling' or 'gt rig add')
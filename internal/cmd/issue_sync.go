@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/issuesync"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var issueSyncDryRun bool
+
+var issueSyncCmd = &cobra.Command{
+	Use:     "issue-sync [rig...]",
+	GroupID: GroupWork,
+	Short:   "Sync work beads with external issue trackers",
+	Long: `Mirror each rig's work beads to the issue tracker configured in its
+settings, and back.
+
+A sync:
+  - creates a work bead for each new open issue in scope (title, body,
+    priority and labels carried over, linked by a label such as
+    github:owner/repo#12 or jira:OPS-12)
+  - closes or reopens the bead when its issue is closed or reopened, and
+    the issue when the bead is: closing a bead (its work merged) closes
+    the issue with a comment naming it
+  - keeps priority in step in both directions (GitHub: a P0-P4 label;
+    JIRA: the issue's priority, Highest-Lowest by default)
+  - comments on the issue when the bead is assigned to a polecat (JIRA:
+    also moves it to an in-progress status)
+  - opens an issue for each open bead with the export label, if set
+
+Each sync remembers where both sides stood in
+<town>/.runtime/issue-sync/, so it can tell which side changed; a change
+on both sides is settled in the tracker's favour.
+
+Configure a rig in <rig>/settings/config.json:
+
+  "issue_sync": {"github": {"repo": "owner/name", "label": "gastown"}}
+
+or, for the issues a JIRA filter selects:
+
+  "issue_sync": {"jira": {"url": "https://example.atlassian.net",
+                          "jql": "project = OPS AND labels = gastown",
+                          "project": "OPS"}}
+
+GitHub sync runs through the gh CLI, which must be authenticated. JIRA
+sync reads an API token from GT_JIRA_TOKEN and, for JIRA Cloud, the
+account's email from GT_JIRA_USER (or "user"); status changes run the
+workflow transition into the matching status category unless
+done_transition, start_transition or reopen_transition name one. With no
+rigs named, every rig with issue_sync settings is synced. The daemon runs
+the same sync when the issue_sync patrol is enabled in mayor/daemon.json:
+
+  "issue_sync": {"enabled": true, "interval": "5m"}
+
+Examples:
+  gt issue-sync
+  gt issue-sync gastown --dry-run`,
+	RunE: runIssueSync,
+}
+
+func init() {
+	issueSyncCmd.Flags().BoolVar(&issueSyncDryRun, "dry-run", false, "Show what would change without changing beads or issues")
+	rootCmd.AddCommand(issueSyncCmd)
+}
+
+func runIssueSync(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	rigs := args
+	if len(rigs) == 0 {
+		rigs = discoverRigs(townRoot)
+	}
+	synced, failed := 0, 0
+	for _, rigName := range rigs {
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rigName)))
+		if err != nil || settings.IssueSync == nil || len(issuesync.RigTrackers(settings.IssueSync)) == 0 {
+			if len(args) > 0 {
+				style.PrintWarning("%s: no issue_sync settings", rigName)
+			}
+			continue
+		}
+		synced++
+
+		results, err := issuesync.SyncRig(ctx, townRoot, rigName, settings.IssueSync, issueSyncDryRun)
+		names := make([]string, 0, len(results))
+		for name := range results {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			printIssueSyncResult(rigName, name, results[name])
+		}
+		if err != nil {
+			failed++
+			style.PrintWarning("%s: %v", rigName, err)
+		}
+	}
+
+	if synced == 0 {
+		fmt.Printf("%s No rigs have issue_sync settings\n", style.Dim.Render("○"))
+		return nil
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rig(s) failed to sync", failed, synced)
+	}
+	return nil
+}
+
+func printIssueSyncResult(rigName, tracker string, result *issuesync.Result) {
+	verb := "synced"
+	if issueSyncDryRun {
+		verb = "would sync"
+	}
+	fmt.Printf("%s %s ↔ %s: %s, %d change(s)\n", style.Bold.Render("✓"), rigName, tracker, verb, len(result.Actions))
+	for _, action := range result.Actions {
+		fmt.Printf("    %s\n", action)
+	}
+	for _, w := range result.Warnings {
+		style.PrintWarning("%s", w)
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/lint"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	lintJSON    bool
+	lintOffline bool
+	lintStrict  bool
+)
+
+var lintCmd = &cobra.Command{
+	Use:     "lint",
+	GroupID: GroupDiag,
+	Short:   "Validate town configuration and role prompts",
+	Long: `Validate the town's configuration as a whole, without touching running agents.
+
+Rules:
+  schema      Config files parse, pass their loader's validation, use known
+              keys, and hold valid durations; role overrides are valid TOML
+  prompt      Role overrides point prompt_template at an existing template
+  remote      Registered rigs exist on disk and their git remotes answer
+  policy      Agent names resolve, role maps and overrides name real roles
+              and valid values, paired thresholds are ordered
+  deprecated  Settings that were removed or replaced
+
+Exits 1 when any error is found (or any finding, with --strict), so it can
+run in CI for the town's own repo. Use --offline where rig remotes can't be
+reached.
+
+Examples:
+  gt lint                 # Lint the current town
+  gt lint --offline       # Skip probing rig remotes
+  gt lint --json --strict # Structured findings; fail on warnings too`,
+	Args: cobra.NoArgs,
+	RunE: runLint,
+}
+
+func init() {
+	lintCmd.Flags().BoolVar(&lintJSON, "json", false, "Output findings as JSON")
+	lintCmd.Flags().BoolVar(&lintOffline, "offline", false, "Skip probing rig remotes")
+	lintCmd.Flags().BoolVar(&lintStrict, "strict", false, "Exit non-zero on warnings too")
+	rootCmd.AddCommand(lintCmd)
+}
+
+// lintReport is the JSON output of gt lint.
+type lintReport struct {
+	TownRoot string         `json:"town_root"`
+	Errors   int            `json:"errors"`
+	Warnings int            `json:"warnings"`
+	Findings []lint.Finding `json:"findings"`
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	findings := lint.New(townRoot, lint.Options{Offline: lintOffline}).Run()
+	errs, warns := lint.Counts(findings)
+
+	if lintJSON {
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(lintReport{TownRoot: townRoot, Errors: errs, Warnings: warns, Findings: findings}); err != nil {
+			return err
+		}
+	} else {
+		for _, f := range findings {
+			icon := style.Warning.Render("⚠")
+			if f.Severity == lint.SeverityError {
+				icon = style.Error.Render("✗")
+			}
+			fmt.Printf("%s %s\n", icon, f)
+		}
+		if len(findings) == 0 {
+			fmt.Printf("%s Town configuration is clean\n", style.Success.Render("✓"))
+		} else {
+			fmt.Printf("\n%d error(s), %d warning(s)\n", errs, warns)
+		}
+	}
+
+	if errs > 0 || (lintStrict && warns > 0) {
+		return NewSilentExit(1)
+	}
+	return nil
+}
@@ -116,7 +116,7 @@ func runMailInbox(cmd *cobra.Command, args []string) error {
 
 		// Show 1-based index for easy reference with 'gt mail read <n>'
 		indexStr := style.Dim.Render(fmt.Sprintf("%d.", i+1))
-		fmt.Printf("  %s %s %s%s%s%s\n", indexStr, readMarker, msg.Subject, typeMarker, priorityMarker, wispMarker)
+		fmt.Println(style.FitLine(fmt.Sprintf("  %s %s %s%s%s%s", indexStr, readMarker, msg.Subject, typeMarker, priorityMarker, wispMarker)))
 		fmt.Printf("      %s from %s\n",
 			style.Dim.Render(msg.ID),
 			msg.From)
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/metrics"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Metrics command flags
+var (
+	metricsJSON       bool
+	metricsPushAddr   string
+	metricsPushPrefix string
+	metricsPushTags   []string
+	metricsPushFlavor string
+	metricsPushDryRun bool
+)
+
+var metricsCmd = &cobra.Command{
+	Use:     "metrics",
+	GroupID: GroupDiag,
+	Short:   "Show or push key town metrics",
+	Long: `Show key Gas Town metrics, or push them to a StatsD/Datadog agent.
+
+Metrics collected:
+  sessions.active              Running agent sessions (tags: role, rig)
+  nudge_queue.pending          Queued nudges across all agent sessions
+  polecats.count               Polecat worktrees per rig (tag: rig)
+  daemon.running               1 if the daemon is running, else 0
+  daemon.heartbeats            Completed daemon heartbeats
+  daemon.heartbeat_age_seconds Seconds since the last daemon heartbeat
+
+Without a subcommand, prints the current values (use --json for scrapers).
+
+For push-based monitoring, enable the metrics_push patrol in
+mayor/daemon.json and the daemon pushes on an interval:
+
+  "patrols": {
+    "metrics_push": {
+      "enabled": true,
+      "address": "127.0.0.1:8125",
+      "prefix": "gt",
+      "flavor": "datadog",
+      "tags": ["env:prod", "town:hq"],
+      "interval": "60s"
+    }
+  }
+
+Plain statsd has no tags, so tag values are folded into the metric name
+(gt.polecats.count.gastown). The datadog flavor sends them as DogStatsD tags.`,
+	Args: cobra.NoArgs,
+	RunE: runMetrics,
+}
+
+var metricsPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Push metrics once to a StatsD/Datadog agent",
+	Long: `Collect metrics and push them once to a StatsD/Datadog agent over UDP.
+
+Settings come from the metrics_push section of mayor/daemon.json;
+flags override them. Useful for testing an agent setup or driving pushes
+from cron instead of the daemon.
+
+Examples:
+  gt metrics push
+  gt metrics push --addr statsd.internal:8125 --prefix town
+  gt metrics push --flavor datadog --tag env:prod --tag team:infra
+  gt metrics push --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runMetricsPush,
+}
+
+func init() {
+	metricsCmd.Flags().BoolVar(&metricsJSON, "json", false, "Output as JSON")
+
+	metricsPushCmd.Flags().StringVar(&metricsPushAddr, "addr", "", "StatsD agent host:port (default 127.0.0.1:8125)")
+	metricsPushCmd.Flags().StringVar(&metricsPushPrefix, "prefix", "", "Metric name prefix (default gt)")
+	metricsPushCmd.Flags().StringArrayVar(&metricsPushTags, "tag", nil, "Extra key:value tag for every metric (repeatable)")
+	metricsPushCmd.Flags().StringVar(&metricsPushFlavor, "flavor", "", "Wire format: statsd or datadog")
+	metricsPushCmd.Flags().BoolVar(&metricsPushDryRun, "dry-run", false, "Print the lines that would be sent")
+
+	metricsCmd.AddCommand(metricsPushCmd)
+	rootCmd.AddCommand(metricsCmd)
+}
+
+// collectTownMetrics gathers metrics from the CLI's vantage point: live tmux
+// sessions plus the daemon's persisted state.
+func collectTownMetrics(townRoot string) []metrics.Metric {
+	var snap metrics.Snapshot
+	if sessions, err := tmux.NewTmux().ListSessions(); err == nil {
+		snap.Sessions = sessions
+	}
+	if running, _, err := daemon.IsRunning(townRoot); err == nil {
+		snap.DaemonRunning = running
+	}
+	if state, err := daemon.LoadState(townRoot); err == nil {
+		snap.HeartbeatCount = state.HeartbeatCount
+		snap.LastHeartbeat = state.LastHeartbeat
+	}
+	return metrics.Collect(townRoot, snap, time.Now())
+}
+
+func runMetrics(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	collected := collectTownMetrics(townRoot)
+	if metricsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(collected)
+	}
+
+	fmt.Printf("%s Town Metrics\n\n", style.Bold.Render("📈"))
+	for _, m := range collected {
+		fmt.Printf("  %-30s %10s  %s\n", m.Name, formatMetricValue(m.Value), style.Dim.Render(formatMetricTags(m.Tags)))
+	}
+	return nil
+}
+
+func runMetricsPush(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	opts, err := daemon.MetricsPushOptions(daemon.LoadPatrolConfig(townRoot))
+	if err != nil {
+		return err
+	}
+	if metricsPushAddr != "" {
+		opts.Address = metricsPushAddr
+	}
+	if cmd.Flags().Changed("prefix") {
+		opts.Prefix = metricsPushPrefix
+	}
+	if len(metricsPushTags) > 0 {
+		opts.Tags = append(opts.Tags, metricsPushTags...)
+	}
+	if metricsPushFlavor != "" {
+		if opts.Flavor, err = metrics.ParseFlavor(metricsPushFlavor); err != nil {
+			return err
+		}
+	}
+
+	collected := collectTownMetrics(townRoot)
+	if metricsPushDryRun {
+		for _, m := range collected {
+			fmt.Println(metrics.FormatLine(m, opts))
+		}
+		return nil
+	}
+
+	if err := metrics.Push(collected, opts); err != nil {
+		return err
+	}
+	addr := opts.Address
+	if addr == "" {
+		addr = metrics.DefaultStatsDAddress
+	}
+	fmt.Printf("%s Pushed %d metric(s) to %s\n", style.Success.Render("✓"), len(collected), addr)
+	return nil
+}
+
+// formatMetricValue prints integers without a fractional part.
+func formatMetricValue(v float64) string {
+	if v == float64(int64(v)) {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%.1f", v)
+}
+
+// formatMetricTags renders tags as sorted key=value pairs.
+func formatMetricTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
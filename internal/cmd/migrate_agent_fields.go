@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var migrateAgentFieldsDryRun bool
+
+var migrateAgentFieldsCmd = &cobra.Command{
+	Use:     "migrate-agent-fields",
+	Short:   "Move agent bead fields from description lines into the JSON fields block",
+	GroupID: GroupWorkspace,
+	Long: fmt.Sprintf(`Migrate agent beads to the versioned agent fields block.
+
+Agent beads used to hold their fields (role_type, rig, hook_bead,
+circuit_state, ...) as "key: value" lines in the description. They now hold
+them in a JSON block, schema version %d, fenced as gt:agent-fields. Beads
+still in the old format are read as before and rewritten the next time a
+field changes; this command rewrites them all at once.
+
+The migration iterates all databases (town + per-rig). Each bead is
+rewritten under its agent bead lock, so it's safe to run while agents are
+working. Dog beads keep their own format and are skipped, as are beads
+whose description has no agent fields ('gt doctor' reports those).
+
+Examples:
+  gt migrate-agent-fields            # Run the migration
+  gt migrate-agent-fields --dry-run  # Preview what would be migrated`, beads.AgentFieldsSchemaVersion),
+	RunE: runMigrateAgentFields,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateAgentFieldsCmd)
+	migrateAgentFieldsCmd.Flags().BoolVar(&migrateAgentFieldsDryRun, "dry-run", false, "Preview what would be migrated without making changes")
+}
+
+func runMigrateAgentFields(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	targets, err := migrationTargets(townRoot)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Migrating agent fields across %d database(s)\n\n",
+		style.Bold.Render("Agent Fields Migration"), len(targets))
+
+	totalMigrated, totalCurrent, totalSkipped, totalFailed := 0, 0, 0, 0
+	for _, target := range targets {
+		b := beads.NewWithBeadsDir(filepath.Dir(target.beadsDir), target.beadsDir)
+		migration, err := b.MigrateAgentBeads(migrateAgentFieldsDryRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  warning: %s: listing agent beads: %v\n", target.name, err)
+			continue
+		}
+
+		for _, id := range migration.Migrated {
+			if migrateAgentFieldsDryRun {
+				fmt.Printf("  %s %s — would migrate\n", style.Dim.Render("[DRY RUN]"), id)
+			} else {
+				fmt.Printf("  %s %s\n", style.Success.Render("✓"), id)
+			}
+		}
+		failed := make([]string, 0, len(migration.Failed))
+		for id := range migration.Failed {
+			failed = append(failed, id)
+		}
+		sort.Strings(failed)
+		for _, id := range failed {
+			fmt.Fprintf(os.Stderr, "  warning: %s: failed to migrate %s: %v\n", target.name, id, migration.Failed[id])
+		}
+
+		if len(migration.Migrated) > 0 || len(failed) > 0 {
+			fmt.Printf("  %s: %d migrated, %d current, %d skipped, %d failed\n",
+				target.name, len(migration.Migrated), migration.Current, len(migration.Skipped), len(failed))
+		} else {
+			fmt.Printf("  %s: %s\n", target.name,
+				style.Dim.Render(fmt.Sprintf("%d current, %d skipped", migration.Current, len(migration.Skipped))))
+		}
+		totalMigrated += len(migration.Migrated)
+		totalCurrent += migration.Current
+		totalSkipped += len(migration.Skipped)
+		totalFailed += len(failed)
+	}
+
+	if migrateAgentFieldsDryRun {
+		fmt.Printf("\n%s Would migrate %d agent bead(s); %d current, %d skipped\n",
+			style.Bold.Render("[DRY RUN]"), totalMigrated, totalCurrent, totalSkipped)
+	} else if totalFailed > 0 {
+		fmt.Printf("\n%s Migrated %d, failed %d; %d current, %d skipped\n",
+			style.Bold.Render("Done:"), totalMigrated, totalFailed, totalCurrent, totalSkipped)
+	} else {
+		fmt.Printf("\n%s Migrated %d agent bead(s); %d current, %d skipped\n",
+			style.Success.Render("✓"), totalMigrated, totalCurrent, totalSkipped)
+	}
+	return nil
+}
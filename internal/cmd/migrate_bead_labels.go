@@ -39,6 +39,39 @@ func init() {
 	migrateBeadLabelsCmd.Flags().BoolVar(&migrateBeadLabelsDryRun, "dry-run", false, "Preview what would be migrated without making changes")
 }
 
+// migrationTarget is a beads database a migration command processes.
+type migrationTarget struct {
+	name     string // display name
+	beadsDir string // path to .beads directory
+}
+
+// migrationTargets lists the town's beads databases: the town's, then each
+// rig's from routes.jsonl.
+func migrationTargets(townRoot string) ([]migrationTarget, error) {
+	// Load routes to discover all beads databases
+	townBeadsDir := beads.GetTownBeadsPath(townRoot)
+	routes, err := beads.LoadRoutes(townBeadsDir)
+	if err != nil {
+		return nil, fmt.Errorf("loading routes: %w", err)
+	}
+
+	// Town-level beads
+	targets := []migrationTarget{{name: "town", beadsDir: townBeadsDir}}
+
+	// Per-rig beads from routes
+	for _, route := range routes {
+		if route.Path == "." {
+			continue // Already handled as town
+		}
+		rigBeadsDir := filepath.Join(townRoot, route.Path, ".beads")
+		if _, err := os.Stat(rigBeadsDir); os.IsNotExist(err) {
+			continue // Skip if rig beads dir doesn't exist
+		}
+		targets = append(targets, migrationTarget{name: route.Path, beadsDir: rigBeadsDir})
+	}
+	return targets, nil
+}
+
 // gtTypesToMigrate lists the original GT types that need label migration.
 // Later types (queue, event, message, etc.) already use labels at creation time.
 var gtTypesToMigrate = []string{"agent", "role", "rig", "convoy", "slot"}
@@ -64,39 +97,9 @@ func runMigrateBeadLabels(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Load routes to discover all beads databases
-	townBeadsDir := beads.GetTownBeadsPath(townRoot)
-	routes, err := beads.LoadRoutes(townBeadsDir)
+	targets, err := migrationTargets(townRoot)
 	if err != nil {
-		return fmt.Errorf("loading routes: %w", err)
-	}
-
-	// Build list of databases to process: town + each rig
-	type dbTarget struct {
-		name     string // display name
-		beadsDir string // path to .beads directory
-	}
-	var targets []dbTarget
-
-	// Town-level beads
-	targets = append(targets, dbTarget{
-		name:     "town",
-		beadsDir: townBeadsDir,
-	})
-
-	// Per-rig beads from routes
-	for _, route := range routes {
-		if route.Path == "." {
-			continue // Already handled as town
-		}
-		rigBeadsDir := filepath.Join(townRoot, route.Path, ".beads")
-		if _, err := os.Stat(rigBeadsDir); os.IsNotExist(err) {
-			continue // Skip if rig beads dir doesn't exist
-		}
-		targets = append(targets, dbTarget{
-			name:     route.Path,
-			beadsDir: rigBeadsDir,
-		})
+		return err
 	}
 
 	fmt.Printf("%s Migrating bead labels across %d database(s)\n\n",
@@ -8,19 +8,6 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 )
 
-// isBlockingDepType returns true for dependency types that block molecule step
-// progress. Matches beads' canonical blocking types (AffectsReadyWork) except
-// parent-child, which represents molecule→step hierarchy in this context.
-// Unknown/custom types are non-blocking, matching beads' default behavior.
-func isBlockingDepType(depType string) bool {
-	switch depType {
-	case "blocks", "conditional-blocks", "waits-for":
-		return true
-	default:
-		return false
-	}
-}
-
 // sortStepsBySequence sorts step issues by their sequence number suffix (.1, .2, etc.)
 func sortStepsBySequence(steps []*beads.Issue) {
 	sort.Slice(steps, func(i, j int) bool {
@@ -238,7 +238,7 @@ func findAllReadySteps(b *beads.Beads, moleculeID string) ([]*beads.Issue, bool,
 	}
 
 	// Delegate readiness to beads' canonical ready-work semantics.
-	// This replaces manual dependency walking with isBlockingDepType,
+	// This replaces manual dependency walking with beads.IsBlockingDepType,
 	// using beads' blocked_issues_cache which handles all blocking types,
 	// transitive propagation, and conditional-blocks resolution.
 	readySteps, err := b.ReadyForMol(moleculeID)
@@ -289,7 +289,7 @@ func TestStepDoneScenarios(t *testing.T) {
 					allDepsClosed := true
 					hasBlockingDeps := false
 					for _, dep := range step.Dependencies {
-						if !isBlockingDepType(dep.DependencyType) {
+						if !beads.IsBlockingDepType(dep.DependencyType) {
 							continue // Skip parent-child and other non-blocking relationships
 						}
 						hasBlockingDeps = true
@@ -340,7 +340,7 @@ func makeStepIssueWithDepType(id, title, parent, status string, deps []beads.Iss
 	}
 }
 
-// TestDepTypeBlockingSemantics verifies that isBlockingDepType matches beads'
+// TestDepTypeBlockingSemantics verifies that beads.IsBlockingDepType matches beads'
 // canonical AffectsReadyWork semantics: only "blocks", "conditional-blocks",
 // and "waits-for" are blocking. Unknown/custom types (including "needs", empty
 // string) are non-blocking — matching beads' default behavior. Parent-child is
@@ -438,7 +438,7 @@ func TestDepTypeBlockingSemantics(t *testing.T) {
 				allDepsClosed := true
 				hasBlockingDeps := false
 				for _, dep := range step.Dependencies {
-					if !isBlockingDepType(dep.DependencyType) {
+					if !beads.IsBlockingDepType(dep.DependencyType) {
 						continue
 					}
 					hasBlockingDeps = true
@@ -491,7 +491,7 @@ func TestReadyStepOrderReversed(t *testing.T) {
 		allDepsClosed := true
 		hasBlockingDeps := false
 		for _, dep := range step.Dependencies {
-			if !isBlockingDepType(dep.DependencyType) {
+			if !beads.IsBlockingDepType(dep.DependencyType) {
 				continue
 			}
 			hasBlockingDeps = true
@@ -556,7 +556,7 @@ func TestMoleculeDepTypeFilterMixed(t *testing.T) {
 		{ID: "gt-mol", Title: "intake", DependencyType: "parent-child"},
 	}))
 
-	// Run algorithm using isBlockingDepType
+	// Run algorithm using beads.IsBlockingDepType
 	children, _ := m.List(beads.ListOptions{Parent: "gt-mol", Status: "all"})
 	closedIDs := make(map[string]bool)
 	var openStepIDs []string
@@ -579,7 +579,7 @@ func TestMoleculeDepTypeFilterMixed(t *testing.T) {
 		allDepsClosed := true
 		hasBlockingDeps := false
 		for _, dep := range step.Dependencies {
-			if !isBlockingDepType(dep.DependencyType) {
+			if !beads.IsBlockingDepType(dep.DependencyType) {
 				continue
 			}
 			hasBlockingDeps = true
@@ -23,6 +23,8 @@ var (
 	mqSubmitEpic      string
 	mqSubmitPriority  int
 	mqSubmitNoCleanup bool
+	mqSubmitHotfix    bool
+	mqSubmitPreempt   bool
 
 	// Retry flags
 	mqRetryNow bool
@@ -39,6 +41,7 @@ var (
 	mqListEpic    string
 	mqListJSON    bool
 	mqListVerify  bool
+	mqListLabels  []string
 
 	// Status command flags
 	mqStatusJSON bool
@@ -106,6 +109,7 @@ Examples:
   gt mq submit --issue gp-abc            # Explicit issue
   gt mq submit --epic gt-xyz             # Target integration branch explicitly
   gt mq submit --priority 0              # Override priority (P0)
+  gt mq submit --hotfix --preempt        # Production-down fix (see 'gt mq hotfix')
   gt mq submit --no-cleanup              # Submit without auto-cleanup`,
 	RunE: runMqSubmit,
 }
@@ -143,7 +147,8 @@ Examples:
   gt mq list greenplace
   gt mq list greenplace --ready
   gt mq list greenplace --status=open
-  gt mq list greenplace --worker=Nux`,
+  gt mq list greenplace --worker=Nux
+  gt mq list greenplace --label 'area:*'`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMQList,
 }
@@ -285,6 +290,8 @@ func init() {
 	mqSubmitCmd.Flags().StringVar(&mqSubmitEpic, "epic", "", "Target epic's integration branch instead of main")
 	mqSubmitCmd.Flags().IntVarP(&mqSubmitPriority, "priority", "p", -1, "Override priority (0-4, default: inherit from issue)")
 	mqSubmitCmd.Flags().BoolVar(&mqSubmitNoCleanup, "no-cleanup", false, "Don't auto-cleanup after submit (for polecats)")
+	mqSubmitCmd.Flags().BoolVar(&mqSubmitHotfix, "hotfix", false, "Submit into the hotfix lane (jumps the queue, runs hotfix gates)")
+	mqSubmitCmd.Flags().BoolVar(&mqSubmitPreempt, "preempt", false, "With --hotfix: preempt an in-progress merge of non-critical work")
 
 	// Retry flags
 	mqRetryCmd.Flags().BoolVar(&mqRetryNow, "now", false, "Immediately process instead of waiting for refinery loop")
@@ -296,6 +303,7 @@ func init() {
 	mqListCmd.Flags().StringVar(&mqListEpic, "epic", "", "Show MRs targeting integration/<epic>")
 	mqListCmd.Flags().BoolVar(&mqListJSON, "json", false, "Output as JSON")
 	mqListCmd.Flags().BoolVar(&mqListVerify, "verify", false, "Verify branches exist in git (shows MISSING for deleted branches)")
+	mqListCmd.Flags().StringArrayVar(&mqListLabels, "label", nil, "Only show MRs with this label (!label: without it), can be repeated")
 
 	// Reject flags
 	mqRejectCmd.Flags().StringVarP(&mqRejectReason, "reason", "r", "", "Reason for rejection (required unless --stdin)")
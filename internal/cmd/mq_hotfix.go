@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ hotfix command flags
+var (
+	mqHotfixPreempt bool
+	mqHotfixClear   bool
+)
+
+var mqHotfixCmd = &cobra.Command{
+	Use:   "hotfix <rig> <mr-id-or-branch>",
+	Short: "Move a merge request into the hotfix lane",
+	Long: `Move a merge request into the hotfix lane.
+
+Hotfixes (label gt:hotfix) are for production-down fixes that can't wait
+behind convoy work. They sort ahead of every other MR in the queue and run
+the rig's hotfix_gates (merge_queue config) instead of the full gates, when
+those are configured.
+
+With --preempt, the refinery also abandons an in-progress merge of
+non-critical work (not P0, not itself a hotfix) so the hotfix lands sooner.
+Preemption only happens before the squash merge is pushed: the target
+branch is reset to origin and the preempted MR goes back to the queue
+unchanged, with no failure notification.
+
+Examples:
+  gt mq hotfix gastown gt-mr-abc123
+  gt mq hotfix gastown polecat/nux/gt-xyz --preempt
+  gt mq hotfix gastown gt-mr-abc123 --clear    # Back to the normal lane
+
+Submit straight into the lane with: gt mq submit --hotfix [--preempt]`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQHotfix,
+}
+
+func init() {
+	mqHotfixCmd.Flags().BoolVar(&mqHotfixPreempt, "preempt", false, "Preempt an in-progress merge of non-critical work")
+	mqHotfixCmd.Flags().BoolVar(&mqHotfixClear, "clear", false, "Remove the MR from the hotfix lane")
+
+	mqCmd.AddCommand(mqHotfixCmd)
+}
+
+// hotfixLabels returns the labels that put an MR in the hotfix lane.
+func hotfixLabels(preempt bool) []string {
+	labels := []string{refinery.LabelHotfix}
+	if preempt {
+		labels = append(labels, refinery.LabelHotfixPreempt)
+	}
+	return labels
+}
+
+func runMQHotfix(cmd *cobra.Command, args []string) error {
+	rigName, idOrBranch := args[0], args[1]
+	if mqHotfixClear && mqHotfixPreempt {
+		return fmt.Errorf("cannot use --preempt with --clear")
+	}
+
+	mgr, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+	mr, err := mgr.FindMR(idOrBranch)
+	if err != nil {
+		if err == refinery.ErrMRNotFound {
+			return fmt.Errorf("merge request '%s' not found in rig '%s'", idOrBranch, rigName)
+		}
+		return fmt.Errorf("finding merge request: %w", err)
+	}
+
+	b := beads.New(r.BeadsPath())
+	if mqHotfixClear {
+		if err := b.Update(mr.ID, beads.UpdateOptions{RemoveLabels: hotfixLabels(true)}); err != nil {
+			return fmt.Errorf("updating %s: %w", mr.ID, err)
+		}
+		fmt.Printf("%s %s moved back to the normal lane\n", style.Bold.Render("✓"), mr.ID)
+		return nil
+	}
+
+	if err := b.Update(mr.ID, beads.UpdateOptions{AddLabels: hotfixLabels(mqHotfixPreempt)}); err != nil {
+		return fmt.Errorf("updating %s: %w", mr.ID, err)
+	}
+
+	msg := fmt.Sprintf("HOTFIX queued: %s branch=%s", mr.ID, mr.Branch)
+	if mqHotfixPreempt {
+		msg += " (preempt: abandon non-critical merge before push)"
+	}
+	nudgeRefinery(rigName, msg)
+
+	fmt.Printf("%s %s is in the hotfix lane\n", style.Bold.Render("🚑"), mr.ID)
+	fmt.Printf("  Branch: %s\n", mr.Branch)
+	if mqHotfixPreempt {
+		fmt.Printf("  %s\n", style.Dim.Render("Will preempt an in-progress merge of non-critical work"))
+	}
+	return nil
+}
@@ -19,6 +19,11 @@ import (
 func runMQList(cmd *cobra.Command, args []string) error {
 	rigName := args[0]
 
+	labelFilter, err := beads.ParseLabelFilter(mqListLabels)
+	if err != nil {
+		return err
+	}
+
 	_, r, _, err := getRefineryManager(rigName)
 	if err != nil {
 		return err
@@ -76,6 +81,7 @@ func runMQList(cmd *cobra.Command, args []string) error {
 
 	// Apply additional filters and calculate scores
 	now := time.Now()
+	scoreConfig := refinery.RigScoreConfig(r.Path)
 	type scoredIssue struct {
 		issue          *beads.Issue
 		fields         *beads.MRFields
@@ -85,7 +91,7 @@ func runMQList(cmd *cobra.Command, args []string) error {
 	}
 	var scored []scoredIssue
 
-	for _, issue := range issues {
+	for _, issue := range beads.FilterByLabels(issues, labelFilter) {
 		// Manual status filtering as workaround for bd list not respecting --status filter
 		if mqListReady {
 			// Ready view should only show open MRs
@@ -132,7 +138,7 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		branchMissing, branchVerifyErr := verifyBranch(mqListVerify, gitClient, fields)
 
 		// Calculate priority score
-		score := calculateMRScore(issue, fields, now)
+		score := calculateMRScore(issue, fields, now, scoreConfig)
 		scored = append(scored, scoredIssue{issue: issue, fields: fields, score: score, branchMissing: branchMissing, branchVerifyErr: branchVerifyErr})
 	}
 
@@ -246,7 +252,9 @@ func runMQList(cmd *cobra.Command, args []string) error {
 
 		// Format priority with color
 		priority := fmt.Sprintf("P%d", issue.Priority)
-		if issue.Priority <= 1 {
+		if beads.HasLabel(issue, refinery.LabelHotfix) {
+			priority = style.Error.Render("HOT")
+		} else if issue.Priority <= 1 {
 			priority = style.Error.Render(priority)
 		} else if issue.Priority == 2 {
 			priority = style.Warning.Render(priority)
@@ -355,7 +363,7 @@ func outputJSON(data interface{}) error {
 
 // calculateMRScore computes the priority score for an MR using the refinery scoring function.
 // Higher scores mean higher priority (process first).
-func calculateMRScore(issue *beads.Issue, fields *beads.MRFields, now time.Time) float64 {
+func calculateMRScore(issue *beads.Issue, fields *beads.MRFields, now time.Time, scoreConfig refinery.ScoreConfig) float64 {
 	// Parse MR creation time
 	mrCreatedAt, err := time.Parse(time.RFC3339, issue.CreatedAt)
 	if err != nil {
@@ -369,6 +377,8 @@ func calculateMRScore(issue *beads.Issue, fields *beads.MRFields, now time.Time)
 	input := refinery.ScoreInput{
 		Priority:    issue.Priority,
 		MRCreatedAt: mrCreatedAt,
+		Hotfix:      beads.HasLabel(issue, refinery.LabelHotfix),
+		Labels:      issue.Labels,
 		Now:         now,
 	}
 
@@ -384,7 +394,7 @@ func calculateMRScore(issue *beads.Issue, fields *beads.MRFields, now time.Time)
 		}
 	}
 
-	return refinery.ScoreMRWithDefaults(input)
+	return refinery.ScoreMR(input, scoreConfig)
 }
 
 // branchVerifier abstracts git branch existence checks for testability.
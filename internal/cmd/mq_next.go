@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -23,6 +24,7 @@ var mqNextCmd = &cobra.Command{
 	Long: `Show the next merge request to process based on priority score.
 
 The priority scoring function considers:
+  - Hotfix lane: MRs marked with 'gt mq hotfix' always come first
   - Convoy age: Older convoys get higher priority (starvation prevention)
   - Issue priority: P0 > P1 > P2 > P3 > P4
   - Retry count: MRs that fail repeatedly get deprioritized
@@ -91,11 +93,16 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 	}
 
 	now := time.Now()
+	scoreConfig := refinery.RigScoreConfig(r.Path)
 
 	// Sort based on strategy
 	if mqNextStrategy == "fifo" {
-		// FIFO: oldest first by creation time
+		// FIFO: oldest first by creation time, hotfixes still first
 		sort.Slice(ready, func(i, j int) bool {
+			hi, hj := beads.HasLabel(ready[i], refinery.LabelHotfix), beads.HasLabel(ready[j], refinery.LabelHotfix)
+			if hi != hj {
+				return hi
+			}
 			ti, _ := time.Parse(time.RFC3339, ready[i].CreatedAt)
 			tj, _ := time.Parse(time.RFC3339, ready[j].CreatedAt)
 			return ti.Before(tj)
@@ -109,7 +116,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 		scored := make([]scoredIssue, len(ready))
 		for i, issue := range ready {
 			fields := beads.ParseMRFields(issue)
-			score := calculateMRScore(issue, fields, now)
+			score := calculateMRScore(issue, fields, now, scoreConfig)
 			scored[i] = scoredIssue{issue: issue, score: score}
 		}
 
@@ -140,11 +147,14 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 	// Human-readable output
 	fmt.Printf("%s Next MR to process:\n\n", style.Bold.Render("🎯"))
 
-	score := calculateMRScore(next, fields, now)
+	score := calculateMRScore(next, fields, now, scoreConfig)
 
 	fmt.Printf("  ID:       %s\n", next.ID)
 	fmt.Printf("  Score:    %.1f\n", score)
 	fmt.Printf("  Priority: P%d\n", next.Priority)
+	if beads.HasLabel(next, refinery.LabelHotfix) {
+		fmt.Printf("  Lane:     %s\n", style.Error.Render("hotfix"))
+	}
 
 	if fields != nil {
 		if fields.Branch != "" {
@@ -76,6 +76,10 @@ func parseBranchName(branch string) branchInfo {
 }
 
 func runMqSubmit(cmd *cobra.Command, args []string) error {
+	if mqSubmitPreempt && !mqSubmitHotfix {
+		return fmt.Errorf("--preempt requires --hotfix")
+	}
+
 	// Find workspace
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -187,19 +191,15 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Get source issue for priority inheritance
-	var priority int
+	// Get source issue for priority and label inheritance
+	priority := 2 // Default if the issue isn't found
+	var labels []string
+	if sourceIssue, err := bd.Show(issueID); err == nil {
+		priority = sourceIssue.Priority
+		labels = beads.UserLabels(sourceIssue.Labels)
+	}
 	if mqSubmitPriority >= 0 {
 		priority = mqSubmitPriority
-	} else {
-		// Try to inherit from source issue
-		sourceIssue, err := bd.Show(issueID)
-		if err != nil {
-			// Issue not found, use default priority
-			priority = 2
-		} else {
-			priority = sourceIssue.Priority
-		}
 	}
 
 	// Build MR bead title and description
@@ -227,13 +227,28 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 			Priority:    priority,
 			Description: description,
 			Ephemeral:   true,
+			Labels:      labels,
 		})
 		if err != nil {
 			return fmt.Errorf("creating merge request bead: %w", err)
 		}
 
 		// Nudge refinery to pick up the new MR
-		nudgeRefinery(rigName, fmt.Sprintf("MR submitted: %s branch=%s", mrIssue.ID, branch))
+		if !mqSubmitHotfix {
+			nudgeRefinery(rigName, fmt.Sprintf("MR submitted: %s branch=%s", mrIssue.ID, branch))
+		}
+	}
+
+	// Hotfix lane applies to new and existing MRs alike
+	if mqSubmitHotfix {
+		if err := bd.Update(mrIssue.ID, beads.UpdateOptions{AddLabels: hotfixLabels(mqSubmitPreempt)}); err != nil {
+			return fmt.Errorf("marking %s as hotfix: %w", mrIssue.ID, err)
+		}
+		msg := fmt.Sprintf("HOTFIX submitted: %s branch=%s", mrIssue.ID, branch)
+		if mqSubmitPreempt {
+			msg += " (preempt: abandon non-critical merge before push)"
+		}
+		nudgeRefinery(rigName, msg)
 	}
 
 	// Success output
@@ -246,6 +261,13 @@ func runMqSubmit(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Worker: %s\n", worker)
 	}
 	fmt.Printf("  Priority: P%d\n", priority)
+	if mqSubmitHotfix {
+		lane := "hotfix"
+		if mqSubmitPreempt {
+			lane = "hotfix (preempting)"
+		}
+		fmt.Printf("  Lane: %s\n", style.Error.Render(lane))
+	}
 
 	// Auto-cleanup for polecats: if this is a polecat branch and cleanup not disabled,
 	// send lifecycle request and wait for termination
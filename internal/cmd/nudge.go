@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -358,6 +359,13 @@ func runNudge(cmd *cobra.Command, args []string) error {
 					return err
 				}
 				sessionName = mgr.SessionName(polecatName)
+				// A quarantined polecat's session is frozen: keys sent now
+				// would pile up and replay when it is released.
+				if townRoot != "" {
+					if reason := witness.QuarantineReason(townRoot, rigName, polecatName); reason != "" {
+						return fmt.Errorf("%s/%s is quarantined (%s): resolve it with 'gt witness quarantine release|nuke'", rigName, polecatName, reason)
+					}
+				}
 			}
 		}
 
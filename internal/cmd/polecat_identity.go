@@ -534,10 +534,7 @@ func runPolecatIdentityShow(cmd *cobra.Command, args []string) error {
 			if work.Type != "" {
 				typeStr = work.Type + ": "
 			}
-			title := work.Title
-			if len(title) > 40 {
-				title = title[:37] + "..."
-			}
+			title := style.Truncate(work.Title, 40)
 			fmt.Printf("  %-10s %s%s  %s\n", work.ID, typeStr, title, style.Dim.Render(work.Ago))
 		}
 	}
@@ -2,20 +2,25 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/activehours"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/degraded"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/smokegate"
+	"github.com/steveyegge/gastown/internal/squad"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -34,6 +39,11 @@ type SpawnedPolecatInfo struct {
 	// Internal fields for deferred session start
 	account string
 	agent   string
+
+	// saga records a compensation for each spawn step that completed
+	// (polecat provisioning, Dolt branch, tmux session) so a failure in a
+	// later step can undo the earlier ones. See Rollback.
+	saga *polecat.Saga
 }
 
 // AgentID returns the agent identifier (e.g., "gastown/polecats/Toast")
@@ -41,6 +51,17 @@ func (s *SpawnedPolecatInfo) AgentID() string {
 	return fmt.Sprintf("%s/polecats/%s", s.RigName, s.PolecatName)
 }
 
+// Rollback undoes every spawn step that has completed, newest first:
+// tmux session, Dolt branch, then the polecat itself (worktree and agent bead).
+// It is best-effort and idempotent; errors from individual compensations are
+// joined and returned for reporting.
+func (s *SpawnedPolecatInfo) Rollback() error {
+	if s.saga == nil {
+		return nil
+	}
+	return s.saga.Rollback()
+}
+
 // SessionStarted returns true if the tmux session has been started.
 func (s *SpawnedPolecatInfo) SessionStarted() bool {
 	return s.Pane != ""
@@ -54,6 +75,9 @@ type SlingSpawnOptions struct {
 	HookBead   string // Bead ID to set as hook_bead at spawn time (atomic assignment)
 	Agent      string // Agent override for this spawn (e.g., "gemini", "codex", "claude-haiku")
 	BaseBranch string // Override base branch for polecat worktree (e.g., "develop", "release/v2")
+
+	IgnoreActiveHours bool   // Spawn even outside the town's active hours
+	Squad             string // Squad the polecat joins (pause and budget are checked first)
 }
 
 // SpawnPolecatForSling creates a fresh polecat and optionally starts its session.
@@ -66,6 +90,21 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	// Refuse before allocating a worktree: the session could not start anyway.
+	if degraded.IsActive(townRoot) {
+		return nil, fmt.Errorf("%w: spawns are paused until it recovers (see 'gt degraded status')", degraded.ErrDegraded)
+	}
+	if !opts.IgnoreActiveHours {
+		if err := activehours.Check(townRoot); err != nil {
+			return nil, fmt.Errorf("%w (see 'gt active-hours', or pass --ignore-active-hours)", err)
+		}
+	}
+	if opts.Squad != "" {
+		if err := checkSquadSpawn(townRoot, opts.Squad); err != nil {
+			return nil, err
+		}
+	}
+
 	// Load rig config
 	rigsConfigPath := filepath.Join(townRoot, "mayor", "rigs.json")
 	rigsConfig, err := config.LoadRigsConfig(rigsConfigPath)
@@ -79,6 +118,9 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	if err != nil {
 		return nil, fmt.Errorf("rig '%s' not found", rigName)
 	}
+	if smokegate.IsRequired(r.Path) {
+		return nil, fmt.Errorf("%w: spawns on %s are paused (see 'gt witness smoke %s')", smokegate.ErrGated, rigName, rigName)
+	}
 
 	// Get polecat manager (with tmux for session-aware allocation)
 	polecatGit := git.NewGit(r.Path)
@@ -138,6 +180,15 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		BaseBranch: baseBranch,
 	}
 
+	// Spawn saga: each provisioning step below records how to undo itself, so a
+	// failure in a later step (worktree verification here, or Dolt branch and
+	// session start in the caller) rolls back instead of leaving a
+	// half-provisioned polecat for the Witness to garbage-collect.
+	saga := polecat.NewSaga("spawn " + rigName + "/" + polecatName)
+	removePolecat := func() error {
+		return polecatMgr.Remove(polecatName, true) // force=true to clean up partial state
+	}
+
 	if err == nil {
 		// Stale state: polecat exists despite fresh name allocation - repair it
 		// Check for uncommitted work first
@@ -163,13 +214,21 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		}
 
 		fmt.Printf("Repairing stale polecat %s with fresh worktree...\n", polecatName)
-		if _, err = polecatMgr.RepairWorktreeWithOptions(polecatName, opts.Force, addOpts); err != nil {
+		if err = saga.Step("polecat", func() error {
+			_, repairErr := polecatMgr.RepairWorktreeWithOptions(polecatName, opts.Force, addOpts)
+			return repairErr
+		}, removePolecat); err != nil {
 			return nil, fmt.Errorf("repairing stale polecat: %w", err)
 		}
 	} else if err == polecat.ErrPolecatNotFound {
 		// Create new polecat
 		fmt.Printf("Creating polecat %s...\n", polecatName)
-		if _, err = polecatMgr.AddWithOptions(polecatName, addOpts); err != nil {
+		// AddWithOptions rolls back its own partial work on failure, so the
+		// compensation only covers a polecat that was fully created.
+		if err = saga.Step("polecat", func() error {
+			_, addErr := polecatMgr.AddWithOptions(polecatName, addOpts)
+			return addErr
+		}, removePolecat); err != nil {
 			return nil, fmt.Errorf("creating polecat: %w", err)
 		}
 	} else {
@@ -179,6 +238,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	// Get polecat object for path info
 	polecatObj, err := polecatMgr.Get(polecatName)
 	if err != nil {
+		_ = saga.Rollback()
 		return nil, fmt.Errorf("getting polecat after creation: %w", err)
 	}
 
@@ -186,11 +246,22 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	// The identity bead may exist but worktree creation can fail silently
 	if err := verifyWorktreeExists(polecatObj.ClonePath); err != nil {
 		// Clean up the partial state before returning error
-		_ = polecatMgr.Remove(polecatName, true) // force=true to clean up partial state
+		_ = saga.Rollback()
 		return nil, fmt.Errorf("worktree verification failed for %s: %w\nHint: try 'gt polecat nuke %s/%s --force' to clean up",
 			polecatName, err, rigName, polecatName)
 	}
 
+	// Join the squad, or leave the one a previous polecat of this name was in.
+	agentID := fmt.Sprintf("%s/polecats/%s", rigName, polecatName)
+	if err := saga.Step("squad", func() error {
+		return squad.Assign(townRoot, agentID, opts.Squad)
+	}, func() error {
+		return squad.Assign(townRoot, agentID, "")
+	}); err != nil {
+		_ = saga.Rollback()
+		return nil, fmt.Errorf("assigning squad: %w", err)
+	}
+
 	// Branch-per-polecat: generate name but DEFER creation to after sling writes.
 	// DOLT_BRANCH forks from HEAD, but BD_DOLT_AUTO_COMMIT=off means writes
 	// stay in working set. Caller must call CreateDoltBranch() after all writes
@@ -222,6 +293,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		BaseBranch:  effectiveBranch,
 		account:     opts.Account,
 		agent:       opts.Agent,
+		saga:        saga,
 	}, nil
 }
 
@@ -276,9 +348,22 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 		}
 		startOpts.Command = cmd
 	}
+	killSession := func() error {
+		if running, _ := t.HasSession(s.SessionName); !running {
+			return nil
+		}
+		return t.KillSessionWithProcesses(s.SessionName)
+	}
 	if err := polecatSessMgr.Start(s.PolecatName, startOpts); err != nil {
+		// Start can fail after the tmux session was created (e.g. the agent
+		// dies during startup), so rollback must still kill it. A session that
+		// was already running before this spawn is never ours to kill.
+		if !errors.Is(err, polecat.ErrSessionRunning) {
+			s.recordStep("session", killSession)
+		}
 		return "", fmt.Errorf("starting session: %w", err)
 	}
+	s.recordStep("session", killSession)
 
 	// Wait for runtime to be fully ready before returning.
 	spawnTownRoot := filepath.Dir(r.Path)
@@ -309,7 +394,8 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	// Kill the dead session to prevent "session already running" on next attempt (gt-jn40ft).
 	pane, err := getSessionPane(s.SessionName)
 	if err != nil {
-		// Session likely died — clean up the tmux session so it doesn't block re-sling
+		// Session likely died — clean up the tmux session so it doesn't block re-sling.
+		// The rest of the spawn is rolled back by the caller via Rollback.
 		_ = t.KillSession(s.SessionName)
 		return "", fmt.Errorf("getting pane for %s (session likely died during startup): %w", s.SessionName, err)
 	}
@@ -342,10 +428,23 @@ func (s *SpawnedPolecatInfo) CreateDoltBranch() error {
 	if err := doltserver.CreatePolecatBranch(townRoot, s.RigName, s.DoltBranch); err != nil {
 		return fmt.Errorf("creating Dolt branch %s: %w", s.DoltBranch, err)
 	}
+	s.recordStep("dolt branch", func() error {
+		doltserver.DeletePolecatBranch(townRoot, s.RigName, s.DoltBranch)
+		return nil
+	})
 	fmt.Printf("%s Dolt branch: %s\n", style.Bold.Render("✓"), s.DoltBranch)
 	return nil
 }
 
+// recordStep registers a compensation with the spawn saga, creating the saga
+// if the info was built without one.
+func (s *SpawnedPolecatInfo) recordStep(name string, compensate func() error) {
+	if s.saga == nil {
+		s.saga = polecat.NewSaga("spawn " + s.AgentID())
+	}
+	s.saga.Record(name, compensate)
+}
+
 // IsRigName checks if a target string is a rig name (not a role or path).
 // Returns the rig name and true if it's a valid rig.
 func IsRigName(target string) (string, bool) {
@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// postmortemTimelineMax caps the events copied into a postmortem; the rest
+// are summarized in one line so the bead stays readable.
+const postmortemTimelineMax = 200
+
+// Postmortem command flags
+var (
+	postmortemSince     string
+	postmortemFrom      string
+	postmortemTo        string
+	postmortemSummary   string
+	postmortemAssignee  string
+	postmortemRootCause string
+	postmortemForce     bool
+	postmortemAll       bool
+	postmortemJSON      bool
+)
+
+var postmortemCmd = &cobra.Command{
+	Use:     "postmortem",
+	GroupID: GroupDiag,
+	Short:   "Run structured postmortems on town incidents",
+	RunE:    requireSubcommand,
+	Long: `Run structured postmortems on town incidents.
+
+A postmortem is a bead (gt:postmortem) linked to an incident window in the
+town event log. Starting one copies the window's events into a timeline so
+the write-up begins from what actually happened. Action items are child
+beads of the postmortem; it can only be finished once they are all closed,
+so recurring failures get fixed rather than just discussed.
+
+WORKFLOW:
+  1. gt postmortem start "Mass polecat death" --since 2h
+  2. Review the timeline: gt postmortem show <id>
+  3. Add action items: gt postmortem action <id> "Raise nofile limit" --assignee mayor/
+  4. Close action items as they are done (bd close <action-id>)
+  5. gt postmortem finish <id> --root-cause "fd exhaustion after dolt upgrade"
+
+Examples:
+  gt postmortem start "Refinery stalled" --from 2026-10-01T09:00:00Z --to 2026-10-01T11:30:00Z
+  gt postmortem list
+  gt postmortem finish hq-abc123 --force    # Finish with open action items`,
+}
+
+var postmortemStartCmd = &cobra.Command{
+	Use:   "start <title>",
+	Short: "Open a postmortem with a timeline from the event log",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPostmortemStart,
+}
+
+var postmortemShowCmd = &cobra.Command{
+	Use:   "show <postmortem-id>",
+	Short: "Show a postmortem, its timeline, and action items",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPostmortemShow,
+}
+
+var postmortemActionCmd = &cobra.Command{
+	Use:   "action <postmortem-id> <title>",
+	Short: "Add an action item to a postmortem",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runPostmortemAction,
+}
+
+var postmortemFinishCmd = &cobra.Command{
+	Use:   "finish <postmortem-id>",
+	Short: "Record the root cause and close the postmortem",
+	Long: `Record the root cause and close the postmortem.
+
+Refuses while any action item is still open; pass --force to finish anyway
+(the open action items stay open and keep their assignees).`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPostmortemFinish,
+}
+
+var postmortemListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List postmortems and their action item progress",
+	RunE:  runPostmortemList,
+}
+
+func init() {
+	postmortemStartCmd.Flags().StringVar(&postmortemSince, "since", "1h", "Incident window: from this long ago until now (e.g., 30m, 2h, 1d)")
+	postmortemStartCmd.Flags().StringVar(&postmortemFrom, "from", "", "Incident start (RFC3339); overrides --since")
+	postmortemStartCmd.Flags().StringVar(&postmortemTo, "to", "", "Incident end (RFC3339, default: now)")
+	postmortemStartCmd.Flags().StringVar(&postmortemSummary, "summary", "", "One-line description of what happened")
+
+	postmortemActionCmd.Flags().StringVar(&postmortemAssignee, "assignee", "", "Agent address that owns the action item")
+
+	postmortemFinishCmd.Flags().StringVar(&postmortemRootCause, "root-cause", "", "Root cause of the incident")
+	postmortemFinishCmd.Flags().BoolVar(&postmortemForce, "force", false, "Finish even if action items are still open")
+
+	postmortemShowCmd.Flags().BoolVar(&postmortemJSON, "json", false, "Output as JSON")
+	postmortemListCmd.Flags().BoolVar(&postmortemAll, "all", false, "Include finished postmortems")
+	postmortemListCmd.Flags().BoolVar(&postmortemJSON, "json", false, "Output as JSON")
+
+	postmortemCmd.AddCommand(postmortemStartCmd)
+	postmortemCmd.AddCommand(postmortemShowCmd)
+	postmortemCmd.AddCommand(postmortemActionCmd)
+	postmortemCmd.AddCommand(postmortemFinishCmd)
+	postmortemCmd.AddCommand(postmortemListCmd)
+	rootCmd.AddCommand(postmortemCmd)
+}
+
+// postmortemWindow resolves the incident window from --from/--to/--since.
+func postmortemWindow(now time.Time) (start, end time.Time, err error) {
+	end = now
+	if postmortemTo != "" {
+		if end, err = time.Parse(time.RFC3339, postmortemTo); err != nil {
+			return start, end, fmt.Errorf("invalid --to: %w", err)
+		}
+	}
+	if postmortemFrom != "" {
+		if start, err = time.Parse(time.RFC3339, postmortemFrom); err != nil {
+			return start, end, fmt.Errorf("invalid --from: %w", err)
+		}
+	} else {
+		d, err := parseDuration(postmortemSince)
+		if err != nil {
+			return start, end, fmt.Errorf("invalid --since: %w", err)
+		}
+		start = end.Add(-d)
+	}
+	if !start.Before(end) {
+		return start, end, fmt.Errorf("incident start %s is not before end %s", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+	return start, end, nil
+}
+
+func runPostmortemStart(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	now := time.Now()
+	start, end, err := postmortemWindow(now)
+	if err != nil {
+		return err
+	}
+
+	evts, err := readTownEvents(townRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not read events: %v\n", err)
+	}
+	timeline := buildPostmortemTimeline(evts, start, end)
+
+	fields := &beads.PostmortemFields{
+		IncidentStart: start.UTC().Format(time.RFC3339),
+		IncidentEnd:   end.UTC().Format(time.RFC3339),
+		Summary:       postmortemSummary,
+		StartedBy:     detectSender(),
+		StartedAt:     now.UTC().Format(time.RFC3339),
+	}
+
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	issue, err := bd.CreatePostmortemBead(args[0], fields, timeline)
+	if err != nil {
+		return fmt.Errorf("creating postmortem: %w", err)
+	}
+
+	fmt.Printf("%s Postmortem opened: %s\n", style.Bold.Render("✓"), issue.ID)
+	fmt.Printf("  Window: %s → %s\n", fields.IncidentStart, fields.IncidentEnd)
+	fmt.Printf("  Timeline: %d event(s)\n", len(timeline))
+	fmt.Printf("Add action items with: %s\n", style.Dim.Render(fmt.Sprintf("gt postmortem action %s \"<title>\"", issue.ID)))
+	return nil
+}
+
+// buildPostmortemTimeline formats the events inside [start, end] oldest
+// first, one line each, capped at postmortemTimelineMax.
+func buildPostmortemTimeline(evts []events.Event, start, end time.Time) []string {
+	type stamped struct {
+		ts time.Time
+		e  events.Event
+	}
+	var in []stamped
+	for _, e := range evts {
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil || ts.Before(start) || ts.After(end) {
+			continue
+		}
+		in = append(in, stamped{ts, e})
+	}
+	sort.SliceStable(in, func(i, j int) bool { return in[i].ts.Before(in[j].ts) })
+
+	var lines []string
+	for i, s := range in {
+		if i == postmortemTimelineMax {
+			lines = append(lines, fmt.Sprintf("- ... %d more event(s) until %s", len(in)-i, end.UTC().Format(time.RFC3339)))
+			break
+		}
+		line := fmt.Sprintf("- %s %s %s", s.ts.UTC().Format("2006-01-02 15:04:05"), s.e.Type, s.e.Actor)
+		if detail := timelineDetail(s.e.Payload); detail != "" {
+			line += ": " + detail
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// timelineDetail renders an event payload's scalar values as key=value.
+func timelineDetail(payload map[string]interface{}) string {
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		switch v := payload[k].(type) {
+		case string:
+			if v != "" {
+				parts = append(parts, fmt.Sprintf("%s=%s", k, strings.ReplaceAll(v, "\n", " ")))
+			}
+		case float64, int, bool:
+			parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func runPostmortemShow(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	issue, fields, timeline, err := bd.GetPostmortemBead(args[0])
+	if err != nil {
+		return err
+	}
+	actions, err := bd.ListPostmortemActions(issue.ID)
+	if err != nil {
+		return fmt.Errorf("listing action items: %w", err)
+	}
+
+	if postmortemJSON {
+		out, _ := json.MarshalIndent(map[string]interface{}{
+			"postmortem": issue,
+			"timeline":   timeline,
+			"actions":    actions,
+		}, "", "  ")
+		fmt.Println(string(out))
+		return nil
+	}
+
+	fmt.Printf("%s %s [%s]\n", style.Bold.Render(issue.ID), issue.Title, issue.Status)
+	fmt.Printf("  Window: %s → %s\n", fields.IncidentStart, fields.IncidentEnd)
+	if fields.Summary != "" {
+		fmt.Printf("  Summary: %s\n", fields.Summary)
+	}
+	fmt.Printf("  Started by %s at %s\n", fields.StartedBy, fields.StartedAt)
+	if fields.RootCause != "" {
+		fmt.Printf("  Root cause: %s\n", fields.RootCause)
+	}
+	if fields.FinishedBy != "" {
+		fmt.Printf("  Finished by %s at %s\n", fields.FinishedBy, fields.FinishedAt)
+	}
+
+	fmt.Printf("\nTimeline (%d):\n", len(timeline))
+	for _, line := range timeline {
+		fmt.Printf("  %s\n", line)
+	}
+
+	open := beads.OpenPostmortemActions(actions)
+	fmt.Printf("\nAction items (%d/%d done):\n", len(actions)-len(open), len(actions))
+	if len(actions) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("none yet"))
+	}
+	for _, a := range actions {
+		mark := style.Warning.Render("○")
+		if a.Status == "closed" {
+			mark = style.Success.Render("✓")
+		}
+		owner := ""
+		if a.Assignee != "" {
+			owner = " → " + a.Assignee
+		}
+		fmt.Printf("  %s %s %s%s\n", mark, a.ID, a.Title, owner)
+	}
+	return nil
+}
+
+func runPostmortemAction(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	action, err := bd.AddPostmortemAction(args[0], args[1], postmortemAssignee)
+	if err != nil {
+		return fmt.Errorf("adding action item: %w", err)
+	}
+	fmt.Printf("%s Action item %s added to %s\n", style.Bold.Render("✓"), action.ID, args[0])
+	if action.Assignee != "" {
+		fmt.Printf("  Assigned to: %s\n", action.Assignee)
+	}
+	return nil
+}
+
+func runPostmortemFinish(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	if err := bd.FinishPostmortem(args[0], detectSender(), postmortemRootCause, postmortemForce); err != nil {
+		return fmt.Errorf("finishing postmortem: %w", err)
+	}
+	fmt.Printf("%s Postmortem finished: %s\n", style.Bold.Render("✓"), args[0])
+	return nil
+}
+
+func runPostmortemList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	issues, err := bd.ListPostmortems(postmortemAll)
+	if err != nil {
+		return fmt.Errorf("listing postmortems: %w", err)
+	}
+
+	if postmortemJSON {
+		out, _ := json.MarshalIndent(issues, "", "  ")
+		fmt.Println(string(out))
+		return nil
+	}
+	if len(issues) == 0 {
+		fmt.Println("No postmortems found")
+		return nil
+	}
+
+	fmt.Printf("Postmortems (%d):\n\n", len(issues))
+	for _, issue := range issues {
+		progress := "?"
+		if actions, err := bd.ListPostmortemActions(issue.ID); err == nil {
+			progress = fmt.Sprintf("%d/%d", len(actions)-len(beads.OpenPostmortemActions(actions)), len(actions))
+		}
+		fields, _ := beads.ParsePostmortemFields(issue.Description)
+		fmt.Printf("  %s [%s] %s\n", issue.ID, issue.Status, issue.Title)
+		fmt.Printf("     Window: %s → %s | Actions done: %s\n\n", fields.IncidentStart, fields.IncidentEnd, progress)
+	}
+	return nil
+}
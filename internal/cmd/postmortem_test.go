@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestBuildPostmortemTimeline(t *testing.T) {
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	evts := []events.Event{
+		{Timestamp: "2026-10-01T09:30:00Z", Type: events.TypeSessionDeath, Actor: "daemon",
+			Payload: events.SessionDeathPayload("gt-toast", "gastown/polecats/toast", "zombie\ncleanup", "daemon")},
+		{Timestamp: "2026-10-01T08:59:59Z", Type: events.TypeSling, Actor: "mayor"}, // before window
+		{Timestamp: "2026-10-01T09:05:00Z", Type: events.TypeSpawn, Actor: "mayor",
+			Payload: events.SpawnPayload("gastown", "toast")},
+		{Timestamp: "2026-10-01T10:00:01Z", Type: events.TypeDone, Actor: "toast"}, // after window
+		{Timestamp: "garbage", Type: events.TypeHook, Actor: "toast"},
+	}
+
+	lines := buildPostmortemTimeline(evts, start, end)
+	if len(lines) != 2 {
+		t.Fatalf("lines = %q", lines)
+	}
+	if lines[0] != "- 2026-10-01 09:05:00 spawn mayor: polecat=toast rig=gastown" {
+		t.Errorf("first line = %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "reason=zombie cleanup") {
+		t.Errorf("newlines in payloads should be flattened: %q", lines[1])
+	}
+}
+
+func TestBuildPostmortemTimeline_Cap(t *testing.T) {
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	var evts []events.Event
+	for i := 0; i < postmortemTimelineMax+5; i++ {
+		evts = append(evts, events.Event{Timestamp: start.Add(time.Duration(i) * time.Second).Format(time.RFC3339), Type: events.TypeNudge, Actor: "witness"})
+	}
+	lines := buildPostmortemTimeline(evts, start, start.Add(time.Hour))
+	if len(lines) != postmortemTimelineMax+1 || !strings.Contains(lines[len(lines)-1], "... 5 more event(s)") {
+		t.Errorf("got %d lines, last %q", len(lines), lines[len(lines)-1])
+	}
+}
+
+func TestPostmortemWindow(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	defer func() { postmortemSince, postmortemFrom, postmortemTo = "1h", "", "" }()
+
+	postmortemSince, postmortemFrom, postmortemTo = "2h", "", ""
+	start, end, err := postmortemWindow(now)
+	if err != nil || !end.Equal(now) || !start.Equal(now.Add(-2*time.Hour)) {
+		t.Errorf("--since: %v %v %v", start, end, err)
+	}
+
+	postmortemFrom, postmortemTo = "2026-10-01T11:00:00Z", "2026-10-01T10:00:00Z"
+	if _, _, err := postmortemWindow(now); err == nil {
+		t.Error("expected error when --from is after --to")
+	}
+}
@@ -148,6 +148,9 @@ func runPrime(cmd *cobra.Command, args []string) error {
 	if err := outputRoleContext(ctx); err != nil {
 		return err
 	}
+	if !primeDryRun {
+		recordPromptVersion(ctx)
+	}
 
 	hasSlungWork := checkSlungWork(ctx)
 	explain(hasSlungWork, "Autonomous mode: hooked/in-progress work detected")
@@ -11,6 +11,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/checkpoint"
 	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/promptpack"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -20,8 +21,14 @@ import (
 
 // outputPrimeContext outputs the role-specific context using templates or fallback.
 func outputPrimeContext(ctx RoleContext) error {
-	// Try to use templates first
-	tmpl, err := templates.New()
+	// Try to use templates first, from the agent's prompt version
+	version, source := resolvePromptVersion(ctx)
+	explain(version != promptpack.Builtin, fmt.Sprintf("Prompt version: %s (from %s)", version, source))
+	tmpl, err := promptpack.Templates(ctx.TownRoot, version)
+	if err != nil && version != promptpack.Builtin {
+		fmt.Fprintf(os.Stderr, "warning: prompt version %s: %v; using builtin\n", version, err)
+		tmpl, err = templates.New()
+	}
 	if err != nil {
 		// Fall back to hardcoded output if templates fail
 		return outputPrimeContextFallback(ctx)
@@ -84,6 +91,30 @@ func outputPrimeContext(ctx RoleContext) error {
 	return nil
 }
 
+// resolvePromptVersion returns the prompt version the agent is primed with.
+func resolvePromptVersion(ctx RoleContext) (version, source string) {
+	if ctx.TownRoot == "" {
+		return promptpack.Builtin, promptpack.SourceDefault
+	}
+	return promptpack.Resolve(ctx.TownRoot, ctx.Rig, getAgentIdentity(ctx))
+}
+
+// recordPromptVersion records the prompt version this session was primed
+// with, for gt prompt status and rollouts.
+func recordPromptVersion(ctx RoleContext) {
+	actor := getAgentIdentity(ctx)
+	if actor == "" || ctx.TownRoot == "" {
+		return
+	}
+	version, source := resolvePromptVersion(ctx)
+	_ = promptpack.RecordSession(ctx.TownRoot, promptpack.Session{
+		Agent:     actor,
+		Version:   version,
+		Source:    source,
+		SessionID: resolveSessionIDForPrime(actor),
+	})
+}
+
 func outputPrimeContextFallback(ctx RoleContext) error {
 	switch ctx.Role {
 	case RoleMayor:
@@ -665,11 +696,12 @@ func outputDeaconPausedMessage(state *deacon.PauseState) {
 	fmt.Println()
 	fmt.Println("**DO NOT:**")
 	fmt.Println("- Create patrol molecules")
-	fmt.Println("- Run heartbeats")
+	fmt.Println("- Trigger pending spawns or nudge other agents")
 	fmt.Println("- Check agent health")
 	fmt.Println("- Take any autonomous actions")
 	fmt.Println()
-	fmt.Println("You may respond to direct human questions.")
+	fmt.Println("You may respond to direct human questions, and `" + cli.Name() + " deacon heartbeat`")
+	fmt.Println("still records a heartbeat so the daemon knows you're alive.")
 }
 
 // explain outputs an explanatory message if --explain mode is enabled.
@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/promptpack"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	promptStatusJSON bool
+	promptStatusAll  bool
+)
+
+var promptCmd = &cobra.Command{
+	Use:     "prompt",
+	GroupID: GroupConfig,
+	Short:   "Manage prompt versions (role priming packs) and roll them out",
+	Long: `Manage the prompt version agents are primed with.
+
+A prompt version is "builtin" (the role templates compiled into gt) or a
+prompt pack: a directory <town>/prompts/<version>/ containing
+roles/<role>.md.tmpl files (and optionally messages/*.md.tmpl) that
+override the built-in templates of the same name.
+
+An agent's version is resolved each time it is primed: a rig pin wins,
+then a staged rollout, then the town default, then builtin. gt prime
+records the version each session started with.
+
+Examples:
+  gt prompt                         # Which version each running agent has
+  gt prompt list                    # Available versions
+  gt prompt pin gastown v2          # Keep gastown's agents on v2
+  gt prompt rollout v3 --batch 2    # Restart agents onto v3 gradually`,
+	RunE: runPromptStatus,
+}
+
+var promptStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the prompt version each agent session was primed with",
+	Long: `Show the prompt version each running agent session was primed with, and
+the version it would get if restarted now. Agents whose two versions differ
+are marked as drifted.`,
+	Args: cobra.NoArgs,
+	RunE: runPromptStatus,
+}
+
+var promptListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available prompt versions",
+	Args:  cobra.NoArgs,
+	RunE:  runPromptList,
+}
+
+var promptSetCmd = &cobra.Command{
+	Use:   "set <version>",
+	Short: "Set the town's default prompt version (no restarts)",
+	Long: `Set the prompt version agents are primed with by default.
+
+Running sessions keep their version until they restart. To move running
+agents over gradually while watching for failures, use 'gt prompt rollout'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPromptSet,
+}
+
+var promptPinCmd = &cobra.Command{
+	Use:   "pin <rig> <version>",
+	Short: "Pin a rig's agents to a prompt version",
+	Long: `Pin a rig's agents to a prompt version, overriding the town default and
+any staged rollout. Rollouts skip agents of pinned rigs. The pin applies as
+each agent restarts.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runPromptPin,
+}
+
+var promptUnpinCmd = &cobra.Command{
+	Use:   "unpin <rig>",
+	Short: "Remove a rig's prompt version pin",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPromptUnpin,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{promptCmd, promptStatusCmd} {
+		c.Flags().BoolVar(&promptStatusJSON, "json", false, "Output as JSON")
+		c.Flags().BoolVar(&promptStatusAll, "all", false, "Include agents without a running session")
+	}
+	promptCmd.AddCommand(promptStatusCmd)
+	promptCmd.AddCommand(promptListCmd)
+	promptCmd.AddCommand(promptSetCmd)
+	promptCmd.AddCommand(promptPinCmd)
+	promptCmd.AddCommand(promptUnpinCmd)
+	rootCmd.AddCommand(promptCmd)
+}
+
+// promptAgentStatus is one agent's row in gt prompt status.
+type promptAgentStatus struct {
+	promptpack.Session
+	Rig        string `json:"rig,omitempty"`
+	Running    bool   `json:"running"`
+	Configured string `json:"configured"`
+	ConfigFrom string `json:"configured_from"`
+	Drifted    bool   `json:"drifted"`
+}
+
+func runPromptStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	sessions, err := promptpack.LoadSessions(townRoot)
+	if err != nil {
+		return err
+	}
+	running := runningAgentIdentities(tmux.NewTmux())
+
+	var rows []promptAgentStatus
+	for agent, s := range sessions {
+		_, isRunning := running[agent]
+		if !isRunning && !promptStatusAll {
+			continue
+		}
+		row := promptAgentStatus{Session: s, Running: isRunning}
+		if id, err := session.ParseAddress(agent); err == nil {
+			row.Rig = id.Rig
+		}
+		row.Configured, row.ConfigFrom = promptpack.Resolve(townRoot, row.Rig, agent)
+		row.Drifted = row.Configured != s.Version
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Agent < rows[j].Agent })
+	rollout, _ := promptpack.LoadRollout(townRoot)
+
+	if promptStatusJSON {
+		out := struct {
+			Agents  []promptAgentStatus `json:"agents"`
+			Rollout *promptpack.Rollout `json:"rollout,omitempty"`
+		}{rows, rollout}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(rows) == 0 {
+		fmt.Printf("%s No primed agent sessions recorded\n", style.Dim.Render("○"))
+	} else {
+		table := style.NewTable(
+			style.Column{Name: "AGENT", Width: 28},
+			style.Column{Name: "PRIMED WITH", Width: 14},
+			style.Column{Name: "CONFIGURED", Width: 22},
+			style.Column{Name: "PRIMED AT", Width: 17},
+		)
+		drifted := 0
+		for _, r := range rows {
+			configured := fmt.Sprintf("%s (%s)", r.Configured, r.ConfigFrom)
+			if r.Drifted {
+				configured = style.Warning.Render(configured)
+				drifted++
+			}
+			table.AddRow(r.Agent, r.Version, configured, r.PrimedAt.Local().Format("2006-01-02 15:04"))
+		}
+		fmt.Print(table.Render())
+		if drifted > 0 {
+			fmt.Printf("\n%s %d agent(s) would get a different version on restart\n", style.Warning.Render("⚠"), drifted)
+		}
+	}
+	if rollout != nil && rollout.Status == promptpack.RolloutRunning {
+		fmt.Printf("\nRollout %s → %s in progress: %d agent(s) moved, %d failed\n",
+			rollout.From, rollout.To, len(rollout.Agents), len(rollout.Failed))
+	}
+	return nil
+}
+
+func runPromptList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	versions, err := promptpack.List(townRoot)
+	if err != nil {
+		return err
+	}
+	townDefault, _ := promptpack.Resolve(townRoot, "", "")
+	sessions, _ := promptpack.LoadSessions(townRoot)
+	counts := make(map[string]int)
+	for _, s := range sessions {
+		counts[s.Version]++
+	}
+	for _, v := range versions {
+		marker := " "
+		if v == townDefault {
+			marker = style.Bold.Render("*")
+		}
+		fmt.Printf("%s %-20s %s\n", marker, v, style.Dim.Render(fmt.Sprintf("%d session(s) primed", counts[v])))
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("* town default. Prompt packs live in "+promptpack.Dir(townRoot)))
+	return nil
+}
+
+func runPromptSet(cmd *cobra.Command, args []string) error {
+	version := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if err := promptpack.Validate(townRoot, version); err != nil {
+		return err
+	}
+	if r, _ := promptpack.LoadRollout(townRoot); r != nil && r.Status == promptpack.RolloutRunning {
+		return fmt.Errorf("a rollout to %s is in progress; finish it or run 'gt prompt rollout --abort'", r.To)
+	}
+	if err := setTownPromptVersion(townRoot, version); err != nil {
+		return err
+	}
+	fmt.Printf("%s Town prompt version set to %s\n", style.Bold.Render("✓"), version)
+	fmt.Printf("  %s\n", style.Dim.Render("Agents pick it up as they restart; 'gt prompt rollout' moves them gradually"))
+	return nil
+}
+
+func runPromptPin(cmd *cobra.Command, args []string) error {
+	rigName, version := args[0], args[1]
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	if err := promptpack.Validate(townRoot, version); err != nil {
+		return err
+	}
+	if err := updateRigPromptPin(r.Path, version); err != nil {
+		return err
+	}
+	fmt.Printf("%s Pinned %s to prompt version %s\n", style.Bold.Render("✓"), rigName, version)
+	fmt.Printf("  %s\n", style.Dim.Render("Applies as the rig's agents restart (gt rig restart "+rigName+")"))
+	return nil
+}
+
+func runPromptUnpin(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	if err := updateRigPromptPin(r.Path, ""); err != nil {
+		return err
+	}
+	fmt.Printf("%s Unpinned %s's prompt version\n", style.Bold.Render("✓"), rigName)
+	return nil
+}
+
+// setTownPromptVersion saves the town default prompt version.
+func setTownPromptVersion(townRoot, version string) error {
+	path := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(path)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	settings.PromptVersion = version
+	if version == promptpack.Builtin {
+		settings.PromptVersion = ""
+	}
+	if err := config.SaveTownSettings(path, settings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+	return nil
+}
+
+// updateRigPromptPin sets (or, with "", clears) a rig's prompt pin.
+func updateRigPromptPin(rigPath, version string) error {
+	path := config.RigSettingsPath(rigPath)
+	settings, err := config.LoadRigSettings(path)
+	if errors.Is(err, config.ErrNotFound) {
+		settings = config.NewRigSettings()
+	} else if err != nil {
+		return fmt.Errorf("loading rig settings: %w", err)
+	}
+	settings.PromptVersion = version
+	if err := config.SaveRigSettings(path, settings); err != nil {
+		return fmt.Errorf("saving rig settings: %w", err)
+	}
+	return nil
+}
+
+// runningAgentIdentities maps the address of every agent with a running
+// tmux session to its identity.
+func runningAgentIdentities(t *tmux.Tmux) map[string]*session.AgentIdentity {
+	running := make(map[string]*session.AgentIdentity)
+	if !t.IsAvailable() {
+		return running
+	}
+	names, err := t.ListSessions()
+	if err != nil {
+		return running
+	}
+	for _, name := range names {
+		id, err := session.ParseSessionName(name)
+		if err != nil || id.Name == "boot" || id.Role == session.RoleOverseer {
+			continue
+		}
+		running[id.Address()] = id
+	}
+	return running
+}
@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cli"
+	"github.com/steveyegge/gastown/internal/promptpack"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	promptRolloutBatch      int
+	promptRolloutSoak       time.Duration
+	promptRolloutMaxFailure float64
+	promptRolloutDryRun     bool
+	promptRolloutAbort      bool
+	promptRolloutNoRollback bool
+)
+
+var promptRolloutCmd = &cobra.Command{
+	Use:   "rollout [version]",
+	Short: "Restart agents onto a new prompt version gradually",
+	Long: `Move running agents onto a new prompt version a batch at a time.
+
+Each batch of agents is restarted onto the new version, then watched for
+--soak. An agent fails if its session isn't running at the end of the soak
+or it didn't prime with the new version. When the failure rate across all
+moved agents exceeds --max-failure-rate, the rollout aborts and the moved
+agents are restarted back onto the old version (unless --no-rollback).
+
+Agents are moved lowest-risk first: crew, refineries, witnesses, then the
+Deacon and Mayor. Agents of pinned rigs are skipped. When every agent is
+moved, the version becomes the town default.
+
+Ctrl-C pauses the rollout: moved agents stay on the new version, and
+running the same command again resumes it. --abort rolls it back.
+
+Examples:
+  gt prompt rollout v3 --dry-run
+  gt prompt rollout v3 --batch 2 --soak 10m
+  gt prompt rollout --abort`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPromptRollout,
+}
+
+func init() {
+	promptRolloutCmd.Flags().IntVar(&promptRolloutBatch, "batch", 1, "Agents restarted per batch")
+	promptRolloutCmd.Flags().DurationVar(&promptRolloutSoak, "soak", 5*time.Minute, "How long to watch each batch before the next")
+	promptRolloutCmd.Flags().Float64Var(&promptRolloutMaxFailure, "max-failure-rate", 0.25, "Abort when this fraction of moved agents has failed")
+	promptRolloutCmd.Flags().BoolVarP(&promptRolloutDryRun, "dry-run", "n", false, "Show the batches without restarting anything")
+	promptRolloutCmd.Flags().BoolVar(&promptRolloutAbort, "abort", false, "Abort the running rollout and restart moved agents onto the old version")
+	promptRolloutCmd.Flags().BoolVar(&promptRolloutNoRollback, "no-rollback", false, "On abort, leave moved agents running on the new version")
+	promptCmd.AddCommand(promptRolloutCmd)
+}
+
+// promptRolloutTarget is an agent a rollout restarts.
+type promptRolloutTarget struct {
+	Agent       string
+	RestartArgs []string // gt subcommand that restarts the agent
+	order       int
+}
+
+func runPromptRollout(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rollout, err := promptpack.LoadRollout(townRoot)
+	if err != nil {
+		return err
+	}
+	running := rollout != nil && rollout.Status == promptpack.RolloutRunning
+	t := tmux.NewTmux()
+
+	if promptRolloutAbort {
+		if !running {
+			return fmt.Errorf("no rollout in progress")
+		}
+		return abortPromptRollout(townRoot, t, rollout, "aborted by hand")
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("version required (or --abort)")
+	}
+	version := args[0]
+	if promptRolloutBatch < 1 {
+		return fmt.Errorf("--batch must be at least 1")
+	}
+	if promptRolloutMaxFailure < 0 || promptRolloutMaxFailure > 1 {
+		return fmt.Errorf("--max-failure-rate must be between 0 and 1")
+	}
+	if err := promptpack.Validate(townRoot, version); err != nil {
+		return err
+	}
+
+	if running && rollout.To != version {
+		return fmt.Errorf("a rollout to %s is in progress; finish it or run 'gt prompt rollout --abort'", rollout.To)
+	}
+	if !running {
+		from, _ := promptpack.Resolve(townRoot, "", "")
+		if from == version {
+			return fmt.Errorf("%s is already the town's prompt version", version)
+		}
+		rollout = &promptpack.Rollout{From: from, To: version, Status: promptpack.RolloutRunning, StartedAt: time.Now().UTC()}
+	} else {
+		fmt.Printf("Resuming rollout %s → %s (%d agent(s) already moved)\n", rollout.From, rollout.To, len(rollout.Agents))
+	}
+
+	targets := promptRolloutTargets(townRoot, runningAgentIdentities(t), rollout)
+	batches := batchPromptRolloutTargets(targets, promptRolloutBatch)
+	if promptRolloutDryRun {
+		fmt.Printf("Rollout %s → %s: %d agent(s) in %d batch(es), soak %v each\n", rollout.From, rollout.To, len(targets), len(batches), promptRolloutSoak)
+		for i, b := range batches {
+			for _, tgt := range b {
+				fmt.Printf("  batch %d: %s\n", i+1, tgt.Agent)
+			}
+		}
+		return nil
+	}
+	if err := promptpack.SaveRollout(townRoot, rollout); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for i, batch := range batches {
+		fmt.Printf("%s Batch %d/%d\n", style.Bold.Render("▶"), i+1, len(batches))
+		restartedAt := time.Now().UTC()
+		for _, tgt := range batch {
+			// Mark the agent moved first so its restart primes with the new version.
+			rollout.Agents = append(rollout.Agents, tgt.Agent)
+			if err := promptpack.SaveRollout(townRoot, rollout); err != nil {
+				return err
+			}
+			if err := restartPromptAgent(tgt); err != nil {
+				style.PrintWarning("restarting %s: %v", tgt.Agent, err)
+			} else {
+				fmt.Printf("  restarted %s\n", tgt.Agent)
+			}
+		}
+
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("soaking for %v...", promptRolloutSoak)))
+		select {
+		case <-ctx.Done():
+			fmt.Printf("%s Rollout paused; rerun 'gt prompt rollout %s' to resume or --abort to roll back\n", style.Warning.Render("⏸"), version)
+			return nil
+		case <-time.After(promptRolloutSoak):
+		}
+
+		failed := checkPromptRolloutBatch(townRoot, t, batch, version, restartedAt)
+		rollout.Failed = append(rollout.Failed, failed...)
+		if err := promptpack.SaveRollout(townRoot, rollout); err != nil {
+			return err
+		}
+		for _, f := range failed {
+			fmt.Printf("  %s %s failed its health check\n", style.Error.Render("✗"), f)
+		}
+		rate := rollout.FailureRate()
+		fmt.Printf("  %d/%d moved agent(s) failed (%.0f%%)\n", len(rollout.Failed), len(rollout.Agents), rate*100)
+		if rate > promptRolloutMaxFailure {
+			reason := fmt.Sprintf("failure rate %.0f%% exceeded %.0f%%", rate*100, promptRolloutMaxFailure*100)
+			if err := abortPromptRollout(townRoot, t, rollout, reason); err != nil {
+				return err
+			}
+			return NewSilentExit(1)
+		}
+	}
+
+	if err := setTownPromptVersion(townRoot, version); err != nil {
+		return err
+	}
+	rollout.Status = promptpack.RolloutCompleted
+	rollout.EndedAt = time.Now().UTC()
+	if err := promptpack.SaveRollout(townRoot, rollout); err != nil {
+		return err
+	}
+	fmt.Printf("%s Rollout complete: %s is now the town's prompt version\n", style.Bold.Render("✓"), version)
+	return nil
+}
+
+// promptRolloutTargets returns the running agents a rollout still has to
+// move, lowest-risk first. Agents of pinned rigs and agents already moved
+// are left out.
+func promptRolloutTargets(townRoot string, running map[string]*session.AgentIdentity, rollout *promptpack.Rollout) []promptRolloutTarget {
+	var targets []promptRolloutTarget
+	for agent, id := range running {
+		if rollout.Moved(agent) {
+			continue
+		}
+		if _, source := promptpack.Resolve(townRoot, id.Rig, agent); source == promptpack.SourceRigPin {
+			continue
+		}
+		tgt := promptRolloutTarget{Agent: agent}
+		switch id.Role {
+		case session.RoleCrew:
+			tgt.order, tgt.RestartArgs = 0, []string{"crew", "restart", id.Rig + "/" + id.Name}
+		case session.RoleRefinery:
+			tgt.order, tgt.RestartArgs = 1, []string{"refinery", "restart", id.Rig}
+		case session.RoleWitness:
+			tgt.order, tgt.RestartArgs = 2, []string{"witness", "restart", id.Rig}
+		case session.RoleDeacon:
+			tgt.order, tgt.RestartArgs = 3, []string{"deacon", "restart"}
+		case session.RoleMayor:
+			tgt.order, tgt.RestartArgs = 4, []string{"mayor", "restart"}
+		default:
+			continue // Polecats are ephemeral and pick up the version when spawned
+		}
+		targets = append(targets, tgt)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].order != targets[j].order {
+			return targets[i].order < targets[j].order
+		}
+		return targets[i].Agent < targets[j].Agent
+	})
+	return targets
+}
+
+// batchPromptRolloutTargets splits targets into batches of size n.
+func batchPromptRolloutTargets(targets []promptRolloutTarget, n int) [][]promptRolloutTarget {
+	var batches [][]promptRolloutTarget
+	for len(targets) > 0 {
+		k := min(n, len(targets))
+		batches = append(batches, targets[:k])
+		targets = targets[k:]
+	}
+	return batches
+}
+
+// checkPromptRolloutBatch returns the agents in batch that failed: their
+// session isn't running, or they haven't primed with version since the
+// restart.
+func checkPromptRolloutBatch(townRoot string, t *tmux.Tmux, batch []promptRolloutTarget, version string, restartedAt time.Time) []string {
+	running := runningAgentIdentities(t)
+	sessions, _ := promptpack.LoadSessions(townRoot)
+	var failed []string
+	for _, tgt := range batch {
+		s, primed := sessions[tgt.Agent]
+		_, alive := running[tgt.Agent]
+		if !alive || !primed || s.Version != version || s.PrimedAt.Before(restartedAt) {
+			failed = append(failed, tgt.Agent)
+		}
+	}
+	return failed
+}
+
+// abortPromptRollout ends a rollout and, unless --no-rollback, restarts the
+// moved agents so they prime with the old version again.
+func abortPromptRollout(townRoot string, t *tmux.Tmux, rollout *promptpack.Rollout, reason string) error {
+	rollout.Status = promptpack.RolloutAborted
+	rollout.Reason = reason
+	rollout.EndedAt = time.Now().UTC()
+	if err := promptpack.SaveRollout(townRoot, rollout); err != nil {
+		return err
+	}
+	fmt.Printf("%s Rollout %s → %s aborted: %s\n", style.Error.Render("✗"), rollout.From, rollout.To, reason)
+	if promptRolloutNoRollback {
+		fmt.Printf("  %s\n", style.Dim.Render("Moved agents keep the new version until they restart"))
+		return nil
+	}
+
+	running := runningAgentIdentities(t)
+	moved := make(map[string]*session.AgentIdentity)
+	for _, agent := range rollout.Agents {
+		if id, ok := running[agent]; ok {
+			moved[agent] = id
+		}
+	}
+	for _, tgt := range promptRolloutTargets(townRoot, moved, &promptpack.Rollout{}) {
+		if err := restartPromptAgent(tgt); err != nil {
+			style.PrintWarning("rolling back %s: %v", tgt.Agent, err)
+			continue
+		}
+		fmt.Printf("  rolled back %s to %s\n", tgt.Agent, rollout.From)
+	}
+	return nil
+}
+
+// restartPromptAgent restarts one agent with its gt restart command.
+func restartPromptAgent(tgt promptRolloutTarget) error {
+	gtPath, err := os.Executable()
+	if err != nil {
+		gtPath = cli.Name()
+	}
+	out, err := exec.Command(gtPath, tgt.RestartArgs...).CombinedOutput() //nolint:gosec // G204: fixed gt subcommands
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, out)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/promptpack"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func TestPromptRolloutTargetsOrder(t *testing.T) {
+	townRoot := t.TempDir()
+	running := map[string]*session.AgentIdentity{
+		"mayor":                {Role: session.RoleMayor},
+		"deacon":               {Role: session.RoleDeacon},
+		"gastown/witness":      {Role: session.RoleWitness, Rig: "gastown"},
+		"gastown/refinery":     {Role: session.RoleRefinery, Rig: "gastown"},
+		"gastown/crew/max":     {Role: session.RoleCrew, Rig: "gastown", Name: "max"},
+		"gastown/polecats/nux": {Role: session.RolePolecat, Rig: "gastown", Name: "nux"},
+	}
+	rollout := &promptpack.Rollout{Agents: []string{"gastown/refinery"}}
+
+	var agents []string
+	for _, tgt := range promptRolloutTargets(townRoot, running, rollout) {
+		agents = append(agents, tgt.Agent)
+	}
+	want := []string{"gastown/crew/max", "gastown/witness", "deacon", "mayor"}
+	if !reflect.DeepEqual(agents, want) {
+		t.Errorf("targets = %v, want %v", agents, want)
+	}
+}
+
+func TestBatchPromptRolloutTargets(t *testing.T) {
+	targets := []promptRolloutTarget{{Agent: "a"}, {Agent: "b"}, {Agent: "c"}}
+	batches := batchPromptRolloutTargets(targets, 2)
+	if len(batches) != 2 || len(batches[0]) != 2 || len(batches[1]) != 1 || batches[1][0].Agent != "c" {
+		t.Errorf("batches = %v", batches)
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 var readyJSON bool
 var readyRig string
+var readyLabels []string
 
 var readyCmd = &cobra.Command{
 	Use:     "ready",
@@ -36,16 +37,21 @@ Aggregates ready issues from:
 Ready items have no blockers and can be worked immediately.
 Results are sorted by priority (highest first) then by source.
 
+--label keeps only work with a label matching each pattern given, or
+without it for !pattern; a pattern ending in * matches a label prefix.
+
 Examples:
   gt ready              # Show all ready work
   gt ready --json       # Output as JSON
-  gt ready --rig=gastown  # Show only one rig`,
+  gt ready --rig=gastown  # Show only one rig
+  gt ready --label area:refinery --label '!needs-human'  # Filter by labels`,
 	RunE: runReady,
 }
 
 func init() {
 	readyCmd.Flags().BoolVar(&readyJSON, "json", false, "Output as JSON")
 	readyCmd.Flags().StringVar(&readyRig, "rig", "", "Filter to a specific rig")
+	readyCmd.Flags().StringArrayVar(&readyLabels, "label", nil, "Only show work with this label (!label: without it), can be repeated")
 	rootCmd.AddCommand(readyCmd)
 }
 
@@ -75,6 +81,11 @@ type ReadySummary struct {
 }
 
 func runReady(cmd *cobra.Command, args []string) error {
+	labelFilter, err := beads.ParseLabelFilter(readyLabels)
+	if err != nil {
+		return err
+	}
+
 	// Find town root
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -188,6 +199,7 @@ func runReady(cmd *cobra.Command, args []string) error {
 
 	// Sort issues within each source by priority (lower number = higher priority)
 	for i := range sources {
+		sources[i].Issues = beads.FilterByLabels(sources[i].Issues, labelFilter)
 		sort.Slice(sources[i].Issues, func(a, b int) bool {
 			return sources[i].Issues[a].Priority < sources[i].Issues[b].Priority
 		})
@@ -288,13 +300,12 @@ func printReadyHuman(result ReadyResult) error {
 				priorityStyled = style.Dim.Render(priorityStr)
 			}
 
-			// Truncate title if too long
-			title := issue.Title
-			if len(title) > 60 {
-				title = title[:57] + "..."
+			// Truncate title if too long, then fit the line to the terminal
+			title := style.Truncate(issue.Title, 60)
+			if labels := beads.UserLabels(issue.Labels); len(labels) > 0 {
+				title += " " + style.Dim.Render("["+strings.Join(labels, ", ")+"]")
 			}
-
-			fmt.Printf("  [%s] %s %s\n", priorityStyled, style.Dim.Render(issue.ID), title)
+			fmt.Println(style.FitLine(fmt.Sprintf("  [%s] %s %s", priorityStyled, style.Dim.Render(issue.ID), title)))
 		}
 		fmt.Println()
 	}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	queueExportFormat  string
+	queueExportOutput  string
+	queueExportAll     bool
+	queueExportHistory bool
+	queueExportSince   string
+	queueExportDepth   bool
+
+	queueSnapshotRetainDays int
+)
+
+var refineryQueueExportCmd = &cobra.Command{
+	Use:   "export [rig]",
+	Short: "Export the merge queue as CSV or JSON",
+	Long: `Export the merge queue for spreadsheets and BI tools.
+
+By default the current queue is exported, one row per MR. With --history,
+the snapshots recorded by 'gt refinery queue snapshot' (or the daemon's
+queue_snapshots patrol) are exported instead; --depth turns them into one
+row per snapshot with the queue depth and the age of the oldest MR, which
+is what a depth-over-time chart needs.
+
+CSV columns (per MR):
+  captured_at, rig, position, id, branch, target, worker, issue, status,
+  created_at, age_seconds, error
+
+CSV columns (--depth):
+  captured_at, rig, depth, oldest_age_seconds
+
+Examples:
+  gt refinery queue export gastown > queue.csv
+  gt refinery queue export --all --format json
+  gt refinery queue export --all --history --since 7d --depth -o depth.csv`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRefineryQueueExport,
+}
+
+var refineryQueueSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Record a snapshot of every rig's merge queue",
+	Long: `Record the current merge queue of every rig for later export.
+
+Snapshots are appended to .runtime/queue-snapshots/, one file per UTC day,
+and files older than --retain-days are removed. Run it from cron, or
+enable the queue_snapshots patrol in mayor/daemon.json to take them
+periodically:
+
+  "queue_snapshots": {"enabled": true, "interval": "15m", "retain_days": 30}`,
+	Args: cobra.NoArgs,
+	RunE: runRefineryQueueSnapshot,
+}
+
+func init() {
+	refineryQueueExportCmd.Flags().StringVar(&queueExportFormat, "format", "csv", "Output format: csv or json")
+	refineryQueueExportCmd.Flags().StringVarP(&queueExportOutput, "output", "o", "", "Write to file instead of stdout")
+	refineryQueueExportCmd.Flags().BoolVar(&queueExportAll, "all", false, "Export every rig")
+	refineryQueueExportCmd.Flags().BoolVar(&queueExportHistory, "history", false, "Export recorded snapshots instead of the current queue")
+	refineryQueueExportCmd.Flags().StringVar(&queueExportSince, "since", "7d", "With --history, only snapshots since duration (e.g., 24h, 30d)")
+	refineryQueueExportCmd.Flags().BoolVar(&queueExportDepth, "depth", false, "One row per snapshot with queue depth instead of one per MR")
+
+	refineryQueueSnapshotCmd.Flags().IntVar(&queueSnapshotRetainDays, "retain-days", refinery.DefaultSnapshotRetainDays, "Remove snapshots older than this many days")
+
+	refineryQueueCmd.AddCommand(refineryQueueExportCmd)
+	refineryQueueCmd.AddCommand(refineryQueueSnapshotCmd)
+}
+
+func runRefineryQueueExport(cmd *cobra.Command, args []string) error {
+	if queueExportFormat != "csv" && queueExportFormat != "json" {
+		return fmt.Errorf("invalid --format %q (use csv or json)", queueExportFormat)
+	}
+	if queueExportAll && len(args) > 0 {
+		return fmt.Errorf("--all and a rig name are mutually exclusive")
+	}
+
+	var snaps []refinery.QueueSnapshot
+	if queueExportHistory {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a Gas Town workspace: %w", err)
+		}
+		d, err := parseDuration(queueExportSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since duration: %w", err)
+		}
+		rigName := ""
+		if !queueExportAll {
+			if len(args) > 0 {
+				rigName = args[0]
+			} else if rigName, err = inferRigFromCwd(townRoot); err != nil {
+				return fmt.Errorf("could not determine rig: %w\nUsage: gt refinery queue export <rig> (or --all)", err)
+			}
+		}
+		if snaps, err = refinery.LoadSnapshots(townRoot, rigName, time.Now().Add(-d)); err != nil {
+			return fmt.Errorf("loading snapshots: %w", err)
+		}
+	} else {
+		var err error
+		if queueExportAll {
+			_, snaps, err = captureAllQueueSnapshots(time.Now())
+		} else {
+			var snap refinery.QueueSnapshot
+			snap, err = captureQueueSnapshot(args)
+			snaps = []refinery.QueueSnapshot{snap}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	out := io.Writer(os.Stdout)
+	if queueExportOutput != "" {
+		f, err := os.Create(queueExportOutput)
+		if err != nil {
+			return fmt.Errorf("creating output: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	return writeQueueExport(out, snaps, queueExportFormat, queueExportDepth)
+}
+
+// writeQueueExport writes snaps in format; depth selects one row per
+// snapshot instead of one per MR.
+func writeQueueExport(w io.Writer, snaps []refinery.QueueSnapshot, format string, depth bool) error {
+	if format == "csv" {
+		if depth {
+			return refinery.WriteQueueDepthCSV(w, snaps)
+		}
+		return refinery.WriteQueueCSV(w, snaps)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if depth {
+		for i := range snaps {
+			snaps[i].Items = nil
+		}
+		return enc.Encode(snaps)
+	}
+	rows := []refinery.QueueRow{}
+	for _, snap := range snaps {
+		rows = append(rows, snap.Items...)
+	}
+	return enc.Encode(rows)
+}
+
+// captureQueueSnapshot captures the queue of the rig in args, or of the rig
+// inferred from the current directory.
+func captureQueueSnapshot(args []string) (refinery.QueueSnapshot, error) {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+	mgr, _, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return refinery.QueueSnapshot{}, err
+	}
+	snap, err := mgr.Snapshot(time.Now())
+	if err != nil {
+		return refinery.QueueSnapshot{}, fmt.Errorf("getting queue: %w", err)
+	}
+	return snap, nil
+}
+
+// captureAllQueueSnapshots captures every rig's queue. A rig whose queue
+// can't be read is warned about and left out.
+func captureAllQueueSnapshots(now time.Time) (string, []refinery.QueueSnapshot, error) {
+	rigs, townRoot, err := getAllRigs()
+	if err != nil {
+		return "", nil, err
+	}
+	sort.Slice(rigs, func(i, j int) bool { return rigs[i].Name < rigs[j].Name })
+	var snaps []refinery.QueueSnapshot
+	for _, r := range rigs {
+		snap, err := refinery.NewManager(r).Snapshot(now)
+		if err != nil {
+			style.PrintWarning("%s: %v", r.Name, err)
+			continue
+		}
+		snaps = append(snaps, snap)
+	}
+	return townRoot, snaps, nil
+}
+
+func runRefineryQueueSnapshot(cmd *cobra.Command, args []string) error {
+	townRoot, snaps, err := captureAllQueueSnapshots(time.Now())
+	if err != nil {
+		return err
+	}
+	if err := refinery.AppendSnapshots(townRoot, snaps); err != nil {
+		return err
+	}
+	pruned, err := refinery.PruneSnapshots(townRoot, queueSnapshotRetainDays, time.Now())
+	if err != nil {
+		style.PrintWarning("pruning old snapshots: %v", err)
+	}
+
+	total := 0
+	for _, s := range snaps {
+		total += s.Depth
+	}
+	fmt.Printf("%s Recorded queue snapshot: %d rig(s), %d MR(s)\n", style.Bold.Render("✓"), len(snaps), total)
+	if pruned > 0 {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("removed %d day file(s) older than %d days", pruned, queueSnapshotRetainDays)))
+	}
+	return nil
+}
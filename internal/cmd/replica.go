@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/replica"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	replicaJSON bool
+)
+
+var replicaCmd = &cobra.Command{
+	Use:     "replica",
+	GroupID: GroupDiag,
+	Short:   "Read-only SQLite snapshot of town state for reporting",
+	Long: `Manage the replica: a read-only SQLite snapshot of town state that
+reporting commands query instead of the live stores.
+
+Counting beads across every rig, scanning the event log or summarizing
+merge queues is heavy; done against the live beads databases it competes
+with the writes witnesses, refineries and the daemon make on the hot path.
+The replica copies that state into <town>/.runtime/replica.db, and
+'gt stats', dashboards and ad-hoc SQL read from the copy.
+
+Tables:
+  beads        rig, id, title, status, type, priority, assignee, parent,
+               labels (JSON), created_by, created_at, updated_at, closed_at
+               (town beads have rig 'hq')
+  events       ts, source, type, actor, visibility, payload (JSON);
+               the last 30 days of .events.jsonl
+  merge_queue  rig, position, id, branch, target, worker, issue, status,
+               created_at, age_seconds, error
+  meta         refreshed_at and row counts
+
+Each refresh rebuilds the whole file and swaps it in atomically, so a
+reader never sees a half-written snapshot. The daemon refreshes it when
+the replica patrol is enabled in mayor/daemon.json:
+
+  "replica": {"enabled": true, "interval": "10m"}
+
+Requires the sqlite3 command-line tool.`,
+	RunE: requireSubcommand,
+}
+
+var replicaRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Rebuild the replica from the live stores now",
+	Args:  cobra.NoArgs,
+	RunE:  runReplicaRefresh,
+}
+
+var replicaStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show when the replica was refreshed and what it holds",
+	Args:  cobra.NoArgs,
+	RunE:  runReplicaStatus,
+}
+
+var replicaQueryCmd = &cobra.Command{
+	Use:   "query <sql>",
+	Short: "Run a read-only SQL query against the replica",
+	Long: `Run a read-only SQL query against the replica.
+
+Examples:
+  gt replica query "SELECT rig, status, count(*) FROM beads GROUP BY 1, 2"
+  gt replica query "SELECT type, count(*) FROM events WHERE ts > datetime('now', '-1 day') GROUP BY 1"
+  gt replica query "SELECT rig, max(age_seconds) FROM merge_queue GROUP BY 1" --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReplicaQuery,
+}
+
+func init() {
+	replicaStatusCmd.Flags().BoolVar(&replicaJSON, "json", false, "Output as JSON")
+	replicaQueryCmd.Flags().BoolVar(&replicaJSON, "json", false, "Output as JSON")
+
+	replicaCmd.AddCommand(replicaRefreshCmd)
+	replicaCmd.AddCommand(replicaStatusCmd)
+	replicaCmd.AddCommand(replicaQueryCmd)
+	rootCmd.AddCommand(replicaCmd)
+}
+
+func runReplicaRefresh(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	start := time.Now()
+	snap, err := replica.Refresh(ctx, townRoot, discoverRigs(townRoot))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Replica refreshed in %s: %d beads, %d events, %d queued MRs\n",
+		style.Bold.Render("✓"), time.Since(start).Round(time.Millisecond),
+		len(snap.Beads), len(snap.Events), len(snap.Queue))
+	for _, w := range snap.Warnings {
+		style.PrintWarning("%s", w)
+	}
+	return nil
+}
+
+func runReplicaStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	meta, err := replica.ReadMeta(context.Background(), townRoot)
+	if errors.Is(err, replica.ErrNoReplica) && !replicaJSON {
+		fmt.Printf("%s No replica yet (run 'gt replica refresh')\n", style.Dim.Render("○"))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if replicaJSON {
+		return outputJSON(meta)
+	}
+
+	fmt.Printf("%s Replica refreshed %s ago (%s)\n", style.Bold.Render("●"),
+		meta.Age().Round(time.Second), meta.RefreshedAt.Local().Format("2006-01-02 15:04:05"))
+	fmt.Printf("  Path:        %s (%d KB)\n", replica.Path(townRoot), meta.SizeBytes/1024)
+	fmt.Printf("  Beads:       %d\n", meta.Beads)
+	fmt.Printf("  Events:      %d\n", meta.Events)
+	fmt.Printf("  Merge queue: %d\n", meta.MergeQueue)
+	if len(meta.Rigs) > 0 {
+		fmt.Printf("  Rigs:        %s\n", strings.Join(meta.Rigs, ", "))
+	}
+	for _, w := range meta.Warnings {
+		fmt.Printf("  %s %s\n", style.Warning.Render("!"), w)
+	}
+	return nil
+}
+
+func runReplicaQuery(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	res, err := replica.Query(context.Background(), townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	if replicaJSON {
+		return outputJSON(res)
+	}
+	if len(res.Rows) == 0 {
+		fmt.Printf("%s No rows\n", style.Dim.Render("○"))
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(res.Columns, "\t")))
+	for _, row := range res.Rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\n%d row(s)\n", len(res.Rows))
+	return nil
+}
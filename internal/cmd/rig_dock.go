@@ -255,6 +255,7 @@ func runRigUndock(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Label removed: %s\n", RigDockedLabel)
 	fmt.Printf("  Daemon can now auto-restart agents\n")
 	fmt.Printf("  Use '%s' to start agents immediately\n", style.Dim.Render("gt rig start "+rigName))
+	requireSmokeGateAfterIncident(rigName, r.Path, "rig undocked")
 
 	return nil
 }
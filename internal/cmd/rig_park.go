@@ -158,7 +158,7 @@ func runRigUnpark(cmd *cobra.Command, args []string) error {
 
 func unparkOneRig(rigName string) error {
 	// Get rig and town root
-	townRoot, _, err := getRig(rigName)
+	townRoot, r, err := getRig(rigName)
 	if err != nil {
 		return err
 	}
@@ -172,6 +172,7 @@ func unparkOneRig(rigName string) error {
 	fmt.Printf("%s Rig %s unparked\n", style.Success.Render("✓"), rigName)
 	fmt.Printf("  Daemon can now auto-restart agents\n")
 	fmt.Printf("  Use '%s' to start agents immediately\n", style.Dim.Render("gt rig start "+rigName))
+	requireSmokeGateAfterIncident(rigName, r.Path, "rig unparked")
 
 	return nil
 }
@@ -52,6 +52,8 @@ var beadsExemptCommands = map[string]bool{
 	"nudge":      true,
 	"seance":     true,
 	"doctor":     true,
+	"diagnose":   true,
+	"stats":      true,
 	"dolt":       true,
 	"handoff":    true,
 	"costs":      true,
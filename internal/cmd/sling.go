@@ -123,6 +123,8 @@ var (
 	slingMerge         string // --merge: merge strategy for convoy (direct/mr/local)
 	slingNoBoot        bool   // --no-boot: skip wakeRigAgents (avoid witness/refinery boot and lock contention)
 	slingMaxConcurrent int    // --max-concurrent: limit concurrent spawns in batch mode
+	slingIgnoreHours   bool   // --ignore-active-hours: spawn even outside the town's active hours
+	slingSquad         string // --squad: squad a spawned polecat joins
 	slingBaseBranch    string // --base-branch: override base branch for polecat worktree
 	slingRalph         bool   // --ralph: enable Ralph Wiggum loop mode for multi-step workflows
 )
@@ -148,6 +150,8 @@ func init() {
 	slingCmd.Flags().StringVar(&slingMerge, "merge", "", "Merge strategy: direct (push to main), mr (merge queue, default), local (keep on branch)")
 	slingCmd.Flags().BoolVar(&slingNoBoot, "no-boot", false, "Skip rig boot after polecat spawn (avoids witness/refinery lock contention)")
 	slingCmd.Flags().IntVar(&slingMaxConcurrent, "max-concurrent", 0, "Limit concurrent polecat spawns in batch mode (0 = no limit)")
+	slingCmd.Flags().BoolVar(&slingIgnoreHours, "ignore-active-hours", false, "Spawn even outside the town's active hours (see gt active-hours)")
+	slingCmd.Flags().StringVar(&slingSquad, "squad", "", "Squad a spawned polecat joins; refused if the squad is paused or over budget (see gt squad)")
 	slingCmd.Flags().StringVar(&slingBaseBranch, "base-branch", "", "Override base branch for polecat worktree (e.g., 'develop', 'release/v2')")
 	slingCmd.Flags().BoolVar(&slingRalph, "ralph", false, "Enable Ralph Wiggum loop mode (fresh context per step, for multi-step workflows)")
 
@@ -359,6 +363,20 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Blocked work waits for its dependencies: refuse before any polecat is
+	// spawned for it.
+	if err := checkDependenciesMet(beadID, info); err != nil && !force {
+		return fmt.Errorf("%w\nFinish the dependencies first (gt beads graph %s), or use --force to sling anyway", err, beadID)
+	}
+
+	// Labeled work goes to the rigs set up for it (work_labels in rig
+	// settings): refuse a rig that isn't before a polecat is spawned there.
+	if len(args) > 1 && !force {
+		if err := checkLabelRouting(beadID, info, args[1], townRoot); err != nil {
+			return err
+		}
+	}
+
 	// Preflight: check existing molecules BEFORE spawning polecat.
 	// When formulaName is already known (explicit formula via --on flag), we can
 	// validate early to avoid spawning a polecat that will be immediately orphaned
@@ -395,6 +413,9 @@ func runSling(cmd *cobra.Command, args []string) error {
 		BeadID:     beadID,
 		TownRoot:   townRoot,
 		BaseBranch: slingBaseBranch,
+
+		IgnoreActiveHours: slingIgnoreHours,
+		Squad:             slingSquad,
 	})
 	if err != nil {
 		return err
@@ -633,7 +654,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 	// Log sling event to activity feed
 	actor := detectActor()
-	_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadID, targetAgent))
+	_ = events.LogFeed(events.TypeSling, actor, slingEventPayload(beadID, targetAgent, info))
 
 	// Update agent bead's hook_bead field (ZFC: agents track their current work)
 	// Skip if hook was already set atomically during polecat spawn - avoids "agent bead not found"
@@ -824,11 +845,22 @@ func rollbackSlingArtifacts(spawnInfo *SpawnedPolecatInfo, beadID, hookWorkDir s
 		}
 	}
 
-	// 2. Clean up Dolt branch if it was created
+	// 2. Undo the spawn steps that completed (tmux session, Dolt branch,
+	// worktree and agent bead) in reverse order via the spawn saga.
+	if spawnInfo.saga != nil {
+		if rbErr := spawnInfo.Rollback(); rbErr != nil {
+			fmt.Printf("  %s Incomplete spawn rollback for %s: %v\n",
+				style.Dim.Render("Warning:"), spawnInfo.PolecatName, rbErr)
+		} else {
+			fmt.Printf("  %s Rolled back spawned polecat %s\n", style.Dim.Render("○"), spawnInfo.PolecatName)
+		}
+		return
+	}
+
+	// No saga (info not produced by SpawnPolecatForSling): fall back to
+	// cleaning up the Dolt branch and polecat directly.
 	if err == nil && spawnInfo.DoltBranch != "" && townRoot != "" {
 		doltserver.DeletePolecatBranch(townRoot, spawnInfo.RigName, spawnInfo.DoltBranch)
 	}
-
-	// 3. Clean up the spawned polecat (worktree, agent bead, etc.)
 	cleanupSpawnedPolecat(spawnInfo, spawnInfo.RigName)
 }
@@ -102,6 +102,18 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			continue
 		}
 
+		if err := checkDependenciesMet(beadID, info); err != nil && !slingForce {
+			results = append(results, slingResult{beadID: beadID, success: false, errMsg: "blocked by dependencies"})
+			fmt.Printf("  %s %v (use --force to sling anyway)\n", style.Dim.Render("✗"), err)
+			continue
+		}
+
+		if err := checkLabelRouting(beadID, info, rigName, filepath.Dir(townBeadsDir)); err != nil && !slingForce {
+			results = append(results, slingResult{beadID: beadID, success: false, errMsg: "labeled for another rig"})
+			fmt.Printf("  %s %v\n", style.Dim.Render("✗"), err)
+			continue
+		}
+
 		// Guard: burn existing molecules before applying new formula.
 		// Runs before polecat spawn to avoid wasted spawn/cleanup on rejected beads.
 		if formulaName != "" {
@@ -133,6 +145,9 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			HookBead:   beadID, // Set atomically at spawn time
 			Agent:      slingAgent,
 			BaseBranch: slingBaseBranch,
+
+			IgnoreActiveHours: slingIgnoreHours,
+			Squad:             slingSquad,
 		}
 		spawnInfo, err := spawnPolecatForSling(rigName, spawnOpts)
 		if err != nil {
@@ -207,7 +222,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 		// Log sling event
 		actor := detectActor()
-		_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadToHook, targetAgent))
+		_ = events.LogFeed(events.TypeSling, actor, slingEventPayload(beadToHook, targetAgent, info))
 
 		// Update agent bead state
 		updateAgentHookBead(targetAgent, beadToHook, hookWorkDir, townBeadsDir)
@@ -96,6 +96,9 @@ func runSlingFormula(args []string) error {
 		NoBoot:   slingNoBoot,
 		WorkDesc: formulaName,
 		TownRoot: townRoot,
+
+		IgnoreActiveHours: slingIgnoreHours,
+		Squad:             slingSquad,
 	})
 	if err != nil {
 		return err
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"os"
@@ -16,6 +17,7 @@ import (
 	"github.com/steveyegge/gastown/internal/cli"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/nudge"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -77,6 +79,57 @@ type beadInfo struct {
 	Assignee     string          `json:"assignee"`
 	Description  string          `json:"description"`
 	Dependencies []beads.IssueDep `json:"dependencies,omitempty"`
+	Labels       []string         `json:"labels,omitempty"`
+}
+
+// checkDependenciesMet returns a *beads.BlockedError if the bead has
+// depends_on relations on beads that aren't closed yet: a polecat given it
+// would be building on work that doesn't exist.
+func checkDependenciesMet(beadID string, info *beadInfo) error {
+	if unmet := beads.UnmetDependencies(&beads.Issue{Dependencies: info.Dependencies}); len(unmet) > 0 {
+		return &beads.BlockedError{ID: beadID, Blockers: unmet}
+	}
+	return nil
+}
+
+// rigWorkLabels returns every rig of the town with the work label
+// patterns its settings claim (nil for a rig that claims none).
+func rigWorkLabels(townRoot string) map[string][]string {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	claims := make(map[string][]string, len(rigsConfig.Rigs))
+	for rigName := range rigsConfig.Rigs {
+		claims[rigName] = nil
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rigName)))
+		if err == nil {
+			claims[rigName] = settings.WorkLabels
+		}
+	}
+	return claims
+}
+
+// checkLabelRouting returns an error if the bead carries labels that
+// route work (some rig claims them in its work_labels) and the sling
+// target isn't in a rig claiming them all. The error names the rigs that
+// do. Targets outside a rig (mayor, dogs) aren't checked.
+func checkLabelRouting(beadID string, info *beadInfo, target, townRoot string) error {
+	claims := rigWorkLabels(townRoot)
+	targetRig := strings.SplitN(target, "/", 2)[0]
+	if _, ok := claims[targetRig]; !ok {
+		return nil
+	}
+	route := beads.RouteLabels(info.Labels, claims)
+	if route.Allows(targetRig) {
+		return nil
+	}
+	if len(route.Rigs) == 0 {
+		return fmt.Errorf("bead %s is labeled %s, which no single rig takes (see work_labels in each rig's settings)\nUse --force to override",
+			beadID, strings.Join(route.Routed, ", "))
+	}
+	return fmt.Errorf("bead %s is labeled %s, which rig %q isn't set up for\nSling it to %s instead, or use --force to override",
+		beadID, strings.Join(route.Routed, ", "), targetRig, strings.Join(route.Rigs, " or "))
 }
 
 // collectExistingMolecules returns all molecule wisp IDs attached to a bead.
@@ -186,6 +239,17 @@ func verifyBeadExists(beadID string) error {
 	return nil
 }
 
+// slingEventPayload builds the sling event payload. Work the Witness
+// requeued from a tripped polecat carries the requeue's correlation ID, which
+// links this reassignment to the requeue.
+func slingEventPayload(beadID, target string, info *beadInfo) map[string]interface{} {
+	payload := events.SlingPayload(beadID, target)
+	if id := beads.CorrelationID(info.Description); id != "" {
+		payload["correlation_id"] = id
+	}
+	return payload
+}
+
 // getBeadInfo returns status and assignee for a bead.
 // Resolves the rig directory from the bead's prefix for correct dolt access.
 func getBeadInfo(beadID string) (*beadInfo, error) {
@@ -749,6 +813,10 @@ func hookBeadWithRetry(beadID, targetAgent, hookDir string) error {
 	const maxBackoff = 30 * time.Second
 	skipVerify := os.Getenv("GT_TEST_SKIP_HOOK_VERIFY") != ""
 
+	if err := claimBeadForHook(beadID, targetAgent, hookDir); err != nil {
+		return err
+	}
+
 	var lastErr error
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		hookCmd := exec.Command("bd", "update", beadID, "--status=hooked", "--assignee="+targetAgent)
@@ -803,6 +871,22 @@ func hookBeadWithRetry(beadID, targetAgent, hookDir string) error {
 	return nil
 }
 
+// claimBeadForHook claims a bead for the agent about to be hooked to it, so two
+// dispatchers acting on the same stale list can't both attach it: the loser
+// gets a *beads.AlreadyClaimedError. Any other claim failure is only a warning;
+// the hook itself surfaces real database problems.
+func claimBeadForHook(beadID, targetAgent, hookDir string) error {
+	_, err := beads.New(hookDir).Claim(beadID, targetAgent, beads.DefaultClaimLease)
+	var claimed *beads.AlreadyClaimedError
+	if errors.As(err, &claimed) {
+		return fmt.Errorf("hooking bead: %w", err)
+	}
+	if err != nil {
+		fmt.Printf("%s Could not claim %s: %v\n", style.Dim.Render("Warning:"), beadID, err)
+	}
+	return nil
+}
+
 // slingBackoff calculates exponential backoff with ±25% jitter for a given attempt (1-indexed).
 // Formula: base * 2^(attempt-1) * (1 ± 25% random), capped at max.
 func slingBackoff(attempt int, base, max time.Duration) time.Duration { //nolint:unparam // base is parameterized for testability
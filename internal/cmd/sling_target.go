@@ -107,6 +107,9 @@ type ResolveTargetOptions struct {
 	TownRoot   string
 	WorkDesc   string // Description for dog dispatch (defaults to HookBead if empty)
 	BaseBranch string // Override base branch for polecat worktree
+
+	IgnoreActiveHours bool   // Spawn even outside the town's active hours
+	Squad             string // Squad a spawned polecat joins
 }
 
 // ResolvedTarget holds the results of target resolution.
@@ -195,6 +198,9 @@ func resolveTarget(target string, opts ResolveTargetOptions) (*ResolvedTarget, e
 			HookBead:   opts.HookBead,
 			Agent:      opts.Agent,
 			BaseBranch: opts.BaseBranch,
+
+			IgnoreActiveHours: opts.IgnoreActiveHours,
+			Squad:             opts.Squad,
 		}
 		spawnInfo, err := spawnPolecatForSling(rigName, spawnOpts)
 		if err != nil {
@@ -232,6 +238,9 @@ func resolveTarget(target string, opts ResolveTargetOptions) (*ResolvedTarget, e
 					HookBead:   opts.HookBead,
 					Agent:      opts.Agent,
 					BaseBranch: opts.BaseBranch,
+
+					IgnoreActiveHours: opts.IgnoreActiveHours,
+					Squad:             opts.Squad,
 				}
 				spawnInfo, spawnErr := spawnPolecatForSling(rigName, spawnOpts)
 				if spawnErr != nil {
@@ -1397,6 +1397,48 @@ func TestCheckCrossRigGuard(t *testing.T) {
 	}
 }
 
+// TestCheckLabelRouting verifies that a bead whose labels a rig claims in
+// its work_labels is only slung into rigs claiming them all.
+func TestCheckLabelRouting(t *testing.T) {
+	townRoot := t.TempDir()
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(filepath.Join(townRoot, "mayor", "rigs.json"), `{"version":1,"rigs":{"gastown":{},"beads":{}}}`)
+	writeFile(filepath.Join(townRoot, "gastown", "settings", "config.json"),
+		`{"type":"rig-settings","version":1,"work_labels":["area:refinery","area:cli"]}`)
+
+	tests := []struct {
+		name    string
+		labels  []string
+		target  string
+		wantErr bool
+	}{
+		{"claimed label to claiming rig", []string{"area:refinery"}, "gastown", false},
+		{"claimed label to claiming rig's polecat", []string{"area:cli", "flaky"}, "gastown/polecats/Toast", false},
+		{"claimed label to another rig", []string{"area:refinery"}, "beads", true},
+		{"unclaimed labels to any rig", []string{"flaky", "gt:task"}, "beads", false},
+		{"claimed label to a non-rig target", []string{"area:refinery"}, "mayor", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkLabelRouting("gt-abc", &beadInfo{Labels: tc.labels}, tc.target, townRoot)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("checkLabelRouting(%v, %q) error = %v, wantErr %v", tc.labels, tc.target, err, tc.wantErr)
+			}
+			if err != nil && (!strings.Contains(err.Error(), "gastown") || !strings.Contains(err.Error(), "--force")) {
+				t.Errorf("error should suggest gastown and mention --force, got: %v", err)
+			}
+		})
+	}
+}
+
 func TestIsHookedAgentDead_UnknownFormat(t *testing.T) {
 	// Unknown assignee formats should return false (conservative)
 	tests := []struct {
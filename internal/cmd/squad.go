@@ -0,0 +1,583 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/squad"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	squadJSON        bool
+	squadDescription string
+	squadMaxAgents   int
+	squadDailyUSD    float64
+	squadPauseReason string
+)
+
+var squadCmd = &cobra.Command{
+	Use:     "squad",
+	GroupID: GroupWork,
+	Short:   "Manage squads (named groups of agents)",
+	Long: `Manage squads: named groups of agents working on one initiative.
+
+A squad groups agents across rigs, usually polecats, so they can be
+nudged, paused and reported on together. Members are agent addresses or
+patterns:
+  gastown/polecats/nux      one agent
+  gastown/polecats/*        every polecat of a rig
+  */crew/max                a crew member on any rig
+
+Polecats slung with 'gt sling <bead> <rig> --squad <name>' join the squad
+when they spawn. A polecat name reused by a later spawn outside the squad
+leaves it.
+
+Budgets are enforced by 'gt sling --squad' before spawning:
+  --max-agents  members that may be running at once
+  --daily-usd   what the squad's sessions may spend per day (from gt costs)
+
+Squads are stored in mayor/squads.json. They are unrelated to crew
+workspaces ('gt crew'), which are persistent workers.
+
+Examples:
+  gt squad create search-revamp gastown/polecats/nux beads/polecats/toast
+  gt squad budget search-revamp --max-agents 4 --daily-usd 50
+  gt squad nudge search-revamp "Rebase on main before your next push"
+  gt squad pause search-revamp --reason "waiting on API decision"
+  gt squad report search-revamp`,
+	RunE: requireSubcommand,
+}
+
+var squadListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List squads",
+	Args:  cobra.NoArgs,
+	RunE:  runSquadList,
+}
+
+var squadCreateCmd = &cobra.Command{
+	Use:   "create <name> [members...]",
+	Short: "Create a squad",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runSquadCreate,
+}
+
+var squadDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a squad (its agents keep running)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSquadDelete,
+}
+
+var squadAddCmd = &cobra.Command{
+	Use:   "add <name> <member>...",
+	Short: "Add members to a squad",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runSquadAdd,
+}
+
+var squadRemoveCmd = &cobra.Command{
+	Use:   "remove <name> <member>...",
+	Short: "Remove members from a squad",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runSquadRemove,
+}
+
+var squadBudgetCmd = &cobra.Command{
+	Use:   "budget <name>",
+	Short: "Set a squad's budget",
+	Long: `Set a squad's budget. Zero removes a limit.
+
+Examples:
+  gt squad budget search-revamp --max-agents 4
+  gt squad budget search-revamp --daily-usd 50
+  gt squad budget search-revamp --max-agents 0   # no agent limit`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSquadBudget,
+}
+
+var squadNudgeCmd = &cobra.Command{
+	Use:   "nudge <name> <message>",
+	Short: "Nudge every running member of a squad",
+	Long: `Nudge every running member of a squad.
+
+Members with DND enabled are skipped. --mode works as for gt nudge.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runSquadNudge,
+}
+
+var squadPauseCmd = &cobra.Command{
+	Use:   "pause <name>",
+	Short: "Pause a squad",
+	Long: `Pause a squad: gt sling --squad refuses to spawn into it, and running
+members are told to finish their current step and wait.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSquadPause,
+}
+
+var squadResumeCmd = &cobra.Command{
+	Use:   "resume <name>",
+	Short: "Resume a paused squad",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSquadResume,
+}
+
+var squadReportCmd = &cobra.Command{
+	Use:   "report <name>",
+	Short: "Show a squad's members, activity and budget",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSquadReport,
+}
+
+func init() {
+	squadListCmd.Flags().BoolVar(&squadJSON, "json", false, "Output as JSON")
+	squadReportCmd.Flags().BoolVar(&squadJSON, "json", false, "Output as JSON")
+
+	squadCreateCmd.Flags().StringVar(&squadDescription, "description", "", "What the squad is working on")
+	squadCreateCmd.Flags().IntVar(&squadMaxAgents, "max-agents", 0, "Members that may run at once (0 = unlimited)")
+	squadCreateCmd.Flags().Float64Var(&squadDailyUSD, "daily-usd", 0, "Daily spend limit in USD (0 = unlimited)")
+
+	squadBudgetCmd.Flags().IntVar(&squadMaxAgents, "max-agents", 0, "Members that may run at once (0 = unlimited)")
+	squadBudgetCmd.Flags().Float64Var(&squadDailyUSD, "daily-usd", 0, "Daily spend limit in USD (0 = unlimited)")
+
+	squadNudgeCmd.Flags().StringVar(&nudgeModeFlag, "mode", NudgeModeImmediate, "Delivery mode: immediate (default), queue, or wait-idle")
+
+	squadPauseCmd.Flags().StringVar(&squadPauseReason, "reason", "", "Why the squad is paused")
+
+	squadCmd.AddCommand(squadListCmd)
+	squadCmd.AddCommand(squadCreateCmd)
+	squadCmd.AddCommand(squadDeleteCmd)
+	squadCmd.AddCommand(squadAddCmd)
+	squadCmd.AddCommand(squadRemoveCmd)
+	squadCmd.AddCommand(squadBudgetCmd)
+	squadCmd.AddCommand(squadNudgeCmd)
+	squadCmd.AddCommand(squadPauseCmd)
+	squadCmd.AddCommand(squadResumeCmd)
+	squadCmd.AddCommand(squadReportCmd)
+	rootCmd.AddCommand(squadCmd)
+}
+
+func runSquadList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	reg, err := squad.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	squads := reg.Sorted()
+	if squadJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(squads)
+	}
+	if len(squads) == 0 {
+		fmt.Printf("%s No squads (create one with 'gt squad create')\n", style.Dim.Render("○"))
+		return nil
+	}
+	running := runningAgentIdentities(tmux.NewTmux())
+	for _, s := range squads {
+		state := ""
+		if s.Paused {
+			state = " " + style.Warning.Render("[paused]")
+		}
+		fmt.Printf("%s %s%s  %d member(s), %d running\n", style.Bold.Render("●"), s.Name, state,
+			len(s.Members), len(squadRunningMembers(s, running)))
+		if s.Description != "" {
+			fmt.Printf("  %s\n", style.Dim.Render(s.Description))
+		}
+	}
+	return nil
+}
+
+func runSquadCreate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	name := args[0]
+	if err := squad.ValidateName(name); err != nil {
+		return err
+	}
+	if squadMaxAgents < 0 || squadDailyUSD < 0 {
+		return fmt.Errorf("budgets can't be negative")
+	}
+	err = squad.Update(townRoot, func(reg *squad.Registry) error {
+		if _, exists := reg.Squads[name]; exists {
+			return fmt.Errorf("squad %s already exists", name)
+		}
+		s := &squad.Squad{
+			Name:        name,
+			Description: squadDescription,
+			Members:     []string{},
+			Budget:      squad.Budget{MaxAgents: squadMaxAgents, DailyUSD: squadDailyUSD},
+			CreatedAt:   time.Now().UTC(),
+		}
+		for _, m := range args[1:] {
+			s.AddMember(m)
+		}
+		reg.Squads[name] = s
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Created squad %s with %d member(s)\n", style.Bold.Render("✓"), name, len(args)-1)
+	return nil
+}
+
+func runSquadDelete(cmd *cobra.Command, args []string) error {
+	return updateSquad(args[0], func(reg *squad.Registry, s *squad.Squad) error {
+		delete(reg.Squads, s.Name)
+		fmt.Printf("%s Deleted squad %s\n", style.Bold.Render("✓"), s.Name)
+		return nil
+	})
+}
+
+func runSquadAdd(cmd *cobra.Command, args []string) error {
+	return updateSquad(args[0], func(_ *squad.Registry, s *squad.Squad) error {
+		for _, m := range args[1:] {
+			if s.AddMember(m) {
+				fmt.Printf("%s Added %s to %s\n", style.Bold.Render("✓"), m, s.Name)
+			} else {
+				fmt.Printf("%s %s is already in %s\n", style.Dim.Render("○"), m, s.Name)
+			}
+		}
+		return nil
+	})
+}
+
+func runSquadRemove(cmd *cobra.Command, args []string) error {
+	return updateSquad(args[0], func(_ *squad.Registry, s *squad.Squad) error {
+		for _, m := range args[1:] {
+			if s.RemoveMember(m) {
+				fmt.Printf("%s Removed %s from %s\n", style.Bold.Render("✓"), m, s.Name)
+			} else {
+				fmt.Printf("%s %s is not listed in %s\n", style.Dim.Render("○"), m, s.Name)
+			}
+		}
+		return nil
+	})
+}
+
+func runSquadBudget(cmd *cobra.Command, args []string) error {
+	maxChanged := cmd.Flags().Changed("max-agents")
+	usdChanged := cmd.Flags().Changed("daily-usd")
+	if !maxChanged && !usdChanged {
+		return fmt.Errorf("nothing to set: use --max-agents and/or --daily-usd")
+	}
+	if squadMaxAgents < 0 || squadDailyUSD < 0 {
+		return fmt.Errorf("budgets can't be negative")
+	}
+	return updateSquad(args[0], func(_ *squad.Registry, s *squad.Squad) error {
+		if maxChanged {
+			s.Budget.MaxAgents = squadMaxAgents
+		}
+		if usdChanged {
+			s.Budget.DailyUSD = squadDailyUSD
+		}
+		fmt.Printf("%s Budget for %s: %s\n", style.Bold.Render("✓"), s.Name, formatSquadBudget(s.Budget))
+		return nil
+	})
+}
+
+func runSquadNudge(cmd *cobra.Command, args []string) error {
+	if !validNudgeModes[nudgeModeFlag] {
+		return fmt.Errorf("invalid --mode %q: must be one of immediate, queue, wait-idle", nudgeModeFlag)
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	reg, err := squad.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	s, err := reg.Get(args[0])
+	if err != nil {
+		return err
+	}
+	sent, failed := nudgeSquad(townRoot, s, args[1])
+	fmt.Printf("%s Nudged %d member(s) of %s\n", style.Bold.Render("✓"), sent, s.Name)
+	if failed > 0 {
+		return fmt.Errorf("%d nudge(s) failed", failed)
+	}
+	return nil
+}
+
+func runSquadPause(cmd *cobra.Command, args []string) error {
+	var paused *squad.Squad
+	err := updateSquad(args[0], func(_ *squad.Registry, s *squad.Squad) error {
+		if s.Paused {
+			return fmt.Errorf("squad %s is already paused", s.Name)
+		}
+		now := time.Now().UTC()
+		s.Paused, s.PauseReason, s.PausedAt = true, squadPauseReason, &now
+		paused = s
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Paused squad %s\n", style.Bold.Render("⏸"), paused.Name)
+
+	msg := fmt.Sprintf("Squad %s is paused", paused.Name)
+	if paused.PauseReason != "" {
+		msg += ": " + paused.PauseReason
+	}
+	msg += ". Finish your current step, push what you have, and wait for a resume nudge before starting new work."
+	townRoot, _ := workspace.FindFromCwd()
+	nudgeModeFlag = NudgeModeQueue
+	sent, _ := nudgeSquad(townRoot, paused, msg)
+	fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("told %d running member(s) to wait", sent)))
+	return nil
+}
+
+func runSquadResume(cmd *cobra.Command, args []string) error {
+	var resumed *squad.Squad
+	err := updateSquad(args[0], func(_ *squad.Registry, s *squad.Squad) error {
+		if !s.Paused {
+			return fmt.Errorf("squad %s is not paused", s.Name)
+		}
+		s.Paused, s.PauseReason, s.PausedAt = false, "", nil
+		resumed = s
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Resumed squad %s\n", style.Bold.Render("▶"), resumed.Name)
+
+	townRoot, _ := workspace.FindFromCwd()
+	nudgeModeFlag = NudgeModeQueue
+	sent, _ := nudgeSquad(townRoot, resumed, fmt.Sprintf("Squad %s is resumed. Continue your work.", resumed.Name))
+	fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("told %d running member(s) to continue", sent)))
+	return nil
+}
+
+// squadReport is the JSON form of gt squad report.
+type squadReport struct {
+	Squad      *squad.Squad        `json:"squad"`
+	Running    []string            `json:"running"`
+	SpentToday float64             `json:"spent_today_usd"`
+	ByAgent    map[string]float64  `json:"spent_today_by_agent,omitempty"`
+	Budget     squadBudgetHeadroom `json:"budget"`
+}
+
+// squadBudgetHeadroom is how much of each budget limit is used.
+type squadBudgetHeadroom struct {
+	AgentsUsed  int     `json:"agents_used"`
+	AgentsLimit int     `json:"agents_limit,omitempty"`
+	USDUsed     float64 `json:"usd_used"`
+	USDLimit    float64 `json:"usd_limit,omitempty"`
+	SpawnsOK    bool    `json:"spawns_ok"`
+	Blocked     string  `json:"blocked,omitempty"`
+}
+
+func runSquadReport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	reg, err := squad.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	s, err := reg.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	running := squadRunningMembers(s, runningAgentIdentities(tmux.NewTmux()))
+	spent, byAgent := squadSpendToday(s, time.Now())
+	report := squadReport{
+		Squad:      s,
+		Running:    running,
+		SpentToday: spent,
+		ByAgent:    byAgent,
+		Budget: squadBudgetHeadroom{
+			AgentsUsed:  len(running),
+			AgentsLimit: s.Budget.MaxAgents,
+			USDUsed:     spent,
+			USDLimit:    s.Budget.DailyUSD,
+			SpawnsOK:    true,
+		},
+	}
+	if err := s.CheckSpawn(len(running), spent); err != nil {
+		report.Budget.SpawnsOK, report.Budget.Blocked = false, err.Error()
+	}
+
+	if squadJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	fmt.Printf("%s Squad %s\n", style.Bold.Render("●"), s.Name)
+	if s.Description != "" {
+		fmt.Printf("  %s\n", s.Description)
+	}
+	if s.Paused {
+		since := ""
+		if s.PausedAt != nil {
+			since = fmt.Sprintf(" for %s", time.Since(*s.PausedAt).Round(time.Minute))
+		}
+		fmt.Printf("  %s paused%s %s\n", style.Warning.Render("⏸"), since, style.Dim.Render(s.PauseReason))
+	}
+	fmt.Printf("\n  Members (%d):\n", len(s.Members))
+	for _, m := range s.Members {
+		fmt.Printf("    %s\n", m)
+	}
+	fmt.Printf("\n  Running (%d):\n", len(running))
+	if len(running) == 0 {
+		fmt.Printf("    %s\n", style.Dim.Render("(none)"))
+	}
+	for _, a := range running {
+		fmt.Printf("    %s %s\n", style.Success.Render("●"), a)
+	}
+	fmt.Printf("\n  Spent today: $%.2f\n", spent)
+	agents := make([]string, 0, len(byAgent))
+	for a := range byAgent {
+		agents = append(agents, a)
+	}
+	sort.Strings(agents)
+	for _, a := range agents {
+		fmt.Printf("    %-32s $%.2f\n", a, byAgent[a])
+	}
+	fmt.Printf("\n  Budget: %s\n", formatSquadBudget(s.Budget))
+	if !report.Budget.SpawnsOK {
+		fmt.Printf("  %s %s\n", style.Warning.Render("⚠"), report.Budget.Blocked)
+	}
+	return nil
+}
+
+// updateSquad applies fn to the named squad under the registry lock.
+func updateSquad(name string, fn func(*squad.Registry, *squad.Squad) error) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return squad.Update(townRoot, func(reg *squad.Registry) error {
+		s, err := reg.Get(name)
+		if err != nil {
+			return err
+		}
+		return fn(reg, s)
+	})
+}
+
+// squadRunningMembers returns the addresses of running agents in s, sorted.
+func squadRunningMembers(s *squad.Squad, running map[string]*session.AgentIdentity) []string {
+	members := []string{}
+	for addr := range running {
+		if s.Matches(addr) {
+			members = append(members, addr)
+		}
+	}
+	sort.Strings(members)
+	return members
+}
+
+// nudgeSquad nudges the running members of s, skipping those in DND, and
+// returns how many were nudged and how many failed.
+func nudgeSquad(townRoot string, s *squad.Squad, message string) (sent, failed int) {
+	t := tmux.NewTmux()
+	running := runningAgentIdentities(t)
+	sender := detectSender()
+	for _, addr := range squadRunningMembers(s, running) {
+		if townRoot != "" {
+			if ok, level, _ := shouldNudgeTarget(townRoot, addr, false); !ok {
+				fmt.Printf("  %s %s (DND: %s)\n", style.Dim.Render("○"), addr, level)
+				continue
+			}
+		}
+		if err := deliverNudge(t, running[addr].SessionName(), message, sender); err != nil {
+			failed++
+			fmt.Printf("  %s %s: %v\n", style.ErrorPrefix, addr, err)
+			continue
+		}
+		sent++
+	}
+	return sent, failed
+}
+
+// squadSpendToday sums today's recorded session costs (gt costs record) of
+// the squad's members, in total and per agent.
+func squadSpendToday(s *squad.Squad, now time.Time) (float64, map[string]float64) {
+	byAgent := make(map[string]float64)
+	f, err := os.Open(getCostsLogPath())
+	if err != nil {
+		return 0, byAgent
+	}
+	defer f.Close()
+
+	today := now.Format("2006-01-02")
+	var total float64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry CostLogEntry
+		if json.Unmarshal([]byte(line), &entry) != nil || entry.EndedAt.Local().Format("2006-01-02") != today {
+			continue
+		}
+		id, err := session.ParseSessionName(entry.SessionID)
+		if err != nil || !s.Matches(id.Address()) {
+			continue
+		}
+		byAgent[id.Address()] += entry.CostUSD
+		total += entry.CostUSD
+	}
+	return total, byAgent
+}
+
+// formatSquadBudget renders a budget for display.
+func formatSquadBudget(b squad.Budget) string {
+	var parts []string
+	if b.MaxAgents > 0 {
+		parts = append(parts, fmt.Sprintf("%d agent(s) at once", b.MaxAgents))
+	}
+	if b.DailyUSD > 0 {
+		parts = append(parts, fmt.Sprintf("$%.2f/day", b.DailyUSD))
+	}
+	if len(parts) == 0 {
+		return "unlimited"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// checkSquadSpawn returns an error if spawning into the named squad would
+// break its pause or budget.
+func checkSquadSpawn(townRoot, name string) error {
+	reg, err := squad.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	s, err := reg.Get(name)
+	if err != nil {
+		return err
+	}
+	running := squadRunningMembers(s, runningAgentIdentities(tmux.NewTmux()))
+	spent, _ := squadSpendToday(s, time.Now())
+	if err := s.CheckSpawn(len(running), spent); err != nil {
+		if errors.Is(err, squad.ErrPaused) {
+			return fmt.Errorf("%w (see 'gt squad resume %s')", err, name)
+		}
+		return fmt.Errorf("%w (see 'gt squad report %s')", err, name)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/replica"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// statsStaleAfter is the replica age past which gt stats warns that its
+// numbers are old.
+const statsStaleAfter = time.Hour
+
+var (
+	statsJSON    bool
+	statsRefresh bool
+	statsSince   time.Duration
+)
+
+var statsCmd = &cobra.Command{
+	Use:     "stats",
+	GroupID: GroupDiag,
+	Short:   "Town-wide counts of beads, merge queues and activity",
+	Long: `Show town-wide statistics: beads per rig by status, beads created and
+closed recently, merge queue depth per rig, and events by type.
+
+Stats are read from the replica (see 'gt replica'), never from the live
+beads databases, so running them doesn't slow down agents at work. The
+numbers are as of the replica's last refresh; use --refresh to rebuild
+it first.
+
+Examples:
+  gt stats                  # Last 24 hours of activity
+  gt stats --since 168h     # Last week
+  gt stats --refresh --json`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "Output as JSON")
+	statsCmd.Flags().BoolVar(&statsRefresh, "refresh", false, "Refresh the replica before reading it")
+	statsCmd.Flags().DurationVar(&statsSince, "since", 24*time.Hour, "Window for recent activity")
+	rootCmd.AddCommand(statsCmd)
+}
+
+// TownStats is the output of gt stats.
+type TownStats struct {
+	RefreshedAt time.Time       `json:"refreshed_at"`
+	Since       time.Time       `json:"since"`
+	Beads       []RigBeadStats  `json:"beads"`
+	Created     int             `json:"created"`
+	Closed      int             `json:"closed"`
+	MergeQueue  []RigQueueStats `json:"merge_queue"`
+	Events      []EventCount    `json:"events"`
+}
+
+// RigBeadStats counts one rig's beads by status.
+type RigBeadStats struct {
+	Rig        string `json:"rig"`
+	Open       int    `json:"open"`
+	InProgress int    `json:"in_progress"`
+	Closed     int    `json:"closed"`
+	Other      int    `json:"other"`
+	Total      int    `json:"total"`
+}
+
+// RigQueueStats is one rig's merge queue.
+type RigQueueStats struct {
+	Rig        string `json:"rig"`
+	Depth      int    `json:"depth"`
+	OldestAgeS int64  `json:"oldest_age_seconds"`
+}
+
+// EventCount is how many events of one type were logged.
+type EventCount struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+// loadTownStats reads town statistics from the replica, counting activity
+// at or after since.
+func loadTownStats(ctx context.Context, townRoot string, since time.Time) (*TownStats, error) {
+	meta, err := replica.ReadMeta(ctx, townRoot)
+	if err != nil {
+		return nil, err
+	}
+	st := &TownStats{
+		RefreshedAt: meta.RefreshedAt,
+		Since:       since.UTC(),
+		Beads:       []RigBeadStats{},
+		MergeQueue:  []RigQueueStats{},
+		Events:      []EventCount{},
+	}
+	cutoff := "julianday('" + since.UTC().Format(time.RFC3339) + "')"
+
+	res, err := replica.Query(ctx, townRoot, `SELECT rig, count(*),
+  sum(status = 'open'), sum(status = 'in_progress'), sum(status = 'closed')
+FROM beads GROUP BY rig ORDER BY rig;`)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range res.Rows {
+		b := RigBeadStats{Rig: row[0], Total: statsInt(row[1]), Open: statsInt(row[2]),
+			InProgress: statsInt(row[3]), Closed: statsInt(row[4])}
+		b.Other = b.Total - b.Open - b.InProgress - b.Closed
+		st.Beads = append(st.Beads, b)
+	}
+
+	res, err = replica.Query(ctx, townRoot, `SELECT
+  sum(julianday(created_at) >= `+cutoff+`), sum(julianday(closed_at) >= `+cutoff+`)
+FROM beads;`)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Rows) == 1 {
+		st.Created, st.Closed = statsInt(res.Rows[0][0]), statsInt(res.Rows[0][1])
+	}
+
+	res, err = replica.Query(ctx, townRoot,
+		`SELECT rig, count(*), max(age_seconds) FROM merge_queue GROUP BY rig ORDER BY rig;`)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range res.Rows {
+		st.MergeQueue = append(st.MergeQueue, RigQueueStats{Rig: row[0], Depth: statsInt(row[1]), OldestAgeS: int64(statsInt(row[2]))})
+	}
+
+	res, err = replica.Query(ctx, townRoot, `SELECT type, count(*) FROM events
+WHERE julianday(ts) >= `+cutoff+` GROUP BY type ORDER BY 2 DESC, 1;`)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range res.Rows {
+		st.Events = append(st.Events, EventCount{Type: row[0], Count: statsInt(row[1])})
+	}
+	return st, nil
+}
+
+// statsInt parses a numeric query column; NULL (empty) is zero.
+func statsInt(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	ctx := context.Background()
+	if statsRefresh {
+		if _, err := replica.Refresh(ctx, townRoot, discoverRigs(townRoot)); err != nil {
+			return err
+		}
+	}
+	st, err := loadTownStats(ctx, townRoot, time.Now().Add(-statsSince))
+	if err != nil {
+		return err
+	}
+	if statsJSON {
+		return outputJSON(st)
+	}
+
+	age := time.Since(st.RefreshedAt).Round(time.Second)
+	fmt.Printf("%s Town stats %s\n", style.Bold.Render("●"), style.Dim.Render(fmt.Sprintf("(replica refreshed %s ago)", age)))
+	if age > statsStaleAfter {
+		style.PrintWarning("replica is %s old: run 'gt stats --refresh' or enable the replica daemon patrol", age)
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Beads"))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  RIG\tOPEN\tIN PROGRESS\tCLOSED\tOTHER\tTOTAL")
+	for _, b := range st.Beads {
+		fmt.Fprintf(w, "  %s\t%d\t%d\t%d\t%d\t%d\n", b.Rig, b.Open, b.InProgress, b.Closed, b.Other, b.Total)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("  Last %s: %d created, %d closed\n", statsSince, st.Created, st.Closed)
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Merge queue"))
+	if len(st.MergeQueue) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("empty"))
+	}
+	for _, q := range st.MergeQueue {
+		fmt.Printf("  %s: %d queued, oldest %s\n", q.Rig, q.Depth, (time.Duration(q.OldestAgeS) * time.Second).String())
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render(fmt.Sprintf("Events (last %s)", statsSince)))
+	if len(st.Events) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("none"))
+	}
+	for _, e := range st.Events {
+		fmt.Printf("  %-24s %d\n", e.Type, e.Count)
+	}
+	return nil
+}
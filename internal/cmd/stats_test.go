@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/replica"
+)
+
+func TestLoadTownStats(t *testing.T) {
+	if !replica.Available() {
+		t.Skip("sqlite3 not installed")
+	}
+	townRoot := t.TempDir()
+	now := time.Now().UTC()
+	recent := now.Add(-time.Hour).Format(time.RFC3339)
+	old := now.Add(-72 * time.Hour).Format(time.RFC3339)
+	snap := &replica.Snapshot{
+		TakenAt: now,
+		Beads: []replica.BeadRow{
+			{Rig: "gastown", ID: "gt-1", Status: "open", CreatedAt: recent},
+			{Rig: "gastown", ID: "gt-2", Status: "in_progress", CreatedAt: old},
+			{Rig: "gastown", ID: "gt-3", Status: "closed", CreatedAt: old, ClosedAt: recent},
+			{Rig: "gastown", ID: "gt-4", Status: "blocked", CreatedAt: old},
+			{Rig: "hq", ID: "hq-1", Status: "closed", CreatedAt: old, ClosedAt: old},
+		},
+		Events: []replica.EventRow{
+			{Timestamp: recent, Type: "sling"},
+			{Timestamp: recent, Type: "sling"},
+			{Timestamp: recent, Type: "done"},
+			{Timestamp: old, Type: "done"},
+		},
+		Queue: []refinery.QueueRow{
+			{Rig: "gastown", Position: 1, ID: "gt-mr1", AgeSeconds: 600},
+			{Rig: "gastown", Position: 2, ID: "gt-mr2", AgeSeconds: 60},
+		},
+	}
+	if err := replica.Build(context.Background(), townRoot, snap); err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	st, err := loadTownStats(context.Background(), townRoot, now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("loadTownStats: %v", err)
+	}
+	if len(st.Beads) != 2 {
+		t.Fatalf("Beads = %+v, want gastown and hq", st.Beads)
+	}
+	want := RigBeadStats{Rig: "gastown", Open: 1, InProgress: 1, Closed: 1, Other: 1, Total: 4}
+	if st.Beads[0] != want {
+		t.Errorf("gastown = %+v, want %+v", st.Beads[0], want)
+	}
+	if st.Created != 1 || st.Closed != 1 {
+		t.Errorf("created/closed = %d/%d, want 1/1", st.Created, st.Closed)
+	}
+	if len(st.MergeQueue) != 1 || st.MergeQueue[0].Depth != 2 || st.MergeQueue[0].OldestAgeS != 600 {
+		t.Errorf("MergeQueue = %+v", st.MergeQueue)
+	}
+	if len(st.Events) != 2 || st.Events[0] != (EventCount{Type: "sling", Count: 2}) || st.Events[1] != (EventCount{Type: "done", Count: 1}) {
+		t.Errorf("Events = %+v", st.Events)
+	}
+}
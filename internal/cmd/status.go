@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/activehours"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
@@ -24,6 +25,7 @@ import (
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
 	"golang.org/x/term"
 )
@@ -65,6 +67,9 @@ type TownStatus struct {
 	Agents   []AgentRuntime `json:"agents"`             // Global agents (Mayor, Deacon)
 	Rigs     []RigStatus    `json:"rigs"`
 	Summary  StatusSum      `json:"summary"`
+
+	// ActiveHours is set when the town has active hours or an override.
+	ActiveHours *activehours.Status `json:"active_hours,omitempty"`
 }
 
 // OverseerInfo represents the human operator's identity and status.
@@ -78,19 +83,20 @@ type OverseerInfo struct {
 
 // AgentRuntime represents the runtime state of an agent.
 type AgentRuntime struct {
-	Name         string `json:"name"`                    // Display name (e.g., "mayor", "witness")
-	Address      string `json:"address"`                 // Full address (e.g., "greenplace/witness")
-	Session      string `json:"session"`                 // tmux session name
-	Role         string `json:"role"`                    // Role type
-	Running      bool   `json:"running"`                 // Is tmux session running?
-	HasWork      bool   `json:"has_work"`                // Has pinned work?
-	WorkTitle    string `json:"work_title,omitempty"`    // Title of pinned work
-	HookBead     string `json:"hook_bead,omitempty"`     // Pinned bead ID from agent bead
-	State        string `json:"state,omitempty"`         // Agent state from agent bead
-	UnreadMail   int    `json:"unread_mail"`             // Number of unread messages
-	FirstSubject string `json:"first_subject,omitempty"` // Subject of first unread message
-	AgentAlias   string `json:"agent_alias,omitempty"`   // Configured agent name (e.g., "opus-46", "pi")
-	AgentInfo    string `json:"agent_info,omitempty"`    // Runtime summary (e.g., "claude/opus", "pi/kimi-k2p5")
+	Name         string   `json:"name"`                    // Display name (e.g., "mayor", "witness")
+	Address      string   `json:"address"`                 // Full address (e.g., "greenplace/witness")
+	Session      string   `json:"session"`                 // tmux session name
+	Role         string   `json:"role"`                    // Role type
+	Running      bool     `json:"running"`                 // Is tmux session running?
+	HasWork      bool     `json:"has_work"`                // Has pinned work?
+	WorkTitle    string   `json:"work_title,omitempty"`    // Title of pinned work
+	WorkLabels   []string `json:"work_labels,omitempty"`   // User labels of pinned work
+	HookBead     string   `json:"hook_bead,omitempty"`     // Pinned bead ID from agent bead
+	State        string   `json:"state,omitempty"`         // Agent state from agent bead
+	UnreadMail   int      `json:"unread_mail"`             // Number of unread messages
+	FirstSubject string   `json:"first_subject,omitempty"` // Subject of first unread message
+	AgentAlias   string   `json:"agent_alias,omitempty"`   // Configured agent name (e.g., "opus-46", "pi")
+	AgentInfo    string   `json:"agent_info,omitempty"`    // Runtime summary (e.g., "claude/opus", "pi/kimi-k2p5")
 }
 
 // RigStatus represents status of a single rig.
@@ -105,6 +111,10 @@ type RigStatus struct {
 	Hooks        []AgentHookInfo `json:"hooks,omitempty"`
 	Agents       []AgentRuntime  `json:"agents,omitempty"` // Runtime state of all agents in rig
 	MQ           *MQSummary      `json:"mq,omitempty"`     // Merge queue summary
+
+	// WitnessHeartbeat is the Witness's last circuit breaker sweep; nil if
+	// it has never swept the rig.
+	WitnessHeartbeat *witness.Heartbeat `json:"witness_heartbeat,omitempty"`
 }
 
 // MQSummary represents the merge queue status for a rig.
@@ -798,6 +808,9 @@ func gatherStatus() (TownStatus, error) {
 			if !statusFast {
 				rs.MQ = getMQSummary(r)
 			}
+			if r.HasWitness {
+				rs.WitnessHeartbeat = witness.ReadHeartbeat(r.Path)
+			}
 
 			status.Rigs[idx] = rs
 		}(i, r)
@@ -835,6 +848,10 @@ func gatherStatus() (TownStatus, error) {
 	}
 	status.Summary.RigCount = len(rigs)
 
+	if hours, err := activehours.Current(townRoot, time.Now()); err == nil && (hours.Configured || hours.Override != nil) {
+		status.ActiveHours = hours
+	}
+
 	return status, nil
 }
 
@@ -849,6 +866,14 @@ func outputStatusText(w io.Writer, status TownStatus) error {
 	fmt.Fprintf(w, "%s %s\n", style.Bold.Render("Town:"), status.Name)
 	fmt.Fprintf(w, "%s\n\n", style.Dim.Render(status.Location))
 
+	if hours := status.ActiveHours; hours != nil {
+		icon := "🕒"
+		if !hours.Open {
+			icon = "⏸"
+		}
+		fmt.Fprintf(w, "%s %s spawning %s\n\n", icon, style.Bold.Render("Active hours:"), hours.Summary(time.Now()))
+	}
+
 	// Overseer info
 	if status.Overseer != nil {
 		overseerDisplay := status.Overseer.Name
@@ -904,7 +929,7 @@ func outputStatusText(w io.Writer, status TownStatus) error {
 	// Rigs
 	for _, r := range status.Rigs {
 		// Rig header with separator
-		fmt.Fprintf(w, "─── %s ───────────────────────────────────────────\n\n", style.Bold.Render(r.Name+"/"))
+		fmt.Fprintf(w, "%s\n\n", style.Rule(style.Bold.Render(r.Name+"/")))
 
 		// Group agents by role
 		var witnesses, refineries, crews, polecats []AgentRuntime
@@ -928,10 +953,18 @@ func outputStatusText(w io.Writer, status TownStatus) error {
 				for _, agent := range witnesses {
 					renderAgentDetails(w, agent, "   ", r.Hooks, status.Location)
 				}
+				if hb := r.WitnessHeartbeat; hb != nil {
+					fmt.Fprintf(w, "   Heartbeat: %s (sweep #%d: %d failing, %d open)\n",
+						formatWitnessHeartbeat(hb), hb.Sweeps, hb.Checked, hb.Open)
+				}
 				fmt.Fprintln(w)
 			} else {
 				for _, agent := range witnesses {
-					renderAgentCompact(w, agent, roleIcons["witness"]+" ", r.Hooks, status.Location)
+					suffix := ""
+					if r.WitnessHeartbeat != nil {
+						suffix = "  ♥ " + formatWitnessHeartbeat(r.WitnessHeartbeat)
+					}
+					renderAgentCompactWithSuffix(w, agent, roleIcons["witness"]+" ", r.Hooks, status.Location, suffix)
 				}
 			}
 		}
@@ -1095,6 +1128,9 @@ func renderAgentDetails(w io.Writer, agent AgentRuntime, indent string, hooks []
 		} else {
 			hookStr = hookBead
 		}
+		if len(agent.WorkLabels) > 0 {
+			hookStr += " " + style.Dim.Render("["+strings.Join(agent.WorkLabels, ", ")+"]")
+		}
 	} else if hookTitle != "" {
 		// Has title but no molecule ID
 		hookStr = truncateWithEllipsis(hookTitle, 50)
@@ -1163,6 +1199,16 @@ func formatMQSummaryCompact(mq *MQSummary) string {
 	return fmt.Sprintf("MQ:%d%s", total, healthSuffix)
 }
 
+// formatWitnessHeartbeat shows how long ago the Witness last swept, flagged
+// when stale.
+func formatWitnessHeartbeat(hb *witness.Heartbeat) string {
+	age := formatDuration(hb.Age()) + " ago"
+	if hb.IsStale() {
+		return style.Error.Render(age + " [stale]")
+	}
+	return style.Dim.Render(age)
+}
+
 // renderAgentCompactWithSuffix renders a single-line agent status with an extra suffix
 func renderAgentCompactWithSuffix(w io.Writer, agent AgentRuntime, indent string, hooks []AgentHookInfo, _ string, suffix string) {
 	// Build status indicator (gt-zecmc: use tmux state, not bead state)
@@ -1206,7 +1252,8 @@ func renderAgentCompactWithSuffix(w io.Writer, agent AgentRuntime, indent string
 	}
 
 	// Print single line: name + status + agent-info + hook + mail + suffix
-	fmt.Fprintf(w, "%s%-12s %s%s%s%s%s\n", indent, agent.Name, statusIndicator, agentSuffix, hookSuffix, mailSuffix, suffix)
+	line := indent + style.PadRight(agent.Name, 12) + " " + statusIndicator + agentSuffix + hookSuffix + mailSuffix + suffix
+	fmt.Fprintln(w, style.FitLine(line))
 }
 
 // renderAgentCompact renders a single-line agent status
@@ -1252,7 +1299,8 @@ func renderAgentCompact(w io.Writer, agent AgentRuntime, indent string, hooks []
 	}
 
 	// Print single line: name + status + agent-info + hook + mail
-	fmt.Fprintf(w, "%s%-12s %s%s%s%s\n", indent, agent.Name, statusIndicator, agentSuffix, hookSuffix, mailSuffix)
+	line := indent + style.PadRight(agent.Name, 12) + " " + statusIndicator + agentSuffix + hookSuffix + mailSuffix
+	fmt.Fprintln(w, style.FitLine(line))
 }
 
 // buildStatusIndicator creates the visual status indicator for an agent.
@@ -1296,15 +1344,10 @@ func formatHookInfo(hookBead, title string, maxLen int) string {
 	return fmt.Sprintf(" → %s", title)
 }
 
-// truncateWithEllipsis shortens a string to maxLen, adding "..." if truncated
+// truncateWithEllipsis shortens a string to maxLen display columns, adding
+// "..." if truncated. Wide characters and emoji count as two columns.
 func truncateWithEllipsis(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
-	}
-	if maxLen < 4 {
-		return s[:maxLen]
-	}
-	return s[:maxLen-3] + "..."
+	return style.Truncate(s, maxLen)
 }
 
 // capitalizeFirst capitalizes the first letter of a string
@@ -1408,6 +1451,7 @@ func discoverGlobalAgents(allSessions map[string]bool, allAgentBeads map[string]
 					// Get hook title from preloaded map
 					if pinnedIssue, ok := allHookBeads[agent.HookBead]; ok {
 						agent.WorkTitle = pinnedIssue.Title
+						agent.WorkLabels = beads.UserLabels(pinnedIssue.Labels)
 					}
 				}
 				// Fallback to description for legacy beads without database columns
@@ -1548,6 +1592,7 @@ func discoverRigAgents(allSessions map[string]bool, r *rig.Rig, crews []string,
 					// Get hook title from preloaded map
 					if pinnedIssue, ok := allHookBeads[agent.HookBead]; ok {
 						agent.WorkTitle = pinnedIssue.Title
+						agent.WorkLabels = beads.UserLabels(pinnedIssue.Labels)
 					}
 				}
 				// Fallback to description for legacy beads without database columns
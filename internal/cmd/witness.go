@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -81,7 +84,9 @@ var witnessStatusCmd = &cobra.Command{
 	Short: "Show witness status",
 	Long: `Show the status of a rig's Witness.
 
-Displays running state, monitored polecats, and statistics.`,
+Displays running state, monitored polecats, and the circuit breaker of
+each polecat agent bead: its state, failure count, hooked work, time since
+its last failure and, for an open circuit, when its cooldown ends.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runWitnessStatus,
 }
@@ -230,6 +235,9 @@ type WitnessStatusOutput struct {
 	RigName           string   `json:"rig_name"`
 	Session           string   `json:"session,omitempty"`
 	MonitoredPolecats []string `json:"monitored_polecats,omitempty"`
+
+	Circuits  []witness.CircuitStatus `json:"circuits,omitempty"`
+	Heartbeat *witness.Heartbeat      `json:"heartbeat,omitempty"`
 }
 
 func runWitnessStatus(cmd *cobra.Command, args []string) error {
@@ -250,12 +258,20 @@ func runWitnessStatus(cmd *cobra.Command, args []string) error {
 	// Polecats come from rig config, not state file
 	polecats := r.Polecats
 
+	cfg, err := witness.LoadCircuitBreakerConfig(r.Path)
+	if err != nil {
+		style.PrintWarning("circuit breaker settings: %v (using defaults)", err)
+	}
+	circuits, circuitsErr := witness.ListCircuits(r.Path, rigName, cfg)
+
 	// JSON output
 	if witnessStatusJSON {
 		output := WitnessStatusOutput{
 			Running:           running,
 			RigName:           rigName,
 			MonitoredPolecats: polecats,
+			Circuits:          circuits,
+			Heartbeat:         witness.ReadHeartbeat(r.Path),
 		}
 		if sessionInfo != nil {
 			output.Session = sessionInfo.Name
@@ -276,6 +292,11 @@ func runWitnessStatus(cmd *cobra.Command, args []string) error {
 	} else {
 		fmt.Printf("  State: %s\n", style.Dim.Render("○ stopped"))
 	}
+	if hb := witness.ReadHeartbeat(r.Path); hb != nil {
+		fmt.Printf("  Heartbeat: %s (sweep #%d)\n", formatWitnessHeartbeat(hb), hb.Sweeps)
+	} else {
+		fmt.Printf("  Heartbeat: %s\n", style.Dim.Render("none (no sweep yet)"))
+	}
 
 	// Show monitored polecats
 	fmt.Printf("\n  %s\n", style.Bold.Render("Monitored Polecats:"))
@@ -287,9 +308,82 @@ func runWitnessStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	fmt.Printf("\n  %s\n", style.Bold.Render("Circuits:"))
+	switch {
+	case circuitsErr != nil:
+		fmt.Printf("    %s\n", style.Dim.Render(circuitsErr.Error()))
+	case len(circuits) == 0:
+		fmt.Printf("    %s\n", style.Dim.Render("(no polecat agent beads)"))
+	default:
+		printCircuitTable(circuits, time.Now())
+	}
+
 	return nil
 }
 
+// printCircuitTable prints one line per polecat circuit for gt witness status.
+func printCircuitTable(circuits []witness.CircuitStatus, now time.Time) {
+	fmt.Printf("    %-14s %-10s %-9s %-14s %-14s %s\n", "Polecat", "Circuit", "Failures", "Hook", "Last failure", "Cooldown")
+	for _, c := range circuits {
+		failures := strconv.Itoa(c.FailureCount)
+		if c.TransientFailures > 0 {
+			failures += fmt.Sprintf(" (%dt)", c.TransientFailures)
+		}
+		hook := c.HookBead
+		if hook == "" {
+			hook = "-"
+		}
+		lastFailure := "-"
+		if c.LastFailureAt != nil {
+			lastFailure = formatDuration(now.Sub(*c.LastFailureAt)) + " ago"
+		}
+		cooldown := "-"
+		if c.CooldownExpiresAt != nil {
+			if remaining := c.CooldownExpiresAt.Sub(now); remaining > 0 {
+				cooldown = "ends in " + formatDuration(remaining)
+			} else {
+				cooldown = "expired"
+			}
+		}
+		// Pad before styling so the escape codes don't break alignment.
+		state := fmt.Sprintf("%-10s", c.State)
+		switch c.State {
+		case beads.CircuitOpen:
+			state = style.Error.Render(state)
+		case beads.CircuitHalfOpen:
+			state = style.Warning.Render(state)
+		}
+		fmt.Printf("    %-14s %s %-9s %-14s %-14s %s\n", c.Polecat, state, failures, hook, lastFailure, cooldown)
+		if c.Note != "" {
+			fmt.Printf("    %s\n", style.Dim.Render("  └ "+c.Note))
+		}
+		if c.ResourceUsage != "" {
+			resources := "  └ " + c.ResourceUsage
+			if c.ResourceStrikes > 0 {
+				resources += fmt.Sprintf(" (over budget %dx)", c.ResourceStrikes)
+			}
+			fmt.Printf("    %s\n", style.Dim.Render(resources))
+		}
+		if c.StallState != "" && c.OutputChangedAt != nil {
+			stalled := fmt.Sprintf("  └ output frozen %s, %s", formatDuration(now.Sub(*c.OutputChangedAt)), c.StallState)
+			fmt.Printf("    %s\n", style.Warning.Render(stalled))
+		}
+		if c.State == beads.CircuitHalfOpen && c.SuccessStreak > 0 {
+			fmt.Printf("    %s\n", style.Dim.Render(fmt.Sprintf("  └ %d probe(s) landed in a row", c.SuccessStreak)))
+		}
+		if c.ProbationUntil != nil {
+			fmt.Printf("    %s\n", style.Dim.Render("  └ on probation for "+formatDuration(c.ProbationUntil.Sub(now))))
+		}
+		if c.Quarantine != "" {
+			quarantined := "  └ quarantined: " + c.Quarantine
+			if c.QuarantinedAt != nil {
+				quarantined += fmt.Sprintf(" (%s ago)", formatDuration(now.Sub(*c.QuarantinedAt)))
+			}
+			fmt.Printf("    %s\n", style.Error.Render(quarantined))
+		}
+	}
+}
+
 // witnessSessionName returns the tmux session name for a rig's witness.
 func witnessSessionName(rigName string) string {
 	return session.WitnessSessionName(session.PrefixFor(rigName))
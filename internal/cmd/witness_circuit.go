@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+var witnessCircuitReason string
+
+var witnessCircuitCmd = &cobra.Command{
+	Use:   "circuit",
+	Short: "Reset or trip a polecat's circuit breaker by hand",
+	Long: `Override a polecat's circuit breaker.
+
+Each override is recorded in the agent bead's circuit_note (who, when, why)
+and in the town's audit event log.
+
+Examples:
+  gt witness circuit reset gastown/nux
+  gt witness circuit trip gastown/nux --reason "pushing to the wrong branch"`,
+	RunE: requireSubcommand,
+}
+
+var witnessCircuitResetCmd = &cobra.Command{
+	Use:   "reset <rig>/<polecat>",
+	Short: "Close a polecat's circuit and clear its failures",
+	Long: `Close a polecat's circuit and clear its failure and trip counts.
+
+Use this to unblock a polecat whose circuit tripped by mistake, e.g. during
+a provider outage that was counted against it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessCircuitReset,
+}
+
+var witnessCircuitTripCmd = &cobra.Command{
+	Use:   "trip <rig>/<polecat> --reason <why>",
+	Short: "Open a polecat's circuit to take it out of rotation",
+	Long: `Open a polecat's circuit, taking it out of rotation.
+
+The next 'gt witness sweep' treats it like any tripped circuit: its hooked
+work is requeued through the Mayor, and it is nuked if clean or
+quarantined and escalated if not. The circuit goes half_open after the cooldown.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessCircuitTrip,
+}
+
+func init() {
+	witnessCircuitResetCmd.Flags().StringVar(&witnessCircuitReason, "reason", "", "Why the circuit is being reset")
+	witnessCircuitTripCmd.Flags().StringVar(&witnessCircuitReason, "reason", "", "Why the circuit is being tripped (required)")
+	_ = witnessCircuitTripCmd.MarkFlagRequired("reason")
+
+	witnessCircuitCmd.AddCommand(witnessCircuitResetCmd)
+	witnessCircuitCmd.AddCommand(witnessCircuitTripCmd)
+	witnessCmd.AddCommand(witnessCircuitCmd)
+}
+
+func runWitnessCircuitReset(cmd *cobra.Command, args []string) error {
+	rigName, polecatName, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	actor := detectActor()
+	if err := witness.ResetCircuit(r.Path, rigName, polecatName, actor, witnessCircuitReason); err != nil {
+		return err
+	}
+	_ = events.LogAudit(events.TypeCircuitReset, actor, events.CircuitPayload(rigName, polecatName, witnessCircuitReason))
+
+	fmt.Printf("%s Circuit closed for %s/%s; failures cleared\n", style.Bold.Render("✓"), rigName, polecatName)
+	return nil
+}
+
+func runWitnessCircuitTrip(cmd *cobra.Command, args []string) error {
+	rigName, polecatName, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	actor := detectActor()
+	fields, err := witness.TripCircuit(r.Path, rigName, polecatName, actor, witnessCircuitReason)
+	if err != nil {
+		return err
+	}
+	_ = events.LogAudit(events.TypeCircuitTrip, actor, events.CircuitPayload(rigName, polecatName, witnessCircuitReason))
+
+	fmt.Printf("%s Circuit opened for %s/%s (trip %d)\n", style.Bold.Render("⚡"), rigName, polecatName, fields.TripCount)
+	fmt.Printf("  %s\n", style.Dim.Render("The next 'gt witness sweep "+rigName+"' requeues its work and nukes or quarantines it."))
+	return nil
+}
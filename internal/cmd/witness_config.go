@@ -0,0 +1,457 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+var witnessConfigJSON bool
+
+var witnessConfigCmd = &cobra.Command{
+	Use:   "config <rig>",
+	Short: "View and update a rig's Witness circuit breaker and escalation settings",
+	Long: `View and update the Witness circuit breaker, resource budget, stall
+detection and escalation settings for a rig.
+
+The settings live under "witness.circuit_breaker",
+"witness.resource_budget", "witness.stall_detection" and
+"witness.escalation" in the rig's settings/config.json.
+
+Circuit breaker keys:
+  max_failures         Failures that open a polecat's circuit (default 3)
+  cooldown_period      How long an open circuit stays open before the
+                       polecat may take a probe assignment (default 30m);
+                       doubles with each further trip
+  max_cooldown_period  Cap on the doubled cooldown (default 8h)
+  max_bead_failures    Non-transient failures, by any polecats, after which
+                       a work bead is blocked as likely broken instead of
+                       requeued (default 3)
+  remediation_hook     Command run on a tripped polecat before it is nuked
+                       (from the rig directory, with GT_* variables
+                       describing the polecat); exit 2 vetoes the nuke
+                       and escalates instead (default: none)
+  remediation_hook_timeout
+                       How long the hook may run (default 5m)
+  max_actions_per_sweep
+                       Open circuits one sweep acts on (requeue, nuke,
+                       escalate); the rest wait for the next sweep
+                       (default 10)
+  action_pacing        Pause between those actions (default 2s)
+  success_streak       Probe assignments a half-open polecat must land in
+                       a row before its circuit closes (default 2)
+  probation_period     How long a polecat stays on probation once its
+                       circuit closes again; its first failure in that
+                       window reopens the circuit (default 2h)
+
+Resource budget keys (enforced by the daemon's polecat_resources patrol,
+which samples each polecat session's processes):
+  max_cpu_percent      CPU a session may use, in percent of one core
+                       (default: no budget)
+  max_rss_mb           Resident memory a session may use, in MB
+                       (default: no budget)
+  max_fds              Open file descriptors a session may hold
+                       (default: no budget)
+  sustain              Consecutive samples over budget before the Witness
+                       acts (default 3)
+  action               trip (open the polecat's circuit) or escalate
+                       (mail the Mayor) (default trip)
+
+Stall detection keys (see 'gt witness stalls'):
+  stall_timeout        How long a working polecat's output may stay
+                       unchanged before it is nudged (default 20m)
+  stall_nudge_grace    How long after the nudge it may stay unchanged
+                       before the Witness acts (default 10m)
+  stall_lines          Trailing pane lines fingerprinted (default 40)
+  stall_action         trip (open the polecat's circuit) or escalate
+                       (mail the Mayor) (default trip)
+
+Escalation keys (route CIRCUIT_TRIPPED, BROKEN_WORK, RESOURCE_BUDGET and
+POLECAT_STALLED; WORK_REQUEUE always goes to the Mayor):
+  escalation_to        Mail address to escalate to, e.g. a rig overseer
+                       (default mayor/)
+  escalation_priority  low, normal, high or urgent (default: each
+                       escalation's own)
+  escalation_webhook   URL that also receives each escalation as a JSON
+                       POST, e.g. an on-call pager (default: none)
+
+Unset keys use the defaults. The Witness reads the file on each sweep;
+no restart needed.
+
+Examples:
+  gt witness config gastown                       # Show effective values
+  gt witness config set gastown max_failures 5
+  gt witness config set gastown cooldown_period 2h
+  gt witness config set gastown remediation_hook ./scripts/salvage.sh
+  gt witness config set gastown max_rss_mb 4096
+  gt witness config set gastown stall_timeout 45m
+  gt witness config set gastown escalation_to gastown/crew/overseer
+  gt witness config unset gastown max_failures    # Back to the default`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessConfigShow,
+}
+
+var witnessConfigSetCmd = &cobra.Command{
+	Use:   "set <rig> <key> <value>",
+	Short: "Set a Witness setting for a rig",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateWitnessConfig(args[0], args[1], args[2])
+	},
+}
+
+var witnessConfigUnsetCmd = &cobra.Command{
+	Use:   "unset <rig> <key>",
+	Short: "Reset a Witness setting for a rig to its default",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateWitnessConfig(args[0], args[1], "")
+	},
+}
+
+func init() {
+	witnessConfigCmd.Flags().BoolVar(&witnessConfigJSON, "json", false, "Output as JSON")
+	witnessConfigCmd.AddCommand(witnessConfigSetCmd)
+	witnessConfigCmd.AddCommand(witnessConfigUnsetCmd)
+	witnessCmd.AddCommand(witnessConfigCmd)
+}
+
+// witnessConfigValue is one effective setting in 'gt witness config' output.
+type witnessConfigValue struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"` // "file" or "default"
+}
+
+func runWitnessConfigShow(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	settingsPath := config.RigSettingsPath(r.Path)
+	ws, err := loadWitnessSettings(settingsPath)
+	if err != nil {
+		return err
+	}
+	s, rb, sd, es := ws.CircuitBreaker, ws.ResourceBudget, ws.StallDetection, ws.Escalation
+	cfg, err := witness.CircuitBreakerConfigFrom(s)
+	if err != nil {
+		return err
+	}
+	budget, err := witness.ResourceBudgetConfigFrom(rb)
+	if err != nil {
+		return err
+	}
+	stall, err := witness.StallDetectionConfigFrom(sd)
+	if err != nil {
+		return err
+	}
+	policy, err := witness.EscalationPolicyFrom(es)
+	if err != nil {
+		return err
+	}
+
+	source := func(set bool) string {
+		if set {
+			return "file"
+		}
+		return "default"
+	}
+	values := []witnessConfigValue{
+		{Key: "max_failures", Value: strconv.Itoa(cfg.MaxFailures), Source: source(s != nil && s.MaxFailures > 0)},
+		{Key: "cooldown_period", Value: cfg.CooldownPeriod.String(), Source: source(s != nil && s.CooldownPeriod != "")},
+		{Key: "max_cooldown_period", Value: cfg.MaxCooldownPeriod.String(), Source: source(s != nil && s.MaxCooldownPeriod != "")},
+		{Key: "max_bead_failures", Value: strconv.Itoa(cfg.MaxBeadFailures), Source: source(s != nil && s.MaxBeadFailures > 0)},
+		{Key: "remediation_hook", Value: hookOrNone(cfg.RemediationHook), Source: source(s != nil && s.RemediationHook != "")},
+		{Key: "remediation_hook_timeout", Value: cfg.RemediationHookTimeout.String(), Source: source(s != nil && s.RemediationHookTimeout != "")},
+		{Key: "max_actions_per_sweep", Value: strconv.Itoa(cfg.MaxActionsPerSweep), Source: source(s != nil && s.MaxActionsPerSweep > 0)},
+		{Key: "action_pacing", Value: cfg.ActionPacing.String(), Source: source(s != nil && s.ActionPacing != "")},
+		{Key: "success_streak", Value: strconv.Itoa(cfg.SuccessStreak), Source: source(s != nil && s.SuccessStreak > 0)},
+		{Key: "probation_period", Value: cfg.ProbationPeriod.String(), Source: source(s != nil && s.ProbationPeriod != "")},
+		{Key: "max_cpu_percent", Value: budgetOrNone(budget.MaxCPUPercent), Source: source(rb != nil && rb.MaxCPUPercent > 0)},
+		{Key: "max_rss_mb", Value: budgetOrNone(float64(budget.MaxRSSBytes >> 20)), Source: source(rb != nil && rb.MaxRSSMB > 0)},
+		{Key: "max_fds", Value: budgetOrNone(float64(budget.MaxFDs)), Source: source(rb != nil && rb.MaxFDs > 0)},
+		{Key: "sustain", Value: strconv.Itoa(budget.Sustain), Source: source(rb != nil && rb.Sustain > 0)},
+		{Key: "action", Value: budget.Action, Source: source(rb != nil && rb.Action != "")},
+		{Key: "stall_timeout", Value: stall.Timeout.String(), Source: source(sd != nil && sd.Timeout != "")},
+		{Key: "stall_nudge_grace", Value: stall.NudgeGrace.String(), Source: source(sd != nil && sd.NudgeGrace != "")},
+		{Key: "stall_lines", Value: strconv.Itoa(stall.Lines), Source: source(sd != nil && sd.Lines > 0)},
+		{Key: "stall_action", Value: stall.Action, Source: source(sd != nil && sd.Action != "")},
+		{Key: "escalation_to", Value: policy.Target(), Source: source(es != nil && es.To != "")},
+		{Key: "escalation_priority", Value: priorityOrDefault(policy.Priority), Source: source(es != nil && es.Priority != "")},
+		{Key: "escalation_webhook", Value: webhookOrNone(policy.Webhook), Source: source(es != nil && es.Webhook != "")},
+	}
+
+	if witnessConfigJSON {
+		return outputJSON(values)
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render("Witness settings: "+settingsPath))
+	fmt.Printf("  %-24s %-10s %s\n", "Key", "Value", "Source")
+	for _, v := range values {
+		src := v.Source
+		if src == "default" {
+			src = style.Dim.Render(src)
+		}
+		fmt.Printf("  %-24s %-10s %s\n", v.Key, v.Value, src)
+	}
+	return nil
+}
+
+// loadWitnessSettings returns the rig's witness settings, empty when the
+// file or section doesn't exist.
+func loadWitnessSettings(settingsPath string) (*config.WitnessConfig, error) {
+	settings, err := config.LoadRigSettings(settingsPath)
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return &config.WitnessConfig{}, nil
+		}
+		return nil, fmt.Errorf("loading settings: %w", err)
+	}
+	if settings.Witness == nil {
+		return &config.WitnessConfig{}, nil
+	}
+	return settings.Witness, nil
+}
+
+// updateWitnessConfig sets key (or resets it when value is empty) in the
+// rig's settings file. An invalid value is refused before anything is saved.
+func updateWitnessConfig(rigName, key, value string) error {
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	settingsPath := config.RigSettingsPath(r.Path)
+
+	settings, err := config.LoadRigSettings(settingsPath)
+	if err != nil {
+		if !errors.Is(err, config.ErrNotFound) {
+			return fmt.Errorf("loading settings: %w", err)
+		}
+		settings = config.NewRigSettings()
+	}
+	if settings.Witness == nil {
+		settings.Witness = &config.WitnessConfig{}
+	}
+	if settings.Witness.CircuitBreaker == nil {
+		settings.Witness.CircuitBreaker = &config.CircuitBreakerSettings{}
+	}
+	if settings.Witness.ResourceBudget == nil {
+		settings.Witness.ResourceBudget = &config.ResourceBudgetSettings{}
+	}
+	if settings.Witness.StallDetection == nil {
+		settings.Witness.StallDetection = &config.StallDetectionSettings{}
+	}
+	if settings.Witness.Escalation == nil {
+		settings.Witness.Escalation = &config.WitnessEscalationSettings{}
+	}
+	cb, rb, sd := settings.Witness.CircuitBreaker, settings.Witness.ResourceBudget, settings.Witness.StallDetection
+	es := settings.Witness.Escalation
+
+	switch key {
+	case "max_failures", "max_bead_failures", "max_actions_per_sweep", "success_streak":
+		n := 0
+		if value != "" {
+			if n, err = strconv.Atoi(value); err != nil || n <= 0 {
+				return fmt.Errorf("invalid %s %q: must be a positive integer", key, value)
+			}
+		}
+		switch key {
+		case "max_failures":
+			cb.MaxFailures = n
+		case "max_bead_failures":
+			cb.MaxBeadFailures = n
+		case "success_streak":
+			cb.SuccessStreak = n
+		default:
+			cb.MaxActionsPerSweep = n
+		}
+	case "cooldown_period", "max_cooldown_period", "remediation_hook_timeout", "action_pacing", "probation_period", "stall_timeout", "stall_nudge_grace":
+		if value != "" {
+			if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+				return fmt.Errorf("invalid %s %q: must be a positive duration (e.g. 30m)", key, value)
+			}
+		}
+		switch key {
+		case "cooldown_period":
+			cb.CooldownPeriod = value
+		case "max_cooldown_period":
+			cb.MaxCooldownPeriod = value
+		case "stall_timeout":
+			sd.Timeout = value
+		case "stall_nudge_grace":
+			sd.NudgeGrace = value
+		case "action_pacing":
+			cb.ActionPacing = value
+		case "probation_period":
+			cb.ProbationPeriod = value
+		default:
+			cb.RemediationHookTimeout = value
+		}
+	case "remediation_hook":
+		cb.RemediationHook = value
+	case "max_cpu_percent":
+		pct := 0.0
+		if value != "" {
+			if pct, err = strconv.ParseFloat(value, 64); err != nil || pct <= 0 {
+				return fmt.Errorf("invalid %s %q: must be a positive number", key, value)
+			}
+		}
+		rb.MaxCPUPercent = pct
+	case "max_rss_mb", "max_fds", "sustain", "stall_lines":
+		n := 0
+		if value != "" {
+			if n, err = strconv.Atoi(value); err != nil || n <= 0 {
+				return fmt.Errorf("invalid %s %q: must be a positive integer", key, value)
+			}
+		}
+		switch key {
+		case "max_rss_mb":
+			rb.MaxRSSMB = n
+		case "max_fds":
+			rb.MaxFDs = n
+		case "stall_lines":
+			sd.Lines = n
+		default:
+			rb.Sustain = n
+		}
+	case "action", "stall_action":
+		if value != "" && value != config.WitnessActionTrip && value != config.WitnessActionEscalate {
+			return fmt.Errorf("invalid %s %q: must be %s or %s", key, value, config.WitnessActionTrip, config.WitnessActionEscalate)
+		}
+		if key == "action" {
+			rb.Action = value
+		} else {
+			sd.Action = value
+		}
+	case "escalation_to", "escalation_priority", "escalation_webhook":
+		updated := *es
+		switch key {
+		case "escalation_to":
+			updated.To = value
+		case "escalation_priority":
+			updated.Priority = value
+		default:
+			updated.Webhook = value
+		}
+		if err := config.ValidateWitnessEscalationSettings(&updated); err != nil {
+			return err
+		}
+		*es = updated
+	default:
+		return fmt.Errorf("unknown key %q (valid: max_failures, cooldown_period, max_cooldown_period, max_bead_failures, remediation_hook, remediation_hook_timeout, max_actions_per_sweep, action_pacing, success_streak, probation_period, max_cpu_percent, max_rss_mb, max_fds, sustain, action, stall_timeout, stall_nudge_grace, stall_lines, stall_action, escalation_to, escalation_priority, escalation_webhook)", key)
+	}
+
+	if *cb == (config.CircuitBreakerSettings{}) {
+		settings.Witness.CircuitBreaker = nil
+	}
+	if *rb == (config.ResourceBudgetSettings{}) {
+		settings.Witness.ResourceBudget = nil
+	}
+	if *sd == (config.StallDetectionSettings{}) {
+		settings.Witness.StallDetection = nil
+	}
+	if *es == (config.WitnessEscalationSettings{}) {
+		settings.Witness.Escalation = nil
+	}
+	if *settings.Witness == (config.WitnessConfig{}) {
+		settings.Witness = nil
+	}
+	if err := config.SaveRigSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("saving settings: %w", err)
+	}
+
+	cfg, _ := witness.CircuitBreakerConfigFrom(cb)
+	budget, _ := witness.ResourceBudgetConfigFrom(rb)
+	stall, _ := witness.StallDetectionConfigFrom(sd)
+	policy, _ := witness.EscalationPolicyFrom(es)
+	effective := strconv.Itoa(cfg.MaxFailures)
+	switch key {
+	case "cooldown_period":
+		effective = cfg.CooldownPeriod.String()
+	case "max_cooldown_period":
+		effective = cfg.MaxCooldownPeriod.String()
+	case "max_bead_failures":
+		effective = strconv.Itoa(cfg.MaxBeadFailures)
+	case "remediation_hook":
+		effective = hookOrNone(cfg.RemediationHook)
+	case "remediation_hook_timeout":
+		effective = cfg.RemediationHookTimeout.String()
+	case "max_actions_per_sweep":
+		effective = strconv.Itoa(cfg.MaxActionsPerSweep)
+	case "action_pacing":
+		effective = cfg.ActionPacing.String()
+	case "success_streak":
+		effective = strconv.Itoa(cfg.SuccessStreak)
+	case "probation_period":
+		effective = cfg.ProbationPeriod.String()
+	case "max_cpu_percent":
+		effective = budgetOrNone(budget.MaxCPUPercent)
+	case "max_rss_mb":
+		effective = budgetOrNone(float64(budget.MaxRSSBytes >> 20))
+	case "max_fds":
+		effective = budgetOrNone(float64(budget.MaxFDs))
+	case "sustain":
+		effective = strconv.Itoa(budget.Sustain)
+	case "action":
+		effective = budget.Action
+	case "stall_timeout":
+		effective = stall.Timeout.String()
+	case "stall_nudge_grace":
+		effective = stall.NudgeGrace.String()
+	case "stall_lines":
+		effective = strconv.Itoa(stall.Lines)
+	case "stall_action":
+		effective = stall.Action
+	case "escalation_to":
+		effective = policy.Target()
+	case "escalation_priority":
+		effective = priorityOrDefault(policy.Priority)
+	case "escalation_webhook":
+		effective = webhookOrNone(policy.Webhook)
+	}
+	if value == "" {
+		fmt.Printf("%s %s reset to default (%s) for rig %s\n", style.Bold.Render("✓"), key, effective, rigName)
+	} else {
+		fmt.Printf("%s %s = %s for rig %s\n", style.Bold.Render("✓"), key, effective, rigName)
+	}
+	return nil
+}
+
+func hookOrNone(hook string) string {
+	if hook == "" {
+		return "none"
+	}
+	return hook
+}
+
+func budgetOrNone(budget float64) string {
+	if budget <= 0 {
+		return "none"
+	}
+	return strconv.FormatFloat(budget, 'f', -1, 64)
+}
+
+func priorityOrDefault(p mail.Priority) string {
+	if p == "" {
+		return "default"
+	}
+	return string(p)
+}
+
+// webhookOrNone shows a webhook URL without its path, which usually
+// carries its secret.
+func webhookOrNone(url string) string {
+	if url == "" {
+		return "none"
+	}
+	return deacon.RedactURL(url)
+}
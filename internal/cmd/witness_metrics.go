@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+var (
+	witnessMetricsJSON  bool
+	witnessMetricsSince string
+)
+
+var witnessMetricsCmd = &cobra.Command{
+	Use:   "metrics <rig>",
+	Short: "Show circuit breaker metrics from the rig's history",
+	Long: `Summarize a rig's circuit breaker history.
+
+Every trip, half_open move, reset, requeue, quarantine and nuke is
+recorded in <rig>/.runtime/circuit_history.jsonl, along with the
+failures and landed work that drove them ('gt witness simulate' replays
+those). This command reports, over
+the --since window:
+  - trips per day
+  - mean failure count at a trip (failure trips only; manual, resource
+    budget and stall trips have no count)
+  - mean time from a trip to its work being requeued
+  - the work beads whose polecats tripped most often
+
+Use it to tune max_failures ('gt witness config'): many trips at the
+threshold with the same few beads point at broken work rather than
+broken polecats.
+
+Examples:
+  gt witness metrics gastown
+  gt witness metrics gastown --since 30d
+  gt witness metrics gastown --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessMetrics,
+}
+
+func init() {
+	witnessMetricsCmd.Flags().BoolVar(&witnessMetricsJSON, "json", false, "Output as JSON")
+	witnessMetricsCmd.Flags().StringVar(&witnessMetricsSince, "since", "7d", "Window to summarize (e.g. 24h, 7d)")
+	witnessCmd.AddCommand(witnessMetricsCmd)
+}
+
+func runWitnessMetrics(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	window, err := parseDuration(witnessMetricsSince)
+	if err != nil || window <= 0 {
+		return fmt.Errorf("invalid --since %q: use a duration like 24h or 7d", witnessMetricsSince)
+	}
+
+	events, err := witness.LoadCircuitHistory(r.Path)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	m := witness.ComputeCircuitMetrics(events, now.Add(-window), now)
+	if witnessMetricsJSON {
+		return outputJSON(m)
+	}
+
+	fmt.Printf("%s %s: circuit breaker metrics %s\n\n", style.Bold.Render("●"), rigName,
+		style.Dim.Render("(last "+witnessMetricsSince+")"))
+	if m.Trips == 0 && m.HalfOpens == 0 && m.Resets == 0 && m.Requeues == 0 && m.Nukes == 0 && m.Quarantines == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No circuit breaker events in this window"))
+		return nil
+	}
+	fmt.Printf("  Trips:                   %d (%.1f/day)\n", m.Trips, m.TripsPerDay)
+	fmt.Printf("  Half-open probes:        %d\n", m.HalfOpens)
+	fmt.Printf("  Resets:                  %d\n", m.Resets)
+	fmt.Printf("  Requeues:                %d\n", m.Requeues)
+	fmt.Printf("  Nukes:                   %d\n", m.Nukes)
+	fmt.Printf("  Quarantines:             %d\n", m.Quarantines)
+	if m.MeanFailuresBeforeTrip > 0 {
+		fmt.Printf("  Mean failures at trip:   %.1f\n", m.MeanFailuresBeforeTrip)
+	}
+	if m.MeanTripToRequeue > 0 {
+		fmt.Printf("  Mean trip to requeue:    %s\n", formatDuration(m.MeanTripToRequeue))
+	}
+	if len(m.TopBeads) > 0 {
+		fmt.Printf("\n  %s\n", style.Bold.Render("Top tripping beads:"))
+		for _, b := range m.TopBeads {
+			fmt.Printf("    %-20s %d trip(s)\n", b.Bead, b.Trips)
+		}
+	}
+	return nil
+}
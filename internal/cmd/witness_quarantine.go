@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+var (
+	witnessQuarantineJSON   bool
+	witnessQuarantineReason string
+)
+
+var witnessQuarantineCmd = &cobra.Command{
+	Use:   "quarantine",
+	Short: "List and resolve quarantined polecats",
+	Long: `Manage polecats held in quarantine.
+
+When 'gt witness sweep' can't nuke a tripped polecat without losing work
+(its worktree has uncommitted, stashed or unpushed changes), it
+quarantines it instead: the polecat's session is frozen, its worktree is
+kept, and the sweep leaves it alone until a human resolves it here. Its
+circuit stays open, so it gets no nudges or new work meanwhile.
+
+Examples:
+  gt witness quarantine list gastown
+  gt witness quarantine release gastown/nux --reason "pushed its branch by hand"
+  gt witness quarantine nuke gastown/nux`,
+	RunE: requireSubcommand,
+}
+
+var witnessQuarantineListCmd = &cobra.Command{
+	Use:   "list <rig>",
+	Short: "List a rig's quarantined polecats",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWitnessQuarantineList,
+}
+
+var witnessQuarantineReleaseCmd = &cobra.Command{
+	Use:   "release <rig>/<polecat>",
+	Short: "Resume a quarantined polecat with its circuit closed",
+	Long: `Resume a quarantined polecat.
+
+Its session's processes are continued, the quarantine is lifted and its
+circuit closed with its failures cleared, as by 'gt witness circuit reset'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessQuarantineRelease,
+}
+
+var witnessQuarantineNukeCmd = &cobra.Command{
+	Use:   "nuke <rig>/<polecat>",
+	Short: "Nuke a quarantined polecat, discarding its worktree",
+	Long: `Nuke a quarantined polecat.
+
+The polecat was quarantined because its worktree held work, so the nuke
+bypasses gt polecat nuke's safety checks: anything left in the worktree
+is lost. Recover what's worth keeping first.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessQuarantineNuke,
+}
+
+func init() {
+	witnessQuarantineListCmd.Flags().BoolVar(&witnessQuarantineJSON, "json", false, "Output as JSON")
+	witnessQuarantineReleaseCmd.Flags().StringVar(&witnessQuarantineReason, "reason", "", "Why the polecat is being released")
+	witnessQuarantineNukeCmd.Flags().StringVar(&witnessQuarantineReason, "reason", "", "Why the polecat is being nuked")
+
+	witnessQuarantineCmd.AddCommand(witnessQuarantineListCmd)
+	witnessQuarantineCmd.AddCommand(witnessQuarantineReleaseCmd)
+	witnessQuarantineCmd.AddCommand(witnessQuarantineNukeCmd)
+	witnessCmd.AddCommand(witnessQuarantineCmd)
+}
+
+func runWitnessQuarantineList(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	quarantined, err := witness.ListQuarantined(r.Path, rigName)
+	if err != nil {
+		return err
+	}
+	if witnessQuarantineJSON {
+		return outputJSON(quarantined)
+	}
+
+	fmt.Printf("%s %s: %d quarantined polecat(s)\n", style.Bold.Render("●"), rigName, len(quarantined))
+	for _, q := range quarantined {
+		fmt.Printf("  %s %s: %s", style.Warning.Render("⛔"), q.Polecat, q.Reason)
+		if q.QuarantinedAt != "" {
+			fmt.Print(style.Dim.Render(" (since " + q.QuarantinedAt + ")"))
+		}
+		fmt.Println()
+		fmt.Printf("    %s\n", style.Dim.Render(q.Worktree))
+	}
+	return nil
+}
+
+func runWitnessQuarantineRelease(cmd *cobra.Command, args []string) error {
+	rigName, polecatName, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	actor := detectActor()
+	if err := witness.ReleaseQuarantine(r.Path, rigName, polecatName, actor, witnessQuarantineReason); err != nil {
+		return err
+	}
+	_ = events.LogAudit(events.TypeQuarantineRelease, actor, events.CircuitPayload(rigName, polecatName, witnessQuarantineReason))
+
+	fmt.Printf("%s Released %s/%s from quarantine; circuit closed\n", style.Bold.Render("✓"), rigName, polecatName)
+	return nil
+}
+
+func runWitnessQuarantineNuke(cmd *cobra.Command, args []string) error {
+	rigName, polecatName, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	actor := detectActor()
+	if err := witness.NukeQuarantined(r.Path, rigName, polecatName, actor, witnessQuarantineReason); err != nil {
+		return err
+	}
+	_ = events.LogAudit(events.TypeQuarantineNuke, actor, events.CircuitPayload(rigName, polecatName, witnessQuarantineReason))
+
+	fmt.Printf("%s Nuked quarantined polecat %s/%s\n", style.Bold.Render("✓"), rigName, polecatName)
+	return nil
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+var (
+	witnessSimulateJSON        bool
+	witnessSimulateInput       string
+	witnessSimulateSince       string
+	witnessSimulateSweepEvery  time.Duration
+	witnessSimulateMaxFailures int
+	witnessSimulateCooldown    time.Duration
+	witnessSimulateMaxBead     int
+)
+
+var witnessSimulateCmd = &cobra.Command{
+	Use:   "simulate <rig>",
+	Short: "Replay recorded failures against the circuit breaker config",
+	Long: `Show what the circuit breaker would have done with a rig's recorded
+failures.
+
+The failures and landed work in the rig's circuit history
+(<rig>/.runtime/circuit_history.jsonl) are replayed, in memory, against
+the rig's current breaker settings, sweeping every --sweep-every as the
+Witness patrol would. Nothing is changed: every tripped polecat is assumed
+clean and nuked, and every requeue succeeds.
+
+Override settings with --max-failures, --cooldown and --max-bead-failures
+to try a change before making it with 'gt witness config'. The recorded
+and simulated trips, requeues and nukes are shown side by side.
+
+--input replays another file of the same format: one JSON object per line
+with time, event ("failure" or "success"), polecat, and for failures the
+bead and reason (transient, deterministic or unknown).
+
+Examples:
+  gt witness simulate gastown
+  gt witness simulate gastown --since 30d --max-failures 5
+  gt witness simulate gastown --input failures.jsonl --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessSimulate,
+}
+
+func init() {
+	witnessSimulateCmd.Flags().BoolVar(&witnessSimulateJSON, "json", false, "Output as JSON")
+	witnessSimulateCmd.Flags().StringVar(&witnessSimulateInput, "input", "", "Replay this events file instead of the rig's circuit history")
+	witnessSimulateCmd.Flags().StringVar(&witnessSimulateSince, "since", "7d", "Window of history to replay (e.g. 24h, 7d)")
+	witnessSimulateCmd.Flags().DurationVar(&witnessSimulateSweepEvery, "sweep-every", witness.DefaultSimulationSweepInterval, "Simulated interval between sweeps")
+	witnessSimulateCmd.Flags().IntVar(&witnessSimulateMaxFailures, "max-failures", 0, "Simulate with this max_failures")
+	witnessSimulateCmd.Flags().DurationVar(&witnessSimulateCooldown, "cooldown", 0, "Simulate with this cooldown_period")
+	witnessSimulateCmd.Flags().IntVar(&witnessSimulateMaxBead, "max-bead-failures", 0, "Simulate with this max_bead_failures")
+	witnessCmd.AddCommand(witnessSimulateCmd)
+}
+
+func runWitnessSimulate(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	window, err := parseDuration(witnessSimulateSince)
+	if err != nil || window <= 0 {
+		return fmt.Errorf("invalid --since %q: use a duration like 24h or 7d", witnessSimulateSince)
+	}
+
+	cfg, err := witness.LoadCircuitBreakerConfig(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading circuit breaker settings: %w", err)
+	}
+	if witnessSimulateMaxFailures > 0 {
+		cfg.MaxFailures = witnessSimulateMaxFailures
+	}
+	if witnessSimulateCooldown > 0 {
+		cfg.CooldownPeriod = witnessSimulateCooldown
+	}
+	if witnessSimulateMaxBead > 0 {
+		cfg.MaxBeadFailures = witnessSimulateMaxBead
+	}
+
+	var recorded []witness.CircuitEvent
+	if witnessSimulateInput != "" {
+		recorded, err = witness.LoadCircuitEvents(witnessSimulateInput)
+	} else {
+		recorded, err = witness.LoadCircuitHistory(r.Path)
+	}
+	if err != nil {
+		return err
+	}
+	since := time.Now().UTC().Add(-window)
+	var inputs []witness.CircuitEvent
+	for _, ev := range recorded {
+		if !ev.Time.Before(since) {
+			inputs = append(inputs, ev)
+		}
+	}
+
+	sim := witness.SimulateCircuitBreakers(rigName, cfg, inputs, witnessSimulateSweepEvery)
+	if witnessSimulateJSON {
+		return outputJSON(sim)
+	}
+
+	fmt.Printf("%s %s: replayed %d failure(s) and %d landing(s) %s\n",
+		style.Bold.Render("●"), rigName, sim.Failures, sim.Successes,
+		style.Dim.Render(fmt.Sprintf("(max_failures %d, cooldown %s, max_bead_failures %d, last %s)",
+			cfg.MaxFailures, cfg.CooldownPeriod, cfg.MaxBeadFailures, witnessSimulateSince)))
+	if sim.Failures == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No failures recorded in this window"))
+		return nil
+	}
+
+	actual := witness.ComputeCircuitMetrics(inputs, sim.Since, sim.Until)
+	fmt.Printf("\n  %-12s %9s %10s\n", "", "Recorded", "Simulated")
+	for _, row := range []struct {
+		name                string
+		recorded, simulated int
+	}{
+		{"Trips", actual.Trips, sim.Metrics.Trips},
+		{"Half-open", actual.HalfOpens, sim.Metrics.HalfOpens},
+		{"Resets", actual.Resets, sim.Metrics.Resets},
+		{"Requeues", actual.Requeues, sim.Metrics.Requeues},
+		{"Nukes", actual.Nukes, sim.Metrics.Nukes},
+	} {
+		fmt.Printf("  %-12s %9d %10d\n", row.name, row.recorded, row.simulated)
+	}
+
+	if len(sim.BrokenWork) > 0 {
+		fmt.Printf("\n  Would block as broken: %v\n", sim.BrokenWork)
+	}
+	if len(sim.Mail) > 0 {
+		kinds := make([]string, 0, len(sim.Mail))
+		for kind := range sim.Mail {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		fmt.Printf("\n  Mail the Witness would send:\n")
+		for _, kind := range kinds {
+			fmt.Printf("    %-16s %d\n", kind, sim.Mail[kind])
+		}
+	}
+	if sim.Deferred > 0 {
+		fmt.Printf("\n  %d circuit action(s) deferred by max_actions_per_sweep\n", sim.Deferred)
+	}
+
+	var trips []witness.CircuitEvent
+	for _, ev := range sim.Events {
+		if ev.Event == witness.CircuitEventTrip {
+			trips = append(trips, ev)
+		}
+	}
+	if len(trips) > 0 {
+		fmt.Printf("\n  %s\n", style.Bold.Render("Simulated trips:"))
+		for _, ev := range trips {
+			fmt.Printf("    %s %-14s %d failure(s)", ev.Time.Local().Format("2006-01-02 15:04"), ev.Polecat, ev.Failures)
+			if ev.Bead != "" {
+				fmt.Print(style.Dim.Render(" (" + ev.Bead + ")"))
+			}
+			fmt.Println()
+		}
+	}
+	return nil
+}
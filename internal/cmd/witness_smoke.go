@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/smokegate"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Witness smoke flags
+var (
+	witnessSmokeRequire bool
+	witnessSmokeReason  string
+	witnessSmokeStatus  bool
+	witnessSmokeTimeout time.Duration
+	witnessSmokeJSON    bool
+)
+
+var witnessSmokeCmd = &cobra.Command{
+	Use:   "smoke <rig>",
+	Short: "Run the smoke gate that re-enables a rig after an incident",
+	Long: `Run a rig's post-incident smoke gate.
+
+When a rig comes back from an incident (it is unparked or undocked), its
+smoke gate becomes required: new polecat spawns are refused and the
+refinery holds MRs in queue. The gate clones a fresh worktree of
+origin/<default-branch> and runs the rig's merge_queue setup_command,
+build_command and smoke_command (test_command if no smoke_command is set).
+A pass releases the rig; pass or fail, the result is recorded as an event
+and mailed to the Mayor.
+
+Unparking or undocking only requires the gate when the rig has smoke steps
+configured. Use --require to hold a rig by hand.
+
+Exits non-zero when the gate fails.
+
+Examples:
+  gt witness smoke gastown
+  gt witness smoke gastown --status
+  gt witness smoke gastown --require --reason "bad migration on main"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessSmoke,
+}
+
+func init() {
+	witnessSmokeCmd.Flags().BoolVar(&witnessSmokeRequire, "require", false, "Hold the rig until the gate passes, without running it")
+	witnessSmokeCmd.Flags().StringVar(&witnessSmokeReason, "reason", "required by hand", "Why the gate is required (with --require)")
+	witnessSmokeCmd.Flags().BoolVar(&witnessSmokeStatus, "status", false, "Show the gate state and last run")
+	witnessSmokeCmd.Flags().DurationVar(&witnessSmokeTimeout, "timeout", smokegate.DefaultTimeout, "Give up on the gate run after this long")
+	witnessSmokeCmd.Flags().BoolVar(&witnessSmokeJSON, "json", false, "Output as JSON")
+	witnessSmokeCmd.MarkFlagsMutuallyExclusive("require", "status")
+
+	witnessCmd.AddCommand(witnessSmokeCmd)
+}
+
+func runWitnessSmoke(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	if witnessSmokeStatus {
+		state, err := smokegate.Load(r.Path)
+		if err != nil {
+			return err
+		}
+		if witnessSmokeJSON {
+			return printSmokeGateJSON(state)
+		}
+		printSmokeGateStatus(rigName, state)
+		return nil
+	}
+
+	if witnessSmokeRequire {
+		if _, err := requireSmokeGate(rigName, r.Path, witnessSmokeReason); err != nil {
+			return err
+		}
+		fmt.Printf("%s %s held until '%s' passes\n", style.Bold.Render("⏸"), rigName, "gt witness smoke "+rigName)
+		return nil
+	}
+
+	if !witnessSmokeJSON {
+		fmt.Printf("Running smoke gate for %s on %s...\n", rigName, smokegate.DefaultRef(r.Path))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), witnessSmokeTimeout)
+	defer cancel()
+	result := smokegate.Run(ctx, r.Path, smokegate.DefaultRef(r.Path), smokegate.StepsForRig(r.Path))
+	wasRequired := smokegate.IsRequired(r.Path)
+	if err := smokegate.Record(r.Path, result); err != nil {
+		return fmt.Errorf("recording smoke gate result: %w", err)
+	}
+
+	actor := rigName + "/witness"
+	if result.Passed {
+		_ = events.LogFeed(events.TypeSmokeGatePassed, actor, events.SmokeGatePayload(rigName, "", result.Commit))
+	} else {
+		_ = events.LogFeed(events.TypeSmokeGateFailed, actor, events.SmokeGatePayload(rigName, result.Error, result.Commit))
+	}
+	sendMail(townRoot, "mayor/", smokeGateMailSubject(rigName, result), formatSmokeGateMail(rigName, wasRequired, result))
+
+	if witnessSmokeJSON {
+		state, err := smokegate.Load(r.Path)
+		if err != nil {
+			return err
+		}
+		if err := printSmokeGateJSON(state); err != nil {
+			return err
+		}
+	} else if result.Passed {
+		fmt.Printf("%s Smoke gate passed for %s at %s (%s)\n", style.Bold.Render("✓"), rigName, shortCommit(result.Commit), result.Duration)
+		if wasRequired {
+			fmt.Printf("  Spawns and merges re-enabled\n")
+		}
+	} else {
+		fmt.Printf("%s Smoke gate failed for %s: %s\n", style.Bold.Render("✗"), rigName, result.Error)
+		if result.Output != "" {
+			fmt.Println(style.Dim.Render(result.Output))
+		}
+		if smokegate.IsRequired(r.Path) {
+			fmt.Printf("  %s remains held; fix the default branch and rerun\n", rigName)
+		}
+	}
+
+	if !result.Passed {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// requireSmokeGate holds a rig until its smoke gate passes, recording an
+// event the first time. Returns whether the rig was newly held.
+func requireSmokeGate(rigName, rigPath, reason string) (bool, error) {
+	required, err := smokegate.Require(rigPath, reason)
+	if err != nil {
+		return false, fmt.Errorf("requiring smoke gate: %w", err)
+	}
+	if required {
+		_ = events.LogFeed(events.TypeSmokeGateRequired, rigName+"/witness", events.SmokeGatePayload(rigName, reason, ""))
+	}
+	return required, nil
+}
+
+// requireSmokeGateAfterIncident holds a rig coming back from an incident
+// (unpark, undock) when it has smoke steps configured. A rig without them
+// would stay held forever, so it is only warned about.
+func requireSmokeGateAfterIncident(rigName, rigPath, reason string) {
+	if len(smokegate.StepsForRig(rigPath)) == 0 {
+		return
+	}
+	required, err := requireSmokeGate(rigName, rigPath, reason)
+	if err != nil {
+		style.PrintWarning("could not require smoke gate: %v", err)
+		return
+	}
+	if required {
+		fmt.Printf("  Spawns and merges held until '%s' passes\n", style.Dim.Render("gt witness smoke "+rigName))
+	}
+}
+
+func printSmokeGateJSON(state *smokegate.State) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(state)
+}
+
+func printSmokeGateStatus(rigName string, state *smokegate.State) {
+	if state.Required {
+		fmt.Printf("%s %s is held until its smoke gate passes\n", style.Warning.Render("⏸"), rigName)
+		fmt.Printf("  Reason: %s\n", state.Reason)
+		fmt.Printf("  Since:  %s\n", state.Since.Local().Format("2006-01-02 15:04"))
+	} else {
+		fmt.Printf("%s %s is not held by its smoke gate\n", style.Success.Render("✓"), rigName)
+	}
+	if run := state.LastRun; run != nil {
+		outcome := "passed"
+		if !run.Passed {
+			outcome = "failed: " + run.Error
+		}
+		fmt.Printf("  Last run: %s at %s, %s\n", run.StartedAt.Local().Format("2006-01-02 15:04"), shortCommit(run.Commit), outcome)
+	}
+}
+
+func smokeGateMailSubject(rigName string, result *smokegate.Result) string {
+	if result.Passed {
+		return fmt.Sprintf("SMOKE_PASSED %s", rigName)
+	}
+	return fmt.Sprintf("SMOKE_FAILED %s", rigName)
+}
+
+// formatSmokeGateMail is the body of the mail sent to the Mayor after a run.
+func formatSmokeGateMail(rigName string, wasRequired bool, result *smokegate.Result) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Rig: %s\n", rigName)
+	fmt.Fprintf(&sb, "Ref: %s (%s)\n", result.Ref, shortCommit(result.Commit))
+	fmt.Fprintf(&sb, "Duration: %s\n", result.Duration)
+	switch {
+	case result.Passed && wasRequired:
+		sb.WriteString("\nSmoke gate passed. Spawns and merges are re-enabled.\n")
+	case result.Passed:
+		sb.WriteString("\nSmoke gate passed.\n")
+	default:
+		fmt.Fprintf(&sb, "\nSmoke gate failed: %s\n", result.Error)
+		if wasRequired {
+			sb.WriteString("The rig remains held: no spawns or merges until a run passes.\n")
+		}
+		if result.Output != "" {
+			fmt.Fprintf(&sb, "\nOutput (tail):\n%s\n", result.Output)
+		}
+	}
+	return sb.String()
+}
+
+// shortCommit abbreviates a commit hash for display.
+func shortCommit(commit string) string {
+	if commit == "" {
+		return "unknown commit"
+	}
+	if len(commit) > 8 {
+		return commit[:8]
+	}
+	return commit
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/smokegate"
+)
+
+func TestFormatSmokeGateMail(t *testing.T) {
+	passed := &smokegate.Result{Passed: true, Ref: "origin/main", Commit: "0123456789abcdef"}
+	body := formatSmokeGateMail("gastown", true, passed)
+	if !strings.Contains(body, "01234567") || !strings.Contains(body, "re-enabled") {
+		t.Errorf("pass body = %q", body)
+	}
+	if got := smokeGateMailSubject("gastown", passed); got != "SMOKE_PASSED gastown" {
+		t.Errorf("subject = %q", got)
+	}
+
+	failed := &smokegate.Result{Ref: "origin/main", Error: "build (make): exit status 2", Output: "undefined: foo"}
+	body = formatSmokeGateMail("gastown", true, failed)
+	for _, want := range []string{"unknown commit", "exit status 2", "remains held", "undefined: foo"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("fail body missing %q:\n%s", want, body)
+		}
+	}
+	if got := smokeGateMailSubject("gastown", failed); got != "SMOKE_FAILED gastown" {
+		t.Errorf("subject = %q", got)
+	}
+}
+
+func TestRequireSmokeGateAfterIncidentNeedsSteps(t *testing.T) {
+	rigPath := t.TempDir()
+	requireSmokeGateAfterIncident("gastown", rigPath, "rig unparked")
+	if smokegate.IsRequired(rigPath) {
+		t.Error("a rig without smoke steps must not be held: nothing could release it")
+	}
+}
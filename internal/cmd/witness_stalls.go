@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+var witnessStallsJSON bool
+
+var witnessStallsCmd = &cobra.Command{
+	Use:   "stalls <rig>",
+	Short: "Detect polecats whose session output has stopped changing",
+	Long: `Check a rig's working polecats for frozen output.
+
+Each pass fingerprints the last stall_lines lines of every working
+polecat's pane (spinners, ANSI escapes and elapsed-time counters are
+ignored) and records the fingerprint on its agent bead. A polecat whose
+fingerprint hasn't changed for stall_timeout is nudged once. If it is
+still unchanged stall_nudge_grace after the nudge, the Witness acts per
+stall_action (see 'gt witness config'):
+  - trip: opens the polecat's circuit, so the next 'gt witness sweep'
+    requeues its work and nukes it
+  - escalate: mails the Mayor POLECAT_STALLED, once
+
+Any change in output clears the stall. Polecats without hooked work or a
+live agent, and those whose circuit is already open, are skipped.
+
+Examples:
+  gt witness stalls gastown
+  gt witness stalls gastown --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessStalls,
+}
+
+func init() {
+	witnessStallsCmd.Flags().BoolVar(&witnessStallsJSON, "json", false, "Output as JSON")
+	witnessCmd.AddCommand(witnessStallsCmd)
+}
+
+func runWitnessStalls(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := witness.LoadStallDetectionConfig(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading stall detection settings: %w", err)
+	}
+
+	result := witness.CheckOutputStalls(r.Path, rigName, mail.NewRouter(townRoot), cfg)
+	if witnessStallsJSON {
+		return outputJSON(result)
+	}
+
+	fmt.Printf("%s %s: %d polecat(s) checked, %d stalled %s\n",
+		style.Bold.Render("●"), rigName, result.Checked, len(result.Stalled),
+		style.Dim.Render(fmt.Sprintf("(timeout %s, nudge grace %s)", cfg.Timeout, cfg.NudgeGrace)))
+	for _, s := range result.Stalled {
+		fmt.Printf("  %s %s frozen since %s", style.Bold.Render("⏸"), s.Polecat, s.FrozenSince)
+		if s.HookBead != "" {
+			fmt.Print(style.Dim.Render(" (" + s.HookBead + ")"))
+		}
+		fmt.Println()
+		switch {
+		case s.Nudged:
+			fmt.Println("    nudged")
+		case s.Tripped:
+			fmt.Println("    still frozen after nudge: circuit tripped")
+		case s.Escalated:
+			fmt.Println("    still frozen after nudge: escalated")
+		case s.State != "":
+			fmt.Printf("    %s\n", style.Dim.Render("already "+s.State))
+		}
+		if s.Error != "" {
+			fmt.Printf("    %s\n", style.Warning.Render(s.Error))
+		}
+	}
+	for _, e := range result.Errors {
+		style.PrintWarning("%s", e)
+	}
+	return nil
+}
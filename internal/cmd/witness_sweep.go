@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+var (
+	witnessSweepJSON     bool
+	witnessSweepDryRun   bool
+	witnessSweepWatch    bool
+	witnessSweepInterval time.Duration
+)
+
+var witnessSweepCmd = &cobra.Command{
+	Use:   "sweep <rig>",
+	Short: "Act on polecats whose circuit breaker has tripped",
+	Long: `Sweep a rig's polecat circuit breakers.
+
+Each polecat's agent bead counts its failures: dying or hanging with work
+hooked, or claiming a completion that didn't verify. Failures classified
+transient (rate limits, network timeouts) count half. When the count
+reaches the rig's max_failures (see 'gt witness config'), the circuit
+opens. For each open circuit the sweep:
+  - captures the polecat's pane, git status/diff and agent bead into
+    <town>/forensics/<rig>/<polecat>-<ts>.tar.gz
+  - resets the polecat's hooked work to open and mails the Mayor
+    WORK_REQUEUE so it goes to a different polecat, once per trip; the
+    bead records a correlation ID that its reassignment (sling) and
+    completion (done) report too
+  - or, if that work has itself failed max_bead_failures times across
+    polecats, blocks it and mails the Mayor BROKEN_WORK instead
+  - runs the rig's remediation_hook, if set, with the polecat described in
+    GT_* environment variables; exit code 2 vetoes the nuke until
+    'gt witness circuit reset'
+  - nukes the polecat if it is clean
+  - otherwise quarantines it (its session is frozen and its worktree
+    kept until 'gt witness quarantine release|nuke') and mails the Mayor
+    CIRCUIT_TRIPPED to recover it by hand
+
+BROKEN_WORK and CIRCUIT_TRIPPED go to the Mayor unless the rig's
+escalation settings route them elsewhere, or also to a webhook (see
+'gt witness config').
+
+Once an open circuit's cooldown_period has passed, the sweep moves it to
+half_open: the polecat may take probe assignments, one at a time. The
+cooldown doubles each time the same polecat trips again, up to
+max_cooldown_period. Once success_streak probes in a row have landed
+(MERGED) the circuit closes and the polecat goes on probation for
+probation_period; a failed probe, or a failure on probation, reopens it.
+A quarantined polecat's circuit stays open until it is resolved.
+
+A sweep acts on at most max_actions_per_sweep open circuits, pausing
+action_pacing between them, so a large town isn't nuked and the Mayor
+isn't flooded with mail all at once. The rest are listed as deferred and
+handled by the next sweep.
+
+Use --dry-run to preview: it lists the circuits that would trip, the work
+that would be requeued, the polecats that would be nuked, quarantined or
+escalated and the circuits that would go half_open, without changing
+anything. The remediation hook is not run.
+
+With --watch the sweep keeps running: it follows the rig's polecat agent
+beads and sweeps again as soon as a circuit opens or a polecat's hook is
+cleared, and every --interval regardless (for cooldowns that expire),
+until interrupted.
+
+Examples:
+  gt witness sweep gastown
+  gt witness sweep gastown --dry-run
+  gt witness sweep gastown --json
+  gt witness sweep gastown --watch --interval 10m`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessSweep,
+}
+
+func init() {
+	witnessSweepCmd.Flags().BoolVar(&witnessSweepJSON, "json", false, "Output as JSON")
+	witnessSweepCmd.Flags().BoolVarP(&witnessSweepDryRun, "dry-run", "n", false, "Show what the sweep would do without doing it")
+	witnessSweepCmd.Flags().BoolVar(&witnessSweepWatch, "watch", false, "Keep sweeping as polecat circuits open, until interrupted")
+	witnessSweepCmd.Flags().DurationVar(&witnessSweepInterval, "interval", 5*time.Minute, "With --watch, also sweep this often")
+	witnessCmd.AddCommand(witnessSweepCmd)
+}
+
+func runWitnessSweep(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := witness.LoadCircuitBreakerConfig(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading circuit breaker settings: %w", err)
+	}
+
+	escalateTo := "the Mayor"
+	if policy, err := witness.LoadEscalationPolicy(r.Path); err == nil && policy.To != "" {
+		escalateTo = policy.To
+	}
+
+	sweep := func() error {
+		var result *witness.CheckCircuitBreakersResult
+		if witnessSweepDryRun {
+			result = witness.PreviewCircuitBreakers(r.Path, rigName, cfg)
+		} else {
+			result = witness.CheckCircuitBreakers(r.Path, rigName, mail.NewRouter(townRoot), cfg)
+		}
+		if witnessSweepJSON {
+			return outputJSON(result)
+		}
+		printWitnessSweep(rigName, result, cfg, escalateTo)
+		return nil
+	}
+	if err := sweep(); err != nil || !witnessSweepWatch {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	bd := beads.NewWithBeadsDir(r.Path, beads.ResolveBeadsDir(r.Path))
+	changes, err := bd.Query().Role("polecat").Rig(rigName).Watch(ctx, 0)
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(witnessSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case change, ok := <-changes:
+			if !ok {
+				return nil
+			}
+			if !change.Became("circuit_state", beads.CircuitOpen) && !change.Cleared("hook_bead") {
+				continue
+			}
+			if !witnessSweepJSON {
+				fmt.Printf("\n%s %s changed (%s)\n", style.Dim.Render("↻"), change.ID, strings.Join(change.Fields, ", "))
+			}
+		}
+		if err := sweep(); err != nil {
+			return err
+		}
+	}
+}
+
+// printWitnessSweep prints a sweep's result for people.
+func printWitnessSweep(rigName string, result *witness.CheckCircuitBreakersResult, cfg witness.CircuitBreakerConfig, escalateTo string) {
+
+	// In a dry run every action is reported as what would happen.
+	would := func(done, preview string) string {
+		if result.DryRun {
+			return preview
+		}
+		return done
+	}
+
+	if result.DryRun {
+		fmt.Printf("%s\n", style.Dim.Render("Dry run: nothing will be changed"))
+	}
+	fmt.Printf("%s %s: %d failing polecat(s) checked, %d open circuit(s) %s\n",
+		style.Bold.Render("●"), rigName, result.Checked, result.Open,
+		style.Dim.Render(fmt.Sprintf("(max_failures %d, cooldown %s)", cfg.MaxFailures, cfg.CooldownPeriod)))
+	for _, tc := range result.Tripped {
+		fmt.Printf("  %s %s (%d failure(s), %d transient, trip %d)", style.Bold.Render("⚡"), tc.Polecat, tc.FailureCount, tc.Transient, tc.TripCount)
+		if tc.Tripped {
+			fmt.Print(would(" tripped", " would trip"))
+		}
+		fmt.Println()
+		if tc.Deferred {
+			fmt.Printf("    %s\n", style.Dim.Render("deferred to the next sweep (max_actions_per_sweep reached)"))
+			continue
+		}
+		if tc.Forensics != "" {
+			fmt.Printf("    forensics: %s\n", tc.Forensics)
+		}
+		if tc.Requeued {
+			fmt.Printf("    %s %s", would("requeued", "would requeue"), tc.HookBead)
+			if tc.CorrelationID != "" {
+				fmt.Print(style.Dim.Render(" (correlation " + tc.CorrelationID + ")"))
+			}
+			fmt.Println()
+		}
+		if tc.Deduplicated {
+			fmt.Printf("    %s already requeued for this trip (correlation %s)\n", tc.HookBead, tc.CorrelationID)
+		}
+		if tc.BrokenWork {
+			fmt.Printf("    %s %s as likely broken (%d failure(s) across polecats)\n",
+				would("blocked", "would block"), tc.HookBead, tc.WorkFailures)
+		}
+		if r := tc.Remediation; r != nil {
+			status := fmt.Sprintf("exit %d", r.ExitCode)
+			if r.Error != "" {
+				status = r.Error
+			}
+			fmt.Printf("    remediation hook: %s %s\n", r.Hook, style.Dim.Render("("+status+", "+r.Duration+")"))
+		}
+		if tc.NukeResult != "" {
+			fmt.Printf("    %s\n", tc.NukeResult)
+		}
+		if tc.Quarantine != "" && tc.NukeResult != "quarantined: "+tc.Quarantine {
+			// Quarantined this sweep, not held from an earlier one.
+			frozen := ""
+			if tc.Frozen {
+				frozen = ", session frozen"
+			}
+			fmt.Printf("    %s%s\n", would("quarantined", "would quarantine"), frozen)
+		}
+		if tc.Escalated {
+			fmt.Printf("    %s to %s\n", would("escalated", "would escalate"), escalateTo)
+		}
+		if tc.Error != "" {
+			fmt.Printf("    %s\n", style.Warning.Render(tc.Error))
+		}
+	}
+	if result.Deferred > 0 {
+		fmt.Printf("  %s %d circuit(s) deferred to the next sweep %s\n", style.Warning.Render("⏳"), result.Deferred,
+			style.Dim.Render(fmt.Sprintf("(acted on %d, max_actions_per_sweep %d)", result.Acted, cfg.MaxActionsPerSweep)))
+	}
+	for _, name := range result.HalfOpen {
+		fmt.Printf("  %s %s cooled down, circuit %s\n", style.Dim.Render("◐"), name, would("half_open", "would go half_open"))
+	}
+	for _, e := range result.Errors {
+		style.PrintWarning("%s", e)
+	}
+}
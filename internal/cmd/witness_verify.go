@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+// Witness verify flags
+var (
+	witnessVerifyIssue  string
+	witnessVerifyBranch string
+	witnessVerifyMR     string
+	witnessVerifyDryRun bool
+	witnessVerifyJSON   bool
+)
+
+var witnessVerifyCmd = &cobra.Command{
+	Use:   "verify <rig> <polecat>",
+	Short: "Verify a polecat's COMPLETED claim against the remote",
+	Long: `Verify the work a polecat claimed in POLECAT_DONE before marking it complete.
+
+Checks that:
+  - the branch exists on origin
+  - it has commits beyond the MR's target branch (the rig's default branch
+    when there is no MR)
+  - those commits, or the MR description, reference the issue ID
+
+When verification fails, the completion is demoted to failed: the MR is
+closed as rejected, the issue is reopened for re-dispatch, a cleanup wisp
+(state:verification-failed) keeps the polecat's worktree for recovery, and
+the Deacon gets a WORK_UNVERIFIED mail with the specifics. Use --dry-run to
+only report.
+
+Exits non-zero when the work did not verify.
+
+Examples:
+  gt witness verify gastown nux --issue gt-abc --branch polecat/nux/gt-abc --mr gt-mr-xyz
+  gt witness verify gastown nux --issue gt-abc --branch polecat/nux/gt-abc --dry-run --json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runWitnessVerify,
+}
+
+func init() {
+	witnessVerifyCmd.Flags().StringVar(&witnessVerifyIssue, "issue", "", "Issue the polecat completed (required)")
+	witnessVerifyCmd.Flags().StringVar(&witnessVerifyBranch, "branch", "", "Branch the polecat pushed (required)")
+	witnessVerifyCmd.Flags().StringVar(&witnessVerifyMR, "mr", "", "Merge request bead the polecat submitted")
+	witnessVerifyCmd.Flags().BoolVar(&witnessVerifyDryRun, "dry-run", false, "Report only; don't demote the completion")
+	witnessVerifyCmd.Flags().BoolVar(&witnessVerifyJSON, "json", false, "Output as JSON")
+	_ = witnessVerifyCmd.MarkFlagRequired("issue")
+	_ = witnessVerifyCmd.MarkFlagRequired("branch")
+
+	witnessCmd.AddCommand(witnessVerifyCmd)
+}
+
+func runWitnessVerify(cmd *cobra.Command, args []string) error {
+	rigName, polecatName := args[0], args[1]
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	payload := &witness.PolecatDonePayload{
+		PolecatName: polecatName,
+		Exit:        "COMPLETED",
+		IssueID:     witnessVerifyIssue,
+		MRID:        witnessVerifyMR,
+		Branch:      witnessVerifyBranch,
+	}
+	v, err := witness.VerifyPushedWork(r.Path, townRoot, rigName, payload)
+	if err != nil {
+		return fmt.Errorf("verification could not run: %w", err)
+	}
+
+	var wispID string
+	var demoteErr error
+	if !v.Verified() && !witnessVerifyDryRun {
+		wispID, demoteErr = witness.DemoteUnverifiedCompletion(r.Path, rigName, payload, v, mail.NewRouter(townRoot))
+	}
+
+	if witnessVerifyJSON {
+		out := struct {
+			*witness.WorkVerification
+			Verified    bool   `json:"verified"`
+			Demoted     bool   `json:"demoted"`
+			CleanupWisp string `json:"cleanup_wisp,omitempty"`
+		}{v, v.Verified(), !v.Verified() && !witnessVerifyDryRun, wispID}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+	} else if v.Verified() {
+		fmt.Printf("%s %s/%s: %s verified (%d commit(s) beyond %s, references %s)\n",
+			style.Bold.Render("✓"), rigName, polecatName, v.Branch, v.CommitsAhead, v.Base, witnessVerifyIssue)
+	} else {
+		fmt.Printf("%s %s/%s: completion did not verify\n", style.Bold.Render("✗"), rigName, polecatName)
+		for _, p := range v.Problems {
+			fmt.Printf("  - %s\n", p)
+		}
+		if witnessVerifyDryRun {
+			fmt.Printf("  %s\n", style.Dim.Render("Dry run: completion not demoted"))
+		} else {
+			fmt.Printf("  Demoted to failed; cleanup wisp %s, Deacon notified\n", wispID)
+		}
+	}
+
+	if demoteErr != nil {
+		return demoteErr
+	}
+	if !v.Verified() {
+		return NewSilentExit(1)
+	}
+	return nil
+}
@@ -119,6 +119,11 @@ type AgentPresetInfo struct {
 	// Empty means delay-based detection only.
 	ReadyPromptPrefix string `json:"ready_prompt_prefix,omitempty"`
 
+	// ReadyPattern is a regular expression matched against each pane line
+	// for readiness detection, for agents (or wrappers) whose prompt is not
+	// a fixed prefix. Takes precedence over ReadyPromptPrefix.
+	ReadyPattern string `json:"ready_pattern,omitempty"`
+
 	// ReadyDelayMs is the delay-based readiness fallback in milliseconds.
 	ReadyDelayMs int `json:"ready_delay_ms,omitempty"`
 
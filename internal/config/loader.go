@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
@@ -219,9 +220,136 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.Witness != nil && c.Witness.CircuitBreaker != nil {
+		if err := ValidateCircuitBreakerSettings(c.Witness.CircuitBreaker); err != nil {
+			return err
+		}
+	}
+	if c.Witness != nil && c.Witness.ResourceBudget != nil {
+		if err := ValidateResourceBudgetSettings(c.Witness.ResourceBudget); err != nil {
+			return err
+		}
+	}
+	if c.Witness != nil && c.Witness.StallDetection != nil {
+		if err := ValidateStallDetectionSettings(c.Witness.StallDetection); err != nil {
+			return err
+		}
+	}
+	if c.Witness != nil && c.Witness.Escalation != nil {
+		if err := ValidateWitnessEscalationSettings(c.Witness.Escalation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateCircuitBreakerSettings checks the witness circuit breaker settings.
+func ValidateCircuitBreakerSettings(c *CircuitBreakerSettings) error {
+	if c.MaxFailures < 0 {
+		return fmt.Errorf("invalid circuit_breaker.max_failures %d: must be non-negative", c.MaxFailures)
+	}
+	if c.MaxBeadFailures < 0 {
+		return fmt.Errorf("invalid circuit_breaker.max_bead_failures %d: must be non-negative", c.MaxBeadFailures)
+	}
+	if c.MaxActionsPerSweep < 0 {
+		return fmt.Errorf("invalid circuit_breaker.max_actions_per_sweep %d: must be non-negative", c.MaxActionsPerSweep)
+	}
+	if c.SuccessStreak < 0 {
+		return fmt.Errorf("invalid circuit_breaker.success_streak %d: must be non-negative", c.SuccessStreak)
+	}
+	for key, value := range map[string]string{
+		"cooldown_period":          c.CooldownPeriod,
+		"max_cooldown_period":      c.MaxCooldownPeriod,
+		"remediation_hook_timeout": c.RemediationHookTimeout,
+		"action_pacing":            c.ActionPacing,
+		"probation_period":         c.ProbationPeriod,
+	} {
+		if value == "" {
+			continue
+		}
+		dur, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid circuit_breaker.%s: %w", key, err)
+		}
+		if dur <= 0 {
+			return fmt.Errorf("circuit_breaker.%s must be positive, got %v", key, dur)
+		}
+	}
+	return nil
+}
+
+// ValidateResourceBudgetSettings checks the witness resource budget settings.
+func ValidateResourceBudgetSettings(c *ResourceBudgetSettings) error {
+	if c.MaxCPUPercent < 0 {
+		return fmt.Errorf("invalid resource_budget.max_cpu_percent %g: must be non-negative", c.MaxCPUPercent)
+	}
+	if c.MaxRSSMB < 0 {
+		return fmt.Errorf("invalid resource_budget.max_rss_mb %d: must be non-negative", c.MaxRSSMB)
+	}
+	if c.MaxFDs < 0 {
+		return fmt.Errorf("invalid resource_budget.max_fds %d: must be non-negative", c.MaxFDs)
+	}
+	if c.Sustain < 0 {
+		return fmt.Errorf("invalid resource_budget.sustain %d: must be non-negative", c.Sustain)
+	}
+	switch c.Action {
+	case "", WitnessActionTrip, WitnessActionEscalate:
+	default:
+		return fmt.Errorf("invalid resource_budget.action %q: must be %q or %q", c.Action, WitnessActionTrip, WitnessActionEscalate)
+	}
 	return nil
 }
 
+// ValidateStallDetectionSettings checks the witness stall detection settings.
+func ValidateStallDetectionSettings(c *StallDetectionSettings) error {
+	if c.Lines < 0 {
+		return fmt.Errorf("invalid stall_detection.lines %d: must be non-negative", c.Lines)
+	}
+	for key, value := range map[string]string{
+		"timeout":     c.Timeout,
+		"nudge_grace": c.NudgeGrace,
+	} {
+		if value == "" {
+			continue
+		}
+		dur, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid stall_detection.%s: %w", key, err)
+		}
+		if dur <= 0 {
+			return fmt.Errorf("stall_detection.%s must be positive, got %v", key, dur)
+		}
+	}
+	switch c.Action {
+	case "", WitnessActionTrip, WitnessActionEscalate:
+	default:
+		return fmt.Errorf("invalid stall_detection.action %q: must be %q or %q", c.Action, WitnessActionTrip, WitnessActionEscalate)
+	}
+	return nil
+}
+
+// ValidateWitnessEscalationSettings checks the witness escalation routing.
+func ValidateWitnessEscalationSettings(c *WitnessEscalationSettings) error {
+	if strings.ContainsAny(c.To, " \t\n") {
+		return fmt.Errorf("invalid escalation.to %q: must be a mail address (e.g., gastown/crew/overseer)", c.To)
+	}
+	switch c.Priority {
+	case "", "low", "normal", "high", "urgent":
+	default:
+		return fmt.Errorf("invalid escalation.priority %q: must be low, normal, high or urgent", c.Priority)
+	}
+	if c.Webhook != "" && !strings.HasPrefix(c.Webhook, "http://") && !strings.HasPrefix(c.Webhook, "https://") {
+		return fmt.Errorf("invalid escalation.webhook: must be an http(s) url")
+	}
+	return nil
+}
+
+// ValidateRigSettings checks rig settings as LoadRigSettings does, without
+// printing deprecation warnings.
+func ValidateRigSettings(c *RigSettings) error {
+	return validateRigSettings(c)
+}
+
 // ErrInvalidOnConflict indicates an invalid on_conflict strategy.
 var ErrInvalidOnConflict = errors.New("invalid on_conflict strategy")
 
@@ -1063,6 +1191,12 @@ func ValidateAgentConfig(agentName string, townSettings *TownSettings, rigSettin
 		return fmt.Errorf("agent %q binary %q not found in PATH", agentName, rc.Command)
 	}
 
+	if rc.Tmux != nil && rc.Tmux.ReadyPattern != "" {
+		if _, err := regexp.Compile(rc.Tmux.ReadyPattern); err != nil {
+			return fmt.Errorf("agent %q has an invalid ready_pattern: %w", agentName, err)
+		}
+	}
+
 	return nil
 }
 
@@ -1483,6 +1617,7 @@ func fillRuntimeDefaults(rc *RuntimeConfig) *RuntimeConfig {
 	if rc.Tmux != nil {
 		result.Tmux = &RuntimeTmuxConfig{
 			ReadyPromptPrefix: rc.Tmux.ReadyPromptPrefix,
+			ReadyPattern:      rc.Tmux.ReadyPattern,
 			ReadyDelayMs:      rc.Tmux.ReadyDelayMs,
 		}
 		// Deep copy ProcessNames slice
@@ -1752,6 +1887,8 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 		}
 	}
 
+	rc = withOverrideRuntimeArgs(rc, townRoot, envVars["GT_ROLE"])
+
 	// Copy env vars to avoid mutating caller map
 	resolvedEnv := make(map[string]string, len(envVars)+2)
 	for k, v := range envVars {
@@ -1806,6 +1943,22 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 	return cmd
 }
 
+// withOverrideRuntimeArgs appends runtime_args from the override hierarchy
+// (town → rig → role → agent) for the agent at address. rc is not modified.
+func withOverrideRuntimeArgs(rc *RuntimeConfig, townRoot, address string) *RuntimeConfig {
+	eff := ResolveAgentOverrides(townRoot, address)
+	if eff == nil {
+		return rc
+	}
+	extra := eff.GetStrings(OverrideRuntimeArgs)
+	if len(extra) == 0 {
+		return rc
+	}
+	rc = normalizeRuntimeConfig(rc)
+	rc.Args = append(append([]string(nil), rc.Args...), extra...)
+	return rc
+}
+
 // SanitizeAgentEnv clears environment variables that are known to break agent
 // startup when inherited from the parent shell/tmux environment.
 //
@@ -1923,6 +2076,8 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 		}
 	}
 
+	rc = withOverrideRuntimeArgs(rc, townRoot, envVars["GT_ROLE"])
+
 	// Copy env vars to avoid mutating caller map
 	resolvedEnv := make(map[string]string, len(envVars)+2)
 	for k, v := range envVars {
@@ -372,6 +372,127 @@ func TestRigSettingsValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid circuit_breaker",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Witness: &WitnessConfig{
+					CircuitBreaker: &CircuitBreakerSettings{MaxFailures: 5, CooldownPeriod: "2h"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative max_failures",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Witness: &WitnessConfig{
+					CircuitBreaker: &CircuitBreakerSettings{MaxFailures: -1},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative max_bead_failures",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Witness: &WitnessConfig{
+					CircuitBreaker: &CircuitBreakerSettings{MaxBeadFailures: -2},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid cooldown_period",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Witness: &WitnessConfig{
+					CircuitBreaker: &CircuitBreakerSettings{CooldownPeriod: "soon"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative max_actions_per_sweep",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Witness: &WitnessConfig{
+					CircuitBreaker: &CircuitBreakerSettings{MaxActionsPerSweep: -1},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative probation_period",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Witness: &WitnessConfig{
+					CircuitBreaker: &CircuitBreakerSettings{SuccessStreak: 2, ProbationPeriod: "-1h"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid remediation_hook_timeout",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Witness: &WitnessConfig{
+					CircuitBreaker: &CircuitBreakerSettings{RemediationHook: "./salvage.sh", RemediationHookTimeout: "a while"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid resource_budget",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Witness: &WitnessConfig{
+					ResourceBudget: &ResourceBudgetSettings{MaxCPUPercent: 150, MaxRSSMB: 4096, Action: "escalate"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid resource_budget action",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Witness: &WitnessConfig{
+					ResourceBudget: &ResourceBudgetSettings{MaxRSSMB: 4096, Action: "kill"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid stall_detection timeout",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Witness: &WitnessConfig{
+					StallDetection: &StallDetectionSettings{Timeout: "-5m"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid escalation webhook",
+			settings: &RigSettings{
+				Type:    "rig-settings",
+				Version: 1,
+				Witness: &WitnessConfig{
+					Escalation: &WitnessEscalationSettings{To: "gastown/crew/overseer", Webhook: "pager.example.com/hook"},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1456,6 +1577,33 @@ func TestValidateAgentConfig(t *testing.T) {
 			t.Errorf("unexpected error message: %v", err)
 		}
 	})
+
+	t.Run("custom agent with invalid ready_pattern", func(t *testing.T) {
+		townSettings := NewTownSettings()
+		townSettings.Agents = map[string]*RuntimeConfig{
+			"wrapped": {
+				Command: os.Args[0], // the test binary: always exists
+				Args:    []string{},
+				Tmux:    &RuntimeTmuxConfig{ReadyPattern: "^aider> ("},
+			},
+		}
+		err := ValidateAgentConfig("wrapped", townSettings, nil)
+		if err == nil || !strings.Contains(err.Error(), "ready_pattern") {
+			t.Errorf("expected ready_pattern error, got %v", err)
+		}
+	})
+}
+
+func TestFillRuntimeDefaults_KeepsReadyPattern(t *testing.T) {
+	t.Parallel()
+	rc := fillRuntimeDefaults(&RuntimeConfig{
+		Command: "aider",
+		Args:    []string{"--yes-always"},
+		Tmux:    &RuntimeTmuxConfig{ReadyPattern: `^(architect|code)?> $`},
+	})
+	if rc.Tmux == nil || rc.Tmux.ReadyPattern != `^(architect|code)?> $` {
+		t.Errorf("ReadyPattern lost: %+v", rc.Tmux)
+	}
 }
 
 func TestResolveRoleAgentConfig_FallsBackOnInvalidAgent(t *testing.T) {
@@ -0,0 +1,241 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Keys with built-in consumers in the override hierarchy.
+const (
+	// OverrideNukePolicy controls whether the witness may auto-nuke an
+	// orphaned polecat whose work is safely landed: "auto" (default) or
+	// "manual" (leave it for a human).
+	OverrideNukePolicy = "nuke_policy"
+
+	// OverrideRuntimeArgs are extra command-line flags appended to the agent
+	// runtime command (a string or list of strings).
+	OverrideRuntimeArgs = "runtime_args"
+)
+
+// Nuke policy values.
+const (
+	NukePolicyAuto   = "auto"
+	NukePolicyManual = "manual"
+)
+
+// OverrideDefaults are the compiled-in values at the bottom of the hierarchy.
+var OverrideDefaults = map[string]interface{}{
+	OverrideNukePolicy: NukePolicyAuto,
+}
+
+// Override layer names, lowest precedence first.
+const (
+	OverrideSourceSystem    = "system"
+	OverrideSourceTown      = "town"
+	OverrideSourceRig       = "rig"
+	OverrideSourceTownRole  = "town role"
+	OverrideSourceRigRole   = "rig role"
+	OverrideSourceTownAgent = "town agent"
+	OverrideSourceRigAgent  = "rig agent"
+)
+
+// ConfigOverrides holds key/value overrides at one scope (town or rig
+// settings/config.json). Defaults apply to every agent in scope; Roles and
+// Agents refine them for a role ("polecat", "witness", ...) or a single
+// agent address ("gastown/polecats/Toast", "mayor").
+type ConfigOverrides struct {
+	Defaults map[string]interface{}            `json:"defaults,omitempty"`
+	Roles    map[string]map[string]interface{} `json:"roles,omitempty"`
+	Agents   map[string]map[string]interface{} `json:"agents,omitempty"`
+}
+
+// OverrideTarget identifies the agent whose effective config is resolved.
+type OverrideTarget struct {
+	Address string // e.g. "gastown/polecats/Toast", "mayor"
+	Rig     string // empty for town-level agents
+	Role    string // simple role: "mayor", "polecat", "crew", ...
+}
+
+// ParseOverrideTarget derives the rig and role from an agent address.
+// The short polecat form "rig/name" is normalized to "rig/polecats/name".
+func ParseOverrideTarget(address string) (OverrideTarget, error) {
+	address = strings.Trim(strings.TrimSpace(address), "/")
+	parts := strings.Split(address, "/")
+	switch {
+	case address == "":
+		return OverrideTarget{}, fmt.Errorf("empty agent address")
+	case len(parts) == 1:
+		return OverrideTarget{Address: address, Role: address}, nil
+	case address == "deacon/boot":
+		return OverrideTarget{Address: address, Role: "boot"}, nil
+	case len(parts) == 2 && parts[1] != "witness" && parts[1] != "refinery":
+		address = parts[0] + "/polecats/" + parts[1]
+	case len(parts) > 3, len(parts) == 3 && parts[1] != "polecats" && parts[1] != "crew":
+		return OverrideTarget{}, fmt.Errorf("invalid agent address %q", address)
+	}
+	return OverrideTarget{Address: address, Rig: parts[0], Role: ExtractSimpleRole(address)}, nil
+}
+
+// OverrideLayer is one level of the hierarchy.
+type OverrideLayer struct {
+	Source string
+	Path   string // settings file the values came from; empty for system
+	Values map[string]interface{}
+}
+
+// EffectiveValue is a resolved key with its provenance.
+type EffectiveValue struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+	Path   string      `json:"path,omitempty"`
+	// Shadowed lists the lower layers that also set the key, highest first.
+	Shadowed []string `json:"shadowed,omitempty"`
+}
+
+// EffectiveConfig is the merged result for one agent.
+type EffectiveConfig struct {
+	Target OverrideTarget   `json:"target"`
+	Values []EffectiveValue `json:"values"`
+}
+
+// Get returns a resolved value, or nil if no layer sets key.
+func (e *EffectiveConfig) Get(key string) interface{} {
+	for _, v := range e.Values {
+		if v.Key == key {
+			return v.Value
+		}
+	}
+	return nil
+}
+
+// GetString returns a resolved string value, or "" if unset or not a string.
+func (e *EffectiveConfig) GetString(key string) string {
+	s, _ := e.Get(key).(string)
+	return s
+}
+
+// GetStrings returns a resolved value as a list of strings. A single string
+// is split on whitespace.
+func (e *EffectiveConfig) GetStrings(key string) []string {
+	switch v := e.Get(key).(type) {
+	case string:
+		return strings.Fields(v)
+	case []string:
+		return v
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// ResolveEffectiveConfig merges the override hierarchy for an agent:
+// system → town → rig → town role → rig role → town agent → rig agent.
+// Missing settings files contribute no values.
+func ResolveEffectiveConfig(townRoot string, target OverrideTarget) (*EffectiveConfig, error) {
+	townPath := TownSettingsPath(townRoot)
+	town, err := LoadOrCreateTownSettings(townPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	var rigSettings *RigSettings
+	var rigPath string
+	if target.Rig != "" {
+		rigPath = RigSettingsPath(filepath.Join(townRoot, target.Rig))
+		rigSettings, err = LoadRigSettings(rigPath)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("loading rig settings: %w", err)
+		}
+	}
+	return &EffectiveConfig{
+		Target: target,
+		Values: MergeOverrideLayers(OverrideLayers(town, townPath, rigSettings, rigPath, target)),
+	}, nil
+}
+
+// OverrideLayers returns the layers that apply to target, lowest precedence first.
+func OverrideLayers(town *TownSettings, townPath string, rig *RigSettings, rigPath string, target OverrideTarget) []OverrideLayer {
+	var townOv, rigOv *ConfigOverrides
+	if town != nil {
+		townOv = town.Overrides
+	}
+	if rig != nil {
+		rigOv = rig.Overrides
+	}
+
+	layers := []OverrideLayer{{Source: OverrideSourceSystem, Values: OverrideDefaults}}
+	add := func(source, path string, values map[string]interface{}) {
+		if len(values) > 0 {
+			layers = append(layers, OverrideLayer{Source: source, Path: path, Values: values})
+		}
+	}
+	if townOv != nil {
+		add(OverrideSourceTown, townPath, townOv.Defaults)
+	}
+	if rigOv != nil {
+		add(OverrideSourceRig, rigPath, rigOv.Defaults)
+	}
+	if townOv != nil {
+		add(OverrideSourceTownRole, townPath, townOv.Roles[target.Role])
+	}
+	if rigOv != nil {
+		add(OverrideSourceRigRole, rigPath, rigOv.Roles[target.Role])
+	}
+	if townOv != nil {
+		add(OverrideSourceTownAgent, townPath, townOv.Agents[target.Address])
+	}
+	if rigOv != nil {
+		add(OverrideSourceRigAgent, rigPath, rigOv.Agents[target.Address])
+	}
+	return layers
+}
+
+// MergeOverrideLayers resolves each key to the value of the highest layer
+// that sets it, recording the layers it shadows. Results are sorted by key.
+func MergeOverrideLayers(layers []OverrideLayer) []EffectiveValue {
+	merged := make(map[string]*EffectiveValue)
+	for _, layer := range layers {
+		for key, value := range layer.Values {
+			if prev, ok := merged[key]; ok {
+				prev.Shadowed = append([]string{prev.Source}, prev.Shadowed...)
+				prev.Value, prev.Source, prev.Path = value, layer.Source, layer.Path
+				continue
+			}
+			merged[key] = &EffectiveValue{Key: key, Value: value, Source: layer.Source, Path: layer.Path}
+		}
+	}
+
+	values := make([]EffectiveValue, 0, len(merged))
+	for _, v := range merged {
+		values = append(values, *v)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Key < values[j].Key })
+	return values
+}
+
+// ResolveAgentOverrides resolves the effective config for an agent address,
+// returning nil if it can't be resolved. Callers treat nil as "no overrides".
+func ResolveAgentOverrides(townRoot, address string) *EffectiveConfig {
+	if townRoot == "" {
+		return nil
+	}
+	target, err := ParseOverrideTarget(address)
+	if err != nil {
+		return nil
+	}
+	eff, err := ResolveEffectiveConfig(townRoot, target)
+	if err != nil {
+		return nil
+	}
+	return eff
+}
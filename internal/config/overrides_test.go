@@ -0,0 +1,146 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseOverrideTarget(t *testing.T) {
+	tests := []struct {
+		address string
+		want    OverrideTarget
+	}{
+		{"mayor", OverrideTarget{Address: "mayor", Role: "mayor"}},
+		{"deacon/boot", OverrideTarget{Address: "deacon/boot", Role: "boot"}},
+		{"gastown/witness", OverrideTarget{Address: "gastown/witness", Rig: "gastown", Role: "witness"}},
+		{"gastown/Toast", OverrideTarget{Address: "gastown/polecats/Toast", Rig: "gastown", Role: "polecat"}},
+		{"gastown/polecats/Toast/", OverrideTarget{Address: "gastown/polecats/Toast", Rig: "gastown", Role: "polecat"}},
+		{"gastown/crew/max", OverrideTarget{Address: "gastown/crew/max", Rig: "gastown", Role: "crew"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseOverrideTarget(tt.address)
+		if err != nil {
+			t.Errorf("ParseOverrideTarget(%q): %v", tt.address, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseOverrideTarget(%q) = %+v, want %+v", tt.address, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "gastown/bogus/x", "a/b/c/d"} {
+		if _, err := ParseOverrideTarget(bad); err == nil {
+			t.Errorf("ParseOverrideTarget(%q) should fail", bad)
+		}
+	}
+}
+
+func TestResolveEffectiveConfig_Precedence(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+
+	town := NewTownSettings()
+	town.Overrides = &ConfigOverrides{
+		Defaults: map[string]interface{}{"a": "town", "b": "town", "c": "town"},
+		Roles:    map[string]map[string]interface{}{"polecat": {"b": "town-role", "d": "town-role"}},
+		Agents:   map[string]map[string]interface{}{"gastown/polecats/Toast": {"d": "town-agent"}},
+	}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), town); err != nil {
+		t.Fatal(err)
+	}
+	rig := NewRigSettings()
+	rig.Overrides = &ConfigOverrides{
+		Defaults: map[string]interface{}{"a": "rig"},
+		Roles:    map[string]map[string]interface{}{"polecat": {"b": "rig-role"}, "witness": {"c": "rig-witness"}},
+		Agents:   map[string]map[string]interface{}{"gastown/polecats/Toast": {"e": "rig-agent"}},
+	}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), rig); err != nil {
+		t.Fatal(err)
+	}
+
+	target, _ := ParseOverrideTarget("gastown/Toast")
+	eff, err := ResolveEffectiveConfig(townRoot, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"a":                "rig",
+		"b":                "rig-role",
+		"c":                "town",
+		"d":                "town-agent",
+		"e":                "rig-agent",
+		OverrideNukePolicy: NukePolicyAuto,
+	}
+	for key, value := range want {
+		if got := eff.GetString(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+
+	for _, v := range eff.Values {
+		if v.Key == "b" {
+			if v.Source != OverrideSourceRigRole {
+				t.Errorf("b source = %q, want %q", v.Source, OverrideSourceRigRole)
+			}
+			wantShadowed := []string{OverrideSourceTownRole, OverrideSourceTown}
+			if !reflect.DeepEqual(v.Shadowed, wantShadowed) {
+				t.Errorf("b shadowed = %v, want %v", v.Shadowed, wantShadowed)
+			}
+		}
+	}
+}
+
+func TestResolveEffectiveConfig_TownLevelAgentWithoutSettings(t *testing.T) {
+	eff, err := ResolveEffectiveConfig(t.TempDir(), OverrideTarget{Address: "mayor", Role: "mayor"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eff.Values) != 1 || eff.Values[0].Source != OverrideSourceSystem {
+		t.Errorf("values = %+v, want only system defaults", eff.Values)
+	}
+}
+
+func TestEffectiveConfig_GetStrings(t *testing.T) {
+	eff := &EffectiveConfig{Values: []EffectiveValue{
+		{Key: "s", Value: "--model  opus"},
+		{Key: "l", Value: []interface{}{"--verbose", 3, "--x"}},
+	}}
+	if got := eff.GetStrings("s"); !reflect.DeepEqual(got, []string{"--model", "opus"}) {
+		t.Errorf("GetStrings(s) = %v", got)
+	}
+	if got := eff.GetStrings("l"); !reflect.DeepEqual(got, []string{"--verbose", "--x"}) {
+		t.Errorf("GetStrings(l) = %v", got)
+	}
+	if got := eff.GetStrings("missing"); got != nil {
+		t.Errorf("GetStrings(missing) = %v, want nil", got)
+	}
+}
+
+func TestBuildStartupCommand_AppendsOverrideRuntimeArgs(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+
+	town := NewTownSettings()
+	town.Overrides = &ConfigOverrides{
+		Roles: map[string]map[string]interface{}{"polecat": {OverrideRuntimeArgs: "--verbose"}},
+	}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), town); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), NewRigSettings()); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := BuildStartupCommand(map[string]string{"GT_ROLE": "gastown/polecats/Toast"}, rigPath, "")
+	if !strings.Contains(cmd, "--dangerously-skip-permissions") || !strings.HasSuffix(cmd, " --verbose") {
+		t.Errorf("polecat command = %q, want default args plus --verbose", cmd)
+	}
+
+	cmd = BuildStartupCommand(map[string]string{"GT_ROLE": "gastown/witness"}, rigPath, "")
+	if strings.Contains(cmd, "--verbose") {
+		t.Errorf("witness command = %q, should not get polecat runtime_args", cmd)
+	}
+}
@@ -73,6 +73,10 @@ type TownSettings struct {
 	// WebTimeouts configures command execution timeouts for the web dashboard.
 	WebTimeouts *WebTimeoutsConfig `json:"web_timeouts,omitempty"`
 
+	// WebAuth configures authentication for the web dashboard. Required to
+	// serve the dashboard beyond localhost. See gt dashboard token.
+	WebAuth *WebAuthConfig `json:"web_auth,omitempty"`
+
 	// WorkerStatus configures activity-age thresholds for worker status classification.
 	WorkerStatus *WorkerStatusConfig `json:"worker_status,omitempty"`
 
@@ -82,6 +86,31 @@ type TownSettings struct {
 	// Convoy configures convoy behavior settings.
 	Convoy *ConvoyConfig `json:"convoy,omitempty"`
 
+	// MailHealth configures the thresholds used by the gt doctor mail check.
+	MailHealth *MailHealthConfig `json:"mail_health,omitempty"`
+
+	// BootBudget configures polecat session boot-time budgets and
+	// regression detection.
+	BootBudget *BootBudgetConfig `json:"boot_budget,omitempty"`
+
+	// Limits overrides the resource limit targets used by the gt doctor
+	// limits check and the fix script it generates.
+	Limits *LimitsConfig `json:"limits,omitempty"`
+
+	// ActiveHours restricts when new polecats may be spawned.
+	// See gt active-hours.
+	ActiveHours *ActiveHoursConfig `json:"active_hours,omitempty"`
+
+	// Overrides holds town-wide defaults plus per-role and per-agent
+	// refinements, resolved town → rig → role → agent.
+	// See gt config effective --for <agent>.
+	Overrides *ConfigOverrides `json:"overrides,omitempty"`
+
+	// PromptVersion is the prompt pack agents are primed with by default:
+	// "builtin" (or empty) for the templates compiled into gt, otherwise a
+	// directory under <town>/prompts/. See gt prompt.
+	PromptVersion string `json:"prompt_version,omitempty"`
+
 	// CostTier tracks which cost tier preset was applied (informational).
 	// Actual model assignments live in RoleAgents and Agents.
 	// Values: "standard", "economy", "budget", or empty for custom configs.
@@ -127,6 +156,36 @@ func DefaultWebTimeoutsConfig() *WebTimeoutsConfig {
 	}
 }
 
+// WebAuthConfig configures token and mTLS authentication for the web
+// dashboard. Each credential carries scopes limiting which endpoints it
+// may use: "read" (dashboard, static assets, read-only API), "write" (mail
+// send, issue create/close/update), "run" (/api/run, arbitrary gt
+// commands), or "*" for all.
+type WebAuthConfig struct {
+	// Tokens are bearer tokens, stored as SHA-256 hashes.
+	Tokens []WebAuthToken `json:"tokens,omitempty"`
+
+	// TLSCertFile and TLSKeyFile serve the dashboard over HTTPS.
+	TLSCertFile string `json:"tls_cert_file,omitempty"`
+	TLSKeyFile  string `json:"tls_key_file,omitempty"`
+
+	// ClientCAFile enables mTLS: a client certificate signed by this CA
+	// authenticates as its subject common name. Requires TLSCertFile.
+	ClientCAFile string `json:"client_ca_file,omitempty"`
+
+	// ClientScopes maps client certificate common names to scopes. The
+	// "*" entry applies to any verified client not listed by name.
+	ClientScopes map[string][]string `json:"client_scopes,omitempty"`
+}
+
+// WebAuthToken is a named dashboard bearer token.
+type WebAuthToken struct {
+	Name      string   `json:"name"`
+	SHA256    string   `json:"sha256"` // hex SHA-256 of the token
+	Scopes    []string `json:"scopes"`
+	CreatedAt string   `json:"created_at,omitempty"`
+}
+
 // WorkerStatusConfig configures activity-age thresholds for worker status classification.
 type WorkerStatusConfig struct {
 	// StaleThreshold is the activity age after which a worker is considered "stale".
@@ -175,6 +234,103 @@ func DefaultFeedCuratorConfig() *FeedCuratorConfig {
 	}
 }
 
+// MailHealthConfig configures inbox backlog thresholds for the mail doctor check.
+type MailHealthConfig struct {
+	// MaxUnread is the unread message count above which an agent's inbox is
+	// reported as backlogged.
+	// Default: 20.
+	MaxUnread int `json:"max_unread,omitempty"`
+	// MaxUnreadAge is how long a message may sit unread before the inbox is
+	// reported as stale.
+	// Default: "24h".
+	MaxUnreadAge string `json:"max_unread_age,omitempty"`
+}
+
+// DefaultMailHealthConfig returns a MailHealthConfig with sensible defaults.
+func DefaultMailHealthConfig() *MailHealthConfig {
+	return &MailHealthConfig{
+		MaxUnread:    20,
+		MaxUnreadAge: "24h",
+	}
+}
+
+// LimitsConfig overrides the resource limit targets gt doctor checks
+// against. Zero fields keep the default, which scales with the town's
+// configured agent count; set them to size a small laptop or a big dev box
+// explicitly.
+type LimitsConfig struct {
+	// NOFILE is the open-file target for agent sessions (ulimit -n) and
+	// fs.file-max.
+	// Default: 4096 + 1024 per agent.
+	NOFILE uint64 `json:"nofile,omitempty"`
+	// NPROC is the process target for agent sessions (ulimit -u) and
+	// kernel.pid_max.
+	// Default: 512 + 128 per agent.
+	NPROC uint64 `json:"nproc,omitempty"`
+	// InotifyWatches is the fs.inotify.max_user_watches target (Linux).
+	// Default: 65536 + 8192 per agent.
+	InotifyWatches uint64 `json:"inotify_watches,omitempty"`
+	// FDsPerProcess is the open descriptor count above which doctor's
+	// fd-leaks check reports a town process (Linux).
+	// Default: 1024.
+	FDsPerProcess int `json:"fds_per_process,omitempty"`
+}
+
+// ActiveHoursConfig restricts polecat spawning to scheduled windows, for
+// towns that should only work heavily at night or only while someone is
+// around to supervise. Outside every window new spawns are refused and the
+// daemon stops feeding convoys; running agents are left alone.
+type ActiveHoursConfig struct {
+	// Timezone is the IANA time zone the windows are written in
+	// (e.g. "America/Los_Angeles").
+	// Default: the daemon host's local time zone.
+	Timezone string `json:"timezone,omitempty"`
+	// Windows lists when spawning is allowed. An empty list means always.
+	Windows []ActiveWindow `json:"windows,omitempty"`
+}
+
+// ActiveWindow is one recurring spawn window.
+type ActiveWindow struct {
+	// Days the window opens on: "mon".."sun", "weekdays", or "weekends".
+	// Default: every day.
+	Days []string `json:"days,omitempty"`
+	// Start is when the window opens, as "HH:MM".
+	Start string `json:"start"`
+	// End is when the window closes, as "HH:MM". An End at or before
+	// Start closes the window the next day (e.g. "22:00"-"06:00").
+	End string `json:"end"`
+}
+
+// BootBudgetConfig configures how long a polecat session may take from
+// session creation to a ready agent prompt before the spawn is flagged.
+type BootBudgetConfig struct {
+	// Budget is the total boot time allowed before a spawn is flagged.
+	// Default: "60s".
+	Budget string `json:"budget,omitempty"`
+	// ShellInit is how long the shell may take to exec the agent command
+	// before shell initialization is blamed.
+	// Default: "10s".
+	ShellInit string `json:"shell_init,omitempty"`
+	// MaxPrimingBytes is the priming context size (beacon plus CLAUDE.md/AGENTS.md)
+	// above which priming size is blamed.
+	// Default: 32768.
+	MaxPrimingBytes int `json:"max_priming_bytes,omitempty"`
+	// RegressionFactor is how many times slower the recent median boot time
+	// must be than the rig's baseline median to count as a regression.
+	// Default: 1.5.
+	RegressionFactor float64 `json:"regression_factor,omitempty"`
+}
+
+// DefaultBootBudgetConfig returns a BootBudgetConfig with sensible defaults.
+func DefaultBootBudgetConfig() *BootBudgetConfig {
+	return &BootBudgetConfig{
+		Budget:           "60s",
+		ShellInit:        "10s",
+		MaxPrimingBytes:  32768,
+		RegressionFactor: 1.5,
+	}
+}
+
 // ConvoyConfig configures convoy behavior settings.
 type ConvoyConfig struct {
 	// NotifyOnComplete controls whether convoy completion pushes a notification
@@ -332,6 +488,7 @@ type RigSettings struct {
 	Namepool   *NamepoolConfig   `json:"namepool,omitempty"`    // polecat name pool settings
 	Crew       *CrewConfig       `json:"crew,omitempty"`        // crew startup settings
 	Workflow   *WorkflowConfig   `json:"workflow,omitempty"`    // workflow settings
+	Witness    *WitnessConfig    `json:"witness,omitempty"`     // witness settings
 	Runtime    *RuntimeConfig    `json:"runtime,omitempty"`     // LLM runtime settings (deprecated: use Agent)
 
 	// Agent selects which agent preset to use for this rig.
@@ -352,6 +509,215 @@ type RigSettings struct {
 	// Overrides TownSettings.RoleAgents for this specific rig.
 	// Example: {"witness": "claude-haiku", "polecat": "claude-sonnet"}
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
+
+	// Overrides holds rig defaults plus per-role and per-agent refinements
+	// for this rig's agents. Rig layers take precedence over the town's at
+	// each level (rig role beats town role, rig agent beats town agent).
+	Overrides *ConfigOverrides `json:"overrides,omitempty"`
+
+	// PromptVersion pins this rig's agents to a prompt pack, overriding the
+	// town default and any staged rollout. See gt prompt pin.
+	PromptVersion string `json:"prompt_version,omitempty"`
+
+	// IssueSync mirrors this rig's work beads to external issue trackers
+	// (gt issue-sync).
+	IssueSync *IssueSyncSettings `json:"issue_sync,omitempty"`
+
+	// WorkLabels are the label patterns of the work this rig is set up
+	// for, e.g. ["area:refinery", "needs-gpu", "lang:*"]. A bead carrying
+	// a label some rig claims is only slung to rigs that claim all such
+	// labels it has (gt sling --force overrides).
+	WorkLabels []string `json:"work_labels,omitempty"`
+}
+
+// IssueSyncSettings names the issue trackers a rig's work beads sync with.
+type IssueSyncSettings struct {
+	// GitHub syncs with a repository's GitHub issues.
+	GitHub *GitHubSyncSettings `json:"github,omitempty"`
+
+	// JIRA syncs with the issues of a JIRA JQL filter.
+	JIRA *JIRASyncSettings `json:"jira,omitempty"`
+}
+
+// GitHubSyncSettings configures a rig's sync with GitHub issues, which runs
+// through the gh CLI.
+type GitHubSyncSettings struct {
+	// Repo is the repository, as owner/name.
+	Repo string `json:"repo"`
+
+	// Label limits the sync to issues with this label (e.g. "gastown").
+	// Empty syncs every issue in the repository.
+	Label string `json:"label,omitempty"`
+
+	// ExportLabel, if set, also opens an issue for each open bead carrying
+	// this label that isn't linked to one yet.
+	ExportLabel string `json:"export_label,omitempty"`
+}
+
+// JIRASyncSettings configures a rig's sync with JIRA, which runs through
+// its REST API. Credentials come from the environment: GT_JIRA_TOKEN is an
+// API token (with GT_JIRA_USER, or User, for JIRA Cloud's basic auth) or,
+// alone, a personal access token for JIRA Data Center.
+type JIRASyncSettings struct {
+	// URL is the JIRA site, e.g. "https://example.atlassian.net".
+	URL string `json:"url"`
+
+	// JQL selects the issues in scope, e.g. "project = OPS AND labels = gastown".
+	JQL string `json:"jql"`
+
+	// User is the account the API token belongs to (default GT_JIRA_USER).
+	User string `json:"user,omitempty"`
+
+	// Project and IssueType are where exported beads are opened (issue type
+	// default "Task"). Export needs Project.
+	Project   string `json:"project,omitempty"`
+	IssueType string `json:"issue_type,omitempty"`
+
+	// DoneTransition, StartTransition and ReopenTransition name the
+	// workflow transitions run when a bead closes, is assigned, or reopens.
+	// Unset, the first transition into a done, in-progress or to-do status
+	// is used.
+	DoneTransition   string `json:"done_transition,omitempty"`
+	StartTransition  string `json:"start_transition,omitempty"`
+	ReopenTransition string `json:"reopen_transition,omitempty"`
+
+	// Priorities maps JIRA priority names to bead priorities 0-4, over the
+	// default Highest/Blocker 0, High/Critical 1, Medium/Major 2,
+	// Low/Minor 3, Lowest/Trivial 4.
+	Priorities map[string]int `json:"priorities,omitempty"`
+
+	// ExportLabel, if set, also opens an issue for each open bead carrying
+	// this label that isn't linked to one yet.
+	ExportLabel string `json:"export_label,omitempty"`
+}
+
+// WitnessConfig represents witness settings for a rig.
+type WitnessConfig struct {
+	// CircuitBreaker tunes when a polecat's circuit trips.
+	CircuitBreaker *CircuitBreakerSettings `json:"circuit_breaker,omitempty"`
+
+	// ResourceBudget caps what a polecat's session may consume.
+	ResourceBudget *ResourceBudgetSettings `json:"resource_budget,omitempty"`
+
+	// StallDetection tunes when a polecat whose output stopped changing is
+	// considered stuck.
+	StallDetection *StallDetectionSettings `json:"stall_detection,omitempty"`
+
+	// Escalation routes the Witness's escalations about polecats
+	// (tripped circuits, broken work, runaway or stalled polecats).
+	Escalation *WitnessEscalationSettings `json:"escalation,omitempty"`
+}
+
+// WitnessEscalationSettings routes the Witness's polecat escalations. Zero
+// values mail the Mayor at each escalation's own priority.
+type WitnessEscalationSettings struct {
+	// To is the mail address escalations go to instead of the Mayor
+	// (e.g., a rig-specific overseer "gastown/crew/overseer").
+	To string `json:"to,omitempty"`
+
+	// Priority overrides the escalations' mail priority: low, normal,
+	// high or urgent.
+	Priority string `json:"priority,omitempty"`
+
+	// Webhook, if set, also receives each escalation as a JSON POST
+	// (e.g., an on-call paging service).
+	Webhook string `json:"webhook,omitempty"`
+}
+
+// StallDetectionSettings tunes the Witness's output-fingerprint stall
+// detection. Zero values use the defaults.
+type StallDetectionSettings struct {
+	// Timeout is how long a polecat's pane output may stay unchanged
+	// before it is nudged (e.g., "20m").
+	Timeout string `json:"timeout,omitempty"`
+
+	// NudgeGrace is how long after the nudge the output must stay frozen
+	// before the Witness acts (e.g., "10m").
+	NudgeGrace string `json:"nudge_grace,omitempty"`
+
+	// Lines is how many trailing lines of pane output are fingerprinted.
+	Lines int `json:"lines,omitempty"`
+
+	// Action is what the Witness does about a polecat still frozen after
+	// the nudge: "trip" opens its circuit (default), "escalate" only mails
+	// the Mayor.
+	Action string `json:"action,omitempty"`
+}
+
+// ResourceBudgetSettings caps the CPU, memory and file descriptors of a
+// polecat's session processes, as sampled by the Witness resource monitor.
+// A zero budget is not enforced; usage is still recorded.
+type ResourceBudgetSettings struct {
+	// MaxCPUPercent is the CPU the session may use, in percent of one core
+	// (e.g., 200 for two cores), averaged between samples.
+	MaxCPUPercent float64 `json:"max_cpu_percent,omitempty"`
+
+	// MaxRSSMB is the resident memory the session may use, in megabytes.
+	MaxRSSMB int `json:"max_rss_mb,omitempty"`
+
+	// MaxFDs is how many file descriptors the session may hold open.
+	MaxFDs int `json:"max_fds,omitempty"`
+
+	// Sustain is how many consecutive samples must be over budget before
+	// the Witness acts, so a build's brief spike is tolerated.
+	Sustain int `json:"sustain,omitempty"`
+
+	// Action is what the Witness does about a polecat over budget: "trip"
+	// opens its circuit (default), "escalate" only mails the Mayor.
+	Action string `json:"action,omitempty"`
+}
+
+// Witness actions against a polecat that stays over its resource budget
+// or whose output stays frozen.
+const (
+	WitnessActionTrip     = "trip"
+	WitnessActionEscalate = "escalate"
+)
+
+// CircuitBreakerSettings tunes the Witness's per-polecat circuit breaker.
+// Zero values use the defaults.
+type CircuitBreakerSettings struct {
+	// MaxFailures is how many failures open a polecat's circuit.
+	MaxFailures int `json:"max_failures,omitempty"`
+
+	// CooldownPeriod is how long an open circuit stays open before the
+	// polecat may be probed again (e.g., "30m"). It doubles with each
+	// further trip of the same polecat.
+	CooldownPeriod string `json:"cooldown_period,omitempty"`
+
+	// MaxCooldownPeriod caps the doubled cooldown (e.g., "8h").
+	MaxCooldownPeriod string `json:"max_cooldown_period,omitempty"`
+
+	// MaxBeadFailures is how many non-transient failures, by any polecats,
+	// mark a work bead as likely broken: it is blocked and escalated
+	// instead of requeued.
+	MaxBeadFailures int `json:"max_bead_failures,omitempty"`
+
+	// RemediationHook is a command (run through sh, from the rig directory)
+	// the Witness runs on a tripped polecat before nuking it, e.g. to
+	// salvage branches or collect logs. Exit code 2 vetoes the nuke and
+	// escalates the polecat instead.
+	RemediationHook string `json:"remediation_hook,omitempty"`
+
+	// RemediationHookTimeout bounds a RemediationHook run (e.g., "5m").
+	RemediationHookTimeout string `json:"remediation_hook_timeout,omitempty"`
+
+	// MaxActionsPerSweep caps how many open circuits one sweep acts on
+	// (requeue, nuke, escalate); the rest wait for the next sweep.
+	MaxActionsPerSweep int `json:"max_actions_per_sweep,omitempty"`
+
+	// ActionPacing is the pause between those actions (e.g., "2s"), so a
+	// large sweep doesn't nuke polecats and mail the Mayor all at once.
+	ActionPacing string `json:"action_pacing,omitempty"`
+
+	// SuccessStreak is how many probe assignments in a row a half-open
+	// polecat must land before its circuit closes.
+	SuccessStreak int `json:"success_streak,omitempty"`
+
+	// ProbationPeriod is how long a polecat stays on probation once its
+	// circuit closes again (e.g., "2h"): its first failure in that window
+	// reopens the circuit, as in half_open.
+	ProbationPeriod string `json:"probation_period,omitempty"`
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -456,6 +822,11 @@ type RuntimeTmuxConfig struct {
 	// ReadyPromptPrefix is the prompt prefix to detect readiness (e.g., "> ").
 	ReadyPromptPrefix string `json:"ready_prompt_prefix,omitempty"`
 
+	// ReadyPattern is a regular expression matched against each pane line
+	// to detect readiness (e.g., "^aider> $"). Takes precedence over
+	// ReadyPromptPrefix.
+	ReadyPattern string `json:"ready_pattern,omitempty"`
+
 	// ReadyDelayMs is a fixed delay used when prompt detection is unavailable.
 	ReadyDelayMs int `json:"ready_delay_ms,omitempty"`
 }
@@ -622,6 +993,10 @@ func normalizeRuntimeConfig(rc *RuntimeConfig) *RuntimeConfig {
 		rc.Tmux.ReadyPromptPrefix = defaultReadyPromptPrefix(rc.Provider)
 	}
 
+	if rc.Tmux.ReadyPattern == "" {
+		rc.Tmux.ReadyPattern = defaultReadyPattern(rc.Provider)
+	}
+
 	if rc.Tmux.ReadyDelayMs == 0 {
 		rc.Tmux.ReadyDelayMs = defaultReadyDelayMs(rc.Provider)
 	}
@@ -753,6 +1128,13 @@ func defaultReadyPromptPrefix(provider string) string {
 	return ""
 }
 
+func defaultReadyPattern(provider string) string {
+	if preset := GetAgentPresetByName(provider); preset != nil {
+		return preset.ReadyPattern
+	}
+	return ""
+}
+
 func defaultReadyDelayMs(provider string) int {
 	if preset := GetAgentPresetByName(provider); preset != nil {
 		return preset.ReadyDelayMs
@@ -868,6 +1250,11 @@ type MergeQueueConfig struct {
 	// TypecheckCommand is the command to run for type checking (e.g., tsc --noEmit).
 	TypecheckCommand string `json:"typecheck_command,omitempty"`
 
+	// SmokeCommand is the command the Witness smoke gate runs on a fresh
+	// worktree before re-enabling a rig after an incident.
+	// Empty falls back to TestCommand.
+	SmokeCommand string `json:"smoke_command,omitempty"`
+
 	// DeleteMergedBranches controls whether to delete branches after merging.
 	// Nil defaults to true (merged branches are deleted).
 	DeleteMergedBranches *bool `json:"delete_merged_branches,omitempty"`
@@ -884,6 +1271,12 @@ type MergeQueueConfig struct {
 	// StaleClaimTimeout is how long a claimed MR can go without updates before
 	// being considered abandoned and eligible for re-claim (e.g., "30m").
 	StaleClaimTimeout string `json:"stale_claim_timeout,omitempty"`
+
+	// LabelWeights adds points to the queue score of MRs carrying a label
+	// matching each pattern (negative to hold them back), e.g.
+	// {"area:security": 300, "flaky": -100}. MRs carry the user labels of
+	// the work they merge.
+	LabelWeights map[string]float64 `json:"label_weights,omitempty"`
 }
 
 // OnConflict strategy constants.
@@ -0,0 +1,62 @@
+package daemon
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// defaultAgentGCInterval is how often stale agent beads are collected when
+// agent_gc.interval is unset.
+const defaultAgentGCInterval = 6 * time.Hour
+
+// agentGCInterval returns the configured GC interval or the default.
+func agentGCInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.AgentGC != nil {
+		if d, err := time.ParseDuration(config.Patrols.AgentGC.Interval); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultAgentGCInterval
+}
+
+// AgentGCRetention returns the configured retention or the default; gt
+// beads gc uses it too.
+func AgentGCRetention(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.AgentGC != nil && config.Patrols.AgentGC.RetainDays > 0 {
+		return time.Duration(config.Patrols.AgentGC.RetainDays) * 24 * time.Hour
+	}
+	return beads.DefaultAgentRetention
+}
+
+// agentGCDirs returns the beads directories the agent GC covers: the
+// town's and each known rig's, each once.
+func (d *Daemon) agentGCDirs() []string {
+	dirs := []string{beads.GetTownBeadsPath(d.config.TownRoot)}
+	seen := map[string]bool{dirs[0]: true}
+	for _, rigName := range d.getKnownRigs() {
+		dir := beads.ResolveBeadsDir(filepath.Join(d.config.TownRoot, rigName))
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// collectAgentBeads archives and deletes the stale agent beads of every
+// database. Non-fatal: a database that can't be collected is logged and
+// skipped.
+func (d *Daemon) collectAgentBeads() {
+	retention := AgentGCRetention(d.patrolConfig)
+	for _, dir := range d.agentGCDirs() {
+		collected, archive, err := beads.NewWithBeadsDir(filepath.Dir(dir), dir).WithSubsystem("daemon").GCAgentBeads(retention, false)
+		if err != nil {
+			d.logger.Printf("agent_gc: %s: %v", dir, err)
+		}
+		if len(collected) > 0 {
+			d.logger.Printf("agent_gc: archived %d agent bead(s) to %s", len(collected), archive)
+		}
+	}
+}
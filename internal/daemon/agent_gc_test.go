@@ -0,0 +1,34 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestIsPatrolEnabled_AgentGC(t *testing.T) {
+	if IsPatrolEnabled(nil, "agent_gc") {
+		t.Error("expected agent_gc to be disabled with nil config")
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{AgentGC: &AgentGCConfig{Enabled: true}}}
+	if !IsPatrolEnabled(config, "agent_gc") {
+		t.Error("expected agent_gc to be enabled when configured")
+	}
+}
+
+func TestAgentGCIntervalAndRetention(t *testing.T) {
+	if got := agentGCInterval(nil); got != defaultAgentGCInterval {
+		t.Errorf("nil config: interval %v, want %v", got, defaultAgentGCInterval)
+	}
+	if got := AgentGCRetention(nil); got != beads.DefaultAgentRetention {
+		t.Errorf("nil config: retention %v, want %v", got, beads.DefaultAgentRetention)
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{AgentGC: &AgentGCConfig{Interval: "1h", RetainDays: 7}}}
+	if got := agentGCInterval(config); got != time.Hour {
+		t.Errorf("interval %v, want 1h", got)
+	}
+	if got := AgentGCRetention(config); got != 7*24*time.Hour {
+		t.Errorf("retention %v, want 168h", got)
+	}
+}
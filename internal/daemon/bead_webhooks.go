@@ -0,0 +1,283 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// defaultBeadWebhooksInterval is how often beads are checked for lifecycle
+// events when bead_webhooks.interval is unset.
+const defaultBeadWebhooksInterval = time.Minute
+
+// beadWebhookTimeout bounds one event POST.
+const beadWebhookTimeout = 10 * time.Second
+
+// maxBeadWebhookBacklog bounds the events held for an endpoint that is
+// failing; past it the oldest are dropped.
+const maxBeadWebhookBacklog = 1000
+
+// beadWebhooksInterval returns the configured check interval or the default.
+func beadWebhooksInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.BeadWebhooks != nil {
+		if d, err := time.ParseDuration(config.Patrols.BeadWebhooks.Interval); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultBeadWebhooksInterval
+}
+
+// validateBeadWebhookEndpoint checks an endpoint's type, URL and events.
+func validateBeadWebhookEndpoint(e BeadWebhookEndpoint) error {
+	switch e.Type {
+	case "", deacon.NotifySinkWebhook, deacon.NotifySinkSlack:
+	default:
+		return fmt.Errorf("unknown endpoint type %q (valid: webhook, slack)", e.Type)
+	}
+	if !strings.HasPrefix(e.URL, "http://") && !strings.HasPrefix(e.URL, "https://") {
+		return fmt.Errorf("endpoint needs an http(s) url, got %q", e.URL)
+	}
+	for _, ev := range e.Events {
+		if !slices.Contains(beads.LifecycleEvents, ev) {
+			return fmt.Errorf("unknown bead event %q (valid: %s)", ev, strings.Join(beads.LifecycleEvents, ", "))
+		}
+	}
+	return nil
+}
+
+// beadWebhookSink returns the sink delivering to an endpoint.
+func beadWebhookSink(e BeadWebhookEndpoint) deacon.NotifySink {
+	if e.Type == deacon.NotifySinkSlack {
+		return &deacon.SlackSink{URL: e.URL}
+	}
+	return &deacon.WebhookSink{URL: e.URL}
+}
+
+// BeadWebhooksStateFile returns the path to the bead webhooks state file:
+// the beads' lifecycle state as last seen and the events endpoints haven't
+// taken yet, so events aren't missed or sent twice across daemon restarts.
+func BeadWebhooksStateFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "bead_webhooks.json")
+}
+
+// beadWebhooksState is the lifecycle snapshot of each watched database,
+// by name ("hq" or a rig name), and the events held for each endpoint, by
+// URL, until it takes them.
+type beadWebhooksState struct {
+	DBs     map[string]beads.LifecycleSnapshot `json:"dbs"`
+	Backlog map[string][]deacon.Notification   `json:"backlog,omitempty"`
+}
+
+func loadBeadWebhooksState(townRoot string) (*beadWebhooksState, error) {
+	state := &beadWebhooksState{DBs: make(map[string]beads.LifecycleSnapshot), Backlog: make(map[string][]deacon.Notification)}
+	data, err := os.ReadFile(BeadWebhooksStateFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing bead webhooks state: %w", err)
+	}
+	if state.DBs == nil {
+		state.DBs = make(map[string]beads.LifecycleSnapshot)
+	}
+	if state.Backlog == nil {
+		state.Backlog = make(map[string][]deacon.Notification)
+	}
+	return state, nil
+}
+
+func saveBeadWebhooksState(townRoot string, state *beadWebhooksState) error {
+	path := BeadWebhooksStateFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, state)
+}
+
+// beadLifecycleNotification describes a lifecycle event of a bead in db.
+func beadLifecycleNotification(town, db string, ev beads.LifecycleEvent, now time.Time) deacon.Notification {
+	issue := ev.Issue
+	n := deacon.Notification{
+		Event:   ev.Event,
+		Message: issue.Title,
+		Town:    town,
+		Time:    now,
+		Fields: map[string]string{
+			"db":       db,
+			"id":       issue.ID,
+			"title":    issue.Title,
+			"status":   issue.Status,
+			"priority": fmt.Sprint(issue.Priority),
+		},
+	}
+	if issue.Type != "" {
+		n.Fields["type"] = issue.Type
+	}
+	if issue.Assignee != "" {
+		n.Fields["assignee"] = issue.Assignee
+	}
+	if len(issue.Labels) > 0 {
+		n.Fields["labels"] = strings.Join(issue.Labels, ",")
+	}
+	if ev.PrevStatus != "" {
+		n.Fields["prev_status"] = ev.PrevStatus
+	}
+
+	switch ev.Event {
+	case beads.LifecycleCreated:
+		n.Title = fmt.Sprintf("%s created", issue.ID)
+	case beads.LifecycleStatusChanged:
+		n.Title = fmt.Sprintf("%s: %s → %s", issue.ID, ev.PrevStatus, issue.Status)
+	case beads.LifecycleClosed:
+		n.Title = fmt.Sprintf("%s closed", issue.ID)
+	case beads.LifecycleCircuitTripped:
+		n.Title = fmt.Sprintf("Circuit tripped: %s", issue.ID)
+		n.Message = fmt.Sprintf("Circuit opened after %d failure(s) (trip %d); no new work until it cools down.",
+			ev.Agent.FailureCount, ev.Agent.TripCount)
+		if ev.Agent.CircuitNote != "" {
+			n.Message += " " + ev.Agent.CircuitNote
+		}
+		n.Fields["rig"] = ev.Agent.Rig
+		n.Fields["failure_count"] = fmt.Sprint(ev.Agent.FailureCount)
+		n.Fields["trip_count"] = fmt.Sprint(ev.Agent.TripCount)
+	}
+	return n
+}
+
+// beadWebhookDBs returns the databases the patrol watches, by name: the
+// town's ("hq") and each patrolled rig's, each beads directory once.
+func (d *Daemon) beadWebhookDBs() map[string]string {
+	townDir := beads.GetTownBeadsPath(d.config.TownRoot)
+	dbs := map[string]string{"hq": townDir}
+	seen := map[string]bool{townDir: true}
+	for _, rigName := range d.getPatrolRigs("bead_webhooks") {
+		dir := beads.ResolveBeadsDir(filepath.Join(d.config.TownRoot, rigName))
+		if !seen[dir] {
+			seen[dir] = true
+			dbs[rigName] = dir
+		}
+	}
+	return dbs
+}
+
+// sendBeadWebhooks works out the lifecycle events since the last check and
+// delivers them to each endpoint in the background. The events are queued
+// in the state file before any is sent, and an endpoint's events stay
+// there until it takes them, so the ones it fails to take are retried, in
+// order, on the next check, even after a daemon restart. A tick that comes
+// round while a delivery is still running is skipped.
+func (d *Daemon) sendBeadWebhooks() {
+	cfg := d.patrolConfig.Patrols.BeadWebhooks
+	if !d.beadWebhooksRunning.CompareAndSwap(false, true) {
+		return
+	}
+	dbs := d.beadWebhookDBs()
+	go func() {
+		defer d.beadWebhooksRunning.Store(false)
+
+		state, err := loadBeadWebhooksState(d.config.TownRoot)
+		if err != nil {
+			d.logger.Printf("bead_webhooks: %v", err)
+			return
+		}
+		town := filepath.Base(d.config.TownRoot)
+		now := time.Now().UTC()
+		var notifications []deacon.Notification
+		for db, dir := range dbs {
+			issues, err := beads.NewWithBeadsDir(filepath.Dir(dir), dir).WithSubsystem("daemon").List(beads.ListOptions{Status: "all", Priority: -1})
+			if err != nil {
+				d.logger.Printf("bead_webhooks: listing %s beads: %v", db, err)
+				continue
+			}
+			for _, ev := range beads.DiffLifecycle(state.DBs[db], issues) {
+				notifications = append(notifications, beadLifecycleNotification(town, db, ev, now))
+			}
+			state.DBs[db] = beads.TakeLifecycleSnapshot(issues)
+		}
+
+		var endpoints []BeadWebhookEndpoint
+		configured := make(map[string]bool)
+		for _, endpoint := range cfg.Endpoints {
+			if validateBeadWebhookEndpoint(endpoint) != nil {
+				continue // Logged when the patrol started
+			}
+			endpoints = append(endpoints, endpoint)
+			configured[endpoint.URL] = true
+			d.queueBeadWebhooks(state, endpoint, notifications)
+		}
+		for url := range state.Backlog {
+			if !configured[url] {
+				delete(state.Backlog, url) // Endpoint removed from the config
+			}
+		}
+		if err := saveBeadWebhooksState(d.config.TownRoot, state); err != nil {
+			d.logger.Printf("bead_webhooks: saving state: %v", err)
+			return
+		}
+
+		for _, endpoint := range endpoints {
+			d.deliverBeadWebhooks(state, endpoint)
+		}
+		if err := saveBeadWebhooksState(d.config.TownRoot, state); err != nil {
+			d.logger.Printf("bead_webhooks: saving state: %v", err)
+		}
+	}()
+}
+
+// queueBeadWebhooks adds the new events an endpoint subscribes to after
+// the ones it holds, dropping the oldest past maxBeadWebhookBacklog.
+func (d *Daemon) queueBeadWebhooks(state *beadWebhooksState, endpoint BeadWebhookEndpoint, notifications []deacon.Notification) {
+	queue := state.Backlog[endpoint.URL]
+	for _, n := range notifications {
+		if len(endpoint.Events) == 0 || slices.Contains(endpoint.Events, n.Event) {
+			queue = append(queue, n)
+		}
+	}
+	if dropped := len(queue) - maxBeadWebhookBacklog; dropped > 0 {
+		d.logger.Printf("bead_webhooks: %s: dropping %d held event(s)", deacon.RedactURL(endpoint.URL), dropped)
+		queue = queue[dropped:]
+	}
+	if len(queue) == 0 {
+		delete(state.Backlog, endpoint.URL)
+		return
+	}
+	state.Backlog[endpoint.URL] = slices.Clone(queue)
+}
+
+// deliverBeadWebhooks sends an endpoint the events queued for it, in
+// order, stopping at the first failure and holding the rest for the next
+// check.
+func (d *Daemon) deliverBeadWebhooks(state *beadWebhooksState, endpoint BeadWebhookEndpoint) {
+	queue := state.Backlog[endpoint.URL]
+	sink := beadWebhookSink(endpoint)
+	sent := 0
+	for _, n := range queue {
+		ctx, cancel := context.WithTimeout(d.ctx, beadWebhookTimeout)
+		err := sink.Notify(ctx, n)
+		cancel()
+		if err != nil {
+			d.logger.Printf("bead_webhooks: %v (%d event(s) held for retry)", err, len(queue)-sent)
+			break
+		}
+		sent++
+	}
+
+	queue = queue[sent:]
+	if len(queue) == 0 {
+		delete(state.Backlog, endpoint.URL)
+		return
+	}
+	state.Backlog[endpoint.URL] = slices.Clone(queue)
+}
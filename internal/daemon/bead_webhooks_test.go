@@ -0,0 +1,128 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/deacon"
+)
+
+func TestIsPatrolEnabled_BeadWebhooks(t *testing.T) {
+	if IsPatrolEnabled(nil, "bead_webhooks") {
+		t.Error("expected bead_webhooks to be disabled with nil config")
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{BeadWebhooks: &BeadWebhooksConfig{Enabled: true, Interval: "15s"}}}
+	if !IsPatrolEnabled(config, "bead_webhooks") {
+		t.Error("expected bead_webhooks to be enabled when configured")
+	}
+	if got := beadWebhooksInterval(config); got != 15*time.Second {
+		t.Errorf("interval = %v, want 15s", got)
+	}
+	if got := beadWebhooksInterval(nil); got != defaultBeadWebhooksInterval {
+		t.Errorf("nil config: interval = %v, want %v", got, defaultBeadWebhooksInterval)
+	}
+}
+
+func TestValidateBeadWebhookEndpoint(t *testing.T) {
+	valid := []BeadWebhookEndpoint{
+		{URL: "https://example.com/hook"},
+		{Type: "slack", URL: "https://hooks.slack.com/services/x", Events: []string{beads.LifecycleClosed}},
+	}
+	for _, e := range valid {
+		if err := validateBeadWebhookEndpoint(e); err != nil {
+			t.Errorf("validateBeadWebhookEndpoint(%+v) = %v", e, err)
+		}
+	}
+	invalid := []BeadWebhookEndpoint{
+		{URL: "example.com/hook"},
+		{Type: "desktop", URL: "https://example.com/hook"},
+		{URL: "https://example.com/hook", Events: []string{"bead.deleted"}},
+	}
+	for _, e := range invalid {
+		if err := validateBeadWebhookEndpoint(e); err == nil {
+			t.Errorf("validateBeadWebhookEndpoint(%+v) should fail", e)
+		}
+	}
+}
+
+func TestBeadLifecycleNotification(t *testing.T) {
+	ev := beads.LifecycleEvent{
+		Event:      beads.LifecycleClosed,
+		Issue:      &beads.Issue{ID: "gt-abc", Title: "Fix login", Status: "closed", Priority: 1, Labels: []string{"area:cli"}},
+		PrevStatus: "in_progress",
+	}
+	n := beadLifecycleNotification("town", "gastown", ev, time.Now())
+	if n.Event != beads.LifecycleClosed || n.Title != "gt-abc closed" || n.Message != "Fix login" {
+		t.Errorf("notification = %+v", n)
+	}
+	for key, want := range map[string]string{"db": "gastown", "status": "closed", "prev_status": "in_progress", "priority": "1", "labels": "area:cli"} {
+		if n.Fields[key] != want {
+			t.Errorf("field %s = %q, want %q", key, n.Fields[key], want)
+		}
+	}
+}
+
+func TestDeliverBeadWebhooks_HoldsFailedEvents(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var n deacon.Notification
+		if err := json.Unmarshal(body, &n); err != nil {
+			t.Errorf("bad payload %s: %v", body, err)
+		}
+		received = append(received, n.Fields["id"])
+	}))
+	defer server.Close()
+
+	d := &Daemon{ctx: context.Background(), logger: log.New(io.Discard, "", 0)}
+	endpoint := BeadWebhookEndpoint{URL: server.URL, Events: []string{beads.LifecycleCreated}}
+	event := func(name, id string) deacon.Notification {
+		return deacon.Notification{Event: name, Fields: map[string]string{"id": id}}
+	}
+
+	townRoot := t.TempDir()
+	state, err := loadBeadWebhooksState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.queueBeadWebhooks(state, endpoint, []deacon.Notification{event(beads.LifecycleCreated, "gt-1"), event(beads.LifecycleClosed, "gt-0")})
+	d.deliverBeadWebhooks(state, endpoint)
+	if held := len(state.Backlog[server.URL]); held != 1 {
+		t.Fatalf("a failing endpoint should hold its one subscribed event, holds %d", held)
+	}
+
+	// The held event survives a restart.
+	if err := saveBeadWebhooksState(townRoot, state); err != nil {
+		t.Fatal(err)
+	}
+	if state, err = loadBeadWebhooksState(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	failing = false
+	mu.Unlock()
+	d.queueBeadWebhooks(state, endpoint, []deacon.Notification{event(beads.LifecycleCreated, "gt-2")})
+	d.deliverBeadWebhooks(state, endpoint)
+	if len(received) != 2 || received[0] != "gt-1" || received[1] != "gt-2" {
+		t.Errorf("received %v, want the held gt-1 then gt-2", received)
+	}
+	if _, ok := state.Backlog[server.URL]; ok {
+		t.Error("backlog should be empty once delivered")
+	}
+}
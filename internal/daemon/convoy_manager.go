@@ -11,6 +11,7 @@ import (
 	"time"
 
 	beadsdk "github.com/steveyegge/beads"
+	"github.com/steveyegge/gastown/internal/activehours"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/convoy"
 	"github.com/steveyegge/gastown/internal/util"
@@ -268,6 +269,11 @@ func (m *ConvoyManager) scan() {
 		return
 	}
 
+	// Outside active hours, stranded convoys wait: feeding one would only
+	// have gt sling refuse the spawn. Empty convoys still close.
+	hoursErr := activehours.Check(m.townRoot)
+	held := 0
+
 	for _, c := range stranded {
 		select {
 		case <-m.ctx.Done():
@@ -276,11 +282,19 @@ func (m *ConvoyManager) scan() {
 		}
 
 		if c.ReadyCount > 0 {
+			if hoursErr != nil {
+				held++
+				continue
+			}
 			m.feedFirstReady(c)
 		} else {
 			m.closeEmptyConvoy(c.ID)
 		}
 	}
+
+	if held > 0 {
+		m.logger("Convoy: not feeding %d stranded convoy(s): %v", held, hoursErr)
+	}
 }
 
 // findStranded runs `gt convoy stranded --json` and parses the output.
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -12,6 +13,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -53,6 +55,7 @@ type Daemon struct {
 	beadsStores   map[string]beadsdk.Storage
 	doltServer    *DoltServerManager
 	krcPruner     *KRCPruner
+	forgeWebhook  *http.Server
 
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
@@ -75,6 +78,21 @@ type Daemon struct {
 
 	// Restart tracking with exponential backoff to prevent crash loops
 	restartTracker *RestartTracker
+
+	// scheduleRunning is set while a batch of scheduled tasks runs, so a
+	// slow batch doesn't overlap the next check.
+	scheduleRunning atomic.Bool
+
+	// replicaRunning is set while the replica is being rebuilt.
+	replicaRunning atomic.Bool
+
+	// issueSyncRunning is set while rigs are being synced with their issue
+	// trackers.
+	issueSyncRunning atomic.Bool
+
+	// beadWebhooksRunning is set while bead lifecycle events are being
+	// worked out and delivered.
+	beadWebhooksRunning atomic.Bool
 }
 
 // sessionDeath records a detected session death for mass death analysis.
@@ -291,6 +309,145 @@ func (d *Daemon) Run() error {
 		d.logger.Printf("Dolt remotes push ticker started (interval %v)", interval)
 	}
 
+	// Start dependency-update duty ticker if configured.
+	// The per-rig cadence (default weekly) is tracked in each witness's state
+	// file; the ticker only controls how often due rigs are checked.
+	var depUpdatesTicker *time.Ticker
+	var depUpdatesChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "dep_updates") {
+		depUpdatesTicker = time.NewTicker(depUpdatesCheckInterval)
+		depUpdatesChan = depUpdatesTicker.C
+		defer depUpdatesTicker.Stop()
+		d.logger.Printf("Dependency update duty started (cadence %v)", depUpdatesInterval(d.patrolConfig))
+	}
+
+	// Start inbox nag ticker if configured. Agents sitting on unread
+	// actionable mail get an inbox summary queued for their next nudge.
+	var inboxNagTicker *time.Ticker
+	var inboxNagChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "inbox_nag") {
+		inboxNagTicker = time.NewTicker(inboxNagCheckInterval)
+		inboxNagChan = inboxNagTicker.C
+		defer inboxNagTicker.Stop()
+		d.logger.Printf("Inbox nag patrol started (interval %v)", inboxNagCheckInterval)
+	}
+
+	// Start metrics push ticker if configured, for operators whose
+	// monitoring is push-based (StatsD / DogStatsD agents).
+	var metricsPushTicker *time.Ticker
+	var metricsPushChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "metrics_push") {
+		interval := metricsPushInterval(d.patrolConfig)
+		metricsPushTicker = time.NewTicker(interval)
+		metricsPushChan = metricsPushTicker.C
+		defer metricsPushTicker.Stop()
+		d.logger.Printf("Metrics push started (interval %v)", interval)
+	}
+
+	// Start queue snapshot ticker if configured, so merge queue depth can be
+	// exported over time without a metrics stack.
+	var queueSnapsTicker *time.Ticker
+	var queueSnapsChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "queue_snapshots") {
+		interval := queueSnapsInterval(d.patrolConfig)
+		queueSnapsTicker = time.NewTicker(interval)
+		queueSnapsChan = queueSnapsTicker.C
+		defer queueSnapsTicker.Stop()
+		d.logger.Printf("Queue snapshots started (interval %v)", interval)
+	}
+
+	// Start model API probe ticker. Probe failures (and confirmed agent
+	// launch failures) put the town into degraded mode; the first successful
+	// probe afterwards takes it out again.
+	var modelProbeTicker *time.Ticker
+	var modelProbeChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "model_probe") {
+		interval := modelProbeInterval(d.patrolConfig)
+		modelProbeTicker = time.NewTicker(interval)
+		modelProbeChan = modelProbeTicker.C
+		defer modelProbeTicker.Stop()
+		d.logger.Printf("Model API probe started (interval %v)", interval)
+	}
+
+	// Start the polecat resource monitor if configured. Each rig's budgets
+	// are re-read on every tick.
+	var polecatResourcesTicker *time.Ticker
+	var polecatResourcesChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "polecat_resources") {
+		interval := polecatResourcesInterval(d.patrolConfig)
+		polecatResourcesTicker = time.NewTicker(interval)
+		polecatResourcesChan = polecatResourcesTicker.C
+		defer polecatResourcesTicker.Stop()
+		d.logger.Printf("Polecat resource monitor started (interval %v)", interval)
+	}
+
+	// Start the scheduled task ticker (gt deacon schedule) along with Deacon
+	// supervision. With no schedule file each check is a stat.
+	var scheduleTicker *time.Ticker
+	var scheduleChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "deacon") {
+		scheduleTicker = time.NewTicker(scheduleCheckInterval)
+		scheduleChan = scheduleTicker.C
+		defer scheduleTicker.Stop()
+	}
+
+	// Start the replica ticker if configured, refreshing once up front so
+	// reporting commands have a snapshot to query.
+	var replicaTicker *time.Ticker
+	var replicaChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "replica") {
+		interval := replicaInterval(d.patrolConfig)
+		replicaTicker = time.NewTicker(interval)
+		replicaChan = replicaTicker.C
+		defer replicaTicker.Stop()
+		d.logger.Printf("Replica refresh started (interval %v)", interval)
+		d.refreshReplica()
+	}
+
+	// Start the issue sync ticker if configured.
+	var issueSyncTicker *time.Ticker
+	var issueSyncChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "issue_sync") {
+		interval := issueSyncInterval(d.patrolConfig)
+		issueSyncTicker = time.NewTicker(interval)
+		issueSyncChan = issueSyncTicker.C
+		defer issueSyncTicker.Stop()
+		d.logger.Printf("Issue sync started (interval %v)", interval)
+	}
+
+	// Start the agent bead GC ticker if configured.
+	var agentGCTicker *time.Ticker
+	var agentGCChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "agent_gc") {
+		interval := agentGCInterval(d.patrolConfig)
+		agentGCTicker = time.NewTicker(interval)
+		agentGCChan = agentGCTicker.C
+		defer agentGCTicker.Stop()
+		d.logger.Printf("Agent bead GC started (interval %v, retention %v)", interval, AgentGCRetention(d.patrolConfig))
+	}
+
+	// Start the bead webhooks ticker if configured.
+	var beadWebhooksTicker *time.Ticker
+	var beadWebhooksChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "bead_webhooks") {
+		interval := beadWebhooksInterval(d.patrolConfig)
+		beadWebhooksTicker = time.NewTicker(interval)
+		beadWebhooksChan = beadWebhooksTicker.C
+		defer beadWebhooksTicker.Stop()
+		d.logger.Printf("Bead webhooks started (interval %v, %d endpoint(s))", interval, len(d.patrolConfig.Patrols.BeadWebhooks.Endpoints))
+		for _, endpoint := range d.patrolConfig.Patrols.BeadWebhooks.Endpoints {
+			if err := validateBeadWebhookEndpoint(endpoint); err != nil {
+				d.logger.Printf("bead_webhooks: skipping endpoint: %v", err)
+			}
+		}
+	}
+
+	// Start the forge webhook endpoint if configured, so PRs opened or
+	// updated upstream reach the merge queue without polling.
+	if IsPatrolEnabled(d.patrolConfig, "forge_webhook") {
+		d.startForgeWebhook()
+	}
+
 	// Note: PATCH-010 uses per-session hooks in deacon/manager.go (SetAutoRespawnHook).
 	// Global pane-died hooks don't fire reliably in tmux 3.2a, so we rely on the
 	// per-session approach which has been tested to work for continuous recovery.
@@ -311,6 +468,9 @@ func (d *Daemon) Run() error {
 				d.processLifecycleRequests()
 			} else {
 				d.logger.Printf("Received signal %v, shutting down", sig)
+				if isDrainSignal(sig) {
+					d.drain(state, fmt.Sprintf("received %v", sig))
+				}
 				return d.shutdown(state)
 			}
 
@@ -328,6 +488,74 @@ func (d *Daemon) Run() error {
 				d.pushDoltRemotes()
 			}
 
+		case <-depUpdatesChan:
+			// Routine dependency-update beads, opened on behalf of each
+			// rig's witness and fed into the normal polecat/refinery flow.
+			if !d.isShutdownInProgress() {
+				d.openDependencyUpdates()
+			}
+
+		case <-inboxNagChan:
+			// Inbox-zero enforcement: nag agents with stale unread mail
+			// and escalate repeated neglect to their supervisor.
+			if !d.isShutdownInProgress() {
+				d.checkInboxNag()
+			}
+
+		case <-metricsPushChan:
+			// Push key town metrics to the configured StatsD agent.
+			if !d.isShutdownInProgress() {
+				d.pushMetrics(state)
+			}
+
+		case <-queueSnapsChan:
+			// Record every rig's merge queue for later export.
+			if !d.isShutdownInProgress() {
+				d.recordQueueSnapshots()
+			}
+
+		case <-polecatResourcesChan:
+			// Runaway polecat detection against each rig's resource budget.
+			if !d.isShutdownInProgress() {
+				d.monitorPolecatResources()
+			}
+
+		case <-modelProbeChan:
+			// Degraded mode detection and automatic resume.
+			if !d.isShutdownInProgress() {
+				d.probeModelAPI()
+			}
+
+		case <-scheduleChan:
+			// Recurring Deacon tasks whose cron schedule has come due.
+			if !d.isShutdownInProgress() {
+				d.runScheduledTasks()
+			}
+
+		case <-replicaChan:
+			// Rebuild the read-only snapshot reporting commands query.
+			if !d.isShutdownInProgress() {
+				d.refreshReplica()
+			}
+
+		case <-issueSyncChan:
+			// Mirror work beads to and from external issue trackers.
+			if !d.isShutdownInProgress() {
+				d.syncIssues()
+			}
+
+		case <-agentGCChan:
+			// Archive the agent beads of long-dead polecats.
+			if !d.isShutdownInProgress() {
+				d.collectAgentBeads()
+			}
+
+		case <-beadWebhooksChan:
+			// Tell external consumers what changed in the town's beads.
+			if !d.isShutdownInProgress() {
+				d.sendBeadWebhooks()
+			}
+
 		case <-timer.C:
 			d.heartbeat(state)
 
@@ -365,9 +593,16 @@ func (d *Daemon) heartbeat(state *State) {
 	// This must happen before beads operations that depend on Dolt.
 	d.ensureDoltServerRunning()
 
+	// A paused Deacon (gt deacon pause) is left alone: no restarts, no Boot
+	// triage, no heartbeat pokes, no spawn triggers. A human may be driving
+	// its session or operating on beads by hand.
+	deaconPaused := d.isDeaconPaused()
+
 	// 1. Ensure Deacon is running (restart if dead)
 	// Check patrol config - can be disabled in mayor/daemon.json
-	if IsPatrolEnabled(d.patrolConfig, "deacon") {
+	if IsPatrolEnabled(d.patrolConfig, "deacon") && deaconPaused {
+		d.logger.Printf("Deacon is paused, not restarting, poking or triggering spawns")
+	} else if IsPatrolEnabled(d.patrolConfig, "deacon") {
 		d.ensureDeaconRunning()
 	} else {
 		d.logger.Printf("Deacon patrol disabled in config, skipping")
@@ -379,15 +614,17 @@ func (d *Daemon) heartbeat(state *State) {
 
 	// 2. Poke Boot for intelligent triage (stuck/nudge/interrupt)
 	// Boot handles nuanced "is Deacon responsive" decisions
-	// Only run if Deacon patrol is enabled
-	if IsPatrolEnabled(d.patrolConfig, "deacon") {
+	// Only run if Deacon patrol is enabled. A headless Deacon has no session
+	// for Boot to triage; ensureDeaconRunning already restarted it if stale.
+	headlessDeacon := deacon.IsHeadless(d.config.TownRoot)
+	if IsPatrolEnabled(d.patrolConfig, "deacon") && !headlessDeacon && !deaconPaused {
 		d.ensureBootRunning()
 	}
 
 	// 3. Direct Deacon heartbeat check (belt-and-suspenders)
 	// Boot may not detect all stuck states; this provides a fallback
 	// Only run if Deacon patrol is enabled
-	if IsPatrolEnabled(d.patrolConfig, "deacon") {
+	if IsPatrolEnabled(d.patrolConfig, "deacon") && !headlessDeacon && !deaconPaused {
 		d.checkDeaconHeartbeat()
 	}
 
@@ -417,7 +654,9 @@ func (d *Daemon) heartbeat(state *State) {
 	// 7. Trigger pending polecat spawns (bootstrap mode - ZFC violation acceptable)
 	// This ensures polecats get nudged even when Deacon isn't in a patrol cycle.
 	// Uses regex-based WaitForRuntimeReady, which is acceptable for daemon bootstrap.
-	d.triggerPendingSpawns()
+	if !deaconPaused {
+		d.triggerPendingSpawns()
+	}
 
 	// 8. Process lifecycle requests
 	d.processLifecycleRequests()
@@ -445,6 +684,10 @@ func (d *Daemon) heartbeat(state *State) {
 	// branches persist indefinitely. This cleans them up periodically.
 	d.pruneStaleBranches()
 
+	// 14. Reap one-shot sessions (Boot triage, dogs) past their max lifetime
+	// or idle timeout, so hung helpers don't accumulate.
+	d.reapOneShotSessions()
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -594,6 +837,12 @@ func (d *Daemon) runDegradedBootTriage(b *boot.Boot) {
 func (d *Daemon) ensureDeaconRunning() {
 	const agentID = "deacon"
 
+	// Headless mode: the patrol loop is a supervised process, not a session.
+	if deacon.IsHeadless(d.config.TownRoot) {
+		d.ensureHeadlessDeaconRunning()
+		return
+	}
+
 	// Check restart tracker for backoff/crash loop
 	if d.restartTracker != nil {
 		if d.restartTracker.IsInCrashLoop(agentID) {
@@ -633,6 +882,7 @@ func (d *Daemon) ensureDeaconRunning() {
 	// The heartbeat file will still be stale until the Deacon runs a full patrol cycle.
 	d.deaconLastStarted = time.Now()
 	d.logger.Println("Deacon started successfully")
+	d.logDeacon(deacon.LogEventWake, "started Deacon session", nil)
 }
 
 // deaconGracePeriod is the time to wait after starting a Deacon before checking heartbeat.
@@ -700,8 +950,20 @@ func (d *Daemon) checkDeaconHeartbeat() {
 
 	age := hb.Age()
 
+	cfg, err := deacon.LoadConfigOrDefault(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Warning: invalid deacon config, using defaults: %v", err)
+	}
+
 	// If heartbeat is fresh, nothing to do
-	if !hb.IsVeryStale() {
+	if !cfg.IsVeryStale(hb) {
+		return
+	}
+
+	// The Deacon can't patrol without the model API; a stale heartbeat is
+	// expected until degraded mode ends.
+	if d.heartbeatsRelaxed() {
+		d.logger.Printf("Deacon heartbeat is stale (%s old) but town is degraded (model API down), not intervening", age.Round(time.Minute))
 		return
 	}
 
@@ -723,13 +985,27 @@ func (d *Daemon) checkDeaconHeartbeat() {
 	// Session exists but heartbeat is stale - Deacon is stuck
 	// PATCH-002: Reduced from 30m to 10m for faster recovery.
 	// Must be > backoff-max (5m) to avoid false positive kills during legitimate sleep.
-	if age > 10*time.Minute {
+	// Configurable as stuck_restart in deacon/config.json.
+	action := "nudging it"
+	if age > cfg.GetStuckRestart() {
+		action = "restarting it"
+	}
+	d.notify(deacon.Notification{
+		Event:   deacon.NotifyEventStaleHeartbeat,
+		Title:   "Deacon heartbeat is stale",
+		Message: fmt.Sprintf("No Deacon heartbeat for %s; the daemon is %s.", age.Round(time.Minute), action),
+		Fields:  map[string]string{"heartbeat_age": age.Round(time.Second).String(), "session": sessionName},
+	})
+	if age > cfg.GetStuckRestart() {
 		d.restartStuckDeacon(sessionName)
 	} else {
 		// Stuck but not critically - nudge to wake up
 		d.logger.Printf("Deacon stuck for %s - nudging session", age.Round(time.Minute))
 		if err := d.tmux.NudgeSession(sessionName, "HEALTH_CHECK: heartbeat stale, respond to confirm responsiveness"); err != nil {
 			d.logger.Printf("Error nudging stuck Deacon: %v", err)
+		} else {
+			d.logDeacon(deacon.LogEventWake, "nudged Deacon with stale heartbeat",
+				map[string]string{"heartbeat_age": age.Round(time.Second).String()})
 		}
 	}
 }
@@ -743,12 +1019,34 @@ func (d *Daemon) restartStuckDeacon(sessionName string) {
 		d.logger.Printf("Killing stuck Deacon session %s", sessionName)
 		if err := d.tmux.KillSessionWithProcesses(sessionName); err != nil {
 			d.logger.Printf("Error killing stuck Deacon: %v", err)
+		} else {
+			d.logDeacon(deacon.LogEventWake, "killed stuck Deacon session for restart", nil)
 		}
 	}
 	// Spawn new Deacon immediately
 	d.ensureDeaconRunning()
 }
 
+// logDeacon records a daemon action on the Deacon in the Deacon's log,
+// so `gt deacon logs` shows what woke it alongside its own cycles.
+func (d *Daemon) logDeacon(event, message string, fields map[string]string) {
+	if fields == nil {
+		fields = make(map[string]string)
+	}
+	fields["by"] = "daemon"
+	if err := deacon.AppendLog(d.config.TownRoot, deacon.LogEntry{Event: event, Message: message, Fields: fields}); err != nil {
+		d.logger.Printf("Warning: failed to write deacon log: %v", err)
+	}
+}
+
+// notify sends a high-signal event to the operator's notification sinks
+// (deacon config "notify"). Failures are logged, never fatal.
+func (d *Daemon) notify(n deacon.Notification) {
+	if err := deacon.Notify(d.config.TownRoot, n); err != nil {
+		d.logger.Printf("Warning: notification %s: %v", n.Event, err)
+	}
+}
+
 // ensureWitnessesRunning ensures witnesses are running for configured rigs.
 // Called on each heartbeat to maintain witness patrol loops.
 // Respects the rigs filter in daemon.json patrol config.
@@ -780,10 +1078,12 @@ func (d *Daemon) ensureWitnessRunning(rigName string) {
 	// Check for hung session before Start (which only detects process-dead zombies).
 	// A hung session has a live process but no tmux activity for an extended period,
 	// indicating Claude is stuck. Kill it so Start() can recreate a fresh one.
-	if status := mgr.IsHealthy(hungSessionThreshold); status == tmux.AgentHung {
+	if status := mgr.IsHealthy(hungSessionThreshold); status == tmux.AgentHung && !d.heartbeatsRelaxed() {
 		d.logger.Printf("Witness for %s is hung (no activity for %v), killing for restart", rigName, hungSessionThreshold)
 		t := tmux.NewTmux()
 		_ = t.KillSession(mgr.SessionName())
+	} else {
+		d.checkWitnessHeartbeat(r, mgr.SessionName())
 	}
 
 	if err := mgr.Start(false, "", nil); err != nil {
@@ -799,6 +1099,36 @@ func (d *Daemon) ensureWitnessRunning(rigName string) {
 	d.logger.Printf("Witness session for %s started successfully", rigName)
 }
 
+// checkWitnessHeartbeat kills a Witness session whose sweeps have stopped
+// (see witness.Heartbeat.NeedsRestart) so ensureWitnessRunning starts a
+// fresh one. A session can be active without patrolling, e.g. stuck in a
+// long tool call or a loop, which the hung-session check doesn't catch.
+func (d *Daemon) checkWitnessHeartbeat(r *rig.Rig, sessionName string) {
+	hb := witness.ReadHeartbeat(r.Path)
+	if hb == nil || d.heartbeatsRelaxed() {
+		return
+	}
+	created, err := d.tmux.GetSessionCreatedUnix(sessionName)
+	if err != nil || created == 0 {
+		return // No session: Start creates one
+	}
+	if !hb.NeedsRestart(time.Unix(created, 0), time.Now()) {
+		return
+	}
+
+	age := hb.Age().Round(time.Minute)
+	d.logger.Printf("Witness heartbeat for %s is stale (%s old), killing session for restart", r.Name, age)
+	d.notify(deacon.Notification{
+		Event:   deacon.NotifyEventStaleHeartbeat,
+		Title:   "Witness heartbeat is stale",
+		Message: fmt.Sprintf("No %s Witness sweep for %s; the daemon is restarting it.", r.Name, age),
+		Fields:  map[string]string{"rig": r.Name, "heartbeat_age": hb.Age().Round(time.Second).String(), "session": sessionName},
+	})
+	if err := d.tmux.KillSessionWithProcesses(sessionName); err != nil {
+		d.logger.Printf("Error killing stale Witness for %s: %v", r.Name, err)
+	}
+}
+
 // ensureRefineriesRunning ensures refineries are running for configured rigs.
 // Called on each heartbeat to maintain refinery merge queue processing.
 // Respects the rigs filter in daemon.json patrol config.
@@ -830,7 +1160,7 @@ func (d *Daemon) ensureRefineryRunning(rigName string) {
 	// Check for hung session before Start (which only detects process-dead zombies).
 	// A hung refinery means MRs pile up with no processing. Kill it so Start()
 	// can recreate a fresh one. See: gt-tr3d
-	if status := mgr.IsHealthy(hungSessionThreshold); status == tmux.AgentHung {
+	if status := mgr.IsHealthy(hungSessionThreshold); status == tmux.AgentHung && !d.heartbeatsRelaxed() {
 		d.logger.Printf("Refinery for %s is hung (no activity for %v), killing for restart", rigName, hungSessionThreshold)
 		t := tmux.NewTmux()
 		_ = t.KillSession(mgr.SessionName())
@@ -879,6 +1209,12 @@ func (d *Daemon) killDeaconSessions() {
 			}
 		}
 	}
+	if HeadlessDeaconProcess(d.config.TownRoot) != nil {
+		d.logger.Printf("Stopping leftover headless Deacon (patrol disabled)")
+		if err := StopHeadlessDeacon(d.config.TownRoot, 5*time.Second); err != nil {
+			d.logger.Printf("Error stopping headless Deacon: %v", err)
+		}
+	}
 }
 
 // killWitnessSessions kills leftover witness tmux sessions for all rigs.
@@ -1062,8 +1398,18 @@ func (d *Daemon) triggerPendingSpawns() {
 
 	d.logger.Printf("Found %d pending spawn(s), attempting to trigger...", len(pending))
 
+	cfg, _ := deacon.LoadConfigOrDefault(d.config.TownRoot)
+	policy := polecat.RetryPolicy{
+		MaxAttempts: cfg.GetPendingSpawnMaxAttempts(),
+		Backoff:     cfg.GetPendingSpawnBackoff(),
+	}
+
 	// Trigger pending spawns (uses WaitForRuntimeReady with short timeout)
-	results, err := polecat.TriggerPendingSpawns(d.config.TownRoot, triggerTimeout)
+	limits := polecat.TriggerLimits{
+		MaxPerRig:    cfg.GetMaxPolecatsPerRig(),
+		MaxPerMinute: cfg.GetMaxTriggersPerMinute(),
+	}
+	results, err := polecat.TriggerPendingSpawns(d.config.TownRoot, triggerTimeout, policy, limits)
 	if err != nil {
 		d.logger.Printf("Error triggering spawns: %v", err)
 		return
@@ -1075,6 +1421,13 @@ func (d *Daemon) triggerPendingSpawns() {
 		if r.Triggered {
 			triggered++
 			d.logger.Printf("Triggered polecat: %s/%s", r.Spawn.Rig, r.Spawn.Polecat)
+			d.logDeacon(deacon.LogEventSpawn, "triggered pending spawn",
+				map[string]string{"rig": r.Spawn.Rig, "polecat": r.Spawn.Polecat, "session": r.Spawn.Session})
+		} else if r.Escalated {
+			d.logger.Printf("Escalated %s/%s to mayor: not ready after %d checks", r.Spawn.Rig, r.Spawn.Polecat, r.Spawn.Attempts)
+			d.logDeacon(deacon.LogEventEscalation, "pending spawn never became ready",
+				map[string]string{"rig": r.Spawn.Rig, "polecat": r.Spawn.Polecat, "attempts": fmt.Sprint(r.Spawn.Attempts)})
+			d.notify(deacon.SpawnFailureNotification(r.Spawn.Rig, r.Spawn.Polecat, r.Spawn.Attempts))
 		} else if r.Error != nil {
 			d.logger.Printf("Error triggering %s: %v", r.Spawn.Session, r.Error)
 		}
@@ -1084,10 +1437,10 @@ func (d *Daemon) triggerPendingSpawns() {
 		d.logger.Printf("Triggered %d/%d pending spawn(s)", triggered, len(pending))
 	}
 
-	// Prune stale pending spawns (older than 5 minutes - likely dead sessions)
-	pruned, _ := polecat.PruneStalePending(d.config.TownRoot, 5*time.Minute)
+	// Escalate stale pending spawns (older than pending_spawn_max_age)
+	pruned, _ := polecat.PruneStalePending(d.config.TownRoot, cfg.GetPendingSpawnMaxAge())
 	if pruned > 0 {
-		d.logger.Printf("Pruned %d stale pending spawn(s)", pruned)
+		d.logger.Printf("Escalated %d stale pending spawn(s) to mayor", pruned)
 	}
 }
 
@@ -1113,6 +1466,9 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 	}
 	d.beadsStores = nil
 
+	// Stop forge webhook endpoint
+	d.stopForgeWebhook()
+
 	// Stop KRC pruner
 	if d.krcPruner != nil {
 		d.krcPruner.Stop()
@@ -1137,6 +1493,17 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 	return nil
 }
 
+// isDeaconPaused reports whether the Deacon is paused (gt deacon pause).
+// An unreadable pause file counts as not paused, so a corrupt file can't
+// silently stop supervision.
+func (d *Daemon) isDeaconPaused() bool {
+	paused, _, err := deacon.IsPaused(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Warning: reading deacon pause state: %v", err)
+	}
+	return paused
+}
+
 // Stop signals the daemon to stop.
 func (d *Daemon) Stop() {
 	d.cancel()
@@ -1291,8 +1658,13 @@ func StopDaemon(townRoot string) error {
 		return fmt.Errorf("sending SIGTERM: %w", err)
 	}
 
-	// Wait a bit for graceful shutdown
+	// Wait for the drain (see drain.go) to finish, bounded by its deadline
+	// plus time to persist state and send mail.
+	waitUntil := time.Now().Add(drainTimeout(LoadPatrolConfig(townRoot)) + drainGrace)
 	time.Sleep(constants.ShutdownNotifyDelay)
+	for time.Now().Before(waitUntil) && process.Signal(syscall.Signal(0)) == nil {
+		time.Sleep(constants.ShutdownNotifyDelay)
+	}
 
 	// Check if still running
 	if err := process.Signal(syscall.Signal(0)); err == nil {
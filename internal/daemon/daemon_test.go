@@ -2,6 +2,8 @@ package daemon
 
 import (
 	"encoding/json"
+	"io"
+	"log"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -10,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/deacon"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -291,6 +294,30 @@ func TestIsShutdownInProgress_NoLockFile(t *testing.T) {
 	}
 }
 
+func TestIsDeaconPaused(t *testing.T) {
+	tmpDir := t.TempDir()
+	d := &Daemon{
+		config: &Config{TownRoot: tmpDir},
+		logger: log.New(io.Discard, "", 0),
+	}
+
+	if d.isDeaconPaused() {
+		t.Error("expected not paused without a pause file")
+	}
+	if err := deacon.Pause(tmpDir, "upgrading", "human"); err != nil {
+		t.Fatal(err)
+	}
+	if !d.isDeaconPaused() {
+		t.Error("expected paused after gt deacon pause")
+	}
+	if err := deacon.Resume(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+	if d.isDeaconPaused() {
+		t.Error("expected not paused after resume")
+	}
+}
+
 func TestIsShutdownInProgress_StaleLockFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	lockDir := filepath.Join(tmpDir, "daemon")
@@ -0,0 +1,148 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deacon"
+)
+
+// headlessStaleAfter is how old the headless loop's heartbeat may get before
+// the daemon restarts it. The loop beats every cycle, so this only trips
+// when it is wedged.
+const headlessStaleAfter = 10 * time.Minute
+
+// StartHeadlessDeacon launches the Deacon's patrol loop (`gt deacon
+// run-headless`) as a detached process and records its PID. Output goes to
+// deacon/logs/headless.out. The process is detached from the caller's
+// process group so it outlives both `gt deacon start` and a daemon restart.
+func StartHeadlessDeacon(townRoot, gtPath string) (int, error) {
+	if gtPath == "" {
+		gtPath = "gt"
+	}
+	deaconDir := filepath.Join(townRoot, "deacon")
+	if err := os.MkdirAll(deacon.LogDir(townRoot), 0755); err != nil {
+		return 0, fmt.Errorf("creating deacon log dir: %w", err)
+	}
+	out, err := os.OpenFile(filepath.Join(deacon.LogDir(townRoot), "headless.out"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("opening headless output: %w", err)
+	}
+
+	cmd := exec.Command(gtPath, "deacon", "run-headless") //nolint:gosec // G204: gtPath is the resolved gt binary
+	cmd.Dir = deaconDir
+	cmd.Env = os.Environ()
+	for k, v := range config.AgentEnv(config.AgentEnvConfig{Role: "deacon", TownRoot: townRoot}) {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	cmd.Stdout = out
+	cmd.Stderr = out
+	setSysProcAttr(cmd)
+
+	if err := cmd.Start(); err != nil {
+		_ = out.Close()
+		return 0, fmt.Errorf("starting headless deacon: %w", err)
+	}
+	go func() {
+		_ = cmd.Wait()
+		_ = out.Close()
+	}()
+
+	state := deacon.ReadHeadlessState(townRoot)
+	if state == nil {
+		state = &deacon.HeadlessState{}
+	}
+	state.PID = cmd.Process.Pid
+	state.StartedAt = time.Now().UTC()
+	if err := deacon.WriteHeadlessState(townRoot, state); err != nil {
+		return state.PID, fmt.Errorf("recording headless deacon PID: %w", err)
+	}
+	return state.PID, nil
+}
+
+// HeadlessDeaconProcess returns the running headless loop, or nil if headless
+// mode is off or the recorded process is gone.
+func HeadlessDeaconProcess(townRoot string) *os.Process {
+	state := deacon.ReadHeadlessState(townRoot)
+	if state == nil || state.PID <= 0 {
+		return nil
+	}
+	p, err := os.FindProcess(state.PID)
+	if err != nil || !isProcessAlive(p) {
+		return nil
+	}
+	return p
+}
+
+// StopHeadlessDeacon stops the headless loop, escalating to a kill if it
+// doesn't exit within the timeout. Headless mode stays selected; callers
+// that want the tmux Deacon back also clear it.
+func StopHeadlessDeacon(townRoot string, timeout time.Duration) error {
+	p := HeadlessDeaconProcess(townRoot)
+	if p == nil {
+		return nil
+	}
+	if err := sendTermSignal(p); err != nil {
+		return fmt.Errorf("signaling headless deacon (PID %d): %w", p.Pid, err)
+	}
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(100 * time.Millisecond) {
+		if !isProcessAlive(p) {
+			return nil
+		}
+	}
+	return sendKillSignal(p)
+}
+
+// ensureHeadlessDeaconRunning supervises the headless Deacon loop: it is
+// started when not running and restarted when its heartbeat goes stale.
+// Restarts share the tmux Deacon's backoff and crash-loop tracking.
+func (d *Daemon) ensureHeadlessDeaconRunning() {
+	const agentID = "deacon"
+	townRoot := d.config.TownRoot
+
+	if p := HeadlessDeaconProcess(townRoot); p != nil {
+		state := deacon.ReadHeadlessState(townRoot)
+		hb := deacon.ReadHeartbeat(townRoot)
+		if state == nil || time.Since(state.StartedAt) < headlessStaleAfter || hb.Age() < headlessStaleAfter {
+			if d.restartTracker != nil {
+				d.restartTracker.RecordSuccess(agentID)
+			}
+			return
+		}
+		d.logger.Printf("Headless Deacon (PID %d) heartbeat stale (%s old), restarting", p.Pid, hb.Age().Round(time.Minute))
+		if err := StopHeadlessDeacon(townRoot, 5*time.Second); err != nil {
+			d.logger.Printf("Error stopping stale headless Deacon: %v", err)
+			return
+		}
+		d.logDeacon(deacon.LogEventWake, "killed stale headless Deacon for restart", nil)
+	}
+
+	if d.restartTracker != nil {
+		if d.restartTracker.IsInCrashLoop(agentID) {
+			d.logger.Printf("Headless Deacon is in crash loop, skipping restart (use 'gt daemon clear-backoff deacon' to reset)")
+			return
+		}
+		if !d.restartTracker.CanRestart(agentID) {
+			d.logger.Printf("Headless Deacon restart in backoff, %s remaining", d.restartTracker.GetBackoffRemaining(agentID).Round(time.Second))
+			return
+		}
+	}
+
+	pid, err := StartHeadlessDeacon(townRoot, d.gtPath)
+	if err != nil {
+		d.logger.Printf("Error starting headless Deacon: %v", err)
+		return
+	}
+	if d.restartTracker != nil {
+		d.restartTracker.RecordRestart(agentID)
+		if err := d.restartTracker.Save(); err != nil {
+			d.logger.Printf("Warning: failed to save restart state: %v", err)
+		}
+	}
+	d.logger.Printf("Headless Deacon started (PID %d)", pid)
+	d.logDeacon(deacon.LogEventWake, "started headless Deacon", map[string]string{"pid": fmt.Sprint(pid)})
+}
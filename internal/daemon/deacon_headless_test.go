@@ -0,0 +1,43 @@
+package daemon
+
+import (
+	"os"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/deacon"
+)
+
+func TestHeadlessDeaconProcess(t *testing.T) {
+	townRoot := t.TempDir()
+	if HeadlessDeaconProcess(townRoot) != nil {
+		t.Error("no process expected outside headless mode")
+	}
+
+	// Headless mode selected but not yet started.
+	if err := deacon.WriteHeadlessState(townRoot, &deacon.HeadlessState{}); err != nil {
+		t.Fatal(err)
+	}
+	if HeadlessDeaconProcess(townRoot) != nil {
+		t.Error("no process expected before the loop starts")
+	}
+
+	if err := deacon.WriteHeadlessState(townRoot, &deacon.HeadlessState{PID: os.Getpid()}); err != nil {
+		t.Fatal(err)
+	}
+	if p := HeadlessDeaconProcess(townRoot); p == nil || p.Pid != os.Getpid() {
+		t.Errorf("expected the recorded live process, got %v", p)
+	}
+}
+
+func TestStopHeadlessDeacon_NotRunning(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := deacon.WriteHeadlessState(townRoot, &deacon.HeadlessState{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := StopHeadlessDeacon(townRoot, 0); err != nil {
+		t.Errorf("stopping a loop that isn't running should be a no-op: %v", err)
+	}
+	if !deacon.IsHeadless(townRoot) {
+		t.Error("StopHeadlessDeacon must leave headless mode selected")
+	}
+}
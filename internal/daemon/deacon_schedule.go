@@ -0,0 +1,39 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/deacon"
+)
+
+// scheduleCheckInterval is how often the daemon looks for due scheduled
+// tasks (gt deacon schedule). Cron has minute resolution.
+const scheduleCheckInterval = time.Minute
+
+// runScheduledTasks runs the Deacon's due scheduled tasks in the
+// background so a slow task can't hold up the heartbeat. A check that
+// comes round while the previous batch is still running is skipped; the
+// tasks it would have found stay due for the next one.
+func (d *Daemon) runScheduledTasks() {
+	if d.isDeaconPaused() {
+		return
+	}
+	if !d.scheduleRunning.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer d.scheduleRunning.Store(false)
+		runs, err := deacon.RunDueTasks(d.ctx, d.config.TownRoot, time.Now())
+		if err != nil {
+			d.logger.Printf("schedule: %v", err)
+			return
+		}
+		for _, run := range runs {
+			if run.Err != nil {
+				d.logger.Printf("schedule: %s failed after %v: %v", run.Name, run.Duration.Round(time.Second), run.Err)
+			} else {
+				d.logger.Printf("schedule: %s ok (%v)", run.Name, run.Duration.Round(time.Second))
+			}
+		}
+	}()
+}
@@ -0,0 +1,58 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+// depUpdatesCheckInterval is how often the daemon checks whether any rig is
+// due for a dependency-update bead. The per-rig cadence itself is enforced by
+// the witness state file, so this only bounds how late an update can open.
+const depUpdatesCheckInterval = time.Hour
+
+// depUpdatesInterval returns the configured per-rig cadence, or the default (weekly).
+func depUpdatesInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.DepUpdates != nil {
+		if d, err := time.ParseDuration(config.Patrols.DepUpdates.Interval); err == nil && d > 0 {
+			return d
+		}
+	}
+	return witness.DefaultDepUpdateInterval
+}
+
+// openDependencyUpdates runs the witness dependency-update duty for each
+// operational rig. Non-fatal: errors are logged but don't stop the patrol.
+func (d *Daemon) openDependencyUpdates() {
+	if !IsPatrolEnabled(d.patrolConfig, "dep_updates") {
+		return
+	}
+
+	cfg := d.patrolConfig.Patrols.DepUpdates
+	opts := witness.DepUpdateOptions{
+		Interval:    depUpdatesInterval(d.patrolConfig),
+		Priority:    cfg.Priority,
+		MaxPriority: cfg.MaxPriority,
+		Labels:      cfg.Labels,
+	}
+
+	for _, rigName := range d.getPatrolRigs("dep_updates") {
+		if ok, reason := d.isRigOperational(rigName); !ok {
+			d.logger.Printf("dep_updates: %s: skipping (%s)", rigName, reason)
+			continue
+		}
+
+		result, err := witness.OpenDependencyUpdate(d.config.TownRoot, rigName, opts)
+		if err != nil {
+			d.logger.Printf("dep_updates: %s: %v", rigName, err)
+			continue
+		}
+		if !result.Opened {
+			continue
+		}
+
+		d.logger.Printf("dep_updates: %s: opened %s", rigName, result.BeadID)
+		_ = events.LogFeed(events.TypeDepUpdate, rigName+"/witness", events.DepUpdatePayload(rigName, result.BeadID))
+	}
+}
@@ -0,0 +1,170 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// defaultDrainTimeout bounds how long a SIGTERM drain waits for in-flight
+// merges when shutdown.drain_timeout is unset. It stays well under the
+// 90s systemd and launchd give a service before SIGKILL.
+const defaultDrainTimeout = 60 * time.Second
+
+// drainGrace is how long past the drain deadline StopDaemon waits for the
+// critical drain steps and regular shutdown before sending SIGKILL.
+const drainGrace = 30 * time.Second
+
+// drainPollInterval is how often in-flight merges are rechecked.
+const drainPollInterval = 2 * time.Second
+
+// townSuspendingRecipients are told the daemon is going away, so they stop
+// relying on it for restarts until it is back.
+var townSuspendingRecipients = []string{"deacon/", "mayor/"}
+
+// drainTimeout returns the configured drain deadline or the default.
+func drainTimeout(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Shutdown != nil {
+		if d, err := time.ParseDuration(config.Shutdown.DrainTimeout); err == nil && d >= 0 {
+			return d
+		}
+	}
+	return defaultDrainTimeout
+}
+
+// drainStep is one stage of a shutdown drain. Steps run in order; once the
+// deadline passes, only critical steps still run, so state is persisted and
+// supervisors are told even when merges overran.
+type drainStep struct {
+	name     string
+	critical bool
+	run      func(deadline time.Time) error
+}
+
+// runDrainSteps runs steps in priority order against the deadline and
+// returns the names of steps skipped because it had passed.
+func runDrainSteps(steps []drainStep, deadline time.Time, now func() time.Time, logf func(string, ...interface{})) []string {
+	var skipped []string
+	for _, step := range steps {
+		if !step.critical && !now().Before(deadline) {
+			logf("Drain: deadline passed, skipping %s", step.name)
+			skipped = append(skipped, step.name)
+			continue
+		}
+		if err := step.run(deadline); err != nil {
+			logf("Drain: %s: %v", step.name, err)
+		} else {
+			logf("Drain: %s done", step.name)
+		}
+	}
+	return skipped
+}
+
+// waitForMerges polls inFlight until it reports nothing or the deadline
+// passes. Returns what was still in flight at the deadline.
+func waitForMerges(inFlight func() []string, deadline time.Time, poll time.Duration, now func() time.Time, sleep func(time.Duration)) []string {
+	for {
+		pending := inFlight()
+		if len(pending) == 0 {
+			return nil
+		}
+		remaining := deadline.Sub(now())
+		if remaining <= 0 {
+			return pending
+		}
+		sleep(min(poll, remaining))
+	}
+}
+
+// drain shuts the town's supervision down gracefully on SIGTERM: it stops
+// initiating new work, gives in-flight merges until the deadline to land,
+// persists daemon state, and mails TOWN_SUSPENDING to the Deacon and Mayor.
+// The caller then runs the regular shutdown.
+func (d *Daemon) drain(state *State, reason string) {
+	timeout := drainTimeout(d.patrolConfig)
+	start := time.Now()
+	deadline := start.Add(timeout)
+	d.logger.Printf("Drain: %s, draining (deadline %v)", reason, timeout)
+
+	var unfinished []string
+	steps := []drainStep{
+		{name: "stop initiating work", critical: true, run: func(time.Time) error {
+			// The heartbeat loop is blocked in drain, so no restarts or
+			// patrol spawns happen; the convoy manager is the only
+			// background goroutine that dispatches work.
+			if d.convoyManager != nil {
+				d.convoyManager.Stop()
+				d.convoyManager = nil
+				d.beadsStores = nil
+			}
+			return nil
+		}},
+		{name: "wait for in-flight merges", run: func(deadline time.Time) error {
+			unfinished = waitForMerges(d.mergesInFlight, deadline, drainPollInterval, time.Now, time.Sleep)
+			if len(unfinished) > 0 {
+				return fmt.Errorf("still merging at deadline: %s", strings.Join(unfinished, ", "))
+			}
+			return nil
+		}},
+		{name: "persist state", critical: true, run: func(time.Time) error {
+			state.StopReason = reason
+			state.StoppedAt = time.Now()
+			return SaveState(d.config.TownRoot, state)
+		}},
+		{name: "notify supervisors", critical: true, run: func(time.Time) error {
+			return d.sendTownSuspending(reason, time.Since(start), unfinished)
+		}},
+	}
+	runDrainSteps(steps, deadline, time.Now, d.logger.Printf)
+}
+
+// mergesInFlight lists rigs whose refinery holds the merge slot, i.e. is
+// pushing a merge to the target branch right now. Rigs whose slot can't be
+// read are not waited on.
+func (d *Daemon) mergesInFlight() []string {
+	var busy []string
+	for _, rigName := range d.getKnownRigs() {
+		status, err := beads.New(filepath.Join(d.config.TownRoot, rigName)).MergeSlotCheck()
+		if err != nil || status == nil || status.Available || status.Holder == "" {
+			continue
+		}
+		busy = append(busy, fmt.Sprintf("%s (%s)", rigName, status.Holder))
+	}
+	sort.Strings(busy)
+	return busy
+}
+
+// sendTownSuspending mails the Deacon and Mayor that the daemon is stopping.
+func (d *Daemon) sendTownSuspending(reason string, drained time.Duration, unfinished []string) error {
+	subject := "TOWN_SUSPENDING: daemon shutting down"
+	body := fmt.Sprintf(`The daemon is shutting down (%s) after draining for %s.
+
+Until it is back, nothing restarts dead sessions, runs patrols, or
+dispatches stranded convoys. Avoid starting long-running work that relies
+on supervision; it resumes when 'gt daemon start' runs again.`,
+		reason, drained.Round(time.Second))
+	if len(unfinished) > 0 {
+		body += fmt.Sprintf("\n\nMerges still in flight at the drain deadline:\n  %s\nCheck the merge queue after restart.",
+			strings.Join(unfinished, "\n  "))
+	}
+
+	var failed []string
+	for _, to := range townSuspendingRecipients {
+		cmd := exec.Command(d.gtPath, "mail", "send", to, "-s", subject, "-m", body) //nolint:gosec // G204: args are constructed internally
+		cmd.Dir = d.config.TownRoot
+		cmd.Env = os.Environ() // Inherit PATH to find gt executable
+		if out, err := cmd.CombinedOutput(); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v (%s)", to, err, strings.TrimSpace(string(out))))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("mail send failed: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
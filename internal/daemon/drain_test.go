@@ -0,0 +1,115 @@
+package daemon
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDrainTimeout(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *DaemonPatrolConfig
+		want   time.Duration
+	}{
+		{"nil config", nil, defaultDrainTimeout},
+		{"no shutdown section", &DaemonPatrolConfig{}, defaultDrainTimeout},
+		{"configured", &DaemonPatrolConfig{Shutdown: &ShutdownConfig{DrainTimeout: "90s"}}, 90 * time.Second},
+		{"zero disables waiting", &DaemonPatrolConfig{Shutdown: &ShutdownConfig{DrainTimeout: "0s"}}, 0},
+		{"invalid", &DaemonPatrolConfig{Shutdown: &ShutdownConfig{DrainTimeout: "soon"}}, defaultDrainTimeout},
+		{"negative", &DaemonPatrolConfig{Shutdown: &ShutdownConfig{DrainTimeout: "-5s"}}, defaultDrainTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := drainTimeout(tt.config); got != tt.want {
+				t.Errorf("drainTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunDrainSteps_Order(t *testing.T) {
+	var ran []string
+	step := func(name string, critical bool) drainStep {
+		return drainStep{name: name, critical: critical, run: func(time.Time) error {
+			ran = append(ran, name)
+			return nil
+		}}
+	}
+	now := time.Now()
+	steps := []drainStep{step("stop", true), step("merges", false), step("persist", true), step("notify", true)}
+
+	skipped := runDrainSteps(steps, now.Add(time.Minute), func() time.Time { return now }, t.Logf)
+	if len(skipped) != 0 {
+		t.Errorf("skipped = %v, want none before deadline", skipped)
+	}
+	if want := []string{"stop", "merges", "persist", "notify"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran = %v, want %v", ran, want)
+	}
+}
+
+func TestRunDrainSteps_DeadlineSkipsBestEffort(t *testing.T) {
+	var ran []string
+	now := time.Now()
+	clock := now
+	steps := []drainStep{
+		{name: "slow", run: func(deadline time.Time) error {
+			ran = append(ran, "slow")
+			clock = deadline // overran the deadline
+			return nil
+		}},
+		{name: "optional", run: func(time.Time) error {
+			ran = append(ran, "optional")
+			return nil
+		}},
+		{name: "persist", critical: true, run: func(time.Time) error {
+			ran = append(ran, "persist")
+			return nil
+		}},
+	}
+
+	skipped := runDrainSteps(steps, now.Add(time.Second), func() time.Time { return clock }, t.Logf)
+	if want := []string{"optional"}; !reflect.DeepEqual(skipped, want) {
+		t.Errorf("skipped = %v, want %v", skipped, want)
+	}
+	if want := []string{"slow", "persist"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran = %v, want %v (critical steps run past the deadline)", ran, want)
+	}
+}
+
+func TestWaitForMerges(t *testing.T) {
+	start := time.Now()
+
+	t.Run("merges finish", func(t *testing.T) {
+		clock := start
+		polls := 0
+		inFlight := func() []string {
+			polls++
+			if polls < 3 {
+				return []string{"gastown (refinery)"}
+			}
+			return nil
+		}
+		left := waitForMerges(inFlight, start.Add(time.Minute), 2*time.Second,
+			func() time.Time { return clock }, func(d time.Duration) { clock = clock.Add(d) })
+		if left != nil {
+			t.Errorf("left = %v, want nil", left)
+		}
+		if polls != 3 {
+			t.Errorf("polls = %d, want 3", polls)
+		}
+	})
+
+	t.Run("deadline passes", func(t *testing.T) {
+		clock := start
+		inFlight := func() []string { return []string{"gastown (refinery)"} }
+		left := waitForMerges(inFlight, start.Add(5*time.Second), 2*time.Second,
+			func() time.Time { return clock }, func(d time.Duration) { clock = clock.Add(d) })
+		if len(left) != 1 {
+			t.Errorf("left = %v, want the in-flight merge", left)
+		}
+		if clock.After(start.Add(5 * time.Second)) {
+			t.Errorf("slept past the deadline: %v", clock.Sub(start))
+		}
+	})
+}
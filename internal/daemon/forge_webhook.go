@@ -0,0 +1,190 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// defaultForgeWebhookListen is the address the webhook endpoint listens on
+// when forge_webhook.listen is unset. Loopback only: expose it through a
+// reverse proxy or tunnel rather than binding it publicly by default.
+const defaultForgeWebhookListen = "127.0.0.1:8788"
+
+// forgeWebhookSecretEnv supplies the shared secret when
+// forge_webhook.secret is unset, to keep it out of daemon.json.
+const forgeWebhookSecretEnv = "GT_FORGE_WEBHOOK_SECRET"
+
+// maxForgeWebhookBody caps webhook payloads; PR events are a few KB.
+const maxForgeWebhookBody = 5 << 20
+
+// forgeWebhookSettings returns the listen address and shared secret.
+func forgeWebhookSettings(config *DaemonPatrolConfig) (listen, secret string) {
+	listen = defaultForgeWebhookListen
+	if config != nil && config.Patrols != nil && config.Patrols.ForgeWebhook != nil {
+		if config.Patrols.ForgeWebhook.Listen != "" {
+			listen = config.Patrols.ForgeWebhook.Listen
+		}
+		secret = config.Patrols.ForgeWebhook.Secret
+	}
+	if secret == "" {
+		secret = os.Getenv(forgeWebhookSecretEnv)
+	}
+	return listen, secret
+}
+
+// forgeWebhookHandler serves POST /forge/<rig> for GitHub and GitLab pull
+// request webhooks, creating or refreshing the rig's MR beads.
+type forgeWebhookHandler struct {
+	secret   string
+	knownRig func(name string) bool
+	ingest   func(rigName string, ev *refinery.ForgeEvent) (*refinery.ForgeIngestResult, error)
+	notify   func(rigName string, ev *refinery.ForgeEvent, res *refinery.ForgeIngestResult)
+	logf     func(format string, args ...interface{})
+
+	// mu serializes ingestion so a burst of events for one PR (opened,
+	// then labeled) can't race into two MR beads.
+	mu sync.Mutex
+}
+
+func (h *forgeWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	rigName, ok := strings.CutPrefix(r.URL.Path, "/forge/")
+	if !ok || rigName == "" || strings.Contains(rigName, "/") || !h.knownRig(rigName) {
+		http.Error(w, "unknown rig", http.StatusNotFound)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxForgeWebhookBody))
+	if err != nil {
+		http.Error(w, "reading body", http.StatusBadRequest)
+		return
+	}
+
+	var ev *refinery.ForgeEvent
+	switch {
+	case r.Header.Get("X-GitHub-Event") != "":
+		if !refinery.VerifyGitHubSignature(h.secret, body, r.Header.Get("X-Hub-Signature-256")) {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		ev, err = refinery.ParseGitHubEvent(r.Header.Get("X-GitHub-Event"), body)
+	case r.Header.Get("X-Gitlab-Event") != "":
+		if !refinery.VerifyGitLabToken(h.secret, r.Header.Get("X-Gitlab-Token")) {
+			http.Error(w, "bad token", http.StatusUnauthorized)
+			return
+		}
+		ev, err = refinery.ParseGitLabEvent(body)
+	default:
+		http.Error(w, "not a GitHub or GitLab webhook", http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, refinery.ErrIgnoredEvent) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = fmt.Fprintln(w, "ignored")
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	res, err := h.ingest(rigName, ev)
+	h.mu.Unlock()
+	if err != nil {
+		h.logf("forge_webhook: %s %s: %v", rigName, ev.Ref(), err)
+		http.Error(w, "ingest failed", http.StatusInternalServerError)
+		return
+	}
+	h.logf("forge_webhook: %s %s %s: %s %s", rigName, ev.Ref(), ev.Action, res.Outcome, strings.Join(res.Changes, ", "))
+	if h.notify != nil && (res.Outcome == "created" || res.Outcome == "updated") {
+		h.notify(rigName, ev, res)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// startForgeWebhook starts the forge webhook endpoint. It is not started
+// without a shared secret: unauthenticated events could enqueue any branch.
+func (d *Daemon) startForgeWebhook() {
+	listen, secret := forgeWebhookSettings(d.patrolConfig)
+	if secret == "" {
+		d.logger.Printf("forge_webhook: not started: set forge_webhook.secret or %s", forgeWebhookSecretEnv)
+		return
+	}
+
+	townRoot := d.config.TownRoot
+	h := &forgeWebhookHandler{
+		secret: secret,
+		knownRig: func(name string) bool {
+			return slices.Contains(d.getKnownRigs(), name)
+		},
+		ingest: func(rigName string, ev *refinery.ForgeEvent) (*refinery.ForgeIngestResult, error) {
+			r := &rig.Rig{Name: rigName, Path: filepath.Join(townRoot, rigName)}
+			return refinery.IngestForgeEvent(r, ev)
+		},
+		notify: func(rigName string, ev *refinery.ForgeEvent, res *refinery.ForgeIngestResult) {
+			// Queue for the refinery's next turn boundary, like gt mq submit.
+			msg := fmt.Sprintf("MR %s from %s: %s branch=%s", res.Outcome, ev.Ref(), strings.Join(res.Changes, ", "), ev.Branch)
+			if err := nudge.Enqueue(townRoot, session.RefinerySessionName(session.PrefixFor(rigName)), nudge.QueuedNudge{
+				Sender:  "daemon",
+				Message: msg,
+			}); err != nil {
+				d.logger.Printf("forge_webhook: nudging %s refinery: %v", rigName, err)
+			}
+		},
+		logf: d.logger.Printf,
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/forge/", h)
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		d.logger.Printf("forge_webhook: listening on %s: %v", listen, err)
+		return
+	}
+	d.forgeWebhook = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := d.forgeWebhook.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			d.logger.Printf("forge_webhook: %v", err)
+		}
+	}()
+	d.logger.Printf("Forge webhook endpoint started on http://%s/forge/<rig>", ln.Addr())
+}
+
+// stopForgeWebhook shuts the webhook endpoint down, letting in-flight
+// events finish.
+func (d *Daemon) stopForgeWebhook() {
+	if d.forgeWebhook == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := d.forgeWebhook.Shutdown(ctx); err != nil {
+		d.logger.Printf("Warning: forge webhook shutdown: %v", err)
+	}
+	d.forgeWebhook = nil
+	d.logger.Println("Forge webhook endpoint stopped")
+}
@@ -0,0 +1,77 @@
+package daemon
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+func TestIsPatrolEnabled_ForgeWebhook(t *testing.T) {
+	if IsPatrolEnabled(nil, "forge_webhook") {
+		t.Error("expected forge_webhook to be disabled with nil config")
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{ForgeWebhook: &ForgeWebhookConfig{Enabled: true}}}
+	if !IsPatrolEnabled(config, "forge_webhook") {
+		t.Error("expected forge_webhook to be enabled when configured")
+	}
+	if listen, _ := forgeWebhookSettings(config); listen != defaultForgeWebhookListen {
+		t.Errorf("listen = %q, want default", listen)
+	}
+}
+
+func TestForgeWebhookHandler(t *testing.T) {
+	const secret = "s3cret"
+	body := `{"action":"opened","pull_request":{"number":4,"head":{"ref":"feat/x","sha":"abc"},"base":{"ref":"main"}},"repository":{"full_name":"acme/widgets"}}`
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	sig := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	var ingested, notified []string
+	h := &forgeWebhookHandler{
+		secret:   secret,
+		knownRig: func(name string) bool { return name == "widgets" },
+		ingest: func(rigName string, ev *refinery.ForgeEvent) (*refinery.ForgeIngestResult, error) {
+			ingested = append(ingested, rigName+" "+ev.Ref())
+			return &refinery.ForgeIngestResult{MRID: "gt-mr1", Outcome: "created"}, nil
+		},
+		notify: func(rigName string, _ *refinery.ForgeEvent, _ *refinery.ForgeIngestResult) {
+			notified = append(notified, rigName)
+		},
+		logf: t.Logf,
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		event   string
+		sig     string
+		payload string
+		want    int
+	}{
+		{"valid", "/forge/widgets", "pull_request", sig, body, http.StatusOK},
+		{"bad signature", "/forge/widgets", "pull_request", "sha256=00", body, http.StatusUnauthorized},
+		{"unknown rig", "/forge/nope", "pull_request", sig, body, http.StatusNotFound},
+		{"ignored event", "/forge/widgets", "issues", sig, body, http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.payload))
+			req.Header.Set("X-GitHub-Event", tt.event)
+			req.Header.Set("X-Hub-Signature-256", tt.sig)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+	if len(ingested) != 1 || ingested[0] != "widgets github:acme/widgets#4" || len(notified) != 1 {
+		t.Errorf("ingested %v, notified %v", ingested, notified)
+	}
+}
@@ -0,0 +1,386 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// inboxNagCheckInterval is how often the daemon scans agent inboxes.
+// The per-agent nag cadence is bounded separately by NagInterval.
+const inboxNagCheckInterval = 10 * time.Minute
+
+// Inbox nag defaults, used when daemon.json leaves a threshold unset.
+const (
+	defaultInboxNagMaxUnread     = 5
+	defaultInboxNagMaxAge        = 2 * time.Hour
+	defaultInboxNagInterval      = 30 * time.Minute
+	defaultInboxNagEscalateAfter = 3
+
+	// inboxNagMaxSubjects caps how many subjects a nag summary lists.
+	inboxNagMaxSubjects = 5
+)
+
+// inboxNagThresholds are the resolved inbox_nag settings.
+type inboxNagThresholds struct {
+	MaxUnread     int
+	MaxAge        time.Duration
+	NagInterval   time.Duration
+	EscalateAfter int
+}
+
+// inboxNagSettings resolves the inbox_nag thresholds from config, applying defaults.
+func inboxNagSettings(config *DaemonPatrolConfig) inboxNagThresholds {
+	th := inboxNagThresholds{
+		MaxUnread:     defaultInboxNagMaxUnread,
+		MaxAge:        defaultInboxNagMaxAge,
+		NagInterval:   defaultInboxNagInterval,
+		EscalateAfter: defaultInboxNagEscalateAfter,
+	}
+	if config == nil || config.Patrols == nil || config.Patrols.InboxNag == nil {
+		return th
+	}
+	cfg := config.Patrols.InboxNag
+	if cfg.MaxUnread > 0 {
+		th.MaxUnread = cfg.MaxUnread
+	}
+	if d, err := time.ParseDuration(cfg.MaxAge); err == nil && d > 0 {
+		th.MaxAge = d
+	}
+	if d, err := time.ParseDuration(cfg.NagInterval); err == nil && d > 0 {
+		th.NagInterval = d
+	}
+	if cfg.EscalateAfter > 0 {
+		th.EscalateAfter = cfg.EscalateAfter
+	}
+	return th
+}
+
+// inboxNagState is persisted in daemon/inbox_nag.json so nag counts survive
+// daemon restarts. Agents drop out of the map once their inbox is healthy.
+type inboxNagState struct {
+	Agents map[string]*agentInboxNag `json:"agents"`
+}
+
+// agentInboxNag tracks one agent's current neglect episode.
+type agentInboxNag struct {
+	Unread       int       `json:"unread"`
+	OldestUnread time.Time `json:"oldest_unread"`
+	Nags         int       `json:"nags"`
+	LastNag      time.Time `json:"last_nag"`
+	Escalated    bool      `json:"escalated,omitempty"`
+}
+
+// inboxSummary describes an agent's unread actionable mail.
+type inboxSummary struct {
+	Address  string
+	Unread   int
+	Oldest   time.Time
+	Subjects []string // Oldest first
+}
+
+// inboxNagAction is what the patrol does for one agent on one pass.
+type inboxNagAction int
+
+const (
+	inboxNagNone     inboxNagAction = iota // Healthy, or nagged recently
+	inboxNagNudge                          // Append an inbox summary to the agent's next nudge
+	inboxNagEscalate                       // Nudge and escalate to the supervisor
+)
+
+// inboxNagDeps are the side effects of an inbox nag pass.
+// Allows mocking in tests.
+type inboxNagDeps struct {
+	listUnread func(address string) ([]*mail.Message, error)
+	nudge      func(sessionName, text string) error
+	escalate   func(address, to string, summary inboxSummary) error
+}
+
+// InboxNagStateFile returns the path to the inbox nag state file.
+func InboxNagStateFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "inbox_nag.json")
+}
+
+func loadInboxNagState(townRoot string) (*inboxNagState, error) {
+	state := &inboxNagState{Agents: make(map[string]*agentInboxNag)}
+	data, err := os.ReadFile(InboxNagStateFile(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing inbox nag state: %w", err)
+	}
+	if state.Agents == nil {
+		state.Agents = make(map[string]*agentInboxNag)
+	}
+	return state, nil
+}
+
+func saveInboxNagState(townRoot string, state *inboxNagState) error {
+	path := InboxNagStateFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, state)
+}
+
+// isActionableMail reports whether an unread message expects a response:
+// tasks, replies, and anything sent high priority or above. Plain
+// notifications can sit unread without blocking coordination.
+func isActionableMail(msg *mail.Message) bool {
+	if msg.Read {
+		return false
+	}
+	switch msg.Type {
+	case mail.TypeTask, mail.TypeReply:
+		return true
+	}
+	return msg.Priority == mail.PriorityHigh || msg.Priority == mail.PriorityUrgent
+}
+
+// summarizeInbox collects the unread actionable messages for an agent.
+func summarizeInbox(address string, msgs []*mail.Message) inboxSummary {
+	var actionable []*mail.Message
+	for _, msg := range msgs {
+		if isActionableMail(msg) {
+			actionable = append(actionable, msg)
+		}
+	}
+	sort.Slice(actionable, func(i, j int) bool {
+		return actionable[i].Timestamp.Before(actionable[j].Timestamp)
+	})
+
+	s := inboxSummary{Address: address, Unread: len(actionable)}
+	for i, msg := range actionable {
+		if i == 0 {
+			s.Oldest = msg.Timestamp
+		}
+		if i < inboxNagMaxSubjects {
+			s.Subjects = append(s.Subjects, msg.Subject)
+		}
+	}
+	return s
+}
+
+// neglected reports whether the summary crosses either nag threshold.
+func (th inboxNagThresholds) neglected(s inboxSummary, now time.Time) bool {
+	if s.Unread == 0 {
+		return false
+	}
+	return s.Unread >= th.MaxUnread || now.Sub(s.Oldest) >= th.MaxAge
+}
+
+// evaluateInboxNag updates an agent's neglect record and decides what to do.
+// It returns the (possibly new) record, or nil when the inbox is healthy and
+// the record should be dropped.
+func evaluateInboxNag(rec *agentInboxNag, s inboxSummary, th inboxNagThresholds, now time.Time) (*agentInboxNag, inboxNagAction) {
+	if !th.neglected(s, now) {
+		return nil, inboxNagNone
+	}
+	if rec == nil {
+		rec = &agentInboxNag{}
+	}
+	rec.Unread = s.Unread
+	rec.OldestUnread = s.Oldest
+
+	if !rec.LastNag.IsZero() && now.Sub(rec.LastNag) < th.NagInterval {
+		return rec, inboxNagNone
+	}
+	rec.Nags++
+	rec.LastNag = now
+
+	if rec.Nags > th.EscalateAfter && !rec.Escalated {
+		rec.Escalated = true
+		return rec, inboxNagEscalate
+	}
+	return rec, inboxNagNudge
+}
+
+// inboxNagEscalationTarget returns who hears about an agent ignoring its mail.
+// Rig workers escalate to their witness, the witness to the deacon, the
+// deacon to the mayor, and the mayor to the overseer.
+func inboxNagEscalationTarget(id *session.AgentIdentity) string {
+	switch id.Role {
+	case session.RoleMayor:
+		return "overseer"
+	case session.RoleDeacon:
+		return "mayor/"
+	case session.RoleWitness:
+		return "deacon/"
+	default:
+		return id.Rig + "/witness"
+	}
+}
+
+// formatInboxNag renders the summary appended to an agent's next nudge.
+func formatInboxNag(s inboxSummary, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "INBOX: %d unread actionable message(s), oldest %s old.",
+		s.Unread, now.Sub(s.Oldest).Round(time.Minute))
+	for _, subj := range s.Subjects {
+		fmt.Fprintf(&b, "\n  - %s", subj)
+	}
+	if more := s.Unread - len(s.Subjects); more > 0 {
+		fmt.Fprintf(&b, "\n  ... and %d more", more)
+	}
+	b.WriteString("\nRun 'gt mail inbox' and process your mail before continuing.")
+	return b.String()
+}
+
+// runInboxNag checks the inboxes of the given agent sessions once and
+// nags or escalates as needed. Records for agents no longer running are
+// dropped so a restarted agent starts a fresh episode.
+func runInboxNag(state *inboxNagState, sessions map[string]*session.AgentIdentity, th inboxNagThresholds, deps inboxNagDeps, now time.Time) []error {
+	var errs []error
+	active := make(map[string]bool, len(sessions))
+
+	names := make([]string, 0, len(sessions))
+	for name := range sessions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, sessionName := range names {
+		id := sessions[sessionName]
+		address := id.Address()
+		active[address] = true
+
+		msgs, err := deps.listUnread(address)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: listing inbox: %w", address, err))
+			continue
+		}
+		summary := summarizeInbox(address, msgs)
+
+		rec, action := evaluateInboxNag(state.Agents[address], summary, th, now)
+		if rec == nil {
+			delete(state.Agents, address)
+			continue
+		}
+		state.Agents[address] = rec
+
+		if action == inboxNagNone {
+			continue
+		}
+		if err := deps.nudge(sessionName, formatInboxNag(summary, now)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: nudging: %w", address, err))
+		}
+		if action == inboxNagEscalate {
+			if err := deps.escalate(address, inboxNagEscalationTarget(id), summary); err != nil {
+				errs = append(errs, fmt.Errorf("%s: escalating: %w", address, err))
+			}
+		}
+	}
+
+	for address := range state.Agents {
+		if !active[address] {
+			delete(state.Agents, address)
+		}
+	}
+	return errs
+}
+
+// inboxNagSessions maps running agent tmux sessions to their identities.
+func (d *Daemon) inboxNagSessions() map[string]*session.AgentIdentity {
+	names, err := d.tmux.ListSessions()
+	if err != nil {
+		d.logger.Printf("inbox_nag: listing sessions: %v", err)
+		return nil
+	}
+	sessions := make(map[string]*session.AgentIdentity)
+	for _, name := range names {
+		id, err := session.ParseSessionName(name)
+		if err != nil || id.Address() == "" || id.Role == session.RoleOverseer {
+			continue
+		}
+		sessions[name] = id
+	}
+	return sessions
+}
+
+// checkInboxNag runs the inbox_nag patrol: agents sitting on unread actionable
+// mail get an inbox summary appended to their next nudge, and repeated neglect
+// is escalated by mail so coordination never fails silently.
+func (d *Daemon) checkInboxNag() {
+	if !IsPatrolEnabled(d.patrolConfig, "inbox_nag") {
+		return
+	}
+
+	townRoot := d.config.TownRoot
+	state, err := loadInboxNagState(townRoot)
+	if err != nil {
+		d.logger.Printf("inbox_nag: %v", err)
+		return
+	}
+
+	router := mail.NewRouter(townRoot)
+	deps := inboxNagDeps{
+		listUnread: func(address string) ([]*mail.Message, error) {
+			mailbox, err := router.GetMailbox(address)
+			if err != nil {
+				return nil, err
+			}
+			return mailbox.ListUnread()
+		},
+		nudge: func(sessionName, text string) error {
+			return nudge.Enqueue(townRoot, sessionName, nudge.QueuedNudge{
+				Sender:  "daemon",
+				Message: text,
+			})
+		},
+		escalate: d.escalateInboxNeglect,
+	}
+
+	th := inboxNagSettings(d.patrolConfig)
+	for _, err := range runInboxNag(state, d.inboxNagSessions(), th, deps, time.Now()) {
+		d.logger.Printf("inbox_nag: %v", err)
+	}
+
+	if err := saveInboxNagState(townRoot, state); err != nil {
+		d.logger.Printf("inbox_nag: saving state: %v", err)
+	}
+}
+
+// escalateInboxNeglect mails the supervisor about an agent ignoring its inbox.
+func (d *Daemon) escalateInboxNeglect(address, to string, summary inboxSummary) error {
+	subject := fmt.Sprintf("INBOX_NEGLECTED: %s has %d unread actionable message(s)", address, summary.Unread)
+	body := fmt.Sprintf(`Agent %s has ignored repeated inbox nags.
+
+unread: %d
+oldest_unread: %s
+
+%s
+
+The agent may be stuck or wedged in a loop. Check its session and nudge or restart it.`,
+		address, summary.Unread, summary.Oldest.Format(time.RFC3339),
+		strings.Join(summary.Subjects, "\n"))
+
+	cmd := exec.Command(d.gtPath, "mail", "send", to, "-s", subject, "-m", body) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	cmd.Env = os.Environ() // Inherit PATH to find gt executable
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("mail send to %s: %w (%s)", to, err, strings.TrimSpace(string(out)))
+	}
+
+	rig := ""
+	if id, err := session.ParseAddress(address); err == nil {
+		rig = id.Rig
+	}
+	d.logger.Printf("inbox_nag: escalated %s to %s (%d unread)", address, to, summary.Unread)
+	_ = events.LogFeed(events.TypeEscalationSent, "daemon", events.EscalationPayload(rig, address, to, "inbox neglected"))
+	return nil
+}
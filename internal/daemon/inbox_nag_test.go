@@ -0,0 +1,213 @@
+package daemon
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func taskMail(subject string, sent time.Time) *mail.Message {
+	return &mail.Message{Subject: subject, Timestamp: sent, Type: mail.TypeTask, Priority: mail.PriorityNormal}
+}
+
+func TestIsActionableMail(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *mail.Message
+		want bool
+	}{
+		{"task", &mail.Message{Type: mail.TypeTask}, true},
+		{"reply", &mail.Message{Type: mail.TypeReply}, true},
+		{"notification", &mail.Message{Type: mail.TypeNotification, Priority: mail.PriorityNormal}, false},
+		{"urgent notification", &mail.Message{Type: mail.TypeNotification, Priority: mail.PriorityUrgent}, true},
+		{"read task", &mail.Message{Type: mail.TypeTask, Read: true}, false},
+	}
+	for _, tt := range tests {
+		if got := isActionableMail(tt.msg); got != tt.want {
+			t.Errorf("%s: isActionableMail = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestInboxNagSettings(t *testing.T) {
+	th := inboxNagSettings(nil)
+	if th.MaxUnread != defaultInboxNagMaxUnread || th.MaxAge != defaultInboxNagMaxAge ||
+		th.NagInterval != defaultInboxNagInterval || th.EscalateAfter != defaultInboxNagEscalateAfter {
+		t.Errorf("defaults = %+v", th)
+	}
+
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{InboxNag: &InboxNagConfig{
+		Enabled:       true,
+		MaxUnread:     2,
+		MaxAge:        "45m",
+		NagInterval:   "bogus",
+		EscalateAfter: 1,
+	}}}
+	th = inboxNagSettings(config)
+	if th.MaxUnread != 2 || th.MaxAge != 45*time.Minute || th.EscalateAfter != 1 {
+		t.Errorf("configured thresholds not applied: %+v", th)
+	}
+	if th.NagInterval != defaultInboxNagInterval {
+		t.Errorf("invalid nag_interval should fall back to default, got %v", th.NagInterval)
+	}
+}
+
+func TestEvaluateInboxNag_EscalatesAfterRepeatedNeglect(t *testing.T) {
+	th := inboxNagThresholds{MaxUnread: 5, MaxAge: time.Hour, NagInterval: 30 * time.Minute, EscalateAfter: 2}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	summary := inboxSummary{Unread: 1, Oldest: now.Add(-2 * time.Hour)}
+
+	rec, action := evaluateInboxNag(nil, summary, th, now)
+	if action != inboxNagNudge || rec.Nags != 1 {
+		t.Fatalf("first pass: action=%v rec=%+v, want nudge with 1 nag", action, rec)
+	}
+
+	// Within the nag interval: no repeat
+	if _, action = evaluateInboxNag(rec, summary, th, now.Add(10*time.Minute)); action != inboxNagNone {
+		t.Fatalf("within nag interval: action=%v, want none", action)
+	}
+
+	now = now.Add(30 * time.Minute)
+	if rec, action = evaluateInboxNag(rec, summary, th, now); action != inboxNagNudge {
+		t.Fatalf("second nag: action=%v, want nudge", action)
+	}
+
+	now = now.Add(30 * time.Minute)
+	if rec, action = evaluateInboxNag(rec, summary, th, now); action != inboxNagEscalate || !rec.Escalated {
+		t.Fatalf("third nag: action=%v rec=%+v, want escalate", action, rec)
+	}
+
+	// Escalation happens once per neglect episode
+	now = now.Add(30 * time.Minute)
+	if _, action = evaluateInboxNag(rec, summary, th, now); action != inboxNagNudge {
+		t.Fatalf("after escalation: action=%v, want plain nudge", action)
+	}
+
+	// Inbox cleared: record dropped
+	if rec, action = evaluateInboxNag(rec, inboxSummary{}, th, now); rec != nil || action != inboxNagNone {
+		t.Fatalf("healthy inbox: rec=%+v action=%v, want nil/none", rec, action)
+	}
+}
+
+func TestEvaluateInboxNag_UnreadThreshold(t *testing.T) {
+	th := inboxNagThresholds{MaxUnread: 3, MaxAge: 24 * time.Hour, NagInterval: time.Minute, EscalateAfter: 3}
+	now := time.Now()
+
+	if _, action := evaluateInboxNag(nil, inboxSummary{Unread: 2, Oldest: now}, th, now); action != inboxNagNone {
+		t.Errorf("below thresholds: action=%v, want none", action)
+	}
+	if _, action := evaluateInboxNag(nil, inboxSummary{Unread: 3, Oldest: now}, th, now); action != inboxNagNudge {
+		t.Errorf("at unread threshold: action=%v, want nudge", action)
+	}
+}
+
+func TestRunInboxNag(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	th := inboxNagThresholds{MaxUnread: 2, MaxAge: time.Hour, NagInterval: time.Minute, EscalateAfter: 0}
+
+	inboxes := map[string][]*mail.Message{
+		"gastown/polecats/Toast": {
+			taskMail("MERGE_FAILED gt-abc", now.Add(-3*time.Hour)),
+			{Subject: "fyi", Timestamp: now.Add(-5 * time.Hour), Type: mail.TypeNotification},
+		},
+		"gastown/witness": {},
+	}
+	sessions := map[string]*session.AgentIdentity{
+		"gt-gastown-Toast":   {Role: session.RolePolecat, Rig: "gastown", Name: "Toast"},
+		"gt-gastown-witness": {Role: session.RoleWitness, Rig: "gastown"},
+		"gt-gastown-broken":  {Role: session.RolePolecat, Rig: "gastown", Name: "broken"},
+	}
+
+	nudged := make(map[string]string)
+	var escalations []string
+	deps := inboxNagDeps{
+		listUnread: func(address string) ([]*mail.Message, error) {
+			if strings.HasSuffix(address, "broken") {
+				return nil, errors.New("bd unavailable")
+			}
+			return inboxes[address], nil
+		},
+		nudge: func(sessionName, text string) error {
+			nudged[sessionName] = text
+			return nil
+		},
+		escalate: func(address, to string, summary inboxSummary) error {
+			escalations = append(escalations, address+"->"+to)
+			return nil
+		},
+	}
+
+	state := &inboxNagState{Agents: map[string]*agentInboxNag{
+		"gastown/polecats/gone": {Nags: 4}, // no longer running
+	}}
+	errs := runInboxNag(state, sessions, th, deps, now)
+	if len(errs) != 1 {
+		t.Errorf("expected 1 error for the broken inbox, got %v", errs)
+	}
+
+	text, ok := nudged["gt-gastown-Toast"]
+	if !ok {
+		t.Fatal("expected Toast to be nudged")
+	}
+	if !strings.Contains(text, "1 unread actionable") || !strings.Contains(text, "MERGE_FAILED gt-abc") {
+		t.Errorf("nudge text missing summary: %q", text)
+	}
+	if strings.Contains(text, "fyi") {
+		t.Errorf("notification should not be listed as actionable: %q", text)
+	}
+	if _, ok := nudged["gt-gastown-witness"]; ok {
+		t.Error("witness with an empty inbox should not be nudged")
+	}
+
+	if len(escalations) != 1 || escalations[0] != "gastown/polecats/Toast->gastown/witness" {
+		t.Errorf("escalations = %v, want Toast escalated to its witness", escalations)
+	}
+	if _, ok := state.Agents["gastown/polecats/gone"]; ok {
+		t.Error("record for an agent no longer running should be dropped")
+	}
+	if rec := state.Agents["gastown/polecats/Toast"]; rec == nil || rec.Nags != 1 {
+		t.Errorf("Toast record = %+v, want 1 nag", rec)
+	}
+}
+
+func TestInboxNagEscalationTarget(t *testing.T) {
+	tests := []struct {
+		id   *session.AgentIdentity
+		want string
+	}{
+		{&session.AgentIdentity{Role: session.RolePolecat, Rig: "gastown", Name: "Toast"}, "gastown/witness"},
+		{&session.AgentIdentity{Role: session.RoleCrew, Rig: "gastown", Name: "max"}, "gastown/witness"},
+		{&session.AgentIdentity{Role: session.RoleWitness, Rig: "gastown"}, "deacon/"},
+		{&session.AgentIdentity{Role: session.RoleDeacon}, "mayor/"},
+		{&session.AgentIdentity{Role: session.RoleMayor}, "overseer"},
+	}
+	for _, tt := range tests {
+		if got := inboxNagEscalationTarget(tt.id); got != tt.want {
+			t.Errorf("inboxNagEscalationTarget(%s) = %q, want %q", tt.id.Address(), got, tt.want)
+		}
+	}
+}
+
+func TestInboxNagStateRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	state, err := loadInboxNagState(townRoot)
+	if err != nil || len(state.Agents) != 0 {
+		t.Fatalf("empty load: state=%+v err=%v", state, err)
+	}
+
+	state.Agents["mayor"] = &agentInboxNag{Unread: 3, Nags: 2}
+	if err := saveInboxNagState(townRoot, state); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadInboxNagState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec := loaded.Agents["mayor"]; rec == nil || rec.Unread != 3 || rec.Nags != 2 {
+		t.Errorf("round trip = %+v", loaded.Agents)
+	}
+}
@@ -0,0 +1,54 @@
+package daemon
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/issuesync"
+)
+
+// defaultIssueSyncInterval is how often rigs are synced with their issue
+// trackers when issue_sync.interval is unset.
+const defaultIssueSyncInterval = 5 * time.Minute
+
+// issueSyncInterval returns the configured sync interval or the default.
+func issueSyncInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.IssueSync != nil {
+		if d, err := time.ParseDuration(config.Patrols.IssueSync.Interval); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultIssueSyncInterval
+}
+
+// syncIssues syncs every patrolled rig that has issue_sync settings, in the
+// background: the trackers are remote and may be slow. A tick that comes
+// round while a sync is still running is skipped.
+func (d *Daemon) syncIssues() {
+	if !d.issueSyncRunning.CompareAndSwap(false, true) {
+		return
+	}
+	rigs := d.getPatrolRigs("issue_sync")
+	go func() {
+		defer d.issueSyncRunning.Store(false)
+		for _, rigName := range rigs {
+			settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(d.config.TownRoot, rigName)))
+			if err != nil || settings.IssueSync == nil {
+				continue
+			}
+			results, err := issuesync.SyncRig(d.ctx, d.config.TownRoot, rigName, settings.IssueSync, false)
+			if err != nil {
+				d.logger.Printf("issue_sync: %s: %v", rigName, err)
+			}
+			for tracker, result := range results {
+				for _, w := range result.Warnings {
+					d.logger.Printf("issue_sync: %s/%s: %s", rigName, tracker, w)
+				}
+				if len(result.Actions) > 0 {
+					d.logger.Printf("issue_sync: %s/%s: %d change(s)", rigName, tracker, len(result.Actions))
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,29 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsPatrolEnabled_IssueSync(t *testing.T) {
+	if IsPatrolEnabled(nil, "issue_sync") {
+		t.Error("expected issue_sync to be disabled with nil config")
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{IssueSync: &IssueSyncConfig{Enabled: true, Rigs: []string{"gastown"}}}}
+	if !IsPatrolEnabled(config, "issue_sync") {
+		t.Error("expected issue_sync to be enabled when configured")
+	}
+	if rigs := GetPatrolRigs(config, "issue_sync"); len(rigs) != 1 || rigs[0] != "gastown" {
+		t.Errorf("GetPatrolRigs = %v, want [gastown]", rigs)
+	}
+}
+
+func TestIssueSyncInterval(t *testing.T) {
+	if got := issueSyncInterval(nil); got != defaultIssueSyncInterval {
+		t.Errorf("nil config: got %v, want %v", got, defaultIssueSyncInterval)
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{IssueSync: &IssueSyncConfig{Interval: "30s"}}}
+	if got := issueSyncInterval(config); got != 30*time.Second {
+		t.Errorf("got %v, want 30s", got)
+	}
+}
@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/metrics"
+)
+
+// defaultMetricsPushInterval is how often metrics are pushed when
+// metrics_push.interval is unset.
+const defaultMetricsPushInterval = 60 * time.Second
+
+// metricsPushInterval returns the configured push interval or the default.
+func metricsPushInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.MetricsPush != nil {
+		if d, err := time.ParseDuration(config.Patrols.MetricsPush.Interval); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultMetricsPushInterval
+}
+
+// MetricsPushOptions resolves the StatsD push options from the metrics_push
+// section of daemon.json. A missing section yields the defaults, so
+// 'gt metrics push' works against a local agent without any config.
+func MetricsPushOptions(config *DaemonPatrolConfig) (metrics.StatsDOptions, error) {
+	opts := metrics.StatsDOptions{Prefix: metrics.DefaultPrefix}
+	if config == nil || config.Patrols == nil || config.Patrols.MetricsPush == nil {
+		return opts, nil
+	}
+	cfg := config.Patrols.MetricsPush
+	flavor, err := metrics.ParseFlavor(cfg.Flavor)
+	if err != nil {
+		return opts, err
+	}
+	opts.Flavor = flavor
+	opts.Address = cfg.Address
+	opts.Tags = cfg.Tags
+	if cfg.Prefix != "" {
+		opts.Prefix = cfg.Prefix
+	}
+	return opts, nil
+}
+
+// pushMetrics collects town metrics and pushes them to the configured
+// StatsD agent. Non-fatal: a missing agent is logged and retried next tick.
+func (d *Daemon) pushMetrics(state *State) {
+	if !IsPatrolEnabled(d.patrolConfig, "metrics_push") {
+		return
+	}
+
+	opts, err := MetricsPushOptions(d.patrolConfig)
+	if err != nil {
+		d.logger.Printf("metrics_push: %v", err)
+		return
+	}
+
+	sessions, err := d.tmux.ListSessions()
+	if err != nil {
+		d.logger.Printf("metrics_push: listing sessions: %v", err)
+	}
+	snap := metrics.Snapshot{
+		Sessions:       sessions,
+		DaemonRunning:  true,
+		HeartbeatCount: state.HeartbeatCount,
+		LastHeartbeat:  state.LastHeartbeat,
+	}
+
+	if err := metrics.Push(metrics.Collect(d.config.TownRoot, snap, time.Now()), opts); err != nil {
+		d.logger.Printf("metrics_push: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/metrics"
+)
+
+func TestIsPatrolEnabled_MetricsPush(t *testing.T) {
+	// metrics_push is opt-in: no agent to push to by default
+	if IsPatrolEnabled(nil, "metrics_push") {
+		t.Error("expected metrics_push to be disabled with nil config")
+	}
+
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{}}
+	if IsPatrolEnabled(config, "metrics_push") {
+		t.Error("expected metrics_push to be disabled by default")
+	}
+
+	config.Patrols.MetricsPush = &MetricsPushConfig{Enabled: true}
+	if !IsPatrolEnabled(config, "metrics_push") {
+		t.Error("expected metrics_push to be enabled when configured")
+	}
+}
+
+func TestMetricsPushInterval(t *testing.T) {
+	if got := metricsPushInterval(nil); got != defaultMetricsPushInterval {
+		t.Errorf("expected default interval, got %v", got)
+	}
+
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{MetricsPush: &MetricsPushConfig{Interval: "15s"}}}
+	if got := metricsPushInterval(config); got != 15*time.Second {
+		t.Errorf("expected 15s, got %v", got)
+	}
+
+	config.Patrols.MetricsPush.Interval = "soon"
+	if got := metricsPushInterval(config); got != defaultMetricsPushInterval {
+		t.Errorf("invalid interval should fall back to default, got %v", got)
+	}
+}
+
+func TestMetricsPushOptions(t *testing.T) {
+	opts, err := MetricsPushOptions(nil)
+	if err != nil || opts.Prefix != metrics.DefaultPrefix {
+		t.Errorf("defaults = %+v, %v", opts, err)
+	}
+
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{MetricsPush: &MetricsPushConfig{
+		Enabled: true,
+		Address: "statsd:8125",
+		Prefix:  "town",
+		Flavor:  "datadog",
+		Tags:    []string{"env:prod"},
+	}}}
+	opts, err = MetricsPushOptions(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Address != "statsd:8125" || opts.Prefix != "town" || opts.Flavor != metrics.FlavorDatadog || len(opts.Tags) != 1 {
+		t.Errorf("configured options not applied: %+v", opts)
+	}
+
+	config.Patrols.MetricsPush.Flavor = "graphite"
+	if _, err := MetricsPushOptions(config); err == nil {
+		t.Error("expected error for unknown flavor")
+	}
+}
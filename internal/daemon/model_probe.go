@@ -0,0 +1,79 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/degraded"
+)
+
+// defaultModelProbeInterval is how often the model API is probed when
+// model_probe.interval is unset.
+const defaultModelProbeInterval = time.Minute
+
+// modelProbeInterval returns the configured probe interval or the default.
+func modelProbeInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.ModelProbe != nil {
+		if d, err := time.ParseDuration(config.Patrols.ModelProbe.Interval); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultModelProbeInterval
+}
+
+// modelProbeURL returns the configured probe endpoint or the default.
+func modelProbeURL(config *DaemonPatrolConfig) string {
+	if config != nil && config.Patrols != nil && config.Patrols.ModelProbe != nil && config.Patrols.ModelProbe.URL != "" {
+		return config.Patrols.ModelProbe.URL
+	}
+	return degraded.ProbeURL()
+}
+
+// probeModelAPI probes the model API and records the result, entering
+// degraded mode after repeated failures and leaving it once the API answers.
+func (d *Daemon) probeModelAPI() {
+	ctx, cancel := context.WithTimeout(d.ctx, 15*time.Second)
+	defer cancel()
+
+	url := modelProbeURL(d.patrolConfig)
+	probeErr := degraded.Probe(ctx, nil, url)
+	if d.ctx.Err() != nil {
+		return // Shutting down; the failure is ours, not the API's
+	}
+
+	wasActive := degraded.IsActive(d.config.TownRoot)
+	state, err := degraded.RecordProbe(d.config.TownRoot, probeErr)
+	if err != nil {
+		d.logger.Printf("model_probe: %v", err)
+		return
+	}
+	switch {
+	case state.Active && !wasActive:
+		d.logger.Printf("model_probe: %s unreachable %d times, entering degraded mode: %v", url, state.ProbeFailures, probeErr)
+		d.notify(deacon.Notification{
+			Event:   deacon.NotifyEventCircuit,
+			Key:     "circuit:open",
+			Title:   "Model API unreachable: town degraded",
+			Message: fmt.Sprintf("%s failed %d probes in a row (%v). Spawns are refused until it recovers.", url, state.ProbeFailures, probeErr),
+		})
+	case !state.Active && wasActive:
+		d.logger.Printf("model_probe: %s reachable again, leaving degraded mode", url)
+		d.notify(deacon.Notification{
+			Event:   deacon.NotifyEventCircuit,
+			Key:     "circuit:closed",
+			Title:   "Model API reachable again",
+			Message: fmt.Sprintf("%s answered a probe; the town has left degraded mode.", url),
+		})
+	case probeErr != nil:
+		d.logger.Printf("model_probe: %s unreachable (%d consecutive): %v", url, state.ProbeFailures, probeErr)
+	}
+}
+
+// heartbeatsRelaxed reports whether stale heartbeats and idle sessions
+// should be tolerated: while the model API is down, agents can't make
+// progress, and restarting them only burns launches that will fail too.
+func (d *Daemon) heartbeatsRelaxed() bool {
+	return degraded.IsActive(d.config.TownRoot)
+}
@@ -0,0 +1,47 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/degraded"
+)
+
+func TestModelProbeEnabledByDefault(t *testing.T) {
+	if !IsPatrolEnabled(nil, "model_probe") {
+		t.Error("model_probe should be enabled with no config")
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{}}
+	if !IsPatrolEnabled(cfg, "model_probe") {
+		t.Error("model_probe should be enabled when unset")
+	}
+	cfg.Patrols.ModelProbe = &ModelProbeConfig{Enabled: false}
+	if IsPatrolEnabled(cfg, "model_probe") {
+		t.Error("model_probe should be disabled when enabled=false")
+	}
+}
+
+func TestModelProbeInterval(t *testing.T) {
+	if got := modelProbeInterval(nil); got != defaultModelProbeInterval {
+		t.Errorf("modelProbeInterval(nil) = %v, want %v", got, defaultModelProbeInterval)
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{ModelProbe: &ModelProbeConfig{Enabled: true, Interval: "5m"}}}
+	if got := modelProbeInterval(cfg); got != 5*time.Minute {
+		t.Errorf("modelProbeInterval = %v, want 5m", got)
+	}
+	cfg.Patrols.ModelProbe.Interval = "bogus"
+	if got := modelProbeInterval(cfg); got != defaultModelProbeInterval {
+		t.Errorf("modelProbeInterval(bogus) = %v, want default", got)
+	}
+}
+
+func TestModelProbeURL(t *testing.T) {
+	t.Setenv("ANTHROPIC_BASE_URL", "")
+	if got := modelProbeURL(nil); got != degraded.DefaultProbeURL {
+		t.Errorf("modelProbeURL(nil) = %q, want %q", got, degraded.DefaultProbeURL)
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{ModelProbe: &ModelProbeConfig{Enabled: true, URL: "http://proxy:8080"}}}
+	if got := modelProbeURL(cfg); got != "http://proxy:8080" {
+		t.Errorf("modelProbeURL = %q, want configured URL", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadPatrolConfig(t *testing.T) {
@@ -98,3 +99,61 @@ func TestDoltRemotesInterval(t *testing.T) {
 		t.Errorf("expected 5m interval, got %v", got)
 	}
 }
+
+func TestIsPatrolEnabled_DepUpdates(t *testing.T) {
+	// dep_updates is opt-in like dolt_remotes
+	if IsPatrolEnabled(nil, "dep_updates") {
+		t.Error("expected dep_updates to be disabled with nil config")
+	}
+
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{}}
+	if IsPatrolEnabled(config, "dep_updates") {
+		t.Error("expected dep_updates to be disabled by default")
+	}
+
+	config.Patrols.DepUpdates = &DepUpdatesConfig{Enabled: true, Rigs: []string{"gastown"}}
+	if !IsPatrolEnabled(config, "dep_updates") {
+		t.Error("expected dep_updates to be enabled when configured")
+	}
+	if rigs := GetPatrolRigs(config, "dep_updates"); len(rigs) != 1 || rigs[0] != "gastown" {
+		t.Errorf("GetPatrolRigs(dep_updates) = %v, want [gastown]", rigs)
+	}
+}
+
+func TestIsPatrolEnabled_InboxNag(t *testing.T) {
+	// inbox_nag is opt-in like dolt_remotes
+	if IsPatrolEnabled(nil, "inbox_nag") {
+		t.Error("expected inbox_nag to be disabled with nil config")
+	}
+
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{}}
+	if IsPatrolEnabled(config, "inbox_nag") {
+		t.Error("expected inbox_nag to be disabled by default")
+	}
+
+	config.Patrols.InboxNag = &InboxNagConfig{Enabled: true}
+	if !IsPatrolEnabled(config, "inbox_nag") {
+		t.Error("expected inbox_nag to be enabled when configured")
+	}
+}
+
+func TestDepUpdatesInterval(t *testing.T) {
+	if got := depUpdatesInterval(nil); got != 7*24*time.Hour {
+		t.Errorf("expected weekly default, got %v", got)
+	}
+
+	config := &DaemonPatrolConfig{
+		Patrols: &PatrolsConfig{
+			DepUpdates: &DepUpdatesConfig{Enabled: true, Interval: "72h"},
+		},
+	}
+	if got := depUpdatesInterval(config); got != 72*time.Hour {
+		t.Errorf("expected 72h interval, got %v", got)
+	}
+
+	// Unparseable interval falls back to the default
+	config.Patrols.DepUpdates.Interval = "fortnightly"
+	if got := depUpdatesInterval(config); got != 7*24*time.Hour {
+		t.Errorf("expected default for invalid interval, got %v", got)
+	}
+}
@@ -0,0 +1,59 @@
+package daemon
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+// defaultPolecatResourcesInterval is how often polecat sessions are sampled
+// when polecat_resources.interval is unset.
+const defaultPolecatResourcesInterval = time.Minute
+
+// polecatResourcesInterval returns the configured sampling interval or the default.
+func polecatResourcesInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.PolecatResources != nil {
+		if d, err := time.ParseDuration(config.Patrols.PolecatResources.Interval); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultPolecatResourcesInterval
+}
+
+// monitorPolecatResources runs the witness resource monitor for each
+// operational rig. Non-fatal: errors are logged but don't stop the patrol.
+func (d *Daemon) monitorPolecatResources() {
+	if !IsPatrolEnabled(d.patrolConfig, "polecat_resources") {
+		return
+	}
+
+	router := mail.NewRouter(d.config.TownRoot)
+	for _, rigName := range d.getPatrolRigs("polecat_resources") {
+		if ok, _ := d.isRigOperational(rigName); !ok {
+			continue
+		}
+		rigPath := filepath.Join(d.config.TownRoot, rigName)
+		cfg, err := witness.LoadResourceBudgetConfig(rigPath)
+		if err != nil {
+			d.logger.Printf("polecat_resources: %s: %v", rigName, err)
+			continue
+		}
+
+		result := witness.MonitorPolecatResources(rigPath, rigName, router, cfg)
+		for _, e := range result.Errors {
+			d.logger.Printf("polecat_resources: %s: %s", rigName, e)
+		}
+		for _, pr := range result.Polecats {
+			switch {
+			case pr.Error != "":
+				d.logger.Printf("polecat_resources: %s/%s: %s", rigName, pr.Polecat, pr.Error)
+			case pr.Tripped:
+				d.logger.Printf("polecat_resources: %s/%s: tripped circuit (%s)", rigName, pr.Polecat, pr.Usage)
+			case pr.Escalated:
+				d.logger.Printf("polecat_resources: %s/%s: escalated to mayor (%s)", rigName, pr.Polecat, pr.Usage)
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsPatrolEnabled_PolecatResources(t *testing.T) {
+	if IsPatrolEnabled(nil, "polecat_resources") {
+		t.Error("expected polecat_resources to be disabled with nil config")
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{PolecatResources: &PolecatResourcesConfig{Enabled: true, Rigs: []string{"gastown"}}}}
+	if !IsPatrolEnabled(config, "polecat_resources") {
+		t.Error("expected polecat_resources to be enabled when configured")
+	}
+	if rigs := GetPatrolRigs(config, "polecat_resources"); len(rigs) != 1 || rigs[0] != "gastown" {
+		t.Errorf("GetPatrolRigs = %v, want [gastown]", rigs)
+	}
+}
+
+func TestPolecatResourcesInterval(t *testing.T) {
+	if got := polecatResourcesInterval(nil); got != defaultPolecatResourcesInterval {
+		t.Errorf("nil config: got %v, want %v", got, defaultPolecatResourcesInterval)
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{PolecatResources: &PolecatResourcesConfig{Interval: "30s"}}}
+	if got := polecatResourcesInterval(config); got != 30*time.Second {
+		t.Errorf("got %v, want 30s", got)
+	}
+	config.Patrols.PolecatResources.Interval = "soon"
+	if got := polecatResourcesInterval(config); got != defaultPolecatResourcesInterval {
+		t.Errorf("bad interval: got %v, want default", got)
+	}
+}
@@ -5,24 +5,37 @@ package daemon
 import (
 	"os"
 	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/windows"
 )
 
+// stillActive is the exit code GetExitCodeProcess reports for a running process.
+const stillActive = 259
+
 // setSysProcAttr sets platform-specific process attributes.
-// On Windows, no special attributes needed for process group detachment.
+// On Windows, the child gets its own process group and no console, so a
+// Ctrl+C in the launching terminal doesn't reach it and it outlives the parent.
 func setSysProcAttr(cmd *exec.Cmd) {
-	// No-op on Windows - process will run independently
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: windows.CREATE_NEW_PROCESS_GROUP | windows.DETACHED_PROCESS,
+	}
 }
 
 // isProcessAlive checks if a process is still running.
-// On Windows, we try to open the process with minimal access.
+// On Windows, we open the process with minimal access and check its exit code.
 func isProcessAlive(p *os.Process) bool {
-	// On Windows, FindProcess always succeeds, and Signal(0) may not work.
-	// The best we can do is try to signal and see if it fails.
-	// A killed process will return an error.
-	err := p.Signal(os.Signal(nil))
-	// If err is nil or "not supported", process may still be alive
-	// If err mentions "finished" or similar, process is dead
-	return err == nil
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(p.Pid))
+	if err != nil {
+		return false
+	}
+	defer func() { _ = windows.CloseHandle(handle) }()
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(handle, &code); err != nil {
+		return false
+	}
+	return code == stillActive
 }
 
 // sendTermSignal sends a termination signal.
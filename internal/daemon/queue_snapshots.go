@@ -0,0 +1,50 @@
+package daemon
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// defaultQueueSnapsInterval is how often queue snapshots are recorded when
+// queue_snapshots.interval is unset.
+const defaultQueueSnapsInterval = 15 * time.Minute
+
+// queueSnapsInterval returns the configured snapshot interval or the default.
+func queueSnapsInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.QueueSnaps != nil {
+		if d, err := time.ParseDuration(config.Patrols.QueueSnaps.Interval); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultQueueSnapsInterval
+}
+
+// recordQueueSnapshots appends every rig's merge queue to the snapshot
+// history and prunes days past retention. Non-fatal: a rig whose queue
+// can't be read is logged and skipped.
+func (d *Daemon) recordQueueSnapshots() {
+	if !IsPatrolEnabled(d.patrolConfig, "queue_snapshots") {
+		return
+	}
+
+	now := time.Now()
+	var snaps []refinery.QueueSnapshot
+	for _, rigName := range d.getKnownRigs() {
+		r := &rig.Rig{Name: rigName, Path: filepath.Join(d.config.TownRoot, rigName)}
+		snap, err := refinery.NewManager(r).Snapshot(now)
+		if err != nil {
+			d.logger.Printf("queue_snapshots: %s: %v", rigName, err)
+			continue
+		}
+		snaps = append(snaps, snap)
+	}
+	if err := refinery.AppendSnapshots(d.config.TownRoot, snaps); err != nil {
+		d.logger.Printf("queue_snapshots: %v", err)
+	}
+	if _, err := refinery.PruneSnapshots(d.config.TownRoot, d.patrolConfig.Patrols.QueueSnaps.RetainDays, now); err != nil {
+		d.logger.Printf("queue_snapshots: pruning: %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/steveyegge/gastown/internal/replica"
+)
+
+// defaultReplicaInterval is how often the replica is rebuilt when
+// replica.interval is unset.
+const defaultReplicaInterval = 10 * time.Minute
+
+// replicaInterval returns the configured refresh interval or the default.
+func replicaInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.Replica != nil {
+		if d, err := time.ParseDuration(config.Patrols.Replica.Interval); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultReplicaInterval
+}
+
+// refreshReplica rebuilds the replica in the background: gathering every
+// rig's beads can take a while and must not hold up the heartbeat. A tick
+// that comes round while a rebuild is still running is skipped.
+func (d *Daemon) refreshReplica() {
+	if !d.replicaRunning.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer d.replicaRunning.Store(false)
+		start := time.Now()
+		snap, err := replica.Refresh(d.ctx, d.config.TownRoot, d.getKnownRigs())
+		if err != nil {
+			d.logger.Printf("replica: %v", err)
+			return
+		}
+		for _, w := range snap.Warnings {
+			d.logger.Printf("replica: %s", w)
+		}
+		d.logger.Printf("replica: refreshed %d beads, %d events, %d queued MRs in %v",
+			len(snap.Beads), len(snap.Events), len(snap.Queue), time.Since(start).Round(time.Millisecond))
+	}()
+}
@@ -0,0 +1,30 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsPatrolEnabled_Replica(t *testing.T) {
+	if IsPatrolEnabled(nil, "replica") {
+		t.Error("expected replica to be disabled with nil config")
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{Replica: &ReplicaConfig{Enabled: true}}}
+	if !IsPatrolEnabled(config, "replica") {
+		t.Error("expected replica to be enabled when configured")
+	}
+}
+
+func TestReplicaInterval(t *testing.T) {
+	if got := replicaInterval(nil); got != defaultReplicaInterval {
+		t.Errorf("nil config: got %v, want %v", got, defaultReplicaInterval)
+	}
+	config := &DaemonPatrolConfig{Patrols: &PatrolsConfig{Replica: &ReplicaConfig{Interval: "2m"}}}
+	if got := replicaInterval(config); got != 2*time.Minute {
+		t.Errorf("got %v, want 2m", got)
+	}
+	config.Patrols.Replica.Interval = "soon"
+	if got := replicaInterval(config); got != defaultReplicaInterval {
+		t.Errorf("bad interval: got %v, want default", got)
+	}
+}
@@ -0,0 +1,192 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/dog"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// oneShotSession is a running session started with one-shot limits.
+type oneShotSession struct {
+	Name         string
+	Limits       session.OneShotLimits
+	Created      time.Time
+	LastActivity time.Time
+}
+
+// reapedSession records a one-shot session the reaper cleaned up.
+type reapedSession struct {
+	Name        string
+	Reason      string
+	CapturePath string
+	Summary     string
+}
+
+// sessionReaperDeps are the side effects of reaping, injectable for tests.
+type sessionReaperDeps struct {
+	capture func(name string) (string, error)
+	kill    func(name string) error
+	release func(name string) error
+
+	// summaryLines is how many trailing non-empty pane lines go into a
+	// reaped session's summary (the Deacon config's capture_lines).
+	summaryLines int
+}
+
+// SessionReaperDir returns the directory where reaped sessions' panes are saved.
+func SessionReaperDir(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "reaped")
+}
+
+// summarizeReapedPane renders a short summary of a reaped session: why it
+// was reaped and the last lines it printed.
+func summarizeReapedPane(s oneShotSession, reason, pane string, maxLines int, now time.Time) string {
+	var tail []string
+	lines := strings.Split(pane, "\n")
+	for i := len(lines) - 1; i >= 0 && len(tail) < maxLines; i-- {
+		if line := strings.TrimRight(lines[i], " \t\r"); strings.TrimSpace(line) != "" {
+			tail = append([]string{line}, tail...)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Session: %s\n", s.Name)
+	fmt.Fprintf(&b, "Reaped: %s\n", reason)
+	if !s.Created.IsZero() {
+		fmt.Fprintf(&b, "Age: %v\n", now.Sub(s.Created).Round(time.Second))
+	}
+	if !s.LastActivity.IsZero() {
+		fmt.Fprintf(&b, "Idle: %v\n", now.Sub(s.LastActivity).Round(time.Second))
+	}
+	if len(tail) == 0 {
+		b.WriteString("Last output: (none)\n")
+	} else {
+		b.WriteString("Last output:\n")
+		for _, line := range tail {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// runSessionReaper reaps the one-shot sessions that have outlived their
+// limits: each pane is captured to captureDir with a summary header, then
+// the session is killed and its owner released. A failed capture doesn't
+// spare the session; a stuck helper is worse than a lost transcript.
+func runSessionReaper(sessions []oneShotSession, deps sessionReaperDeps, captureDir string, now time.Time) ([]reapedSession, []error) {
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Name < sessions[j].Name })
+
+	var reaped []reapedSession
+	var errs []error
+	for _, s := range sessions {
+		reason := s.Limits.Exceeded(s.Created, s.LastActivity, now)
+		if reason == "" {
+			continue
+		}
+
+		pane, err := deps.capture(s.Name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: capturing pane: %w", s.Name, err))
+		}
+		r := reapedSession{Name: s.Name, Reason: reason, Summary: summarizeReapedPane(s, reason, pane, deps.summaryLines, now)}
+
+		if err := os.MkdirAll(captureDir, 0755); err != nil {
+			errs = append(errs, fmt.Errorf("%s: creating capture dir: %w", s.Name, err))
+		} else {
+			path := filepath.Join(captureDir, fmt.Sprintf("%s-%s.log", s.Name, now.Format("20060102-150405")))
+			if err := os.WriteFile(path, []byte(r.Summary+"\n"+pane), 0644); err != nil {
+				errs = append(errs, fmt.Errorf("%s: saving capture: %w", s.Name, err))
+			} else {
+				r.CapturePath = path
+			}
+		}
+
+		if err := deps.kill(s.Name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: killing session: %w", s.Name, err))
+			continue
+		}
+		if deps.release != nil {
+			if err := deps.release(s.Name); err != nil {
+				errs = append(errs, fmt.Errorf("%s: releasing: %w", s.Name, err))
+			}
+		}
+		reaped = append(reaped, r)
+	}
+	return reaped, errs
+}
+
+// oneShotSessions lists running sessions that were started with one-shot limits.
+func (d *Daemon) oneShotSessions() []oneShotSession {
+	names, err := d.tmux.ListSessions()
+	if err != nil {
+		d.logger.Printf("session_reaper: listing sessions: %v", err)
+		return nil
+	}
+	var sessions []oneShotSession
+	for _, name := range names {
+		limits := session.ReadOneShotLimits(d.tmux, name)
+		if limits.IsZero() {
+			continue
+		}
+		s := oneShotSession{Name: name, Limits: limits}
+		if created, err := d.tmux.GetSessionCreatedUnix(name); err == nil && created > 0 {
+			s.Created = time.Unix(created, 0)
+		}
+		if activity, err := d.tmux.GetSessionActivity(name); err == nil {
+			s.LastActivity = activity
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions
+}
+
+// releaseReapedSession returns a reaped dog to the kennel so it can be
+// reassigned. Other one-shot sessions hold no state to release.
+func (d *Daemon) releaseReapedSession(name string) error {
+	dogName, ok := strings.CutPrefix(name, session.HQPrefix+"dog-")
+	if !ok {
+		return nil
+	}
+	return dog.NewManager(d.config.TownRoot, nil).ClearWork(dogName)
+}
+
+// reapOneShotSessions runs the session_reaper patrol: one-shot helpers
+// (Boot triage, dogs) that outlive their max lifetime or idle timeout are
+// captured, summarized, and cleaned up so they don't accumulate.
+func (d *Daemon) reapOneShotSessions() {
+	if !IsPatrolEnabled(d.patrolConfig, "session_reaper") || d.heartbeatsRelaxed() {
+		return
+	}
+
+	cfg, err := deacon.LoadConfigOrDefault(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("session_reaper: invalid deacon config, using defaults: %v", err)
+	}
+	deps := sessionReaperDeps{
+		capture:      d.tmux.CapturePaneAll,
+		kill:         d.tmux.KillSessionWithProcesses,
+		release:      d.releaseReapedSession,
+		summaryLines: cfg.GetCaptureLines(),
+	}
+	reaped, errs := runSessionReaper(d.oneShotSessions(), deps, SessionReaperDir(d.config.TownRoot), time.Now())
+	for _, err := range errs {
+		d.logger.Printf("session_reaper: %v", err)
+	}
+	for _, r := range reaped {
+		d.logger.Printf("session_reaper: reaped %s (%s), pane saved to %s\n%s", r.Name, r.Reason, r.CapturePath, r.Summary)
+		agent := r.Name
+		if id, err := session.ParseSessionName(r.Name); err == nil && id.Address() != "" {
+			agent = id.Address()
+		}
+		_ = events.LogFeed(events.TypeSessionDeath, "daemon",
+			events.SessionDeathPayload(r.Name, agent, "one-shot "+r.Reason, "daemon"))
+	}
+}
@@ -0,0 +1,99 @@
+package daemon
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func TestSessionReaperEnabledByDefault(t *testing.T) {
+	if !IsPatrolEnabled(nil, "session_reaper") {
+		t.Error("session_reaper should be enabled with no config")
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{}}
+	if !IsPatrolEnabled(cfg, "session_reaper") {
+		t.Error("session_reaper should be enabled when unset")
+	}
+	cfg.Patrols.SessionReaper = &SessionReaperConfig{Enabled: false}
+	if IsPatrolEnabled(cfg, "session_reaper") {
+		t.Error("session_reaper should be disabled when enabled=false")
+	}
+}
+
+func TestRunSessionReaper(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	limits := session.OneShotLimits{MaxLifetime: time.Hour, IdleTimeout: 10 * time.Minute}
+	sessions := []oneShotSession{
+		{Name: "hq-dog-beta", Limits: limits, Created: now.Add(-2 * time.Hour), LastActivity: now.Add(-time.Minute)},
+		{Name: "hq-boot", Limits: limits, Created: now.Add(-20 * time.Minute), LastActivity: now.Add(-15 * time.Minute)},
+		{Name: "hq-dog-alpha", Limits: limits, Created: now.Add(-5 * time.Minute), LastActivity: now.Add(-time.Minute)},
+	}
+
+	var killed, released []string
+	deps := sessionReaperDeps{
+		capture: func(name string) (string, error) {
+			if name == "hq-boot" {
+				return "", errors.New("pane gone")
+			}
+			return "starting formula\n\nstep 3 of 5\nwaiting for bd...\n\n", nil
+		},
+		kill: func(name string) error {
+			killed = append(killed, name)
+			return nil
+		},
+		release: func(name string) error {
+			released = append(released, name)
+			return nil
+		},
+		summaryLines: 10,
+	}
+
+	dir := t.TempDir()
+	reaped, errs := runSessionReaper(sessions, deps, dir, now)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "hq-boot: capturing pane") {
+		t.Errorf("expected only the capture failure, got %v", errs)
+	}
+	if strings.Join(killed, ",") != "hq-boot,hq-dog-beta" || strings.Join(released, ",") != "hq-boot,hq-dog-beta" {
+		t.Fatalf("killed=%v released=%v, want hq-boot and hq-dog-beta (failed capture doesn't spare a session)", killed, released)
+	}
+
+	if len(reaped) != 2 {
+		t.Fatalf("got %d reaped, want 2", len(reaped))
+	}
+	if !strings.Contains(reaped[0].Reason, "idle timeout") || !strings.Contains(reaped[1].Reason, "max lifetime") {
+		t.Errorf("unexpected reasons: %q, %q", reaped[0].Reason, reaped[1].Reason)
+	}
+
+	data, err := os.ReadFile(reaped[1].CapturePath)
+	if err != nil {
+		t.Fatalf("reading capture: %v", err)
+	}
+	for _, want := range []string{"Session: hq-dog-beta", "Age: 2h0m0s", "Last output:\n  starting formula\n  step 3 of 5\n  waiting for bd...\n", "\nstarting formula\n\nstep 3"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("capture missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestRunSessionReaper_KillFailureNotReleased(t *testing.T) {
+	now := time.Now()
+	sessions := []oneShotSession{{
+		Name:    "hq-dog-alpha",
+		Limits:  session.OneShotLimits{MaxLifetime: time.Minute},
+		Created: now.Add(-time.Hour),
+	}}
+	released := false
+	deps := sessionReaperDeps{
+		capture: func(string) (string, error) { return "", nil },
+		kill:    func(string) error { return errors.New("no server") },
+		release: func(string) error { released = true; return nil },
+	}
+	reaped, errs := runSessionReaper(sessions, deps, t.TempDir(), now)
+	if len(reaped) != 0 || len(errs) != 1 || released {
+		t.Errorf("a session that couldn't be killed must not be reported or released: reaped=%v errs=%v released=%v", reaped, errs, released)
+	}
+}
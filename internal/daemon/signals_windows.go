@@ -17,3 +17,10 @@ func daemonSignals() []os.Signal {
 func isLifecycleSignal(sig os.Signal) bool {
 	return false
 }
+
+// isDrainSignal reports whether sig asks for a graceful drain before
+// shutdown (SIGTERM from systemd, launchd, or 'gt daemon stop'), as opposed
+// to an interactive interrupt that exits immediately.
+func isDrainSignal(sig os.Signal) bool {
+	return sig == syscall.SIGTERM
+}
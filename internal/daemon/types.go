@@ -59,6 +59,12 @@ type State struct {
 
 	// HeartbeatCount is how many heartbeats have completed.
 	HeartbeatCount int64 `json:"heartbeat_count"`
+
+	// StopReason is why the daemon last drained before stopping.
+	StopReason string `json:"stop_reason,omitempty"`
+
+	// StoppedAt is when the daemon last drained before stopping.
+	StoppedAt time.Time `json:"stopped_at,omitempty"`
 }
 
 // StateFile returns the path to the state file.
@@ -113,11 +119,245 @@ type PatrolConfig struct {
 
 // PatrolsConfig holds configuration for all patrols.
 type PatrolsConfig struct {
-	Refinery    *PatrolConfig      `json:"refinery,omitempty"`
-	Witness     *PatrolConfig      `json:"witness,omitempty"`
-	Deacon      *PatrolConfig      `json:"deacon,omitempty"`
-	DoltServer  *DoltServerConfig  `json:"dolt_server,omitempty"`
-	DoltRemotes *DoltRemotesConfig `json:"dolt_remotes,omitempty"`
+	Refinery      *PatrolConfig        `json:"refinery,omitempty"`
+	Witness       *PatrolConfig        `json:"witness,omitempty"`
+	Deacon        *PatrolConfig        `json:"deacon,omitempty"`
+	DoltServer    *DoltServerConfig    `json:"dolt_server,omitempty"`
+	DoltRemotes   *DoltRemotesConfig   `json:"dolt_remotes,omitempty"`
+	DepUpdates    *DepUpdatesConfig    `json:"dep_updates,omitempty"`
+	InboxNag      *InboxNagConfig      `json:"inbox_nag,omitempty"`
+	MetricsPush   *MetricsPushConfig   `json:"metrics_push,omitempty"`
+	ModelProbe    *ModelProbeConfig    `json:"model_probe,omitempty"`
+	SessionReaper *SessionReaperConfig `json:"session_reaper,omitempty"`
+	QueueSnaps    *QueueSnapsConfig    `json:"queue_snapshots,omitempty"`
+	ForgeWebhook  *ForgeWebhookConfig  `json:"forge_webhook,omitempty"`
+	Replica       *ReplicaConfig       `json:"replica,omitempty"`
+	IssueSync     *IssueSyncConfig     `json:"issue_sync,omitempty"`
+	AgentGC       *AgentGCConfig       `json:"agent_gc,omitempty"`
+	BeadWebhooks  *BeadWebhooksConfig  `json:"bead_webhooks,omitempty"`
+
+	PolecatResources *PolecatResourcesConfig `json:"polecat_resources,omitempty"`
+}
+
+// ReplicaConfig holds configuration for the replica patrol.
+// This patrol rebuilds the read-only SQLite snapshot of beads, events and
+// merge queues (gt replica) that reporting commands query, so heavy reads
+// stay off the live stores.
+type ReplicaConfig struct {
+	// Enabled controls whether the replica is refreshed.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to refresh, as a Go duration string (default "10m").
+	Interval string `json:"interval,omitempty"`
+}
+
+// IssueSyncConfig holds configuration for the issue_sync patrol.
+// This patrol syncs each rig's work beads with the issue trackers named in
+// its settings (gt issue-sync).
+type IssueSyncConfig struct {
+	// Enabled controls whether rigs are synced.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to sync, as a Go duration string (default "5m").
+	Interval string `json:"interval,omitempty"`
+
+	// Rigs limits the patrol to specific rigs. If empty, every rig with
+	// issue_sync settings is synced.
+	Rigs []string `json:"rigs,omitempty"`
+}
+
+// AgentGCConfig holds configuration for the agent_gc patrol.
+// This patrol archives and deletes the agent beads of polecats that were
+// nuked (or closed) and haven't been updated within the retention, so
+// agent listings stay small (gt beads gc).
+type AgentGCConfig struct {
+	// Enabled controls whether stale agent beads are collected.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to collect, as a Go duration string (default "6h").
+	Interval string `json:"interval,omitempty"`
+
+	// RetainDays is how many days a dead polecat's agent bead is kept after
+	// its last update (default 30).
+	RetainDays int `json:"retain_days,omitempty"`
+}
+
+// BeadWebhooksConfig holds configuration for the bead_webhooks patrol.
+// This patrol watches the town's and each rig's beads and POSTs their
+// lifecycle events (bead.created, bead.status_changed, bead.closed,
+// agent.circuit_tripped) to the configured endpoints, so dashboards, chat
+// bots and warehouses can follow the town without reading its files.
+type BeadWebhooksConfig struct {
+	// Enabled controls whether bead lifecycle events are sent.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to check for events, as a Go duration string
+	// (default "1m").
+	Interval string `json:"interval,omitempty"`
+
+	// Rigs limits the patrol to specific rigs. If empty, all rigs are
+	// watched. The town's own beads are always watched.
+	Rigs []string `json:"rigs,omitempty"`
+
+	// Endpoints are where events are sent.
+	Endpoints []BeadWebhookEndpoint `json:"endpoints,omitempty"`
+}
+
+// BeadWebhookEndpoint is one destination for bead lifecycle events.
+type BeadWebhookEndpoint struct {
+	// Type is "webhook" (the default), which POSTs each event as JSON, or
+	// "slack", which posts it to a Slack incoming webhook as a message.
+	Type string `json:"type,omitempty"`
+
+	// URL is the endpoint to POST to.
+	URL string `json:"url"`
+
+	// Events limits the endpoint to these events; empty means all.
+	Events []string `json:"events,omitempty"`
+}
+
+// ForgeWebhookConfig holds configuration for the forge_webhook endpoint.
+// The daemon accepts GitHub and GitLab pull request webhooks at
+// POST /forge/<rig> and creates or refreshes the rig's MR beads as PRs are
+// opened, pushed to, relabeled or closed upstream.
+type ForgeWebhookConfig struct {
+	// Enabled controls whether the endpoint is started.
+	Enabled bool `json:"enabled"`
+
+	// Listen is the address to listen on (default "127.0.0.1:8788").
+	Listen string `json:"listen,omitempty"`
+
+	// Secret is the shared webhook secret: the HMAC key for GitHub, the
+	// X-Gitlab-Token value for GitLab. Falls back to GT_FORGE_WEBHOOK_SECRET;
+	// without either the endpoint is not started.
+	Secret string `json:"secret,omitempty"`
+}
+
+// PolecatResourcesConfig holds configuration for the polecat_resources
+// patrol. This patrol samples the CPU, memory and file descriptors of each
+// polecat session on behalf of its rig's witness, records them on the
+// polecat's agent bead, and trips or escalates polecats that stay over the
+// rig's witness.resource_budget.
+type PolecatResourcesConfig struct {
+	// Enabled controls whether polecat sessions are sampled.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to sample, as a Go duration string (default "1m").
+	Interval string `json:"interval,omitempty"`
+
+	// Rigs limits the patrol to specific rigs. If empty, all rigs are sampled.
+	Rigs []string `json:"rigs,omitempty"`
+}
+
+// QueueSnapsConfig holds configuration for the queue_snapshots patrol.
+// This patrol records every rig's merge queue periodically so queue depth
+// can be exported over time (gt refinery queue export --history).
+type QueueSnapsConfig struct {
+	// Enabled controls whether snapshots are recorded.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to record, as a Go duration string (default "15m").
+	Interval string `json:"interval,omitempty"`
+
+	// RetainDays is how many days of snapshots are kept (default 30).
+	RetainDays int `json:"retain_days,omitempty"`
+}
+
+// SessionReaperConfig holds configuration for the session_reaper patrol.
+// This patrol enforces the max lifetime and idle timeout that one-shot
+// sessions (Boot triage, dogs) record in their tmux environment: a session
+// past either limit has its pane captured and summarized, then is killed.
+// Enabled by default.
+type SessionReaperConfig struct {
+	// Enabled controls whether one-shot sessions are reaped.
+	Enabled bool `json:"enabled"`
+}
+
+// ModelProbeConfig holds configuration for the model_probe patrol.
+// This patrol probes the model API and moves the town into and out of
+// degraded mode (spawns paused, model-dependent refinery gates frozen,
+// heartbeat expectations relaxed). Enabled by default: without it, a town
+// that entered degraded mode after launch failures never resumes on its own.
+type ModelProbeConfig struct {
+	// Enabled controls whether the model API is probed.
+	Enabled bool `json:"enabled"`
+
+	// Interval is how often to probe, as a Go duration string (default "1m").
+	Interval string `json:"interval,omitempty"`
+
+	// URL overrides the endpoint probed (default ANTHROPIC_BASE_URL, else
+	// https://api.anthropic.com).
+	URL string `json:"url,omitempty"`
+}
+
+// MetricsPushConfig holds configuration for the metrics_push patrol.
+// This patrol periodically pushes key town metrics (agent sessions, polecat
+// counts, nudge queue depth, daemon liveness) to a StatsD or DogStatsD agent.
+type MetricsPushConfig struct {
+	// Enabled controls whether metrics are pushed.
+	Enabled bool `json:"enabled"`
+
+	// Address is the agent's host:port (default "127.0.0.1:8125").
+	Address string `json:"address,omitempty"`
+
+	// Prefix is prepended to every metric name (default "gt").
+	Prefix string `json:"prefix,omitempty"`
+
+	// Tags are "key:value" pairs added to every metric (datadog flavor only).
+	Tags []string `json:"tags,omitempty"`
+
+	// Flavor is "statsd" (default) or "datadog".
+	Flavor string `json:"flavor,omitempty"`
+
+	// Interval is how often to push, as a Go duration string (default "60s").
+	Interval string `json:"interval,omitempty"`
+}
+
+// InboxNagConfig holds configuration for the inbox_nag patrol.
+// This patrol tracks unread actionable mail per agent, appends an inbox
+// summary to the agent's nudge queue when the backlog crosses a threshold,
+// and escalates to the agent's supervisor after repeated neglect.
+type InboxNagConfig struct {
+	// Enabled controls whether inbox nagging runs.
+	Enabled bool `json:"enabled"`
+
+	// MaxUnread is the unread actionable message count that triggers a nag (default 5).
+	MaxUnread int `json:"max_unread,omitempty"`
+
+	// MaxAge is how old the oldest unread actionable message may get before
+	// a nag, as a Go duration string (default "2h").
+	MaxAge string `json:"max_age,omitempty"`
+
+	// NagInterval is the minimum time between nags to the same agent,
+	// as a Go duration string (default "30m").
+	NagInterval string `json:"nag_interval,omitempty"`
+
+	// EscalateAfter is how many consecutive nags an agent may ignore before
+	// the neglect is escalated (default 3).
+	EscalateAfter int `json:"escalate_after,omitempty"`
+}
+
+// DepUpdatesConfig holds configuration for the dep_updates patrol.
+// This patrol periodically opens a routine dependency-update work bead per rig
+// on behalf of the rig's witness.
+type DepUpdatesConfig struct {
+	// Enabled controls whether dependency-update beads are opened.
+	Enabled bool `json:"enabled"`
+
+	// Interval is the cadence per rig as a Go duration string (default "168h").
+	Interval string `json:"interval,omitempty"`
+
+	// Rigs limits the duty to specific rigs. If empty, all rigs get updates.
+	Rigs []string `json:"rigs,omitempty"`
+
+	// Labels are extra labels applied to each update bead (e.g. "area:deps").
+	Labels []string `json:"labels,omitempty"`
+
+	// Priority is the requested bead priority (0-4).
+	Priority int `json:"priority,omitempty"`
+
+	// MaxPriority caps how urgent an update bead may be (default 3).
+	MaxPriority int `json:"max_priority,omitempty"`
 }
 
 // DoltRemotesConfig holds configuration for the dolt_remotes patrol.
@@ -142,10 +382,18 @@ type DoltRemotesConfig struct {
 
 // DaemonPatrolConfig is the structure of mayor/daemon.json.
 type DaemonPatrolConfig struct {
-	Type      string         `json:"type"`
-	Version   int            `json:"version"`
-	Heartbeat *PatrolConfig  `json:"heartbeat,omitempty"`
-	Patrols   *PatrolsConfig `json:"patrols,omitempty"`
+	Type      string          `json:"type"`
+	Version   int             `json:"version"`
+	Heartbeat *PatrolConfig   `json:"heartbeat,omitempty"`
+	Patrols   *PatrolsConfig  `json:"patrols,omitempty"`
+	Shutdown  *ShutdownConfig `json:"shutdown,omitempty"`
+}
+
+// ShutdownConfig controls the drain the daemon runs on SIGTERM.
+type ShutdownConfig struct {
+	// DrainTimeout bounds how long in-flight merges are waited for
+	// (e.g. "90s"). Default 60s.
+	DrainTimeout string `json:"drain_timeout,omitempty"`
 }
 
 // PatrolConfigFile returns the path to the patrol config file.
@@ -171,7 +419,9 @@ func LoadPatrolConfig(townRoot string) *DaemonPatrolConfig {
 
 // IsPatrolEnabled checks if a patrol is enabled in the config.
 // Returns true if the config doesn't exist (default enabled for backwards compatibility).
-// Exception: opt-in patrols (dolt_remotes) default to disabled.
+// Exception: opt-in patrols (dolt_remotes, dep_updates, inbox_nag, metrics_push,
+// queue_snapshots, forge_webhook, replica, polecat_resources, issue_sync)
+// default to disabled.
 func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 	// Opt-in patrols: disabled unless explicitly enabled in config.
 	// Must check before the nil-config fallback, otherwise nil config
@@ -182,6 +432,66 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		}
 		return config.Patrols.DoltRemotes.Enabled
 	}
+	if patrol == "dep_updates" {
+		if config == nil || config.Patrols == nil || config.Patrols.DepUpdates == nil {
+			return false
+		}
+		return config.Patrols.DepUpdates.Enabled
+	}
+	if patrol == "inbox_nag" {
+		if config == nil || config.Patrols == nil || config.Patrols.InboxNag == nil {
+			return false
+		}
+		return config.Patrols.InboxNag.Enabled
+	}
+	if patrol == "metrics_push" {
+		if config == nil || config.Patrols == nil || config.Patrols.MetricsPush == nil {
+			return false
+		}
+		return config.Patrols.MetricsPush.Enabled
+	}
+	if patrol == "queue_snapshots" {
+		if config == nil || config.Patrols == nil || config.Patrols.QueueSnaps == nil {
+			return false
+		}
+		return config.Patrols.QueueSnaps.Enabled
+	}
+	if patrol == "forge_webhook" {
+		if config == nil || config.Patrols == nil || config.Patrols.ForgeWebhook == nil {
+			return false
+		}
+		return config.Patrols.ForgeWebhook.Enabled
+	}
+	if patrol == "replica" {
+		if config == nil || config.Patrols == nil || config.Patrols.Replica == nil {
+			return false
+		}
+		return config.Patrols.Replica.Enabled
+	}
+	if patrol == "polecat_resources" {
+		if config == nil || config.Patrols == nil || config.Patrols.PolecatResources == nil {
+			return false
+		}
+		return config.Patrols.PolecatResources.Enabled
+	}
+	if patrol == "issue_sync" {
+		if config == nil || config.Patrols == nil || config.Patrols.IssueSync == nil {
+			return false
+		}
+		return config.Patrols.IssueSync.Enabled
+	}
+	if patrol == "agent_gc" {
+		if config == nil || config.Patrols == nil || config.Patrols.AgentGC == nil {
+			return false
+		}
+		return config.Patrols.AgentGC.Enabled
+	}
+	if patrol == "bead_webhooks" {
+		if config == nil || config.Patrols == nil || config.Patrols.BeadWebhooks == nil {
+			return false
+		}
+		return config.Patrols.BeadWebhooks.Enabled
+	}
 
 	if config == nil || config.Patrols == nil {
 		return true // Default: enabled
@@ -200,6 +510,14 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		if config.Patrols.Deacon != nil {
 			return config.Patrols.Deacon.Enabled
 		}
+	case "model_probe":
+		if config.Patrols.ModelProbe != nil {
+			return config.Patrols.ModelProbe.Enabled
+		}
+	case "session_reaper":
+		if config.Patrols.SessionReaper != nil {
+			return config.Patrols.SessionReaper.Enabled
+		}
 	}
 	return true // Default: enabled
 }
@@ -219,6 +537,22 @@ func GetPatrolRigs(config *DaemonPatrolConfig, patrol string) []string {
 		if config.Patrols.Witness != nil {
 			return config.Patrols.Witness.Rigs
 		}
+	case "dep_updates":
+		if config.Patrols.DepUpdates != nil {
+			return config.Patrols.DepUpdates.Rigs
+		}
+	case "polecat_resources":
+		if config.Patrols.PolecatResources != nil {
+			return config.Patrols.PolecatResources.Rigs
+		}
+	case "issue_sync":
+		if config.Patrols.IssueSync != nil {
+			return config.Patrols.IssueSync.Rigs
+		}
+	case "bead_webhooks":
+		if config.Patrols.BeadWebhooks != nil {
+			return config.Patrols.BeadWebhooks.Rigs
+		}
 	}
 	return nil // All rigs
 }
@@ -0,0 +1,355 @@
+// Package deacon provides the Deacon agent infrastructure.
+package deacon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// CurrentConfigVersion is the current schema version for Config.
+const CurrentConfigVersion = 1
+
+// Defaults for the Deacon's cadence and thresholds, used for any field
+// config.json leaves unset.
+const (
+	DefaultHeartbeatStale     = 5 * time.Minute  // Heartbeat stops being fresh
+	DefaultHeartbeatVeryStale = 15 * time.Minute // Daemon intervenes
+	DefaultStuckRestart       = 10 * time.Minute // Very stale Deacon is restarted rather than nudged
+	DefaultPendingSpawnMaxAge = 5 * time.Minute  // trigger-pending prunes older spawns
+	DefaultCaptureLines       = 10               // Pane lines kept in a reaped session's summary
+
+	DefaultPendingSpawnMaxAttempts = 5                // Failed readiness checks before a spawn is escalated
+	DefaultPendingSpawnBackoff     = 10 * time.Second // Delay after the first failed readiness check
+)
+
+// Config holds the Deacon's heartbeat cadence and thresholds, read from
+// <town>/deacon/config.json. Durations are Go duration strings ("5m");
+// empty or zero fields fall back to the defaults above.
+type Config struct {
+	Version int `json:"version"`
+
+	// HeartbeatStale is the age at which a heartbeat stops being fresh.
+	HeartbeatStale string `json:"heartbeat_stale,omitempty"`
+
+	// HeartbeatVeryStale is the age at which the daemon checks on the
+	// Deacon, nudging or restarting it.
+	HeartbeatVeryStale string `json:"heartbeat_very_stale,omitempty"`
+
+	// StuckRestart is the heartbeat age past which a very stale Deacon is
+	// restarted instead of nudged. Keep it above the patrol's backoff max
+	// so a Deacon legitimately sleeping isn't killed.
+	StuckRestart string `json:"stuck_restart,omitempty"`
+
+	// PendingSpawnMaxAge is how long a pending polecat spawn waits before
+	// trigger-pending prunes it.
+	PendingSpawnMaxAge string `json:"pending_spawn_max_age,omitempty"`
+
+	// PendingSpawnMaxAttempts is how many readiness checks of a pending
+	// spawn may fail before it is escalated to the Mayor.
+	PendingSpawnMaxAttempts int `json:"pending_spawn_max_attempts,omitempty"`
+
+	// PendingSpawnBackoff is the delay after a pending spawn's first failed
+	// readiness check; it doubles with each further failure.
+	PendingSpawnBackoff string `json:"pending_spawn_backoff,omitempty"`
+
+	// MaxPolecatsPerRig caps the polecats working at once in each rig;
+	// ready spawns past it stay pending until one finishes. Zero means no
+	// cap.
+	MaxPolecatsPerRig int `json:"max_polecats_per_rig,omitempty"`
+
+	// MaxTriggersPerMinute caps how many pending spawns are triggered
+	// across the town in any minute. Zero means no limit.
+	MaxTriggersPerMinute int `json:"max_triggers_per_minute,omitempty"`
+
+	// CaptureLines is how many trailing pane lines go into the summary of a
+	// reaped one-shot session (Boot, dogs).
+	CaptureLines int `json:"capture_lines,omitempty"`
+
+	// Notify lists the sinks (desktop, webhook, slack) that high-signal
+	// events are sent to: escalations, stale heartbeats, spawn failures and
+	// the model API circuit opening.
+	Notify []NotifySinkConfig `json:"notify,omitempty"`
+
+	// NotifyCooldown is the minimum time between repeats of the same
+	// notification.
+	NotifyCooldown string `json:"notify_cooldown,omitempty"`
+}
+
+// ConfigFile returns the path to the Deacon config file.
+func ConfigFile(townRoot string) string {
+	return filepath.Join(townRoot, "deacon", "config.json")
+}
+
+// LoadConfig reads the Deacon config. A missing file yields an empty config
+// (all defaults); an unreadable or invalid one is an error.
+func LoadConfig(townRoot string) (*Config, error) {
+	data, err := os.ReadFile(ConfigFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Config{Version: CurrentConfigVersion}, nil
+		}
+		return nil, err
+	}
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ConfigFile(townRoot), err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", ConfigFile(townRoot), err)
+	}
+	return &c, nil
+}
+
+// LoadConfigOrDefault reads the Deacon config, falling back to the defaults
+// when it is missing or invalid so a bad edit can't stop the patrol. The
+// error, if any, is returned for the caller to report.
+func LoadConfigOrDefault(townRoot string) (*Config, error) {
+	c, err := LoadConfig(townRoot)
+	if err != nil {
+		return &Config{Version: CurrentConfigVersion}, err
+	}
+	return c, nil
+}
+
+// SaveConfig validates and writes the Deacon config.
+func SaveConfig(townRoot string, c *Config) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if c.Version == 0 {
+		c.Version = CurrentConfigVersion
+	}
+	path := ConfigFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644) //nolint:gosec // G306: config is not sensitive
+}
+
+// Validate checks that durations parse and the thresholds are ordered.
+func (c *Config) Validate() error {
+	if c.Version > CurrentConfigVersion {
+		return fmt.Errorf("unsupported deacon config version %d (max %d)", c.Version, CurrentConfigVersion)
+	}
+	for _, key := range configDurationKeys {
+		v := *configDurationFields[key](c)
+		if v == "" {
+			continue
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("%s: invalid duration %q", key, v)
+		}
+		if d <= 0 {
+			return fmt.Errorf("%s: must be positive, got %s", key, v)
+		}
+	}
+	for _, key := range configIntKeys {
+		if v := *configIntFields[key](c); v < 0 {
+			return fmt.Errorf("%s: must be non-negative, got %d", key, v)
+		}
+	}
+	for i := range c.Notify {
+		if err := c.Notify[i].Validate(); err != nil {
+			return fmt.Errorf("notify[%d]: %w", i, err)
+		}
+	}
+	if c.GetHeartbeatStale() >= c.GetHeartbeatVeryStale() {
+		return fmt.Errorf("heartbeat_stale (%s) must be less than heartbeat_very_stale (%s)",
+			c.GetHeartbeatStale(), c.GetHeartbeatVeryStale())
+	}
+	return nil
+}
+
+// GetHeartbeatStale returns the age at which a heartbeat stops being fresh.
+func (c *Config) GetHeartbeatStale() time.Duration {
+	return parseDurationOr(c.HeartbeatStale, DefaultHeartbeatStale)
+}
+
+// GetHeartbeatVeryStale returns the age at which the daemon checks on the Deacon.
+func (c *Config) GetHeartbeatVeryStale() time.Duration {
+	return parseDurationOr(c.HeartbeatVeryStale, DefaultHeartbeatVeryStale)
+}
+
+// GetStuckRestart returns the heartbeat age past which a stuck Deacon is restarted.
+func (c *Config) GetStuckRestart() time.Duration {
+	return parseDurationOr(c.StuckRestart, DefaultStuckRestart)
+}
+
+// GetPendingSpawnMaxAge returns how long a pending spawn waits before being pruned.
+func (c *Config) GetPendingSpawnMaxAge() time.Duration {
+	return parseDurationOr(c.PendingSpawnMaxAge, DefaultPendingSpawnMaxAge)
+}
+
+// GetPendingSpawnMaxAttempts returns how many failed readiness checks a
+// pending spawn gets before being escalated.
+func (c *Config) GetPendingSpawnMaxAttempts() int {
+	if c.PendingSpawnMaxAttempts <= 0 {
+		return DefaultPendingSpawnMaxAttempts
+	}
+	return c.PendingSpawnMaxAttempts
+}
+
+// GetPendingSpawnBackoff returns the delay after a pending spawn's first
+// failed readiness check.
+func (c *Config) GetPendingSpawnBackoff() time.Duration {
+	return parseDurationOr(c.PendingSpawnBackoff, DefaultPendingSpawnBackoff)
+}
+
+// GetMaxPolecatsPerRig returns the per-rig cap on working polecats; zero
+// means no cap.
+func (c *Config) GetMaxPolecatsPerRig() int {
+	return max(c.MaxPolecatsPerRig, 0)
+}
+
+// GetMaxTriggersPerMinute returns the town-wide trigger rate limit; zero
+// means no limit.
+func (c *Config) GetMaxTriggersPerMinute() int {
+	return max(c.MaxTriggersPerMinute, 0)
+}
+
+// GetCaptureLines returns how many pane lines go into a reaped session's summary.
+func (c *Config) GetCaptureLines() int {
+	if c.CaptureLines <= 0 {
+		return DefaultCaptureLines
+	}
+	return c.CaptureLines
+}
+
+// GetNotifyCooldown returns the minimum time between repeats of a notification.
+func (c *Config) GetNotifyCooldown() time.Duration {
+	return parseDurationOr(c.NotifyCooldown, DefaultNotifyCooldown)
+}
+
+// IsFresh reports whether hb is younger than the stale threshold.
+func (c *Config) IsFresh(hb *Heartbeat) bool {
+	return hb != nil && hb.Age() < c.GetHeartbeatStale()
+}
+
+// IsStale reports whether hb is between the stale and very-stale thresholds.
+func (c *Config) IsStale(hb *Heartbeat) bool {
+	if hb == nil {
+		return false
+	}
+	age := hb.Age()
+	return age >= c.GetHeartbeatStale() && age < c.GetHeartbeatVeryStale()
+}
+
+// IsVeryStale reports whether hb is missing or past the very-stale threshold.
+func (c *Config) IsVeryStale(hb *Heartbeat) bool {
+	return hb == nil || hb.Age() >= c.GetHeartbeatVeryStale()
+}
+
+// configDurationFields maps each duration key to its field.
+var configDurationFields = map[string]func(*Config) *string{
+	"heartbeat_stale":       func(c *Config) *string { return &c.HeartbeatStale },
+	"heartbeat_very_stale":  func(c *Config) *string { return &c.HeartbeatVeryStale },
+	"stuck_restart":         func(c *Config) *string { return &c.StuckRestart },
+	"pending_spawn_max_age": func(c *Config) *string { return &c.PendingSpawnMaxAge },
+	"pending_spawn_backoff": func(c *Config) *string { return &c.PendingSpawnBackoff },
+	"notify_cooldown":       func(c *Config) *string { return &c.NotifyCooldown },
+}
+
+// configDurationKeys lists the duration keys in file order.
+var configDurationKeys = []string{"heartbeat_stale", "heartbeat_very_stale", "stuck_restart", "pending_spawn_max_age", "pending_spawn_backoff", "notify_cooldown"}
+
+// configIntFields maps each integer key to its field.
+var configIntFields = map[string]func(*Config) *int{
+	"pending_spawn_max_attempts": func(c *Config) *int { return &c.PendingSpawnMaxAttempts },
+	"max_polecats_per_rig":       func(c *Config) *int { return &c.MaxPolecatsPerRig },
+	"max_triggers_per_minute":    func(c *Config) *int { return &c.MaxTriggersPerMinute },
+	"capture_lines":              func(c *Config) *int { return &c.CaptureLines },
+}
+
+// configIntKeys lists the integer keys in file order.
+var configIntKeys = []string{"pending_spawn_max_attempts", "max_polecats_per_rig", "max_triggers_per_minute", "capture_lines"}
+
+// ConfigKeys lists the settable keys, sorted.
+func ConfigKeys() []string {
+	keys := append(append([]string{}, configIntKeys...), configDurationKeys...)
+	sort.Strings(keys)
+	return keys
+}
+
+// Get returns the effective value of key, with defaults applied.
+func (c *Config) Get(key string) (string, error) {
+	switch key {
+	case "heartbeat_stale":
+		return c.GetHeartbeatStale().String(), nil
+	case "heartbeat_very_stale":
+		return c.GetHeartbeatVeryStale().String(), nil
+	case "stuck_restart":
+		return c.GetStuckRestart().String(), nil
+	case "pending_spawn_max_age":
+		return c.GetPendingSpawnMaxAge().String(), nil
+	case "pending_spawn_max_attempts":
+		return strconv.Itoa(c.GetPendingSpawnMaxAttempts()), nil
+	case "pending_spawn_backoff":
+		return c.GetPendingSpawnBackoff().String(), nil
+	case "max_polecats_per_rig":
+		return strconv.Itoa(c.GetMaxPolecatsPerRig()), nil
+	case "max_triggers_per_minute":
+		return strconv.Itoa(c.GetMaxTriggersPerMinute()), nil
+	case "capture_lines":
+		return strconv.Itoa(c.GetCaptureLines()), nil
+	case "notify_cooldown":
+		return c.GetNotifyCooldown().String(), nil
+	}
+	return "", fmt.Errorf("unknown deacon config key %q (valid: %v)", key, ConfigKeys())
+}
+
+// IsSet reports whether key has an explicit value in the file.
+func (c *Config) IsSet(key string) bool {
+	if field, ok := configIntFields[key]; ok {
+		return *field(c) > 0
+	}
+	if field, ok := configDurationFields[key]; ok {
+		return *field(c) != ""
+	}
+	return false
+}
+
+// Set sets key to value, or back to its default when value is empty. The
+// result is validated as a whole, so an out-of-order threshold is rejected.
+func (c *Config) Set(key, value string) error {
+	next := *c
+	if field, ok := configIntFields[key]; ok {
+		n := 0
+		if value != "" {
+			var err error
+			if n, err = strconv.Atoi(value); err != nil {
+				return fmt.Errorf("%s: invalid number %q", key, value)
+			}
+		}
+		*field(&next) = n
+	} else if field, ok := configDurationFields[key]; ok {
+		*field(&next) = value
+	} else {
+		return fmt.Errorf("unknown deacon config key %q (valid: %v)", key, ConfigKeys())
+	}
+	if err := next.Validate(); err != nil {
+		return err
+	}
+	*c = next
+	return nil
+}
+
+func parseDurationOr(s string, def time.Duration) time.Duration {
+	if s == "" {
+		return def
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return def
+	}
+	return d
+}
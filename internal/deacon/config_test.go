@@ -0,0 +1,102 @@
+package deacon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig_MissingUsesDefaults(t *testing.T) {
+	cfg, err := LoadConfig(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.GetHeartbeatVeryStale() != DefaultHeartbeatVeryStale || cfg.GetCaptureLines() != DefaultCaptureLines {
+		t.Errorf("expected defaults, got %+v", cfg)
+	}
+}
+
+func TestConfig_SetSaveLoad(t *testing.T) {
+	townRoot := t.TempDir()
+	cfg, _ := LoadConfig(townRoot)
+	if err := cfg.Set("heartbeat_very_stale", "20m"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("capture_lines", "25"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("pending_spawn_max_attempts", "3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveConfig(townRoot, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadConfig(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetHeartbeatVeryStale() != 20*time.Minute || got.GetCaptureLines() != 25 || got.GetPendingSpawnMaxAttempts() != 3 {
+		t.Errorf("round trip lost values: %+v", got)
+	}
+	if !got.IsSet("heartbeat_very_stale") || got.IsSet("stuck_restart") {
+		t.Errorf("IsSet wrong: %+v", got)
+	}
+
+	if err := got.Set("heartbeat_very_stale", ""); err != nil {
+		t.Fatal(err)
+	}
+	if got.IsSet("heartbeat_very_stale") || got.GetHeartbeatVeryStale() != DefaultHeartbeatVeryStale {
+		t.Errorf("unset should restore the default: %+v", got)
+	}
+}
+
+func TestConfig_SetRejectsInvalid(t *testing.T) {
+	cfg := &Config{}
+	for _, tc := range []struct{ key, value, want string }{
+		{"heartbeat_stale", "soon", "invalid duration"},
+		{"stuck_restart", "-1m", "must be positive"},
+		{"heartbeat_stale", "30m", "must be less than heartbeat_very_stale"},
+		{"capture_lines", "lots", "invalid number"},
+		{"pending_spawn_max_attempts", "-2", "must be non-negative"},
+		{"cadence", "1m", "unknown deacon config key"},
+	} {
+		err := cfg.Set(tc.key, tc.value)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Set(%s, %s) = %v, want error containing %q", tc.key, tc.value, err, tc.want)
+		}
+	}
+	if cfg.IsSet("heartbeat_stale") {
+		t.Errorf("rejected Set should leave the config unchanged: %+v", cfg)
+	}
+}
+
+func TestLoadConfig_InvalidFile(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Dir(ConfigFile(townRoot)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ConfigFile(townRoot), []byte(`{"heartbeat_stale": "forever"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(townRoot); err == nil {
+		t.Fatal("expected error for invalid duration")
+	}
+	cfg, err := LoadConfigOrDefault(townRoot)
+	if err == nil || cfg.GetHeartbeatStale() != DefaultHeartbeatStale {
+		t.Errorf("LoadConfigOrDefault should report the error and fall back: %+v, %v", cfg, err)
+	}
+}
+
+func TestConfig_HeartbeatThresholds(t *testing.T) {
+	cfg := &Config{HeartbeatStale: "1m", HeartbeatVeryStale: "3m"}
+	hb := &Heartbeat{Timestamp: time.Now().Add(-2 * time.Minute)}
+	if cfg.IsFresh(hb) || !cfg.IsStale(hb) || cfg.IsVeryStale(hb) {
+		t.Errorf("2m-old heartbeat should be stale under 1m/3m thresholds")
+	}
+	if !hb.IsFresh() {
+		t.Errorf("2m-old heartbeat should be fresh under the defaults")
+	}
+}
@@ -0,0 +1,155 @@
+package deacon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSpec is a parsed five-field cron expression:
+//
+//	minute hour day-of-month month day-of-week
+//
+// Fields accept *, single values, ranges (1-5), lists (1,15) and steps
+// (*/15, 0-30/10). Day-of-week is 0-7 with both 0 and 7 meaning Sunday.
+// As in classic cron, when both day fields are restricted a day matches if
+// either does. The shorthands @hourly, @daily (@midnight), @weekly,
+// @monthly and @yearly (@annually) are also accepted.
+type CronSpec struct {
+	minute, hour, dom, month, dow uint64
+
+	// domStar and dowStar record an unrestricted day field, which decides
+	// whether the two day fields combine with AND or OR.
+	domStar, dowStar bool
+}
+
+var cronShorthands = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses a cron expression.
+func ParseCron(expr string) (*CronSpec, error) {
+	expr = strings.TrimSpace(expr)
+	if full, ok := cronShorthands[strings.ToLower(expr)]; ok {
+		expr = full
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: want 5 fields (minute hour day-of-month month day-of-week)", expr)
+	}
+
+	spec := &CronSpec{}
+	var err error
+	if spec.minute, _, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("invalid minute %q: %w", fields[0], err)
+	}
+	if spec.hour, _, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("invalid hour %q: %w", fields[1], err)
+	}
+	if spec.dom, spec.domStar, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("invalid day-of-month %q: %w", fields[2], err)
+	}
+	if spec.month, _, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("invalid month %q: %w", fields[3], err)
+	}
+	if spec.dow, spec.dowStar, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("invalid day-of-week %q: %w", fields[4], err)
+	}
+	if spec.dow&(1<<7) != 0 {
+		spec.dow |= 1 // 7 is Sunday too
+	}
+	return spec, nil
+}
+
+// parseCronField parses one comma-separated field into a bit set of the
+// values it allows, reporting whether the field was an unrestricted *.
+func parseCronField(field string, lo, hi int) (bits uint64, star bool, err error) {
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step < 1 {
+				return 0, false, fmt.Errorf("bad step %q", stepStr)
+			}
+		}
+
+		var start, end int
+		switch {
+		case rng == "*":
+			start, end = lo, hi
+			if !hasStep {
+				star = true
+			}
+		case strings.Contains(rng, "-"):
+			a, b, _ := strings.Cut(rng, "-")
+			if start, err = strconv.Atoi(a); err != nil {
+				return 0, false, fmt.Errorf("bad value %q", a)
+			}
+			if end, err = strconv.Atoi(b); err != nil {
+				return 0, false, fmt.Errorf("bad value %q", b)
+			}
+		default:
+			if start, err = strconv.Atoi(rng); err != nil {
+				return 0, false, fmt.Errorf("bad value %q", rng)
+			}
+			end = start
+			if hasStep {
+				end = hi // 5/15 means 5-hi/15
+			}
+		}
+		if start < lo || end > hi || start > end {
+			return 0, false, fmt.Errorf("%d-%d out of range %d-%d", start, end, lo, hi)
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, star, nil
+}
+
+// dayMatches reports whether t's day satisfies the day-of-month and
+// day-of-week fields.
+func (c *CronSpec) dayMatches(t time.Time) bool {
+	domOK := c.dom&(1<<uint(t.Day())) != 0
+	dowOK := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
+
+// Next returns the first time strictly after t that matches the spec, in
+// t's location. It returns the zero time if nothing matches within five
+// years (e.g. 30 February).
+func (c *CronSpec) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,70 @@
+package deacon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@fortnightly",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) = nil error, want error", expr)
+		}
+	}
+}
+
+func TestCronSpec_Next(t *testing.T) {
+	// Friday 2026-01-02 10:17 UTC
+	from := time.Date(2026, 1, 2, 10, 17, 30, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 1, 2, 10, 18, 0, 0, time.UTC)},
+		{"0 */2 * * *", time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 1, 2, 10, 30, 0, 0, time.UTC)},
+		{"5,45 10 * * *", time.Date(2026, 1, 2, 10, 45, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2026, 1, 2, 13, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2026, 1, 3, 2, 30, 0, 0, time.UTC)},
+		{"0 0 * * 1", time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: the 15th or any Monday.
+		{"0 0 15 * 1", time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 1, 2, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		spec, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		if got := spec.Next(from); !got.Equal(tt.want) {
+			t.Errorf("Next(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCronSpec_NextNever(t *testing.T) {
+	spec, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := spec.Next(time.Now()); !got.IsZero() {
+		t.Errorf("Next(30 Feb) = %v, want zero", got)
+	}
+}
@@ -0,0 +1,91 @@
+package deacon
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// HeadlessState records that the Deacon runs headless: its mechanical
+// patrol loop is a plain gt process supervised by the daemon instead of a
+// Claude session in tmux. The file's presence selects headless mode; the
+// daemon keeps PID current as it restarts the loop.
+type HeadlessState struct {
+	// PID is the running headless loop's process ID (0 if not yet started).
+	PID int `json:"pid"`
+
+	// StartedAt is when the current loop process was started.
+	StartedAt time.Time `json:"started_at,omitempty"`
+
+	// EnabledAt is when headless mode was selected.
+	EnabledAt time.Time `json:"enabled_at"`
+}
+
+// HeadlessFile returns the path to the Deacon's headless state file.
+func HeadlessFile(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "deacon", "headless.json")
+}
+
+// ReadHeadlessState returns the headless state, or nil if the Deacon isn't
+// in headless mode.
+func ReadHeadlessState(townRoot string) *HeadlessState {
+	data, err := os.ReadFile(HeadlessFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		return nil
+	}
+	var state HeadlessState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil
+	}
+	return &state
+}
+
+// IsHeadless reports whether the Deacon is configured to run headless.
+func IsHeadless(townRoot string) bool {
+	return ReadHeadlessState(townRoot) != nil
+}
+
+// WriteHeadlessState writes the headless state, selecting headless mode.
+func WriteHeadlessState(townRoot string, state *HeadlessState) error {
+	path := HeadlessFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if state.EnabledAt.IsZero() {
+		state.EnabledAt = time.Now().UTC()
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// ClearHeadless leaves headless mode. The daemon goes back to running the
+// Deacon as a tmux session.
+func ClearHeadless(townRoot string) error {
+	err := os.Remove(HeadlessFile(townRoot))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// NeedsInteractive returns the unread Deacon mail the headless loop can't
+// handle mechanically. POLECAT_STARTED is consumed by trigger-pending;
+// anything else (escalations, lifecycle requests, WORK_UNVERIFIED, ...)
+// needs judgment, so the headless loop brings up a Claude session for it.
+func NeedsInteractive(msgs []*mail.Message) []*mail.Message {
+	var out []*mail.Message
+	for _, msg := range msgs {
+		if msg.Read || strings.HasPrefix(msg.Subject, "POLECAT_STARTED ") {
+			continue
+		}
+		out = append(out, msg)
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+package deacon
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func TestHeadlessState(t *testing.T) {
+	townRoot := t.TempDir()
+	if IsHeadless(townRoot) {
+		t.Fatal("fresh town should not be headless")
+	}
+
+	if err := WriteHeadlessState(townRoot, &HeadlessState{PID: 4242}); err != nil {
+		t.Fatalf("WriteHeadlessState: %v", err)
+	}
+	state := ReadHeadlessState(townRoot)
+	if state == nil || state.PID != 4242 || state.EnabledAt.IsZero() {
+		t.Fatalf("unexpected state: %+v", state)
+	}
+	if !IsHeadless(townRoot) {
+		t.Error("expected headless after WriteHeadlessState")
+	}
+
+	if err := ClearHeadless(townRoot); err != nil {
+		t.Fatalf("ClearHeadless: %v", err)
+	}
+	if IsHeadless(townRoot) {
+		t.Error("expected tmux mode after ClearHeadless")
+	}
+	if err := ClearHeadless(townRoot); err != nil {
+		t.Errorf("ClearHeadless should be idempotent: %v", err)
+	}
+}
+
+func TestNeedsInteractive(t *testing.T) {
+	msgs := []*mail.Message{
+		{ID: "1", Subject: "POLECAT_STARTED gastown/nux"},
+		{ID: "2", Subject: "WORK_UNVERIFIED gt-abc"},
+		{ID: "3", Subject: "ESCALATION: refinery stuck", Read: true},
+		{ID: "4", Subject: "LIFECYCLE: restart witness"},
+	}
+	got := NeedsInteractive(msgs)
+	if len(got) != 2 || got[0].ID != "2" || got[1].ID != "4" {
+		t.Errorf("NeedsInteractive = %+v, want messages 2 and 4", got)
+	}
+}
@@ -28,6 +28,30 @@ type Heartbeat struct {
 
 	// UnhealthyAgents is the count of unhealthy agents observed.
 	UnhealthyAgents int `json:"unhealthy_agents"`
+
+	// PID is the process that owns the Deacon: the tmux pane's process
+	// for an agent session, or the loop itself for watch and headless mode.
+	// Zero when unknown.
+	PID int `json:"pid,omitempty"`
+
+	// Session is the tmux session the Deacon runs in, if any.
+	Session string `json:"session,omitempty"`
+
+	// StartedAt is the first heartbeat from the current owner.
+	StartedAt time.Time `json:"started_at,omitempty"`
+
+	// OwnerCycles counts the heartbeats from the current owner.
+	OwnerCycles int64 `json:"owner_cycles,omitempty"`
+
+	// Restarts counts owner changes: a new PID or session taking over
+	// the heartbeat.
+	Restarts int64 `json:"restarts,omitempty"`
+}
+
+// HeartbeatOwner identifies the process writing a heartbeat.
+type HeartbeatOwner struct {
+	PID     int
+	Session string
 }
 
 // HeartbeatFile returns the path to the Deacon heartbeat file.
@@ -87,55 +111,64 @@ func (hb *Heartbeat) Age() time.Duration {
 
 // IsFresh returns true if the heartbeat is less than 5 minutes old.
 // A fresh heartbeat means the Deacon is actively working or recently finished.
+// Uses the default thresholds; see Config.IsFresh for the configured ones.
 func (hb *Heartbeat) IsFresh() bool {
-	return hb != nil && hb.Age() < 5*time.Minute
+	return (&Config{}).IsFresh(hb)
 }
 
 // IsStale returns true if the heartbeat is 5-15 minutes old.
 // A stale heartbeat may indicate the Deacon is doing a long operation.
 func (hb *Heartbeat) IsStale() bool {
-	if hb == nil {
-		return false
-	}
-	age := hb.Age()
-	return age >= 5*time.Minute && age < 15*time.Minute
+	return (&Config{}).IsStale(hb)
 }
 
 // IsVeryStale returns true if the heartbeat is more than 15 minutes old.
 // A very stale heartbeat means the Deacon should be poked.
 func (hb *Heartbeat) IsVeryStale() bool {
-	return hb == nil || hb.Age() >= 15*time.Minute
+	return (&Config{}).IsVeryStale(hb)
 }
 
 // Touch writes a minimal heartbeat with just the timestamp.
 // This is a convenience function for simple heartbeat updates.
 func Touch(townRoot string) error {
-	// Read existing heartbeat to increment cycle
-	existing := ReadHeartbeat(townRoot)
-	cycle := int64(1)
-	if existing != nil {
-		cycle = existing.Cycle + 1
-	}
-
-	return WriteHeartbeat(townRoot, &Heartbeat{
-		Timestamp: time.Now().UTC(),
-		Cycle:     cycle,
-	})
+	return TouchFrom(townRoot, HeartbeatOwner{PID: os.Getpid()}, "", 0, 0)
 }
 
-// TouchWithAction writes a heartbeat with an action description.
+// TouchWithAction writes a heartbeat with an action description, owned by
+// the calling process.
 func TouchWithAction(townRoot, action string, healthy, unhealthy int) error {
+	return TouchFrom(townRoot, HeartbeatOwner{PID: os.Getpid()}, action, healthy, unhealthy)
+}
+
+// TouchFrom writes a heartbeat on behalf of owner, carrying the cycle and
+// owner counters forward from the previous heartbeat. A zero owner (a
+// heartbeat touched by hand) keeps the previous owner.
+func TouchFrom(townRoot string, owner HeartbeatOwner, action string, healthy, unhealthy int) error {
+	now := time.Now().UTC()
 	existing := ReadHeartbeat(townRoot)
-	cycle := int64(1)
-	if existing != nil {
-		cycle = existing.Cycle + 1
+	if owner == (HeartbeatOwner{}) && existing != nil {
+		owner = HeartbeatOwner{PID: existing.PID, Session: existing.Session}
 	}
-
-	return WriteHeartbeat(townRoot, &Heartbeat{
-		Timestamp:       time.Now().UTC(),
-		Cycle:           cycle,
+	hb := &Heartbeat{
+		Timestamp:       now,
+		Cycle:           1,
 		LastAction:      action,
 		HealthyAgents:   healthy,
 		UnhealthyAgents: unhealthy,
-	})
+		PID:             owner.PID,
+		Session:         owner.Session,
+		StartedAt:       now,
+		OwnerCycles:     1,
+	}
+	if existing != nil {
+		hb.Cycle = existing.Cycle + 1
+		hb.Restarts = existing.Restarts
+		if existing.PID == owner.PID && existing.Session == owner.Session && !existing.StartedAt.IsZero() {
+			hb.StartedAt = existing.StartedAt
+			hb.OwnerCycles = existing.OwnerCycles + 1
+		} else if existing.PID != 0 || existing.Session != "" {
+			hb.Restarts++
+		}
+	}
+	return WriteHeartbeat(townRoot, hb)
 }
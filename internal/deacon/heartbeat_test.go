@@ -335,3 +335,44 @@ func TestWriteHeartbeat_SetsTimestamp(t *testing.T) {
 		t.Error("Timestamp should be recent")
 	}
 }
+
+func TestTouchFrom_OwnerCounters(t *testing.T) {
+	tmpDir := t.TempDir()
+	a := HeartbeatOwner{PID: 100, Session: "hq-deacon"}
+	b := HeartbeatOwner{PID: 200, Session: "hq-deacon"}
+
+	for i := 0; i < 3; i++ {
+		if err := TouchFrom(tmpDir, a, "patrol", 0, 0); err != nil {
+			t.Fatalf("TouchFrom: %v", err)
+		}
+	}
+	hb := ReadHeartbeat(tmpDir)
+	if hb.PID != 100 || hb.Session != "hq-deacon" {
+		t.Errorf("owner = %d/%q, want 100/hq-deacon", hb.PID, hb.Session)
+	}
+	if hb.Cycle != 3 || hb.OwnerCycles != 3 || hb.Restarts != 0 {
+		t.Errorf("Cycle/OwnerCycles/Restarts = %d/%d/%d, want 3/3/0", hb.Cycle, hb.OwnerCycles, hb.Restarts)
+	}
+	started := hb.StartedAt
+
+	// A new owner restarts the owner counters and counts a restart.
+	if err := TouchFrom(tmpDir, b, "patrol", 0, 0); err != nil {
+		t.Fatalf("TouchFrom: %v", err)
+	}
+	hb = ReadHeartbeat(tmpDir)
+	if hb.Cycle != 4 || hb.OwnerCycles != 1 || hb.Restarts != 1 {
+		t.Errorf("after takeover Cycle/OwnerCycles/Restarts = %d/%d/%d, want 4/1/1", hb.Cycle, hb.OwnerCycles, hb.Restarts)
+	}
+	if hb.StartedAt.Before(started) {
+		t.Errorf("StartedAt = %v, want reset to the takeover", hb.StartedAt)
+	}
+
+	// A heartbeat touched by hand keeps the current owner.
+	if err := TouchFrom(tmpDir, HeartbeatOwner{}, "manual", 0, 0); err != nil {
+		t.Fatalf("TouchFrom: %v", err)
+	}
+	hb = ReadHeartbeat(tmpDir)
+	if hb.PID != 200 || hb.OwnerCycles != 2 || hb.Restarts != 1 {
+		t.Errorf("after manual touch PID/OwnerCycles/Restarts = %d/%d/%d, want 200/2/1", hb.PID, hb.OwnerCycles, hb.Restarts)
+	}
+}
@@ -0,0 +1,82 @@
+package deacon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// FailoverLease is held by the standby that is bringing the Deacon back.
+// Several standbys (a 'gt deacon standby' process, each rig's Witness) may
+// notice a dead Deacon at once; only the lease holder starts it, and the
+// lease outlives the new session's startup so nobody starts a second one
+// before its first heartbeat.
+type FailoverLease struct {
+	// Holder identifies the standby (e.g., "standby/host-1234", "gastown/witness").
+	Holder string `json:"holder"`
+
+	// Host and PID locate the holder's process.
+	Host string `json:"host,omitempty"`
+	PID  int    `json:"pid,omitempty"`
+
+	// AcquiredAt is when the lease was taken.
+	AcquiredAt time.Time `json:"acquired_at"`
+
+	// ExpiresAt is when other standbys may take the lease.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// FailoverLeaseFile returns the path to the Deacon failover lease.
+func FailoverLeaseFile(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "deacon", "failover-lease.json")
+}
+
+// ReadFailoverLease returns the current lease, or nil if there is none or
+// it can't be read.
+func ReadFailoverLease(townRoot string) *FailoverLease {
+	data, err := os.ReadFile(FailoverLeaseFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		return nil
+	}
+	var lease FailoverLease
+	if err := json.Unmarshal(data, &lease); err != nil {
+		return nil
+	}
+	return &lease
+}
+
+// AcquireFailoverLease takes the failover lease for holder for ttl. It
+// fails (returning the current lease and false) while another holder's
+// lease is unexpired. A holder may re-acquire its own lease.
+func AcquireFailoverLease(townRoot, holder string, ttl time.Duration, now time.Time) (*FailoverLease, bool, error) {
+	path := FailoverLeaseFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, false, err
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return nil, false, fmt.Errorf("locking failover lease: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	if cur := ReadFailoverLease(townRoot); cur != nil && cur.Holder != holder && now.Before(cur.ExpiresAt) {
+		return cur, false, nil
+	}
+	host, _ := os.Hostname()
+	lease := &FailoverLease{
+		Holder:     holder,
+		Host:       host,
+		PID:        os.Getpid(),
+		AcquiredAt: now.UTC(),
+		ExpiresAt:  now.Add(ttl).UTC(),
+	}
+	if err := util.AtomicWriteJSON(path, lease); err != nil {
+		return nil, false, err
+	}
+	return lease, true, nil
+}
@@ -0,0 +1,33 @@
+package deacon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAcquireFailoverLease(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+
+	lease, won, err := AcquireFailoverLease(townRoot, "standby/a", 10*time.Minute, now)
+	if err != nil || !won || lease.Holder != "standby/a" {
+		t.Fatalf("first acquire = %+v, %v, %v", lease, won, err)
+	}
+
+	lease, won, err = AcquireFailoverLease(townRoot, "gastown/witness", 10*time.Minute, now.Add(time.Minute))
+	if err != nil || won || lease.Holder != "standby/a" {
+		t.Errorf("second holder should be refused while the lease is live, got %+v, %v, %v", lease, won, err)
+	}
+
+	if _, won, _ := AcquireFailoverLease(townRoot, "standby/a", 10*time.Minute, now.Add(2*time.Minute)); !won {
+		t.Error("holder should be able to re-acquire its own lease")
+	}
+
+	lease, won, err = AcquireFailoverLease(townRoot, "gastown/witness", 10*time.Minute, now.Add(time.Hour))
+	if err != nil || !won || lease.Holder != "gastown/witness" {
+		t.Errorf("expired lease should be taken over, got %+v, %v, %v", lease, won, err)
+	}
+	if cur := ReadFailoverLease(townRoot); cur == nil || cur.Holder != "gastown/witness" {
+		t.Errorf("ReadFailoverLease = %+v", cur)
+	}
+}
@@ -0,0 +1,221 @@
+package deacon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// Deacon log event kinds.
+const (
+	// LogEventHeartbeat is the Deacon writing its heartbeat at the top of a cycle.
+	LogEventHeartbeat = "heartbeat"
+
+	// LogEventWake is the daemon waking the Deacon: a nudge for a stale
+	// heartbeat, a restart of a stuck session, or a fresh start.
+	LogEventWake = "wake"
+
+	// LogEventSpawn is a pending polecat spawn being triggered.
+	LogEventSpawn = "spawn"
+
+	// LogEventPendingCleared is stale pending spawns being pruned without
+	// ever being triggered.
+	LogEventPendingCleared = "pending-cleared"
+
+	// LogEventEscalation is the Deacon handing a problem to the Mayor: a
+	// bead that failed too many re-dispatches, or a force-killed agent.
+	LogEventEscalation = "escalation"
+
+	// LogEventKill is the Deacon force-killing an unresponsive agent.
+	LogEventKill = "kill"
+
+	// LogEventPause and LogEventResume are the Deacon being paused and resumed.
+	LogEventPause  = "pause"
+	LogEventResume = "resume"
+
+	// LogEventFailover is a standby starting the Deacon after it died
+	// and the daemon failed to bring it back.
+	LogEventFailover = "failover"
+
+	// LogEventSchedule is a scheduled task (gt deacon schedule) being run.
+	LogEventSchedule = "schedule"
+)
+
+// LogEvents lists the Deacon log event kinds, for filtering.
+var LogEvents = []string{
+	LogEventHeartbeat,
+	LogEventWake,
+	LogEventSpawn,
+	LogEventPendingCleared,
+	LogEventEscalation,
+	LogEventKill,
+	LogEventPause,
+	LogEventResume,
+	LogEventFailover,
+	LogEventSchedule,
+}
+
+const (
+	// LogFileName is the active Deacon log under LogDir.
+	LogFileName = "deacon.log"
+
+	// maxLogBytes is the size at which the active log is rotated.
+	maxLogBytes = 5 * 1024 * 1024
+
+	// maxLogBackups is how many rotated logs are kept (deacon.log.1 is newest).
+	maxLogBackups = 3
+)
+
+// LogEntry is one line of the Deacon's structured log.
+type LogEntry struct {
+	Time    time.Time         `json:"time"`
+	Event   string            `json:"event"`
+	Cycle   int64             `json:"cycle,omitempty"`
+	Message string            `json:"message,omitempty"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+// LogDir returns the directory holding the Deacon's logs.
+func LogDir(townRoot string) string {
+	return filepath.Join(townRoot, "deacon", "logs")
+}
+
+// LogFile returns the path to the active Deacon log.
+func LogFile(townRoot string) string {
+	return filepath.Join(LogDir(townRoot), LogFileName)
+}
+
+// logFiles returns the Deacon log files oldest first, active log last.
+func logFiles(townRoot string) []string {
+	var files []string
+	for i := maxLogBackups; i >= 1; i-- {
+		files = append(files, fmt.Sprintf("%s.%d", LogFile(townRoot), i))
+	}
+	return append(files, LogFile(townRoot))
+}
+
+// AppendLog adds an entry to the Deacon log, rotating it first if it has
+// grown past the size limit. Both the Deacon's gt commands and the daemon
+// write here, so appends are serialized with a lock file.
+func AppendLog(townRoot string, entry LogEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	dir := LogDir(townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	fl := flock.New(filepath.Join(dir, ".deacon.log.lock"))
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking deacon log: %w", err)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	path := LogFile(townRoot)
+	if info, err := os.Stat(path); err == nil && info.Size()+int64(len(data)) >= maxLogBytes {
+		rotateLog(townRoot)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// rotateLog shifts deacon.log to deacon.log.1, .1 to .2, and so on,
+// dropping the oldest. Rename failures are ignored; the worst case is a
+// log that keeps growing until the next rotation succeeds.
+func rotateLog(townRoot string) {
+	path := LogFile(townRoot)
+	_ = os.Remove(fmt.Sprintf("%s.%d", path, maxLogBackups))
+	for i := maxLogBackups - 1; i >= 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", path, i), fmt.Sprintf("%s.%d", path, i+1))
+	}
+	_ = os.Rename(path, path+".1")
+}
+
+// ReadLog returns the Deacon log entries at or after since, oldest first,
+// across rotated logs. A zero since returns everything. Malformed lines
+// are skipped.
+func ReadLog(townRoot string, since time.Time) ([]LogEntry, error) {
+	var entries []LogEntry
+	for _, path := range logFiles(townRoot) {
+		f, err := os.Open(path) //nolint:gosec // G304: path is constructed from trusted townRoot
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		fileEntries, _, err := ReadLogEntries(f, since)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		entries = append(entries, fileEntries...)
+	}
+	return entries, nil
+}
+
+// ReadLogEntries reads complete log lines from r, keeping entries at or
+// after since. It returns the number of bytes consumed, which stops short
+// of a trailing partial line so a follower can resume from there.
+func ReadLogEntries(r io.Reader, since time.Time) ([]LogEntry, int64, error) {
+	var entries []LogEntry
+	var consumed int64
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if err == io.EOF {
+			return entries, consumed, nil
+		}
+		if err != nil {
+			return entries, consumed, err
+		}
+		consumed += int64(len(line))
+
+		var entry LogEntry
+		if json.Unmarshal(line, &entry) != nil {
+			continue
+		}
+		if !since.IsZero() && entry.Time.Before(since) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// FilterLogEntries returns the entries whose event is one of events. An
+// empty events list keeps everything.
+func FilterLogEntries(entries []LogEntry, events []string) []LogEntry {
+	if len(events) == 0 {
+		return entries
+	}
+	want := make(map[string]bool, len(events))
+	for _, e := range events {
+		want[e] = true
+	}
+	var kept []LogEntry
+	for _, e := range entries {
+		if want[e.Event] {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
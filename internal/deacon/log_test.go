@@ -0,0 +1,105 @@
+package deacon
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendAndReadLog(t *testing.T) {
+	townRoot := t.TempDir()
+	base := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	entries := []LogEntry{
+		{Time: base, Event: LogEventWake, Message: "started Deacon session", Fields: map[string]string{"by": "daemon"}},
+		{Time: base.Add(time.Minute), Event: LogEventHeartbeat, Cycle: 1, Message: "patrol"},
+		{Time: base.Add(2 * time.Minute), Event: LogEventSpawn, Fields: map[string]string{"rig": "gastown", "polecat": "nux"}},
+	}
+	for _, e := range entries {
+		if err := AppendLog(townRoot, e); err != nil {
+			t.Fatalf("AppendLog: %v", err)
+		}
+	}
+
+	got, err := ReadLog(townRoot, time.Time{})
+	if err != nil {
+		t.Fatalf("ReadLog: %v", err)
+	}
+	if len(got) != 3 || got[1].Cycle != 1 || got[2].Fields["polecat"] != "nux" {
+		t.Fatalf("unexpected entries: %+v", got)
+	}
+
+	got, err = ReadLog(townRoot, base.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("ReadLog since: %v", err)
+	}
+	if len(got) != 2 || got[0].Event != LogEventHeartbeat {
+		t.Errorf("since filter: got %+v", got)
+	}
+}
+
+func TestAppendLog_Rotates(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := AppendLog(townRoot, LogEntry{Event: LogEventHeartbeat, Cycle: 1}); err != nil {
+		t.Fatal(err)
+	}
+	// Grow the active log to the rotation threshold.
+	f, err := os.OpenFile(LogFile(townRoot), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(strings.Repeat("x", maxLogBytes) + "\n"); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	if err := AppendLog(townRoot, LogEntry{Event: LogEventHeartbeat, Cycle: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(LogFile(townRoot) + ".1"); err != nil {
+		t.Fatalf("expected rotated deacon.log.1: %v", err)
+	}
+
+	got, err := ReadLog(townRoot, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Cycle != 1 || got[1].Cycle != 2 {
+		t.Errorf("entries should span rotated and active logs oldest first: %+v", got)
+	}
+}
+
+func TestReadLogEntries_PartialLine(t *testing.T) {
+	complete := `{"time":"2026-10-16T12:00:00Z","event":"heartbeat","cycle":7}` + "\n"
+	entries, n, err := ReadLogEntries(strings.NewReader(complete+`{"time":"2026-10-16T12:01`), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Cycle != 7 {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+	if n != int64(len(complete)) {
+		t.Errorf("consumed %d bytes, want %d (partial line left for the next read)", n, len(complete))
+	}
+}
+
+func TestFilterLogEntries(t *testing.T) {
+	entries := []LogEntry{
+		{Event: LogEventHeartbeat},
+		{Event: LogEventSpawn},
+		{Event: LogEventEscalation},
+		{Event: LogEventSpawn},
+	}
+
+	if got := FilterLogEntries(entries, nil); len(got) != 4 {
+		t.Errorf("no filter: got %d entries, want 4", len(got))
+	}
+	got := FilterLogEntries(entries, []string{LogEventSpawn, LogEventEscalation})
+	if len(got) != 3 || got[0].Event != LogEventSpawn || got[1].Event != LogEventEscalation {
+		t.Errorf("spawn+escalation filter: got %+v", got)
+	}
+	if got := FilterLogEntries(entries, []string{LogEventKill}); len(got) != 0 {
+		t.Errorf("kill filter: got %+v, want none", got)
+	}
+}
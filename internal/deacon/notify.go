@@ -0,0 +1,324 @@
+package deacon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Notification events: the high-signal conditions worth interrupting an
+// operator for.
+const (
+	// NotifyEventEscalation is a problem handed to the Mayor: a bead that
+	// failed too many re-dispatches, or a force-killed agent.
+	NotifyEventEscalation = "escalation"
+
+	// NotifyEventStaleHeartbeat is the daemon finding the Deacon's or a
+	// Witness's heartbeat very stale and nudging or restarting it.
+	NotifyEventStaleHeartbeat = "stale-heartbeat"
+
+	// NotifyEventSpawnFailure is a pending polecat spawn that never became
+	// ready and was escalated.
+	NotifyEventSpawnFailure = "spawn-failure"
+
+	// NotifyEventCircuit is the model API circuit opening or closing: the
+	// town entering or leaving degraded mode.
+	NotifyEventCircuit = "circuit"
+
+	// NotifyEventTest is a test notification (gt deacon notify test).
+	NotifyEventTest = "test"
+)
+
+// NotifyEvents lists the notification events a sink can subscribe to.
+var NotifyEvents = []string{
+	NotifyEventEscalation,
+	NotifyEventStaleHeartbeat,
+	NotifyEventSpawnFailure,
+	NotifyEventCircuit,
+}
+
+// Notification sink types.
+const (
+	NotifySinkDesktop = "desktop"
+	NotifySinkWebhook = "webhook"
+	NotifySinkSlack   = "slack"
+)
+
+// DefaultNotifyCooldown is the minimum time between two notifications with
+// the same key.
+const DefaultNotifyCooldown = 30 * time.Minute
+
+// notifyTimeout bounds delivery to one sink.
+const notifyTimeout = 10 * time.Second
+
+// NotifySinkConfig configures one notification sink in the Deacon config.
+type NotifySinkConfig struct {
+	// Type is desktop, webhook or slack.
+	Type string `json:"type"`
+
+	// URL is the endpoint for webhook and slack sinks. Slack takes an
+	// incoming webhook URL.
+	URL string `json:"url,omitempty"`
+
+	// Events limits the sink to these events; empty means all.
+	Events []string `json:"events,omitempty"`
+}
+
+// Validate checks the sink's type, URL and events.
+func (s *NotifySinkConfig) Validate() error {
+	switch s.Type {
+	case NotifySinkDesktop:
+	case NotifySinkWebhook, NotifySinkSlack:
+		if !strings.HasPrefix(s.URL, "http://") && !strings.HasPrefix(s.URL, "https://") {
+			return fmt.Errorf("%s sink needs an http(s) url, got %q", s.Type, s.URL)
+		}
+	default:
+		return fmt.Errorf("unknown notify sink type %q (valid: desktop, webhook, slack)", s.Type)
+	}
+	for _, ev := range s.Events {
+		if !slices.Contains(NotifyEvents, ev) {
+			return fmt.Errorf("unknown notify event %q (valid: %s)", ev, strings.Join(NotifyEvents, ", "))
+		}
+	}
+	return nil
+}
+
+// Wants reports whether the sink subscribes to event. Test notifications
+// go to every sink.
+func (s *NotifySinkConfig) Wants(event string) bool {
+	return len(s.Events) == 0 || event == NotifyEventTest || slices.Contains(s.Events, event)
+}
+
+// Notification is one event sent to the notification sinks.
+type Notification struct {
+	Event   string            `json:"event"`
+	Title   string            `json:"title"`
+	Message string            `json:"message"`
+	Town    string            `json:"town"`
+	Time    time.Time         `json:"time"`
+	Fields  map[string]string `json:"fields,omitempty"`
+
+	// Key deduplicates: a notification whose key was sent within the
+	// cooldown is dropped. Empty means Event.
+	Key string `json:"-"`
+}
+
+// SpawnFailureNotification describes a pending polecat spawn that never
+// became ready and was escalated to the Mayor.
+func SpawnFailureNotification(rig, polecat string, attempts int) Notification {
+	return Notification{
+		Event:   NotifyEventSpawnFailure,
+		Key:     "spawn-failure:" + rig + "/" + polecat,
+		Title:   fmt.Sprintf("Polecat %s/%s failed to start", rig, polecat),
+		Message: fmt.Sprintf("Not ready after %d readiness checks; escalated to the Mayor.", attempts),
+		Fields:  map[string]string{"rig": rig, "polecat": polecat, "attempts": fmt.Sprint(attempts)},
+	}
+}
+
+// NotifySink delivers notifications somewhere an operator will see them.
+type NotifySink interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// NewNotifySink builds the sink a config describes.
+func NewNotifySink(cfg NotifySinkConfig) (NotifySink, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	switch cfg.Type {
+	case NotifySinkWebhook:
+		return &WebhookSink{URL: cfg.URL}, nil
+	case NotifySinkSlack:
+		return &SlackSink{URL: cfg.URL}, nil
+	default:
+		return &DesktopSink{}, nil
+	}
+}
+
+// DesktopSink shows a desktop notification with notify-send on Linux or
+// osascript on macOS.
+type DesktopSink struct{}
+
+func (DesktopSink) Notify(ctx context.Context, n Notification) error {
+	var c *exec.Cmd
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		c = exec.CommandContext(ctx, "notify-send", "--app-name=Gas Town", n.Title, n.Message)
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(n.Message), appleScriptString(n.Title))
+		c = exec.CommandContext(ctx, "osascript", "-e", script)
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+	if out, err := c.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", c.Args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// WebhookSink POSTs the notification as JSON to a URL.
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func (w *WebhookSink) Notify(ctx context.Context, n Notification) error {
+	return postJSON(ctx, w.Client, w.URL, n)
+}
+
+// SlackSink posts the notification to a Slack incoming webhook.
+type SlackSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func (s *SlackSink) Notify(ctx context.Context, n Notification) error {
+	text := fmt.Sprintf("*%s* (%s)\n%s", n.Title, n.Town, n.Message)
+	return postJSON(ctx, s.Client, s.URL, map[string]string{"text": text})
+}
+
+func postJSON(ctx context.Context, client *http.Client, endpoint string, v interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s: invalid url", RedactURL(endpoint))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		// The client's error repeats the full URL, secret included.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("%s: %w", RedactURL(endpoint), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s: HTTP %d", RedactURL(endpoint), resp.StatusCode)
+	}
+	return nil
+}
+
+// RedactURL drops the path of a webhook URL, which usually carries its
+// secret, for error messages and listings.
+func RedactURL(endpoint string) string {
+	scheme, rest, ok := strings.Cut(endpoint, "://")
+	if !ok {
+		return "webhook"
+	}
+	host, _, _ := strings.Cut(rest, "/")
+	return scheme + "://" + host + "/…"
+}
+
+// notifyStateFile records when each notification key was last sent.
+func notifyStateFile(townRoot string) string {
+	return filepath.Join(townRoot, "deacon", "notify-state.json")
+}
+
+// claimNotification records key as sent at now, reporting false if it was
+// already sent within cooldown. Keys older than a day past the cooldown are
+// pruned.
+func claimNotification(townRoot, key string, cooldown time.Duration, now time.Time) (bool, error) {
+	path := notifyStateFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return false, fmt.Errorf("locking notify state: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	sent := make(map[string]time.Time)
+	if data, err := os.ReadFile(path); err == nil { //nolint:gosec // G304: path is constructed from trusted townRoot
+		_ = json.Unmarshal(data, &sent)
+	}
+	if last, ok := sent[key]; ok && now.Sub(last) < cooldown {
+		return false, nil
+	}
+	for k, t := range sent {
+		if now.Sub(t) > cooldown+24*time.Hour {
+			delete(sent, k)
+		}
+	}
+	sent[key] = now
+	return true, util.AtomicWriteJSON(path, sent)
+}
+
+// Notify sends n to every configured sink subscribed to its event. A
+// notification with the same key as one sent within notify_cooldown is
+// dropped, so a condition that persists across patrol cycles notifies
+// once. Delivery is best-effort: failures are returned, not retried.
+func Notify(townRoot string, n Notification) error {
+	cfg, err := LoadConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Notify) == 0 {
+		return nil
+	}
+	if n.Time.IsZero() {
+		n.Time = time.Now().UTC()
+	}
+	if n.Town == "" {
+		n.Town = filepath.Base(townRoot)
+	}
+	key := n.Key
+	if key == "" {
+		key = n.Event
+	}
+	if n.Event != NotifyEventTest {
+		ok, err := claimNotification(townRoot, key, cfg.GetNotifyCooldown(), n.Time)
+		if err != nil || !ok {
+			return err
+		}
+	}
+	return sendNotification(cfg.Notify, n)
+}
+
+// sendNotification delivers n to the subscribed sinks, collecting failures.
+func sendNotification(sinks []NotifySinkConfig, n Notification) error {
+	var errs []error
+	for i, sc := range sinks {
+		if !sc.Wants(n.Event) {
+			continue
+		}
+		sink, err := NewNotifySink(sc)
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+			err = sink.Notify(ctx, n)
+			cancel()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notify sink %d (%s): %w", i+1, sc.Type, err))
+		}
+	}
+	return errors.Join(errs...)
+}
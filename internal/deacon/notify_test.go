@@ -0,0 +1,181 @@
+package deacon
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// notifyServer records the bodies POSTed to it.
+func notifyServer(t *testing.T, status int) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(data))
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), bodies...)
+	}
+}
+
+func TestNotifySinkConfig_Validate(t *testing.T) {
+	tests := []struct {
+		sink NotifySinkConfig
+		ok   bool
+	}{
+		{NotifySinkConfig{Type: "desktop"}, true},
+		{NotifySinkConfig{Type: "webhook", URL: "https://example.com/hook"}, true},
+		{NotifySinkConfig{Type: "slack", URL: "ftp://example.com"}, false},
+		{NotifySinkConfig{Type: "webhook"}, false},
+		{NotifySinkConfig{Type: "pager"}, false},
+		{NotifySinkConfig{Type: "desktop", Events: []string{"circuit", "escalation"}}, true},
+		{NotifySinkConfig{Type: "desktop", Events: []string{"everything"}}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.sink.Validate(); (err == nil) != tt.ok {
+			t.Errorf("Validate(%+v) = %v, want ok=%v", tt.sink, err, tt.ok)
+		}
+	}
+
+	cfg := &Config{Notify: []NotifySinkConfig{{Type: "desktop"}, {Type: "slack"}}}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "notify[1]") {
+		t.Errorf("Config.Validate = %v, want notify[1] error", err)
+	}
+}
+
+func TestNotifySinkConfig_Wants(t *testing.T) {
+	all := NotifySinkConfig{Type: "desktop"}
+	some := NotifySinkConfig{Type: "desktop", Events: []string{NotifyEventCircuit}}
+	if !all.Wants(NotifyEventEscalation) || !some.Wants(NotifyEventCircuit) {
+		t.Error("expected subscribed events to be wanted")
+	}
+	if some.Wants(NotifyEventEscalation) {
+		t.Error("expected unsubscribed event to be skipped")
+	}
+	if !some.Wants(NotifyEventTest) {
+		t.Error("expected test notifications to reach every sink")
+	}
+}
+
+func TestNotify_WebhookAndSlack(t *testing.T) {
+	hook, hookBodies := notifyServer(t, http.StatusOK)
+	slack, slackBodies := notifyServer(t, http.StatusOK)
+	townRoot := t.TempDir()
+	cfg := &Config{Notify: []NotifySinkConfig{
+		{Type: NotifySinkWebhook, URL: hook.URL + "/secret"},
+		{Type: NotifySinkSlack, URL: slack.URL, Events: []string{NotifyEventCircuit}},
+	}}
+	if err := SaveConfig(townRoot, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Notify(townRoot, SpawnFailureNotification("gastown", "nux", 5)); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	bodies := hookBodies()
+	if len(bodies) != 1 {
+		t.Fatalf("webhook got %d posts, want 1", len(bodies))
+	}
+	var got Notification
+	if err := json.Unmarshal([]byte(bodies[0]), &got); err != nil {
+		t.Fatalf("webhook body: %v", err)
+	}
+	if got.Event != NotifyEventSpawnFailure || got.Fields["polecat"] != "nux" || got.Town == "" || got.Time.IsZero() {
+		t.Errorf("webhook payload = %+v", got)
+	}
+	if len(slackBodies()) != 0 {
+		t.Error("slack sink received an event it didn't subscribe to")
+	}
+
+	if err := Notify(townRoot, Notification{Event: NotifyEventCircuit, Title: "Model API unreachable", Message: "degraded"}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	sb := slackBodies()
+	if len(sb) != 1 {
+		t.Fatalf("slack got %d posts, want 1", len(sb))
+	}
+	var msg map[string]string
+	if err := json.Unmarshal([]byte(sb[0]), &msg); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(msg["text"], "*Model API unreachable*") || !strings.Contains(msg["text"], "degraded") {
+		t.Errorf("slack text = %q", msg["text"])
+	}
+}
+
+func TestNotify_Cooldown(t *testing.T) {
+	hook, bodies := notifyServer(t, http.StatusOK)
+	townRoot := t.TempDir()
+	if err := SaveConfig(townRoot, &Config{Notify: []NotifySinkConfig{{Type: NotifySinkWebhook, URL: hook.URL}}}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	n := Notification{Event: NotifyEventEscalation, Key: "escalation:gt-1", Title: "t", Time: now}
+	for i := 0; i < 3; i++ {
+		if err := Notify(townRoot, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := len(bodies()); got != 1 {
+		t.Errorf("repeats within cooldown sent %d times, want 1", got)
+	}
+
+	other := n
+	other.Key = "escalation:gt-2"
+	_ = Notify(townRoot, other)
+	later := n
+	later.Time = now.Add(DefaultNotifyCooldown + time.Minute)
+	_ = Notify(townRoot, later)
+	test := Notification{Event: NotifyEventTest, Title: "t"}
+	_ = Notify(townRoot, test)
+	_ = Notify(townRoot, test)
+	if got := len(bodies()); got != 5 {
+		t.Errorf("sent %d, want 5 (new key, expired cooldown, two tests)", got)
+	}
+}
+
+func TestNotify_NoSinks(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := Notify(townRoot, Notification{Event: NotifyEventCircuit}); err != nil {
+		t.Errorf("Notify with no sinks = %v", err)
+	}
+	if _, err := LoadConfig(townRoot); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNotify_ErrorRedactsURL(t *testing.T) {
+	hook, _ := notifyServer(t, http.StatusForbidden)
+	townRoot := t.TempDir()
+	if err := SaveConfig(townRoot, &Config{Notify: []NotifySinkConfig{{Type: NotifySinkSlack, URL: hook.URL + "/T000/B000/token"}}}); err != nil {
+		t.Fatal(err)
+	}
+	err := Notify(townRoot, Notification{Event: NotifyEventTest, Title: "t"})
+	if err == nil || !strings.Contains(err.Error(), "HTTP 403") {
+		t.Fatalf("err = %v, want HTTP 403", err)
+	}
+	if strings.Contains(err.Error(), "token") {
+		t.Errorf("error leaks the webhook secret: %v", err)
+	}
+
+	if err := SaveConfig(townRoot, &Config{Notify: []NotifySinkConfig{{Type: NotifySinkWebhook, URL: "http://127.0.0.1:1/hooks/token"}}}); err != nil {
+		t.Fatal(err)
+	}
+	err = Notify(townRoot, Notification{Event: NotifyEventTest, Title: "t"})
+	if err == nil || strings.Contains(err.Error(), "token") {
+		t.Errorf("err = %v, want a redacted connection error", err)
+	}
+}
@@ -226,6 +226,18 @@ func Redispatch(townRoot, beadID, sourceRig string, maxAttempts int, cooldown ti
 		} else {
 			beadState.RecordEscalation()
 			result.Message = fmt.Sprintf("escalated to Mayor after %d failed re-dispatches", beadState.AttemptCount)
+			_ = AppendLog(townRoot, LogEntry{
+				Event:   LogEventEscalation,
+				Message: result.Message,
+				Fields:  map[string]string{"bead": beadID, "attempts": fmt.Sprint(beadState.AttemptCount)},
+			})
+			_ = Notify(townRoot, Notification{
+				Event:   NotifyEventEscalation,
+				Key:     "escalation:" + beadID,
+				Title:   "Bead " + beadID + " escalated to the Mayor",
+				Message: result.Message,
+				Fields:  map[string]string{"bead": beadID, "attempts": fmt.Sprint(beadState.AttemptCount)},
+			})
 		}
 
 		// Save state regardless of escalation success
@@ -0,0 +1,326 @@
+package deacon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// ErrScheduleNotFound is returned for an unknown scheduled task name.
+var ErrScheduleNotFound = errors.New("scheduled task not found")
+
+// DefaultScheduleTimeout bounds one run of a scheduled task.
+const DefaultScheduleTimeout = 30 * time.Minute
+
+// maxScheduleOutput is how much of a run's combined output is kept.
+const maxScheduleOutput = 4096
+
+// Scheduled task run statuses.
+const (
+	ScheduleStatusRunning = "running"
+	ScheduleStatusOK      = "ok"
+	ScheduleStatusFailed  = "failed"
+)
+
+var validScheduleName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ScheduledTask is a shell command the Deacon runs on a cron schedule.
+type ScheduledTask struct {
+	Name    string `json:"name"`
+	Cron    string `json:"cron"`
+	Command string `json:"command"`
+
+	// Timeout bounds one run; zero means DefaultScheduleTimeout.
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	NextRun   time.Time `json:"next_run"`
+
+	LastRun      *time.Time    `json:"last_run,omitempty"`
+	LastStatus   string        `json:"last_status,omitempty"`
+	LastError    string        `json:"last_error,omitempty"`
+	LastDuration time.Duration `json:"last_duration,omitempty"`
+	LastOutput   string        `json:"last_output,omitempty"`
+}
+
+// Schedule holds every scheduled task in a town.
+type Schedule struct {
+	Tasks map[string]*ScheduledTask `json:"tasks"`
+}
+
+// Sorted returns the tasks ordered by name.
+func (s *Schedule) Sorted() []*ScheduledTask {
+	out := make([]*ScheduledTask, 0, len(s.Tasks))
+	for _, t := range s.Tasks {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// ScheduleFile returns the path to the town's schedule.
+func ScheduleFile(townRoot string) string {
+	return filepath.Join(townRoot, "deacon", "schedule.json")
+}
+
+// LoadSchedule reads the town's schedule. A missing file is an empty schedule.
+func LoadSchedule(townRoot string) (*Schedule, error) {
+	s := &Schedule{Tasks: make(map[string]*ScheduledTask)}
+	data, err := os.ReadFile(ScheduleFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading schedule: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ScheduleFile(townRoot), err)
+	}
+	if s.Tasks == nil {
+		s.Tasks = make(map[string]*ScheduledTask)
+	}
+	return s, nil
+}
+
+// UpdateSchedule loads the schedule under a lock, applies fn and saves the
+// result. Nothing is saved if fn returns an error.
+func UpdateSchedule(townRoot string, fn func(*Schedule) error) error {
+	p := ScheduleFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	fl := flock.New(p + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking schedule: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	s, err := LoadSchedule(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := fn(s); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(p, s)
+}
+
+// AddScheduledTask validates and stores a new task, computing its first run
+// from now.
+func AddScheduledTask(townRoot string, task ScheduledTask, now time.Time) (*ScheduledTask, error) {
+	if !validScheduleName.MatchString(task.Name) {
+		return nil, fmt.Errorf("invalid task name %q: use lowercase letters, digits, '-' and '_'", task.Name)
+	}
+	if strings.TrimSpace(task.Command) == "" {
+		return nil, fmt.Errorf("command is required")
+	}
+	spec, err := ParseCron(task.Cron)
+	if err != nil {
+		return nil, err
+	}
+	task.NextRun = spec.Next(now)
+	if task.NextRun.IsZero() {
+		return nil, fmt.Errorf("cron expression %q never fires", task.Cron)
+	}
+	task.CreatedAt = now
+	err = UpdateSchedule(townRoot, func(s *Schedule) error {
+		if _, ok := s.Tasks[task.Name]; ok {
+			return fmt.Errorf("scheduled task %q already exists", task.Name)
+		}
+		s.Tasks[task.Name] = &task
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// RemoveScheduledTask deletes the named task.
+func RemoveScheduledTask(townRoot, name string) error {
+	return UpdateSchedule(townRoot, func(s *Schedule) error {
+		if _, ok := s.Tasks[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrScheduleNotFound, name)
+		}
+		delete(s.Tasks, name)
+		return nil
+	})
+}
+
+// ScheduleRun is the outcome of one run of a scheduled task.
+type ScheduleRun struct {
+	Name     string
+	Command  string
+	Started  time.Time
+	Duration time.Duration
+	Output   string
+	Err      error
+}
+
+// claimDueTasks returns the tasks due at now, advancing each one's NextRun
+// and marking it running under the schedule lock, so a task is run once
+// even with the daemon and a watch loop both polling. Missed runs are not
+// caught up: a task overdue by several periods runs once.
+func claimDueTasks(townRoot string, now time.Time) ([]ScheduledTask, error) {
+	if _, err := os.Stat(ScheduleFile(townRoot)); os.IsNotExist(err) {
+		return nil, nil
+	}
+	var due []ScheduledTask
+	err := UpdateSchedule(townRoot, func(s *Schedule) error {
+		for _, t := range s.Sorted() {
+			if t.NextRun.IsZero() || t.NextRun.After(now) {
+				continue
+			}
+			spec, err := ParseCron(t.Cron)
+			if err != nil {
+				// Hand-edited into something unparseable; record it
+				// rather than retrying every poll.
+				t.LastStatus = ScheduleStatusFailed
+				t.LastError = err.Error()
+				t.NextRun = time.Time{}
+				continue
+			}
+			t.NextRun = spec.Next(now)
+			started := now
+			t.LastRun = &started
+			t.LastStatus = ScheduleStatusRunning
+			t.LastError = ""
+			due = append(due, *t)
+		}
+		if len(due) == 0 {
+			return errNothingDue
+		}
+		return nil
+	})
+	if errors.Is(err, errNothingDue) {
+		return nil, nil
+	}
+	return due, err
+}
+
+// errNothingDue skips rewriting the schedule when no task is due.
+var errNothingDue = errors.New("nothing due")
+
+// recordScheduleRun stores a run's outcome on its task. A task removed
+// while it ran is left removed.
+func recordScheduleRun(townRoot string, run ScheduleRun) error {
+	return UpdateSchedule(townRoot, func(s *Schedule) error {
+		t, ok := s.Tasks[run.Name]
+		if !ok {
+			return nil
+		}
+		started := run.Started
+		t.LastRun = &started
+		t.LastDuration = run.Duration
+		t.LastOutput = run.Output
+		if run.Err != nil {
+			t.LastStatus = ScheduleStatusFailed
+			t.LastError = run.Err.Error()
+		} else {
+			t.LastStatus = ScheduleStatusOK
+			t.LastError = ""
+		}
+		return nil
+	})
+}
+
+// executeScheduledTask runs the task's command through the shell in
+// townRoot, keeping the tail of its combined output.
+func executeScheduledTask(ctx context.Context, townRoot string, t ScheduledTask) ScheduleRun {
+	timeout := t.Timeout
+	if timeout <= 0 {
+		timeout = DefaultScheduleTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", t.Command) //nolint:gosec // G204: command configured by the town operator
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", t.Command) //nolint:gosec // G204: command configured by the town operator
+	}
+	c.Dir = townRoot
+	c.Env = append(os.Environ(), "GT_TOWN_ROOT="+townRoot, "GT_SCHEDULED_TASK="+t.Name)
+	var out bytes.Buffer
+	c.Stdout = &out
+	c.Stderr = &out
+
+	run := ScheduleRun{Name: t.Name, Command: t.Command, Started: time.Now()}
+	run.Err = c.Run()
+	run.Duration = time.Since(run.Started)
+	if ctx.Err() == context.DeadlineExceeded {
+		run.Err = fmt.Errorf("timed out after %v", timeout)
+	}
+	output := out.String()
+	if len(output) > maxScheduleOutput {
+		output = "…" + output[len(output)-maxScheduleOutput:]
+	}
+	run.Output = strings.TrimSpace(output)
+	return run
+}
+
+// runScheduledTask executes a task, records the outcome and logs it.
+func runScheduledTask(ctx context.Context, townRoot string, t ScheduledTask) ScheduleRun {
+	run := executeScheduledTask(ctx, townRoot, t)
+	if err := recordScheduleRun(townRoot, run); err != nil && run.Err == nil {
+		run.Err = fmt.Errorf("recording result: %w", err)
+	}
+	fields := map[string]string{
+		"task":     t.Name,
+		"command":  t.Command,
+		"duration": run.Duration.Round(time.Millisecond).String(),
+	}
+	msg := fmt.Sprintf("scheduled task %s ok", t.Name)
+	if run.Err != nil {
+		fields["error"] = run.Err.Error()
+		msg = fmt.Sprintf("scheduled task %s failed", t.Name)
+	}
+	_ = AppendLog(townRoot, LogEntry{Event: LogEventSchedule, Message: msg, Fields: fields})
+	return run
+}
+
+// RunDueTasks runs every scheduled task due at now, one after another.
+func RunDueTasks(ctx context.Context, townRoot string, now time.Time) ([]ScheduleRun, error) {
+	due, err := claimDueTasks(townRoot, now)
+	if err != nil {
+		return nil, err
+	}
+	var runs []ScheduleRun
+	for _, t := range due {
+		if ctx.Err() != nil {
+			// Claimed but not started: clear the running mark.
+			_ = recordScheduleRun(townRoot, ScheduleRun{Name: t.Name, Command: t.Command, Started: now, Err: ctx.Err()})
+			continue
+		}
+		runs = append(runs, runScheduledTask(ctx, townRoot, t))
+	}
+	return runs, nil
+}
+
+// RunScheduledTaskNow runs the named task immediately, outside its
+// schedule. Its next scheduled run is unchanged.
+func RunScheduledTaskNow(ctx context.Context, townRoot, name string) (ScheduleRun, error) {
+	s, err := LoadSchedule(townRoot)
+	if err != nil {
+		return ScheduleRun{}, err
+	}
+	t, ok := s.Tasks[name]
+	if !ok {
+		return ScheduleRun{}, fmt.Errorf("%w: %s", ErrScheduleNotFound, name)
+	}
+	return runScheduledTask(ctx, townRoot, *t), nil
+}
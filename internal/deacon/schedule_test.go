@@ -0,0 +1,127 @@
+package deacon
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddScheduledTask(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Date(2026, 1, 2, 10, 17, 0, 0, time.UTC)
+
+	task, err := AddScheduledTask(townRoot, ScheduledTask{Name: "sweep", Cron: "0 */2 * * *", Command: "gt witness sweep"}, now)
+	if err != nil {
+		t.Fatalf("AddScheduledTask: %v", err)
+	}
+	if want := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC); !task.NextRun.Equal(want) {
+		t.Errorf("NextRun = %v, want %v", task.NextRun, want)
+	}
+	if _, err := AddScheduledTask(townRoot, ScheduledTask{Name: "sweep", Cron: "@daily", Command: "true"}, now); err == nil {
+		t.Error("duplicate name: want error")
+	}
+	if _, err := AddScheduledTask(townRoot, ScheduledTask{Name: "Bad Name", Cron: "@daily", Command: "true"}, now); err == nil {
+		t.Error("invalid name: want error")
+	}
+	if _, err := AddScheduledTask(townRoot, ScheduledTask{Name: "bad-cron", Cron: "* *", Command: "true"}, now); err == nil {
+		t.Error("invalid cron: want error")
+	}
+
+	s, err := LoadSchedule(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Tasks) != 1 || s.Tasks["sweep"].Command != "gt witness sweep" {
+		t.Errorf("schedule = %+v, want only sweep", s.Tasks)
+	}
+
+	if err := RemoveScheduledTask(townRoot, "sweep"); err != nil {
+		t.Fatalf("RemoveScheduledTask: %v", err)
+	}
+	if err := RemoveScheduledTask(townRoot, "sweep"); !errors.Is(err, ErrScheduleNotFound) {
+		t.Errorf("second remove = %v, want ErrScheduleNotFound", err)
+	}
+}
+
+func TestRunDueTasks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	townRoot := t.TempDir()
+	now := time.Date(2026, 1, 2, 10, 17, 0, 0, time.UTC)
+	for _, task := range []ScheduledTask{
+		{Name: "hello", Cron: "*/5 * * * *", Command: "echo hello from $GT_SCHEDULED_TASK"},
+		{Name: "broken", Cron: "*/5 * * * *", Command: "echo oops; exit 3"},
+		{Name: "later", Cron: "@daily", Command: "echo later"},
+	} {
+		if _, err := AddScheduledTask(townRoot, task, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Nothing is due yet.
+	runs, err := RunDueTasks(context.Background(), townRoot, now)
+	if err != nil || len(runs) != 0 {
+		t.Fatalf("RunDueTasks before due = %v, %v; want none", runs, err)
+	}
+
+	at := time.Date(2026, 1, 2, 10, 20, 0, 0, time.UTC)
+	runs, err = RunDueTasks(context.Background(), townRoot, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("ran %d tasks, want 2 (broken, hello)", len(runs))
+	}
+
+	s, err := LoadSchedule(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hello := s.Tasks["hello"]
+	if hello.LastStatus != ScheduleStatusOK || hello.LastOutput != "hello from hello" {
+		t.Errorf("hello = %q %q, want ok with output", hello.LastStatus, hello.LastOutput)
+	}
+	if want := time.Date(2026, 1, 2, 10, 25, 0, 0, time.UTC); !hello.NextRun.Equal(want) {
+		t.Errorf("hello NextRun = %v, want %v", hello.NextRun, want)
+	}
+	broken := s.Tasks["broken"]
+	if broken.LastStatus != ScheduleStatusFailed || !strings.Contains(broken.LastError, "exit status 3") {
+		t.Errorf("broken = %q %q, want failed with exit status", broken.LastStatus, broken.LastError)
+	}
+	if s.Tasks["later"].LastRun != nil {
+		t.Error("later should not have run")
+	}
+
+	// Claimed tasks are not run again until their next time.
+	runs, err = RunDueTasks(context.Background(), townRoot, at)
+	if err != nil || len(runs) != 0 {
+		t.Errorf("second RunDueTasks = %d runs, %v; want none", len(runs), err)
+	}
+}
+
+func TestRunScheduledTaskNow(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	townRoot := t.TempDir()
+	now := time.Now()
+	task, err := AddScheduledTask(townRoot, ScheduledTask{Name: "pwd", Cron: "@yearly", Command: "pwd"}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, err := RunScheduledTaskNow(context.Background(), townRoot, "pwd")
+	if err != nil || run.Err != nil {
+		t.Fatalf("RunScheduledTaskNow = %v, %v", run.Err, err)
+	}
+	s, _ := LoadSchedule(townRoot)
+	if !s.Tasks["pwd"].NextRun.Equal(task.NextRun) {
+		t.Error("running now should not move NextRun")
+	}
+	if _, err := RunScheduledTaskNow(context.Background(), townRoot, "nope"); !errors.Is(err, ErrScheduleNotFound) {
+		t.Errorf("unknown task = %v, want ErrScheduleNotFound", err)
+	}
+}
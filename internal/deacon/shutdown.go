@@ -0,0 +1,116 @@
+package deacon
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ShutdownRequestTTL bounds how long a shutdown request stays live. An older
+// request was left behind by a stop that never finished, and a Deacon that
+// heartbeats later ignores it.
+const ShutdownRequestTTL = 30 * time.Minute
+
+// ShutdownRequest represents the SHUTDOWN_REQUESTED file written by
+// gt deacon stop. The Deacon acknowledges it on its next heartbeat, after
+// flushing its state, and the stop then kills the session.
+type ShutdownRequest struct {
+	// RequestedAt is when the shutdown was requested.
+	RequestedAt time.Time `json:"requested_at"`
+
+	// RequestedBy identifies who asked (e.g., "human", "mayor").
+	RequestedBy string `json:"requested_by,omitempty"`
+
+	// ReadyAt is when the Deacon acknowledged the request: its heartbeat
+	// is written and its pending work persisted. Zero until then.
+	ReadyAt time.Time `json:"ready_at,omitempty"`
+
+	// Cycle is the heartbeat cycle the Deacon acknowledged on.
+	Cycle int64 `json:"cycle,omitempty"`
+
+	// Pending lists the sessions of the pending spawns handed off to the
+	// next Deacon.
+	Pending []string `json:"pending,omitempty"`
+}
+
+// Ready reports whether the Deacon has acknowledged the request.
+func (r *ShutdownRequest) Ready() bool {
+	return r != nil && !r.ReadyAt.IsZero()
+}
+
+// Expired reports whether the request is older than ShutdownRequestTTL.
+func (r *ShutdownRequest) Expired() bool {
+	return r == nil || time.Since(r.RequestedAt) > ShutdownRequestTTL
+}
+
+// ShutdownFile returns the path to the Deacon shutdown request file.
+func ShutdownFile(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "deacon", "SHUTDOWN_REQUESTED")
+}
+
+// ReadShutdownRequest returns the pending shutdown request.
+// If the file doesn't exist, returns (nil, nil).
+func ReadShutdownRequest(townRoot string) (*ShutdownRequest, error) {
+	data, err := os.ReadFile(ShutdownFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var req ShutdownRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// RequestShutdown asks the Deacon to wind down by writing the shutdown
+// request file, replacing any earlier request.
+func RequestShutdown(townRoot, requestedBy string) (*ShutdownRequest, error) {
+	req := &ShutdownRequest{
+		RequestedAt: time.Now().UTC(),
+		RequestedBy: requestedBy,
+	}
+	return req, writeShutdownRequest(townRoot, req)
+}
+
+// AcknowledgeShutdown marks the request ready, recording the heartbeat
+// cycle and the pending spawns handed off. It is a no-op returning
+// (nil, nil) when no live request is pending.
+func AcknowledgeShutdown(townRoot string, cycle int64, pending []string) (*ShutdownRequest, error) {
+	req, err := ReadShutdownRequest(townRoot)
+	if err != nil || req == nil || req.Expired() {
+		return nil, err
+	}
+	req.ReadyAt = time.Now().UTC()
+	req.Cycle = cycle
+	req.Pending = pending
+	return req, writeShutdownRequest(townRoot, req)
+}
+
+// ClearShutdownRequest removes the shutdown request file.
+func ClearShutdownRequest(townRoot string) error {
+	err := os.Remove(ShutdownFile(townRoot))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func writeShutdownRequest(townRoot string, req *ShutdownRequest) error {
+	path := ShutdownFile(townRoot)
+
+	// Ensure parent directory exists
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(req, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,63 @@
+package deacon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShutdownRequest_Lifecycle(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	req, err := ReadShutdownRequest(tmpDir)
+	if err != nil || req != nil {
+		t.Fatalf("ReadShutdownRequest() with no file = %v, %v; want nil, nil", req, err)
+	}
+	if req, err := AcknowledgeShutdown(tmpDir, 1, nil); err != nil || req != nil {
+		t.Fatalf("AcknowledgeShutdown() with no request = %v, %v; want nil, nil", req, err)
+	}
+
+	if _, err := RequestShutdown(tmpDir, "human"); err != nil {
+		t.Fatalf("RequestShutdown() error = %v", err)
+	}
+	req, err = ReadShutdownRequest(tmpDir)
+	if err != nil || req == nil {
+		t.Fatalf("ReadShutdownRequest() = %v, %v", req, err)
+	}
+	if req.Ready() || req.Expired() || req.RequestedBy != "human" {
+		t.Errorf("new request = %+v, want live and not ready", req)
+	}
+
+	if _, err := AcknowledgeShutdown(tmpDir, 42, []string{"gt-nux"}); err != nil {
+		t.Fatalf("AcknowledgeShutdown() error = %v", err)
+	}
+	req, _ = ReadShutdownRequest(tmpDir)
+	if !req.Ready() || req.Cycle != 42 || len(req.Pending) != 1 || req.Pending[0] != "gt-nux" {
+		t.Errorf("acknowledged request = %+v", req)
+	}
+
+	if err := ClearShutdownRequest(tmpDir); err != nil {
+		t.Fatalf("ClearShutdownRequest() error = %v", err)
+	}
+	if req, _ := ReadShutdownRequest(tmpDir); req != nil {
+		t.Errorf("request after clear = %+v", req)
+	}
+	if err := ClearShutdownRequest(tmpDir); err != nil {
+		t.Errorf("clearing a missing request: %v", err)
+	}
+}
+
+func TestAcknowledgeShutdown_IgnoresExpiredRequest(t *testing.T) {
+	tmpDir := t.TempDir()
+	old := &ShutdownRequest{RequestedAt: time.Now().Add(-2 * ShutdownRequestTTL)}
+	if err := writeShutdownRequest(tmpDir, old); err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := AcknowledgeShutdown(tmpDir, 1, nil)
+	if err != nil || req != nil {
+		t.Errorf("AcknowledgeShutdown() on expired request = %v, %v; want nil, nil", req, err)
+	}
+	if req, _ := ReadShutdownRequest(tmpDir); req.Ready() {
+		t.Error("expired request should not be acknowledged")
+	}
+}
@@ -0,0 +1,212 @@
+package deacon
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// SnapshotFormat identifies the manifest of a Deacon snapshot archive.
+const SnapshotFormat = "gastown-deacon-snapshot/1"
+
+// snapshotManifestName is the archive entry holding the manifest.
+const snapshotManifestName = "manifest.json"
+
+// snapshotStatePrefix is the archive directory holding state files.
+const snapshotStatePrefix = "state/"
+
+// maxSnapshotEntry bounds one archive entry when reading, so a corrupt or
+// hostile archive can't exhaust memory.
+const maxSnapshotEntry = 16 << 20
+
+// SnapshotStateFiles are the town-relative runtime state files a snapshot
+// carries. The failover lease and the daemon's PID state are left out on
+// purpose: they name processes on the host that wrote them and mean
+// nothing after a reboot or on another machine.
+var SnapshotStateFiles = []string{
+	"deacon/config.json",
+	"deacon/heartbeat.json",
+	"deacon/schedule.json",
+	"deacon/redispatch-state.json",
+	"deacon/health-check-state.json",
+	"deacon/notify-state.json",
+	".runtime/deacon/paused.json",
+	".runtime/deacon/headless.json",
+	".runtime/deacon/inbox-cursor.json",
+	".runtime/pending-spawns.json",
+	"daemon/inbox_nag.json",
+	"daemon/restart_state.json",
+}
+
+// SnapshotManifest describes a Deacon snapshot: the state files it carries
+// and an inventory of what was running when it was taken.
+type SnapshotManifest struct {
+	Format    string    `json:"format"`
+	CreatedAt time.Time `json:"created_at"`
+	Host      string    `json:"host"`
+	TownRoot  string    `json:"town_root"`
+
+	// Files are the town-relative state files in the archive.
+	Files []string `json:"files"`
+
+	// PendingSpawns are the polecats the Deacon was waiting on. The
+	// POLECAT_STARTED mail stays the source of truth; this records what
+	// to look for after a restore.
+	PendingSpawns []SnapshotSpawn `json:"pending_spawns,omitempty"`
+
+	// Sessions is the town's tmux session inventory.
+	Sessions []SnapshotSession `json:"sessions,omitempty"`
+}
+
+// SnapshotSpawn is a pending polecat spawn recorded in a snapshot.
+type SnapshotSpawn struct {
+	Rig       string    `json:"rig"`
+	Polecat   string    `json:"polecat"`
+	Session   string    `json:"session"`
+	Issue     string    `json:"issue,omitempty"`
+	MailID    string    `json:"mail_id"`
+	SpawnedAt time.Time `json:"spawned_at"`
+	Attempts  int       `json:"attempts,omitempty"`
+}
+
+// SnapshotSession is one agent session recorded in a snapshot.
+type SnapshotSession struct {
+	Name    string `json:"name"`
+	Role    string `json:"role"`
+	Address string `json:"address"`
+}
+
+// WriteSnapshot writes a gzipped tar archive of the manifest and every
+// state file present under townRoot to w. It fills in m.Format and
+// m.Files.
+func WriteSnapshot(townRoot string, w io.Writer, m *SnapshotManifest) error {
+	files := make(map[string][]byte)
+	m.Files = nil
+	for _, rel := range SnapshotStateFiles {
+		data, err := os.ReadFile(filepath.Join(townRoot, filepath.FromSlash(rel))) //nolint:gosec // G304: path is constructed from trusted townRoot
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", rel, err)
+		}
+		files[rel] = data
+		m.Files = append(m.Files, rel)
+	}
+	m.Format = SnapshotFormat
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	add := func(name string, data []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: m.CreatedAt}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	if err := add(snapshotManifestName, manifest); err != nil {
+		return err
+	}
+	for _, rel := range m.Files {
+		if err := add(snapshotStatePrefix+rel, files[rel]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// ReadSnapshot reads an archive written by WriteSnapshot, returning its
+// manifest and state files keyed by town-relative path. Entries that
+// aren't known state files are rejected, so restoring an archive can only
+// ever write the files listed in SnapshotStateFiles.
+func ReadSnapshot(r io.Reader) (*SnapshotManifest, map[string][]byte, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("not a Deacon snapshot: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	var m *SnapshotManifest
+	files := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading snapshot: %w", err)
+		}
+		if hdr.Size > maxSnapshotEntry {
+			return nil, nil, fmt.Errorf("snapshot entry %s is too large (%d bytes)", hdr.Name, hdr.Size)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxSnapshotEntry))
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading %s: %w", hdr.Name, err)
+		}
+		rel, isState := strings.CutPrefix(hdr.Name, snapshotStatePrefix)
+		switch {
+		case hdr.Name == snapshotManifestName:
+			m = &SnapshotManifest{}
+			if err := json.Unmarshal(data, m); err != nil {
+				return nil, nil, fmt.Errorf("parsing snapshot manifest: %w", err)
+			}
+		case isState:
+			if !slices.Contains(SnapshotStateFiles, rel) {
+				return nil, nil, fmt.Errorf("snapshot contains unexpected file %q", rel)
+			}
+			files[rel] = data
+		default:
+			return nil, nil, fmt.Errorf("snapshot contains unexpected entry %q", hdr.Name)
+		}
+	}
+	if m == nil {
+		return nil, nil, fmt.Errorf("not a Deacon snapshot: no %s", snapshotManifestName)
+	}
+	if m.Format != SnapshotFormat {
+		return nil, nil, fmt.Errorf("unsupported snapshot format %q (want %s)", m.Format, SnapshotFormat)
+	}
+	return m, files, nil
+}
+
+// RestoreSnapshot writes the state files read from a snapshot into
+// townRoot, replacing what is there, and returns the paths written in
+// SnapshotStateFiles order. Files the snapshot doesn't carry are left
+// alone. Each write is atomic; a failure stops the restore and reports
+// which files were already written.
+func RestoreSnapshot(townRoot string, files map[string][]byte) ([]string, error) {
+	var restored []string
+	for _, rel := range SnapshotStateFiles {
+		data, ok := files[rel]
+		if !ok {
+			continue
+		}
+		path := filepath.Join(townRoot, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return restored, err
+		}
+		if err := util.AtomicWriteFile(path, data, 0644); err != nil {
+			return restored, fmt.Errorf("restoring %s: %w", rel, err)
+		}
+		restored = append(restored, rel)
+	}
+	return restored, nil
+}
@@ -0,0 +1,101 @@
+package deacon
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	src := t.TempDir()
+	if err := TouchWithAction(src, "patrol", 3, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveConfig(src, &Config{CaptureLines: 25}); err != nil {
+		t.Fatal(err)
+	}
+	lease := filepath.Join(src, ".runtime", "deacon", "failover-lease.json")
+	if err := os.MkdirAll(filepath.Dir(lease), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(lease, []byte(`{"holder":"x"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	m := &SnapshotManifest{
+		CreatedAt:     time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC),
+		Host:          "old-host",
+		PendingSpawns: []SnapshotSpawn{{Rig: "gastown", Polecat: "nux", MailID: "hq-m1"}},
+		Sessions:      []SnapshotSession{{Name: "hq-deacon", Role: "deacon", Address: "deacon/"}},
+	}
+	if err := WriteSnapshot(src, &buf, m); err != nil {
+		t.Fatalf("WriteSnapshot: %v", err)
+	}
+	if strings.Join(m.Files, ",") != "deacon/config.json,deacon/heartbeat.json" {
+		t.Errorf("Files = %v", m.Files)
+	}
+
+	got, files, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("ReadSnapshot: %v", err)
+	}
+	if got.Host != "old-host" || len(got.PendingSpawns) != 1 || len(got.Sessions) != 1 {
+		t.Errorf("manifest = %+v", got)
+	}
+
+	dst := t.TempDir()
+	restored, err := RestoreSnapshot(dst, files)
+	if err != nil {
+		t.Fatalf("RestoreSnapshot: %v", err)
+	}
+	if len(restored) != 2 {
+		t.Errorf("restored = %v", restored)
+	}
+	hb := ReadHeartbeat(dst)
+	if hb == nil || hb.LastAction != "patrol" || hb.HealthyAgents != 3 {
+		t.Errorf("restored heartbeat = %+v", hb)
+	}
+	cfg, err := LoadConfig(dst)
+	if err != nil || cfg.GetCaptureLines() != 25 {
+		t.Errorf("restored config = %+v, %v", cfg, err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, ".runtime", "deacon", "failover-lease.json")); !os.IsNotExist(err) {
+		t.Error("failover lease should not be carried by a snapshot")
+	}
+}
+
+func TestReadSnapshot_Rejects(t *testing.T) {
+	archive := func(entries map[string]string) *bytes.Buffer {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for name, body := range entries {
+			_ = tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(body))})
+			_, _ = tw.Write([]byte(body))
+		}
+		_ = tw.Close()
+		_ = gz.Close()
+		return &buf
+	}
+	manifest := `{"format":"` + SnapshotFormat + `"}`
+
+	tests := map[string]*bytes.Buffer{
+		"not gzip":       bytes.NewBufferString("plain text"),
+		"no manifest":    archive(map[string]string{"state/deacon/heartbeat.json": "{}"}),
+		"wrong format":   archive(map[string]string{"manifest.json": `{"format":"other/9"}`}),
+		"unknown file":   archive(map[string]string{"manifest.json": manifest, "state/mayor/town.json": "{}"}),
+		"path traversal": archive(map[string]string{"manifest.json": manifest, "state/../../etc/passwd": "x"}),
+		"stray entry":    archive(map[string]string{"manifest.json": manifest, "README": "hi"}),
+	}
+	for name, buf := range tests {
+		if _, _, err := ReadSnapshot(buf); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
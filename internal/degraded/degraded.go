@@ -0,0 +1,341 @@
+// Package degraded tracks whether the town is running without the model API.
+//
+// When the API is unreachable, agents cannot make progress: new polecats die
+// at startup or sit at an error prompt, AI-review gates fail spuriously, and
+// healthy agents stop writing heartbeats. Degraded mode records the outage in
+// <town>/.runtime/degraded.json so the rest of the town can react: spawns are
+// refused, the refinery holds MRs whose gates need the model, and the daemon
+// relaxes its heartbeat expectations instead of restarting agents that are
+// only waiting on the API.
+//
+// The mode is entered automatically after repeated probe failures, or after
+// repeated agent launch failures confirmed by a probe. It is left
+// automatically when a probe succeeds again, unless it was entered by hand.
+package degraded
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Sources that can enter or leave degraded mode.
+const (
+	SourceProbe  = "probe"  // Periodic reachability probe (daemon)
+	SourceLaunch = "launch" // Agent sessions dying during startup
+	SourceManual = "manual" // gt degraded enter/exit
+)
+
+// Thresholds for automatic entry. Failures older than failureWindow don't
+// count toward the threshold.
+const (
+	ProbeFailureThreshold  = 3
+	LaunchFailureThreshold = 3
+	failureWindow          = 15 * time.Minute
+)
+
+// DefaultProbeURL is probed when ANTHROPIC_BASE_URL is not set.
+const DefaultProbeURL = "https://api.anthropic.com"
+
+// probeTimeout bounds a single probe request.
+const probeTimeout = 10 * time.Second
+
+// stateFile lives under <town>/.runtime/.
+const stateFile = "degraded.json"
+
+// ErrDegraded is returned by operations refused while the town is degraded.
+var ErrDegraded = errors.New("town is in degraded mode (model API unreachable)")
+
+// probe is the reachability check used to confirm launch failures.
+// Replaced in tests.
+var probe = func(ctx context.Context) error {
+	return Probe(ctx, nil, ProbeURL())
+}
+
+// State is the contents of the degraded mode state file.
+type State struct {
+	// Active is true while the town is in degraded mode.
+	Active bool `json:"active"`
+
+	// Reason explains why degraded mode was entered.
+	Reason string `json:"reason,omitempty"`
+
+	// Source is what entered degraded mode (probe, launch, manual).
+	Source string `json:"source,omitempty"`
+
+	// Since is when degraded mode was entered.
+	Since time.Time `json:"since,omitempty"`
+
+	// ProbeFailures counts consecutive failed probes.
+	ProbeFailures int `json:"probe_failures,omitempty"`
+
+	// LaunchFailures counts consecutive agent launch failures.
+	LaunchFailures int `json:"launch_failures,omitempty"`
+
+	// LastFailure is when the most recent probe or launch failure happened.
+	LastFailure time.Time `json:"last_failure,omitempty"`
+
+	// LastProbe is when the API was last probed.
+	LastProbe time.Time `json:"last_probe,omitempty"`
+}
+
+// Duration returns how long degraded mode has been active.
+func (s *State) Duration(now time.Time) time.Duration {
+	if !s.Active || s.Since.IsZero() {
+		return 0
+	}
+	return now.Sub(s.Since)
+}
+
+// StatePath returns the path of the degraded mode state file.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, stateFile)
+}
+
+// Load reads the degraded mode state. A missing file means not degraded.
+func Load(townRoot string) (*State, error) {
+	data, err := os.ReadFile(StatePath(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if os.IsNotExist(err) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading degraded state: %w", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing degraded state: %w", err)
+	}
+	return &state, nil
+}
+
+// IsActive reports whether the town is in degraded mode.
+// An unreadable state file is treated as not degraded.
+func IsActive(townRoot string) bool {
+	state, err := Load(townRoot)
+	return err == nil && state.Active
+}
+
+// Enter puts the town into degraded mode. Returns false if it already was.
+func Enter(townRoot, source, reason string) (bool, error) {
+	var entered bool
+	err := update(townRoot, func(s *State) {
+		entered = enter(s, source, reason, time.Now().UTC())
+	})
+	if err != nil || !entered {
+		return false, err
+	}
+	_ = events.LogFeed(events.TypeDegradedEnter, eventActor(source), events.DegradedPayload(source, reason, 0))
+	return true, nil
+}
+
+// Exit leaves degraded mode. Returns false if the town wasn't degraded.
+func Exit(townRoot, source string) (bool, error) {
+	var duration time.Duration
+	var exited bool
+	err := update(townRoot, func(s *State) {
+		duration, exited = exit(s, time.Now().UTC())
+	})
+	if err != nil || !exited {
+		return false, err
+	}
+	_ = events.LogFeed(events.TypeDegradedExit, eventActor(source), events.DegradedPayload(source, "", duration))
+	return true, nil
+}
+
+// RecordProbe updates the state with a probe result: consecutive failures
+// enter degraded mode once they reach ProbeFailureThreshold, and a success
+// leaves a degraded mode that wasn't entered by hand.
+func RecordProbe(townRoot string, probeErr error) (*State, error) {
+	now := time.Now().UTC()
+	var entered bool
+	var duration time.Duration
+	var exited bool
+	reason := ""
+	if probeErr != nil {
+		reason = fmt.Sprintf("model API probe failed: %v", probeErr)
+	}
+
+	var result State
+	err := update(townRoot, func(s *State) {
+		s.LastProbe = now
+		if probeErr == nil {
+			s.ProbeFailures = 0
+			s.LaunchFailures = 0
+			if s.Source != SourceManual {
+				duration, exited = exit(s, now)
+			}
+		} else {
+			recordFailure(s, &s.ProbeFailures, now)
+			if s.ProbeFailures >= ProbeFailureThreshold {
+				entered = enter(s, SourceProbe, reason, now)
+			}
+		}
+		result = *s
+	})
+	if err != nil {
+		return nil, err
+	}
+	if entered {
+		_ = events.LogFeed(events.TypeDegradedEnter, eventActor(SourceProbe), events.DegradedPayload(SourceProbe, reason, 0))
+	}
+	if exited {
+		_ = events.LogFeed(events.TypeDegradedExit, eventActor(SourceProbe), events.DegradedPayload(SourceProbe, "", duration))
+	}
+	return &result, nil
+}
+
+// ReportLaunchFailure records an agent session that died during startup.
+// Once LaunchFailureThreshold launches have failed in a row, the model API
+// is probed, and degraded mode is entered only if it is unreachable too, so a
+// broken agent command alone doesn't pause the town. Returns true if
+// degraded mode was entered.
+func ReportLaunchFailure(townRoot string, launchErr error) (bool, error) {
+	var failures int
+	err := update(townRoot, func(s *State) {
+		recordFailure(s, &s.LaunchFailures, time.Now().UTC())
+		failures = s.LaunchFailures
+	})
+	if err != nil || failures < LaunchFailureThreshold || IsActive(townRoot) {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	probeErr := probe(ctx)
+	if probeErr == nil {
+		return false, nil
+	}
+	return Enter(townRoot, SourceLaunch, fmt.Sprintf("%d agent launches failed (last: %v); model API probe failed: %v",
+		failures, launchErr, probeErr))
+}
+
+// ReportLaunchSuccess resets the launch failure count.
+func ReportLaunchSuccess(townRoot string) error {
+	state, err := Load(townRoot)
+	if err != nil || state.LaunchFailures == 0 {
+		return err
+	}
+	return update(townRoot, func(s *State) { s.LaunchFailures = 0 })
+}
+
+// ProbeURL returns the model API endpoint to probe, honoring
+// ANTHROPIC_BASE_URL for proxies and gateways.
+func ProbeURL() string {
+	if u := os.Getenv("ANTHROPIC_BASE_URL"); u != "" {
+		return u
+	}
+	return DefaultProbeURL
+}
+
+// Probe checks that the model API at url answers. Any HTTP response below
+// 500 counts as reachable (an unauthenticated request is expected to be
+// rejected); connection errors, timeouts, and 5xx responses (including 529
+// overloaded) count as down. A nil client uses a default with probeTimeout.
+func Probe(ctx context.Context, client *http.Client, url string) error {
+	if client == nil {
+		client = &http.Client{Timeout: probeTimeout}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("building probe request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return nil
+}
+
+// eventActor names who entered or left degraded mode in events: the daemon
+// for probes, otherwise the current agent (or a human).
+func eventActor(source string) string {
+	if source == SourceProbe {
+		return "daemon"
+	}
+	if role := os.Getenv("GT_ROLE"); role != "" {
+		return role
+	}
+	return "human"
+}
+
+// enter marks s degraded. Returns false if it already was.
+func enter(s *State, source, reason string, now time.Time) bool {
+	if s.Active {
+		return false
+	}
+	s.Active = true
+	s.Source = source
+	s.Reason = reason
+	s.Since = now
+	return true
+}
+
+// exit clears degraded mode on s, returning how long it lasted.
+func exit(s *State, now time.Time) (time.Duration, bool) {
+	if !s.Active {
+		return 0, false
+	}
+	duration := s.Duration(now)
+	s.Active = false
+	s.Source = ""
+	s.Reason = ""
+	s.Since = time.Time{}
+	s.ProbeFailures = 0
+	s.LaunchFailures = 0
+	return duration, true
+}
+
+// recordFailure bumps a consecutive failure counter, restarting the count
+// when the previous failure is outside failureWindow.
+func recordFailure(s *State, counter *int, now time.Time) {
+	if !s.LastFailure.IsZero() && now.Sub(s.LastFailure) > failureWindow {
+		s.ProbeFailures = 0
+		s.LaunchFailures = 0
+	}
+	*counter++
+	s.LastFailure = now
+}
+
+// update applies fn to the state under a cross-process file lock and
+// writes the result back atomically.
+func update(townRoot string, fn func(*State)) error {
+	path := StatePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking degraded state: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	state, err := Load(townRoot)
+	if err != nil {
+		// A corrupt state file must not wedge the town; start over.
+		state = &State{}
+	}
+	fn(state)
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding degraded state: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: not sensitive
+		return fmt.Errorf("writing degraded state: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
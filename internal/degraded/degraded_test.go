@@ -0,0 +1,203 @@
+package degraded
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLoadMissingIsNormal(t *testing.T) {
+	state, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if state.Active {
+		t.Error("missing state file should mean not degraded")
+	}
+}
+
+func TestEnterExit(t *testing.T) {
+	townRoot := t.TempDir()
+
+	entered, err := Enter(townRoot, SourceManual, "planned outage")
+	if err != nil || !entered {
+		t.Fatalf("Enter = %v, %v; want true, nil", entered, err)
+	}
+	if !IsActive(townRoot) {
+		t.Fatal("IsActive = false after Enter")
+	}
+	if entered, _ := Enter(townRoot, SourceManual, "again"); entered {
+		t.Error("second Enter should report already degraded")
+	}
+	state, _ := Load(townRoot)
+	if state.Reason != "planned outage" || state.Source != SourceManual || state.Since.IsZero() {
+		t.Errorf("state = %+v", state)
+	}
+
+	exited, err := Exit(townRoot, SourceManual)
+	if err != nil || !exited {
+		t.Fatalf("Exit = %v, %v; want true, nil", exited, err)
+	}
+	if IsActive(townRoot) {
+		t.Error("IsActive = true after Exit")
+	}
+	if exited, _ := Exit(townRoot, SourceManual); exited {
+		t.Error("second Exit should report not degraded")
+	}
+}
+
+func TestRecordProbeThresholdAndResume(t *testing.T) {
+	townRoot := t.TempDir()
+	down := errors.New("connection refused")
+
+	for i := 1; i < ProbeFailureThreshold; i++ {
+		state, err := RecordProbe(townRoot, down)
+		if err != nil {
+			t.Fatalf("RecordProbe: %v", err)
+		}
+		if state.Active {
+			t.Fatalf("degraded after %d failures, threshold is %d", i, ProbeFailureThreshold)
+		}
+	}
+	state, _ := RecordProbe(townRoot, down)
+	if !state.Active || state.Source != SourceProbe {
+		t.Fatalf("state after threshold = %+v, want active from probe", state)
+	}
+
+	state, _ = RecordProbe(townRoot, nil)
+	if state.Active {
+		t.Error("successful probe should end probe-entered degraded mode")
+	}
+	if state.ProbeFailures != 0 {
+		t.Errorf("ProbeFailures = %d after success, want 0", state.ProbeFailures)
+	}
+}
+
+func TestRecordProbeKeepsManualMode(t *testing.T) {
+	townRoot := t.TempDir()
+	if _, err := Enter(townRoot, SourceManual, ""); err != nil {
+		t.Fatal(err)
+	}
+	state, _ := RecordProbe(townRoot, nil)
+	if !state.Active {
+		t.Error("probe success should not end manually entered degraded mode")
+	}
+}
+
+func TestRecordProbeSuccessResetsCount(t *testing.T) {
+	townRoot := t.TempDir()
+	down := errors.New("timeout")
+	for i := 0; i < ProbeFailureThreshold-1; i++ {
+		_, _ = RecordProbe(townRoot, down)
+	}
+	_, _ = RecordProbe(townRoot, nil)
+	state, _ := RecordProbe(townRoot, down)
+	if state.Active {
+		t.Error("failures separated by a success should not add up")
+	}
+}
+
+func TestRecordFailureWindow(t *testing.T) {
+	now := time.Now()
+	s := &State{ProbeFailures: 2, LastFailure: now.Add(-failureWindow - time.Minute)}
+	recordFailure(s, &s.ProbeFailures, now)
+	if s.ProbeFailures != 1 {
+		t.Errorf("ProbeFailures = %d, want 1 (old failures expire)", s.ProbeFailures)
+	}
+}
+
+func TestReportLaunchFailureConfirmsWithProbe(t *testing.T) {
+	origProbe := probe
+	defer func() { probe = origProbe }()
+	launchErr := errors.New("session died during startup")
+
+	// API reachable: launch failures alone don't degrade the town.
+	townRoot := t.TempDir()
+	probe = func(context.Context) error { return nil }
+	for i := 0; i < LaunchFailureThreshold+1; i++ {
+		entered, err := ReportLaunchFailure(townRoot, launchErr)
+		if err != nil || entered {
+			t.Fatalf("ReportLaunchFailure = %v, %v; want false, nil", entered, err)
+		}
+	}
+
+	// API down: the threshold-th failure enters degraded mode.
+	townRoot = t.TempDir()
+	probe = func(context.Context) error { return errors.New("503") }
+	for i := 1; i < LaunchFailureThreshold; i++ {
+		if entered, _ := ReportLaunchFailure(townRoot, launchErr); entered {
+			t.Fatalf("entered after %d launch failures", i)
+		}
+	}
+	entered, err := ReportLaunchFailure(townRoot, launchErr)
+	if err != nil || !entered {
+		t.Fatalf("ReportLaunchFailure = %v, %v; want true, nil", entered, err)
+	}
+	state, _ := Load(townRoot)
+	if state.Source != SourceLaunch {
+		t.Errorf("Source = %q, want %q", state.Source, SourceLaunch)
+	}
+}
+
+func TestReportLaunchSuccessResets(t *testing.T) {
+	origProbe := probe
+	defer func() { probe = origProbe }()
+	probe = func(context.Context) error { return errors.New("down") }
+
+	townRoot := t.TempDir()
+	for i := 0; i < LaunchFailureThreshold-1; i++ {
+		_, _ = ReportLaunchFailure(townRoot, errors.New("died"))
+	}
+	if err := ReportLaunchSuccess(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	if entered, _ := ReportLaunchFailure(townRoot, errors.New("died")); entered {
+		t.Error("a successful launch should reset the failure count")
+	}
+}
+
+func TestProbe(t *testing.T) {
+	tests := []struct {
+		status  int
+		wantErr bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusUnauthorized, false},
+		{http.StatusNotFound, false},
+		{http.StatusServiceUnavailable, true},
+		{529, true},
+	}
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+		err := Probe(context.Background(), srv.Client(), srv.URL)
+		srv.Close()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("status %d: err = %v, wantErr %v", tt.status, err, tt.wantErr)
+		}
+	}
+}
+
+func TestProbeUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+	if err := Probe(context.Background(), nil, url); err == nil {
+		t.Error("Probe of a closed server should fail")
+	}
+}
+
+func TestProbeURL(t *testing.T) {
+	t.Setenv("ANTHROPIC_BASE_URL", "")
+	if got := ProbeURL(); got != DefaultProbeURL {
+		t.Errorf("ProbeURL() = %q, want %q", got, DefaultProbeURL)
+	}
+	t.Setenv("ANTHROPIC_BASE_URL", "https://gateway.example.com")
+	if got := ProbeURL(); got != "https://gateway.example.com" {
+		t.Errorf("ProbeURL() = %q, want ANTHROPIC_BASE_URL", got)
+	}
+}
@@ -0,0 +1,335 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// QuarantineLabel marks agent beads pulled out of circulation by the
+// beads-integrity fix. Quarantined beads lose the gt:agent label so agent
+// listings skip them, but the record is preserved for inspection:
+//
+//	bd list --label=gt:quarantined
+const QuarantineLabel = "gt:quarantined"
+
+// integrityAction is what the fix path does with a bad agent bead.
+type integrityAction int
+
+const (
+	integrityReport     integrityAction = iota // Report only, needs a human
+	integrityRepair                            // Rewrite the description with corrected fields
+	integrityQuarantine                        // Strip gt:agent and add QuarantineLabel
+)
+
+// integrityFinding is a single problem found by BeadsIntegrityCheck.
+type integrityFinding struct {
+	workDir string       // Beads working directory holding the record
+	issue   *beads.Issue // The offending agent bead
+	problem string       // Human-readable description
+	action  integrityAction
+	fields  *beads.AgentFields // Corrected fields (integrityRepair only)
+}
+
+// agentBeadSource lists agent beads from one beads database.
+// Allows mocking in tests.
+type agentBeadSource func(workDir string) ([]*beads.Issue, error)
+
+// BeadsIntegrityCheck validates agent bead records across the town and rig
+// beads databases:
+//   - descriptions missing a usable role_type (ParseAgentFields finds nothing)
+//   - circuit_state values outside closed/open/half_open
+//   - polecat beads whose rig no longer exists in rigs.json
+//   - agent bead IDs present in more than one database
+//
+// The fix repairs records whose correct values can be derived (role_type from
+// the bead ID, normalizable circuit states) and quarantines the rest.
+type BeadsIntegrityCheck struct {
+	FixableCheck
+	findings []integrityFinding // Cached for Fix
+
+	// Injected dependencies for testing
+	listAgents agentBeadSource
+	updater    func(workDir, id string, opts beads.UpdateOptions) error
+}
+
+// NewBeadsIntegrityCheck creates a new beads integrity check.
+func NewBeadsIntegrityCheck() *BeadsIntegrityCheck {
+	return &BeadsIntegrityCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "beads-integrity",
+				CheckDescription: "Validate agent bead fields, circuit states, rig references, and IDs",
+				CheckCategory:    CategoryRig,
+			},
+		},
+		listAgents: func(workDir string) ([]*beads.Issue, error) {
+			return beads.New(workDir).List(beads.ListOptions{
+				Status:   "open",
+				Label:    "gt:agent",
+				Priority: -1,
+			})
+		},
+		updater: func(workDir, id string, opts beads.UpdateOptions) error {
+			return beads.New(workDir).Update(id, opts)
+		},
+	}
+}
+
+// Run scans every beads database for malformed agent beads.
+func (c *BeadsIntegrityCheck) Run(ctx *CheckContext) *CheckResult {
+	c.findings = nil
+
+	workDirs := integrityWorkDirs(ctx.TownRoot)
+
+	// Known rigs (nil when rigs.json is unavailable: skip the rig-reference check)
+	var knownRigs map[string]bool
+	if cfg, err := loadRigsConfig(filepath.Join(ctx.TownRoot, "mayor", "rigs.json")); err == nil {
+		knownRigs = make(map[string]bool, len(cfg.Rigs))
+		for name := range cfg.Rigs {
+			knownRigs[name] = true
+		}
+	}
+
+	seen := make(map[string][]string) // bead ID -> work dirs containing it
+	issuesByDir := make(map[string]map[string]*beads.Issue)
+	var listErrors []string
+
+	for _, workDir := range workDirs {
+		issues, err := c.listAgents(workDir)
+		if err != nil {
+			listErrors = append(listErrors, fmt.Sprintf("%s: %v", relOrSelf(ctx.TownRoot, workDir), err))
+			continue
+		}
+		issuesByDir[workDir] = make(map[string]*beads.Issue, len(issues))
+		for _, issue := range issues {
+			seen[issue.ID] = append(seen[issue.ID], workDir)
+			issuesByDir[workDir][issue.ID] = issue
+			if f := checkAgentBeadRecord(issue, knownRigs); f != nil {
+				f.workDir = workDir
+				c.findings = append(c.findings, *f)
+			}
+		}
+	}
+
+	c.findings = append(c.findings, duplicateAgentFindings(ctx.TownRoot, seen, issuesByDir)...)
+
+	if len(c.findings) == 0 {
+		if len(listErrors) > 0 {
+			return &CheckResult{
+				Name:    c.Name(),
+				Status:  StatusWarning,
+				Message: fmt.Sprintf("Could not read %d beads database(s)", len(listErrors)),
+				Details: listErrors,
+			}
+		}
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("Agent beads valid across %d database(s)", len(workDirs)),
+		}
+	}
+
+	details := make([]string, 0, len(c.findings)+len(listErrors))
+	fixable := 0
+	for _, f := range c.findings {
+		details = append(details, fmt.Sprintf("%s: %s", f.issue.ID, f.problem))
+		if f.action != integrityReport {
+			fixable++
+		}
+	}
+	details = append(details, listErrors...)
+
+	hint := "Run 'gt doctor --fix' to repair or quarantine bad agent beads"
+	if fixable == 0 {
+		hint = "Resolve duplicate agent beads manually (bd show <id> in each database)"
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d agent bead integrity problem(s)", len(c.findings)),
+		Details: details,
+		FixHint: hint,
+	}
+}
+
+// Fix repairs or quarantines the records found by the last Run.
+func (c *BeadsIntegrityCheck) Fix(ctx *CheckContext) error {
+	var errs []string
+	for _, f := range c.findings {
+		var err error
+		switch f.action {
+		case integrityRepair:
+			description := beads.FormatAgentDescription(f.issue.Title, f.fields)
+			err = c.updater(f.workDir, f.issue.ID, beads.UpdateOptions{Description: &description})
+		case integrityQuarantine:
+			err = c.updater(f.workDir, f.issue.ID, beads.UpdateOptions{
+				AddLabels:    []string{QuarantineLabel},
+				RemoveLabels: []string{"gt:agent"},
+			})
+		default:
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", f.issue.ID, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("fixing agent beads: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// integrityWorkDirs returns the town beads location plus every routed rig.
+func integrityWorkDirs(townRoot string) []string {
+	dirs := []string{townRoot}
+	seen := map[string]bool{townRoot: true}
+
+	routes, err := beads.LoadRoutes(filepath.Join(townRoot, ".beads"))
+	if err != nil {
+		return dirs
+	}
+	for _, r := range routes {
+		dir := filepath.Join(townRoot, r.Path)
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// checkAgentBeadRecord validates one agent bead. Returns nil if it is healthy.
+func checkAgentBeadRecord(issue *beads.Issue, knownRigs map[string]bool) *integrityFinding {
+	fields := beads.ParseAgentFields(issue.Description)
+	idRig, idRole, _, idOK := beads.ParseAgentBeadID(issue.ID)
+
+	// Unparseable description: no role_type means ParseAgentFields recovered nothing.
+	if fields.RoleType == "" {
+		if idOK && isKnownAgentRole(idRole) {
+			fields.RoleType = idRole
+			if fields.Rig == "" {
+				fields.Rig = idRig
+			}
+			return &integrityFinding{
+				issue:   issue,
+				problem: fmt.Sprintf("missing role_type (repair from ID as %s)", idRole),
+				action:  integrityRepair,
+				fields:  fields,
+			}
+		}
+		return &integrityFinding{
+			issue:   issue,
+			problem: "description has no parseable agent fields",
+			action:  integrityQuarantine,
+		}
+	}
+	if !isKnownAgentRole(fields.RoleType) {
+		return &integrityFinding{
+			issue:   issue,
+			problem: fmt.Sprintf("unknown role_type %q", fields.RoleType),
+			action:  integrityQuarantine,
+		}
+	}
+
+	if !beads.IsValidCircuitState(fields.CircuitState) {
+		bad := fields.CircuitState
+		fields.CircuitState = normalizeCircuitState(bad)
+		return &integrityFinding{
+			issue:   issue,
+			problem: fmt.Sprintf("invalid circuit_state %q (repair to %s)", bad, fields.CircuitState),
+			action:  integrityRepair,
+			fields:  fields,
+		}
+	}
+
+	if fields.RoleType == "polecat" && knownRigs != nil && fields.Rig != "" && !knownRigs[fields.Rig] {
+		return &integrityFinding{
+			issue:   issue,
+			problem: fmt.Sprintf("polecat references removed rig %q", fields.Rig),
+			action:  integrityQuarantine,
+		}
+	}
+
+	return nil
+}
+
+// duplicateAgentFindings reports agent bead IDs found in more than one
+// database. The copy in the database that routing resolves the ID to is kept;
+// the others are quarantined. When routing can't decide, all copies are
+// reported for manual resolution.
+func duplicateAgentFindings(townRoot string, seen map[string][]string, issuesByDir map[string]map[string]*beads.Issue) []integrityFinding {
+	var ids []string
+	for id, dirs := range seen {
+		if len(dirs) > 1 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var findings []integrityFinding
+	for _, id := range ids {
+		dirs := seen[id]
+		expected := beads.GetRigPathForPrefix(townRoot, beads.ExtractPrefix(id))
+
+		owner := ""
+		for _, dir := range dirs {
+			if expected != "" && filepath.Clean(dir) == filepath.Clean(expected) {
+				owner = dir
+				break
+			}
+		}
+
+		for _, dir := range dirs {
+			if dir == owner {
+				continue
+			}
+			f := integrityFinding{
+				workDir: dir,
+				issue:   issuesByDir[dir][id],
+				problem: fmt.Sprintf("duplicate agent bead (also in %d other database(s))", len(dirs)-1),
+				action:  integrityReport,
+			}
+			if owner != "" {
+				f.problem = fmt.Sprintf("duplicate of %s copy (quarantine)", relOrSelf(townRoot, owner))
+				f.action = integrityQuarantine
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// isKnownAgentRole reports whether role is one of beads.ValidAgentRoles.
+func isKnownAgentRole(role string) bool {
+	for _, r := range beads.ValidAgentRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeCircuitState maps near-miss spellings ("half-open", "OPEN") onto a
+// valid state. Anything unrecognizable resets to closed so the breaker
+// re-evaluates from live failure counts.
+func normalizeCircuitState(s string) string {
+	n := strings.ToLower(strings.TrimSpace(s))
+	n = strings.NewReplacer("-", "_", " ", "_").Replace(n)
+	if beads.IsValidCircuitState(n) && n != "" {
+		return n
+	}
+	return beads.CircuitClosed
+}
+
+// relOrSelf returns path relative to root, or path itself if that fails.
+func relOrSelf(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil {
+		return rel
+	}
+	return path
+}
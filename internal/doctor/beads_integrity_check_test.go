@@ -0,0 +1,160 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// setupIntegrityTown creates a town with routes for hq- (town) and gt- (gastown)
+// and a rigs.json registering only gastown.
+func setupIntegrityTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	beadsDir := filepath.Join(townRoot, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	routes := `{"prefix":"hq-","path":"."}` + "\n" + `{"prefix":"gt-","path":"gastown/mayor/rig"}` + "\n"
+	if err := os.WriteFile(filepath.Join(beadsDir, "routes.jsonl"), []byte(routes), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rigs := `{"version":1,"rigs":{"gastown":{"git_url":"x"}}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte(rigs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot
+}
+
+func agentIssue(id string, fields *beads.AgentFields) *beads.Issue {
+	return &beads.Issue{
+		ID:          id,
+		Title:       id,
+		Description: beads.FormatAgentDescription(id, fields),
+		Labels:      []string{"gt:agent"},
+	}
+}
+
+type recordedUpdate struct {
+	workDir string
+	id      string
+	opts    beads.UpdateOptions
+}
+
+func newTestIntegrityCheck(byDir map[string][]*beads.Issue, updates *[]recordedUpdate) *BeadsIntegrityCheck {
+	check := NewBeadsIntegrityCheck()
+	check.listAgents = func(workDir string) ([]*beads.Issue, error) {
+		return byDir[workDir], nil
+	}
+	check.updater = func(workDir, id string, opts beads.UpdateOptions) error {
+		*updates = append(*updates, recordedUpdate{workDir: workDir, id: id, opts: opts})
+		return nil
+	}
+	return check
+}
+
+func TestBeadsIntegrityCheck_Healthy(t *testing.T) {
+	townRoot := setupIntegrityTown(t)
+	rigDir := filepath.Join(townRoot, "gastown/mayor/rig")
+	var updates []recordedUpdate
+	check := newTestIntegrityCheck(map[string][]*beads.Issue{
+		rigDir: {agentIssue("gt-gastown-polecat-nux", &beads.AgentFields{RoleType: "polecat", Rig: "gastown", CircuitState: beads.CircuitOpen})},
+	}, &updates)
+
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusOK {
+		t.Fatalf("expected OK, got %v: %s %v", result.Status, result.Message, result.Details)
+	}
+}
+
+func TestBeadsIntegrityCheck_FindsAndFixesProblems(t *testing.T) {
+	townRoot := setupIntegrityTown(t)
+	rigDir := filepath.Join(townRoot, "gastown/mayor/rig")
+
+	noFields := &beads.Issue{ID: "gt-gastown-witness", Title: "witness", Description: "just prose", Labels: []string{"gt:agent"}}
+	garbage := &beads.Issue{ID: "gt-zzz", Title: "zzz", Description: "nothing useful here", Labels: []string{"gt:agent"}}
+	badCircuit := agentIssue("gt-gastown-polecat-nux", &beads.AgentFields{RoleType: "polecat", Rig: "gastown", CircuitState: "Half-Open"})
+	orphanRig := agentIssue("gt-oldrig-polecat-ace", &beads.AgentFields{RoleType: "polecat", Rig: "oldrig"})
+	dupHome := agentIssue("gt-gastown-polecat-max", &beads.AgentFields{RoleType: "polecat", Rig: "gastown"})
+	dupStray := agentIssue("gt-gastown-polecat-max", &beads.AgentFields{RoleType: "polecat", Rig: "gastown"})
+
+	var updates []recordedUpdate
+	check := newTestIntegrityCheck(map[string][]*beads.Issue{
+		townRoot: {dupStray},
+		rigDir:   {noFields, garbage, badCircuit, orphanRig, dupHome},
+	}, &updates)
+
+	ctx := &CheckContext{TownRoot: townRoot}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("expected warning, got %v: %s", result.Status, result.Message)
+	}
+	if len(result.Details) != 5 {
+		t.Fatalf("expected 5 findings, got %d: %v", len(result.Details), result.Details)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+
+	got := make(map[string]recordedUpdate)
+	for _, u := range updates {
+		got[u.workDir+"|"+u.id] = u
+	}
+
+	// role_type repaired from the ID
+	if u, ok := got[rigDir+"|gt-gastown-witness"]; !ok || u.opts.Description == nil ||
+		beads.ParseAgentFields(*u.opts.Description).RoleType != "witness" {
+		t.Errorf("expected witness bead role_type repair, got %+v", u)
+	}
+	// circuit state normalized
+	if u, ok := got[rigDir+"|gt-gastown-polecat-nux"]; !ok || u.opts.Description == nil ||
+		beads.ParseAgentFields(*u.opts.Description).CircuitState != beads.CircuitHalfOpen {
+		t.Errorf("expected circuit_state repair to half_open, got %+v", u)
+	}
+	// unparseable and orphaned-rig beads quarantined
+	for _, key := range []string{rigDir + "|gt-zzz", rigDir + "|gt-oldrig-polecat-ace", townRoot + "|gt-gastown-polecat-max"} {
+		u, ok := got[key]
+		if !ok || len(u.opts.AddLabels) != 1 || u.opts.AddLabels[0] != QuarantineLabel {
+			t.Errorf("expected %s to be quarantined, got %+v", key, u)
+		}
+	}
+	// the routed copy of the duplicate is kept
+	if _, ok := got[rigDir+"|gt-gastown-polecat-max"]; ok {
+		t.Error("routed copy of duplicate bead should not be modified")
+	}
+}
+
+func TestBeadsIntegrityCheck_ListErrors(t *testing.T) {
+	townRoot := setupIntegrityTown(t)
+	check := NewBeadsIntegrityCheck()
+	check.listAgents = func(workDir string) ([]*beads.Issue, error) {
+		return nil, errors.New("bd unavailable")
+	}
+
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusWarning {
+		t.Fatalf("expected warning on list errors, got %v", result.Status)
+	}
+}
+
+func TestNormalizeCircuitState(t *testing.T) {
+	tests := map[string]string{
+		"OPEN":      beads.CircuitOpen,
+		"half-open": beads.CircuitHalfOpen,
+		"Half Open": beads.CircuitHalfOpen,
+		"tripped":   beads.CircuitClosed,
+		"":          beads.CircuitClosed,
+	}
+	for in, want := range tests {
+		if got := normalizeCircuitState(in); got != want {
+			t.Errorf("normalizeCircuitState(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
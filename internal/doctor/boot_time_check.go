@@ -0,0 +1,105 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/polecat"
+)
+
+// bootTimeRecentSpawns is how many of a rig's latest spawns are checked
+// against the boot budget.
+const bootTimeRecentSpawns = 10
+
+// BootTimeCheck reviews recorded polecat boot times per rig and reports:
+//   - recent spawns that exceeded the boot_budget, with their diagnosis
+//   - rigs whose recent median boot time regressed against their baseline
+type BootTimeCheck struct {
+	BaseCheck
+}
+
+// NewBootTimeCheck creates a new polecat boot time check.
+func NewBootTimeCheck() *BootTimeCheck {
+	return &BootTimeCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "boot-time",
+			CheckDescription: "Check polecat boot times against the budget and for regressions",
+			CheckCategory:    CategoryRig,
+		},
+	}
+}
+
+// Run reads each rig's boot log.
+func (c *BootTimeCheck) Run(ctx *CheckContext) *CheckResult {
+	budget := polecat.LoadBootBudget(ctx.TownRoot)
+
+	var rigNames []string
+	if ctx.RigName != "" {
+		rigNames = []string{ctx.RigName}
+	} else if cfg, err := loadRigsConfig(filepath.Join(ctx.TownRoot, "mayor", "rigs.json")); err == nil {
+		for name := range cfg.Rigs {
+			rigNames = append(rigNames, name)
+		}
+		sort.Strings(rigNames)
+	}
+
+	var details []string
+	spawns := 0
+	for _, rigName := range rigNames {
+		records, err := polecat.LoadBootRecords(filepath.Join(ctx.TownRoot, rigName))
+		if err != nil {
+			details = append(details, fmt.Sprintf("%s: %v", rigName, err))
+			continue
+		}
+		spawns += len(records)
+
+		if reg, ok := polecat.DetectBootRegression(records, budget.RegressionFactor); ok && reg.Regressed {
+			details = append(details, fmt.Sprintf("%s: boot time regressed, recent median %s vs baseline %s",
+				rigName, reg.Recent.Round(100*time.Millisecond), reg.Baseline.Round(100*time.Millisecond)))
+		}
+
+		recent := records
+		if len(recent) > bootTimeRecentSpawns {
+			recent = recent[len(recent)-bootTimeRecentSpawns:]
+		}
+		for _, rec := range recent {
+			causes := polecat.DiagnoseBoot(rec, budget)
+			if len(causes) == 0 {
+				continue
+			}
+			described := make([]string, len(causes))
+			for i, code := range causes {
+				described[i] = polecat.DescribeBootCause(code)
+			}
+			details = append(details, fmt.Sprintf("%s/%s: %s at %s: %s", rigName, rec.Polecat,
+				rec.Total().Round(100*time.Millisecond), rec.StartedAt.Format("2006-01-02 15:04"), strings.Join(described, "; ")))
+		}
+	}
+
+	if spawns == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No polecat boot times recorded yet",
+		}
+	}
+
+	if len(details) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Slow polecat boots (budget %s)", budget.Budget),
+			Details: details,
+			FixHint: "Address the diagnosed cause, or raise boot_budget.budget in settings/config.json",
+		}
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: fmt.Sprintf("%d recorded spawn(s), recent boots within %s budget", spawns, budget.Budget),
+	}
+}
@@ -0,0 +1,47 @@
+package doctor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/polecat"
+)
+
+func TestBootTimeCheck_NoRecords(t *testing.T) {
+	result := NewBootTimeCheck().Run(&CheckContext{TownRoot: setupMailTown(t)})
+	if result.Status != StatusOK {
+		t.Errorf("expected OK without boot records, got %v: %s", result.Status, result.Message)
+	}
+}
+
+func TestBootTimeCheck_OverBudgetAndRegression(t *testing.T) {
+	townRoot := setupMailTown(t)
+	rigPath := townRoot + "/gastown"
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		if err := polecat.AppendBootRecord(rigPath, polecat.BootRecord{Polecat: "Toast", StartedAt: started, ShellInitMs: 1000, AgentReadyMs: 14000, TotalMs: 15000}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		if err := polecat.AppendBootRecord(rigPath, polecat.BootRecord{Polecat: "Nux", StartedAt: started, ShellInitMs: 25000, AgentReadyMs: 15000, TotalMs: 40000}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// One spawn over the default 60s budget
+	if err := polecat.AppendBootRecord(rigPath, polecat.BootRecord{Polecat: "Nux", StartedAt: started, ShellInitMs: 30000, AgentReadyMs: 45000, TotalMs: 75000}); err != nil {
+		t.Fatal(err)
+	}
+
+	result := NewBootTimeCheck().Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusWarning {
+		t.Fatalf("expected warning, got %v: %s", result.Status, result.Message)
+	}
+	joined := strings.Join(result.Details, "\n")
+	for _, want := range []string{"gastown: boot time regressed", "gastown/Nux: 1m15s", "slow shell init"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("details missing %q:\n%s", want, joined)
+		}
+	}
+}
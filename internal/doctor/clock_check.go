@@ -0,0 +1,160 @@
+package doctor
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Clock skew thresholds. Heartbeat staleness is judged in minutes and worker
+// activity goes stale after 5m, so skew of that order makes live agents look
+// dead (or dead ones alive); smaller skew still distorts MR and convoy-age
+// scoring and pending-spawn pruning across machines.
+const (
+	clockSkewWarn  = 30 * time.Second
+	clockSkewError = 5 * time.Minute
+)
+
+// clockNTPServers are queried in order until one answers.
+var clockNTPServers = []string{"pool.ntp.org", "time.google.com", "time.cloudflare.com"}
+
+// clockNTPTimeout bounds each server query.
+const clockNTPTimeout = 2 * time.Second
+
+// ntpEpochOffset is the number of seconds between the NTP epoch (1900) and
+// the Unix epoch (1970).
+const ntpEpochOffset = 2208988800
+
+// ClockCheck compares the local clock against NTP. Agents coordinate through
+// heartbeat timestamps, pending-spawn ages, and MR/convoy-age scoring, all of
+// which assume clocks that agree.
+type ClockCheck struct {
+	BaseCheck
+
+	// Injected for testing.
+	queryOffset func(server string) (time.Duration, error)
+}
+
+// NewClockCheck creates a new clock skew check.
+func NewClockCheck() *ClockCheck {
+	return &ClockCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "clock",
+			CheckDescription: "Check local clock skew against NTP",
+			CheckCategory:    CategoryInfrastructure,
+		},
+		queryOffset: queryNTPOffset,
+	}
+}
+
+// Run measures the clock offset against the first reachable NTP server.
+func (c *ClockCheck) Run(ctx *CheckContext) *CheckResult {
+	var errs []string
+	for _, server := range clockNTPServers {
+		offset, err := c.queryOffset(server)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", server, err))
+			continue
+		}
+		return c.resultFor(server, offset)
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: "No NTP server reachable (skipped)",
+		Details: errs,
+	}
+}
+
+// resultFor grades a measured offset. A positive offset means the local
+// clock is behind.
+func (c *ClockCheck) resultFor(server string, offset time.Duration) *CheckResult {
+	skew := offset.Abs()
+	direction := "behind"
+	if offset < 0 {
+		direction = "ahead of"
+	}
+	rounded := skew.Round(time.Millisecond)
+
+	switch {
+	case skew >= clockSkewError:
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("Clock is %s %s %s", rounded, direction, server),
+			Details: []string{
+				"Heartbeat staleness checks will misjudge live and dead agents",
+				"Pending spawn pruning and MR/convoy-age scoring use skewed timestamps",
+			},
+			FixHint: "Enable time sync (e.g. 'sudo timedatectl set-ntp true', or start chronyd/ntpd)",
+		}
+	case skew >= clockSkewWarn:
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Clock is %s %s %s", rounded, direction, server),
+			Details: []string{"MR/convoy-age scoring and pending spawn pruning use skewed timestamps"},
+			FixHint: "Enable time sync (e.g. 'sudo timedatectl set-ntp true', or start chronyd/ntpd)",
+		}
+	default:
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("Clock within %s of %s", rounded, server),
+		}
+	}
+}
+
+// queryNTPOffset sends one SNTP request and returns the local clock offset.
+func queryNTPOffset(server string) (time.Duration, error) {
+	conn, err := net.DialTimeout("udp", net.JoinHostPort(server, "123"), clockNTPTimeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(clockNTPTimeout)); err != nil {
+		return 0, err
+	}
+
+	req := make([]byte, 48)
+	req[0] = 0x1B // LI=0, VN=3, Mode=3 (client)
+	sent := time.Now()
+	if _, err := conn.Write(req); err != nil {
+		return 0, err
+	}
+	resp := make([]byte, 48)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return 0, err
+	}
+	received := time.Now()
+	return parseNTPResponse(resp[:n], sent, received)
+}
+
+// parseNTPResponse computes the clock offset from an SNTP server reply and the
+// local send/receive times: ((T2-T1) + (T3-T4)) / 2.
+func parseNTPResponse(resp []byte, sent, received time.Time) (time.Duration, error) {
+	if len(resp) < 48 {
+		return 0, fmt.Errorf("short NTP response (%d bytes)", len(resp))
+	}
+	if mode := resp[0] & 0x07; mode != 4 {
+		return 0, fmt.Errorf("unexpected NTP mode %d", mode)
+	}
+	if resp[1] == 0 {
+		return 0, errors.New("NTP server sent kiss-of-death")
+	}
+	serverRecv := ntpTime(resp[32:40])
+	serverSend := ntpTime(resp[40:48])
+	return (serverRecv.Sub(sent) + serverSend.Sub(received)) / 2, nil
+}
+
+// ntpTime decodes a 64-bit NTP timestamp.
+func ntpTime(b []byte) time.Time {
+	secs := int64(binary.BigEndian.Uint32(b[0:4])) - ntpEpochOffset
+	frac := int64(binary.BigEndian.Uint32(b[4:8]))
+	nanos := (frac * 1e9) >> 32
+	return time.Unix(secs, nanos)
+}
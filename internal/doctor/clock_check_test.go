@@ -0,0 +1,82 @@
+package doctor
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// putNTPTime encodes a time as a 64-bit NTP timestamp.
+func putNTPTime(b []byte, t time.Time) {
+	binary.BigEndian.PutUint32(b[0:4], uint32(t.Unix()+ntpEpochOffset))
+	binary.BigEndian.PutUint32(b[4:8], uint32(math.Round(float64(t.Nanosecond())*(1<<32)/1e9)))
+}
+
+func TestParseNTPResponse(t *testing.T) {
+	sent := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	received := sent.Add(100 * time.Millisecond)
+	skew := 42 * time.Second // server is ahead, so the local clock is behind
+
+	resp := make([]byte, 48)
+	resp[0] = 0x24 // LI=0, VN=4, Mode=4 (server)
+	resp[1] = 2    // stratum
+	putNTPTime(resp[32:40], sent.Add(skew+50*time.Millisecond))
+	putNTPTime(resp[40:48], sent.Add(skew+50*time.Millisecond))
+
+	offset, err := parseNTPResponse(resp, sent, received)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := (offset - skew).Abs(); d > time.Millisecond {
+		t.Errorf("offset = %v, want %v", offset, skew)
+	}
+
+	resp[1] = 0
+	if _, err := parseNTPResponse(resp, sent, received); err == nil {
+		t.Error("expected kiss-of-death error for stratum 0")
+	}
+	if _, err := parseNTPResponse(resp[:20], sent, received); err == nil {
+		t.Error("expected error for short response")
+	}
+}
+
+func TestClockCheck_Grades(t *testing.T) {
+	tests := []struct {
+		offset time.Duration
+		want   CheckStatus
+	}{
+		{200 * time.Millisecond, StatusOK},
+		{-45 * time.Second, StatusWarning},
+		{10 * time.Minute, StatusError},
+	}
+	for _, tt := range tests {
+		check := NewClockCheck()
+		check.queryOffset = func(string) (time.Duration, error) { return tt.offset, nil }
+		if got := check.Run(&CheckContext{}); got.Status != tt.want {
+			t.Errorf("offset %v: status = %v (%s), want %v", tt.offset, got.Status, got.Message, tt.want)
+		}
+	}
+}
+
+func TestClockCheck_FallsBackAndSkips(t *testing.T) {
+	var queried []string
+	check := NewClockCheck()
+	check.queryOffset = func(server string) (time.Duration, error) {
+		queried = append(queried, server)
+		if len(queried) < 2 {
+			return 0, errors.New("timeout")
+		}
+		return time.Second, nil
+	}
+	if got := check.Run(&CheckContext{}); got.Status != StatusOK || len(queried) != 2 {
+		t.Errorf("got %v after %v, want OK from second server", got.Status, queried)
+	}
+
+	check.queryOffset = func(string) (time.Duration, error) { return 0, errors.New("unreachable") }
+	got := check.Run(&CheckContext{})
+	if got.Status != StatusOK || len(got.Details) != len(clockNTPServers) {
+		t.Errorf("got %v %v, want skipped with one detail per server", got.Status, got.Details)
+	}
+}
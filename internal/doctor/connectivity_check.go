@@ -0,0 +1,337 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Connectivity thresholds. A probe slower than connectivitySlow still works
+// but makes every agent turn and git fetch sluggish.
+const (
+	connectivityTimeout = 10 * time.Second
+	connectivitySlow    = 2 * time.Second
+)
+
+// anthropicAPIURL is probed unless ANTHROPIC_BASE_URL overrides it.
+const anthropicAPIURL = "https://api.anthropic.com"
+
+// ConnectivityCheck verifies the machine can reach the Anthropic API and
+// every rig's git remote, that credentials are present and not expired, and
+// reports each endpoint's latency. A town with broken auth otherwise spawns
+// a wave of polecats that all fail on their first request.
+type ConnectivityCheck struct {
+	BaseCheck
+
+	// Injected for testing.
+	getenv      func(string) string
+	probeAPI    func(baseURL, apiKey string) (status int, latency time.Duration, err error)
+	probeRemote func(remote string) (latency time.Duration, err error)
+	now         func() time.Time
+}
+
+// NewConnectivityCheck creates a new API and git remote connectivity check.
+func NewConnectivityCheck() *ConnectivityCheck {
+	return &ConnectivityCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "connectivity",
+			CheckDescription: "Check Anthropic API and git remote reachability, auth, and latency",
+			CheckCategory:    CategoryInfrastructure,
+		},
+		getenv:      os.Getenv,
+		probeAPI:    probeAnthropicAPI,
+		probeRemote: probeGitRemote,
+		now:         time.Now,
+	}
+}
+
+// Run probes the API and each distinct rig remote.
+func (c *ConnectivityCheck) Run(ctx *CheckContext) *CheckResult {
+	var details, failures, slow []string
+
+	apiDetail, apiErr, apiSlow := c.checkAPI(ctx.TownRoot)
+	details = append(details, apiDetail)
+	if apiErr != "" {
+		failures = append(failures, apiErr)
+	} else if apiSlow {
+		slow = append(slow, "Anthropic API")
+	}
+
+	remotes := rigRemotes(ctx.TownRoot, ctx.RigName)
+	for _, r := range remotes {
+		latency, err := c.probeRemote(r.url)
+		name := fmt.Sprintf("%s (%s)", redactRemote(r.url), strings.Join(r.rigs, ", "))
+		if err != nil {
+			details = append(details, fmt.Sprintf("✗ %s: %v", name, err))
+			failures = append(failures, redactRemote(r.url))
+			continue
+		}
+		details = append(details, fmt.Sprintf("✓ %s: %s", name, latency.Round(time.Millisecond)))
+		if latency >= connectivitySlow {
+			slow = append(slow, redactRemote(r.url))
+		}
+	}
+
+	switch {
+	case len(failures) > 0:
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("%d endpoint(s) unreachable or rejecting credentials: %s", len(failures), strings.Join(failures, ", ")),
+			Details: details,
+			FixHint: "Fix network access or credentials before spawning polecats ('claude /login', ANTHROPIC_API_KEY, git credential helper or SSH key)",
+		}
+	case len(slow) > 0:
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Slow to respond (>%s): %s", connectivitySlow, strings.Join(slow, ", ")),
+			Details: details,
+		}
+	default:
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("Anthropic API and %d git remote(s) reachable", len(remotes)),
+			Details: details,
+		}
+	}
+}
+
+// checkAPI verifies Anthropic credentials and reachability. It returns the
+// detail line, a failure label (empty when fine), and whether it was slow.
+func (c *ConnectivityCheck) checkAPI(townRoot string) (detail, failure string, slow bool) {
+	baseURL := anthropicAPIURL
+	if v := c.getenv("ANTHROPIC_BASE_URL"); v != "" {
+		baseURL = strings.TrimRight(v, "/")
+	}
+	apiKey := c.getenv("ANTHROPIC_API_KEY")
+
+	// Without an API key, look for a Claude login and check its expiry.
+	credNote := "API key"
+	if apiKey == "" {
+		note, err := c.checkOAuthCredentials(townRoot)
+		if err != nil {
+			return fmt.Sprintf("✗ Anthropic API: %v", err), "Anthropic API credentials", false
+		}
+		credNote = note
+	}
+
+	status, latency, err := c.probeAPI(baseURL, apiKey)
+	if err != nil {
+		return fmt.Sprintf("✗ Anthropic API (%s): %v", baseURL, err), "Anthropic API", false
+	}
+	rounded := latency.Round(time.Millisecond)
+	if apiKey != "" && (status == http.StatusUnauthorized || status == http.StatusForbidden) {
+		return fmt.Sprintf("✗ Anthropic API: ANTHROPIC_API_KEY rejected (HTTP %d, %s)", status, rounded), "Anthropic API key", false
+	}
+	return fmt.Sprintf("✓ Anthropic API (%s, %s): %s", baseURL, credNote, rounded), "", latency >= connectivitySlow
+}
+
+// claudeOAuth is the subset of Claude's .credentials.json we inspect.
+type claudeOAuth struct {
+	ClaudeAiOauth *struct {
+		AccessToken  string `json:"accessToken"`
+		RefreshToken string `json:"refreshToken"`
+		ExpiresAt    int64  `json:"expiresAt"` // Unix milliseconds
+	} `json:"claudeAiOauth"`
+}
+
+// checkOAuthCredentials finds the Claude login agents will use (the town's
+// default account, CLAUDE_CONFIG_DIR, or ~/.claude) and checks it has not
+// expired beyond refresh. It returns a short note describing the login.
+func (c *ConnectivityCheck) checkOAuthCredentials(townRoot string) (string, error) {
+	if c.getenv("CLAUDE_CODE_OAUTH_TOKEN") != "" {
+		return "CLAUDE_CODE_OAUTH_TOKEN", nil
+	}
+
+	configDir := c.getenv("CLAUDE_CONFIG_DIR")
+	if dir, _, _ := config.ResolveAccountConfigDir(constants.MayorAccountsPath(townRoot), ""); dir != "" {
+		configDir = dir
+	}
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("no ANTHROPIC_API_KEY and cannot locate home directory: %w", err)
+		}
+		configDir = filepath.Join(home, ".claude")
+	}
+
+	path := filepath.Join(configDir, ".credentials.json")
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the Claude config dir
+	if errors.Is(err, os.ErrNotExist) {
+		if runtime.GOOS == "darwin" {
+			// Claude keeps macOS logins in the Keychain, which we can't read.
+			return "Keychain login (expiry not checked)", nil
+		}
+		return "", fmt.Errorf("no ANTHROPIC_API_KEY and no Claude login in %s", configDir)
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var creds claudeOAuth
+	if err := json.Unmarshal(data, &creds); err != nil || creds.ClaudeAiOauth == nil || creds.ClaudeAiOauth.AccessToken == "" {
+		return "", fmt.Errorf("no Claude login token in %s", path)
+	}
+	oauth := creds.ClaudeAiOauth
+	if oauth.ExpiresAt == 0 {
+		return "Claude login", nil
+	}
+	expires := time.UnixMilli(oauth.ExpiresAt)
+	if c.now().Before(expires) {
+		return fmt.Sprintf("Claude login, expires %s", expires.Local().Format("2006-01-02 15:04")), nil
+	}
+	if oauth.RefreshToken != "" {
+		return "Claude login, refreshes on next use", nil
+	}
+	return "", fmt.Errorf("the Claude login in %s expired %s and cannot be refreshed", configDir, expires.Local().Format("2006-01-02 15:04"))
+}
+
+// probeAnthropicAPI lists models, which is cheap and needs auth. Without a
+// key any HTTP response proves the endpoint is reachable.
+func probeAnthropicAPI(baseURL, apiKey string) (int, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), connectivityTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/v1/models", nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("anthropic-version", "2023-06-01")
+	if apiKey != "" {
+		req.Header.Set("x-api-key", apiKey)
+	}
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode, time.Since(start), nil
+}
+
+// probeGitRemote runs 'git ls-remote' with prompts disabled, so it fails on
+// missing credentials instead of hanging on a password prompt.
+func probeGitRemote(remote string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), connectivityTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--quiet", remote, "HEAD")
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if os.Getenv("GIT_SSH_COMMAND") == "" {
+		cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes -o ConnectTimeout=5")
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	start := time.Now()
+	err := cmd.Run()
+	latency := time.Since(start)
+	if ctx.Err() != nil {
+		return 0, fmt.Errorf("timed out after %s", connectivityTimeout)
+	}
+	if err != nil {
+		return 0, classifyRemoteError(stderr.String(), err)
+	}
+	return latency, nil
+}
+
+// classifyRemoteError turns git's stderr into a one-line reason, calling
+// out authentication failures so they aren't mistaken for outages.
+func classifyRemoteError(stderr string, err error) error {
+	lower := strings.ToLower(stderr)
+	for _, marker := range []string{"authentication failed", "permission denied", "could not read username", "terminal prompts disabled", "403", "401"} {
+		if strings.Contains(lower, marker) {
+			return fmt.Errorf("authentication failed: %s", firstLine(stderr))
+		}
+	}
+	if line := firstLine(stderr); line != "" {
+		return errors.New(line)
+	}
+	return err
+}
+
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// rigRemote is one distinct network remote and the rigs that use it.
+type rigRemote struct {
+	url  string
+	rigs []string
+}
+
+// rigRemotes collects the fetch and push URLs from mayor/rigs.json,
+// skipping local paths. Only rigName is included when set.
+func rigRemotes(townRoot, rigName string) []rigRemote {
+	rigsCfg, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	byURL := make(map[string]*rigRemote)
+	for name, entry := range rigsCfg.Rigs {
+		if rigName != "" && name != rigName {
+			continue
+		}
+		for _, u := range []string{entry.GitURL, entry.PushURL} {
+			if u == "" || isLocalRemote(u) {
+				continue
+			}
+			if byURL[u] == nil {
+				byURL[u] = &rigRemote{url: u}
+			}
+			byURL[u].rigs = append(byURL[u].rigs, name)
+		}
+	}
+
+	remotes := make([]rigRemote, 0, len(byURL))
+	for _, r := range byURL {
+		sort.Strings(r.rigs)
+		remotes = append(remotes, *r)
+	}
+	sort.Slice(remotes, func(i, j int) bool { return remotes[i].url < remotes[j].url })
+	return remotes
+}
+
+// isLocalRemote reports whether a git URL points at the local filesystem.
+func isLocalRemote(u string) bool {
+	if strings.HasPrefix(u, "file://") || filepath.IsAbs(u) || strings.HasPrefix(u, ".") || strings.HasPrefix(u, "~") {
+		return true
+	}
+	// scp-style "host:path" and URLs with a scheme are remote.
+	return !strings.Contains(u, ":")
+}
+
+// redactRemote hides any password or token embedded in an https URL.
+func redactRemote(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.User == nil || parsed.Scheme == "" {
+		return u
+	}
+	if _, hasPassword := parsed.User.Password(); hasPassword {
+		return parsed.Redacted()
+	}
+	if parsed.Scheme == "http" || parsed.Scheme == "https" {
+		// A bare userinfo on https is usually a token.
+		parsed.User = url.User("xxxxx")
+		return parsed.String()
+	}
+	return u
+}
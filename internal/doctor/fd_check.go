@@ -0,0 +1,346 @@
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/procfs"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// FD leak thresholds. A process is a likely leaker when its descriptor count
+// rose on each of the last fdLeakSamples runs by at least fdLeakMinGrowth in
+// total; healthy agents plateau after startup.
+const (
+	fdDefaultPerProcess = limitsNOFILEPerAgent
+	fdLeakSamples       = 3
+	fdLeakMinGrowth     = 50
+	fdHistoryMax        = 10
+	fdTopProcesses      = 5
+)
+
+// fdHistoryFile lives under <town>/.runtime/ and carries samples between runs.
+const fdHistoryFile = "fd-history.json"
+
+// fdCounts is one process's open descriptors by kind.
+type fdCounts struct {
+	Total     int `json:"total"`
+	Sockets   int `json:"sockets"`
+	Inotify   int `json:"inotify"`
+	Pipes     int `json:"pipes"`
+	TownFiles int `json:"town_files"` // regular files under the town root
+	Other     int `json:"other"`
+}
+
+// fdClass is one kind of descriptor and its count.
+type fdClass struct {
+	name string
+	n    int
+}
+
+// classes returns the per-kind counts with their labels, in report order.
+func (c fdCounts) classes() []fdClass {
+	return []fdClass{{"sockets", c.Sockets}, {"inotify", c.Inotify}, {"pipes", c.Pipes}, {"town files", c.TownFiles}, {"other", c.Other}}
+}
+
+// fdSample is one run's counts for a process.
+type fdSample struct {
+	At     time.Time `json:"at"`
+	Counts fdCounts  `json:"counts"`
+}
+
+// fdProcessHistory is the recent samples for one process. Processes are
+// keyed by pid and start time so a reused pid starts a fresh history.
+type fdProcessHistory struct {
+	PID     int        `json:"pid"`
+	Name    string     `json:"name"`
+	Samples []fdSample `json:"samples"`
+}
+
+// FDCheck attributes open file descriptors to the town's processes
+// (agents, gt, bd, dolt, tmux) and classifies them as sockets, inotify
+// instances, pipes, and files under the town root. Each run is saved to
+// <town>/.runtime/fd-history.json so the next run can report deltas and flag
+// processes whose counts keep growing, naming the likely leaker and what
+// kind of descriptor it is accumulating. Linux only (reads /proc).
+type FDCheck struct {
+	BaseCheck
+
+	// Injected for testing.
+	goos     string
+	procRoot string
+	now      func() time.Time
+}
+
+// NewFDCheck creates a new per-process FD attribution check.
+func NewFDCheck() *FDCheck {
+	return &FDCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "fd-leaks",
+			CheckDescription: "Attribute open descriptors to town processes and flag steady growth",
+			CheckCategory:    CategoryInfrastructure,
+		},
+		goos:     runtime.GOOS,
+		procRoot: procRoot,
+		now:      time.Now,
+	}
+}
+
+// fdFinding is one process's current state compared with its history.
+type fdFinding struct {
+	history *fdProcessHistory
+	delta   int // change in total since the previous run
+	leaking bool
+	over    bool
+}
+
+// Run counts descriptors, updates the history, and grades the result.
+func (c *FDCheck) Run(ctx *CheckContext) *CheckResult {
+	if c.goos != "linux" {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("FD attribution not supported on %s (skipped)", c.goos),
+		}
+	}
+	procs, err := scanTownProcessesIn(c.procRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not scan town processes",
+			Details: []string{err.Error()},
+		}
+	}
+
+	limit := fdDefaultPerProcess
+	if l := ctx.TownSettings().Limits; l != nil && l.FDsPerProcess > 0 {
+		limit = l.FDsPerProcess
+	}
+
+	historyPath := filepath.Join(ctx.TownRoot, constants.DirRuntime, fdHistoryFile)
+	prev := loadFDHistory(historyPath)
+	next := make(map[string]*fdProcessHistory)
+	now := c.now()
+
+	var findings []fdFinding
+	var town fdCounts
+	for _, p := range procs {
+		counts, err := countProcessFDs(c.procRoot, p.PID, ctx.TownRoot)
+		if err != nil {
+			continue // exited or not ours to inspect
+		}
+		town = addFDCounts(town, counts)
+
+		key := fmt.Sprintf("%d:%s", p.PID, procfs.StartTime(c.procRoot, p.PID))
+		h := prev[key]
+		if h == nil {
+			h = &fdProcessHistory{PID: p.PID, Name: p.Name}
+		}
+		f := fdFinding{history: h}
+		if n := len(h.Samples); n > 0 {
+			f.delta = counts.Total - h.Samples[n-1].Counts.Total
+		}
+		h.Samples = append(h.Samples, fdSample{At: now, Counts: counts})
+		if len(h.Samples) > fdHistoryMax {
+			h.Samples = h.Samples[len(h.Samples)-fdHistoryMax:]
+		}
+		f.leaking = growingMonotonically(h.Samples)
+		f.over = counts.Total > limit
+		next[key] = h
+		findings = append(findings, f)
+	}
+	_ = saveFDHistory(historyPath, next)
+
+	sort.Slice(findings, func(i, j int) bool {
+		return latestFDs(findings[i]).Total > latestFDs(findings[j]).Total
+	})
+
+	var leakers, over []fdFinding
+	for _, f := range findings {
+		if f.leaking {
+			leakers = append(leakers, f)
+		} else if f.over {
+			over = append(over, f)
+		}
+	}
+
+	summary := fmt.Sprintf("%d descriptor(s) across %d town process(es): %s",
+		town.Total, len(findings), formatFDClasses(town))
+	if len(leakers) == 0 && len(over) == 0 {
+		var details []string
+		for i, f := range findings {
+			if i == fdTopProcesses {
+				break
+			}
+			details = append(details, formatFDFinding(f))
+		}
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: summary,
+			Details: details,
+		}
+	}
+
+	var details []string
+	if len(leakers) > 0 {
+		details = append(details, "Likely leakers (descriptor count grew on each of the last runs):")
+		for _, f := range leakers {
+			details = append(details, "  "+formatFDLeak(f))
+		}
+	}
+	if len(over) > 0 {
+		details = append(details, fmt.Sprintf("Over %d descriptors:", limit))
+		for _, f := range over {
+			details = append(details, "  "+formatFDFinding(f))
+		}
+	}
+
+	message := fmt.Sprintf("%d process(es) over %d descriptors; %s", len(over), limit, summary)
+	if len(leakers) > 0 {
+		top := leakers[0].history
+		message = fmt.Sprintf("Likely FD leak in %s (pid %d); %s", top.Name, top.PID, summary)
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: message,
+		Details: details,
+		FixHint: "Restart the named agent (gt polecat nuke / gt crew restart) or process; re-run 'gt doctor' to confirm the count resets",
+	}
+}
+
+// countProcessFDs reads /proc/<pid>/fd and classifies each descriptor by
+// its link target.
+func countProcessFDs(root string, pid int, townRoot string) (fdCounts, error) {
+	dir := filepath.Join(root, strconv.Itoa(pid), "fd")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fdCounts{}, err
+	}
+	var c fdCounts
+	for _, e := range entries {
+		target, err := os.Readlink(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue // closed mid-scan
+		}
+		c.Total++
+		switch {
+		case strings.HasPrefix(target, "socket:"):
+			c.Sockets++
+		case target == "anon_inode:inotify":
+			c.Inotify++
+		case strings.HasPrefix(target, "pipe:"):
+			c.Pipes++
+		case townRoot != "" && (target == townRoot || strings.HasPrefix(target, townRoot+"/")):
+			c.TownFiles++
+		default:
+			c.Other++
+		}
+	}
+	return c, nil
+}
+
+// growingMonotonically reports whether the total rose on each of the last
+// fdLeakSamples samples by at least fdLeakMinGrowth overall.
+func growingMonotonically(samples []fdSample) bool {
+	if len(samples) < fdLeakSamples {
+		return false
+	}
+	window := samples[len(samples)-fdLeakSamples:]
+	for i := 1; i < len(window); i++ {
+		if window[i].Counts.Total <= window[i-1].Counts.Total {
+			return false
+		}
+	}
+	return window[len(window)-1].Counts.Total-window[0].Counts.Total >= fdLeakMinGrowth
+}
+
+func latestFDs(f fdFinding) fdCounts {
+	return f.history.Samples[len(f.history.Samples)-1].Counts
+}
+
+func addFDCounts(a, b fdCounts) fdCounts {
+	return fdCounts{
+		Total:     a.Total + b.Total,
+		Sockets:   a.Sockets + b.Sockets,
+		Inotify:   a.Inotify + b.Inotify,
+		Pipes:     a.Pipes + b.Pipes,
+		TownFiles: a.TownFiles + b.TownFiles,
+		Other:     a.Other + b.Other,
+	}
+}
+
+// formatFDClasses renders "sockets 12, inotify 3, ..." skipping zero kinds.
+func formatFDClasses(c fdCounts) string {
+	var parts []string
+	for _, cl := range c.classes() {
+		if cl.n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", cl.name, cl.n))
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatFDFinding(f fdFinding) string {
+	c := latestFDs(f)
+	line := fmt.Sprintf("%s (pid %d): %d fds (%s)", f.history.Name, f.history.PID, c.Total, formatFDClasses(c))
+	if len(f.history.Samples) > 1 {
+		line += fmt.Sprintf(", %+d since last run", f.delta)
+	}
+	return line
+}
+
+// formatFDLeak names the leaker, its trend, and the kind that grew most.
+func formatFDLeak(f fdFinding) string {
+	window := f.history.Samples[len(f.history.Samples)-fdLeakSamples:]
+	var trend []string
+	for _, s := range window {
+		trend = append(trend, strconv.Itoa(s.Counts.Total))
+	}
+	first, last := window[0].Counts.classes(), window[len(window)-1].Counts.classes()
+	grew, growth := "", 0
+	for i := range last {
+		if d := last[i].n - first[i].n; d > growth {
+			grew, growth = last[i].name, d
+		}
+	}
+	line := fmt.Sprintf("%s (pid %d): %s fds over %d runs", f.history.Name, f.history.PID, strings.Join(trend, " → "), len(window))
+	if grew != "" {
+		line += fmt.Sprintf(", mostly %s (+%d)", grew, growth)
+	}
+	return line
+}
+
+// loadFDHistory reads the previous run's samples; a missing or corrupt
+// file starts fresh.
+func loadFDHistory(path string) map[string]*fdProcessHistory {
+	history := make(map[string]*fdProcessHistory)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is under the town runtime dir
+	if err != nil {
+		return history
+	}
+	_ = json.Unmarshal(data, &history)
+	return history
+}
+
+// saveFDHistory writes the samples for processes seen this run; exited
+// processes drop out.
+func saveFDHistory(path string, history map[string]*fdProcessHistory) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, history)
+}
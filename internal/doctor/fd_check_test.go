@@ -0,0 +1,159 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/procfs"
+)
+
+// fakeFDProc writes a /proc entry for pid with the given descriptor link
+// targets (dangling symlinks are fine; only the target text is read).
+func fakeFDProc(t *testing.T, root string, pid int, comm string, targets []string) {
+	t.Helper()
+	dir := filepath.Join(root, fmt.Sprint(pid))
+	fdDir := filepath.Join(dir, "fd")
+	if err := os.RemoveAll(fdDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(fdDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"comm":   comm + "\n",
+		"status": "Name:\t" + comm + "\nVmRSS:\t1000 kB\n",
+		"stat":   fmt.Sprintf("%d (%s) S 1 1 1 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 4242 0 0", pid, comm),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, target := range targets {
+		if err := os.Symlink(target, filepath.Join(fdDir, fmt.Sprint(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func repeatFD(target string, n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("%s%d]", target, i)
+	}
+	return out
+}
+
+func TestCountProcessFDs(t *testing.T) {
+	root := t.TempDir()
+	fakeFDProc(t, root, 100, "claude", []string{
+		"socket:[1]", "socket:[2]", "anon_inode:inotify", "pipe:[3]",
+		"/home/u/gt/gastown/polecats/toast/main.go", "/dev/null",
+	})
+	c, err := countProcessFDs(root, 100, "/home/u/gt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fdCounts{Total: 6, Sockets: 2, Inotify: 1, Pipes: 1, TownFiles: 1, Other: 1}
+	if c != want {
+		t.Errorf("counts = %+v, want %+v", c, want)
+	}
+	if got := procfs.StartTime(root, 100); got != "4242" {
+		t.Errorf("start time = %q, want 4242", got)
+	}
+}
+
+func newTestFDCheck(root string) (*FDCheck, *time.Time) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	c := NewFDCheck()
+	c.goos = "linux"
+	c.procRoot = root
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestFDCheck_FlagsMonotonicGrowth(t *testing.T) {
+	root, townRoot := t.TempDir(), t.TempDir()
+	c, now := newTestFDCheck(root)
+	ctx := &CheckContext{TownRoot: townRoot}
+
+	for i, sockets := range []int{10, 60, 140} {
+		fakeFDProc(t, root, 100, "claude", append(repeatFD("socket:[", sockets), "pipe:[1]"))
+		fakeFDProc(t, root, 200, "dolt", []string{"pipe:[9]", "/dev/null"}) // steady
+		*now = now.Add(time.Hour)
+		result := c.Run(ctx)
+		if i < 2 {
+			if result.Status != StatusOK {
+				t.Fatalf("run %d: status = %v (%s)", i, result.Status, result.Message)
+			}
+			continue
+		}
+		if result.Status != StatusWarning || !strings.Contains(result.Message, "Likely FD leak in claude (pid 100)") {
+			t.Fatalf("run %d: %v %q", i, result.Status, result.Message)
+		}
+		joined := strings.Join(result.Details, "\n")
+		if !strings.Contains(joined, "11 → 61 → 141 fds over 3 runs, mostly sockets (+130)") {
+			t.Errorf("details should name the leaking kind:\n%s", joined)
+		}
+		if strings.Contains(joined, "dolt") {
+			t.Errorf("steady process should not be flagged:\n%s", joined)
+		}
+	}
+}
+
+func TestFDCheck_DeltaAndPlateau(t *testing.T) {
+	root, townRoot := t.TempDir(), t.TempDir()
+	c, _ := newTestFDCheck(root)
+	ctx := &CheckContext{TownRoot: townRoot}
+
+	for _, n := range []int{10, 80, 80} { // grows then plateaus
+		fakeFDProc(t, root, 100, "claude", repeatFD("socket:[", n))
+		result := c.Run(ctx)
+		if result.Status != StatusOK {
+			t.Fatalf("status = %v (%s)", result.Status, result.Message)
+		}
+		if n == 80 && !strings.Contains(strings.Join(result.Details, "\n"), "since last run") {
+			t.Errorf("details should show delta: %v", result.Details)
+		}
+	}
+}
+
+func TestFDCheck_OverLimit(t *testing.T) {
+	root, townRoot := t.TempDir(), t.TempDir()
+	settings := config.NewTownSettings()
+	settings.Limits = &config.LimitsConfig{FDsPerProcess: 5}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	c, _ := newTestFDCheck(root)
+	fakeFDProc(t, root, 100, "bd", repeatFD("pipe:[", 6))
+
+	result := c.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusWarning || !strings.Contains(result.Message, "1 process(es) over 5 descriptors") {
+		t.Errorf("got %v %q", result.Status, result.Message)
+	}
+}
+
+func TestGrowingMonotonically(t *testing.T) {
+	samples := []fdSample{{Counts: fdCounts{Total: 10}}, {Counts: fdCounts{Total: 100}}}
+	if growingMonotonically(samples) {
+		t.Error("two samples are not enough to call a leak")
+	}
+	samples = append(samples, fdSample{Counts: fdCounts{Total: 100}})
+	if growingMonotonically(samples) {
+		t.Error("a flat sample breaks the trend")
+	}
+}
+
+func TestFDCheck_SkipsOffLinux(t *testing.T) {
+	c := NewFDCheck()
+	c.goos = "darwin"
+	if result := c.Run(&CheckContext{TownRoot: t.TempDir()}); result.Status != StatusOK {
+		t.Errorf("status = %v", result.Status)
+	}
+}
@@ -0,0 +1,239 @@
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/ui"
+)
+
+// diffContext is how many unchanged lines surround each diff hunk.
+const diffContext = 3
+
+// FileChange is one file a fix would create or rewrite.
+type FileChange struct {
+	Path   string // Absolute path of the file
+	Before string // Current contents ("" when the file doesn't exist)
+	After  string // Contents after the fix
+	Exists bool   // Whether the file exists now
+	Note   string // How the change is applied, when not by doctor itself
+}
+
+// Changed reports whether the fix would alter the file.
+func (fc FileChange) Changed() bool {
+	return !fc.Exists || fc.Before != fc.After
+}
+
+// Diff renders the change as a unified diff.
+func (fc FileChange) Diff() string {
+	from := "a" + fc.Path
+	if !fc.Exists {
+		from = "/dev/null"
+	}
+	return unifiedDiff(from, "b"+fc.Path, fc.Before, fc.After)
+}
+
+// FixPlanner is implemented by fixable checks that can describe their fix
+// as file changes without applying it, for 'gt doctor --fix --diff'.
+type FixPlanner interface {
+	// PlanFix returns the files the fix would write. Must not modify
+	// anything. Should only be called after Run reported a problem.
+	PlanFix(ctx *CheckContext) ([]FileChange, error)
+}
+
+// plannedFile reads path's current contents into a FileChange.
+func plannedFile(path, after, note string) FileChange {
+	fc := FileChange{Path: path, After: after, Note: note}
+	if data, err := os.ReadFile(path); err == nil { //nolint:gosec // G304: paths are fixed system config locations
+		fc.Before = string(data)
+		fc.Exists = true
+	}
+	return fc
+}
+
+// PlanFixesStreaming runs all checks like RunStreaming, then prints the file
+// changes each failing fixable check would make instead of fixing anything.
+// Fixable checks that can't preview their changes are listed by name.
+func (d *Doctor) PlanFixesStreaming(ctx *CheckContext, w io.Writer, slowThreshold time.Duration) *Report {
+	report := d.RunStreaming(ctx, w, slowThreshold)
+
+	var opaque []string
+	planned := 0
+	for i, check := range d.checks {
+		result := report.Checks[i]
+		if result.Status == StatusOK || !check.CanFix() {
+			continue
+		}
+		planner, ok := check.(FixPlanner)
+		if !ok {
+			opaque = append(opaque, check.Name())
+			continue
+		}
+		planned++
+		fmt.Fprintf(w, "\n%s %s\n", ui.RenderWarnIcon(), check.Name())
+		changes, err := planner.PlanFix(ctx)
+		if err != nil {
+			fmt.Fprintf(w, "  Cannot preview fix: %v\n", err)
+			continue
+		}
+		printFileChanges(w, changes)
+	}
+
+	if planned == 0 && len(opaque) == 0 {
+		fmt.Fprintf(w, "\n%s\n", ui.RenderMuted("No fixes to preview."))
+	}
+	if len(opaque) > 0 {
+		fmt.Fprintf(w, "\n%s %s\n", ui.RenderMuted("Fixes without a file preview (run --fix to apply):"), strings.Join(opaque, ", "))
+	}
+	fmt.Fprintln(w)
+	return report
+}
+
+// printFileChanges writes each changed file's note and diff.
+func printFileChanges(w io.Writer, changes []FileChange) {
+	shown := 0
+	for _, fc := range changes {
+		if !fc.Changed() {
+			continue
+		}
+		shown++
+		action := "modify"
+		if !fc.Exists {
+			action = "create"
+		}
+		fmt.Fprintf(w, "  Would %s %s", action, fc.Path)
+		if fc.Note != "" {
+			fmt.Fprintf(w, " %s", ui.RenderMuted("("+fc.Note+")"))
+		}
+		fmt.Fprintln(w)
+		fmt.Fprint(w, fc.Diff())
+	}
+	if shown == 0 {
+		fmt.Fprintln(w, "  No file changes (fix only prints instructions or runs commands)")
+	}
+}
+
+// diffOp is one line of a line-level edit script.
+type diffOp struct {
+	kind byte // ' ' unchanged, '-' removed, '+' added
+	text string
+}
+
+// unifiedDiff renders a unified diff of before and after. Returns "" when
+// they are equal.
+func unifiedDiff(from, to, before, after string) string {
+	ops := diffLines(splitLines(before), splitLines(after))
+
+	// aPos[k], bPos[k]: lines of before/after consumed before ops[k].
+	aPos := make([]int, len(ops)+1)
+	bPos := make([]int, len(ops)+1)
+	changed := false
+	for k, op := range ops {
+		aPos[k+1], bPos[k+1] = aPos[k], bPos[k]
+		if op.kind != '+' {
+			aPos[k+1]++
+		}
+		if op.kind != '-' {
+			bPos[k+1]++
+		}
+		if op.kind != ' ' {
+			changed = true
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", from, to)
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		// Grow the hunk while the next change is close enough to share context.
+		start := max(0, i-diffContext)
+		last := i
+		for j := i + 1; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				last = j
+			} else if j-last > 2*diffContext {
+				break
+			}
+		}
+		end := min(len(ops), last+diffContext+1)
+
+		aCount, bCount := aPos[end]-aPos[start], bPos[end]-bPos[start]
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(aPos[start], aCount), hunkRange(bPos[start], bCount))
+		for _, op := range ops[start:end] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.text)
+			b.WriteByte('\n')
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// hunkRange formats a hunk header range; an empty range names the line
+// before it, as diff(1) does.
+func hunkRange(pos, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", pos)
+	}
+	return fmt.Sprintf("%d,%d", pos+1, count)
+}
+
+// diffLines computes a minimal line edit script via longest common
+// subsequence. Fix files are small, so the quadratic table is fine.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// splitLines splits s into lines without their terminators.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,113 @@
+package doctor
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// planningMockCheck is a fixable mock that previews its fix as file changes.
+type planningMockCheck struct {
+	*mockCheck
+	changes []FileChange
+}
+
+func (p *planningMockCheck) PlanFix(ctx *CheckContext) ([]FileChange, error) {
+	return p.changes, nil
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	before := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	after := "a\nb\nC\nd\ne\nf\ng\nh\ni\nj\nk\n"
+	got := unifiedDiff("a/x", "b/x", before, after)
+	want := `--- a/x
++++ b/x
+@@ -1,6 +1,6 @@
+ a
+ b
+-c
++C
+ d
+ e
+ f
+@@ -8,3 +8,4 @@
+ h
+ i
+ j
++k
+`
+	// Changes 8 lines apart share no context at diffContext=3, so they
+	// land in separate hunks.
+	if got != want {
+		t.Errorf("unifiedDiff =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUnifiedDiff_MergesNearbyHunks(t *testing.T) {
+	got := unifiedDiff("a/x", "b/x", "1\n2\n3\n4\n5\n", "1\nX\n3\n4\nY\n")
+	if strings.Count(got, "@@ -") != 1 {
+		t.Errorf("nearby changes should share one hunk:\n%s", got)
+	}
+}
+
+func TestUnifiedDiff_NewFileAndEqual(t *testing.T) {
+	got := unifiedDiff("/dev/null", "b/etc/x.conf", "", "one\ntwo\n")
+	if !strings.Contains(got, "@@ -0,0 +1,2 @@\n+one\n+two\n") {
+		t.Errorf("new file diff:\n%s", got)
+	}
+	if got := unifiedDiff("a/x", "b/x", "same\n", "same\n"); got != "" {
+		t.Errorf("equal contents should produce no diff, got:\n%s", got)
+	}
+}
+
+func TestPlannedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limits.conf")
+	if fc := plannedFile(path, "new\n", ""); fc.Exists || !fc.Changed() {
+		t.Errorf("missing file: %+v", fc)
+	}
+	if err := os.WriteFile(path, []byte("new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if fc := plannedFile(path, "new\n", ""); !fc.Exists || fc.Changed() {
+		t.Errorf("up-to-date file should be unchanged: %+v", fc)
+	}
+}
+
+func TestDoctor_PlanFixesStreaming(t *testing.T) {
+	planned := &planningMockCheck{
+		mockCheck: newMockCheck("planned", StatusWarning),
+		changes: []FileChange{
+			{Path: "/etc/new.conf", After: "x=1\n"},
+			{Path: "/etc/same.conf", Before: "y\n", After: "y\n", Exists: true},
+		},
+	}
+	planned.fixable = true
+	opaque := newMockCheck("opaque", StatusError)
+	opaque.fixable = true
+	healthy := newMockCheck("healthy", StatusOK)
+	healthy.fixable = true
+
+	d := NewDoctor()
+	d.RegisterAll(planned, opaque, healthy)
+
+	var buf bytes.Buffer
+	report := d.PlanFixesStreaming(&CheckContext{TownRoot: t.TempDir()}, &buf, 0)
+	out := buf.String()
+
+	if planned.fixCount != 0 || opaque.fixCount != 0 || healthy.fixCount != 0 {
+		t.Error("--diff must not apply any fix")
+	}
+	if report.Summary.Warnings != 1 || report.Summary.Errors != 1 {
+		t.Errorf("summary = %+v, want 1 warning and 1 error", report.Summary)
+	}
+	for _, want := range []string{"Would create /etc/new.conf", "--- /dev/null", "+x=1", "opaque"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "same.conf") {
+		t.Errorf("unchanged files should not be listed:\n%s", out)
+	}
+}
@@ -0,0 +1,339 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// looseObjectLimit matches git's own gc.auto threshold: past it, every
+// object lookup in a busy rig pays for scanning loose files.
+const looseObjectLimit = 6700
+
+// gitHealthTimeout bounds each git command, fsck included.
+const gitHealthTimeout = 2 * time.Minute
+
+// gitRepoProblem is one finding in a rig repository or worktree.
+type gitRepoProblem struct {
+	path    string // repository or worktree directory
+	kind    string // "corrupt", "loose", "dangling", "detached"
+	message string
+}
+
+// GitCheck walks each rig's repositories (.repo.git, mayor/rig, crew
+// clones) and polecat worktrees for git health problems: object corruption
+// (git fsck --connectivity-only), loose objects past git's gc.auto
+// threshold, worktree registrations whose directory is gone, and polecat
+// worktrees on a detached HEAD.
+//
+// Fix only runs operations that can't lose work: 'git worktree prune' for
+// dangling registrations (locked worktrees are left alone) and 'git gc'
+// with git's default two-week prune grace for loose objects. Corruption and
+// detached HEADs are reported with guidance but never touched.
+type GitCheck struct {
+	FixableCheck
+
+	problems []gitRepoProblem
+}
+
+// NewGitCheck creates a new rig git health check.
+func NewGitCheck() *GitCheck {
+	return &GitCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "git-health",
+				CheckDescription: "Check rig repos and worktrees for corruption, loose objects, and stale worktrees",
+				CheckCategory:    CategoryRig,
+			},
+		},
+	}
+}
+
+// Run inspects every rig (or only --rig).
+func (c *GitCheck) Run(ctx *CheckContext) *CheckResult {
+	c.problems = nil
+	rigs := 0
+	for _, rigPath := range findAllRigs(ctx.TownRoot) {
+		if ctx.RigName != "" && filepath.Base(rigPath) != ctx.RigName {
+			continue
+		}
+		rigs++
+		c.checkRig(rigPath)
+	}
+
+	if len(c.problems) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("Git repositories healthy in %d rig(s)", rigs),
+		}
+	}
+
+	counts := make(map[string]int)
+	var details []string
+	for _, p := range c.problems {
+		counts[p.kind]++
+		rel, err := filepath.Rel(ctx.TownRoot, p.path)
+		if err != nil {
+			rel = p.path
+		}
+		details = append(details, fmt.Sprintf("%s: %s", rel, p.message))
+	}
+
+	status := StatusWarning
+	if counts["corrupt"] > 0 {
+		status = StatusError
+	}
+	var parts []string
+	for _, kind := range []string{"corrupt", "loose", "dangling", "detached"} {
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[kind], gitProblemLabels[kind]))
+		}
+	}
+
+	var hints []string
+	if counts["loose"]+counts["dangling"] > 0 {
+		hints = append(hints, "Run 'gt doctor --fix' to prune stale worktrees and gc loose objects")
+	}
+	if counts["detached"] > 0 {
+		hints = append(hints, "a detached polecat should 'git switch -c <branch>' to keep its commits, or be recycled if it has none")
+	}
+	if counts["corrupt"] > 0 {
+		hints = append(hints, "corrupt repos need a fresh clone: back up unpushed work, then re-create the clone or worktree")
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  status,
+		Message: "Git problems: " + strings.Join(parts, ", "),
+		Details: details,
+		FixHint: strings.Join(hints, "; "),
+	}
+}
+
+// gitProblemLabels describe each problem kind in the summary message.
+var gitProblemLabels = map[string]string{
+	"corrupt":  "corrupt repo(s)",
+	"loose":    "repo(s) with excess loose objects",
+	"dangling": "dangling worktree registration(s)",
+	"detached": "detached polecat worktree(s)",
+}
+
+// checkRig inspects one rig's repositories and polecat worktrees.
+func (c *GitCheck) checkRig(rigPath string) {
+	for _, repo := range rigGitRepos(rigPath) {
+		c.checkRepo(repo)
+	}
+	for _, wt := range polecatWorktrees(rigPath) {
+		detached, err := gitHeadDetached(wt)
+		if err == nil && detached {
+			c.problems = append(c.problems, gitRepoProblem{path: wt, kind: "detached", message: "HEAD is detached (commits made here belong to no branch)"})
+		}
+	}
+}
+
+// checkRepo runs the object-store checks on one git directory.
+func (c *GitCheck) checkRepo(gitDir string) {
+	if out, err := runGit(gitDir, "fsck", "--connectivity-only", "--no-dangling", "--no-progress"); err != nil {
+		c.problems = append(c.problems, gitRepoProblem{path: gitDir, kind: "corrupt", message: "fsck: " + summarizeFsck(out, err)})
+	}
+
+	if out, err := runGit(gitDir, "count-objects", "-v"); err == nil {
+		if n := parseLooseCount(out); n > looseObjectLimit {
+			c.problems = append(c.problems, gitRepoProblem{path: gitDir, kind: "loose", message: fmt.Sprintf("%d loose objects (gc threshold %d)", n, looseObjectLimit)})
+		}
+	}
+
+	for _, wt := range danglingWorktrees(gitDir) {
+		c.problems = append(c.problems, gitRepoProblem{path: gitDir, kind: "dangling", message: fmt.Sprintf("worktree %q is registered but %s is gone", wt.name, wt.path)})
+	}
+}
+
+// Fix prunes dangling worktree registrations and collects loose objects.
+// Corruption and detached HEADs are left for a human.
+func (c *GitCheck) Fix(ctx *CheckContext) error {
+	var errs []string
+	pruned := make(map[string]bool)
+	for _, p := range c.problems {
+		var err error
+		switch p.kind {
+		case "dangling":
+			if pruned[p.path] {
+				continue
+			}
+			pruned[p.path] = true
+			_, err = runGit(p.path, "worktree", "prune")
+		case "loose":
+			_, err = runGit(p.path, "gc", "--quiet")
+		default:
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", p.path, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// rigGitRepos lists a rig's git directories: the shared .repo.git and any
+// full clones (mayor/rig, refinery/rig, witness/rig, crew/<name>).
+func rigGitRepos(rigPath string) []string {
+	var repos []string
+	if isGitDir(filepath.Join(rigPath, ".repo.git")) {
+		repos = append(repos, filepath.Join(rigPath, ".repo.git"))
+	}
+	clones := []string{
+		filepath.Join(rigPath, "mayor", "rig"),
+		filepath.Join(rigPath, "refinery", "rig"),
+		filepath.Join(rigPath, "witness", "rig"),
+	}
+	if entries, err := os.ReadDir(filepath.Join(rigPath, "crew")); err == nil {
+		for _, e := range entries {
+			if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+				clones = append(clones, filepath.Join(rigPath, "crew", e.Name()))
+			}
+		}
+	}
+	for _, dir := range clones {
+		if info, err := os.Stat(filepath.Join(dir, ".git")); err == nil && info.IsDir() {
+			repos = append(repos, filepath.Join(dir, ".git"))
+		}
+	}
+	return repos
+}
+
+// polecatWorktrees lists polecat checkouts in either layout:
+// polecats/<name>/<rig>/ or the older polecats/<name>/.
+func polecatWorktrees(rigPath string) []string {
+	var dirs []string
+	polecatsDir := filepath.Join(rigPath, "polecats")
+	entries, err := os.ReadDir(polecatsDir)
+	if err != nil {
+		return nil
+	}
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		for _, dir := range []string{
+			filepath.Join(polecatsDir, e.Name(), filepath.Base(rigPath)),
+			filepath.Join(polecatsDir, e.Name()),
+		} {
+			if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+				dirs = append(dirs, dir)
+				break
+			}
+		}
+	}
+	return dirs
+}
+
+// isGitDir reports whether dir looks like a git directory (bare or .git).
+func isGitDir(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "HEAD"))
+	return err == nil
+}
+
+// registeredWorktree is an entry under <gitdir>/worktrees/.
+type registeredWorktree struct {
+	name string // admin directory name
+	path string // worktree's .git file, per the gitdir entry
+}
+
+// danglingWorktrees lists unlocked worktree registrations whose checkout
+// no longer exists, i.e. what 'git worktree prune' would remove.
+func danglingWorktrees(gitDir string) []registeredWorktree {
+	entries, err := os.ReadDir(filepath.Join(gitDir, "worktrees"))
+	if err != nil {
+		return nil
+	}
+	var dangling []registeredWorktree
+	for _, e := range entries {
+		adminDir := filepath.Join(gitDir, "worktrees", e.Name())
+		if _, err := os.Stat(filepath.Join(adminDir, "locked")); err == nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(adminDir, "gitdir")) //nolint:gosec // G304: path is within the rig's git dir
+		if err != nil {
+			continue
+		}
+		target := strings.TrimSpace(string(data))
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(adminDir, target)
+		}
+		if _, err := os.Stat(target); os.IsNotExist(err) {
+			dangling = append(dangling, registeredWorktree{name: e.Name(), path: filepath.Dir(target)})
+		}
+	}
+	return dangling
+}
+
+// gitHeadDetached reports whether the checkout at dir is on a detached HEAD.
+func gitHeadDetached(dir string) (bool, error) {
+	_, err := runGit(dir, "symbolic-ref", "-q", "HEAD")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return true, nil
+	}
+	return false, err
+}
+
+// parseLooseCount reads the loose object count from 'git count-objects -v'.
+func parseLooseCount(out string) int {
+	for _, line := range strings.Split(out, "\n") {
+		if v, ok := strings.CutPrefix(line, "count: "); ok {
+			n, _ := strconv.Atoi(strings.TrimSpace(v))
+			return n
+		}
+	}
+	return 0
+}
+
+// summarizeFsck condenses fsck output to its first problem and a count,
+// preferring "missing <type> <id>" lines over the broken links that lead
+// to them.
+func summarizeFsck(out string, err error) string {
+	var problems, missing []string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "missing "):
+			missing = append(missing, line)
+		default:
+			problems = append(problems, line)
+		}
+	}
+	if len(missing) > 0 {
+		problems = missing
+	}
+	switch len(problems) {
+	case 0:
+		return err.Error()
+	case 1:
+		return problems[0]
+	default:
+		return fmt.Sprintf("%s (and %d more)", problems[0], len(problems)-1)
+	}
+}
+
+// runGit runs git against dir (a work tree or git directory) with a timeout.
+func runGit(dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitHealthTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...) //nolint:gosec // G204: args are constructed internally
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		return string(out), fmt.Errorf("git %s timed out after %v", args[0], gitHealthTimeout)
+	}
+	return string(out), err
+}
@@ -0,0 +1,170 @@
+package doctor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// gitRun runs git in dir and fails the test on error.
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// setupGitRig creates <town>/gastown with a .repo.git holding one commit,
+// a refinery worktree, and a polecat worktree on its own branch.
+func setupGitRig(t *testing.T) (townRoot, rigPath string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	townRoot = t.TempDir()
+	rigPath = filepath.Join(townRoot, "gastown")
+
+	src := filepath.Join(t.TempDir(), "src")
+	if err := os.MkdirAll(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, src, "init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(src, "README"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, src, "add", "README")
+	gitRun(t, src, "commit", "-q", "-m", "initial")
+
+	if err := os.MkdirAll(rigPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, rigPath, "clone", "-q", "--bare", src, ".repo.git")
+	bare := filepath.Join(rigPath, ".repo.git")
+	gitRun(t, bare, "worktree", "add", "-q", filepath.Join(rigPath, "refinery", "rig"), "main")
+	gitRun(t, bare, "worktree", "add", "-q", "-b", "polecat/toast", filepath.Join(rigPath, "polecats", "toast", "gastown"), "main")
+	return townRoot, rigPath
+}
+
+func TestGitCheck_Healthy(t *testing.T) {
+	townRoot, _ := setupGitRig(t)
+	result := NewGitCheck().Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusOK {
+		t.Errorf("expected OK, got %v: %s %v", result.Status, result.Message, result.Details)
+	}
+}
+
+func TestGitCheck_DetachedPolecat(t *testing.T) {
+	townRoot, rigPath := setupGitRig(t)
+	gitRun(t, filepath.Join(rigPath, "polecats", "toast", "gastown"), "checkout", "-q", "--detach")
+
+	check := NewGitCheck()
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusWarning {
+		t.Fatalf("expected warning, got %v: %v", result.Status, result.Details)
+	}
+	want := filepath.Join("gastown", "polecats", "toast", "gastown") + ": HEAD is detached"
+	if len(result.Details) != 1 || !strings.HasPrefix(result.Details[0], want) {
+		t.Errorf("details = %v, want %q", result.Details, want)
+	}
+	if !strings.Contains(result.FixHint, "git switch -c") {
+		t.Errorf("FixHint %q should explain how to recover the detached polecat", result.FixHint)
+	}
+
+	// Fix never touches a detached worktree.
+	if err := check.Fix(&CheckContext{TownRoot: townRoot}); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if detached, _ := gitHeadDetached(filepath.Join(rigPath, "polecats", "toast", "gastown")); !detached {
+		t.Error("Fix should leave the detached HEAD for a human")
+	}
+}
+
+func TestGitCheck_DanglingWorktreeFix(t *testing.T) {
+	townRoot, rigPath := setupGitRig(t)
+	if err := os.RemoveAll(filepath.Join(rigPath, "polecats", "toast")); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewGitCheck()
+	ctx := &CheckContext{TownRoot: townRoot}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning || len(result.Details) != 1 ||
+		!strings.Contains(result.Details[0], `worktree "gastown" is registered`) {
+		t.Fatalf("expected one dangling worktree, got %v: %v", result.Status, result.Details)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("after Fix: %v %v", result.Status, result.Details)
+	}
+	// The live refinery worktree survives the prune.
+	if out := gitRun(t, filepath.Join(rigPath, ".repo.git"), "worktree", "list"); !strings.Contains(out, filepath.Join("refinery", "rig")) {
+		t.Errorf("refinery worktree should still be registered:\n%s", out)
+	}
+}
+
+func TestGitCheck_LockedWorktreeNotDangling(t *testing.T) {
+	townRoot, rigPath := setupGitRig(t)
+	bare := filepath.Join(rigPath, ".repo.git")
+	wt := filepath.Join(rigPath, "polecats", "toast", "gastown")
+	gitRun(t, bare, "worktree", "lock", wt)
+	if err := os.RemoveAll(filepath.Join(rigPath, "polecats", "toast")); err != nil {
+		t.Fatal(err)
+	}
+
+	if result := NewGitCheck().Run(&CheckContext{TownRoot: townRoot}); result.Status != StatusOK {
+		t.Errorf("locked worktrees (e.g. on removable media) should not be flagged: %v", result.Details)
+	}
+}
+
+func TestGitCheck_Corrupt(t *testing.T) {
+	townRoot, rigPath := setupGitRig(t)
+	bare := filepath.Join(rigPath, ".repo.git")
+	blob := gitRun(t, bare, "rev-parse", "main:README")
+	if err := os.Remove(filepath.Join(bare, "objects", blob[:2], blob[2:])); err != nil {
+		t.Fatalf("removing blob (expected loose object): %v", err)
+	}
+
+	result := NewGitCheck().Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusError {
+		t.Fatalf("expected error, got %v: %v", result.Status, result.Details)
+	}
+	found := false
+	for _, d := range result.Details {
+		if strings.HasPrefix(d, filepath.Join("gastown", ".repo.git")+": fsck: ") && strings.Contains(d, blob) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("details should name the missing blob %s: %v", blob, result.Details)
+	}
+}
+
+func TestGitCheck_RigFilter(t *testing.T) {
+	townRoot, rigPath := setupGitRig(t)
+	gitRun(t, filepath.Join(rigPath, "polecats", "toast", "gastown"), "checkout", "-q", "--detach")
+
+	if result := NewGitCheck().Run(&CheckContext{TownRoot: townRoot, RigName: "other"}); result.Status != StatusOK {
+		t.Errorf("--rig other should skip gastown, got %v: %v", result.Status, result.Details)
+	}
+}
+
+func TestParseLooseCount(t *testing.T) {
+	out := "count: 7012\nsize: 30000\nin-pack: 120\npacks: 1\n"
+	if got := parseLooseCount(out); got != 7012 {
+		t.Errorf("parseLooseCount = %d, want 7012", got)
+	}
+	if got := parseLooseCount(""); got != 0 {
+		t.Errorf("parseLooseCount(\"\") = %d, want 0", got)
+	}
+}
@@ -0,0 +1,241 @@
+package doctor
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Per-agent container budgets. A Claude process idles at a few hundred MiB
+// and spends most of its time waiting on the model API, so CPU needs are small.
+const (
+	limitsBaseMemory     = 1 << 30   // 1 GiB for tmux, daemon, Dolt
+	limitsMemoryPerAgent = 512 << 20 // 512 MiB
+	limitsBaseCPUs       = 1.0
+	limitsCPUsPerAgent   = 0.25
+)
+
+// cgroupRoot is where the unified (v2) hierarchy is mounted.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// errNoCgroupV2 means the host uses cgroup v1 or hybrid mode.
+var errNoCgroupV2 = errors.New("cgroup v2 not mounted")
+
+// cgroupLimits are the cgroup v2 limits of this process's cgroup.
+// Zero means unknown; limitUnlimited (or CPUs == 0) means no ceiling.
+type cgroupLimits struct {
+	MemoryMax uint64  // memory.max in bytes
+	PidsMax   uint64  // pids.max
+	CPUs      float64 // cpu.max quota/period, in cores
+}
+
+// checkCgroup reports cgroup v2 limits below the cached requirement.
+func (c *LimitsCheck) checkCgroup() []string {
+	limits, err := c.readCgroup()
+	if errors.Is(err, errNoCgroupV2) {
+		return nil
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("Could not read cgroup limits: %v", err)}
+	}
+
+	var details []string
+	if limits.MemoryMax != 0 && limits.MemoryMax != limitUnlimited && limits.MemoryMax < c.required.MemoryBytes {
+		details = append(details, fmt.Sprintf("cgroup memory.max: %s (need %s)", formatBytes(int64(limits.MemoryMax)), formatBytes(int64(c.required.MemoryBytes))))
+	}
+	details = append(details, checkLimit("cgroup pids.max", limits.PidsMax, c.required.NPROC)...)
+	if limits.CPUs > 0 && limits.CPUs < c.required.CPUs {
+		details = append(details, fmt.Sprintf("cgroup cpu.max: %.2f CPUs (need %.2f)", limits.CPUs, c.required.CPUs))
+	}
+	return details
+}
+
+// readCgroupLimits reads memory.max, pids.max, and cpu.max for this
+// process's cgroup. Inside a container's cgroup namespace the cgroup path is
+// "/", so the files sit directly under the mount point.
+func readCgroupLimits() (cgroupLimits, error) {
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		return cgroupLimits{}, errNoCgroupV2
+	}
+	dir := cgroupRoot
+	if f, err := os.Open("/proc/self/cgroup"); err == nil {
+		rel := parseCgroupV2Path(f)
+		f.Close()
+		if rel != "" {
+			dir = filepath.Join(cgroupRoot, rel)
+		}
+	}
+	return readCgroupDir(dir)
+}
+
+// readCgroupDir reads the limit files of one cgroup directory. Missing files
+// (controller not enabled) leave the corresponding limit unknown.
+func readCgroupDir(dir string) (cgroupLimits, error) {
+	var limits cgroupLimits
+	read := func(name string) (string, bool) {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return "", false
+		}
+		return strings.TrimSpace(string(data)), true
+	}
+
+	if v, ok := read("memory.max"); ok {
+		limits.MemoryMax = parseLimit(v)
+	}
+	if v, ok := read("pids.max"); ok {
+		limits.PidsMax = parseLimit(v)
+	}
+	if v, ok := read("cpu.max"); ok {
+		cpus, err := parseCPUMax(v)
+		if err != nil {
+			return limits, err
+		}
+		limits.CPUs = cpus
+	}
+	return limits, nil
+}
+
+// parseCgroupV2Path returns the unified-hierarchy path ("0::/path") from
+// /proc/self/cgroup, or "" if there is none.
+func parseCgroupV2Path(r io.Reader) string {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if path, ok := strings.CutPrefix(scanner.Text(), "0::"); ok {
+			return path
+		}
+	}
+	return ""
+}
+
+// parseCPUMax parses cpu.max ("max 100000" or "<quota> <period>") into cores.
+// Returns 0 when unlimited.
+func parseCPUMax(s string) (float64, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 || fields[0] == "max" {
+		return 0, nil
+	}
+	quota, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing cpu.max %q: %w", s, err)
+	}
+	period := 100000.0
+	if len(fields) > 1 {
+		if period, err = strconv.ParseFloat(fields[1], 64); err != nil || period <= 0 {
+			return 0, fmt.Errorf("parsing cpu.max %q: bad period", s)
+		}
+	}
+	return quota / period, nil
+}
+
+// writeContainerFixes appends container remediation to a fix script. Limits
+// of a container can only be raised from outside it, so the script prints
+// the settings to apply on the host for the detected runtime rather than
+// changing anything. In a user namespace the host's sysctls apply too, and
+// a rootless runtime is further capped by its host user's own limits.
+func writeContainerFixes(w io.Writer, req limitsRequirement, env containerEnv, sysctls []sysctlSetting) {
+	memMiB := req.MemoryBytes >> 20
+	var b strings.Builder
+	switch {
+	case env.Rootless:
+		writeRootlessFixes(&b, req, env)
+	case env.Runtime == runtimeNspawn:
+		fmt.Fprintf(&b, `  systemd-nspawn (on the host, as root):
+    systemctl set-property systemd-nspawn@<machine>.service \
+      MemoryMax=%dM TasksMax=%d CPUQuota=%.0f%%
+    # /etc/systemd/nspawn/<machine>.nspawn
+    [Exec]
+    LimitNOFILE=%d
+    LimitNPROC=%d
+    # then: machinectl reboot <machine>
+`, memMiB, req.NPROC, req.CPUs*100, req.NOFILE, req.NPROC)
+	case env.Runtime == runtimeLXC:
+		fmt.Fprintf(&b, `  LXC (container config on the host):
+    lxc.prlimit.nofile = %d
+    lxc.prlimit.nproc = %d
+    lxc.cgroup2.memory.max = %dM
+    lxc.cgroup2.pids.max = %d
+
+  LXD / Incus:
+    lxc config set <container> limits.memory=%dMiB limits.processes=%d
+    # nofile: lxc config set <container> limits.kernel.nofile=%d
+`, req.NOFILE, req.NPROC, memMiB, req.NPROC, memMiB, req.NPROC, req.NOFILE)
+	default:
+		fmt.Fprintf(&b, `  docker / podman (running container):
+    docker update --memory %dm --memory-swap %dm --pids-limit %d --cpus %.2f <container>
+
+  docker / podman (new container; ulimits can't be changed on a running one):
+    docker run --memory %dm --pids-limit %d --cpus %.2f \
+      --ulimit nofile=%d:%d --ulimit nproc=%d:%d ...
+
+  docker compose:
+    deploy.resources.limits: { memory: %dM, cpus: "%.2f", pids: %d }
+    ulimits: { nofile: { soft: %d, hard: %d } }
+
+  Kubernetes (pod spec):
+    resources:
+      limits:
+        memory: %dMi
+        cpu: "%.2f"
+    # pids.max comes from the kubelet's podPidsLimit (KubeletConfiguration);
+    # it must be at least %d. The nofile limit comes from the container
+    # runtime's default ulimits (containerd/CRI-O config).
+`,
+			memMiB, memMiB, req.NPROC, req.CPUs,
+			memMiB, req.NPROC, req.CPUs, req.NOFILE, req.NOFILE, req.NPROC, req.NPROC,
+			memMiB, req.CPUs, req.NPROC, req.NOFILE, req.NOFILE,
+			memMiB, req.CPUs, req.NPROC)
+	}
+
+	if env.UserNS && len(sysctls) > 0 {
+		b.WriteString(`
+  Kernel settings are shared with the host and read-only in a user namespace.
+  On the host, as root:
+`)
+		for _, s := range sysctls {
+			fmt.Fprintf(&b, "    echo '%s = %d' >> %s\n", s.Name, s.Need, limitsSysctlPath)
+		}
+		b.WriteString("    sysctl --system\n")
+	}
+
+	fmt.Fprintf(w, `
+# Running inside a container: limits are set by the container runtime and
+# cannot be raised from in here. Apply one of the following on the host.
+cat <<'EOF'
+Container limits must be raised from the host:
+
+%sEOF
+`, b.String())
+}
+
+// writeRootlessFixes covers rootless podman and docker: --ulimit can't go
+// above the hard limits of the host user running the runtime, and the
+// cgroup flags only work when systemd delegates those controllers to that
+// user. Both are raised on the host before recreating the container.
+func writeRootlessFixes(b *strings.Builder, req limitsRequirement, env containerEnv) {
+	name := env.Runtime
+	if name != runtimeDocker {
+		name = runtimePodman
+	}
+	memMiB := req.MemoryBytes >> 20
+	fmt.Fprintf(b, `  Rootless %[1]s: the container is capped by the limits of the host user
+  that runs %[1]s. On the host, as root (replace <host-user>):
+    echo '<host-user> hard nofile %[2]d' >> %[3]s
+    echo '<host-user> hard nproc %[4]d' >> %[3]s
+    mkdir -p %[5]s
+    printf '[Service]\nLimitNOFILE=%[2]d:%[2]d\nLimitNPROC=%[4]d:%[4]d\nTasksMax=%[4]d\nDelegate=cpu memory pids\n' \
+      > %[6]s
+    systemctl daemon-reload
+    # then log <host-user> out and back in (or: loginctl terminate-user <host-user>)
+
+  Then, as <host-user>, recreate the container:
+    %[1]s run --memory %[7]dm --pids-limit %[4]d --cpus %.2[8]f \
+      --ulimit nofile=%[2]d:%[2]d --ulimit nproc=%[4]d:%[4]d ...
+`, name, req.NOFILE, limitsConfPath, req.NPROC, path.Dir(limitsUserServiceDrop), limitsUserServiceDrop, memMiB, req.CPUs)
+}
@@ -0,0 +1,175 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCPUMax(t *testing.T) {
+	tests := map[string]float64{
+		"max 100000":    0,
+		"200000 100000": 2,
+		"50000 100000":  0.5,
+		"150000":        1.5,
+	}
+	for in, want := range tests {
+		got, err := parseCPUMax(in)
+		if err != nil || got != want {
+			t.Errorf("parseCPUMax(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := parseCPUMax("lots 100000"); err == nil {
+		t.Error("expected error for malformed cpu.max")
+	}
+}
+
+func TestParseCgroupV2Path(t *testing.T) {
+	in := "12:memory:/docker/abc\n0::/system.slice/docker-abc.scope\n"
+	if got := parseCgroupV2Path(strings.NewReader(in)); got != "/system.slice/docker-abc.scope" {
+		t.Errorf("parseCgroupV2Path = %q", got)
+	}
+	if got := parseCgroupV2Path(strings.NewReader("4:memory:/x\n")); got != "" {
+		t.Errorf("v1-only cgroup should yield empty path, got %q", got)
+	}
+}
+
+func TestReadCgroupDir(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"memory.max": "2147483648\n",
+		"pids.max":   "max\n",
+		"cpu.max":    "100000 100000\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	limits, err := readCgroupDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limits.MemoryMax != 2<<30 || limits.PidsMax != limitUnlimited || limits.CPUs != 1 {
+		t.Errorf("readCgroupDir = %+v", limits)
+	}
+}
+
+func TestLimitsCheck_ContainerCgroup(t *testing.T) {
+	check := newTestLimitsCheck(processLimits{
+		NOFILESoft: 1 << 20, NOFILEHard: 1 << 20, NPROCSoft: limitUnlimited, NPROCHard: limitUnlimited,
+	})
+	check.platform = func() Platform { return PlatformLinuxContainer }
+	check.readCgroup = func() (cgroupLimits, error) {
+		return cgroupLimits{MemoryMax: 2 << 30, PidsMax: 1024, CPUs: 0}, nil
+	}
+
+	townRoot := setupMailTown(t) // 14 agents: 8GiB, 2304 pids
+	ctx := &CheckContext{TownRoot: townRoot}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("expected warning for tight cgroup limits, got %v", result.Status)
+	}
+	joined := strings.Join(result.Details, "\n")
+	for _, want := range []string{"cgroup memory.max: 2.0 GB (need 8.0 GB)", "cgroup pids.max: 1024"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("details missing %q:\n%s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "cpu.max") {
+		t.Errorf("unlimited cpu.max should not be flagged:\n%s", joined)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(check.scriptPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := string(data)
+	for _, want := range []string{"docker update --memory 8192m", "--pids-limit 2304", "--ulimit nofile=", "memory: 8192Mi", "podPidsLimit"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("container fix script missing %q:\n%s", want, script)
+		}
+	}
+	if strings.Contains(script, "limits.d") {
+		t.Errorf("container fix script should not touch limits.d:\n%s", script)
+	}
+}
+
+func TestLimitsCheck_HostIgnoresCgroup(t *testing.T) {
+	check := newTestLimitsCheck(processLimits{NOFILESoft: 1 << 20, NOFILEHard: 1 << 20})
+	check.readCgroup = func() (cgroupLimits, error) {
+		t.Error("cgroup limits should only be read inside containers")
+		return cgroupLimits{}, nil
+	}
+	check.Run(&CheckContext{TownRoot: setupMailTown(t)})
+}
+
+func TestLimitsCheck_RootlessContainer(t *testing.T) {
+	check := newTestLimitsCheck(processLimits{
+		NOFILESoft: 1 << 20, NOFILEHard: 1 << 20, NPROCSoft: limitUnlimited, NPROCHard: limitUnlimited,
+	})
+	check.platform = func() Platform { return PlatformLinuxContainer }
+	check.container = func() containerEnv {
+		return containerEnv{Runtime: runtimePodman, UserNS: true, Rootless: true}
+	}
+	check.readCgroup = func() (cgroupLimits, error) { return cgroupLimits{}, nil }
+	check.readSysctl = func(name string) (uint64, error) {
+		if name == "fs.inotify.max_user_watches" {
+			return 8192, nil
+		}
+		return 1 << 22, nil
+	}
+
+	ctx := &CheckContext{TownRoot: setupMailTown(t)}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("expected warning for host sysctl in a user namespace, got %v", result.Status)
+	}
+	if !strings.Contains(strings.Join(result.Details, "\n"), "sysctl fs.inotify.max_user_watches: 8192") {
+		t.Errorf("details should report the host sysctl:\n%v", result.Details)
+	}
+	if !strings.Contains(result.FixHint, "rootless podman") {
+		t.Errorf("fix hint should name the rootless runtime: %q", result.FixHint)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(check.scriptPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := string(data)
+	for _, want := range []string{
+		"Rootless podman",
+		"<host-user> hard nofile",
+		"Delegate=cpu memory pids",
+		"podman run --memory 8192m",
+		"echo 'fs.inotify.max_user_watches = ",
+		"sysctl --system",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("rootless fix script missing %q:\n%s", want, script)
+		}
+	}
+	if strings.Contains(script, "docker update") {
+		t.Errorf("rootless fix script should not suggest docker update:\n%s", script)
+	}
+}
+
+func TestWriteContainerFixes_Nspawn(t *testing.T) {
+	var b strings.Builder
+	writeContainerFixes(&b, limitsFor(4, nil), containerEnv{Runtime: runtimeNspawn}, nil)
+	out := b.String()
+	for _, want := range []string{"systemd-nspawn@<machine>.service", "TasksMax=", "CPUQuota=", "[Exec]", "LimitNOFILE="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("nspawn fixes missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "%!") {
+		t.Errorf("bad format verb in output:\n%s", out)
+	}
+}
@@ -0,0 +1,336 @@
+package doctor
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/wisp"
+)
+
+// Per-agent resource budgets. Each agent is a tmux pane running a shell and
+// a Claude process (node, MCP servers, git, bd), which together hold a few
+// hundred descriptors, tens of threads, and file watchers over the worktree.
+// The budgets leave headroom. Towns can override the resulting targets in
+// settings/config.json (limits).
+const (
+	limitsBaseNOFILE      = 4096
+	limitsNOFILEPerAgent  = 1024
+	limitsBaseNPROC       = 512
+	limitsNPROCPerAgent   = 128
+	limitsBaseInotify     = 65536
+	limitsInotifyPerAgent = 8192
+)
+
+// limitUnlimited marks a limit with no ceiling ("unlimited" / "infinity").
+const limitUnlimited = math.MaxUint64
+
+// limitsFixScriptName is the fix script written under <town>/.runtime/.
+const limitsFixScriptName = "fix-limits.sh"
+
+// Platform identifies the host environment for limits detection.
+type Platform string
+
+const (
+	// PlatformLinux is a Linux host (bare metal or VM).
+	PlatformLinux Platform = "linux"
+	// PlatformLinuxContainer is Linux inside a container (docker, podman, k8s,
+	// systemd-nspawn, LXC) or any other user namespace.
+	PlatformLinuxContainer Platform = "linux-container"
+	// PlatformDarwin is macOS.
+	PlatformDarwin Platform = "darwin"
+	// PlatformWindows is Windows, which has no rlimits; handle counts are checked instead.
+	PlatformWindows Platform = "windows"
+	// PlatformOther is any other OS; limits checks are skipped.
+	PlatformOther Platform = "other"
+)
+
+// processLimits are the resource limits agent sessions inherit.
+// Zero means unknown; limitUnlimited means no ceiling.
+type processLimits struct {
+	NOFILESoft uint64
+	NOFILEHard uint64
+	NPROCSoft  uint64
+	NPROCHard  uint64
+}
+
+// limitsRequirement is what the town needs at its configured size.
+type limitsRequirement struct {
+	Agents         int
+	NOFILE         uint64
+	NPROC          uint64
+	InotifyWatches uint64
+	MemoryBytes    uint64
+	CPUs           float64
+}
+
+// LimitsCheck verifies that the open-file and process limits sessions inherit
+// are high enough for the configured number of agents (every rig's
+// max_polecats plus witness, refinery, mayor, and deacon), or for the
+// targets set under limits in settings/config.json.
+//
+// On systemd hosts the effective limits for tmux and Claude sessions come from
+// the systemd manager defaults and user@.service, not /etc/security/limits.conf,
+// so those are inspected too, along with the system-wide fs.file-max,
+// kernel.pid_max, and fs.inotify.max_user_watches. Inside containers the cgroup v2 memory, pids, and CPU
+// limits are checked as well, plus the host sysctls when in a user namespace
+// (rootless podman or docker, nspawn -U). Windows has no such limits to raise, so
+// there the check looks for agent processes leaking handles instead.
+//
+// Fix writes a script to <town>/.runtime/fix-limits.sh that installs systemd
+// drop-ins (or edits limits.conf elsewhere, or prints the host-side settings
+// for the detected container runtime); it needs root and a fresh login, so doctor never
+// runs it itself. PlanFix previews the files the script would write.
+type LimitsCheck struct {
+	FixableCheck
+
+	// Injected for testing.
+	platform      func() Platform
+	readLimits    func() (processLimits, error)
+	systemdBooted func() bool
+	systemctlShow func(userManager bool, unit string, props ...string) (map[string]string, error)
+	readCgroup    func() (cgroupLimits, error)
+	listHandles   func() ([]processHandleCount, error)
+	readSysctl    func(name string) (uint64, error)
+	container     func() containerEnv
+
+	// Cached by Run for Fix.
+	required   limitsRequirement
+	systemd    bool
+	sysctls    []sysctlSetting
+	env        containerEnv
+	scriptPath string
+}
+
+// NewLimitsCheck creates a new resource limits check.
+func NewLimitsCheck() *LimitsCheck {
+	return &LimitsCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "limits",
+				CheckDescription: "Check file descriptor and process limits for the configured polecat count",
+				CheckCategory:    CategoryInfrastructure,
+			},
+		},
+		platform:      detectPlatform,
+		readLimits:    readProcessLimits,
+		systemdBooted: systemdBooted,
+		systemctlShow: systemctlShow,
+		readCgroup:    readCgroupLimits,
+		listHandles:   listProcessHandles,
+		readSysctl:    readSysctl,
+		container:     detectContainer,
+	}
+}
+
+// Run compares inherited, systemd-managed, and cgroup limits against the requirement.
+func (c *LimitsCheck) Run(ctx *CheckContext) *CheckResult {
+	platform := c.platform()
+	if platform == PlatformOther {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("Limits check not supported on %s (skipped)", runtime.GOOS),
+		}
+	}
+	if platform == PlatformWindows {
+		return c.runHandleCheck()
+	}
+
+	c.required = limitsFor(configuredAgentCount(ctx.TownRoot), ctx.TownSettings().Limits)
+	c.systemd = platform == PlatformLinux && c.systemdBooted()
+
+	var details []string
+	limits, err := c.readLimits()
+	if err != nil {
+		details = append(details, fmt.Sprintf("Could not read process limits: %v", err))
+	} else {
+		details = append(details, checkLimit("open files (soft)", limits.NOFILESoft, c.required.NOFILE)...)
+		details = append(details, checkLimit("open files (hard)", limits.NOFILEHard, c.required.NOFILE)...)
+		details = append(details, checkLimit("processes (soft)", limits.NPROCSoft, c.required.NPROC)...)
+	}
+	if c.systemd {
+		details = append(details, c.checkSystemd()...)
+	}
+	if platform == PlatformLinux {
+		details = append(details, c.checkSysctl()...)
+	}
+	if platform == PlatformLinuxContainer {
+		c.env = c.container()
+		details = append(details, c.checkCgroup()...)
+		// Sysctls aren't namespaced, so in a user namespace the host's
+		// values are what the container gets, and only the host can change them.
+		if c.env.UserNS {
+			details = append(details, c.checkSysctl()...)
+		}
+	}
+
+	if len(details) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("Limits sufficient for %d agent(s) (nofile ≥ %d, nproc ≥ %d)", c.required.Agents, c.required.NOFILE, c.required.NPROC),
+		}
+	}
+
+	fixHint := "Run 'gt doctor --fix' to generate " + filepath.Join(constants.DirRuntime, limitsFixScriptName) +
+		" (preview with --diff), then run it with sudo and log in again"
+	if c.scriptPath != "" {
+		fixHint = fmt.Sprintf("Run 'sudo sh %s', then log out and back in (or reboot)", c.scriptPath)
+	}
+	if platform == PlatformLinuxContainer {
+		fixHint = "Container limits must be raised from the host; run 'gt doctor --fix' and see " +
+			filepath.Join(constants.DirRuntime, limitsFixScriptName) + " for " + containerFixTarget(c.env)
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("Resource limits too low for %d agent(s) (need nofile ≥ %d, nproc ≥ %d)", c.required.Agents, c.required.NOFILE, c.required.NPROC),
+		Details: details,
+		FixHint: fixHint,
+	}
+}
+
+// Fix writes the fix script; applying it requires root and a new login session.
+func (c *LimitsCheck) Fix(ctx *CheckContext) error {
+	if c.platform() == PlatformWindows {
+		return fmt.Errorf("no fix script on Windows: restart the processes holding too many handles")
+	}
+	if c.required.Agents == 0 {
+		c.required = limitsFor(configuredAgentCount(ctx.TownRoot), ctx.TownSettings().Limits)
+	}
+	if c.platform() == PlatformLinuxContainer && !c.env.detected() {
+		c.env = c.container()
+	}
+
+	dir := filepath.Join(ctx.TownRoot, constants.DirRuntime)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, limitsFixScriptName)
+	if err := os.WriteFile(path, []byte(limitsFixScript(c.required, c.platform(), c.env, c.systemd, currentUser(), c.sysctls)), 0755); err != nil { //nolint:gosec // G306: script must be executable
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	c.scriptPath = path
+	return nil
+}
+
+// limitsFor computes the required limits for a number of agents, with any
+// targets the town configured taking precedence.
+func limitsFor(agents int, targets *config.LimitsConfig) limitsRequirement {
+	req := limitsRequirement{
+		Agents:         agents,
+		NOFILE:         uint64(limitsBaseNOFILE + agents*limitsNOFILEPerAgent),
+		NPROC:          uint64(limitsBaseNPROC + agents*limitsNPROCPerAgent),
+		InotifyWatches: uint64(limitsBaseInotify + agents*limitsInotifyPerAgent),
+		MemoryBytes:    uint64(limitsBaseMemory + agents*limitsMemoryPerAgent),
+		CPUs:           limitsBaseCPUs + float64(agents)*limitsCPUsPerAgent,
+	}
+	if targets != nil {
+		if targets.NOFILE > 0 {
+			req.NOFILE = targets.NOFILE
+		}
+		if targets.NPROC > 0 {
+			req.NPROC = targets.NPROC
+		}
+		if targets.InotifyWatches > 0 {
+			req.InotifyWatches = targets.InotifyWatches
+		}
+	}
+	return req
+}
+
+// checkLimit reports a limit below its requirement. Unknown limits are skipped.
+func checkLimit(label string, have, need uint64) []string {
+	if have == 0 || have >= need {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s: %s (need %d)", label, formatLimit(have), need)}
+}
+
+// formatLimit renders a limit value, spelling out unlimited.
+func formatLimit(v uint64) string {
+	if v == limitUnlimited {
+		return "unlimited"
+	}
+	return strconv.FormatUint(v, 10)
+}
+
+// parseLimit parses a limit as printed by ulimit, /proc/self/limits,
+// systemctl show, or a cgroup limit file. Returns 0 for values it can't
+// interpret.
+func parseLimit(s string) uint64 {
+	s = strings.TrimSpace(s)
+	switch s {
+	case "unlimited", "infinity", "max":
+		return limitUnlimited
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// configuredAgentCount returns how many agent sessions the town may run at
+// full capacity: each rig's max_polecats (wisp layer, else the system default)
+// plus its witness and refinery, plus the mayor and deacon.
+func configuredAgentCount(townRoot string) int {
+	agents := 2 // mayor, deacon
+	cfg, err := loadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return agents
+	}
+	defaultMax, _ := rig.SystemDefaults["max_polecats"].(int)
+	for rigName := range cfg.Rigs {
+		maxPolecats := defaultMax
+		switch v := wisp.NewConfig(townRoot, rigName).Get("max_polecats").(type) {
+		case float64:
+			maxPolecats = int(v)
+		case string:
+			if n, err := strconv.Atoi(v); err == nil {
+				maxPolecats = n
+			}
+		}
+		agents += maxPolecats + 2 // polecats, witness, refinery
+	}
+	return agents
+}
+
+// detectPlatform identifies the host OS and whether we're inside a container.
+func detectPlatform() Platform {
+	switch runtime.GOOS {
+	case "darwin":
+		return PlatformDarwin
+	case "windows":
+		return PlatformWindows
+	case "linux":
+		if detectContainer().detected() {
+			return PlatformLinuxContainer
+		}
+		return PlatformLinux
+	default:
+		return PlatformOther
+	}
+}
+
+// currentUser returns the login name for the limits.conf entry. The fix
+// files are written verbatim (so --diff shows exactly what lands on disk),
+// so when the name is unknown the entry falls back to the "*" wildcard.
+func currentUser() string {
+	if u := os.Getenv("USER"); u != "" {
+		return u
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "*"
+}
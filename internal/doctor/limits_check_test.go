@@ -0,0 +1,169 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// newTestLimitsCheck returns a LimitsCheck on a non-systemd Linux host with
+// the given inherited limits.
+func newTestLimitsCheck(limits processLimits) *LimitsCheck {
+	check := NewLimitsCheck()
+	check.platform = func() Platform { return PlatformLinux }
+	check.readLimits = func() (processLimits, error) { return limits, nil }
+	check.systemdBooted = func() bool { return false }
+	check.systemctlShow = func(bool, string, ...string) (map[string]string, error) {
+		return nil, errors.New("no systemd")
+	}
+	check.readSysctl = func(string) (uint64, error) { return 0, errors.New("no sysctl") }
+	check.container = func() containerEnv { return containerEnv{Runtime: runtimeDocker} }
+	return check
+}
+
+func TestConfiguredAgentCount(t *testing.T) {
+	townRoot := setupMailTown(t) // one rig: gastown
+	if got := configuredAgentCount(townRoot); got != 2+10+2 {
+		t.Errorf("configuredAgentCount = %d, want 14 (mayor, deacon, 10 polecats, witness, refinery)", got)
+	}
+
+	wispDir := filepath.Join(townRoot, ".beads-wisp", "config")
+	if err := os.MkdirAll(wispDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	wispCfg := `{"rig":"gastown","values":{"max_polecats":3}}`
+	if err := os.WriteFile(filepath.Join(wispDir, "gastown.json"), []byte(wispCfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := configuredAgentCount(townRoot); got != 2+3+2 {
+		t.Errorf("configuredAgentCount with wisp override = %d, want 7", got)
+	}
+
+	if got := configuredAgentCount(t.TempDir()); got != 2 {
+		t.Errorf("configuredAgentCount without rigs = %d, want 2", got)
+	}
+}
+
+func TestLimitsCheck_Sufficient(t *testing.T) {
+	check := newTestLimitsCheck(processLimits{
+		NOFILESoft: 65536, NOFILEHard: limitUnlimited, NPROCSoft: 63000, NPROCHard: 63000,
+	})
+	result := check.Run(&CheckContext{TownRoot: setupMailTown(t)})
+	if result.Status != StatusOK {
+		t.Errorf("expected OK, got %v: %s %v", result.Status, result.Message, result.Details)
+	}
+}
+
+func TestLimitsCheck_LowSoftLimit(t *testing.T) {
+	check := newTestLimitsCheck(processLimits{
+		NOFILESoft: 1024, NOFILEHard: 524288, NPROCSoft: 63000, NPROCHard: 63000,
+	})
+	result := check.Run(&CheckContext{TownRoot: setupMailTown(t)})
+	if result.Status != StatusWarning {
+		t.Fatalf("expected warning, got %v", result.Status)
+	}
+	if len(result.Details) != 1 || !strings.Contains(result.Details[0], "open files (soft): 1024") {
+		t.Errorf("details = %v, want only the soft nofile limit flagged", result.Details)
+	}
+}
+
+func TestLimitsFor_ConfiguredTargets(t *testing.T) {
+	computed := limitsFor(4, nil)
+	if computed.NOFILE != limitsBaseNOFILE+4*limitsNOFILEPerAgent {
+		t.Errorf("NOFILE = %d, want computed default", computed.NOFILE)
+	}
+
+	req := limitsFor(4, &config.LimitsConfig{NOFILE: 2048, InotifyWatches: 1 << 20})
+	if req.NOFILE != 2048 || req.InotifyWatches != 1<<20 {
+		t.Errorf("configured targets not applied: %+v", req)
+	}
+	if req.NPROC != computed.NPROC {
+		t.Errorf("NPROC = %d, unset target should keep computed %d", req.NPROC, computed.NPROC)
+	}
+}
+
+func TestLimitsCheck_UsesTownTargets(t *testing.T) {
+	townRoot := setupMailTown(t)
+	settings := config.NewTownSettings()
+	settings.Limits = &config.LimitsConfig{NOFILE: 1024, NPROC: 256}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	// A small laptop configured for 1024 files passes with 1024.
+	check := newTestLimitsCheck(processLimits{NOFILESoft: 1024, NOFILEHard: 1024, NPROCSoft: 256, NPROCHard: 256})
+	ctx := &CheckContext{TownRoot: townRoot}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Fatalf("expected OK against configured targets, got %v: %v", result.Status, result.Details)
+	}
+
+	// The fix script raises to the configured values, not the computed ones.
+	check = newTestLimitsCheck(processLimits{NOFILESoft: 512, NOFILEHard: 512})
+	check.Run(ctx)
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(townRoot, ".runtime", limitsFixScriptName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "NOFILE=1024\n") || !strings.Contains(string(data), "hard nofile 1024") {
+		t.Errorf("fix script should use configured targets:\n%s", data)
+	}
+}
+
+func TestLimitsCheck_SkipsUnsupportedPlatform(t *testing.T) {
+	check := newTestLimitsCheck(processLimits{})
+	check.platform = func() Platform { return PlatformOther }
+	if result := check.Run(&CheckContext{TownRoot: t.TempDir()}); result.Status != StatusOK {
+		t.Errorf("expected OK on unsupported platform, got %v", result.Status)
+	}
+}
+
+func TestLimitsCheck_FixWritesScript(t *testing.T) {
+	townRoot := setupMailTown(t)
+	check := newTestLimitsCheck(processLimits{NOFILESoft: 256, NOFILEHard: 256})
+	ctx := &CheckContext{TownRoot: townRoot}
+	check.Run(ctx)
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	path := filepath.Join(townRoot, ".runtime", limitsFixScriptName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("fix script not written: %v", err)
+	}
+	if !strings.Contains(string(data), "/etc/security/limits.d/90-gastown.conf") {
+		t.Errorf("script should configure limits.d:\n%s", data)
+	}
+	if strings.Contains(string(data), "systemctl") {
+		t.Errorf("non-systemd host should not get systemd drop-ins:\n%s", data)
+	}
+
+	// Re-run after Fix points at the generated script
+	if result := check.Run(ctx); !strings.Contains(result.FixHint, path) {
+		t.Errorf("FixHint %q should reference %s", result.FixHint, path)
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := map[string]uint64{
+		"1024":      1024,
+		" 4096\n":   4096,
+		"unlimited": limitUnlimited,
+		"infinity":  limitUnlimited,
+		"max":       limitUnlimited,
+		"":          0,
+		"bogus":     0,
+	}
+	for in, want := range tests {
+		if got := parseLimit(in); got != want {
+			t.Errorf("parseLimit(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
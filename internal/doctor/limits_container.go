@@ -0,0 +1,196 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Container runtimes recognized by detectContainer.
+const (
+	runtimeDocker     = "docker"
+	runtimePodman     = "podman"
+	runtimeKubernetes = "kubernetes"
+	runtimeNspawn     = "systemd-nspawn"
+	runtimeLXC        = "lxc"
+	runtimeUnknown    = "container" // a user namespace with no runtime markers
+)
+
+// containerEnv describes the container (if any) this process runs in.
+type containerEnv struct {
+	Runtime string // one of the runtime* constants, "" on a plain host
+
+	// UserNS is set inside a user namespace: uid 0 here is not root on the
+	// host, so hard limits cannot be raised above those of the namespace
+	// owner and kernel sysctls are read-only.
+	UserNS bool
+
+	// Rootless is set when an unprivileged host user started the container
+	// (rootless podman or docker). Its limits come from that user's login
+	// session on the host.
+	Rootless bool
+}
+
+// detected reports whether this is any kind of container.
+func (e containerEnv) detected() bool {
+	return e.Runtime != ""
+}
+
+// containerFixTarget names the settings the fix script prints for env, for
+// the check's fix hint.
+func containerFixTarget(env containerEnv) string {
+	switch {
+	case env.Rootless:
+		return "rootless " + env.Runtime + " host settings"
+	case env.Runtime == runtimeNspawn:
+		return "systemd-nspawn settings"
+	case env.Runtime == runtimeLXC:
+		return "LXC settings"
+	default:
+		return "docker/Kubernetes settings"
+	}
+}
+
+// detectContainer inspects the running system for container markers.
+func detectContainer() containerEnv {
+	return detectContainerIn("/", os.Getenv)
+}
+
+// detectContainerIn looks for container markers under root (normally "/").
+// Runtimes are recognized by their marker files, the "container" variable
+// systemd-nspawn, podman, and LXC set for PID 1, and the cgroup of PID 1.
+// A non-identity /proc/self/uid_map means a user namespace even when no
+// runtime left a marker.
+func detectContainerIn(root string, getenv func(string) string) containerEnv {
+	var env containerEnv
+	read := func(path string) string {
+		data, err := os.ReadFile(filepath.Join(root, path))
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+	exists := func(path string) bool {
+		_, err := os.Stat(filepath.Join(root, path))
+		return err == nil
+	}
+
+	switch {
+	case getenv("KUBERNETES_SERVICE_HOST") != "":
+		env.Runtime = runtimeKubernetes
+	case exists("run/.containerenv"):
+		env.Runtime = runtimePodman
+		// Podman writes rootless=1 into .containerenv for rootless containers.
+		for _, line := range strings.Split(read("run/.containerenv"), "\n") {
+			if strings.TrimSpace(line) == "rootless=1" {
+				env.Rootless = true
+			}
+		}
+	case exists(".dockerenv"):
+		env.Runtime = runtimeDocker
+	default:
+		name := strings.TrimSpace(read("run/systemd/container"))
+		if name == "" {
+			name = getenv("container")
+		}
+		if name == "" {
+			name = pid1ContainerEnv(read("proc/1/environ"))
+		}
+		if name != "" {
+			env.Runtime = normalizeRuntime(name)
+		} else {
+			env.Runtime = runtimeFromCgroup(read("proc/1/cgroup"))
+		}
+	}
+
+	// Rootless podman and docker map container root onto the single UID of
+	// the host user who started them ("0 1000 1"). Namespaces created by a
+	// root daemon (userns-remap, nspawn -U, unprivileged LXC) map root onto a
+	// subordinate range instead.
+	if m, ok := parseUIDMap(read("proc/self/uid_map")); ok {
+		env.UserNS = true
+		if m.inside == 0 && m.outside != 0 && m.count == 1 {
+			env.Rootless = true
+		}
+	}
+	if env.UserNS && env.Runtime == "" {
+		env.Runtime = runtimeUnknown
+	}
+	return env
+}
+
+// pid1ContainerEnv returns the "container" variable from PID 1's environment
+// (NUL-separated), which is only readable when we share PID 1's user.
+func pid1ContainerEnv(environ string) string {
+	for _, kv := range strings.Split(environ, "\x00") {
+		if v, ok := strings.CutPrefix(kv, "container="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// normalizeRuntime maps the value of the "container" variable or
+// /run/systemd/container to a runtime constant.
+func normalizeRuntime(name string) string {
+	switch name {
+	case "systemd-nspawn":
+		return runtimeNspawn
+	case "podman", "oci":
+		return runtimePodman
+	case "docker":
+		return runtimeDocker
+	case "lxc", "lxc-libvirt":
+		return runtimeLXC
+	default:
+		return runtimeUnknown
+	}
+}
+
+// runtimeFromCgroup guesses the runtime from PID 1's cgroup path.
+func runtimeFromCgroup(cgroup string) string {
+	switch {
+	case strings.Contains(cgroup, "kubepods"):
+		return runtimeKubernetes
+	case strings.Contains(cgroup, "libpod"):
+		return runtimePodman
+	case strings.Contains(cgroup, "docker"), strings.Contains(cgroup, "containerd"):
+		return runtimeDocker
+	case strings.Contains(cgroup, "lxc"):
+		return runtimeLXC
+	case strings.Contains(cgroup, "machine.slice"), strings.Contains(cgroup, "systemd-nspawn@"):
+		return runtimeNspawn
+	default:
+		return ""
+	}
+}
+
+// uidMapping is one line of /proc/<pid>/uid_map.
+type uidMapping struct {
+	inside, outside, count uint64
+}
+
+// parseUIDMap reads the first line of /proc/self/uid_map. The initial user
+// namespace maps the full range "0 0 4294967295"; anything else is a user
+// namespace, reported with ok set.
+func parseUIDMap(uidMap string) (m uidMapping, ok bool) {
+	line, _, _ := strings.Cut(strings.TrimSpace(uidMap), "\n")
+	fields := strings.Fields(line)
+	if len(fields) != 3 {
+		return m, false
+	}
+	var nums [3]uint64
+	for i, f := range fields {
+		n, err := strconv.ParseUint(f, 10, 64)
+		if err != nil {
+			return m, false
+		}
+		nums[i] = n
+	}
+	m = uidMapping{inside: nums[0], outside: nums[1], count: nums[2]}
+	if m == (uidMapping{0, 0, 4294967295}) {
+		return m, false
+	}
+	return m, true
+}
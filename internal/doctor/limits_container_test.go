@@ -0,0 +1,103 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectContainerIn(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		env   map[string]string
+		want  containerEnv
+	}{
+		{
+			name:  "plain host",
+			files: map[string]string{"proc/self/uid_map": "         0          0 4294967295\n", "proc/1/cgroup": "0::/init.scope\n"},
+			want:  containerEnv{},
+		},
+		{
+			name:  "rootful docker",
+			files: map[string]string{".dockerenv": "", "proc/self/uid_map": "0 0 4294967295\n"},
+			want:  containerEnv{Runtime: runtimeDocker},
+		},
+		{
+			name: "rootless podman",
+			files: map[string]string{
+				"run/.containerenv": "engine=\"podman-4.9.3\"\nname=\"gt\"\nrootless=1\n",
+				"proc/self/uid_map": "0 1000 1\n1 100000 65536\n",
+			},
+			want: containerEnv{Runtime: runtimePodman, UserNS: true, Rootless: true},
+		},
+		{
+			name:  "rootless docker",
+			files: map[string]string{".dockerenv": "", "proc/self/uid_map": "0 1000 1\n1 165536 65536\n"},
+			want:  containerEnv{Runtime: runtimeDocker, UserNS: true, Rootless: true},
+		},
+		{
+			name:  "docker userns-remap",
+			files: map[string]string{".dockerenv": "", "proc/self/uid_map": "0 165536 65536\n"},
+			want:  containerEnv{Runtime: runtimeDocker, UserNS: true},
+		},
+		{
+			name:  "systemd-nspawn marker",
+			files: map[string]string{"run/systemd/container": "systemd-nspawn\n"},
+			want:  containerEnv{Runtime: runtimeNspawn},
+		},
+		{
+			name:  "nspawn private users",
+			env:   map[string]string{"container": "systemd-nspawn"},
+			files: map[string]string{"proc/self/uid_map": "0 1879310336 65536\n"},
+			want:  containerEnv{Runtime: runtimeNspawn, UserNS: true},
+		},
+		{
+			name:  "lxc by cgroup",
+			files: map[string]string{"proc/1/cgroup": "0::/lxc.payload.dev/init.scope\n"},
+			want:  containerEnv{Runtime: runtimeLXC},
+		},
+		{
+			name: "kubernetes",
+			env:  map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"},
+			want: containerEnv{Runtime: runtimeKubernetes},
+		},
+		{
+			name:  "bare user namespace",
+			files: map[string]string{"proc/self/uid_map": "0 1000 1\n"},
+			want:  containerEnv{Runtime: runtimeUnknown, UserNS: true, Rootless: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(root, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			getenv := func(k string) string { return tt.env[k] }
+			if got := detectContainerIn(root, getenv); got != tt.want {
+				t.Errorf("detectContainerIn = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUIDMap(t *testing.T) {
+	if _, ok := parseUIDMap("0 0 4294967295"); ok {
+		t.Error("identity map should not be a user namespace")
+	}
+	if _, ok := parseUIDMap(""); ok {
+		t.Error("missing uid_map should not be a user namespace")
+	}
+	m, ok := parseUIDMap("  0  1000  1\n1 100000 65536\n")
+	if !ok || m != (uidMapping{0, 1000, 1}) {
+		t.Errorf("parseUIDMap = %+v, %v", m, ok)
+	}
+}
@@ -0,0 +1,205 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Files the limits fix script installs.
+const (
+	limitsConfPath        = "/etc/security/limits.d/90-gastown.conf"
+	limitsSysctlPath      = "/etc/sysctl.d/90-gastown.conf"
+	limitsSystemDropIn    = "/etc/systemd/system.conf.d/90-gastown-limits.conf"
+	limitsUserDropIn      = "/etc/systemd/user.conf.d/90-gastown-limits.conf"
+	limitsUserServiceDrop = "/etc/systemd/system/user@.service.d/90-gastown-limits.conf"
+	limitsUserSliceDrop   = "/etc/systemd/system/user-.slice.d/90-gastown-limits.conf"
+	limitsLaunchdDir      = "/Library/LaunchDaemons"
+)
+
+// sysctlSetting is a kernel tunable below what the town needs.
+type sysctlSetting struct {
+	Name string
+	Have uint64
+	Need uint64
+}
+
+// limitsFixFile is one system file the fix script writes.
+type limitsFixFile struct {
+	Path    string
+	Content string
+}
+
+// checkSysctl reports system-wide kernel limits below the requirement:
+// fs.file-max caps open files across all processes, kernel.pid_max caps
+// processes and threads, and fs.inotify.max_user_watches caps the file
+// watchers agents hold per user. Unreadable tunables are skipped.
+func (c *LimitsCheck) checkSysctl() []string {
+	c.sysctls = nil
+	needs := []sysctlSetting{
+		{Name: "fs.file-max", Need: c.required.NOFILE},
+		{Name: "kernel.pid_max", Need: c.required.NPROC},
+		{Name: "fs.inotify.max_user_watches", Need: c.required.InotifyWatches},
+	}
+	var details []string
+	for _, s := range needs {
+		have, err := c.readSysctl(s.Name)
+		if err != nil || have == 0 || have >= s.Need {
+			continue
+		}
+		s.Have = have
+		c.sysctls = append(c.sysctls, s)
+		details = append(details, fmt.Sprintf("sysctl %s: %d (need %d)", s.Name, have, s.Need))
+	}
+	return details
+}
+
+// readSysctl reads a kernel tunable from /proc/sys.
+func readSysctl(name string) (uint64, error) {
+	data, err := os.ReadFile(filepath.Join("/proc/sys", strings.ReplaceAll(name, ".", "/")))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// PlanFix reports the system files the fix script would install and how
+// they differ from what's there now, without writing anything.
+func (c *LimitsCheck) PlanFix(ctx *CheckContext) ([]FileChange, error) {
+	platform := c.platform()
+	if platform == PlatformWindows {
+		return nil, fmt.Errorf("no fix script on Windows: restart the processes holding too many handles")
+	}
+	if c.required.Agents == 0 {
+		c.required = limitsFor(configuredAgentCount(ctx.TownRoot), ctx.TownSettings().Limits)
+	}
+
+	note := "written by " + filepath.Join(constants.DirRuntime, limitsFixScriptName) + " when run as root"
+	var changes []FileChange
+	for _, f := range limitsFixFiles(c.required, platform, c.systemd, currentUser(), c.sysctls) {
+		changes = append(changes, plannedFile(f.Path, f.Content, note))
+	}
+	return changes, nil
+}
+
+// limitsFixFiles lists the files that raise limits to the requirement. On
+// systemd hosts these are drop-ins for the manager defaults, user@.service,
+// and the user slice, since pam_limits alone doesn't reach sessions started
+// under systemd; a limits.d entry is still written for console and SSH
+// logins. macOS gets LaunchDaemons that apply launchctl limits at boot.
+// Containers get none: their limits can only be raised from the host.
+func limitsFixFiles(req limitsRequirement, platform Platform, systemd bool, user string, sysctls []sysctlSetting) []limitsFixFile {
+	var files []limitsFixFile
+	switch platform {
+	case PlatformLinuxContainer:
+		return nil
+	case PlatformDarwin:
+		files = append(files,
+			limitsFixFile{path.Join(limitsLaunchdDir, "com.gastown.limit.maxfiles.plist"), launchdLimitPlist("maxfiles", req.NOFILE)},
+			limitsFixFile{path.Join(limitsLaunchdDir, "com.gastown.limit.maxproc.plist"), launchdLimitPlist("maxproc", req.NPROC)})
+	default:
+		files = append(files, limitsFixFile{limitsConfPath, fmt.Sprintf(
+			"%s soft nofile %d\n%s hard nofile %d\n%s soft nproc  %d\n%s hard nproc  %d\n",
+			user, req.NOFILE, user, req.NOFILE, user, req.NPROC, user, req.NPROC)})
+	}
+
+	if len(sysctls) > 0 {
+		var b strings.Builder
+		b.WriteString("# Raised by gt doctor (limits check)\n")
+		for _, s := range sysctls {
+			fmt.Fprintf(&b, "%s = %d\n", s.Name, s.Need)
+		}
+		files = append(files, limitsFixFile{limitsSysctlPath, b.String()})
+	}
+
+	if systemd {
+		manager := fmt.Sprintf("[Manager]\nDefaultLimitNOFILE=%d:%d\nDefaultLimitNPROC=%d:%d\n", req.NOFILE, req.NOFILE, req.NPROC, req.NPROC)
+		files = append(files,
+			limitsFixFile{limitsSystemDropIn, manager},
+			limitsFixFile{limitsUserDropIn, manager},
+			limitsFixFile{limitsUserServiceDrop, fmt.Sprintf("[Service]\nLimitNOFILE=%d:%d\nLimitNPROC=%d:%d\nTasksMax=%d\n",
+				req.NOFILE, req.NOFILE, req.NPROC, req.NPROC, req.NPROC)},
+			limitsFixFile{limitsUserSliceDrop, fmt.Sprintf("[Slice]\nTasksMax=%d\n", req.NPROC)})
+	}
+	return files
+}
+
+// launchdLimitPlist renders a LaunchDaemon that sets a launchctl limit at boot.
+func launchdLimitPlist(resource string, value uint64) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+  <key>Label</key>
+  <string>com.gastown.limit.%[1]s</string>
+  <key>ProgramArguments</key>
+  <array>
+    <string>launchctl</string>
+    <string>limit</string>
+    <string>%[1]s</string>
+    <string>%[2]d</string>
+    <string>%[2]d</string>
+  </array>
+  <key>RunAtLoad</key>
+  <true/>
+</dict>
+</plist>
+`, resource, value)
+}
+
+// limitsFixScript renders a root shell script that installs limitsFixFiles
+// and then applies them (launchctl, sysctl, systemd reload) where that can
+// happen without a reboot. Containers get host-side docker/Kubernetes
+// instructions instead (see writeContainerFixes).
+func limitsFixScript(req limitsRequirement, platform Platform, env containerEnv, systemd bool, user string, sysctls []sysctlSetting) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `#!/bin/sh
+# Generated by 'gt doctor --fix' (limits check).
+# Raises resource limits for %d Gas Town agent session(s).
+# Run as root, then log out and back in (or reboot) so new sessions pick them up.
+# Preview the file changes with 'gt doctor --fix --diff'.
+set -e
+
+NOFILE=%d
+NPROC=%d
+`, req.Agents, req.NOFILE, req.NPROC)
+
+	if platform == PlatformLinuxContainer {
+		writeContainerFixes(&b, req, env, sysctls)
+		return b.String()
+	}
+
+	for _, f := range limitsFixFiles(req, platform, systemd, user, sysctls) {
+		fmt.Fprintf(&b, "\nmkdir -p %s\ncat > %s <<'EOF'\n%sEOF\n", path.Dir(f.Path), f.Path, f.Content)
+	}
+
+	if platform == PlatformDarwin {
+		b.WriteString(`
+# Apply now; the LaunchDaemons above reapply them at every boot
+chown root:wheel /Library/LaunchDaemons/com.gastown.limit.*.plist
+chmod 644 /Library/LaunchDaemons/com.gastown.limit.*.plist
+launchctl limit maxfiles $NOFILE $NOFILE
+launchctl limit maxproc $NPROC $NPROC
+`)
+	}
+	if len(sysctls) > 0 {
+		fmt.Fprintf(&b, "\nsysctl -p %s\n", limitsSysctlPath)
+	}
+	if systemd {
+		b.WriteString(`
+# systemd governs limits for user services and lingering sessions
+systemctl daemon-reexec
+systemctl daemon-reload
+`)
+	}
+
+	b.WriteString(`
+echo "Limits raised (nofile=$NOFILE, nproc=$NPROC). Log out and back in, then run 'gt doctor'."
+`)
+	return b.String()
+}
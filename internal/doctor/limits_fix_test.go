@@ -0,0 +1,89 @@
+package doctor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLimitsCheck_Sysctl(t *testing.T) {
+	check := newTestLimitsCheck(processLimits{NOFILESoft: 1 << 20, NOFILEHard: 1 << 20})
+	check.readSysctl = func(name string) (uint64, error) {
+		if name == "fs.file-max" {
+			return 8192, nil
+		}
+		return 4194304, nil
+	}
+	ctx := &CheckContext{TownRoot: setupMailTown(t)}
+
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("status = %v, want warning", result.Status)
+	}
+	want := fmt.Sprintf("sysctl fs.file-max: 8192 (need %d)", check.required.NOFILE)
+	if len(result.Details) != 1 || result.Details[0] != want {
+		t.Errorf("details = %v, want [%s]", result.Details, want)
+	}
+
+	changes, err := check.PlanFix(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sysctl *FileChange
+	for i := range changes {
+		if changes[i].Path == limitsSysctlPath {
+			sysctl = &changes[i]
+		}
+	}
+	if sysctl == nil {
+		t.Fatalf("PlanFix should include %s: %+v", limitsSysctlPath, changes)
+	}
+	if !strings.Contains(sysctl.After, fmt.Sprintf("fs.file-max = %d", check.required.NOFILE)) ||
+		strings.Contains(sysctl.After, "kernel.pid_max") {
+		t.Errorf("sysctl file should raise only fs.file-max:\n%s", sysctl.After)
+	}
+
+	script := limitsFixScript(check.required, PlatformLinux, containerEnv{}, false, "mayor", check.sysctls)
+	if !strings.Contains(script, "sysctl -p "+limitsSysctlPath) {
+		t.Errorf("script should apply the sysctl file:\n%s", script)
+	}
+}
+
+func TestLimitsFixFiles(t *testing.T) {
+	req := limitsFor(4, nil)
+
+	files := limitsFixFiles(req, PlatformLinux, true, "mayor", nil)
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+	wantPaths := []string{limitsConfPath, limitsSystemDropIn, limitsUserDropIn, limitsUserServiceDrop, limitsUserSliceDrop}
+	if strings.Join(paths, ",") != strings.Join(wantPaths, ",") {
+		t.Errorf("systemd paths = %v, want %v", paths, wantPaths)
+	}
+	if want := fmt.Sprintf("mayor hard nofile %d\n", req.NOFILE); !strings.Contains(files[0].Content, want) {
+		t.Errorf("limits.conf missing %q:\n%s", want, files[0].Content)
+	}
+
+	darwin := limitsFixFiles(req, PlatformDarwin, false, "mayor", nil)
+	if len(darwin) != 2 || !strings.HasSuffix(darwin[0].Path, "com.gastown.limit.maxfiles.plist") {
+		t.Fatalf("darwin files = %+v", darwin)
+	}
+	if want := fmt.Sprintf("<string>%d</string>", req.NOFILE); !strings.Contains(darwin[0].Content, want) {
+		t.Errorf("maxfiles plist missing %q:\n%s", want, darwin[0].Content)
+	}
+
+	if files := limitsFixFiles(req, PlatformLinuxContainer, false, "mayor", nil); len(files) != 0 {
+		t.Errorf("containers should get no files, got %+v", files)
+	}
+}
+
+func TestLimitsFixScript_WritesPlannedFiles(t *testing.T) {
+	req := limitsFor(4, nil)
+	script := limitsFixScript(req, PlatformLinux, containerEnv{}, true, "mayor", nil)
+	for _, f := range limitsFixFiles(req, PlatformLinux, true, "mayor", nil) {
+		if !strings.Contains(script, "cat > "+f.Path+" <<'EOF'\n"+f.Content+"EOF\n") {
+			t.Errorf("script should write %s verbatim:\n%s", f.Path, script)
+		}
+	}
+}
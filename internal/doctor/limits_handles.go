@@ -0,0 +1,81 @@
+package doctor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// limitsHandlesPerProcess is the handle count above which an agent process is
+// reported on Windows. A healthy Claude/node process holds well under a
+// thousand handles; tens of thousands means a leak long before the per-process
+// kernel ceiling (16M) is reached.
+const limitsHandlesPerProcess = 10000
+
+// limitsWatchedProcesses are the executables whose handle counts are checked.
+var limitsWatchedProcesses = []string{"claude.exe", "node.exe", "dolt.exe", "bd.exe", "gt.exe"}
+
+// processHandleCount is one process's open handle count.
+type processHandleCount struct {
+	Name    string
+	PID     uint32
+	Handles uint32
+}
+
+// runHandleCheck is the Windows variant of the limits check. Windows has no
+// per-user descriptor or process limits to raise, so the useful signal is a
+// process whose handle count has grown far past normal.
+func (c *LimitsCheck) runHandleCheck() *CheckResult {
+	procs, err := c.listHandles()
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not read process handle counts",
+			Details: []string{err.Error()},
+		}
+	}
+
+	var watched int
+	var leaking []processHandleCount
+	for _, p := range procs {
+		if !isWatchedProcess(p.Name) {
+			continue
+		}
+		watched++
+		if p.Handles > limitsHandlesPerProcess {
+			leaking = append(leaking, p)
+		}
+	}
+
+	if len(leaking) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("Handle counts normal across %d agent process(es)", watched),
+		}
+	}
+
+	sort.Slice(leaking, func(i, j int) bool { return leaking[i].Handles > leaking[j].Handles })
+	details := make([]string, len(leaking))
+	for i, p := range leaking {
+		details[i] = fmt.Sprintf("%s (pid %d): %d handles (max %d)", p.Name, p.PID, p.Handles, limitsHandlesPerProcess)
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d process(es) holding an unusually large number of handles", len(leaking)),
+		Details: details,
+		FixHint: "Likely a handle leak; restart the affected agent (gt polecat nuke / gt crew restart) or process",
+	}
+}
+
+// isWatchedProcess reports whether an executable name is an agent-related process.
+func isWatchedProcess(name string) bool {
+	for _, w := range limitsWatchedProcesses {
+		if strings.EqualFold(name, w) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package doctor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newTestHandlesCheck(procs []processHandleCount, err error) *LimitsCheck {
+	check := newTestLimitsCheck(processLimits{})
+	check.platform = func() Platform { return PlatformWindows }
+	check.listHandles = func() ([]processHandleCount, error) { return procs, err }
+	return check
+}
+
+func TestLimitsCheck_WindowsHandlesOK(t *testing.T) {
+	check := newTestHandlesCheck([]processHandleCount{
+		{Name: "claude.exe", PID: 10, Handles: 800},
+		{Name: "explorer.exe", PID: 11, Handles: 50000}, // not an agent process
+	}, nil)
+	result := check.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusOK {
+		t.Fatalf("status = %v, want OK: %s %v", result.Status, result.Message, result.Details)
+	}
+	if !strings.Contains(result.Message, "1 agent process") {
+		t.Errorf("message = %q, want count of watched processes", result.Message)
+	}
+}
+
+func TestLimitsCheck_WindowsHandleLeak(t *testing.T) {
+	check := newTestHandlesCheck([]processHandleCount{
+		{Name: "node.exe", PID: 20, Handles: 12000},
+		{Name: "Claude.EXE", PID: 21, Handles: 40000},
+		{Name: "dolt.exe", PID: 22, Handles: 300},
+	}, nil)
+	result := check.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusWarning {
+		t.Fatalf("status = %v, want warning", result.Status)
+	}
+	if len(result.Details) != 2 || !strings.Contains(result.Details[0], "pid 21") {
+		t.Errorf("details = %v, want two leaks, largest first", result.Details)
+	}
+	if err := check.Fix(&CheckContext{TownRoot: t.TempDir()}); err == nil {
+		t.Error("Fix on Windows should return an error")
+	}
+}
+
+func TestLimitsCheck_WindowsHandleError(t *testing.T) {
+	check := newTestHandlesCheck(nil, errors.New("access denied"))
+	result := check.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusWarning || len(result.Details) != 1 {
+		t.Errorf("got %v %v, want warning with the error", result.Status, result.Details)
+	}
+}
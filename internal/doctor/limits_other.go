@@ -0,0 +1,18 @@
+//go:build !linux && !darwin && !windows
+
+package doctor
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// readProcessLimits is not implemented on this platform.
+func readProcessLimits() (processLimits, error) {
+	return processLimits{}, fmt.Errorf("resource limits not supported on %s", runtime.GOOS)
+}
+
+// listProcessHandles is only implemented on Windows.
+func listProcessHandles() ([]processHandleCount, error) {
+	return nil, fmt.Errorf("handle counts not supported on %s", runtime.GOOS)
+}
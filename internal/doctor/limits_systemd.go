@@ -0,0 +1,93 @@
+package doctor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// systemdLimitProbe is one systemd property compared against a requirement.
+type systemdLimitProbe struct {
+	userManager bool   // Query the per-user manager (systemctl --user)
+	unit        string // Unit to inspect; empty for the manager itself
+	label       string // Human-readable scope for details
+	props       []string
+	nofile      bool // true: compare against NOFILE, false: against NPROC
+}
+
+// systemdLimitProbes lists where systemd caps what agent sessions inherit:
+// the system manager defaults, the user@UID.service that runs the user
+// manager, the user manager's own defaults (tmux under systemd --user),
+// and the logind user slice's TasksMax, which counts every thread.
+func systemdLimitProbes(uid int) []systemdLimitProbe {
+	userService := fmt.Sprintf("user@%d.service", uid)
+	userSlice := fmt.Sprintf("user-%d.slice", uid)
+	return []systemdLimitProbe{
+		{label: "system.conf", props: []string{"DefaultLimitNOFILESoft", "DefaultLimitNOFILE"}, nofile: true},
+		{label: "system.conf", props: []string{"DefaultLimitNPROCSoft", "DefaultLimitNPROC"}},
+		{unit: userService, label: userService, props: []string{"LimitNOFILESoft", "LimitNOFILE"}, nofile: true},
+		{unit: userService, label: userService, props: []string{"LimitNPROCSoft", "LimitNPROC", "TasksMax"}},
+		{userManager: true, label: "user.conf", props: []string{"DefaultLimitNOFILESoft", "DefaultLimitNOFILE"}, nofile: true},
+		{unit: userSlice, label: userSlice, props: []string{"TasksMax"}},
+	}
+}
+
+// checkSystemd reports systemd limits below the cached requirement.
+// Probes that can't be queried (no user manager, unit not loaded) are skipped.
+func (c *LimitsCheck) checkSystemd() []string {
+	var details []string
+	for _, probe := range systemdLimitProbes(os.Getuid()) {
+		values, err := c.systemctlShow(probe.userManager, probe.unit, probe.props...)
+		if err != nil {
+			continue
+		}
+		need := c.required.NPROC
+		if probe.nofile {
+			need = c.required.NOFILE
+		}
+		for _, prop := range probe.props {
+			details = append(details, checkLimit("systemd "+probe.label+" "+prop, parseLimit(values[prop]), need)...)
+		}
+	}
+	return details
+}
+
+// systemdBooted reports whether systemd is the init system (sd_booted).
+func systemdBooted() bool {
+	_, err := os.Stat("/run/systemd/system")
+	return err == nil
+}
+
+// systemctlShow returns the requested properties of a unit (or of the
+// manager when unit is empty) as reported by 'systemctl show'.
+func systemctlShow(userManager bool, unit string, props ...string) (map[string]string, error) {
+	args := []string{"show"}
+	if userManager {
+		args = []string{"--user", "show"}
+	}
+	if unit != "" {
+		args = append(args, unit)
+	}
+	for _, p := range props {
+		args = append(args, "--property="+p)
+	}
+	out, err := exec.Command("systemctl", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("systemctl %s: %w", strings.Join(args, " "), err)
+	}
+	return parseSystemctlShow(string(out)), nil
+}
+
+// parseSystemctlShow parses KEY=VALUE lines from 'systemctl show'.
+func parseSystemctlShow(out string) map[string]string {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), "="); ok {
+			values[key] = value
+		}
+	}
+	return values
+}
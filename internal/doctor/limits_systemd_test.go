@@ -0,0 +1,77 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseSystemctlShow(t *testing.T) {
+	values := parseSystemctlShow("LimitNOFILE=524288\nLimitNOFILESoft=1024\nTasksMax=infinity\n")
+	if values["LimitNOFILE"] != "524288" || values["LimitNOFILESoft"] != "1024" || values["TasksMax"] != "infinity" {
+		t.Errorf("parseSystemctlShow = %v", values)
+	}
+}
+
+func TestLimitsCheck_SystemdUserService(t *testing.T) {
+	userService := fmt.Sprintf("user@%d.service", os.Getuid())
+	check := newTestLimitsCheck(processLimits{
+		NOFILESoft: 65536, NOFILEHard: 65536, NPROCSoft: 63000, NPROCHard: 63000,
+	})
+	check.systemdBooted = func() bool { return true }
+	check.systemctlShow = func(userManager bool, unit string, props ...string) (map[string]string, error) {
+		values := make(map[string]string)
+		for _, p := range props {
+			values[p] = "infinity"
+		}
+		if unit == userService {
+			values["LimitNOFILESoft"] = "1024"
+		}
+		if userManager {
+			return nil, fmt.Errorf("no user manager")
+		}
+		return values, nil
+	}
+
+	townRoot := setupMailTown(t)
+	ctx := &CheckContext{TownRoot: townRoot}
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("expected warning for low user@.service limit, got %v", result.Status)
+	}
+	want := "systemd " + userService + " LimitNOFILESoft: 1024"
+	if len(result.Details) != 1 || !strings.Contains(result.Details[0], want) {
+		t.Errorf("details = %v, want %q", result.Details, want)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(check.scriptPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"/etc/systemd/system/user@.service.d/90-gastown-limits.conf",
+		"/etc/systemd/system.conf.d",
+		fmt.Sprintf("DefaultLimitNOFILE=%d:%d", check.required.NOFILE, check.required.NOFILE),
+		fmt.Sprintf("TasksMax=%d", check.required.NPROC),
+		"systemctl daemon-reload",
+		"/etc/security/limits.d/90-gastown.conf",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("fix script missing %q", want)
+		}
+	}
+}
+
+func TestLimitsFixScript_Darwin(t *testing.T) {
+	script := limitsFixScript(limitsFor(4, nil), PlatformDarwin, containerEnv{}, false, "mayor", nil)
+	if !strings.Contains(script, "launchctl limit maxfiles") {
+		t.Errorf("darwin script should use launchctl:\n%s", script)
+	}
+	if strings.Contains(script, "limits.d") {
+		t.Errorf("darwin script should not write limits.d:\n%s", script)
+	}
+}
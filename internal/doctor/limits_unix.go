@@ -0,0 +1,70 @@
+//go:build linux || darwin
+
+package doctor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// readProcessLimits returns the limits agent sessions inherit from gt.
+//
+// The Go runtime raises its own soft NOFILE limit to the hard limit at
+// startup but restores the original for child processes, so the soft value
+// is read from a child shell rather than from Getrlimit.
+func readProcessLimits() (processLimits, error) {
+	var limits processLimits
+
+	var rl syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rl); err != nil {
+		return limits, fmt.Errorf("getrlimit(NOFILE): %w", err)
+	}
+	limits.NOFILEHard = normalizeRlimit(rl.Max)
+	limits.NOFILESoft = limits.NOFILEHard
+	if out, err := exec.Command("sh", "-c", "ulimit -n").Output(); err == nil {
+		if v := parseLimit(string(out)); v != 0 {
+			limits.NOFILESoft = v
+		}
+	}
+
+	// Max processes is only exposed via /proc on Linux.
+	if f, err := os.Open("/proc/self/limits"); err == nil {
+		defer f.Close()
+		limits.NPROCSoft, limits.NPROCHard = parseProcLimits(bufio.NewScanner(f), "Max processes")
+	}
+	return limits, nil
+}
+
+// normalizeRlimit maps RLIM_INFINITY (all ones on Linux, 1<<63-1 on macOS)
+// to limitUnlimited.
+func normalizeRlimit(v uint64) uint64 {
+	if v >= 1<<63-1 {
+		return limitUnlimited
+	}
+	return v
+}
+
+// parseProcLimits extracts the soft and hard values of one row of
+// /proc/<pid>/limits ("Max processes  63211  63211  processes").
+func parseProcLimits(scanner *bufio.Scanner, name string) (soft, hard uint64) {
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, name) {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, name))
+		if len(fields) >= 2 {
+			return parseLimit(fields[0]), parseLimit(fields[1])
+		}
+	}
+	return 0, 0
+}
+
+// listProcessHandles is only implemented on Windows; Unix uses rlimits.
+func listProcessHandles() ([]processHandleCount, error) {
+	return nil, fmt.Errorf("handle counts are only checked on Windows")
+}
@@ -0,0 +1,68 @@
+//go:build windows
+
+package doctor
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// windowsMaxHandles is the per-process kernel handle ceiling (2^24).
+const windowsMaxHandles = 1 << 24
+
+var procGetProcessHandleCount = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetProcessHandleCount")
+
+// readProcessLimits reports Windows' fixed per-process handle ceiling.
+// There is no per-user process limit to report.
+func readProcessLimits() (processLimits, error) {
+	return processLimits{NOFILESoft: windowsMaxHandles, NOFILEHard: windowsMaxHandles}, nil
+}
+
+// listProcessHandles returns the handle count of every process this user
+// can query, via a Toolhelp snapshot and GetProcessHandleCount.
+func listProcessHandles() ([]processHandleCount, error) {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, fmt.Errorf("process snapshot: %w", err)
+	}
+	defer func() { _ = windows.CloseHandle(snapshot) }()
+
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	if err := windows.Process32First(snapshot, &entry); err != nil {
+		return nil, fmt.Errorf("enumerating processes: %w", err)
+	}
+
+	var procs []processHandleCount
+	for {
+		if count, ok := processHandleCountFor(entry.ProcessID); ok {
+			procs = append(procs, processHandleCount{
+				Name:    windows.UTF16ToString(entry.ExeFile[:]),
+				PID:     entry.ProcessID,
+				Handles: count,
+			})
+		}
+		if err := windows.Process32Next(snapshot, &entry); err != nil {
+			break // ERROR_NO_MORE_FILES
+		}
+	}
+	return procs, nil
+}
+
+// processHandleCountFor returns a process's handle count. Processes owned by
+// other users (or protected) can't be opened and are skipped.
+func processHandleCountFor(pid uint32) (uint32, bool) {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, pid)
+	if err != nil {
+		return 0, false
+	}
+	defer func() { _ = windows.CloseHandle(handle) }()
+
+	var count uint32
+	if r, _, _ := procGetProcessHandleCount.Call(uintptr(handle), uintptr(unsafe.Pointer(&count))); r == 0 {
+		return 0, false
+	}
+	return count, true
+}
@@ -0,0 +1,213 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// mailUndeliverableSampleSize caps how many undeliverable messages are listed.
+const mailUndeliverableSampleSize = 5
+
+// MailCheck inspects the mailboxes of every agent (mayor, deacon, witnesses,
+// polecats) and reports:
+//   - inboxes whose unread backlog exceeds mail_health.max_unread
+//   - inboxes whose oldest unread message is older than mail_health.max_unread_age
+//   - open messages addressed to agents that no longer exist (undeliverable)
+//
+// Thresholds come from the mail_health section of settings/config.json.
+type MailCheck struct {
+	BaseCheck
+
+	// Injected for testing. Returns all open direct messages in the town.
+	listMessages func(townRoot string) ([]*mail.Message, error)
+	now          func() time.Time
+}
+
+// mailboxStats summarizes one agent's unread mail.
+type mailboxStats struct {
+	address string
+	unread  int
+	oldest  time.Time
+}
+
+// NewMailCheck creates a new mail system health check.
+func NewMailCheck() *MailCheck {
+	return &MailCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "mail-health",
+			CheckDescription: "Check agent inbox backlogs and undeliverable mail",
+			CheckCategory:    CategoryInfrastructure,
+		},
+		listMessages: mail.ListOpenMessages,
+		now:          time.Now,
+	}
+}
+
+// Run lists open mail once and buckets it by recipient.
+func (c *MailCheck) Run(ctx *CheckContext) *CheckResult {
+	cfg := config.DefaultMailHealthConfig()
+	if ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(ctx.TownRoot)); err == nil && ts.MailHealth != nil {
+		cfg = ts.MailHealth
+	}
+	maxUnread := cfg.MaxUnread
+	if maxUnread <= 0 {
+		maxUnread = config.DefaultMailHealthConfig().MaxUnread
+	}
+	maxAge := config.ParseDurationOrDefault(cfg.MaxUnreadAge, 24*time.Hour)
+
+	messages, err := c.listMessages(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not list mail",
+			Details: []string{err.Error()},
+			FixHint: "Check that bd is installed and the Dolt server is running",
+		}
+	}
+
+	agents, recipients := mailAgents(ctx.TownRoot)
+	stats := make(map[string]*mailboxStats, len(agents))
+	for _, addr := range agents {
+		stats[mail.AddressToIdentity(addr)] = &mailboxStats{address: addr}
+	}
+
+	var undeliverable []*mail.Message
+	for _, msg := range messages {
+		identity := mail.AddressToIdentity(msg.To)
+		if !isKnownMailRecipient(identity, recipients) {
+			undeliverable = append(undeliverable, msg)
+			continue
+		}
+		s, ok := stats[identity]
+		if !ok || msg.Read {
+			continue // Recipient exists but isn't an inspected agent (crew, refinery, overseer)
+		}
+		s.unread++
+		if s.oldest.IsZero() || msg.Timestamp.Before(s.oldest) {
+			s.oldest = msg.Timestamp
+		}
+	}
+
+	now := c.now()
+	var details []string
+	totalUnread := 0
+	for _, addr := range agents {
+		s := stats[mail.AddressToIdentity(addr)]
+		totalUnread += s.unread
+		if s.unread == 0 {
+			continue
+		}
+		age := now.Sub(s.oldest)
+		var problems []string
+		if s.unread > maxUnread {
+			problems = append(problems, fmt.Sprintf("%d unread (max %d)", s.unread, maxUnread))
+		}
+		if age > maxAge {
+			problems = append(problems, fmt.Sprintf("oldest unread %s old (max %s)", age.Round(time.Minute), maxAge))
+		}
+		if len(problems) > 0 {
+			details = append(details, fmt.Sprintf("%s: %s", s.address, strings.Join(problems, ", ")))
+		}
+	}
+
+	if len(undeliverable) > 0 {
+		sort.Slice(undeliverable, func(i, j int) bool {
+			return undeliverable[i].Timestamp.Before(undeliverable[j].Timestamp)
+		})
+		details = append(details, fmt.Sprintf("%d undeliverable message(s) to unknown agents:", len(undeliverable)))
+		for i, msg := range undeliverable {
+			if i == mailUndeliverableSampleSize {
+				details = append(details, fmt.Sprintf("  ... and %d more", len(undeliverable)-i))
+				break
+			}
+			details = append(details, fmt.Sprintf("  %s → %s: %s", msg.ID, msg.To, msg.Subject))
+		}
+	}
+
+	if len(details) > 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Mail backlog or delivery problems (%d unread across %d agent(s))", totalUnread, len(agents)),
+			Details: details,
+			FixHint: "Nudge backlogged agents to run 'gt mail inbox'; archive undeliverable mail with 'gt mail archive <id>'",
+		}
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: fmt.Sprintf("%d unread message(s) across %d agent(s), all within thresholds", totalUnread, len(agents)),
+	}
+}
+
+// mailAgents returns the addresses whose inboxes are inspected (mayor, deacon,
+// each rig's witness and polecats) and the identity set of every agent that
+// can receive mail, which additionally covers refineries, crew, and the overseer.
+// The recipient set is nil when rigs.json is unreadable, which disables the
+// undeliverable check rather than flagging every rig message.
+func mailAgents(townRoot string) ([]string, map[string]bool) {
+	agents := []string{"mayor/", "deacon/"}
+
+	cfg, err := loadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return agents, nil
+	}
+	recipients := map[string]bool{
+		"overseer": true,
+		"mayor/":   true,
+		"deacon/":  true,
+	}
+
+	rigNames := make([]string, 0, len(cfg.Rigs))
+	for name := range cfg.Rigs {
+		rigNames = append(rigNames, name)
+	}
+	sort.Strings(rigNames)
+
+	for _, rigName := range rigNames {
+		agents = append(agents, rigName+"/witness")
+		recipients[rigName+"/witness"] = true
+		recipients[rigName+"/refinery"] = true
+		recipients[rigName] = true // Rig broadcast
+
+		for _, name := range listAgentDirs(filepath.Join(townRoot, rigName, "polecats")) {
+			agents = append(agents, rigName+"/polecats/"+name)
+			recipients[rigName+"/"+name] = true
+		}
+		for _, name := range listAgentDirs(filepath.Join(townRoot, rigName, "crew")) {
+			recipients[rigName+"/"+name] = true
+		}
+	}
+	return agents, recipients
+}
+
+// isKnownMailRecipient reports whether a normalized identity names a live agent.
+// Anything under deacon/ (dogs) is treated as known, as is everything when the
+// recipient set is unavailable.
+func isKnownMailRecipient(identity string, recipients map[string]bool) bool {
+	return recipients == nil || recipients[identity] || strings.HasPrefix(identity, "deacon/")
+}
+
+// listAgentDirs returns the non-hidden subdirectories of dir.
+func listAgentDirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
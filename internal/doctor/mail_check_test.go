@@ -0,0 +1,126 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// setupMailTown creates a town with one rig (gastown) holding polecat Toast
+// and crew member max.
+func setupMailTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	for _, dir := range []string{
+		"mayor",
+		"settings",
+		"gastown/polecats/Toast",
+		"gastown/crew/max",
+	} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rigs := `{"version":1,"rigs":{"gastown":{"git_url":"x"}}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte(rigs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot
+}
+
+func newTestMailCheck(msgs []*mail.Message, now time.Time) *MailCheck {
+	check := NewMailCheck()
+	check.listMessages = func(string) ([]*mail.Message, error) { return msgs, nil }
+	check.now = func() time.Time { return now }
+	return check
+}
+
+func TestMailCheck_Healthy(t *testing.T) {
+	townRoot := setupMailTown(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	check := newTestMailCheck([]*mail.Message{
+		{ID: "hq-1", To: "mayor/", Subject: "hi", Timestamp: now.Add(-time.Hour)},
+		{ID: "gt-1", To: "gastown/Toast", Subject: "work", Timestamp: now.Add(-time.Hour)},
+		{ID: "gt-2", To: "gastown/max", Subject: "crew", Timestamp: now.Add(-time.Hour)},
+		{ID: "gt-3", To: "gastown/refinery", Subject: "merge", Timestamp: now.Add(-48 * time.Hour)},
+	}, now)
+
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusOK {
+		t.Fatalf("expected OK, got %v: %s %v", result.Status, result.Message, result.Details)
+	}
+	if !strings.Contains(result.Message, "2 unread") {
+		t.Errorf("message %q should count unread mail of inspected agents only", result.Message)
+	}
+}
+
+func TestMailCheck_BacklogAgeAndUndeliverable(t *testing.T) {
+	townRoot := setupMailTown(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var msgs []*mail.Message
+	for i := 0; i < 21; i++ {
+		msgs = append(msgs, &mail.Message{ID: "hq-m", To: "mayor/", Timestamp: now.Add(-time.Minute)})
+	}
+	msgs = append(msgs,
+		&mail.Message{ID: "gt-old", To: "gastown/witness", Subject: "old", Timestamp: now.Add(-30 * time.Hour)},
+		&mail.Message{ID: "gt-read", To: "gastown/Toast", Read: true, Timestamp: now.Add(-90 * time.Hour)},
+		&mail.Message{ID: "gt-gone", To: "gastown/Nux", Subject: "to nuked polecat", Timestamp: now.Add(-time.Hour)},
+		&mail.Message{ID: "xx-1", To: "oldrig/witness", Subject: "to removed rig", Timestamp: now.Add(-time.Hour)},
+	)
+
+	result := newTestMailCheck(msgs, now).Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusWarning {
+		t.Fatalf("expected warning, got %v: %s", result.Status, result.Message)
+	}
+	joined := strings.Join(result.Details, "\n")
+	for _, want := range []string{
+		"mayor/: 21 unread (max 20)",
+		"gastown/witness: oldest unread 30h0m0s old",
+		"2 undeliverable",
+		"gt-gone",
+		"xx-1",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("details missing %q:\n%s", want, joined)
+		}
+	}
+	if strings.Contains(joined, "gastown/polecats/Toast") {
+		t.Errorf("read mail should not count toward backlog:\n%s", joined)
+	}
+}
+
+func TestMailCheck_TownThresholds(t *testing.T) {
+	townRoot := setupMailTown(t)
+	settings := `{"type":"town-settings","version":1,"mail_health":{"max_unread":1,"max_unread_age":"10m"}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	check := newTestMailCheck([]*mail.Message{
+		{ID: "gt-1", To: "gastown/Toast", Timestamp: now.Add(-20 * time.Minute)},
+	}, now)
+
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusWarning {
+		t.Fatalf("expected warning with tightened thresholds, got %v: %s", result.Status, result.Message)
+	}
+	if len(result.Details) != 1 || !strings.Contains(result.Details[0], "max 10m0s") {
+		t.Errorf("details = %v, want age violation against 10m", result.Details)
+	}
+}
+
+func TestMailCheck_ListError(t *testing.T) {
+	check := NewMailCheck()
+	check.listMessages = func(string) ([]*mail.Message, error) { return nil, errors.New("bd down") }
+
+	result := check.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusWarning {
+		t.Errorf("expected warning when mail can't be listed, got %v", result.Status)
+	}
+}
@@ -0,0 +1,241 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/procfs"
+)
+
+// Memory pressure thresholds. PSI "some" is the share of time at least one
+// task stalled on memory; "full" is the share of time all non-idle tasks did,
+// which on a desktop-class box means the system is thrashing.
+const (
+	memoryAvailableWarnPct = 10.0
+	memoryAvailableErrPct  = 5.0
+	memoryPSISomeWarn      = 10.0 // avg60 %
+	memoryPSIFullErr       = 5.0  // avg60 %
+	memorySwapUsedWarnPct  = 50.0
+	memoryTopProcesses     = 5
+)
+
+// memoryInfo is the subset of /proc/meminfo the check uses, in bytes.
+type memoryInfo struct {
+	Total     uint64
+	Available uint64
+	SwapTotal uint64
+	SwapFree  uint64
+}
+
+// memoryPressure holds PSI avg60 values from /proc/pressure/memory.
+type memoryPressure struct {
+	SomeAvg60 float64
+	FullAvg60 float64
+}
+
+// MemoryCheck warns when the host is close to running out of memory, before
+// the OOM killer starts taking out agents. It reads /proc/meminfo, the PSI
+// memory pressure file (when the kernel has PSI), and the resident size of
+// the town's processes to show who is using the memory.
+type MemoryCheck struct {
+	BaseCheck
+
+	// Injected for testing.
+	goos         string
+	readMeminfo  func() (memoryInfo, error)
+	readPressure func() (memoryPressure, error)
+	scan         func() ([]townProcess, error)
+}
+
+// NewMemoryCheck creates a new memory pressure check.
+func NewMemoryCheck() *MemoryCheck {
+	return &MemoryCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "memory",
+			CheckDescription: "Check memory headroom, pressure, and swap thrashing",
+			CheckCategory:    CategoryInfrastructure,
+		},
+		goos:         runtime.GOOS,
+		readMeminfo:  readMeminfo,
+		readPressure: readMemoryPressure,
+		scan:         scanTownProcesses,
+	}
+}
+
+// Run grades memory headroom and pressure.
+func (c *MemoryCheck) Run(ctx *CheckContext) *CheckResult {
+	if c.goos != "linux" {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("Memory check not supported on %s (skipped)", c.goos),
+		}
+	}
+
+	mem, err := c.readMeminfo()
+	if err != nil || mem.Total == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not read /proc/meminfo",
+			Details: errDetails(err),
+		}
+	}
+
+	status := StatusOK
+	raise := func(s CheckStatus) {
+		if s > status {
+			status = s
+		}
+	}
+	var problems []string
+
+	availPct := 100 * float64(mem.Available) / float64(mem.Total)
+	switch {
+	case availPct < memoryAvailableErrPct:
+		raise(StatusError)
+		problems = append(problems, fmt.Sprintf("only %s available (%.1f%% of %s)", formatBytes(int64(mem.Available)), availPct, formatBytes(int64(mem.Total))))
+	case availPct < memoryAvailableWarnPct:
+		raise(StatusWarning)
+		problems = append(problems, fmt.Sprintf("only %s available (%.1f%% of %s)", formatBytes(int64(mem.Available)), availPct, formatBytes(int64(mem.Total))))
+	}
+	if mem.Available < limitsMemoryPerAgent {
+		raise(StatusWarning)
+		problems = append(problems, fmt.Sprintf("less than one agent's worth of memory free (%s per agent)", formatBytes(limitsMemoryPerAgent)))
+	}
+
+	swapUsedPct := 0.0
+	if mem.SwapTotal > 0 {
+		swapUsedPct = 100 * float64(mem.SwapTotal-mem.SwapFree) / float64(mem.SwapTotal)
+	}
+
+	// PSI is absent on older kernels and some containers; skip silently.
+	if psi, err := c.readPressure(); err == nil {
+		switch {
+		case psi.FullAvg60 >= memoryPSIFullErr:
+			raise(StatusError)
+			problems = append(problems, fmt.Sprintf("thrashing: all tasks stalled on memory %.1f%% of the last minute", psi.FullAvg60))
+		case psi.SomeAvg60 >= memoryPSISomeWarn:
+			raise(StatusWarning)
+			problems = append(problems, fmt.Sprintf("memory pressure: tasks stalled on memory %.1f%% of the last minute", psi.SomeAvg60))
+		}
+		if swapUsedPct >= memorySwapUsedWarnPct && psi.SomeAvg60 > 0 {
+			raise(StatusWarning)
+			problems = append(problems, fmt.Sprintf("swap %.0f%% used while under memory pressure", swapUsedPct))
+		}
+	}
+
+	var details []string
+	details = append(details, problems...)
+	if status != StatusOK {
+		details = append(details, c.topProcesses()...)
+	}
+
+	if status == StatusOK {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("%s of %s available (%.0f%%)", formatBytes(int64(mem.Available)), formatBytes(int64(mem.Total)), availPct),
+		}
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  status,
+		Message: "Memory is running low; agents risk being OOM-killed",
+		Details: details,
+		FixHint: "Lower max_polecats (gt rig config set <rig> max_polecats N), stop idle crew, or add memory/swap",
+	}
+}
+
+// topProcesses summarizes the town's memory use and its largest processes.
+func (c *MemoryCheck) topProcesses() []string {
+	procs, err := c.scan()
+	if err != nil || len(procs) == 0 {
+		return nil
+	}
+	var total uint64
+	for _, p := range procs {
+		total += p.RSSBytes
+	}
+	lines := []string{fmt.Sprintf("town processes: %d using %s RSS", len(procs), formatBytes(int64(total)))}
+	for i, p := range procs {
+		if i == memoryTopProcesses {
+			break
+		}
+		lines = append(lines, fmt.Sprintf("  %s (pid %d): %s", p.Name, p.PID, formatBytes(int64(p.RSSBytes))))
+	}
+	return lines
+}
+
+// errDetails wraps an error as result details, if any.
+func errDetails(err error) []string {
+	if err == nil {
+		return nil
+	}
+	return []string{err.Error()}
+}
+
+// readMeminfo reads /proc/meminfo.
+func readMeminfo() (memoryInfo, error) {
+	data, err := os.ReadFile(filepath.Join(procRoot, "meminfo"))
+	if err != nil {
+		return memoryInfo{}, err
+	}
+	return parseMeminfo(string(data)), nil
+}
+
+// parseMeminfo extracts the fields the check uses from /proc/meminfo.
+func parseMeminfo(content string) memoryInfo {
+	return memoryInfo{
+		Total:     procfs.StatusKB(content, "MemTotal") * 1024,
+		Available: procfs.StatusKB(content, "MemAvailable") * 1024,
+		SwapTotal: procfs.StatusKB(content, "SwapTotal") * 1024,
+		SwapFree:  procfs.StatusKB(content, "SwapFree") * 1024,
+	}
+}
+
+// readMemoryPressure reads /proc/pressure/memory.
+func readMemoryPressure() (memoryPressure, error) {
+	data, err := os.ReadFile(filepath.Join(procRoot, "pressure", "memory"))
+	if err != nil {
+		return memoryPressure{}, err
+	}
+	return parsePressure(string(data))
+}
+
+// parsePressure parses a PSI file ("some avg10=0.00 avg60=1.23 ...").
+func parsePressure(content string) (memoryPressure, error) {
+	var psi memoryPressure
+	found := false
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		for _, f := range fields[1:] {
+			v, ok := strings.CutPrefix(f, "avg60=")
+			if !ok {
+				continue
+			}
+			avg, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return psi, fmt.Errorf("parsing PSI %q: %w", line, err)
+			}
+			switch fields[0] {
+			case "some":
+				psi.SomeAvg60 = avg
+				found = true
+			case "full":
+				psi.FullAvg60 = avg
+			}
+		}
+	}
+	if !found {
+		return psi, fmt.Errorf("no PSI data")
+	}
+	return psi, nil
+}
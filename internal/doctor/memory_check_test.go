@@ -0,0 +1,121 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const gib = 1 << 30
+
+func newTestMemoryCheck(mem memoryInfo, psi memoryPressure, psiErr error) *MemoryCheck {
+	check := NewMemoryCheck()
+	check.goos = "linux"
+	check.readMeminfo = func() (memoryInfo, error) { return mem, nil }
+	check.readPressure = func() (memoryPressure, error) { return psi, psiErr }
+	check.scan = func() ([]townProcess, error) {
+		return []townProcess{{PID: 42, Name: "claude", RSSBytes: gib}, {PID: 7, Name: "dolt", RSSBytes: gib / 2}}, nil
+	}
+	return check
+}
+
+func TestMemoryCheck_Grades(t *testing.T) {
+	tests := []struct {
+		name string
+		mem  memoryInfo
+		psi  memoryPressure
+		want CheckStatus
+	}{
+		{"plenty", memoryInfo{Total: 16 * gib, Available: 8 * gib}, memoryPressure{}, StatusOK},
+		{"low available", memoryInfo{Total: 16 * gib, Available: 1 * gib}, memoryPressure{}, StatusWarning},
+		{"critically low", memoryInfo{Total: 16 * gib, Available: gib / 2}, memoryPressure{}, StatusError},
+		{"some pressure", memoryInfo{Total: 16 * gib, Available: 8 * gib}, memoryPressure{SomeAvg60: 15}, StatusWarning},
+		{"thrashing", memoryInfo{Total: 16 * gib, Available: 8 * gib}, memoryPressure{SomeAvg60: 40, FullAvg60: 12}, StatusError},
+		{"swap under pressure", memoryInfo{Total: 16 * gib, Available: 8 * gib, SwapTotal: 4 * gib, SwapFree: gib}, memoryPressure{SomeAvg60: 1}, StatusWarning},
+	}
+	for _, tt := range tests {
+		result := newTestMemoryCheck(tt.mem, tt.psi, nil).Run(&CheckContext{})
+		if result.Status != tt.want {
+			t.Errorf("%s: status = %v, want %v (%s %v)", tt.name, result.Status, tt.want, result.Message, result.Details)
+		}
+		if tt.want != StatusOK && !strings.Contains(strings.Join(result.Details, "\n"), "claude (pid 42)") {
+			t.Errorf("%s: details should list the largest town processes: %v", tt.name, result.Details)
+		}
+	}
+}
+
+func TestMemoryCheck_NoPSI(t *testing.T) {
+	result := newTestMemoryCheck(memoryInfo{Total: 16 * gib, Available: 8 * gib}, memoryPressure{}, errors.New("no such file")).Run(&CheckContext{})
+	if result.Status != StatusOK {
+		t.Errorf("status = %v, want OK when PSI is unavailable", result.Status)
+	}
+}
+
+func TestMemoryCheck_SkipsNonLinux(t *testing.T) {
+	check := NewMemoryCheck()
+	check.goos = "darwin"
+	if result := check.Run(&CheckContext{}); result.Status != StatusOK || !strings.Contains(result.Message, "skipped") {
+		t.Errorf("got %v %q, want skipped", result.Status, result.Message)
+	}
+}
+
+func TestParseMeminfoAndPressure(t *testing.T) {
+	mem := parseMeminfo("MemTotal:        6147400 kB\nMemFree:         2285872 kB\nMemAvailable:    5424240 kB\nSwapTotal:       0 kB\n")
+	if mem.Total != 6147400*1024 || mem.Available != 5424240*1024 || mem.SwapTotal != 0 {
+		t.Errorf("parseMeminfo = %+v", mem)
+	}
+
+	psi, err := parsePressure("some avg10=1.00 avg60=2.50 avg300=0.10 total=100\nfull avg10=0.00 avg60=0.75 avg300=0.00 total=5\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if psi.SomeAvg60 != 2.5 || psi.FullAvg60 != 0.75 {
+		t.Errorf("parsePressure = %+v", psi)
+	}
+	if _, err := parsePressure(""); err == nil {
+		t.Error("expected error for empty PSI file")
+	}
+}
+
+func TestScanTownProcessesIn(t *testing.T) {
+	root := t.TempDir()
+	write := func(pid, comm, cmdline string, rssKB int) {
+		dir := filepath.Join(root, pid)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		files := map[string]string{
+			"comm":    comm + "\n",
+			"cmdline": cmdline,
+			"status":  "Name:\t" + comm + "\nVmRSS:\t" + strings.Repeat(" ", 4) + strconv.Itoa(rssKB) + " kB\n",
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	write("100", "claude", "claude\x00--dangerously-skip-permissions", 2048)
+	write("101", "node", "node\x00/usr/lib/claude/cli.js", 4096)
+	write("102", "node", "node\x00server.js", 8192) // not an agent
+	write("103", "bash", "bash", 100)
+	write("104", "dolt", "dolt\x00sql-server", 1024)
+	if err := os.WriteFile(filepath.Join(root, "meminfo"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	procs, err := scanTownProcessesIn(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range procs {
+		got = append(got, p.Name+":"+strconv.Itoa(int(p.RSSBytes/1024)))
+	}
+	if want := "node:4096 claude:2048 dolt:1024"; strings.Join(got, " ") != want {
+		t.Errorf("scan = %q, want %q (sorted by RSS)", strings.Join(got, " "), want)
+	}
+}
@@ -0,0 +1,81 @@
+package doctor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/procfs"
+)
+
+// procRoot is where the Linux process filesystem is mounted.
+const procRoot = procfs.Root
+
+// townProcessNames are the executables that make up a running town.
+var townProcessNames = map[string]bool{
+	"claude":       true,
+	"claude-code":  true,
+	"codex":        true,
+	"gt":           true,
+	"bd":           true,
+	"dolt":         true,
+	"tmux":         true,
+	"tmux: server": true,
+}
+
+// townProcess is a Gas Town-related process found by scanTownProcesses.
+type townProcess struct {
+	PID      int
+	Name     string
+	RSSBytes uint64
+}
+
+// scanTownProcesses lists the town's processes (agents, gt, bd, dolt, tmux)
+// from /proc. Node processes count only when running Claude. Processes that
+// exit mid-scan are skipped.
+func scanTownProcesses() ([]townProcess, error) {
+	return scanTownProcessesIn(procRoot)
+}
+
+// scanTownProcessesIn scans a /proc-style directory.
+func scanTownProcessesIn(root string) ([]townProcess, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", root, err)
+	}
+
+	var procs []townProcess
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		dir := filepath.Join(root, entry.Name())
+		comm, err := os.ReadFile(filepath.Join(dir, "comm"))
+		if err != nil {
+			continue
+		}
+		name := strings.TrimSpace(string(comm))
+		if !townProcessNames[name] {
+			if name != "node" {
+				continue
+			}
+			cmdline, _ := os.ReadFile(filepath.Join(dir, "cmdline"))
+			if !bytes.Contains(cmdline, []byte("claude")) {
+				continue
+			}
+		}
+		status, err := os.ReadFile(filepath.Join(dir, "status"))
+		if err != nil {
+			continue
+		}
+		procs = append(procs, townProcess{PID: pid, Name: name, RSSBytes: procfs.StatusKB(string(status), "VmRSS") * 1024})
+	}
+
+	sort.Slice(procs, func(i, j int) bool { return procs[i].RSSBytes > procs[j].RSSBytes })
+	return procs, nil
+}
@@ -0,0 +1,507 @@
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// securityPolicyWindow is how far back the check looks for denials.
+const securityPolicyWindow = 24 * time.Hour
+
+// securityPolicyLogTail caps how much of a log file is read.
+const securityPolicyLogTail = 4 << 20
+
+// securityPolicyFixScriptName is the fix script written under <town>/.runtime/.
+const securityPolicyFixScriptName = "fix-security-policy.sh"
+
+// securityPolicyBinaries are the programs agent sessions depend on.
+var securityPolicyBinaries = []string{"tmux", "claude", "gt"}
+
+// Log files searched for denials, in order. auditd logs both SELinux AVCs
+// and AppArmor events; without auditd they go to the kernel log.
+var securityPolicyLogFiles = []string{
+	"/var/log/audit/audit.log",
+	"/var/log/kern.log",
+	"/var/log/messages",
+}
+
+// Security modules recognized in denial records.
+const (
+	macAppArmor = "AppArmor"
+	macSELinux  = "SELinux"
+)
+
+// macDenial is one AppArmor or SELinux denial against an agent binary.
+type macDenial struct {
+	Module  string    // macAppArmor or macSELinux
+	At      time.Time // From the audit(epoch:serial) stamp
+	Binary  string    // Which of securityPolicyBinaries was involved
+	Comm    string    // Process that was denied
+	Target  string    // Path or name acted on (name=)
+	Access  string    // AppArmor requested_mask or operation, SELinux permissions
+	Profile string    // AppArmor profile
+	Cap     string    // AppArmor capability name, for capable operations
+	Source  string    // SELinux scontext
+	Context string    // SELinux tcontext
+	Class   string    // SELinux tclass
+	Raw     string    // The log line, for audit2allow
+}
+
+// key groups repeats of the same denial.
+func (d macDenial) key() string {
+	return strings.Join([]string{d.Module, d.Binary, d.Comm, d.Target, d.Access, d.Profile, d.Cap, d.Source, d.Context, d.Class}, "\x00")
+}
+
+// describe renders the denial for check details.
+func (d macDenial) describe() string {
+	target := d.Target
+	if d.Cap != "" {
+		target = "capability " + d.Cap
+	}
+	if target == "" {
+		target = d.Class
+	}
+	if d.Module == macAppArmor {
+		return fmt.Sprintf("AppArmor denied %s %s to %s (profile %s)", d.Access, target, d.Comm, d.Profile)
+	}
+	return fmt.Sprintf("SELinux denied { %s } on %s %s to %s (%s → %s)", d.Access, d.Class, target, d.Comm, d.Source, d.Context)
+}
+
+// macDenialGroup is a denial and how often it recurred in the window.
+type macDenialGroup struct {
+	macDenial
+	Count int
+	Last  time.Time
+}
+
+// SecurityPolicyCheck scans the audit and kernel logs for AppArmor and
+// SELinux denials against tmux, claude, or gt. Hardened distros ship
+// profiles that block these, and the agent only sees a failed spawn or a
+// pane that dies on start with no hint of the policy behind it.
+//
+// Fix writes <town>/.runtime/fix-security-policy.sh with the policy
+// adjustment for what was denied: AppArmor rules appended to the profiles'
+// local overrides, or an SELinux module built with audit2allow from the
+// recorded denials. It changes system policy and needs root, so doctor
+// never runs it itself; review it first.
+type SecurityPolicyCheck struct {
+	FixableCheck
+
+	// Injected for testing.
+	goos    string
+	readLog func() (source string, lines []string, err error)
+	modules func() []string
+	now     func() time.Time
+
+	// Cached by Run for Fix.
+	denials    []macDenialGroup
+	scriptPath string
+}
+
+// NewSecurityPolicyCheck creates a new AppArmor/SELinux denial check.
+func NewSecurityPolicyCheck() *SecurityPolicyCheck {
+	return &SecurityPolicyCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "security-policy",
+				CheckDescription: "Check for AppArmor/SELinux denials against tmux, claude, and gt",
+				CheckCategory:    CategoryInfrastructure,
+			},
+		},
+		goos:    runtime.GOOS,
+		readLog: readSecurityLog,
+		modules: activeSecurityModules,
+		now:     time.Now,
+	}
+}
+
+// Run looks for recent denials against agent binaries.
+func (c *SecurityPolicyCheck) Run(ctx *CheckContext) *CheckResult {
+	c.denials = nil
+	if c.goos != "linux" {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("AppArmor/SELinux not applicable on %s (skipped)", c.goos),
+		}
+	}
+	modules := c.modules()
+	if len(modules) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No AppArmor or SELinux enforcement active",
+		}
+	}
+
+	source, lines, err := c.readLog()
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("%s active; denial logs not readable (skipped)", strings.Join(modules, ", ")),
+			Details: []string{err.Error(), "Run 'sudo gt doctor' to scan the audit log"},
+		}
+	}
+
+	c.denials = groupMACDenials(parseMACDenials(lines), c.now().Add(-securityPolicyWindow))
+	if len(c.denials) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("No %s denials against agent binaries in the last %s", strings.Join(modules, "/"), securityPolicyWindow),
+		}
+	}
+
+	total := 0
+	var details []string
+	for _, g := range c.denials {
+		total += g.Count
+		details = append(details, fmt.Sprintf("%s ×%d, last %s", g.describe(), g.Count, g.Last.Format("2006-01-02 15:04")))
+	}
+	details = append(details, "Source: "+source)
+
+	fixHint := "Run 'gt doctor --fix' to generate " + filepath.Join(constants.DirRuntime, securityPolicyFixScriptName) +
+		", review it, then run it with sudo"
+	if c.scriptPath != "" {
+		fixHint = fmt.Sprintf("Review %s, then run 'sudo sh %s'", c.scriptPath, c.scriptPath)
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("%d AppArmor/SELinux denial(s) against agent binaries in the last %s", total, securityPolicyWindow),
+		Details: details,
+		FixHint: fixHint,
+	}
+}
+
+// Fix writes the policy script; applying it requires root.
+func (c *SecurityPolicyCheck) Fix(ctx *CheckContext) error {
+	if len(c.denials) == 0 {
+		return nil
+	}
+	dir := filepath.Join(ctx.TownRoot, constants.DirRuntime)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, securityPolicyFixScriptName)
+	if err := os.WriteFile(path, []byte(securityPolicyFixScript(c.denials)), 0755); err != nil { //nolint:gosec // G306: script must be executable
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	c.scriptPath = path
+	return nil
+}
+
+var (
+	auditStampRe   = regexp.MustCompile(`audit\((\d+)(?:\.\d+)?:\d+\)`)
+	auditFieldRe   = regexp.MustCompile(`(\w+)=("[^"]*"|\S+)`)
+	selinuxPermsRe = regexp.MustCompile(`avc:\s+denied\s+\{\s*([^}]*?)\s*\}`)
+)
+
+// parseMACDenials extracts denials against agent binaries from audit
+// records, as written to audit.log or (prefixed) to the kernel log.
+// AppArmor ALLOWED records (complain mode) and SELinux permissive denials
+// didn't block anything and are skipped.
+func parseMACDenials(lines []string) []macDenial {
+	var denials []macDenial
+	for _, line := range lines {
+		var d macDenial
+		fields := auditFields(line)
+		switch {
+		case fields["apparmor"] == "DENIED":
+			d = macDenial{
+				Module:  macAppArmor,
+				Access:  fields["requested_mask"],
+				Profile: fields["profile"],
+				Cap:     fields["capname"],
+			}
+			if d.Access == "" {
+				d.Access = fields["operation"]
+			}
+		case selinuxPermsRe.MatchString(line):
+			if fields["permissive"] == "1" {
+				continue
+			}
+			d = macDenial{
+				Module:  macSELinux,
+				Access:  selinuxPermsRe.FindStringSubmatch(line)[1],
+				Source:  fields["scontext"],
+				Context: fields["tcontext"],
+				Class:   fields["tclass"],
+			}
+		default:
+			continue
+		}
+		d.Comm = fields["comm"]
+		d.Target = fields["name"]
+		d.Raw = strings.TrimSpace(line)
+		d.Binary = agentBinaryIn(d.Comm, d.Target, fields["exe"], d.Profile)
+		if d.Binary == "" {
+			continue
+		}
+		if m := auditStampRe.FindStringSubmatch(line); m != nil {
+			if sec, err := strconv.ParseInt(m[1], 10, 64); err == nil {
+				d.At = time.Unix(sec, 0)
+			}
+		}
+		denials = append(denials, d)
+	}
+	return denials
+}
+
+// auditFields parses key=value pairs from an audit record, unquoting values.
+// The first occurrence of a key wins.
+func auditFields(line string) map[string]string {
+	fields := make(map[string]string)
+	for _, m := range auditFieldRe.FindAllStringSubmatch(line, -1) {
+		if _, seen := fields[m[1]]; !seen {
+			fields[m[1]] = strings.Trim(m[2], `"`)
+		}
+	}
+	return fields
+}
+
+// agentBinaryIn returns the agent binary named by any of the candidates
+// (a comm or a path), or "".
+func agentBinaryIn(candidates ...string) string {
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+		base := path.Base(c)
+		for _, bin := range securityPolicyBinaries {
+			if base == bin {
+				return bin
+			}
+		}
+	}
+	return ""
+}
+
+// groupMACDenials folds repeats of the same denial since the cutoff,
+// most frequent first. Denials without a timestamp are kept.
+func groupMACDenials(denials []macDenial, since time.Time) []macDenialGroup {
+	index := make(map[string]int)
+	var groups []macDenialGroup
+	for _, d := range denials {
+		if !d.At.IsZero() && d.At.Before(since) {
+			continue
+		}
+		i, ok := index[d.key()]
+		if !ok {
+			i = len(groups)
+			index[d.key()] = i
+			groups = append(groups, macDenialGroup{macDenial: d})
+		}
+		groups[i].Count++
+		if d.At.After(groups[i].Last) {
+			groups[i].Last = d.At
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Count > groups[j].Count
+	})
+	return groups
+}
+
+// appArmorRule converts a denial into a rule for the profile's local
+// override, or "" when there's no safe equivalent (signals, ptrace, mounts).
+func appArmorRule(d macDenial) string {
+	if d.Cap != "" {
+		return fmt.Sprintf("capability %s,", d.Cap)
+	}
+	if !strings.HasPrefix(d.Target, "/") || d.Access == "" {
+		return ""
+	}
+	var perms []string
+	add := func(p string) {
+		for _, have := range perms {
+			if have == p {
+				return
+			}
+		}
+		perms = append(perms, p)
+	}
+	for _, m := range d.Access {
+		switch m {
+		case 'r', 'w', 'k', 'l', 'm':
+			add(string(m))
+		case 'a', 'c', 'd':
+			add("w") // append, create, and delete are granted by write
+		case 'x':
+			add("ix")
+		default:
+			return ""
+		}
+	}
+	return fmt.Sprintf("%s %s,", d.Target, strings.Join(perms, ""))
+}
+
+// appArmorProfileFile returns the file under /etc/apparmor.d that defines
+// an attachment-path profile (/usr/bin/foo → usr.bin.foo), or "" for named
+// profiles, whose file can't be derived from the name.
+func appArmorProfileFile(profile string) string {
+	profile, _, _ = strings.Cut(profile, "//") // child profiles live in the parent's file
+	if !strings.HasPrefix(profile, "/") {
+		return ""
+	}
+	return strings.ReplaceAll(strings.TrimPrefix(profile, "/"), "/", ".")
+}
+
+// securityPolicyFixScript renders a root shell script that allows what was
+// denied: AppArmor rules go into /etc/apparmor.d/local/<profile> (included
+// by distro profiles for site changes) and the profile is reloaded; SELinux
+// denials are fed to audit2allow to build and install a local module.
+func securityPolicyFixScript(groups []macDenialGroup) string {
+	var b strings.Builder
+	b.WriteString(`#!/bin/sh
+# Generated by 'gt doctor --fix' (security-policy check).
+# Allows the AppArmor/SELinux accesses that agent binaries were denied.
+# This changes system security policy: review every rule before running as root.
+set -e
+`)
+
+	rules := make(map[string][]string) // profile file -> rules
+	var profiles, named []string
+	var avcs []string
+	for _, g := range groups {
+		switch g.Module {
+		case macAppArmor:
+			file := appArmorProfileFile(g.Profile)
+			rule := appArmorRule(g.macDenial)
+			if file == "" || rule == "" {
+				named = append(named, fmt.Sprintf("# %s (profile %s): %s", g.describe(), g.Profile, "adjust by hand, or: aa-complain "+g.Profile))
+				continue
+			}
+			if _, ok := rules[file]; !ok {
+				profiles = append(profiles, file)
+			}
+			rules[file] = append(rules[file], rule)
+		case macSELinux:
+			avcs = append(avcs, g.Raw)
+		}
+	}
+
+	for _, file := range profiles {
+		local := config.ShellQuote("/etc/apparmor.d/local/" + file)
+		fmt.Fprintf(&b, "\n# AppArmor: %s\nmkdir -p /etc/apparmor.d/local\ntouch %s\n", file, local)
+		for _, rule := range rules[file] {
+			rule = config.ShellQuote(rule)
+			fmt.Fprintf(&b, "grep -qxF %s %s || echo %s >> %s\n", rule, local, rule, local)
+		}
+		fmt.Fprintf(&b, "apparmor_parser -r %s\n", config.ShellQuote("/etc/apparmor.d/"+file))
+	}
+	if len(named) > 0 {
+		b.WriteString("\n# AppArmor denials with no automatic rule:\n")
+		for _, n := range named {
+			b.WriteString(n + "\n")
+		}
+	}
+
+	if len(avcs) > 0 {
+		b.WriteString(`
+# SELinux: build a local policy module from the recorded denials
+cd "$(mktemp -d)"
+cat > gastown-agents.avc <<'EOF'
+`)
+		for _, avc := range avcs {
+			b.WriteString(avc + "\n")
+		}
+		b.WriteString(`EOF
+audit2allow -M gastown-agents < gastown-agents.avc
+semodule -i gastown-agents.pp
+`)
+	}
+
+	b.WriteString(`
+echo "Policy updated. Restart the affected agents, then run 'gt doctor'."
+`)
+	return b.String()
+}
+
+// activeSecurityModules reports which of AppArmor and SELinux are enforcing.
+func activeSecurityModules() []string {
+	var modules []string
+	if data, err := os.ReadFile("/sys/module/apparmor/parameters/enabled"); err == nil && strings.TrimSpace(string(data)) == "Y" {
+		modules = append(modules, macAppArmor)
+	}
+	if data, err := os.ReadFile("/sys/fs/selinux/enforce"); err == nil && strings.TrimSpace(string(data)) == "1" {
+		modules = append(modules, macSELinux)
+	}
+	return modules
+}
+
+// readSecurityLog returns recent lines from the first readable log that
+// carries audit records: the audit log, the kernel journal, then syslog.
+func readSecurityLog() (string, []string, error) {
+	var errs []string
+	for _, file := range securityPolicyLogFiles[:1] {
+		lines, err := readLogTail(file, securityPolicyLogTail)
+		if err == nil {
+			return file, lines, nil
+		}
+		errs = append(errs, err.Error())
+	}
+
+	since := fmt.Sprintf("-%dh", int(securityPolicyWindow.Hours()))
+	out, err := exec.Command("journalctl", "-k", "--no-pager", "-o", "cat", "--since", since).Output()
+	if err == nil && len(out) > 0 {
+		return "journalctl -k", strings.Split(string(out), "\n"), nil
+	}
+	if err != nil {
+		errs = append(errs, "journalctl -k: "+err.Error())
+	}
+
+	for _, file := range securityPolicyLogFiles[1:] {
+		lines, err := readLogTail(file, securityPolicyLogTail)
+		if err == nil {
+			return file, lines, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	return "", nil, fmt.Errorf("no readable audit source: %s", strings.Join(errs, "; "))
+}
+
+// readLogTail reads up to max bytes from the end of a log file, dropping
+// the first (likely partial) line when the file was cut.
+func readLogTail(file string, max int64) ([]string, error) {
+	f, err := os.Open(file) //nolint:gosec // G304: fixed system log locations
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := int64(0)
+	if info.Size() > max {
+		offset = info.Size() - max
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(data), "\n")
+	if offset > 0 && len(lines) > 0 {
+		lines = lines[1:]
+	}
+	return lines, nil
+}
@@ -0,0 +1,167 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	// 2026-10-15 12:00:00 UTC
+	testAuditNow = time.Unix(1792065600, 0)
+
+	appArmorExecDenial = `audit: type=1400 audit(1792062000.123:456): apparmor="DENIED" operation="exec" profile="/usr/bin/sandboxd" name="/usr/bin/tmux" pid=4242 comm="sandboxd" requested_mask="x" denied_mask="x" fsuid=1000 ouid=0`
+	appArmorCapDenial  = `type=AVC msg=audit(1792062100.000:457): apparmor="DENIED" operation="capable" profile="/usr/local/bin/gt" pid=4243 comm="gt" capability=24 capname="sys_resource"`
+	appArmorComplain   = `audit: type=1400 audit(1792062000.123:458): apparmor="ALLOWED" operation="exec" profile="/usr/bin/sandboxd" name="/usr/bin/tmux" pid=4242 comm="sandboxd" requested_mask="x"`
+	seLinuxDenial      = `type=AVC msg=audit(1792063000.500:900): avc:  denied  { execute } for  pid=5151 comm="tmux: server" name="claude" dev="dm-0" ino=1234 scontext=user_u:user_r:user_t:s0 tcontext=system_u:object_r:usr_t:s0 tclass=file permissive=0`
+	seLinuxPermissive  = `type=AVC msg=audit(1792063000.500:901): avc:  denied  { execute } for  pid=5151 comm="tmux" name="claude" scontext=user_u:user_r:user_t:s0 tcontext=system_u:object_r:usr_t:s0 tclass=file permissive=1`
+	unrelatedDenial    = `audit: type=1400 audit(1792062000.123:459): apparmor="DENIED" operation="open" profile="/usr/sbin/cupsd" name="/etc/shadow" pid=99 comm="cupsd" requested_mask="r"`
+	staleDenial        = `audit: type=1400 audit(1791900000.000:1): apparmor="DENIED" operation="exec" profile="/usr/bin/sandboxd" name="/usr/bin/tmux" pid=1 comm="sandboxd" requested_mask="x"`
+)
+
+func newTestSecurityPolicyCheck(lines []string, readErr error, modules ...string) *SecurityPolicyCheck {
+	c := NewSecurityPolicyCheck()
+	c.goos = "linux"
+	c.modules = func() []string { return modules }
+	c.readLog = func() (string, []string, error) { return "/var/log/audit/audit.log", lines, readErr }
+	c.now = func() time.Time { return testAuditNow }
+	return c
+}
+
+func TestParseMACDenials(t *testing.T) {
+	denials := parseMACDenials([]string{
+		appArmorExecDenial, appArmorCapDenial, appArmorComplain,
+		seLinuxDenial, seLinuxPermissive, unrelatedDenial, "",
+	})
+	if len(denials) != 3 {
+		t.Fatalf("got %d denials, want 3: %+v", len(denials), denials)
+	}
+
+	exec := denials[0]
+	if exec.Module != macAppArmor || exec.Binary != "tmux" || exec.Target != "/usr/bin/tmux" ||
+		exec.Access != "x" || exec.Profile != "/usr/bin/sandboxd" || !exec.At.Equal(time.Unix(1792062000, 0)) {
+		t.Errorf("unexpected AppArmor exec denial: %+v", exec)
+	}
+	if capDenial := denials[1]; capDenial.Binary != "gt" || capDenial.Cap != "sys_resource" {
+		t.Errorf("unexpected AppArmor capability denial: %+v", capDenial)
+	}
+
+	se := denials[2]
+	if se.Module != macSELinux || se.Binary != "claude" || se.Access != "execute" ||
+		se.Comm != "tmux: server" || se.Class != "file" || se.Source != "user_u:user_r:user_t:s0" {
+		t.Errorf("unexpected SELinux denial: %+v", se)
+	}
+}
+
+func TestGroupMACDenials(t *testing.T) {
+	denials := parseMACDenials([]string{staleDenial, appArmorExecDenial, seLinuxDenial, appArmorExecDenial})
+	groups := groupMACDenials(denials, testAuditNow.Add(-securityPolicyWindow))
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2 (stale denial dropped): %+v", len(groups), groups)
+	}
+	if groups[0].Binary != "tmux" || groups[0].Count != 2 {
+		t.Errorf("expected repeated tmux denial first with count 2, got %+v", groups[0])
+	}
+}
+
+func TestAppArmorRule(t *testing.T) {
+	tests := []struct {
+		denial macDenial
+		want   string
+	}{
+		{macDenial{Target: "/usr/bin/tmux", Access: "x"}, "/usr/bin/tmux ix,"},
+		{macDenial{Target: "/tmp/tmux-1000/default", Access: "wc"}, "/tmp/tmux-1000/default w,"},
+		{macDenial{Target: "/home/u/.claude.json", Access: "rw"}, "/home/u/.claude.json rw,"},
+		{macDenial{Cap: "sys_resource"}, "capability sys_resource,"},
+		{macDenial{Target: "/usr/bin/tmux", Access: "signal"}, ""},
+		{macDenial{Access: "r"}, ""},
+	}
+	for _, tt := range tests {
+		if got := appArmorRule(tt.denial); got != tt.want {
+			t.Errorf("appArmorRule(%+v) = %q, want %q", tt.denial, got, tt.want)
+		}
+	}
+}
+
+func TestAppArmorProfileFile(t *testing.T) {
+	tests := map[string]string{
+		"/usr/bin/sandboxd":        "usr.bin.sandboxd",
+		"/usr/bin/man//man_filter": "usr.bin.man",
+		"snap.foo.bar":             "",
+	}
+	for profile, want := range tests {
+		if got := appArmorProfileFile(profile); got != want {
+			t.Errorf("appArmorProfileFile(%q) = %q, want %q", profile, got, want)
+		}
+	}
+}
+
+func TestSecurityPolicyCheck_NotEnforcing(t *testing.T) {
+	c := newTestSecurityPolicyCheck([]string{appArmorExecDenial}, nil)
+	result := c.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusOK {
+		t.Errorf("expected OK without AppArmor/SELinux, got %v: %s", result.Status, result.Message)
+	}
+}
+
+func TestSecurityPolicyCheck_LogsUnreadable(t *testing.T) {
+	c := newTestSecurityPolicyCheck(nil, errors.New("permission denied"), macSELinux)
+	result := c.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusOK || !strings.Contains(result.Message, "not readable") {
+		t.Errorf("expected skipped OK result, got %v: %s", result.Status, result.Message)
+	}
+}
+
+func TestSecurityPolicyCheck_NoDenials(t *testing.T) {
+	c := newTestSecurityPolicyCheck([]string{unrelatedDenial, appArmorComplain}, nil, macAppArmor)
+	result := c.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusOK {
+		t.Errorf("expected OK, got %v: %s %v", result.Status, result.Message, result.Details)
+	}
+}
+
+func TestSecurityPolicyCheck_DenialsAndFix(t *testing.T) {
+	townRoot := t.TempDir()
+	c := newTestSecurityPolicyCheck([]string{appArmorExecDenial, appArmorCapDenial, seLinuxDenial}, nil, macAppArmor, macSELinux)
+	ctx := &CheckContext{TownRoot: townRoot}
+
+	result := c.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("expected warning, got %v: %s", result.Status, result.Message)
+	}
+	if !strings.Contains(result.Message, "3 AppArmor/SELinux denial(s)") {
+		t.Errorf("unexpected message: %s", result.Message)
+	}
+	if !strings.Contains(strings.Join(result.Details, "\n"), "AppArmor denied x /usr/bin/tmux to sandboxd") {
+		t.Errorf("details should describe the tmux denial: %v", result.Details)
+	}
+
+	if err := c.Fix(ctx); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(townRoot, ".runtime", securityPolicyFixScriptName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := string(data)
+	for _, want := range []string{
+		"grep -qxF '/usr/bin/tmux ix,' /etc/apparmor.d/local/usr.bin.sandboxd || echo '/usr/bin/tmux ix,' >> /etc/apparmor.d/local/usr.bin.sandboxd",
+		"apparmor_parser -r /etc/apparmor.d/usr.bin.sandboxd",
+		"'capability sys_resource,'",
+		"apparmor_parser -r /etc/apparmor.d/usr.local.bin.gt",
+		seLinuxDenial,
+		"audit2allow -M gastown-agents < gastown-agents.avc",
+		"semodule -i gastown-agents.pp",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("fix script missing %q:\n%s", want, script)
+		}
+	}
+
+	if result := c.Run(ctx); !strings.Contains(result.FixHint, "sudo sh") {
+		t.Errorf("fix hint should point at the written script, got %q", result.FixHint)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/ui"
 )
 
@@ -63,6 +64,21 @@ type CheckContext struct {
 	RigName         string // Rig name (empty for town-level checks)
 	Verbose         bool   // Enable verbose output
 	RestartSessions bool   // Restart patrol sessions when fixing (requires explicit --restart-sessions flag)
+
+	townSettings *config.TownSettings // Loaded on first TownSettings call
+}
+
+// TownSettings returns the town's settings/config.json, loaded once per
+// context. Missing or unreadable settings yield the defaults.
+func (ctx *CheckContext) TownSettings() *config.TownSettings {
+	if ctx.townSettings == nil {
+		settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(ctx.TownRoot))
+		if err != nil {
+			settings = config.NewTownSettings()
+		}
+		ctx.townSettings = settings
+	}
+	return ctx.townSettings
 }
 
 // RigPath returns the full path to the rig directory.
@@ -0,0 +1,375 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// staleLockAge is how old a git lock file must be before it's considered
+// left behind by a killed process. Git holds these for the length of a
+// single command, so anything past a few minutes has no owner.
+const staleLockAge = 10 * time.Minute
+
+// repoLockFiles are the lock files git leaves behind when killed mid-write
+// in a repository's git dir. Worktree admin dirs have their own index and
+// HEAD, so only the first two apply there.
+var repoLockFiles = []string{"index.lock", "HEAD.lock", "config.lock", "packed-refs.lock", "shallow.lock"}
+
+// worktreeProblem is one finding from WorktreeCheck.
+type worktreeProblem struct {
+	path    string // lock file, worktree, or admin dir
+	kind    string // "lock", "pointer", "admin", "orphan"
+	message string
+
+	commonDir string // repository the worktree belongs to
+	adminDir  string // <commonDir>/worktrees/<id>
+	missing   []string
+}
+
+// WorktreeCheck looks for the debris a killed polecat session leaves in a
+// rig's git state: stale lock files (index.lock and friends) that make every
+// later git command fail with "Another git process seems to be running",
+// worktree admin entries whose gitdir pointer doesn't lead back to the
+// checkout, admin dirs missing HEAD or commondir (git then stops treating the
+// checkout as a repository, which looks like a detached or broken worktree),
+// and admin entries with no gitdir file at all.
+//
+// A worktree whose admin dir is missing entirely is worktree-gitdir-valid's
+// job; registrations for deleted checkouts are git-health's.
+type WorktreeCheck struct {
+	FixableCheck
+
+	now      func() time.Time
+	problems []worktreeProblem
+}
+
+// NewWorktreeCheck creates a new worktree and lock integrity check.
+func NewWorktreeCheck() *WorktreeCheck {
+	return &WorktreeCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "worktree-integrity",
+				CheckDescription: "Check rig worktrees for stale git locks and damaged admin files",
+				CheckCategory:    CategoryRig,
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// Run inspects every rig (or only --rig).
+func (c *WorktreeCheck) Run(ctx *CheckContext) *CheckResult {
+	c.problems = nil
+	for _, rigPath := range findAllRigs(ctx.TownRoot) {
+		if ctx.RigName != "" && filepath.Base(rigPath) != ctx.RigName {
+			continue
+		}
+		c.checkRig(rigPath)
+	}
+
+	if len(c.problems) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No stale git locks or damaged worktrees",
+		}
+	}
+
+	counts := make(map[string]int)
+	var details []string
+	for _, p := range c.problems {
+		counts[p.kind]++
+		rel, err := filepath.Rel(ctx.TownRoot, p.path)
+		if err != nil {
+			rel = p.path
+		}
+		details = append(details, fmt.Sprintf("%s: %s", rel, p.message))
+	}
+
+	var parts []string
+	for _, kind := range []string{"lock", "pointer", "admin", "orphan"} {
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[kind], worktreeProblemLabels[kind]))
+		}
+	}
+
+	status := StatusWarning
+	if counts["pointer"]+counts["admin"] > 0 {
+		status = StatusError
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  status,
+		Message: "Worktree problems: " + strings.Join(parts, ", "),
+		Details: details,
+		FixHint: "Run 'gt doctor --fix' to remove stale locks and repair or prune damaged worktrees",
+	}
+}
+
+// worktreeProblemLabels describe each problem kind in the summary message.
+var worktreeProblemLabels = map[string]string{
+	"lock":    "stale git lock(s)",
+	"pointer": "broken gitdir pointer(s)",
+	"admin":   "worktree(s) missing admin files",
+	"orphan":  "orphaned worktree admin dir(s)",
+}
+
+// checkRig inspects one rig's repositories and the worktrees checked out
+// from them.
+func (c *WorktreeCheck) checkRig(rigPath string) {
+	worktrees := polecatWorktrees(rigPath)
+	for _, dir := range []string{
+		filepath.Join(rigPath, "refinery", "rig"),
+		filepath.Join(rigPath, "witness", "rig"),
+	} {
+		if info, err := os.Stat(filepath.Join(dir, ".git")); err == nil && !info.IsDir() {
+			worktrees = append(worktrees, dir)
+		}
+	}
+	// Admin dirs a checkout still points at are repaired, not pruned.
+	inUse := make(map[string]bool)
+	for _, wt := range worktrees {
+		if adminDir := c.checkWorktree(wt); adminDir != "" {
+			inUse[adminDir] = true
+		}
+	}
+
+	for _, gitDir := range rigGitRepos(rigPath) {
+		c.checkLocks(gitDir, repoLockFiles)
+		c.checkAdminEntries(gitDir, inUse)
+	}
+}
+
+// checkLocks records lock files in dir older than staleLockAge.
+func (c *WorktreeCheck) checkLocks(dir string, names []string) {
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		age := c.now().Sub(info.ModTime())
+		if age < staleLockAge {
+			continue
+		}
+		c.problems = append(c.problems, worktreeProblem{
+			path:    path,
+			kind:    "lock",
+			message: fmt.Sprintf("stale lock (%s old)", age.Round(time.Minute)),
+		})
+	}
+}
+
+// checkAdminEntries checks each <gitDir>/worktrees/<id> for stale locks and,
+// unless a checkout uses it, for a missing gitdir file, which leaves the
+// entry pointing nowhere.
+func (c *WorktreeCheck) checkAdminEntries(gitDir string, inUse map[string]bool) {
+	entries, err := os.ReadDir(filepath.Join(gitDir, "worktrees"))
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		adminDir := filepath.Join(gitDir, "worktrees", e.Name())
+		if !e.IsDir() {
+			continue
+		}
+		c.checkLocks(adminDir, repoLockFiles[:2])
+		if inUse[adminDir] {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(adminDir, "locked")); err == nil {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(adminDir, "gitdir")); os.IsNotExist(err) {
+			c.problems = append(c.problems, worktreeProblem{
+				path:      adminDir,
+				kind:      "orphan",
+				message:   "admin entry has no gitdir file (no checkout refers to it)",
+				commonDir: gitDir,
+			})
+		}
+	}
+}
+
+// checkWorktree follows a checkout's .git file to its admin dir and checks
+// that the admin dir is complete and points back at the checkout. It
+// returns the admin dir, or "" if there isn't one to check.
+func (c *WorktreeCheck) checkWorktree(wt string) string {
+	adminDir, ok := readGitFile(wt)
+	if !ok {
+		return ""
+	}
+	if info, err := os.Stat(adminDir); err != nil || !info.IsDir() {
+		return "" // Missing admin dir: worktree-gitdir-valid
+	}
+	commonDir := filepath.Dir(filepath.Dir(adminDir))
+	if filepath.Base(filepath.Dir(adminDir)) != "worktrees" || !isGitDir(commonDir) {
+		return "" // Not a layout we know how to repair
+	}
+
+	var missing []string
+	for _, name := range []string{"HEAD", "commondir"} {
+		if _, err := os.Stat(filepath.Join(adminDir, name)); os.IsNotExist(err) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		c.problems = append(c.problems, worktreeProblem{
+			path:      wt,
+			kind:      "admin",
+			message:   fmt.Sprintf("admin dir %s is missing %s", filepath.Base(adminDir), strings.Join(missing, ", ")),
+			commonDir: commonDir,
+			adminDir:  adminDir,
+			missing:   missing,
+		})
+	}
+
+	back := readAdminGitdir(adminDir)
+	if back == "" || !sameFile(back, filepath.Join(wt, ".git")) {
+		msg := "admin gitdir file is missing"
+		if back != "" {
+			msg = fmt.Sprintf("admin gitdir points to %s, not this checkout", back)
+		}
+		c.problems = append(c.problems, worktreeProblem{
+			path:      wt,
+			kind:      "pointer",
+			message:   msg,
+			commonDir: commonDir,
+			adminDir:  adminDir,
+		})
+	}
+	return adminDir
+}
+
+// Fix removes stale locks, restores missing admin files, repairs gitdir
+// pointers with 'git worktree repair', and prunes orphaned admin entries.
+// Admin files are restored before pointers are repaired because git won't
+// recognise a worktree without HEAD and commondir.
+func (c *WorktreeCheck) Fix(ctx *CheckContext) error {
+	var errs []string
+	fail := func(path string, err error) {
+		errs = append(errs, fmt.Sprintf("%s: %v", path, err))
+	}
+
+	for _, p := range c.problems {
+		switch p.kind {
+		case "lock":
+			if err := os.Remove(p.path); err != nil && !os.IsNotExist(err) {
+				fail(p.path, err)
+			}
+		case "admin":
+			if err := restoreAdminFiles(p.adminDir, p.missing); err != nil {
+				fail(p.path, err)
+			}
+		}
+	}
+
+	pruned := make(map[string]bool)
+	for _, p := range c.problems {
+		switch p.kind {
+		case "pointer":
+			if out, err := runGit(p.commonDir, "worktree", "repair", p.path); err != nil {
+				fail(p.path, fmt.Errorf("git worktree repair: %v (%s)", err, strings.TrimSpace(out)))
+			}
+		case "orphan":
+			if pruned[p.commonDir] {
+				continue
+			}
+			pruned[p.commonDir] = true
+			if out, err := runGit(p.commonDir, "worktree", "prune"); err != nil {
+				fail(p.commonDir, fmt.Errorf("git worktree prune: %v (%s)", err, strings.TrimSpace(out)))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// restoreAdminFiles re-creates a worktree admin dir's commondir and HEAD.
+// HEAD is recovered from the worktree's reflog, so the checkout comes back
+// on the commit it was last at (detached; the polecat's branch still holds
+// its commits).
+func restoreAdminFiles(adminDir string, missing []string) error {
+	for _, name := range missing {
+		var content string
+		switch name {
+		case "commondir":
+			content = "../..\n"
+		case "HEAD":
+			sha := lastReflogCommit(filepath.Join(adminDir, "logs", "HEAD"))
+			if sha == "" {
+				return fmt.Errorf("HEAD is missing and there is no reflog to recover it from; re-create the worktree")
+			}
+			content = sha + "\n"
+		default:
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(adminDir, name), []byte(content), 0644); err != nil { //nolint:gosec // G306: git admin files are world-readable
+			return err
+		}
+	}
+	return nil
+}
+
+// lastReflogCommit returns the new-value commit of the last reflog entry.
+func lastReflogCommit(path string) string {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the rig's git dir
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 2 || len(fields[1]) < 40 {
+		return ""
+	}
+	return fields[1]
+}
+
+// readGitFile returns the admin dir a worktree's .git file points to.
+func readGitFile(wt string) (string, bool) {
+	data, err := os.ReadFile(filepath.Join(wt, ".git")) //nolint:gosec // G304: path is within the rig
+	if err != nil {
+		return "", false
+	}
+	target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return "", false
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(wt, target)
+	}
+	return filepath.Clean(target), true
+}
+
+// readAdminGitdir returns the checkout .git path an admin dir points back to.
+func readAdminGitdir(adminDir string) string {
+	data, err := os.ReadFile(filepath.Join(adminDir, "gitdir")) //nolint:gosec // G304: path is within the rig's git dir
+	if err != nil {
+		return ""
+	}
+	target := strings.TrimSpace(string(data))
+	if target != "" && !filepath.IsAbs(target) {
+		target = filepath.Join(adminDir, target)
+	}
+	return target
+}
+
+// sameFile reports whether two paths name the same file, allowing for
+// symlinked temp or home directories.
+func sameFile(a, b string) bool {
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bi, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(ai, bi)
+}
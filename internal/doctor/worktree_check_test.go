@@ -0,0 +1,145 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// polecatAdminDir returns the toast polecat's admin dir in setupGitRig's rig.
+func polecatAdminDir(t *testing.T, rigPath string) string {
+	t.Helper()
+	adminDir, ok := readGitFile(filepath.Join(rigPath, "polecats", "toast", "gastown"))
+	if !ok {
+		t.Fatal("polecat has no .git file")
+	}
+	return adminDir
+}
+
+func TestWorktreeCheck_Healthy(t *testing.T) {
+	townRoot, _ := setupGitRig(t)
+	result := NewWorktreeCheck().Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusOK {
+		t.Errorf("expected OK, got %v: %s %v", result.Status, result.Message, result.Details)
+	}
+}
+
+func TestWorktreeCheck_StaleLocks(t *testing.T) {
+	townRoot, rigPath := setupGitRig(t)
+	adminLock := filepath.Join(polecatAdminDir(t, rigPath), "index.lock")
+	repoLock := filepath.Join(rigPath, ".repo.git", "packed-refs.lock")
+	for _, p := range []string{adminLock, repoLock} {
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Fresh locks may belong to a running git command.
+	check := NewWorktreeCheck()
+	if result := check.Run(&CheckContext{TownRoot: townRoot}); result.Status != StatusOK {
+		t.Fatalf("fresh locks should be left alone, got %v: %v", result.Status, result.Details)
+	}
+
+	check.now = func() time.Time { return time.Now().Add(time.Hour) }
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusWarning || len(result.Details) != 2 {
+		t.Fatalf("expected 2 stale locks, got %v: %v", result.Status, result.Details)
+	}
+	if err := check.Fix(&CheckContext{TownRoot: townRoot}); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	for _, p := range []string{adminLock, repoLock} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s should have been removed", p)
+		}
+	}
+}
+
+func TestWorktreeCheck_BrokenPointer(t *testing.T) {
+	townRoot, rigPath := setupGitRig(t)
+	adminDir := polecatAdminDir(t, rigPath)
+	if err := os.WriteFile(filepath.Join(adminDir, "gitdir"), []byte("/nonexistent/polecat/.git\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewWorktreeCheck()
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusError || len(result.Details) != 1 || !strings.Contains(result.Details[0], "not this checkout") {
+		t.Fatalf("expected broken pointer, got %v: %v", result.Status, result.Details)
+	}
+	if err := check.Fix(&CheckContext{TownRoot: townRoot}); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if result := check.Run(&CheckContext{TownRoot: townRoot}); result.Status != StatusOK {
+		t.Errorf("expected OK after fix, got %v: %v", result.Status, result.Details)
+	}
+}
+
+func TestWorktreeCheck_MissingAdminFiles(t *testing.T) {
+	townRoot, rigPath := setupGitRig(t)
+	polecat := filepath.Join(rigPath, "polecats", "toast", "gastown")
+	want := gitRun(t, polecat, "rev-parse", "HEAD")
+	adminDir := polecatAdminDir(t, rigPath)
+	for _, name := range []string{"HEAD", "commondir"} {
+		if err := os.Remove(filepath.Join(adminDir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	check := NewWorktreeCheck()
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusError || !strings.Contains(result.Message, "missing admin files") {
+		t.Fatalf("expected missing admin files, got %v: %s %v", result.Status, result.Message, result.Details)
+	}
+	if err := check.Fix(&CheckContext{TownRoot: townRoot}); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if got := gitRun(t, polecat, "rev-parse", "HEAD"); got != want {
+		t.Errorf("HEAD after fix = %s, want %s", got, want)
+	}
+	if result := check.Run(&CheckContext{TownRoot: townRoot}); result.Status != StatusOK {
+		t.Errorf("expected OK after fix, got %v: %v", result.Status, result.Details)
+	}
+}
+
+func TestWorktreeCheck_OrphanedAdminDir(t *testing.T) {
+	townRoot, rigPath := setupGitRig(t)
+	orphan := filepath.Join(rigPath, ".repo.git", "worktrees", "ghost")
+	if err := os.MkdirAll(orphan, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewWorktreeCheck()
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusWarning || len(result.Details) != 1 {
+		t.Fatalf("expected one orphaned admin dir, got %v: %v", result.Status, result.Details)
+	}
+	if err := check.Fix(&CheckContext{TownRoot: townRoot}); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if _, err := os.Stat(orphan); !os.IsNotExist(err) {
+		t.Errorf("orphaned admin dir should have been pruned")
+	}
+}
+
+func TestWorktreeCheck_MissingGitdirRepairedNotPruned(t *testing.T) {
+	townRoot, rigPath := setupGitRig(t)
+	adminDir := polecatAdminDir(t, rigPath)
+	if err := os.Remove(filepath.Join(adminDir, "gitdir")); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewWorktreeCheck()
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if len(result.Details) != 1 || !strings.Contains(result.Details[0], "gitdir file is missing") {
+		t.Fatalf("expected one broken pointer, got %v: %v", result.Status, result.Details)
+	}
+	if err := check.Fix(&CheckContext{TownRoot: townRoot}); err != nil {
+		t.Fatalf("Fix: %v", err)
+	}
+	if result := check.Run(&CheckContext{TownRoot: townRoot}); result.Status != StatusOK {
+		t.Errorf("expected OK after fix, got %v: %v", result.Status, result.Details)
+	}
+}
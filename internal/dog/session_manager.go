@@ -20,6 +20,14 @@ var (
 	ErrSessionNotFound = errors.New("session not found")
 )
 
+// OneShotLimits bound a dog session. Dogs run one formula or bead and go
+// back to idle; one that outlives these is reaped by the daemon and returned
+// to the kennel.
+var OneShotLimits = session.OneShotLimits{
+	MaxLifetime: 2 * time.Hour,
+	IdleTimeout: 30 * time.Minute,
+}
+
 // SessionManager handles dog session lifecycle.
 type SessionManager struct {
 	tmux     *tmux.Tmux
@@ -124,6 +132,7 @@ func (m *SessionManager) Start(dogName string, opts SessionStartOptions) error {
 		ReadyDelay:     true,
 		VerifySurvived: true,
 		TrackPID:       true,
+		OneShot:        &OneShotLimits,
 	})
 	if err != nil {
 		return err
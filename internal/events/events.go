@@ -62,12 +62,30 @@ const (
 	TypeEscalationAcked  = "escalation_acked"
 	TypeEscalationClosed = "escalation_closed"
 	TypePatrolComplete   = "patrol_complete"
+	TypeDepUpdate        = "dep_update"
 
 	// Merge queue events (emitted by refinery)
 	TypeMergeStarted = "merge_started"
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// Degraded mode window (model API unreachable)
+	TypeDegradedEnter = "degraded_enter"
+	TypeDegradedExit  = "degraded_exit"
+
+	// Smoke gate (rig held after an incident until a smoke test passes)
+	TypeSmokeGateRequired = "smoke_gate_required"
+	TypeSmokeGatePassed   = "smoke_gate_passed"
+	TypeSmokeGateFailed   = "smoke_gate_failed"
+
+	// Manual circuit breaker overrides (gt witness circuit)
+	TypeCircuitReset = "circuit_reset"
+	TypeCircuitTrip  = "circuit_trip"
+
+	// Resolving quarantined polecats (gt witness quarantine)
+	TypeQuarantineRelease = "quarantine_release"
+	TypeQuarantineNuke    = "quarantine_nuke"
 )
 
 // EventsFile is the name of the raw events log.
@@ -259,6 +277,61 @@ func EscalationPayload(rig, target, to, reason string) map[string]interface{} {
 	}
 }
 
+// DepUpdatePayload creates a payload for dependency-update duty events.
+func DepUpdatePayload(rig, beadID string) map[string]interface{} {
+	return map[string]interface{}{
+		"rig":  rig,
+		"bead": beadID,
+	}
+}
+
+// DegradedPayload creates a payload for degraded mode enter/exit events.
+// source: what triggered the transition (probe, launch, manual)
+// reason: why degraded mode was entered (enter only)
+// duration: how long degraded mode lasted (exit only)
+func DegradedPayload(source, reason string, duration time.Duration) map[string]interface{} {
+	p := map[string]interface{}{
+		"source": source,
+	}
+	if reason != "" {
+		p["reason"] = reason
+	}
+	if duration > 0 {
+		p["duration"] = duration.Round(time.Second).String()
+	}
+	return p
+}
+
+// SmokeGatePayload creates a payload for smoke gate events.
+// detail: why the gate was required (required), or the failing step's
+// error (failed); empty for passed
+// commit: the commit smoke-tested (passed/failed only)
+func SmokeGatePayload(rig, detail, commit string) map[string]interface{} {
+	p := map[string]interface{}{
+		"rig": rig,
+	}
+	if detail != "" {
+		p["detail"] = detail
+	}
+	if commit != "" {
+		p["commit"] = commit
+	}
+	return p
+}
+
+// CircuitPayload creates a payload for manual circuit reset/trip events
+// and quarantine release/nuke events.
+func CircuitPayload(rig, polecat, reason string) map[string]interface{} {
+	p := map[string]interface{}{
+		"rig":     rig,
+		"polecat": polecat,
+	}
+	if reason != "" {
+		p["reason"] = reason
+	}
+	return p
+}
+
 // UnhookPayload creates a payload for unhook events.
 func UnhookPayload(beadID string) map[string]interface{} {
 	return map[string]interface{}{
@@ -507,6 +507,18 @@ func (c *Curator) generateSummary(event *events.Event) string {
 		}
 		return "Multiple sessions died simultaneously"
 
+	case events.TypeDegradedEnter:
+		if reason, ok := event.Payload["reason"].(string); ok {
+			return fmt.Sprintf("DEGRADED: model API unreachable, spawns paused - %s", reason)
+		}
+		return "DEGRADED: model API unreachable, spawns paused"
+
+	case events.TypeDegradedExit:
+		if duration, ok := event.Payload["duration"].(string); ok {
+			return fmt.Sprintf("Model API back after %s, degraded mode ended", duration)
+		}
+		return "Model API back, degraded mode ended"
+
 	default:
 		return fmt.Sprintf("%s: %s", event.Actor, event.Type)
 	}
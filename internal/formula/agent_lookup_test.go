@@ -0,0 +1,56 @@
+package formula_test
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// agentLookup is the agent bead lookup a patrol runs at the end of each
+// cycle, up to its rig placeholder.
+var agentLookup = regexp.MustCompile(`gt beads query ([^|]*?) --json`)
+
+// TestPatrolFormulas_AgentLookupMatchesFieldsBlock checks that each patrol's
+// agent bead lookup, in both the embedded and the town copy, finds its own
+// agent bead as FormatAgentDescription writes it, and no other.
+func TestPatrolFormulas_AgentLookupMatchesFieldsBlock(t *testing.T) {
+	patrols := map[string]string{
+		"mol-witness-patrol.formula.toml":  "witness",
+		"mol-refinery-patrol.formula.toml": "refinery",
+	}
+	for name, role := range patrols {
+		for _, path := range []string{"formulas/" + name, "../../.beads/formulas/" + name} {
+			t.Run(path, func(t *testing.T) {
+				content, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				m := agentLookup.FindStringSubmatch(string(content))
+				if m == nil {
+					t.Fatal("no gt beads query agent lookup")
+				}
+				filters, err := beads.ParseAgentQuery(strings.ReplaceAll(m[1], "<YOUR_RIG>", "gastown"))
+				if err != nil {
+					t.Fatalf("lookup %q: %v", m[1], err)
+				}
+				matches := func(fields *beads.AgentFields) bool {
+					issue := &beads.Issue{Description: beads.FormatAgentDescription("Agent", fields)}
+					return beads.New("").Query().Filter(filters...).Matches(issue)
+				}
+
+				if !matches(&beads.AgentFields{RoleType: role, Rig: "gastown", AgentState: "running"}) {
+					t.Errorf("lookup %q doesn't match the gastown %s", m[1], role)
+				}
+				if matches(&beads.AgentFields{RoleType: role, Rig: "beads"}) {
+					t.Errorf("lookup %q matches another rig's %s", m[1], role)
+				}
+				if matches(&beads.AgentFields{RoleType: "polecat", Rig: "gastown"}) {
+					t.Errorf("lookup %q matches a polecat", m[1])
+				}
+			})
+		}
+	}
+}
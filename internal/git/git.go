@@ -1112,6 +1112,23 @@ func (g *Git) CommitsAhead(base, branch string) (int, error) {
 	return count, nil
 }
 
+// CommitMessages returns the full messages of the commits on branch that are
+// not on base, newest first.
+func (g *Git) CommitMessages(base, branch string) ([]string, error) {
+	out, err := g.run("log", "--format=%B%x00", base+".."+branch)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []string
+	for _, msg := range strings.Split(out, "\x00") {
+		if msg = strings.TrimSpace(msg); msg != "" {
+			messages = append(messages, msg)
+		}
+	}
+	return messages, nil
+}
+
 // CountCommitsBehind returns the number of commits that HEAD is behind the given ref.
 // For example, CountCommitsBehind("origin/main") returns how many commits
 // are on origin/main that are not on the current HEAD.
@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -1554,3 +1555,49 @@ func TestClearPushURL(t *testing.T) {
 		t.Errorf("ClearPushURL (idempotent) should not error, got: %v", err)
 	}
 }
+
+func TestCommitMessages(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	base, err := g.CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch: %v", err)
+	}
+
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	for i, msg := range []string{"first change (gt-abc)", "second change\n\nBody line"} {
+		name := filepath.Join(dir, "f"+strconv.Itoa(i)+".txt")
+		if err := os.WriteFile(name, []byte(msg), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if err := g.Add(name); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		if err := g.Commit(msg); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+
+	messages, err := g.CommitMessages(base, "feature")
+	if err != nil {
+		t.Fatalf("CommitMessages: %v", err)
+	}
+	want := []string{"second change\n\nBody line", "first change (gt-abc)"}
+	if len(messages) != len(want) {
+		t.Fatalf("got %d messages %q, want %q", len(messages), messages, want)
+	}
+	for i := range want {
+		if messages[i] != want[i] {
+			t.Errorf("message %d = %q, want %q", i, messages[i], want[i])
+		}
+	}
+
+	if messages, err := g.CommitMessages("feature", "feature"); err != nil || len(messages) != 0 {
+		t.Errorf("expected no messages for an empty range, got %q (err %v)", messages, err)
+	}
+}
@@ -0,0 +1,272 @@
+// Package graphexport turns town activity into a graph dataset for offline
+// analysis: agents and beads are nodes; events, bead relationships, and mail
+// are edges. The result is written as JSONL or GraphML so it can be loaded
+// into tools like Gephi, NetworkX, or a notebook to study collaboration
+// patterns, bottlenecks, and agent performance.
+package graphexport
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// Node kinds.
+const (
+	KindAgent = "agent"
+	KindBead  = "bead"
+)
+
+// Edge kinds not taken from event types.
+const (
+	EdgeCreated   = "created"    // agent -> bead it created
+	EdgeAssigned  = "assigned"   // agent -> bead assigned to it
+	EdgeDependsOn = "depends_on" // bead -> bead it depends on
+	EdgeChildOf   = "child_of"   // bead -> parent bead
+	EdgeMail      = "mail"       // sender -> recipient
+)
+
+// Node is an agent or bead.
+type Node struct {
+	ID    string            `json:"id"`
+	Kind  string            `json:"kind"`
+	Label string            `json:"label"`
+	Attrs map[string]string `json:"attrs,omitempty"`
+}
+
+// Edge is one relationship or interaction. Interactions (events and mail)
+// are not merged: each occurrence is its own edge with a timestamp.
+type Edge struct {
+	Source    string            `json:"source"`
+	Target    string            `json:"target"`
+	Kind      string            `json:"kind"`
+	Timestamp time.Time         `json:"ts,omitzero"`
+	Attrs     map[string]string `json:"attrs,omitempty"`
+}
+
+// Graph accumulates nodes and edges. Records older than Since are skipped.
+type Graph struct {
+	Since time.Time
+
+	nodes map[string]*Node
+	edges []Edge
+}
+
+// New creates an empty graph. A zero since keeps everything.
+func New(since time.Time) *Graph {
+	return &Graph{Since: since, nodes: make(map[string]*Node)}
+}
+
+// AgentID returns the node ID for an agent address. Trailing slashes are
+// dropped so "mayor/" and "mayor" are the same node.
+func AgentID(address string) string {
+	return KindAgent + ":" + strings.TrimSuffix(address, "/")
+}
+
+// BeadID returns the node ID for a bead.
+func BeadID(id string) string {
+	return KindBead + ":" + id
+}
+
+// agent returns the node for an address, creating it on first use.
+func (g *Graph) agent(address string) *Node {
+	id := AgentID(address)
+	if n, ok := g.nodes[id]; ok {
+		return n
+	}
+	n := &Node{ID: id, Kind: KindAgent, Label: strings.TrimSuffix(address, "/"), Attrs: map[string]string{}}
+	g.nodes[id] = n
+	return n
+}
+
+// bead returns the node for a bead ID, creating a stub on first use. The
+// stub is filled in if the bead itself is added later.
+func (g *Graph) bead(id string) *Node {
+	nid := BeadID(id)
+	if n, ok := g.nodes[nid]; ok {
+		return n
+	}
+	n := &Node{ID: nid, Kind: KindBead, Label: id, Attrs: map[string]string{}}
+	g.nodes[nid] = n
+	return n
+}
+
+// touch records activity on an agent: an event count and first/last seen.
+func (g *Graph) touch(n *Node, ts time.Time) {
+	count, _ := strconv.Atoi(n.Attrs["activity"])
+	n.Attrs["activity"] = strconv.Itoa(count + 1)
+	if ts.IsZero() {
+		return
+	}
+	stamp := ts.UTC().Format(time.RFC3339)
+	if first := n.Attrs["first_seen"]; first == "" || stamp < first {
+		n.Attrs["first_seen"] = stamp
+	}
+	if stamp > n.Attrs["last_seen"] {
+		n.Attrs["last_seen"] = stamp
+	}
+}
+
+func (g *Graph) skip(ts time.Time) bool {
+	return !g.Since.IsZero() && !ts.IsZero() && ts.Before(g.Since)
+}
+
+// AddEvent adds an event from the town event log as edges from its actor
+// to the beads and agents named in its payload. Events that name neither
+// still count toward the actor's activity.
+func (g *Graph) AddEvent(e events.Event) {
+	ts, _ := time.Parse(time.RFC3339, e.Timestamp)
+	if g.skip(ts) || e.Actor == "" {
+		return
+	}
+	actor := g.agent(e.Actor)
+	g.touch(actor, ts)
+
+	attrs := map[string]string{"source": "events"}
+	for _, key := range []string{"rig", "reason", "branch", "status"} {
+		if v := payloadString(e.Payload, key); v != "" {
+			attrs[key] = v
+		}
+	}
+
+	for _, key := range []string{"bead", "issue", "mr"} {
+		if id := payloadString(e.Payload, key); id != "" {
+			g.edges = append(g.edges, Edge{Source: actor.ID, Target: g.bead(id).ID, Kind: e.Type, Timestamp: ts, Attrs: attrs})
+		}
+	}
+	for _, target := range eventAgents(e.Payload) {
+		if AgentID(target) == actor.ID {
+			continue
+		}
+		g.edges = append(g.edges, Edge{Source: actor.ID, Target: g.agent(target).ID, Kind: e.Type, Timestamp: ts, Attrs: attrs})
+	}
+}
+
+// eventAgents returns the agent addresses an event payload points at.
+func eventAgents(p map[string]interface{}) []string {
+	var agents []string
+	for _, key := range []string{"target", "to", "agent"} {
+		if v := payloadString(p, key); v != "" {
+			agents = append(agents, v)
+		}
+	}
+	if polecat := payloadString(p, "polecat"); polecat != "" {
+		if rig := payloadString(p, "rig"); rig != "" {
+			agents = append(agents, rig+"/polecats/"+polecat)
+		}
+	}
+	return agents
+}
+
+func payloadString(p map[string]interface{}, key string) string {
+	s, _ := p[key].(string)
+	return s
+}
+
+// AddIssue adds a bead with edges to the agents that created and own it
+// and to the beads it depends on or belongs to. Message beads belong in
+// AddMessage and are skipped here.
+func (g *Graph) AddIssue(issue *beads.Issue) {
+	if issue == nil || issue.ID == "" || beads.HasLabel(issue, "gt:message") {
+		return
+	}
+	created := parseTime(issue.CreatedAt)
+	updated := parseTime(issue.UpdatedAt)
+	if g.skip(updated) {
+		return
+	}
+
+	n := g.bead(issue.ID)
+	n.Label = issue.Title
+	setAttr(n.Attrs, "type", issue.Type)
+	setAttr(n.Attrs, "status", issue.Status)
+	n.Attrs["priority"] = fmt.Sprint(issue.Priority)
+	setAttr(n.Attrs, "created_at", issue.CreatedAt)
+	setAttr(n.Attrs, "closed_at", issue.ClosedAt)
+	if closed := parseTime(issue.ClosedAt); !created.IsZero() && !closed.IsZero() {
+		n.Attrs["cycle_seconds"] = fmt.Sprint(int64(closed.Sub(created).Seconds()))
+	}
+	setAttr(n.Attrs, "labels", strings.Join(issue.Labels, ","))
+
+	if issue.CreatedBy != "" {
+		creator := g.agent(issue.CreatedBy)
+		g.touch(creator, created)
+		g.edges = append(g.edges, Edge{Source: creator.ID, Target: n.ID, Kind: EdgeCreated, Timestamp: created})
+	}
+	if issue.Assignee != "" {
+		g.edges = append(g.edges, Edge{Source: g.agent(issue.Assignee).ID, Target: n.ID, Kind: EdgeAssigned, Timestamp: updated})
+	}
+	if issue.Parent != "" {
+		g.edges = append(g.edges, Edge{Source: n.ID, Target: g.bead(issue.Parent).ID, Kind: EdgeChildOf})
+	}
+	deps := append([]string{}, issue.DependsOn...)
+	for _, d := range issue.Dependencies {
+		deps = append(deps, d.ID)
+	}
+	seen := make(map[string]bool)
+	for _, dep := range deps {
+		if dep == "" || seen[dep] || dep == issue.Parent {
+			continue
+		}
+		seen[dep] = true
+		g.edges = append(g.edges, Edge{Source: n.ID, Target: g.bead(dep).ID, Kind: EdgeDependsOn})
+	}
+}
+
+// AddMessage adds a mail edge from sender to recipient. Only metadata is
+// exported; message bodies stay out of the dataset.
+func (g *Graph) AddMessage(msg *mail.Message) {
+	if msg == nil || msg.From == "" || msg.To == "" || g.skip(msg.Timestamp) {
+		return
+	}
+	from := g.agent(msg.From)
+	g.touch(from, msg.Timestamp)
+	to := g.agent(msg.To)
+
+	attrs := map[string]string{"id": msg.ID, "subject": msg.Subject}
+	setAttr(attrs, "priority", string(msg.Priority))
+	setAttr(attrs, "type", string(msg.Type))
+	setAttr(attrs, "thread", msg.ThreadID)
+	setAttr(attrs, "reply_to", msg.ReplyTo)
+	attrs["read"] = fmt.Sprint(msg.Read)
+	g.edges = append(g.edges, Edge{Source: from.ID, Target: to.ID, Kind: EdgeMail, Timestamp: msg.Timestamp, Attrs: attrs})
+}
+
+// Nodes returns all nodes sorted by ID.
+func (g *Graph) Nodes() []*Node {
+	nodes := make([]*Node, 0, len(g.nodes))
+	for _, n := range g.nodes {
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
+
+// Edges returns all edges, oldest first. Edges without a timestamp
+// (structural bead relationships) come first in insertion order.
+func (g *Graph) Edges() []Edge {
+	edges := append([]Edge(nil), g.edges...)
+	sort.SliceStable(edges, func(i, j int) bool { return edges[i].Timestamp.Before(edges[j].Timestamp) })
+	return edges
+}
+
+func setAttr(attrs map[string]string, key, value string) {
+	if value != "" {
+		attrs[key] = value
+	}
+}
+
+func parseTime(s string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
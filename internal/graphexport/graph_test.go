@@ -0,0 +1,113 @@
+package graphexport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func findNode(g *Graph, id string) *Node {
+	for _, n := range g.Nodes() {
+		if n.ID == id {
+			return n
+		}
+	}
+	return nil
+}
+
+func countEdges(g *Graph, source, target, kind string) int {
+	n := 0
+	for _, e := range g.Edges() {
+		if e.Source == source && e.Target == target && e.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func TestAddEvent(t *testing.T) {
+	g := New(time.Time{})
+	g.AddEvent(events.Event{Timestamp: "2026-10-01T10:00:00Z", Type: events.TypeSling, Actor: "mayor/",
+		Payload: events.SlingPayload("gt-1", "gastown/polecats/toast")})
+	g.AddEvent(events.Event{Timestamp: "2026-10-01T11:00:00Z", Type: events.TypeSpawn, Actor: "mayor",
+		Payload: events.SpawnPayload("gastown", "nux")})
+	g.AddEvent(events.Event{Timestamp: "2026-10-01T12:00:00Z", Type: events.TypeHalt, Actor: "mayor",
+		Payload: events.HaltPayload([]string{"daemon"})})
+
+	if countEdges(g, "agent:mayor", "bead:gt-1", events.TypeSling) != 1 ||
+		countEdges(g, "agent:mayor", "agent:gastown/polecats/toast", events.TypeSling) != 1 {
+		t.Errorf("sling should link the actor to its bead and target: %+v", g.Edges())
+	}
+	if countEdges(g, "agent:mayor", "agent:gastown/polecats/nux", events.TypeSpawn) != 1 {
+		t.Errorf("spawn should link to rig/polecats/name: %+v", g.Edges())
+	}
+
+	mayor := findNode(g, "agent:mayor")
+	if mayor == nil || mayor.Attrs["activity"] != "3" ||
+		mayor.Attrs["first_seen"] != "2026-10-01T10:00:00Z" || mayor.Attrs["last_seen"] != "2026-10-01T12:00:00Z" {
+		t.Errorf("mayor/ and mayor should be one node with 3 events: %+v", mayor)
+	}
+}
+
+func TestAddIssue(t *testing.T) {
+	g := New(time.Time{})
+	g.AddIssue(&beads.Issue{
+		ID: "gt-2", Title: "Fix build", Status: "closed", Priority: 1, Type: "bug",
+		CreatedAt: "2026-10-01T10:00:00Z", UpdatedAt: "2026-10-01T12:00:00Z", ClosedAt: "2026-10-01T12:00:00Z",
+		CreatedBy: "mayor", Assignee: "gastown/polecats/toast", Parent: "gt-epic",
+		DependsOn: []string{"gt-1", "gt-epic"},
+	})
+	g.AddIssue(&beads.Issue{ID: "hq-m1", Labels: []string{"gt:message"}})
+
+	n := findNode(g, "bead:gt-2")
+	if n == nil || n.Label != "Fix build" || n.Attrs["cycle_seconds"] != "7200" || n.Attrs["status"] != "closed" {
+		t.Fatalf("bead node = %+v", n)
+	}
+	for _, want := range []struct{ src, dst, kind string }{
+		{"agent:mayor", "bead:gt-2", EdgeCreated},
+		{"agent:gastown/polecats/toast", "bead:gt-2", EdgeAssigned},
+		{"bead:gt-2", "bead:gt-epic", EdgeChildOf},
+		{"bead:gt-2", "bead:gt-1", EdgeDependsOn},
+	} {
+		if countEdges(g, want.src, want.dst, want.kind) != 1 {
+			t.Errorf("missing %s edge %s -> %s", want.kind, want.src, want.dst)
+		}
+	}
+	if countEdges(g, "bead:gt-2", "bead:gt-epic", EdgeDependsOn) != 0 {
+		t.Error("parent should not also be a depends_on edge")
+	}
+	if findNode(g, "bead:hq-m1") != nil {
+		t.Error("message beads should be skipped")
+	}
+}
+
+func TestAddMessage(t *testing.T) {
+	g := New(time.Time{})
+	msg := mail.NewMessage("gastown/witness", "mayor/", "HELP", "secret body")
+	msg.ID = "hq-1"
+	g.AddMessage(msg)
+
+	edges := g.Edges()
+	if len(edges) != 1 || edges[0].Kind != EdgeMail || edges[0].Attrs["id"] != "hq-1" {
+		t.Fatalf("edges = %+v", edges)
+	}
+	for _, v := range edges[0].Attrs {
+		if v == "secret body" {
+			t.Error("mail bodies must not be exported")
+		}
+	}
+}
+
+func TestSince(t *testing.T) {
+	g := New(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	g.AddEvent(events.Event{Timestamp: "2026-09-30T23:00:00Z", Type: events.TypeHook, Actor: "mayor", Payload: events.HookPayload("gt-1")})
+	g.AddIssue(&beads.Issue{ID: "gt-old", UpdatedAt: "2026-09-01T00:00:00Z"})
+	g.AddEvent(events.Event{Timestamp: "2026-10-01T01:00:00Z", Type: events.TypeHook, Actor: "mayor", Payload: events.HookPayload("gt-2")})
+
+	if len(g.Edges()) != 1 || findNode(g, "bead:gt-old") != nil || findNode(g, "bead:gt-1") != nil {
+		t.Errorf("records before since should be dropped: nodes=%d edges=%+v", len(g.Nodes()), g.Edges())
+	}
+}
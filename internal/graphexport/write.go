@@ -0,0 +1,172 @@
+package graphexport
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Output formats.
+const (
+	FormatJSONL   = "jsonl"
+	FormatGraphML = "graphml"
+)
+
+// Write writes the graph in the given format.
+func (g *Graph) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatJSONL:
+		return g.WriteJSONL(w)
+	case FormatGraphML:
+		return g.WriteGraphML(w)
+	default:
+		return fmt.Errorf("unknown format %q (use %s or %s)", format, FormatJSONL, FormatGraphML)
+	}
+}
+
+// JSONL lines are tagged with "record" so nodes and edges can share a file.
+type (
+	jsonlNode struct {
+		Record string `json:"record"` // "node"
+		*Node
+	}
+	jsonlEdge struct {
+		Record string `json:"record"` // "edge"
+		*Edge
+	}
+)
+
+// WriteJSONL writes one JSON object per line: all nodes, then all edges.
+func (g *Graph) WriteJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, n := range g.Nodes() {
+		if err := enc.Encode(jsonlNode{Record: "node", Node: n}); err != nil {
+			return err
+		}
+	}
+	for _, e := range g.Edges() {
+		e := e
+		if err := enc.Encode(jsonlEdge{Record: "edge", Edge: &e}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GraphML document structure. Node and edge attributes become GraphML
+// string keys; every key used anywhere is declared up front.
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the graph as a directed GraphML document.
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Graph: graphMLGraph{ID: "town", EdgeDefault: "directed"},
+	}
+	nodeKeys := map[string]bool{"kind": true, "label": true}
+	edgeKeys := map[string]bool{"kind": true}
+
+	for _, n := range g.Nodes() {
+		attrs := map[string]string{"kind": n.Kind, "label": n.Label}
+		for k, v := range n.Attrs {
+			attrs[k] = v
+			nodeKeys[k] = true
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: n.ID, Data: graphMLDataFor("n_", attrs)})
+	}
+	for i, e := range g.Edges() {
+		attrs := map[string]string{"kind": e.Kind}
+		if !e.Timestamp.IsZero() {
+			attrs["ts"] = e.Timestamp.UTC().Format(time.RFC3339)
+			edgeKeys["ts"] = true
+		}
+		for k, v := range e.Attrs {
+			attrs[k] = v
+			edgeKeys[k] = true
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     fmt.Sprintf("e%d", i),
+			Source: e.Source,
+			Target: e.Target,
+			Data:   graphMLDataFor("e_", attrs),
+		})
+	}
+	doc.Keys = append(graphMLKeys("n_", "node", nodeKeys), graphMLKeys("e_", "edge", edgeKeys)...)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// graphMLKeys declares keys in sorted order. Node and edge keys are
+// prefixed so an attribute name used by both doesn't collide.
+func graphMLKeys(prefix, scope string, names map[string]bool) []graphMLKey {
+	var keys []graphMLKey
+	for _, name := range sortedKeys(names) {
+		keys = append(keys, graphMLKey{ID: prefix + name, For: scope, AttrName: name, AttrType: "string"})
+	}
+	return keys
+}
+
+func graphMLDataFor(prefix string, attrs map[string]string) []graphMLData {
+	var data []graphMLData
+	for _, k := range sortedKeys(attrs) {
+		data = append(data, graphMLData{Key: prefix + k, Value: attrs[k]})
+	}
+	return data
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
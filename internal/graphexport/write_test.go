@@ -0,0 +1,101 @@
+package graphexport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func sampleGraph() *Graph {
+	g := New(time.Time{})
+	g.AddIssue(&beads.Issue{ID: "gt-1", Title: "Fix <build> & test", CreatedBy: "mayor", Parent: "gt-epic",
+		CreatedAt: "2026-10-01T10:00:00Z", UpdatedAt: "2026-10-01T10:00:00Z"})
+	g.AddEvent(events.Event{Timestamp: "2026-10-01T11:00:00Z", Type: events.TypeSling, Actor: "mayor",
+		Payload: events.SlingPayload("gt-1", "gastown/polecats/toast")})
+	return g
+}
+
+func TestWriteJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleGraph().Write(&buf, FormatJSONL); err != nil {
+		t.Fatal(err)
+	}
+
+	var nodes, edges int
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var rec map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("bad line %q: %v", scanner.Text(), err)
+		}
+		if rec["kind"] == nil {
+			t.Errorf("record missing kind: %s", scanner.Text())
+		}
+		switch rec["record"] {
+		case "node":
+			nodes++
+		case "edge":
+			edges++
+			if rec["kind"] == EdgeChildOf && rec["ts"] != nil {
+				t.Errorf("structural edge should have no timestamp: %s", scanner.Text())
+			}
+		default:
+			t.Errorf("unknown record: %s", scanner.Text())
+		}
+	}
+	// mayor, toast, gt-1, gt-epic; created, child_of, two sling edges.
+	if nodes != 4 || edges != 4 {
+		t.Errorf("nodes=%d edges=%d, want 4 and 4", nodes, edges)
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleGraph().Write(&buf, FormatGraphML); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	var doc graphML
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, out)
+	}
+	if len(doc.Graph.Nodes) != 4 || len(doc.Graph.Edges) != 4 || doc.Graph.EdgeDefault != "directed" {
+		t.Errorf("graph = %d nodes, %d edges, %s", len(doc.Graph.Nodes), len(doc.Graph.Edges), doc.Graph.EdgeDefault)
+	}
+
+	declared := make(map[string]bool)
+	for _, k := range doc.Keys {
+		declared[k.ID] = true
+	}
+	for _, n := range doc.Graph.Nodes {
+		for _, d := range n.Data {
+			if !declared[d.Key] {
+				t.Errorf("node data key %s not declared", d.Key)
+			}
+		}
+	}
+	for _, e := range doc.Graph.Edges {
+		for _, d := range e.Data {
+			if !declared[d.Key] {
+				t.Errorf("edge data key %s not declared", d.Key)
+			}
+		}
+	}
+	if !strings.Contains(out, "Fix &lt;build&gt; &amp; test") {
+		t.Error("labels should be XML-escaped")
+	}
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	if err := New(time.Time{}).Write(&bytes.Buffer{}, "dot"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
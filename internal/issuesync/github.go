@@ -0,0 +1,170 @@
+package issuesync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// githubIssueLimit caps how many issues one sync lists.
+const githubIssueLimit = 1000
+
+// GitHub syncs with a repository's GitHub issues through the gh CLI, which
+// must be installed and authenticated. Issue keys are "owner/repo#N";
+// priority is a P0-P4 label.
+type GitHub struct {
+	Repo  string // owner/name
+	Label string // Only issues with this label are in scope ("" = all)
+
+	// run runs gh with args and returns its stdout; tests replace it.
+	run func(ctx context.Context, args ...string) ([]byte, error)
+}
+
+// NewGitHub returns a tracker for a repository's issues, scoped to those
+// with label unless it's empty.
+func NewGitHub(repo, label string) *GitHub {
+	return &GitHub{Repo: repo, Label: label, run: runGH}
+}
+
+// Name implements Tracker.
+func (g *GitHub) Name() string { return "github" }
+
+// githubIssue is an issue as gh issue list --json prints it.
+type githubIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	State  string `json:"state"`
+	URL    string `json:"url"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// List implements Tracker.
+func (g *GitHub) List(ctx context.Context) ([]Issue, error) {
+	args := []string{"issue", "list", "--repo", g.Repo, "--state", "all",
+		"--limit", strconv.Itoa(githubIssueLimit), "--json", "number,title,body,state,url,labels"}
+	if g.Label != "" {
+		args = append(args, "--label", g.Label)
+	}
+	out, err := g.run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	var raw []githubIssue
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, fmt.Errorf("parsing gh issue list: %w", err)
+	}
+
+	issues := make([]Issue, 0, len(raw))
+	for _, r := range raw {
+		issue := Issue{
+			Key:      g.key(r.Number),
+			URL:      r.URL,
+			Title:    r.Title,
+			Body:     r.Body,
+			Open:     strings.EqualFold(r.State, "open"),
+			Priority: -1,
+		}
+		for _, label := range r.Labels {
+			if p, ok := githubPriority(label.Name); ok {
+				if issue.Priority < 0 || p < issue.Priority {
+					issue.Priority = p
+				}
+				continue
+			}
+			if label.Name != g.Label {
+				issue.Labels = append(issue.Labels, label.Name)
+			}
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// Create implements Tracker.
+func (g *GitHub) Create(ctx context.Context, title, body string, priority int) (Issue, error) {
+	args := []string{"issue", "create", "--repo", g.Repo, "--title", title, "--body", body,
+		"--label", githubPriorityLabel(priority)}
+	if g.Label != "" {
+		args = append(args, "--label", g.Label)
+	}
+	out, err := g.run(ctx, args...)
+	if err != nil {
+		return Issue{}, err
+	}
+	// gh prints the new issue's URL, ending in its number.
+	url := strings.TrimSpace(string(out))
+	number, err := strconv.Atoi(url[strings.LastIndex(url, "/")+1:])
+	if err != nil {
+		return Issue{}, fmt.Errorf("parsing gh issue create output %q", url)
+	}
+	return Issue{Key: g.key(number), URL: url, Title: title, Body: body, Open: true, Priority: priority}, nil
+}
+
+// SetOpen implements Tracker.
+func (g *GitHub) SetOpen(ctx context.Context, issue Issue, open bool, comment string) error {
+	verb := "close"
+	if open {
+		verb = "reopen"
+	}
+	_, err := g.run(ctx, "issue", verb, g.number(issue), "--repo", g.Repo, "--comment", comment)
+	return err
+}
+
+// SetPriority implements Tracker, replacing any other priority label.
+func (g *GitHub) SetPriority(ctx context.Context, issue Issue, priority int) error {
+	args := []string{"issue", "edit", g.number(issue), "--repo", g.Repo, "--add-label", githubPriorityLabel(priority)}
+	if issue.Priority >= 0 && issue.Priority != priority {
+		args = append(args, "--remove-label", githubPriorityLabel(issue.Priority))
+	}
+	_, err := g.run(ctx, args...)
+	return err
+}
+
+// Comment implements Tracker.
+func (g *GitHub) Comment(ctx context.Context, issue Issue, body string) error {
+	_, err := g.run(ctx, "issue", "comment", g.number(issue), "--repo", g.Repo, "--body", body)
+	return err
+}
+
+func (g *GitHub) key(number int) string {
+	return fmt.Sprintf("%s#%d", g.Repo, number)
+}
+
+// number returns the issue number of a key.
+func (g *GitHub) number(issue Issue) string {
+	return issue.Key[strings.LastIndex(issue.Key, "#")+1:]
+}
+
+// githubPriority maps a P0-P4 label (any case) to a priority.
+func githubPriority(label string) (int, bool) {
+	if len(label) != 2 || (label[0] != 'P' && label[0] != 'p') || label[1] < '0' || label[1] > '4' {
+		return 0, false
+	}
+	return int(label[1] - '0'), true
+}
+
+func githubPriorityLabel(priority int) string {
+	return fmt.Sprintf("P%d", priority)
+}
+
+// runGH runs the gh CLI, folding its stderr into the error.
+func runGH(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "gh", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("gh %s: %s", args[0]+" "+args[1], msg)
+		}
+		return nil, fmt.Errorf("gh %s: %w", args[0]+" "+args[1], err)
+	}
+	return stdout.Bytes(), nil
+}
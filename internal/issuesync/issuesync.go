@@ -0,0 +1,394 @@
+// Package issuesync mirrors a rig's work beads to an external issue tracker
+// and back.
+//
+// A sync pulls the tracker's issues in scope into work beads, creating a
+// bead for each new open issue, and pushes what Gas Town did with them
+// back: closing an issue when its bead closes (its work merged), commenting
+// when a polecat is assigned, and keeping status and priority in step in
+// both directions. Beads carry their link as labels (gt:<tracker> and
+// <tracker>:<key>), and the sync keeps the state both sides were in at the
+// last sync, so it can tell which side changed; a change on both sides
+// since then is settled in the tracker's favour.
+package issuesync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultPriority is the bead priority of an issue with no priority.
+const DefaultPriority = 2
+
+// Issue is an issue in an external tracker, as a sync sees it.
+type Issue struct {
+	Key      string // Tracker-wide key, e.g. "owner/repo#12" or "OPS-12"
+	URL      string // Where a human can read it
+	Title    string
+	Body     string
+	Open     bool
+	Priority int      // 0-4, or -1 for none
+	Labels   []string // The tracker's labels, less any it maps to the priority
+}
+
+// Tracker is an external issue tracker a rig's work beads sync with.
+type Tracker interface {
+	// Name names the tracker in bead labels and state files, e.g. "github".
+	Name() string
+	// List returns the issues in the sync's scope, open and closed.
+	List(ctx context.Context) ([]Issue, error)
+	// Create opens a new issue and returns it.
+	Create(ctx context.Context, title, body string, priority int) (Issue, error)
+	// SetOpen closes or reopens an issue, leaving comment on it.
+	SetOpen(ctx context.Context, issue Issue, open bool, comment string) error
+	// SetPriority changes an issue's priority.
+	SetPriority(ctx context.Context, issue Issue, priority int) error
+	// Comment leaves a comment on an issue.
+	Comment(ctx context.Context, issue Issue, body string) error
+}
+
+// Starter is a Tracker whose issues have an in-progress state. A sync
+// moves an issue into it, instead of only commenting, when a polecat is
+// assigned its bead.
+type Starter interface {
+	Start(ctx context.Context, issue Issue, comment string) error
+}
+
+// Store is the subset of beads operations a sync needs.
+type Store interface {
+	List(opts beads.ListOptions) ([]*beads.Issue, error)
+	Create(opts beads.CreateOptions) (*beads.Issue, error)
+	Update(id string, opts beads.UpdateOptions) error
+	CloseWithReason(reason string, ids ...string) error
+}
+
+// Options tunes a sync.
+type Options struct {
+	// ExportLabel, if set, pushes open work beads carrying it that aren't
+	// linked yet as new issues.
+	ExportLabel string
+	// DryRun reports what the sync would do without changing either side.
+	DryRun bool
+}
+
+// Result reports what a sync did (or, dry-run, would do), one line per
+// change.
+type Result struct {
+	Actions  []string
+	Warnings []string
+}
+
+// Link is the state of a bead and its issue when they were last synced.
+type Link struct {
+	Bead          string `json:"bead"`
+	BeadOpen      bool   `json:"bead_open"`
+	BeadPriority  int    `json:"bead_priority"`
+	BeadAssignee  string `json:"bead_assignee,omitempty"`
+	IssueOpen     bool   `json:"issue_open"`
+	IssuePriority int    `json:"issue_priority"`
+	IssueTitle    string `json:"issue_title"`
+}
+
+// State is what a tracker's sync with a rig remembers between runs, by
+// issue key.
+type State struct {
+	Links map[string]*Link `json:"links"`
+}
+
+// StatePath returns where a rig's sync with a tracker keeps its state.
+func StatePath(townRoot, rigName, tracker string) string {
+	return filepath.Join(townRoot, ".runtime", "issue-sync", rigName+"-"+tracker+".json")
+}
+
+// LoadState reads sync state; a missing file is an empty state.
+func LoadState(path string) (*State, error) {
+	state := &State{Links: map[string]*Link{}}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if state.Links == nil {
+		state.Links = map[string]*Link{}
+	}
+	return state, nil
+}
+
+// Save writes sync state, replacing the file whole under its lock so a
+// sync run by hand and the daemon's can't interleave writes.
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking sync state: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	return util.AtomicWriteJSON(path, s)
+}
+
+// TrackerLabel is the label every bead linked to tracker carries.
+func TrackerLabel(tracker string) string {
+	return "gt:" + tracker
+}
+
+// LinkLabel is the label linking a bead to the issue with key.
+func LinkLabel(tracker, key string) string {
+	return tracker + ":" + key
+}
+
+// LinkedKey returns the key of the issue a bead is linked to, if any.
+func LinkedKey(tracker string, issue *beads.Issue) (string, bool) {
+	prefix := tracker + ":"
+	for _, label := range issue.Labels {
+		if strings.HasPrefix(label, prefix) {
+			return strings.TrimPrefix(label, prefix), true
+		}
+	}
+	return "", false
+}
+
+// Sync runs one sync between a tracker and a rig's beads, updating state.
+// A failure on one issue is a warning and leaves that issue's state as it
+// was, so the next sync retries it; only failing to list either side fails
+// the sync.
+func Sync(ctx context.Context, tracker Tracker, store Store, state *State, opts Options) (*Result, error) {
+	name := tracker.Name()
+	issues, err := tracker.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing %s issues: %w", name, err)
+	}
+	linkedBeads, err := store.List(beads.ListOptions{Label: TrackerLabel(name), Status: "all", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing linked beads: %w", err)
+	}
+	byKey := make(map[string]*beads.Issue, len(linkedBeads))
+	for _, bead := range linkedBeads {
+		if key, ok := LinkedKey(name, bead); ok {
+			byKey[key] = bead
+		}
+	}
+
+	s := &syncRun{ctx: ctx, tracker: tracker, store: store, state: state, opts: opts, result: &Result{}}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Key < issues[j].Key })
+	for _, issue := range issues {
+		bead := byKey[issue.Key]
+		if bead == nil {
+			s.pull(issue)
+			continue
+		}
+		s.reconcile(issue, bead)
+	}
+
+	if opts.ExportLabel != "" {
+		exported, err := store.List(beads.ListOptions{Label: opts.ExportLabel, Status: "open", Priority: -1})
+		if err != nil {
+			s.warn("listing beads to export: %v", err)
+		}
+		for _, bead := range exported {
+			if _, linked := LinkedKey(name, bead); !linked {
+				s.export(bead)
+			}
+		}
+	}
+	return s.result, nil
+}
+
+// syncRun is one Sync in progress.
+type syncRun struct {
+	ctx     context.Context
+	tracker Tracker
+	store   Store
+	state   *State
+	opts    Options
+	result  *Result
+}
+
+func (s *syncRun) act(format string, args ...interface{}) {
+	s.result.Actions = append(s.result.Actions, fmt.Sprintf(format, args...))
+}
+
+func (s *syncRun) warn(format string, args ...interface{}) {
+	s.result.Warnings = append(s.result.Warnings, fmt.Sprintf(format, args...))
+}
+
+// pull creates a work bead for an open issue that has none.
+func (s *syncRun) pull(issue Issue) {
+	if !issue.Open {
+		return
+	}
+	priority := issue.Priority
+	if priority < 0 {
+		priority = DefaultPriority
+	}
+	s.act("create bead for %s: %s", issue.Key, issue.Title)
+	if s.opts.DryRun {
+		return
+	}
+
+	description := strings.TrimSpace(issue.Body)
+	if issue.URL != "" {
+		description = strings.TrimSpace(description + "\n\nSource: " + issue.URL)
+	}
+	// The link labels go on in the create, so a bead never exists unlinked
+	// for the next sync to pull its issue into again.
+	name := s.tracker.Name()
+	labels := append([]string{TrackerLabel(name), LinkLabel(name, issue.Key)}, issue.Labels...)
+	bead, err := s.store.Create(beads.CreateOptions{Title: issue.Title, Type: "task", Priority: priority,
+		Description: description, Labels: labels})
+	if err != nil {
+		s.warn("%s: creating bead: %v", issue.Key, err)
+		return
+	}
+	s.state.Links[issue.Key] = &Link{Bead: bead.ID, BeadOpen: true, BeadPriority: priority,
+		IssueOpen: true, IssuePriority: issue.Priority, IssueTitle: issue.Title}
+}
+
+// export opens an issue for a bead marked for export.
+func (s *syncRun) export(bead *beads.Issue) {
+	s.act("create %s issue for %s: %s", s.tracker.Name(), bead.ID, bead.Title)
+	if s.opts.DryRun {
+		return
+	}
+	body := strings.TrimSpace(bead.Description + "\n\nGas Town bead: " + bead.ID)
+	issue, err := s.tracker.Create(s.ctx, bead.Title, body, bead.Priority)
+	if err != nil {
+		s.warn("%s: creating issue: %v", bead.ID, err)
+		return
+	}
+	name := s.tracker.Name()
+	if err := s.store.Update(bead.ID, beads.UpdateOptions{AddLabels: []string{TrackerLabel(name), LinkLabel(name, issue.Key)}}); err != nil {
+		s.warn("%s: linking to %s: %v", bead.ID, issue.Key, err)
+		return
+	}
+	s.state.Links[issue.Key] = &Link{Bead: bead.ID, BeadOpen: true, BeadPriority: bead.Priority, BeadAssignee: bead.Assignee,
+		IssueOpen: true, IssuePriority: bead.Priority, IssueTitle: issue.Title}
+}
+
+// reconcile brings a linked bead and issue into step. Without a record of
+// the last sync (a lost state file, a bead linked by hand), a close on
+// either side wins and the issue's priority and title are taken.
+func (s *syncRun) reconcile(issue Issue, bead *beads.Issue) {
+	beadOpen := bead.Status != "closed"
+	last := s.state.Links[issue.Key]
+	if last == nil || last.Bead != bead.ID {
+		last = &Link{Bead: bead.ID, BeadOpen: true, BeadPriority: -1, BeadAssignee: bead.Assignee,
+			IssueOpen: true, IssuePriority: -2}
+	}
+	next := &Link{Bead: bead.ID, BeadOpen: beadOpen, BeadPriority: bead.Priority, BeadAssignee: bead.Assignee,
+		IssueOpen: issue.Open, IssuePriority: issue.Priority, IssueTitle: issue.Title}
+	failed := false
+	fail := func(format string, args ...interface{}) {
+		s.warn(format, args...)
+		failed = true
+	}
+
+	// Status: the side that changed since the last sync wins.
+	switch {
+	case issue.Open != last.IssueOpen && issue.Open != beadOpen:
+		if issue.Open {
+			s.act("reopen %s (%s reopened)", bead.ID, issue.Key)
+		} else {
+			s.act("close %s (%s closed)", bead.ID, issue.Key)
+		}
+		if !s.opts.DryRun {
+			var err error
+			if issue.Open {
+				status := "open"
+				err = s.store.Update(bead.ID, beads.UpdateOptions{Status: &status})
+			} else {
+				err = s.store.CloseWithReason("closed in "+s.tracker.Name()+" ("+issue.Key+")", bead.ID)
+			}
+			if err != nil {
+				fail("%s: updating status of %s: %v", issue.Key, bead.ID, err)
+			}
+		}
+		next.BeadOpen = issue.Open
+	case beadOpen != last.BeadOpen && beadOpen != issue.Open:
+		comment := fmt.Sprintf("Reopened in Gas Town (bead %s).", bead.ID)
+		if beadOpen {
+			s.act("reopen %s (%s reopened)", issue.Key, bead.ID)
+		} else {
+			comment = fmt.Sprintf("Completed in Gas Town: bead %s closed.", bead.ID)
+			s.act("close %s (%s closed)", issue.Key, bead.ID)
+		}
+		if !s.opts.DryRun {
+			if err := s.tracker.SetOpen(s.ctx, issue, beadOpen, comment); err != nil {
+				fail("%s: updating status: %v", issue.Key, err)
+			}
+		}
+		next.IssueOpen = beadOpen
+	}
+
+	// Priority, likewise.
+	switch {
+	case issue.Priority >= 0 && issue.Priority != last.IssuePriority && issue.Priority != bead.Priority:
+		s.act("set %s priority to P%d (from %s)", bead.ID, issue.Priority, issue.Key)
+		if !s.opts.DryRun {
+			priority := issue.Priority
+			if err := s.store.Update(bead.ID, beads.UpdateOptions{Priority: &priority}); err != nil {
+				fail("%s: updating priority of %s: %v", issue.Key, bead.ID, err)
+			}
+		}
+		next.BeadPriority = issue.Priority
+	case bead.Priority != issue.Priority && bead.Priority != last.BeadPriority:
+		s.act("set %s priority to P%d (from %s)", issue.Key, bead.Priority, bead.ID)
+		if !s.opts.DryRun {
+			if err := s.tracker.SetPriority(s.ctx, issue, bead.Priority); err != nil {
+				fail("%s: updating priority: %v", issue.Key, err)
+			}
+		}
+		next.IssuePriority = bead.Priority
+	}
+
+	// Title: edits in the tracker carry over.
+	if last.IssueTitle != "" && issue.Title != last.IssueTitle && issue.Title != bead.Title {
+		s.act("retitle %s (from %s)", bead.ID, issue.Key)
+		if !s.opts.DryRun {
+			title := issue.Title
+			if err := s.store.Update(bead.ID, beads.UpdateOptions{Title: &title}); err != nil {
+				fail("%s: updating title of %s: %v", issue.Key, bead.ID, err)
+			}
+		}
+	}
+
+	// Assignment: say in the issue who is working on it, and start it
+	// where the tracker can.
+	if bead.Assignee != "" && bead.Assignee != last.BeadAssignee && beadOpen {
+		starter, starts := s.tracker.(Starter)
+		if starts {
+			s.act("start %s: assigned to %s", issue.Key, bead.Assignee)
+		} else {
+			s.act("comment on %s: assigned to %s", issue.Key, bead.Assignee)
+		}
+		if !s.opts.DryRun {
+			comment := fmt.Sprintf("Assigned to %s in Gas Town (bead %s).", bead.Assignee, bead.ID)
+			if starts {
+				if err := starter.Start(s.ctx, issue, comment); err != nil {
+					fail("%s: starting: %v", issue.Key, err)
+				}
+			} else if err := s.tracker.Comment(s.ctx, issue, comment); err != nil {
+				fail("%s: commenting: %v", issue.Key, err)
+			}
+		}
+	}
+
+	if !s.opts.DryRun && !failed {
+		s.state.Links[issue.Key] = next
+	}
+}
@@ -0,0 +1,325 @@
+package issuesync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// fakeTracker is an in-memory Tracker that records the calls made to it.
+type fakeTracker struct {
+	issues map[string]*Issue
+	calls  []string
+}
+
+func (f *fakeTracker) Name() string { return "github" }
+
+func (f *fakeTracker) List(ctx context.Context) ([]Issue, error) {
+	var issues []Issue
+	for _, issue := range f.issues {
+		issues = append(issues, *issue)
+	}
+	return issues, nil
+}
+
+func (f *fakeTracker) Create(ctx context.Context, title, body string, priority int) (Issue, error) {
+	issue := Issue{Key: fmt.Sprintf("o/r#%d", len(f.issues)+1), Title: title, Body: body, Open: true, Priority: priority}
+	f.issues[issue.Key] = &issue
+	f.calls = append(f.calls, "create "+issue.Key)
+	return issue, nil
+}
+
+func (f *fakeTracker) SetOpen(ctx context.Context, issue Issue, open bool, comment string) error {
+	f.issues[issue.Key].Open = open
+	f.calls = append(f.calls, fmt.Sprintf("open %s %v: %s", issue.Key, open, comment))
+	return nil
+}
+
+func (f *fakeTracker) SetPriority(ctx context.Context, issue Issue, priority int) error {
+	f.issues[issue.Key].Priority = priority
+	f.calls = append(f.calls, fmt.Sprintf("priority %s %d", issue.Key, priority))
+	return nil
+}
+
+func (f *fakeTracker) Comment(ctx context.Context, issue Issue, body string) error {
+	f.calls = append(f.calls, fmt.Sprintf("comment %s: %s", issue.Key, body))
+	return nil
+}
+
+// fakeStore is an in-memory Store.
+type fakeStore struct {
+	beads map[string]*beads.Issue
+}
+
+func (f *fakeStore) List(opts beads.ListOptions) ([]*beads.Issue, error) {
+	var list []*beads.Issue
+	for _, bead := range f.beads {
+		if beads.HasLabel(bead, opts.Label) && (opts.Status != "open" || bead.Status != "closed") {
+			copied := *bead
+			list = append(list, &copied)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeStore) Create(opts beads.CreateOptions) (*beads.Issue, error) {
+	bead := &beads.Issue{ID: fmt.Sprintf("gt-%d", len(f.beads)+1), Title: opts.Title, Description: opts.Description,
+		Priority: opts.Priority, Type: opts.Type, Status: "open", Labels: opts.Labels}
+	f.beads[bead.ID] = bead
+	return bead, nil
+}
+
+func (f *fakeStore) Update(id string, opts beads.UpdateOptions) error {
+	bead := f.beads[id]
+	bead.Labels = append(bead.Labels, opts.AddLabels...)
+	if opts.Status != nil {
+		bead.Status = *opts.Status
+	}
+	if opts.Priority != nil {
+		bead.Priority = *opts.Priority
+	}
+	if opts.Title != nil {
+		bead.Title = *opts.Title
+	}
+	return nil
+}
+
+func (f *fakeStore) CloseWithReason(reason string, ids ...string) error {
+	for _, id := range ids {
+		f.beads[id].Status = "closed"
+	}
+	return nil
+}
+
+func TestSync_PullsAndReconciles(t *testing.T) {
+	ctx := context.Background()
+	tracker := &fakeTracker{issues: map[string]*Issue{
+		"o/r#1": {Key: "o/r#1", URL: "https://github.com/o/r/issues/1", Title: "Fix login", Body: "It breaks.", Open: true, Priority: 1, Labels: []string{"bug"}},
+		"o/r#2": {Key: "o/r#2", Title: "Old", Open: false, Priority: -1},
+		"o/r#3": {Key: "o/r#3", Title: "No priority", Open: true, Priority: -1},
+	}}
+	store := &fakeStore{beads: map[string]*beads.Issue{}}
+	state := &State{Links: map[string]*Link{}}
+
+	result, err := Sync(ctx, tracker, store, state, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Actions) != 2 || len(store.beads) != 2 {
+		t.Fatalf("actions = %v, beads = %d; want the two open issues pulled", result.Actions, len(store.beads))
+	}
+	bead := store.beads[state.Links["o/r#1"].Bead]
+	if bead.Priority != 1 || !strings.Contains(bead.Description, "Source: https://github.com/o/r/issues/1") {
+		t.Errorf("pulled bead = %+v", bead)
+	}
+	if want := []string{"gt:github", "github:o/r#1", "bug"}; !reflect.DeepEqual(bead.Labels, want) {
+		t.Errorf("labels = %v, want %v", bead.Labels, want)
+	}
+	if store.beads[state.Links["o/r#3"].Bead].Priority != DefaultPriority {
+		t.Error("issue with no priority should get the default")
+	}
+
+	// A second sync with nothing changed does nothing.
+	if result, _ := Sync(ctx, tracker, store, state, Options{}); len(result.Actions) != 0 || len(tracker.calls) != 0 {
+		t.Fatalf("idle sync acted: %v %v", result.Actions, tracker.calls)
+	}
+
+	// Gas Town assigns and finishes #1; #3 is reprioritized on GitHub.
+	bead.Assignee = "gastown/polecats/nux"
+	tracker.issues["o/r#3"].Priority = 0
+	if _, err := Sync(ctx, tracker, store, state, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	bead.Status = "closed"
+	if _, err := Sync(ctx, tracker, store, state, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"comment o/r#1: Assigned to gastown/polecats/nux in Gas Town (bead gt-1).",
+		"open o/r#1 false: Completed in Gas Town: bead gt-1 closed.",
+	}
+	if !reflect.DeepEqual(tracker.calls, want) {
+		t.Errorf("tracker calls = %v, want %v", tracker.calls, want)
+	}
+	if got := store.beads[state.Links["o/r#3"].Bead].Priority; got != 0 {
+		t.Errorf("#3 bead priority = %d, want 0 from GitHub", got)
+	}
+
+	// Closed on GitHub: the bead closes. Bead priority changed: GitHub follows.
+	tracker.calls = nil
+	tracker.issues["o/r#3"].Open = false
+	store.beads["gt-1"].Priority = 3
+	store.beads["gt-1"].Status = "open"
+	if _, err := Sync(ctx, tracker, store, state, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if store.beads[state.Links["o/r#3"].Bead].Status != "closed" {
+		t.Error("closing #3 on GitHub should close its bead")
+	}
+	want = []string{
+		"open o/r#1 true: Reopened in Gas Town (bead gt-1).",
+		"priority o/r#1 3",
+	}
+	if !reflect.DeepEqual(tracker.calls, want) {
+		t.Errorf("tracker calls = %v, want %v", tracker.calls, want)
+	}
+}
+
+func TestSync_ExportAndDryRun(t *testing.T) {
+	ctx := context.Background()
+	tracker := &fakeTracker{issues: map[string]*Issue{}}
+	store := &fakeStore{beads: map[string]*beads.Issue{
+		"gt-abc": {ID: "gt-abc", Title: "Ship it", Status: "open", Priority: 2, Labels: []string{"export"}},
+	}}
+	state := &State{Links: map[string]*Link{}}
+
+	result, err := Sync(ctx, tracker, store, state, Options{ExportLabel: "export", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Actions) != 1 || len(tracker.issues) != 0 || len(state.Links) != 0 {
+		t.Fatalf("dry run changed something: %v", result.Actions)
+	}
+
+	if _, err := Sync(ctx, tracker, store, state, Options{ExportLabel: "export"}); err != nil {
+		t.Fatal(err)
+	}
+	if key, ok := LinkedKey("github", store.beads["gt-abc"]); !ok || tracker.issues[key] == nil {
+		t.Fatalf("bead not linked to a new issue: %v", store.beads["gt-abc"].Labels)
+	}
+	if result, _ := Sync(ctx, tracker, store, state, Options{ExportLabel: "export"}); len(result.Actions) != 0 {
+		t.Errorf("exported bead exported again: %v", result.Actions)
+	}
+}
+
+func TestState_SaveLoad(t *testing.T) {
+	path := StatePath(t.TempDir(), "gastown", "github")
+	if filepath.Base(path) != "gastown-github.json" {
+		t.Errorf("StatePath = %s", path)
+	}
+	state, err := LoadState(path)
+	if err != nil || len(state.Links) != 0 {
+		t.Fatalf("LoadState of a missing file = %v, %v", state, err)
+	}
+	state.Links["o/r#1"] = &Link{Bead: "gt-1", BeadOpen: true, IssuePriority: -1}
+	if err := state.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadState(path)
+	if err != nil || !reflect.DeepEqual(loaded, state) {
+		t.Errorf("loaded %+v, %v; want %+v", loaded, err, state)
+	}
+}
+
+func TestGitHub_ListAndCreate(t *testing.T) {
+	var calls [][]string
+	gh := &GitHub{Repo: "o/r", Label: "gastown", run: func(ctx context.Context, args ...string) ([]byte, error) {
+		calls = append(calls, args)
+		if args[1] == "create" {
+			return []byte("https://github.com/o/r/issues/42\n"), nil
+		}
+		return []byte(`[{"number":7,"title":"T","body":"B","state":"OPEN","url":"u",
+			"labels":[{"name":"gastown"},{"name":"p3"},{"name":"P1"},{"name":"bug"}]}]`), nil
+	}}
+
+	issues, err := gh.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Issue{{Key: "o/r#7", URL: "u", Title: "T", Body: "B", Open: true, Priority: 1, Labels: []string{"bug"}}}
+	if !reflect.DeepEqual(issues, want) {
+		t.Errorf("List = %+v, want %+v", issues, want)
+	}
+
+	issue, err := gh.Create(context.Background(), "New", "body", 2)
+	if err != nil || issue.Key != "o/r#42" {
+		t.Fatalf("Create = %+v, %v", issue, err)
+	}
+	if err := gh.SetPriority(context.Background(), Issue{Key: "o/r#7", Priority: 1}, 0); err != nil {
+		t.Fatal(err)
+	}
+	last := strings.Join(calls[len(calls)-1], " ")
+	if last != "issue edit 7 --repo o/r --add-label P0 --remove-label P1" {
+		t.Errorf("SetPriority ran gh %s", last)
+	}
+}
+
+func TestJIRA_ListAndTransitions(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, strings.TrimSpace(r.Method+" "+r.URL.Path+" "+string(body)))
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me@example.com" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/rest/api/2/search":
+			if r.URL.Query().Get("jql") != "project = OPS" {
+				t.Errorf("jql = %q", r.URL.Query().Get("jql"))
+			}
+			fmt.Fprint(w, `{"total":2,"issues":[
+				{"key":"OPS-1","fields":{"summary":"Fix login","description":"It breaks.","labels":["auth"],
+					"status":{"statusCategory":{"key":"indeterminate"}},"priority":{"name":"High"}}},
+				{"key":"OPS-2","fields":{"summary":"Old","status":{"statusCategory":{"key":"done"}},"priority":{"name":"Urgent"}}}]}`)
+		case strings.HasSuffix(r.URL.Path, "/transitions") && r.Method == http.MethodGet:
+			fmt.Fprint(w, `{"transitions":[
+				{"id":"11","name":"Start","to":{"statusCategory":{"key":"indeterminate"}}},
+				{"id":"31","name":"Resolve","to":{"statusCategory":{"key":"done"}}}]}`)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("GT_JIRA_TOKEN", "secret")
+	t.Setenv("GT_JIRA_USER", "me@example.com")
+	jira := NewJIRA(&config.JIRASyncSettings{URL: srv.URL + "/", JQL: "project = OPS", Priorities: map[string]int{"Urgent": 0}})
+	ctx := context.Background()
+
+	issues, err := jira.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Issue{
+		{Key: "OPS-1", URL: srv.URL + "/browse/OPS-1", Title: "Fix login", Body: "It breaks.", Open: true, Priority: 1, Labels: []string{"auth"}},
+		{Key: "OPS-2", URL: srv.URL + "/browse/OPS-2", Title: "Old", Open: false, Priority: 0},
+	}
+	if !reflect.DeepEqual(issues, want) {
+		t.Errorf("List = %+v, want %+v", issues, want)
+	}
+
+	requests = nil
+	if err := jira.SetOpen(ctx, issues[0], false, "Done in Gas Town."); err != nil {
+		t.Fatal(err)
+	}
+	if err := jira.SetPriority(ctx, issues[0], 0); err != nil {
+		t.Fatal(err)
+	}
+	wantRequests := []string{
+		"GET /rest/api/2/issue/OPS-1/transitions",
+		`POST /rest/api/2/issue/OPS-1/transitions {"transition":{"id":"31"}}`,
+		`POST /rest/api/2/issue/OPS-1/comment {"body":"Done in Gas Town."}`,
+		`PUT /rest/api/2/issue/OPS-1 {"fields":{"priority":{"name":"Urgent"}}}`,
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("requests = %q, want %q", requests, wantRequests)
+	}
+
+	if _, err := jira.Create(ctx, "New", "body", 2); err == nil {
+		t.Error("Create without a project should fail")
+	}
+	jira.settings.DoneTransition = "Close"
+	if err := jira.SetOpen(ctx, issues[0], false, ""); err == nil || !strings.Contains(err.Error(), `no transition "Close"`) {
+		t.Errorf("a missing named transition should fail, got %v", err)
+	}
+}
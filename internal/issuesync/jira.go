@@ -0,0 +1,298 @@
+package issuesync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// JIRA credentials are read from the environment, to keep them out of rig
+// settings.
+const (
+	jiraUserEnv  = "GT_JIRA_USER"
+	jiraTokenEnv = "GT_JIRA_TOKEN"
+)
+
+// jiraPageSize is how many issues one search request returns.
+const jiraPageSize = 100
+
+// jiraIssueLimit caps how many issues one sync lists.
+const jiraIssueLimit = 1000
+
+// defaultJIRAPriorities maps JIRA's default priority schemes, old and new,
+// to bead priorities.
+var defaultJIRAPriorities = map[string]int{
+	"highest": 0, "blocker": 0,
+	"high": 1, "critical": 1,
+	"medium": 2, "major": 2,
+	"low": 3, "minor": 3,
+	"lowest": 4, "trivial": 4,
+}
+
+// JIRA syncs with the issues a JQL filter selects, through JIRA's REST
+// API (v2, which takes plain-text descriptions and comments). Issue keys
+// are JIRA's, e.g. "OPS-12"; priority is mapped from the issue's priority
+// name, and status changes run workflow transitions.
+type JIRA struct {
+	settings   config.JIRASyncSettings
+	priorities map[string]int // Lower-cased JIRA priority name -> bead priority
+	user       string
+	token      string
+	client     *http.Client
+}
+
+// NewJIRA returns a tracker for a JQL filter's issues, with credentials
+// from the environment.
+func NewJIRA(settings *config.JIRASyncSettings) *JIRA {
+	j := &JIRA{
+		settings:   *settings,
+		priorities: make(map[string]int),
+		user:       settings.User,
+		token:      os.Getenv(jiraTokenEnv),
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}
+	if j.user == "" {
+		j.user = os.Getenv(jiraUserEnv)
+	}
+	for name, p := range defaultJIRAPriorities {
+		j.priorities[name] = p
+	}
+	for name, p := range settings.Priorities {
+		j.priorities[strings.ToLower(name)] = p
+	}
+	return j
+}
+
+// Name implements Tracker.
+func (j *JIRA) Name() string { return "jira" }
+
+// jiraIssue is an issue as JIRA's search returns it.
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string   `json:"summary"`
+		Description string   `json:"description"`
+		Labels      []string `json:"labels"`
+		Status      struct {
+			StatusCategory struct {
+				Key string `json:"key"` // new, indeterminate or done
+			} `json:"statusCategory"`
+		} `json:"status"`
+		Priority *struct {
+			Name string `json:"name"`
+		} `json:"priority"`
+	} `json:"fields"`
+}
+
+// List implements Tracker.
+func (j *JIRA) List(ctx context.Context) ([]Issue, error) {
+	var issues []Issue
+	for start := 0; start < jiraIssueLimit; start += jiraPageSize {
+		query := url.Values{
+			"jql":        {j.settings.JQL},
+			"fields":     {"summary,description,labels,status,priority"},
+			"startAt":    {fmt.Sprint(start)},
+			"maxResults": {fmt.Sprint(jiraPageSize)},
+		}
+		var page struct {
+			Issues []jiraIssue `json:"issues"`
+			Total  int         `json:"total"`
+		}
+		if err := j.do(ctx, http.MethodGet, "/rest/api/2/search?"+query.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		for _, r := range page.Issues {
+			issue := Issue{
+				Key:      r.Key,
+				URL:      j.browseURL(r.Key),
+				Title:    r.Fields.Summary,
+				Body:     r.Fields.Description,
+				Open:     r.Fields.Status.StatusCategory.Key != "done",
+				Priority: -1,
+				Labels:   r.Fields.Labels,
+			}
+			if r.Fields.Priority != nil {
+				if p, ok := j.priorities[strings.ToLower(r.Fields.Priority.Name)]; ok {
+					issue.Priority = p
+				}
+			}
+			issues = append(issues, issue)
+		}
+		if len(page.Issues) < jiraPageSize || start+len(page.Issues) >= page.Total {
+			break
+		}
+	}
+	return issues, nil
+}
+
+// Create implements Tracker.
+func (j *JIRA) Create(ctx context.Context, title, body string, priority int) (Issue, error) {
+	if j.settings.Project == "" {
+		return Issue{}, fmt.Errorf("jira.project isn't set, so beads can't be exported")
+	}
+	issueType := j.settings.IssueType
+	if issueType == "" {
+		issueType = "Task"
+	}
+	fields := map[string]interface{}{
+		"project":     map[string]string{"key": j.settings.Project},
+		"issuetype":   map[string]string{"name": issueType},
+		"summary":     title,
+		"description": body,
+	}
+	if name := j.priorityName(priority); name != "" {
+		fields["priority"] = map[string]string{"name": name}
+	}
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := j.do(ctx, http.MethodPost, "/rest/api/2/issue", map[string]interface{}{"fields": fields}, &created); err != nil {
+		return Issue{}, err
+	}
+	return Issue{Key: created.Key, URL: j.browseURL(created.Key), Title: title, Body: body, Open: true, Priority: priority}, nil
+}
+
+// SetOpen implements Tracker by running the done or reopen transition.
+func (j *JIRA) SetOpen(ctx context.Context, issue Issue, open bool, comment string) error {
+	name, category := j.settings.DoneTransition, "done"
+	if open {
+		name, category = j.settings.ReopenTransition, "new"
+	}
+	if err := j.transition(ctx, issue, name, category); err != nil {
+		return err
+	}
+	return j.Comment(ctx, issue, comment)
+}
+
+// Start implements Starter by running the in-progress transition.
+func (j *JIRA) Start(ctx context.Context, issue Issue, comment string) error {
+	if err := j.transition(ctx, issue, j.settings.StartTransition, "indeterminate"); err != nil {
+		return err
+	}
+	return j.Comment(ctx, issue, comment)
+}
+
+// SetPriority implements Tracker.
+func (j *JIRA) SetPriority(ctx context.Context, issue Issue, priority int) error {
+	name := j.priorityName(priority)
+	if name == "" {
+		return fmt.Errorf("no JIRA priority maps to P%d", priority)
+	}
+	body := map[string]interface{}{"fields": map[string]interface{}{"priority": map[string]string{"name": name}}}
+	return j.do(ctx, http.MethodPut, "/rest/api/2/issue/"+url.PathEscape(issue.Key), body, nil)
+}
+
+// Comment implements Tracker.
+func (j *JIRA) Comment(ctx context.Context, issue Issue, body string) error {
+	return j.do(ctx, http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(issue.Key)+"/comment",
+		map[string]string{"body": body}, nil)
+}
+
+// transition runs the issue's transition named name or, if name is empty,
+// its first transition into a status of category.
+func (j *JIRA) transition(ctx context.Context, issue Issue, name, category string) error {
+	path := "/rest/api/2/issue/" + url.PathEscape(issue.Key) + "/transitions"
+	var available struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := j.do(ctx, http.MethodGet, path, nil, &available); err != nil {
+		return err
+	}
+	for _, t := range available.Transitions {
+		if (name != "" && strings.EqualFold(t.Name, name)) || (name == "" && t.To.StatusCategory.Key == category) {
+			return j.do(ctx, http.MethodPost, path, map[string]interface{}{"transition": map[string]string{"id": t.ID}}, nil)
+		}
+	}
+	if name != "" {
+		return fmt.Errorf("%s has no transition %q", issue.Key, name)
+	}
+	return fmt.Errorf("%s has no transition into a %s status", issue.Key, category)
+}
+
+// priorityName returns the JIRA priority a bead priority maps to: a name
+// configured for it (the first alphabetically, if several are), else
+// JIRA's current default scheme.
+func (j *JIRA) priorityName(priority int) string {
+	configured := ""
+	for name, p := range j.settings.Priorities {
+		if p == priority && (configured == "" || name < configured) {
+			configured = name
+		}
+	}
+	if configured != "" {
+		return configured
+	}
+	names := []string{"Highest", "High", "Medium", "Low", "Lowest"}
+	if priority < 0 || priority >= len(names) {
+		return ""
+	}
+	return names[priority]
+}
+
+func (j *JIRA) browseURL(key string) string {
+	return strings.TrimSuffix(j.settings.URL, "/") + "/browse/" + key
+}
+
+// do sends a JSON request to the JIRA API and decodes the response into
+// out, unless out is nil.
+func (j *JIRA) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(j.settings.URL, "/")+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case j.token == "":
+		return fmt.Errorf("%s isn't set", jiraTokenEnv)
+	case j.user != "":
+		req.SetBasicAuth(j.user, j.token)
+	default:
+		req.Header.Set("Authorization", "Bearer "+j.token)
+	}
+
+	endpoint, _, _ := strings.Cut(path, "?")
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("jira %s %s: %w", method, endpoint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("jira %s %s: %s: %s", method, endpoint, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("parsing jira response: %w", err)
+	}
+	return nil
+}
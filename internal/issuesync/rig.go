@@ -0,0 +1,64 @@
+package issuesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// RigTracker is a tracker a rig syncs with, and how.
+type RigTracker struct {
+	Tracker     Tracker
+	ExportLabel string
+}
+
+// RigTrackers returns the trackers a rig's settings sync with; none if
+// settings is nil.
+func RigTrackers(settings *config.IssueSyncSettings) []RigTracker {
+	var trackers []RigTracker
+	if settings == nil {
+		return trackers
+	}
+	if gh := settings.GitHub; gh != nil && gh.Repo != "" {
+		trackers = append(trackers, RigTracker{Tracker: NewGitHub(gh.Repo, gh.Label), ExportLabel: gh.ExportLabel})
+	}
+	if jira := settings.JIRA; jira != nil && jira.URL != "" && jira.JQL != "" {
+		trackers = append(trackers, RigTracker{Tracker: NewJIRA(jira), ExportLabel: jira.ExportLabel})
+	}
+	return trackers
+}
+
+// SyncRig syncs a rig's beads with each tracker its settings name, loading
+// and saving each tracker's state, and returns the results by tracker
+// name. A tracker that fails doesn't stop the others; the error joins
+// their failures.
+func SyncRig(ctx context.Context, townRoot, rigName string, settings *config.IssueSyncSettings, dryRun bool) (map[string]*Result, error) {
+	rigPath := filepath.Join(townRoot, rigName)
+	store := beads.NewWithBeadsDir(rigPath, beads.ResolveBeadsDir(rigPath)).WithSubsystem("issue-sync")
+
+	results := make(map[string]*Result)
+	var errs []error
+	for _, rt := range RigTrackers(settings) {
+		name := rt.Tracker.Name()
+		statePath := StatePath(townRoot, rigName, name)
+		state, err := LoadState(statePath)
+		if err == nil {
+			var result *Result
+			result, err = Sync(ctx, rt.Tracker, store, state, Options{ExportLabel: rt.ExportLabel, DryRun: dryRun})
+			if err == nil {
+				results[name] = result
+				if !dryRun {
+					err = state.Save(statePath)
+				}
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
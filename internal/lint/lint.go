@@ -0,0 +1,132 @@
+// Package lint validates a town's configuration as a whole: config file
+// schemas, role prompt overrides, rig remotes, cross-file policy
+// consistency, and deprecated settings. Unlike gt doctor it never touches
+// running agents or fixes anything, so it can run in CI against a checkout
+// of the town's own repo.
+package lint
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Severity ranks a finding.
+type Severity string
+
+const (
+	// SeverityError marks configuration that is broken or will be rejected.
+	SeverityError Severity = "error"
+	// SeverityWarning marks configuration that is ignored, stale, or suspect.
+	SeverityWarning Severity = "warning"
+)
+
+// Rules group findings by what they validate.
+const (
+	RuleSchema     = "schema"     // Config files parse, validate, and use known keys
+	RulePrompt     = "prompt"     // Role overrides reference existing prompt templates
+	RuleRemote     = "remote"     // Rigs exist locally and their remotes are reachable
+	RulePolicy     = "policy"     // Settings agree with each other
+	RuleDeprecated = "deprecated" // Settings that have been removed or replaced
+)
+
+// remoteTimeout bounds each git ls-remote probe.
+const remoteTimeout = 15 * time.Second
+
+// Finding is one problem in the town's configuration.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Path     string   `json:"path"` // Relative to the town root
+	Message  string   `json:"message"`
+}
+
+// String renders the finding as one line for terminal output.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s [%s]", f.Path, f.Message, f.Rule)
+}
+
+// Options control which rules run.
+type Options struct {
+	// Offline skips probing rig remotes over the network.
+	Offline bool
+}
+
+// Linter validates one town.
+type Linter struct {
+	townRoot string
+	opts     Options
+	findings []Finding
+
+	// lsRemote probes a git remote. Injected for testing.
+	lsRemote func(ctx context.Context, url string) error
+}
+
+// New creates a linter for the town at townRoot.
+func New(townRoot string, opts Options) *Linter {
+	return &Linter{townRoot: townRoot, opts: opts, lsRemote: gitLsRemote}
+}
+
+// Run applies every rule and returns the findings sorted by path.
+func (l *Linter) Run() []Finding {
+	l.findings = nil
+	town := l.lintSchemas()
+	l.lintRoles(town)
+	l.lintRigs(town)
+	l.lintPolicies(town)
+	l.lintDeprecated(town)
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		if l.findings[i].Path != l.findings[j].Path {
+			return l.findings[i].Path < l.findings[j].Path
+		}
+		return l.findings[i].Severity == SeverityError && l.findings[j].Severity != SeverityError
+	})
+	return l.findings
+}
+
+// Counts tallies findings by severity.
+func Counts(findings []Finding) (errors, warnings int) {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+	return errors, warnings
+}
+
+func (l *Linter) errorf(rule, path, format string, args ...interface{}) {
+	l.add(rule, SeverityError, path, format, args...)
+}
+
+func (l *Linter) warnf(rule, path, format string, args ...interface{}) {
+	l.add(rule, SeverityWarning, path, format, args...)
+}
+
+func (l *Linter) add(rule string, sev Severity, path, format string, args ...interface{}) {
+	l.findings = append(l.findings, Finding{Rule: rule, Severity: sev, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// gitLsRemote checks that url answers as a git remote without prompting
+// for credentials.
+func gitLsRemote(ctx context.Context, url string) error {
+	ctx, cancel := context.WithTimeout(ctx, remoteTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--quiet", url, "HEAD")
+	cmd.Env = append(cmd.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("timed out after %v", remoteTimeout)
+		}
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s", strings.SplitN(msg, "\n", 2)[0])
+		}
+		return err
+	}
+	return nil
+}
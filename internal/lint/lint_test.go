@@ -0,0 +1,227 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// setupTown writes a town with one rig ("gastown") whose config files are
+// what gt install and gt rig add produce.
+func setupTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	writeJSON(t, townRoot, "mayor/town.json", &config.TownConfig{Type: "town", Version: config.CurrentTownVersion, Name: "test"})
+	writeJSON(t, townRoot, "mayor/rigs.json", &config.RigsConfig{
+		Version: config.CurrentRigsVersion,
+		Rigs:    map[string]config.RigEntry{"gastown": {GitURL: "https://example.com/gastown.git"}},
+	})
+	writeJSON(t, townRoot, "mayor/config.json", config.NewMayorConfig())
+	writeJSON(t, townRoot, "mayor/daemon.json", config.NewDaemonPatrolConfig())
+	writeJSON(t, townRoot, "settings/config.json", config.NewTownSettings())
+	writeJSON(t, townRoot, "settings/escalation.json", config.NewEscalationConfig())
+	writeJSON(t, townRoot, "config/messaging.json", config.NewMessagingConfig())
+	writeJSON(t, townRoot, "gastown/config.json", config.NewRigConfig("gastown", "https://example.com/gastown.git"))
+	writeJSON(t, townRoot, "gastown/settings/config.json", config.NewRigSettings())
+	return townRoot
+}
+
+func writeJSON(t *testing.T, townRoot, rel string, v interface{}) {
+	t.Helper()
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, townRoot, rel, string(data))
+}
+
+func writeFile(t *testing.T, townRoot, rel, content string) {
+	t.Helper()
+	path := filepath.Join(townRoot, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// runLint lints townRoot with remotes answering per reachable.
+func runLint(t *testing.T, townRoot string, reachable bool) []Finding {
+	t.Helper()
+	l := New(townRoot, Options{})
+	l.lsRemote = func(context.Context, string) error {
+		if reachable {
+			return nil
+		}
+		return errors.New("Could not resolve host")
+	}
+	return l.Run()
+}
+
+// hasFinding reports whether a finding matches rule, path, and message substring.
+func hasFinding(findings []Finding, rule, path, substr string) bool {
+	for _, f := range findings {
+		if f.Rule == rule && f.Path == filepath.FromSlash(path) && strings.Contains(f.Message, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLint_CleanTown(t *testing.T) {
+	findings := runLint(t, setupTown(t), true)
+	if len(findings) != 0 {
+		t.Errorf("freshly installed town should lint clean, got:\n%v", findings)
+	}
+}
+
+func TestLint_Schema(t *testing.T) {
+	townRoot := setupTown(t)
+	writeFile(t, townRoot, "mayor/config.json", `{"type": "mayor-config",`)
+	writeFile(t, townRoot, "settings/config.json", `{"type": "town-settings", "defualt_agent": "claude"}`)
+	writeFile(t, townRoot, "gastown/settings/config.json",
+		`{"type": "rig-settings", "merge_queue": {"poll_interval": "soon", "on_conflict": "yolo"}}`)
+	if err := os.Remove(filepath.Join(townRoot, "gastown", "config.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	findings := runLint(t, townRoot, true)
+	for _, want := range []struct{ path, substr string }{
+		{"mayor/config.json", "invalid JSON: line 1"},
+		{"settings/config.json", `unknown key "defualt_agent"`},
+		{"gastown/settings/config.json", "invalid on_conflict"},
+		{"gastown/settings/config.json", `merge_queue.poll_interval: invalid duration "soon"`},
+		{"gastown/config.json", "missing"},
+	} {
+		if !hasFinding(findings, RuleSchema, want.path, want.substr) {
+			t.Errorf("missing schema finding %s: %q in:\n%v", want.path, want.substr, findings)
+		}
+	}
+}
+
+func TestLint_Roles(t *testing.T) {
+	townRoot := setupTown(t)
+	writeFile(t, townRoot, "roles/polecat.toml", "prompt_template = \"polecat-v2.md.tmpl\"\nnudge_text = \"go\"\n")
+	writeFile(t, townRoot, "roles/janitor.toml", "scope = \"town\"\n")
+	writeFile(t, townRoot, "gastown/roles/mayor.toml", "prompt_template = \"mayor.md.tmpl\"\n")
+
+	findings := runLint(t, townRoot, true)
+	if !hasFinding(findings, RulePrompt, "roles/polecat.toml", `"polecat-v2.md.tmpl" is not a known role template`) {
+		t.Errorf("missing prompt finding in:\n%v", findings)
+	}
+	if !hasFinding(findings, RuleSchema, "roles/polecat.toml", `unknown key "nudge_text"`) {
+		t.Errorf("missing unknown-key finding in:\n%v", findings)
+	}
+	if !hasFinding(findings, RuleSchema, "roles/janitor.toml", `unknown role "janitor"`) {
+		t.Errorf("missing unknown-role finding in:\n%v", findings)
+	}
+	if !hasFinding(findings, RuleSchema, "gastown/roles/mayor.toml", "town-level role") {
+		t.Errorf("missing town-role-in-rig finding in:\n%v", findings)
+	}
+	if hasFinding(findings, RulePrompt, "gastown/roles/mayor.toml", "") {
+		t.Error("mayor.md.tmpl is built in and should not be flagged")
+	}
+}
+
+func TestLint_Remotes(t *testing.T) {
+	townRoot := setupTown(t)
+	writeJSON(t, townRoot, "mayor/rigs.json", &config.RigsConfig{
+		Version: config.CurrentRigsVersion,
+		Rigs: map[string]config.RigEntry{
+			"gastown": {GitURL: "https://example.com/gastown.git"},
+			"beads":   {GitURL: "https://example.com/beads.git"},
+			"nowhere": {},
+		},
+	})
+
+	findings := runLint(t, townRoot, false)
+	for _, want := range []string{
+		`rig "beads" is registered but beads/ does not exist`,
+		`rig "gastown" remote https://example.com/gastown.git is unreachable: Could not resolve host`,
+		`rig "nowhere" has no git_url`,
+	} {
+		if !hasFinding(findings, RuleRemote, "mayor/rigs.json", want) {
+			t.Errorf("missing remote finding %q in:\n%v", want, findings)
+		}
+	}
+
+	l := New(townRoot, Options{Offline: true})
+	l.lsRemote = func(context.Context, string) error {
+		t.Error("offline lint should not probe remotes")
+		return nil
+	}
+	if hasFinding(l.Run(), RuleRemote, "mayor/rigs.json", "unreachable") {
+		t.Error("offline lint reported an unreachable remote")
+	}
+}
+
+func TestLint_Policies(t *testing.T) {
+	townRoot := setupTown(t)
+	settings := config.NewTownSettings()
+	settings.DefaultAgent = "claude-nightly"
+	settings.RoleAgents = map[string]string{"polecat": "claude", "janitor": "claude", "witness": "haiku-typo"}
+	settings.WorkerStatus = &config.WorkerStatusConfig{StaleThreshold: "1h", StuckThreshold: "30m"}
+	settings.Overrides = &config.ConfigOverrides{
+		Roles: map[string]map[string]interface{}{"polecat": {config.OverrideNukePolicy: "never"}},
+	}
+	settings.ActiveHours = &config.ActiveHoursConfig{Windows: []config.ActiveWindow{{Start: "22:00", End: "6am"}}}
+	writeJSON(t, townRoot, "settings/config.json", settings)
+
+	rigSettings := config.NewRigSettings()
+	rigSettings.Agents = map[string]*config.RuntimeConfig{"local-model": {Command: "local"}}
+	rigSettings.RoleAgents = map[string]string{"polecat": "local-model"}
+	writeJSON(t, townRoot, "gastown/settings/config.json", rigSettings)
+
+	findings := runLint(t, townRoot, true)
+	for _, want := range []string{
+		`default_agent "claude-nightly"`,
+		`role_agents: unknown role "janitor"`,
+		`role_agents.witness: agent "haiku-typo"`,
+		"worker_status.stale_threshold (1h) exceeds worker_status.stuck_threshold (30m)",
+		"overrides.roles.polecat.nuke_policy: never",
+		`active_hours.windows[0]: end: invalid time "6am"`,
+	} {
+		if !hasFinding(findings, RulePolicy, "settings/config.json", want) {
+			t.Errorf("missing policy finding %q in:\n%v", want, findings)
+		}
+	}
+	if hasFinding(findings, RulePolicy, "gastown/settings/config.json", "") {
+		t.Errorf("rig custom agent should resolve, got:\n%v", findings)
+	}
+}
+
+func TestLint_Deprecated(t *testing.T) {
+	townRoot := setupTown(t)
+	writeFile(t, townRoot, "gastown/settings/config.json",
+		`{"type": "rig-settings", "merge_queue": {"target_branch": "develop"}, "runtime": {"command": "claude"}}`)
+
+	findings := runLint(t, townRoot, true)
+	if !hasFinding(findings, RuleDeprecated, "gastown/settings/config.json", "merge_queue.target_branch is deprecated") {
+		t.Errorf("missing deprecated merge_queue finding in:\n%v", findings)
+	}
+	if !hasFinding(findings, RuleDeprecated, "gastown/settings/config.json", "runtime is deprecated") {
+		t.Errorf("missing deprecated runtime finding in:\n%v", findings)
+	}
+	if errs, warns := Counts(findings); errs != 0 || warns != 2 {
+		t.Errorf("Counts = %d errors, %d warnings; want 0, 2:\n%v", errs, warns, findings)
+	}
+}
+
+func TestBadDurations(t *testing.T) {
+	var raw interface{}
+	data := `{"heartbeat": {"interval": "3m"}, "patrols": [{"timeout": "forever"}], "stale_threshold": 5, "name": "abc"}`
+	if err := json.Unmarshal([]byte(data), &raw); err != nil {
+		t.Fatal(err)
+	}
+	got := badDurations(raw, "")
+	if len(got) != 1 || got[0] != `patrols.timeout: invalid duration "forever"` {
+		t.Errorf("badDurations = %v", got)
+	}
+}
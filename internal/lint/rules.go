@@ -0,0 +1,271 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/activehours"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/templates"
+)
+
+// lintRoles checks role override files in <town>/roles and <rig>/roles:
+// they must parse, name a known role, use known keys, and point
+// prompt_template at a template that exists.
+func (l *Linter) lintRoles(town *townConfigs) {
+	l.lintRoleDir("roles", false)
+	for _, name := range town.rigNames() {
+		l.lintRoleDir(filepath.Join(name, "roles"), true)
+	}
+}
+
+func (l *Linter) lintRoleDir(relDir string, inRig bool) {
+	files, _ := filepath.Glob(filepath.Join(l.townRoot, relDir, "*.toml"))
+	for _, path := range files {
+		rel := filepath.Join(relDir, filepath.Base(path))
+		role := strings.TrimSuffix(filepath.Base(path), ".toml")
+		if !contains(config.AllRoles(), role) {
+			l.warnf(RuleSchema, rel, "unknown role %q (ignored; valid roles: %s)", role, strings.Join(config.AllRoles(), ", "))
+			continue
+		}
+		if inRig && contains(config.TownRoles(), role) {
+			l.warnf(RuleSchema, rel, "%s is a town-level role; rig overrides for it have no effect", role)
+		}
+
+		var def config.RoleDefinition
+		md, err := toml.DecodeFile(path, &def)
+		if err != nil {
+			l.errorf(RuleSchema, rel, "invalid TOML: %v", err)
+			continue
+		}
+		for _, key := range md.Undecoded() {
+			l.warnf(RuleSchema, rel, "unknown key %q (ignored)", key.String())
+		}
+		if def.Role != "" && def.Role != role {
+			l.warnf(RuleSchema, rel, "role = %q does not match the file name", def.Role)
+		}
+		if def.PromptTemplate != "" && !templates.HasRoleTemplate(def.PromptTemplate) {
+			l.errorf(RulePrompt, rel, "prompt_template %q is not a known role template", def.PromptTemplate)
+		}
+	}
+}
+
+// lintRigs checks that registered rigs exist on disk and that their
+// remotes answer, probing remotes in parallel.
+func (l *Linter) lintRigs(town *townConfigs) {
+	if town.rigs == nil {
+		return
+	}
+	rigsRel := filepath.Join("mayor", "rigs.json")
+
+	type probe struct {
+		rig, url string
+		err      error
+	}
+	var probes []*probe
+	for _, name := range town.rigNames() {
+		entry := town.rigs.Rigs[name]
+		if _, err := os.Stat(filepath.Join(l.townRoot, name)); err != nil {
+			l.errorf(RuleRemote, rigsRel, "rig %q is registered but %s/ does not exist", name, name)
+		}
+		if entry.LocalRepo != "" {
+			if _, err := os.Stat(entry.LocalRepo); err != nil {
+				l.warnf(RuleRemote, rigsRel, "rig %q local_repo %s does not exist", name, entry.LocalRepo)
+			}
+		}
+		if entry.GitURL == "" {
+			l.errorf(RuleRemote, rigsRel, "rig %q has no git_url", name)
+			continue
+		}
+		if !l.opts.Offline {
+			probes = append(probes, &probe{rig: name, url: entry.GitURL})
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, p := range probes {
+		wg.Add(1)
+		go func(p *probe) {
+			defer wg.Done()
+			p.err = l.lsRemote(context.Background(), p.url)
+		}(p)
+	}
+	wg.Wait()
+	for _, p := range probes {
+		if p.err != nil {
+			l.errorf(RuleRemote, rigsRel, "rig %q remote %s is unreachable: %v", p.rig, p.url, p.err)
+		}
+	}
+}
+
+// lintPolicies checks settings that must agree with each other: agent
+// names resolve, role maps name real roles, overrides use valid values,
+// and paired thresholds are ordered.
+func (l *Linter) lintPolicies(town *townConfigs) {
+	_ = config.LoadAgentRegistry(config.DefaultAgentRegistryPath(l.townRoot))
+	settingsRel := filepath.Join("settings", "config.json")
+
+	if s := town.settings; s != nil {
+		if s.DefaultAgent != "" && !agentDefined(s.DefaultAgent, s, nil) {
+			l.errorf(RulePolicy, settingsRel, "default_agent %q is not a built-in preset or a configured agent", s.DefaultAgent)
+		}
+		l.lintRoleAgents(settingsRel, s.RoleAgents, s, nil)
+		l.lintOverrides(settingsRel, s.Overrides)
+		l.lintThresholds(settingsRel, s)
+		if _, err := activehours.Parse(s.ActiveHours); err != nil {
+			l.errorf(RulePolicy, settingsRel, "%v", err)
+		}
+	}
+
+	for _, name := range town.rigNames() {
+		rs := town.rigSettings[name]
+		if rs == nil {
+			continue
+		}
+		rel := filepath.Join(name, "settings", "config.json")
+		if rs.Agent != "" && !agentDefined(rs.Agent, town.settings, rs) {
+			l.errorf(RulePolicy, rel, "agent %q is not a built-in preset or a configured agent", rs.Agent)
+		}
+		l.lintRoleAgents(rel, rs.RoleAgents, town.settings, rs)
+		l.lintOverrides(rel, rs.Overrides)
+	}
+}
+
+func (l *Linter) lintRoleAgents(rel string, roleAgents map[string]string, town *config.TownSettings, rig *config.RigSettings) {
+	roles := sortedKeys(roleAgents)
+	for _, role := range roles {
+		if !isRoleName(role) {
+			l.errorf(RulePolicy, rel, "role_agents: unknown role %q", role)
+		}
+		if agent := roleAgents[role]; !agentDefined(agent, town, rig) {
+			l.errorf(RulePolicy, rel, "role_agents.%s: agent %q is not a built-in preset or a configured agent", role, agent)
+		}
+	}
+}
+
+func (l *Linter) lintOverrides(rel string, o *config.ConfigOverrides) {
+	if o == nil {
+		return
+	}
+	l.lintNukePolicy(rel, "overrides.defaults", o.Defaults)
+	for _, role := range sortedKeys(o.Roles) {
+		if !isRoleName(role) {
+			l.errorf(RulePolicy, rel, "overrides.roles: unknown role %q", role)
+		}
+		l.lintNukePolicy(rel, "overrides.roles."+role, o.Roles[role])
+	}
+	for _, addr := range sortedKeys(o.Agents) {
+		if _, err := config.ParseOverrideTarget(addr); err != nil {
+			l.errorf(RulePolicy, rel, "overrides.agents: %q: %v", addr, err)
+		}
+		l.lintNukePolicy(rel, "overrides.agents."+addr, o.Agents[addr])
+	}
+}
+
+func (l *Linter) lintNukePolicy(rel, scope string, values map[string]interface{}) {
+	v, ok := values[config.OverrideNukePolicy]
+	if !ok {
+		return
+	}
+	if s, _ := v.(string); s != config.NukePolicyAuto && s != config.NukePolicyManual {
+		l.errorf(RulePolicy, rel, "%s.%s: %v is not %q or %q", scope, config.OverrideNukePolicy, v, config.NukePolicyAuto, config.NukePolicyManual)
+	}
+}
+
+// lintThresholds checks paired town settings whose order matters.
+func (l *Linter) lintThresholds(rel string, s *config.TownSettings) {
+	if ws := s.WorkerStatus; ws != nil {
+		l.lintOrdered(rel, "worker_status.stale_threshold", ws.StaleThreshold, "worker_status.stuck_threshold", ws.StuckThreshold)
+	}
+	if wt := s.WebTimeouts; wt != nil {
+		l.lintOrdered(rel, "web_timeouts.default_run_timeout", wt.DefaultRunTimeout, "web_timeouts.max_run_timeout", wt.MaxRunTimeout)
+	}
+	if bb := s.BootBudget; bb != nil {
+		l.lintOrdered(rel, "boot_budget.shell_init", bb.ShellInit, "boot_budget.budget", bb.Budget)
+	}
+}
+
+// lintOrdered reports lowName exceeding highName when both are set and parse.
+func (l *Linter) lintOrdered(rel, lowName, low, highName, high string) {
+	lo, err1 := time.ParseDuration(low)
+	hi, err2 := time.ParseDuration(high)
+	if low == "" || high == "" || err1 != nil || err2 != nil {
+		return
+	}
+	if lo > hi {
+		l.errorf(RulePolicy, rel, "%s (%s) exceeds %s (%s)", lowName, low, highName, high)
+	}
+}
+
+// lintDeprecated reports settings that were removed or replaced.
+func (l *Linter) lintDeprecated(town *townConfigs) {
+	for _, name := range town.rigNames() {
+		rs := town.rigSettings[name]
+		if rs == nil {
+			continue
+		}
+		rel := filepath.Join(name, "settings", "config.json")
+
+		var raw struct {
+			MergeQueue map[string]json.RawMessage `json:"merge_queue"`
+		}
+		if json.Unmarshal(town.rigSettingsRaw[name], &raw) == nil {
+			for _, key := range config.DeprecatedMergeQueueKeys {
+				if _, ok := raw.MergeQueue[key]; ok {
+					l.warnf(RuleDeprecated, rel, "merge_queue.%s is deprecated and ignored (use rig default_branch instead)", key)
+				}
+			}
+		}
+
+		if rs.Runtime != nil {
+			if rs.Agent != "" {
+				l.warnf(RuleDeprecated, rel, "runtime is deprecated and ignored because agent is set")
+			} else {
+				l.warnf(RuleDeprecated, rel, "runtime is deprecated; select an agent preset with agent instead")
+			}
+		}
+	}
+}
+
+// agentDefined reports whether name is a custom agent in rig or town
+// settings or a registered preset. Unlike config.ValidateAgentConfig it
+// doesn't require the binary on PATH, so it holds in CI.
+func agentDefined(name string, town *config.TownSettings, rig *config.RigSettings) bool {
+	if rig != nil && rig.Agents[name] != nil {
+		return true
+	}
+	if town != nil && town.Agents[name] != nil {
+		return true
+	}
+	return config.GetAgentPresetByName(name) != nil
+}
+
+// isRoleName accepts every role plus boot, which role maps may name.
+func isRoleName(role string) bool {
+	return role == "boot" || contains(config.AllRoles(), role)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
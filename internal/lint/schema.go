@@ -0,0 +1,278 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/daemon"
+)
+
+// townConfigs is what the schema rule loaded, for the rules that follow.
+// Files that failed to parse are left nil.
+type townConfigs struct {
+	settings *config.TownSettings
+	rigs     *config.RigsConfig
+
+	rigSettings    map[string]*config.RigSettings // by rig name
+	rigSettingsRaw map[string][]byte              // by rig name
+}
+
+// jsonFile is one JSON config file and how its consumer reads it.
+type jsonFile struct {
+	rel      string             // Path relative to the town root
+	required bool               // Missing file is an error
+	schema   func() interface{} // Struct the consumer decodes into
+	validate func(path string) error
+	// deprecated strips keys the deprecated rule reports, so they aren't
+	// also flagged as unknown.
+	deprecated func(raw map[string]interface{})
+}
+
+// townFiles are the town-level JSON config files.
+var townFiles = []jsonFile{
+	{
+		rel:      filepath.Join(constants.DirMayor, "town.json"),
+		required: true,
+		schema:   func() interface{} { return &config.TownConfig{} },
+		validate: func(p string) error { _, err := config.LoadTownConfig(p); return err },
+	},
+	{
+		rel:      filepath.Join(constants.DirMayor, "rigs.json"),
+		required: true,
+		schema:   func() interface{} { return &config.RigsConfig{} },
+		validate: func(p string) error { _, err := config.LoadRigsConfig(p); return err },
+	},
+	{
+		rel:      filepath.Join(constants.DirMayor, "config.json"),
+		schema:   func() interface{} { return &config.MayorConfig{} },
+		validate: func(p string) error { _, err := config.LoadMayorConfig(p); return err },
+	},
+	{
+		rel:      filepath.Join(constants.DirMayor, config.DaemonPatrolConfigFileName),
+		schema:   func() interface{} { return &daemon.DaemonPatrolConfig{} },
+		validate: func(p string) error { _, err := config.LoadDaemonPatrolConfig(p); return err },
+	},
+	{
+		rel:    filepath.Join("settings", "config.json"),
+		schema: func() interface{} { return &config.TownSettings{} },
+	},
+	{
+		rel:      filepath.Join("settings", "escalation.json"),
+		schema:   func() interface{} { return &config.EscalationConfig{} },
+		validate: func(p string) error { _, err := config.LoadEscalationConfig(p); return err },
+	},
+	{
+		rel:      filepath.Join("config", "messaging.json"),
+		schema:   func() interface{} { return &config.MessagingConfig{} },
+		validate: func(p string) error { _, err := config.LoadMessagingConfig(p); return err },
+	},
+}
+
+// rigFiles are the JSON config files inside each rig.
+var rigFiles = []jsonFile{
+	{
+		rel:      "config.json",
+		required: true,
+		schema:   func() interface{} { return &config.RigConfig{} },
+		validate: func(p string) error { _, err := config.LoadRigConfig(p); return err },
+	},
+	{
+		rel:    filepath.Join("settings", "config.json"),
+		schema: func() interface{} { return &config.RigSettings{} },
+		validate: func(p string) error {
+			data, err := os.ReadFile(p) //nolint:gosec // G304: path is constructed internally
+			if err != nil {
+				return err
+			}
+			var s config.RigSettings
+			if err := json.Unmarshal(data, &s); err != nil {
+				return err
+			}
+			return config.ValidateRigSettings(&s)
+		},
+		deprecated: func(raw map[string]interface{}) {
+			if mq, ok := raw["merge_queue"].(map[string]interface{}); ok {
+				for _, key := range config.DeprecatedMergeQueueKeys {
+					delete(mq, key)
+				}
+			}
+		},
+	},
+}
+
+// durationKeySuffixes mark JSON keys whose string values are Go durations.
+var durationKeySuffixes = []string{"interval", "timeout", "threshold", "cooldown", "_age", "_window", "budget"}
+
+// lintSchemas checks every known JSON config file in the town and its rigs.
+func (l *Linter) lintSchemas() *townConfigs {
+	town := &townConfigs{
+		rigSettings:    make(map[string]*config.RigSettings),
+		rigSettingsRaw: make(map[string][]byte),
+	}
+
+	for _, f := range townFiles {
+		data := l.lintJSONFile(f.rel, f)
+		if data == nil {
+			continue
+		}
+		switch f.rel {
+		case filepath.Join(constants.DirMayor, "rigs.json"):
+			var rigs config.RigsConfig
+			if json.Unmarshal(data, &rigs) == nil {
+				town.rigs = &rigs
+			}
+		case filepath.Join("settings", "config.json"):
+			var settings config.TownSettings
+			if json.Unmarshal(data, &settings) == nil {
+				town.settings = &settings
+			}
+		}
+	}
+
+	for _, rigName := range town.rigNames() {
+		if _, err := os.Stat(filepath.Join(l.townRoot, rigName)); err != nil {
+			continue // reported by the remote rule
+		}
+		for _, f := range rigFiles {
+			rel := filepath.Join(rigName, f.rel)
+			data := l.lintJSONFile(rel, f)
+			if data == nil || f.rel != filepath.Join("settings", "config.json") {
+				continue
+			}
+			var settings config.RigSettings
+			if json.Unmarshal(data, &settings) == nil {
+				town.rigSettings[rigName] = &settings
+				town.rigSettingsRaw[rigName] = data
+			}
+		}
+	}
+	return town
+}
+
+// lintJSONFile reports a file that is missing, unparseable, rejected by its
+// loader, carries keys its consumer ignores, or holds malformed durations.
+// Returns the file's contents when it parsed.
+func (l *Linter) lintJSONFile(rel string, f jsonFile) []byte {
+	path := filepath.Join(l.townRoot, rel)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if !os.IsNotExist(err) {
+			l.errorf(RuleSchema, rel, "cannot read: %v", err)
+		} else if f.required {
+			l.errorf(RuleSchema, rel, "missing")
+		}
+		return nil
+	}
+
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		l.errorf(RuleSchema, rel, "invalid JSON: %s", describeJSONError(data, err))
+		return nil
+	}
+
+	if f.validate != nil {
+		if err := f.validate(path); err != nil {
+			l.errorf(RuleSchema, rel, "%v", err)
+		}
+	}
+
+	strict := data
+	if _, isObject := raw.(map[string]interface{}); isObject && f.deprecated != nil {
+		var stripped map[string]interface{}
+		_ = json.Unmarshal(data, &stripped)
+		f.deprecated(stripped)
+		if b, err := json.Marshal(stripped); err == nil {
+			strict = b
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(strict))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(f.schema()); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &typeErr):
+			l.errorf(RuleSchema, rel, "%s: expected %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+		case strings.HasPrefix(err.Error(), "json: unknown field "):
+			l.warnf(RuleSchema, rel, "unknown key %s (ignored)", strings.TrimPrefix(err.Error(), "json: unknown field "))
+		default:
+			l.errorf(RuleSchema, rel, "%v", err)
+		}
+	}
+
+	for _, d := range badDurations(raw, "") {
+		l.errorf(RuleSchema, rel, "%s", d)
+	}
+	return data
+}
+
+// badDurations walks decoded JSON for duration-valued keys that don't parse.
+func badDurations(v interface{}, prefix string) []string {
+	var bad []string
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			if s, ok := v[k].(string); ok && s != "" && isDurationKey(k) {
+				if _, err := time.ParseDuration(s); err != nil {
+					bad = append(bad, fmt.Sprintf("%s: invalid duration %q", key, s))
+				}
+				continue
+			}
+			bad = append(bad, badDurations(v[k], key)...)
+		}
+	case []interface{}:
+		for _, item := range v {
+			bad = append(bad, badDurations(item, prefix)...)
+		}
+	}
+	return bad
+}
+
+func isDurationKey(key string) bool {
+	for _, suffix := range durationKeySuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeJSONError adds the line number to a JSON syntax error.
+func describeJSONError(data []byte, err error) string {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line := 1 + bytes.Count(data[:min(int(syntaxErr.Offset), len(data))], []byte("\n"))
+		return fmt.Sprintf("line %d: %v", line, syntaxErr)
+	}
+	return err.Error()
+}
+
+// rigNames lists the registered rigs in order.
+func (t *townConfigs) rigNames() []string {
+	if t.rigs == nil {
+		return nil
+	}
+	names := make([]string, 0, len(t.rigs.Rigs))
+	for name := range t.rigs.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
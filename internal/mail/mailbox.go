@@ -191,6 +191,71 @@ func (m *Mailbox) listFromDir(beadsDir string) ([]*Message, error) {
 	return messages, nil
 }
 
+// ListOpenMessages returns every open or hooked direct message in the beads
+// database for workDir, whatever the recipient. Town-wide health checks use it
+// to avoid one bd query per mailbox. Queue and channel messages (no assignee)
+// are skipped.
+func ListOpenMessages(workDir string) ([]*Message, error) {
+	allMsgs, err := listAllBeadsMessages(workDir, false)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*Message, 0, len(allMsgs))
+	for i := range allMsgs {
+		bm := &allMsgs[i]
+		if bm.Assignee == "" || (bm.Status != "open" && bm.Status != "hooked") {
+			continue
+		}
+		messages = append(messages, bm.ToMessage())
+	}
+	return messages, nil
+}
+
+// ListAllMessages returns every message in the beads database for workDir,
+// read or unread, whatever the recipient. Used for town-wide exports.
+func ListAllMessages(workDir string) ([]*Message, error) {
+	allMsgs, err := listAllBeadsMessages(workDir, true)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]*Message, 0, len(allMsgs))
+	for i := range allMsgs {
+		messages = append(messages, allMsgs[i].ToMessage())
+	}
+	return messages, nil
+}
+
+// listAllBeadsMessages runs one bd query for every gt:message bead,
+// including closed (read) ones when includeClosed is set.
+func listAllBeadsMessages(workDir string, includeClosed bool) ([]BeadsMessage, error) {
+	beadsDir := beads.ResolveBeadsDir(workDir)
+	args := []string{"list",
+		"--label", "gt:message",
+		"--json",
+		"--limit", "0",
+	}
+	if includeClosed {
+		args = append(args, "--status=all")
+	}
+
+	ctx, cancel := bdReadCtx()
+	defer cancel()
+	stdout, err := runBdCommand(ctx, args, workDir, beadsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var allMsgs []BeadsMessage
+	if err := json.Unmarshal(stdout, &allMsgs); err != nil {
+		if len(stdout) == 0 || string(stdout) == "null" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return allMsgs, nil
+}
+
 // identityVariants returns all identity formats to query.
 // For town-level agents (mayor/, deacon/), also includes the variant without
 // trailing slash for backwards compatibility with legacy messages.
@@ -394,17 +394,10 @@ func agentBeadToAddress(bead *agentBead) string {
 //   - bd-beads-crew-beavis → beads/beavis
 func parseRigAgentAddress(bead *agentBead) string {
 	// Parse rig and role_type from description
-	var roleType, rig string
-	for _, line := range strings.Split(bead.Description, "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "role_type:") {
-			roleType = strings.TrimSpace(strings.TrimPrefix(line, "role_type:"))
-		} else if strings.HasPrefix(line, "rig:") {
-			rig = strings.TrimSpace(strings.TrimPrefix(line, "rig:"))
-		}
-	}
+	fields := beads.ParseAgentFields(bead.Description)
+	roleType, rig := fields.RoleType, fields.Rig
 
-	if rig == "" || rig == "null" || roleType == "" || roleType == "null" {
+	if rig == "" || roleType == "" {
 		// Fallback: parse from bead ID by scanning for known role markers.
 		// ID format: <prefix>-<rig>-<role>[-<name>]
 		// Known rig-level roles: crew, polecat, witness, refinery
@@ -506,20 +499,17 @@ func parseRigAgentAddressFromID(id string) string {
 
 // parseAgentAddressFromDescription extracts agent address from description metadata.
 // Looks for "location: X" first (explicit address), then falls back to
-// "role_type: X" and "rig: Y" patterns in the description.
+// the agent's role_type and rig fields.
 func parseAgentAddressFromDescription(desc string) string {
-	var roleType, rig, location string
-
+	var location string
 	for _, line := range strings.Split(desc, "\n") {
 		line = strings.TrimSpace(line)
 		if strings.HasPrefix(line, "location:") {
 			location = strings.TrimSpace(strings.TrimPrefix(line, "location:"))
-		} else if strings.HasPrefix(line, "role_type:") {
-			roleType = strings.TrimSpace(strings.TrimPrefix(line, "role_type:"))
-		} else if strings.HasPrefix(line, "rig:") {
-			rig = strings.TrimSpace(strings.TrimPrefix(line, "rig:"))
 		}
 	}
+	fields := beads.ParseAgentFields(desc)
+	roleType, rig := fields.RoleType, fields.Rig
 
 	// Explicit location takes priority (used by dogs and other agents
 	// whose address can't be derived from role_type + rig alone)
@@ -527,11 +517,8 @@ func parseAgentAddressFromDescription(desc string) string {
 		return location
 	}
 
-	// Handle null values from description
-	if rig == "null" || rig == "" {
-		rig = ""
-	}
-	if roleType == "null" || roleType == "" {
+	// ParseAgentFields reads "null" values as empty
+	if roleType == "" {
 		return ""
 	}
 
@@ -599,7 +586,7 @@ func (r *Router) resolveOverseer() ([]string, error) {
 // resolveTownAgents resolves @town to all town-level agents (mayor, deacon).
 func (r *Router) resolveTownAgents() ([]string, error) {
 	// Town-level agents have rig=null in their description
-	agents := r.queryAgents("rig: null")
+	agents := r.queryAgentsWithField("rig", "null")
 
 	var addresses []string
 	for _, agent := range agents {
@@ -614,16 +601,13 @@ func (r *Router) resolveTownAgents() ([]string, error) {
 // resolveAgentsByRole resolves agents by their role_type.
 // If rig is non-empty, also filters by rig.
 func (r *Router) resolveAgentsByRole(roleType, rig string) ([]string, error) {
-	// Build query filter
-	query := "role_type: " + roleType
-	agents := r.queryAgents(query)
+	agents := r.queryAgentsWithField("role_type", roleType)
 
 	var addresses []string
 	for _, agent := range agents {
 		// Filter by rig if specified
 		if rig != "" {
-			// Check if agent's description contains matching rig
-			if !strings.Contains(agent.Description, "rig: "+rig) {
+			if beads.ParseAgentFields(agent.Description).Rig != rig {
 				continue
 			}
 		}
@@ -638,8 +622,7 @@ func (r *Router) resolveAgentsByRole(roleType, rig string) ([]string, error) {
 // resolveAgentsByRig resolves @rig/<rigname> to all agents in that rig.
 func (r *Router) resolveAgentsByRig(rig string) ([]string, error) {
 	// Query for agents with matching rig in description
-	query := "rig: " + rig
-	agents := r.queryAgents(query)
+	agents := r.queryAgentsWithField("rig", rig)
 
 	var addresses []string
 	for _, agent := range agents {
@@ -651,6 +634,23 @@ func (r *Router) resolveAgentsByRig(rig string) ([]string, error) {
 	return addresses, nil
 }
 
+// queryAgentsWithField queries agent beads whose field key is value, in
+// each form beads.AgentFieldPatterns gives while agent beads are migrated
+// to the JSON fields block.
+func (r *Router) queryAgentsWithField(key, value string) []*agentBead {
+	seen := make(map[string]bool)
+	var agents []*agentBead
+	for _, pattern := range beads.AgentFieldPatterns(key, value) {
+		for _, agent := range r.queryAgents(pattern) {
+			if !seen[agent.ID] {
+				seen[agent.ID] = true
+				agents = append(agents, agent)
+			}
+		}
+	}
+	return agents
+}
+
 // queryAgents queries agent beads using bd list with description filtering.
 // Searches both town-level and rig-level beads to find all agents.
 func (r *Router) queryAgents(descContains string) []*agentBead {
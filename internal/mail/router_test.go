@@ -1,12 +1,15 @@
 package mail
 
 import (
+	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/session"
 )
 
@@ -873,6 +876,55 @@ func TestParseAgentAddressFromDescription(t *testing.T) {
 	}
 }
 
+func TestParseAgentAddressFromDescription_HookedDog(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell bd stub")
+	}
+	dir := t.TempDir()
+	binDir := filepath.Join(dir, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	dogDesc := "Dog: alpha\n\nrole_type: dog\nrig: town\nlocation: deacon/dogs/alpha"
+	show, err := json.Marshal([]map[string]string{{"id": "hq-dog-alpha", "title": "Dog: alpha", "description": dogDesc}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	showPath := filepath.Join(dir, "show.json")
+	descPath := filepath.Join(dir, "desc")
+	if err := os.WriteFile(showPath, show, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// The stub shows the dog bead and keeps the description an update writes.
+	script := `#!/bin/sh
+for a in "$@"; do
+  case "$a" in
+    show) cat "` + showPath + `" ;;
+    --description=*) printf '%s' "${a#--description=}" > "` + descPath + `" ;;
+  esac
+done
+exit 0
+`
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if err := beads.New(dir).SetHookBead("hq-dog-alpha", "hq-abc"); err != nil {
+		t.Fatalf("SetHookBead: %v", err)
+	}
+	desc, err := os.ReadFile(descPath)
+	if err != nil {
+		t.Fatalf("hooking didn't bump the revision: %v", err)
+	}
+	if got := parseAgentAddressFromDescription(string(desc)); got != "deacon/dogs/alpha" {
+		t.Errorf("address after hooking = %q, want deacon/dogs/alpha; description:\n%s", got, desc)
+	}
+	if fields := beads.ParseAgentFields(string(desc)); fields.Revision != 1 || fields.RoleType != "dog" {
+		t.Errorf("fields after hooking = %+v, want a dog at revision 1", fields)
+	}
+}
+
 func TestExpandAnnounce(t *testing.T) {
 	// Create temp directory with messaging config
 	tmpDir := t.TempDir()
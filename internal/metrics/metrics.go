@@ -0,0 +1,147 @@
+// Package metrics collects key Gas Town health metrics and pushes them to
+// StatsD-compatible agents (plain StatsD or DogStatsD/Datadog) for operators
+// whose monitoring is push-based.
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// Kind is the StatsD metric type.
+type Kind string
+
+const (
+	// Gauge is a point-in-time value.
+	Gauge Kind = "g"
+	// Count is a delta since the last push.
+	Count Kind = "c"
+)
+
+// Metric is a single named value with optional tags.
+type Metric struct {
+	Name  string            `json:"name"`
+	Value float64           `json:"value"`
+	Kind  Kind              `json:"kind"`
+	Tags  map[string]string `json:"tags,omitempty"`
+}
+
+// Snapshot is the process-level state Collect cannot read on its own.
+// The daemon fills it from memory; the CLI from daemon/state.json.
+type Snapshot struct {
+	// Sessions are the running tmux session names.
+	Sessions []string
+
+	// DaemonRunning reports whether the daemon process is alive.
+	DaemonRunning bool
+
+	// HeartbeatCount is the daemon's completed heartbeat count.
+	HeartbeatCount int64
+
+	// LastHeartbeat is when the daemon last completed a heartbeat.
+	LastHeartbeat time.Time
+}
+
+// Collect gathers the key town metrics:
+//   - sessions.active          running agent sessions, tagged by role (and rig)
+//   - polecats.count           polecat worktrees per rig
+//   - nudge_queue.pending      queued nudges across all agent sessions
+//   - daemon.running           1 if the daemon is running, else 0
+//   - daemon.heartbeats        completed daemon heartbeats
+//   - daemon.heartbeat_age_seconds  seconds since the last daemon heartbeat
+//
+// Metric names are unprefixed; the configured prefix is applied at push time.
+func Collect(townRoot string, snap Snapshot, now time.Time) []Metric {
+	var out []Metric
+
+	// Agent sessions by role and rig
+	type roleRig struct{ role, rig string }
+	sessionCounts := make(map[roleRig]int)
+	pendingNudges := 0
+	for _, name := range snap.Sessions {
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue
+		}
+		sessionCounts[roleRig{string(id.Role), id.Rig}]++
+		if n, err := nudge.Pending(townRoot, name); err == nil {
+			pendingNudges += n
+		}
+	}
+	keys := make([]roleRig, 0, len(sessionCounts))
+	for k := range sessionCounts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].role != keys[j].role {
+			return keys[i].role < keys[j].role
+		}
+		return keys[i].rig < keys[j].rig
+	})
+	for _, k := range keys {
+		tags := map[string]string{"role": k.role}
+		if k.rig != "" {
+			tags["rig"] = k.rig
+		}
+		out = append(out, Metric{Name: "sessions.active", Value: float64(sessionCounts[k]), Kind: Gauge, Tags: tags})
+	}
+	out = append(out, Metric{Name: "nudge_queue.pending", Value: float64(pendingNudges), Kind: Gauge})
+
+	// Polecat worktrees per registered rig
+	if rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json")); err == nil {
+		rigNames := make([]string, 0, len(rigsConfig.Rigs))
+		for name := range rigsConfig.Rigs {
+			rigNames = append(rigNames, name)
+		}
+		sort.Strings(rigNames)
+		for _, rigName := range rigNames {
+			out = append(out, Metric{
+				Name:  "polecats.count",
+				Value: float64(countDirs(filepath.Join(townRoot, rigName, "polecats"))),
+				Kind:  Gauge,
+				Tags:  map[string]string{"rig": rigName},
+			})
+		}
+	}
+
+	// Daemon liveness
+	running := 0.0
+	if snap.DaemonRunning {
+		running = 1
+	}
+	out = append(out,
+		Metric{Name: "daemon.running", Value: running, Kind: Gauge},
+		Metric{Name: "daemon.heartbeats", Value: float64(snap.HeartbeatCount), Kind: Gauge},
+	)
+	if !snap.LastHeartbeat.IsZero() {
+		out = append(out, Metric{
+			Name:  "daemon.heartbeat_age_seconds",
+			Value: now.Sub(snap.LastHeartbeat).Seconds(),
+			Kind:  Gauge,
+		})
+	}
+
+	return out
+}
+
+// countDirs returns the number of non-hidden subdirectories of dir.
+func countDirs(dir string) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			n++
+		}
+	}
+	return n
+}
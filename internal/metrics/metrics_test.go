@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func findMetric(ms []Metric, name string, tags map[string]string) *Metric {
+	for i := range ms {
+		if ms[i].Name != name || len(ms[i].Tags) != len(tags) {
+			continue
+		}
+		match := true
+		for k, v := range tags {
+			if ms[i].Tags[k] != v {
+				match = false
+			}
+		}
+		if match {
+			return &ms[i]
+		}
+	}
+	return nil
+}
+
+func TestCollect(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, dir := range []string{"mayor", "gastown/polecats/Toast", "gastown/polecats/Nux", "gastown/polecats/.claude", "beads/polecats"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rigs := `{"version":1,"rigs":{"gastown":{"git_url":"x"},"beads":{"git_url":"y"}}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "rigs.json"), []byte(rigs), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reg := session.NewPrefixRegistry()
+	reg.Register("gt", "gastown")
+	old := session.DefaultRegistry()
+	session.SetDefaultRegistry(reg)
+	t.Cleanup(func() { session.SetDefaultRegistry(old) })
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ms := Collect(townRoot, Snapshot{
+		Sessions:       []string{"hq-mayor", "gt-Toast", "gt-Nux", "gt-witness", "not-an-agent"},
+		DaemonRunning:  true,
+		HeartbeatCount: 7,
+		LastHeartbeat:  now.Add(-90 * time.Second),
+	}, now)
+
+	checks := []struct {
+		name string
+		tags map[string]string
+		want float64
+	}{
+		{"sessions.active", map[string]string{"role": "mayor"}, 1},
+		{"sessions.active", map[string]string{"role": "polecat", "rig": "gastown"}, 2},
+		{"sessions.active", map[string]string{"role": "witness", "rig": "gastown"}, 1},
+		{"polecats.count", map[string]string{"rig": "gastown"}, 2},
+		{"polecats.count", map[string]string{"rig": "beads"}, 0},
+		{"nudge_queue.pending", nil, 0},
+		{"daemon.running", nil, 1},
+		{"daemon.heartbeats", nil, 7},
+		{"daemon.heartbeat_age_seconds", nil, 90},
+	}
+	for _, c := range checks {
+		m := findMetric(ms, c.name, c.tags)
+		if m == nil {
+			t.Errorf("missing %s %v in %+v", c.name, c.tags, ms)
+			continue
+		}
+		if m.Value != c.want {
+			t.Errorf("%s %v = %v, want %v", c.name, c.tags, m.Value, c.want)
+		}
+	}
+}
+
+func TestCollect_NoDaemonHeartbeat(t *testing.T) {
+	ms := Collect(t.TempDir(), Snapshot{}, time.Now())
+	if m := findMetric(ms, "daemon.running", nil); m == nil || m.Value != 0 {
+		t.Errorf("daemon.running = %+v, want 0", m)
+	}
+	if m := findMetric(ms, "daemon.heartbeat_age_seconds", nil); m != nil {
+		t.Errorf("heartbeat age should be omitted without a heartbeat, got %+v", m)
+	}
+}
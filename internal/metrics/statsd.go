@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Flavor selects the StatsD wire dialect.
+type Flavor string
+
+const (
+	// FlavorStatsD is plain StatsD. It has no tags, so tag values are folded
+	// into the metric name (gt.polecats.count.gastown).
+	FlavorStatsD Flavor = "statsd"
+
+	// FlavorDatadog is DogStatsD, which carries tags natively
+	// (gt.polecats.count:3|g|#rig:gastown).
+	FlavorDatadog Flavor = "datadog"
+)
+
+// Defaults for StatsD push.
+const (
+	DefaultStatsDAddress = "127.0.0.1:8125"
+	DefaultPrefix        = "gt"
+
+	// maxPacketSize keeps each UDP datagram under a typical Ethernet MTU so
+	// agents never receive truncated packets.
+	maxPacketSize = 1432
+
+	dialTimeout = 2 * time.Second
+)
+
+// StatsDOptions configures a push.
+type StatsDOptions struct {
+	// Address is the agent's host:port (default 127.0.0.1:8125).
+	Address string
+
+	// Prefix is prepended to every metric name (default "gt").
+	Prefix string
+
+	// Tags are "key:value" pairs added to every metric.
+	Tags []string
+
+	// Flavor is the wire dialect (default statsd).
+	Flavor Flavor
+}
+
+func (o StatsDOptions) withDefaults() StatsDOptions {
+	if o.Address == "" {
+		o.Address = DefaultStatsDAddress
+	}
+	if o.Flavor == "" {
+		o.Flavor = FlavorStatsD
+	}
+	return o
+}
+
+// ParseFlavor validates a flavor name. "dogstatsd" is accepted as an alias for datadog.
+func ParseFlavor(s string) (Flavor, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", string(FlavorStatsD):
+		return FlavorStatsD, nil
+	case string(FlavorDatadog), "dogstatsd":
+		return FlavorDatadog, nil
+	}
+	return "", fmt.Errorf("unknown statsd flavor %q (want statsd or datadog)", s)
+}
+
+// FormatLine renders one metric in the requested dialect.
+func FormatLine(m Metric, opts StatsDOptions) string {
+	opts = opts.withDefaults()
+	name := sanitizeName(m.Name)
+	if opts.Prefix != "" {
+		name = sanitizeName(opts.Prefix) + "." + name
+	}
+
+	kind := m.Kind
+	if kind == "" {
+		kind = Gauge
+	}
+	value := strconv.FormatFloat(m.Value, 'f', -1, 64)
+
+	tagKeys := make([]string, 0, len(m.Tags))
+	for k := range m.Tags {
+		tagKeys = append(tagKeys, k)
+	}
+	sort.Strings(tagKeys)
+
+	if opts.Flavor != FlavorDatadog {
+		for _, k := range tagKeys {
+			name += "." + sanitizeName(m.Tags[k])
+		}
+		return fmt.Sprintf("%s:%s|%s", name, value, kind)
+	}
+
+	tags := make([]string, 0, len(opts.Tags)+len(tagKeys))
+	tags = append(tags, opts.Tags...)
+	for _, k := range tagKeys {
+		tags = append(tags, k+":"+m.Tags[k])
+	}
+	line := fmt.Sprintf("%s:%s|%s", name, value, kind)
+	if len(tags) > 0 {
+		line += "|#" + strings.Join(tags, ",")
+	}
+	return line
+}
+
+// Push sends metrics to a StatsD agent over UDP, batching lines into
+// MTU-sized datagrams. Delivery is fire-and-forget, as StatsD intends;
+// only dial and write errors are reported.
+func Push(metrics []Metric, opts StatsDOptions) error {
+	opts = opts.withDefaults()
+	conn, err := net.DialTimeout("udp", opts.Address, dialTimeout)
+	if err != nil {
+		return fmt.Errorf("dialing statsd %s: %w", opts.Address, err)
+	}
+	defer conn.Close()
+
+	for _, packet := range packets(metrics, opts) {
+		if _, err := conn.Write([]byte(packet)); err != nil {
+			return fmt.Errorf("writing to statsd %s: %w", opts.Address, err)
+		}
+	}
+	return nil
+}
+
+// packets groups formatted lines into newline-separated datagrams no larger
+// than maxPacketSize (a single oversized line gets its own datagram).
+func packets(metrics []Metric, opts StatsDOptions) []string {
+	var out []string
+	var b strings.Builder
+	for _, m := range metrics {
+		line := FormatLine(m, opts)
+		if b.Len() > 0 && b.Len()+1+len(line) > maxPacketSize {
+			out = append(out, b.String())
+			b.Reset()
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(line)
+	}
+	if b.Len() > 0 {
+		out = append(out, b.String())
+	}
+	return out
+}
+
+// sanitizeName replaces characters that are reserved in the StatsD line
+// protocol (':', '|', '@', '#', ',') and whitespace with underscores.
+func sanitizeName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', '@', '#', ',', ' ', '\t', '\n', '/':
+			return '_'
+		}
+		return r
+	}, s)
+}
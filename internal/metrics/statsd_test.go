@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatLine(t *testing.T) {
+	m := Metric{Name: "polecats.count", Value: 3, Kind: Gauge, Tags: map[string]string{"rig": "gastown"}}
+
+	tests := []struct {
+		name string
+		opts StatsDOptions
+		want string
+	}{
+		{"statsd folds tags", StatsDOptions{Prefix: "gt"}, "gt.polecats.count.gastown:3|g"},
+		{"statsd ignores global tags", StatsDOptions{Prefix: "gt", Tags: []string{"env:prod"}}, "gt.polecats.count.gastown:3|g"},
+		{"datadog tags", StatsDOptions{Prefix: "gt", Flavor: FlavorDatadog, Tags: []string{"env:prod"}}, "gt.polecats.count:3|g|#env:prod,rig:gastown"},
+		{"no prefix", StatsDOptions{Flavor: FlavorDatadog}, "polecats.count:3|g|#rig:gastown"},
+	}
+	for _, tt := range tests {
+		if got := FormatLine(m, tt.opts); got != tt.want {
+			t.Errorf("%s: FormatLine = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	fractional := Metric{Name: "daemon.heartbeat_age_seconds", Value: 12.5}
+	if got := FormatLine(fractional, StatsDOptions{}); got != "daemon.heartbeat_age_seconds:12.5|g" {
+		t.Errorf("fractional gauge = %q", got)
+	}
+
+	reserved := Metric{Name: "x", Value: 1, Kind: Count, Tags: map[string]string{"rig": "a:b|c"}}
+	if got := FormatLine(reserved, StatsDOptions{}); got != "x.a_b_c:1|c" {
+		t.Errorf("reserved characters not sanitized: %q", got)
+	}
+}
+
+func TestParseFlavor(t *testing.T) {
+	for in, want := range map[string]Flavor{"": FlavorStatsD, "statsd": FlavorStatsD, "Datadog": FlavorDatadog, "dogstatsd": FlavorDatadog} {
+		got, err := ParseFlavor(in)
+		if err != nil || got != want {
+			t.Errorf("ParseFlavor(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFlavor("graphite"); err == nil {
+		t.Error("expected error for unknown flavor")
+	}
+}
+
+func TestPackets_SplitAtMTU(t *testing.T) {
+	var ms []Metric
+	for i := 0; i < 200; i++ {
+		ms = append(ms, Metric{Name: "sessions.active", Value: float64(i), Tags: map[string]string{"role": "polecat", "rig": "gastown"}})
+	}
+	pkts := packets(ms, StatsDOptions{Prefix: "gt", Flavor: FlavorDatadog})
+	if len(pkts) < 2 {
+		t.Fatalf("expected metrics split across packets, got %d", len(pkts))
+	}
+	lines := 0
+	for _, p := range pkts {
+		if len(p) > maxPacketSize {
+			t.Errorf("packet of %d bytes exceeds %d", len(p), maxPacketSize)
+		}
+		lines += len(strings.Split(p, "\n"))
+	}
+	if lines != len(ms) {
+		t.Errorf("packets carry %d lines, want %d", lines, len(ms))
+	}
+}
+
+func TestPush(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("udp unavailable: %v", err)
+	}
+	defer conn.Close()
+
+	ms := []Metric{
+		{Name: "daemon.running", Value: 1, Kind: Gauge},
+		{Name: "nudge_queue.pending", Value: 4, Kind: Gauge},
+	}
+	if err := Push(ms, StatsDOptions{Address: conn.LocalAddr().String(), Prefix: "town"}); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	buf := make([]byte, maxPacketSize)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("reading packet: %v", err)
+	}
+	want := "town.daemon.running:1|g\ntown.nudge_queue.pending:4|g"
+	if got := string(buf[:n]); got != want {
+		t.Errorf("packet = %q, want %q", got, want)
+	}
+}
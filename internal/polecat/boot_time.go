@@ -0,0 +1,264 @@
+package polecat
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Boot diagnosis codes recorded on the agent bead's boot_diagnosis field.
+const (
+	BootSlowShellInit = "slow_shell_init"   // Shell took too long to exec the agent
+	BootAPILatency    = "model_api_latency" // Agent took too long to reach its prompt
+	BootPrimingSize   = "priming_size"      // Priming context is unusually large
+)
+
+// bootLogFile holds per-rig boot records under <rig>/.runtime/.
+const bootLogFile = "boot_times.jsonl"
+
+// bootLogMaxRecords caps how many boot records are kept per rig.
+const bootLogMaxRecords = 200
+
+// Boot regression windows: the recent median is compared against the
+// median of the spawns before it.
+const (
+	bootRegressionRecent   = 5
+	bootRegressionBaseline = 20
+	bootRegressionMinBase  = 5
+)
+
+// BootBudget is the resolved boot_budget town setting.
+type BootBudget struct {
+	Budget           time.Duration
+	ShellInit        time.Duration
+	MaxPrimingBytes  int
+	RegressionFactor float64
+}
+
+// LoadBootBudget resolves the boot budget from settings/config.json,
+// applying defaults for unset or invalid values.
+func LoadBootBudget(townRoot string) BootBudget {
+	defaults := config.DefaultBootBudgetConfig()
+	cfg := defaults
+	if ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && ts.BootBudget != nil {
+		cfg = ts.BootBudget
+	}
+	budget := BootBudget{
+		Budget:           config.ParseDurationOrDefault(cfg.Budget, 60*time.Second),
+		ShellInit:        config.ParseDurationOrDefault(cfg.ShellInit, 10*time.Second),
+		MaxPrimingBytes:  cfg.MaxPrimingBytes,
+		RegressionFactor: cfg.RegressionFactor,
+	}
+	if budget.MaxPrimingBytes <= 0 {
+		budget.MaxPrimingBytes = defaults.MaxPrimingBytes
+	}
+	if budget.RegressionFactor <= 1 {
+		budget.RegressionFactor = defaults.RegressionFactor
+	}
+	return budget
+}
+
+// BootRecord is one polecat spawn's boot timing.
+type BootRecord struct {
+	Polecat      string    `json:"polecat"`
+	StartedAt    time.Time `json:"started_at"`
+	ShellInitMs  int64     `json:"shell_init_ms"`  // NewSession until the agent process replaced the shell
+	AgentReadyMs int64     `json:"agent_ready_ms"` // Agent process start until the ready prompt
+	TotalMs      int64     `json:"total_ms"`       // NewSession until the ready prompt
+	PrimingBytes int       `json:"priming_bytes"`  // Beacon plus CLAUDE.md/AGENTS.md context
+	Diagnosis    []string  `json:"diagnosis,omitempty"`
+}
+
+// Total returns the record's total boot time.
+func (r BootRecord) Total() time.Duration {
+	return time.Duration(r.TotalMs) * time.Millisecond
+}
+
+// DiagnoseBoot returns the likely causes of a boot that exceeded its budget,
+// or nil when it finished within budget. Every boot over budget gets at least
+// one cause; when no phase stands out, the agent phase is blamed.
+func DiagnoseBoot(rec BootRecord, budget BootBudget) []string {
+	if rec.Total() <= budget.Budget {
+		return nil
+	}
+	var causes []string
+	shellInit := time.Duration(rec.ShellInitMs) * time.Millisecond
+	if shellInit > budget.ShellInit {
+		causes = append(causes, BootSlowShellInit)
+	}
+	if rec.PrimingBytes > budget.MaxPrimingBytes {
+		causes = append(causes, BootPrimingSize)
+	}
+	agentReady := time.Duration(rec.AgentReadyMs) * time.Millisecond
+	if agentReady > budget.Budget-budget.ShellInit || len(causes) == 0 {
+		causes = append(causes, BootAPILatency)
+	}
+	return causes
+}
+
+// DescribeBootCause explains a diagnosis code for humans.
+func DescribeBootCause(code string) string {
+	switch code {
+	case BootSlowShellInit:
+		return "slow shell init (check shell rc files, PATH setup, version managers)"
+	case BootAPILatency:
+		return "slow agent startup (model API latency or MCP server startup)"
+	case BootPrimingSize:
+		return "large priming context (trim CLAUDE.md/AGENTS.md or hooked formula)"
+	default:
+		return code
+	}
+}
+
+// BootLogPath returns the path of a rig's boot record log.
+func BootLogPath(rigPath string) string {
+	return filepath.Join(rigPath, constants.DirRuntime, bootLogFile)
+}
+
+// AppendBootRecord adds a record to the rig's boot log, keeping only the
+// most recent bootLogMaxRecords.
+func AppendBootRecord(rigPath string, rec BootRecord) error {
+	records, err := LoadBootRecords(rigPath)
+	if err != nil {
+		return err
+	}
+	records = append(records, rec)
+	if len(records) > bootLogMaxRecords {
+		records = records[len(records)-bootLogMaxRecords:]
+	}
+
+	var b strings.Builder
+	for _, r := range records {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("encoding boot record: %w", err)
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+
+	path := BootLogPath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating boot log dir: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0644); err != nil { //nolint:gosec // G306: not sensitive
+		return fmt.Errorf("writing boot log: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadBootRecords reads a rig's boot log, oldest first. A missing log yields
+// no records; malformed lines are skipped.
+func LoadBootRecords(rigPath string) ([]BootRecord, error) {
+	f, err := os.Open(BootLogPath(rigPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading boot log: %w", err)
+	}
+	defer f.Close()
+
+	var records []BootRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec BootRecord
+		if json.Unmarshal(scanner.Bytes(), &rec) == nil {
+			records = append(records, rec)
+		}
+	}
+	return records, scanner.Err()
+}
+
+// BootRegression compares the median of the most recent boots against the
+// median of the boots before them.
+type BootRegression struct {
+	Recent    time.Duration
+	Baseline  time.Duration
+	Regressed bool
+}
+
+// DetectBootRegression reports whether recent boots are markedly slower than
+// the rig's baseline. Returns ok=false when there isn't enough history.
+func DetectBootRegression(records []BootRecord, factor float64) (BootRegression, bool) {
+	if len(records) < bootRegressionRecent+bootRegressionMinBase {
+		return BootRegression{}, false
+	}
+	recent := records[len(records)-bootRegressionRecent:]
+	baseline := records[:len(records)-bootRegressionRecent]
+	if len(baseline) > bootRegressionBaseline {
+		baseline = baseline[len(baseline)-bootRegressionBaseline:]
+	}
+
+	reg := BootRegression{Recent: medianBoot(recent), Baseline: medianBoot(baseline)}
+	reg.Regressed = reg.Baseline > 0 && float64(reg.Recent) > float64(reg.Baseline)*factor
+	return reg, true
+}
+
+// medianBoot returns the median total boot time of records.
+func medianBoot(records []BootRecord) time.Duration {
+	totals := make([]int64, len(records))
+	for i, r := range records {
+		totals[i] = r.TotalMs
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i] < totals[j] })
+	mid := len(totals) / 2
+	if len(totals)%2 == 0 {
+		return time.Duration((totals[mid-1]+totals[mid])/2) * time.Millisecond
+	}
+	return time.Duration(totals[mid]) * time.Millisecond
+}
+
+// primingBytes estimates the priming context an agent loads at startup: the
+// beacon plus CLAUDE.md and AGENTS.md files from workDir up to the town root.
+func primingBytes(workDir, townRoot, beacon string) int {
+	total := len(beacon)
+	dir := workDir
+	for {
+		for _, name := range []string{"CLAUDE.md", "AGENTS.md"} {
+			if info, err := os.Stat(filepath.Join(dir, name)); err == nil && !info.IsDir() {
+				total += int(info.Size())
+			}
+		}
+		parent := filepath.Dir(dir)
+		if dir == townRoot || parent == dir || !strings.HasPrefix(parent, townRoot) {
+			break
+		}
+		dir = parent
+	}
+	return total
+}
+
+// recordBootTime stores a spawn's boot timing: it is appended to the rig's
+// boot log and written to the polecat's agent bead, and a spawn over budget
+// is flagged with its diagnosis. All of this is best-effort.
+func (m *SessionManager) recordBootTime(townRoot, polecat string, rec BootRecord) {
+	budget := LoadBootBudget(townRoot)
+	rec.Diagnosis = DiagnoseBoot(rec, budget)
+	debugSession("AppendBootRecord", AppendBootRecord(m.rig.Path, rec))
+
+	bd := beads.New(filepath.Dir(beads.ResolveBeadsDir(m.rig.Path)))
+	agentID := beads.PolecatBeadIDWithPrefix(beads.GetPrefixForRig(townRoot, m.rig.Name), m.rig.Name, polecat)
+	debugSession("UpdateAgentBootTime", bd.UpdateAgentBootTime(agentID, rec.Total(), strings.Join(rec.Diagnosis, ",")))
+
+	if len(rec.Diagnosis) == 0 {
+		return
+	}
+	causes := make([]string, len(rec.Diagnosis))
+	for i, code := range rec.Diagnosis {
+		causes[i] = DescribeBootCause(code)
+	}
+	style.PrintWarning("polecat %s took %s to boot (budget %s): %s",
+		polecat, rec.Total().Round(100*time.Millisecond), budget.Budget, strings.Join(causes, "; "))
+}
@@ -0,0 +1,123 @@
+package polecat
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testBootBudget() BootBudget {
+	return BootBudget{Budget: 60 * time.Second, ShellInit: 10 * time.Second, MaxPrimingBytes: 32768, RegressionFactor: 1.5}
+}
+
+func TestDiagnoseBoot(t *testing.T) {
+	tests := []struct {
+		name string
+		rec  BootRecord
+		want []string
+	}{
+		{"within budget", BootRecord{ShellInitMs: 20000, AgentReadyMs: 30000, TotalMs: 50000, PrimingBytes: 90000}, nil},
+		{"slow shell", BootRecord{ShellInitMs: 40000, AgentReadyMs: 30000, TotalMs: 70000}, []string{BootSlowShellInit}},
+		{"slow agent", BootRecord{ShellInitMs: 2000, AgentReadyMs: 80000, TotalMs: 82000}, []string{BootAPILatency}},
+		{"large priming", BootRecord{ShellInitMs: 5000, AgentReadyMs: 45000, TotalMs: 65000, PrimingBytes: 50000}, []string{BootPrimingSize}},
+		{"unclassified blames agent", BootRecord{ShellInitMs: 8000, AgentReadyMs: 45000, TotalMs: 61000}, []string{BootAPILatency}},
+	}
+	for _, tt := range tests {
+		if got := DiagnoseBoot(tt.rec, testBootBudget()); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: DiagnoseBoot = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadBootBudget(t *testing.T) {
+	townRoot := t.TempDir()
+	if got := LoadBootBudget(townRoot); got != testBootBudget() {
+		t.Errorf("defaults = %+v", got)
+	}
+
+	if err := os.MkdirAll(filepath.Join(townRoot, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := `{"type":"town-settings","version":1,"boot_budget":{"budget":"2m","shell_init":"bogus"}}`
+	if err := os.WriteFile(filepath.Join(townRoot, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got := LoadBootBudget(townRoot)
+	if got.Budget != 2*time.Minute || got.ShellInit != 10*time.Second || got.MaxPrimingBytes != 32768 || got.RegressionFactor != 1.5 {
+		t.Errorf("configured budget = %+v", got)
+	}
+}
+
+func TestBootRecordLog(t *testing.T) {
+	rigPath := t.TempDir()
+	if records, err := LoadBootRecords(rigPath); err != nil || len(records) != 0 {
+		t.Fatalf("empty log: %v, %v", records, err)
+	}
+
+	for i := 0; i < bootLogMaxRecords+3; i++ {
+		if err := AppendBootRecord(rigPath, BootRecord{Polecat: "Toast", TotalMs: int64(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	records, err := LoadBootRecords(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != bootLogMaxRecords {
+		t.Fatalf("log holds %d records, want %d", len(records), bootLogMaxRecords)
+	}
+	if records[0].TotalMs != 3 || records[len(records)-1].TotalMs != bootLogMaxRecords+2 {
+		t.Errorf("log should keep the newest records, got first=%d last=%d", records[0].TotalMs, records[len(records)-1].TotalMs)
+	}
+}
+
+func TestDetectBootRegression(t *testing.T) {
+	var records []BootRecord
+	for i := 0; i < 4; i++ {
+		records = append(records, BootRecord{TotalMs: 20000})
+	}
+	if _, ok := DetectBootRegression(records, 1.5); ok {
+		t.Error("expected no verdict with too little history")
+	}
+
+	for i := 0; i < 6; i++ {
+		records = append(records, BootRecord{TotalMs: 20000})
+	}
+	if reg, ok := DetectBootRegression(records, 1.5); !ok || reg.Regressed {
+		t.Errorf("steady boots: reg=%+v ok=%v, want no regression", reg, ok)
+	}
+
+	for i := 0; i < 5; i++ {
+		records = append(records, BootRecord{TotalMs: 45000})
+	}
+	reg, ok := DetectBootRegression(records, 1.5)
+	if !ok || !reg.Regressed || reg.Recent != 45*time.Second || reg.Baseline != 20*time.Second {
+		t.Errorf("slower recent boots: reg=%+v ok=%v, want regression 45s vs 20s", reg, ok)
+	}
+}
+
+func TestPrimingBytes(t *testing.T) {
+	townRoot := t.TempDir()
+	workDir := filepath.Join(townRoot, "gastown", "polecats", "Toast", "gastown")
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for path, size := range map[string]int{
+		filepath.Join(workDir, "CLAUDE.md"):                         100,
+		filepath.Join(workDir, "AGENTS.md"):                         50,
+		filepath.Join(townRoot, "gastown", "polecats", "CLAUDE.md"): 200,
+		filepath.Join(townRoot, "CLAUDE.md"):                        400,
+		filepath.Join(filepath.Dir(townRoot), "CLAUDE.md"):          9999, // outside the town
+	} {
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() { _ = os.Remove(filepath.Join(filepath.Dir(townRoot), "CLAUDE.md")) })
+
+	if got := primingBytes(workDir, townRoot, "beacon"); got != 6+100+50+200+400 {
+		t.Errorf("primingBytes = %d, want 756", got)
+	}
+}
@@ -0,0 +1,212 @@
+package polecat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// spawnSubjectPrefix starts the subject of the mail announcing a spawn.
+const spawnSubjectPrefix = "POLECAT_STARTED "
+
+// inboxEntry is what a scan learned from one Deacon inbox message. Every
+// message listed is recorded, spawn or not, so later scans skip it
+// instead of parsing it again.
+type inboxEntry struct {
+	Spawn     bool      `json:"spawn,omitempty"`
+	Rig       string    `json:"rig,omitempty"`
+	Polecat   string    `json:"polecat,omitempty"`
+	Session   string    `json:"session,omitempty"`
+	Issue     string    `json:"issue,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// InboxStats counts the work CheckInboxForSpawns has done.
+type InboxStats struct {
+	Scans      int64 `json:"scans"`
+	Parsed     int64 `json:"parsed"`     // messages parsed for the first time
+	Skipped    int64 `json:"skipped"`    // messages already processed, not re-parsed
+	Duplicates int64 `json:"duplicates"` // extra POLECAT_STARTED mail for a session already pending
+
+	LastScan       time.Time `json:"last_scan,omitempty"`
+	LastMessages   int       `json:"last_messages"`
+	LastParsed     int       `json:"last_parsed"`
+	LastSkipped    int       `json:"last_skipped"`
+	LastDuplicates int       `json:"last_duplicates"`
+	LastPending    int       `json:"last_pending"`
+}
+
+// inboxCursor is the durable record of how far the Deacon's inbox has been
+// processed: the newest message seen, and every open message already
+// parsed, keyed by message ID.
+type inboxCursor struct {
+	LastID        string                `json:"last_id,omitempty"`
+	LastTimestamp time.Time             `json:"last_timestamp,omitempty"`
+	Entries       map[string]inboxEntry `json:"entries"`
+	Stats         InboxStats            `json:"stats"`
+}
+
+// InboxCursorStatus is the cursor summary gt deacon inbox-stats shows.
+type InboxCursorStatus struct {
+	LastID        string     `json:"last_id,omitempty"`
+	LastTimestamp time.Time  `json:"last_timestamp,omitempty"`
+	Tracked       int        `json:"tracked"` // open messages the cursor has processed
+	Spawns        int        `json:"spawns"`  // of which POLECAT_STARTED
+	Stats         InboxStats `json:"stats"`
+}
+
+// InboxCursorFile returns the path to the Deacon inbox cursor.
+func InboxCursorFile(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "deacon", "inbox-cursor.json")
+}
+
+// loadInboxCursor reads the cursor. A missing or unreadable cursor is
+// empty: the next scan parses the whole inbox once and rebuilds it.
+func loadInboxCursor(townRoot string) *inboxCursor {
+	cur := &inboxCursor{}
+	if data, err := os.ReadFile(InboxCursorFile(townRoot)); err == nil { //nolint:gosec // G304: path is constructed from trusted townRoot
+		_ = json.Unmarshal(data, cur)
+	}
+	if cur.Entries == nil {
+		cur.Entries = make(map[string]inboxEntry)
+	}
+	return cur
+}
+
+// ReadInboxCursor summarizes the Deacon inbox cursor.
+func ReadInboxCursor(townRoot string) InboxCursorStatus {
+	cur := loadInboxCursor(townRoot)
+	st := InboxCursorStatus{LastID: cur.LastID, LastTimestamp: cur.LastTimestamp, Tracked: len(cur.Entries), Stats: cur.Stats}
+	for _, e := range cur.Entries {
+		if e.Spawn {
+			st.Spawns++
+		}
+	}
+	return st
+}
+
+// ResetInboxCursor removes the cursor, so the next scan parses the whole
+// inbox again.
+func ResetInboxCursor(townRoot string) error {
+	if err := os.Remove(InboxCursorFile(townRoot)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// scannedSpawn is one pending spawn found by scanInbox: the newest
+// POLECAT_STARTED message for a session, plus any older ones for the same
+// session.
+type scannedSpawn struct {
+	ID         string
+	Entry      inboxEntry
+	Duplicates []string
+}
+
+// scanInbox brings cur up to date with messages, the open messages in the
+// inbox, newest first. Messages the cursor already holds are reused without
+// parsing; entries for messages no longer open are dropped. It returns the
+// pending spawns, oldest first, one per session.
+func scanInbox(cur *inboxCursor, messages []*mail.Message, now time.Time) []scannedSpawn {
+	open := make(map[string]bool, len(messages))
+	parsed, skipped := 0, 0
+	for _, msg := range messages {
+		open[msg.ID] = true
+		if _, ok := cur.Entries[msg.ID]; ok {
+			skipped++
+			continue
+		}
+		parsed++
+		cur.Entries[msg.ID] = parseInboxMessage(msg)
+	}
+	for id := range cur.Entries {
+		if !open[id] {
+			delete(cur.Entries, id)
+		}
+	}
+	if len(messages) > 0 {
+		cur.LastID, cur.LastTimestamp = messages[0].ID, messages[0].Timestamp
+	}
+
+	// One spawn per session: the newest announcement wins, older ones are
+	// carried along so they are archived with it.
+	var spawns []scannedSpawn
+	bySession := make(map[string]int)
+	duplicates := 0
+	for i := len(messages) - 1; i >= 0; i-- {
+		id := messages[i].ID
+		e := cur.Entries[id]
+		if !e.Spawn {
+			continue
+		}
+		key := e.Rig + "/" + e.Polecat + "/" + e.Session
+		if j, ok := bySession[key]; ok {
+			duplicates++
+			spawns[j].Duplicates = append(spawns[j].Duplicates, spawns[j].ID)
+			spawns[j].ID, spawns[j].Entry = id, e
+			continue
+		}
+		bySession[key] = len(spawns)
+		spawns = append(spawns, scannedSpawn{ID: id, Entry: e})
+	}
+
+	s := &cur.Stats
+	s.Scans++
+	s.Parsed += int64(parsed)
+	s.Skipped += int64(skipped)
+	s.Duplicates += int64(duplicates)
+	s.LastScan = now
+	s.LastMessages, s.LastParsed, s.LastSkipped, s.LastDuplicates, s.LastPending = len(messages), parsed, skipped, duplicates, len(spawns)
+	return spawns
+}
+
+// parseInboxMessage records whether msg announces a spawn and, if so, for
+// which rig, polecat, session and issue.
+func parseInboxMessage(msg *mail.Message) inboxEntry {
+	e := inboxEntry{Timestamp: msg.Timestamp}
+	if !strings.HasPrefix(msg.Subject, spawnSubjectPrefix) {
+		return e
+	}
+
+	// Parse subject: "POLECAT_STARTED rig/polecat"
+	parts := strings.SplitN(strings.TrimPrefix(msg.Subject, spawnSubjectPrefix), "/", 2)
+	if len(parts) != 2 {
+		return e
+	}
+	e.Spawn, e.Rig, e.Polecat = true, parts[0], parts[1]
+
+	// Parse body for session and issue
+	for _, line := range strings.Split(msg.Body, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Session: ") {
+			e.Session = strings.TrimPrefix(line, "Session: ")
+		} else if strings.HasPrefix(line, "Issue: ") {
+			e.Issue = strings.TrimPrefix(line, "Issue: ")
+		}
+	}
+	return e
+}
+
+// updateInboxCursor loads the cursor under a lock, applies fn and saves it.
+func updateInboxCursor(townRoot string, fn func(*inboxCursor)) error {
+	p := InboxCursorFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	fl := flock.New(p + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking inbox cursor: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	cur := loadInboxCursor(townRoot)
+	fn(cur)
+	return util.AtomicWriteJSON(p, cur)
+}
@@ -0,0 +1,101 @@
+package polecat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func spawnMail(id, rig, polecat, session string, at time.Time) *mail.Message {
+	return &mail.Message{
+		ID:        id,
+		Subject:   "POLECAT_STARTED " + rig + "/" + polecat,
+		Body:      "Session: " + session + "\nIssue: gt-1\n",
+		Timestamp: at,
+	}
+}
+
+func TestScanInbox_CursorSkipsProcessedMail(t *testing.T) {
+	base := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	cur := &inboxCursor{Entries: make(map[string]inboxEntry)}
+	// Newest first, as Mailbox.List returns them.
+	inbox := []*mail.Message{
+		spawnMail("m3", "gastown", "slit", "gt-slit", base.Add(2*time.Minute)),
+		{ID: "m2", Subject: "HEALTH_CHECK", Timestamp: base.Add(time.Minute)},
+		spawnMail("m1", "gastown", "nux", "gt-nux", base),
+	}
+
+	spawns := scanInbox(cur, inbox, base)
+	if len(spawns) != 2 || spawns[0].ID != "m1" || spawns[1].ID != "m3" {
+		t.Fatalf("spawns = %+v, want m1 then m3", spawns)
+	}
+	if e := spawns[0].Entry; e.Rig != "gastown" || e.Polecat != "nux" || e.Session != "gt-nux" || e.Issue != "gt-1" {
+		t.Errorf("entry = %+v", e)
+	}
+	if cur.Stats.LastParsed != 3 || cur.Stats.LastSkipped != 0 || cur.LastID != "m3" {
+		t.Errorf("first scan: stats = %+v, last id %q", cur.Stats, cur.LastID)
+	}
+
+	// m1 archived, m4 arrives: only m4 is parsed, m1 leaves the cursor.
+	inbox = []*mail.Message{
+		spawnMail("m4", "beads", "furiosa", "bd-furiosa", base.Add(3*time.Minute)),
+		inbox[0], inbox[1],
+	}
+	spawns = scanInbox(cur, inbox, base)
+	if len(spawns) != 2 || spawns[0].ID != "m3" || spawns[1].ID != "m4" {
+		t.Fatalf("spawns = %+v, want m3 then m4", spawns)
+	}
+	if cur.Stats.LastParsed != 1 || cur.Stats.LastSkipped != 2 {
+		t.Errorf("second scan: stats = %+v", cur.Stats)
+	}
+	if _, ok := cur.Entries["m1"]; ok {
+		t.Error("archived message should leave the cursor")
+	}
+	if cur.Stats.Scans != 2 || cur.Stats.Parsed != 4 || cur.Stats.Skipped != 2 {
+		t.Errorf("totals = %+v", cur.Stats)
+	}
+}
+
+func TestScanInbox_DeduplicatesSessions(t *testing.T) {
+	base := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	cur := &inboxCursor{Entries: make(map[string]inboxEntry)}
+	inbox := []*mail.Message{
+		spawnMail("m3", "gastown", "nux", "gt-nux", base.Add(2*time.Minute)),
+		spawnMail("m2", "gastown", "nux", "gt-nux", base.Add(time.Minute)),
+		spawnMail("m1", "gastown", "nux", "gt-nux", base),
+	}
+	spawns := scanInbox(cur, inbox, base)
+	if len(spawns) != 1 {
+		t.Fatalf("spawns = %+v, want one per session", spawns)
+	}
+	if spawns[0].ID != "m3" || len(spawns[0].Duplicates) != 2 {
+		t.Errorf("spawn = %+v, want newest m3 with two duplicates", spawns[0])
+	}
+	if cur.Stats.LastDuplicates != 2 || cur.Stats.LastPending != 1 {
+		t.Errorf("stats = %+v", cur.Stats)
+	}
+}
+
+func TestInboxCursor_Persists(t *testing.T) {
+	townRoot := t.TempDir()
+	inbox := []*mail.Message{spawnMail("m1", "gastown", "nux", "gt-nux", time.Now())}
+	for i := 0; i < 2; i++ {
+		if err := updateInboxCursor(townRoot, func(cur *inboxCursor) { scanInbox(cur, inbox, time.Now()) }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	st := ReadInboxCursor(townRoot)
+	if st.Tracked != 1 || st.Spawns != 1 || st.Stats.Scans != 2 || st.Stats.Skipped != 1 {
+		t.Errorf("status = %+v", st)
+	}
+	if err := ResetInboxCursor(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	if st := ReadInboxCursor(townRoot); st.Stats.Scans != 0 || st.Tracked != 0 {
+		t.Errorf("after reset = %+v", st)
+	}
+	if err := ResetInboxCursor(townRoot); err != nil {
+		t.Errorf("resetting a missing cursor: %v", err)
+	}
+}
@@ -154,7 +154,7 @@ func NewManager(r *rig.Rig, g *git.Git, t *tmux.Tmux) *Manager {
 	return &Manager{
 		rig:      r,
 		git:      g,
-		beads:    beads.NewWithBeadsDir(beadsPath, resolvedBeads),
+		beads:    beads.NewWithBeadsDir(beadsPath, resolvedBeads).WithSubsystem("polecat"),
 		namePool: pool,
 		tmux:     t,
 	}
@@ -635,35 +635,24 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 	// AddWithOptions creates several resources in sequence (directory, worktree,
 	// agent bead); on failure, all created resources must be cleaned up to prevent
 	// leaking names, orphaning beads, or leaving stale worktree registrations.
+	// Each resource records its compensation in the spawn saga; Rollback undoes
+	// them in reverse order (agent bead, worktree, directory).
 	// See: gt-2vs22
-	var worktreeCreated bool
-	cleanupOnError := func() {
-		// Best-effort reset of agent bead (may have been partially created
-		// by a failed createAgentBeadWithRetry)
-		aid := m.agentBeadID(name)
-		_ = m.beads.ResetAgentBeadForReuse(aid, "spawn rollback")
-
-		// Remove git worktree registration if worktree was successfully added.
-		// Must happen before directory removal so git can clean up properly.
-		if worktreeCreated {
-			if rg, repoErr := m.repoBase(); repoErr == nil {
-				_ = rg.WorktreeRemove(clonePath, true)
-			}
-		}
-
-		// Remove polecat directory
-		_ = os.RemoveAll(polecatDir)
+	saga := NewSaga("spawn " + name)
+	saga.Record("polecat directory", func() error {
+		err := os.RemoveAll(polecatDir)
 
 		// Release name back to pool so it can be reallocated immediately
 		// rather than waiting for the next reconcile cycle.
 		m.namePool.Release(name)
 		_ = m.namePool.Save()
-	}
+		return err
+	})
 
 	// Get the repo base (bare repo or mayor/rig)
 	repoGit, err := m.repoBase()
 	if err != nil {
-		cleanupOnError()
+		_ = saga.Rollback()
 		return nil, fmt.Errorf("finding repo base: %w", err)
 	}
 
@@ -687,10 +676,10 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 
 	// Validate that startPoint ref exists before attempting worktree creation
 	if exists, err := repoGit.RefExists(startPoint); err != nil {
-		cleanupOnError()
+		_ = saga.Rollback()
 		return nil, fmt.Errorf("checking ref %s: %w", startPoint, err)
 	} else if !exists {
-		cleanupOnError()
+		_ = saga.Rollback()
 		return nil, fmt.Errorf("configured default_branch not found as %s in bare repo\n\n"+
 			"Possible causes:\n"+
 			"  - Branch doesn't exist on the remote (create it there first)\n"+
@@ -703,11 +692,16 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 	// Always create fresh branch - unique name guarantees no collision
 	// git worktree add -b polecat/<name>-<timestamp> <path> <startpoint>
 	// Worktree goes in polecats/<name>/<rigname>/ for LLM ergonomics
-	if err := repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint); err != nil {
-		cleanupOnError()
+	// Removing the worktree registration must happen before directory removal
+	// so git can clean up properly; the saga's reverse order guarantees that.
+	if err := saga.Step("worktree", func() error {
+		return repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint)
+	}, func() error {
+		return repoGit.WorktreeRemove(clonePath, true)
+	}); err != nil {
+		_ = saga.Rollback()
 		return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
 	}
-	worktreeCreated = true
 
 	// NOTE: No per-directory CLAUDE.md or AGENTS.md is created here.
 	// Only ~/gt/CLAUDE.md (town-root identity anchor) exists on disk.
@@ -766,7 +760,12 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 	// HookBead is set atomically at creation time if provided (avoids cross-beads routing issues).
 	// Uses CreateOrReopenAgentBead to handle re-spawning with same name (GH #332).
 	// Retries with backoff — a polecat without an agent bead is untrackable (gt-94llt7).
+	// The compensation is recorded before the attempt because a failed
+	// createAgentBeadWithRetry may still have partially created the bead.
 	agentID := m.agentBeadID(name)
+	saga.Record("agent bead", func() error {
+		return m.beads.ResetAgentBeadForReuse(agentID, "spawn rollback")
+	})
 	if err = m.createAgentBeadWithRetry(agentID, &beads.AgentFields{
 		RoleType:   "polecat",
 		Rig:        m.rig.Name,
@@ -774,7 +773,7 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 		HookBead:   opts.HookBead, // Set atomically at spawn time
 	}); err != nil {
 		// Hard fail — an untrackable polecat is worse than no polecat
-		cleanupOnError()
+		_ = saga.Rollback()
 		return nil, fmt.Errorf("agent bead required for polecat tracking: %w", err)
 	}
 
@@ -1245,7 +1244,7 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 		_ = repoGit.WorktreeRemove(newClonePath, true)
 		_ = os.RemoveAll(newClonePath)
 		// Remove polecatDir to prevent limbo state where m.exists(name) returns true
-		// but no valid worktree exists. Matches AddWithOptions cleanupOnError behavior.
+		// but no valid worktree exists. Matches AddWithOptions spawn-saga rollback.
 		_ = os.RemoveAll(polecatDir)
 		return nil, fmt.Errorf("agent bead required for polecat tracking: %w", err)
 	}
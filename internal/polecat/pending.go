@@ -2,14 +2,17 @@
 package polecat
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
+	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/util"
 )
 
 // PendingSpawn represents a polecat that has been spawned but not yet triggered.
@@ -33,12 +36,33 @@ type PendingSpawn struct {
 	// MailID is the ID of the POLECAT_STARTED message
 	MailID string `json:"mail_id"`
 
+	// Attempts is how many readiness checks found the runtime not ready.
+	Attempts int `json:"attempts,omitempty"`
+
+	// LastAttempt is when the last failed readiness check ran.
+	LastAttempt time.Time `json:"last_attempt,omitempty"`
+
+	// NextAttempt is when the next readiness check is due (backoff).
+	NextAttempt time.Time `json:"next_attempt,omitempty"`
+
+	// HeldSince is when the spawn, ready to trigger, was first held back by
+	// TriggerLimits. Zero when it isn't held.
+	HeldSince time.Time `json:"held_since,omitempty"`
+
 	// mailbox is kept for archiving after trigger (not serialized)
 	mailbox *mail.Mailbox `json:"-"`
+
+	// duplicates are older POLECAT_STARTED messages for the same session,
+	// archived along with this one.
+	duplicates []string
 }
 
 // CheckInboxForSpawns discovers pending spawns from POLECAT_STARTED messages
-// in the Deacon's inbox. Uses mail as source of truth (ZFC principle).
+// in the Deacon's inbox. Uses mail as source of truth (ZFC principle): the
+// inbox is listed on every call, but a durable cursor remembers every
+// message already parsed, so only new mail is read. When a session was
+// announced more than once, only the newest message is returned; the older
+// ones are archived along with it. Spawns are returned oldest first.
 func CheckInboxForSpawns(townRoot string) ([]*PendingSpawn, error) {
 	// Get Deacon's mailbox
 	router := mail.NewRouter(townRoot)
@@ -53,75 +77,236 @@ func CheckInboxForSpawns(townRoot string) ([]*PendingSpawn, error) {
 		return nil, fmt.Errorf("listing messages: %w", err)
 	}
 
-	var pending []*PendingSpawn
+	var scanned []scannedSpawn
+	if err := updateInboxCursor(townRoot, func(cur *inboxCursor) {
+		scanned = scanInbox(cur, messages, time.Now())
+	}); err != nil {
+		// The cursor only saves work; scan without it.
+		scanned = scanInbox(&inboxCursor{Entries: make(map[string]inboxEntry)}, messages, time.Now())
+	}
 
-	// Look for POLECAT_STARTED messages
-	for _, msg := range messages {
-		if !strings.HasPrefix(msg.Subject, "POLECAT_STARTED ") {
-			continue
+	attempts := loadPendingAttempts(townRoot)
+	pending := make([]*PendingSpawn, 0, len(scanned))
+	for _, sc := range scanned {
+		ps := &PendingSpawn{
+			Rig:        sc.Entry.Rig,
+			Polecat:    sc.Entry.Polecat,
+			Session:    sc.Entry.Session,
+			Issue:      sc.Entry.Issue,
+			SpawnedAt:  sc.Entry.Timestamp,
+			MailID:     sc.ID,
+			mailbox:    mailbox,
+			duplicates: sc.Duplicates,
 		}
-
-		// Parse subject: "POLECAT_STARTED rig/polecat"
-		parts := strings.SplitN(strings.TrimPrefix(msg.Subject, "POLECAT_STARTED "), "/", 2)
-		if len(parts) != 2 {
-			continue
+		if a, ok := attempts[sc.ID]; ok {
+			ps.Attempts, ps.LastAttempt, ps.NextAttempt, ps.HeldSince = a.Attempts, a.LastAttempt, a.NextAttempt, a.HeldSince
 		}
+		pending = append(pending, ps)
+	}
+
+	return pending, nil
+}
 
-		rig := parts[0]
-		polecat := parts[1]
+// Defaults for RetryPolicy fields left zero.
+const (
+	DefaultPendingMaxAttempts = 5                // Failed readiness checks before escalating
+	DefaultPendingBackoff     = 10 * time.Second // Delay after the first failed check
+	DefaultPendingMaxBackoff  = time.Minute      // Cap on the doubling delay
+)
 
-		// Parse body for session and issue
-		var session, issue string
-		for _, line := range strings.Split(msg.Body, "\n") {
-			line = strings.TrimSpace(line)
-			if strings.HasPrefix(line, "Session: ") {
-				session = strings.TrimPrefix(line, "Session: ")
-			} else if strings.HasPrefix(line, "Issue: ") {
-				issue = strings.TrimPrefix(line, "Issue: ")
-			}
-		}
+// RetryPolicy bounds how often a pending spawn's readiness is re-checked.
+// After each failed check the next one is delayed by Backoff, doubling up
+// to MaxBackoff; after MaxAttempts failed checks the spawn is escalated to
+// the Mayor instead of being retried. Zero fields use the defaults above.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
 
-		ps := &PendingSpawn{
-			Rig:       rig,
-			Polecat:   polecat,
-			Session:   session,
-			Issue:     issue,
-			SpawnedAt: msg.Timestamp,
-			MailID:    msg.ID,
-			mailbox:   mailbox,
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultPendingMaxAttempts
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = DefaultPendingBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultPendingMaxBackoff
+	}
+	if p.MaxBackoff < p.Backoff {
+		p.MaxBackoff = p.Backoff
+	}
+	return p
+}
+
+// Delay returns how long to wait before the next readiness check after the
+// given number of failed ones.
+func (p RetryPolicy) Delay(attempts int) time.Duration {
+	p = p.withDefaults()
+	d := p.Backoff
+	for i := 1; i < attempts && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, p.MaxBackoff)
+}
+
+// Exhausted reports whether attempts failed checks use up the budget.
+func (p RetryPolicy) Exhausted(attempts int) bool {
+	return attempts >= p.withDefaults().MaxAttempts
+}
+
+// pendingAttempt is the retry state of one pending spawn. It is kept beside
+// the mail, keyed by MailID, because the POLECAT_STARTED message itself is
+// immutable.
+type pendingAttempt struct {
+	Attempts    int       `json:"attempts"`
+	LastAttempt time.Time `json:"last_attempt"`
+	NextAttempt time.Time `json:"next_attempt"`
+	HeldSince   time.Time `json:"held_since,omitempty"`
+}
+
+// pendingAttemptsFile returns the path to the pending spawn retry state.
+func pendingAttemptsFile(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "pending-spawns.json")
+}
+
+// loadPendingAttempts reads the retry state. A missing or unreadable file
+// is empty: the worst case is a spawn getting its full budget again.
+func loadPendingAttempts(townRoot string) map[string]pendingAttempt {
+	attempts := make(map[string]pendingAttempt)
+	data, err := os.ReadFile(pendingAttemptsFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		return attempts
+	}
+	_ = json.Unmarshal(data, &attempts)
+	return attempts
+}
+
+// updatePendingAttempts applies fn to the retry state under a lock and
+// saves it.
+func updatePendingAttempts(townRoot string, fn func(map[string]pendingAttempt)) error {
+	p := pendingAttemptsFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	fl := flock.New(p + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking pending spawns: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	attempts := loadPendingAttempts(townRoot)
+	fn(attempts)
+	return util.AtomicWriteJSON(p, attempts)
+}
+
+// recordAttempt saves ps's retry state.
+func recordAttempt(townRoot string, ps *PendingSpawn) error {
+	return updatePendingAttempts(townRoot, func(m map[string]pendingAttempt) {
+		m[ps.MailID] = pendingAttempt{Attempts: ps.Attempts, LastAttempt: ps.LastAttempt, NextAttempt: ps.NextAttempt, HeldSince: ps.HeldSince}
+	})
+}
+
+// finishSpawn archives ps's mail, and any duplicate announcements of its
+// session, and forgets its retry state.
+func finishSpawn(townRoot string, ps *PendingSpawn) error {
+	if ps.mailbox != nil {
+		if err := ps.mailbox.Archive(ps.MailID); err != nil {
+			return err
 		}
-		pending = append(pending, ps)
+		for _, id := range ps.duplicates {
+			_ = ps.mailbox.Archive(id)
+		}
+	}
+	if ps.Attempts == 0 && ps.HeldSince.IsZero() {
+		return nil
 	}
+	return updatePendingAttempts(townRoot, func(m map[string]pendingAttempt) {
+		delete(m, ps.MailID)
+	})
+}
 
-	return pending, nil
+// FinishPendingSpawn archives ps's POLECAT_STARTED mail and forgets its
+// retry state, for a spawn triggered, killed or dismissed by hand.
+func FinishPendingSpawn(townRoot string, ps *PendingSpawn) error {
+	return finishSpawn(townRoot, ps)
+}
+
+// escalateSpawn mails the Mayor about a spawn that never became ready and
+// archives its POLECAT_STARTED message.
+func escalateSpawn(townRoot string, ps *PendingSpawn, reason string) error {
+	subject := fmt.Sprintf("SPAWN_STUCK %s/%s", ps.Rig, ps.Polecat)
+	body := fmt.Sprintf(`Polecat %s/%s was spawned but never received its start trigger.
+
+Session: %s
+Issue: %s
+Spawned: %s
+Readiness checks failed: %d
+Reason: %s
+
+The session may be hung at a prompt. Inspect it with 'gt peek %s/%s',
+then nudge it manually or nuke it and re-sling the issue.`,
+		ps.Rig, ps.Polecat, ps.Session, ps.Issue, ps.SpawnedAt.Format(time.RFC3339),
+		ps.Attempts, reason, ps.Rig, ps.Polecat)
+
+	msg := mail.NewMessage("deacon/", "mayor/", subject, body)
+	msg.Priority = mail.PriorityHigh
+	if err := mail.NewRouter(townRoot).Send(msg); err != nil {
+		return fmt.Errorf("escalating to mayor: %w", err)
+	}
+	return finishSpawn(townRoot, ps)
 }
 
 // TriggerResult holds the result of attempting to trigger a pending spawn.
 type TriggerResult struct {
 	Spawn     *PendingSpawn
 	Triggered bool
-	Skipped   bool // true when spawn exists but runtime is not ready yet
+	Skipped   bool   // true when spawn exists but runtime is not ready yet
+	Deferred  bool   // true when the spawn is backing off and wasn't checked
+	Escalated bool   // true when the retry budget ran out and the Mayor was mailed
+	Held      bool   // true when the spawn was ready but held back by TriggerLimits
+	HeldBy    string // which limit held it, when Held
 	Error     error
+
+	// QueuePosition is a held spawn's place among the held spawns, from 1.
+	QueuePosition int
 }
 
 // TriggerPendingSpawns polls each pending spawn and triggers when ready.
 // Archives mail after successful trigger (ZFC: mail is source of truth).
-func TriggerPendingSpawns(townRoot string, timeout time.Duration) ([]TriggerResult, error) {
+// A spawn whose runtime isn't ready is retried with backoff until policy's
+// budget runs out, then escalated to the Mayor. Spawns are taken oldest
+// first, and a ready spawn over limits is held in pending for a later pass.
+func TriggerPendingSpawns(townRoot string, timeout time.Duration, policy RetryPolicy, limits TriggerLimits) ([]TriggerResult, error) {
 	pending, err := CheckInboxForSpawns(townRoot)
 	if err != nil {
 		return nil, fmt.Errorf("checking inbox: %w", err)
 	}
 
+	forgetFinishedAttempts(townRoot, pending)
 	if len(pending) == 0 {
 		return nil, nil
 	}
 
 	t := tmux.NewTmux()
+	now := time.Now()
+	sortPendingOldestFirst(pending)
+	gate, err := newTriggerGate(townRoot, limits, pending, t.ListSessions, now)
+	if err != nil {
+		return nil, err
+	}
 	var results []TriggerResult
 
 	for _, ps := range pending {
 		result := TriggerResult{Spawn: ps}
 
+		if now.Before(ps.NextAttempt) {
+			result.Deferred = true
+			results = append(results, result)
+			continue
+		}
+
 		// Check if session still exists (ZFC: query tmux directly)
 		running, err := t.HasSession(ps.Session)
 		if err != nil {
@@ -133,9 +318,7 @@ func TriggerPendingSpawns(townRoot string, timeout time.Duration) ([]TriggerResu
 		if !running {
 			// Session gone - archive the mail (spawn is dead)
 			result.Error = fmt.Errorf("session no longer exists")
-			if ps.mailbox != nil {
-				_ = ps.mailbox.Archive(ps.MailID)
-			}
+			_ = finishSpawn(townRoot, ps)
 			results = append(results, result)
 			continue
 		}
@@ -145,13 +328,43 @@ func TriggerPendingSpawns(townRoot string, timeout time.Duration) ([]TriggerResu
 		runtimeConfig := config.ResolveRoleAgentConfig("polecat", townRoot, rigPath)
 		err = t.WaitForRuntimeReady(ps.Session, runtimeConfig, timeout)
 		if err != nil {
-			// Not ready yet - leave mail in inbox for next poll
+			ps.HeldSince = time.Time{}
+			ps.Attempts++
+			ps.LastAttempt = now
+			ps.NextAttempt = now.Add(policy.Delay(ps.Attempts))
+			if policy.Exhausted(ps.Attempts) {
+				reason := fmt.Sprintf("runtime not ready after %d checks", ps.Attempts)
+				if err := escalateSpawn(townRoot, ps, reason); err != nil {
+					result.Error = err
+				} else {
+					result.Escalated = true
+				}
+				results = append(results, result)
+				continue
+			}
+			// Not ready yet - leave mail in inbox for the next poll after backoff
+			if err := recordAttempt(townRoot, ps); err != nil {
+				result.Error = fmt.Errorf("recording attempt: %w", err)
+			}
 			result.Skipped = true
 			results = append(results, result)
 			continue
 		}
 
-		// Runtime is ready - send trigger
+		// Runtime is ready - hold it if the rig or town is at its limit
+		if ok, why := gate.admit(ps.Rig); !ok {
+			if ps.HeldSince.IsZero() {
+				ps.HeldSince = now
+				if err := recordAttempt(townRoot, ps); err != nil {
+					result.Error = fmt.Errorf("recording hold: %w", err)
+				}
+			}
+			result.Held, result.HeldBy = true, why
+			results = append(results, result)
+			continue
+		}
+
+		// Send trigger
 		triggerMsg := "Begin."
 		if err := t.NudgeSession(ps.Session, triggerMsg); err != nil {
 			result.Error = fmt.Errorf("nudging session: %w", err)
@@ -161,17 +374,147 @@ func TriggerPendingSpawns(townRoot string, timeout time.Duration) ([]TriggerResu
 
 		// Successfully triggered - archive the mail
 		result.Triggered = true
-		if ps.mailbox != nil {
-			_ = ps.mailbox.Archive(ps.MailID)
+		gate.record(ps.Rig)
+		if limits.MaxPerMinute > 0 {
+			_ = recordTrigger(townRoot, now)
 		}
+		_ = finishSpawn(townRoot, ps)
 		results = append(results, result)
 	}
 
+	var held []*PendingSpawn
+	for _, r := range results {
+		if r.Held {
+			held = append(held, r.Spawn)
+		}
+	}
+	positions := QueuePositions(held)
+	for i := range results {
+		results[i].QueuePosition = positions[results[i].Spawn.MailID]
+	}
 	return results, nil
 }
 
-// PruneStalePending archives POLECAT_STARTED messages older than the given age.
-// Old spawns likely had their sessions die without triggering.
+// forgetFinishedAttempts drops retry state for spawns whose mail is no
+// longer pending (archived by hand, or by an older gt).
+func forgetFinishedAttempts(townRoot string, pending []*PendingSpawn) {
+	attempts := loadPendingAttempts(townRoot)
+	live := make(map[string]bool, len(pending))
+	for _, ps := range pending {
+		live[ps.MailID] = true
+	}
+	stale := false
+	for id := range attempts {
+		if !live[id] {
+			stale = true
+			break
+		}
+	}
+	if !stale {
+		return
+	}
+	_ = updatePendingAttempts(townRoot, func(m map[string]pendingAttempt) {
+		for id := range m {
+			if !live[id] {
+				delete(m, id)
+			}
+		}
+	})
+}
+
+// PendingAction is what a trigger-pending pass would do with a pending spawn.
+type PendingAction string
+
+const (
+	PendingNudge    PendingAction = "nudge"    // runtime ready; would send "Begin."
+	PendingWait     PendingAction = "wait"     // runtime not ready; retried after backoff
+	PendingBackoff  PendingAction = "backoff"  // backing off; not checked this pass
+	PendingEscalate PendingAction = "escalate" // retry budget or max age exhausted; Mayor would be mailed
+	PendingArchive  PendingAction = "archive"  // session gone; mail would be archived
+	PendingHold     PendingAction = "hold"     // runtime ready but over TriggerLimits; stays queued
+	PendingError    PendingAction = "error"    // session state couldn't be checked
+)
+
+// PendingPlan is the planned action for one pending spawn and why.
+type PendingPlan struct {
+	Spawn  *PendingSpawn `json:"spawn"`
+	Action PendingAction `json:"action"`
+	Reason string        `json:"reason"`
+
+	// QueuePosition is a held spawn's place in the queue, from 1.
+	QueuePosition int `json:"queue_position,omitempty"`
+}
+
+// PlanPendingSpawns reports what TriggerPendingSpawns followed by
+// PruneStalePending would do with each pending spawn, without nudging any
+// session, archiving any mail or recording any attempt.
+func PlanPendingSpawns(townRoot string, timeout, maxAge time.Duration, policy RetryPolicy, limits TriggerLimits) ([]PendingPlan, error) {
+	pending, err := CheckInboxForSpawns(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("checking inbox: %w", err)
+	}
+
+	t := tmux.NewTmux()
+	now := time.Now()
+	sortPendingOldestFirst(pending)
+	gate, err := newTriggerGate(townRoot, limits, pending, t.ListSessions, now)
+	if err != nil {
+		return nil, err
+	}
+	queued := 0
+	var plans []PendingPlan
+	for _, ps := range pending {
+		if now.Before(ps.NextAttempt) {
+			plans = append(plans, PendingPlan{Spawn: ps, Action: PendingBackoff,
+				Reason: fmt.Sprintf("attempt %d failed; next check in %v", ps.Attempts, ps.NextAttempt.Sub(now).Round(time.Second))})
+			continue
+		}
+		running, err := t.HasSession(ps.Session)
+		ready := false
+		if err == nil && running {
+			rigPath := filepath.Join(townRoot, ps.Rig)
+			runtimeConfig := config.ResolveRoleAgentConfig("polecat", townRoot, rigPath)
+			ready = t.WaitForRuntimeReady(ps.Session, runtimeConfig, timeout) == nil
+		}
+		action, reason := planPendingSpawn(now.Sub(ps.SpawnedAt), maxAge, ps.Attempts, policy, running, ready, err)
+		plan := PendingPlan{Spawn: ps, Action: action, Reason: reason}
+		if action == PendingNudge {
+			if ok, why := gate.admit(ps.Rig); ok {
+				gate.record(ps.Rig)
+			} else {
+				queued++
+				plan.Action, plan.Reason, plan.QueuePosition = PendingHold, why, queued
+			}
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// planPendingSpawn mirrors the decisions of TriggerPendingSpawns and
+// PruneStalePending for a due spawn of the given age that has already
+// failed attempts readiness checks.
+func planPendingSpawn(age, maxAge time.Duration, attempts int, policy RetryPolicy, running, ready bool, sessionErr error) (PendingAction, string) {
+	switch {
+	case sessionErr != nil:
+		return PendingError, fmt.Sprintf("checking session: %v", sessionErr)
+	case !running:
+		return PendingArchive, "session no longer exists"
+	case ready:
+		return PendingNudge, "runtime prompt detected"
+	case policy.Exhausted(attempts + 1):
+		return PendingEscalate, fmt.Sprintf("runtime not ready after %d checks", attempts+1)
+	case age > maxAge:
+		return PendingEscalate, fmt.Sprintf("waiting %v, over the %v max age", age.Round(time.Second), maxAge)
+	default:
+		return PendingWait, fmt.Sprintf("runtime not ready (check %d); retry in %v", attempts+1, policy.Delay(attempts+1))
+	}
+}
+
+// PruneStalePending escalates POLECAT_STARTED messages older than the given
+// age to the Mayor and archives them. Old spawns likely had their sessions
+// hang or die without triggering, which someone should look at. Spawns held
+// by TriggerLimits are ready and only waiting for room, so they are kept.
 func PruneStalePending(townRoot string, maxAge time.Duration) (int, error) {
 	pending, err := CheckInboxForSpawns(townRoot)
 	if err != nil {
@@ -182,12 +525,10 @@ func PruneStalePending(townRoot string, maxAge time.Duration) (int, error) {
 	pruned := 0
 
 	for _, ps := range pending {
-		if ps.SpawnedAt.Before(cutoff) {
-			// Archive stale spawn message
-			if ps.mailbox != nil {
-				if err := ps.mailbox.Archive(ps.MailID); err != nil {
-					continue // Don't count as pruned if archive failed
-				}
+		if ps.SpawnedAt.Before(cutoff) && ps.HeldSince.IsZero() {
+			reason := fmt.Sprintf("pending for over %v", maxAge)
+			if err := escalateSpawn(townRoot, ps, reason); err != nil {
+				continue // Don't count as pruned if escalation failed
 			}
 			pruned++
 		}
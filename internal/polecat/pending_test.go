@@ -0,0 +1,72 @@
+package polecat
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPlanPendingSpawn(t *testing.T) {
+	maxAge := 5 * time.Minute
+	policy := RetryPolicy{MaxAttempts: 3}
+	tests := []struct {
+		name     string
+		age      time.Duration
+		attempts int
+		running  bool
+		ready    bool
+		err      error
+		want     PendingAction
+	}{
+		{"ready", time.Minute, 0, true, true, nil, PendingNudge},
+		{"ready beats stale", time.Hour, 0, true, true, nil, PendingNudge},
+		{"ready beats exhausted", time.Minute, 2, true, true, nil, PendingNudge},
+		{"not ready", time.Minute, 0, true, false, nil, PendingWait},
+		{"not ready and stale", time.Hour, 0, true, false, nil, PendingEscalate},
+		{"last attempt fails", time.Minute, 2, true, false, nil, PendingEscalate},
+		{"session gone", time.Minute, 0, false, false, nil, PendingArchive},
+		{"tmux error", time.Minute, 0, false, false, errors.New("no server"), PendingError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := planPendingSpawn(tt.age, maxAge, tt.attempts, policy, tt.running, tt.ready, tt.err)
+			if got != tt.want || reason == "" {
+				t.Errorf("planPendingSpawn = %s, %q; want %s", got, reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{Backoff: 10 * time.Second, MaxBackoff: time.Minute}
+	want := []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}
+	for i, w := range want {
+		if got := p.Delay(i + 1); got != w {
+			t.Errorf("Delay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+
+	var def RetryPolicy
+	if def.Delay(1) != DefaultPendingBackoff || def.Exhausted(DefaultPendingMaxAttempts-1) || !def.Exhausted(DefaultPendingMaxAttempts) {
+		t.Errorf("zero policy should use the defaults")
+	}
+}
+
+func TestPendingAttemptsRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now().UTC().Truncate(time.Second)
+	ps := &PendingSpawn{MailID: "msg-1", Attempts: 2, LastAttempt: now, NextAttempt: now.Add(20 * time.Second)}
+	if err := recordAttempt(townRoot, ps); err != nil {
+		t.Fatal(err)
+	}
+
+	got := loadPendingAttempts(townRoot)["msg-1"]
+	if got.Attempts != 2 || !got.NextAttempt.Equal(ps.NextAttempt) {
+		t.Errorf("round trip = %+v", got)
+	}
+
+	forgetFinishedAttempts(townRoot, []*PendingSpawn{{MailID: "msg-2"}})
+	if len(loadPendingAttempts(townRoot)) != 0 {
+		t.Errorf("attempts for archived mail should be forgotten")
+	}
+}
@@ -0,0 +1,77 @@
+package polecat
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Saga sequences the side-effecting steps of a multi-stage operation such as
+// polecat spawn (agent bead, worktree, Dolt branch, tmux session) and records a
+// compensation for each step that completes. When a later step fails, Rollback
+// runs the recorded compensations in reverse order, so a failure halfway
+// (e.g. a tmux error) leaves no half-provisioned polecat behind for the
+// Witness to garbage-collect.
+//
+// A Saga is safe for concurrent use. Rollback is idempotent: compensations
+// run at most once.
+type Saga struct {
+	name string
+
+	mu   sync.Mutex
+	done []sagaStep
+}
+
+// sagaStep is a completed step and the action that undoes it.
+type sagaStep struct {
+	name       string
+	compensate func() error
+}
+
+// NewSaga creates an empty saga. The name is used in rollback error messages.
+func NewSaga(name string) *Saga {
+	return &Saga{name: name}
+}
+
+// Step runs action and, if it succeeds, records compensate so a later
+// Rollback can undo it. A nil compensate marks a step with nothing to undo.
+// The action's error is returned unchanged; Step never rolls back on its own,
+// so the caller decides whether a failure is fatal.
+func (s *Saga) Step(name string, action func() error, compensate func() error) error {
+	if action != nil {
+		if err := action(); err != nil {
+			return err
+		}
+	}
+	s.Record(name, compensate)
+	return nil
+}
+
+// Record registers a compensation for a step that was performed outside the
+// saga (e.g. by a callee that already succeeded).
+func (s *Saga) Record(name string, compensate func() error) {
+	if compensate == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done = append(s.done, sagaStep{name: name, compensate: compensate})
+}
+
+// Rollback runs the compensations of all completed steps in reverse order.
+// Rollback is best-effort: every compensation runs even if an earlier one
+// fails, and the failures are returned joined together.
+func (s *Saga) Rollback() error {
+	s.mu.Lock()
+	steps := s.done
+	s.done = nil
+	s.mu.Unlock()
+
+	var errs []error
+	for i := len(steps) - 1; i >= 0; i-- {
+		if err := steps[i].compensate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s rollback: undoing %s: %w", s.name, steps[i].name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
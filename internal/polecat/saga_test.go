@@ -0,0 +1,68 @@
+package polecat
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSaga_RollbackRunsCompensationsInReverse(t *testing.T) {
+	saga := NewSaga("spawn test/Toast")
+	var undone []string
+	undo := func(name string) func() error {
+		return func() error {
+			undone = append(undone, name)
+			return nil
+		}
+	}
+
+	if err := saga.Step("agent bead", nil, undo("agent bead")); err != nil {
+		t.Fatal(err)
+	}
+	if err := saga.Step("worktree", func() error { return nil }, undo("worktree")); err != nil {
+		t.Fatal(err)
+	}
+	saga.Record("dolt branch", undo("dolt branch"))
+
+	// A failing step is not recorded (so not undone) and its error is
+	// returned unchanged.
+	tmuxErr := errors.New("tmux: server exited unexpectedly")
+	if err := saga.Step("session", func() error { return tmuxErr }, undo("session")); err != tmuxErr {
+		t.Fatalf("Step error = %v, want %v", err, tmuxErr)
+	}
+
+	if err := saga.Rollback(); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if want := []string{"dolt branch", "worktree", "agent bead"}; !reflect.DeepEqual(undone, want) {
+		t.Errorf("compensation order = %v, want %v", undone, want)
+	}
+
+	// Rollback is idempotent.
+	undone = nil
+	if err := saga.Rollback(); err != nil || len(undone) != 0 {
+		t.Errorf("second Rollback ran %v (err %v), want nothing", undone, err)
+	}
+}
+
+func TestSaga_RollbackIsBestEffort(t *testing.T) {
+	saga := NewSaga("spawn test/Toast")
+	ranFirst := false
+	saga.Record("worktree", func() error {
+		ranFirst = true
+		return nil
+	})
+	saga.Record("session", func() error { return errors.New("no such session") })
+
+	err := saga.Rollback()
+	if err == nil {
+		t.Fatal("expected rollback error")
+	}
+	if !strings.Contains(err.Error(), "undoing session") {
+		t.Errorf("error %q should name the failed step", err)
+	}
+	if !ranFirst {
+		t.Error("earlier compensation should still run after a later one fails")
+	}
+}
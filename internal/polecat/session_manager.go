@@ -15,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/degraded"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
@@ -226,6 +227,12 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	townRoot := filepath.Dir(m.rig.Path)
 	runtimeConfig := config.ResolveRoleAgentConfig("polecat", townRoot, m.rig.Path)
 
+	// Don't launch agents while the model API is down: they would only die
+	// at startup or sit at an error prompt holding a slot.
+	if degraded.IsActive(townRoot) {
+		return fmt.Errorf("%w: not starting %s (see 'gt degraded status')", degraded.ErrDegraded, sessionID)
+	}
+
 	// Ensure runtime settings exist in the shared polecats parent directory.
 	// Settings are passed to Claude Code via --settings flag.
 	polecatSettingsDir := config.RoleSettingsDir("polecat", m.rig.Path)
@@ -295,6 +302,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
+	bootStart := time.Now()
 	if err := m.tmux.NewSessionWithCommand(sessionID, workDir, command); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
@@ -356,12 +364,17 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 
 	// Wait for Claude to start (non-fatal)
 	debugSession("WaitForCommand", m.tmux.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout))
+	shellInit := time.Since(bootStart)
 
 	// Accept bypass permissions warning dialog if it appears
-	debugSession("AcceptBypassPermissionsWarning", m.tmux.AcceptBypassPermissionsWarning(sessionID))
+	if runtime.EmitsPermissionWarning(runtimeConfig) {
+		debugSession("AcceptBypassPermissionsWarning", m.tmux.AcceptBypassPermissionsWarning(sessionID))
+	}
 
-	// Wait for runtime to be fully ready at the prompt (not just started)
-	runtime.SleepForReadyDelay(runtimeConfig)
+	// Wait for runtime to be fully ready at the prompt (not just started):
+	// its ready_pattern if configured, otherwise the ready delay.
+	debugSession("WaitForReady", runtime.WaitForReady(m.tmux, sessionID, runtimeConfig, constants.ClaudeStartTimeout))
+	bootTotal := time.Since(bootStart)
 
 	// Handle fallback nudges for non-hook agents.
 	// See StartupFallbackInfo in runtime package for the fallback matrix.
@@ -396,8 +409,16 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		return fmt.Errorf("verifying session: %w", err)
 	}
 	if !running {
-		return fmt.Errorf("session %s died during startup (agent command may have failed)", sessionID)
+		startErr := fmt.Errorf("session %s died during startup (agent command may have failed)", sessionID)
+		// Repeated launch failures may mean the model API is down (non-fatal)
+		if entered, err := degraded.ReportLaunchFailure(townRoot, startErr); err != nil {
+			debugSession("ReportLaunchFailure", err)
+		} else if entered {
+			style.PrintWarning("model API unreachable: town entered degraded mode, spawns paused")
+		}
+		return startErr
 	}
+	debugSession("ReportLaunchSuccess", degraded.ReportLaunchSuccess(townRoot))
 
 	// Validate GT_AGENT is set. Without GT_AGENT, IsAgentAlive falls back to
 	// ["node", "claude"] process detection and witness patrol will auto-nuke
@@ -414,6 +435,16 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// Track PID for defense-in-depth orphan cleanup (non-fatal)
 	_ = session.TrackSessionPID(townRoot, sessionID, m.tmux)
 
+	// Record boot time on the agent bead and flag spawns over budget (non-fatal)
+	m.recordBootTime(townRoot, polecat, BootRecord{
+		Polecat:      polecat,
+		StartedAt:    bootStart,
+		ShellInitMs:  shellInit.Milliseconds(),
+		AgentReadyMs: (bootTotal - shellInit).Milliseconds(),
+		TotalMs:      bootTotal.Milliseconds(),
+		PrimingBytes: primingBytes(workDir, townRoot, beacon),
+	})
+
 	return nil
 }
 
@@ -0,0 +1,158 @@
+package polecat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// triggerRateWindow is the window MaxPerMinute is counted over.
+const triggerRateWindow = time.Minute
+
+// TriggerLimits caps how fast pending spawns are triggered. A ready spawn
+// over a limit is held: it stays pending, keeps its place in the queue
+// (oldest first) and is triggered on a later pass once there is room.
+// Zero fields mean no limit.
+type TriggerLimits struct {
+	// MaxPerRig caps the polecats working at once in one rig: running
+	// polecat sessions that are no longer pending.
+	MaxPerRig int
+
+	// MaxPerMinute caps triggers across the town in any one minute.
+	MaxPerMinute int
+}
+
+// triggerGate admits ready spawns within a pass while tracking the room
+// left under TriggerLimits.
+type triggerGate struct {
+	limits TriggerLimits
+	active map[string]int // working polecats per rig
+	recent int            // triggers in the current rate window
+}
+
+// admit reports whether a spawn in rig may be triggered now, and if not,
+// which limit holds it.
+func (g *triggerGate) admit(rig string) (bool, string) {
+	if g.limits.MaxPerRig > 0 && g.active[rig] >= g.limits.MaxPerRig {
+		return false, fmt.Sprintf("%s has %d/%d polecats working", rig, g.active[rig], g.limits.MaxPerRig)
+	}
+	if g.limits.MaxPerMinute > 0 && g.recent >= g.limits.MaxPerMinute {
+		return false, fmt.Sprintf("%d/%d triggers in the last minute", g.recent, g.limits.MaxPerMinute)
+	}
+	return true, ""
+}
+
+// record counts a trigger in rig against the limits.
+func (g *triggerGate) record(rig string) {
+	g.active[rig]++
+	g.recent++
+}
+
+// newTriggerGate counts the working polecats and recent triggers the
+// limits need. Counts a limit doesn't use are skipped.
+func newTriggerGate(townRoot string, limits TriggerLimits, pending []*PendingSpawn, sessions func() ([]string, error), now time.Time) (*triggerGate, error) {
+	g := &triggerGate{limits: limits, active: make(map[string]int)}
+	if limits.MaxPerRig > 0 {
+		names, err := sessions()
+		if err != nil {
+			return nil, fmt.Errorf("listing sessions: %w", err)
+		}
+		g.active = workingPolecats(names, pending)
+	}
+	if limits.MaxPerMinute > 0 {
+		g.recent = len(loadRecentTriggers(townRoot, now))
+	}
+	return g, nil
+}
+
+// workingPolecats counts polecat sessions per rig, leaving out the ones
+// still waiting in pending.
+func workingPolecats(sessions []string, pending []*PendingSpawn) map[string]int {
+	waiting := make(map[string]bool, len(pending))
+	for _, ps := range pending {
+		waiting[ps.Session] = true
+	}
+	counts := make(map[string]int)
+	for _, name := range sessions {
+		if waiting[name] {
+			continue
+		}
+		id, err := session.ParseSessionName(name)
+		if err != nil || id.Role != session.RolePolecat {
+			continue
+		}
+		counts[id.Rig]++
+	}
+	return counts
+}
+
+// sortPendingOldestFirst orders pending spawns by spawn time, the order
+// they are admitted in.
+func sortPendingOldestFirst(pending []*PendingSpawn) {
+	sort.SliceStable(pending, func(i, j int) bool { return pending[i].SpawnedAt.Before(pending[j].SpawnedAt) })
+}
+
+// QueuePositions numbers the held spawns in pending from 1, oldest first:
+// the order they will be triggered in as room frees up. Spawns that aren't
+// held are left out.
+func QueuePositions(pending []*PendingSpawn) map[string]int {
+	held := make([]*PendingSpawn, 0, len(pending))
+	for _, ps := range pending {
+		if !ps.HeldSince.IsZero() {
+			held = append(held, ps)
+		}
+	}
+	sortPendingOldestFirst(held)
+	positions := make(map[string]int, len(held))
+	for i, ps := range held {
+		positions[ps.MailID] = i + 1
+	}
+	return positions
+}
+
+// triggerLogFile returns the path to the recent trigger times used for
+// MaxPerMinute.
+func triggerLogFile(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "pending-triggers.json")
+}
+
+// loadRecentTriggers returns the trigger times within the rate window
+// before now.
+func loadRecentTriggers(townRoot string, now time.Time) []time.Time {
+	var times []time.Time
+	data, err := os.ReadFile(triggerLogFile(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		return nil
+	}
+	_ = json.Unmarshal(data, &times)
+	recent := times[:0]
+	for _, t := range times {
+		if now.Sub(t) < triggerRateWindow {
+			recent = append(recent, t)
+		}
+	}
+	return recent
+}
+
+// recordTrigger adds a trigger at now to the log, dropping entries outside
+// the rate window.
+func recordTrigger(townRoot string, now time.Time) error {
+	p := triggerLogFile(townRoot)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	fl := flock.New(p + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking trigger log: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	return util.AtomicWriteJSON(p, append(loadRecentTriggers(townRoot, now), now))
+}
@@ -0,0 +1,117 @@
+package polecat
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func TestTriggerGate(t *testing.T) {
+	g := &triggerGate{limits: TriggerLimits{MaxPerRig: 2, MaxPerMinute: 3}, active: map[string]int{"gastown": 1}}
+
+	if ok, _ := g.admit("gastown"); !ok {
+		t.Fatal("expected room for a second gastown polecat")
+	}
+	g.record("gastown")
+	if ok, why := g.admit("gastown"); ok || why == "" {
+		t.Errorf("gastown at its cap: admit = %v, %q", ok, why)
+	}
+	if ok, _ := g.admit("beads"); !ok {
+		t.Error("the cap is per rig; beads should still be admitted")
+	}
+	g.record("beads")
+	g.record("beads")
+	if ok, why := g.admit("other"); ok || why == "" {
+		t.Errorf("rate limit reached: admit = %v, %q", ok, why)
+	}
+
+	unlimited := &triggerGate{active: map[string]int{"gastown": 50}, recent: 50}
+	if ok, _ := unlimited.admit("gastown"); !ok {
+		t.Error("zero limits should admit everything")
+	}
+}
+
+func TestNewTriggerGate_CountsWorkingPolecats(t *testing.T) {
+	reg := session.NewPrefixRegistry()
+	reg.Register("gt", "gastown")
+	reg.Register("bd", "beads")
+	old := session.DefaultRegistry()
+	session.SetDefaultRegistry(reg)
+	t.Cleanup(func() { session.SetDefaultRegistry(old) })
+
+	sessions := func() ([]string, error) {
+		return []string{"gt-nux", "gt-slit", "gt-witness", "gt-crew-max", "bd-furiosa", "hq-deacon", "scratch"}, nil
+	}
+	pending := []*PendingSpawn{{Session: "gt-slit"}}
+	g, err := newTriggerGate(t.TempDir(), TriggerLimits{MaxPerRig: 1}, pending, sessions, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.active["gastown"] != 1 || g.active["beads"] != 1 {
+		t.Errorf("active = %v, want gastown=1 (pending slit excluded) beads=1", g.active)
+	}
+
+	failing := func() ([]string, error) { return nil, errors.New("no server") }
+	if _, err := newTriggerGate(t.TempDir(), TriggerLimits{MaxPerRig: 1}, nil, failing, time.Now()); err == nil {
+		t.Error("expected a session listing error with a per-rig cap")
+	}
+	if _, err := newTriggerGate(t.TempDir(), TriggerLimits{}, nil, failing, time.Now()); err != nil {
+		t.Errorf("sessions shouldn't be listed without a per-rig cap: %v", err)
+	}
+}
+
+func TestRecentTriggers(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now().UTC()
+	for _, at := range []time.Time{now.Add(-2 * time.Minute), now.Add(-30 * time.Second), now} {
+		if err := recordTrigger(townRoot, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := len(loadRecentTriggers(townRoot, now)); got != 2 {
+		t.Errorf("recent triggers = %d, want 2", got)
+	}
+	g, err := newTriggerGate(townRoot, TriggerLimits{MaxPerMinute: 2}, nil, nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := g.admit("gastown"); ok {
+		t.Error("two triggers in the last minute should use up a limit of 2")
+	}
+}
+
+func TestQueuePositions(t *testing.T) {
+	base := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	pending := []*PendingSpawn{
+		{MailID: "c", SpawnedAt: base.Add(3 * time.Minute), HeldSince: base.Add(4 * time.Minute)},
+		{MailID: "a", SpawnedAt: base.Add(time.Minute), HeldSince: base.Add(4 * time.Minute)},
+		{MailID: "b", SpawnedAt: base.Add(2 * time.Minute)},
+	}
+	got := QueuePositions(pending)
+	if len(got) != 2 || got["a"] != 1 || got["c"] != 2 {
+		t.Errorf("QueuePositions = %v, want a=1 c=2", got)
+	}
+	if pending[0].MailID != "c" {
+		t.Error("QueuePositions must not reorder its input")
+	}
+}
+
+func TestPendingAttempts_HeldSurvivesRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	held := time.Now().UTC().Truncate(time.Second)
+	ps := &PendingSpawn{MailID: "msg-1", HeldSince: held}
+	if err := recordAttempt(townRoot, ps); err != nil {
+		t.Fatal(err)
+	}
+	if got := loadPendingAttempts(townRoot)["msg-1"]; !got.HeldSince.Equal(held) {
+		t.Errorf("HeldSince = %v, want %v", got.HeldSince, held)
+	}
+	if err := finishSpawn(townRoot, ps); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := loadPendingAttempts(townRoot)["msg-1"]; ok {
+		t.Error("finishing a held spawn should forget its state")
+	}
+}
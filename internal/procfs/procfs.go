@@ -0,0 +1,124 @@
+// Package procfs reads process statistics from the Linux /proc filesystem.
+// Every function takes the mount point, so tests can point it at a fake
+// tree. On other platforms reads fail and callers treat the data as
+// unavailable.
+package procfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Root is where the Linux process filesystem is mounted.
+const Root = "/proc"
+
+// ClockTicks is the kernel's USER_HZ, the unit of the CPU times in
+// /proc/<pid>/stat. It is 100 on every mainstream Linux architecture.
+const ClockTicks = 100
+
+// Stat is the part of /proc/<pid>/stat the town cares about.
+type Stat struct {
+	PPID      int
+	CPUTicks  int64  // utime + stime, in ClockTicks
+	StartTime string // start time in clock ticks since boot (field 22)
+}
+
+// ReadStat parses /proc/<pid>/stat. The command name in field 2 may
+// contain spaces, so fields are counted from the closing parenthesis.
+func ReadStat(root string, pid int) (*Stat, error) {
+	data, err := os.ReadFile(filepath.Join(root, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return nil, err
+	}
+	s := string(data)
+	i := strings.LastIndexByte(s, ')')
+	if i < 0 {
+		return nil, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+	fields := strings.Fields(s[i+1:])
+	// fields[0] is field 3 (state): ppid is field 4, utime and stime are
+	// fields 14 and 15, starttime is field 22.
+	if len(fields) < 20 {
+		return nil, fmt.Errorf("short stat for pid %d", pid)
+	}
+	ppid, _ := strconv.Atoi(fields[1])
+	utime, _ := strconv.ParseInt(fields[11], 10, 64)
+	stime, _ := strconv.ParseInt(fields[12], 10, 64)
+	return &Stat{PPID: ppid, CPUTicks: utime + stime, StartTime: fields[19]}, nil
+}
+
+// StartTime returns a process's start time from /proc/<pid>/stat, or "" if
+// unreadable. Together with the pid it identifies a process across pid reuse.
+func StartTime(root string, pid int) string {
+	stat, err := ReadStat(root, pid)
+	if err != nil {
+		return ""
+	}
+	return stat.StartTime
+}
+
+// StatusKB returns a "Key:   123 kB" value from /proc/<pid>/status or
+// /proc/meminfo, in kB. Returns 0 if the key is missing.
+func StatusKB(content, key string) uint64 {
+	for _, line := range strings.Split(content, "\n") {
+		k, v, ok := strings.Cut(line, ":")
+		if !ok || k != key {
+			continue
+		}
+		fields := strings.Fields(v)
+		if len(fields) == 0 {
+			return 0
+		}
+		n, _ := strconv.ParseUint(fields[0], 10, 64)
+		return n
+	}
+	return 0
+}
+
+// RSSBytes returns a process's resident set size from /proc/<pid>/status.
+func RSSBytes(root string, pid int) (uint64, error) {
+	status, err := os.ReadFile(filepath.Join(root, strconv.Itoa(pid), "status"))
+	if err != nil {
+		return 0, err
+	}
+	return StatusKB(string(status), "VmRSS") * 1024, nil
+}
+
+// CountFDs returns how many descriptors a process has open.
+func CountFDs(root string, pid int) (int, error) {
+	entries, err := os.ReadDir(filepath.Join(root, strconv.Itoa(pid), "fd"))
+	if err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// Tree returns pid and all its descendants, found by parent pid. Processes
+// that exit mid-scan are skipped.
+func Tree(root string, pid int) ([]int, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", root, err)
+	}
+	children := make(map[int][]int)
+	for _, entry := range entries {
+		child, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		stat, err := ReadStat(root, child)
+		if err != nil {
+			continue
+		}
+		children[stat.PPID] = append(children[stat.PPID], child)
+	}
+
+	tree := []int{pid}
+	for i := 0; i < len(tree); i++ {
+		tree = append(tree, children[tree[i]]...)
+	}
+	return tree, nil
+}
@@ -0,0 +1,89 @@
+package procfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// fakeProc writes a /proc entry for pid: its stat (with a command name
+// containing spaces, as tmux's "tmux: server" does), status and nfds
+// descriptors.
+func fakeProc(t *testing.T, root string, pid, ppid int, utime, stime int64, rssKB, nfds int) {
+	t.Helper()
+	dir := filepath.Join(root, fmt.Sprint(pid))
+	if err := os.MkdirAll(filepath.Join(dir, "fd"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	stat := fmt.Sprintf("%d (cmd with spaces) S %d 1 1 0 -1 0 0 0 0 0 %d %d 0 0 20 0 1 0 4242 0 0", pid, ppid, utime, stime)
+	files := map[string]string{
+		"stat":   stat,
+		"status": fmt.Sprintf("Name:\tcmd\nVmRSS:\t%d kB\n", rssKB),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < nfds; i++ {
+		if err := os.Symlink("/dev/null", filepath.Join(dir, "fd", fmt.Sprint(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadStat(t *testing.T) {
+	root := t.TempDir()
+	fakeProc(t, root, 100, 1, 30, 12, 1000, 0)
+
+	stat, err := ReadStat(root, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Stat{PPID: 1, CPUTicks: 42, StartTime: "4242"}
+	if *stat != want {
+		t.Errorf("ReadStat = %+v, want %+v", *stat, want)
+	}
+	if got := StartTime(root, 100); got != "4242" {
+		t.Errorf("StartTime = %q, want 4242", got)
+	}
+	if got := StartTime(root, 999); got != "" {
+		t.Errorf("StartTime of a missing pid = %q, want empty", got)
+	}
+}
+
+func TestTreeRSSAndFDs(t *testing.T) {
+	root := t.TempDir()
+	fakeProc(t, root, 100, 1, 0, 0, 1000, 3)   // pane shell
+	fakeProc(t, root, 200, 100, 0, 0, 5000, 4) // agent
+	fakeProc(t, root, 300, 200, 0, 0, 200, 1)  // agent's subprocess
+	fakeProc(t, root, 400, 1, 0, 0, 9000, 9)   // unrelated
+
+	tree, err := Tree(root, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(tree)
+	if !slices.Equal(tree, []int{100, 200, 300}) {
+		t.Errorf("Tree = %v, want [100 200 300]", tree)
+	}
+
+	if rss, err := RSSBytes(root, 200); err != nil || rss != 5000*1024 {
+		t.Errorf("RSSBytes = %d, %v; want %d", rss, err, 5000*1024)
+	}
+	if n, err := CountFDs(root, 200); err != nil || n != 4 {
+		t.Errorf("CountFDs = %d, %v; want 4", n, err)
+	}
+}
+
+func TestStatusKB(t *testing.T) {
+	content := "MemTotal:       16384 kB\nMemAvailable:    8192 kB\n"
+	if got := StatusKB(content, "MemAvailable"); got != 8192 {
+		t.Errorf("StatusKB(MemAvailable) = %d, want 8192", got)
+	}
+	if got := StatusKB(content, "SwapTotal"); got != 0 {
+		t.Errorf("StatusKB(missing) = %d, want 0", got)
+	}
+}
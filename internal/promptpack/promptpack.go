@@ -0,0 +1,178 @@
+// Package promptpack versions the role priming agents start with.
+//
+// A prompt version is either the role templates built into gt ("builtin")
+// or a prompt pack: a directory <town>/prompts/<version>/ whose
+// roles/*.md.tmpl (and messages/*.md.tmpl) override the built-in templates
+// of the same name. Each agent's version is resolved when it is primed:
+//
+//	rig pin (rig settings prompt_version)
+//	  → staged rollout (agents already moved by 'gt prompt rollout')
+//	  → town default (town settings prompt_version)
+//	  → builtin
+//
+// 'gt prime' records the version each session was primed with, so drift
+// between what is running and what is configured can be reported and
+// rolled out gradually.
+package promptpack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/templates"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Builtin is the version of the role templates compiled into gt.
+const Builtin = "builtin"
+
+// Resolution sources, from most to least specific.
+const (
+	SourceRigPin  = "rig-pin"
+	SourceRollout = "rollout"
+	SourceTown    = "town"
+	SourceDefault = "default"
+)
+
+// versionName matches valid prompt pack directory names.
+var versionName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ErrUnknownVersion is returned for a version with no prompt pack.
+var ErrUnknownVersion = errors.New("unknown prompt version")
+
+// Dir returns the directory holding a town's prompt packs.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, "prompts")
+}
+
+// PackDir returns the directory of one prompt pack.
+func PackDir(townRoot, version string) string {
+	return filepath.Join(Dir(townRoot), version)
+}
+
+// List returns the available versions: builtin, then the town's prompt
+// packs in name order.
+func List(townRoot string) ([]string, error) {
+	versions := []string{Builtin}
+	entries, err := os.ReadDir(Dir(townRoot))
+	if os.IsNotExist(err) {
+		return versions, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading prompt packs: %w", err)
+	}
+	var packs []string
+	for _, e := range entries {
+		if e.IsDir() && versionName.MatchString(e.Name()) && e.Name() != Builtin {
+			packs = append(packs, e.Name())
+		}
+	}
+	sort.Strings(packs)
+	return append(versions, packs...), nil
+}
+
+// Validate checks that version names builtin or an existing prompt pack.
+func Validate(townRoot, version string) error {
+	if version == Builtin {
+		return nil
+	}
+	if !versionName.MatchString(version) {
+		return fmt.Errorf("%w %q: invalid name", ErrUnknownVersion, version)
+	}
+	if info, err := os.Stat(PackDir(townRoot, version)); err != nil || !info.IsDir() {
+		return fmt.Errorf("%w %q: no prompt pack at %s", ErrUnknownVersion, version, PackDir(townRoot, version))
+	}
+	return nil
+}
+
+// Templates returns the role and message templates for version.
+func Templates(townRoot, version string) (*templates.Templates, error) {
+	if version == "" || version == Builtin {
+		return templates.New()
+	}
+	if err := Validate(townRoot, version); err != nil {
+		return nil, err
+	}
+	return templates.NewWithOverrides(PackDir(townRoot, version))
+}
+
+// Resolve returns the prompt version agent (a mail-style address such as
+// "gastown/witness") should be primed with, and where that came from.
+// rig is empty for town-level agents.
+func Resolve(townRoot, rig, agent string) (version, source string) {
+	if rig != "" {
+		if settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rig))); err == nil && settings.PromptVersion != "" {
+			return settings.PromptVersion, SourceRigPin
+		}
+	}
+	if r, err := LoadRollout(townRoot); err == nil && r != nil && r.Status == RolloutRunning && r.Moved(agent) {
+		return r.To, SourceRollout
+	}
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && settings.PromptVersion != "" {
+		return settings.PromptVersion, SourceTown
+	}
+	return Builtin, SourceDefault
+}
+
+// Session is the prompt version an agent's current session was primed with.
+type Session struct {
+	Agent     string    `json:"agent"`
+	Version   string    `json:"version"`
+	Source    string    `json:"source"`
+	SessionID string    `json:"session_id,omitempty"`
+	PrimedAt  time.Time `json:"primed_at"`
+}
+
+// SessionsPath returns the path of the town's session version registry.
+func SessionsPath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "prompt-sessions.json")
+}
+
+// LoadSessions returns the recorded sessions by agent.
+func LoadSessions(townRoot string) (map[string]Session, error) {
+	sessions := make(map[string]Session)
+	data, err := os.ReadFile(SessionsPath(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if os.IsNotExist(err) {
+		return sessions, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading prompt sessions: %w", err)
+	}
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, fmt.Errorf("parsing prompt sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// RecordSession records the version an agent's session was primed with,
+// replacing the agent's previous session.
+func RecordSession(townRoot string, s Session) error {
+	if s.PrimedAt.IsZero() {
+		s.PrimedAt = time.Now().UTC()
+	}
+	path := SessionsPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking prompt sessions: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	sessions, err := LoadSessions(townRoot)
+	if err != nil {
+		sessions = make(map[string]Session) // Corrupt registry; start over
+	}
+	sessions[s.Agent] = s
+	return util.AtomicWriteJSON(path, sessions)
+}
@@ -0,0 +1,149 @@
+package promptpack
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/templates"
+)
+
+func writePack(t *testing.T, townRoot, version, role, body string) {
+	t.Helper()
+	dir := filepath.Join(PackDir(townRoot, version), "roles")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, role+".md.tmpl"), []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListAndValidate(t *testing.T) {
+	townRoot := t.TempDir()
+	versions, err := List(townRoot)
+	if err != nil || !reflect.DeepEqual(versions, []string{Builtin}) {
+		t.Fatalf("List without packs = %v, %v", versions, err)
+	}
+
+	writePack(t, townRoot, "v2", "witness", "v2 witness")
+	writePack(t, townRoot, "v10", "witness", "v10 witness")
+	versions, _ = List(townRoot)
+	if !reflect.DeepEqual(versions, []string{Builtin, "v10", "v2"}) {
+		t.Errorf("List = %v", versions)
+	}
+
+	if err := Validate(townRoot, "v2"); err != nil {
+		t.Errorf("Validate(v2) = %v", err)
+	}
+	for _, bad := range []string{"v3", "../etc", ""} {
+		if err := Validate(townRoot, bad); !errors.Is(err, ErrUnknownVersion) {
+			t.Errorf("Validate(%q) = %v, want ErrUnknownVersion", bad, err)
+		}
+	}
+}
+
+func TestTemplatesOverridesOnlyPackRoles(t *testing.T) {
+	townRoot := t.TempDir()
+	writePack(t, townRoot, "v2", "witness", "PACK WITNESS for {{ .RigName }}")
+
+	tmpl, err := Templates(townRoot, "v2")
+	if err != nil {
+		t.Fatalf("Templates: %v", err)
+	}
+	out, err := tmpl.RenderRole("witness", templates.RoleData{RigName: "gastown"})
+	if err != nil || out != "PACK WITNESS for gastown" {
+		t.Errorf("witness = %q, %v", out, err)
+	}
+	out, err = tmpl.RenderRole("refinery", templates.RoleData{RigName: "gastown"})
+	if err != nil || strings.Contains(out, "PACK") || out == "" {
+		t.Errorf("refinery should stay built in, got %q, %v", out, err)
+	}
+}
+
+func TestResolvePrecedence(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+
+	if v, src := Resolve(townRoot, "gastown", "gastown/witness"); v != Builtin || src != SourceDefault {
+		t.Errorf("no config: %s (%s)", v, src)
+	}
+
+	settings := config.NewTownSettings()
+	settings.PromptVersion = "v1"
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	if v, src := Resolve(townRoot, "gastown", "gastown/witness"); v != "v1" || src != SourceTown {
+		t.Errorf("town default: %s (%s)", v, src)
+	}
+
+	r := &Rollout{From: "v1", To: "v2", Status: RolloutRunning, Agents: []string{"gastown/witness"}}
+	if err := SaveRollout(townRoot, r); err != nil {
+		t.Fatal(err)
+	}
+	if v, src := Resolve(townRoot, "gastown", "gastown/witness"); v != "v2" || src != SourceRollout {
+		t.Errorf("moved agent: %s (%s)", v, src)
+	}
+	if v, _ := Resolve(townRoot, "gastown", "gastown/refinery"); v != "v1" {
+		t.Errorf("agent not yet moved should keep the town default, got %s", v)
+	}
+
+	rigSettings := config.NewRigSettings()
+	rigSettings.PromptVersion = "v0"
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), rigSettings); err != nil {
+		t.Fatal(err)
+	}
+	if v, src := Resolve(townRoot, "gastown", "gastown/witness"); v != "v0" || src != SourceRigPin {
+		t.Errorf("rig pin: %s (%s)", v, src)
+	}
+
+	// An aborted rollout no longer applies.
+	if err := os.Remove(config.RigSettingsPath(rigPath)); err != nil {
+		t.Fatal(err)
+	}
+	r.Status = RolloutAborted
+	if err := SaveRollout(townRoot, r); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := Resolve(townRoot, "gastown", "gastown/witness"); v != "v1" {
+		t.Errorf("aborted rollout: got %s, want town default v1", v)
+	}
+}
+
+func TestRecordSession(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := RecordSession(townRoot, Session{Agent: "mayor", Version: Builtin}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordSession(townRoot, Session{Agent: "mayor", Version: "v2", SessionID: "abc"}); err != nil {
+		t.Fatal(err)
+	}
+	sessions, err := LoadSessions(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := sessions["mayor"]
+	if len(sessions) != 1 || s.Version != "v2" || s.SessionID != "abc" || s.PrimedAt.IsZero() {
+		t.Errorf("sessions = %+v", sessions)
+	}
+}
+
+func TestRolloutFailureRate(t *testing.T) {
+	r := &Rollout{}
+	if r.FailureRate() != 0 {
+		t.Error("empty rollout should have no failures")
+	}
+	r.Agents = []string{"a", "b", "c", "d"}
+	r.Failed = []string{"c"}
+	if got := r.FailureRate(); got != 0.25 {
+		t.Errorf("FailureRate = %v, want 0.25", got)
+	}
+	if !r.Moved("b") || r.Moved("e") {
+		t.Error("Moved mismatch")
+	}
+}
@@ -0,0 +1,88 @@
+package promptpack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Rollout statuses.
+const (
+	RolloutRunning   = "running"
+	RolloutCompleted = "completed"
+	RolloutAborted   = "aborted"
+)
+
+// Rollout is a staged move of the town's agents from one prompt version to
+// another. Only a running rollout affects resolution: agents in Agents
+// resolve to To, everyone else keeps the town default (From).
+type Rollout struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Status    string    `json:"status"`
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at,omitempty"`
+
+	// Agents have been restarted onto To, in order.
+	Agents []string `json:"agents,omitempty"`
+
+	// Failed are moved agents that failed their health check.
+	Failed []string `json:"failed,omitempty"`
+
+	// Reason explains an aborted rollout.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Moved reports whether agent has been restarted onto the new version.
+func (r *Rollout) Moved(agent string) bool {
+	for _, a := range r.Agents {
+		if a == agent {
+			return true
+		}
+	}
+	return false
+}
+
+// FailureRate is the fraction of moved agents that failed.
+func (r *Rollout) FailureRate() float64 {
+	if len(r.Agents) == 0 {
+		return 0
+	}
+	return float64(len(r.Failed)) / float64(len(r.Agents))
+}
+
+// RolloutPath returns the path of the town's rollout state.
+func RolloutPath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "prompt-rollout.json")
+}
+
+// LoadRollout returns the town's most recent rollout, or nil if there has
+// never been one.
+func LoadRollout(townRoot string) (*Rollout, error) {
+	data, err := os.ReadFile(RolloutPath(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading prompt rollout: %w", err)
+	}
+	var r Rollout
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing prompt rollout: %w", err)
+	}
+	return &r, nil
+}
+
+// SaveRollout writes the town's rollout state.
+func SaveRollout(townRoot string, r *Rollout) error {
+	path := RolloutPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, r)
+}
@@ -19,10 +19,12 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/degraded"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/smokegate"
 )
 
 // DefaultStaleClaimTimeout is the default duration after which a claimed MR
@@ -54,6 +56,12 @@ type GateConfig struct {
 	// Timeout is the maximum time the gate command may run.
 	// Zero means no timeout (inherits context deadline).
 	Timeout time.Duration `json:"timeout"`
+
+	// RequiresModel marks a gate that calls the model API (e.g., AI review).
+	// While the town is in degraded mode, MRs are held rather than run
+	// through such gates, since they would fail for reasons unrelated to
+	// the change.
+	RequiresModel bool `json:"requires_model"`
 }
 
 // GateResult holds the outcome of a single gate execution.
@@ -111,6 +119,11 @@ type MergeQueueConfig struct {
 	// GatesParallel controls whether gates run concurrently.
 	// When true, all gates start simultaneously; any failure = overall failure.
 	GatesParallel bool `json:"gates_parallel"`
+
+	// HotfixGates are the quality gates for the hotfix lane (MRs labeled
+	// gt:hotfix), typically a fast smoke test instead of the full suite.
+	// When empty, hotfixes run the normal gates.
+	HotfixGates map[string]*GateConfig `json:"hotfix_gates"`
 }
 
 // DefaultMergeQueueConfig returns sensible defaults for merge queue configuration.
@@ -145,6 +158,7 @@ type MRInfo struct {
 	ConvoyCreatedAt *time.Time // Convoy creation time
 	CreatedAt       time.Time  // MR creation time
 	BlockedBy       string     // Task ID blocking this MR
+	Hotfix          bool       // Labeled gt:hotfix: sorts first, runs HotfixGates
 
 	// Raw data for agent-side queue health analysis (ZFC: agent decides, Go transports)
 	UpdatedAt          time.Time // When the MR was last updated
@@ -195,6 +209,10 @@ type Engineer struct {
 	mergeSlotRelease      func(holder string) error
 	mergeSlotMaxRetries   int           // Max retries for slot acquisition (0 = no retry)
 	mergeSlotRetryBackoff time.Duration // Initial backoff between retries
+	townDegraded          func() bool   // Reports whether the model API is down
+	rigSmokeGated         func() bool   // Reports whether the rig is held by its smoke gate
+	hotfixWaiting         func() string // Returns a queued preempting hotfix MR, or ""
+	processing            string        // ID of the MR being processed, if any
 }
 
 // NewEngineer creates a new Engineer for the given rig.
@@ -210,7 +228,7 @@ func NewEngineer(r *rig.Rig) *Engineer {
 	}
 	beadsClient := beads.New(r.Path)
 
-	return &Engineer{
+	e := &Engineer{
 		rig:     r,
 		beads:   beadsClient,
 		git:     git.NewGit(gitDir),
@@ -229,7 +247,17 @@ func NewEngineer(r *rig.Rig) *Engineer {
 		},
 		mergeSlotMaxRetries:   10,
 		mergeSlotRetryBackoff: 500 * time.Millisecond,
+		townDegraded: func() bool {
+			return degraded.IsActive(filepath.Dir(r.Path))
+		},
+		rigSmokeGated: func() bool {
+			return smokegate.IsRequired(r.Path)
+		},
 	}
+	e.hotfixWaiting = func() string {
+		return e.findPreemptingHotfix(e.processing)
+	}
+	return e
 }
 
 // SetOutput sets the output writer for user-facing messages.
@@ -277,6 +305,7 @@ func (e *Engineer) LoadConfig() error {
 		StaleClaimTimeout    *string                    `json:"stale_claim_timeout"`
 		Gates                map[string]*gateConfigRaw  `json:"gates"`
 		GatesParallel        *bool                      `json:"gates_parallel"`
+		HotfixGates          map[string]*gateConfigRaw  `json:"hotfix_gates"`
 	}
 
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
@@ -325,34 +354,52 @@ func (e *Engineer) LoadConfig() error {
 
 	// Parse gates configuration
 	if mqRaw.Gates != nil {
-		e.config.Gates = make(map[string]*GateConfig, len(mqRaw.Gates))
-		for name, raw := range mqRaw.Gates {
-			gc := &GateConfig{Cmd: raw.Cmd}
-			if raw.Timeout != "" {
-				dur, err := time.ParseDuration(raw.Timeout)
-				if err != nil {
-					return fmt.Errorf("invalid timeout for gate %q: %w", name, err)
-				}
-				if dur <= 0 {
-					return fmt.Errorf("gate %q timeout must be positive, got %v", name, dur)
-				}
-				gc.Timeout = dur
-			}
-			e.config.Gates[name] = gc
+		gates, err := parseGateConfigs(mqRaw.Gates)
+		if err != nil {
+			return err
 		}
+		e.config.Gates = gates
 	}
 	if mqRaw.GatesParallel != nil {
 		e.config.GatesParallel = *mqRaw.GatesParallel
 	}
+	if mqRaw.HotfixGates != nil {
+		gates, err := parseGateConfigs(mqRaw.HotfixGates)
+		if err != nil {
+			return fmt.Errorf("hotfix_gates: %w", err)
+		}
+		e.config.HotfixGates = gates
+	}
 
 	return nil
 }
 
+// parseGateConfigs converts raw gate configs, parsing their timeouts.
+func parseGateConfigs(raws map[string]*gateConfigRaw) (map[string]*GateConfig, error) {
+	gates := make(map[string]*GateConfig, len(raws))
+	for name, raw := range raws {
+		gc := &GateConfig{Cmd: raw.Cmd, RequiresModel: raw.RequiresModel}
+		if raw.Timeout != "" {
+			dur, err := time.ParseDuration(raw.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeout for gate %q: %w", name, err)
+			}
+			if dur <= 0 {
+				return nil, fmt.Errorf("gate %q timeout must be positive, got %v", name, dur)
+			}
+			gc.Timeout = dur
+		}
+		gates[name] = gc
+	}
+	return gates, nil
+}
+
 // gateConfigRaw is the JSON-friendly representation of a gate config
 // with timeout as a string duration.
 type gateConfigRaw struct {
-	Cmd     string `json:"cmd"`
-	Timeout string `json:"timeout"`
+	Cmd           string `json:"cmd"`
+	Timeout       string `json:"timeout"`
+	RequiresModel bool   `json:"requires_model"`
 }
 
 // Config returns the current merge queue configuration.
@@ -367,11 +414,25 @@ type ProcessResult struct {
 	Error       string
 	Conflict    bool
 	TestsFailed bool
-	SlotTimeout bool // Merge slot contention timeout (distinct from build/test failure)
+	SlotTimeout bool   // Merge slot contention timeout (distinct from build/test failure)
+	GatesFrozen bool   // Model-dependent gates frozen while the town is degraded
+	SmokeHeld   bool   // Rig held after an incident until its smoke gate passes
+	Preempted   bool   // Abandoned before push so a waiting hotfix can land first
+	PreemptedBy string // Hotfix MR that preempted this one
 }
 
 // doMerge performs the actual git merge operation.
-func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue string) ProcessResult {
+func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue string, lane mergeLane) ProcessResult {
+	// Hold everything while the rig waits for its post-incident smoke gate:
+	// merging onto a target that may be broken only compounds the incident.
+	if e.rigSmokeGated != nil && e.rigSmokeGated() {
+		return ProcessResult{
+			Success:   false,
+			SmokeHeld: true,
+			Error:     "rig is held until its smoke gate passes (gt witness smoke)",
+		}
+	}
+
 	// Step 1: Verify source branch exists locally (shared .repo.git with polecats)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Checking local branch %s...\n", branch)
 	exists, err := e.git.BranchExists(branch)
@@ -451,8 +512,20 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Pushed %d submodule(s)\n", len(subChanges))
 	}
 
-	// Step 4: Run quality gates (or legacy tests) if configured
-	if len(e.config.Gates) > 0 {
+	// Preemption checkpoint: nothing has been merged locally yet.
+	if result, preempted := e.checkPreempt(lane, target); preempted {
+		return result
+	}
+
+	// Step 4: Run quality gates (or legacy tests) if configured.
+	// The hotfix lane runs its own gates when configured.
+	if lane.hotfix && len(e.config.HotfixGates) > 0 {
+		_, _ = fmt.Fprintln(e.output, "[Engineer] Hotfix lane: running hotfix gates")
+		gateResult := e.runGateSet(ctx, e.config.HotfixGates)
+		if !gateResult.Success {
+			return gateResult
+		}
+	} else if len(e.config.Gates) > 0 {
 		// New gates system: run configured quality gates
 		gateResult := e.runGates(ctx)
 		if !gateResult.Success {
@@ -472,6 +545,12 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
 	}
 
+	// Last preemption checkpoint: gates can take a while, and after the
+	// squash merge below the MR is committed to landing.
+	if result, preempted := e.checkPreempt(lane, target); preempted {
+		return result
+	}
+
 	// Step 5: Perform the actual merge using squash merge
 	// Get the original commit message from the polecat branch to preserve the
 	// conventional commit format (feat:/fix:) instead of creating redundant merge commits
@@ -743,7 +822,11 @@ func (e *Engineer) runGate(ctx context.Context, name string, gate *GateConfig) G
 // Gates run in parallel if GatesParallel is true; otherwise sequentially.
 // Any single gate failure means overall failure.
 func (e *Engineer) runGates(ctx context.Context) ProcessResult {
-	gates := e.config.Gates
+	return e.runGateSet(ctx, e.config.Gates)
+}
+
+// runGateSet runs the given gates as runGates does.
+func (e *Engineer) runGateSet(ctx context.Context, gates map[string]*GateConfig) ProcessResult {
 	if len(gates) == 0 {
 		return ProcessResult{Success: true}
 	}
@@ -755,6 +838,24 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 	}
 	sort.Strings(names)
 
+	// Freeze model-dependent gates while the model API is down. The MR is
+	// held as-is rather than merged without review or failed spuriously.
+	if e.townDegraded != nil && e.townDegraded() {
+		var frozen []string
+		for _, name := range names {
+			if gates[name].RequiresModel {
+				frozen = append(frozen, name)
+			}
+		}
+		if len(frozen) > 0 {
+			return ProcessResult{
+				Success:     false,
+				GatesFrozen: true,
+				Error:       fmt.Sprintf("gates %s need the model API, which is unreachable (degraded mode)", strings.Join(frozen, ", ")),
+			}
+		}
+	}
+
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Running %d quality gate(s) (parallel=%v)\n", len(names), e.config.GatesParallel)
 
 	var results []GateResult
@@ -846,9 +947,15 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Target: %s\n", mr.Target)
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mr.Worker)
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
+	if mr.Hotfix {
+		_, _ = fmt.Fprintln(e.output, "  Lane: hotfix")
+	}
+
+	e.processing = mr.ID
+	defer func() { e.processing = "" }()
 
 	// Use the shared merge logic
-	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
+	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue, laneFor(mr))
 }
 
 // HandleMRInfoSuccess handles a successful merge from MRInfo.
@@ -934,7 +1041,7 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 
 // HandleMRInfoFailure handles a failed merge from MRInfo.
 // For conflicts, creates a resolution task and blocks the MR until resolved.
-// For slot timeouts, the MR stays in queue for automatic retry without notifying polecats.
+// For slot timeouts, frozen gates, and hotfix preemption, the MR stays in queue for automatic retry without notifying polecats.
 // This enables non-blocking delegation: the queue continues to the next MR.
 func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	// Slot timeout is transient infrastructure contention — not a build/test/conflict failure.
@@ -946,6 +1053,35 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		return
 	}
 
+	// Frozen gates are likewise not the worker's problem: the MR waits in
+	// queue until the model API is back and degraded mode ends.
+	if result.GatesFrozen {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] ⏸ Gates frozen: %s - %s\n", mr.ID, result.Error)
+		_, _ = fmt.Fprintln(e.output, "[Engineer] MR remains in queue until degraded mode ends")
+		return
+	}
+
+	// A smoke gate hold is the rig's state, not the MR's: it waits in queue
+	// until the Witness's smoke run passes.
+	if result.SmokeHeld {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] ⏸ Smoke gate hold: %s - %s\n", mr.ID, result.Error)
+		_, _ = fmt.Fprintln(e.output, "[Engineer] MR remains in queue until the smoke gate passes")
+		return
+	}
+
+	// Preemption is not a failure either: the MR goes back to the queue
+	// and is retried after the hotfix lands.
+	if result.Preempted {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] ⏸ Preempted: %s yields to hotfix %s\n", mr.ID, result.PreemptedBy)
+		if mr.ID != "" {
+			if err := e.ReleaseMR(mr.ID); err != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to release claim on %s: %v\n", mr.ID, err)
+			}
+		}
+		_, _ = fmt.Fprintln(e.output, "[Engineer] MR remains in queue and will be retried after the hotfix")
+		return
+	}
+
 	// Notify Witness of the failure so polecat can be alerted
 	// Determine failure type from result
 	failureType := "build"
@@ -1167,11 +1303,14 @@ func issueToMRInfo(issue *beads.Issue, fields *beads.MRFields) *MRInfo {
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
 		Assignee:        issue.Assignee,
+		Hotfix:          beads.HasLabel(issue, LabelHotfix),
 	}
 }
 
-// firstOpenBlocker returns the ID of the first open blocker for an issue,
-// or empty string if none are open.
+// firstOpenBlocker returns the ID of the first open blocker for an MR, or
+// empty string if none are open. Besides the MR's own blockers (conflict
+// tasks), an unfinished depends_on relation of its source issue blocks it:
+// work can't merge before the work it builds on.
 func (e *Engineer) firstOpenBlocker(issue *beads.Issue) string {
 	for _, blockerID := range issue.BlockedBy {
 		isOpen, err := e.IsBeadOpen(blockerID)
@@ -1179,13 +1318,21 @@ func (e *Engineer) firstOpenBlocker(issue *beads.Issue) string {
 			return blockerID
 		}
 	}
+	if fields := beads.ParseMRFields(issue); fields != nil && fields.SourceIssue != "" {
+		if source, err := e.beads.Show(fields.SourceIssue); err == nil {
+			if unmet := beads.UnmetDependencies(source); len(unmet) > 0 {
+				return unmet[0].ID
+			}
+		}
+	}
 	return ""
 }
 
 // ListReadyMRs returns MRs that are ready for processing:
 // - Not claimed by another worker (checked via assignee field)
-// - Not blocked by an open task (checked via firstOpenBlocker)
-// Sorted by priority (highest first).
+// - Not blocked by an open task or an unfinished dependency of its source
+//   issue (checked via firstOpenBlocker)
+// Hotfixes (gt:hotfix) come first; otherwise in bd list order.
 //
 // Uses bd list instead of bd ready because MRs are ephemeral beads and
 // bd ready filters out ephemeral issues (see gt-t5t6y). This matches the
@@ -1248,11 +1395,12 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 		mrs = append(mrs, issueToMRInfo(issue, fields))
 	}
 
+	sortHotfixesFirst(mrs)
 	return mrs, nil
 }
 
-// ListBlockedMRs returns MRs that are blocked by open tasks.
-// Useful for monitoring/reporting.
+// ListBlockedMRs returns MRs that are blocked by open tasks or by their
+// source issue's unfinished dependencies. Useful for monitoring/reporting.
 //
 // This queries beads for blocked merge-request issues.
 func (e *Engineer) ListBlockedMRs() ([]*MRInfo, error) {
@@ -1269,11 +1417,6 @@ func (e *Engineer) ListBlockedMRs() ([]*MRInfo, error) {
 	// Filter for blocked issues (those with open blockers)
 	var mrs []*MRInfo
 	for _, issue := range issues {
-		// Skip if not blocked
-		if len(issue.BlockedBy) == 0 {
-			continue
-		}
-
 		// Check if any blocker is still open
 		blockedBy := e.firstOpenBlocker(issue)
 		if blockedBy == "" {
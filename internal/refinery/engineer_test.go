@@ -279,6 +279,10 @@ func TestEngineer_LoadConfig_WithGates(t *testing.T) {
 				"build": map[string]interface{}{
 					"cmd": "go build ./...",
 				},
+				"review": map[string]interface{}{
+					"cmd":            "ai-review",
+					"requires_model": true,
+				},
 			},
 			"gates_parallel": true,
 		},
@@ -296,8 +300,8 @@ func TestEngineer_LoadConfig_WithGates(t *testing.T) {
 		t.Fatalf("unexpected error loading config: %v", err)
 	}
 
-	if len(e.config.Gates) != 3 {
-		t.Fatalf("expected 3 gates, got %d", len(e.config.Gates))
+	if len(e.config.Gates) != 4 {
+		t.Fatalf("expected 4 gates, got %d", len(e.config.Gates))
 	}
 	if e.config.Gates["test"].Cmd != "go test ./..." {
 		t.Errorf("expected test gate cmd 'go test ./...', got %q", e.config.Gates["test"].Cmd)
@@ -311,6 +315,9 @@ func TestEngineer_LoadConfig_WithGates(t *testing.T) {
 	if e.config.Gates["build"].Timeout != 0 {
 		t.Errorf("expected build gate timeout 0 (no timeout), got %v", e.config.Gates["build"].Timeout)
 	}
+	if !e.config.Gates["review"].RequiresModel || e.config.Gates["test"].RequiresModel {
+		t.Error("expected only the review gate to require the model")
+	}
 	if !e.config.GatesParallel {
 		t.Error("expected gates_parallel to be true")
 	}
@@ -444,6 +451,52 @@ func TestRunGates_Sequential_AllPass(t *testing.T) {
 	}
 }
 
+func TestRunGates_DegradedFreezesModelGates(t *testing.T) {
+	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
+	e := NewEngineer(r)
+	e.workDir = t.TempDir()
+	e.output = io.Discard
+	e.townDegraded = func() bool { return true }
+
+	markerDir := t.TempDir()
+	e.config.Gates = map[string]*GateConfig{
+		"build":  {Cmd: fmt.Sprintf("touch %s/build", markerDir)},
+		"review": {Cmd: "exit 1", RequiresModel: true},
+	}
+
+	result := e.runGates(context.Background())
+	if result.Success || !result.GatesFrozen {
+		t.Fatalf("expected frozen gates, got %+v", result)
+	}
+	if result.TestsFailed {
+		t.Error("frozen gates must not count as a test failure")
+	}
+	if _, err := os.Stat(filepath.Join(markerDir, "build")); err == nil {
+		t.Error("no gate should run while model-dependent gates are frozen")
+	}
+
+	// Without model-dependent gates, degraded mode doesn't hold the MR.
+	e.config.Gates = map[string]*GateConfig{"build": {Cmd: "true"}}
+	if result := e.runGates(context.Background()); !result.Success {
+		t.Errorf("expected success without model gates, got: %s", result.Error)
+	}
+}
+
+func TestDoMerge_SmokeGateHoldsMR(t *testing.T) {
+	r := &rig.Rig{Name: "test-rig", Path: t.TempDir()}
+	e := NewEngineer(r)
+	e.output = io.Discard
+	e.rigSmokeGated = func() bool { return true }
+
+	result := e.doMerge(context.Background(), "polecat/nux", "main", "gt-abc", mergeLane{})
+	if result.Success || !result.SmokeHeld {
+		t.Fatalf("expected smoke gate hold, got %+v", result)
+	}
+	if result.TestsFailed || result.Conflict {
+		t.Error("a smoke gate hold must not count as the MR's failure")
+	}
+}
+
 func TestRunGates_Sequential_StopsOnFirstFailure(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("gate commands run via sh -c; touch with Windows paths breaks under MSYS2 shell")
@@ -0,0 +1,409 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains forge webhook ingestion: pull/merge request events
+// from GitHub or GitLab are turned into MR beads as they happen, instead of
+// waiting for someone to run gt mq submit.
+
+package refinery
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// ErrIgnoredEvent is returned for forge events that don't affect the queue
+// (pings, comments, review events, unrelated PR actions).
+var ErrIgnoredEvent = errors.New("forge event ignored")
+
+// defaultForgePriority is the priority of an ingested MR without a
+// priority label, matching gt mq submit for an unknown source issue.
+const defaultForgePriority = 2
+
+// ForgeAction is what happened to an upstream pull request.
+type ForgeAction string
+
+const (
+	ForgeOpened  ForgeAction = "opened"  // opened, reopened or marked ready for review
+	ForgeUpdated ForgeAction = "updated" // new commits, edited, relabeled
+	ForgeClosed  ForgeAction = "closed"  // closed or merged upstream
+)
+
+// ForgeEvent is a pull/merge request webhook event, normalized across forges.
+type ForgeEvent struct {
+	Forge   string // "github" or "gitlab"
+	Action  ForgeAction
+	Repo    string // owner/name (GitHub) or group/project (GitLab)
+	Number  int    // PR number (GitHub) or MR iid (GitLab)
+	Title   string
+	URL     string
+	Branch  string // Head (source) branch
+	Target  string // Base (target) branch
+	HeadSHA string // Head commit
+	Labels  []string
+	Draft   bool // Draft PRs are not queued
+	Merged  bool // Closed by merging upstream
+}
+
+// Ref identifies the upstream PR, e.g. "github:acme/widgets#12".
+func (ev *ForgeEvent) Ref() string {
+	return fmt.Sprintf("%s:%s#%d", ev.Forge, ev.Repo, ev.Number)
+}
+
+// ParseGitHubEvent parses a GitHub webhook body; event is the
+// X-GitHub-Event header. Only pull_request events are used.
+func ParseGitHubEvent(event string, body []byte) (*ForgeEvent, error) {
+	if event != "pull_request" {
+		return nil, fmt.Errorf("%w: github %s event", ErrIgnoredEvent, event)
+	}
+	var payload struct {
+		Action      string `json:"action"`
+		PullRequest struct {
+			Number  int    `json:"number"`
+			Title   string `json:"title"`
+			HTMLURL string `json:"html_url"`
+			Draft   bool   `json:"draft"`
+			Merged  bool   `json:"merged"`
+			Head    struct {
+				Ref string `json:"ref"`
+				SHA string `json:"sha"`
+			} `json:"head"`
+			Base struct {
+				Ref string `json:"ref"`
+			} `json:"base"`
+			Labels []struct {
+				Name string `json:"name"`
+			} `json:"labels"`
+		} `json:"pull_request"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("parsing github payload: %w", err)
+	}
+
+	var action ForgeAction
+	switch payload.Action {
+	case "opened", "reopened", "ready_for_review":
+		action = ForgeOpened
+	case "synchronize", "edited", "labeled", "unlabeled":
+		action = ForgeUpdated
+	case "closed":
+		action = ForgeClosed
+	default:
+		return nil, fmt.Errorf("%w: github pull_request %s", ErrIgnoredEvent, payload.Action)
+	}
+
+	pr := payload.PullRequest
+	ev := &ForgeEvent{
+		Forge:   "github",
+		Action:  action,
+		Repo:    payload.Repository.FullName,
+		Number:  pr.Number,
+		Title:   pr.Title,
+		URL:     pr.HTMLURL,
+		Branch:  pr.Head.Ref,
+		Target:  pr.Base.Ref,
+		HeadSHA: pr.Head.SHA,
+		Draft:   pr.Draft,
+		Merged:  pr.Merged,
+	}
+	for _, l := range pr.Labels {
+		ev.Labels = append(ev.Labels, l.Name)
+	}
+	return ev, ev.validate()
+}
+
+// ParseGitLabEvent parses a GitLab webhook body. Only merge_request
+// events are used.
+func ParseGitLabEvent(body []byte) (*ForgeEvent, error) {
+	var payload struct {
+		ObjectKind string `json:"object_kind"`
+		Project    struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+		} `json:"project"`
+		Attrs struct {
+			IID            int    `json:"iid"`
+			Title          string `json:"title"`
+			URL            string `json:"url"`
+			Action         string `json:"action"`
+			SourceBranch   string `json:"source_branch"`
+			TargetBranch   string `json:"target_branch"`
+			Draft          bool   `json:"draft"`
+			WorkInProgress bool   `json:"work_in_progress"`
+			LastCommit     struct {
+				ID string `json:"id"`
+			} `json:"last_commit"`
+		} `json:"object_attributes"`
+		Labels []struct {
+			Title string `json:"title"`
+		} `json:"labels"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("parsing gitlab payload: %w", err)
+	}
+	if payload.ObjectKind != "merge_request" {
+		return nil, fmt.Errorf("%w: gitlab %s event", ErrIgnoredEvent, payload.ObjectKind)
+	}
+
+	attrs := payload.Attrs
+	var action ForgeAction
+	merged := false
+	switch attrs.Action {
+	case "open", "reopen":
+		action = ForgeOpened
+	case "update":
+		action = ForgeUpdated
+	case "close":
+		action = ForgeClosed
+	case "merge":
+		action, merged = ForgeClosed, true
+	default:
+		return nil, fmt.Errorf("%w: gitlab merge_request %s", ErrIgnoredEvent, attrs.Action)
+	}
+
+	ev := &ForgeEvent{
+		Forge:   "gitlab",
+		Action:  action,
+		Repo:    payload.Project.PathWithNamespace,
+		Number:  attrs.IID,
+		Title:   attrs.Title,
+		URL:     attrs.URL,
+		Branch:  attrs.SourceBranch,
+		Target:  attrs.TargetBranch,
+		HeadSHA: attrs.LastCommit.ID,
+		Draft:   attrs.Draft || attrs.WorkInProgress,
+		Merged:  merged,
+	}
+	for _, l := range payload.Labels {
+		ev.Labels = append(ev.Labels, l.Title)
+	}
+	return ev, ev.validate()
+}
+
+func (ev *ForgeEvent) validate() error {
+	if ev.Branch == "" || ev.Target == "" || ev.Number == 0 {
+		return fmt.Errorf("%s event is missing branch, target or number", ev.Forge)
+	}
+	return nil
+}
+
+// VerifyGitHubSignature checks an X-Hub-Signature-256 header against the
+// HMAC-SHA256 of body under secret.
+func VerifyGitHubSignature(secret string, body []byte, header string) bool {
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// VerifyGitLabToken checks an X-Gitlab-Token header against secret.
+func VerifyGitLabToken(secret, header string) bool {
+	return header != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(header)) == 1
+}
+
+// forgePriorityLabel matches priority labels: P1, p1, priority:P1,
+// priority/p1, priority-1.
+var forgePriorityLabel = regexp.MustCompile(`(?i)^(?:priority[:/ -]*p?|p)([0-4])$`)
+
+// forgePriority returns the priority named by the first priority label.
+func forgePriority(labels []string) (int, bool) {
+	for _, l := range labels {
+		if m := forgePriorityLabel.FindStringSubmatch(strings.TrimSpace(l)); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// forgeHotfix reports whether labels put the PR in the hotfix lane.
+func forgeHotfix(labels []string) bool {
+	for _, l := range labels {
+		if l == LabelHotfix || strings.EqualFold(l, "hotfix") {
+			return true
+		}
+	}
+	return false
+}
+
+// ForgeIngestResult describes what ingesting a forge event did.
+type ForgeIngestResult struct {
+	MRID    string   // MR bead, empty when ignored
+	Outcome string   // created, updated, unchanged, closed or ignored
+	Changes []string // Human-readable changes, for logs and the refinery nudge
+}
+
+// forgeMRStore is the beads surface forge ingestion needs.
+type forgeMRStore interface {
+	FindMRForBranch(branch string) (*beads.Issue, error)
+	Create(opts beads.CreateOptions) (*beads.Issue, error)
+	Update(id string, opts beads.UpdateOptions) error
+	CloseWithReason(reason string, ids ...string) error
+}
+
+// IngestForgeEvent creates or refreshes the MR bead for ev in r's queue.
+//
+// A new PR becomes an MR bead; an updated one has its target, priority and
+// hotfix label synced from upstream, and a new head commit resets its
+// conflict retry count. MR scores are computed from the bead on every
+// queue read, so these updates re-rank the MR on the refinery's next pass.
+// A PR closed upstream closes its MR bead.
+func IngestForgeEvent(r *rig.Rig, ev *ForgeEvent) (*ForgeIngestResult, error) {
+	return ingestForgeEvent(beads.New(r.BeadsPath()), r.Name, ev)
+}
+
+func ingestForgeEvent(store forgeMRStore, rigName string, ev *ForgeEvent) (*ForgeIngestResult, error) {
+	existing, err := store.FindMRForBranch(ev.Branch)
+	if err != nil {
+		return nil, fmt.Errorf("finding MR for %s: %w", ev.Branch, err)
+	}
+
+	if existing == nil {
+		if ev.Action == ForgeClosed || ev.Draft {
+			return &ForgeIngestResult{Outcome: "ignored"}, nil
+		}
+		return createForgeMR(store, rigName, ev)
+	}
+
+	result := &ForgeIngestResult{MRID: existing.ID}
+	if ev.Action == ForgeClosed {
+		reason := "closed upstream"
+		if ev.Merged {
+			reason = "merged upstream"
+		}
+		if err := store.CloseWithReason(reason+": "+ev.Ref(), existing.ID); err != nil {
+			return nil, fmt.Errorf("closing %s: %w", existing.ID, err)
+		}
+		result.Outcome = "closed"
+		result.Changes = append(result.Changes, reason)
+		return result, nil
+	}
+
+	opts, changes := forgeMRUpdate(existing, rigName, ev)
+	if len(changes) == 0 {
+		result.Outcome = "unchanged"
+		return result, nil
+	}
+	if err := store.Update(existing.ID, opts); err != nil {
+		return nil, fmt.Errorf("updating %s: %w", existing.ID, err)
+	}
+	result.Outcome = "updated"
+	result.Changes = changes
+	return result, nil
+}
+
+// createForgeMR creates the MR bead for a newly opened PR.
+func createForgeMR(store forgeMRStore, rigName string, ev *ForgeEvent) (*ForgeIngestResult, error) {
+	priority, ok := forgePriority(ev.Labels)
+	if !ok {
+		priority = defaultForgePriority
+	}
+	fields := &beads.MRFields{
+		Branch:  ev.Branch,
+		Target:  ev.Target,
+		Rig:     rigName,
+		ForgePR: ev.Ref(),
+		HeadSHA: ev.HeadSHA,
+	}
+	description := beads.FormatMRFields(fields)
+	if ev.URL != "" {
+		description += "\n\n" + ev.URL
+	}
+	issue, err := store.Create(beads.CreateOptions{
+		Title:       fmt.Sprintf("Merge: %s #%d %s", ev.Repo, ev.Number, ev.Title),
+		Type:        "merge-request",
+		Priority:    priority,
+		Description: description,
+		Ephemeral:   true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating merge request bead: %w", err)
+	}
+
+	result := &ForgeIngestResult{MRID: issue.ID, Outcome: "created",
+		Changes: []string{fmt.Sprintf("queued %s at P%d", ev.Ref(), priority)}}
+	if forgeHotfix(ev.Labels) {
+		if err := store.Update(issue.ID, beads.UpdateOptions{AddLabels: []string{LabelHotfix}}); err != nil {
+			return nil, fmt.Errorf("marking %s as hotfix: %w", issue.ID, err)
+		}
+		result.Changes = append(result.Changes, "hotfix lane")
+	}
+	return result, nil
+}
+
+// forgeMRUpdate compares an existing MR bead with ev and returns the
+// update that brings it in line, with a description of each change.
+func forgeMRUpdate(existing *beads.Issue, rigName string, ev *ForgeEvent) (beads.UpdateOptions, []string) {
+	var opts beads.UpdateOptions
+	var changes []string
+
+	if p, ok := forgePriority(ev.Labels); ok && p != existing.Priority {
+		opts.Priority = &p
+		changes = append(changes, fmt.Sprintf("priority P%d → P%d", existing.Priority, p))
+	}
+
+	// Upstream can only add the hotfix lane: an MR marked with gt mq
+	// submit --hotfix stays there whatever its PR's labels say.
+	if forgeHotfix(ev.Labels) && !beads.HasLabel(existing, LabelHotfix) {
+		opts.AddLabels = []string{LabelHotfix}
+		changes = append(changes, "added to hotfix lane")
+	}
+
+	fields := beads.ParseMRFields(existing)
+	if fields == nil {
+		fields = &beads.MRFields{Branch: ev.Branch, Rig: rigName}
+	}
+	before := *fields
+	if fields.Target != ev.Target {
+		changes = append(changes, fmt.Sprintf("target %s → %s", fields.Target, ev.Target))
+		fields.Target = ev.Target
+	}
+	if fields.ForgePR == "" {
+		fields.ForgePR = ev.Ref()
+	}
+	if ev.HeadSHA != "" && fields.HeadSHA != ev.HeadSHA {
+		// New commits upstream: earlier conflicts say nothing about them.
+		if fields.HeadSHA != "" {
+			changes = append(changes, fmt.Sprintf("new head %s", shortSHA(ev.HeadSHA)))
+			if fields.RetryCount > 0 {
+				changes = append(changes, fmt.Sprintf("retry count %d → 0", fields.RetryCount))
+			}
+			fields.RetryCount = 0
+			fields.LastConflictSHA = ""
+		}
+		fields.HeadSHA = ev.HeadSHA
+	}
+	if *fields != before {
+		desc := beads.SetMRFields(existing, fields)
+		opts.Description = &desc
+		if len(changes) == 0 {
+			changes = append(changes, "recorded "+ev.Ref())
+		}
+	}
+	return opts, changes
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
@@ -0,0 +1,187 @@
+package refinery
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+const githubPRPayload = `{
+  "action": "synchronize",
+  "pull_request": {
+    "number": 12, "title": "Fix flaky login", "html_url": "https://github.com/acme/widgets/pull/12",
+    "head": {"ref": "fix/login", "sha": "bbbbbbbbbbbb"},
+    "base": {"ref": "main"},
+    "labels": [{"name": "bug"}, {"name": "priority:P1"}]
+  },
+  "repository": {"full_name": "acme/widgets"}
+}`
+
+func TestParseGitHubEvent(t *testing.T) {
+	ev, err := ParseGitHubEvent("pull_request", []byte(githubPRPayload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Action != ForgeUpdated || ev.Branch != "fix/login" || ev.Target != "main" || ev.HeadSHA != "bbbbbbbbbbbb" {
+		t.Errorf("parsed = %+v", ev)
+	}
+	if ev.Ref() != "github:acme/widgets#12" {
+		t.Errorf("Ref() = %q", ev.Ref())
+	}
+
+	if _, err := ParseGitHubEvent("ping", []byte(`{}`)); !errors.Is(err, ErrIgnoredEvent) {
+		t.Errorf("ping: got %v, want ErrIgnoredEvent", err)
+	}
+	if _, err := ParseGitHubEvent("pull_request", []byte(`{"action":"assigned"}`)); !errors.Is(err, ErrIgnoredEvent) {
+		t.Errorf("assigned: got %v, want ErrIgnoredEvent", err)
+	}
+}
+
+func TestParseGitLabEvent(t *testing.T) {
+	body := `{"object_kind":"merge_request","project":{"path_with_namespace":"acme/widgets"},
+	  "object_attributes":{"iid":7,"title":"Hotfix","action":"merge","source_branch":"hotfix/db","target_branch":"main","last_commit":{"id":"cafe"}},
+	  "labels":[{"title":"hotfix"}]}`
+	ev, err := ParseGitLabEvent([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Action != ForgeClosed || !ev.Merged || ev.Ref() != "gitlab:acme/widgets#7" || !forgeHotfix(ev.Labels) {
+		t.Errorf("parsed = %+v", ev)
+	}
+}
+
+func TestVerifyGitHubSignature(t *testing.T) {
+	body := []byte(githubPRPayload)
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	sig := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !VerifyGitHubSignature("s3cret", body, sig) {
+		t.Error("valid signature rejected")
+	}
+	if VerifyGitHubSignature("other", body, sig) || VerifyGitHubSignature("s3cret", body, "") {
+		t.Error("invalid signature accepted")
+	}
+	if !VerifyGitLabToken("s3cret", "s3cret") || VerifyGitLabToken("s3cret", "") {
+		t.Error("VerifyGitLabToken wrong")
+	}
+}
+
+func TestForgePriority(t *testing.T) {
+	tests := []struct {
+		labels []string
+		want   int
+		ok     bool
+	}{
+		{[]string{"P0"}, 0, true},
+		{[]string{"bug", "priority/p3"}, 3, true},
+		{[]string{"priority-1"}, 1, true},
+		{[]string{"1", "p9", "bug"}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := forgePriority(tt.labels)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("forgePriority(%v) = %d, %v; want %d, %v", tt.labels, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// fakeForgeStore is an in-memory forgeMRStore.
+type fakeForgeStore struct {
+	issues  map[string]*beads.Issue
+	updates []beads.UpdateOptions
+	closed  string
+}
+
+func (s *fakeForgeStore) FindMRForBranch(branch string) (*beads.Issue, error) {
+	for _, issue := range s.issues {
+		if strings.HasPrefix(issue.Description, "branch: "+branch+"\n") {
+			return issue, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *fakeForgeStore) Create(opts beads.CreateOptions) (*beads.Issue, error) {
+	issue := &beads.Issue{ID: "gt-mr1", Title: opts.Title, Priority: opts.Priority, Description: opts.Description}
+	s.issues[issue.ID] = issue
+	return issue, nil
+}
+
+func (s *fakeForgeStore) Update(id string, opts beads.UpdateOptions) error {
+	s.updates = append(s.updates, opts)
+	issue := s.issues[id]
+	if opts.Priority != nil {
+		issue.Priority = *opts.Priority
+	}
+	if opts.Description != nil {
+		issue.Description = *opts.Description
+	}
+	issue.Labels = append(issue.Labels, opts.AddLabels...)
+	return nil
+}
+
+func (s *fakeForgeStore) CloseWithReason(reason string, ids ...string) error {
+	s.closed = reason
+	return nil
+}
+
+func TestIngestForgeEvent(t *testing.T) {
+	store := &fakeForgeStore{issues: map[string]*beads.Issue{}}
+	ev := &ForgeEvent{Forge: "github", Action: ForgeOpened, Repo: "acme/widgets", Number: 12,
+		Branch: "fix/login", Target: "main", HeadSHA: "aaaa"}
+
+	res, err := ingestForgeEvent(store, "widgets", ev)
+	if err != nil || res.Outcome != "created" {
+		t.Fatalf("open: %+v, %v", res, err)
+	}
+	issue := store.issues["gt-mr1"]
+	if issue.Priority != defaultForgePriority {
+		t.Errorf("priority = %d, want default", issue.Priority)
+	}
+
+	// Simulate a conflict retry, then a relabel plus a new push upstream.
+	fields := beads.ParseMRFields(issue)
+	fields.RetryCount = 3
+	issue.Description = beads.SetMRFields(issue, fields)
+	ev.Action, ev.HeadSHA, ev.Labels = ForgeUpdated, "bbbb", []string{"P0"}
+
+	res, err = ingestForgeEvent(store, "widgets", ev)
+	if err != nil || res.Outcome != "updated" {
+		t.Fatalf("update: %+v, %v", res, err)
+	}
+	got := beads.ParseMRFields(issue)
+	if issue.Priority != 0 || got.RetryCount != 0 || got.HeadSHA != "bbbb" || got.ForgePR != "github:acme/widgets#12" {
+		t.Errorf("after update: priority %d, fields %+v", issue.Priority, got)
+	}
+
+	if res, _ := ingestForgeEvent(store, "widgets", ev); res.Outcome != "unchanged" {
+		t.Errorf("replayed event: outcome %q, want unchanged", res.Outcome)
+	}
+
+	ev.Action, ev.Merged = ForgeClosed, true
+	if res, _ := ingestForgeEvent(store, "widgets", ev); res.Outcome != "closed" || !strings.HasPrefix(store.closed, "merged upstream") {
+		t.Errorf("close: outcome %q, reason %q", res.Outcome, store.closed)
+	}
+}
+
+func TestIngestForgeEvent_IgnoresDraftsAndUnknownCloses(t *testing.T) {
+	store := &fakeForgeStore{issues: map[string]*beads.Issue{}}
+	for _, ev := range []*ForgeEvent{
+		{Forge: "github", Action: ForgeOpened, Number: 1, Branch: "wip", Target: "main", Draft: true},
+		{Forge: "github", Action: ForgeClosed, Number: 2, Branch: "gone", Target: "main"},
+	} {
+		res, err := ingestForgeEvent(store, "widgets", ev)
+		if err != nil || res.Outcome != "ignored" {
+			t.Errorf("%s: %+v, %v", ev.Branch, res, err)
+		}
+	}
+	if len(store.issues) != 0 {
+		t.Errorf("no MR should be created, got %d", len(store.issues))
+	}
+}
@@ -0,0 +1,94 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains the hotfix lane: MRs for production-down fixes that
+// jump the queue and, when asked to, preempt the merge in progress.
+
+package refinery
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// Hotfix labels on MR beads.
+const (
+	// LabelHotfix marks an MR for the hotfix lane: it sorts ahead of all
+	// other work and runs HotfixGates (when configured) instead of Gates.
+	LabelHotfix = "gt:hotfix"
+
+	// LabelHotfixPreempt additionally asks the refinery to abandon an
+	// in-progress merge of non-critical work so the hotfix lands sooner.
+	LabelHotfixPreempt = "gt:hotfix-preempt"
+)
+
+// mergeLane selects the checks an MR goes through in doMerge.
+type mergeLane struct {
+	hotfix      bool // run HotfixGates in place of the normal gates
+	preemptible bool // may yield to a waiting preempting hotfix before merging
+}
+
+// laneFor picks the lane for an MR. Hotfixes and P0 work are never
+// preempted; everything else may be, if a hotfix asks for it.
+func laneFor(mr *MRInfo) mergeLane {
+	return mergeLane{
+		hotfix:      mr.Hotfix,
+		preemptible: !mr.Hotfix && mr.Priority > 0,
+	}
+}
+
+// sortHotfixesFirst moves hotfix MRs to the front, keeping the existing
+// order within each group.
+func sortHotfixesFirst(mrs []*MRInfo) {
+	sort.SliceStable(mrs, func(i, j int) bool {
+		return mrs[i].Hotfix && !mrs[j].Hotfix
+	})
+}
+
+// findPreemptingHotfix returns the ID of an open, unclaimed MR other than
+// exclude that carries LabelHotfixPreempt, or "" if there is none.
+func (e *Engineer) findPreemptingHotfix(exclude string) string {
+	issues, err := e.beads.List(beads.ListOptions{
+		Status:   "open",
+		Label:    LabelHotfixPreempt,
+		Priority: -1,
+	})
+	if err != nil {
+		// Fail closed: an unreadable queue must not abort merges.
+		return ""
+	}
+	for _, issue := range issues {
+		if issue.Status != "open" || issue.ID == exclude || issue.Assignee != "" {
+			continue
+		}
+		if !beads.HasLabel(issue, "gt:merge-request") || e.firstOpenBlocker(issue) != "" {
+			continue
+		}
+		return issue.ID
+	}
+	return ""
+}
+
+// checkPreempt is a doMerge checkpoint. Before anything is pushed, a
+// preemptible merge yields to a waiting hotfix: the target branch is reset
+// to origin so the refinery worktree is clean for the hotfix, and the MR is
+// returned to the queue untouched.
+func (e *Engineer) checkPreempt(lane mergeLane, target string) (ProcessResult, bool) {
+	if !lane.preemptible || e.hotfixWaiting == nil {
+		return ProcessResult{}, false
+	}
+	hotfixID := e.hotfixWaiting()
+	if hotfixID == "" {
+		return ProcessResult{}, false
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Hotfix %s waiting: preempting this merge before push\n", hotfixID)
+	if err := e.git.ResetHard("origin/" + target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after preemption: %v\n", target, err)
+	}
+	return ProcessResult{
+		Success:     false,
+		Preempted:   true,
+		PreemptedBy: hotfixID,
+		Error:       fmt.Sprintf("preempted by hotfix %s", hotfixID),
+	}, true
+}
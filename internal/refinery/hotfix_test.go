@@ -0,0 +1,161 @@
+package refinery
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestLaneFor(t *testing.T) {
+	tests := []struct {
+		name        string
+		mr          MRInfo
+		hotfix      bool
+		preemptible bool
+	}{
+		{"normal work", MRInfo{Priority: 2}, false, true},
+		{"P0 is never preempted", MRInfo{Priority: 0}, false, false},
+		{"hotfix", MRInfo{Priority: 2, Hotfix: true}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lane := laneFor(&tt.mr)
+			if lane.hotfix != tt.hotfix || lane.preemptible != tt.preemptible {
+				t.Errorf("laneFor() = %+v, want hotfix=%v preemptible=%v", lane, tt.hotfix, tt.preemptible)
+			}
+		})
+	}
+}
+
+func TestSortHotfixesFirst(t *testing.T) {
+	mrs := []*MRInfo{
+		{ID: "a"},
+		{ID: "hf1", Hotfix: true},
+		{ID: "b"},
+		{ID: "hf2", Hotfix: true},
+	}
+	sortHotfixesFirst(mrs)
+
+	want := []string{"hf1", "hf2", "a", "b"}
+	for i, id := range want {
+		if mrs[i].ID != id {
+			t.Fatalf("position %d = %s, want %s (order must be stable)", i, mrs[i].ID, id)
+		}
+	}
+}
+
+func TestCheckPreempt(t *testing.T) {
+	newEngineer := func(waiting string) *Engineer {
+		return &Engineer{
+			output:        io.Discard,
+			git:           git.NewGit(t.TempDir()),
+			hotfixWaiting: func() string { return waiting },
+		}
+	}
+
+	t.Run("no hotfix waiting", func(t *testing.T) {
+		e := newEngineer("")
+		if _, preempted := e.checkPreempt(mergeLane{preemptible: true}, "main"); preempted {
+			t.Error("expected no preemption without a waiting hotfix")
+		}
+	})
+
+	t.Run("lane not preemptible", func(t *testing.T) {
+		e := newEngineer("gt-mr-hot")
+		if _, preempted := e.checkPreempt(mergeLane{hotfix: true}, "main"); preempted {
+			t.Error("expected hotfix lane to be immune to preemption")
+		}
+	})
+
+	t.Run("preempts for waiting hotfix", func(t *testing.T) {
+		e := newEngineer("gt-mr-hot")
+		result, preempted := e.checkPreempt(mergeLane{preemptible: true}, "main")
+		if !preempted {
+			t.Fatal("expected preemption")
+		}
+		if result.Success || !result.Preempted || result.PreemptedBy != "gt-mr-hot" {
+			t.Errorf("unexpected result: %+v", result)
+		}
+		if result.Conflict || result.TestsFailed {
+			t.Errorf("preemption must not look like a merge failure: %+v", result)
+		}
+	})
+}
+
+func TestScoreMR_HotfixBonus(t *testing.T) {
+	now := time.Now()
+	config := DefaultScoreConfig()
+
+	// An old P0 convoy MR that has never been retried is the strongest
+	// normal contender; a fresh P4 hotfix still has to beat it.
+	convoyCreated := now.Add(-72 * time.Hour)
+	normal := ScoreMR(ScoreInput{
+		Priority:        0,
+		MRCreatedAt:     now.Add(-48 * time.Hour),
+		ConvoyCreatedAt: &convoyCreated,
+		Now:             now,
+	}, config)
+	hotfix := ScoreMR(ScoreInput{
+		Priority:    4,
+		MRCreatedAt: now,
+		Hotfix:      true,
+		Now:         now,
+	}, config)
+
+	if hotfix <= normal {
+		t.Errorf("hotfix score %.1f should exceed P0 convoy score %.1f", hotfix, normal)
+	}
+}
+
+func TestEngineer_LoadConfig_WithHotfixGates(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := map[string]interface{}{
+		"merge_queue": map[string]interface{}{
+			"gates": map[string]interface{}{
+				"test": map[string]interface{}{"cmd": "go test ./..."},
+			},
+			"hotfix_gates": map[string]interface{}{
+				"smoke": map[string]interface{}{"cmd": "make smoke", "timeout": "1m"},
+			},
+		},
+	}
+	data, _ := json.MarshalIndent(config, "", "  ")
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("unexpected error loading config: %v", err)
+	}
+
+	smoke := e.config.HotfixGates["smoke"]
+	if len(e.config.HotfixGates) != 1 || smoke == nil {
+		t.Fatalf("expected one hotfix gate, got %v", e.config.HotfixGates)
+	}
+	if smoke.Cmd != "make smoke" || smoke.Timeout != time.Minute {
+		t.Errorf("unexpected hotfix gate: %+v", smoke)
+	}
+	if len(e.config.Gates) != 1 {
+		t.Errorf("normal gates should be unaffected, got %v", e.config.Gates)
+	}
+}
+
+func TestEngineer_LoadConfig_HotfixGateInvalidTimeout(t *testing.T) {
+	tmpDir := t.TempDir()
+	data := []byte(`{"merge_queue": {"hotfix_gates": {"smoke": {"cmd": "make smoke", "timeout": "soon"}}}}`)
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	if err := e.LoadConfig(); err == nil {
+		t.Error("expected error for invalid hotfix gate timeout")
+	}
+}
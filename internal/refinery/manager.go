@@ -246,6 +246,7 @@ func (m *Manager) Queue() ([]QueueItem, error) {
 
 	// Score and sort issues by priority score (highest first)
 	now := time.Now()
+	scoreConfig := RigScoreConfig(m.rig.Path)
 	type scoredIssue struct {
 		issue *beads.Issue
 		score float64
@@ -256,7 +257,7 @@ func (m *Manager) Queue() ([]QueueItem, error) {
 		if issue == nil || issue.Status != "open" {
 			continue
 		}
-		score := m.calculateIssueScore(issue, now)
+		score := m.calculateIssueScore(issue, now, scoreConfig)
 		scored = append(scored, scoredIssue{issue: issue, score: score})
 	}
 
@@ -284,7 +285,7 @@ func (m *Manager) Queue() ([]QueueItem, error) {
 
 // calculateIssueScore computes the priority score for an MR issue.
 // Higher scores mean higher priority (process first).
-func (m *Manager) calculateIssueScore(issue *beads.Issue, now time.Time) float64 {
+func (m *Manager) calculateIssueScore(issue *beads.Issue, now time.Time, scoreConfig ScoreConfig) float64 {
 	fields := beads.ParseMRFields(issue)
 
 	// Parse MR creation time
@@ -297,6 +298,8 @@ func (m *Manager) calculateIssueScore(issue *beads.Issue, now time.Time) float64
 	input := ScoreInput{
 		Priority:    issue.Priority,
 		MRCreatedAt: mrCreatedAt,
+		Hotfix:      beads.HasLabel(issue, LabelHotfix),
+		Labels:      issue.Labels,
 		Now:         now,
 	}
 
@@ -312,7 +315,7 @@ func (m *Manager) calculateIssueScore(issue *beads.Issue, now time.Time) float64
 		}
 	}
 
-	return ScoreMRWithDefaults(input)
+	return ScoreMR(input, scoreConfig)
 }
 
 // issueToMR converts a beads issue to a MergeRequest.
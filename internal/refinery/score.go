@@ -5,6 +5,9 @@ package refinery
 
 import (
 	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 // ScoreConfig contains tunable weights for MR priority scoring.
@@ -37,6 +40,16 @@ type ScoreConfig struct {
 	// MaxRetryPenalty caps the total retry penalty to prevent permanent deprioritization.
 	// Default: 300.0 (after 6 retries, penalty is capped)
 	MaxRetryPenalty float64
+
+	// HotfixBonus is added to hotfix-lane MRs (gt:hotfix). It is large
+	// enough to put every hotfix ahead of all other work.
+	// Default: 100000.0
+	HotfixBonus float64
+
+	// LabelWeights adds points for each pattern (see beads.MatchLabel)
+	// any of the MR's labels matches. Set from the rig's
+	// merge_queue.label_weights; none by default.
+	LabelWeights map[string]float64
 }
 
 // DefaultScoreConfig returns sensible defaults for MR scoring.
@@ -48,6 +61,7 @@ func DefaultScoreConfig() ScoreConfig {
 		RetryPenalty:    50.0,
 		MRAgeWeight:     1.0,
 		MaxRetryPenalty: 300.0,
+		HotfixBonus:     100000.0,
 	}
 }
 
@@ -69,6 +83,12 @@ type ScoreInput struct {
 	// 0 = first attempt.
 	RetryCount int
 
+	// Hotfix is true for MRs in the hotfix lane (labeled gt:hotfix).
+	Hotfix bool
+
+	// Labels are the MR's labels, weighed by ScoreConfig.LabelWeights.
+	Labels []string
+
 	// Now is the current time (for deterministic testing).
 	// If zero, time.Now() is used.
 	Now time.Time
@@ -84,6 +104,8 @@ type ScoreInput struct {
 //	      + PriorityWeight * (4 - priority)          // P0=+400, P4=+0
 //	      - min(RetryPenalty * retryCount, MaxRetryPenalty)  // Prevent thrashing
 //	      + MRAgeWeight * hoursOld(MR)               // FIFO tiebreaker
+//	      + HotfixBonus (if hotfix)                  // Hotfix lane jumps the queue
+//	      + LabelWeights[pattern] (per matching pattern)  // Rig-tuned label boosts
 func ScoreMR(input ScoreInput, config ScoreConfig) float64 {
 	now := input.Now
 	if now.IsZero() {
@@ -125,9 +147,34 @@ func ScoreMR(input ScoreInput, config ScoreConfig) float64 {
 		score += config.MRAgeWeight * mrHours
 	}
 
+	// Hotfix lane: production-down fixes don't wait behind convoy work
+	if input.Hotfix {
+		score += config.HotfixBonus
+	}
+
+	// Label weights: each pattern counts once, however many labels match it
+	for pattern, weight := range config.LabelWeights {
+		for _, label := range input.Labels {
+			if beads.MatchLabel(pattern, label) {
+				score += weight
+				break
+			}
+		}
+	}
+
 	return score
 }
 
+// RigScoreConfig returns the default config with the rig's
+// merge_queue.label_weights, from the rig settings at rigPath.
+func RigScoreConfig(rigPath string) ScoreConfig {
+	cfg := DefaultScoreConfig()
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath)); err == nil && settings.MergeQueue != nil {
+		cfg.LabelWeights = settings.MergeQueue.LabelWeights
+	}
+	return cfg
+}
+
 // ScoreMRWithDefaults is a convenience wrapper using default config.
 func ScoreMRWithDefaults(input ScoreInput) float64 {
 	return ScoreMR(input, DefaultScoreConfig())
@@ -146,6 +193,7 @@ func (mr *MRInfo) ScoreAt(now time.Time) float64 {
 		MRCreatedAt:     mr.CreatedAt,
 		ConvoyCreatedAt: mr.ConvoyCreatedAt,
 		RetryCount:      mr.RetryCount,
+		Hotfix:          mr.Hotfix,
 		Now:             now,
 	}
 	return ScoreMRWithDefaults(input)
@@ -0,0 +1,50 @@
+package refinery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestScoreMR_LabelWeights(t *testing.T) {
+	now := time.Now()
+	config := DefaultScoreConfig()
+	config.LabelWeights = map[string]float64{"area:*": 50, "flaky": -200}
+
+	base := ScoreMR(ScoreInput{Priority: 2, MRCreatedAt: now, Now: now}, config)
+	score := func(labels ...string) float64 {
+		return ScoreMR(ScoreInput{Priority: 2, MRCreatedAt: now, Labels: labels, Now: now}, config) - base
+	}
+
+	if got := score("area:refinery"); got != 50 {
+		t.Errorf("area:refinery adds %.1f, want 50", got)
+	}
+	if got := score("area:refinery", "area:cli"); got != 50 {
+		t.Errorf("a pattern should count once however many labels match it, got %.1f", got)
+	}
+	if got := score("area:cli", "flaky"); got != -150 {
+		t.Errorf("area:cli and flaky add %.1f, want -150", got)
+	}
+	if got := score("needs-human"); got != 0 {
+		t.Errorf("an unweighted label adds %.1f, want 0", got)
+	}
+}
+
+func TestRigScoreConfig(t *testing.T) {
+	rigPath := t.TempDir()
+	if cfg := RigScoreConfig(rigPath); cfg.LabelWeights != nil || cfg.BaseScore != DefaultScoreConfig().BaseScore {
+		t.Errorf("a rig without settings should get the defaults, got %+v", cfg)
+	}
+
+	settings := `{"type":"rig-settings","version":1,"merge_queue":{"label_weights":{"area:refinery":25}}}`
+	if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rigPath, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := RigScoreConfig(rigPath).LabelWeights["area:refinery"]; got != 25 {
+		t.Errorf("label weight = %.1f, want 25", got)
+	}
+}
@@ -0,0 +1,250 @@
+package refinery
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// DefaultSnapshotRetainDays is how long queue snapshots are kept when no
+// retention is configured.
+const DefaultSnapshotRetainDays = 30
+
+// QueueRow is one MR of a queue, flattened for CSV and BI tools.
+type QueueRow struct {
+	CapturedAt time.Time `json:"captured_at"`
+	Rig        string    `json:"rig"`
+	Position   int       `json:"position"`
+	ID         string    `json:"id"`
+	Branch     string    `json:"branch"`
+	Target     string    `json:"target"`
+	Worker     string    `json:"worker"`
+	Issue      string    `json:"issue,omitempty"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+	AgeSeconds int64     `json:"age_seconds"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// QueueSnapshot is a rig's queue at one moment. Depth is recorded even when
+// the queue is empty so depth-over-time charts have no gaps.
+type QueueSnapshot struct {
+	CapturedAt time.Time  `json:"captured_at"`
+	Rig        string     `json:"rig"`
+	Depth      int        `json:"depth"`
+	OldestAge  int64      `json:"oldest_age_seconds"`
+	Items      []QueueRow `json:"items,omitempty"`
+}
+
+// queueCSVHeader is the column order of WriteQueueCSV.
+var queueCSVHeader = []string{
+	"captured_at", "rig", "position", "id", "branch", "target", "worker",
+	"issue", "status", "created_at", "age_seconds", "error",
+}
+
+// queueDepthCSVHeader is the column order of WriteQueueDepthCSV.
+var queueDepthCSVHeader = []string{"captured_at", "rig", "depth", "oldest_age_seconds"}
+
+// NewQueueSnapshot flattens a rig's queue as seen at now.
+func NewQueueSnapshot(rig string, items []QueueItem, now time.Time) QueueSnapshot {
+	snap := QueueSnapshot{CapturedAt: now.UTC(), Rig: rig, Depth: len(items)}
+	for _, item := range items {
+		mr := item.MR
+		row := QueueRow{
+			CapturedAt: snap.CapturedAt,
+			Rig:        rig,
+			Position:   item.Position,
+			ID:         mr.ID,
+			Branch:     mr.Branch,
+			Target:     mr.TargetBranch,
+			Worker:     mr.Worker,
+			Issue:      mr.IssueID,
+			Status:     string(mr.Status),
+			Error:      mr.Error,
+		}
+		if !mr.CreatedAt.IsZero() {
+			row.CreatedAt = mr.CreatedAt.UTC()
+			row.AgeSeconds = int64(now.Sub(mr.CreatedAt).Seconds())
+		}
+		snap.OldestAge = max(snap.OldestAge, row.AgeSeconds)
+		snap.Items = append(snap.Items, row)
+	}
+	return snap
+}
+
+// WriteQueueCSV writes the MRs of snaps as CSV rows, one per MR.
+func WriteQueueCSV(w io.Writer, snaps []QueueSnapshot) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(queueCSVHeader); err != nil {
+		return err
+	}
+	for _, snap := range snaps {
+		for _, r := range snap.Items {
+			created := ""
+			if !r.CreatedAt.IsZero() {
+				created = r.CreatedAt.Format(time.RFC3339)
+			}
+			if err := cw.Write([]string{
+				r.CapturedAt.Format(time.RFC3339), r.Rig, strconv.Itoa(r.Position), r.ID,
+				r.Branch, r.Target, r.Worker, r.Issue, r.Status, created,
+				strconv.FormatInt(r.AgeSeconds, 10), r.Error,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteQueueDepthCSV writes one CSV row per snapshot with the queue depth
+// and the age of its oldest MR.
+func WriteQueueDepthCSV(w io.Writer, snaps []QueueSnapshot) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(queueDepthCSVHeader); err != nil {
+		return err
+	}
+	for _, snap := range snaps {
+		if err := cw.Write([]string{
+			snap.CapturedAt.Format(time.RFC3339), snap.Rig,
+			strconv.Itoa(snap.Depth), strconv.FormatInt(snap.OldestAge, 10),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// SnapshotDir returns the directory holding queue snapshots, one JSONL file
+// per UTC day.
+func SnapshotDir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "queue-snapshots")
+}
+
+// snapshotFile returns the day file a snapshot taken at t is appended to.
+func snapshotFile(townRoot string, t time.Time) string {
+	return filepath.Join(SnapshotDir(townRoot), t.UTC().Format("2006-01-02")+".jsonl")
+}
+
+// AppendSnapshots appends snaps to the day file for their capture time.
+func AppendSnapshots(townRoot string, snaps []QueueSnapshot) error {
+	if len(snaps) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(SnapshotDir(townRoot), 0755); err != nil {
+		return fmt.Errorf("creating snapshot dir: %w", err)
+	}
+	fl := flock.New(filepath.Join(SnapshotDir(townRoot), ".lock"))
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking snapshots: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	for _, snap := range snaps {
+		data, err := json.Marshal(snap)
+		if err != nil {
+			return err
+		}
+		f, err := os.OpenFile(snapshotFile(townRoot, snap.CapturedAt), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644) //nolint:gosec // G302: snapshots are not secret
+		if err != nil {
+			return fmt.Errorf("opening snapshot file: %w", err)
+		}
+		_, err = f.Write(append(data, '\n'))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("writing snapshot: %w", err)
+		}
+	}
+	return nil
+}
+
+// LoadSnapshots returns the snapshots captured at or after since, oldest
+// first. A non-empty rig limits them to that rig. Malformed lines are skipped.
+func LoadSnapshots(townRoot, rig string, since time.Time) ([]QueueSnapshot, error) {
+	entries, err := os.ReadDir(SnapshotDir(townRoot))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	firstDay := since.UTC().Format("2006-01-02")
+	var snaps []QueueSnapshot
+	for _, e := range entries {
+		day, ok := strings.CutSuffix(e.Name(), ".jsonl")
+		if !ok || day < firstDay {
+			continue
+		}
+		f, err := os.Open(filepath.Join(SnapshotDir(townRoot), e.Name())) //nolint:gosec // G304: path is constructed from trusted townRoot
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			var snap QueueSnapshot
+			if json.Unmarshal(scanner.Bytes(), &snap) != nil {
+				continue
+			}
+			if snap.CapturedAt.Before(since) || (rig != "" && snap.Rig != rig) {
+				continue
+			}
+			snaps = append(snaps, snap)
+		}
+		_ = f.Close()
+	}
+	sort.SliceStable(snaps, func(i, j int) bool {
+		return snaps[i].CapturedAt.Before(snaps[j].CapturedAt)
+	})
+	return snaps, nil
+}
+
+// PruneSnapshots removes day files older than retainDays and returns how
+// many were removed.
+func PruneSnapshots(townRoot string, retainDays int, now time.Time) (int, error) {
+	if retainDays <= 0 {
+		retainDays = DefaultSnapshotRetainDays
+	}
+	entries, err := os.ReadDir(SnapshotDir(townRoot))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	cutoff := now.UTC().AddDate(0, 0, -retainDays).Format("2006-01-02")
+	removed := 0
+	for _, e := range entries {
+		day, ok := strings.CutSuffix(e.Name(), ".jsonl")
+		if !ok || day >= cutoff {
+			continue
+		}
+		if err := os.Remove(filepath.Join(SnapshotDir(townRoot), e.Name())); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// Snapshot captures the manager's rig queue as of now.
+func (m *Manager) Snapshot(now time.Time) (QueueSnapshot, error) {
+	items, err := m.Queue()
+	if err != nil {
+		return QueueSnapshot{}, err
+	}
+	return NewQueueSnapshot(m.rig.Name, items, now), nil
+}
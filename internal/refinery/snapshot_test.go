@@ -0,0 +1,73 @@
+package refinery
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewQueueSnapshotAndCSV(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	items := []QueueItem{
+		{Position: 1, MR: &MergeRequest{ID: "gt-1", Branch: "polecat/nux", TargetBranch: "main", Worker: "nux", Status: MROpen, CreatedAt: now.Add(-time.Hour)}},
+		{Position: 2, MR: &MergeRequest{ID: "gt-2", Branch: "polecat/ace, x", TargetBranch: "main", Status: MROpen, CreatedAt: now.Add(-time.Minute)}},
+	}
+	snap := NewQueueSnapshot("gastown", items, now)
+	if snap.Depth != 2 || snap.OldestAge != 3600 || snap.Items[1].AgeSeconds != 60 {
+		t.Fatalf("snapshot = %+v", snap)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteQueueCSV(&buf, []QueueSnapshot{snap}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "captured_at,rig,position") {
+		t.Fatalf("csv = %q", buf.String())
+	}
+	if !strings.Contains(lines[2], `"polecat/ace, x"`) {
+		t.Errorf("branch with a comma should be quoted: %q", lines[2])
+	}
+
+	buf.Reset()
+	empty := NewQueueSnapshot("beads", nil, now)
+	if err := WriteQueueDepthCSV(&buf, []QueueSnapshot{snap, empty}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "gastown,2,3600") || !strings.Contains(buf.String(), "beads,0,0") {
+		t.Errorf("depth csv = %q", buf.String())
+	}
+}
+
+func TestSnapshotHistoryAndPrune(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	old := NewQueueSnapshot("gastown", nil, now.AddDate(0, 0, -40))
+	recent := NewQueueSnapshot("gastown", nil, now.Add(-time.Hour))
+	other := NewQueueSnapshot("beads", nil, now)
+	if err := AppendSnapshots(townRoot, []QueueSnapshot{other, old, recent}); err != nil {
+		t.Fatal(err)
+	}
+
+	snaps, err := LoadSnapshots(townRoot, "gastown", now.AddDate(0, 0, -7))
+	if err != nil || len(snaps) != 1 || !snaps[0].CapturedAt.Equal(recent.CapturedAt) {
+		t.Errorf("LoadSnapshots(gastown, 7d) = %+v, %v", snaps, err)
+	}
+	snaps, _ = LoadSnapshots(townRoot, "", time.Time{})
+	if len(snaps) != 3 || snaps[0].Rig != "gastown" || snaps[2].Rig != "beads" {
+		t.Errorf("all snapshots should be sorted oldest first, got %+v", snaps)
+	}
+
+	removed, err := PruneSnapshots(townRoot, 30, now)
+	if err != nil || removed != 1 {
+		t.Errorf("PruneSnapshots = %d, %v; want 1", removed, err)
+	}
+	entries, _ := os.ReadDir(SnapshotDir(townRoot))
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), "2026-01") {
+			t.Errorf("old day file %s not pruned", e.Name())
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package replica
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// TownRig is the rig name under which town-level (hq) beads are stored.
+const TownRig = "hq"
+
+// DefaultEventsWindow is how far back the event log is copied.
+const DefaultEventsWindow = 30 * 24 * time.Hour
+
+// BeadRow is one bead in the replica.
+type BeadRow struct {
+	Rig       string
+	ID        string
+	Title     string
+	Status    string
+	Type      string
+	Priority  int
+	Assignee  string
+	Parent    string
+	Labels    string // JSON array
+	CreatedBy string
+	CreatedAt string
+	UpdatedAt string
+	ClosedAt  string
+}
+
+// EventRow is one town event in the replica.
+type EventRow struct {
+	Timestamp  string
+	Source     string
+	Type       string
+	Actor      string
+	Visibility string
+	Payload    string // JSON object
+}
+
+// Snapshot is the town state copied into the replica.
+type Snapshot struct {
+	TakenAt time.Time
+	Rigs    []string
+	Beads   []BeadRow
+	Events  []EventRow
+	Queue   []refinery.QueueRow
+
+	// Warnings are sources that could not be read; the rest of the
+	// snapshot is still usable.
+	Warnings []string
+}
+
+// Gather reads the town's beads, events since eventsSince and merge queues
+// into a snapshot. A source that can't be read is recorded as a warning.
+func Gather(townRoot string, rigs []string, eventsSince, now time.Time) *Snapshot {
+	snap := &Snapshot{TakenAt: now, Rigs: rigs}
+
+	hq := beads.NewWithBeadsDir(townRoot, beads.ResolveBeadsDir(townRoot))
+	snap.addBeads(TownRig, hq)
+	for _, rigName := range rigs {
+		r := &rig.Rig{Name: rigName, Path: filepath.Join(townRoot, rigName)}
+		snap.addBeads(rigName, beads.New(r.BeadsPath()))
+
+		q, err := refinery.NewManager(r).Snapshot(now)
+		if err != nil {
+			snap.Warnings = append(snap.Warnings, fmt.Sprintf("%s merge queue: %v", rigName, err))
+			continue
+		}
+		snap.Queue = append(snap.Queue, q.Items...)
+	}
+
+	evs, err := readEvents(filepath.Join(townRoot, events.EventsFile), eventsSince)
+	if err != nil {
+		snap.Warnings = append(snap.Warnings, fmt.Sprintf("events: %v", err))
+	}
+	snap.Events = evs
+	return snap
+}
+
+func (s *Snapshot) addBeads(rigName string, b *beads.Beads) {
+	issues, err := b.List(beads.ListOptions{Status: "all", Priority: -1})
+	if err != nil {
+		s.Warnings = append(s.Warnings, fmt.Sprintf("%s beads: %v", rigName, err))
+		return
+	}
+	for _, issue := range issues {
+		if issue == nil {
+			continue
+		}
+		labels, _ := json.Marshal(issue.Labels)
+		if issue.Labels == nil {
+			labels = []byte("[]")
+		}
+		s.Beads = append(s.Beads, BeadRow{
+			Rig:       rigName,
+			ID:        issue.ID,
+			Title:     issue.Title,
+			Status:    issue.Status,
+			Type:      issue.Type,
+			Priority:  issue.Priority,
+			Assignee:  issue.Assignee,
+			Parent:    issue.Parent,
+			Labels:    string(labels),
+			CreatedBy: issue.CreatedBy,
+			CreatedAt: issue.CreatedAt,
+			UpdatedAt: issue.UpdatedAt,
+			ClosedAt:  issue.ClosedAt,
+		})
+	}
+}
+
+// readEvents reads the event log, keeping events at or after since.
+// Malformed lines are skipped; a missing log is empty.
+func readEvents(path string, since time.Time) ([]EventRow, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rows []EventRow
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		var e events.Event
+		if json.Unmarshal(sc.Bytes(), &e) != nil {
+			continue
+		}
+		if ts, err := time.Parse(time.RFC3339, e.Timestamp); err == nil && ts.Before(since) {
+			continue
+		}
+		payload := ""
+		if len(e.Payload) > 0 {
+			data, _ := json.Marshal(e.Payload)
+			payload = string(data)
+		}
+		rows = append(rows, EventRow{
+			Timestamp:  e.Timestamp,
+			Source:     e.Source,
+			Type:       e.Type,
+			Actor:      e.Actor,
+			Visibility: e.Visibility,
+			Payload:    payload,
+		})
+	}
+	return rows, sc.Err()
+}
+
+// Refresh gathers the town state and rebuilds the replica.
+func Refresh(ctx context.Context, townRoot string, rigs []string) (*Snapshot, error) {
+	now := time.Now().UTC()
+	snap := Gather(townRoot, rigs, now.Add(-DefaultEventsWindow), now)
+	if err := Build(ctx, townRoot, snap); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
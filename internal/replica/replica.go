@@ -0,0 +1,256 @@
+// Package replica maintains a read-only SQLite snapshot of town state.
+//
+// Reporting commands (gt stats, dashboards, ad-hoc SQL) query the replica
+// instead of the live stores, so heavy analytical reads never contend with
+// the hot-path writes of witnesses, refineries and the daemon. The replica
+// holds every rig's beads, the town event log and the merge queues, and is
+// rebuilt whole on each refresh: a new file is written next to the old one
+// and renamed over it, so readers always see a complete snapshot.
+//
+// The replica is built and queried with the sqlite3 command-line tool.
+package replica
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoReplica is returned when the replica has not been built yet.
+var ErrNoReplica = errors.New("no replica: run 'gt replica refresh' or enable the replica daemon patrol")
+
+// ErrNoSQLite is returned when the sqlite3 tool is not installed.
+var ErrNoSQLite = errors.New("sqlite3 not found in PATH: the replica needs the sqlite3 command-line tool")
+
+// FileName is the replica's file name under the town's .runtime directory.
+const FileName = "replica.db"
+
+// Path returns the path to the town's replica.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", FileName)
+}
+
+// sqliteBin is the sqlite3 tool; a variable for tests.
+var sqliteBin = "sqlite3"
+
+// Available reports whether the sqlite3 tool is installed.
+func Available() bool {
+	_, err := exec.LookPath(sqliteBin)
+	return err == nil
+}
+
+// schema is the replica's layout. Times are RFC 3339 text and labels and
+// payloads are JSON, so SQLite's date and json functions apply.
+const schema = `
+CREATE TABLE meta (key TEXT PRIMARY KEY, value TEXT);
+CREATE TABLE beads (
+  rig TEXT NOT NULL, id TEXT NOT NULL, title TEXT, status TEXT, type TEXT,
+  priority INTEGER, assignee TEXT, parent TEXT, labels TEXT,
+  created_by TEXT, created_at TEXT, updated_at TEXT, closed_at TEXT,
+  PRIMARY KEY (rig, id)
+);
+CREATE INDEX beads_status ON beads (status);
+CREATE TABLE events (
+  ts TEXT, source TEXT, type TEXT, actor TEXT, visibility TEXT, payload TEXT
+);
+CREATE INDEX events_ts ON events (ts);
+CREATE INDEX events_type ON events (type);
+CREATE TABLE merge_queue (
+  rig TEXT NOT NULL, position INTEGER, id TEXT, branch TEXT, target TEXT,
+  worker TEXT, issue TEXT, status TEXT, created_at TEXT, age_seconds INTEGER,
+  error TEXT
+);
+`
+
+// Build writes snap to the replica, replacing the previous one atomically.
+func Build(ctx context.Context, townRoot string, snap *Snapshot) error {
+	if !Available() {
+		return ErrNoSQLite
+	}
+	path := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	_ = os.Remove(tmp)
+
+	var script bytes.Buffer
+	writeScript(&script, snap)
+
+	c := exec.CommandContext(ctx, sqliteBin, "-bail", tmp) //nolint:gosec // G204: fixed tool, path from trusted townRoot
+	c.Stdin = &script
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("building replica: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("installing replica: %w", err)
+	}
+	return nil
+}
+
+// writeScript renders the SQL that creates the replica from snap, in one
+// transaction.
+func writeScript(w io.Writer, snap *Snapshot) {
+	bw := bufio.NewWriter(w)
+	defer bw.Flush() //nolint:errcheck // bytes.Buffer writes don't fail
+
+	fmt.Fprintln(bw, "PRAGMA journal_mode=OFF;")
+	fmt.Fprintln(bw, "BEGIN;")
+	fmt.Fprint(bw, schema)
+
+	meta := map[string]string{
+		"refreshed_at": snap.TakenAt.UTC().Format(time.RFC3339),
+		"beads":        strconv.Itoa(len(snap.Beads)),
+		"events":       strconv.Itoa(len(snap.Events)),
+		"merge_queue":  strconv.Itoa(len(snap.Queue)),
+		"rigs":         strings.Join(snap.Rigs, ","),
+		"warnings":     strings.Join(snap.Warnings, "\n"),
+	}
+	for _, k := range sortedKeys(meta) {
+		fmt.Fprintf(bw, "INSERT INTO meta VALUES (%s, %s);\n", sqlText(k), sqlText(meta[k]))
+	}
+	for _, b := range snap.Beads {
+		fmt.Fprintf(bw, "INSERT OR REPLACE INTO beads VALUES (%s, %s, %s, %s, %s, %d, %s, %s, %s, %s, %s, %s, %s);\n",
+			sqlText(b.Rig), sqlText(b.ID), sqlText(b.Title), sqlText(b.Status), sqlText(b.Type),
+			b.Priority, sqlText(b.Assignee), sqlText(b.Parent), sqlText(b.Labels),
+			sqlText(b.CreatedBy), sqlText(b.CreatedAt), sqlText(b.UpdatedAt), sqlText(b.ClosedAt))
+	}
+	for _, e := range snap.Events {
+		fmt.Fprintf(bw, "INSERT INTO events VALUES (%s, %s, %s, %s, %s, %s);\n",
+			sqlText(e.Timestamp), sqlText(e.Source), sqlText(e.Type), sqlText(e.Actor),
+			sqlText(e.Visibility), sqlText(e.Payload))
+	}
+	for _, q := range snap.Queue {
+		fmt.Fprintf(bw, "INSERT INTO merge_queue VALUES (%s, %d, %s, %s, %s, %s, %s, %s, %s, %d, %s);\n",
+			sqlText(q.Rig), q.Position, sqlText(q.ID), sqlText(q.Branch), sqlText(q.Target),
+			sqlText(q.Worker), sqlText(q.Issue), sqlText(q.Status), sqlTime(q.CreatedAt),
+			q.AgeSeconds, sqlText(q.Error))
+	}
+	fmt.Fprintln(bw, "COMMIT;")
+}
+
+// sqlText quotes s as an SQL string literal; empty strings become NULL.
+func sqlText(s string) string {
+	if s == "" {
+		return "NULL"
+	}
+	s = strings.ReplaceAll(s, "\x00", "")
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// sqlTime renders t as RFC 3339 text; the zero time becomes NULL.
+func sqlTime(t time.Time) string {
+	if t.IsZero() {
+		return "NULL"
+	}
+	return sqlText(t.UTC().Format(time.RFC3339))
+}
+
+// Result is the output of a query: column names and rows of text values.
+// NULL reads as an empty string. Columns is empty when no rows matched.
+type Result struct {
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+}
+
+// Query runs a read-only SQL statement against the replica. The sqlite3
+// shell runs in safe mode (3.37+), so the query can't use dot-commands such
+// as .shell or .output, or SQL functions that touch files, like writefile.
+func Query(ctx context.Context, townRoot, query string) (*Result, error) {
+	if !Available() {
+		return nil, ErrNoSQLite
+	}
+	path := Path(townRoot)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, ErrNoReplica
+	}
+
+	c := exec.CommandContext(ctx, sqliteBin, "-readonly", "-safe", "-bail", "-csv", "-header", path) //nolint:gosec // G204: fixed tool, path from trusted townRoot
+	c.Stdin = strings.NewReader(query)
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("querying replica: %s", msg)
+		}
+		return nil, fmt.Errorf("querying replica: %w", err)
+	}
+
+	records, err := csv.NewReader(&stdout).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("reading query output: %w", err)
+	}
+	res := &Result{Rows: [][]string{}}
+	if len(records) > 0 {
+		res.Columns = records[0]
+		res.Rows = append(res.Rows, records[1:]...)
+	}
+	return res, nil
+}
+
+// Meta describes the replica's contents.
+type Meta struct {
+	RefreshedAt time.Time `json:"refreshed_at"`
+	Beads       int       `json:"beads"`
+	Events      int       `json:"events"`
+	MergeQueue  int       `json:"merge_queue"`
+	Rigs        []string  `json:"rigs"`
+	Warnings    []string  `json:"warnings,omitempty"`
+	SizeBytes   int64     `json:"size_bytes"`
+}
+
+// Age returns how long ago the replica was refreshed.
+func (m *Meta) Age() time.Duration {
+	return time.Since(m.RefreshedAt)
+}
+
+// ReadMeta returns the replica's metadata.
+func ReadMeta(ctx context.Context, townRoot string) (*Meta, error) {
+	res, err := Query(ctx, townRoot, "SELECT key, value FROM meta;")
+	if err != nil {
+		return nil, err
+	}
+	m := &Meta{}
+	for _, row := range res.Rows {
+		if len(row) != 2 {
+			continue
+		}
+		switch v := row[1]; row[0] {
+		case "refreshed_at":
+			m.RefreshedAt, _ = time.Parse(time.RFC3339, v)
+		case "beads":
+			m.Beads, _ = strconv.Atoi(v)
+		case "events":
+			m.Events, _ = strconv.Atoi(v)
+		case "merge_queue":
+			m.MergeQueue, _ = strconv.Atoi(v)
+		case "rigs":
+			if v != "" {
+				m.Rigs = strings.Split(v, ",")
+			}
+		case "warnings":
+			if v != "" {
+				m.Warnings = strings.Split(v, "\n")
+			}
+		}
+	}
+	if info, err := os.Stat(Path(townRoot)); err == nil {
+		m.SizeBytes = info.Size()
+	}
+	return m, nil
+}
@@ -0,0 +1,145 @@
+package replica
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/refinery"
+)
+
+func requireSQLite(t *testing.T) {
+	t.Helper()
+	if !Available() {
+		t.Skip("sqlite3 not installed")
+	}
+}
+
+func TestBuildAndQuery(t *testing.T) {
+	requireSQLite(t)
+	townRoot := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	snap := &Snapshot{
+		TakenAt: now,
+		Rigs:    []string{"gastown"},
+		Beads: []BeadRow{
+			{Rig: "gastown", ID: "gt-1", Title: "it's quoted", Status: "open", Priority: 1, Labels: `["gt:task"]`},
+			{Rig: "gastown", ID: "gt-2", Title: "done", Status: "closed", Priority: 2, Labels: `[]`},
+			{Rig: TownRig, ID: "hq-1", Title: "town", Status: "open", Labels: `[]`},
+		},
+		Events: []EventRow{
+			{Timestamp: "2026-03-01T11:00:00Z", Type: "sling", Actor: "mayor", Payload: `{"bead":"gt-1"}`},
+		},
+		Queue: []refinery.QueueRow{
+			{Rig: "gastown", Position: 1, ID: "gt-mr1", Branch: "polecat/a", CreatedAt: now.Add(-time.Hour), AgeSeconds: 3600},
+		},
+		Warnings: []string{"other beads: unreadable"},
+	}
+	if err := Build(context.Background(), townRoot, snap); err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if _, err := os.Stat(Path(townRoot) + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind")
+	}
+
+	res, err := Query(context.Background(), townRoot,
+		"SELECT rig, count(*) AS n FROM beads WHERE status = 'open' GROUP BY rig ORDER BY rig;")
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if strings.Join(res.Columns, ",") != "rig,n" {
+		t.Errorf("Columns = %v", res.Columns)
+	}
+	if len(res.Rows) != 2 || res.Rows[0][0] != "gastown" || res.Rows[0][1] != "1" {
+		t.Errorf("Rows = %v", res.Rows)
+	}
+
+	res, err = Query(context.Background(), townRoot, "SELECT title FROM beads WHERE id = 'gt-1';")
+	if err != nil || len(res.Rows) != 1 || res.Rows[0][0] != "it's quoted" {
+		t.Errorf("quoted title = %v, %v", res, err)
+	}
+	res, err = Query(context.Background(), townRoot,
+		"SELECT json_extract(payload, '$.bead') FROM events WHERE type = 'sling';")
+	if err != nil || len(res.Rows) != 1 || res.Rows[0][0] != "gt-1" {
+		t.Errorf("event payload = %v, %v", res, err)
+	}
+
+	meta, err := ReadMeta(context.Background(), townRoot)
+	if err != nil {
+		t.Fatalf("ReadMeta: %v", err)
+	}
+	if !meta.RefreshedAt.Equal(now) || meta.Beads != 3 || meta.Events != 1 || meta.MergeQueue != 1 {
+		t.Errorf("meta = %+v", meta)
+	}
+	if len(meta.Warnings) != 1 || len(meta.Rigs) != 1 {
+		t.Errorf("meta warnings/rigs = %v / %v", meta.Warnings, meta.Rigs)
+	}
+}
+
+func TestQuery_ReadOnly(t *testing.T) {
+	requireSQLite(t)
+	townRoot := t.TempDir()
+	if err := Build(context.Background(), townRoot, &Snapshot{TakenAt: time.Now()}); err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if _, err := Query(context.Background(), townRoot, "DELETE FROM meta;"); err == nil {
+		t.Error("expected a write to be refused")
+	}
+}
+
+func TestQuery_Safe(t *testing.T) {
+	requireSQLite(t)
+	townRoot := t.TempDir()
+	if err := Build(context.Background(), townRoot, &Snapshot{TakenAt: time.Now()}); err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	marker := filepath.Join(townRoot, "touched")
+	for _, query := range []string{
+		".shell touch " + marker,
+		".system touch " + marker,
+		".output " + marker + "\nSELECT 1;",
+		"SELECT writefile(" + sqlText(marker) + ", 'x');",
+	} {
+		if _, err := Query(context.Background(), townRoot, query); err == nil {
+			t.Errorf("Query(%q) succeeded, want it refused", query)
+		}
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("a query reached the filesystem: %v", err)
+	}
+}
+
+func TestQuery_NoReplica(t *testing.T) {
+	requireSQLite(t)
+	_, err := Query(context.Background(), t.TempDir(), "SELECT 1;")
+	if !errors.Is(err, ErrNoReplica) {
+		t.Errorf("err = %v, want ErrNoReplica", err)
+	}
+}
+
+func TestReadEvents_Since(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	data := `{"ts":"2026-01-01T00:00:00Z","type":"old","actor":"a"}
+not json
+{"ts":"2026-03-01T00:00:00Z","type":"new","actor":"b","payload":{"k":"v"}}
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := readEvents(path, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("readEvents: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Type != "new" || rows[0].Payload != `{"k":"v"}` {
+		t.Errorf("rows = %+v", rows)
+	}
+
+	rows, err = readEvents(filepath.Join(t.TempDir(), "missing"), time.Time{})
+	if err != nil || rows != nil {
+		t.Errorf("missing log = %v, %v", rows, err)
+	}
+}
@@ -3,6 +3,7 @@ package runtime
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -108,6 +109,36 @@ func SleepForReadyDelay(rc *config.RuntimeConfig) {
 	time.Sleep(time.Duration(rc.Tmux.ReadyDelayMs) * time.Millisecond)
 }
 
+// WaitForReady waits for the runtime in session to accept input. With a
+// ready_pattern configured it polls the pane for a matching line, up to
+// timeout; otherwise it sleeps for the readiness delay.
+func WaitForReady(t *tmux.Tmux, session string, rc *config.RuntimeConfig, timeout time.Duration) error {
+	if rc != nil && rc.Tmux != nil && rc.Tmux.ReadyPattern != "" {
+		return t.WaitForRuntimeReady(session, rc, timeout)
+	}
+	SleepForReadyDelay(rc)
+	return nil
+}
+
+// EmitsPermissionWarning reports whether the runtime shows a
+// bypass-permissions dialog at startup that must be accepted over tmux.
+// The agent is identified by its resolved name, then its provider, then its
+// command, so a custom agent wrapping claude still gets the dialog accepted.
+func EmitsPermissionWarning(rc *config.RuntimeConfig) bool {
+	if rc == nil {
+		return true // Default runtime is Claude
+	}
+	for _, name := range []string{rc.ResolvedAgent, rc.Provider, filepath.Base(rc.Command)} {
+		if name == "" || name == "." {
+			continue
+		}
+		if preset := config.GetAgentPresetByName(name); preset != nil {
+			return preset.EmitsPermissionWarning
+		}
+	}
+	return rc.Command == ""
+}
+
 // StartupFallbackCommands returns commands that approximate Claude hooks when hooks are unavailable.
 func StartupFallbackCommands(role string, rc *config.RuntimeConfig) []string {
 	if rc == nil {
@@ -148,6 +148,41 @@ func TestSleepForReadyDelay_NilTmuxConfig(t *testing.T) {
 	}
 }
 
+func TestWaitForReady_NoPatternSleepsDelay(t *testing.T) {
+	rc := &config.RuntimeConfig{Tmux: &config.RuntimeTmuxConfig{ReadyDelayMs: 10}}
+
+	start := time.Now()
+	// No tmux calls are made without a ready_pattern.
+	if err := WaitForReady(nil, "gt-test", rc, time.Second); err != nil {
+		t.Fatalf("WaitForReady() = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("WaitForReady() returned after %v, want the 10ms ready delay", elapsed)
+	}
+}
+
+func TestEmitsPermissionWarning(t *testing.T) {
+	tests := []struct {
+		name string
+		rc   *config.RuntimeConfig
+		want bool
+	}{
+		{"nil config is claude", nil, true},
+		{"claude preset", &config.RuntimeConfig{ResolvedAgent: "claude", Command: "claude"}, true},
+		{"custom name wrapping claude", &config.RuntimeConfig{ResolvedAgent: "claude-opus", Command: "/usr/local/bin/claude"}, true},
+		{"codex preset", &config.RuntimeConfig{ResolvedAgent: "codex", Command: "codex"}, false},
+		{"custom wrapper", &config.RuntimeConfig{ResolvedAgent: "aider", Command: "aider"}, false},
+		{"provider wins over command", &config.RuntimeConfig{Provider: "codex", Command: "my-wrapper"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EmitsPermissionWarning(tt.rc); got != tt.want {
+				t.Errorf("EmitsPermissionWarning() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestStartupFallbackCommands_NoHooks(t *testing.T) {
 	rc := &config.RuntimeConfig{
 		Hooks: &config.RuntimeHooksConfig{
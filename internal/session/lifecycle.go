@@ -75,6 +75,11 @@ type SessionConfig struct {
 	// These are set in the tmux session environment after the standard vars.
 	ExtraEnv map[string]string
 
+	// OneShot marks the session as a short-lived helper. The limits are
+	// recorded in the tmux environment and enforced by the daemon's
+	// session_reaper patrol. Nil means the session runs unbounded.
+	OneShot *OneShotLimits
+
 	// Theme is the tmux theme to apply. Nil means no theme is applied.
 	Theme *tmux.Theme
 
@@ -121,7 +126,7 @@ type StartResult struct {
 //  2. Ensure settings/plugins exist for the agent
 //  3. Build startup command (if not provided)
 //  4. Create tmux session with command
-//  5. Set environment variables (standard + extra)
+//  5. Set environment variables (standard + extra + one-shot limits)
 //  6. Apply theme (if configured)
 //  7. Optional post-start: wait for agent, accept bypass, ready delay,
 //     auto-respawn, PID tracking, verify survived
@@ -199,6 +204,12 @@ func StartSession(t *tmux.Tmux, cfg SessionConfig) (*StartResult, error) {
 	for _, k := range mapKeysSorted(cfg.ExtraEnv) {
 		_ = t.SetEnvironment(cfg.SessionID, k, cfg.ExtraEnv[k])
 	}
+	if cfg.OneShot != nil {
+		oneShotEnv := cfg.OneShot.Env()
+		for _, k := range mapKeysSorted(oneShotEnv) {
+			_ = t.SetEnvironment(cfg.SessionID, k, oneShotEnv[k])
+		}
+	}
 
 	// 7. Apply theme.
 	if cfg.Theme != nil {
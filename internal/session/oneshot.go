@@ -0,0 +1,82 @@
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Tmux session environment variables that mark a session as a one-shot
+// helper. The daemon's session_reaper patrol captures, summarizes, and kills
+// sessions that outlive either limit, so short-lived agents (Boot triage,
+// dogs) don't accumulate when they hang or forget to exit.
+const (
+	EnvMaxLifetime = "GT_MAX_LIFETIME"
+	EnvIdleTimeout = "GT_IDLE_TIMEOUT"
+)
+
+// OneShotLimits bounds how long a one-shot session may live. A zero limit
+// is not enforced.
+type OneShotLimits struct {
+	// MaxLifetime is the longest the session may run, measured from creation.
+	MaxLifetime time.Duration
+
+	// IdleTimeout is the longest the session may go without pane output.
+	IdleTimeout time.Duration
+}
+
+// Env returns the tmux environment that records the limits on a session.
+func (l OneShotLimits) Env() map[string]string {
+	env := make(map[string]string)
+	if l.MaxLifetime > 0 {
+		env[EnvMaxLifetime] = l.MaxLifetime.String()
+	}
+	if l.IdleTimeout > 0 {
+		env[EnvIdleTimeout] = l.IdleTimeout.String()
+	}
+	return env
+}
+
+// IsZero reports whether no limit is set.
+func (l OneShotLimits) IsZero() bool {
+	return l.MaxLifetime <= 0 && l.IdleTimeout <= 0
+}
+
+// Exceeded returns why a session created at created and last active at
+// lastActivity has outlived its limits at now, or "" if it hasn't.
+// The lifetime limit is reported first since it applies even to a busy session.
+func (l OneShotLimits) Exceeded(created, lastActivity, now time.Time) string {
+	if l.MaxLifetime > 0 && !created.IsZero() {
+		if age := now.Sub(created); age > l.MaxLifetime {
+			return fmt.Sprintf("max lifetime %v exceeded (running %v)", l.MaxLifetime, age.Round(time.Second))
+		}
+	}
+	if l.IdleTimeout > 0 && !lastActivity.IsZero() {
+		if idle := now.Sub(lastActivity); idle > l.IdleTimeout {
+			return fmt.Sprintf("idle timeout %v exceeded (idle %v)", l.IdleTimeout, idle.Round(time.Second))
+		}
+	}
+	return ""
+}
+
+// ParseOneShotLimits builds limits from the session environment values.
+// Unset or unparseable values leave that limit unenforced.
+func ParseOneShotLimits(maxLifetime, idleTimeout string) OneShotLimits {
+	var l OneShotLimits
+	if d, err := time.ParseDuration(maxLifetime); err == nil && d > 0 {
+		l.MaxLifetime = d
+	}
+	if d, err := time.ParseDuration(idleTimeout); err == nil && d > 0 {
+		l.IdleTimeout = d
+	}
+	return l
+}
+
+// ReadOneShotLimits reads the one-shot limits recorded on a session.
+// Sessions started without limits return the zero value.
+func ReadOneShotLimits(t *tmux.Tmux, sessionID string) OneShotLimits {
+	maxLifetime, _ := t.GetEnvironment(sessionID, EnvMaxLifetime)
+	idleTimeout, _ := t.GetEnvironment(sessionID, EnvIdleTimeout)
+	return ParseOneShotLimits(maxLifetime, idleTimeout)
+}
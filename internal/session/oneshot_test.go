@@ -0,0 +1,53 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOneShotLimitsEnvRoundTrip(t *testing.T) {
+	l := OneShotLimits{MaxLifetime: 15 * time.Minute, IdleTimeout: 5 * time.Minute}
+	env := l.Env()
+	if env[EnvMaxLifetime] != "15m0s" || env[EnvIdleTimeout] != "5m0s" {
+		t.Fatalf("unexpected env: %v", env)
+	}
+	if got := ParseOneShotLimits(env[EnvMaxLifetime], env[EnvIdleTimeout]); got != l {
+		t.Errorf("round trip = %+v, want %+v", got, l)
+	}
+
+	if env := (OneShotLimits{IdleTimeout: time.Minute}).Env(); len(env) != 1 {
+		t.Errorf("unset limits should not be recorded: %v", env)
+	}
+	if got := ParseOneShotLimits("", "soon"); !got.IsZero() {
+		t.Errorf("missing or invalid values should leave limits unset, got %+v", got)
+	}
+}
+
+func TestOneShotLimitsExceeded(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	l := OneShotLimits{MaxLifetime: time.Hour, IdleTimeout: 10 * time.Minute}
+
+	tests := []struct {
+		name     string
+		limits   OneShotLimits
+		created  time.Time
+		activity time.Time
+		want     string
+	}{
+		{"within limits", l, now.Add(-30 * time.Minute), now.Add(-time.Minute), ""},
+		{"too old while busy", l, now.Add(-2 * time.Hour), now, "max lifetime 1h0m0s exceeded (running 2h0m0s)"},
+		{"idle", l, now.Add(-30 * time.Minute), now.Add(-11 * time.Minute), "idle timeout 10m0s exceeded (idle 11m0s)"},
+		{"lifetime reported first", l, now.Add(-2 * time.Hour), now.Add(-time.Hour), "max lifetime"},
+		{"unknown times", l, time.Time{}, time.Time{}, ""},
+		{"no limits", OneShotLimits{}, now.Add(-48 * time.Hour), now.Add(-48 * time.Hour), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.limits.Exceeded(tt.created, tt.activity, now)
+			if (tt.want == "") != (got == "") || !strings.HasPrefix(got, tt.want) {
+				t.Errorf("Exceeded() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
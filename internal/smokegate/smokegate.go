@@ -0,0 +1,270 @@
+// Package smokegate holds a rig's spawns and merges after an incident until
+// its default branch passes a smoke test.
+//
+// When a rig comes back from an incident (it was parked or docked, or its
+// circuit tripped), the code on its default branch may be what caused the
+// trouble. Re-enabling it straight away lets new polecats pile onto a broken
+// build. Instead, the gate is marked required in <rig>/.runtime/smoke-gate.json:
+// spawns are refused and the refinery holds MRs until the Witness runs the
+// gate (fresh worktree of origin/<default-branch>, setup, build, smoke
+// command) and it passes.
+package smokegate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// stateFile lives under <rig>/.runtime/.
+const stateFile = "smoke-gate.json"
+
+// DefaultTimeout bounds a whole gate run.
+const DefaultTimeout = 20 * time.Minute
+
+// outputTailLines is how much of a failing step's output is kept.
+const outputTailLines = 40
+
+// ErrGated is returned by operations refused while a rig's smoke gate is
+// required.
+var ErrGated = errors.New("rig is held until its smoke gate passes")
+
+// State is the contents of a rig's smoke gate file.
+type State struct {
+	// Required is true while the rig is held waiting for a passing run.
+	Required bool `json:"required"`
+
+	// Reason explains why the gate was required.
+	Reason string `json:"reason,omitempty"`
+
+	// Since is when the gate was required.
+	Since time.Time `json:"since,omitempty"`
+
+	// LastRun is the most recent gate run, passing or not.
+	LastRun *Result `json:"last_run,omitempty"`
+}
+
+// Result is the outcome of one gate run.
+type Result struct {
+	Passed     bool          `json:"passed"`
+	Ref        string        `json:"ref"`
+	Commit     string        `json:"commit,omitempty"`
+	FailedStep string        `json:"failed_step,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Output     string        `json:"output,omitempty"` // Tail of the failing step's output
+	StartedAt  time.Time     `json:"started_at"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// Step is one command the gate runs in the fresh worktree.
+type Step struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+}
+
+// StatePath returns the path of a rig's smoke gate file.
+func StatePath(rigPath string) string {
+	return filepath.Join(rigPath, constants.DirRuntime, stateFile)
+}
+
+// Load reads a rig's smoke gate state. A missing file means not required.
+func Load(rigPath string) (*State, error) {
+	data, err := os.ReadFile(StatePath(rigPath)) //nolint:gosec // G304: path is constructed from trusted rigPath
+	if os.IsNotExist(err) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading smoke gate state: %w", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing smoke gate state: %w", err)
+	}
+	return &state, nil
+}
+
+// IsRequired reports whether the rig is held by its smoke gate.
+// An unreadable state file is treated as not required.
+func IsRequired(rigPath string) bool {
+	state, err := Load(rigPath)
+	return err == nil && state.Required
+}
+
+// Require holds the rig until the gate passes. Returns false if it was
+// already required; the original reason and time are kept.
+func Require(rigPath, reason string) (bool, error) {
+	var required bool
+	err := update(rigPath, func(s *State) {
+		if s.Required {
+			return
+		}
+		s.Required = true
+		s.Reason = reason
+		s.Since = time.Now().UTC()
+		required = true
+	})
+	return required, err
+}
+
+// Record stores a gate run. A passing run releases the rig.
+func Record(rigPath string, result *Result) error {
+	return update(rigPath, func(s *State) {
+		s.LastRun = result
+		if result.Passed {
+			s.Required = false
+			s.Reason = ""
+			s.Since = time.Time{}
+		}
+	})
+}
+
+// StepsForRig returns the gate's steps from the rig's merge_queue settings:
+// setup_command, build_command, then smoke_command (test_command when no
+// smoke command is set). Unset commands are skipped.
+func StepsForRig(rigPath string) []Step {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil || settings.MergeQueue == nil {
+		return nil
+	}
+	mq := settings.MergeQueue
+	smoke := mq.SmokeCommand
+	if smoke == "" {
+		smoke = mq.TestCommand
+	}
+	var steps []Step
+	for _, s := range []Step{{"setup", mq.SetupCommand}, {"build", mq.BuildCommand}, {"smoke", smoke}} {
+		if strings.TrimSpace(s.Command) != "" {
+			steps = append(steps, s)
+		}
+	}
+	return steps
+}
+
+// DefaultRef returns origin/<default-branch> for the rig.
+func DefaultRef(rigPath string) string {
+	branch := "main"
+	if rigCfg, err := rig.LoadRigConfig(rigPath); err == nil && rigCfg.DefaultBranch != "" {
+		branch = rigCfg.DefaultBranch
+	}
+	return "origin/" + branch
+}
+
+// repoBase returns the repo fresh worktrees are made from: the shared bare
+// repo (.repo.git) when present, otherwise mayor/rig.
+func repoBase(rigPath string) (*git.Git, error) {
+	bare := filepath.Join(rigPath, ".repo.git")
+	if info, err := os.Stat(bare); err == nil && info.IsDir() {
+		return git.NewGitWithDir(bare, ""), nil
+	}
+	mayorRig := filepath.Join(rigPath, "mayor", "rig")
+	if _, err := os.Stat(mayorRig); err != nil {
+		return nil, fmt.Errorf("no repo base found (neither .repo.git nor mayor/rig exists)")
+	}
+	return git.NewGit(mayorRig), nil
+}
+
+// Run fetches origin, checks ref out into a fresh detached worktree, and
+// runs steps there in order, stopping at the first failure. The worktree is
+// removed afterwards. Run never returns nil; a gate that couldn't run at
+// all is a failed result with Error set.
+//
+// Trust boundary: steps come from the rig's settings (operator-controlled),
+// so they run through the shell, as the refinery's gates do.
+func Run(ctx context.Context, rigPath, ref string, steps []Step) *Result {
+	result := &Result{Ref: ref, StartedAt: time.Now().UTC()}
+	defer func() { result.Duration = time.Since(result.StartedAt).Round(time.Second) }()
+
+	if len(steps) == 0 {
+		result.Error = "no smoke steps configured (set merge_queue.smoke_command or test_command in the rig settings)"
+		return result
+	}
+	repo, err := repoBase(rigPath)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	_ = repo.Fetch("origin") // Best effort: a stale ref still gets smoke-tested
+
+	tmpDir, err := os.MkdirTemp("", "gt-smoke-")
+	if err != nil {
+		result.Error = fmt.Sprintf("creating temp dir: %v", err)
+		return result
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+	wt := filepath.Join(tmpDir, "worktree")
+	if err := repo.WorktreeAddDetached(wt, ref); err != nil {
+		result.Error = fmt.Sprintf("creating worktree at %s: %v", ref, err)
+		return result
+	}
+	defer func() {
+		_ = repo.WorktreeRemove(wt, true)
+		_ = repo.WorktreePrune()
+	}()
+	if commit, err := git.NewGit(wt).Rev("HEAD"); err == nil {
+		result.Commit = commit
+	}
+
+	for _, step := range steps {
+		cmd := exec.CommandContext(ctx, "sh", "-c", step.Command) //nolint:gosec // G204: step commands are from trusted rig config
+		cmd.Dir = wt
+		var out bytes.Buffer
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			result.FailedStep = step.Name
+			result.Error = fmt.Sprintf("%s (%s): %v", step.Name, step.Command, err)
+			result.Output = tailLines(out.String(), outputTailLines)
+			return result
+		}
+	}
+	result.Passed = true
+	return result
+}
+
+// tailLines returns the last n lines of s.
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// update applies fn to the state under a cross-process file lock and
+// writes the result back atomically.
+func update(rigPath string, fn func(*State)) error {
+	path := StatePath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime dir: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking smoke gate state: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	state, err := Load(rigPath)
+	if err != nil {
+		// A corrupt state file must not wedge the rig; start over.
+		state = &State{}
+	}
+	fn(state)
+	return util.AtomicWriteJSON(path, state)
+}
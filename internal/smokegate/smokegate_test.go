@@ -0,0 +1,165 @@
+package smokegate
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadMissingIsNotRequired(t *testing.T) {
+	state, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if state.Required || IsRequired(t.TempDir()) {
+		t.Error("missing state file should mean not required")
+	}
+}
+
+func TestRequireAndRecord(t *testing.T) {
+	rigPath := t.TempDir()
+
+	required, err := Require(rigPath, "rig unparked")
+	if err != nil || !required {
+		t.Fatalf("Require = %v, %v; want true, nil", required, err)
+	}
+	if required, _ := Require(rigPath, "again"); required {
+		t.Error("second Require should report already required")
+	}
+	state, _ := Load(rigPath)
+	if state.Reason != "rig unparked" || state.Since.IsZero() {
+		t.Errorf("state = %+v", state)
+	}
+
+	// A failing run keeps the rig held.
+	if err := Record(rigPath, &Result{Passed: false, Error: "build failed"}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	state, _ = Load(rigPath)
+	if !state.Required || state.LastRun == nil || state.LastRun.Error != "build failed" {
+		t.Errorf("after failed run: %+v", state)
+	}
+
+	// A passing run releases it.
+	if err := Record(rigPath, &Result{Passed: true}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	state, _ = Load(rigPath)
+	if state.Required || state.Reason != "" || state.LastRun == nil || !state.LastRun.Passed {
+		t.Errorf("after passing run: %+v", state)
+	}
+}
+
+func TestStepsForRig(t *testing.T) {
+	rigPath := t.TempDir()
+	if steps := StepsForRig(rigPath); len(steps) != 0 {
+		t.Errorf("no settings: steps = %v", steps)
+	}
+
+	writeSettings := func(mq string) {
+		t.Helper()
+		dir := filepath.Join(rigPath, "settings")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		body := `{"type":"rig-settings","version":1,"merge_queue":` + mq + `}`
+		if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeSettings(`{"build_command":"make","test_command":"make test"}`)
+	steps := StepsForRig(rigPath)
+	if len(steps) != 2 || steps[0].Name != "build" || steps[1].Command != "make test" {
+		t.Errorf("test_command fallback: steps = %v", steps)
+	}
+
+	writeSettings(`{"setup_command":"npm ci","test_command":"make test","smoke_command":"make smoke"}`)
+	steps = StepsForRig(rigPath)
+	if len(steps) != 2 || steps[0].Name != "setup" || steps[1].Command != "make smoke" {
+		t.Errorf("smoke_command: steps = %v", steps)
+	}
+}
+
+// setupRig creates a rig whose mayor/rig clone tracks an origin with one
+// commit on main.
+func setupRig(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin")
+	rigPath := filepath.Join(root, "rig")
+
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	if err := os.MkdirAll(origin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run(origin, "init", "-b", "main")
+	if err := os.WriteFile(filepath.Join(origin, "smoke.txt"), []byte("ok\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(origin, "add", ".")
+	run(origin, "commit", "-m", "initial")
+	if err := os.MkdirAll(filepath.Join(rigPath, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	run(root, "clone", origin, filepath.Join(rigPath, "mayor", "rig"))
+	return rigPath
+}
+
+func TestRunPassesInFreshWorktree(t *testing.T) {
+	rigPath := setupRig(t)
+
+	result := Run(context.Background(), rigPath, "origin/main", []Step{
+		{Name: "build", Command: "test -f smoke.txt"},
+		{Name: "smoke", Command: "grep -q ok smoke.txt"},
+	})
+	if !result.Passed {
+		t.Fatalf("expected pass, got %+v", result)
+	}
+	if result.Commit == "" {
+		t.Error("result should record the commit smoke-tested")
+	}
+}
+
+func TestRunStopsAtFirstFailure(t *testing.T) {
+	rigPath := setupRig(t)
+	marker := filepath.Join(t.TempDir(), "ran")
+
+	result := Run(context.Background(), rigPath, "origin/main", []Step{
+		{Name: "build", Command: "echo compiling; echo broken >&2; exit 3"},
+		{Name: "smoke", Command: "touch " + marker},
+	})
+	if result.Passed || result.FailedStep != "build" {
+		t.Fatalf("expected build failure, got %+v", result)
+	}
+	if !strings.Contains(result.Output, "broken") {
+		t.Errorf("output should include the failing step's stderr, got %q", result.Output)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("steps after a failure must not run")
+	}
+}
+
+func TestRunWithoutStepsFails(t *testing.T) {
+	result := Run(context.Background(), t.TempDir(), "origin/main", nil)
+	if result.Passed || result.Error == "" {
+		t.Errorf("expected failure with no steps, got %+v", result)
+	}
+}
@@ -0,0 +1,227 @@
+// Package squad manages squads: named groups of agents, usually polecats
+// across several rigs, staffed on one initiative.
+//
+// A squad can be nudged, paused and reported on as a unit, and carries a
+// budget (how many members may run at once, how much they may spend a
+// day) that gt sling --squad enforces before spawning into it. Squads are
+// distinct from crew workspaces: a crew member is one persistent worker,
+// a squad is a group of agents of any role.
+//
+// Squads live in <town>/mayor/squads.json.
+package squad
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+var (
+	// ErrNotFound is returned for an unknown squad name.
+	ErrNotFound = errors.New("squad not found")
+
+	// ErrPaused is returned when spawning into a paused squad.
+	ErrPaused = errors.New("squad is paused")
+
+	// ErrOverBudget is returned when spawning would exceed a squad's budget.
+	ErrOverBudget = errors.New("squad is over budget")
+)
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Budget limits a squad. Zero values are unlimited.
+type Budget struct {
+	// MaxAgents is how many members may be running at once.
+	MaxAgents int `json:"max_agents,omitempty"`
+
+	// DailyUSD is how much the squad's sessions may spend per day.
+	DailyUSD float64 `json:"daily_usd,omitempty"`
+}
+
+// Squad is a named group of agents.
+type Squad struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// Members are agent addresses (gastown/polecats/nux) or patterns
+	// (gastown/polecats/*, */crew/*).
+	Members []string `json:"members"`
+
+	Budget Budget `json:"budget"`
+
+	Paused      bool       `json:"paused,omitempty"`
+	PauseReason string     `json:"pause_reason,omitempty"`
+	PausedAt    *time.Time `json:"paused_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Matches reports whether address is a member of the squad.
+func (s *Squad) Matches(address string) bool {
+	for _, m := range s.Members {
+		if m == address {
+			return true
+		}
+		if ok, _ := path.Match(m, address); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// AddMember adds member unless it is already listed, reporting whether it
+// was added.
+func (s *Squad) AddMember(member string) bool {
+	for _, m := range s.Members {
+		if m == member {
+			return false
+		}
+	}
+	s.Members = append(s.Members, member)
+	return true
+}
+
+// RemoveMember removes member, reporting whether it was listed.
+func (s *Squad) RemoveMember(member string) bool {
+	for i, m := range s.Members {
+		if m == member {
+			s.Members = append(s.Members[:i], s.Members[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// CheckSpawn returns an error if another member may not be started: the
+// squad is paused, running already has MaxAgents members, or spentToday
+// has reached DailyUSD.
+func (s *Squad) CheckSpawn(running int, spentToday float64) error {
+	if s.Paused {
+		if s.PauseReason != "" {
+			return fmt.Errorf("%w: %s (%s)", ErrPaused, s.Name, s.PauseReason)
+		}
+		return fmt.Errorf("%w: %s", ErrPaused, s.Name)
+	}
+	if s.Budget.MaxAgents > 0 && running >= s.Budget.MaxAgents {
+		return fmt.Errorf("%w: %s has %d/%d agents running", ErrOverBudget, s.Name, running, s.Budget.MaxAgents)
+	}
+	if s.Budget.DailyUSD > 0 && spentToday >= s.Budget.DailyUSD {
+		return fmt.Errorf("%w: %s spent $%.2f of $%.2f today", ErrOverBudget, s.Name, spentToday, s.Budget.DailyUSD)
+	}
+	return nil
+}
+
+// Registry holds every squad in a town.
+type Registry struct {
+	Squads map[string]*Squad `json:"squads"`
+}
+
+// Get returns the named squad.
+func (r *Registry) Get(name string) (*Squad, error) {
+	s, ok := r.Squads[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return s, nil
+}
+
+// Sorted returns the squads ordered by name.
+func (r *Registry) Sorted() []*Squad {
+	out := make([]*Squad, 0, len(r.Squads))
+	for _, s := range r.Squads {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// ValidateName checks that name is usable as a squad name.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid squad name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// Path returns the path to the town's squads file.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, "mayor", "squads.json")
+}
+
+// Load reads the town's squads. A missing file is an empty registry.
+func Load(townRoot string) (*Registry, error) {
+	reg := &Registry{Squads: make(map[string]*Squad)}
+	data, err := os.ReadFile(Path(townRoot)) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if os.IsNotExist(err) {
+		return reg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading squads: %w", err)
+	}
+	if err := json.Unmarshal(data, reg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", Path(townRoot), err)
+	}
+	if reg.Squads == nil {
+		reg.Squads = make(map[string]*Squad)
+	}
+	return reg, nil
+}
+
+// Update loads the registry under a lock, applies fn and saves the result.
+// Nothing is saved if fn returns an error.
+func Update(townRoot string, fn func(*Registry) error) error {
+	p := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	fl := flock.New(p + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking squads: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	reg, err := Load(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := fn(reg); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(p, reg)
+}
+
+// Assign makes address an explicit member of the named squad and of no
+// other. An empty name only removes address from every squad, which is how
+// a reused polecat name leaves the squad its predecessor was slung into.
+// Without a squads file and with an empty name it does nothing.
+func Assign(townRoot, address, name string) error {
+	if name == "" {
+		if _, err := os.Stat(Path(townRoot)); os.IsNotExist(err) {
+			return nil
+		}
+	}
+	return Update(townRoot, func(reg *Registry) error {
+		if name != "" {
+			if _, err := reg.Get(name); err != nil {
+				return err
+			}
+		}
+		for _, s := range reg.Squads {
+			if s.Name == name {
+				s.AddMember(address)
+			} else {
+				s.RemoveMember(address)
+			}
+		}
+		return nil
+	})
+}
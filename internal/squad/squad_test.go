@@ -0,0 +1,95 @@
+package squad
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestMatches(t *testing.T) {
+	s := &Squad{Members: []string{"gastown/polecats/nux", "beads/polecats/*", "*/crew/max"}}
+	for addr, want := range map[string]bool{
+		"gastown/polecats/nux":   true,
+		"gastown/polecats/toast": false,
+		"beads/polecats/toast":   true,
+		"beads/crew/max":         true,
+		"beads/witness":          false,
+	} {
+		if got := s.Matches(addr); got != want {
+			t.Errorf("Matches(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestCheckSpawn(t *testing.T) {
+	s := &Squad{Name: "search", Budget: Budget{MaxAgents: 2, DailyUSD: 10}}
+	if err := s.CheckSpawn(1, 5); err != nil {
+		t.Errorf("under budget: %v", err)
+	}
+	if err := s.CheckSpawn(2, 5); !errors.Is(err, ErrOverBudget) {
+		t.Errorf("at max agents: %v", err)
+	}
+	if err := s.CheckSpawn(0, 10); !errors.Is(err, ErrOverBudget) {
+		t.Errorf("at daily spend: %v", err)
+	}
+	s.Paused = true
+	if err := s.CheckSpawn(0, 0); !errors.Is(err, ErrPaused) {
+		t.Errorf("paused: %v", err)
+	}
+	if err := (&Squad{}).CheckSpawn(100, 1000); err != nil {
+		t.Errorf("no budget should be unlimited: %v", err)
+	}
+}
+
+func TestAssign(t *testing.T) {
+	townRoot := t.TempDir()
+
+	// Leaving without a squads file must not create one.
+	if err := Assign(townRoot, "gastown/polecats/nux", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(Path(townRoot)); !os.IsNotExist(err) {
+		t.Fatalf("squads file created by a no-op assign: %v", err)
+	}
+
+	if err := Update(townRoot, func(reg *Registry) error {
+		reg.Squads["a"] = &Squad{Name: "a", Members: []string{"gastown/polecats/nux"}}
+		reg.Squads["b"] = &Squad{Name: "b", Members: []string{}}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Assign(townRoot, "gastown/polecats/nux", "b"); err != nil {
+		t.Fatal(err)
+	}
+	reg, _ := Load(townRoot)
+	if reg.Squads["a"].Matches("gastown/polecats/nux") || !reg.Squads["b"].Matches("gastown/polecats/nux") {
+		t.Errorf("assign should move the member from a to b: %+v %+v", reg.Squads["a"], reg.Squads["b"])
+	}
+
+	if err := Assign(townRoot, "gastown/polecats/nux", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("assign to missing squad = %v, want ErrNotFound", err)
+	}
+
+	if err := Assign(townRoot, "gastown/polecats/nux", ""); err != nil {
+		t.Fatal(err)
+	}
+	reg, _ = Load(townRoot)
+	if reg.Squads["b"].Matches("gastown/polecats/nux") {
+		t.Error("empty assign should leave every squad")
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, ok := range []string{"search", "q3-launch", "team_2"} {
+		if err := ValidateName(ok); err != nil {
+			t.Errorf("ValidateName(%q) = %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"", "Search", "-x", "a/b"} {
+		if ValidateName(bad) == nil {
+			t.Errorf("ValidateName(%q) should fail", bad)
+		}
+	}
+}
@@ -2,7 +2,6 @@
 package style
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -27,19 +26,21 @@ const (
 
 // Table provides styled table rendering.
 type Table struct {
-	columns    []Column
-	rows       [][]string
-	headerSep  bool
-	indent     string
+	columns     []Column
+	rows        [][]string
+	headerSep   bool
+	indent      string
+	maxWidth    int
 	headerStyle lipgloss.Style
 }
 
 // NewTable creates a new table with the given columns.
 func NewTable(columns ...Column) *Table {
 	return &Table{
-		columns:    columns,
-		headerSep:  true,
-		indent:     "  ",
+		columns:     columns,
+		headerSep:   true,
+		indent:      "  ",
+		maxWidth:    TerminalWidth(),
 		headerStyle: Bold,
 	}
 }
@@ -56,6 +57,14 @@ func (t *Table) SetHeaderSeparator(enabled bool) *Table {
 	return t
 }
 
+// SetMaxWidth sets the width the table must fit in, including the indent.
+// Columns wider than they need to be are shrunk (widest first) until the
+// table fits; 0 disables fitting. Defaults to the terminal width.
+func (t *Table) SetMaxWidth(width int) *Table {
+	t.maxWidth = width
+	return t
+}
+
 // AddRow adds a row of values to the table.
 func (t *Table) AddRow(values ...string) *Table {
 	// Pad with empty strings if needed
@@ -72,13 +81,14 @@ func (t *Table) Render() string {
 		return ""
 	}
 
+	widths := t.columnWidths()
 	var sb strings.Builder
 
 	// Render header
 	sb.WriteString(t.indent)
 	for i, col := range t.columns {
-		text := t.headerStyle.Render(col.Name)
-		sb.WriteString(t.pad(text, col.Name, col.Width, col.Align))
+		text := t.headerStyle.Render(Truncate(col.Name, widths[i]))
+		sb.WriteString(t.pad(text, widths[i], col.Align))
 		if i < len(t.columns)-1 {
 			sb.WriteString(" ")
 		}
@@ -88,14 +98,7 @@ func (t *Table) Render() string {
 	// Render separator
 	if t.headerSep {
 		sb.WriteString(t.indent)
-		totalWidth := 0
-		for i, col := range t.columns {
-			totalWidth += col.Width
-			if i < len(t.columns)-1 {
-				totalWidth++ // space between columns
-			}
-		}
-		sb.WriteString(Dim.Render(strings.Repeat("─", totalWidth)))
+		sb.WriteString(Dim.Render(strings.Repeat("─", sumWidths(widths))))
 		sb.WriteString("\n")
 	}
 
@@ -108,15 +111,12 @@ func (t *Table) Render() string {
 				val = row[i]
 			}
 			// Truncate if too long
-			plainVal := stripAnsi(val)
-			if len(plainVal) > col.Width {
-				val = plainVal[:col.Width-3] + "..."
-			}
+			val = Truncate(val, widths[i])
 			// Apply column style if set
 			if col.Style.Value() != "" {
 				val = col.Style.Render(val)
 			}
-			sb.WriteString(t.pad(val, plainVal, col.Width, col.Align))
+			sb.WriteString(t.pad(val, widths[i], col.Align))
 			if i < len(t.columns)-1 {
 				sb.WriteString(" ")
 			}
@@ -127,33 +127,58 @@ func (t *Table) Render() string {
 	return sb.String()
 }
 
-// pad pads text to width, accounting for ANSI escape sequences.
-// styledText is the text with ANSI codes, plainText is without.
-func (t *Table) pad(styledText, plainText string, width int, align Alignment) string {
-	plainLen := len(plainText)
-	if plainLen >= width {
-		return styledText
+// columnWidths returns the column widths to render with: the configured
+// widths, shrunk widest-first when the table would overflow maxWidth.
+func (t *Table) columnWidths() []int {
+	widths := make([]int, len(t.columns))
+	for i, col := range t.columns {
+		widths[i] = col.Width
+	}
+	if t.maxWidth <= 0 {
+		return widths
+	}
+	for Width(t.indent)+sumWidths(widths) > t.maxWidth {
+		widest := -1
+		for i, w := range widths {
+			if w > minColumnWidth && (widest < 0 || w > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break // Every column is at its minimum; let the line wrap
+		}
+		widths[widest]--
+	}
+	return widths
+}
+
+// sumWidths returns the total width of columns separated by single spaces.
+func sumWidths(widths []int) int {
+	total := len(widths) - 1
+	for _, w := range widths {
+		total += w
+	}
+	return total
+}
+
+// pad pads text to width display columns, accounting for ANSI escape
+// sequences and wide characters.
+func (t *Table) pad(text string, width int, align Alignment) string {
+	textWidth := Width(text)
+	if textWidth >= width {
+		return text
 	}
 
-	padding := width - plainLen
+	padding := width - textWidth
 
 	switch align {
 	case AlignRight:
-		return strings.Repeat(" ", padding) + styledText
+		return strings.Repeat(" ", padding) + text
 	case AlignCenter:
 		left := padding / 2
 		right := padding - left
-		return strings.Repeat(" ", left) + styledText + strings.Repeat(" ", right)
+		return strings.Repeat(" ", left) + text + strings.Repeat(" ", right)
 	default: // AlignLeft
-		return styledText + strings.Repeat(" ", padding)
+		return text + strings.Repeat(" ", padding)
 	}
 }
-
-// ansiRegex matches CSI escape sequences: ESC [ <params> <final byte>
-var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
-
-// stripAnsi removes ANSI escape sequences from a string.
-func stripAnsi(s string) string {
-	return ansiRegex.ReplaceAllString(s, "")
-}
-
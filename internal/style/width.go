@@ -0,0 +1,87 @@
+package style
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"golang.org/x/term"
+)
+
+// Ellipsis marks truncated text. Plain ASCII: "…" is ambiguous-width and
+// renders two columns wide in many CJK terminal setups.
+const Ellipsis = "..."
+
+// minColumnWidth is the narrowest a table column shrinks to when fitting a
+// narrow terminal.
+const minColumnWidth = 6
+
+// defaultRuleWidth is the width of a Rule when the terminal width is unknown.
+const defaultRuleWidth = 60
+
+// TerminalWidth returns stdout's width in columns, or 0 when stdout isn't a
+// terminal (piped output is never truncated). A positive COLUMNS overrides
+// the detected width.
+func TerminalWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	fd := int(os.Stdout.Fd())
+	if !term.IsTerminal(fd) {
+		return 0
+	}
+	width, _, err := term.GetSize(fd)
+	if err != nil || width <= 0 {
+		return 0
+	}
+	return width
+}
+
+// Width returns the number of terminal columns s occupies, ignoring ANSI
+// escapes and counting wide characters and emoji as two columns.
+func Width(s string) int {
+	return ansi.StringWidth(s)
+}
+
+// Truncate shortens s to at most width columns, ending it with Ellipsis
+// when cut. ANSI styling is preserved and wide characters are never split.
+// A width of 0 or less means no limit.
+func Truncate(s string, width int) string {
+	if width <= 0 || Width(s) <= width {
+		return s
+	}
+	if width <= len(Ellipsis) {
+		return ansi.Truncate(s, width, "")
+	}
+	return ansi.Truncate(s, width, Ellipsis)
+}
+
+// FitLine truncates a single line of output to the terminal width.
+func FitLine(s string) string {
+	return Truncate(s, TerminalWidth())
+}
+
+// PadRight pads s with spaces to width columns. Longer strings are returned
+// unchanged.
+func PadRight(s string, width int) string {
+	if w := Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+// Rule renders a section header like "─── title ─────", filled to
+// defaultRuleWidth columns or the terminal width, whichever is narrower.
+func Rule(title string) string {
+	width := defaultRuleWidth
+	if tw := TerminalWidth(); tw > 0 && tw < width {
+		width = tw
+	}
+	head := "─── " + title + " "
+	fill := width - Width(head)
+	if fill < 3 {
+		fill = 3
+	}
+	return head + strings.Repeat("─", fill)
+}
@@ -0,0 +1,96 @@
+package style
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"abc", 3},
+		{"日本語", 6},
+		{"📬3", 3},
+		{Bold.Render("abc"), 3},
+		{"\x1b[31mred\x1b[0m", 3},
+	}
+	for _, tt := range tests {
+		if got := Width(tt.in); got != tt.want {
+			t.Errorf("Width(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"exactly10!", 10, "exactly10!"},
+		{"a longer title here", 10, "a longe..."},
+		{"日本語のタイトル", 8, "日本..."},
+		{"abcdef", 2, "ab"},
+		{"anything", 0, "anything"},
+	}
+	for _, tt := range tests {
+		got := Truncate(tt.in, tt.width)
+		if got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+		if tt.width > 0 && Width(got) > tt.width {
+			t.Errorf("Truncate(%q, %d) is %d columns wide", tt.in, tt.width, Width(got))
+		}
+	}
+
+	// Styling survives truncation.
+	styled := "\x1b[31mabcdefghijkl\x1b[0m"
+	if got := Truncate(styled, 8); !strings.HasPrefix(got, "\x1b[31m") || Width(got) != 8 {
+		t.Errorf("Truncate(styled) = %q", got)
+	}
+}
+
+func TestPadRight(t *testing.T) {
+	if got := PadRight("日本", 6); got != "日本  " {
+		t.Errorf("PadRight = %q", got)
+	}
+	if got := PadRight("toolong", 3); got != "toolong" {
+		t.Errorf("PadRight should not truncate: %q", got)
+	}
+}
+
+func TestRule(t *testing.T) {
+	t.Setenv("COLUMNS", "30")
+	rule := Rule("gastown/")
+	if !strings.HasPrefix(rule, "─── gastown/ ─") || Width(rule) != 30 {
+		t.Errorf("Rule = %q (%d columns), want 30 columns", rule, Width(rule))
+	}
+}
+
+func TestTable_FitsMaxWidth(t *testing.T) {
+	table := NewTable(
+		Column{Name: "ID", Width: 12},
+		Column{Name: "TITLE", Width: 40},
+		Column{Name: "AGE", Width: 6, Align: AlignRight},
+	).SetMaxWidth(40)
+	table.AddRow("gt-abc123", "修正: handle wide characters in the merge queue", "5m")
+
+	for _, line := range strings.Split(strings.TrimRight(table.Render(), "\n"), "\n") {
+		if w := Width(line); w > 40 {
+			t.Errorf("line is %d columns, want <= 40: %q", w, line)
+		}
+	}
+}
+
+func TestTable_PadsWideCharacters(t *testing.T) {
+	table := NewTable(Column{Name: "NAME", Width: 8}, Column{Name: "X", Width: 1}).SetMaxWidth(0)
+	table.AddRow("日本", "x")
+	table.AddRow("ab", "y")
+	lines := strings.Split(strings.TrimRight(table.Render(), "\n"), "\n")
+	if Width(lines[2]) != Width(lines[3]) {
+		t.Errorf("rows misaligned:\n%s\n%s", lines[2], lines[3])
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"embed"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -136,6 +137,36 @@ func New() (*Templates, error) {
 	return t, nil
 }
 
+// NewWithOverrides creates a Templates instance whose built-in templates
+// are overridden by any roles/*.md.tmpl and messages/*.md.tmpl files in dir
+// (a prompt pack). Templates the pack doesn't provide stay built in.
+func NewWithOverrides(dir string) (*Templates, error) {
+	t, err := New()
+	if err != nil {
+		return nil, err
+	}
+	if t.roleTemplates, err = parseOverrides(t.roleTemplates, filepath.Join(dir, "roles")); err != nil {
+		return nil, err
+	}
+	if t.messageTemplates, err = parseOverrides(t.messageTemplates, filepath.Join(dir, "messages")); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// parseOverrides redefines templates in set from the *.md.tmpl files in dir.
+func parseOverrides(set *template.Template, dir string) (*template.Template, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.md.tmpl"))
+	if err != nil || len(files) == 0 {
+		return set, err
+	}
+	set, err = set.ParseFiles(files...)
+	if err != nil {
+		return nil, fmt.Errorf("parsing prompt pack templates in %s: %w", dir, err)
+	}
+	return set, nil
+}
+
 // RenderRole renders a role context template.
 func (t *Templates) RenderRole(role string, data RoleData) (string, error) {
 	templateName := role + ".md.tmpl"
@@ -165,6 +196,13 @@ func (t *Templates) RoleNames() []string {
 	return []string{"mayor", "witness", "refinery", "polecat", "crew", "deacon", "boot"}
 }
 
+// HasRoleTemplate reports whether a role prompt template file (e.g.
+// "polecat.md.tmpl") is built in, for validating prompt_template overrides.
+func HasRoleTemplate(file string) bool {
+	_, err := fs.Stat(templateFS, "roles/"+file)
+	return err == nil
+}
+
 // MessageNames returns the list of available message templates.
 func (t *Templates) MessageNames() []string {
 	return []string{"spawn", "nudge", "escalation", "handoff"}
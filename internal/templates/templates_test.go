@@ -248,3 +248,14 @@ func TestRoleNames(t *testing.T) {
 	}
 }
 
+
+func TestHasRoleTemplate(t *testing.T) {
+	if !HasRoleTemplate("polecat.md.tmpl") {
+		t.Error("polecat.md.tmpl should be built in")
+	}
+	for _, name := range []string{"polecat-v2.md.tmpl", "../messages/spawn.md.tmpl", ""} {
+		if HasRoleTemplate(name) {
+			t.Errorf("HasRoleTemplate(%q) = true, want false", name)
+		}
+	}
+}
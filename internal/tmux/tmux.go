@@ -1669,7 +1669,8 @@ func (t *Tmux) WaitForShellReady(session string, timeout time.Duration) error {
 }
 
 // WaitForRuntimeReady polls until the runtime's prompt indicator appears in the pane.
-// Runtime is ready when we see the configured prompt prefix at the start of a line.
+// Runtime is ready when a line matches the configured ready pattern or, without
+// one, starts with the configured prompt prefix.
 //
 // IMPORTANT: Bootstrap vs Steady-State Observation
 //
@@ -1714,6 +1715,14 @@ func (t *Tmux) WaitForRuntimeReady(session string, rc *config.RuntimeConfig, tim
 		return nil
 	}
 
+	if rc.Tmux.ReadyPattern != "" {
+		re, err := regexp.Compile(rc.Tmux.ReadyPattern)
+		if err != nil {
+			return fmt.Errorf("invalid ready_pattern %q: %w", rc.Tmux.ReadyPattern, err)
+		}
+		return t.waitForPaneLine(session, timeout, re.MatchString)
+	}
+
 	if rc.Tmux.ReadyPromptPrefix == "" {
 		if rc.Tmux.ReadyDelayMs <= 0 {
 			return nil
@@ -1727,6 +1736,15 @@ func (t *Tmux) WaitForRuntimeReady(session string, rc *config.RuntimeConfig, tim
 		return nil
 	}
 
+	// Look for runtime prompt indicator at start of line
+	return t.waitForPaneLine(session, timeout, func(line string) bool {
+		return matchesPromptPrefix(line, rc.Tmux.ReadyPromptPrefix)
+	})
+}
+
+// waitForPaneLine polls the last lines of the pane until one satisfies
+// ready or the timeout expires.
+func (t *Tmux) waitForPaneLine(session string, timeout time.Duration, ready func(line string) bool) error {
 	deadline := time.Now().Add(timeout)
 	for time.Now().Before(deadline) {
 		// Capture last few lines of the pane
@@ -1735,9 +1753,8 @@ func (t *Tmux) WaitForRuntimeReady(session string, rc *config.RuntimeConfig, tim
 			time.Sleep(200 * time.Millisecond)
 			continue
 		}
-		// Look for runtime prompt indicator at start of line
 		for _, line := range lines {
-			if matchesPromptPrefix(line, rc.Tmux.ReadyPromptPrefix) {
+			if ready(line) {
 				return nil
 			}
 		}
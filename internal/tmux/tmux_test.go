@@ -9,6 +9,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 func hasTmux() bool {
@@ -1837,6 +1839,41 @@ func TestWaitForIdle_Timeout(t *testing.T) {
 	}
 }
 
+func TestWaitForRuntimeReady_Pattern(t *testing.T) {
+	if !hasTmux() {
+		t.Skip("tmux not installed")
+	}
+	if runtime.GOOS != "darwin" && runtime.GOOS != "linux" {
+		t.Skip("test requires unix")
+	}
+
+	tm := NewTmux()
+	sessionName := fmt.Sprintf("gt-test-ready-%d", time.Now().UnixNano())
+	if err := tm.NewSessionWithCommand(sessionName, os.TempDir(), "printf 'loading\\naider> '; sleep 60"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	defer func() { _ = tm.KillSession(sessionName) }()
+
+	// The pattern wins over a prefix that never appears.
+	rc := &config.RuntimeConfig{Tmux: &config.RuntimeTmuxConfig{
+		ReadyPromptPrefix: "❯ ",
+		ReadyPattern:      `^(architect )?aider>`,
+	}}
+	if err := tm.WaitForRuntimeReady(sessionName, rc, 5*time.Second); err != nil {
+		t.Errorf("WaitForRuntimeReady with matching pattern: %v", err)
+	}
+
+	rc.Tmux.ReadyPattern = `^codex>`
+	if err := tm.WaitForRuntimeReady(sessionName, rc, 500*time.Millisecond); err == nil {
+		t.Error("WaitForRuntimeReady should time out when the pattern never matches")
+	}
+
+	rc.Tmux.ReadyPattern = `(`
+	if err := tm.WaitForRuntimeReady(sessionName, rc, time.Second); err == nil || !strings.Contains(err.Error(), "invalid ready_pattern") {
+		t.Errorf("invalid pattern: got %v", err)
+	}
+}
+
 func TestDefaultReadyPromptPrefix(t *testing.T) {
 	// Verify the constant is set correctly
 	if DefaultReadyPromptPrefix == "" {
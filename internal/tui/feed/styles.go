@@ -193,5 +193,8 @@ var (
 		"nudge":   "⚡",
 		"boot":    "🔌",
 		"halt":    "⏹",
+		// Degraded mode window
+		"degraded_enter": "⚠",
+		"degraded_exit":  "✓",
 	}
 )
@@ -431,9 +431,9 @@ func (m *Model) renderEvent(e Event) string {
 		symbolStyle = EventCreateStyle
 	case "update":
 		symbolStyle = EventUpdateStyle
-	case "complete", "patrol_complete", "merged", "done":
+	case "complete", "patrol_complete", "merged", "done", "degraded_exit":
 		symbolStyle = EventCompleteStyle
-	case "fail", "merge_failed":
+	case "fail", "merge_failed", "degraded_enter":
 		symbolStyle = EventFailStyle
 	case "delete":
 		symbolStyle = EventDeleteStyle
@@ -0,0 +1,72 @@
+package pending
+
+import "github.com/charmbracelet/bubbles/key"
+
+// KeyMap defines the key bindings for the pending spawn TUI.
+type KeyMap struct {
+	Up      key.Binding
+	Down    key.Binding
+	Nudge   key.Binding
+	Clear   key.Binding
+	Kill    key.Binding
+	Confirm key.Binding
+	Refresh key.Binding
+	Help    key.Binding
+	Quit    key.Binding
+}
+
+// DefaultKeyMap returns the default key bindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Nudge: key.NewBinding(
+			key.WithKeys("n", "enter"),
+			key.WithHelp("n/enter", "nudge (send Begin.)"),
+		),
+		Clear: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "clear from pending"),
+		),
+		Kill: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "kill session"),
+		),
+		Confirm: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "confirm"),
+		),
+		Refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "refresh now"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "help"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q", "esc", "ctrl+c"),
+			key.WithHelp("q", "quit"),
+		),
+	}
+}
+
+// ShortHelp returns keybindings to show in the help view.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Nudge, k.Clear, k.Kill, k.Quit, k.Help}
+}
+
+// FullHelp returns keybindings for the expanded help view.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Refresh},
+		{k.Nudge, k.Clear, k.Kill, k.Confirm},
+		{k.Help, k.Quit},
+	}
+}
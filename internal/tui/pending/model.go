@@ -0,0 +1,290 @@
+// Package pending provides the interactive triage TUI for pending polecat
+// spawns (gt deacon pending --interactive).
+package pending
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Item is one pending spawn shown in the TUI.
+type Item struct {
+	Session       string
+	Rig           string
+	Polecat       string
+	Issue         string
+	SpawnedAt     time.Time
+	Running       bool
+	Attempts      int
+	QueuePosition int // place among spawns held by trigger limits, from 1
+	Output        []string
+	CaptureError  string
+}
+
+// Source loads pending spawns and acts on them. The TUI calls it from
+// tea commands, off the render path.
+type Source interface {
+	// Load returns the pending spawns with their captured pane output.
+	Load() ([]Item, error)
+
+	// Nudge sends the start trigger to the spawn's session and removes it
+	// from pending.
+	Nudge(Item) error
+
+	// Clear removes the spawn from pending without touching its session.
+	Clear(Item) error
+
+	// Kill kills the spawn's session and removes it from pending.
+	Kill(Item) error
+}
+
+// Model is the bubbletea model for the pending spawn TUI.
+type Model struct {
+	source   Source
+	interval time.Duration
+	now      func() time.Time
+
+	items     []Item
+	cursor    int
+	selected  string // session under the cursor, kept across refreshes
+	loadedAt  time.Time
+	err       error
+	status    string // outcome of the last action
+	statusErr bool
+	confirm   string // session waiting for kill confirmation
+	busy      bool   // an action is running
+
+	// UI state
+	keys     KeyMap
+	help     help.Model
+	showHelp bool
+	width    int
+	height   int
+
+	// mu protects all fields read by View() from concurrent access.
+	// Write lock is held during Update mutations; read lock during View/render.
+	mu sync.RWMutex
+}
+
+// New creates a pending spawn TUI that reloads from source every interval.
+func New(source Source, interval time.Duration) *Model {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	return &Model{
+		source:   source,
+		interval: interval,
+		now:      time.Now,
+		keys:     DefaultKeyMap(),
+		help:     help.New(),
+	}
+}
+
+// loadedMsg is the result of loading pending spawns.
+type loadedMsg struct {
+	items []Item
+	err   error
+}
+
+// tickMsg triggers an auto-refresh.
+type tickMsg time.Time
+
+// actionDoneMsg is the result of a nudge, clear or kill.
+type actionDoneMsg struct {
+	verb    string
+	session string
+	err     error
+}
+
+// Init starts the first load and the refresh timer.
+func (m *Model) Init() tea.Cmd {
+	return tea.Batch(m.load, m.tick())
+}
+
+func (m *Model) load() tea.Msg {
+	items, err := m.source.Load()
+	return loadedMsg{items: items, err: err}
+}
+
+func (m *Model) tick() tea.Cmd {
+	return tea.Tick(m.interval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+// act runs fn on it in the background and reports the outcome.
+func (m *Model) act(verb string, it Item, fn func(Item) error) tea.Cmd {
+	return func() tea.Msg {
+		return actionDoneMsg{verb: verb, session: it.Session, err: fn(it)}
+	}
+}
+
+// Update handles messages.
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.mu.Lock()
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width
+		m.mu.Unlock()
+		return m, nil
+
+	case loadedMsg:
+		m.mu.Lock()
+		m.err = msg.err
+		if msg.err == nil {
+			m.items = msg.items
+			m.loadedAt = m.now()
+			m.restoreCursorLocked()
+		}
+		m.mu.Unlock()
+		return m, nil
+
+	case tickMsg:
+		return m, tea.Batch(m.load, m.tick())
+
+	case actionDoneMsg:
+		m.mu.Lock()
+		m.busy = false
+		if msg.err != nil {
+			m.status, m.statusErr = fmt.Sprintf("%s %s failed: %v", msg.verb, msg.session, msg.err), true
+		} else {
+			m.status, m.statusErr = fmt.Sprintf("%s %s", pastTense(msg.verb), msg.session), false
+		}
+		m.mu.Unlock()
+		return m, m.load
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+
+	return m, nil
+}
+
+func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.confirm != "" {
+		session := m.confirm
+		m.confirm = ""
+		if key.Matches(msg, m.keys.Confirm) {
+			if it, ok := m.currentLocked(); ok && it.Session == session {
+				m.busy = true
+				m.status, m.statusErr = "Killing "+session+"...", false
+				return m, m.act("kill", it, m.source.Kill)
+			}
+		}
+		m.status, m.statusErr = "Kill cancelled", false
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp = !m.showHelp
+
+	case key.Matches(msg, m.keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		m.rememberCursorLocked()
+
+	case key.Matches(msg, m.keys.Down):
+		if m.cursor < len(m.items)-1 {
+			m.cursor++
+		}
+		m.rememberCursorLocked()
+
+	case key.Matches(msg, m.keys.Refresh):
+		return m, m.load
+
+	case key.Matches(msg, m.keys.Nudge):
+		it, ok := m.currentLocked()
+		if !ok || m.busy {
+			return m, nil
+		}
+		if !it.Running {
+			m.status, m.statusErr = it.Session+" is not running; clear it instead", true
+			return m, nil
+		}
+		m.busy = true
+		m.status, m.statusErr = "Nudging "+it.Session+"...", false
+		return m, m.act("nudge", it, m.source.Nudge)
+
+	case key.Matches(msg, m.keys.Clear):
+		it, ok := m.currentLocked()
+		if !ok || m.busy {
+			return m, nil
+		}
+		m.busy = true
+		return m, m.act("clear", it, m.source.Clear)
+
+	case key.Matches(msg, m.keys.Kill):
+		it, ok := m.currentLocked()
+		if !ok || m.busy {
+			return m, nil
+		}
+		m.confirm = it.Session
+		m.status, m.statusErr = "Kill "+it.Session+"? Press y to confirm, any other key to cancel", true
+	}
+	return m, nil
+}
+
+// currentLocked returns the item under the cursor.
+// Caller must hold m.mu.
+func (m *Model) currentLocked() (Item, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.items) {
+		return Item{}, false
+	}
+	return m.items[m.cursor], true
+}
+
+// rememberCursorLocked records which session the cursor is on.
+// Caller must hold m.mu write lock.
+func (m *Model) rememberCursorLocked() {
+	if it, ok := m.currentLocked(); ok {
+		m.selected = it.Session
+	}
+}
+
+// restoreCursorLocked keeps the cursor on the same session after a reload,
+// or clamps it when that session is gone.
+// Caller must hold m.mu write lock.
+func (m *Model) restoreCursorLocked() {
+	for i, it := range m.items {
+		if it.Session == m.selected {
+			m.cursor = i
+			return
+		}
+	}
+	m.cursor = min(m.cursor, len(m.items)-1)
+	m.cursor = max(m.cursor, 0)
+	m.rememberCursorLocked()
+}
+
+func pastTense(verb string) string {
+	switch verb {
+	case "nudge":
+		return "Nudged"
+	case "clear":
+		return "Cleared"
+	case "kill":
+		return "Killed"
+	}
+	return verb
+}
+
+// View renders the model.
+// Acquires read lock to safely access all View-visible fields.
+func (m *Model) View() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.renderView()
+}
@@ -0,0 +1,160 @@
+package pending
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeSource records the actions the TUI takes.
+type fakeSource struct {
+	mu     sync.Mutex
+	items  []Item
+	killed []string
+	nudged []string
+}
+
+func (f *fakeSource) Load() ([]Item, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Item(nil), f.items...), nil
+}
+
+func (f *fakeSource) Nudge(it Item) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nudged = append(f.nudged, it.Session)
+	return nil
+}
+
+func (f *fakeSource) Clear(Item) error { return nil }
+
+func (f *fakeSource) Kill(it Item) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.killed = append(f.killed, it.Session)
+	return nil
+}
+
+func keyRunes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// loaded feeds m a load of src's current items.
+func loaded(t *testing.T, m *Model, src *fakeSource) {
+	t.Helper()
+	items, err := src.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Update(loadedMsg{items: items})
+}
+
+func testItems() []Item {
+	now := time.Now()
+	return []Item{
+		{Session: "gt-nux", Rig: "gastown", Polecat: "nux", SpawnedAt: now, Running: true, Output: []string{"ready>"}},
+		{Session: "gt-slit", Rig: "gastown", Polecat: "slit", SpawnedAt: now, Running: false},
+	}
+}
+
+func TestKillNeedsConfirmation(t *testing.T) {
+	src := &fakeSource{items: testItems()}
+	m := New(src, time.Second)
+	loaded(t, m, src)
+
+	// Any key other than y cancels.
+	m.Update(keyRunes("x"))
+	if _, cmd := m.Update(keyRunes("j")); cmd != nil {
+		t.Fatal("cancelled kill should not run an action")
+	}
+	if m.cursor != 0 || !strings.Contains(m.status, "cancelled") {
+		t.Errorf("cursor = %d, status = %q; want the key consumed by the cancel", m.cursor, m.status)
+	}
+
+	m.Update(keyRunes("x"))
+	_, cmd := m.Update(keyRunes("y"))
+	if cmd == nil {
+		t.Fatal("confirmed kill should run an action")
+	}
+	msg := cmd()
+	if done, ok := msg.(actionDoneMsg); !ok || done.verb != "kill" || done.err != nil {
+		t.Fatalf("action result = %#v", msg)
+	}
+	if len(src.killed) != 1 || src.killed[0] != "gt-nux" {
+		t.Errorf("killed = %v, want [gt-nux]", src.killed)
+	}
+}
+
+func TestNudgeRefusedWhenNotRunning(t *testing.T) {
+	src := &fakeSource{items: testItems()}
+	m := New(src, time.Second)
+	loaded(t, m, src)
+
+	m.Update(keyRunes("j"))
+	if _, cmd := m.Update(keyRunes("n")); cmd != nil {
+		t.Fatal("nudging a dead session should not run an action")
+	}
+	if !m.statusErr || !strings.Contains(m.status, "not running") {
+		t.Errorf("status = %q", m.status)
+	}
+
+	m.Update(keyRunes("k"))
+	_, cmd := m.Update(keyRunes("n"))
+	if cmd == nil {
+		t.Fatal("nudging a running session should run an action")
+	}
+	cmd()
+	if len(src.nudged) != 1 || src.nudged[0] != "gt-nux" {
+		t.Errorf("nudged = %v, want [gt-nux]", src.nudged)
+	}
+}
+
+func TestCursorFollowsSessionAcrossReloads(t *testing.T) {
+	src := &fakeSource{items: testItems()}
+	m := New(src, time.Second)
+	loaded(t, m, src)
+	m.Update(keyRunes("j"))
+
+	// A new spawn ahead of the selected one keeps the cursor on gt-slit.
+	src.items = append([]Item{{Session: "gt-toast", Rig: "gastown", Polecat: "toast"}}, src.items...)
+	loaded(t, m, src)
+	if it, _ := m.currentLocked(); it.Session != "gt-slit" {
+		t.Errorf("cursor on %q, want gt-slit", it.Session)
+	}
+
+	// When the selected spawn goes, the cursor stays in range.
+	src.items = src.items[:2]
+	loaded(t, m, src)
+	if m.cursor != 1 {
+		t.Errorf("cursor = %d, want clamped to 1", m.cursor)
+	}
+}
+
+// TestItemsWriteConcurrentWithView verifies that reloads concurrent with
+// View() do not race.
+func TestItemsWriteConcurrentWithView(t *testing.T) {
+	src := &fakeSource{items: testItems()}
+	m := New(src, time.Second)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			m.Update(loadedMsg{items: testItems()})
+			m.Update(keyRunes("j"))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = m.View()
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,173 @@
+package pending
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Styles for the pending spawn TUI
+var (
+	titleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("12"))
+
+	selectedStyle = lipgloss.NewStyle().
+			Background(lipgloss.Color("236")).
+			Foreground(lipgloss.Color("15"))
+
+	rowStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("15"))
+
+	dimStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8")) // gray
+
+	warnStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")) // yellow
+
+	okStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("10")) // green
+
+	errorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9")) // red
+)
+
+// minOutputLines is the smallest output pane shown, however many spawns
+// are listed.
+const minOutputLines = 5
+
+// renderView renders the entire view.
+// Caller must hold m.mu.
+func (m *Model) renderView() string {
+	var b strings.Builder
+
+	title := fmt.Sprintf("Pending spawns (%d)", len(m.items))
+	b.WriteString(titleStyle.Render(title))
+	if !m.loadedAt.IsZero() {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("  refreshed %s, every %s",
+			m.loadedAt.Format("15:04:05"), m.interval)))
+	}
+	b.WriteString("\n\n")
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		b.WriteString("\n\n")
+	}
+
+	if len(m.items) == 0 && m.err == nil {
+		b.WriteString("No pending spawns.\n")
+	}
+
+	now := m.now()
+	for i, it := range m.items {
+		line := m.fit(fmt.Sprintf("%d. %s/%s  %s  %s  %s", i+1, it.Rig, it.Polecat, it.Session,
+			formatAge(now.Sub(it.SpawnedAt)), itemState(it)))
+		if i == m.cursor {
+			b.WriteString(selectedStyle.Render(line))
+		} else {
+			b.WriteString(rowStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	if it, ok := m.currentLocked(); ok {
+		b.WriteString("\n")
+		header := "── " + it.Session + " "
+		if it.Issue != "" {
+			header += "(" + it.Issue + ") "
+		}
+		b.WriteString(dimStyle.Render(m.fit(header + strings.Repeat("─", max(0, m.width-len([]rune(header)))))))
+		b.WriteString("\n")
+		for _, line := range m.outputLines(it) {
+			b.WriteString(m.fit(line))
+			b.WriteString("\n")
+		}
+	}
+
+	// Status and help footer
+	b.WriteString("\n")
+	if m.status != "" {
+		if m.statusErr {
+			b.WriteString(warnStyle.Render(m.status))
+		} else {
+			b.WriteString(okStyle.Render(m.status))
+		}
+		b.WriteString("\n")
+	}
+	if m.showHelp {
+		b.WriteString(m.help.View(m.keys))
+	} else {
+		b.WriteString(dimStyle.Render("j/k:select  n:nudge  c:clear  x:kill  r:refresh  q:quit  ?:help"))
+	}
+
+	return b.String()
+}
+
+// outputLines returns the tail of the spawn's captured output that fits
+// under the list, or a placeholder.
+// Caller must hold m.mu.
+func (m *Model) outputLines(it Item) []string {
+	switch {
+	case !it.Running:
+		return []string{warnStyle.Render("session not running")}
+	case it.CaptureError != "":
+		return []string{dimStyle.Render("capture failed: " + it.CaptureError)}
+	case len(it.Output) == 0:
+		return []string{dimStyle.Render("(no output)")}
+	}
+	// Title, blank, rows, blank, pane header, then status and help.
+	room := m.height - (2 + len(m.items) + 2 + 3)
+	if m.err != nil {
+		room -= 2
+	}
+	room = max(room, minOutputLines)
+	if m.height == 0 {
+		room = len(it.Output)
+	}
+	lines := it.Output
+	if len(lines) > room {
+		lines = lines[len(lines)-room:]
+	}
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = "│ " + l
+	}
+	return out
+}
+
+// fit truncates s to the terminal width.
+// Caller must hold m.mu.
+func (m *Model) fit(s string) string {
+	if m.width <= 0 {
+		return s
+	}
+	return ansi.Truncate(s, m.width, "…")
+}
+
+// itemState describes why a spawn is still pending.
+func itemState(it Item) string {
+	switch {
+	case !it.Running:
+		return warnStyle.Render("not running")
+	case it.QueuePosition > 0:
+		return dimStyle.Render(fmt.Sprintf("held, queue #%d", it.QueuePosition))
+	case it.Attempts > 0:
+		return dimStyle.Render(fmt.Sprintf("%d failed check(s)", it.Attempts))
+	}
+	return dimStyle.Render("waiting")
+}
+
+// formatAge renders a spawn's age compactly.
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
@@ -2,8 +2,26 @@
 
 package util
 
-import "os/exec"
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
 
-// SetProcessGroup is a no-op on Windows.
-// Process group management is not supported on Windows.
-func SetProcessGroup(cmd *exec.Cmd) {}
+	"golang.org/x/sys/windows"
+)
+
+// SetProcessGroup configures a command to run in its own process group so that
+// context cancellation kills the entire process tree (via taskkill /T),
+// preventing orphaned children.
+func SetProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: windows.CREATE_NEW_PROCESS_GROUP}
+	cmd.Cancel = func() error {
+		if cmd.Process == nil {
+			return nil
+		}
+		if err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run(); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+}
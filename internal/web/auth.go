@@ -0,0 +1,202 @@
+package web
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Dashboard auth scopes. Each endpoint requires one; see RequiredScope.
+const (
+	ScopeRead  = "read"  // Dashboard pages, static assets, read-only API
+	ScopeWrite = "write" // Mail send, issue create/close/update
+	ScopeRun   = "run"   // /api/run: arbitrary gt commands
+	ScopeAll   = "*"
+)
+
+// ValidScopes lists the scopes a credential may be granted.
+var ValidScopes = []string{ScopeRead, ScopeWrite, ScopeRun, ScopeAll}
+
+// authCookie carries a token handed over in a ?token= link, so the browser
+// (and EventSource, which can't set headers) stays authenticated.
+const authCookie = "gt_dashboard_token"
+
+// writeEndpoints are the API paths that change town state.
+var writeEndpoints = map[string]bool{
+	"/api/mail/send":     true,
+	"/api/issues/create": true,
+	"/api/issues/close":  true,
+	"/api/issues/update": true,
+}
+
+// RequiredScope returns the scope a request needs.
+func RequiredScope(r *http.Request) string {
+	switch {
+	case r.URL.Path == "/api/run":
+		return ScopeRun
+	case writeEndpoints[r.URL.Path]:
+		return ScopeWrite
+	default:
+		return ScopeRead
+	}
+}
+
+// AuthEnabled reports whether cfg has any credential configured.
+func AuthEnabled(cfg *config.WebAuthConfig) bool {
+	return cfg != nil && (len(cfg.Tokens) > 0 || cfg.ClientCAFile != "")
+}
+
+// HashToken returns the hex SHA-256 of a token, as stored in settings.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateToken returns a new random dashboard token.
+func GenerateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "gtd_" + hex.EncodeToString(b), nil
+}
+
+// ValidateScopes checks that every scope is known.
+func ValidateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("at least one scope is required (valid: %s)", strings.Join(ValidScopes, ", "))
+	}
+	for _, s := range scopes {
+		if !hasScope(ValidScopes, s) {
+			return fmt.Errorf("unknown scope %q (valid: %s)", s, strings.Join(ValidScopes, ", "))
+		}
+	}
+	return nil
+}
+
+// NewAuthHandler wraps next so every request must present a credential with
+// the scope its endpoint requires: a verified client certificate (mTLS), or
+// a bearer token in the Authorization header, the dashboard cookie, or a
+// ?token= query parameter. A nil or empty cfg passes requests through.
+func NewAuthHandler(cfg *config.WebAuthConfig, next http.Handler) http.Handler {
+	if !AuthEnabled(cfg) {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scopes, fromQuery, ok := authenticate(cfg, r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gastown"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		need := RequiredScope(r)
+		if !hasScope(scopes, need) && !hasScope(scopes, ScopeAll) {
+			http.Error(w, fmt.Sprintf("Forbidden: requires %q scope", need), http.StatusForbidden)
+			return
+		}
+		if fromQuery != "" {
+			http.SetCookie(w, &http.Cookie{
+				Name:     authCookie,
+				Value:    fromQuery,
+				Path:     "/",
+				HttpOnly: true,
+				Secure:   r.TLS != nil,
+				SameSite: http.SameSiteStrictMode,
+			})
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticate returns the scopes granted to the request's credential. A
+// client certificate wins over a token. fromQuery is set when the token came
+// from the query string, so it can be moved into a cookie.
+func authenticate(cfg *config.WebAuthConfig, r *http.Request) (scopes []string, fromQuery string, ok bool) {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		cn := r.TLS.VerifiedChains[0][0].Subject.CommonName
+		if s, found := cfg.ClientScopes[cn]; found {
+			return s, "", true
+		}
+		if s, found := cfg.ClientScopes["*"]; found {
+			return s, "", true
+		}
+	}
+
+	token, src := requestToken(r)
+	if token == "" {
+		return nil, "", false
+	}
+	hash := HashToken(token)
+	for _, t := range cfg.Tokens {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(strings.ToLower(t.SHA256))) == 1 {
+			if src == "query" {
+				fromQuery = token
+			}
+			return t.Scopes, fromQuery, true
+		}
+	}
+	return nil, "", false
+}
+
+// requestToken extracts a bearer token and where it came from.
+func requestToken(r *http.Request) (token, source string) {
+	if h := r.Header.Get("Authorization"); h != "" {
+		if t, ok := strings.CutPrefix(h, "Bearer "); ok {
+			return strings.TrimSpace(t), "header"
+		}
+	}
+	if c, err := r.Cookie(authCookie); err == nil && c.Value != "" {
+		return c.Value, "cookie"
+	}
+	if t := r.URL.Query().Get("token"); t != "" {
+		return t, "query"
+	}
+	return "", ""
+}
+
+// TLSConfig builds the server TLS config for cfg, or returns nil when the
+// dashboard should serve plain HTTP. With a client CA, client certificates
+// are verified when presented but not required, so token clients still work.
+func TLSConfig(cfg *config.WebAuthConfig) (*tls.Config, error) {
+	if cfg == nil || cfg.TLSCertFile == "" {
+		if cfg != nil && cfg.ClientCAFile != "" {
+			return nil, fmt.Errorf("web_auth.client_ca_file requires tls_cert_file and tls_key_file")
+		}
+		return nil, nil
+	}
+	if cfg.TLSKeyFile == "" {
+		return nil, fmt.Errorf("web_auth.tls_cert_file requires tls_key_file")
+	}
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile) //nolint:gosec // G304: path is from town settings
+		if err != nil {
+			return nil, fmt.Errorf("reading client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("client CA %s: no certificates found", cfg.ClientCAFile)
+		}
+		tlsCfg.ClientCAs = pool
+		tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return tlsCfg, nil
+}
+
+func hasScope(scopes []string, want string) bool {
+	for _, s := range scopes {
+		if s == want {
+			return true
+		}
+	}
+	return false
+}
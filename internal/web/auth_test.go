@@ -0,0 +1,138 @@
+package web
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func authTestHandler(cfg *config.WebAuthConfig) http.Handler {
+	return NewAuthHandler(cfg, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestRequiredScope(t *testing.T) {
+	tests := []struct {
+		method, path, want string
+	}{
+		{http.MethodGet, "/", ScopeRead},
+		{http.MethodGet, "/api/mail/inbox", ScopeRead},
+		{http.MethodPost, "/api/mail/send", ScopeWrite},
+		{http.MethodPost, "/api/issues/close", ScopeWrite},
+		{http.MethodPost, "/api/run", ScopeRun},
+	}
+	for _, tt := range tests {
+		if got := RequiredScope(httptest.NewRequest(tt.method, tt.path, nil)); got != tt.want {
+			t.Errorf("RequiredScope(%s %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAuthHandler_Tokens(t *testing.T) {
+	cfg := &config.WebAuthConfig{Tokens: []config.WebAuthToken{
+		{Name: "viewer", SHA256: HashToken("view-token"), Scopes: []string{ScopeRead}},
+		{Name: "admin", SHA256: HashToken("admin-token"), Scopes: []string{ScopeAll}},
+	}}
+	h := authTestHandler(cfg)
+
+	tests := []struct {
+		name, method, path, token string
+		want                      int
+	}{
+		{"no token", http.MethodGet, "/", "", http.StatusUnauthorized},
+		{"bad token", http.MethodGet, "/", "nope", http.StatusUnauthorized},
+		{"viewer reads", http.MethodGet, "/api/mail/inbox", "view-token", http.StatusOK},
+		{"viewer can't run", http.MethodPost, "/api/run", "view-token", http.StatusForbidden},
+		{"admin runs", http.MethodPost, "/api/run", "admin-token", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestAuthHandler_QueryTokenSetsCookie(t *testing.T) {
+	cfg := &config.WebAuthConfig{Tokens: []config.WebAuthToken{
+		{Name: "viewer", SHA256: HashToken("view-token"), Scopes: []string{ScopeRead}},
+	}}
+	h := authTestHandler(cfg)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?token=view-token", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200", rec.Code)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != authCookie || !cookies[0].HttpOnly {
+		t.Fatalf("expected an HttpOnly auth cookie, got %+v", cookies)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("cookie should authenticate, got %d", rec.Code)
+	}
+}
+
+func TestAuthHandler_ClientCertificate(t *testing.T) {
+	cfg := &config.WebAuthConfig{
+		ClientCAFile: "ca.pem",
+		ClientScopes: map[string][]string{"ops-laptop": {ScopeRead, ScopeRun}, "*": {ScopeRead}},
+	}
+	h := authTestHandler(cfg)
+
+	withCert := func(cn, method, path string) *http.Request {
+		req := httptest.NewRequest(method, path, nil)
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: cn}}
+		req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		return req
+	}
+
+	for _, tt := range []struct {
+		req  *http.Request
+		want int
+	}{
+		{withCert("ops-laptop", http.MethodPost, "/api/run"), http.StatusOK},
+		{withCert("someone", http.MethodGet, "/"), http.StatusOK},
+		{withCert("someone", http.MethodPost, "/api/run"), http.StatusForbidden},
+		{httptest.NewRequest(http.MethodGet, "/", nil), http.StatusUnauthorized},
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, tt.req)
+		if rec.Code != tt.want {
+			t.Errorf("%s %s: got %d, want %d", tt.req.Method, tt.req.URL.Path, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestAuthHandler_DisabledPassesThrough(t *testing.T) {
+	rec := httptest.NewRecorder()
+	authTestHandler(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/run", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("no web_auth should pass through, got %d", rec.Code)
+	}
+}
+
+func TestTLSConfig_ClientCARequiresCert(t *testing.T) {
+	if _, err := TLSConfig(&config.WebAuthConfig{ClientCAFile: "ca.pem"}); err == nil {
+		t.Error("client CA without a server certificate should be an error")
+	}
+	if cfg, err := TLSConfig(&config.WebAuthConfig{}); cfg != nil || err != nil {
+		t.Errorf("no TLS configured: got %v, %v", cfg, err)
+	}
+}
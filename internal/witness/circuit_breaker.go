@@ -0,0 +1,1413 @@
+package witness
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Circuit breaker defaults, used when a rig's settings don't override them.
+const (
+	// DefaultCircuitMaxFailures is how many failures open a polecat's circuit.
+	DefaultCircuitMaxFailures = 3
+
+	// DefaultCircuitCooldown is how long an open circuit stays open before
+	// the polecat may be probed again.
+	DefaultCircuitCooldown = 30 * time.Minute
+
+	// DefaultCircuitMaxCooldown caps the cooldown as it doubles with
+	// repeated trips.
+	DefaultCircuitMaxCooldown = 8 * time.Hour
+
+	// DefaultCircuitMaxBeadFailures is how many non-transient failures mark
+	// a work bead as likely broken.
+	DefaultCircuitMaxBeadFailures = 3
+
+	// DefaultRemediationHookTimeout bounds a remediation hook run.
+	DefaultRemediationHookTimeout = 5 * time.Minute
+
+	// DefaultCircuitMaxActionsPerSweep caps how many open circuits one
+	// sweep acts on.
+	DefaultCircuitMaxActionsPerSweep = 10
+
+	// DefaultCircuitActionPacing is the pause between a sweep's actions.
+	DefaultCircuitActionPacing = 2 * time.Second
+
+	// DefaultCircuitSuccessStreak is how many probes in a row a half-open
+	// polecat must land before its circuit closes.
+	DefaultCircuitSuccessStreak = 2
+
+	// DefaultCircuitProbationPeriod is how long a polecat stays on
+	// probation once its circuit closes again.
+	DefaultCircuitProbationPeriod = 2 * time.Hour
+)
+
+// circuitSweepWorkers bounds how many agent beads a sweep parses, or
+// circuits it opens, at once.
+const circuitSweepWorkers = 8
+
+// CircuitBreakerConfig controls when a polecat's circuit trips.
+//
+// Each polecat's agent bead counts its failures (a session dying with work
+// hooked, a completion that didn't verify). At MaxFailures the circuit
+// opens: the Witness requeues the polecat's work, nukes it if clean and
+// escalates it if not. CooldownPeriod is how long the circuit then stays
+// open before the polecat is trusted with a probe assignment (half_open).
+// Each further trip before the circuit closes again doubles the cooldown, up
+// to MaxCooldownPeriod.
+//
+// Failures are also counted on the work bead the polecat had hooked, so work
+// that fails with one fresh polecat after another is caught even though no
+// single polecat trips. At MaxBeadFailures (transient failures aside) the
+// bead is blocked and escalated as likely broken rather than requeued.
+//
+// RemediationHook, if set, runs on a tripped polecat before it is nuked
+// (see RunRemediationHook) and can veto the nuke.
+//
+// A sweep acts on at most MaxActionsPerSweep open circuits, pausing
+// ActionPacing between them; the rest are deferred to the next sweep.
+//
+// A half-open polecat earns back trust one probe at a time: its circuit
+// closes once SuccessStreak probes in a row have landed, and it then stays
+// on probation for ProbationPeriod, during which its first failure reopens
+// the circuit instead of counting towards MaxFailures.
+type CircuitBreakerConfig struct {
+	MaxFailures            int
+	CooldownPeriod         time.Duration
+	MaxCooldownPeriod      time.Duration
+	MaxBeadFailures        int
+	RemediationHook        string
+	RemediationHookTimeout time.Duration
+	MaxActionsPerSweep     int
+	ActionPacing           time.Duration
+	SuccessStreak          int
+	ProbationPeriod        time.Duration
+}
+
+// DefaultCircuitBreakerConfig returns the built-in breaker settings.
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		MaxFailures:            DefaultCircuitMaxFailures,
+		CooldownPeriod:         DefaultCircuitCooldown,
+		MaxCooldownPeriod:      DefaultCircuitMaxCooldown,
+		MaxBeadFailures:        DefaultCircuitMaxBeadFailures,
+		RemediationHookTimeout: DefaultRemediationHookTimeout,
+		MaxActionsPerSweep:     DefaultCircuitMaxActionsPerSweep,
+		ActionPacing:           DefaultCircuitActionPacing,
+		SuccessStreak:          DefaultCircuitSuccessStreak,
+		ProbationPeriod:        DefaultCircuitProbationPeriod,
+	}
+}
+
+// CooldownFor returns the cooldown after a polecat's trips-th trip:
+// CooldownPeriod * 2^(trips-1), capped at MaxCooldownPeriod. The cap never
+// shortens the base cooldown.
+func (c CircuitBreakerConfig) CooldownFor(trips int) time.Duration {
+	cooldown := c.CooldownPeriod
+	for i := 1; i < trips && cooldown < c.MaxCooldownPeriod; i++ {
+		cooldown *= 2
+	}
+	if cooldown > c.MaxCooldownPeriod && c.MaxCooldownPeriod >= c.CooldownPeriod {
+		cooldown = c.MaxCooldownPeriod
+	}
+	return cooldown
+}
+
+// CircuitBreakerConfigFrom applies a rig's circuit_breaker settings over the
+// defaults. Nil settings and zero values keep the defaults.
+func CircuitBreakerConfigFrom(s *config.CircuitBreakerSettings) (CircuitBreakerConfig, error) {
+	cfg := DefaultCircuitBreakerConfig()
+	if s == nil {
+		return cfg, nil
+	}
+	if err := config.ValidateCircuitBreakerSettings(s); err != nil {
+		return cfg, err
+	}
+	if s.MaxFailures > 0 {
+		cfg.MaxFailures = s.MaxFailures
+	}
+	if s.CooldownPeriod != "" {
+		cfg.CooldownPeriod, _ = time.ParseDuration(s.CooldownPeriod)
+	}
+	if s.MaxCooldownPeriod != "" {
+		cfg.MaxCooldownPeriod, _ = time.ParseDuration(s.MaxCooldownPeriod)
+	}
+	if s.MaxBeadFailures > 0 {
+		cfg.MaxBeadFailures = s.MaxBeadFailures
+	}
+	cfg.RemediationHook = strings.TrimSpace(s.RemediationHook)
+	if s.RemediationHookTimeout != "" {
+		cfg.RemediationHookTimeout, _ = time.ParseDuration(s.RemediationHookTimeout)
+	}
+	if s.MaxActionsPerSweep > 0 {
+		cfg.MaxActionsPerSweep = s.MaxActionsPerSweep
+	}
+	if s.ActionPacing != "" {
+		cfg.ActionPacing, _ = time.ParseDuration(s.ActionPacing)
+	}
+	if s.SuccessStreak > 0 {
+		cfg.SuccessStreak = s.SuccessStreak
+	}
+	if s.ProbationPeriod != "" {
+		cfg.ProbationPeriod, _ = time.ParseDuration(s.ProbationPeriod)
+	}
+	return cfg, nil
+}
+
+// LoadCircuitBreakerConfig reads the breaker settings from the rig's
+// settings/config.json ("witness.circuit_breaker"). A rig without a
+// settings file gets the defaults; an invalid file is an error, so a typo
+// doesn't silently fall back.
+func LoadCircuitBreakerConfig(rigPath string) (CircuitBreakerConfig, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return DefaultCircuitBreakerConfig(), nil
+		}
+		return DefaultCircuitBreakerConfig(), err
+	}
+	var s *config.CircuitBreakerSettings
+	if settings.Witness != nil {
+		s = settings.Witness.CircuitBreaker
+	}
+	return CircuitBreakerConfigFrom(s)
+}
+
+// circuitConfigForRig loads the rig's breaker settings from a work
+// directory inside the town, falling back to the defaults when they can't
+// be read.
+func circuitConfigForRig(workDir, rigName string) CircuitBreakerConfig {
+	townRoot, err := workspace.Find(workDir)
+	if err != nil || townRoot == "" {
+		return DefaultCircuitBreakerConfig()
+	}
+	cfg, err := LoadCircuitBreakerConfig(filepath.Join(townRoot, rigName))
+	if err != nil {
+		return DefaultCircuitBreakerConfig()
+	}
+	return cfg
+}
+
+// circuitBeads is the subset of beads operations the breaker needs.
+type circuitBeads interface {
+	ListAgentBeadsWithField(key, value string) (map[string]*beads.Issue, error)
+	UpdateAgentFields(id string, expectedRevision int64, modify func(*beads.AgentFields) error) (*beads.AgentFields, error)
+	OpenAgentCircuit(id string) (*beads.AgentFields, error)
+	ResetAgentFailureCount(id string) error
+	ClearHookBead(id string) error
+	RecordWorkFailure(id, polecat, reason string) (*beads.WorkFailureFields, error)
+	WorkFailures(id string) (*beads.WorkFailureFields, error)
+	ClaimRequeue(id, key string) (*beads.RequeueFields, bool, error)
+	SetAgentNukeVeto(id, reason string) error
+	SetAgentQuarantine(id, reason string, at time.Time) error
+	RecordAgentSuccess(id string, streak int, probationUntil time.Time) (*beads.AgentFields, error)
+}
+
+// rigBeads returns the beads store holding the rig's agent beads.
+func rigBeads(workDir, rigName string) (*beads.Beads, string) {
+	townRoot := findTownRoot(workDir)
+	rigPath := filepath.Join(townRoot, rigName)
+	return beads.NewWithBeadsDir(rigPath, beads.ResolveBeadsDir(rigPath)).WithSubsystem("witness"), townRoot
+}
+
+// findTownRoot returns the town containing workDir, or workDir itself if it
+// isn't in one.
+func findTownRoot(workDir string) string {
+	townRoot, err := workspace.Find(workDir)
+	if err != nil || townRoot == "" {
+		return workDir
+	}
+	return townRoot
+}
+
+// polecatAgentBeadID returns the agent bead ID for a polecat in rigName.
+func polecatAgentBeadID(townRoot, rigName, polecatName string) string {
+	return beads.PolecatBeadIDWithPrefix(beads.GetPrefixForRig(townRoot, rigName), rigName, polecatName)
+}
+
+// transientFailureMarkers are substrings (lowercase) of session output that
+// mark a failure as transient: the provider or network was at fault, not the
+// work.
+var transientFailureMarkers = []string{
+	"rate limit",
+	"rate_limit",
+	"too many requests",
+	"api error: 429",
+	"api error: 503",
+	"api error: 529",
+	"overloaded",
+	"service unavailable",
+	"timed out",
+	"timeout",
+	"connection reset",
+	"econnreset",
+	"connection refused",
+	"network error",
+	"socket hang up",
+}
+
+// ClassifyFailure classifies a failed session from the tail of its output.
+// Output naming a rate limit or network fault is transient; anything else
+// is unknown, since a crash alone says nothing about the work.
+func ClassifyFailure(output string) string {
+	lower := strings.ToLower(output)
+	for _, marker := range transientFailureMarkers {
+		if strings.Contains(lower, marker) {
+			return beads.FailureTransient
+		}
+	}
+	return beads.FailureUnknown
+}
+
+// circuitFailureScore is the failure count the breaker compares against
+// max_failures. Transient failures weigh half as much as the rest, so a
+// polecat caught in a rate limit storm trips later than one whose work
+// keeps failing.
+func circuitFailureScore(fields *beads.AgentFields) int {
+	transient := fields.TransientFailures
+	if transient > fields.FailureCount {
+		transient = fields.FailureCount
+	}
+	return fields.FailureCount - transient + transient/2
+}
+
+// CircuitFailure reports a failure recorded against a polecat's circuit
+// and, when it had work hooked, against that work bead.
+type CircuitFailure struct {
+	AgentBeadID  string
+	Reason       string
+	FailureCount int
+	Score        int // FailureCount with transient failures weighted down
+	TripCount    int
+	Tripped      bool // this failure opened the circuit
+
+	WorkBead     string
+	WorkFailures *beads.WorkFailureFields // failures recorded on WorkBead
+	BrokenWork   bool                     // WorkBead reached max_bead_failures
+}
+
+// HandlePolecatFailure counts a failure, classified as reason (see
+// beads.FailureTransient etc.), against a polecat's circuit and opens the
+// circuit when the rig's max_failures is reached. The tripped circuit is
+// acted on by the next CheckCircuitBreakers sweep.
+//
+// The failure is also counted on hookBead, the work the polecat had hooked
+// (if any). When the result reports BrokenWork the caller should block the
+// bead with BlockBrokenWork instead of requeuing it.
+func HandlePolecatFailure(workDir, rigName, polecatName, hookBead, reason string) (*CircuitFailure, error) {
+	bd, townRoot := rigBeads(workDir, rigName)
+	cfg := circuitConfigForRig(workDir, rigName)
+	agentBeadID := polecatAgentBeadID(townRoot, rigName, polecatName)
+	failure, err := recordPolecatFailure(bd, agentBeadID, reason, cfg, time.Now())
+	if failure == nil {
+		failure = &CircuitFailure{AgentBeadID: agentBeadID, Reason: reason}
+	}
+	recordCircuitEvent(townRoot, rigName, CircuitEvent{
+		Event:   CircuitEventFailure,
+		Polecat: polecatName,
+		Bead:    hookBead,
+		Reason:  reason,
+	})
+	if failure.Tripped {
+		recordCircuitEvent(townRoot, rigName, CircuitEvent{
+			Event:    CircuitEventTrip,
+			Polecat:  polecatName,
+			Bead:     hookBead,
+			Failures: failure.FailureCount,
+			Reason:   reason,
+		})
+	}
+	if hookBead == "" {
+		return failure, err
+	}
+	if werr := recordWorkFailure(bd, failure, hookBead, rigName+"/"+polecatName, cfg); werr != nil && err == nil {
+		err = werr
+	}
+	return failure, err
+}
+
+// recordWorkFailure counts failure against the work bead beadID, failed by
+// polecat (rig/name), and flags it broken at cfg.MaxBeadFailures.
+func recordWorkFailure(bd circuitBeads, failure *CircuitFailure, beadID, polecat string, cfg CircuitBreakerConfig) error {
+	work, err := bd.RecordWorkFailure(beadID, polecat, failure.Reason)
+	if err != nil {
+		return fmt.Errorf("recording failure on %s: %w", beadID, err)
+	}
+	failure.WorkBead = beadID
+	failure.WorkFailures = work
+	failure.BrokenWork = cfg.isBrokenWork(work)
+	return nil
+}
+
+// isBrokenWork reports whether a work bead's failures reach MaxBeadFailures.
+func (c CircuitBreakerConfig) isBrokenWork(work *beads.WorkFailureFields) bool {
+	return work != nil && c.MaxBeadFailures > 0 && work.Score() >= c.MaxBeadFailures
+}
+
+// BlockBrokenWork sets a work bead that reached max_bead_failures (see
+// HandlePolecatFailure) to blocked with no assignee, so it isn't dispatched
+// again, and tells the Mayor it is likely broken (BROKEN_WORK). The caller
+// checks the bead is still worth taking (hooked, in progress). Returns
+// false if the bead couldn't be updated.
+func BlockBrokenWork(workDir, rigName string, failure *CircuitFailure, router *mail.Router) bool {
+	if !blockBrokenBead(workDir, failure.WorkBead) {
+		return false
+	}
+	if router != nil {
+		policy := rigEscalationPolicy(findTownRoot(workDir), rigName)
+		_ = policy.Escalate(router.Send, brokenWorkMessage(rigName, failure.WorkBead, failure.WorkFailures, "")) // Best-effort
+	}
+	return true
+}
+
+// blockBrokenBead sets a bead to blocked with no assignee.
+func blockBrokenBead(workDir, beadID string) bool {
+	return util.ExecRun(workDir, "bd", "update", beadID, "--status=blocked", "--assignee=") == nil
+}
+
+func recordPolecatFailure(bd circuitBeads, agentBeadID, reason string, cfg CircuitBreakerConfig, now time.Time) (*CircuitFailure, error) {
+	failure := &CircuitFailure{AgentBeadID: agentBeadID, Reason: reason}
+	// The failure and the trip it causes are one write, so a reset or trip
+	// by another actor can't land between them.
+	fields, err := bd.UpdateAgentFields(agentBeadID, beads.AnyAgentRevision, func(fields *beads.AgentFields) error {
+		beads.ApplyAgentFailure(fields, reason, now)
+		// A failed half-open probe, or a failure on probation, reopens the
+		// circuit at once.
+		trip := circuitFailureScore(fields) >= cfg.MaxFailures || fields.CircuitState == beads.CircuitHalfOpen ||
+			fields.OnProbation(lastFailureAt(fields))
+		failure.Tripped = trip && fields.CircuitState != beads.CircuitOpen
+		if failure.Tripped {
+			beads.ApplyOpenCircuit(fields, now)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("recording failure on %s: %w", agentBeadID, err)
+	}
+	failure.FailureCount = fields.FailureCount
+	failure.Score = circuitFailureScore(fields)
+	failure.TripCount = fields.TripCount
+	return failure, nil
+}
+
+// lastFailureAt is when a polecat's latest failure was counted, or now if
+// it wasn't stamped.
+func lastFailureAt(fields *beads.AgentFields) time.Time {
+	if at, err := time.Parse(time.RFC3339, fields.LastFailureAt); err == nil {
+		return at
+	}
+	return time.Now()
+}
+
+// RecordPolecatSuccess clears a polecat's failures after its work landed. A
+// half-open circuit closes only once the rig's success_streak of probes has
+// landed in a row, and the polecat then goes on probation. A polecat with
+// no failures is left alone.
+func RecordPolecatSuccess(workDir, rigName, polecatName string) error {
+	bd, townRoot := rigBeads(workDir, rigName)
+	agentBeadID := polecatAgentBeadID(townRoot, rigName, polecatName)
+	_, fields, err := bd.GetAgentBead(agentBeadID)
+	if err != nil || fields == nil {
+		return err
+	}
+	recordCircuitEvent(townRoot, rigName, CircuitEvent{Event: CircuitEventSuccess, Polecat: polecatName})
+	reset, err := recordPolecatSuccess(bd, agentBeadID, fields, circuitConfigForRig(workDir, rigName), time.Now())
+	if reset != "" {
+		recordCircuitEvent(townRoot, rigName, CircuitEvent{Event: CircuitEventReset, Polecat: polecatName, Reason: reset})
+	}
+	return err
+}
+
+// recordPolecatSuccess counts landed work against the circuit of a polecat
+// whose fields were read as fields. Returns the reason to record if it
+// closed a tripped circuit, "" otherwise.
+func recordPolecatSuccess(bd circuitBeads, agentBeadID string, fields *beads.AgentFields, cfg CircuitBreakerConfig, now time.Time) (string, error) {
+	state := fields.CircuitState
+	if fields.FailureCount == 0 && state != beads.CircuitHalfOpen && state != beads.CircuitOpen {
+		return "", nil
+	}
+	var probationUntil time.Time
+	if cfg.ProbationPeriod > 0 {
+		probationUntil = now.Add(cfg.ProbationPeriod)
+	}
+	updated, err := bd.RecordAgentSuccess(agentBeadID, cfg.SuccessStreak, probationUntil)
+	if err != nil {
+		return "", fmt.Errorf("recording success on %s: %w", agentBeadID, err)
+	}
+	if updated.CircuitState != beads.CircuitClosed || (state != beads.CircuitHalfOpen && state != beads.CircuitOpen) {
+		return "", nil
+	}
+	if state == beads.CircuitHalfOpen && cfg.SuccessStreak > 1 {
+		return fmt.Sprintf("work landed (%d probes in a row)", cfg.SuccessStreak), nil
+	}
+	return "work landed", nil
+}
+
+// SetHalfOpenState moves a polecat's open circuit to half_open, allowing a
+// single probe assignment. Circuits that aren't open are left alone.
+// CheckCircuitBreakers does this automatically once the cooldown elapses.
+func SetHalfOpenState(workDir, rigName, polecatName string) error {
+	bd, townRoot := rigBeads(workDir, rigName)
+	agentBeadID := polecatAgentBeadID(townRoot, rigName, polecatName)
+	_, fields, err := bd.GetAgentBead(agentBeadID)
+	if err != nil {
+		return err
+	}
+	if fields == nil || fields.CircuitState != beads.CircuitOpen {
+		return nil
+	}
+	// Only the open circuit just read moves: if it was reset or tripped
+	// again since, the update fails instead of overwriting that.
+	if _, err := bd.UpdateAgentFields(agentBeadID, fields.Revision, halfOpen(time.Now())); err != nil {
+		return err
+	}
+	recordCircuitEvent(townRoot, rigName, CircuitEvent{Event: CircuitEventHalfOpen, Polecat: polecatName})
+	return nil
+}
+
+// halfOpen is the update that moves a circuit to half_open at now.
+func halfOpen(now time.Time) func(*beads.AgentFields) error {
+	return func(fields *beads.AgentFields) error {
+		beads.ApplyCircuitState(fields, beads.CircuitHalfOpen, now)
+		return nil
+	}
+}
+
+// ResetCircuit closes a polecat's circuit by hand, clearing its failure and
+// trip counts, and records who did it and why in the agent bead's
+// circuit_note. Used to unblock a polecat tripped by mistake.
+func ResetCircuit(workDir, rigName, polecatName, actor, reason string) error {
+	bd, townRoot := rigBeads(workDir, rigName)
+	agentBeadID := polecatAgentBeadID(townRoot, rigName, polecatName)
+	if err := requireAgentBead(bd, agentBeadID); err != nil {
+		return err
+	}
+	// The reset and its note are one write.
+	now := time.Now()
+	_, err := bd.UpdateAgentFields(agentBeadID, beads.AnyAgentRevision, func(fields *beads.AgentFields) error {
+		beads.ApplyCircuitReset(fields)
+		fields.CircuitNote = circuitNote("reset", actor, reason, now)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("resetting circuit on %s: %w", agentBeadID, err)
+	}
+	recordCircuitEvent(townRoot, rigName, CircuitEvent{
+		Event:   CircuitEventReset,
+		Polecat: polecatName,
+		Reason:  reason,
+		Actor:   actor,
+	})
+	return nil
+}
+
+// TripCircuit opens a polecat's circuit by hand: the next
+// CheckCircuitBreakers sweep requeues its work and nukes or quarantines it
+// as for any tripped circuit. Who did it and why goes in circuit_note.
+func TripCircuit(workDir, rigName, polecatName, actor, reason string) (*beads.AgentFields, error) {
+	bd, townRoot := rigBeads(workDir, rigName)
+	agentBeadID := polecatAgentBeadID(townRoot, rigName, polecatName)
+	if err := requireAgentBead(bd, agentBeadID); err != nil {
+		return nil, err
+	}
+	// The trip and its note are one write.
+	now := time.Now()
+	fields, err := bd.UpdateAgentFields(agentBeadID, beads.AnyAgentRevision, func(fields *beads.AgentFields) error {
+		beads.ApplyOpenCircuit(fields, now)
+		fields.CircuitNote = circuitNote("tripped", actor, reason, now)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("opening circuit on %s: %w", agentBeadID, err)
+	}
+	recordCircuitEvent(townRoot, rigName, CircuitEvent{
+		Event:   CircuitEventTrip,
+		Polecat: polecatName,
+		Bead:    fields.HookBead,
+		Reason:  reason,
+		Actor:   actor,
+	})
+	return fields, nil
+}
+
+func requireAgentBead(bd *beads.Beads, agentBeadID string) error {
+	_, fields, err := bd.GetAgentBead(agentBeadID)
+	if err != nil {
+		return fmt.Errorf("reading agent bead %s: %w", agentBeadID, err)
+	}
+	if fields == nil {
+		return fmt.Errorf("no agent bead %s", agentBeadID)
+	}
+	return nil
+}
+
+// circuitNote formats a manual override for the single-line circuit_note
+// field, e.g. "tripped by mayor at 2026-03-01T12:00:00Z: flaky worktree".
+func circuitNote(action, actor, reason string, at time.Time) string {
+	note := fmt.Sprintf("%s by %s at %s", action, actor, at.UTC().Format(time.RFC3339))
+	if reason = strings.Join(strings.Fields(reason), " "); reason != "" {
+		note += ": " + reason
+	}
+	return note
+}
+
+// TrippedCircuit is one open circuit acted on by a sweep.
+type TrippedCircuit struct {
+	Polecat      string `json:"polecat"`
+	AgentBeadID  string `json:"agent_bead"`
+	FailureCount int    `json:"failure_count"`
+	Transient    int    `json:"transient_failures,omitempty"`
+	LastFailure  string `json:"last_failure_reason,omitempty"`
+	TripCount    int    `json:"trip_count,omitempty"`
+	HookBead     string `json:"hook_bead,omitempty"`
+	OpenedAt     string `json:"opened_at,omitempty"` // circuit_opened_at of this trip
+
+	// Tripped is set when this sweep opened the circuit, because the
+	// failure count already met a (possibly lowered) max_failures.
+	Tripped bool `json:"tripped,omitempty"`
+
+	// WorkFailures counts failures on HookBead across every polecat that
+	// took it. At max_bead_failures the bead is blocked as BrokenWork
+	// instead of requeued.
+	WorkFailures   int    `json:"work_failures,omitempty"`
+	BrokenWork     bool   `json:"broken_work,omitempty"`
+	BrokenWorkMail string `json:"broken_work_mail,omitempty"`
+
+	Forensics string `json:"forensics,omitempty"` // bundle captured before the nuke
+
+	// Remediation is the rig's remediation hook run on this sweep, if any.
+	// NukeVeto is set while a hook's veto keeps the polecat from being
+	// nuked; 'gt witness circuit reset' lifts it.
+	Remediation *RemediationResult `json:"remediation,omitempty"`
+	NukeVeto    string             `json:"nuke_veto,omitempty"`
+
+	// Quarantine is why the polecat is held for a human instead of nuked:
+	// set by the sweep whose nuke was skipped to keep the worktree's work,
+	// and kept until 'gt witness quarantine release|nuke'. Frozen is set
+	// when that sweep stopped the polecat's session.
+	Quarantine string `json:"quarantine,omitempty"`
+	Frozen     bool   `json:"frozen,omitempty"`
+
+	Requeued    bool   `json:"requeued,omitempty"`
+	RequeueMail string `json:"requeue_mail,omitempty"`
+
+	// CorrelationID traces requeued work through its reassignment and
+	// completion. Deduplicated is set when an earlier sweep already
+	// requeued the work for this trip, so it wasn't requeued again.
+	CorrelationID string `json:"correlation_id,omitempty"`
+	Deduplicated  bool   `json:"deduplicated,omitempty"`
+
+	// Deferred is set when the sweep had used up max_actions_per_sweep:
+	// nothing was done beyond opening the circuit, and the next sweep
+	// picks it up.
+	Deferred bool `json:"deferred,omitempty"`
+
+	Nuked      bool   `json:"nuked,omitempty"`
+	NukeResult string `json:"nuke_result,omitempty"`
+	Escalated  bool   `json:"escalated,omitempty"`
+	Escalation string `json:"escalation_mail,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// CheckCircuitBreakersResult summarizes a circuit breaker sweep.
+//
+// In a dry run (PreviewCircuitBreakers) nothing is changed: Tripped,
+// BrokenWork, Requeued, Nuked, Escalated and HalfOpen say what a real sweep
+// would do.
+type CheckCircuitBreakersResult struct {
+	Rig     string                `json:"rig"`
+	DryRun  bool                  `json:"dry_run,omitempty"`
+	Config  CircuitBreakerSummary `json:"config"`
+	Checked int                   `json:"checked"` // polecats in the rig with failures or an open circuit
+	Open    int                   `json:"open"`    // open circuits, acted on or not
+	Tripped []TrippedCircuit      `json:"tripped,omitempty"`
+
+	// Acted counts the open circuits acted on, up to max_actions_per_sweep;
+	// Deferred those left for the next sweep.
+	Acted    int `json:"acted"`
+	Deferred int `json:"deferred,omitempty"`
+
+	// HalfOpen lists polecats whose cooldown elapsed this sweep; their
+	// circuits moved to half_open for a probe assignment.
+	HalfOpen []string `json:"half_open,omitempty"`
+
+	Errors []string `json:"errors,omitempty"`
+}
+
+// CircuitBreakerSummary is the breaker config a sweep ran with.
+type CircuitBreakerSummary struct {
+	MaxFailures       int    `json:"max_failures"`
+	CooldownPeriod    string `json:"cooldown_period"`
+	MaxCooldownPeriod string `json:"max_cooldown_period"`
+	MaxBeadFailures   int    `json:"max_bead_failures"`
+	RemediationHook   string `json:"remediation_hook,omitempty"`
+	MaxActions        int    `json:"max_actions_per_sweep"`
+	ActionPacing      string `json:"action_pacing"`
+	SuccessStreak     int    `json:"success_streak"`
+	ProbationPeriod   string `json:"probation_period"`
+}
+
+func (c CircuitBreakerConfig) summary() CircuitBreakerSummary {
+	return CircuitBreakerSummary{
+		MaxFailures:       c.MaxFailures,
+		CooldownPeriod:    c.CooldownPeriod.String(),
+		MaxCooldownPeriod: c.MaxCooldownPeriod.String(),
+		MaxBeadFailures:   c.MaxBeadFailures,
+		RemediationHook:   c.RemediationHook,
+		MaxActions:        c.MaxActionsPerSweep,
+		ActionPacing:      c.ActionPacing.String(),
+		SuccessStreak:     c.SuccessStreak,
+		ProbationPeriod:   c.ProbationPeriod.String(),
+	}
+}
+
+// circuitSweep carries what processing a tripped circuit needs, so the
+// side effects can be replaced in tests.
+type circuitSweep struct {
+	workDir string
+	rigName string
+	bd      circuitBeads
+	send    func(*mail.Message) error
+	requeue func(beadID string) bool
+	block   func(beadID string) bool
+	nuke    func(polecatName string) *NukePolecatResult
+	capture func(polecatName string) (string, error)
+
+	// freeze stops a quarantined polecat's session; nil leaves it running.
+	freeze func(polecatName string) error
+
+	// pace waits out the action pacing between two acted-on circuits; nil
+	// doesn't wait.
+	pace func(time.Duration)
+
+	// remediate runs the rig's remediation hook; nil when none is set.
+	remediate func(tc *TrippedCircuit) *RemediationResult
+
+	// escalation routes BROKEN_WORK and CIRCUIT_TRIPPED.
+	escalation EscalationPolicy
+
+	// history records trips, requeues, nukes and half_open moves.
+	history circuitRecorder
+
+	now time.Time
+
+	// dryRun reports instead of acting: no bead writes, mail or captures,
+	// and requeue and nuke are previews.
+	dryRun bool
+}
+
+// CheckCircuitBreakers sweeps the rig's polecat agent beads and acts on
+// open circuits: the polecat's hooked work is requeued through the Mayor
+// (WORK_REQUEUE), then the polecat is nuked if clean, or quarantined and
+// escalated if nuking would lose work. A polecat whose failure count
+// already meets cfg.MaxFailures is tripped first. Once its work is
+// requeued an open circuit has nothing hooked, so later sweeps only retry
+// the nuke; a quarantined polecat waits for a human.
+//
+// At most cfg.MaxActionsPerSweep open circuits are acted on, cfg.ActionPacing
+// apart, so a sweep of a large town doesn't nuke dozens of polecats and
+// flood the Mayor with mail at once. Circuits past the limit are still
+// opened, but their work and nuke are deferred to the next sweep.
+//
+// An open circuit whose CooldownPeriod has elapsed since it opened moves to
+// half_open: the polecat may take probe assignments, one at a time. The
+// circuit closes once cfg.SuccessStreak of them have merged in a row, and
+// reopens if one fails.
+//
+// Each sweep touches the rig's Witness heartbeat (see Heartbeat).
+func CheckCircuitBreakers(workDir, rigName string, router *mail.Router, cfg CircuitBreakerConfig) *CheckCircuitBreakersResult {
+	bd, townRoot := rigBeads(workDir, rigName)
+	sweep := &circuitSweep{
+		workDir: workDir,
+		rigName: rigName,
+		bd:      bd,
+		send:    router.Send,
+		requeue: func(beadID string) bool { return reopenHookedBead(workDir, beadID) },
+		block:   func(beadID string) bool { return canRequeueBead(workDir, beadID) && blockBrokenBead(workDir, beadID) },
+		nuke:    func(polecatName string) *NukePolecatResult { return AutoNukeIfClean(workDir, rigName, polecatName) },
+		capture: func(polecatName string) (string, error) { return CaptureForensics(workDir, rigName, polecatName) },
+		freeze:  func(polecatName string) error { return freezeSession(rigName, polecatName) },
+		pace:    time.Sleep,
+		now:     time.Now(),
+
+		escalation: rigEscalationPolicy(townRoot, rigName),
+		history:    rigCircuitRecorder(townRoot, rigName),
+	}
+	if cfg.RemediationHook != "" {
+		sweep.remediate = func(tc *TrippedCircuit) *RemediationResult {
+			env := remediationEnv(townRoot, rigName, tc)
+			return RunRemediationHook(cfg.RemediationHook, filepath.Join(townRoot, rigName), env, cfg.RemediationHookTimeout)
+		}
+	}
+	result := sweep.run(cfg)
+	_ = TouchHeartbeat(filepath.Join(townRoot, rigName), result) // Best-effort: surfaced by gt status and the daemon
+	return result
+}
+
+// PreviewCircuitBreakers reports what CheckCircuitBreakers would do (which
+// circuits trip, which work is requeued, which polecats are nuked or
+// escalated, which circuits go half_open) without changing anything.
+func PreviewCircuitBreakers(workDir, rigName string, cfg CircuitBreakerConfig) *CheckCircuitBreakersResult {
+	bd, _ := rigBeads(workDir, rigName)
+	sweep := &circuitSweep{
+		workDir: workDir,
+		rigName: rigName,
+		bd:      bd,
+		requeue: func(beadID string) bool { return canRequeueBead(workDir, beadID) },
+		block:   func(beadID string) bool { return canRequeueBead(workDir, beadID) },
+		nuke:    func(polecatName string) *NukePolecatResult { return PreviewAutoNuke(workDir, rigName, polecatName) },
+		now:     time.Now(),
+		dryRun:  true,
+	}
+	return sweep.run(cfg)
+}
+
+func (s *circuitSweep) run(cfg CircuitBreakerConfig) *CheckCircuitBreakersResult {
+	result := &CheckCircuitBreakersResult{
+		Rig:    s.rigName,
+		DryRun: s.dryRun,
+		Config: cfg.summary(),
+	}
+
+	agents, err := circuitCandidates(s.bd)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("listing agent beads: %v", err))
+		return result
+	}
+	candidates := s.parseCandidates(agents, cfg)
+	result.Checked = len(candidates)
+	s.openCircuits(candidates)
+
+	for _, c := range candidates {
+		id, polecatName, issue, fields, trip := c.id, c.polecatName, c.issue, c.fields, c.trip
+		if fields.CircuitState != beads.CircuitOpen && !trip {
+			continue
+		}
+		result.Open++
+
+		if c.nuked {
+			// Already cleaned up; the circuit only waits out its cooldown.
+			s.expireCooldown(id, polecatName, fields, cfg, result)
+			continue
+		}
+
+		tc := TrippedCircuit{
+			Polecat:      polecatName,
+			AgentBeadID:  id,
+			FailureCount: fields.FailureCount,
+			Transient:    fields.TransientFailures,
+			LastFailure:  fields.LastFailureReason,
+			TripCount:    fields.TripCount,
+			HookBead:     fields.HookBead,
+			OpenedAt:     fields.CircuitOpenedAt,
+			NukeVeto:     fields.NukeVeto,
+			Quarantine:   fields.Quarantine,
+		}
+		if tc.HookBead == "" {
+			tc.HookBead = issue.HookBead
+		}
+		if trip && s.dryRun {
+			tc.Tripped = true
+			tc.TripCount++
+		} else if trip {
+			if c.openErr != nil {
+				tc.Error = fmt.Sprintf("opening circuit: %v", c.openErr)
+				result.Tripped = append(result.Tripped, tc)
+				continue
+			}
+			tc.Tripped = true
+			tc.TripCount = c.opened.TripCount
+			tc.OpenedAt = c.opened.CircuitOpenedAt
+			s.history.record(CircuitEvent{
+				Time:     s.now,
+				Event:    CircuitEventTrip,
+				Polecat:  polecatName,
+				Bead:     tc.HookBead,
+				Failures: tc.FailureCount,
+				Reason:   tc.LastFailure,
+			})
+		}
+		if tc.needsAction() {
+			if cfg.MaxActionsPerSweep > 0 && result.Acted >= cfg.MaxActionsPerSweep {
+				tc.Deferred = true
+				result.Deferred++
+				result.Tripped = append(result.Tripped, tc)
+				continue
+			}
+			if result.Acted > 0 && s.pace != nil && !s.dryRun {
+				s.pace(cfg.ActionPacing)
+			}
+			result.Acted++
+		}
+		s.processTrippedCircuit(&tc, cfg)
+		result.Tripped = append(result.Tripped, tc)
+		if !trip && tc.Quarantine == "" {
+			// A quarantined circuit stays open until a human resolves it.
+			s.expireCooldown(id, polecatName, fields, cfg, result)
+		}
+	}
+
+	return result
+}
+
+// circuitCandidates lists the agent beads a sweep looks at: those with an
+// open circuit or failures counted (failure_count is only written when
+// non-zero). Both are filtered in bd, so a sweep of a town with hundreds of
+// healthy polecats doesn't fetch and parse them all.
+func circuitCandidates(bd circuitBeads) (map[string]*beads.Issue, error) {
+	agents, err := bd.ListAgentBeadsWithField("circuit_state", beads.CircuitOpen)
+	if err != nil {
+		return nil, err
+	}
+	failing, err := bd.ListAgentBeadsWithField("failure_count", "")
+	if err != nil {
+		return nil, err
+	}
+	for id, issue := range failing {
+		agents[id] = issue
+	}
+	return agents, nil
+}
+
+// circuitCandidate is a polecat in the rig whose circuit a sweep looks at.
+type circuitCandidate struct {
+	id          string
+	polecatName string
+	issue       *beads.Issue
+	fields      *beads.AgentFields
+	nuked       bool // already cleaned up
+
+	// trip is set when the failures reach max_failures but the circuit
+	// isn't open yet; opened and openErr are the result of opening it.
+	trip    bool
+	opened  *beads.AgentFields
+	openErr error
+}
+
+// parseCandidates parses the agent beads on up to circuitSweepWorkers
+// goroutines and returns the rig's polecats among them, by bead ID.
+func (s *circuitSweep) parseCandidates(agents map[string]*beads.Issue, cfg CircuitBreakerConfig) []*circuitCandidate {
+	ids := make([]string, 0, len(agents))
+	for id := range agents {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	parsed := make([]*circuitCandidate, len(ids))
+	forEachBounded(len(ids), circuitSweepWorkers, func(i int) {
+		id, issue := ids[i], agents[ids[i]]
+		fields := beads.ParseAgentFields(issue.Description)
+		if fields.RoleType != "polecat" || fields.Rig != s.rigName {
+			return
+		}
+		_, _, polecatName, ok := beads.ParseAgentBeadID(id)
+		if !ok || polecatName == "" {
+			return
+		}
+		agentState := issue.AgentState
+		if agentState == "" {
+			agentState = fields.AgentState
+		}
+		state := fields.CircuitState
+		parsed[i] = &circuitCandidate{
+			id:          id,
+			polecatName: polecatName,
+			issue:       issue,
+			fields:      fields,
+			nuked:       agentState == "nuked",
+			trip: state != beads.CircuitOpen && state != beads.CircuitHalfOpen &&
+				circuitFailureScore(fields) >= cfg.MaxFailures,
+		}
+	})
+
+	candidates := parsed[:0]
+	for _, c := range parsed {
+		if c != nil {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates
+}
+
+// openCircuits opens the circuits of candidates that reached max_failures,
+// on up to circuitSweepWorkers goroutines. Each writes its own agent bead;
+// their trips are recorded afterwards, in order.
+func (s *circuitSweep) openCircuits(candidates []*circuitCandidate) {
+	if s.dryRun {
+		return
+	}
+	var tripping []*circuitCandidate
+	for _, c := range candidates {
+		if c.trip && !c.nuked {
+			tripping = append(tripping, c)
+		}
+	}
+	forEachBounded(len(tripping), circuitSweepWorkers, func(i int) {
+		c := tripping[i]
+		c.opened, c.openErr = s.bd.UpdateAgentFields(c.id, c.fields.Revision, func(fields *beads.AgentFields) error {
+			beads.ApplyOpenCircuit(fields, s.now)
+			return nil
+		})
+		if errors.Is(c.openErr, beads.ErrAgentFieldsConflict) {
+			// Changed since it was listed (reset, say): the next sweep
+			// decides on what's there now.
+			c.trip, c.openErr = false, nil
+		}
+	})
+}
+
+// forEachBounded calls fn(i) for each i in [0, n), at most workers at a
+// time, and returns once all calls have.
+func forEachBounded(n, workers int, fn func(i int)) {
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// needsAction reports whether processing the circuit will requeue work or
+// try a nuke, rather than only report a polecat held for a human.
+func (tc *TrippedCircuit) needsAction() bool {
+	return tc.HookBead != "" || (tc.NukeVeto == "" && tc.Quarantine == "")
+}
+
+// expireCooldown moves an open circuit to half_open once its cooldown (see
+// CircuitBreakerConfig.CooldownFor) has passed since circuit_opened_at. A
+// circuit opened before the stamp was recorded is re-opened to start its
+// cooldown now.
+func (s *circuitSweep) expireCooldown(id, polecatName string, fields *beads.AgentFields, cfg CircuitBreakerConfig, result *CheckCircuitBreakersResult) {
+	openedAt, err := time.Parse(time.RFC3339, fields.CircuitOpenedAt)
+	if err != nil {
+		if s.dryRun {
+			return
+		}
+		_, err := s.bd.UpdateAgentFields(id, fields.Revision, func(fields *beads.AgentFields) error {
+			beads.ApplyCircuitState(fields, beads.CircuitOpen, s.now)
+			return nil
+		})
+		if err != nil && !errors.Is(err, beads.ErrAgentFieldsConflict) {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: stamping circuit_opened_at: %v", polecatName, err))
+		}
+		return
+	}
+	if s.now.Sub(openedAt) < cfg.CooldownFor(fields.TripCount) {
+		return
+	}
+	if s.dryRun {
+		result.HalfOpen = append(result.HalfOpen, polecatName)
+		return
+	}
+	// A circuit reset or tripped again since it was listed is left for the
+	// next sweep.
+	if _, err := s.bd.UpdateAgentFields(id, fields.Revision, halfOpen(s.now)); err != nil {
+		if !errors.Is(err, beads.ErrAgentFieldsConflict) {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: moving circuit to half_open: %v", polecatName, err))
+		}
+		return
+	}
+	result.HalfOpen = append(result.HalfOpen, polecatName)
+	s.history.record(CircuitEvent{Time: s.now, Event: CircuitEventHalfOpen, Polecat: polecatName})
+}
+
+// processTrippedCircuit requeues the polecat's hooked work (or blocks it,
+// if the work itself has failed max_bead_failures times), then nukes the
+// polecat or, when that isn't safe, quarantines and escalates it.
+// Escalation happens only on the sweep that takes the polecat's work, so a
+// dirty polecat is reported once. That first sweep also captures a
+// forensic bundle before anything is touched; later sweeps only retry the
+// nuke, and leave a quarantined polecat alone.
+func (s *circuitSweep) processTrippedCircuit(tc *TrippedCircuit, cfg CircuitBreakerConfig) {
+	hadWork := tc.HookBead != ""
+	var work *beads.WorkFailureFields
+	if hadWork {
+		work, _ = s.bd.WorkFailures(tc.HookBead)
+		if work != nil {
+			tc.WorkFailures = work.FailureCount
+		}
+	}
+	broken := cfg.isBrokenWork(work)
+
+	if s.dryRun {
+		tc.BrokenWork = hadWork && broken && s.block(tc.HookBead)
+		tc.Requeued = hadWork && !broken && s.requeue(tc.HookBead)
+		if tc.NukeVeto != "" {
+			tc.NukeResult = "nuke vetoed: " + tc.NukeVeto
+			return
+		}
+		if tc.Quarantine != "" {
+			tc.NukeResult = "quarantined: " + tc.Quarantine
+			return
+		}
+		nuke := s.nuke(tc.Polecat)
+		tc.Nuked = nuke.Nuked
+		tc.NukeResult = nuke.Reason
+		if nuke.Skipped {
+			tc.Quarantine = quarantineReason(nuke)
+		}
+		tc.Escalated = hadWork && !nuke.Nuked
+		return
+	}
+
+	firstSweep := hadWork || tc.Tripped
+	if firstSweep {
+		path, err := s.capture(tc.Polecat)
+		if err != nil {
+			tc.Error = fmt.Sprintf("capturing forensics: %v", err)
+		}
+		tc.Forensics = path
+	}
+	if firstSweep && s.remediate != nil {
+		s.runRemediation(tc)
+	}
+	switch {
+	case hadWork && broken:
+		if s.block(tc.HookBead) {
+			tc.BrokenWork = true
+			msg := brokenWorkMessage(s.rigName, tc.HookBead, work, forensicsLine(tc))
+			if err := s.escalation.Escalate(s.send, msg); err != nil {
+				if tc.Error == "" {
+					tc.Error = fmt.Sprintf("sending BROKEN_WORK: %v", err)
+				}
+			} else {
+				tc.BrokenWorkMail = msg.ID
+			}
+		}
+	case hadWork:
+		s.requeueWork(tc)
+	}
+	if hadWork {
+		// The work is no longer this polecat's, requeued or not.
+		_ = s.bd.ClearHookBead(tc.AgentBeadID)
+	}
+
+	if tc.NukeVeto != "" {
+		// A vetoed polecat waits for a human. It is escalated once, by
+		// the sweep whose hook vetoed it.
+		tc.NukeResult = "nuke vetoed: " + tc.NukeVeto
+		if tc.Remediation == nil || !tc.Remediation.Vetoed {
+			return
+		}
+	} else if tc.Quarantine != "" {
+		// Held for a human by an earlier sweep, which escalated it.
+		tc.NukeResult = "quarantined: " + tc.Quarantine
+		return
+	} else {
+		nuke := s.nuke(tc.Polecat)
+		tc.Nuked = nuke.Nuked
+		tc.NukeResult = nuke.Reason
+		if nuke.Nuked {
+			s.history.record(CircuitEvent{
+				Time:      s.now,
+				Event:     CircuitEventNuke,
+				Polecat:   tc.Polecat,
+				TrippedAt: parseFieldTime(tc.OpenedAt),
+			})
+		}
+		if nuke.Skipped {
+			s.quarantinePolecat(tc, quarantineReason(nuke))
+		}
+		if nuke.Nuked || !hadWork || tc.Deduplicated {
+			// A deduplicated trip was escalated by the sweep that requeued it.
+			return
+		}
+	}
+
+	msg := trippedCircuitEscalation(s.rigName, tc)
+	if err := s.escalation.Escalate(s.send, msg); err != nil {
+		if tc.Error == "" {
+			tc.Error = fmt.Sprintf("escalating tripped circuit: %v", err)
+		}
+		return
+	}
+	tc.Escalated = true
+	tc.Escalation = msg.ID
+}
+
+// quarantinePolecat holds a tripped polecat whose nuke was skipped for a
+// human: the quarantine is recorded on its agent bead, so later sweeps
+// stop retrying the nuke, and its session is frozen so it changes nothing
+// more in the worktree.
+func (s *circuitSweep) quarantinePolecat(tc *TrippedCircuit, reason string) {
+	if err := s.bd.SetAgentQuarantine(tc.AgentBeadID, reason, s.now); err != nil {
+		if tc.Error == "" {
+			tc.Error = fmt.Sprintf("quarantining: %v", err)
+		}
+		return
+	}
+	tc.Quarantine = reason
+	s.history.record(CircuitEvent{
+		Time:      s.now,
+		Event:     CircuitEventQuarantine,
+		Polecat:   tc.Polecat,
+		Reason:    reason,
+		TrippedAt: parseFieldTime(tc.OpenedAt),
+	})
+	if s.freeze == nil {
+		return
+	}
+	switch err := s.freeze(tc.Polecat); {
+	case err == nil:
+		tc.Frozen = true
+	case errors.Is(err, errNoLiveSession):
+		// Nothing running to freeze.
+	case tc.Error == "":
+		tc.Error = fmt.Sprintf("freezing session: %v", err)
+	}
+}
+
+// quarantineReason is why a skipped nuke quarantines the polecat, e.g.
+// "has uncommitted".
+func quarantineReason(nuke *NukePolecatResult) string {
+	if reason := strings.TrimPrefix(nuke.Reason, "skipped: "); reason != "" {
+		return reason
+	}
+	return "nuke skipped"
+}
+
+// runRemediation runs the rig's remediation hook on a tripped polecat. A
+// veto is recorded on the agent bead (nuke_veto) so later sweeps don't nuke
+// the polecat either.
+func (s *circuitSweep) runRemediation(tc *TrippedCircuit) {
+	tc.Remediation = s.remediate(tc)
+	if !tc.Remediation.Vetoed {
+		if tc.Remediation.Error != "" && tc.Error == "" {
+			tc.Error = "remediation hook: " + tc.Remediation.Error
+		}
+		return
+	}
+	tc.NukeVeto = tc.Remediation.VetoReason()
+	if err := s.bd.SetAgentNukeVeto(tc.AgentBeadID, tc.NukeVeto); err != nil && tc.Error == "" {
+		tc.Error = fmt.Sprintf("recording nuke veto: %v", err)
+	}
+}
+
+// requeueWork requeues a tripped polecat's hooked work and mails the Mayor
+// WORK_REQUEUE, once per trip: the requeue is first claimed on the bead
+// under beads.RequeueKey (bead ID + trip timestamp), so a later or racing
+// sweep of the same trip finds the claim and sends no duplicate. If the
+// claim can't be recorded the work is requeued anyway, untraced.
+func (s *circuitSweep) requeueWork(tc *TrippedCircuit) {
+	claim, fresh, err := s.bd.ClaimRequeue(tc.HookBead, beads.RequeueKey(tc.HookBead, tc.OpenedAt))
+	switch {
+	case err != nil:
+		tc.Error = fmt.Sprintf("recording requeue: %v", err)
+	case !fresh:
+		tc.Deduplicated = true
+		tc.CorrelationID = claim.CorrelationID
+		return
+	default:
+		tc.CorrelationID = claim.CorrelationID
+	}
+
+	if !s.requeue(tc.HookBead) {
+		return
+	}
+	tc.Requeued = true
+	s.history.record(CircuitEvent{
+		Time:          s.now,
+		Event:         CircuitEventRequeue,
+		Polecat:       tc.Polecat,
+		Bead:          tc.HookBead,
+		TrippedAt:     parseFieldTime(tc.OpenedAt),
+		CorrelationID: tc.CorrelationID,
+	})
+	msg := workRequeueMessage(s.rigName, tc)
+	if err := s.send(msg); err != nil {
+		if tc.Error == "" {
+			tc.Error = fmt.Sprintf("sending WORK_REQUEUE: %v", err)
+		}
+		return
+	}
+	tc.RequeueMail = msg.ID
+}
+
+// reopenHookedBead resets a tripped polecat's hooked or in-progress bead to
+// open with no assignee. Returns false when the bead is in any other state
+// (already closed, or already requeued).
+func reopenHookedBead(workDir, beadID string) bool {
+	if !canRequeueBead(workDir, beadID) {
+		return false
+	}
+	return util.ExecRun(workDir, "bd", "update", beadID, "--status=open", "--assignee=") == nil
+}
+
+// canRequeueBead reports whether a bead is still hooked or in progress, so
+// reopenHookedBead would requeue it.
+func canRequeueBead(workDir, beadID string) bool {
+	status := getBeadStatus(workDir, beadID)
+	return status == "hooked" || status == "in_progress"
+}
+
+// workRequeueMessage asks the Mayor to re-dispatch work taken from a
+// polecat whose circuit tripped.
+func workRequeueMessage(rigName string, tc *TrippedCircuit) *mail.Message {
+	msg := mail.NewMessage(
+		fmt.Sprintf("%s/witness", rigName),
+		"mayor/",
+		fmt.Sprintf("WORK_REQUEUE %s", tc.HookBead),
+		fmt.Sprintf(`Work requeued from a polecat whose circuit breaker tripped.
+
+Bead: %s
+Polecat: %s/%s
+Failures: %d (%d transient)
+Last failure: %s%s%s
+
+The bead has been reset to open with no assignee.
+%s`,
+			tc.HookBead, rigName, tc.Polecat, tc.FailureCount, tc.Transient, failureReasonOrUnknown(tc.LastFailure),
+			correlationLine(tc), forensicsLine(tc), requeueAdvice(tc)),
+	)
+	msg.Priority = mail.PriorityHigh
+	msg.Type = mail.TypeTask
+	if tc.CorrelationID != "" {
+		// Replies about the reassignment stay on the requeue's thread.
+		msg.ThreadID = tc.CorrelationID
+	}
+	return msg
+}
+
+// requeueAdvice suggests how to re-dispatch work given how its polecat failed.
+func requeueAdvice(tc *TrippedCircuit) string {
+	switch {
+	case tc.Transient > 0 && tc.Transient*2 >= tc.FailureCount:
+		return "Most failures were transient (rate limits, network). Re-dispatch it\nonce the provider has recovered."
+	case tc.LastFailure == beads.FailureDeterministic:
+		return "The work failed deterministically. Check the bead before\nre-dispatching it; another polecat may hit the same failure."
+	default:
+		return "Please re-dispatch it to a different polecat."
+	}
+}
+
+// brokenWorkMessage tells the Mayor a work bead was blocked because it kept
+// failing with different polecats. extra is appended to the failure details
+// (e.g. forensicsLine).
+func brokenWorkMessage(rigName, beadID string, work *beads.WorkFailureFields, extra string) *mail.Message {
+	var failures, transient int
+	failedBy, last := "unknown", beads.FailureUnknown
+	if work != nil {
+		failures, transient = work.FailureCount, work.TransientFailures
+		if len(work.FailedBy) > 0 {
+			failedBy = strings.Join(work.FailedBy, ", ")
+		}
+		last = failureReasonOrUnknown(work.LastFailureReason)
+	}
+	msg := mail.NewMessage(
+		fmt.Sprintf("%s/witness", rigName),
+		"mayor/",
+		fmt.Sprintf("BROKEN_WORK %s", beadID),
+		fmt.Sprintf(`Work bead keeps failing with different polecats; the work itself is
+likely broken, not its workers.
+
+Bead: %s
+Failures: %d (%d transient)
+Failed by: %s
+Last failure: %s%s
+
+The bead has been set to blocked with no assignee so it is not dispatched
+again. Fix or split it, then reopen it with 'bd update %s --status=open'.
+Its failure history is kept, so one more failure blocks it again.`,
+			beadID, failures, transient, failedBy, last, extra, beadID),
+	)
+	msg.Priority = mail.PriorityHigh
+	msg.Type = mail.TypeTask
+	return msg
+}
+
+// correlationLine names the requeue's correlation ID in a mail body, if any.
+func correlationLine(tc *TrippedCircuit) string {
+	if tc.CorrelationID == "" {
+		return ""
+	}
+	return "\nCorrelation: " + tc.CorrelationID
+}
+
+// forensicsLine names the forensic bundle in a mail body, if one was captured.
+func forensicsLine(tc *TrippedCircuit) string {
+	if tc.Forensics == "" {
+		return ""
+	}
+	return "\nForensics: " + tc.Forensics
+}
+
+// remediationLines reports a remediation hook run in a mail body: its exit
+// code and output.
+func remediationLines(tc *TrippedCircuit) string {
+	r := tc.Remediation
+	if r == nil {
+		return ""
+	}
+	line := fmt.Sprintf("\nRemediation hook: %s (exit %d)", r.Hook, r.ExitCode)
+	if r.Error != "" {
+		line = fmt.Sprintf("\nRemediation hook: %s (%s)", r.Hook, r.Error)
+	}
+	if r.Output != "" {
+		line += "\n\n" + r.Output + "\n"
+	}
+	return line
+}
+
+func failureReasonOrUnknown(reason string) string {
+	if reason == "" {
+		return beads.FailureUnknown
+	}
+	return reason
+}
+
+// trippedCircuitEscalation tells the Mayor a tripped polecat could not be
+// nuked automatically.
+func trippedCircuitEscalation(rigName string, tc *TrippedCircuit) *mail.Message {
+	work := "Its work has been requeued."
+	switch {
+	case tc.BrokenWork:
+		work = "Its work was blocked as likely broken (see BROKEN_WORK)."
+	case tc.HookBead == "":
+		work = "It had no work hooked."
+	}
+	resolve := fmt.Sprintf(`Inspect the worktree, recover anything
+worth keeping, then nuke it with 'gt polecat nuke %s/%s'.`, rigName, tc.Polecat)
+	if tc.Quarantine != "" {
+		frozen := "its session is frozen"
+		if !tc.Frozen {
+			frozen = "its session could not be frozen"
+		}
+		resolve = fmt.Sprintf(`It is quarantined: %s and its worktree
+kept. Recover anything worth keeping, then discard it with
+'gt witness quarantine nuke %s/%s', or let it resume with
+'gt witness quarantine release %s/%s'.`, frozen, rigName, tc.Polecat, rigName, tc.Polecat)
+	}
+	msg := mail.NewMessage(
+		fmt.Sprintf("%s/witness", rigName),
+		"mayor/",
+		fmt.Sprintf("CIRCUIT_TRIPPED %s/%s", rigName, tc.Polecat),
+		fmt.Sprintf(`Polecat circuit breaker tripped and the polecat was not nuked.
+
+Polecat: %s/%s
+Failures: %d
+Work: %s
+Nuke: %s%s%s
+
+%s %s`,
+			rigName, tc.Polecat, tc.FailureCount, tc.HookBead, tc.NukeResult, forensicsLine(tc), remediationLines(tc),
+			work, resolve),
+	)
+	msg.Priority = mail.PriorityUrgent
+	return msg
+}
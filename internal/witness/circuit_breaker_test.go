@@ -0,0 +1,741 @@
+package witness
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func TestRecordPolecatFailure_TripsAtMaxFailures(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	id := "gt-gastown-polecat-nux"
+	cfg := CircuitBreakerConfig{MaxFailures: 2, CooldownPeriod: time.Minute}
+
+	f, err := recordPolecatFailure(bd, id, beads.FailureUnknown, cfg, time.Now())
+	if err != nil || f.Tripped || f.FailureCount != 1 {
+		t.Fatalf("first failure = %+v, %v; want count 1, not tripped", f, err)
+	}
+	f, err = recordPolecatFailure(bd, id, beads.FailureUnknown, cfg, time.Now())
+	if err != nil || !f.Tripped || f.FailureCount != 2 {
+		t.Fatalf("second failure = %+v, %v; want count 2, tripped", f, err)
+	}
+	if got := bd.agents[id].CircuitState; got != beads.CircuitOpen {
+		t.Errorf("circuit state = %q, want open", got)
+	}
+
+	// Further failures on an open circuit don't re-trip it.
+	if f, _ := recordPolecatFailure(bd, id, beads.FailureUnknown, cfg, time.Now()); f.Tripped {
+		t.Error("failure on an open circuit reported a new trip")
+	}
+}
+
+func TestRecordPolecatFailure_FailedProbeReopens(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	id := "gt-gastown-polecat-nux"
+	bd.fields(id).CircuitState = beads.CircuitHalfOpen
+
+	f, err := recordPolecatFailure(bd, id, beads.FailureUnknown, DefaultCircuitBreakerConfig(), time.Now())
+	if err != nil || !f.Tripped {
+		t.Fatalf("failed probe = %+v, %v; want tripped", f, err)
+	}
+	if got := bd.agents[id].CircuitState; got != beads.CircuitOpen {
+		t.Errorf("circuit state = %q, want open", got)
+	}
+}
+
+func TestRecordPolecatSuccess_StreakThenProbation(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	bd := newMemoryBeads("gastown")
+	bd.now = func() time.Time { return now }
+	id := "gt-gastown-polecat-nux"
+	*bd.fields(id) = beads.AgentFields{RoleType: "polecat", Rig: "gastown", CircuitState: beads.CircuitHalfOpen, FailureCount: 3, TripCount: 1}
+	cfg := DefaultCircuitBreakerConfig()
+
+	// One landed probe isn't enough: the circuit stays half_open.
+	copied := *bd.agents[id]
+	if reset, err := recordPolecatSuccess(bd, id, &copied, cfg, now); err != nil || reset != "" {
+		t.Fatalf("first probe: reset %q, %v; want none", reset, err)
+	}
+	if got := bd.agents[id]; got.CircuitState != beads.CircuitHalfOpen || got.SuccessStreak != 1 {
+		t.Fatalf("after one probe = %+v, want half_open with a streak of 1", got)
+	}
+
+	copied = *bd.agents[id]
+	if reset, _ := recordPolecatSuccess(bd, id, &copied, cfg, now); reset == "" {
+		t.Fatal("second probe didn't close the circuit")
+	}
+	got := bd.agents[id]
+	if got.CircuitState != beads.CircuitClosed || got.FailureCount != 0 || got.SuccessStreak != 0 ||
+		got.ProbationUntil != now.Add(DefaultCircuitProbationPeriod).Format(time.RFC3339) {
+		t.Fatalf("after the streak = %+v, want closed and on probation", got)
+	}
+
+	// On probation, the first failure reopens the circuit.
+	f, err := recordPolecatFailure(bd, id, beads.FailureUnknown, cfg, now)
+	if err != nil || !f.Tripped || bd.agents[id].ProbationUntil != "" {
+		t.Fatalf("failure on probation = %+v, %v (fields %+v); want tripped, probation over", f, err, bd.agents[id])
+	}
+
+	// Once probation is over, failures count towards max_failures again.
+	_ = bd.ResetAgentFailureCount(id)
+	bd.fields(id).ProbationUntil = now.Add(-time.Minute).Format(time.RFC3339)
+	if f, _ := recordPolecatFailure(bd, id, beads.FailureUnknown, cfg, now); f.Tripped {
+		t.Error("failure after probation tripped at once")
+	}
+}
+
+func TestCircuitSweep_RequeuesNukesAndEscalates(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	clean := "gt-gastown-polecat-clean"
+	dirty := "gt-gastown-polecat-dirty"
+	healthy := "gt-gastown-polecat-ok"
+	*bd.fields(clean) = beads.AgentFields{RoleType: "polecat", Rig: "gastown", CircuitState: beads.CircuitOpen, FailureCount: 3, HookBead: "gt-a"}
+	*bd.fields(dirty) = beads.AgentFields{RoleType: "polecat", Rig: "gastown", FailureCount: 3, HookBead: "gt-b"}
+	*bd.fields(healthy) = beads.AgentFields{RoleType: "polecat", Rig: "gastown", FailureCount: 1}
+
+	var sent []*mail.Message
+	var requeued, captured []string
+	sweep := &circuitSweep{
+		rigName: "gastown",
+		bd:      bd,
+		send:    func(m *mail.Message) error { sent = append(sent, m); return nil },
+		requeue: func(beadID string) bool { requeued = append(requeued, beadID); return true },
+		nuke: func(polecatName string) *NukePolecatResult {
+			if polecatName == "dirty" {
+				return &NukePolecatResult{Skipped: true, Reason: "has uncommitted changes"}
+			}
+			return &NukePolecatResult{Nuked: true, Reason: "auto-nuked"}
+		},
+		capture: func(polecatName string) (string, error) {
+			captured = append(captured, polecatName)
+			return "/town/forensics/gastown/" + polecatName + ".tar.gz", nil
+		},
+	}
+
+	result := sweep.run(DefaultCircuitBreakerConfig())
+	if result.Checked != 3 || result.Open != 2 || len(result.Tripped) != 2 {
+		t.Fatalf("result = %+v, want 3 checked, 2 open", result)
+	}
+	if len(requeued) != 2 {
+		t.Errorf("requeued %v, want gt-a and gt-b", requeued)
+	}
+	if len(bd.cleared) != 2 {
+		t.Errorf("cleared hooks on %v, want both tripped polecats", bd.cleared)
+	}
+
+	byName := map[string]TrippedCircuit{}
+	for _, tc := range result.Tripped {
+		byName[tc.Polecat] = tc
+	}
+	if tc := byName["clean"]; !tc.Nuked || tc.Tripped || tc.Escalation != "" {
+		t.Errorf("clean = %+v, want nuked without escalation", tc)
+	}
+	if tc := byName["dirty"]; tc.Nuked || !tc.Tripped || tc.Escalation == "" {
+		t.Errorf("dirty = %+v, want tripped this sweep and escalated", tc)
+	}
+	if got := bd.agents[dirty].CircuitState; got != beads.CircuitOpen {
+		t.Errorf("dirty circuit state = %q, want open", got)
+	}
+
+	// 2 WORK_REQUEUE + 1 CIRCUIT_TRIPPED
+	if len(sent) != 3 {
+		t.Fatalf("sent %d mails, want 3", len(sent))
+	}
+	for _, m := range sent {
+		if !strings.Contains(m.Body, "Forensics: /town/forensics/gastown/") {
+			t.Errorf("%s body missing the forensics bundle:\n%s", m.Subject, m.Body)
+		}
+	}
+	if len(captured) != 2 {
+		t.Errorf("captured forensics for %v, want both tripped polecats", captured)
+	}
+
+	// The next sweep finds no hooked work, so the dirty polecat is not
+	// escalated again.
+	sent, captured = nil, nil
+	sweep.run(DefaultCircuitBreakerConfig())
+	if len(sent) != 0 || len(captured) != 0 {
+		t.Errorf("second sweep sent %d mails and captured %v, want neither", len(sent), captured)
+	}
+}
+
+func TestRecordWorkFailure_BrokenAcrossPolecats(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	cfg := DefaultCircuitBreakerConfig()
+
+	// A transient failure doesn't count against the work.
+	f := &CircuitFailure{Reason: beads.FailureTransient}
+	if err := recordWorkFailure(bd, f, "gt-a", "gastown/rictus", cfg); err != nil || f.BrokenWork {
+		t.Fatalf("transient failure = %+v, %v; want not broken", f, err)
+	}
+
+	// Three fresh polecats each fail once: no circuit trips, but the work
+	// is flagged broken.
+	for i, name := range []string{"nux", "toast", "slit"} {
+		f, err := recordPolecatFailure(bd, "gt-gastown-polecat-"+name, beads.FailureUnknown, cfg, time.Now())
+		if err != nil || f.Tripped {
+			t.Fatalf("%s failure = %+v, %v; want not tripped", name, f, err)
+		}
+		if err := recordWorkFailure(bd, f, "gt-a", "gastown/"+name, cfg); err != nil {
+			t.Fatal(err)
+		}
+		if want := i == 2; f.BrokenWork != want {
+			t.Errorf("after %s BrokenWork = %v, want %v", name, f.BrokenWork, want)
+		}
+	}
+	if got := bd.work["gt-a"]; got.FailureCount != 4 || len(got.FailedBy) != 4 {
+		t.Errorf("work failures = %+v, want 4 by 4 polecats", got)
+	}
+}
+
+func TestCircuitSweep_BlocksBrokenWork(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	id := "gt-gastown-polecat-nux"
+	*bd.fields(id) = beads.AgentFields{RoleType: "polecat", Rig: "gastown", FailureCount: 3, HookBead: "gt-a"}
+	bd.work["gt-a"] = &beads.WorkFailureFields{FailureCount: 3, FailedBy: []string{"gastown/toast", "gastown/slit", "gastown/nux"}}
+
+	var sent []*mail.Message
+	var blocked []string
+	sweep := &circuitSweep{
+		rigName: "gastown",
+		bd:      bd,
+		send:    func(m *mail.Message) error { sent = append(sent, m); return nil },
+		requeue: func(string) bool { t.Error("broken work was requeued"); return true },
+		block:   func(beadID string) bool { blocked = append(blocked, beadID); return true },
+		nuke:    func(string) *NukePolecatResult { return &NukePolecatResult{Nuked: true} },
+		capture: func(string) (string, error) { return "", nil },
+	}
+	result := sweep.run(DefaultCircuitBreakerConfig())
+
+	if len(result.Tripped) != 1 {
+		t.Fatalf("result = %+v, want one tripped circuit", result)
+	}
+	tc := result.Tripped[0]
+	if !tc.BrokenWork || tc.Requeued || tc.WorkFailures != 3 || tc.BrokenWorkMail == "" {
+		t.Errorf("tripped = %+v, want work blocked and reported, not requeued", tc)
+	}
+	if len(blocked) != 1 || blocked[0] != "gt-a" {
+		t.Errorf("blocked %v, want [gt-a]", blocked)
+	}
+	if len(bd.cleared) != 1 {
+		t.Errorf("cleared hooks on %v, want the polecat's", bd.cleared)
+	}
+	if len(sent) != 1 || sent[0].Subject != "BROKEN_WORK gt-a" {
+		t.Fatalf("sent %v, want one BROKEN_WORK", sent)
+	}
+	if !strings.Contains(sent[0].Body, "Failed by: gastown/toast, gastown/slit, gastown/nux") {
+		t.Errorf("BROKEN_WORK body missing the failing polecats:\n%s", sent[0].Body)
+	}
+}
+
+func TestCircuitSweep_RequeuesOncePerTrip(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	id := "gt-gastown-polecat-nux"
+	*bd.fields(id) = beads.AgentFields{
+		RoleType: "polecat", Rig: "gastown", CircuitState: beads.CircuitOpen, FailureCount: 3,
+		HookBead: "gt-a", CircuitOpenedAt: "2026-03-01T12:00:00Z",
+	}
+
+	var sent []*mail.Message
+	requeues := 0
+	sweep := &circuitSweep{
+		rigName: "gastown",
+		bd:      bd,
+		send:    func(m *mail.Message) error { sent = append(sent, m); return nil },
+		requeue: func(string) bool { requeues++; return true },
+		nuke:    func(string) *NukePolecatResult { return &NukePolecatResult{Skipped: true} },
+		capture: func(string) (string, error) { return "", nil },
+		now:     time.Date(2026, 3, 1, 12, 5, 0, 0, time.UTC),
+	}
+	first := sweep.run(DefaultCircuitBreakerConfig()).Tripped[0]
+	wantID := beads.CorrelationIDFor(beads.RequeueKey("gt-a", "2026-03-01T12:00:00Z"))
+	if !first.Requeued || first.CorrelationID != wantID {
+		t.Fatalf("first sweep = %+v, want requeued with correlation %s", first, wantID)
+	}
+	if len(sent) == 0 || sent[0].ThreadID != wantID || !strings.Contains(sent[0].Body, "Correlation: "+wantID) {
+		t.Fatalf("WORK_REQUEUE not threaded on %s: %+v", wantID, sent)
+	}
+
+	// The hook wasn't cleared (say the write failed), so the next sweep of
+	// the same trip sees the work again; it must not requeue it twice.
+	bd.agents[id].HookBead = "gt-a"
+	sent = nil
+	second := sweep.run(DefaultCircuitBreakerConfig()).Tripped[0]
+	if !second.Deduplicated || second.Requeued || second.CorrelationID != wantID {
+		t.Errorf("second sweep = %+v, want deduplicated under %s", second, wantID)
+	}
+	if requeues != 1 {
+		t.Errorf("requeued %d times, want once", requeues)
+	}
+	for _, m := range sent {
+		t.Errorf("second sweep sent a duplicate %s", m.Subject)
+	}
+}
+
+func TestCircuitSweep_RemediationHookVetoesNuke(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	id := "gt-gastown-polecat-nux"
+	*bd.fields(id) = beads.AgentFields{
+		RoleType: "polecat", Rig: "gastown", CircuitState: beads.CircuitOpen, FailureCount: 3,
+		HookBead: "gt-a", CircuitOpenedAt: "2026-03-01T12:00:00Z",
+	}
+
+	var sent []*mail.Message
+	nukes, hooks := 0, 0
+	sweep := &circuitSweep{
+		rigName: "gastown",
+		bd:      bd,
+		send:    func(m *mail.Message) error { sent = append(sent, m); return nil },
+		requeue: func(string) bool { return true },
+		nuke:    func(string) *NukePolecatResult { nukes++; return &NukePolecatResult{Nuked: true} },
+		capture: func(string) (string, error) { return "", nil },
+		remediate: func(tc *TrippedCircuit) *RemediationResult {
+			hooks++
+			return &RemediationResult{Hook: "./salvage.sh", ExitCode: RemediationVetoExitCode, Vetoed: true, Output: "unpushed branch fix-auth"}
+		},
+		now: time.Date(2026, 3, 1, 12, 5, 0, 0, time.UTC),
+	}
+	tc := sweep.run(DefaultCircuitBreakerConfig()).Tripped[0]
+	if tc.Nuked || nukes != 0 {
+		t.Errorf("vetoed polecat was nuked: %+v", tc)
+	}
+	if !tc.Requeued || !tc.Escalated {
+		t.Errorf("vetoed sweep = %+v, want work requeued and escalated", tc)
+	}
+	want := "remediation hook: unpushed branch fix-auth"
+	if tc.NukeVeto != want || bd.agents[id].NukeVeto != want {
+		t.Errorf("veto = %q (bead %q), want %q", tc.NukeVeto, bd.agents[id].NukeVeto, want)
+	}
+	escalation := sent[len(sent)-1]
+	if !strings.Contains(escalation.Body, "unpushed branch fix-auth") {
+		t.Errorf("escalation missing the hook output:\n%s", escalation.Body)
+	}
+
+	// Later sweeps neither run the hook again, nuke, nor re-escalate.
+	sent = nil
+	tc = sweep.run(DefaultCircuitBreakerConfig()).Tripped[0]
+	if nukes != 0 || hooks != 1 || tc.Escalated || len(sent) != 0 {
+		t.Errorf("second sweep = %+v (nukes %d, hooks %d, mail %d), want it left alone", tc, nukes, hooks, len(sent))
+	}
+	if tc.NukeVeto != want {
+		t.Errorf("second sweep veto = %q, want %q", tc.NukeVeto, want)
+	}
+}
+
+func TestCircuitSweep_SkipsNukedPolecats(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	*bd.fields("gt-gastown-polecat-nux") = beads.AgentFields{
+		RoleType: "polecat", Rig: "gastown", AgentState: "nuked", CircuitState: beads.CircuitOpen, FailureCount: 3,
+	}
+	sweep := &circuitSweep{
+		rigName: "gastown",
+		bd:      bd,
+		send:    func(*mail.Message) error { t.Error("unexpected mail"); return nil },
+		requeue: func(string) bool { t.Error("unexpected requeue"); return false },
+		nuke:    func(string) *NukePolecatResult { t.Error("unexpected nuke"); return &NukePolecatResult{} },
+	}
+	if result := sweep.run(DefaultCircuitBreakerConfig()); result.Open != 1 || len(result.Tripped) != 0 {
+		t.Errorf("result = %+v, want 1 open and nothing acted on", result)
+	}
+}
+
+func TestLoadCircuitBreakerConfig(t *testing.T) {
+	rigPath := t.TempDir()
+
+	cfg, err := LoadCircuitBreakerConfig(rigPath)
+	if err != nil || cfg != DefaultCircuitBreakerConfig() {
+		t.Fatalf("no settings file = %+v, %v; want defaults", cfg, err)
+	}
+
+	settings := config.NewRigSettings()
+	settings.Witness = &config.WitnessConfig{
+		CircuitBreaker: &config.CircuitBreakerSettings{MaxFailures: 5},
+	}
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadCircuitBreakerConfig(rigPath)
+	if err != nil {
+		t.Fatalf("LoadCircuitBreakerConfig() error = %v", err)
+	}
+	if cfg.MaxFailures != 5 || cfg.CooldownPeriod != DefaultCircuitCooldown {
+		t.Errorf("cfg = %+v, want max_failures 5 and the default cooldown", cfg)
+	}
+
+	bad := `{"type":"rig-settings","version":1,"witness":{"circuit_breaker":{"cooldown_period":"soon"}}}`
+	if err := os.WriteFile(filepath.Join(rigPath, "settings", "config.json"), []byte(bad), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCircuitBreakerConfig(rigPath); err == nil || errors.Is(err, config.ErrNotFound) {
+		t.Errorf("invalid cooldown_period: err = %v, want a validation error", err)
+	}
+}
+
+func TestCircuitSweep_HalfOpensAfterCooldown(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	bd := newMemoryBeads("gastown")
+	*bd.fields("gt-gastown-polecat-old") = beads.AgentFields{
+		RoleType: "polecat", Rig: "gastown", AgentState: "nuked", CircuitState: beads.CircuitOpen, FailureCount: 3,
+		CircuitOpenedAt: now.Add(-time.Hour).Format(time.RFC3339),
+	}
+	*bd.fields("gt-gastown-polecat-new") = beads.AgentFields{
+		RoleType: "polecat", Rig: "gastown", AgentState: "nuked", CircuitState: beads.CircuitOpen, FailureCount: 3,
+		CircuitOpenedAt: now.Add(-time.Minute).Format(time.RFC3339),
+	}
+	*bd.fields("gt-gastown-polecat-unstamped") = beads.AgentFields{
+		RoleType: "polecat", Rig: "gastown", AgentState: "nuked", CircuitState: beads.CircuitOpen, FailureCount: 3,
+	}
+	sweep := &circuitSweep{rigName: "gastown", bd: bd, now: now}
+
+	result := sweep.run(CircuitBreakerConfig{MaxFailures: 3, CooldownPeriod: 30 * time.Minute})
+	if len(result.HalfOpen) != 1 || result.HalfOpen[0] != "old" {
+		t.Fatalf("HalfOpen = %v, want [old]", result.HalfOpen)
+	}
+	if got := bd.agents["gt-gastown-polecat-old"].CircuitState; got != beads.CircuitHalfOpen {
+		t.Errorf("old circuit state = %q, want half_open", got)
+	}
+	if got := bd.agents["gt-gastown-polecat-new"].CircuitState; got != beads.CircuitOpen {
+		t.Errorf("new circuit state = %q, want open until its cooldown ends", got)
+	}
+	if bd.agents["gt-gastown-polecat-unstamped"].CircuitOpenedAt == "" {
+		t.Error("unstamped open circuit should get circuit_opened_at")
+	}
+
+	// A failed probe reopens the circuit and restarts the cooldown.
+	f, err := recordPolecatFailure(bd, "gt-gastown-polecat-old", beads.FailureUnknown, DefaultCircuitBreakerConfig(), time.Now())
+	if err != nil || !f.Tripped {
+		t.Fatalf("failed probe = %+v, %v; want tripped", f, err)
+	}
+}
+
+// resettingBeads resets every circuit it lists right after listing it, as
+// a 'gt witness circuit reset' landing mid-sweep would.
+type resettingBeads struct {
+	*memoryBeads
+}
+
+func (r resettingBeads) ListAgentBeadsWithField(key, value string) (map[string]*beads.Issue, error) {
+	agents, err := r.memoryBeads.ListAgentBeadsWithField(key, value)
+	for id := range agents {
+		_ = r.ResetAgentFailureCount(id)
+	}
+	return agents, err
+}
+
+func TestCircuitSweep_LeavesCircuitsChangedSinceListed(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	bd := newMemoryBeads("gastown")
+	*bd.fields("gt-gastown-polecat-failing") = beads.AgentFields{RoleType: "polecat", Rig: "gastown", FailureCount: 3}
+	*bd.fields("gt-gastown-polecat-cooled") = beads.AgentFields{
+		RoleType: "polecat", Rig: "gastown", AgentState: "nuked", CircuitState: beads.CircuitOpen, FailureCount: 3,
+		CircuitOpenedAt: now.Add(-time.Hour).Format(time.RFC3339),
+	}
+	sweep := &circuitSweep{rigName: "gastown", bd: resettingBeads{bd}, now: now}
+
+	result := sweep.run(CircuitBreakerConfig{MaxFailures: 3, CooldownPeriod: 30 * time.Minute})
+	if len(result.Tripped) != 0 || len(result.HalfOpen) != 0 || len(result.Errors) != 0 {
+		t.Fatalf("sweep over reset circuits = tripped %v, half_open %v, errors %v; want nothing done",
+			result.Tripped, result.HalfOpen, result.Errors)
+	}
+	for id, fields := range bd.agents {
+		if fields.CircuitState != beads.CircuitClosed {
+			t.Errorf("%s circuit = %q after the reset, want closed", id, fields.CircuitState)
+		}
+	}
+}
+
+func TestMemoryBeads_UpdateAgentFieldsComparesRevision(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	id := "gt-gastown-polecat-nux"
+	read := *bd.fields(id)
+
+	if _, err := bd.RecordAgentSuccess(id, 1, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bd.OpenAgentCircuit(id); err != nil {
+		t.Fatal(err)
+	}
+	_, err := bd.UpdateAgentFields(id, read.Revision, halfOpen(time.Now()))
+	if !errors.Is(err, beads.ErrAgentFieldsConflict) {
+		t.Fatalf("update from a stale read = %v, want ErrAgentFieldsConflict", err)
+	}
+	if got := bd.agents[id]; got.CircuitState != beads.CircuitOpen || got.Revision != 2 {
+		t.Fatalf("after the conflict = %+v, want open at revision 2", got)
+	}
+
+	updated, err := bd.UpdateAgentFields(id, 2, halfOpen(time.Now()))
+	if err != nil || updated.CircuitState != beads.CircuitHalfOpen || updated.Revision != 3 {
+		t.Errorf("update from the current read = %+v, %v; want half_open at revision 3", updated, err)
+	}
+}
+
+func TestCircuitBreakerConfig_CooldownFor(t *testing.T) {
+	cfg := CircuitBreakerConfig{MaxFailures: 3, CooldownPeriod: 30 * time.Minute, MaxCooldownPeriod: 3 * time.Hour}
+	tests := []struct {
+		trips int
+		want  time.Duration
+	}{
+		{0, 30 * time.Minute},
+		{1, 30 * time.Minute},
+		{2, time.Hour},
+		{3, 2 * time.Hour},
+		{4, 3 * time.Hour}, // capped
+		{60, 3 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := cfg.CooldownFor(tt.trips); got != tt.want {
+			t.Errorf("CooldownFor(%d) = %v, want %v", tt.trips, got, tt.want)
+		}
+	}
+
+	// A cap below the base never shortens it.
+	cfg.MaxCooldownPeriod = time.Minute
+	if got := cfg.CooldownFor(5); got != 30*time.Minute {
+		t.Errorf("CooldownFor with low cap = %v, want the base 30m", got)
+	}
+}
+
+func TestCircuitSweep_RepeatTripsCoolDownLonger(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	bd := newMemoryBeads("gastown")
+	id := "gt-gastown-polecat-nux"
+	*bd.fields(id) = beads.AgentFields{
+		RoleType: "polecat", Rig: "gastown", AgentState: "nuked", CircuitState: beads.CircuitOpen, FailureCount: 6,
+		TripCount: 2, CircuitOpenedAt: now.Add(-45 * time.Minute).Format(time.RFC3339),
+	}
+	sweep := &circuitSweep{rigName: "gastown", bd: bd, now: now}
+	cfg := DefaultCircuitBreakerConfig()
+
+	// Past the 30m base but short of the 1h second-trip cooldown.
+	if result := sweep.run(cfg); len(result.HalfOpen) != 0 {
+		t.Fatalf("HalfOpen = %v after 45m on a second trip, want none", result.HalfOpen)
+	}
+	sweep.now = now.Add(20 * time.Minute)
+	if result := sweep.run(cfg); len(result.HalfOpen) != 1 {
+		t.Fatalf("HalfOpen = %v after 65m on a second trip, want [nux]", result.HalfOpen)
+	}
+
+	// The failed probe is the third trip.
+	f, err := recordPolecatFailure(bd, id, beads.FailureUnknown, cfg, sweep.now)
+	if err != nil || !f.Tripped || f.TripCount != 3 {
+		t.Errorf("failed probe = %+v, %v; want tripped as trip 3", f, err)
+	}
+}
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"API Error: 429 rate_limit_error", beads.FailureTransient},
+		{"Request timed out.", beads.FailureTransient},
+		{"API Error: 529 Overloaded", beads.FailureTransient},
+		{"read ECONNRESET", beads.FailureTransient},
+		{"FAIL: TestParse (0.00s)", beads.FailureUnknown},
+		{"", beads.FailureUnknown},
+	}
+	for _, tt := range tests {
+		if got := ClassifyFailure(tt.output); got != tt.want {
+			t.Errorf("ClassifyFailure(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestRecordPolecatFailure_TransientWeighsLess(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	id := "gt-gastown-polecat-nux"
+	cfg := CircuitBreakerConfig{MaxFailures: 2, CooldownPeriod: time.Minute}
+
+	// Three transient failures score 1; the circuit stays closed.
+	for i := 0; i < 3; i++ {
+		f, err := recordPolecatFailure(bd, id, beads.FailureTransient, cfg, time.Now())
+		if err != nil || f.Tripped {
+			t.Fatalf("transient failure %d = %+v, %v; want not tripped", i+1, f, err)
+		}
+	}
+	// A deterministic failure brings the score to 2.
+	f, err := recordPolecatFailure(bd, id, beads.FailureDeterministic, cfg, time.Now())
+	if err != nil || !f.Tripped || f.Score != 2 || f.FailureCount != 4 {
+		t.Fatalf("deterministic failure = %+v, %v; want tripped at score 2 of 4 failures", f, err)
+	}
+	if got := bd.agents[id].LastFailureReason; got != beads.FailureDeterministic {
+		t.Errorf("last_failure_reason = %q, want deterministic", got)
+	}
+}
+
+func TestWorkRequeueMessage_IncludesClassification(t *testing.T) {
+	tc := &TrippedCircuit{Polecat: "nux", HookBead: "gt-a", FailureCount: 4, Transient: 3, LastFailure: beads.FailureTransient}
+	msg := workRequeueMessage("gastown", tc)
+	for _, want := range []string{"Failures: 4 (3 transient)", "Last failure: transient", "provider has recovered"} {
+		if !strings.Contains(msg.Body, want) {
+			t.Errorf("WORK_REQUEUE body missing %q:\n%s", want, msg.Body)
+		}
+	}
+
+	tc = &TrippedCircuit{Polecat: "nux", HookBead: "gt-a", FailureCount: 3, LastFailure: beads.FailureDeterministic}
+	if msg := workRequeueMessage("gastown", tc); !strings.Contains(msg.Body, "failed deterministically") {
+		t.Errorf("WORK_REQUEUE body missing deterministic advice:\n%s", msg.Body)
+	}
+}
+
+func TestCircuitSweep_DryRunChangesNothing(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	bd := newMemoryBeads("gastown")
+	*bd.fields("gt-gastown-polecat-dirty") = beads.AgentFields{RoleType: "polecat", Rig: "gastown", FailureCount: 3, HookBead: "gt-b"}
+	*bd.fields("gt-gastown-polecat-cool") = beads.AgentFields{
+		RoleType: "polecat", Rig: "gastown", AgentState: "nuked", CircuitState: beads.CircuitOpen, FailureCount: 3,
+		TripCount: 1, CircuitOpenedAt: now.Add(-time.Hour).Format(time.RFC3339),
+	}
+	before := make(map[string]beads.AgentFields)
+	for id, f := range bd.agents {
+		before[id] = *f
+	}
+
+	sweep := &circuitSweep{
+		rigName: "gastown",
+		bd:      bd,
+		requeue: func(string) bool { return true },
+		nuke: func(string) *NukePolecatResult {
+			return &NukePolecatResult{Skipped: true, Reason: "skipped: has uncommitted"}
+		},
+		now:    now,
+		dryRun: true,
+	}
+	result := sweep.run(DefaultCircuitBreakerConfig())
+
+	if !result.DryRun || len(result.Tripped) != 1 || len(result.HalfOpen) != 1 {
+		t.Fatalf("result = %+v, want one trip and one half_open previewed", result)
+	}
+	tc := result.Tripped[0]
+	if !tc.Tripped || tc.TripCount != 1 || !tc.Requeued || tc.Nuked || !tc.Escalated {
+		t.Errorf("dirty = %+v, want would trip, requeue and escalate", tc)
+	}
+	if tc.RequeueMail != "" || tc.Escalation != "" || tc.Forensics != "" {
+		t.Errorf("dry run sent mail or captured forensics: %+v", tc)
+	}
+	for id, f := range bd.agents {
+		if *f != before[id] {
+			t.Errorf("%s changed in a dry run: %+v -> %+v", id, before[id], *f)
+		}
+	}
+	if len(bd.cleared) != 0 {
+		t.Errorf("dry run cleared hooks on %v", bd.cleared)
+	}
+}
+
+func TestCircuitNote(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if got, want := circuitNote("tripped", "mayor", "pushes to\nthe wrong  branch", at),
+		"tripped by mayor at 2026-03-01T12:00:00Z: pushes to the wrong branch"; got != want {
+		t.Errorf("circuitNote() = %q, want %q", got, want)
+	}
+	if got, want := circuitNote("reset", "human", "", at), "reset by human at 2026-03-01T12:00:00Z"; got != want {
+		t.Errorf("circuitNote() without reason = %q, want %q", got, want)
+	}
+}
+
+func TestCircuitSweep_BatchesAndPacesActions(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	for _, name := range []string{"a", "b", "c"} {
+		*bd.fields("gt-gastown-polecat-" + name) = beads.AgentFields{RoleType: "polecat", Rig: "gastown", FailureCount: 3, HookBead: "gt-" + name}
+	}
+	// A quarantined polecat with nothing hooked needs no action and
+	// doesn't count against the limit.
+	*bd.fields("gt-gastown-polecat-held") = beads.AgentFields{
+		RoleType: "polecat", Rig: "gastown", CircuitState: beads.CircuitOpen, FailureCount: 3, Quarantine: "has uncommitted",
+	}
+
+	var nuked []string
+	var paced []time.Duration
+	sweep := &circuitSweep{
+		rigName: "gastown",
+		bd:      bd,
+		send:    func(*mail.Message) error { return nil },
+		requeue: func(string) bool { return true },
+		nuke: func(polecatName string) *NukePolecatResult {
+			nuked = append(nuked, polecatName)
+			bd.fields("gt-gastown-polecat-" + polecatName).AgentState = "nuked"
+			return &NukePolecatResult{Nuked: true}
+		},
+		capture: func(string) (string, error) { return "", nil },
+		pace:    func(d time.Duration) { paced = append(paced, d) },
+		now:     time.Now(),
+	}
+	cfg := DefaultCircuitBreakerConfig()
+	cfg.MaxActionsPerSweep = 2
+	cfg.ActionPacing = 3 * time.Second
+
+	result := sweep.run(cfg)
+	if result.Acted != 2 || result.Deferred != 1 || len(nuked) != 2 {
+		t.Fatalf("acted %d, deferred %d, nuked %v; want 2 acted, 1 deferred", result.Acted, result.Deferred, nuked)
+	}
+	if len(paced) != 1 || paced[0] != 3*time.Second {
+		t.Errorf("paced %v, want one 3s pause between the two actions", paced)
+	}
+	var deferred TrippedCircuit
+	for _, tc := range result.Tripped {
+		if tc.Deferred {
+			deferred = tc
+		}
+	}
+	if deferred.Polecat != "c" || !deferred.Tripped || deferred.Requeued {
+		t.Errorf("deferred = %+v, want c tripped but not requeued", deferred)
+	}
+	if got := bd.agents["gt-gastown-polecat-c"]; got.CircuitState != beads.CircuitOpen || got.HookBead != "gt-c" {
+		t.Errorf("c = %+v, want its circuit open and its work still hooked", got)
+	}
+
+	// The next sweep picks up the deferred circuit.
+	nuked = nil
+	result = sweep.run(cfg)
+	if result.Acted != 1 || result.Deferred != 0 || len(nuked) != 1 || nuked[0] != "c" {
+		t.Errorf("second sweep acted %d, deferred %d, nuked %v; want c", result.Acted, result.Deferred, nuked)
+	}
+}
+
+func TestCircuitSweep_ScansOnlyFailingPolecats(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	for i := 0; i < 120; i++ {
+		*bd.fields(fmt.Sprintf("gt-gastown-polecat-ok%03d", i)) = beads.AgentFields{RoleType: "polecat", Rig: "gastown", AgentState: "working"}
+	}
+	for i := 0; i < 20; i++ {
+		*bd.fields(fmt.Sprintf("gt-gastown-polecat-bad%02d", i)) = beads.AgentFields{RoleType: "polecat", Rig: "gastown", FailureCount: 3}
+	}
+	*bd.fields("gt-gastown-polecat-flaky") = beads.AgentFields{RoleType: "polecat", Rig: "gastown", FailureCount: 1}
+	*bd.fields("gt-gastown-polecat-other") = beads.AgentFields{RoleType: "polecat", Rig: "beads", FailureCount: 3}
+
+	sweep := &circuitSweep{
+		rigName: "gastown",
+		bd:      bd,
+		send:    func(*mail.Message) error { return nil },
+		nuke:    func(string) *NukePolecatResult { return &NukePolecatResult{Nuked: true} },
+		capture: func(string) (string, error) { return "", nil },
+		now:     time.Now(),
+	}
+	cfg := DefaultCircuitBreakerConfig()
+	cfg.MaxActionsPerSweep = 0
+
+	result := sweep.run(cfg)
+	if result.Checked != 21 || result.Open != 20 || len(result.Tripped) != 20 {
+		t.Fatalf("checked %d, open %d, tripped %d; want 21 checked (the failing polecats), 20 open", result.Checked, result.Open, len(result.Tripped))
+	}
+	for i, tc := range result.Tripped {
+		if want := fmt.Sprintf("bad%02d", i); tc.Polecat != want || !tc.Tripped || tc.Error != "" {
+			t.Fatalf("tripped[%d] = %+v, want %s tripped, in order", i, tc, want)
+		}
+		if got := bd.agents[tc.AgentBeadID].CircuitState; got != beads.CircuitOpen {
+			t.Errorf("%s circuit = %q, want open", tc.Polecat, got)
+		}
+	}
+	if got := bd.agents["gt-gastown-polecat-other"].CircuitState; got != "" {
+		t.Errorf("another rig's polecat was tripped: circuit = %q", got)
+	}
+}
@@ -0,0 +1,255 @@
+package witness
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// circuitHistoryFile holds a rig's circuit breaker events under
+// <rig>/.runtime/.
+const circuitHistoryFile = "circuit_history.jsonl"
+
+// circuitHistoryMaxEvents caps how many events are kept per rig.
+const circuitHistoryMaxEvents = 5000
+
+// Circuit breaker events recorded in a rig's history. Failures and
+// successes are the breaker's inputs, recorded so 'gt witness simulate' can
+// replay them; the rest are what it did.
+const (
+	CircuitEventFailure    = "failure"    // A failure was counted against a polecat
+	CircuitEventSuccess    = "success"    // A polecat's work landed
+	CircuitEventTrip       = "trip"       // The circuit opened
+	CircuitEventHalfOpen   = "half_open"  // Cooldown over: the polecat may take a probe assignment
+	CircuitEventReset      = "reset"      // The circuit closed: work landed, or reset or released by hand
+	CircuitEventRequeue    = "requeue"    // A tripped polecat's work was requeued
+	CircuitEventNuke       = "nuke"       // A tripped polecat was nuked
+	CircuitEventQuarantine = "quarantine" // A tripped polecat that couldn't be nuked safely was quarantined
+)
+
+// CircuitEvent is one entry in a rig's circuit breaker history.
+type CircuitEvent struct {
+	Time     time.Time `json:"time"`
+	Event    string    `json:"event"`
+	Polecat  string    `json:"polecat"`
+	Bead     string    `json:"bead,omitempty"`     // work the polecat had hooked
+	Failures int       `json:"failures,omitempty"` // failure count when the circuit tripped
+	Reason   string    `json:"reason,omitempty"`   // failure reason or why it was tripped/reset
+	Actor    string    `json:"actor,omitempty"`    // who tripped or reset it by hand
+
+	// TrippedAt is when the trip being acted on opened, on requeue,
+	// quarantine and nuke events.
+	TrippedAt     *time.Time `json:"tripped_at,omitempty"`
+	CorrelationID string     `json:"correlation_id,omitempty"`
+}
+
+// CircuitHistoryPath returns the path of a rig's circuit breaker history.
+func CircuitHistoryPath(rigPath string) string {
+	return filepath.Join(rigPath, constants.DirRuntime, circuitHistoryFile)
+}
+
+// AppendCircuitEvent adds an event to the rig's circuit breaker history,
+// keeping only the most recent circuitHistoryMaxEvents. The sweep, the
+// monitors and manual overrides write from different processes, so the
+// file is updated under a lock.
+func AppendCircuitEvent(rigPath string, ev CircuitEvent) error {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	ev.Time = ev.Time.UTC()
+
+	path := CircuitHistoryPath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating circuit history dir: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking circuit history: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	events, err := LoadCircuitHistory(rigPath)
+	if err != nil {
+		return err
+	}
+	events = append(events, ev)
+	if len(events) > circuitHistoryMaxEvents {
+		events = events[len(events)-circuitHistoryMaxEvents:]
+	}
+
+	var b strings.Builder
+	for _, e := range events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("encoding circuit event: %w", err)
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0644); err != nil { //nolint:gosec // G306: not sensitive
+		return fmt.Errorf("writing circuit history: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadCircuitHistory reads a rig's circuit breaker history, oldest first. A
+// missing history yields no events; malformed lines are skipped.
+func LoadCircuitHistory(rigPath string) ([]CircuitEvent, error) {
+	events, err := LoadCircuitEvents(CircuitHistoryPath(rigPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return events, err
+}
+
+// LoadCircuitEvents reads circuit events, one JSON object per line, from
+// path. Malformed lines are skipped.
+func LoadCircuitEvents(path string) ([]CircuitEvent, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading circuit events: %w", err)
+	}
+	defer f.Close()
+
+	var events []CircuitEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev CircuitEvent
+		if json.Unmarshal(scanner.Bytes(), &ev) == nil {
+			events = append(events, ev)
+		}
+	}
+	return events, scanner.Err()
+}
+
+// recordCircuitEvent appends ev to the history of rigName's rig.
+// Best-effort: the history is for tuning, and must not fail the action it
+// records.
+func recordCircuitEvent(townRoot, rigName string, ev CircuitEvent) {
+	_ = AppendCircuitEvent(filepath.Join(townRoot, rigName), ev)
+}
+
+// circuitRecorder appends events to a rig's circuit history. A nil
+// recorder drops them, so tests need not write files.
+type circuitRecorder func(CircuitEvent)
+
+// rigCircuitRecorder records to the history of rigName's rig.
+func rigCircuitRecorder(townRoot, rigName string) circuitRecorder {
+	return func(ev CircuitEvent) { recordCircuitEvent(townRoot, rigName, ev) }
+}
+
+func (r circuitRecorder) record(ev CircuitEvent) {
+	if r != nil {
+		r(ev)
+	}
+}
+
+// BeadTrips counts the trips of polecats working on one bead.
+type BeadTrips struct {
+	Bead  string `json:"bead"`
+	Trips int    `json:"trips"`
+}
+
+// circuitMetricsTopBeads is how many of the most-tripped beads are reported.
+const circuitMetricsTopBeads = 5
+
+// CircuitMetrics summarizes a rig's circuit breaker history over a window.
+type CircuitMetrics struct {
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+
+	Trips       int     `json:"trips"`
+	TripsPerDay float64 `json:"trips_per_day"`
+	HalfOpens   int     `json:"half_opens"`
+	Resets      int     `json:"resets"`
+	Requeues    int     `json:"requeues"`
+	Nukes       int     `json:"nukes"`
+	Quarantines int     `json:"quarantines"`
+
+	// MeanFailuresBeforeTrip is the mean failure count at a trip, over
+	// trips caused by failures (not manual, resource or stall trips).
+	MeanFailuresBeforeTrip float64 `json:"mean_failures_before_trip"`
+
+	// MeanTripToRequeue is the mean time from a trip to its work being
+	// requeued.
+	MeanTripToRequeue time.Duration `json:"mean_trip_to_requeue_ns"`
+
+	// TopBeads are the work beads whose polecats tripped most often.
+	TopBeads []BeadTrips `json:"top_beads,omitempty"`
+}
+
+// ComputeCircuitMetrics summarizes the events in [since, until].
+func ComputeCircuitMetrics(events []CircuitEvent, since, until time.Time) CircuitMetrics {
+	m := CircuitMetrics{Since: since, Until: until}
+	var failures, failureTrips int
+	var toRequeue time.Duration
+	var timedRequeues int
+	beadTrips := make(map[string]int)
+
+	for _, ev := range events {
+		if ev.Time.Before(since) || ev.Time.After(until) {
+			continue
+		}
+		switch ev.Event {
+		case CircuitEventTrip:
+			m.Trips++
+			if ev.Failures > 0 {
+				failures += ev.Failures
+				failureTrips++
+			}
+			if ev.Bead != "" {
+				beadTrips[ev.Bead]++
+			}
+		case CircuitEventHalfOpen:
+			m.HalfOpens++
+		case CircuitEventReset:
+			m.Resets++
+		case CircuitEventRequeue:
+			m.Requeues++
+			if ev.TrippedAt != nil && !ev.Time.Before(*ev.TrippedAt) {
+				toRequeue += ev.Time.Sub(*ev.TrippedAt)
+				timedRequeues++
+			}
+		case CircuitEventNuke:
+			m.Nukes++
+		case CircuitEventQuarantine:
+			m.Quarantines++
+		}
+	}
+
+	if days := until.Sub(since).Hours() / 24; days > 0 {
+		m.TripsPerDay = float64(m.Trips) / days
+	}
+	if failureTrips > 0 {
+		m.MeanFailuresBeforeTrip = float64(failures) / float64(failureTrips)
+	}
+	if timedRequeues > 0 {
+		m.MeanTripToRequeue = toRequeue / time.Duration(timedRequeues)
+	}
+
+	for bead, trips := range beadTrips {
+		m.TopBeads = append(m.TopBeads, BeadTrips{Bead: bead, Trips: trips})
+	}
+	sort.Slice(m.TopBeads, func(i, j int) bool {
+		if m.TopBeads[i].Trips != m.TopBeads[j].Trips {
+			return m.TopBeads[i].Trips > m.TopBeads[j].Trips
+		}
+		return m.TopBeads[i].Bead < m.TopBeads[j].Bead
+	})
+	if len(m.TopBeads) > circuitMetricsTopBeads {
+		m.TopBeads = m.TopBeads[:circuitMetricsTopBeads]
+	}
+	return m
+}
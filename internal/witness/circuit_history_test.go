@@ -0,0 +1,95 @@
+package witness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func TestCircuitHistory_AppendAndLoad(t *testing.T) {
+	rigPath := t.TempDir()
+	if events, err := LoadCircuitHistory(rigPath); err != nil || len(events) != 0 {
+		t.Fatalf("empty history = %v, %v", events, err)
+	}
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, ev := range []CircuitEvent{
+		{Time: at, Event: CircuitEventTrip, Polecat: "nux", Bead: "gt-a", Failures: 3},
+		{Time: at.Add(time.Minute), Event: CircuitEventRequeue, Polecat: "nux", Bead: "gt-a", TrippedAt: &at},
+	} {
+		if err := AppendCircuitEvent(rigPath, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	events, err := LoadCircuitHistory(rigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Event != CircuitEventTrip || events[1].TrippedAt == nil || !events[1].TrippedAt.Equal(at) {
+		t.Errorf("history = %+v", events)
+	}
+}
+
+func TestComputeCircuitMetrics(t *testing.T) {
+	now := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	since := now.Add(-7 * 24 * time.Hour)
+	day := func(n int) time.Time { return since.Add(time.Duration(n) * 24 * time.Hour) }
+	trippedAt := day(2)
+
+	events := []CircuitEvent{
+		{Time: since.Add(-time.Hour), Event: CircuitEventTrip, Polecat: "old", Bead: "gt-old", Failures: 9}, // before the window
+		{Time: day(1), Event: CircuitEventTrip, Polecat: "nux", Bead: "gt-a", Failures: 3},
+		{Time: trippedAt, Event: CircuitEventTrip, Polecat: "slit", Bead: "gt-a", Failures: 4},
+		{Time: trippedAt.Add(10 * time.Minute), Event: CircuitEventRequeue, Polecat: "slit", Bead: "gt-a", TrippedAt: &trippedAt},
+		{Time: day(3), Event: CircuitEventTrip, Polecat: "furiosa", Bead: "gt-b", Actor: "mayor"}, // manual
+		{Time: day(4), Event: CircuitEventHalfOpen, Polecat: "nux"},
+		{Time: day(5), Event: CircuitEventReset, Polecat: "nux"},
+		{Time: day(5), Event: CircuitEventNuke, Polecat: "slit"},
+	}
+	m := ComputeCircuitMetrics(events, since, now)
+	if m.Trips != 3 || m.TripsPerDay != 3.0/7 || m.HalfOpens != 1 || m.Resets != 1 || m.Requeues != 1 || m.Nukes != 1 {
+		t.Errorf("counts = %+v", m)
+	}
+	if m.MeanFailuresBeforeTrip != 3.5 {
+		t.Errorf("mean failures before trip = %v, want 3.5 (manual trip excluded)", m.MeanFailuresBeforeTrip)
+	}
+	if m.MeanTripToRequeue != 10*time.Minute {
+		t.Errorf("mean trip to requeue = %v, want 10m", m.MeanTripToRequeue)
+	}
+	if len(m.TopBeads) != 2 || m.TopBeads[0] != (BeadTrips{Bead: "gt-a", Trips: 2}) {
+		t.Errorf("top beads = %+v, want gt-a first with 2 trips", m.TopBeads)
+	}
+}
+
+func TestCircuitSweep_RecordsHistory(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	*bd.fields("gt-gastown-polecat-nux") = beads.AgentFields{RoleType: "polecat", Rig: "gastown", FailureCount: 3, HookBead: "gt-a", LastFailureReason: beads.FailureDeterministic}
+
+	var events []CircuitEvent
+	sweep := &circuitSweep{
+		rigName: "gastown",
+		bd:      bd,
+		send:    func(*mail.Message) error { return nil },
+		requeue: func(string) bool { return true },
+		nuke:    func(string) *NukePolecatResult { return &NukePolecatResult{Nuked: true} },
+		capture: func(string) (string, error) { return "", nil },
+		history: func(ev CircuitEvent) { events = append(events, ev) },
+		now:     time.Now(),
+	}
+	sweep.run(DefaultCircuitBreakerConfig())
+
+	var kinds []string
+	for _, ev := range events {
+		kinds = append(kinds, ev.Event)
+	}
+	if len(events) != 3 || kinds[0] != CircuitEventTrip || kinds[1] != CircuitEventRequeue || kinds[2] != CircuitEventNuke {
+		t.Fatalf("events = %v, want trip, requeue, nuke", kinds)
+	}
+	if trip := events[0]; trip.Failures != 3 || trip.Bead != "gt-a" || trip.Reason != beads.FailureDeterministic {
+		t.Errorf("trip = %+v", trip)
+	}
+	if events[1].TrippedAt == nil {
+		t.Error("requeue event has no trip time")
+	}
+}
@@ -0,0 +1,129 @@
+package witness
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// CircuitStatus is one polecat's circuit breaker as recorded on its agent
+// bead.
+type CircuitStatus struct {
+	Polecat           string     `json:"polecat"`
+	AgentBeadID       string     `json:"agent_bead"`
+	AgentState        string     `json:"agent_state,omitempty"`
+	State             string     `json:"circuit_state"`
+	FailureCount      int        `json:"failure_count"`
+	TransientFailures int        `json:"transient_failures,omitempty"`
+	TripCount         int        `json:"trip_count,omitempty"`
+	HookBead          string     `json:"hook_bead,omitempty"`
+	LastFailureReason string     `json:"last_failure_reason,omitempty"`
+	LastFailureAt     *time.Time `json:"last_failure_at,omitempty"`
+	OpenedAt          *time.Time `json:"opened_at,omitempty"`
+	Note              string     `json:"note,omitempty"` // last manual reset/trip
+
+	// ResourceUsage is the resource monitor's latest sample of the
+	// polecat's session; ResourceStrikes counts consecutive samples over
+	// the rig's budget.
+	ResourceUsage   string `json:"resource_usage,omitempty"`
+	ResourceStrikes int    `json:"resource_strikes,omitempty"`
+
+	// StallState is how stall detection has handled the polecat since its
+	// output froze at OutputChangedAt ("" = not stalled).
+	StallState      string     `json:"stall_state,omitempty"`
+	OutputChangedAt *time.Time `json:"output_changed_at,omitempty"`
+
+	// Quarantine is why the polecat is held for a human ("" = not
+	// quarantined); see 'gt witness quarantine'.
+	Quarantine    string     `json:"quarantine,omitempty"`
+	QuarantinedAt *time.Time `json:"quarantined_at,omitempty"`
+
+	// SuccessStreak counts the probes a half_open polecat has landed in a
+	// row; ProbationUntil is when a polecat whose circuit closed again
+	// comes off probation (nil once it has).
+	SuccessStreak  int        `json:"success_streak,omitempty"`
+	ProbationUntil *time.Time `json:"probation_until,omitempty"`
+
+	// CooldownExpiresAt is when an open circuit goes half_open. A
+	// quarantined circuit has none: it stays open until resolved.
+	CooldownExpiresAt *time.Time `json:"cooldown_expires_at,omitempty"`
+}
+
+// ListCircuits returns the circuit breaker status of every polecat agent
+// bead in the rig, sorted by polecat name.
+func ListCircuits(workDir, rigName string, cfg CircuitBreakerConfig) ([]CircuitStatus, error) {
+	bd, _ := rigBeads(workDir, rigName)
+	agents, err := bd.Query().Role("polecat").Rig(rigName).Run()
+	if err != nil {
+		return nil, fmt.Errorf("listing agent beads: %w", err)
+	}
+	return circuitStatuses(agents, rigName, cfg), nil
+}
+
+func circuitStatuses(agents map[string]*beads.Issue, rigName string, cfg CircuitBreakerConfig) []CircuitStatus {
+	now := time.Now()
+	var statuses []CircuitStatus
+	for id, issue := range agents {
+		fields := beads.ParseAgentFields(issue.Description)
+		if fields.RoleType != "polecat" || fields.Rig != rigName {
+			continue
+		}
+		_, _, polecatName, ok := beads.ParseAgentBeadID(id)
+		if !ok || polecatName == "" {
+			continue
+		}
+
+		st := CircuitStatus{
+			Polecat:           polecatName,
+			AgentBeadID:       id,
+			AgentState:        issue.AgentState,
+			State:             fields.CircuitState,
+			FailureCount:      fields.FailureCount,
+			TransientFailures: fields.TransientFailures,
+			TripCount:         fields.TripCount,
+			HookBead:          fields.HookBead,
+			LastFailureReason: fields.LastFailureReason,
+			LastFailureAt:     parseFieldTime(fields.LastFailureAt),
+			OpenedAt:          parseFieldTime(fields.CircuitOpenedAt),
+			Note:              fields.CircuitNote,
+			ResourceUsage:     fields.ResourceUsage,
+			ResourceStrikes:   fields.ResourceStrikes,
+			StallState:        fields.StallState,
+			OutputChangedAt:   parseFieldTime(fields.OutputChangedAt),
+			Quarantine:        fields.Quarantine,
+			QuarantinedAt:     parseFieldTime(fields.QuarantinedAt),
+			SuccessStreak:     fields.SuccessStreak,
+		}
+		if st.State == "" {
+			st.State = beads.CircuitClosed
+		}
+		if st.AgentState == "" {
+			st.AgentState = fields.AgentState
+		}
+		if st.HookBead == "" {
+			st.HookBead = issue.HookBead
+		}
+		if fields.OnProbation(now) {
+			st.ProbationUntil = parseFieldTime(fields.ProbationUntil)
+		}
+		if st.State == beads.CircuitOpen && st.OpenedAt != nil && st.Quarantine == "" {
+			expires := st.OpenedAt.Add(cfg.CooldownFor(fields.TripCount))
+			st.CooldownExpiresAt = &expires
+		}
+		statuses = append(statuses, st)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Polecat < statuses[j].Polecat })
+	return statuses
+}
+
+// parseFieldTime parses an RFC3339 agent bead field, nil when unset or
+// malformed.
+func parseFieldTime(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
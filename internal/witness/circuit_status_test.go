@@ -0,0 +1,47 @@
+package witness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestCircuitStatuses(t *testing.T) {
+	opened := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	agents := map[string]*beads.Issue{
+		"gt-gastown-polecat-nux": {Description: beads.FormatAgentDescription("nux", &beads.AgentFields{
+			RoleType: "polecat", Rig: "gastown", CircuitState: beads.CircuitOpen, FailureCount: 3, TripCount: 2,
+			CircuitOpenedAt: opened.Format(time.RFC3339), LastFailureAt: opened.Format(time.RFC3339),
+			LastFailureReason: beads.FailureDeterministic,
+		})},
+		"gt-gastown-polecat-ace": {Description: beads.FormatAgentDescription("ace", &beads.AgentFields{
+			RoleType: "polecat", Rig: "gastown", HookBead: "gt-abc",
+		})},
+		"gt-other-polecat-zed": {Description: beads.FormatAgentDescription("zed", &beads.AgentFields{
+			RoleType: "polecat", Rig: "other",
+		})},
+		"gt-gastown-witness": {Description: beads.FormatAgentDescription("witness", &beads.AgentFields{
+			RoleType: "witness", Rig: "gastown",
+		})},
+	}
+
+	got := circuitStatuses(agents, "gastown", DefaultCircuitBreakerConfig())
+	if len(got) != 2 || got[0].Polecat != "ace" || got[1].Polecat != "nux" {
+		t.Fatalf("statuses = %+v, want ace and nux", got)
+	}
+
+	ace := got[0]
+	if ace.State != beads.CircuitClosed || ace.HookBead != "gt-abc" || ace.CooldownExpiresAt != nil || ace.LastFailureAt != nil {
+		t.Errorf("ace = %+v, want closed with hook gt-abc", ace)
+	}
+
+	nux := got[1]
+	if nux.State != beads.CircuitOpen || nux.FailureCount != 3 || nux.LastFailureAt == nil {
+		t.Errorf("nux = %+v", nux)
+	}
+	// Second trip: the 30m base cooldown doubles.
+	if nux.CooldownExpiresAt == nil || !nux.CooldownExpiresAt.Equal(opened.Add(time.Hour)) {
+		t.Errorf("nux cooldown expires %v, want %v", nux.CooldownExpiresAt, opened.Add(time.Hour))
+	}
+}
@@ -0,0 +1,210 @@
+package witness
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DepUpdateLabel tags work beads opened by the dependency-update duty.
+// It is also used to detect an already-open update bead so the duty never
+// stacks a second one on top of unfinished work.
+const DepUpdateLabel = "gt:dep-update"
+
+// DefaultDepUpdatePriority is the priority assigned to dependency-update
+// beads when none is configured. Routine upkeep should never outrank
+// feature or incident work, so this doubles as the priority cap.
+const DefaultDepUpdatePriority = 3
+
+// DefaultDepUpdateInterval is how often a rig gets a dependency-update bead.
+const DefaultDepUpdateInterval = 7 * 24 * time.Hour
+
+// DepUpdateOptions configures a single run of the dependency-update duty.
+type DepUpdateOptions struct {
+	// Interval is the minimum time between update beads for a rig.
+	Interval time.Duration
+
+	// Priority is the requested bead priority. Values more urgent than
+	// MaxPriority are capped.
+	Priority int
+
+	// MaxPriority is the most urgent priority the duty may assign
+	// (lower number = more urgent). Defaults to DefaultDepUpdatePriority.
+	MaxPriority int
+
+	// Labels are extra labels applied in addition to DepUpdateLabel.
+	Labels []string
+
+	// Now overrides the clock (for tests). Zero means time.Now().
+	Now time.Time
+}
+
+// DepUpdateResult describes what the duty did for one rig.
+type DepUpdateResult struct {
+	Rig     string
+	BeadID  string // Bead opened (or the existing open bead when skipped)
+	Opened  bool
+	Skipped string // Reason for skipping, empty when a bead was opened
+}
+
+// depUpdateState is persisted per rig so the cadence survives daemon restarts.
+type depUpdateState struct {
+	LastOpened time.Time `json:"last_opened"`
+	LastBead   string    `json:"last_bead,omitempty"`
+}
+
+// depUpdateBeads is the subset of beads operations the duty needs.
+type depUpdateBeads interface {
+	List(opts beads.ListOptions) ([]*beads.Issue, error)
+	Create(opts beads.CreateOptions) (*beads.Issue, error)
+}
+
+// DepUpdateStateFile returns the path to the rig's dependency-update state file.
+func DepUpdateStateFile(townRoot, rigName string) string {
+	return filepath.Join(townRoot, rigName, "witness", "dep-updates.json")
+}
+
+// CapDepUpdatePriority clamps priority so it is never more urgent than maxPriority.
+// Out-of-range values fall back to maxPriority.
+func CapDepUpdatePriority(priority, maxPriority int) int {
+	if maxPriority < 0 || maxPriority > 4 {
+		maxPriority = DefaultDepUpdatePriority
+	}
+	if priority < maxPriority || priority > 4 {
+		return maxPriority
+	}
+	return priority
+}
+
+func loadDepUpdateState(path string) (*depUpdateState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &depUpdateState{}, nil
+		}
+		return nil, err
+	}
+	var state depUpdateState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// OpenDependencyUpdate opens a routine dependency-update work bead for a rig
+// when the cadence has elapsed and no earlier update bead is still open.
+// The bead is an ordinary task, so it flows through the normal
+// sling → polecat → refinery pipeline like any other work.
+func OpenDependencyUpdate(townRoot, rigName string, opts DepUpdateOptions) (*DepUpdateResult, error) {
+	rigPath := filepath.Join(townRoot, rigName)
+	bd := beads.NewWithBeadsDir(rigPath, beads.ResolveBeadsDir(rigPath))
+	return openDependencyUpdate(bd, townRoot, rigName, opts)
+}
+
+func openDependencyUpdate(bd depUpdateBeads, townRoot, rigName string, opts DepUpdateOptions) (*DepUpdateResult, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultDepUpdateInterval
+	}
+	result := &DepUpdateResult{Rig: rigName}
+
+	statePath := DepUpdateStateFile(townRoot, rigName)
+	state, err := loadDepUpdateState(statePath)
+	if err != nil {
+		return nil, err
+	}
+	if !state.LastOpened.IsZero() && now.Sub(state.LastOpened) < interval {
+		result.BeadID = state.LastBead
+		result.Skipped = fmt.Sprintf("next update due in %s", state.LastOpened.Add(interval).Sub(now).Round(time.Minute))
+		return result, nil
+	}
+
+	// Never stack a second update bead on top of unfinished upkeep, whether
+	// it's waiting, hooked or in progress.
+	updates, err := bd.List(beads.ListOptions{Status: "all", Label: DepUpdateLabel, Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing dependency updates: %w", err)
+	}
+	for _, update := range updates {
+		if update.Status != "closed" && update.Status != "tombstone" {
+			result.BeadID = update.ID
+			result.Skipped = fmt.Sprintf("%s still %s", update.ID, update.Status)
+			return result, nil
+		}
+	}
+
+	maxPriority := opts.MaxPriority
+	if maxPriority == 0 {
+		maxPriority = DefaultDepUpdatePriority
+	}
+	priority := opts.Priority
+	if priority == 0 {
+		priority = maxPriority
+	}
+	priority = CapDepUpdatePriority(priority, maxPriority)
+
+	issue, err := bd.Create(beads.CreateOptions{
+		Title:       fmt.Sprintf("Routine dependency updates for %s (%s)", rigName, now.Format("2006-01-02")),
+		Type:        "task",
+		Priority:    priority,
+		Description: depUpdateDescription(rigName),
+		Labels:      dedupeLabels(append([]string{DepUpdateLabel}, opts.Labels...)),
+		Actor:       rigName + "/witness",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating dependency update bead: %w", err)
+	}
+
+	state.LastOpened = now
+	state.LastBead = issue.ID
+	if err := os.MkdirAll(filepath.Dir(statePath), 0755); err != nil {
+		return nil, fmt.Errorf("creating witness dir: %w", err)
+	}
+	if err := util.AtomicWriteJSON(statePath, state); err != nil {
+		return nil, fmt.Errorf("saving dependency update state: %w", err)
+	}
+
+	result.BeadID = issue.ID
+	result.Opened = true
+	return result, nil
+}
+
+func depUpdateDescription(rigName string) string {
+	return strings.Join([]string{
+		fmt.Sprintf("Routine dependency upkeep for rig %s, opened by the witness dependency-update duty.", rigName),
+		"",
+		"## Scope",
+		"- Bump direct dependencies to their latest compatible (non-major) versions",
+		"- Refresh lockfiles / go.sum and remove unused dependencies",
+		"- Do NOT take major-version upgrades; file a separate bead for each instead",
+		"",
+		"## Acceptance criteria",
+		"- Build and tests pass on the updated dependency set",
+		"- MR description lists each dependency bumped (old → new)",
+	}, "\n")
+}
+
+func dedupeLabels(labels []string) []string {
+	seen := make(map[string]bool, len(labels))
+	var out []string
+	for _, l := range labels {
+		l = strings.TrimSpace(l)
+		if l == "" || seen[l] {
+			continue
+		}
+		seen[l] = true
+		out = append(out, l)
+	}
+	return out
+}
@@ -0,0 +1,126 @@
+package witness
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// fakeDepUpdateBeads records create calls and serves a canned list.
+type fakeDepUpdateBeads struct {
+	open    []*beads.Issue
+	created []beads.CreateOptions
+	listErr error
+}
+
+func (f *fakeDepUpdateBeads) List(opts beads.ListOptions) ([]*beads.Issue, error) {
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	return f.open, nil
+}
+
+func (f *fakeDepUpdateBeads) Create(opts beads.CreateOptions) (*beads.Issue, error) {
+	f.created = append(f.created, opts)
+	return &beads.Issue{ID: "gt-dep1", Title: opts.Title, Priority: opts.Priority}, nil
+}
+
+func TestOpenDependencyUpdate_OpensAndLabels(t *testing.T) {
+	townRoot := t.TempDir()
+	bd := &fakeDepUpdateBeads{}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	result, err := openDependencyUpdate(bd, townRoot, "gastown", DepUpdateOptions{
+		Labels: []string{"area:deps", DepUpdateLabel},
+		Now:    now,
+	})
+	if err != nil {
+		t.Fatalf("openDependencyUpdate: %v", err)
+	}
+	if !result.Opened || result.BeadID != "gt-dep1" {
+		t.Fatalf("result = %+v, want opened gt-dep1", result)
+	}
+	if len(bd.created) != 1 {
+		t.Fatalf("created %d beads, want 1", len(bd.created))
+	}
+	if got := bd.created[0].Priority; got != DefaultDepUpdatePriority {
+		t.Errorf("priority = %d, want %d", got, DefaultDepUpdatePriority)
+	}
+	if got := bd.created[0].Labels; len(got) != 2 || got[0] != DepUpdateLabel || got[1] != "area:deps" {
+		t.Errorf("labels = %v, want [%s area:deps]", got, DepUpdateLabel)
+	}
+	if _, err := os.Stat(DepUpdateStateFile(townRoot, "gastown")); err != nil {
+		t.Errorf("state file not written: %v", err)
+	}
+}
+
+func TestOpenDependencyUpdate_RespectsCadence(t *testing.T) {
+	townRoot := t.TempDir()
+	bd := &fakeDepUpdateBeads{}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if _, err := openDependencyUpdate(bd, townRoot, "gastown", DepUpdateOptions{Now: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A day later: not yet due
+	result, err := openDependencyUpdate(bd, townRoot, "gastown", DepUpdateOptions{Now: now.Add(24 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Opened || result.Skipped == "" {
+		t.Errorf("expected skip before cadence elapsed, got %+v", result)
+	}
+
+	// A week later: due again
+	result, err = openDependencyUpdate(bd, townRoot, "gastown", DepUpdateOptions{Now: now.Add(DefaultDepUpdateInterval)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Opened {
+		t.Errorf("expected a new bead once cadence elapsed, got %+v", result)
+	}
+}
+
+func TestOpenDependencyUpdate_SkipsWhenStillOpen(t *testing.T) {
+	bd := &fakeDepUpdateBeads{open: []*beads.Issue{{ID: "gt-done", Status: "closed"}, {ID: "gt-old", Status: "in_progress"}}}
+
+	result, err := openDependencyUpdate(bd, t.TempDir(), "gastown", DepUpdateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Opened || result.BeadID != "gt-old" {
+		t.Errorf("result = %+v, want skip pointing at gt-old", result)
+	}
+	if len(bd.created) != 0 {
+		t.Errorf("created %d beads, want 0", len(bd.created))
+	}
+}
+
+func TestOpenDependencyUpdate_ListError(t *testing.T) {
+	bd := &fakeDepUpdateBeads{listErr: errors.New("bd down")}
+	if _, err := openDependencyUpdate(bd, t.TempDir(), "gastown", DepUpdateOptions{}); err == nil {
+		t.Fatal("expected error when listing fails")
+	}
+}
+
+func TestCapDepUpdatePriority(t *testing.T) {
+	tests := []struct {
+		priority, max, want int
+	}{
+		{priority: 0, max: 3, want: 3}, // P0 capped
+		{priority: 2, max: 3, want: 3}, // more urgent than cap
+		{priority: 4, max: 3, want: 4}, // less urgent is kept
+		{priority: 2, max: 2, want: 2},
+		{priority: 9, max: 2, want: 2}, // out of range
+		{priority: 1, max: -1, want: DefaultDepUpdatePriority},
+	}
+	for _, tt := range tests {
+		if got := CapDepUpdatePriority(tt.priority, tt.max); got != tt.want {
+			t.Errorf("CapDepUpdatePriority(%d, %d) = %d, want %d", tt.priority, tt.max, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package witness
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// EscalationBatchThreshold is the number of escalations in one sweep at which
+// the witness stops mailing them one by one and sends the Mayor a single
+// digest instead. When main breaks, every polecat fails the same way at
+// once; dozens of near-identical urgent mails bury the one fact that matters.
+const EscalationBatchThreshold = 3
+
+// Escalation is one polecat escalation queued in an EscalationBatcher.
+type Escalation struct {
+	Polecat string
+	IssueID string
+	Kind    string // e.g. "RECOVERY_NEEDED", "HELP"
+	// Detail is the one-line failure description. Escalations whose
+	// details normalize to the same signature are grouped as a likely
+	// common cause in the digest.
+	Detail string
+	// Message is sent as-is when the sweep stays under the batch threshold.
+	Message *mail.Message
+}
+
+// EscalationFlush reports how a batch went out.
+type EscalationFlush struct {
+	Digest  bool     // true if the batch was sent as one digest to the Mayor
+	MailIDs []string // mail ID for each escalation, in Add order
+}
+
+// EscalationBatcher collects the escalations raised during one witness
+// sweep. Flush sends them individually when there are only a few, or as
+// one digest with a common-cause hypothesis when there are many.
+type EscalationBatcher struct {
+	rigName   string
+	threshold int
+	pending   []Escalation
+
+	// send delivers a message (router.Send); injectable for tests.
+	send func(*mail.Message) error
+}
+
+// NewEscalationBatcher creates a batcher that sends through router.
+func NewEscalationBatcher(rigName string, router *mail.Router) *EscalationBatcher {
+	return &EscalationBatcher{
+		rigName:   rigName,
+		threshold: EscalationBatchThreshold,
+		send:      router.Send,
+	}
+}
+
+// Add queues an escalation. A nil batcher drops it, so callers that run
+// without a router need no special casing.
+func (b *EscalationBatcher) Add(e Escalation) {
+	if b == nil {
+		return
+	}
+	b.pending = append(b.pending, e)
+}
+
+// Len returns the number of queued escalations.
+func (b *EscalationBatcher) Len() int {
+	if b == nil {
+		return 0
+	}
+	return len(b.pending)
+}
+
+// Flush sends the queued escalations and empties the queue. Below the
+// threshold each escalation's own message is sent; otherwise one digest
+// goes to the Mayor. Individual send failures don't stop the rest.
+func (b *EscalationBatcher) Flush() (*EscalationFlush, error) {
+	flush := &EscalationFlush{}
+	if b == nil || len(b.pending) == 0 {
+		return flush, nil
+	}
+	pending := b.pending
+	b.pending = nil
+
+	if len(pending) >= b.threshold {
+		digest := BuildEscalationDigest(b.rigName, pending)
+		if err := b.send(digest); err != nil {
+			return flush, fmt.Errorf("sending escalation digest: %w", err)
+		}
+		flush.Digest = true
+		for range pending {
+			flush.MailIDs = append(flush.MailIDs, digest.ID)
+		}
+		return flush, nil
+	}
+
+	var errs []error
+	for _, e := range pending {
+		if err := b.send(e.Message); err != nil {
+			errs = append(errs, fmt.Errorf("escalating %s: %w", e.Polecat, err))
+			flush.MailIDs = append(flush.MailIDs, "")
+			continue
+		}
+		flush.MailIDs = append(flush.MailIDs, e.Message.ID)
+	}
+	return flush, errors.Join(errs...)
+}
+
+// BuildEscalationDigest builds the single Mayor mail that replaces a batch
+// of escalations: a common-cause hypothesis first, then every escalation
+// so nothing is lost by batching.
+func BuildEscalationDigest(rigName string, escalations []Escalation) *mail.Message {
+	priority := mail.PriorityHigh
+	for _, e := range escalations {
+		if e.Message != nil && e.Message.Priority == mail.PriorityUrgent {
+			priority = mail.PriorityUrgent
+		}
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "%d polecat escalations in %s arrived in one witness sweep and were batched into this digest.\n\n",
+		len(escalations), rigName)
+	body.WriteString("## Common-cause hypothesis\n\n")
+	for _, line := range commonCauseHypothesis(escalations) {
+		fmt.Fprintf(&body, "%s\n", line)
+	}
+	body.WriteString("\n## Escalations\n\n")
+	for _, e := range escalations {
+		issue := ""
+		if e.IssueID != "" {
+			issue = fmt.Sprintf(" (%s)", e.IssueID)
+		}
+		fmt.Fprintf(&body, "- %s/%s%s [%s]: %s\n", rigName, e.Polecat, issue, e.Kind, e.Detail)
+	}
+	body.WriteString("\nThe individual escalations were not sent separately.")
+
+	msg := mail.NewMessage(
+		fmt.Sprintf("%s/witness", rigName),
+		"mayor/",
+		fmt.Sprintf("ESCALATION_DIGEST %s: %d polecats need attention", rigName, len(escalations)),
+		body.String(),
+	)
+	msg.Priority = priority
+	msg.Type = mail.TypeTask
+	return msg
+}
+
+// Volatile parts of failure text that differ between polecats hitting the
+// same underlying failure.
+var (
+	signatureHexRe  = regexp.MustCompile(`\b[0-9a-f]{7,40}\b`)
+	signaturePathRe = regexp.MustCompile(`(?:/[\w.-]+){2,}`)
+	signatureNumRe  = regexp.MustCompile(`\d+`)
+)
+
+// failureSignature normalizes failure text so that the same failure seen
+// from different worktrees, commits, and line numbers compares equal.
+func failureSignature(detail string) string {
+	s := strings.ToLower(detail)
+	s = signatureHexRe.ReplaceAllString(s, "<sha>")
+	s = signaturePathRe.ReplaceAllString(s, "<path>")
+	s = signatureNumRe.ReplaceAllString(s, "<n>")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// sharedBranchWords mark failures that usually mean the shared base is
+// broken rather than each polecat's own change.
+var sharedBranchWords = []string{"main", "master", "build", "compile", "test", "ci", "lint", "dependency", "go.mod", "package.json"}
+
+// commonCauseHypothesis groups escalations by failure signature and kind
+// and states what the largest group suggests. It is evidence for the
+// Mayor, not a diagnosis: it reports what the escalations share.
+func commonCauseHypothesis(escalations []Escalation) []string {
+	total := len(escalations)
+	bySig := make(map[string][]Escalation)
+	byKind := make(map[string]int)
+	for _, e := range escalations {
+		sig := failureSignature(e.Detail)
+		bySig[sig] = append(bySig[sig], e)
+		byKind[e.Kind]++
+	}
+
+	sigs := make([]string, 0, len(bySig))
+	for sig := range bySig {
+		sigs = append(sigs, sig)
+	}
+	sort.Slice(sigs, func(i, j int) bool {
+		if len(bySig[sigs[i]]) != len(bySig[sigs[j]]) {
+			return len(bySig[sigs[i]]) > len(bySig[sigs[j]])
+		}
+		return sigs[i] < sigs[j]
+	})
+
+	var lines []string
+	top := bySig[sigs[0]]
+	if len(top) < 2 {
+		lines = append(lines, "No two escalations share a failure signature; these look like independent problems that happened to coincide.")
+	} else {
+		var names []string
+		for _, e := range top {
+			names = append(names, e.Polecat)
+		}
+		lines = append(lines, fmt.Sprintf("%d of %d share one failure: %q (%s).",
+			len(top), total, top[0].Detail, strings.Join(names, ", ")))
+		if mentionsSharedBase(sigs[0]) {
+			lines = append(lines, "The failure names a build, test, or the default branch: the shared base is probably broken. Check recent merges to the default branch before re-dispatching, or more polecats will fail the same way.")
+		} else {
+			lines = append(lines, "A shared environment problem (credentials, network, tooling, or a bad dependency) is more likely than independent bugs in each polecat's work.")
+		}
+		if rest := total - len(top); rest > 0 {
+			lines = append(lines, fmt.Sprintf("The other %d escalation(s) have different failures and may be unrelated.", rest))
+		}
+	}
+
+	if len(byKind) == 1 {
+		for kind := range byKind {
+			lines = append(lines, fmt.Sprintf("All %d are %s escalations.", total, kind))
+		}
+	}
+	return lines
+}
+
+func mentionsSharedBase(signature string) bool {
+	words := strings.FieldsFunc(signature, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r == '.' || r == '_')
+	})
+	for _, w := range words {
+		for _, shared := range sharedBranchWords {
+			if w == shared || w == shared+"s" || w == shared+"ing" {
+				return true
+			}
+		}
+	}
+	return false
+}
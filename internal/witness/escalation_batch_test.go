@@ -0,0 +1,212 @@
+package witness
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// testBatcher returns a batcher that records sent mail instead of routing it.
+func testBatcher(sent *[]*mail.Message) *EscalationBatcher {
+	return &EscalationBatcher{
+		rigName:   "gastown",
+		threshold: EscalationBatchThreshold,
+		send: func(msg *mail.Message) error {
+			if msg.ID == "" {
+				msg.ID = fmt.Sprintf("hq-%d", len(*sent))
+			}
+			*sent = append(*sent, msg)
+			return nil
+		},
+	}
+}
+
+func helpEscalation(polecat, problem string) Escalation {
+	return Escalation{
+		Polecat: polecat,
+		Kind:    "HELP",
+		Detail:  problem,
+		Message: helpEscalationMessage("gastown", &HelpPayload{Agent: "gastown/" + polecat, Problem: problem}, "Test failures require investigation"),
+	}
+}
+
+func TestEscalationBatcher_BelowThresholdSendsIndividually(t *testing.T) {
+	var sent []*mail.Message
+	b := testBatcher(&sent)
+	b.Add(helpEscalation("toast", "test fail in pkg/a"))
+	b.Add(helpEscalation("nux", "test fail in pkg/b"))
+
+	flush, err := b.Flush()
+	if err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if flush.Digest || len(sent) != 2 {
+		t.Fatalf("expected 2 individual mails, got digest=%v sent=%d", flush.Digest, len(sent))
+	}
+	for i, msg := range sent {
+		if msg.To != "deacon/" || flush.MailIDs[i] != msg.ID {
+			t.Errorf("mail %d: to=%s id=%s, flush id=%s", i, msg.To, msg.ID, flush.MailIDs[i])
+		}
+	}
+	if b.Len() != 0 {
+		t.Errorf("Flush should empty the queue, Len = %d", b.Len())
+	}
+}
+
+func TestEscalationBatcher_DigestAtThreshold(t *testing.T) {
+	var sent []*mail.Message
+	b := testBatcher(&sent)
+	b.Add(helpEscalation("toast", "test fail: TestMerge at /home/gt/gastown/polecats/toast/merge_test.go:42 on main@a1b2c3d"))
+	b.Add(helpEscalation("nux", "test fail: TestMerge at /home/gt/gastown/polecats/nux/merge_test.go:43 on main@d4e5f6a"))
+	b.Add(helpEscalation("furiosa", "test fail: TestMerge at /home/gt/gastown/polecats/furiosa/merge_test.go:42 on main@a1b2c3d"))
+	b.Add(helpEscalation("slit", "requirements unclear for gt-123"))
+
+	flush, err := b.Flush()
+	if err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if !flush.Digest || len(sent) != 1 {
+		t.Fatalf("expected one digest, got digest=%v sent=%d", flush.Digest, len(sent))
+	}
+	digest := sent[0]
+	if digest.To != "mayor/" || !strings.HasPrefix(digest.Subject, "ESCALATION_DIGEST gastown: 4 polecats") {
+		t.Errorf("digest to=%s subject=%q", digest.To, digest.Subject)
+	}
+	for _, want := range []string{
+		"## Common-cause hypothesis",
+		"3 of 4 share one failure",
+		"(toast, nux, furiosa)",
+		"shared base is probably broken",
+		"The other 1 escalation(s) have different failures",
+		"All 4 are HELP escalations.",
+		"- gastown/slit [HELP]: requirements unclear for gt-123",
+	} {
+		if !strings.Contains(digest.Body, want) {
+			t.Errorf("digest body missing %q:\n%s", want, digest.Body)
+		}
+	}
+	for _, id := range flush.MailIDs {
+		if id != digest.ID {
+			t.Errorf("every batched escalation should map to the digest, got %v", flush.MailIDs)
+		}
+	}
+}
+
+func TestEscalationBatcher_DigestPriorityFollowsUrgent(t *testing.T) {
+	escs := []Escalation{helpEscalation("toast", "x"), helpEscalation("nux", "y")}
+	if got := BuildEscalationDigest("gastown", escs).Priority; got != mail.PriorityHigh {
+		t.Errorf("priority = %v, want high", got)
+	}
+	escs = append(escs, Escalation{
+		Polecat: "furiosa",
+		Kind:    "RECOVERY_NEEDED",
+		Message: recoveryNeededMessage("gastown", &RecoveryPayload{PolecatName: "furiosa"}),
+	})
+	if got := BuildEscalationDigest("gastown", escs).Priority; got != mail.PriorityUrgent {
+		t.Errorf("priority = %v, want urgent", got)
+	}
+}
+
+func TestEscalationBatcher_IndividualSendErrors(t *testing.T) {
+	var sent []*mail.Message
+	b := testBatcher(&sent)
+	record := b.send
+	b.send = func(msg *mail.Message) error {
+		if strings.Contains(msg.Subject, "toast") {
+			return errors.New("mailbox locked")
+		}
+		return record(msg)
+	}
+	b.Add(helpEscalation("toast", "a"))
+	b.Add(helpEscalation("nux", "b"))
+
+	flush, err := b.Flush()
+	if err == nil || !strings.Contains(err.Error(), "escalating toast: mailbox locked") {
+		t.Errorf("err = %v", err)
+	}
+	if len(sent) != 1 || flush.MailIDs[0] != "" || flush.MailIDs[1] == "" {
+		t.Errorf("one failure should not stop the rest: sent=%d ids=%v", len(sent), flush.MailIDs)
+	}
+}
+
+func TestEscalationBatcher_Nil(t *testing.T) {
+	var b *EscalationBatcher
+	b.Add(helpEscalation("toast", "a"))
+	if flush, err := b.Flush(); err != nil || len(flush.MailIDs) != 0 || b.Len() != 0 {
+		t.Errorf("nil batcher should be a no-op: %v %v", flush, err)
+	}
+}
+
+func TestCommonCauseHypothesis_Independent(t *testing.T) {
+	lines := commonCauseHypothesis([]Escalation{
+		{Polecat: "toast", Kind: "HELP", Detail: "requirements unclear"},
+		{Polecat: "nux", Kind: "RECOVERY_NEEDED", Detail: "died with cleanup_status=has_unpushed"},
+		{Polecat: "slit", Kind: "HELP", Detail: "git conflict in README"},
+	})
+	if len(lines) != 1 || !strings.Contains(lines[0], "independent problems") {
+		t.Errorf("lines = %v", lines)
+	}
+}
+
+func TestCommonCauseHypothesis_SharedEnvironment(t *testing.T) {
+	lines := commonCauseHypothesis([]Escalation{
+		{Polecat: "toast", Kind: "RECOVERY_NEEDED", Detail: "died with cleanup_status=has_unpushed"},
+		{Polecat: "nux", Kind: "RECOVERY_NEEDED", Detail: "died with cleanup_status=has_unpushed"},
+		{Polecat: "slit", Kind: "RECOVERY_NEEDED", Detail: "died with cleanup_status=has_unpushed"},
+	})
+	joined := strings.Join(lines, "\n")
+	if !strings.Contains(joined, "3 of 3 share one failure") || !strings.Contains(joined, "shared environment problem") {
+		t.Errorf("lines = %v", lines)
+	}
+}
+
+func TestFailureSignature(t *testing.T) {
+	a := failureSignature("FAIL /home/gt/rig/polecats/toast/x_test.go:12 at a1b2c3d4")
+	b := failureSignature("fail /tmp/other/polecats/nux/x_test.go:99 at 9f8e7d6c")
+	if a != b {
+		t.Errorf("signatures differ:\n%s\n%s", a, b)
+	}
+}
+
+func TestHandleHelpBatch(t *testing.T) {
+	helpMsg := func(name, topic, problem string) *mail.Message {
+		return &mail.Message{
+			ID:      "msg-" + name,
+			Subject: "HELP: " + topic,
+			Body:    fmt.Sprintf("Agent: gastown/polecats/%s\nIssue: gt-%s\nProblem: %s\n", name, name, problem),
+		}
+	}
+
+	var sent []*mail.Message
+	results := handleHelpBatch("gastown", []*mail.Message{
+		helpMsg("toast", "tests", "test fail on main"),
+		helpMsg("nux", "tests", "test fail on main"),
+		helpMsg("slit", "build", "compile error"), // witness can help: not escalated
+		helpMsg("furiosa", "tests", "test fail on main"),
+	}, testBatcher(&sent))
+
+	if len(sent) != 1 || sent[0].To != "mayor/" {
+		t.Fatalf("expected one digest to mayor/, got %d mails", len(sent))
+	}
+	if !strings.Contains(sent[0].Body, "- gastown/toast (gt-toast) [HELP]: test fail on main") {
+		t.Errorf("digest should list polecats by name:\n%s", sent[0].Body)
+	}
+	for _, i := range []int{0, 1, 3} {
+		r := results[i]
+		if !r.Handled || r.MailSent != sent[0].ID || !strings.HasSuffix(r.Action, "to mayor in digest") {
+			t.Errorf("result %d = %+v", i, r)
+		}
+	}
+	if r := results[2]; !r.Handled || r.MailSent != "" || !strings.HasPrefix(r.Action, "can help") {
+		t.Errorf("build help should be handled locally, got %+v", r)
+	}
+
+	sent = nil
+	results = handleHelpBatch("gastown", []*mail.Message{helpMsg("toast", "tests", "test fail on main")}, testBatcher(&sent))
+	if len(sent) != 1 || sent[0].To != "deacon/" || !strings.HasSuffix(results[0].Action, "to deacon") {
+		t.Errorf("a lone escalation should go to the deacon as before: %+v", results[0])
+	}
+}
@@ -0,0 +1,129 @@
+package witness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// escalationWebhookTimeout bounds one escalation webhook POST.
+const escalationWebhookTimeout = 10 * time.Second
+
+// EscalationPolicy routes the escalations the Witness raises about polecats:
+// CIRCUIT_TRIPPED, BROKEN_WORK, RESOURCE_BUDGET and POLECAT_STALLED. The
+// zero policy mails the Mayor at each escalation's own priority.
+// WORK_REQUEUE is not an escalation: it always goes to the Mayor, who
+// dispatches work.
+type EscalationPolicy struct {
+	To       string        // mail address; "" means the Mayor
+	Priority mail.Priority // "" keeps each escalation's own
+	Webhook  string        // also POSTed here as JSON; "" means none
+
+	town string // town name in webhook payloads
+
+	// post delivers a webhook notification; replaced in tests.
+	post func(ctx context.Context, n deacon.Notification) error
+}
+
+// EscalationPolicyFrom builds the policy from a rig's escalation settings.
+// Nil settings give the zero policy.
+func EscalationPolicyFrom(s *config.WitnessEscalationSettings) (EscalationPolicy, error) {
+	if s == nil {
+		return EscalationPolicy{}, nil
+	}
+	if err := config.ValidateWitnessEscalationSettings(s); err != nil {
+		return EscalationPolicy{}, err
+	}
+	p := EscalationPolicy{To: s.To, Webhook: s.Webhook}
+	if s.Priority != "" {
+		p.Priority = mail.ParsePriority(s.Priority)
+	}
+	return p, nil
+}
+
+// LoadEscalationPolicy reads the escalation policy from the rig's
+// settings/config.json ("witness.escalation"). A rig without a settings
+// file gets the zero policy; an invalid file is an error.
+func LoadEscalationPolicy(rigPath string) (EscalationPolicy, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return EscalationPolicy{}, nil
+		}
+		return EscalationPolicy{}, err
+	}
+	var s *config.WitnessEscalationSettings
+	if settings.Witness != nil {
+		s = settings.Witness.Escalation
+	}
+	p, err := EscalationPolicyFrom(s)
+	p.town = filepath.Base(filepath.Dir(rigPath))
+	return p, err
+}
+
+// rigEscalationPolicy loads the policy of rigName's rig. Escalations must
+// not be lost to a bad settings file, so one that can't be read routes them
+// to the Mayor.
+func rigEscalationPolicy(townRoot, rigName string) EscalationPolicy {
+	p, err := LoadEscalationPolicy(filepath.Join(townRoot, rigName))
+	if err != nil {
+		return EscalationPolicy{town: filepath.Base(townRoot)}
+	}
+	return p
+}
+
+// Target returns where the policy mails escalations.
+func (p EscalationPolicy) Target() string {
+	if p.To == "" {
+		return "mayor/"
+	}
+	return p.To
+}
+
+// Escalate readdresses msg per the policy, sends it, and posts it to the
+// webhook. The webhook is tried even if the mail fails, so an on-call human
+// still hears about it; both errors are returned.
+func (p EscalationPolicy) Escalate(send func(*mail.Message) error, msg *mail.Message) error {
+	if p.To != "" {
+		msg.To = p.To
+	}
+	if p.Priority != "" {
+		msg.Priority = p.Priority
+	}
+	mailErr := send(msg)
+	if p.Webhook == "" {
+		return mailErr
+	}
+
+	post := p.post
+	if post == nil {
+		sink := &deacon.WebhookSink{URL: p.Webhook}
+		post = sink.Notify
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), escalationWebhookTimeout)
+	defer cancel()
+	n := deacon.Notification{
+		Event:   deacon.NotifyEventEscalation,
+		Title:   msg.Subject,
+		Message: msg.Body,
+		Town:    p.town,
+		Time:    time.Now().UTC(),
+		Fields: map[string]string{
+			"from":     msg.From,
+			"to":       msg.To,
+			"priority": string(msg.Priority),
+			"mail_id":  msg.ID,
+		},
+	}
+	var webhookErr error
+	if err := post(ctx, n); err != nil {
+		webhookErr = fmt.Errorf("escalation webhook: %w", err)
+	}
+	return errors.Join(mailErr, webhookErr)
+}
@@ -0,0 +1,65 @@
+package witness
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func TestEscalationPolicy_Escalate(t *testing.T) {
+	policy, err := EscalationPolicyFrom(&config.WitnessEscalationSettings{
+		To:       "gastown/crew/overseer",
+		Priority: "normal",
+		Webhook:  "https://pager.example.com/hook/secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var posted []deacon.Notification
+	policy.post = func(_ context.Context, n deacon.Notification) error {
+		posted = append(posted, n)
+		return nil
+	}
+
+	var sent []*mail.Message
+	msg := trippedCircuitEscalation("gastown", &TrippedCircuit{Polecat: "nux", HookBead: "gt-abc"})
+	if err := policy.Escalate(func(m *mail.Message) error { sent = append(sent, m); return nil }, msg); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent[0].To != "gastown/crew/overseer" || sent[0].Priority != mail.PriorityNormal {
+		t.Fatalf("sent = %+v, want one mail to the overseer at normal priority", sent)
+	}
+	if len(posted) != 1 || posted[0].Title != "CIRCUIT_TRIPPED gastown/nux" || posted[0].Event != deacon.NotifyEventEscalation ||
+		posted[0].Fields["to"] != "gastown/crew/overseer" || posted[0].Fields["mail_id"] != msg.ID {
+		t.Errorf("posted = %+v, want the escalation", posted)
+	}
+
+	// The webhook still hears about it when the mail fails.
+	posted = nil
+	err = policy.Escalate(func(*mail.Message) error { return errors.New("mail down") }, brokenWorkMessage("gastown", "gt-abc", nil, ""))
+	if err == nil || len(posted) != 1 {
+		t.Errorf("err = %v, posted %d; want the mail error and one post", err, len(posted))
+	}
+}
+
+func TestEscalationPolicy_ZeroMailsMayor(t *testing.T) {
+	var policy EscalationPolicy
+	var sent []*mail.Message
+	msg := trippedCircuitEscalation("gastown", &TrippedCircuit{Polecat: "nux"})
+	if err := policy.Escalate(func(m *mail.Message) error { sent = append(sent, m); return nil }, msg); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent[0].To != "mayor/" || sent[0].Priority != mail.PriorityUrgent {
+		t.Errorf("sent = %+v, want the Mayor at the escalation's own priority", sent)
+	}
+	if policy.Target() != "mayor/" {
+		t.Errorf("Target = %q, want mayor/", policy.Target())
+	}
+	if _, err := EscalationPolicyFrom(&config.WitnessEscalationSettings{Priority: "asap"}); err == nil {
+		t.Error("invalid priority accepted")
+	}
+}
@@ -0,0 +1,153 @@
+package witness
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/util"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// ForensicsDir is the town-relative directory holding forensic bundles.
+const ForensicsDir = "forensics"
+
+// ForensicManifest describes a forensic bundle: what it holds, and what
+// couldn't be captured.
+type ForensicManifest struct {
+	Rig        string            `json:"rig"`
+	Polecat    string            `json:"polecat"`
+	CapturedAt time.Time         `json:"captured_at"`
+	Session    string            `json:"session"`
+	Worktree   string            `json:"worktree"`
+	AgentBead  string            `json:"agent_bead"`
+	Files      []string          `json:"files"`
+	Missing    map[string]string `json:"missing,omitempty"` // file -> why it wasn't captured
+}
+
+// ForensicBundlePath returns where the bundle for a polecat captured at ts
+// is written: <town>/forensics/<rig>/<polecat>-<ts>.tar.gz.
+func ForensicBundlePath(townRoot, rigName, polecatName string, ts time.Time) string {
+	name := fmt.Sprintf("%s-%s.tar.gz", polecatName, ts.UTC().Format("20060102T150405Z"))
+	return filepath.Join(townRoot, ForensicsDir, rigName, name)
+}
+
+// CaptureForensics saves what a polecat leaves behind before it is nuked:
+// its tmux pane history, the git status and diff of its worktree, and its
+// agent bead. Sources that can't be read (a dead session, a missing
+// worktree) are noted in the manifest rather than failing the capture.
+// Returns the bundle path.
+func CaptureForensics(workDir, rigName, polecatName string) (string, error) {
+	townRoot, err := workspace.Find(workDir)
+	if err != nil || townRoot == "" {
+		return "", fmt.Errorf("finding town root: %v", err)
+	}
+	now := time.Now()
+	m := &ForensicManifest{
+		Rig:        rigName,
+		Polecat:    polecatName,
+		CapturedAt: now.UTC(),
+		Session:    session.PolecatSessionName(session.PrefixFor(rigName), polecatName),
+		Worktree:   polecatWorktree(townRoot, rigName, polecatName),
+		AgentBead:  polecatAgentBeadID(townRoot, rigName, polecatName),
+	}
+
+	files := make(map[string][]byte)
+	m.Missing = make(map[string]string)
+	collect := func(name string, out string, err error) {
+		if err != nil {
+			m.Missing[name] = err.Error()
+			return
+		}
+		files[name] = []byte(out)
+	}
+
+	pane, err := tmux.NewTmux().CapturePaneAll(m.Session)
+	collect("pane.txt", pane, err)
+	if _, err := os.Stat(m.Worktree); err != nil {
+		m.Missing["git-status.txt"] = err.Error()
+		m.Missing["git-diff.txt"] = err.Error()
+	} else {
+		status, err := util.ExecWithOutput(m.Worktree, "git", "status", "--branch", "--porcelain=v1")
+		collect("git-status.txt", status, err)
+		diff, err := util.ExecWithOutput(m.Worktree, "git", "diff", "HEAD")
+		collect("git-diff.txt", diff, err)
+	}
+	agent, err := util.ExecWithOutput(workDir, "bd", "show", m.AgentBead, "--json")
+	collect("agent-bead.json", agent, err)
+
+	path := ForensicBundlePath(townRoot, rigName, polecatName, now)
+	if err := writeForensicBundle(path, m, files); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// polecatWorktree returns a polecat's git worktree, handling both the
+// polecats/<name>/<rig>/ and the older polecats/<name>/ layouts.
+func polecatWorktree(townRoot, rigName, polecatName string) string {
+	path := filepath.Join(townRoot, rigName, "polecats", polecatName, rigName)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		path = filepath.Join(townRoot, rigName, "polecats", polecatName)
+	}
+	return path
+}
+
+// writeForensicBundle writes manifest.json and files as a gzipped tar at
+// path, filling in m.Files.
+func writeForensicBundle(path string, m *ForensicManifest, files map[string][]byte) error {
+	m.Files = m.Files[:0]
+	for name := range files {
+		m.Files = append(m.Files, name)
+	}
+	sort.Strings(m.Files)
+	if len(m.Missing) == 0 {
+		m.Missing = nil
+	}
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating forensics dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		return fmt.Errorf("creating forensic bundle: %w", err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	add := func(name string, data []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), ModTime: m.CapturedAt}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	if err := add("manifest.json", manifest); err != nil {
+		return err
+	}
+	for _, name := range m.Files {
+		if err := add(name, files[name]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,72 @@
+package witness
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestForensicBundlePath(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 12, 30, 5, 0, time.UTC)
+	got := ForensicBundlePath("/town", "gastown", "nux", ts)
+	want := filepath.Join("/town", "forensics", "gastown", "nux-20260301T123005Z.tar.gz")
+	if got != want {
+		t.Errorf("ForensicBundlePath() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteForensicBundle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "forensics", "gastown", "nux.tar.gz")
+	m := &ForensicManifest{
+		Rig:        "gastown",
+		Polecat:    "nux",
+		CapturedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Missing:    map[string]string{"pane.txt": "no such session"},
+	}
+	files := map[string][]byte{
+		"git-status.txt": []byte("## polecat/nux\n M main.go\n"),
+		"git-diff.txt":   []byte("diff --git a/main.go b/main.go\n"),
+	}
+	if err := writeForensicBundle(path, m, files); err != nil {
+		t.Fatalf("writeForensicBundle() error = %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	got := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		got[hdr.Name] = data
+	}
+
+	var manifest ForensicManifest
+	if err := json.Unmarshal(got["manifest.json"], &manifest); err != nil {
+		t.Fatalf("manifest.json: %v", err)
+	}
+	if len(manifest.Files) != 2 || manifest.Files[0] != "git-diff.txt" || manifest.Missing["pane.txt"] == "" {
+		t.Errorf("manifest = %+v", manifest)
+	}
+	if string(got["git-status.txt"]) != string(files["git-status.txt"]) {
+		t.Errorf("git-status.txt = %q", got["git-status.txt"])
+	}
+}
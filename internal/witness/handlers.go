@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/nudge"
@@ -49,6 +50,8 @@ type HandlerResult struct {
 }
 
 // HandlePolecatDone processes a POLECAT_DONE message from a polecat.
+// COMPLETED exits with an MR are first verified against the remote (see
+// VerifyPushedWork); unverified completions are demoted to failed.
 // For ESCALATED/DEFERRED exits (no pending MR), auto-nukes if clean.
 // For PHASE_COMPLETE exits, recycles the polecat (session ends, worktree kept).
 // For COMPLETED exits with MR and clean state, auto-nukes immediately (ephemeral model).
@@ -75,6 +78,15 @@ func HandlePolecatDone(workDir, rigName string, msg *mail.Message, router *mail.
 		return result
 	}
 
+	if payload.CorrelationID != "" {
+		// Requeued work: tag the outcome so the requeue can be traced to it.
+		defer func() {
+			if result.Action != "" {
+				result.Action += fmt.Sprintf(" [correlation %s]", payload.CorrelationID)
+			}
+		}()
+	}
+
 	if stale, reason := isStalePolecatDone(workDir, rigName, payload.PolecatName, msg); stale {
 		result.Handled = true
 		result.Action = fmt.Sprintf("ignored stale POLECAT_DONE for %s (%s)", payload.PolecatName, reason)
@@ -87,6 +99,12 @@ func HandlePolecatDone(workDir, rigName string, msg *mail.Message, router *mail.
 		return result
 	}
 
+	if payload.Exit == "COMPLETED" && payload.MRID != "" {
+		if demoted := verifyCompletion(workDir, rigName, payload, router, result); demoted {
+			return result
+		}
+	}
+
 	hasPendingMR := payload.MRID != "" || payload.Exit == "COMPLETED"
 	if hasPendingMR {
 		return handlePolecatDonePendingMR(workDir, rigName, payload, router, result)
@@ -94,6 +112,32 @@ func HandlePolecatDone(workDir, rigName string, msg *mail.Message, router *mail.
 	return handlePolecatDoneNoMR(workDir, rigName, payload, result)
 }
 
+// verifyCompletion checks a COMPLETED polecat's pushed work before it is
+// handed to the Refinery, demoting the completion when the claim doesn't hold
+// up. Returns true if it was demoted (result is then final). When the check
+// can't run, the completion proceeds unverified with a non-fatal error.
+func verifyCompletion(workDir, rigName string, payload *PolecatDonePayload, router *mail.Router, result *HandlerResult) bool {
+	townRoot, _ := workspace.Find(workDir)
+	if townRoot == "" {
+		return false
+	}
+	v, err := VerifyPushedWork(workDir, townRoot, rigName, payload)
+	if err != nil {
+		result.Error = fmt.Errorf("verifying pushed work: %w (non-fatal, proceeding unverified)", err)
+		return false
+	}
+	if v.Verified() {
+		return false
+	}
+
+	wispID, err := DemoteUnverifiedCompletion(workDir, rigName, payload, v, router)
+	result.Handled = true
+	result.WispCreated = wispID
+	result.Error = err
+	result.Action = fmt.Sprintf("demoted COMPLETED to failed for %s: %s", payload.PolecatName, strings.Join(v.Problems, "; "))
+	return true
+}
+
 // handlePolecatDonePendingMR handles a POLECAT_DONE when there's a pending MR.
 // Creates a cleanup wisp, sends MERGE_READY to the Refinery, and nudges it.
 func handlePolecatDonePendingMR(workDir, rigName string, payload *PolecatDonePayload, router *mail.Router, result *HandlerResult) *HandlerResult {
@@ -224,44 +268,85 @@ func HandleLifecycleShutdown(workDir, rigName string, msg *mail.Message) *Handle
 }
 
 // HandleHelp processes a HELP message from a polecat requesting intervention.
-// Assesses the request and either helps directly or escalates to Mayor.
+// Assesses the request and either helps directly or escalates to the Deacon.
 func HandleHelp(workDir, rigName string, msg *mail.Message, router *mail.Router) *HandlerResult {
-	result := &HandlerResult{
-		MessageID:    msg.ID,
-		ProtocolType: ProtoHelp,
-	}
+	return HandleHelpBatch(workDir, rigName, []*mail.Message{msg}, router)[0]
+}
 
-	// Parse the message
-	payload, err := ParseHelp(msg.Subject, msg.Body)
-	if err != nil {
-		result.Error = fmt.Errorf("parsing HELP: %w", err)
-		return result
-	}
+// HandleHelpBatch processes the HELP messages found in one inbox check.
+// Escalations are batched: a few go to the Deacon individually, but when
+// EscalationBatchThreshold or more polecats need escalating at once they go
+// to the Mayor as a single digest with a common-cause hypothesis.
+func HandleHelpBatch(workDir, rigName string, msgs []*mail.Message, router *mail.Router) []*HandlerResult {
+	return handleHelpBatch(rigName, msgs, NewEscalationBatcher(rigName, router))
+}
 
-	// Assess the help request
-	assessment := AssessHelpRequest(payload)
+func handleHelpBatch(rigName string, msgs []*mail.Message, batcher *EscalationBatcher) []*HandlerResult {
+	results := make([]*HandlerResult, len(msgs))
+	var escalated []*HandlerResult
 
-	if assessment.CanHelp {
-		// Log that we can help - actual help is done by the Claude agent
-		result.Handled = true
-		result.Action = fmt.Sprintf("can help with '%s': %s", payload.Topic, assessment.HelpAction)
-		return result
-	}
+	for i, msg := range msgs {
+		result := &HandlerResult{
+			MessageID:    msg.ID,
+			ProtocolType: ProtoHelp,
+		}
+		results[i] = result
 
-	// Need to escalate to Deacon (first line of escalation for routine ops)
-	if assessment.NeedsEscalation {
-		mailID, err := escalateToDeacon(router, rigName, payload, assessment.EscalationReason)
+		// Parse the message
+		payload, err := ParseHelp(msg.Subject, msg.Body)
 		if err != nil {
-			result.Error = fmt.Errorf("escalating to deacon: %w", err)
-			return result
+			result.Error = fmt.Errorf("parsing HELP: %w", err)
+			continue
 		}
 
+		// Assess the help request
+		assessment := AssessHelpRequest(payload)
+
+		if assessment.CanHelp {
+			// Log that we can help - actual help is done by the Claude agent
+			result.Handled = true
+			result.Action = fmt.Sprintf("can help with '%s': %s", payload.Topic, assessment.HelpAction)
+			continue
+		}
+
+		// Need to escalate to Deacon (first line of escalation for routine ops)
+		if assessment.NeedsEscalation {
+			detail := payload.Problem
+			if detail == "" {
+				detail = payload.Topic
+			}
+			batcher.Add(Escalation{
+				Polecat: payload.Agent[strings.LastIndex(payload.Agent, "/")+1:],
+				IssueID: payload.IssueID,
+				Kind:    "HELP",
+				Detail:  detail,
+				Message: helpEscalationMessage(rigName, payload, assessment.EscalationReason),
+			})
+			result.Action = fmt.Sprintf("'%s': %s", payload.Topic, assessment.EscalationReason)
+			escalated = append(escalated, result)
+		}
+	}
+
+	flush, err := batcher.Flush()
+	for i, result := range escalated {
+		mailID := ""
+		if i < len(flush.MailIDs) {
+			mailID = flush.MailIDs[i]
+		}
+		if mailID == "" {
+			result.Error = fmt.Errorf("escalating to deacon: %w", err)
+			continue
+		}
 		result.Handled = true
 		result.MailSent = mailID
-		result.Action = fmt.Sprintf("escalated '%s' to deacon: %s", payload.Topic, assessment.EscalationReason)
+		if flush.Digest {
+			result.Action = "escalated " + result.Action + " to mayor in digest"
+		} else {
+			result.Action = "escalated " + result.Action + " to deacon"
+		}
 	}
 
-	return result
+	return results
 }
 
 // HandleMerged processes a MERGED message from the Refinery.
@@ -278,6 +363,10 @@ func HandleMerged(workDir, rigName string, msg *mail.Message) *HandlerResult {
 		return result
 	}
 
+	// Landed work clears the polecat's circuit breaker failures, or counts
+	// towards closing a half-open circuit.
+	_ = RecordPolecatSuccess(workDir, rigName, payload.PolecatName)
+
 	wispID, err := findCleanupWisp(workDir, payload.PolecatName)
 	if err != nil {
 		result.Error = fmt.Errorf("finding cleanup wisp: %w", err)
@@ -559,21 +648,7 @@ func getCleanupStatus(workDir, rigName, polecatName string) string {
 		return ""
 	}
 
-	// Parse cleanup_status from description
-	// Description format has "cleanup_status: <value>" line
-	for _, line := range strings.Split(issues[0].Description, "\n") {
-		line = strings.TrimSpace(line)
-		lower := strings.ToLower(line)
-		if strings.HasPrefix(lower, "cleanup_status:") {
-			// Use the lowercased version to ensure consistent prefix removal
-			value := strings.TrimSpace(strings.TrimPrefix(lower, "cleanup_status:"))
-			if value != "" && value != "null" {
-				return value
-			}
-		}
-	}
-
-	return ""
+	return strings.ToLower(beads.ParseAgentFields(issues[0].Description).CleanupStatus)
 }
 
 // sendMergeReady sends a MERGE_READY notification to the Refinery.
@@ -636,11 +711,11 @@ func nudgeRefinery(townRoot, rigName string) error {
 	return t.NudgeSession(sessionName, nudgeMsg)
 }
 
-// escalateToDeacon sends an escalation mail to the Deacon for routine operational issues.
+// helpEscalationMessage builds the escalation mail to the Deacon for routine operational issues.
 // The Deacon is the first line of escalation for witness operations. Only truly strategic
-// issues (deacon down, cross-rig coordination) should go directly to Mayor.
-func escalateToDeacon(router *mail.Router, rigName string, payload *HelpPayload, reason string) (string, error) {
-	msg := &mail.Message{
+// issues (deacon down, cross-rig coordination, digests of many failures) go directly to Mayor.
+func helpEscalationMessage(rigName string, payload *HelpPayload, reason string) *mail.Message {
+	return &mail.Message{
 		From:     fmt.Sprintf("%s/witness", rigName),
 		To:       "deacon/",
 		Subject:  fmt.Sprintf("Escalation: %s needs help", payload.Agent),
@@ -661,12 +736,6 @@ Requested at: %s`,
 			payload.RequestedAt.Format(time.RFC3339),
 		),
 	}
-
-	if err := router.Send(msg); err != nil {
-		return "", err
-	}
-
-	return msg.ID, nil
 }
 
 // RecoveryPayload contains data for RECOVERY_NEEDED escalation.
@@ -685,7 +754,17 @@ type RecoveryPayload struct {
 // save the work) before authorizing cleanup. Only escalates to Mayor if Deacon
 // cannot resolve.
 func EscalateRecoveryNeeded(router *mail.Router, rigName string, payload *RecoveryPayload) (string, error) {
-	msg := &mail.Message{
+	msg := recoveryNeededMessage(rigName, payload)
+	if err := router.Send(msg); err != nil {
+		return "", err
+	}
+
+	return msg.ID, nil
+}
+
+// recoveryNeededMessage builds the RECOVERY_NEEDED mail to the Deacon.
+func recoveryNeededMessage(rigName string, payload *RecoveryPayload) *mail.Message {
+	return &mail.Message{
 		From:     fmt.Sprintf("%s/witness", rigName),
 		To:       "deacon/",
 		Subject:  fmt.Sprintf("RECOVERY_NEEDED %s/%s", rigName, payload.PolecatName),
@@ -711,12 +790,6 @@ DO NOT nuke without --force after recovery.`,
 			payload.DetectedAt.Format(time.RFC3339),
 		),
 	}
-
-	if err := router.Send(msg); err != nil {
-		return "", err
-	}
-
-	return msg.ID, nil
 }
 
 // UpdateCleanupWispState updates a cleanup wisp's state label.
@@ -765,6 +838,12 @@ func extractPolecatFromJSON(output string) string {
 // This kills the tmux session, removes the worktree, and cleans up beads.
 // Should only be called after all safety checks pass.
 func NukePolecat(workDir, rigName, polecatName string) error {
+	return nukePolecat(workDir, rigName, polecatName, false)
+}
+
+// nukePolecat is NukePolecat; force bypasses gt polecat nuke's safety
+// checks, discarding any work left in the worktree.
+func nukePolecat(workDir, rigName, polecatName string, force bool) error {
 	// CRITICAL: Kill the tmux session FIRST and unconditionally.
 	// We do this explicitly here because gt polecat nuke may fail to kill the
 	// session due to rig loading issues or race conditions with IsRunning checks.
@@ -789,7 +868,11 @@ func NukePolecat(workDir, rigName, polecatName string) error {
 	// Now run gt polecat nuke to clean up worktree, branch, and beads
 	address := fmt.Sprintf("%s/%s", rigName, polecatName)
 
-	if err := util.ExecRun(workDir, "gt", "polecat", "nuke", address); err != nil {
+	args := []string{"polecat", "nuke", address}
+	if force {
+		args = append(args, "--force")
+	}
+	if err := util.ExecRun(workDir, "gt", args...); err != nil {
 		return fmt.Errorf("nuke failed: %w", err)
 	}
 
@@ -812,49 +895,69 @@ type NukePolecatResult struct {
 func AutoNukeIfClean(workDir, rigName, polecatName string) *NukePolecatResult {
 	result := &NukePolecatResult{}
 
+	safe, reason := checkAutoNuke(workDir, rigName, polecatName)
+	if !safe {
+		result.Skipped = true
+		result.Reason = reason
+		return result
+	}
+
+	if err := NukePolecat(workDir, rigName, polecatName); err != nil {
+		result.Error = err
+		result.Reason = fmt.Sprintf("nuke failed: %v", err)
+	} else {
+		result.Nuked = true
+		result.Reason = "auto-nuked (" + reason + ")"
+	}
+	return result
+}
+
+// PreviewAutoNuke reports what AutoNukeIfClean would do without nuking:
+// Nuked is set when the polecat would be nuked.
+func PreviewAutoNuke(workDir, rigName, polecatName string) *NukePolecatResult {
+	safe, reason := checkAutoNuke(workDir, rigName, polecatName)
+	if !safe {
+		return &NukePolecatResult{Skipped: true, Reason: reason}
+	}
+	return &NukePolecatResult{Nuked: true, Reason: "would auto-nuke (" + reason + ")"}
+}
+
+// checkAutoNuke decides whether a polecat is safe to nuke. When safe, the
+// reason says why; otherwise it says why the nuke is skipped.
+func checkAutoNuke(workDir, rigName, polecatName string) (bool, string) {
+	// Respect nuke_policy from the override hierarchy (rig, role, or agent).
+	townRoot, _ := workspace.Find(workDir)
+	if eff := config.ResolveAgentOverrides(townRoot, rigName+"/polecats/"+polecatName); eff != nil &&
+		eff.GetString(config.OverrideNukePolicy) == config.NukePolicyManual {
+		return false, "skipped: nuke_policy=manual"
+	}
+
 	// Check cleanup_status from agent bead
 	cleanupStatus := getCleanupStatus(workDir, rigName, polecatName)
 
 	switch cleanupStatus {
 	case "clean":
 		// Safe to nuke
-		if err := NukePolecat(workDir, rigName, polecatName); err != nil {
-			result.Error = err
-			result.Reason = fmt.Sprintf("nuke failed: %v", err)
-		} else {
-			result.Nuked = true
-			result.Reason = "auto-nuked (cleanup_status=clean, no MR)"
-		}
+		return true, "cleanup_status=clean, no MR"
 
 	case "has_uncommitted", "has_stash", "has_unpushed":
 		// Not safe - has work that could be lost
-		result.Skipped = true
-		result.Reason = fmt.Sprintf("skipped: has %s", strings.TrimPrefix(cleanupStatus, "has_"))
+		return false, fmt.Sprintf("skipped: has %s", strings.TrimPrefix(cleanupStatus, "has_"))
 
 	default:
 		// Unknown status - check git state directly as fallback
 		onMain, err := verifyCommitOnMain(workDir, rigName, polecatName)
 		if err != nil {
 			// Can't verify - skip (polecat may not exist)
-			result.Skipped = true
-			result.Reason = fmt.Sprintf("skipped: couldn't verify git state: %v", err)
-		} else if onMain {
-			// Commit is on main, likely safe
-			if err := NukePolecat(workDir, rigName, polecatName); err != nil {
-				result.Error = err
-				result.Reason = fmt.Sprintf("nuke failed: %v", err)
-			} else {
-				result.Nuked = true
-				result.Reason = "auto-nuked (commit on main, no cleanup_status)"
-			}
-		} else {
+			return false, fmt.Sprintf("skipped: couldn't verify git state: %v", err)
+		}
+		if !onMain {
 			// Not on main - skip, might have unpushed work
-			result.Skipped = true
-			result.Reason = "skipped: commit not on main, may have unpushed work"
+			return false, "skipped: commit not on main, may have unpushed work"
 		}
+		// Commit is on main, likely safe
+		return true, "commit on main, no cleanup_status"
 	}
-
-	return result
 }
 
 // verifyCommitOnMain checks if the polecat's current commit is on the default branch.
@@ -881,14 +984,7 @@ func verifyCommitOnMain(workDir, rigName, polecatName string) (bool, error) {
 		defaultBranch = rigCfg.DefaultBranch
 	}
 
-	// Construct polecat path, handling both new and old structures
-	// New structure: polecats/<name>/<rigname>/
-	// Old structure: polecats/<name>/
-	polecatPath := filepath.Join(townRoot, rigName, "polecats", polecatName, rigName)
-	if _, err := os.Stat(polecatPath); os.IsNotExist(err) {
-		// Fall back to old structure
-		polecatPath = filepath.Join(townRoot, rigName, "polecats", polecatName)
-	}
+	polecatPath := polecatWorktree(townRoot, rigName, polecatName)
 
 	// Get git for the polecat worktree
 	g := git.NewGit(polecatPath)
@@ -937,6 +1033,7 @@ type ZombieResult struct {
 	HookBead      string
 	Action        string // "auto-nuked", "escalated", "cleanup-wisp-created"
 	BeadRecovered bool   // true if hooked bead was reset to open for re-dispatch
+	BeadBlocked   bool   // true if hooked bead was blocked as likely broken work
 	Error         error
 }
 
@@ -967,8 +1064,11 @@ type DetectZombiePolecatsResult struct {
 //
 // For each zombie found:
 //   - If git state is clean (no unpushed work): auto-nuke
-//   - If git state is dirty (unpushed/uncommitted work): escalate to Mayor via
-//     EscalateRecoveryNeeded, create cleanup wisp
+//   - If git state is dirty (unpushed/uncommitted work): escalate RECOVERY_NEEDED,
+//     create cleanup wisp
+//
+// Recovery escalations are batched across the sweep: when many polecats die
+// dirty at once they reach the Mayor as one digest (see EscalationBatcher).
 func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZombiePolecatsResult {
 	result := &DetectZombiePolecatsResult{}
 
@@ -985,6 +1085,10 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 	}
 
 	t := tmux.NewTmux()
+	var escalations *EscalationBatcher
+	if router != nil {
+		escalations = NewEscalationBatcher(rigName, router)
+	}
 
 	for _, entry := range entries {
 		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -1061,6 +1165,13 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 						inactiveMinutes := int(time.Since(lastActivity).Minutes())
 						if inactiveMinutes >= HungSessionThresholdMinutes {
 							_, hungHookBead := getAgentBeadState(workDir, agentBeadID)
+							var hungFailure *CircuitFailure
+							if hungHookBead != "" {
+								// Hanging with work hooked counts against the circuit;
+								// the last output tells a stalled API call from a stuck task.
+								content, _ := t.CapturePane(sessionName, 30)
+								hungFailure, _ = HandlePolecatFailure(workDir, rigName, polecatName, hungHookBead, ClassifyFailure(content))
+							}
 							zombie := ZombieResult{
 								PolecatName: polecatName,
 								AgentState:  "agent-hung",
@@ -1071,7 +1182,7 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 								zombie.Error = err
 								zombie.Action = fmt.Sprintf("kill-hung-session-failed: %v", err)
 							}
-							zombie.BeadRecovered = resetAbandonedBead(workDir, rigName, hungHookBead, polecatName, router)
+							zombie.BeadRecovered, zombie.BeadBlocked = recoverAbandonedBead(workDir, rigName, hungHookBead, polecatName, hungFailure, router)
 							result.Zombies = append(result.Zombies, zombie)
 						}
 					}
@@ -1080,11 +1191,15 @@ func DetectZombiePolecats(workDir, rigName string, router *mail.Router) *DetectZ
 			continue // Either handled or not a zombie
 		}
 
-		if zombie, found := detectZombieDeadSession(workDir, rigName, polecatName, agentBeadID, sessionName, t, doneIntent, detectedAt, router); found {
+		if zombie, found := detectZombieDeadSession(workDir, rigName, polecatName, agentBeadID, sessionName, t, doneIntent, detectedAt, router, escalations); found {
 			result.Zombies = append(result.Zombies, zombie)
 		}
 	}
 
+	if _, err := escalations.Flush(); err != nil {
+		result.Errors = append(result.Errors, err)
+	}
+
 	return result
 }
 
@@ -1146,7 +1261,7 @@ func detectZombieLiveSession(workDir, rigName, polecatName, agentBeadID, session
 
 // detectZombieDeadSession checks a polecat with a dead tmux session for zombie indicators:
 // stale done-intent, or active agent state / hooked bead with no session.
-func detectZombieDeadSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t *tmux.Tmux, doneIntent *DoneIntent, detectedAt time.Time, router *mail.Router) (ZombieResult, bool) {
+func detectZombieDeadSession(workDir, rigName, polecatName, agentBeadID, sessionName string, t *tmux.Tmux, doneIntent *DoneIntent, detectedAt time.Time, router *mail.Router, escalations *EscalationBatcher) (ZombieResult, bool) {
 	// Done-intent: polecat was trying to exit.
 	if doneIntent != nil {
 		age := time.Since(doneIntent.Timestamp)
@@ -1185,9 +1300,16 @@ func detectZombieDeadSession(workDir, rigName, polecatName, agentBeadID, session
 		HookBead:    hookBead,
 	}
 
+	// Dying with work hooked counts against the polecat's circuit breaker,
+	// and against the work itself.
+	var failure *CircuitFailure
+	if hookBead != "" {
+		failure, _ = HandlePolecatFailure(workDir, rigName, polecatName, hookBead, beads.FailureUnknown)
+	}
+
 	cleanupStatus := getCleanupStatus(workDir, rigName, polecatName)
-	handleZombieCleanup(workDir, rigName, polecatName, hookBead, cleanupStatus, router, &zombie)
-	zombie.BeadRecovered = resetAbandonedBead(workDir, rigName, hookBead, polecatName, router)
+	handleZombieCleanup(workDir, rigName, polecatName, hookBead, cleanupStatus, escalations, &zombie)
+	zombie.BeadRecovered, zombie.BeadBlocked = recoverAbandonedBead(workDir, rigName, hookBead, polecatName, failure, router)
 	return zombie, true
 }
 
@@ -1200,8 +1322,9 @@ func isZombieState(agentState, hookBead string) bool {
 }
 
 // handleZombieCleanup determines the cleanup action for a confirmed zombie based on
-// its cleanup_status. Clean or empty status → auto-nuke. Dirty status → escalate.
-func handleZombieCleanup(workDir, rigName, polecatName, hookBead, cleanupStatus string, router *mail.Router, zombie *ZombieResult) {
+// its cleanup_status. Clean or empty status → auto-nuke. Dirty status → queue
+// a RECOVERY_NEEDED escalation on escalations (nil when there is no router).
+func handleZombieCleanup(workDir, rigName, polecatName, hookBead, cleanupStatus string, escalations *EscalationBatcher, zombie *ZombieResult) {
 	switch cleanupStatus {
 	case "clean", "":
 		// Clean state or no cleanup info — try auto-nuke.
@@ -1228,18 +1351,19 @@ func handleZombieCleanup(workDir, rigName, polecatName, hookBead, cleanupStatus
 			zombie.Action = fmt.Sprintf("already-tracked (cleanup_status=%s, existing-wisp=%s)", cleanupStatus, existingWisp)
 			return
 		}
-		if router != nil {
-			_, escErr := EscalateRecoveryNeeded(router, rigName, &RecoveryPayload{
+		escalations.Add(Escalation{
+			Polecat: polecatName,
+			IssueID: hookBead,
+			Kind:    "RECOVERY_NEEDED",
+			Detail:  fmt.Sprintf("died with cleanup_status=%s", cleanupStatus),
+			Message: recoveryNeededMessage(rigName, &RecoveryPayload{
 				PolecatName:   polecatName,
 				Rig:           rigName,
 				CleanupStatus: cleanupStatus,
 				IssueID:       hookBead,
 				DetectedAt:    time.Now(),
-			})
-			if escErr != nil {
-				zombie.Error = escErr
-			}
-		}
+			}),
+		})
 		wispID, wispErr := createCleanupWisp(workDir, polecatName, hookBead, "")
 		if wispErr != nil && zombie.Error == nil {
 			zombie.Error = wispErr
@@ -1383,6 +1507,17 @@ func getBeadStatus(workDir, beadID string) string {
 	return issues[0].Status
 }
 
+// recoverAbandonedBead resets a dead polecat's hooked bead like
+// resetAbandonedBead, unless failure (from HandlePolecatFailure) found the
+// work itself broken: then the bead is blocked and the Mayor told instead.
+// Returns whether the bead was recovered and whether it was blocked.
+func recoverAbandonedBead(workDir, rigName, hookBead, polecatName string, failure *CircuitFailure, router *mail.Router) (bool, bool) {
+	if failure != nil && failure.BrokenWork && canRequeueBead(workDir, hookBead) {
+		return false, BlockBrokenWork(workDir, rigName, failure, router)
+	}
+	return resetAbandonedBead(workDir, rigName, hookBead, polecatName, router), false
+}
+
 // resetAbandonedBead resets a dead polecat's hooked bead so it can be re-dispatched.
 // If the bead is in "hooked" or "in_progress" status, it:
 // 1. Resets status to open
@@ -0,0 +1,101 @@
+package witness
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// heartbeatFile holds a rig's Witness heartbeat under <rig>/.runtime/.
+const heartbeatFile = "witness-heartbeat.json"
+
+const (
+	// HeartbeatStaleAfter is how old a Witness heartbeat may get before the
+	// Witness is reported stale. A patrol cycle sweeps at least every few
+	// minutes: its await-signal backoff is capped at 5m.
+	HeartbeatStaleAfter = 20 * time.Minute
+
+	// HeartbeatRestartAfter is how old a Witness heartbeat may get before
+	// the daemon restarts the Witness's session.
+	HeartbeatRestartAfter = 45 * time.Minute
+)
+
+// Heartbeat is a rig Witness's heartbeat, written by every circuit breaker
+// sweep (CheckCircuitBreakers). The Witness watches the rig's polecats; the
+// heartbeat lets 'gt status' and the daemon watch the Witness.
+type Heartbeat struct {
+	// Timestamp is when the last sweep finished.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Sweeps counts the sweeps since the heartbeat file was created.
+	Sweeps int64 `json:"sweeps"`
+
+	// Checked, Open and Errors summarize the last sweep: failing polecats
+	// checked, open circuits, and errors it hit.
+	Checked int `json:"checked"`
+	Open    int `json:"open"`
+	Errors  int `json:"errors,omitempty"`
+}
+
+// HeartbeatPath returns the path of a rig's Witness heartbeat.
+func HeartbeatPath(rigPath string) string {
+	return filepath.Join(rigPath, constants.DirRuntime, heartbeatFile)
+}
+
+// ReadHeartbeat reads a rig's Witness heartbeat. Returns nil if the Witness
+// has never swept the rig or the file can't be read.
+func ReadHeartbeat(rigPath string) *Heartbeat {
+	data, err := os.ReadFile(HeartbeatPath(rigPath)) //nolint:gosec // G304: path is constructed from the rig path
+	if err != nil {
+		return nil
+	}
+	var hb Heartbeat
+	if err := json.Unmarshal(data, &hb); err != nil {
+		return nil
+	}
+	return &hb
+}
+
+// TouchHeartbeat records a finished sweep in the rig's Witness heartbeat.
+func TouchHeartbeat(rigPath string, result *CheckCircuitBreakersResult) error {
+	hb := &Heartbeat{
+		Timestamp: time.Now().UTC(),
+		Sweeps:    1,
+		Checked:   result.Checked,
+		Open:      result.Open,
+		Errors:    len(result.Errors),
+	}
+	if existing := ReadHeartbeat(rigPath); existing != nil {
+		hb.Sweeps = existing.Sweeps + 1
+	}
+	return util.EnsureDirAndWriteJSON(HeartbeatPath(rigPath), hb)
+}
+
+// Age returns how old the heartbeat is, or a year if there is none.
+func (hb *Heartbeat) Age() time.Duration {
+	if hb == nil {
+		return 24 * time.Hour * 365
+	}
+	return time.Since(hb.Timestamp)
+}
+
+// IsStale reports whether the heartbeat is older than HeartbeatStaleAfter.
+// A missing heartbeat isn't stale: the Witness may never have swept.
+func (hb *Heartbeat) IsStale() bool {
+	return hb != nil && hb.Age() > HeartbeatStaleAfter
+}
+
+// NeedsRestart reports whether a Witness session started at sessionStarted
+// should be restarted at now: it has had HeartbeatRestartAfter to sweep and
+// the heartbeat is older than that. A missing heartbeat never restarts the
+// session, so a rig whose Witness predates heartbeats isn't killed.
+func (hb *Heartbeat) NeedsRestart(sessionStarted, now time.Time) bool {
+	if hb == nil {
+		return false
+	}
+	return now.Sub(hb.Timestamp) > HeartbeatRestartAfter && now.Sub(sessionStarted) > HeartbeatRestartAfter
+}
@@ -0,0 +1,48 @@
+package witness
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTouchHeartbeat(t *testing.T) {
+	rigPath := t.TempDir()
+	if hb := ReadHeartbeat(rigPath); hb != nil || hb.IsStale() {
+		t.Fatalf("no sweeps yet: heartbeat = %+v, want nil and not stale", hb)
+	}
+
+	if err := TouchHeartbeat(rigPath, &CheckCircuitBreakersResult{Checked: 4, Open: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := TouchHeartbeat(rigPath, &CheckCircuitBreakersResult{Checked: 3, Errors: []string{"boom"}}); err != nil {
+		t.Fatal(err)
+	}
+	hb := ReadHeartbeat(rigPath)
+	if hb == nil || hb.Sweeps != 2 || hb.Checked != 3 || hb.Open != 0 || hb.Errors != 1 {
+		t.Fatalf("heartbeat = %+v, want the second of two sweeps", hb)
+	}
+	if hb.IsStale() || hb.Age() > time.Minute {
+		t.Errorf("fresh heartbeat is %s old, stale %v", hb.Age(), hb.IsStale())
+	}
+}
+
+func TestHeartbeat_NeedsRestart(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-2 * HeartbeatRestartAfter)
+	tests := []struct {
+		name    string
+		hb      *Heartbeat
+		started time.Time
+		want    bool
+	}{
+		{"no heartbeat", nil, old, false},
+		{"fresh", &Heartbeat{Timestamp: now.Add(-time.Minute)}, old, false},
+		{"stale, old session", &Heartbeat{Timestamp: old}, old, true},
+		{"stale, session just restarted", &Heartbeat{Timestamp: old}, now.Add(-time.Minute), false},
+	}
+	for _, tt := range tests {
+		if got := tt.hb.NeedsRestart(tt.started, now); got != tt.want {
+			t.Errorf("%s: NeedsRestart = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
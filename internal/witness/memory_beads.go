@@ -0,0 +1,202 @@
+package witness
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// memoryBeads is an in-memory circuitBeads: agent, work failure and requeue
+// fields kept in maps. It backs 'gt witness simulate' and the breaker's
+// tests, so a sweep can run end to end without a beads database. It is
+// safe for concurrent use, as a sweep opens circuits in parallel.
+type memoryBeads struct {
+	mu       sync.Mutex
+	rig      string
+	agents   map[string]*beads.AgentFields
+	work     map[string]*beads.WorkFailureFields
+	requeues map[string]*beads.RequeueFields
+	cleared  []string // agent beads whose hook was cleared, in order
+
+	// now stamps circuit_opened_at and failure times; a simulation sets
+	// it to its own clock.
+	now func() time.Time
+}
+
+func newMemoryBeads(rigName string) *memoryBeads {
+	return &memoryBeads{
+		rig:      rigName,
+		agents:   make(map[string]*beads.AgentFields),
+		work:     make(map[string]*beads.WorkFailureFields),
+		requeues: make(map[string]*beads.RequeueFields),
+		now:      time.Now,
+	}
+}
+
+// fields returns an agent's fields, creating a polecat in the store's rig
+// on first use.
+func (m *memoryBeads) fields(id string) *beads.AgentFields {
+	if m.agents[id] == nil {
+		m.agents[id] = &beads.AgentFields{RoleType: "polecat", Rig: m.rig}
+	}
+	return m.agents[id]
+}
+
+func (m *memoryBeads) ListAgentBeads() (map[string]*beads.Issue, error) {
+	return m.ListAgentBeadsWithField("", "")
+}
+
+// ListAgentBeadsWithField matches descriptions as bd's --desc-contains
+// does; key "" lists every agent.
+func (m *memoryBeads) ListAgentBeadsWithField(key, value string) (map[string]*beads.Issue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]*beads.Issue, len(m.agents))
+	for id, fields := range m.agents {
+		description := beads.FormatAgentDescription(id, fields)
+		matched := key == ""
+		for _, pattern := range beads.AgentFieldPatterns(key, value) {
+			matched = matched || strings.Contains(description, pattern)
+		}
+		if matched {
+			out[id] = &beads.Issue{ID: id, Description: description}
+		}
+	}
+	return out, nil
+}
+
+// UpdateAgentFields is Beads.UpdateAgentFields: the compare-and-swap
+// checks and bumps the fields' Revision as a write to a bead would.
+func (m *memoryBeads) UpdateAgentFields(id string, expectedRevision int64, modify func(*beads.AgentFields) error) (*beads.AgentFields, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.update(id, expectedRevision, modify)
+}
+
+// update is UpdateAgentFields with m.mu held.
+func (m *memoryBeads) update(id string, expectedRevision int64, modify func(*beads.AgentFields) error) (*beads.AgentFields, error) {
+	stored := m.fields(id)
+	if expectedRevision != beads.AnyAgentRevision && stored.Revision != expectedRevision {
+		return nil, fmt.Errorf("%w: %s is at revision %d, expected %d", beads.ErrAgentFieldsConflict, id, stored.Revision, expectedRevision)
+	}
+	fields := *stored
+	if err := modify(&fields); err != nil {
+		return nil, err
+	}
+	if fields.HookBead == "" && stored.HookBead != "" {
+		m.cleared = append(m.cleared, id)
+	}
+	fields.Revision = stored.Revision + 1
+	*stored = fields
+	return &fields, nil
+}
+
+// modify is an unconditional update with m.mu held.
+func (m *memoryBeads) modify(id string, modify func(*beads.AgentFields)) *beads.AgentFields {
+	fields, _ := m.update(id, beads.AnyAgentRevision, func(fields *beads.AgentFields) error {
+		modify(fields)
+		return nil
+	})
+	return fields
+}
+
+func (m *memoryBeads) OpenAgentCircuit(id string) (*beads.AgentFields, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.modify(id, func(fields *beads.AgentFields) {
+		beads.ApplyOpenCircuit(fields, m.now())
+	}), nil
+}
+
+func (m *memoryBeads) ResetAgentFailureCount(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.modify(id, beads.ApplyCircuitReset)
+	return nil
+}
+
+func (m *memoryBeads) RecordAgentSuccess(id string, streak int, probationUntil time.Time) (*beads.AgentFields, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.modify(id, func(fields *beads.AgentFields) {
+		beads.ApplyAgentSuccess(fields, streak, probationUntil)
+	}), nil
+}
+
+func (m *memoryBeads) SetAgentNukeVeto(id, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.modify(id, func(fields *beads.AgentFields) {
+		fields.NukeVeto = reason
+	})
+	return nil
+}
+
+func (m *memoryBeads) SetAgentQuarantine(id, reason string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.modify(id, func(fields *beads.AgentFields) {
+		fields.Quarantine = reason
+		fields.QuarantinedAt = ""
+		if reason != "" {
+			fields.QuarantinedAt = at.UTC().Format(time.RFC3339)
+		}
+	})
+	return nil
+}
+
+func (m *memoryBeads) ClearHookBead(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fields(id).HookBead = ""
+	m.cleared = append(m.cleared, id)
+	return nil
+}
+
+func (m *memoryBeads) RecordWorkFailure(id, polecat, reason string) (*beads.WorkFailureFields, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	work := m.work[id]
+	if work == nil {
+		work = &beads.WorkFailureFields{}
+		m.work[id] = work
+	}
+	work.FailureCount++
+	if reason == beads.FailureTransient {
+		work.TransientFailures++
+	}
+	work.FailedBy = append(work.FailedBy, polecat)
+	work.LastFailureReason = reason
+	copied := *work
+	return &copied, nil
+}
+
+func (m *memoryBeads) WorkFailures(id string) (*beads.WorkFailureFields, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.work[id], nil
+}
+
+func (m *memoryBeads) ClaimRequeue(id, key string) (*beads.RequeueFields, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing := m.requeues[id]; existing != nil && existing.Key == key {
+		return existing, false, nil
+	}
+	m.requeues[id] = &beads.RequeueFields{Key: key, CorrelationID: beads.CorrelationIDFor(key)}
+	return m.requeues[id], true, nil
+}
+
+// memoryMail collects the mail a sweep sends instead of delivering it.
+type memoryMail struct {
+	sent []*mail.Message
+}
+
+func (m *memoryMail) Send(msg *mail.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
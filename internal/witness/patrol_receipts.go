@@ -15,6 +15,7 @@ type PatrolReceiptEvidence struct {
 	AgentState    string `json:"agent_state,omitempty"`
 	HookBead      string `json:"hook_bead,omitempty"`
 	BeadRecovered bool   `json:"bead_recovered"`
+	BeadBlocked   bool   `json:"bead_blocked,omitempty"`
 	Error         string `json:"error,omitempty"`
 }
 
@@ -60,6 +61,7 @@ func BuildPatrolReceipt(rigName string, z ZombieResult) PatrolReceipt {
 			AgentState:    z.AgentState,
 			HookBead:      z.HookBead,
 			BeadRecovered: z.BeadRecovered,
+			BeadBlocked:   z.BeadBlocked,
 		},
 	}
 
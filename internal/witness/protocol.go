@@ -58,6 +58,10 @@ type PolecatDonePayload struct {
 	MRID        string
 	Branch      string
 	Gate        string // Gate ID when Exit is PHASE_COMPLETE
+
+	// CorrelationID is set when the issue was requeued from a tripped
+	// polecat (see WORK_REQUEUE), tracing the requeue to its completion.
+	CorrelationID string
 }
 
 // HelpPayload contains parsed data from a HELP message.
@@ -139,6 +143,7 @@ func ClassifyMessage(subject string) ProtocolType {
 //	MR: <mr-id>
 //	Gate: <gate-id>
 //	Branch: <branch>
+//	Correlation: <correlation-id>
 func ParsePolecatDone(subject, body string) (*PolecatDonePayload, error) {
 	matches := PatternPolecatDone.FindStringSubmatch(subject)
 	if len(matches) < 2 {
@@ -162,6 +167,8 @@ func ParsePolecatDone(subject, body string) (*PolecatDonePayload, error) {
 			payload.Gate = strings.TrimSpace(strings.TrimPrefix(line, "Gate:"))
 		} else if strings.HasPrefix(line, "Branch:") {
 			payload.Branch = strings.TrimSpace(strings.TrimPrefix(line, "Branch:"))
+		} else if strings.HasPrefix(line, "Correlation:") {
+			payload.CorrelationID = strings.TrimSpace(strings.TrimPrefix(line, "Correlation:"))
 		}
 	}
 
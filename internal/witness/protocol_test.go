@@ -42,7 +42,8 @@ func TestParsePolecatDone(t *testing.T) {
 	body := `Exit: MERGED
 Issue: gt-abc123
 MR: gt-mr-xyz
-Branch: feature-branch`
+Branch: feature-branch
+Correlation: rq-0123456789`
 
 	payload, err := ParsePolecatDone(subject, body)
 	if err != nil {
@@ -64,6 +65,9 @@ Branch: feature-branch`
 	if payload.Branch != "feature-branch" {
 		t.Errorf("Branch = %q, want %q", payload.Branch, "feature-branch")
 	}
+	if payload.CorrelationID != "rq-0123456789" {
+		t.Errorf("CorrelationID = %q, want %q", payload.CorrelationID, "rq-0123456789")
+	}
 }
 
 func TestParsePolecatDone_MinimalBody(t *testing.T) {
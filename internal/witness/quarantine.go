@@ -0,0 +1,189 @@
+package witness
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/procfs"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// A tripped polecat that can't be nuked without losing work is quarantined
+// rather than left running: its session's processes are stopped, its
+// worktree is kept, and the sweep stops retrying the nuke and its circuit
+// stops cooling down. 'gt witness quarantine release' resumes it with a
+// closed circuit; 'gt witness quarantine nuke' discards it.
+
+// QuarantinedPolecat is a polecat held in quarantine.
+type QuarantinedPolecat struct {
+	Polecat       string `json:"polecat"`
+	AgentBeadID   string `json:"agent_bead"`
+	Reason        string `json:"reason"`
+	QuarantinedAt string `json:"quarantined_at,omitempty"`
+	CleanupStatus string `json:"cleanup_status,omitempty"`
+	FailureCount  int    `json:"failure_count,omitempty"`
+	Worktree      string `json:"worktree"`
+}
+
+// ListQuarantined returns the rig's quarantined polecats, by name.
+func ListQuarantined(workDir, rigName string) ([]QuarantinedPolecat, error) {
+	bd, townRoot := rigBeads(workDir, rigName)
+	agents, err := bd.Query().Role("polecat").Rig(rigName).Where("quarantine", "!=", "").Run()
+	if err != nil {
+		return nil, fmt.Errorf("listing agent beads: %w", err)
+	}
+	var out []QuarantinedPolecat
+	for id, issue := range agents {
+		fields := beads.ParseAgentFields(issue.Description)
+		_, _, polecatName, ok := beads.ParseAgentBeadID(id)
+		if !ok || polecatName == "" {
+			continue
+		}
+		out = append(out, QuarantinedPolecat{
+			Polecat:       polecatName,
+			AgentBeadID:   id,
+			Reason:        fields.Quarantine,
+			QuarantinedAt: fields.QuarantinedAt,
+			CleanupStatus: fields.CleanupStatus,
+			FailureCount:  fields.FailureCount,
+			Worktree:      polecatWorktree(townRoot, rigName, polecatName),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Polecat < out[j].Polecat })
+	return out, nil
+}
+
+// QuarantineReason returns why a polecat is quarantined, or "" if it isn't
+// (or its agent bead can't be read).
+func QuarantineReason(workDir, rigName, polecatName string) string {
+	bd, townRoot := rigBeads(workDir, rigName)
+	_, fields, err := bd.GetAgentBead(polecatAgentBeadID(townRoot, rigName, polecatName))
+	if err != nil || fields == nil {
+		return ""
+	}
+	return fields.Quarantine
+}
+
+// ReleaseQuarantine resumes a quarantined polecat: its session's processes
+// are continued, the quarantine lifted and its circuit closed, as by
+// 'gt witness circuit reset'. Who did it and why goes in circuit_note.
+func ReleaseQuarantine(workDir, rigName, polecatName, actor, reason string) error {
+	bd, townRoot, agentBeadID, err := quarantinedAgentBead(workDir, rigName, polecatName)
+	if err != nil {
+		return err
+	}
+	if err := thawSession(rigName, polecatName); err != nil {
+		return err
+	}
+	if err := bd.SetAgentQuarantine(agentBeadID, "", time.Time{}); err != nil {
+		return fmt.Errorf("lifting quarantine on %s: %w", agentBeadID, err)
+	}
+	if err := bd.ResetAgentFailureCount(agentBeadID); err != nil {
+		return fmt.Errorf("resetting circuit on %s: %w", agentBeadID, err)
+	}
+	recordCircuitEvent(townRoot, rigName, CircuitEvent{
+		Event:   CircuitEventReset,
+		Polecat: polecatName,
+		Reason:  quarantineEventReason("released from quarantine", reason),
+		Actor:   actor,
+	})
+	note := circuitNote("released from quarantine", actor, reason, time.Now())
+	return bd.UpdateAgentDescriptionFields(agentBeadID, beads.AgentFieldUpdates{CircuitNote: &note})
+}
+
+// NukeQuarantined nukes a quarantined polecat, discarding whatever is left
+// in its worktree: the quarantine exists because the worktree was dirty,
+// so gt polecat nuke's safety checks are bypassed. Recover anything worth
+// keeping first.
+func NukeQuarantined(workDir, rigName, polecatName, actor, reason string) error {
+	bd, townRoot, agentBeadID, err := quarantinedAgentBead(workDir, rigName, polecatName)
+	if err != nil {
+		return err
+	}
+	// Stopped processes hold every signal but SIGKILL until continued, so
+	// the session would ignore the Ctrl-C and hangup of a nuke.
+	if err := thawSession(rigName, polecatName); err != nil {
+		return err
+	}
+	if err := nukePolecat(workDir, rigName, polecatName, true); err != nil {
+		return err
+	}
+	recordCircuitEvent(townRoot, rigName, CircuitEvent{
+		Event:   CircuitEventNuke,
+		Polecat: polecatName,
+		Reason:  quarantineEventReason("nuked from quarantine", reason),
+		Actor:   actor,
+	})
+	// The nuke may have taken the agent bead with it.
+	_ = bd.SetAgentQuarantine(agentBeadID, "", time.Time{})
+	return nil
+}
+
+// quarantinedAgentBead returns the beads store, town root and agent bead ID
+// of a polecat, which must be quarantined.
+func quarantinedAgentBead(workDir, rigName, polecatName string) (*beads.Beads, string, string, error) {
+	bd, townRoot := rigBeads(workDir, rigName)
+	agentBeadID := polecatAgentBeadID(townRoot, rigName, polecatName)
+	_, fields, err := bd.GetAgentBead(agentBeadID)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("reading agent bead %s: %w", agentBeadID, err)
+	}
+	if fields == nil {
+		return nil, "", "", fmt.Errorf("no agent bead %s", agentBeadID)
+	}
+	if fields.Quarantine == "" {
+		return nil, "", "", fmt.Errorf("%s/%s is not quarantined", rigName, polecatName)
+	}
+	return bd, townRoot, agentBeadID, nil
+}
+
+func quarantineEventReason(action, reason string) string {
+	if reason == "" {
+		return action
+	}
+	return action + ": " + reason
+}
+
+// freezeSession stops every process in a polecat's session, so a
+// quarantined polecat does nothing more to its worktree. Returns
+// errNoLiveSession when the polecat has no session.
+func freezeSession(rigName, polecatName string) error {
+	return signalSession(rigName, polecatName, true)
+}
+
+// thawSession continues the processes freezeSession stopped. A polecat
+// without a session has nothing to thaw.
+func thawSession(rigName, polecatName string) error {
+	err := signalSession(rigName, polecatName, false)
+	if errors.Is(err, errNoLiveSession) {
+		return nil
+	}
+	return err
+}
+
+func signalSession(rigName, polecatName string, stop bool) error {
+	t := tmux.NewTmux()
+	name := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
+	if alive, err := t.HasSession(name); err != nil || !alive {
+		return errNoLiveSession
+	}
+	out, err := t.GetPanePID(name)
+	if err != nil {
+		return fmt.Errorf("finding %s's pane: %w", name, err)
+	}
+	pid, err := strconv.Atoi(out)
+	if err != nil {
+		return fmt.Errorf("finding %s's pane: bad pid %q", name, out)
+	}
+	pids, err := procfs.Tree(procfs.Root, pid)
+	if err != nil {
+		// No process filesystem: the pane's own process is all we can find.
+		pids = []int{pid}
+	}
+	return signalProcesses(pids, stop)
+}
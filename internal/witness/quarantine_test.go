@@ -0,0 +1,93 @@
+package witness
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func TestCircuitSweep_QuarantinesDirtyPolecat(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	bd := newMemoryBeads("gastown")
+	id := "gt-gastown-polecat-dirty"
+	*bd.fields(id) = beads.AgentFields{RoleType: "polecat", Rig: "gastown", FailureCount: 3, HookBead: "gt-b"}
+
+	var sent []*mail.Message
+	var frozen []string
+	var events []CircuitEvent
+	nukes := 0
+	sweep := &circuitSweep{
+		rigName: "gastown",
+		bd:      bd,
+		send:    func(m *mail.Message) error { sent = append(sent, m); return nil },
+		requeue: func(string) bool { return true },
+		nuke: func(string) *NukePolecatResult {
+			nukes++
+			return &NukePolecatResult{Skipped: true, Reason: "skipped: has uncommitted"}
+		},
+		capture: func(string) (string, error) { return "", nil },
+		freeze:  func(polecatName string) error { frozen = append(frozen, polecatName); return nil },
+		history: func(ev CircuitEvent) { events = append(events, ev) },
+		now:     now,
+	}
+
+	result := sweep.run(DefaultCircuitBreakerConfig())
+	tc := result.Tripped[0]
+	if tc.Quarantine != "has uncommitted" || !tc.Frozen || !tc.Escalated {
+		t.Fatalf("tc = %+v, want quarantined, frozen and escalated", tc)
+	}
+	if got := bd.agents[id]; got.Quarantine != "has uncommitted" || got.QuarantinedAt != now.Format(time.RFC3339) {
+		t.Errorf("agent bead = %+v, want the quarantine recorded", got)
+	}
+	if len(frozen) != 1 || frozen[0] != "dirty" {
+		t.Errorf("froze %v, want dirty", frozen)
+	}
+	escalation := sent[len(sent)-1]
+	if !strings.Contains(escalation.Body, "gt witness quarantine nuke gastown/dirty") ||
+		!strings.Contains(escalation.Body, "its session is frozen") {
+		t.Errorf("escalation body doesn't explain the quarantine:\n%s", escalation.Body)
+	}
+	if last := events[len(events)-1]; last.Event != CircuitEventQuarantine || last.TrippedAt == nil {
+		t.Errorf("last event = %+v, want a quarantine", last)
+	}
+
+	// Later sweeps leave it alone, even once the cooldown has passed.
+	sent, frozen = nil, nil
+	sweep.now = now.Add(24 * time.Hour)
+	result = sweep.run(DefaultCircuitBreakerConfig())
+	if nukes != 1 || len(sent) != 0 || len(frozen) != 0 || len(result.HalfOpen) != 0 {
+		t.Errorf("second sweep: %d nukes, %d mails, froze %v, half_open %v; want it left alone", nukes, len(sent), frozen, result.HalfOpen)
+	}
+	if got := result.Tripped[0].NukeResult; got != "quarantined: has uncommitted" {
+		t.Errorf("second sweep nuke result = %q", got)
+	}
+}
+
+func TestCircuitSweep_QuarantineWithoutSession(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	*bd.fields("gt-gastown-polecat-dirty") = beads.AgentFields{RoleType: "polecat", Rig: "gastown", FailureCount: 3, HookBead: "gt-b"}
+
+	var sent []*mail.Message
+	sweep := &circuitSweep{
+		rigName: "gastown",
+		bd:      bd,
+		send:    func(m *mail.Message) error { sent = append(sent, m); return nil },
+		requeue: func(string) bool { return true },
+		nuke: func(string) *NukePolecatResult {
+			return &NukePolecatResult{Skipped: true, Reason: "skipped: has unpushed"}
+		},
+		capture: func(string) (string, error) { return "", nil },
+		freeze:  func(string) error { return errNoLiveSession },
+		now:     time.Now(),
+	}
+	tc := sweep.run(DefaultCircuitBreakerConfig()).Tripped[0]
+	if tc.Quarantine == "" || tc.Frozen || tc.Error != "" {
+		t.Errorf("tc = %+v, want quarantined, not frozen, no error", tc)
+	}
+	if body := sent[len(sent)-1].Body; !strings.Contains(body, "could not be frozen") {
+		t.Errorf("escalation body doesn't say the session kept running:\n%s", body)
+	}
+}
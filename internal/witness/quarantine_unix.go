@@ -0,0 +1,25 @@
+//go:build !windows
+
+package witness
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+// signalProcesses stops (SIGSTOP) or continues (SIGCONT) pids. Processes
+// that have already exited are ignored.
+func signalProcesses(pids []int, stop bool) error {
+	sig := syscall.SIGCONT
+	if stop {
+		sig = syscall.SIGSTOP
+	}
+	var errs []error
+	for _, pid := range pids {
+		if err := syscall.Kill(pid, sig); err != nil && !errors.Is(err, syscall.ESRCH) {
+			errs = append(errs, fmt.Errorf("signalling pid %d: %w", pid, err))
+		}
+	}
+	return errors.Join(errs...)
+}
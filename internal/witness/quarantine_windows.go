@@ -0,0 +1,14 @@
+//go:build windows
+
+package witness
+
+import "errors"
+
+// signalProcesses can't stop processes on Windows: a quarantined polecat's
+// session keeps running there.
+func signalProcesses(pids []int, stop bool) error {
+	if !stop {
+		return nil
+	}
+	return errors.New("freezing sessions is not supported on Windows")
+}
@@ -0,0 +1,109 @@
+package witness
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RemediationVetoExitCode is the remediation hook exit code that vetoes
+// nuking the polecat: it is escalated to the Mayor instead.
+const RemediationVetoExitCode = 2
+
+// remediationOutputLimit caps the hook output kept in a RemediationResult.
+const remediationOutputLimit = 2000
+
+// RemediationResult reports one run of a rig's remediation hook.
+type RemediationResult struct {
+	Hook     string `json:"hook"`
+	ExitCode int    `json:"exit_code"`
+	Vetoed   bool   `json:"vetoed,omitempty"` // exited RemediationVetoExitCode
+	Output   string `json:"output,omitempty"` // tail of stdout and stderr
+	Error    string `json:"error,omitempty"`  // the hook couldn't run or failed
+	Duration string `json:"duration"`
+}
+
+// VetoReason is the single-line reason recorded when the hook vetoes a
+// nuke: its last line of output, if any.
+func (r *RemediationResult) VetoReason() string {
+	lines := strings.Split(strings.TrimSpace(r.Output), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return "remediation hook: " + last
+	}
+	return fmt.Sprintf("remediation hook exited %d", r.ExitCode)
+}
+
+// RunRemediationHook runs a rig's remediation hook for a tripped polecat,
+// through sh from dir, with env added to the Witness's environment. Exit
+// code 0 lets the nuke go ahead and RemediationVetoExitCode vetoes it. Any
+// other failure (another exit code, a timeout) is reported in Error and
+// does not veto: the hook is best-effort, and AutoNukeIfClean still won't
+// nuke a polecat with unsaved work.
+//
+// Trust boundary: the hook comes from the rig's settings
+// (operator-controlled), so it runs through the shell, as smoke gate steps
+// do.
+func RunRemediationHook(hook, dir string, env []string, timeout time.Duration) *RemediationResult {
+	result := &RemediationResult{Hook: hook}
+	start := time.Now()
+	defer func() { result.Duration = time.Since(start).Round(time.Millisecond).String() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", hook) //nolint:gosec // G204: hook is from trusted rig config
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	// Don't wait on children still holding the output pipe after a timeout.
+	cmd.WaitDelay = time.Second
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+
+	output := out.String()
+	if len(output) > remediationOutputLimit {
+		output = "..." + output[len(output)-remediationOutputLimit:]
+	}
+	result.Output = strings.TrimSpace(output)
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case ctx.Err() != nil:
+		result.ExitCode = -1
+		result.Error = fmt.Sprintf("timed out after %s", timeout)
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+		if result.ExitCode == RemediationVetoExitCode {
+			result.Vetoed = true
+		} else {
+			result.Error = fmt.Sprintf("exited %d", result.ExitCode)
+		}
+	default:
+		result.ExitCode = -1
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// remediationEnv describes a tripped polecat to its remediation hook.
+func remediationEnv(townRoot, rigName string, tc *TrippedCircuit) []string {
+	return []string{
+		"GT_ROOT=" + townRoot,
+		"GT_RIG=" + rigName,
+		"GT_POLECAT=" + tc.Polecat,
+		"GT_POLECAT_PATH=" + polecatWorktree(townRoot, rigName, tc.Polecat),
+		"GT_AGENT_BEAD=" + tc.AgentBeadID,
+		"GT_ISSUE=" + tc.HookBead,
+		"GT_FORENSICS=" + tc.Forensics,
+		"GT_FAILURE_COUNT=" + strconv.Itoa(tc.FailureCount),
+		"GT_TRIP_COUNT=" + strconv.Itoa(tc.TripCount),
+		"GT_LAST_FAILURE=" + failureReasonOrUnknown(tc.LastFailure),
+	}
+}
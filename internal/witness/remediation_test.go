@@ -0,0 +1,43 @@
+package witness
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunRemediationHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("remediation hooks run through sh")
+	}
+	tests := []struct {
+		name      string
+		hook      string
+		wantCode  int
+		wantVeto  bool
+		wantError bool
+	}{
+		{"success", "exit 0", 0, false, false},
+		{"veto", "echo keep the worktree; exit 2", 2, true, false},
+		{"failure does not veto", "exit 1", 1, false, true},
+		{"sees env", `test "$GT_POLECAT" = nux`, 0, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := RunRemediationHook(tt.hook, t.TempDir(), []string{"GT_POLECAT=nux"}, time.Minute)
+			if r.ExitCode != tt.wantCode || r.Vetoed != tt.wantVeto || (r.Error != "") != tt.wantError {
+				t.Errorf("RunRemediationHook(%q) = %+v", tt.hook, r)
+			}
+		})
+	}
+
+	r := RunRemediationHook("echo keep the worktree; exit 2", t.TempDir(), nil, time.Minute)
+	if got := r.VetoReason(); got != "remediation hook: keep the worktree" {
+		t.Errorf("VetoReason() = %q", got)
+	}
+	r = RunRemediationHook("sleep 5", t.TempDir(), nil, 50*time.Millisecond)
+	if r.Vetoed || !strings.Contains(r.Error, "timed out") {
+		t.Errorf("timed-out hook = %+v, want a non-vetoing timeout error", r)
+	}
+}
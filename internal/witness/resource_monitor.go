@@ -0,0 +1,352 @@
+package witness
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/procfs"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// DefaultResourceSustain is how many consecutive samples over budget it
+// takes before the Witness acts on a polecat.
+const DefaultResourceSustain = 3
+
+// ResourceBudgetConfig caps what a polecat's session processes (the tmux
+// pane and everything under it) may consume. A zero budget is not enforced.
+// A polecat over any budget for Sustain consecutive samples has its circuit
+// tripped (Action "trip"), so the next sweep requeues its work and nukes it,
+// or is escalated to the Mayor (Action "escalate").
+type ResourceBudgetConfig struct {
+	MaxCPUPercent float64 // percent of one core
+	MaxRSSBytes   uint64
+	MaxFDs        int
+	Sustain       int
+	Action        string
+}
+
+// DefaultResourceBudgetConfig returns the built-in budget settings: no
+// budgets, so usage is only recorded.
+func DefaultResourceBudgetConfig() ResourceBudgetConfig {
+	return ResourceBudgetConfig{
+		Sustain: DefaultResourceSustain,
+		Action:  config.WitnessActionTrip,
+	}
+}
+
+// Enforced reports whether any budget is set.
+func (c ResourceBudgetConfig) Enforced() bool {
+	return c.MaxCPUPercent > 0 || c.MaxRSSBytes > 0 || c.MaxFDs > 0
+}
+
+// ResourceBudgetConfigFrom applies a rig's resource_budget settings over the
+// defaults. Nil settings and zero values keep the defaults.
+func ResourceBudgetConfigFrom(s *config.ResourceBudgetSettings) (ResourceBudgetConfig, error) {
+	cfg := DefaultResourceBudgetConfig()
+	if s == nil {
+		return cfg, nil
+	}
+	if err := config.ValidateResourceBudgetSettings(s); err != nil {
+		return cfg, err
+	}
+	cfg.MaxCPUPercent = s.MaxCPUPercent
+	cfg.MaxRSSBytes = uint64(s.MaxRSSMB) << 20
+	cfg.MaxFDs = s.MaxFDs
+	if s.Sustain > 0 {
+		cfg.Sustain = s.Sustain
+	}
+	if s.Action != "" {
+		cfg.Action = s.Action
+	}
+	return cfg, nil
+}
+
+// LoadResourceBudgetConfig reads the budget settings from the rig's
+// settings/config.json ("witness.resource_budget"). A rig without a
+// settings file gets the defaults; an invalid file is an error.
+func LoadResourceBudgetConfig(rigPath string) (ResourceBudgetConfig, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return DefaultResourceBudgetConfig(), nil
+		}
+		return DefaultResourceBudgetConfig(), err
+	}
+	var s *config.ResourceBudgetSettings
+	if settings.Witness != nil {
+		s = settings.Witness.ResourceBudget
+	}
+	return ResourceBudgetConfigFrom(s)
+}
+
+// ResourceUsage is one sample of a polecat session's processes.
+type ResourceUsage struct {
+	Procs    int    `json:"procs"`
+	RSSBytes uint64 `json:"rss_bytes"`
+	FDs      int    `json:"fds"`
+	CPUTicks int64  `json:"cpu_ticks"` // CPU time so far, in procfs.ClockTicks
+
+	// CPUPercent is the CPU used since the previous sample, in percent of
+	// one core. CPUMeasured is false on a first sample, or when processes
+	// exited in between and the tree's CPU time went down.
+	CPUPercent  float64 `json:"cpu_percent"`
+	CPUMeasured bool    `json:"cpu_measured"`
+}
+
+// String summarizes the sample for the agent bead's resource_usage field.
+func (u ResourceUsage) String() string {
+	cpu := "cpu n/a"
+	if u.CPUMeasured {
+		cpu = fmt.Sprintf("cpu %.1f%%", u.CPUPercent)
+	}
+	return fmt.Sprintf("%s, rss %dMB, fds %d, procs %d", cpu, u.RSSBytes>>20, u.FDs, u.Procs)
+}
+
+// overBudget returns why usage exceeds the config's budgets, if it does.
+func (c ResourceBudgetConfig) overBudget(u ResourceUsage) []string {
+	var over []string
+	if c.MaxCPUPercent > 0 && u.CPUMeasured && u.CPUPercent > c.MaxCPUPercent {
+		over = append(over, fmt.Sprintf("cpu %.1f%% > %g%%", u.CPUPercent, c.MaxCPUPercent))
+	}
+	if c.MaxRSSBytes > 0 && u.RSSBytes > c.MaxRSSBytes {
+		over = append(over, fmt.Sprintf("rss %dMB > %dMB", u.RSSBytes>>20, c.MaxRSSBytes>>20))
+	}
+	if c.MaxFDs > 0 && u.FDs > c.MaxFDs {
+		over = append(over, fmt.Sprintf("fds %d > %d", u.FDs, c.MaxFDs))
+	}
+	return over
+}
+
+// SampleProcessTree sums the resources of pid and its descendants from a
+// /proc-style directory. Processes that exit mid-sample are skipped.
+func SampleProcessTree(root string, pid int) (ResourceUsage, error) {
+	tree, err := procfs.Tree(root, pid)
+	if err != nil {
+		return ResourceUsage{}, err
+	}
+	var u ResourceUsage
+	for _, p := range tree {
+		stat, err := procfs.ReadStat(root, p)
+		if err != nil {
+			continue
+		}
+		u.Procs++
+		u.CPUTicks += stat.CPUTicks
+		if rss, err := procfs.RSSBytes(root, p); err == nil {
+			u.RSSBytes += rss
+		}
+		if n, err := procfs.CountFDs(root, p); err == nil {
+			u.FDs += n
+		}
+	}
+	if u.Procs == 0 {
+		return u, fmt.Errorf("process %d not found", pid)
+	}
+	return u, nil
+}
+
+// measureCPU sets u's CPU percent from the previous sample recorded on the
+// agent bead (its CPU ticks and RFC3339 time).
+func measureCPU(u *ResourceUsage, prevTicks int64, prevAt string, now time.Time) {
+	at, err := time.Parse(time.RFC3339, prevAt)
+	if err != nil || prevTicks <= 0 || u.CPUTicks < prevTicks {
+		return
+	}
+	elapsed := now.Sub(at).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	u.CPUPercent = float64(u.CPUTicks-prevTicks) / procfs.ClockTicks / elapsed * 100
+	u.CPUMeasured = true
+}
+
+// PolecatResources is one polecat's sample and what the monitor did about it.
+type PolecatResources struct {
+	Polecat     string        `json:"polecat"`
+	AgentBeadID string        `json:"agent_bead"`
+	Usage       ResourceUsage `json:"usage"`
+	Over        []string      `json:"over_budget,omitempty"`
+	Strikes     int           `json:"strikes,omitempty"` // consecutive samples over budget
+	Tripped     bool          `json:"tripped,omitempty"`
+	Escalated   bool          `json:"escalated,omitempty"`
+	Escalation  string        `json:"escalation_mail,omitempty"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// MonitorResourcesResult summarizes one resource monitor pass over a rig.
+type MonitorResourcesResult struct {
+	Rig      string             `json:"rig"`
+	Enforced bool               `json:"enforced"` // any budget set
+	Polecats []PolecatResources `json:"polecats,omitempty"`
+	Errors   []string           `json:"errors,omitempty"`
+}
+
+// resourceBeads is the subset of beads operations the monitor needs.
+type resourceBeads interface {
+	ListAgentBeads() (map[string]*beads.Issue, error)
+	RecordAgentResources(id, usage string, cpuTicks int64, sampledAt time.Time, overBudget bool) (*beads.AgentFields, error)
+	OpenAgentCircuit(id string) (*beads.AgentFields, error)
+	UpdateAgentDescriptionFields(id string, updates beads.AgentFieldUpdates) error
+}
+
+// resourceMonitor carries what a monitor pass needs, so the process table
+// and side effects can be replaced in tests.
+type resourceMonitor struct {
+	rigName string
+	bd      resourceBeads
+	panePID func(polecatName string) (int, error)
+	sample  func(pid int) (ResourceUsage, error)
+	send    func(*mail.Message) error
+	now     time.Time
+
+	// escalation routes RESOURCE_BUDGET.
+	escalation EscalationPolicy
+
+	// history records trips.
+	history circuitRecorder
+}
+
+// MonitorPolecatResources samples the CPU, memory and file descriptors of
+// each live polecat session in the rig (its tmux pane process and
+// descendants, from /proc) and records the sample on the polecat's agent
+// bead. A polecat over cfg's budgets for cfg.Sustain consecutive samples
+// has its circuit tripped, or is escalated to the Mayor, once per streak.
+// Polecats without a session, and those whose circuit is already open, are
+// skipped. Linux only: elsewhere no session can be sampled.
+func MonitorPolecatResources(workDir, rigName string, router *mail.Router, cfg ResourceBudgetConfig) *MonitorResourcesResult {
+	bd, townRoot := rigBeads(workDir, rigName)
+	t := tmux.NewTmux()
+	m := &resourceMonitor{
+		rigName: rigName,
+		bd:      bd,
+		panePID: func(polecatName string) (int, error) {
+			pid, err := t.GetPanePID(session.PolecatSessionName(session.PrefixFor(rigName), polecatName))
+			if err != nil {
+				return 0, err
+			}
+			return strconv.Atoi(pid)
+		},
+		sample: func(pid int) (ResourceUsage, error) { return SampleProcessTree(procfs.Root, pid) },
+		send:   router.Send,
+		now:    time.Now(),
+
+		escalation: rigEscalationPolicy(townRoot, rigName),
+		history:    rigCircuitRecorder(townRoot, rigName),
+	}
+	return m.run(cfg)
+}
+
+func (m *resourceMonitor) run(cfg ResourceBudgetConfig) *MonitorResourcesResult {
+	result := &MonitorResourcesResult{Rig: m.rigName, Enforced: cfg.Enforced()}
+
+	agents, err := m.bd.ListAgentBeads()
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("listing agent beads: %v", err))
+		return result
+	}
+	ids := make([]string, 0, len(agents))
+	for id := range agents {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		issue := agents[id]
+		fields := beads.ParseAgentFields(issue.Description)
+		if fields.RoleType != "polecat" || fields.Rig != m.rigName || fields.CircuitState == beads.CircuitOpen {
+			continue
+		}
+		agentState := issue.AgentState
+		if agentState == "" {
+			agentState = fields.AgentState
+		}
+		if agentState == "nuked" {
+			continue
+		}
+		_, _, polecatName, ok := beads.ParseAgentBeadID(id)
+		if !ok || polecatName == "" {
+			continue
+		}
+		pid, err := m.panePID(polecatName)
+		if err != nil {
+			continue // no live session
+		}
+		usage, err := m.sample(pid)
+		if err != nil {
+			continue // exited since tmux reported it
+		}
+		measureCPU(&usage, fields.ResourceCPUTicks, fields.ResourceSampledAt, m.now)
+
+		pr := PolecatResources{Polecat: polecatName, AgentBeadID: id, Usage: usage, Over: cfg.overBudget(usage)}
+		updated, err := m.bd.RecordAgentResources(id, usage.String(), usage.CPUTicks, m.now, len(pr.Over) > 0)
+		if err != nil {
+			pr.Error = fmt.Sprintf("recording sample: %v", err)
+			result.Polecats = append(result.Polecats, pr)
+			continue
+		}
+		pr.Strikes = updated.ResourceStrikes
+		if pr.Strikes == cfg.Sustain {
+			// Act once per streak: the strikes keep counting while the
+			// polecat stays over, and reset when it comes back under.
+			m.act(&pr, cfg)
+		}
+		result.Polecats = append(result.Polecats, pr)
+	}
+	return result
+}
+
+// act trips the circuit of, or escalates, a polecat that stayed over budget.
+func (m *resourceMonitor) act(pr *PolecatResources, cfg ResourceBudgetConfig) {
+	reason := "over resource budget: " + strings.Join(pr.Over, ", ")
+	if cfg.Action == config.WitnessActionTrip {
+		if _, err := m.bd.OpenAgentCircuit(pr.AgentBeadID); err != nil {
+			pr.Error = fmt.Sprintf("opening circuit: %v", err)
+			return
+		}
+		pr.Tripped = true
+		m.history.record(CircuitEvent{Time: m.now, Event: CircuitEventTrip, Polecat: pr.Polecat, Reason: reason})
+		note := circuitNote("tripped", m.rigName+"/witness", reason, m.now)
+		if err := m.bd.UpdateAgentDescriptionFields(pr.AgentBeadID, beads.AgentFieldUpdates{CircuitNote: &note}); err != nil {
+			pr.Error = fmt.Sprintf("recording circuit note: %v", err)
+		}
+		return
+	}
+
+	msg := resourceBudgetEscalation(m.rigName, pr, cfg)
+	if err := m.escalation.Escalate(m.send, msg); err != nil {
+		pr.Error = fmt.Sprintf("escalating: %v", err)
+		return
+	}
+	pr.Escalated = true
+	pr.Escalation = msg.ID
+}
+
+// resourceBudgetEscalation builds the RESOURCE_BUDGET mail to the Mayor for
+// a polecat over budget when the rig's action is "escalate".
+func resourceBudgetEscalation(rigName string, pr *PolecatResources, cfg ResourceBudgetConfig) *mail.Message {
+	msg := mail.NewMessage(
+		fmt.Sprintf("%s/witness", rigName),
+		"mayor/",
+		fmt.Sprintf("RESOURCE_BUDGET %s/%s", rigName, pr.Polecat),
+		fmt.Sprintf(`Polecat has been over its resource budget for %d consecutive samples.
+
+Polecat: %s/%s
+Usage: %s
+Over: %s
+
+Check what it is running ('gt peek %s/%s'). To quarantine it, trip its
+circuit with 'gt witness circuit trip %s/%s --reason "runaway"'.`,
+			cfg.Sustain, rigName, pr.Polecat, pr.Usage, strings.Join(pr.Over, ", "),
+			rigName, pr.Polecat, rigName, pr.Polecat),
+	)
+	msg.Priority = mail.PriorityHigh
+	return msg
+}
@@ -0,0 +1,169 @@
+package witness
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func (f *memoryBeads) RecordAgentResources(id, usage string, cpuTicks int64, sampledAt time.Time, overBudget bool) (*beads.AgentFields, error) {
+	fields := f.fields(id)
+	fields.ResourceUsage = usage
+	fields.ResourceCPUTicks = cpuTicks
+	fields.ResourceSampledAt = sampledAt.UTC().Format(time.RFC3339)
+	if overBudget {
+		fields.ResourceStrikes++
+	} else {
+		fields.ResourceStrikes = 0
+	}
+	copied := *fields
+	return &copied, nil
+}
+
+func (f *memoryBeads) UpdateAgentDescriptionFields(id string, updates beads.AgentFieldUpdates) error {
+	if updates.CircuitNote != nil {
+		f.fields(id).CircuitNote = *updates.CircuitNote
+	}
+	return nil
+}
+
+// newTestResourceMonitor returns a monitor whose every live polecat samples
+// as *usage, one minute apart on each run.
+func newTestResourceMonitor(bd *memoryBeads, usage *ResourceUsage, sent *[]*mail.Message) *resourceMonitor {
+	return &resourceMonitor{
+		rigName: "gastown",
+		bd:      bd,
+		panePID: func(string) (int, error) { return 100, nil },
+		sample:  func(int) (ResourceUsage, error) { return *usage, nil },
+		send:    func(m *mail.Message) error { *sent = append(*sent, m); return nil },
+		now:     time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestResourceMonitor_TripsAfterSustainedOverage(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	id := "gt-gastown-polecat-nux"
+	bd.fields(id)
+
+	var sent []*mail.Message
+	usage := ResourceUsage{Procs: 4, RSSBytes: 6 << 30, FDs: 200, CPUTicks: 1000}
+	m := newTestResourceMonitor(bd, &usage, &sent)
+	cfg := DefaultResourceBudgetConfig()
+	cfg.MaxRSSBytes = 4 << 30
+	cfg.MaxCPUPercent = 150
+	cfg.Sustain = 2
+
+	first := m.run(cfg).Polecats[0]
+	if first.Tripped || first.Strikes != 1 || len(first.Over) != 1 {
+		t.Fatalf("first sample = %+v, want one strike for rss, not tripped", first)
+	}
+	if first.Usage.CPUMeasured {
+		t.Error("first sample measured CPU without a previous sample")
+	}
+
+	// A minute later the tree used 2 cores' worth of CPU: ticks grew by
+	// 2 * 60s * ClockTicks.
+	m.now = m.now.Add(time.Minute)
+	usage.CPUTicks += 2 * 60 * 100
+	second := m.run(cfg).Polecats[0]
+	if !second.Usage.CPUMeasured || second.Usage.CPUPercent != 200 {
+		t.Errorf("cpu = %+v, want 200%% measured", second.Usage)
+	}
+	if !second.Tripped || second.Strikes != 2 || len(second.Over) != 2 {
+		t.Fatalf("second sample = %+v, want tripped on rss and cpu", second)
+	}
+	fields := bd.agents[id]
+	if fields.CircuitState != beads.CircuitOpen || !strings.Contains(fields.CircuitNote, "over resource budget: cpu 200.0% > 150%, rss 6144MB > 4096MB") {
+		t.Errorf("agent bead = %+v, want circuit open with the overage in circuit_note", fields)
+	}
+	if fields.ResourceUsage != second.Usage.String() {
+		t.Errorf("resource_usage = %q, want %q", fields.ResourceUsage, second.Usage.String())
+	}
+	if len(sent) != 0 {
+		t.Errorf("trip action sent mail: %+v", sent)
+	}
+
+	// An open circuit is left to the sweep.
+	if got := m.run(cfg).Polecats; len(got) != 0 {
+		t.Errorf("sampled a polecat whose circuit is open: %+v", got)
+	}
+}
+
+func TestResourceMonitor_EscalatesOncePerStreak(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	id := "gt-gastown-polecat-nux"
+	bd.fields(id)
+
+	var sent []*mail.Message
+	usage := ResourceUsage{Procs: 2, FDs: 5000}
+	m := newTestResourceMonitor(bd, &usage, &sent)
+	cfg := DefaultResourceBudgetConfig()
+	cfg.MaxFDs = 1024
+	cfg.Sustain = 1
+	cfg.Action = config.WitnessActionEscalate
+
+	if pr := m.run(cfg).Polecats[0]; !pr.Escalated || pr.Tripped {
+		t.Fatalf("first sample = %+v, want escalated, not tripped", pr)
+	}
+	if len(sent) != 1 || !strings.HasPrefix(sent[0].Subject, "RESOURCE_BUDGET gastown/nux") ||
+		!strings.Contains(sent[0].Body, "fds 5000 > 1024") {
+		t.Fatalf("escalation = %+v", sent)
+	}
+	if bd.agents[id].CircuitState == beads.CircuitOpen {
+		t.Error("escalate action opened the circuit")
+	}
+
+	// Still over: no repeat. Back under, then over again: a new streak.
+	if pr := m.run(cfg).Polecats[0]; pr.Escalated {
+		t.Errorf("escalated again within the same streak: %+v", pr)
+	}
+	usage.FDs = 100
+	if pr := m.run(cfg).Polecats[0]; pr.Strikes != 0 || len(pr.Over) != 0 {
+		t.Errorf("within budget = %+v, want strikes cleared", pr)
+	}
+	usage.FDs = 5000
+	if pr := m.run(cfg).Polecats[0]; !pr.Escalated {
+		t.Errorf("new streak = %+v, want escalated", pr)
+	}
+	if len(sent) != 2 {
+		t.Errorf("sent %d escalations, want 2", len(sent))
+	}
+}
+
+func TestResourceMonitor_RecordsWithoutBudgets(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	id := "gt-gastown-polecat-nux"
+	bd.fields(id)
+	*bd.fields("gt-gastown-polecat-slit") = beads.AgentFields{RoleType: "polecat", Rig: "gastown", AgentState: "nuked"}
+
+	var sent []*mail.Message
+	usage := ResourceUsage{Procs: 3, RSSBytes: 64 << 30, FDs: 90000}
+	result := newTestResourceMonitor(bd, &usage, &sent).run(DefaultResourceBudgetConfig())
+	if result.Enforced || len(result.Polecats) != 1 {
+		t.Fatalf("result = %+v, want one sample, nothing enforced", result)
+	}
+	if pr := result.Polecats[0]; len(pr.Over) != 0 || pr.Strikes != 0 || pr.Tripped || pr.Escalated {
+		t.Errorf("sample without budgets = %+v, want recorded only", pr)
+	}
+	if got := bd.agents[id].ResourceUsage; got != "cpu n/a, rss 65536MB, fds 90000, procs 3" {
+		t.Errorf("resource_usage = %q", got)
+	}
+}
+
+func TestResourceBudgetConfigFrom(t *testing.T) {
+	cfg, err := ResourceBudgetConfigFrom(&config.ResourceBudgetSettings{MaxRSSMB: 2048, MaxFDs: 512})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MaxRSSBytes != 2048<<20 || cfg.MaxFDs != 512 || cfg.Sustain != DefaultResourceSustain ||
+		cfg.Action != config.WitnessActionTrip || !cfg.Enforced() {
+		t.Errorf("config = %+v", cfg)
+	}
+	if _, err := ResourceBudgetConfigFrom(&config.ResourceBudgetSettings{Action: "kill"}); err == nil {
+		t.Error("invalid action accepted")
+	}
+}
@@ -0,0 +1,185 @@
+package witness
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// DefaultSimulationSweepInterval is how often a simulation sweeps the rig,
+// as the Witness patrol would.
+const DefaultSimulationSweepInterval = 5 * time.Minute
+
+// simulationPrefix is the bead prefix of a simulation's agent beads.
+const simulationPrefix = "gt"
+
+// SimulationResult is what the breaker would have done with a recorded
+// sequence of failures and successes under a given config.
+type SimulationResult struct {
+	Rig       string                `json:"rig"`
+	Config    CircuitBreakerSummary `json:"config"`
+	Since     time.Time             `json:"since"`
+	Until     time.Time             `json:"until"`
+	Failures  int                   `json:"failures"`  // failures replayed
+	Successes int                   `json:"successes"` // landed work replayed
+	Sweeps    int                   `json:"sweeps"`
+
+	// Events is the circuit history the breaker would have recorded, and
+	// Metrics its summary.
+	Events  []CircuitEvent `json:"events,omitempty"`
+	Metrics CircuitMetrics `json:"metrics"`
+
+	// BrokenWork lists the beads that would have been blocked as likely
+	// broken; Deferred counts circuits a sweep left to the next one.
+	BrokenWork []string `json:"broken_work,omitempty"`
+	Deferred   int      `json:"deferred,omitempty"`
+
+	// Mail counts the mail the Witness would have sent, by subject type
+	// (WORK_REQUEUE, CIRCUIT_TRIPPED, BROKEN_WORK).
+	Mail map[string]int `json:"mail,omitempty"`
+}
+
+// SimulateCircuitBreakers replays the failure and success events among
+// inputs (as recorded in a rig's circuit history) against cfg, sweeping
+// every sweepEvery, and reports what the breaker would have done. Nothing
+// outside memory is touched: every requeue and block succeeds, and every
+// tripped polecat is assumed clean and nuked.
+func SimulateCircuitBreakers(rigName string, cfg CircuitBreakerConfig, inputs []CircuitEvent, sweepEvery time.Duration) *SimulationResult {
+	var replay []CircuitEvent
+	for _, ev := range inputs {
+		if ev.Event == CircuitEventFailure || ev.Event == CircuitEventSuccess {
+			replay = append(replay, ev)
+		}
+	}
+	sort.SliceStable(replay, func(i, j int) bool { return replay[i].Time.Before(replay[j].Time) })
+	if sweepEvery <= 0 {
+		sweepEvery = DefaultSimulationSweepInterval
+	}
+
+	sim := &circuitSimulation{
+		result: &SimulationResult{Rig: rigName, Config: cfg.summary(), Mail: make(map[string]int)},
+		bd:     newMemoryBeads(rigName),
+		outbox: &memoryMail{},
+	}
+	sim.bd.now = func() time.Time { return sim.clock }
+	sim.sweep = &circuitSweep{
+		rigName: rigName,
+		bd:      sim.bd,
+		send:    sim.outbox.Send,
+		requeue: func(string) bool { return true },
+		block:   func(string) bool { return true },
+		nuke: func(polecatName string) *NukePolecatResult {
+			sim.bd.fields(sim.agentID(polecatName)).AgentState = "nuked"
+			return &NukePolecatResult{Nuked: true, Reason: "nuked (simulated)"}
+		},
+		capture: func(string) (string, error) { return "", nil },
+		history: sim.record,
+	}
+
+	if len(replay) > 0 {
+		sim.result.Since = replay[0].Time
+		next := replay[0].Time
+		for _, ev := range replay {
+			for !next.After(ev.Time) {
+				sim.sweepAt(next, cfg)
+				next = next.Add(sweepEvery)
+			}
+			sim.clock = ev.Time
+			if ev.Event == CircuitEventFailure {
+				sim.fail(ev, cfg)
+			} else {
+				sim.succeed(ev, cfg)
+			}
+		}
+		// One more sweep acts on circuits the last failures tripped.
+		sim.sweepAt(next, cfg)
+		sim.result.Until = next
+	}
+
+	result := sim.result
+	result.Metrics = ComputeCircuitMetrics(result.Events, result.Since, result.Until)
+	for _, msg := range sim.outbox.sent {
+		kind, _, _ := strings.Cut(msg.Subject, " ")
+		result.Mail[kind]++
+	}
+	return result
+}
+
+// circuitSimulation is the state of one SimulateCircuitBreakers run.
+type circuitSimulation struct {
+	result *SimulationResult
+	bd     *memoryBeads
+	outbox *memoryMail
+	sweep  *circuitSweep
+	clock  time.Time
+}
+
+func (sim *circuitSimulation) agentID(polecatName string) string {
+	return beads.PolecatBeadIDWithPrefix(simulationPrefix, sim.result.Rig, polecatName)
+}
+
+// record adds an event to the simulated history, at the simulation's clock.
+func (sim *circuitSimulation) record(ev CircuitEvent) {
+	ev.Time = sim.clock
+	sim.result.Events = append(sim.result.Events, ev)
+}
+
+func (sim *circuitSimulation) sweepAt(at time.Time, cfg CircuitBreakerConfig) {
+	sim.clock = at
+	sim.sweep.now = at
+	swept := sim.sweep.run(cfg)
+	sim.result.Sweeps++
+	sim.result.Deferred += swept.Deferred
+	for _, tc := range swept.Tripped {
+		if tc.BrokenWork {
+			sim.result.BrokenWork = append(sim.result.BrokenWork, tc.HookBead)
+		}
+	}
+}
+
+// fail replays a failure as HandlePolecatFailure would count it. The
+// polecat is working again, on ev.Bead; if the failure doesn't trip its
+// circuit the work is assumed to be redispatched.
+func (sim *circuitSimulation) fail(ev CircuitEvent, cfg CircuitBreakerConfig) {
+	sim.result.Failures++
+	id := sim.agentID(ev.Polecat)
+	fields := sim.bd.fields(id)
+	fields.AgentState = "working"
+	fields.HookBead = ev.Bead
+
+	reason := ev.Reason
+	switch reason {
+	case beads.FailureTransient, beads.FailureDeterministic, beads.FailureUnknown:
+	default:
+		reason = beads.FailureUnknown
+	}
+	failure, err := recordPolecatFailure(sim.bd, id, reason, cfg, sim.bd.now())
+	if err != nil {
+		return
+	}
+	if failure.Tripped {
+		sim.record(CircuitEvent{Event: CircuitEventTrip, Polecat: ev.Polecat, Bead: ev.Bead, Failures: failure.FailureCount, Reason: reason})
+	}
+	if ev.Bead != "" && recordWorkFailure(sim.bd, failure, ev.Bead, sim.result.Rig+"/"+ev.Polecat, cfg) == nil && failure.BrokenWork && !failure.Tripped {
+		// Blocked by the failure handler; a tripped polecat's broken work
+		// is blocked by the sweep.
+		sim.result.BrokenWork = append(sim.result.BrokenWork, ev.Bead)
+	}
+	if !failure.Tripped {
+		fields.HookBead = ""
+	}
+}
+
+// succeed replays landed work as RecordPolecatSuccess would.
+func (sim *circuitSimulation) succeed(ev CircuitEvent, cfg CircuitBreakerConfig) {
+	sim.result.Successes++
+	id := sim.agentID(ev.Polecat)
+	fields := sim.bd.fields(id)
+	fields.AgentState = "working"
+	copied := *fields
+	if reset, _ := recordPolecatSuccess(sim.bd, id, &copied, cfg, sim.clock); reset != "" {
+		sim.record(CircuitEvent{Event: CircuitEventReset, Polecat: ev.Polecat, Reason: reset})
+	}
+}
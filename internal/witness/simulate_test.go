@@ -0,0 +1,71 @@
+package witness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestSimulateCircuitBreakers(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(min int) time.Time { return start.Add(time.Duration(min) * time.Minute) }
+	inputs := []CircuitEvent{
+		{Time: at(0), Event: CircuitEventFailure, Polecat: "nux", Bead: "gt-a", Reason: beads.FailureDeterministic},
+		{Time: at(10), Event: CircuitEventFailure, Polecat: "nux", Bead: "gt-b", Reason: beads.FailureDeterministic},
+		{Time: at(12), Event: CircuitEventTrip, Polecat: "nux"}, // what happened; not replayed
+		{Time: at(20), Event: CircuitEventFailure, Polecat: "nux", Bead: "gt-c", Reason: beads.FailureDeterministic},
+		{Time: at(30), Event: CircuitEventFailure, Polecat: "slit", Bead: "gt-d", Reason: beads.FailureTransient},
+		{Time: at(40), Event: CircuitEventSuccess, Polecat: "slit"},
+	}
+
+	cfg := DefaultCircuitBreakerConfig()
+	sim := SimulateCircuitBreakers("gastown", cfg, inputs, 5*time.Minute)
+	if sim.Failures != 4 || sim.Successes != 1 || sim.Sweeps == 0 {
+		t.Fatalf("replayed %d failures, %d successes in %d sweeps; want 4 and 1", sim.Failures, sim.Successes, sim.Sweeps)
+	}
+	var kinds []string
+	for _, ev := range sim.Events {
+		kinds = append(kinds, ev.Event+":"+ev.Polecat)
+	}
+	want := []string{"trip:nux", "requeue:nux", "nuke:nux"}
+	if len(kinds) != len(want) || kinds[0] != want[0] || kinds[1] != want[1] || kinds[2] != want[2] {
+		t.Fatalf("simulated history = %v, want %v", kinds, want)
+	}
+	if trip := sim.Events[0]; !trip.Time.Equal(at(20)) || trip.Failures != 3 || trip.Bead != "gt-c" {
+		t.Errorf("trip = %+v, want nux's third failure on gt-c", trip)
+	}
+	if requeue := sim.Events[1]; !requeue.Time.Equal(at(25)) {
+		t.Errorf("requeued at %v, want the next sweep at %v", requeue.Time, at(25))
+	}
+	if sim.Mail["WORK_REQUEUE"] != 1 || sim.Metrics.Trips != 1 {
+		t.Errorf("mail %v, metrics %+v; want one WORK_REQUEUE and one trip", sim.Mail, sim.Metrics)
+	}
+
+	// A more lenient breaker never trips on the same sequence.
+	cfg.MaxFailures = 4
+	if sim := SimulateCircuitBreakers("gastown", cfg, inputs, 5*time.Minute); len(sim.Events) != 0 || len(sim.Mail) != 0 {
+		t.Errorf("max_failures 4: events %v, mail %v; want none", sim.Events, sim.Mail)
+	}
+}
+
+func TestSimulateCircuitBreakers_HalfOpenProbe(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var inputs []CircuitEvent
+	for i := 0; i < 3; i++ {
+		inputs = append(inputs, CircuitEvent{Time: start.Add(time.Duration(i) * time.Minute), Event: CircuitEventFailure, Polecat: "nux"})
+	}
+	// The probe after the cooldown lands, and then a second one.
+	inputs = append(inputs, CircuitEvent{Time: start.Add(2 * time.Hour), Event: CircuitEventSuccess, Polecat: "nux"})
+
+	sim := SimulateCircuitBreakers("gastown", DefaultCircuitBreakerConfig(), inputs, 5*time.Minute)
+	if sim.Metrics.Trips != 1 || sim.Metrics.HalfOpens != 1 || sim.Metrics.Resets != 0 {
+		t.Errorf("one probe: metrics = %+v, want one trip and half_open, no reset", sim.Metrics)
+	}
+
+	inputs = append(inputs, CircuitEvent{Time: start.Add(3 * time.Hour), Event: CircuitEventSuccess, Polecat: "nux"})
+	sim = SimulateCircuitBreakers("gastown", DefaultCircuitBreakerConfig(), inputs, 5*time.Minute)
+	if sim.Metrics.Trips != 1 || sim.Metrics.HalfOpens != 1 || sim.Metrics.Resets != 1 {
+		t.Errorf("two probes: metrics = %+v, want one trip, half_open and reset", sim.Metrics)
+	}
+}
@@ -0,0 +1,347 @@
+package witness
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Stall detection defaults.
+const (
+	DefaultStallTimeout    = 20 * time.Minute
+	DefaultStallNudgeGrace = 10 * time.Minute
+	DefaultStallLines      = 40
+)
+
+// StallDetectionConfig decides when a working polecat whose pane output
+// stopped changing is stuck. Output unchanged for Timeout gets the polecat
+// one nudge; still unchanged NudgeGrace after the nudge, its circuit is
+// tripped (Action "trip") or it is escalated to the Mayor ("escalate").
+type StallDetectionConfig struct {
+	Timeout    time.Duration
+	NudgeGrace time.Duration
+	Lines      int // trailing pane lines fingerprinted
+	Action     string
+}
+
+// DefaultStallDetectionConfig returns the built-in stall detection settings.
+func DefaultStallDetectionConfig() StallDetectionConfig {
+	return StallDetectionConfig{
+		Timeout:    DefaultStallTimeout,
+		NudgeGrace: DefaultStallNudgeGrace,
+		Lines:      DefaultStallLines,
+		Action:     config.WitnessActionTrip,
+	}
+}
+
+// StallDetectionConfigFrom applies a rig's stall_detection settings over the
+// defaults. Nil settings and zero values keep the defaults.
+func StallDetectionConfigFrom(s *config.StallDetectionSettings) (StallDetectionConfig, error) {
+	cfg := DefaultStallDetectionConfig()
+	if s == nil {
+		return cfg, nil
+	}
+	if err := config.ValidateStallDetectionSettings(s); err != nil {
+		return cfg, err
+	}
+	if s.Timeout != "" {
+		cfg.Timeout, _ = time.ParseDuration(s.Timeout)
+	}
+	if s.NudgeGrace != "" {
+		cfg.NudgeGrace, _ = time.ParseDuration(s.NudgeGrace)
+	}
+	if s.Lines > 0 {
+		cfg.Lines = s.Lines
+	}
+	if s.Action != "" {
+		cfg.Action = s.Action
+	}
+	return cfg, nil
+}
+
+// LoadStallDetectionConfig reads the stall detection settings from the rig's
+// settings/config.json ("witness.stall_detection"). A rig without a
+// settings file gets the defaults; an invalid file is an error.
+func LoadStallDetectionConfig(rigPath string) (StallDetectionConfig, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return DefaultStallDetectionConfig(), nil
+		}
+		return DefaultStallDetectionConfig(), err
+	}
+	var s *config.StallDetectionSettings
+	if settings.Witness != nil {
+		s = settings.Witness.StallDetection
+	}
+	return StallDetectionConfigFrom(s)
+}
+
+var (
+	// stallNoisePattern matches output that changes while an agent is
+	// frozen: ANSI escapes, spinner and status glyphs.
+	stallNoisePattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|[\x{2800}-\x{28FF}✻✽✶✳✢⏺·*]`)
+	// stallTimerPattern matches elapsed-time counters ("12s", "3m 4s",
+	// "1.5h") so a ticking clock doesn't count as progress.
+	stallTimerPattern = regexp.MustCompile(`\b\d+(\.\d+)?(ms|s|m|h)(\s*\d+(\.\d+)?(ms|s|m|h))*\b`)
+)
+
+// OutputFingerprint hashes pane output for stall detection. Spinners,
+// escape sequences, elapsed-time counters and whitespace are normalized
+// away first, so a session that only redraws its status line keeps the
+// same fingerprint.
+func OutputFingerprint(content string) string {
+	normalized := stallNoisePattern.ReplaceAllString(content, "")
+	normalized = stallTimerPattern.ReplaceAllString(normalized, "#")
+	normalized = strings.Join(strings.Fields(normalized), " ")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:8])
+}
+
+// PolecatStall is a polecat whose output has been frozen for at least the
+// stall timeout, and what this pass did about it.
+type PolecatStall struct {
+	Polecat     string `json:"polecat"`
+	AgentBeadID string `json:"agent_bead"`
+	HookBead    string `json:"hook_bead,omitempty"`
+	FrozenSince string `json:"frozen_since"`
+	State       string `json:"state"` // stall_state after this pass
+	Nudged      bool   `json:"nudged,omitempty"`
+	Tripped     bool   `json:"tripped,omitempty"`
+	Escalated   bool   `json:"escalated,omitempty"`
+	Escalation  string `json:"escalation_mail,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// CheckOutputStallsResult summarizes one stall detection pass over a rig.
+type CheckOutputStallsResult struct {
+	Rig     string         `json:"rig"`
+	Checked int            `json:"checked"` // working polecats fingerprinted
+	Stalled []PolecatStall `json:"stalled,omitempty"`
+	Errors  []string       `json:"errors,omitempty"`
+}
+
+// errNoLiveSession reports a polecat without a live session or agent to
+// fingerprint.
+var errNoLiveSession = errors.New("no live session")
+
+// stallBeads is the subset of beads operations stall detection needs.
+type stallBeads interface {
+	ListAgentBeads() (map[string]*beads.Issue, error)
+	RecordAgentOutput(id, fingerprint string, at time.Time) (*beads.AgentFields, error)
+	SetAgentStallState(id, state string, at time.Time) error
+	OpenAgentCircuit(id string) (*beads.AgentFields, error)
+	UpdateAgentDescriptionFields(id string, updates beads.AgentFieldUpdates) error
+}
+
+// stallDetector carries what a stall detection pass needs, so the tmux
+// session and side effects can be replaced in tests.
+type stallDetector struct {
+	rigName string
+	bd      stallBeads
+	capture func(polecatName string, lines int) (string, error)
+	nudge   func(polecatName, message string) error
+	send    func(*mail.Message) error
+	now     time.Time
+
+	// escalation routes POLECAT_STALLED.
+	escalation EscalationPolicy
+
+	// history records trips.
+	history circuitRecorder
+}
+
+// CheckOutputStalls fingerprints the last cfg.Lines lines of each working
+// polecat's pane and records the fingerprint on its agent bead. A polecat
+// whose fingerprint hasn't changed for cfg.Timeout is nudged once; if it is
+// still unchanged cfg.NudgeGrace later, its circuit is tripped (the next
+// sweep requeues its work) or the Mayor is mailed POLECAT_STALLED. Any
+// output change clears the stall. Polecats without hooked work, without a
+// live agent, or whose circuit is already open are skipped.
+func CheckOutputStalls(workDir, rigName string, router *mail.Router, cfg StallDetectionConfig) *CheckOutputStallsResult {
+	bd, townRoot := rigBeads(workDir, rigName)
+	t := tmux.NewTmux()
+	sessionName := func(polecatName string) string {
+		return session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
+	}
+	d := &stallDetector{
+		rigName: rigName,
+		bd:      bd,
+		capture: func(polecatName string, lines int) (string, error) {
+			name := sessionName(polecatName)
+			if alive, err := t.HasSession(name); err != nil || !alive || !t.IsAgentAlive(name) {
+				return "", errNoLiveSession
+			}
+			return t.CapturePane(name, lines)
+		},
+		// Nudge the session directly: a frozen agent won't be draining its
+		// queue.
+		nudge: func(polecatName, message string) error {
+			return t.NudgeSession(sessionName(polecatName), message)
+		},
+		send: router.Send,
+		now:  time.Now(),
+
+		escalation: rigEscalationPolicy(townRoot, rigName),
+		history:    rigCircuitRecorder(townRoot, rigName),
+	}
+	return d.run(cfg)
+}
+
+func (d *stallDetector) run(cfg StallDetectionConfig) *CheckOutputStallsResult {
+	result := &CheckOutputStallsResult{Rig: d.rigName}
+
+	agents, err := d.bd.ListAgentBeads()
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("listing agent beads: %v", err))
+		return result
+	}
+	ids := make([]string, 0, len(agents))
+	for id := range agents {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		issue := agents[id]
+		fields := beads.ParseAgentFields(issue.Description)
+		if fields.RoleType != "polecat" || fields.Rig != d.rigName || fields.CircuitState == beads.CircuitOpen {
+			continue
+		}
+		agentState := issue.AgentState
+		if agentState == "" {
+			agentState = fields.AgentState
+		}
+		hookBead := issue.HookBead
+		if hookBead == "" {
+			hookBead = fields.HookBead
+		}
+		// An idle polecat sits at its prompt: unchanged output is expected.
+		if agentState == "nuked" || hookBead == "" {
+			continue
+		}
+		_, _, polecatName, ok := beads.ParseAgentBeadID(id)
+		if !ok || polecatName == "" {
+			continue
+		}
+		content, err := d.capture(polecatName, cfg.Lines)
+		if errors.Is(err, errNoLiveSession) {
+			continue
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("capturing %s: %v", polecatName, err))
+			continue
+		}
+		result.Checked++
+
+		updated, err := d.bd.RecordAgentOutput(id, OutputFingerprint(content), d.now)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("recording output of %s: %v", polecatName, err))
+			continue
+		}
+		changedAt, err := time.Parse(time.RFC3339, updated.OutputChangedAt)
+		if err != nil || d.now.Sub(changedAt) < cfg.Timeout {
+			continue
+		}
+
+		stall := PolecatStall{
+			Polecat:     polecatName,
+			AgentBeadID: id,
+			HookBead:    hookBead,
+			FrozenSince: updated.OutputChangedAt,
+			State:       updated.StallState,
+		}
+		switch updated.StallState {
+		case "":
+			d.nudgeStalled(&stall, cfg)
+		case beads.StallNudged:
+			nudgedAt, err := time.Parse(time.RFC3339, updated.StallStateAt)
+			if err == nil && d.now.Sub(nudgedAt) >= cfg.NudgeGrace {
+				d.act(&stall, cfg)
+			}
+		}
+		result.Stalled = append(result.Stalled, stall)
+	}
+	return result
+}
+
+// nudgeStalled asks a polecat whose output froze how it is doing.
+func (d *stallDetector) nudgeStalled(stall *PolecatStall, cfg StallDetectionConfig) {
+	msg := fmt.Sprintf("Witness: your session output hasn't changed in %s. How's progress? "+
+		"If you're stuck, run 'gt escalate' or mail %s/witness.", cfg.Timeout, d.rigName)
+	if err := d.nudge(stall.Polecat, msg); err != nil {
+		stall.Error = fmt.Sprintf("nudging: %v", err)
+		return
+	}
+	stall.Nudged = true
+	d.setState(stall, beads.StallNudged)
+}
+
+// act trips the circuit of, or escalates, a polecat still frozen after its
+// nudge.
+func (d *stallDetector) act(stall *PolecatStall, cfg StallDetectionConfig) {
+	if cfg.Action == config.WitnessActionTrip {
+		if _, err := d.bd.OpenAgentCircuit(stall.AgentBeadID); err != nil {
+			stall.Error = fmt.Sprintf("opening circuit: %v", err)
+			return
+		}
+		stall.Tripped = true
+		reason := "output frozen since " + stall.FrozenSince
+		d.history.record(CircuitEvent{Time: d.now, Event: CircuitEventTrip, Polecat: stall.Polecat, Bead: stall.HookBead, Reason: reason})
+		note := circuitNote("tripped", d.rigName+"/witness", reason, d.now)
+		if err := d.bd.UpdateAgentDescriptionFields(stall.AgentBeadID, beads.AgentFieldUpdates{CircuitNote: &note}); err != nil {
+			stall.Error = fmt.Sprintf("recording circuit note: %v", err)
+		}
+		d.setState(stall, beads.StallTripped)
+		return
+	}
+
+	msg := stallEscalation(d.rigName, stall)
+	if err := d.escalation.Escalate(d.send, msg); err != nil {
+		stall.Error = fmt.Sprintf("escalating: %v", err)
+		return
+	}
+	stall.Escalated = true
+	stall.Escalation = msg.ID
+	d.setState(stall, beads.StallEscalated)
+}
+
+func (d *stallDetector) setState(stall *PolecatStall, state string) {
+	if err := d.bd.SetAgentStallState(stall.AgentBeadID, state, d.now); err != nil {
+		stall.Error = fmt.Sprintf("recording stall state: %v", err)
+		return
+	}
+	stall.State = state
+}
+
+// stallEscalation builds the POLECAT_STALLED mail to the Mayor for a polecat
+// still frozen after its nudge when the rig's action is "escalate".
+func stallEscalation(rigName string, stall *PolecatStall) *mail.Message {
+	msg := mail.NewMessage(
+		fmt.Sprintf("%s/witness", rigName),
+		"mayor/",
+		fmt.Sprintf("POLECAT_STALLED %s/%s", rigName, stall.Polecat),
+		fmt.Sprintf(`Polecat output has not changed since %s, and it did not respond to a nudge.
+
+Polecat: %s/%s
+Hooked work: %s
+
+Check its session ('gt peek %s/%s'). To requeue its work, trip its circuit
+with 'gt witness circuit trip %s/%s --reason "stalled"'.`,
+			stall.FrozenSince, rigName, stall.Polecat, stall.HookBead,
+			rigName, stall.Polecat, rigName, stall.Polecat),
+	)
+	msg.Priority = mail.PriorityHigh
+	return msg
+}
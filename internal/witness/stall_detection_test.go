@@ -0,0 +1,190 @@
+package witness
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func (f *memoryBeads) RecordAgentOutput(id, fingerprint string, at time.Time) (*beads.AgentFields, error) {
+	fields := f.fields(id)
+	if fields.OutputFingerprint != fingerprint || fields.OutputChangedAt == "" {
+		fields.OutputFingerprint = fingerprint
+		fields.OutputChangedAt = at.UTC().Format(time.RFC3339)
+		fields.StallState = ""
+		fields.StallStateAt = ""
+	}
+	copied := *fields
+	return &copied, nil
+}
+
+func (f *memoryBeads) SetAgentStallState(id, state string, at time.Time) error {
+	fields := f.fields(id)
+	fields.StallState = state
+	fields.StallStateAt = at.UTC().Format(time.RFC3339)
+	return nil
+}
+
+// newTestStallDetector returns a detector whose every live polecat's pane
+// shows *pane, recording nudges and mail.
+func newTestStallDetector(bd *memoryBeads, pane *string, nudged *[]string, sent *[]*mail.Message) *stallDetector {
+	return &stallDetector{
+		rigName: "gastown",
+		bd:      bd,
+		capture: func(string, int) (string, error) { return *pane, nil },
+		nudge:   func(polecat, _ string) error { *nudged = append(*nudged, polecat); return nil },
+		send:    func(m *mail.Message) error { *sent = append(*sent, m); return nil },
+		now:     time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestStallDetector_NudgesThenTrips(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	id := "gt-gastown-polecat-nux"
+	bd.fields(id).HookBead = "gt-abc"
+
+	var nudged []string
+	var sent []*mail.Message
+	pane := "Running tests... ✻ 12s"
+	d := newTestStallDetector(bd, &pane, &nudged, &sent)
+	cfg := DefaultStallDetectionConfig()
+
+	if result := d.run(cfg); result.Checked != 1 || len(result.Stalled) != 0 {
+		t.Fatalf("first pass = %+v, want checked, not stalled", result)
+	}
+
+	// Only the spinner and timer moved: still frozen at the timeout.
+	pane = "Running tests... ✳ 21m 3s"
+	d.now = d.now.Add(cfg.Timeout)
+	result := d.run(cfg)
+	if len(result.Stalled) != 1 || !result.Stalled[0].Nudged || result.Stalled[0].State != beads.StallNudged {
+		t.Fatalf("at timeout = %+v, want nudged", result)
+	}
+	if len(nudged) != 1 || nudged[0] != "nux" {
+		t.Errorf("nudged = %v, want [nux]", nudged)
+	}
+
+	// Within the grace period nothing more happens.
+	d.now = d.now.Add(cfg.NudgeGrace / 2)
+	if s := d.run(cfg).Stalled[0]; s.Nudged || s.Tripped {
+		t.Errorf("within grace = %+v, want no action", s)
+	}
+
+	d.now = d.now.Add(cfg.NudgeGrace)
+	s := d.run(cfg).Stalled[0]
+	if !s.Tripped || s.State != beads.StallTripped {
+		t.Fatalf("after grace = %+v, want tripped", s)
+	}
+	fields := bd.agents[id]
+	if fields.CircuitState != beads.CircuitOpen || !strings.Contains(fields.CircuitNote, "output frozen since 2026-03-01T12:00:00Z") {
+		t.Errorf("agent bead = %+v, want circuit open with the freeze in circuit_note", fields)
+	}
+	if len(nudged) != 1 || len(sent) != 0 {
+		t.Errorf("nudged %v, sent %d mail; want one nudge, no mail", nudged, len(sent))
+	}
+
+	// An open circuit is left to the sweep.
+	if result := d.run(cfg); result.Checked != 0 {
+		t.Errorf("checked a polecat whose circuit is open: %+v", result)
+	}
+}
+
+func TestStallDetector_OutputChangeClearsStall(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	id := "gt-gastown-polecat-nux"
+	bd.fields(id).HookBead = "gt-abc"
+
+	var nudged []string
+	var sent []*mail.Message
+	pane := "Editing main.go"
+	d := newTestStallDetector(bd, &pane, &nudged, &sent)
+	cfg := DefaultStallDetectionConfig()
+
+	d.run(cfg)
+	d.now = d.now.Add(cfg.Timeout)
+	d.run(cfg)
+	if bd.agents[id].StallState != beads.StallNudged {
+		t.Fatalf("stall_state = %q, want nudged", bd.agents[id].StallState)
+	}
+
+	pane = "Editing main.go\nRunning go test"
+	d.now = d.now.Add(cfg.NudgeGrace)
+	if result := d.run(cfg); len(result.Stalled) != 0 {
+		t.Errorf("after output changed = %+v, want no stall", result)
+	}
+	if fields := bd.agents[id]; fields.StallState != "" || fields.CircuitState == beads.CircuitOpen {
+		t.Errorf("agent bead = %+v, want stall cleared and circuit closed", fields)
+	}
+}
+
+func TestStallDetector_EscalatesOnce(t *testing.T) {
+	bd := newMemoryBeads("gastown")
+	id := "gt-gastown-polecat-nux"
+	bd.fields(id).HookBead = "gt-abc"
+	*bd.fields("gt-gastown-polecat-slit") = beads.AgentFields{RoleType: "polecat", Rig: "gastown"} // idle
+
+	var nudged []string
+	var sent []*mail.Message
+	pane := "Thinking..."
+	d := newTestStallDetector(bd, &pane, &nudged, &sent)
+	cfg := DefaultStallDetectionConfig()
+	cfg.Action = config.WitnessActionEscalate
+
+	d.run(cfg)
+	d.now = d.now.Add(cfg.Timeout)
+	d.run(cfg)
+	d.now = d.now.Add(cfg.NudgeGrace)
+	s := d.run(cfg).Stalled[0]
+	if !s.Escalated || s.State != beads.StallEscalated {
+		t.Fatalf("after grace = %+v, want escalated", s)
+	}
+	if len(sent) != 1 || !strings.HasPrefix(sent[0].Subject, "POLECAT_STALLED gastown/nux") ||
+		!strings.Contains(sent[0].Body, "Hooked work: gt-abc") {
+		t.Fatalf("escalation = %+v", sent)
+	}
+	if bd.agents[id].CircuitState == beads.CircuitOpen {
+		t.Error("escalate action opened the circuit")
+	}
+
+	d.now = d.now.Add(time.Hour)
+	if s := d.run(cfg).Stalled[0]; s.Escalated || s.Nudged {
+		t.Errorf("repeat pass = %+v, want no further action", s)
+	}
+	if len(sent) != 1 || len(nudged) != 1 {
+		t.Errorf("sent %d mail, %d nudges; want 1 each (idle polecat skipped)", len(sent), len(nudged))
+	}
+}
+
+func TestOutputFingerprint(t *testing.T) {
+	same := [][2]string{
+		{"Working ✻ 3s\n", "Working ✽ 47s"},
+		{"\x1b[32mok\x1b[0m   done", "ok done"},
+		{"⠋ Building (1m 2s)", "⠙ Building (14m 9s)"},
+	}
+	for _, pair := range same {
+		if OutputFingerprint(pair[0]) != OutputFingerprint(pair[1]) {
+			t.Errorf("fingerprints of %q and %q differ", pair[0], pair[1])
+		}
+	}
+	if OutputFingerprint("PASS: TestA") == OutputFingerprint("PASS: TestB") {
+		t.Error("different output has the same fingerprint")
+	}
+}
+
+func TestStallDetectionConfigFrom(t *testing.T) {
+	cfg, err := StallDetectionConfigFrom(&config.StallDetectionSettings{Timeout: "45m", Lines: 80})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Timeout != 45*time.Minute || cfg.NudgeGrace != DefaultStallNudgeGrace || cfg.Lines != 80 ||
+		cfg.Action != config.WitnessActionTrip {
+		t.Errorf("config = %+v", cfg)
+	}
+	if _, err := StallDetectionConfigFrom(&config.StallDetectionSettings{NudgeGrace: "soon"}); err == nil {
+		t.Error("invalid nudge_grace accepted")
+	}
+}
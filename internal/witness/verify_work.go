@@ -0,0 +1,232 @@
+package witness
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// CleanupStateVerificationFailed marks a cleanup wisp for a polecat whose
+// COMPLETED claim didn't hold up: its worktree may still hold the real work.
+const CleanupStateVerificationFailed = "verification-failed"
+
+// WorkVerification is the outcome of checking a polecat's COMPLETED claim
+// against the remote.
+type WorkVerification struct {
+	Branch       string   `json:"branch"`
+	Base         string   `json:"base"`
+	CommitsAhead int      `json:"commits_ahead"`
+	Referenced   bool     `json:"issue_referenced"`
+	Problems     []string `json:"problems,omitempty"`
+}
+
+// Verified reports whether the claimed work checked out.
+func (v *WorkVerification) Verified() bool {
+	return len(v.Problems) == 0
+}
+
+// VerifyPushedWork checks that a COMPLETED polecat's branch exists on origin,
+// has commits beyond the MR's target (the rig's default branch when there's
+// no MR), and that those commits or the MR description reference the issue.
+//
+// Problems describe a claim that didn't hold up. An error means the check
+// itself couldn't run (no rig clone, origin unreachable) and says nothing
+// about the polecat's work.
+func VerifyPushedWork(workDir, townRoot, rigName string, payload *PolecatDonePayload) (*WorkVerification, error) {
+	var mr *beads.Issue
+	if payload.MRID != "" {
+		mr = showBead(workDir, payload.MRID)
+	}
+
+	base := ""
+	if fields := beads.ParseMRFields(mr); fields != nil {
+		base = fields.Target
+	}
+	if base == "" {
+		base = "main"
+		if rigCfg, err := rig.LoadRigConfig(filepath.Join(townRoot, rigName)); err == nil && rigCfg.DefaultBranch != "" {
+			base = rigCfg.DefaultBranch
+		}
+	}
+
+	mrText := ""
+	if mr != nil {
+		mrText = mr.Title + "\n" + mr.Description
+	}
+	return verifyPushedWorkIn(rigGit(townRoot, rigName), base, payload.Branch, payload.IssueID, mrText)
+}
+
+// rigGit returns git for a clone of the rig with an origin remote: the
+// refinery's worktree, or mayor/rig in the legacy layout.
+func rigGit(townRoot, rigName string) *git.Git {
+	dir := filepath.Join(townRoot, rigName, "refinery", "rig")
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		dir = filepath.Join(townRoot, rigName, "mayor", "rig")
+	}
+	return git.NewGit(dir)
+}
+
+// verifyPushedWorkIn runs the checks in VerifyPushedWork against origin.
+func verifyPushedWorkIn(g *git.Git, base, branch, issueID, mrText string) (*WorkVerification, error) {
+	v := &WorkVerification{Branch: branch, Base: base}
+	if branch == "" {
+		v.Problems = append(v.Problems, "no branch reported in POLECAT_DONE")
+		return v, nil
+	}
+
+	exists, err := g.RemoteBranchExists("origin", branch)
+	if err != nil {
+		return nil, fmt.Errorf("querying origin for %s: %w", branch, err)
+	}
+	if !exists {
+		v.Problems = append(v.Problems, fmt.Sprintf("branch %s does not exist on origin (never pushed?)", branch))
+		return v, nil
+	}
+
+	for _, b := range []string{base, branch} {
+		if err := g.FetchBranch("origin", b); err != nil {
+			return nil, fmt.Errorf("fetching origin/%s: %w", b, err)
+		}
+	}
+	remoteBase, remoteBranch := "origin/"+base, "origin/"+branch
+
+	v.CommitsAhead, err = g.CommitsAhead(remoteBase, remoteBranch)
+	if err != nil {
+		return nil, fmt.Errorf("counting commits on %s beyond %s: %w", remoteBranch, remoteBase, err)
+	}
+	if v.CommitsAhead == 0 {
+		v.Problems = append(v.Problems, fmt.Sprintf("origin/%s has no commits beyond %s", branch, remoteBase))
+		return v, nil
+	}
+
+	if issueID == "" {
+		v.Problems = append(v.Problems, "no issue reported in POLECAT_DONE")
+		return v, nil
+	}
+	messages, err := g.CommitMessages(remoteBase, remoteBranch)
+	if err != nil {
+		return nil, fmt.Errorf("reading commit messages on %s: %w", remoteBranch, err)
+	}
+	for _, msg := range messages {
+		if strings.Contains(msg, issueID) {
+			v.Referenced = true
+			break
+		}
+	}
+	if !v.Referenced && strings.Contains(mrText, issueID) {
+		v.Referenced = true
+	}
+	if !v.Referenced {
+		v.Problems = append(v.Problems, fmt.Sprintf("none of the %d commit(s) nor the MR description reference %s", v.CommitsAhead, issueID))
+	}
+	return v, nil
+}
+
+// DemoteUnverifiedCompletion turns a COMPLETED claim that failed
+// verification into a failure: the MR (if any) is closed as rejected so the
+// Refinery doesn't process it, the issue is reopened for re-dispatch, a
+// cleanup wisp in state verification-failed keeps the polecat's worktree
+// from being nuked, and the Deacon is told what didn't check out. An issue
+// that has now failed max_bead_failures times is blocked as likely broken
+// instead of reopened. Returns the cleanup wisp ID.
+func DemoteUnverifiedCompletion(workDir, rigName string, payload *PolecatDonePayload, v *WorkVerification, router *mail.Router) (string, error) {
+	reason := "completion not verified: " + strings.Join(v.Problems, "; ")
+	var errs []string
+
+	// A false completion counts against the polecat's circuit breaker, and
+	// against the issue.
+	failure, _ := HandlePolecatFailure(workDir, rigName, payload.PolecatName, payload.IssueID, beads.FailureDeterministic)
+
+	if payload.MRID != "" {
+		if err := util.ExecRun(workDir, "bd", "close", payload.MRID, "--reason", "rejected: "+reason); err != nil {
+			errs = append(errs, fmt.Sprintf("closing MR %s: %v", payload.MRID, err))
+		}
+	}
+
+	reopened, blocked := false, false
+	if payload.IssueID != "" {
+		switch getBeadStatus(workDir, payload.IssueID) {
+		case "hooked", "in_progress", "closed":
+			if failure != nil && failure.BrokenWork {
+				if blocked = BlockBrokenWork(workDir, rigName, failure, router); !blocked {
+					errs = append(errs, fmt.Sprintf("blocking %s", payload.IssueID))
+				}
+			} else if err := util.ExecRun(workDir, "bd", "update", payload.IssueID, "--status=open", "--assignee="); err != nil {
+				errs = append(errs, fmt.Sprintf("reopening %s: %v", payload.IssueID, err))
+			} else {
+				reopened = true
+			}
+		}
+	}
+
+	wispID, err := createCleanupWisp(workDir, payload.PolecatName, payload.IssueID, payload.Branch)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("creating cleanup wisp: %v", err))
+	} else if err := UpdateCleanupWispState(workDir, wispID, CleanupStateVerificationFailed); err != nil {
+		errs = append(errs, fmt.Sprintf("updating wisp state: %v", err))
+	}
+
+	if router != nil {
+		if err := router.Send(unverifiedWorkMessage(rigName, payload, v, reopened, blocked)); err != nil {
+			errs = append(errs, fmt.Sprintf("notifying deacon: %v", err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return wispID, fmt.Errorf("demoting completion: %s", strings.Join(errs, "; "))
+	}
+	return wispID, nil
+}
+
+// unverifiedWorkMessage tells the Deacon a completion was demoted.
+func unverifiedWorkMessage(rigName string, payload *PolecatDonePayload, v *WorkVerification, reopened, blocked bool) *mail.Message {
+	next := "The issue was not reopened; check its state before re-dispatching."
+	switch {
+	case blocked:
+		next = "The issue has failed too often and was blocked as likely broken; the\nMayor has been told (BROKEN_WORK). Do not re-dispatch it."
+	case reopened:
+		next = "The issue has been reset to open with no assignee. Please re-dispatch."
+	}
+	return &mail.Message{
+		From:     fmt.Sprintf("%s/witness", rigName),
+		To:       "deacon/",
+		Subject:  fmt.Sprintf("WORK_UNVERIFIED %s", payload.IssueID),
+		Priority: mail.PriorityHigh,
+		Body: fmt.Sprintf(`Polecat reported COMPLETED but its work did not verify.
+
+Polecat: %s/%s
+Issue: %s
+MR: %s
+Branch: %s (base %s)
+
+Problems:
+- %s
+
+The completion was demoted to failed and the MR (if any) closed as rejected.
+The polecat's worktree was kept for recovery (cleanup wisp state: %s).
+%s`,
+			rigName, payload.PolecatName, payload.IssueID, payload.MRID, v.Branch, v.Base,
+			strings.Join(v.Problems, "\n- "), CleanupStateVerificationFailed, next),
+	}
+}
+
+// showBead returns a bead by ID, or nil if it can't be read.
+func showBead(workDir, id string) *beads.Issue {
+	output, err := util.ExecWithOutput(workDir, "bd", "show", id, "--json")
+	if err != nil || output == "" {
+		return nil
+	}
+	var issues []*beads.Issue
+	if err := json.Unmarshal([]byte(output), &issues); err != nil || len(issues) == 0 {
+		return nil
+	}
+	return issues[0]
+}
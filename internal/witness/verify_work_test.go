@@ -0,0 +1,130 @@
+package witness
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// setupVerifyRepos creates a bare origin with a main branch, a polecat clone
+// that pushes branches to it, and a separate rig clone the witness verifies
+// from. Returns (polecatDir, rigGit).
+func setupVerifyRepos(t *testing.T) (string, *git.Git) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	tmp := t.TempDir()
+	origin := filepath.Join(tmp, "origin.git")
+	polecat := filepath.Join(tmp, "polecat")
+	rigDir := filepath.Join(tmp, "rig")
+
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run(tmp, "init", "--bare", "--initial-branch=main", origin)
+	run(tmp, "clone", origin, polecat)
+	run(polecat, "config", "user.email", "test@test.com")
+	run(polecat, "config", "user.name", "Test")
+	run(polecat, "checkout", "-b", "main")
+	run(polecat, "commit", "--allow-empty", "-m", "initial")
+	run(polecat, "push", "origin", "main")
+	run(tmp, "clone", origin, rigDir)
+
+	return polecat, git.NewGit(rigDir)
+}
+
+// pushBranch commits the messages on a new branch off main and pushes it.
+func pushBranch(t *testing.T, polecat, branch string, messages ...string) {
+	t.Helper()
+	for _, args := range [][]string{{"checkout", "main"}, {"checkout", "-b", branch}} {
+		if out, err := exec.Command("git", append([]string{"-C", polecat}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	for i, msg := range messages {
+		name := filepath.Join(polecat, branch[strings.LastIndex(branch, "/")+1:]+string(rune('a'+i)))
+		if err := os.WriteFile(name, []byte(msg), 0644); err != nil {
+			t.Fatal(err)
+		}
+		for _, args := range [][]string{{"add", "."}, {"commit", "-m", msg}} {
+			if out, err := exec.Command("git", append([]string{"-C", polecat}, args...)...).CombinedOutput(); err != nil {
+				t.Fatalf("git %v: %v\n%s", args, err, out)
+			}
+		}
+	}
+	if out, err := exec.Command("git", "-C", polecat, "push", "origin", branch).CombinedOutput(); err != nil {
+		t.Fatalf("push: %v\n%s", err, out)
+	}
+}
+
+func TestVerifyPushedWork(t *testing.T) {
+	polecat, g := setupVerifyRepos(t)
+	pushBranch(t, polecat, "polecat/nux/gt-abc", "fix: handle nil config (gt-abc)")
+	pushBranch(t, polecat, "polecat/nux/empty")
+	pushBranch(t, polecat, "polecat/nux/unref", "fix: something else")
+
+	tests := []struct {
+		name       string
+		branch     string
+		mrText     string
+		verified   bool
+		referenced bool
+		problem    string
+	}{
+		{name: "verified by commit message", branch: "polecat/nux/gt-abc", verified: true, referenced: true},
+		{name: "verified by MR description", branch: "polecat/nux/unref", mrText: "source_issue: gt-abc", verified: true, referenced: true},
+		{name: "branch never pushed", branch: "polecat/nux/missing", problem: "does not exist on origin"},
+		{name: "no commits beyond base", branch: "polecat/nux/empty", problem: "no commits beyond origin/main"},
+		{name: "issue not referenced", branch: "polecat/nux/unref", problem: "reference gt-abc"},
+		{name: "no branch reported", problem: "no branch reported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := verifyPushedWorkIn(g, "main", tt.branch, "gt-abc", tt.mrText)
+			if err != nil {
+				t.Fatalf("verifyPushedWorkIn: %v", err)
+			}
+			if v.Verified() != tt.verified || v.Referenced != tt.referenced {
+				t.Fatalf("verified=%v referenced=%v, want %v/%v (problems: %v)", v.Verified(), v.Referenced, tt.verified, tt.referenced, v.Problems)
+			}
+			if tt.problem != "" && !strings.Contains(strings.Join(v.Problems, "; "), tt.problem) {
+				t.Errorf("problems %v should mention %q", v.Problems, tt.problem)
+			}
+		})
+	}
+}
+
+func TestVerifyPushedWork_OriginUnreachable(t *testing.T) {
+	_, g := setupVerifyRepos(t)
+	if out, err := exec.Command("git", "-C", g.WorkDir(), "remote", "set-url", "origin", filepath.Join(t.TempDir(), "gone.git")).CombinedOutput(); err != nil {
+		t.Fatalf("set-url: %v\n%s", err, out)
+	}
+	if _, err := verifyPushedWorkIn(g, "main", "polecat/nux/gt-abc", "gt-abc", ""); err == nil {
+		t.Error("expected an error (not a problem) when origin can't be queried")
+	}
+}
+
+func TestUnverifiedWorkMessage(t *testing.T) {
+	payload := &PolecatDonePayload{PolecatName: "nux", IssueID: "gt-abc", MRID: "gt-mr-1", Branch: "polecat/nux/gt-abc"}
+	v := &WorkVerification{Branch: payload.Branch, Base: "main", Problems: []string{"branch polecat/nux/gt-abc does not exist on origin (never pushed?)"}}
+
+	msg := unverifiedWorkMessage("gastown", payload, v, true, false)
+	if msg.To != "deacon/" || msg.Subject != "WORK_UNVERIFIED gt-abc" {
+		t.Errorf("unexpected routing: to=%q subject=%q", msg.To, msg.Subject)
+	}
+	for _, want := range []string{"gastown/nux", "does not exist on origin", "state: verification-failed", "reset to open"} {
+		if !strings.Contains(msg.Body, want) {
+			t.Errorf("body missing %q:\n%s", want, msg.Body)
+		}
+	}
+}